@@ -0,0 +1,2118 @@
+// Code generated by clientgen from openapi.yaml. DO NOT EDIT.
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Version - of the API contract(info.version of the spec) the client is built for
+const Version = "1.0.0"
+
+type AdminFilesListResponse struct {
+	Data []AdminUserFile `json:"data,omitempty"`
+	// NextCursor - Cursor for the next page, omitted for non created_at sort or an empty page.
+	NextCursor *string `json:"next_cursor,omitempty"`
+	// Stats - Aggregates of all files matching the filter, not only the page.
+	Stats *FilesStats `json:"stats,omitempty"`
+}
+
+type AdminUser struct {
+	// BirthDate - Midnight UTC of the birth date, e.g. 1990-01-02T00:00:00Z.
+	BirthDate time.Time  `json:"birth_date"`
+	CreatedAt time.Time  `json:"created_at"`
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+	// DeletedReason - A DeletionReason, empty for users deleted before the reasons were recorded
+	DeletedReason *string `json:"deleted_reason,omitempty"`
+	Email         string  `json:"email"`
+	// FilesCount - Active files of the user, only in the profile reads(self/admin). Maintained
+	// asynchronously, may lag behind uploads.
+	FilesCount *int64 `json:"files_count,omitempty"`
+	Lastname   string `json:"lastname"`
+	Name       string `json:"name"`
+	// PendingEmail - Requested email awaiting confirmation, only in the update response.
+	PendingEmail *string `json:"pending_email,omitempty"`
+	Phone        string  `json:"phone"`
+	Role         string  `json:"role"`
+	// TotalStorageBytes - Total size of the active files, only in the profile reads(self/admin).
+	TotalStorageBytes *int64    `json:"total_storage_bytes,omitempty"`
+	UpdatedAt         time.Time `json:"updated_at"`
+	UUID              uuid.UUID `json:"uuid"`
+}
+
+type AdminUserFile struct {
+	CreatedAt   *time.Time `json:"created_at,omitempty"`
+	DownloadURL *string    `json:"download_url,omitempty"`
+	FileName    *string    `json:"file_name,omitempty"`
+	// Folder - Folder path as "a/b", empty - the root.
+	Folder    *string  `json:"folder,omitempty"`
+	MimeType  *string  `json:"mime_type,omitempty"`
+	SizeBytes *int64   `json:"size_bytes,omitempty"`
+	Tags      []string `json:"tags,omitempty"`
+	// ThumbnailURL - PNG preview for images and PDFs, set asynchronously after upload.
+	ThumbnailURL *string    `json:"thumbnail_url,omitempty"`
+	UserUUID     *uuid.UUID `json:"user_uuid,omitempty"`
+	UUID         *uuid.UUID `json:"uuid,omitempty"`
+}
+
+type AuditEntry struct {
+	Action string `json:"action"`
+	// ActorUUID - The nil UUID for the system
+	ActorUUID  uuid.UUID      `json:"actor_uuid"`
+	CreatedAt  time.Time      `json:"created_at"`
+	Details    map[string]any `json:"details"`
+	ID         int64          `json:"id"`
+	TargetUUID *uuid.UUID     `json:"target_uuid,omitempty"`
+}
+
+type AuditListResponse struct {
+	Data []AuditEntry `json:"data"`
+}
+
+type AuthTokenResponse struct {
+	// AccessToken - JWT access token
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+}
+
+type ConflictError struct {
+	Code string `json:"code"`
+	// Details - Additional error details
+	Details json.RawMessage `json:"details,omitempty"`
+	Error   *string         `json:"error,omitempty"`
+}
+
+type DeadLetter struct {
+	// Body - The event JSON, a string if the body is not JSON
+	Body        any    `json:"body"`
+	ContentType string `json:"content_type"`
+	// Error - Of the consumer handler
+	Error       string     `json:"error"`
+	FailedAt    *time.Time `json:"failed_at,omitempty"`
+	MessageID   string     `json:"message_id"`
+	PublishedAt *time.Time `json:"published_at,omitempty"`
+	// Redelivered - Peeked or looked through before
+	Redelivered bool `json:"redelivered"`
+	// RoutingKey - The original one
+	RoutingKey string `json:"routing_key"`
+}
+
+type DeadLetters struct {
+	Messages []DeadLetter `json:"messages"`
+	// Total - The messages in the queue
+	Total int `json:"total"`
+}
+
+type DeadLettersError struct {
+	// Code - Machine-readable reason, the clients branch on it rather than on error: e.g.
+	// token_missing, token_invalid, token_expired, token_revoked, user_not_found, email_taken or the
+	// generic one of the status(bad_request, unauthorized, not_found, internal)
+	Code *string `json:"code,omitempty"`
+	// Details - Additional error details
+	Details json.RawMessage `json:"details,omitempty"`
+	Done    []string        `json:"done,omitempty"`
+	Error   *string         `json:"error,omitempty"`
+}
+
+type DeadLettersRequest struct {
+	MessageIDs []string `json:"message_ids"`
+}
+
+type DeadLettersResult struct {
+	Done     []string `json:"done"`
+	NotFound []string `json:"not_found"`
+}
+
+type DeletionReason string
+
+const (
+	DeletionReasonUserRequest DeletionReason = "user_request"
+	DeletionReasonAdminAction DeletionReason = "admin_action"
+	DeletionReasonGdpr        DeletionReason = "gdpr"
+	DeletionReasonFraud       DeletionReason = "fraud"
+	DeletionReasonMerged      DeletionReason = "merged"
+)
+
+type DirectorySync struct {
+	Created *int `json:"created,omitempty"`
+	// Disabled - Soft deleted accounts disabled in the directory
+	Disabled *int `json:"disabled,omitempty"`
+	// Error - The run failed as a whole(e.g. the directory unreachable)
+	Error *string `json:"error,omitempty"`
+	// Failed - Entries not reconciled(e.g. disabled admins), see the service log
+	Failed     *int       `json:"failed,omitempty"`
+	Fetched    *int       `json:"fetched,omitempty"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+	// Skipped - Entries without the attributes a user requires
+	Skipped   *int       `json:"skipped,omitempty"`
+	StartedAt *time.Time `json:"started_at,omitempty"`
+	Unchanged *int       `json:"unchanged,omitempty"`
+	Updated   *int       `json:"updated,omitempty"`
+}
+
+type DuplicatesResponseDataItem struct {
+	// Key - The shared value(the phone, the email alias, "name lastname YYYY-MM-DD")
+	Key     *string     `json:"key,omitempty"`
+	Reason  *string     `json:"reason,omitempty"`
+	UserIDs []uuid.UUID `json:"user_ids,omitempty"`
+}
+
+type DuplicatesResponse struct {
+	// Data - Ordered by reason and key, a user may be in several groups
+	Data []DuplicatesResponseDataItem `json:"data,omitempty"`
+}
+
+type EmailConfirmRequest struct {
+	Token string `json:"token"`
+}
+
+type Error struct {
+	// Code - Machine-readable reason, the clients branch on it rather than on error: e.g.
+	// token_missing, token_invalid, token_expired, token_revoked, user_not_found, email_taken or the
+	// generic one of the status(bad_request, unauthorized, not_found, internal)
+	Code *string `json:"code,omitempty"`
+	// Details - Additional error details
+	Details json.RawMessage `json:"details,omitempty"`
+	Error   *string         `json:"error,omitempty"`
+}
+
+type FileRetention struct {
+	FileUUID    uuid.UUID  `json:"file_uuid"`
+	LegalHold   bool       `json:"legal_hold"`
+	RetainUntil *time.Time `json:"retain_until,omitempty"`
+}
+
+type FileRetentionRequest struct {
+	LegalHold bool `json:"legal_hold"`
+	// RetainUntil - In the future, null - the retention rules apply.
+	RetainUntil *time.Time `json:"retain_until,omitempty"`
+}
+
+type FilesStatsByMimeTypeItem struct {
+	FilesCount *int64  `json:"files_count,omitempty"`
+	MimeType   *string `json:"mime_type,omitempty"`
+	TotalBytes *int64  `json:"total_bytes,omitempty"`
+}
+
+// FilesStats - Aggregates of all files matching the filter, not only the page.
+type FilesStats struct {
+	ByMimeType []FilesStatsByMimeTypeItem `json:"by_mime_type,omitempty"`
+	FilesCount *int64                     `json:"files_count,omitempty"`
+	TotalBytes *int64                     `json:"total_bytes,omitempty"`
+}
+
+type FilesTotals struct {
+	FilesCount     *int64 `json:"files_count,omitempty"`
+	TotalBytes     *int64 `json:"total_bytes,omitempty"`
+	UsersWithFiles *int64 `json:"users_with_files,omitempty"`
+}
+
+type Folder struct {
+	// FilesCount - Files of the folder and its subfolders.
+	FilesCount int64  `json:"files_count"`
+	Path       string `json:"path"`
+	TotalBytes int64  `json:"total_bytes"`
+}
+
+type FolderMoveRequest struct {
+	From string `json:"from"`
+	// To - Empty or absent - the root.
+	To *string `json:"to,omitempty"`
+}
+
+type HREvent struct {
+	Employee UserRequest `json:"employee"`
+	Event    string      `json:"event"`
+}
+
+type HRResult struct {
+	Result *string    `json:"result,omitempty"`
+	UUID   *uuid.UUID `json:"uuid,omitempty"`
+}
+
+type HistoryResponse struct {
+	// Data - Oldest first, the current version last
+	Data []Revision `json:"data,omitempty"`
+}
+
+type ImpersonationTokenResponse struct {
+	// AccessToken - JWT access token
+	AccessToken string `json:"access_token"`
+	// ActAs - UUID of the impersonating admin, also carried as the "act_as" JWT claim.
+	ActAs     uuid.UUID `json:"act_as"`
+	ExpiresAt time.Time `json:"expires_at"`
+	TokenType string    `json:"token_type"`
+}
+
+type Invitation struct {
+	Email     *string    `json:"email,omitempty"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	Role      *string    `json:"role,omitempty"`
+}
+
+type InvitationAcceptRequest struct {
+	// BirthDate - At least the minimum age of the organization of the email(AGE_POLICY_MIN_AGE,
+	// AGE_POLICY_ORGS) on today's date in the timezone of the user, else 400.
+	BirthDate string `json:"birth_date"`
+	Lastname  string `json:"lastname"`
+	Name      string `json:"name"`
+	Password  string `json:"password"`
+	Phone     string `json:"phone"`
+}
+
+type InvitationRequest struct {
+	Email string  `json:"email"`
+	Role  *string `json:"role,omitempty"`
+}
+
+type LegalHold struct {
+	Reason   string    `json:"reason"`
+	Since    time.Time `json:"since"`
+	UserUUID uuid.UUID `json:"user_uuid"`
+}
+
+type LegalHoldRequest struct {
+	Reason string `json:"reason"`
+}
+
+type LoginRequest struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+type MQEntity struct {
+	Found bool `json:"found"`
+	// Mismatches - The properties differing from the declared ones
+	Mismatches []string `json:"mismatches"`
+	Name       string   `json:"name"`
+}
+
+type MQTopology struct {
+	// Buffered - Events waiting to be published
+	Buffered int `json:"buffered"`
+	// Connected - The AMQP connection of the answering instance
+	Connected bool     `json:"connected"`
+	Drift     bool     `json:"drift"`
+	Exchange  MQEntity `json:"exchange"`
+	// ExtraBindings - Bound routing keys of no event
+	ExtraBindings []string `json:"extra_bindings"`
+	// MissingBindings - Routing keys not bound to the queue
+	MissingBindings []string `json:"missing_bindings"`
+	Queue           MQEntity `json:"queue"`
+	// RetryQueued - Events waiting for a retry
+	RetryQueued int `json:"retry_queued"`
+}
+
+type MQTopologyConflict struct {
+	// Code - Machine-readable reason, the clients branch on it rather than on error: e.g.
+	// token_missing, token_invalid, token_expired, token_revoked, user_not_found, email_taken or the
+	// generic one of the status(bad_request, unauthorized, not_found, internal)
+	Code *string `json:"code,omitempty"`
+	// Details - Additional error details
+	Details  json.RawMessage `json:"details,omitempty"`
+	Error    *string         `json:"error,omitempty"`
+	Topology MQTopology      `json:"topology"`
+}
+
+type MergeRequest struct {
+	// LoserID - The account merged and deleted(deleted_reason merged)
+	LoserID uuid.UUID `json:"loser_id"`
+	// WinnerID - The account kept
+	WinnerID uuid.UUID `json:"winner_id"`
+}
+
+// MergeResponseMoved - The records moved from the loser to the winner
+type MergeResponseMoved struct {
+	Audit *int `json:"audit,omitempty"`
+	Files *int `json:"files,omitempty"`
+	Notes *int `json:"notes,omitempty"`
+}
+
+type MergeResponse struct {
+	// Moved - The records moved from the loser to the winner
+	Moved *MergeResponseMoved `json:"moved,omitempty"`
+	User  *AdminUser          `json:"user,omitempty"`
+}
+
+type NotificationPreference struct {
+	Channel string `json:"channel"`
+	Enabled *bool  `json:"enabled,omitempty"`
+	// Mode - digest - the non-urgent notifications are batched into the daily digest
+	Mode *string `json:"mode,omitempty"`
+	// WebhookURL - The https endpoint of the webhook channel, required to enable it
+	WebhookURL *string `json:"webhook_url,omitempty"`
+}
+
+type NotificationPreferences struct {
+	Preferences []NotificationPreference `json:"preferences"`
+}
+
+type OTPRequest struct {
+	// Phone - E.164 format
+	Phone string `json:"phone"`
+}
+
+type OTPVerifyRequest struct {
+	Code  string `json:"code"`
+	Phone string `json:"phone"`
+}
+
+type OverloadedError struct {
+	Code string `json:"code"`
+	// Details - Additional error details
+	Details json.RawMessage `json:"details,omitempty"`
+	Error   *string         `json:"error,omitempty"`
+}
+
+type PasswordChangeRequest struct {
+	Email       string `json:"email"`
+	NewPassword string `json:"new_password"`
+	Password    string `json:"password"`
+}
+
+type PublicUser struct {
+	Name string    `json:"name"`
+	UUID uuid.UUID `json:"uuid"`
+}
+
+type ReadOnly struct {
+	// Enabled - The toggle
+	Enabled bool `json:"enabled"`
+	// Forced - The answering instance is read-only whatever the toggle
+	Forced    *string   `json:"forced,omitempty"`
+	Reason    string    `json:"reason"`
+	UpdatedAt time.Time `json:"updated_at"`
+	// UpdatedBy - The admin who toggled it last, null if never
+	UpdatedBy *uuid.UUID `json:"updated_by,omitempty"`
+}
+
+type ReadOnlyError struct {
+	Code string `json:"code"`
+	// Details - Additional error details
+	Details json.RawMessage `json:"details,omitempty"`
+	Error   *string         `json:"error,omitempty"`
+}
+
+type ReadOnlyRequest struct {
+	Enabled bool `json:"enabled"`
+	// Reason - Required to enable.
+	Reason *string `json:"reason,omitempty"`
+}
+
+type RetentionRule struct {
+	CreatedAt time.Time `json:"created_at"`
+	Days      int       `json:"days"`
+	MimeType  *string   `json:"mime_type,omitempty"`
+	Tag       *string   `json:"tag,omitempty"`
+	UUID      uuid.UUID `json:"uuid"`
+}
+
+// RetentionRuleRequest - One of mime_type and tag.
+type RetentionRuleRequest struct {
+	Days int `json:"days"`
+	// MimeType - Exact MIME type or the type wildcard "type/*".
+	MimeType *string `json:"mime_type,omitempty"`
+	Tag      *string `json:"tag,omitempty"`
+}
+
+type RevisionChangesItem struct {
+	Field *string `json:"field,omitempty"`
+	From  *string `json:"from,omitempty"`
+	To    *string `json:"to,omitempty"`
+}
+
+type Revision struct {
+	// Changes - From the previous revision, missing for the first one
+	Changes   []RevisionChangesItem `json:"changes,omitempty"`
+	User      *AdminUser            `json:"user,omitempty"`
+	ValidFrom *time.Time            `json:"valid_from,omitempty"`
+	// ValidTo - Missing for the current version
+	ValidTo *time.Time `json:"valid_to,omitempty"`
+}
+
+type RolesRequestAssignmentsItem struct {
+	Role   string    `json:"role"`
+	UserID uuid.UUID `json:"user_id"`
+}
+
+type RolesRequest struct {
+	Assignments []RolesRequestAssignmentsItem `json:"assignments"`
+}
+
+type RolesResponseDataItem struct {
+	// PreviousRole - Missing for not found users
+	PreviousRole *string    `json:"previous_role,omitempty"`
+	Result       *string    `json:"result,omitempty"`
+	Role         *string    `json:"role,omitempty"`
+	UserID       *uuid.UUID `json:"user_id,omitempty"`
+}
+
+type RolesResponse struct {
+	// Data - In the request order
+	Data []RolesResponseDataItem `json:"data,omitempty"`
+}
+
+type SeatLimit struct {
+	// ActiveUsers - Users of the organization who are not deleted.
+	ActiveUsers *int64     `json:"active_users,omitempty"`
+	Org         *string    `json:"org,omitempty"`
+	Seats       *int       `json:"seats,omitempty"`
+	UpdatedAt   *time.Time `json:"updated_at,omitempty"`
+}
+
+type SignupsStatsResponseDataItem struct {
+	Day     *string `json:"day,omitempty"`
+	Signups *int64  `json:"signups,omitempty"`
+}
+
+type SignupsStatsResponse struct {
+	Data []SignupsStatsResponseDataItem `json:"data,omitempty"`
+	// Total - Signups of the whole range.
+	Total *int64 `json:"total,omitempty"`
+}
+
+type SlowQueries struct {
+	Data []SlowQuery `json:"data"`
+	// Threshold - POSTGRES_SLOW_QUERY_THRESHOLD, a Go duration; 0s - the log is off
+	Threshold string `json:"threshold"`
+}
+
+type SlowQuery struct {
+	Calls int64 `json:"calls"`
+	// Fingerprint - "other" for the statements over the 500 tracked ones
+	Fingerprint string     `json:"fingerprint"`
+	LastSlowAt  *time.Time `json:"last_slow_at,omitempty"`
+	MaxMs       float64    `json:"max_ms"`
+	// MeanMs - Of all the calls, the fast ones included
+	MeanMs float64 `json:"mean_ms"`
+	// Slow - The calls over the threshold
+	Slow int64 `json:"slow"`
+	// SQL - Whitespace collapsed, empty for "other"
+	SQL string `json:"sql"`
+}
+
+type TimeoutError struct {
+	Code string `json:"code"`
+	// Details - Additional error details
+	Details json.RawMessage `json:"details,omitempty"`
+	Error   *string         `json:"error,omitempty"`
+	// Timeout - The budget of the request, a Go duration
+	Timeout string `json:"timeout"`
+}
+
+type UpgradeRequiredError struct {
+	Code string `json:"code"`
+	// Details - Additional error details
+	Details json.RawMessage `json:"details,omitempty"`
+	Error   *string         `json:"error,omitempty"`
+}
+
+type UploadPolicy struct {
+	AllowedTypes []string   `json:"allowed_types,omitempty"`
+	MaxFileSize  *int64     `json:"max_file_size,omitempty"`
+	Org          *string    `json:"org,omitempty"`
+	QuotaBytes   *int64     `json:"quota_bytes,omitempty"`
+	UpdatedAt    *time.Time `json:"updated_at,omitempty"`
+}
+
+// UploadPolicyRequest - Replaces the policy, one of the fields at least. 0 or no limit - none of
+// the policy.
+type UploadPolicyRequest struct {
+	// AllowedTypes - type/subtype or the type/* wildcards, empty - any type.
+	AllowedTypes []string `json:"allowed_types,omitempty"`
+	// MaxFileSize - Of a file, in bytes.
+	MaxFileSize *int64 `json:"max_file_size,omitempty"`
+	// QuotaBytes - Of all the files of a user, in bytes.
+	QuotaBytes *int64 `json:"quota_bytes,omitempty"`
+}
+
+type UploadProgress struct {
+	// Received - Bytes of the request body received so far.
+	Received int64  `json:"received"`
+	Status   string `json:"status"`
+	// Total - Length of the request body, 0 if unknown.
+	Total     int64     `json:"total"`
+	UpdatedAt time.Time `json:"updated_at"`
+	UploadID  uuid.UUID `json:"upload_id"`
+}
+
+type UsageReportResponseDataItem struct {
+	AvgDurationMs *int64  `json:"avg_duration_ms,omitempty"`
+	Org           *string `json:"org,omitempty"`
+	Requests      *int64  `json:"requests,omitempty"`
+	Role          *string `json:"role,omitempty"`
+}
+
+type UsageReportResponse struct {
+	Data []UsageReportResponseDataItem `json:"data,omitempty"`
+	From *string                       `json:"from,omitempty"`
+	To   *string                       `json:"to,omitempty"`
+	// TotalRequests - Requests of the whole range.
+	TotalRequests *int64 `json:"total_requests,omitempty"`
+}
+
+type User struct {
+	// BirthDate - Midnight UTC of the birth date, e.g. 1990-01-02T00:00:00Z.
+	BirthDate time.Time `json:"birth_date"`
+	Email     string    `json:"email"`
+	// FilesCount - Active files of the user, only in the profile reads(self/admin). Maintained
+	// asynchronously, may lag behind uploads.
+	FilesCount *int64 `json:"files_count,omitempty"`
+	Lastname   string `json:"lastname"`
+	Name       string `json:"name"`
+	// PendingEmail - Requested email awaiting confirmation, only in the update response.
+	PendingEmail *string `json:"pending_email,omitempty"`
+	Phone        string  `json:"phone"`
+	// TotalStorageBytes - Total size of the active files, only in the profile reads(self/admin).
+	TotalStorageBytes *int64    `json:"total_storage_bytes,omitempty"`
+	UUID              uuid.UUID `json:"uuid"`
+}
+
+// UserFile - Representation of a user file (response DTO).
+type UserFile struct {
+	CreatedAt   *time.Time `json:"created_at,omitempty"`
+	DownloadURL *string    `json:"download_url,omitempty"`
+	FileName    *string    `json:"file_name,omitempty"`
+	// Folder - Folder path as "a/b", empty - the root.
+	Folder    *string  `json:"folder,omitempty"`
+	MimeType  *string  `json:"mime_type,omitempty"`
+	SizeBytes *int64   `json:"size_bytes,omitempty"`
+	Tags      []string `json:"tags,omitempty"`
+	// ThumbnailURL - PNG preview for images and PDFs, set asynchronously after upload.
+	ThumbnailURL *string    `json:"thumbnail_url,omitempty"`
+	UUID         *uuid.UUID `json:"uuid,omitempty"`
+}
+
+type UserFileMoveRequest struct {
+	// FileName - New name, sanitized as on upload. Kept if absent.
+	FileName *string `json:"file_name,omitempty"`
+	// Folder - Target folder as "a/b", empty - the root. Kept if absent.
+	Folder *string `json:"folder,omitempty"`
+}
+
+type UserFileText struct {
+	ExtractedAt time.Time `json:"extracted_at"`
+	FileUUID    uuid.UUID `json:"file_uuid"`
+	Status      string    `json:"status"`
+	// Text - Empty if the extraction failed.
+	Text string `json:"text"`
+}
+
+type UserFilesListResponse struct {
+	Data []UserFile `json:"data,omitempty"`
+	// NextCursor - Cursor for the next page, omitted for non created_at sort or an empty page.
+	NextCursor *string `json:"next_cursor,omitempty"`
+}
+
+type UserIdentity struct {
+	// DeletedAt - Of a deleted user only
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+	// ID - The internal ID
+	ID     int64     `json:"id"`
+	UserID uuid.UUID `json:"user_id"`
+}
+
+type UserNote struct {
+	AuthorUUID *uuid.UUID `json:"author_uuid,omitempty"`
+	Body       *string    `json:"body,omitempty"`
+	CreatedAt  *time.Time `json:"created_at,omitempty"`
+	UUID       *uuid.UUID `json:"uuid,omitempty"`
+}
+
+type UserNoteRequest struct {
+	Body string `json:"body"`
+}
+
+type UserNotesListResponse struct {
+	Data       []UserNote `json:"data,omitempty"`
+	NextCursor *string    `json:"next_cursor,omitempty"`
+}
+
+type UserRequest struct {
+	// BirthDate - At least the minimum age of the organization of the email(AGE_POLICY_MIN_AGE,
+	// AGE_POLICY_ORGS) on today's date in the timezone of the user, else 400.
+	BirthDate string `json:"birth_date"`
+	Email     string `json:"email"`
+	Lastname  string `json:"lastname"`
+	Name      string `json:"name"`
+	Phone     string `json:"phone"`
+}
+
+type UserSummaryActivityItem struct {
+	Day          string `json:"day"`
+	Events       int    `json:"events"`
+	FailedLogins int    `json:"failed_logins"`
+	Logins       int    `json:"logins"`
+}
+
+type UserSummaryFiles struct {
+	Changes int `json:"changes"`
+	// Count - Not deleted files, of the changes carrying the count
+	Count         int64      `json:"count"`
+	LastChangedAt *time.Time `json:"last_changed_at,omitempty"`
+}
+
+type UserSummary struct {
+	// Activity - The days with events, oldest first
+	Activity      []UserSummaryActivityItem `json:"activity"`
+	DeletedAt     *time.Time                `json:"deleted_at,omitempty"`
+	DeletedReason *DeletionReason           `json:"deleted_reason,omitempty"`
+	EmailChanges  int                       `json:"email_changes"`
+	FailedLogins  int                       `json:"failed_logins"`
+	Files         UserSummaryFiles          `json:"files"`
+	LastEventAt   time.Time                 `json:"last_event_at"`
+	LastLoginAt   *time.Time                `json:"last_login_at,omitempty"`
+	Logins        int                       `json:"logins"`
+	// MergedInto - The winner, of a user deleted by a merge
+	MergedInto *uuid.UUID `json:"merged_into,omitempty"`
+	SignedUpAt *time.Time `json:"signed_up_at,omitempty"`
+	// UpdatedAt - The latest profile update or email change
+	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+	UserUUID  uuid.UUID  `json:"user_uuid"`
+}
+
+type UsersListResponse struct {
+	Data []AdminUser `json:"data,omitempty"`
+	// NextCursor - Cursor for the next page, omitted for non created_at sort or an empty page.
+	NextCursor *string `json:"next_cursor,omitempty"`
+}
+
+type ValidationError struct {
+	// Code - Machine-readable reason, the clients branch on it rather than on error: e.g.
+	// token_missing, token_invalid, token_expired, token_revoked, user_not_found, email_taken or the
+	// generic one of the status(bad_request, unauthorized, not_found, internal)
+	Code *string `json:"code,omitempty"`
+	// Details - Additional error details
+	Details json.RawMessage `json:"details,omitempty"`
+	Error   *string         `json:"error,omitempty"`
+}
+
+type ValidationResponse struct {
+	// Errors - The error of every invalid field, keyed by the request field
+	Errors map[string]string `json:"errors,omitempty"`
+	Valid  bool              `json:"valid"`
+}
+
+type VersionResponse struct {
+	Data *Revision `json:"data,omitempty"`
+}
+
+// ListAuditEntriesParams - the query and header parameters of ListAuditEntries, the nil ones are not sent
+type ListAuditEntriesParams struct {
+	// Action - The entries of this action only.
+	Action *string
+	// ActorID - The entries of this actor only.
+	ActorID *uuid.UUID
+	// From - Created at or after, RFC 3339 or YYYY-MM-DD.
+	From *string
+	// Page - Page number, cannot be combined with cursor.
+	Page *int
+	// PerPage - Page size, defaults to APP_PAGE_SIZE.
+	PerPage *int
+	// Sort - Sort field, "-" prefix for descending.
+	Sort *string
+	// TargetID - The entries of this target only.
+	TargetID *uuid.UUID
+	// To - Created before, RFC 3339 or YYYY-MM-DD.
+	To *string
+}
+
+// GetCollectionParams - the query and header parameters of GetCollection, the nil ones are not sent
+type GetCollectionParams struct {
+	Format *string
+}
+
+// GetSlowQueriesParams - the query and header parameters of GetSlowQueries, the nil ones are not sent
+type GetSlowQueriesParams struct {
+	Limit *int
+}
+
+// ListAdminFilesParams - the query and header parameters of ListAdminFiles, the nil ones are not sent
+type ListAdminFilesParams struct {
+	// Cursor - Opaque keyset cursor from "next_cursor", only with created_at sort.
+	Cursor *string
+	// MaxSize - Max size in bytes, inclusive.
+	MaxSize *int64
+	// MimeType - Exact MIME type or the type wildcard "type/*".
+	MimeType *string
+	// MinSize - Min size in bytes, inclusive.
+	MinSize *int64
+	// Page - Page number, cannot be combined with cursor.
+	Page *int
+	// PerPage - Page size, defaults to APP_PAGE_SIZE.
+	PerPage *int
+	// Sort - Sort field, "-" prefix for descending.
+	Sort *string
+	// UploadedFrom - RFC 3339 or YYYY-MM-DD(UTC midnight), inclusive.
+	UploadedFrom *string
+	// UploadedTo - RFC 3339 or YYYY-MM-DD(UTC midnight), exclusive.
+	UploadedTo *string
+	// UserID - Files of one user only.
+	UserID *uuid.UUID
+}
+
+type ListRetentionRulesResponse struct {
+	Data []RetentionRule `json:"data"`
+}
+
+// PeekDeadLettersParams - the query and header parameters of PeekDeadLetters, the nil ones are not sent
+type PeekDeadLettersParams struct {
+	Limit *int
+}
+
+type ListSeatLimitsResponse struct {
+	Data []SeatLimit `json:"data,omitempty"`
+}
+
+type SetSeatLimitRequest struct {
+	Seats int `json:"seats"`
+}
+
+// GetSignupsStatsParams - the query and header parameters of GetSignupsStats, the nil ones are not sent
+type GetSignupsStatsParams struct {
+	// From - First day, inclusive. Defaults to 29 days before "to".
+	From *string
+	// To - Last day, inclusive. Defaults to today(UTC). At most 366 days in the range.
+	To *string
+}
+
+type ListUploadPoliciesResponse struct {
+	Data []UploadPolicy `json:"data,omitempty"`
+}
+
+// GetUsageParams - the query and header parameters of GetUsage, the nil ones are not sent
+type GetUsageParams struct {
+	// From - First day, inclusive. Defaults to 29 days before "to".
+	From *string
+	// Org - Only the requests of the organization, case-insensitive.
+	Org *string
+	// To - Last day, inclusive. Defaults to today(UTC). At most 366 days in the range.
+	To *string
+}
+
+// ExportMonthlyUsageParams - the query and header parameters of ExportMonthlyUsage, the nil ones are not sent
+type ExportMonthlyUsageParams struct {
+	// From - First month, inclusive. Defaults to "to".
+	From *string
+	// To - Last month, inclusive. Defaults to the current month(UTC). At most 24 months in the range.
+	To *string
+}
+
+// ListDeletedUsersParams - the query and header parameters of ListDeletedUsers, the nil ones are not sent
+type ListDeletedUsersParams struct {
+	// Cursor - Opaque keyset cursor from "next_cursor", only with created_at sort.
+	Cursor *string
+	// Page - Page number, cannot be combined with cursor.
+	Page *int
+	// PerPage - Page size, defaults to APP_PAGE_SIZE.
+	PerPage *int
+	// Reason - Users deleted for this reason only.
+	Reason *DeletionReason
+	// Sort - Sort field, "-" prefix for descending.
+	Sort *string
+}
+
+// LookupUserParams - the query and header parameters of LookupUser, the nil ones are not sent
+type LookupUserParams struct {
+	// ID - The internal ID
+	ID *int64
+	// UserID - The UUID
+	UserID *uuid.UUID
+}
+
+// GetUserSummaryParams - the query and header parameters of GetUserSummary, the nil ones are not sent
+type GetUserSummaryParams struct {
+	// Days - The days of activity, today(UTC) included.
+	Days *int
+}
+
+type RequestOTPResponse struct {
+	Message *string `json:"message,omitempty"`
+}
+
+// GetRawFileParams - the query and header parameters of GetRawFile, the nil ones are not sent
+type GetRawFileParams struct {
+	Range *string
+	// Disposition - By the type by default: inline for PDF, plain text, images(but SVG), audio and
+	// video, attachment for the rest. An inline one of another type is an attachment anyway.
+	Disposition *string
+	// Filename - The name to save the file under(Unicode allowed), the one of the key by default.
+	Filename *string
+}
+
+// HRSystemHookParams - the query and header parameters of HRSystemHook, the nil ones are not sent
+type HRSystemHookParams struct {
+	XSignature string
+	// XSignatureTimestamp - Unix time of the request, rejected when off by more than HOOKS_MAX_SKEW
+	XSignatureTimestamp int
+}
+
+// GetMeParams - the query and header parameters of GetMe, the nil ones are not sent
+type GetMeParams struct {
+	// Format - Profile export(attachment): vcard - contact import, pdf - printable. Available to the
+	// user itself and admins only.
+	Format *string
+}
+
+// ListMyFilesParams - the query and header parameters of ListMyFiles, the nil ones are not sent
+type ListMyFilesParams struct {
+	// Cursor - Opaque keyset cursor from "next_cursor", only with created_at sort.
+	Cursor *string
+	// Folder - Files of the folder only (not of its subfolders), empty - the root. All the files if
+	// absent.
+	Folder *string
+	// Page - Page number, cannot be combined with cursor.
+	Page *int
+	// PerPage - Page size, defaults to APP_PAGE_SIZE.
+	PerPage *int
+	// Sort - Sort field, "-" prefix for descending.
+	Sort *string
+	// Tag - Files having all given tags, repeated or comma separated.
+	Tag []string
+}
+
+// ListUsersParams - the query and header parameters of ListUsers, the nil ones are not sent
+type ListUsersParams struct {
+	// Cursor - Opaque keyset cursor from "next_cursor", only with created_at sort.
+	Cursor *string
+	// Page - Page number, cannot be combined with cursor.
+	Page *int
+	// PerPage - Page size, defaults to APP_PAGE_SIZE.
+	PerPage *int
+	// Sort - Sort field, "-" prefix for descending.
+	Sort *string
+}
+
+// ValidateUserParams - the query and header parameters of ValidateUser, the nil ones are not sent
+type ValidateUserParams struct {
+	// Fields - Comma separated fields of a step of a multi-step form, only they are checked. Any of
+	// email, name, middle_name, lastname, suffix, birth_date, phone, timezone.
+	Fields *string
+}
+
+// GetUserParams - the query and header parameters of GetUser, the nil ones are not sent
+type GetUserParams struct {
+	// Format - Profile export(attachment): vcard - contact import, pdf - printable. Available to the
+	// user itself and admins only.
+	Format *string
+}
+
+// GetUserResponse - one of PublicUser, User, AdminUser, the As methods decode it as one of them
+type GetUserResponse struct {
+	json.RawMessage
+}
+
+func (u GetUserResponse) AsPublicUser() (PublicUser, error) {
+	var v PublicUser
+	err := json.Unmarshal(u.RawMessage, &v)
+	return v, err
+}
+
+func (u GetUserResponse) AsUser() (User, error) {
+	var v User
+	err := json.Unmarshal(u.RawMessage, &v)
+	return v, err
+}
+
+func (u GetUserResponse) AsAdminUser() (AdminUser, error) {
+	var v AdminUser
+	err := json.Unmarshal(u.RawMessage, &v)
+	return v, err
+}
+
+// DeleteUserParams - the query and header parameters of DeleteUser, the nil ones are not sent
+type DeleteUserParams struct {
+	// ConfirmSelf - Must be true to delete the own account.
+	ConfirmSelf *bool
+	// Reason - Stored as deleted_reason and sent in the UserDeleted event meta. Defaults to
+	// user_request for the own account and to admin_action for an admin deleting another one. Only
+	// admins set other reasons than user_request, merged is set by the merge only(400).
+	Reason *DeletionReason
+}
+
+// ListUserFilesParams - the query and header parameters of ListUserFiles, the nil ones are not sent
+type ListUserFilesParams struct {
+	// Cursor - Opaque keyset cursor from "next_cursor", only with created_at sort.
+	Cursor *string
+	// Folder - Files of the folder only (not of its subfolders), empty - the root. All the files if
+	// absent.
+	Folder *string
+	// Page - Page number, cannot be combined with cursor.
+	Page *int
+	// PerPage - Page size, defaults to APP_PAGE_SIZE.
+	PerPage *int
+	// Sort - Sort field, "-" prefix for descending.
+	Sort *string
+	// Tag - Files having all given tags, repeated or comma separated.
+	Tag []string
+}
+
+// CreateUserFileParams - the query and header parameters of CreateUserFile, the nil ones are not sent
+type CreateUserFileParams struct {
+	// UploadID - Client chosen id to follow the upload at GET /uploads/{upload_id}/progress.
+	UploadID *uuid.UUID
+}
+
+// DeleteUserFilesParams - the query and header parameters of DeleteUserFiles, the nil ones are not sent
+type DeleteUserFilesParams struct {
+	// Tag - Files having all given tags, repeated or comma separated.
+	Tag []string
+}
+
+// SearchUserFilesParams - the query and header parameters of SearchUserFiles, the nil ones are not sent
+type SearchUserFilesParams struct {
+	Limit *int
+	Q     string
+}
+
+type SearchUserFilesResponse struct {
+	Data []UserFile `json:"data"`
+}
+
+// ListUserFoldersParams - the query and header parameters of ListUserFolders, the nil ones are not sent
+type ListUserFoldersParams struct {
+	// Parent - Folder path as "a/b", the root by default.
+	Parent *string
+}
+
+type ListUserFoldersResponse struct {
+	Data []Folder `json:"data"`
+}
+
+type MoveUserFolderResponse struct {
+	Moved int64 `json:"moved"`
+}
+
+// GetUserHistoryParams - the query and header parameters of GetUserHistory, the nil ones are not sent
+type GetUserHistoryParams struct {
+	// AsOf - The version valid at that time instead of the history
+	AsOf *time.Time
+}
+
+// GetUserHistoryResponse - one of HistoryResponse, VersionResponse, the As methods decode it as one of them
+type GetUserHistoryResponse struct {
+	json.RawMessage
+}
+
+func (u GetUserHistoryResponse) AsHistoryResponse() (HistoryResponse, error) {
+	var v HistoryResponse
+	err := json.Unmarshal(u.RawMessage, &v)
+	return v, err
+}
+
+func (u GetUserHistoryResponse) AsVersionResponse() (VersionResponse, error) {
+	var v VersionResponse
+	err := json.Unmarshal(u.RawMessage, &v)
+	return v, err
+}
+
+// ListUserNotesParams - the query and header parameters of ListUserNotes, the nil ones are not sent
+type ListUserNotesParams struct {
+	// Cursor - Opaque keyset cursor from "next_cursor", only with created_at sort.
+	Cursor *string
+	// Page - Page number, cannot be combined with cursor.
+	Page *int
+	// PerPage - Page size, defaults to APP_PAGE_SIZE.
+	PerPage *int
+}
+
+// ListAuditEntries - Browse the audit log (with pagination)
+//
+// GET /admin/audit
+func (c *Client) ListAuditEntries(ctx context.Context, params *ListAuditEntriesParams) (*AuditListResponse, error) {
+	q, h := url.Values{}, http.Header{}
+	if params != nil {
+		if params.Action != nil {
+			q.Set("action", *params.Action)
+		}
+		if params.ActorID != nil {
+			q.Set("actor_id", (*params.ActorID).String())
+		}
+		if params.From != nil {
+			q.Set("from", *params.From)
+		}
+		if params.Page != nil {
+			q.Set("page", fmt.Sprint(*params.Page))
+		}
+		if params.PerPage != nil {
+			q.Set("per_page", fmt.Sprint(*params.PerPage))
+		}
+		if params.Sort != nil {
+			q.Set("sort", *params.Sort)
+		}
+		if params.TargetID != nil {
+			q.Set("target_id", (*params.TargetID).String())
+		}
+		if params.To != nil {
+			q.Set("to", *params.To)
+		}
+	}
+	var out AuditListResponse
+	if err := c.do(ctx, request{method: http.MethodGet, path: "/admin/audit", query: q, header: h}, &out); err != nil {
+		return nil, err
+	}
+
+	return &out, nil
+}
+
+// GetCollection - Example requests of the API
+//
+// GET /admin/collection
+func (c *Client) GetCollection(ctx context.Context, params *GetCollectionParams) (map[string]any, error) {
+	q, h := url.Values{}, http.Header{}
+	if params != nil {
+		if params.Format != nil {
+			q.Set("format", *params.Format)
+		}
+	}
+	var out map[string]any
+	if err := c.do(ctx, request{method: http.MethodGet, path: "/admin/collection", query: q, header: h}, &out); err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+// GetSlowQueries - The slow statements of the answering instance
+//
+// GET /admin/db/slow-queries
+func (c *Client) GetSlowQueries(ctx context.Context, params *GetSlowQueriesParams) (*SlowQueries, error) {
+	q, h := url.Values{}, http.Header{}
+	if params != nil {
+		if params.Limit != nil {
+			q.Set("limit", fmt.Sprint(*params.Limit))
+		}
+	}
+	var out SlowQueries
+	if err := c.do(ctx, request{method: http.MethodGet, path: "/admin/db/slow-queries", query: q, header: h}, &out); err != nil {
+		return nil, err
+	}
+
+	return &out, nil
+}
+
+// GetDirectorySync - The summary of the latest LDAP/Active Directory sync (sync-directory job)
+//
+// GET /admin/directory/sync
+func (c *Client) GetDirectorySync(ctx context.Context) (*DirectorySync, error) {
+	var out DirectorySync
+	if err := c.do(ctx, request{method: http.MethodGet, path: "/admin/directory/sync"}, &out); err != nil {
+		return nil, err
+	}
+
+	return &out, nil
+}
+
+// ListAdminFiles - Browse files of all users with aggregate stats (storage governance)
+//
+// GET /admin/files
+func (c *Client) ListAdminFiles(ctx context.Context, params *ListAdminFilesParams) (*AdminFilesListResponse, error) {
+	q, h := url.Values{}, http.Header{}
+	if params != nil {
+		if params.Cursor != nil {
+			q.Set("cursor", *params.Cursor)
+		}
+		if params.MaxSize != nil {
+			q.Set("max_size", fmt.Sprint(*params.MaxSize))
+		}
+		if params.MimeType != nil {
+			q.Set("mime_type", *params.MimeType)
+		}
+		if params.MinSize != nil {
+			q.Set("min_size", fmt.Sprint(*params.MinSize))
+		}
+		if params.Page != nil {
+			q.Set("page", fmt.Sprint(*params.Page))
+		}
+		if params.PerPage != nil {
+			q.Set("per_page", fmt.Sprint(*params.PerPage))
+		}
+		if params.Sort != nil {
+			q.Set("sort", *params.Sort)
+		}
+		if params.UploadedFrom != nil {
+			q.Set("uploaded_from", *params.UploadedFrom)
+		}
+		if params.UploadedTo != nil {
+			q.Set("uploaded_to", *params.UploadedTo)
+		}
+		if params.UserID != nil {
+			q.Set("user_id", (*params.UserID).String())
+		}
+	}
+	var out AdminFilesListResponse
+	if err := c.do(ctx, request{method: http.MethodGet, path: "/admin/files", query: q, header: h}, &out); err != nil {
+		return nil, err
+	}
+
+	return &out, nil
+}
+
+// ListRetentionRules - List the file retention rules
+//
+// GET /admin/files/retention-rules
+func (c *Client) ListRetentionRules(ctx context.Context) (*ListRetentionRulesResponse, error) {
+	var out ListRetentionRulesResponse
+	if err := c.do(ctx, request{method: http.MethodGet, path: "/admin/files/retention-rules"}, &out); err != nil {
+		return nil, err
+	}
+
+	return &out, nil
+}
+
+// CreateRetentionRule - Create a file retention rule
+//
+// POST /admin/files/retention-rules
+func (c *Client) CreateRetentionRule(ctx context.Context, body RetentionRuleRequest) (*RetentionRule, error) {
+	var out RetentionRule
+	if err := c.do(ctx, request{method: http.MethodPost, path: "/admin/files/retention-rules", json: body}, &out); err != nil {
+		return nil, err
+	}
+
+	return &out, nil
+}
+
+// DeleteRetentionRule - Delete a file retention rule
+//
+// DELETE /admin/files/retention-rules/{rule_id}
+func (c *Client) DeleteRetentionRule(ctx context.Context, ruleID uuid.UUID) error {
+	return c.do(ctx, request{method: http.MethodDelete, path: "/admin/files/retention-rules/" + url.PathEscape(ruleID.String())}, nil)
+}
+
+// SetFileRetention - Set the retention override and the legal hold of a file
+//
+// PUT /admin/files/{file_id}/retention
+func (c *Client) SetFileRetention(ctx context.Context, fileID uuid.UUID, body FileRetentionRequest) (*FileRetention, error) {
+	var out FileRetention
+	if err := c.do(ctx, request{method: http.MethodPut, path: "/admin/files/" + url.PathEscape(fileID.String()) + "/retention", json: body}, &out); err != nil {
+		return nil, err
+	}
+
+	return &out, nil
+}
+
+// ImpersonateUser - Issue a short-lived token acting as the user (audited)
+//
+// POST /admin/impersonate/{user_id}
+func (c *Client) ImpersonateUser(ctx context.Context, userID uuid.UUID) (*ImpersonationTokenResponse, error) {
+	var out ImpersonationTokenResponse
+	if err := c.do(ctx, request{method: http.MethodPost, path: "/admin/impersonate/" + url.PathEscape(userID.String())}, &out); err != nil {
+		return nil, err
+	}
+
+	return &out, nil
+}
+
+// PeekDeadLetters - Peek the dead-letter queue
+//
+// GET /admin/mq/dead-letters
+func (c *Client) PeekDeadLetters(ctx context.Context, params *PeekDeadLettersParams) (*DeadLetters, error) {
+	q, h := url.Values{}, http.Header{}
+	if params != nil {
+		if params.Limit != nil {
+			q.Set("limit", fmt.Sprint(*params.Limit))
+		}
+	}
+	var out DeadLetters
+	if err := c.do(ctx, request{method: http.MethodGet, path: "/admin/mq/dead-letters", query: q, header: h}, &out); err != nil {
+		return nil, err
+	}
+
+	return &out, nil
+}
+
+// DiscardDeadLetters - Discard dead-lettered messages
+//
+// POST /admin/mq/dead-letters/discard
+func (c *Client) DiscardDeadLetters(ctx context.Context, body DeadLettersRequest) (*DeadLettersResult, error) {
+	var out DeadLettersResult
+	if err := c.do(ctx, request{method: http.MethodPost, path: "/admin/mq/dead-letters/discard", json: body}, &out); err != nil {
+		return nil, err
+	}
+
+	return &out, nil
+}
+
+// RequeueDeadLetters - Requeue dead-lettered messages
+//
+// POST /admin/mq/dead-letters/requeue
+func (c *Client) RequeueDeadLetters(ctx context.Context, body DeadLettersRequest) (*DeadLettersResult, error) {
+	var out DeadLettersResult
+	if err := c.do(ctx, request{method: http.MethodPost, path: "/admin/mq/dead-letters/requeue", json: body}, &out); err != nil {
+		return nil, err
+	}
+
+	return &out, nil
+}
+
+// GetMQTopology - The RabbitMQ topology against the configuration
+//
+// GET /admin/mq/topology
+func (c *Client) GetMQTopology(ctx context.Context) (*MQTopology, error) {
+	var out MQTopology
+	if err := c.do(ctx, request{method: http.MethodGet, path: "/admin/mq/topology"}, &out); err != nil {
+		return nil, err
+	}
+
+	return &out, nil
+}
+
+// RepairMQTopology - Repair the RabbitMQ topology
+//
+// POST /admin/mq/topology/repair
+func (c *Client) RepairMQTopology(ctx context.Context) (*MQTopology, error) {
+	var out MQTopology
+	if err := c.do(ctx, request{method: http.MethodPost, path: "/admin/mq/topology/repair"}, &out); err != nil {
+		return nil, err
+	}
+
+	return &out, nil
+}
+
+// GetReadOnly - The read-only mode
+//
+// GET /admin/read-only
+func (c *Client) GetReadOnly(ctx context.Context) (*ReadOnly, error) {
+	var out ReadOnly
+	if err := c.do(ctx, request{method: http.MethodGet, path: "/admin/read-only"}, &out); err != nil {
+		return nil, err
+	}
+
+	return &out, nil
+}
+
+// SetReadOnly - Toggle the read-only mode of every instance
+//
+// PUT /admin/read-only
+func (c *Client) SetReadOnly(ctx context.Context, body ReadOnlyRequest) (*ReadOnly, error) {
+	var out ReadOnly
+	if err := c.do(ctx, request{method: http.MethodPut, path: "/admin/read-only", json: body}, &out); err != nil {
+		return nil, err
+	}
+
+	return &out, nil
+}
+
+// ListSeatLimits - Seat limits of the organizations(email domains) with their active users
+//
+// GET /admin/seat-limits
+func (c *Client) ListSeatLimits(ctx context.Context) (*ListSeatLimitsResponse, error) {
+	var out ListSeatLimitsResponse
+	if err := c.do(ctx, request{method: http.MethodGet, path: "/admin/seat-limits"}, &out); err != nil {
+		return nil, err
+	}
+
+	return &out, nil
+}
+
+// SetSeatLimit - Set the seat limit of an organization
+//
+// PUT /admin/seat-limits/{org}
+func (c *Client) SetSeatLimit(ctx context.Context, org string, body SetSeatLimitRequest) (*SeatLimit, error) {
+	var out SeatLimit
+	if err := c.do(ctx, request{method: http.MethodPut, path: "/admin/seat-limits/" + url.PathEscape(org), json: body}, &out); err != nil {
+		return nil, err
+	}
+
+	return &out, nil
+}
+
+// RemoveSeatLimit - Remove the seat limit of an organization(unlimited)
+//
+// DELETE /admin/seat-limits/{org}
+func (c *Client) RemoveSeatLimit(ctx context.Context, org string) error {
+	return c.do(ctx, request{method: http.MethodDelete, path: "/admin/seat-limits/" + url.PathEscape(org)}, nil)
+}
+
+// GetFilesTotals - Storage totals of all users, from the maintained aggregate
+//
+// GET /admin/stats/files
+func (c *Client) GetFilesTotals(ctx context.Context) (*FilesTotals, error) {
+	var out FilesTotals
+	if err := c.do(ctx, request{method: http.MethodGet, path: "/admin/stats/files"}, &out); err != nil {
+		return nil, err
+	}
+
+	return &out, nil
+}
+
+// GetSignupsStats - Signups per UTC day, from the maintained aggregate
+//
+// GET /admin/stats/signups
+func (c *Client) GetSignupsStats(ctx context.Context, params *GetSignupsStatsParams) (*SignupsStatsResponse, error) {
+	q, h := url.Values{}, http.Header{}
+	if params != nil {
+		if params.From != nil {
+			q.Set("from", *params.From)
+		}
+		if params.To != nil {
+			q.Set("to", *params.To)
+		}
+	}
+	var out SignupsStatsResponse
+	if err := c.do(ctx, request{method: http.MethodGet, path: "/admin/stats/signups", query: q, header: h}, &out); err != nil {
+		return nil, err
+	}
+
+	return &out, nil
+}
+
+// ListUploadPolicies - Upload policies of the organizations(email domains)
+//
+// GET /admin/upload-policies
+func (c *Client) ListUploadPolicies(ctx context.Context) (*ListUploadPoliciesResponse, error) {
+	var out ListUploadPoliciesResponse
+	if err := c.do(ctx, request{method: http.MethodGet, path: "/admin/upload-policies"}, &out); err != nil {
+		return nil, err
+	}
+
+	return &out, nil
+}
+
+// SetUploadPolicy - Replace the upload policy of an organization
+//
+// PUT /admin/upload-policies/{org}
+func (c *Client) SetUploadPolicy(ctx context.Context, org string, body UploadPolicyRequest) (*UploadPolicy, error) {
+	var out UploadPolicy
+	if err := c.do(ctx, request{method: http.MethodPut, path: "/admin/upload-policies/" + url.PathEscape(org), json: body}, &out); err != nil {
+		return nil, err
+	}
+
+	return &out, nil
+}
+
+// RemoveUploadPolicy - Remove the upload policy of an organization(the global limits only)
+//
+// DELETE /admin/upload-policies/{org}
+func (c *Client) RemoveUploadPolicy(ctx context.Context, org string) error {
+	return c.do(ctx, request{method: http.MethodDelete, path: "/admin/upload-policies/" + url.PathEscape(org)}, nil)
+}
+
+// GetUsage - Requests per organization(email domain) and role, from the usage rollups
+//
+// GET /admin/usage
+func (c *Client) GetUsage(ctx context.Context, params *GetUsageParams) (*UsageReportResponse, error) {
+	q, h := url.Values{}, http.Header{}
+	if params != nil {
+		if params.From != nil {
+			q.Set("from", *params.From)
+		}
+		if params.Org != nil {
+			q.Set("org", *params.Org)
+		}
+		if params.To != nil {
+			q.Set("to", *params.To)
+		}
+	}
+	var out UsageReportResponse
+	if err := c.do(ctx, request{method: http.MethodGet, path: "/admin/usage", query: q, header: h}, &out); err != nil {
+		return nil, err
+	}
+
+	return &out, nil
+}
+
+// ExportMonthlyUsage - Monthly billing records per organization(email domain) as CSV
+//
+// GET /admin/usage/monthly
+func (c *Client) ExportMonthlyUsage(ctx context.Context, params *ExportMonthlyUsageParams) ([]byte, error) {
+	q, h := url.Values{}, http.Header{}
+	if params != nil {
+		if params.From != nil {
+			q.Set("from", *params.From)
+		}
+		if params.To != nil {
+			q.Set("to", *params.To)
+		}
+	}
+	var out []byte
+	if err := c.do(ctx, request{method: http.MethodGet, path: "/admin/usage/monthly", accept: "text/csv", query: q, header: h}, &out); err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+// ListDeletedUsers - Browse soft deleted users (with pagination)
+//
+// GET /admin/users/deleted
+func (c *Client) ListDeletedUsers(ctx context.Context, params *ListDeletedUsersParams) (*UsersListResponse, error) {
+	q, h := url.Values{}, http.Header{}
+	if params != nil {
+		if params.Cursor != nil {
+			q.Set("cursor", *params.Cursor)
+		}
+		if params.Page != nil {
+			q.Set("page", fmt.Sprint(*params.Page))
+		}
+		if params.PerPage != nil {
+			q.Set("per_page", fmt.Sprint(*params.PerPage))
+		}
+		if params.Reason != nil {
+			q.Set("reason", fmt.Sprint(*params.Reason))
+		}
+		if params.Sort != nil {
+			q.Set("sort", *params.Sort)
+		}
+	}
+	var out UsersListResponse
+	if err := c.do(ctx, request{method: http.MethodGet, path: "/admin/users/deleted", query: q, header: h}, &out); err != nil {
+		return nil, err
+	}
+
+	return &out, nil
+}
+
+// FindDuplicates - The groups of active users sharing a phone, an email alias or a name and birth
+// date
+//
+// GET /admin/users/duplicates
+func (c *Client) FindDuplicates(ctx context.Context) (*DuplicatesResponse, error) {
+	var out DuplicatesResponse
+	if err := c.do(ctx, request{method: http.MethodGet, path: "/admin/users/duplicates"}, &out); err != nil {
+		return nil, err
+	}
+
+	return &out, nil
+}
+
+// LookupUser - Resolve the internal ID of a user to its UUID and back (audited)
+//
+// GET /admin/users/lookup
+func (c *Client) LookupUser(ctx context.Context, params *LookupUserParams) (*UserIdentity, error) {
+	q, h := url.Values{}, http.Header{}
+	if params != nil {
+		if params.ID != nil {
+			q.Set("id", fmt.Sprint(*params.ID))
+		}
+		if params.UserID != nil {
+			q.Set("user_id", (*params.UserID).String())
+		}
+	}
+	var out UserIdentity
+	if err := c.do(ctx, request{method: http.MethodGet, path: "/admin/users/lookup", query: q, header: h}, &out); err != nil {
+		return nil, err
+	}
+
+	return &out, nil
+}
+
+// MergeUsers - Merge a duplicate account into another one (audited)
+//
+// POST /admin/users/merge
+func (c *Client) MergeUsers(ctx context.Context, body MergeRequest) (*MergeResponse, error) {
+	var out MergeResponse
+	if err := c.do(ctx, request{method: http.MethodPost, path: "/admin/users/merge", json: body}, &out); err != nil {
+		return nil, err
+	}
+
+	return &out, nil
+}
+
+// AssignRoles - Assign roles in bulk (audited), a result per assignment
+//
+// POST /admin/users/roles
+func (c *Client) AssignRoles(ctx context.Context, body RolesRequest) (*RolesResponse, error) {
+	var out RolesResponse
+	if err := c.do(ctx, request{method: http.MethodPost, path: "/admin/users/roles", json: body}, &out); err != nil {
+		return nil, err
+	}
+
+	return &out, nil
+}
+
+// ForcePasswordReset - Revoke the user tokens and require a password change on next login (audited)
+//
+// POST /admin/users/{user_id}/force-reset
+func (c *Client) ForcePasswordReset(ctx context.Context, userID uuid.UUID) error {
+	return c.do(ctx, request{method: http.MethodPost, path: "/admin/users/" + url.PathEscape(userID.String()) + "/force-reset"}, nil)
+}
+
+// GetLegalHold - The legal hold of a user
+//
+// GET /admin/users/{user_id}/legal-hold
+func (c *Client) GetLegalHold(ctx context.Context, userID uuid.UUID) (*LegalHold, error) {
+	var out LegalHold
+	if err := c.do(ctx, request{method: http.MethodGet, path: "/admin/users/" + url.PathEscape(userID.String()) + "/legal-hold"}, &out); err != nil {
+		return nil, err
+	}
+
+	return &out, nil
+}
+
+// PlaceLegalHold - Place a user on a legal hold (audited)
+//
+// POST /admin/users/{user_id}/legal-hold
+func (c *Client) PlaceLegalHold(ctx context.Context, userID uuid.UUID, body LegalHoldRequest) (*LegalHold, error) {
+	var out LegalHold
+	if err := c.do(ctx, request{method: http.MethodPost, path: "/admin/users/" + url.PathEscape(userID.String()) + "/legal-hold", json: body}, &out); err != nil {
+		return nil, err
+	}
+
+	return &out, nil
+}
+
+// ReleaseLegalHold - Release the legal hold of a user (audited)
+//
+// DELETE /admin/users/{user_id}/legal-hold
+func (c *Client) ReleaseLegalHold(ctx context.Context, userID uuid.UUID) error {
+	return c.do(ctx, request{method: http.MethodDelete, path: "/admin/users/" + url.PathEscape(userID.String()) + "/legal-hold"}, nil)
+}
+
+// ReactivateUser - Lift the suspension of a user inactive for too long (audited)
+//
+// POST /admin/users/{user_id}/reactivate
+func (c *Client) ReactivateUser(ctx context.Context, userID uuid.UUID) error {
+	return c.do(ctx, request{method: http.MethodPost, path: "/admin/users/" + url.PathEscape(userID.String()) + "/reactivate"}, nil)
+}
+
+// GetUserSummary - The summary of a user, projected from the event store
+//
+// GET /admin/users/{user_id}/summary
+func (c *Client) GetUserSummary(ctx context.Context, userID uuid.UUID, params *GetUserSummaryParams) (*UserSummary, error) {
+	q, h := url.Values{}, http.Header{}
+	if params != nil {
+		if params.Days != nil {
+			q.Set("days", fmt.Sprint(*params.Days))
+		}
+	}
+	var out UserSummary
+	if err := c.do(ctx, request{method: http.MethodGet, path: "/admin/users/" + url.PathEscape(userID.String()) + "/summary", query: q, header: h}, &out); err != nil {
+		return nil, err
+	}
+
+	return &out, nil
+}
+
+// ConfirmEmailChange - Confirm the email change with the token from the confirmation link
+//
+// POST /auth/email/confirm
+func (c *Client) ConfirmEmailChange(ctx context.Context, body EmailConfirmRequest) (*User, error) {
+	var out User
+	if err := c.do(ctx, request{method: http.MethodPost, path: "/auth/email/confirm", json: body}, &out); err != nil {
+		return nil, err
+	}
+
+	return &out, nil
+}
+
+// Login - Login
+//
+// POST /auth/login
+func (c *Client) Login(ctx context.Context, body LoginRequest) (*AuthTokenResponse, error) {
+	var out AuthTokenResponse
+	if err := c.do(ctx, request{method: http.MethodPost, path: "/auth/login", json: body}, &out); err != nil {
+		return nil, err
+	}
+
+	return &out, nil
+}
+
+// RequestOTP - Send a one-time login code by SMS
+//
+// POST /auth/otp/request
+func (c *Client) RequestOTP(ctx context.Context, body OTPRequest) (*RequestOTPResponse, error) {
+	var out RequestOTPResponse
+	if err := c.do(ctx, request{method: http.MethodPost, path: "/auth/otp/request", json: body}, &out); err != nil {
+		return nil, err
+	}
+
+	return &out, nil
+}
+
+// VerifyOTP - Exchange the one-time code for an access token
+//
+// POST /auth/otp/verify
+func (c *Client) VerifyOTP(ctx context.Context, body OTPVerifyRequest) (*AuthTokenResponse, error) {
+	var out AuthTokenResponse
+	if err := c.do(ctx, request{method: http.MethodPost, path: "/auth/otp/verify", json: body}, &out); err != nil {
+		return nil, err
+	}
+
+	return &out, nil
+}
+
+// ChangePassword - Change the password with the current credentials
+//
+// POST /auth/password
+func (c *Client) ChangePassword(ctx context.Context, body PasswordChangeRequest) (*AuthTokenResponse, error) {
+	var out AuthTokenResponse
+	if err := c.do(ctx, request{method: http.MethodPost, path: "/auth/password", json: body}, &out); err != nil {
+		return nil, err
+	}
+
+	return &out, nil
+}
+
+// GetRawFile - Download a raw file (STORAGE_DRIVER=fs or S3_DOWNLOAD_MODE=proxy only)
+//
+// GET /files/raw/{key}
+func (c *Client) GetRawFile(ctx context.Context, key string, params *GetRawFileParams) ([]byte, error) {
+	q, h := url.Values{}, http.Header{}
+	if params != nil {
+		if params.Range != nil {
+			h.Set("Range", *params.Range)
+		}
+		if params.Disposition != nil {
+			q.Set("disposition", *params.Disposition)
+		}
+		if params.Filename != nil {
+			q.Set("filename", *params.Filename)
+		}
+	}
+	var out []byte
+	if err := c.do(ctx, request{method: http.MethodGet, path: "/files/raw/" + url.PathEscape(key), accept: "application/octet-stream", query: q, header: h}, &out); err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+// HRSystemHook - Employee events of the HR system, upserted by email (HMAC signed)
+//
+// POST /hooks/hr-system
+func (c *Client) HRSystemHook(ctx context.Context, params *HRSystemHookParams, body HREvent) (*HRResult, error) {
+	q, h := url.Values{}, http.Header{}
+	if params != nil {
+		h.Set("X-Signature", params.XSignature)
+		h.Set("X-Signature-Timestamp", fmt.Sprint(params.XSignatureTimestamp))
+	}
+	var out HRResult
+	if err := c.do(ctx, request{method: http.MethodPost, path: "/hooks/hr-system", json: body, query: q, header: h}, &out); err != nil {
+		return nil, err
+	}
+
+	return &out, nil
+}
+
+// CreateInvitation - Invite a user by email (admin only, InvitationCreated event)
+//
+// POST /invitations
+func (c *Client) CreateInvitation(ctx context.Context, body InvitationRequest) (*Invitation, error) {
+	var out Invitation
+	if err := c.do(ctx, request{method: http.MethodPost, path: "/invitations", json: body}, &out); err != nil {
+		return nil, err
+	}
+
+	return &out, nil
+}
+
+// AcceptInvitation - Sign up by invitation, the email and role come from the invitation
+//
+// POST /invitations/{token}/accept
+func (c *Client) AcceptInvitation(ctx context.Context, token string, body InvitationAcceptRequest) (*User, error) {
+	var out User
+	if err := c.do(ctx, request{method: http.MethodPost, path: "/invitations/" + url.PathEscape(token) + "/accept", json: body}, &out); err != nil {
+		return nil, err
+	}
+
+	return &out, nil
+}
+
+// GetMe - Get the profile of the token subject
+//
+// GET /me
+func (c *Client) GetMe(ctx context.Context, params *GetMeParams) (*User, error) {
+	q, h := url.Values{}, http.Header{}
+	if params != nil {
+		if params.Format != nil {
+			q.Set("format", *params.Format)
+		}
+	}
+	var out User
+	if err := c.do(ctx, request{method: http.MethodGet, path: "/me", query: q, header: h}, &out); err != nil {
+		return nil, err
+	}
+
+	return &out, nil
+}
+
+// UpdateMe - Update the profile of the token subject
+//
+// PUT /me
+func (c *Client) UpdateMe(ctx context.Context, body UserRequest) (*User, error) {
+	var out User
+	if err := c.do(ctx, request{method: http.MethodPut, path: "/me", json: body}, &out); err != nil {
+		return nil, err
+	}
+
+	return &out, nil
+}
+
+// ListMyFiles - Get files of the token subject (with pagination)
+//
+// GET /me/files
+func (c *Client) ListMyFiles(ctx context.Context, params *ListMyFilesParams) (*UserFilesListResponse, error) {
+	q, h := url.Values{}, http.Header{}
+	if params != nil {
+		if params.Cursor != nil {
+			q.Set("cursor", *params.Cursor)
+		}
+		if params.Folder != nil {
+			q.Set("folder", *params.Folder)
+		}
+		if params.Page != nil {
+			q.Set("page", fmt.Sprint(*params.Page))
+		}
+		if params.PerPage != nil {
+			q.Set("per_page", fmt.Sprint(*params.PerPage))
+		}
+		if params.Sort != nil {
+			q.Set("sort", *params.Sort)
+		}
+		for _, v := range params.Tag {
+			q.Add("tag", v)
+		}
+	}
+	var out UserFilesListResponse
+	if err := c.do(ctx, request{method: http.MethodGet, path: "/me/files", query: q, header: h}, &out); err != nil {
+		return nil, err
+	}
+
+	return &out, nil
+}
+
+// GetNotificationPreferences - The notification preferences of the token subject, every channel
+//
+// GET /me/notifications
+func (c *Client) GetNotificationPreferences(ctx context.Context) (*NotificationPreferences, error) {
+	var out NotificationPreferences
+	if err := c.do(ctx, request{method: http.MethodGet, path: "/me/notifications"}, &out); err != nil {
+		return nil, err
+	}
+
+	return &out, nil
+}
+
+// SetNotificationPreferences - Set the listed channels, the others are kept
+//
+// PUT /me/notifications
+func (c *Client) SetNotificationPreferences(ctx context.Context, body NotificationPreferences) (*NotificationPreferences, error) {
+	var out NotificationPreferences
+	if err := c.do(ctx, request{method: http.MethodPut, path: "/me/notifications", json: body}, &out); err != nil {
+		return nil, err
+	}
+
+	return &out, nil
+}
+
+// GetUploadProgress - Get the progress of an upload
+//
+// GET /uploads/{upload_id}/progress
+func (c *Client) GetUploadProgress(ctx context.Context, uploadID uuid.UUID) (*UploadProgress, error) {
+	var out UploadProgress
+	if err := c.do(ctx, request{method: http.MethodGet, path: "/uploads/" + url.PathEscape(uploadID.String()) + "/progress"}, &out); err != nil {
+		return nil, err
+	}
+
+	return &out, nil
+}
+
+// ListUsers - Get list of users (with pagination, admin only)
+//
+// GET /users
+func (c *Client) ListUsers(ctx context.Context, params *ListUsersParams) (*UsersListResponse, error) {
+	q, h := url.Values{}, http.Header{}
+	if params != nil {
+		if params.Cursor != nil {
+			q.Set("cursor", *params.Cursor)
+		}
+		if params.Page != nil {
+			q.Set("page", fmt.Sprint(*params.Page))
+		}
+		if params.PerPage != nil {
+			q.Set("per_page", fmt.Sprint(*params.PerPage))
+		}
+		if params.Sort != nil {
+			q.Set("sort", *params.Sort)
+		}
+	}
+	var out UsersListResponse
+	if err := c.do(ctx, request{method: http.MethodGet, path: "/users", query: q, header: h}, &out); err != nil {
+		return nil, err
+	}
+
+	return &out, nil
+}
+
+// CreateUser - Create a new user
+//
+// POST /users
+func (c *Client) CreateUser(ctx context.Context, body UserRequest) (*User, error) {
+	var out User
+	if err := c.do(ctx, request{method: http.MethodPost, path: "/users", json: body}, &out); err != nil {
+		return nil, err
+	}
+
+	return &out, nil
+}
+
+// ValidateUser - Validate a user like POST /users does, without creating it
+//
+// POST /users/validate
+func (c *Client) ValidateUser(ctx context.Context, params *ValidateUserParams, body UserRequest) (*ValidationResponse, error) {
+	q, h := url.Values{}, http.Header{}
+	if params != nil {
+		if params.Fields != nil {
+			q.Set("fields", *params.Fields)
+		}
+	}
+	var out ValidationResponse
+	if err := c.do(ctx, request{method: http.MethodPost, path: "/users/validate", json: body, query: q, header: h}, &out); err != nil {
+		return nil, err
+	}
+
+	return &out, nil
+}
+
+// GetUser - Get user by UUID
+//
+// GET /users/{user_id}
+func (c *Client) GetUser(ctx context.Context, userID uuid.UUID, params *GetUserParams) (*GetUserResponse, error) {
+	q, h := url.Values{}, http.Header{}
+	if params != nil {
+		if params.Format != nil {
+			q.Set("format", *params.Format)
+		}
+	}
+	var out GetUserResponse
+	if err := c.do(ctx, request{method: http.MethodGet, path: "/users/" + url.PathEscape(userID.String()), query: q, header: h}, &out); err != nil {
+		return nil, err
+	}
+
+	return &out, nil
+}
+
+// UpdateUser - Update user by UUID
+//
+// PUT /users/{user_id}
+func (c *Client) UpdateUser(ctx context.Context, userID uuid.UUID, body UserRequest) (*User, error) {
+	var out User
+	if err := c.do(ctx, request{method: http.MethodPut, path: "/users/" + url.PathEscape(userID.String()), json: body}, &out); err != nil {
+		return nil, err
+	}
+
+	return &out, nil
+}
+
+// DeleteUser - Delete user by UUID
+//
+// DELETE /users/{user_id}
+func (c *Client) DeleteUser(ctx context.Context, userID uuid.UUID, params *DeleteUserParams) error {
+	q, h := url.Values{}, http.Header{}
+	if params != nil {
+		if params.ConfirmSelf != nil {
+			q.Set("confirm_self", fmt.Sprint(*params.ConfirmSelf))
+		}
+		if params.Reason != nil {
+			q.Set("reason", fmt.Sprint(*params.Reason))
+		}
+	}
+	return c.do(ctx, request{method: http.MethodDelete, path: "/users/" + url.PathEscape(userID.String()), query: q, header: h}, nil)
+}
+
+// ListUserFiles - Get user’s files (with pagination)
+//
+// GET /users/{user_id}/files
+func (c *Client) ListUserFiles(ctx context.Context, userID uuid.UUID, params *ListUserFilesParams) (*UserFilesListResponse, error) {
+	q, h := url.Values{}, http.Header{}
+	if params != nil {
+		if params.Cursor != nil {
+			q.Set("cursor", *params.Cursor)
+		}
+		if params.Folder != nil {
+			q.Set("folder", *params.Folder)
+		}
+		if params.Page != nil {
+			q.Set("page", fmt.Sprint(*params.Page))
+		}
+		if params.PerPage != nil {
+			q.Set("per_page", fmt.Sprint(*params.PerPage))
+		}
+		if params.Sort != nil {
+			q.Set("sort", *params.Sort)
+		}
+		for _, v := range params.Tag {
+			q.Add("tag", v)
+		}
+	}
+	var out UserFilesListResponse
+	if err := c.do(ctx, request{method: http.MethodGet, path: "/users/" + url.PathEscape(userID.String()) + "/files", query: q, header: h}, &out); err != nil {
+		return nil, err
+	}
+
+	return &out, nil
+}
+
+// CreateUserFile - Upload a file for a user
+//
+// POST /users/{user_id}/files
+func (c *Client) CreateUserFile(ctx context.Context, userID uuid.UUID, params *CreateUserFileParams, body io.Reader, contentType string) (*UserFile, error) {
+	q, h := url.Values{}, http.Header{}
+	if params != nil {
+		if params.UploadID != nil {
+			q.Set("upload_id", (*params.UploadID).String())
+		}
+	}
+	var out UserFile
+	if err := c.do(ctx, request{method: http.MethodPost, path: "/users/" + url.PathEscape(userID.String()) + "/files", body: body, contentType: contentType, query: q, header: h}, &out); err != nil {
+		return nil, err
+	}
+
+	return &out, nil
+}
+
+// DeleteUserFiles - Delete all files for a user (or only the tagged ones)
+//
+// DELETE /users/{user_id}/files
+func (c *Client) DeleteUserFiles(ctx context.Context, userID uuid.UUID, params *DeleteUserFilesParams) error {
+	q, h := url.Values{}, http.Header{}
+	if params != nil {
+		for _, v := range params.Tag {
+			q.Add("tag", v)
+		}
+	}
+	return c.do(ctx, request{method: http.MethodDelete, path: "/users/" + url.PathEscape(userID.String()) + "/files", query: q, header: h}, nil)
+}
+
+// SearchUserFiles - Search the files by their extracted text
+//
+// GET /users/{user_id}/files/search
+func (c *Client) SearchUserFiles(ctx context.Context, userID uuid.UUID, params *SearchUserFilesParams) (*SearchUserFilesResponse, error) {
+	q, h := url.Values{}, http.Header{}
+	if params != nil {
+		if params.Limit != nil {
+			q.Set("limit", fmt.Sprint(*params.Limit))
+		}
+		q.Set("q", params.Q)
+	}
+	var out SearchUserFilesResponse
+	if err := c.do(ctx, request{method: http.MethodGet, path: "/users/" + url.PathEscape(userID.String()) + "/files/search", query: q, header: h}, &out); err != nil {
+		return nil, err
+	}
+
+	return &out, nil
+}
+
+// MoveUserFile - Move a file to another folder and/or rename it
+//
+// PATCH /users/{user_id}/files/{file_id}
+func (c *Client) MoveUserFile(ctx context.Context, userID uuid.UUID, fileID uuid.UUID, body UserFileMoveRequest) (*UserFile, error) {
+	var out UserFile
+	if err := c.do(ctx, request{method: http.MethodPatch, path: "/users/" + url.PathEscape(userID.String()) + "/files/" + url.PathEscape(fileID.String()), json: body}, &out); err != nil {
+		return nil, err
+	}
+
+	return &out, nil
+}
+
+// GetUserFileText - Get the text extracted from a PDF or an image
+//
+// GET /users/{user_id}/files/{file_id}/text
+func (c *Client) GetUserFileText(ctx context.Context, userID uuid.UUID, fileID uuid.UUID) (*UserFileText, error) {
+	var out UserFileText
+	if err := c.do(ctx, request{method: http.MethodGet, path: "/users/" + url.PathEscape(userID.String()) + "/files/" + url.PathEscape(fileID.String()) + "/text"}, &out); err != nil {
+		return nil, err
+	}
+
+	return &out, nil
+}
+
+// ListUserFolders - List the subfolders of a folder
+//
+// GET /users/{user_id}/folders
+func (c *Client) ListUserFolders(ctx context.Context, userID uuid.UUID, params *ListUserFoldersParams) (*ListUserFoldersResponse, error) {
+	q, h := url.Values{}, http.Header{}
+	if params != nil {
+		if params.Parent != nil {
+			q.Set("parent", *params.Parent)
+		}
+	}
+	var out ListUserFoldersResponse
+	if err := c.do(ctx, request{method: http.MethodGet, path: "/users/" + url.PathEscape(userID.String()) + "/folders", query: q, header: h}, &out); err != nil {
+		return nil, err
+	}
+
+	return &out, nil
+}
+
+// MoveUserFolder - Move (rename) a folder with its subfolders
+//
+// POST /users/{user_id}/folders/move
+func (c *Client) MoveUserFolder(ctx context.Context, userID uuid.UUID, body FolderMoveRequest) (*MoveUserFolderResponse, error) {
+	var out MoveUserFolderResponse
+	if err := c.do(ctx, request{method: http.MethodPost, path: "/users/" + url.PathEscape(userID.String()) + "/folders/move", json: body}, &out); err != nil {
+		return nil, err
+	}
+
+	return &out, nil
+}
+
+// GetUserHistory - The prior versions of a user with their changes, or the version valid at as_of
+//
+// GET /users/{user_id}/history
+func (c *Client) GetUserHistory(ctx context.Context, userID uuid.UUID, params *GetUserHistoryParams) (*GetUserHistoryResponse, error) {
+	q, h := url.Values{}, http.Header{}
+	if params != nil {
+		if params.AsOf != nil {
+			q.Set("as_of", (*params.AsOf).Format(time.RFC3339))
+		}
+	}
+	var out GetUserHistoryResponse
+	if err := c.do(ctx, request{method: http.MethodGet, path: "/users/" + url.PathEscape(userID.String()) + "/history", query: q, header: h}, &out); err != nil {
+		return nil, err
+	}
+
+	return &out, nil
+}
+
+// ListUserNotes - Get notes on a user (with pagination)
+//
+// GET /users/{user_id}/notes
+func (c *Client) ListUserNotes(ctx context.Context, userID uuid.UUID, params *ListUserNotesParams) (*UserNotesListResponse, error) {
+	q, h := url.Values{}, http.Header{}
+	if params != nil {
+		if params.Cursor != nil {
+			q.Set("cursor", *params.Cursor)
+		}
+		if params.Page != nil {
+			q.Set("page", fmt.Sprint(*params.Page))
+		}
+		if params.PerPage != nil {
+			q.Set("per_page", fmt.Sprint(*params.PerPage))
+		}
+	}
+	var out UserNotesListResponse
+	if err := c.do(ctx, request{method: http.MethodGet, path: "/users/" + url.PathEscape(userID.String()) + "/notes", query: q, header: h}, &out); err != nil {
+		return nil, err
+	}
+
+	return &out, nil
+}
+
+// CreateUserNote - Add a note on a user, the author is taken from the token
+//
+// POST /users/{user_id}/notes
+func (c *Client) CreateUserNote(ctx context.Context, userID uuid.UUID, body UserNoteRequest) (*UserNote, error) {
+	var out UserNote
+	if err := c.do(ctx, request{method: http.MethodPost, path: "/users/" + url.PathEscape(userID.String()) + "/notes", json: body}, &out); err != nil {
+		return nil, err
+	}
+
+	return &out, nil
+}
+
+// DeleteUserNote - Delete a note on a user
+//
+// DELETE /users/{user_id}/notes/{note_id}
+func (c *Client) DeleteUserNote(ctx context.Context, userID uuid.UUID, noteID uuid.UUID) error {
+	return c.do(ctx, request{method: http.MethodDelete, path: "/users/" + url.PathEscape(userID.String()) + "/notes/" + url.PathEscape(noteID.String())}, nil)
+}
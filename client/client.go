@@ -0,0 +1,256 @@
+// Package client - the Go client of the User Manager API for the internal
+// consumers. The types and the methods(client.gen.go) are generated from
+// internal/interface/api/rest/api-specs/openapi/usermanagerapi/openapi.yaml by
+// cmd/clientgen, a method per operation named by its operationId; the transport
+// is this file. The contract tests of the server run the client against it with
+// the OpenAPI check on, so a drift of either side fails them.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const userAgent = "user-manager-api-client/" + Version
+
+type (
+	// TokenSource - the bearer token of a request, "" - anonymous
+	TokenSource func(ctx context.Context) (string, error)
+
+	// RetryPolicy - the idempotent requests(GET, PUT, DELETE) are retried on the
+	// transport errors and on 429, 502, 503, 504. The delay doubles from Backoff
+	// up to MaxBackoff, a Retry-After of the answer takes precedence.
+	RetryPolicy struct {
+		MaxAttempts int
+		Backoff     time.Duration
+		MaxBackoff  time.Duration
+	}
+
+	Option func(c *Client)
+
+	Client struct {
+		baseURL    string
+		httpClient *http.Client
+		token      TokenSource
+		retry      RetryPolicy
+	}
+)
+
+// DefaultRetryPolicy - 3 attempts within ~300ms
+var DefaultRetryPolicy = RetryPolicy{MaxAttempts: 3, Backoff: 100 * time.Millisecond, MaxBackoff: 2 * time.Second}
+
+// New - baseURL is the API root with the version, e.g. http://localhost:8080/api/v1
+func New(baseURL string, opts ...Option) *Client {
+	c := &Client{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+		retry:      DefaultRetryPolicy,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+func WithHTTPClient(hc *http.Client) Option { return func(c *Client) { c.httpClient = hc } }
+
+func WithRetryPolicy(p RetryPolicy) Option { return func(c *Client) { c.retry = p } }
+
+// WithToken - a static bearer token, e.g. of a service account
+func WithToken(token string) Option {
+	return WithTokenSource(func(context.Context) (string, error) { return token, nil })
+}
+
+// WithTokenSource - the token is asked for every request, e.g. to refresh it
+func WithTokenSource(ts TokenSource) Option { return func(c *Client) { c.token = ts } }
+
+// WithToken - a copy of the client authenticated by token(e.g. the one of Login)
+func (c *Client) WithToken(token string) *Client {
+	cp := *c
+	WithToken(token)(&cp)
+	return &cp
+}
+
+// APIError - a non 2xx answer, Message and Code are the ones of the JSON error envelope
+type APIError struct {
+	StatusCode int
+	Message    string
+	// Code - the machine readable reason, e.g. last_admin or token_expired, the
+	// generic one of the status(not_found) if the handler has none
+	Code    string
+	Details json.RawMessage
+}
+
+func (e *APIError) Error() string {
+	msg := e.Message
+	if msg == "" {
+		msg = http.StatusText(e.StatusCode)
+	}
+	if e.Code != "" {
+		return fmt.Sprintf("user-manager-api: %d %s(%s)", e.StatusCode, msg, e.Code)
+	}
+
+	return fmt.Sprintf("user-manager-api: %d %s", e.StatusCode, msg)
+}
+
+// IsStatus - err is an *APIError of status
+func IsStatus(err error, status int) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == status
+}
+
+// request - of an operation, built by the generated methods
+type request struct {
+	method string
+	path   string
+	query  url.Values
+	header http.Header
+	// json - the JSON body, nil - none
+	json any
+	// body - a body of contentType read once, the request is not retried
+	body        io.Reader
+	contentType string
+	// accept - the media type of the answer, application/json if empty
+	accept string
+}
+
+// do sends the request and decodes a 2xx answer into out: nil - the body is
+// dropped, *[]byte - the raw body, otherwise the JSON one
+func (c *Client) do(ctx context.Context, r request, out any) error {
+	var body []byte
+	if r.json != nil {
+		var err error
+		if body, err = json.Marshal(r.json); err != nil {
+			return err
+		}
+	}
+
+	attempts := c.retry.MaxAttempts
+	if attempts < 1 || !idempotent(r.method) || r.body != nil {
+		attempts = 1
+	}
+	delay := c.retry.Backoff
+	for attempt := 1; ; attempt++ {
+		resp, err := c.send(ctx, r, body)
+		if attempt == attempts || !retryable(resp, err) || ctx.Err() != nil {
+			if err != nil {
+				return err
+			}
+			return decode(resp, out)
+		}
+
+		wait := min(delay, c.retry.MaxBackoff)
+		if resp != nil {
+			if s, convErr := strconv.Atoi(resp.Header.Get("Retry-After")); convErr == nil && s >= 0 {
+				wait = time.Duration(s) * time.Second
+			}
+			_, _ = io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wait):
+		}
+		delay *= 2
+	}
+}
+
+func (c *Client) send(ctx context.Context, r request, body []byte) (*http.Response, error) {
+	reader, contentType := r.body, r.contentType
+	if body != nil {
+		reader, contentType = bytes.NewReader(body), "application/json"
+	}
+	target := c.baseURL + r.path
+	if len(r.query) > 0 {
+		target += "?" + r.query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, r.method, target, reader)
+	if err != nil {
+		return nil, err
+	}
+	for name, values := range r.header {
+		req.Header[name] = values
+	}
+	accept := r.accept
+	if accept == "" {
+		accept = "application/json"
+	}
+	req.Header.Set("Accept", accept)
+	req.Header.Set("User-Agent", userAgent)
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	if c.token != nil {
+		token, err := c.token(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("token source: %w", err)
+		}
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+	}
+
+	return c.httpClient.Do(req)
+}
+
+func decode(resp *http.Response, out any) error {
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		apiErr := &APIError{StatusCode: resp.StatusCode}
+		var envelope struct {
+			Error   string          `json:"error"`
+			Code    string          `json:"code"`
+			Details json.RawMessage `json:"details"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&envelope); err == nil {
+			apiErr.Message, apiErr.Code, apiErr.Details = envelope.Error, envelope.Code, envelope.Details
+		}
+		return apiErr
+	}
+	if raw, ok := out.(*[]byte); ok {
+		var err error
+		*raw, err = io.ReadAll(resp.Body)
+		return err
+	}
+	if out == nil || resp.StatusCode == http.StatusNoContent {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return nil
+	}
+
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+func idempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete:
+		return true
+	default:
+		return false
+	}
+}
+
+// retryable - the transport errors(the context ones aside) and the answers of an
+// overloaded or restarting server
+func retryable(resp *http.Response, err error) bool {
+	if err != nil {
+		return !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+	}
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,87 @@
+package client_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/evgenyspirin/user-manager-api/client"
+)
+
+func TestClient_Retry(t *testing.T) {
+	type tc struct {
+		name         string
+		method       string
+		statuses     []int
+		wantAttempts int
+		wantStatus   int
+	}
+	tests := []tc{
+		{
+			name:         "idempotent recovered",
+			method:       http.MethodGet,
+			statuses:     []int{http.StatusServiceUnavailable, http.StatusBadGateway, http.StatusOK},
+			wantAttempts: 3,
+		},
+		{
+			name:         "idempotent exhausted",
+			method:       http.MethodGet,
+			statuses:     []int{http.StatusTooManyRequests, http.StatusTooManyRequests, http.StatusTooManyRequests, http.StatusOK},
+			wantAttempts: 3,
+			wantStatus:   http.StatusTooManyRequests,
+		},
+		{
+			name:         "client error not retried",
+			method:       http.MethodGet,
+			statuses:     []int{http.StatusNotFound, http.StatusOK},
+			wantAttempts: 1,
+			wantStatus:   http.StatusNotFound,
+		},
+		{
+			name:         "not idempotent",
+			method:       http.MethodPost,
+			statuses:     []int{http.StatusServiceUnavailable, http.StatusOK},
+			wantAttempts: 1,
+			wantStatus:   http.StatusServiceUnavailable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attempts := 0
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "Bearer t", r.Header.Get("Authorization"))
+				status := tt.statuses[attempts]
+				attempts++
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(status)
+				_, _ = w.Write([]byte(`{"uuid":"` + uuid.Nil.String() + `","name":"n","error":"e"}`))
+			}))
+			defer srv.Close()
+
+			c := client.New(
+				srv.URL,
+				client.WithToken("t"),
+				client.WithRetryPolicy(client.RetryPolicy{MaxAttempts: 3, Backoff: time.Millisecond, MaxBackoff: time.Millisecond}),
+			)
+			var err error
+			if tt.method == http.MethodGet {
+				_, err = c.GetMe(context.Background(), nil)
+			} else {
+				_, err = c.CreateUser(context.Background(), client.UserRequest{})
+			}
+
+			assert.Equal(t, tt.wantAttempts, attempts)
+			if tt.wantStatus == 0 {
+				assert.NoError(t, err)
+				return
+			}
+			assert.True(t, client.IsStatus(err, tt.wantStatus), err)
+		})
+	}
+}
@@ -0,0 +1,549 @@
+// Command clientgen generates the Go client of the API(client/client.gen.go) from
+// the OpenAPI spec: a type per component schema and a method per operation, named
+// by its operationId. The transport(client/client.go) is hand-written.
+//
+//	go generate ./cmd/clientgen/
+package main
+
+//go:generate go run . -out ../../client/client.gen.go
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"go/token"
+	"log"
+	"maps"
+	"net/http"
+	"os"
+	"path"
+	"slices"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/getkin/kin-openapi/openapi3"
+
+	"user-manager-api/internal/interface/api/rest/api-specs/openapi/usermanagerapi"
+)
+
+const refSchemas = "#/components/schemas/"
+
+// initialisms - the name parts spelled in capitals(user_id - UserID)
+var initialisms = map[string]bool{
+	"api": true, "csv": true, "db": true, "dlq": true, "hr": true, "http": true, "id": true,
+	"ip": true, "json": true, "mq": true, "otp": true, "pdf": true, "sql": true, "ttl": true,
+	"uri": true, "url": true, "uuid": true,
+}
+
+// methods - the order of the operations of a path
+var methods = []string{http.MethodGet, http.MethodPut, http.MethodPost, http.MethodPatch, http.MethodDelete}
+
+func main() {
+	out := flag.String("out", "client/client.gen.go", "the generated file")
+	flag.Parse()
+
+	src, err := Generate(usermanagerapi.Spec)
+	if err != nil {
+		log.Fatalf("clientgen: %v", err)
+	}
+	if err = os.WriteFile(*out, src, 0o644); err != nil {
+		log.Fatalf("clientgen: %v", err)
+	}
+}
+
+type generator struct {
+	doc     *openapi3.T
+	types   bytes.Buffer
+	methods bytes.Buffer
+	// defined - the type names taken
+	defined map[string]bool
+}
+
+// Generate - the source of the client of the spec
+func Generate(spec []byte) ([]byte, error) {
+	doc, err := openapi3.NewLoader().LoadFromData(spec)
+	if err != nil {
+		return nil, err
+	}
+	g := &generator{doc: doc, defined: make(map[string]bool)}
+
+	for _, name := range slices.Sorted(maps.Keys(doc.Components.Schemas)) {
+		g.defined[name] = true
+	}
+	for _, name := range slices.Sorted(maps.Keys(doc.Components.Schemas)) {
+		g.namedType(name, doc.Components.Schemas[name].Value)
+	}
+	for _, p := range slices.Sorted(maps.Keys(doc.Paths.Map())) {
+		item := doc.Paths.Value(p)
+		for _, method := range methods {
+			if op := item.GetOperation(method); op != nil {
+				if err = g.operation(p, method, item.Parameters, op); err != nil {
+					return nil, fmt.Errorf("%s %s: %w", method, p, err)
+				}
+			}
+		}
+	}
+
+	body := slices.Concat(g.types.Bytes(), g.methods.Bytes())
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "// Code generated by clientgen from openapi.yaml. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&b, "package client\n\nimport (\n")
+	for _, pkg := range []string{"context", "encoding/json", "fmt", "io", "net/http", "net/url", "time", "", "github.com/google/uuid"} {
+		switch {
+		case pkg == "":
+			fmt.Fprintf(&b, "\n")
+		case bytes.Contains(body, []byte(path.Base(pkg)+".")):
+			fmt.Fprintf(&b, "%q\n", pkg)
+		}
+	}
+	fmt.Fprintf(&b, ")\n\n")
+	fmt.Fprintf(&b, "// Version - of the API contract(info.version of the spec) the client is built for\n")
+	fmt.Fprintf(&b, "const Version = %q\n\n", doc.Info.Version)
+	b.Write(body)
+
+	return format.Source(b.Bytes())
+}
+
+// namedType defines the type of a component schema
+func (g *generator) namedType(name string, s *openapi3.Schema) {
+	switch {
+	case len(s.OneOf) > 0 || len(s.AnyOf) > 0:
+		g.union(name, s)
+	case len(s.AllOf) > 0 || len(s.Properties) > 0:
+		g.structType(name, s)
+	default:
+		g.comment(&g.types, name, s.Description)
+		fmt.Fprintf(&g.types, "type %s %s\n\n", name, g.goType(&openapi3.SchemaRef{Value: s}, name+"Value"))
+		if len(s.Enum) > 0 && s.Type.Is(openapi3.TypeString) {
+			fmt.Fprintf(&g.types, "const (\n")
+			for _, v := range s.Enum {
+				fmt.Fprintf(&g.types, "%s%s %s = %q\n", name, exported(fmt.Sprint(v)), name, v)
+			}
+			fmt.Fprintf(&g.types, ")\n\n")
+		}
+	}
+}
+
+// goType - the Go type of the schema, an inline object or union is defined as hint
+func (g *generator) goType(ref *openapi3.SchemaRef, hint string) string {
+	if ref == nil || ref.Value == nil {
+		return "any"
+	}
+	if name, ok := strings.CutPrefix(ref.Ref, refSchemas); ok {
+		return name
+	}
+
+	s := ref.Value
+	switch {
+	case len(s.OneOf) > 0 || len(s.AnyOf) > 0:
+		if !refsOnly(append(s.OneOf, s.AnyOf...)) {
+			return "json.RawMessage"
+		}
+		return g.union(g.newName(hint), s)
+	case len(s.AllOf) > 0 || len(s.Properties) > 0:
+		return g.structType(g.newName(hint), s)
+	}
+
+	switch {
+	case s.Type.Is(openapi3.TypeObject):
+		if ap := s.AdditionalProperties.Schema; ap != nil {
+			return "map[string]" + g.goType(ap, hint+"Value")
+		}
+		return "map[string]any"
+	case s.Type.Is(openapi3.TypeArray):
+		return "[]" + g.goType(s.Items, hint+"Item")
+	case s.Type.Is(openapi3.TypeString):
+		switch s.Format {
+		case "date-time":
+			return "time.Time"
+		case "uuid":
+			return "uuid.UUID"
+		case "binary":
+			return "[]byte"
+		}
+		return "string"
+	case s.Type.Is(openapi3.TypeInteger):
+		if s.Format == "int64" {
+			return "int64"
+		}
+		return "int"
+	case s.Type.Is(openapi3.TypeNumber):
+		return "float64"
+	case s.Type.Is(openapi3.TypeBoolean):
+		return "bool"
+	}
+
+	return "any"
+}
+
+// structType defines the struct of the properties of s and of its allOf
+func (g *generator) structType(name string, s *openapi3.Schema) string {
+	props := make(map[string]*openapi3.SchemaRef)
+	required := make(map[string]bool)
+	collect(s, props, required)
+
+	var fields bytes.Buffer
+	for _, prop := range slices.Sorted(maps.Keys(props)) {
+		ref := props[prop]
+		field := exported(prop)
+		t := g.goType(ref, name+field)
+		tag := prop
+		if !required[prop] || ref.Value != nil && ref.Value.Nullable {
+			t = optional(t)
+			tag += ",omitempty"
+		}
+		if ref.Value != nil {
+			g.comment(&fields, field, ref.Value.Description)
+		}
+		fmt.Fprintf(&fields, "%s %s `json:%q`\n", field, t, tag)
+	}
+
+	g.comment(&g.types, name, s.Description)
+	fmt.Fprintf(&g.types, "type %s struct {\n%s}\n\n", name, fields.Bytes())
+
+	return name
+}
+
+// union defines the raw JSON of one of the alternatives of s, decoded by its As methods
+func (g *generator) union(name string, s *openapi3.Schema) string {
+	alts := append(s.OneOf, s.AnyOf...)
+	if !refsOnly(alts) {
+		g.comment(&g.types, name, s.Description)
+		fmt.Fprintf(&g.types, "type %s = json.RawMessage\n\n", name)
+		return name
+	}
+
+	names := make([]string, len(alts))
+	for i, alt := range alts {
+		names[i] = strings.TrimPrefix(alt.Ref, refSchemas)
+	}
+	fmt.Fprintf(&g.types, "// %s - one of %s, the As methods decode it as one of them\n", name, strings.Join(names, ", "))
+	fmt.Fprintf(&g.types, "type %s struct {\njson.RawMessage\n}\n\n", name)
+	for _, alt := range names {
+		fmt.Fprintf(&g.types, "func (u %s) As%s() (%s, error) {\nvar v %s\nerr := json.Unmarshal(u.RawMessage, &v)\nreturn v, err\n}\n\n", name, alt, alt, alt)
+	}
+
+	return name
+}
+
+type param struct {
+	name        string
+	description string
+	in          string
+	field       string
+	goVar       string
+	t           string
+	// required - sent as is, an optional one is a pointer left out if nil
+	required bool
+}
+
+func (g *generator) operation(path, method string, shared openapi3.Parameters, op *openapi3.Operation) error {
+	if op.OperationID == "" {
+		return fmt.Errorf("no operationId")
+	}
+	name := exported(op.OperationID)
+
+	// the ones of the operation override the ones of the path
+	byKey := make(map[string]*openapi3.Parameter)
+	for _, p := range slices.Concat(shared, op.Parameters) {
+		byKey[p.Value.In+" "+p.Value.Name] = p.Value
+	}
+	var pathParams, otherParams []param
+	for _, key := range slices.Sorted(maps.Keys(byKey)) {
+		p := byKey[key]
+		field := exported(p.Name)
+		prm := param{name: p.Name, description: p.Description, in: p.In, field: field, goVar: goVar(p.Name), t: g.goType(p.Schema, name+field), required: p.Required}
+		switch p.In {
+		case openapi3.ParameterInPath:
+			pathParams = append(pathParams, prm)
+		case openapi3.ParameterInQuery, openapi3.ParameterInHeader:
+			if !prm.required {
+				prm.t = optional(prm.t)
+			}
+			otherParams = append(otherParams, prm)
+		}
+	}
+	// the path parameters in the order of the path
+	sort.SliceStable(pathParams, func(i, j int) bool {
+		return strings.Index(path, "{"+pathParams[i].name+"}") < strings.Index(path, "{"+pathParams[j].name+"}")
+	})
+
+	args := []string{"ctx context.Context"}
+	for _, p := range pathParams {
+		args = append(args, p.goVar+" "+p.t)
+	}
+	paramsType := name + "Params"
+	if len(otherParams) > 0 {
+		var fields bytes.Buffer
+		for _, p := range otherParams {
+			g.comment(&fields, p.field, p.description)
+			fmt.Fprintf(&fields, "%s %s\n", p.field, p.t)
+		}
+		fmt.Fprintf(&g.types, "// %s - the query and header parameters of %s, the nil ones are not sent\n", paramsType, name)
+		fmt.Fprintf(&g.types, "type %s struct {\n%s}\n\n", paramsType, fields.Bytes())
+		args = append(args, "params *"+paramsType)
+	}
+
+	// the request body
+	var reqFields []string
+	if rb := op.RequestBody; rb != nil && rb.Value != nil {
+		if mt := jsonMedia(rb.Value.Content); mt != nil {
+			args = append(args, "body "+g.goType(mt.Schema, name+"Request"))
+			reqFields = append(reqFields, "json: body")
+		} else {
+			args = append(args, "body io.Reader", "contentType string")
+			reqFields = append(reqFields, "body: body", "contentType: contentType")
+		}
+	}
+
+	// the response of the first documented 2xx with a content
+	result, accept := "", "application/json"
+	var codes []string
+	for code := range op.Responses.Map() {
+		if strings.HasPrefix(code, "2") {
+			codes = append(codes, code)
+		}
+	}
+	sort.Strings(codes)
+	for _, code := range codes {
+		content := op.Responses.Value(code).Value.Content
+		if len(content) == 0 {
+			continue
+		}
+		if mt := jsonMedia(content); mt != nil {
+			result = g.goType(mt.Schema, name+"Response")
+		} else {
+			result = "[]byte"
+			accept = slices.Sorted(maps.Keys(content))[0]
+		}
+		break
+	}
+	if accept != "application/json" {
+		reqFields = append(reqFields, fmt.Sprintf("accept: %q", accept))
+	}
+
+	b := &g.methods
+	g.comment(b, name, op.Summary)
+	fmt.Fprintf(b, "//\n// %s %s\n", method, path)
+	returns, zero := "error", ""
+	if result != "" {
+		returns, zero = "("+pointerTo(result)+", error)", "nil, "
+	}
+	fmt.Fprintf(b, "func (c *Client) %s(%s) %s {\n", name, strings.Join(args, ", "), returns)
+
+	urlPath := fmt.Sprintf("%q", path)
+	for _, p := range pathParams {
+		urlPath = strings.Replace(urlPath, "{"+p.name+"}", `" + url.PathEscape(`+toString(p.goVar, p.t)+`) + "`, 1)
+	}
+	urlPath = strings.TrimSuffix(strings.TrimPrefix(urlPath, `"" + `), ` + ""`)
+	reqFields = append([]string{"method: http.Method" + methodName(method), "path: " + urlPath}, reqFields...)
+
+	if len(otherParams) > 0 {
+		fmt.Fprintf(b, "q, h := url.Values{}, http.Header{}\nif params != nil {\n")
+		for _, p := range otherParams {
+			set := "q.Set"
+			if p.in == openapi3.ParameterInHeader {
+				set = "h.Set"
+			}
+			switch {
+			case strings.HasPrefix(p.t, "[]"):
+				add := strings.Replace(set, "Set", "Add", 1)
+				fmt.Fprintf(b, "for _, v := range params.%s {\n%s(%q, %s)\n}\n", p.field, add, p.name, toString("v", p.t[2:]))
+			case strings.HasPrefix(p.t, "*"):
+				fmt.Fprintf(b, "if params.%s != nil {\n%s(%q, %s)\n}\n", p.field, set, p.name, toString("*params."+p.field, p.t[1:]))
+			default:
+				fmt.Fprintf(b, "%s(%q, %s)\n", set, p.name, toString("params."+p.field, p.t))
+			}
+		}
+		fmt.Fprintf(b, "}\n")
+		reqFields = append(reqFields, "query: q", "header: h")
+	}
+
+	req := "request{" + strings.Join(reqFields, ", ") + "}"
+	if result == "" {
+		fmt.Fprintf(b, "return c.do(ctx, %s, nil)\n}\n\n", req)
+		return nil
+	}
+	fmt.Fprintf(b, "var out %s\nif err := c.do(ctx, %s, &out); err != nil {\nreturn %serr\n}\n\n", result, req, zero)
+	if pointerTo(result) == result {
+		fmt.Fprintf(b, "return out, nil\n}\n\n")
+	} else {
+		fmt.Fprintf(b, "return &out, nil\n}\n\n")
+	}
+
+	return nil
+}
+
+// comment - the description as the doc comment of name, wrapped at 100 columns
+func (g *generator) comment(b *bytes.Buffer, name, description string) {
+	words := strings.Fields(description)
+	if len(words) == 0 {
+		return
+	}
+	line := "// " + name + " -"
+	for _, w := range words {
+		if len(line)+1+len(w) > 100 {
+			fmt.Fprintf(b, "%s\n", line)
+			line = "//"
+		}
+		line += " " + w
+	}
+	fmt.Fprintf(b, "%s\n", line)
+}
+
+// newName - hint, suffixed if it is taken
+func (g *generator) newName(hint string) string {
+	name := hint
+	for i := 2; g.defined[name]; i++ {
+		name = fmt.Sprintf("%s%d", hint, i)
+	}
+	g.defined[name] = true
+
+	return name
+}
+
+// collect - the properties of s and of its allOf, the required ones in required
+func collect(s *openapi3.Schema, props map[string]*openapi3.SchemaRef, required map[string]bool) {
+	for _, sub := range s.AllOf {
+		if sub.Value != nil {
+			collect(sub.Value, props, required)
+		}
+	}
+	for name, ref := range s.Properties {
+		props[name] = ref
+	}
+	for _, name := range s.Required {
+		required[name] = true
+	}
+}
+
+func refsOnly(refs openapi3.SchemaRefs) bool {
+	for _, ref := range refs {
+		if !strings.HasPrefix(ref.Ref, refSchemas) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func jsonMedia(content openapi3.Content) *openapi3.MediaType {
+	for _, ct := range slices.Sorted(maps.Keys(content)) {
+		if ct == "application/json" || strings.HasSuffix(ct, "+json") {
+			return content[ct]
+		}
+	}
+
+	return nil
+}
+
+// optional - a pointer to a scalar or a struct, the slices, maps and raw JSON are nil already
+func optional(t string) string {
+	if strings.HasPrefix(t, "[]") || strings.HasPrefix(t, "map[") || strings.HasPrefix(t, "*") ||
+		t == "any" || t == "json.RawMessage" {
+		return t
+	}
+
+	return "*" + t
+}
+
+// pointerTo - the result type of an operation, the slices, maps and raw JSON as they are
+func pointerTo(t string) string {
+	if strings.HasPrefix(t, "[]") || strings.HasPrefix(t, "map[") || t == "json.RawMessage" || t == "any" {
+		return t
+	}
+
+	return "*" + t
+}
+
+// toString - the expression of v of type t as a parameter value
+func toString(v, t string) string {
+	if strings.HasPrefix(v, "*") && (t == "time.Time" || t == "uuid.UUID") {
+		v = "(" + v + ")"
+	}
+	switch t {
+	case "string":
+		return v
+	case "time.Time":
+		return v + ".Format(time.RFC3339)"
+	case "uuid.UUID":
+		return v + ".String()"
+	}
+
+	return "fmt.Sprint(" + v + ")"
+}
+
+func methodName(method string) string {
+	return method[:1] + strings.ToLower(method[1:])
+}
+
+// exported - user_id, x-signature, message_ids or hrSystemHook as UserID, XSignature,
+// MessageIDs, HRSystemHook
+func exported(name string) string {
+	var b strings.Builder
+	for _, w := range words(name) {
+		lower := strings.ToLower(w)
+		switch {
+		case initialisms[lower]:
+			b.WriteString(strings.ToUpper(w))
+		case strings.HasSuffix(lower, "s") && initialisms[strings.TrimSuffix(lower, "s")]:
+			b.WriteString(strings.ToUpper(w[:len(w)-1]) + "s")
+		default:
+			r := []rune(w)
+			r[0] = unicode.ToUpper(r[0])
+			b.WriteString(string(r))
+		}
+	}
+
+	return b.String()
+}
+
+// words - the parts of a snake, kebab or camel case name: getMQTopology - get, MQ, Topology
+func words(name string) []string {
+	var out []string
+	for _, part := range strings.FieldsFunc(name, func(r rune) bool { return r == '_' || r == '-' || r == '.' || r == ' ' }) {
+		r := []rune(part)
+		start := 0
+		for i := 1; i < len(r); i++ {
+			lowerToUpper := unicode.IsLower(r[i-1]) && unicode.IsUpper(r[i])
+			// MQTopology - MQ, Topology
+			acronymEnd := unicode.IsUpper(r[i-1]) && unicode.IsUpper(r[i]) && i+1 < len(r) && unicode.IsLower(r[i+1])
+			if lowerToUpper || acronymEnd {
+				out = append(out, string(r[start:i]))
+				start = i
+			}
+		}
+		out = append(out, string(r[start:]))
+	}
+
+	return out
+}
+
+// goVar - the parameter name of an argument, user_id as userID
+func goVar(name string) string {
+	e := exported(name)
+	i := 0
+	for i < len(e) && unicode.IsUpper(rune(e[i])) {
+		i++
+	}
+	// UserID - userID, ID - id, UUIDs - uuids
+	switch {
+	case i == len(e):
+		e = strings.ToLower(e)
+	case i > 1:
+		e = strings.ToLower(e[:i-1]) + e[i-1:]
+	default:
+		e = strings.ToLower(e[:1]) + e[1:]
+	}
+	switch e {
+	case "ctx", "params", "body", "contentType", "q", "h", "out", "c", "v", "err":
+		return e + "Param"
+	}
+	if token.IsKeyword(e) {
+		return e + "Param"
+	}
+
+	return e
+}
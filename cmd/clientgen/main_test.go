@@ -0,0 +1,43 @@
+package main
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"user-manager-api/internal/interface/api/rest/api-specs/openapi/usermanagerapi"
+)
+
+// TestGenerate_UpToDate - client/client.gen.go is the one of the current spec, run
+// go generate ./cmd/clientgen/ after a change of openapi.yaml
+func TestGenerate_UpToDate(t *testing.T) {
+	want, err := Generate(usermanagerapi.Spec)
+	require.NoError(t, err)
+
+	got, err := os.ReadFile("../../client/client.gen.go")
+	require.NoError(t, err)
+	assert.True(t, string(want) == string(got), "client/client.gen.go is stale, run go generate ./cmd/clientgen/")
+}
+
+func TestExported(t *testing.T) {
+	type tc struct {
+		name string
+		want string
+	}
+	tests := []tc{
+		{name: "user_id", want: "UserID"},
+		{name: "message_ids", want: "MessageIDs"},
+		{name: "X-Signature", want: "XSignature"},
+		{name: "getMQTopology", want: "GetMQTopology"},
+		{name: "hrSystemHook", want: "HRSystemHook"},
+		{name: "listUsers", want: "ListUsers"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, exported(tt.name))
+		})
+	}
+}
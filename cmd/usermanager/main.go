@@ -2,12 +2,23 @@ package main
 
 import (
 	"context"
+	"flag"
+	"fmt"
 	"log"
 	"os"
+	"slices"
+	"strings"
+	// the timezones of the users do not depend on the zoneinfo of the image
+	_ "time/tzdata"
 
 	"user-manager-api/internal"
+	"user-manager-api/internal/application/jobs"
+	"user-manager-api/internal/interface/api/rest"
 )
 
+// cmdCollection - the subcommand printing the example requests of the API
+const cmdCollection = "collection"
+
 // I focused on implementing more important features and left this list for later.
 // todo: Implement:
 // todo: Linters(GolangCiLint)
@@ -17,13 +28,60 @@ import (
 func main() {
 	ctx := context.Background()
 
+	// subcommands: one-off job runs, e.g. "usermanager reconcile-files -delete",
+	// "usermanager restore -object backups/<time>.umbak", and the example requests
+	// "usermanager collection -format http > usermanagerapi.http"
+	var job string
+	var format, host *string
+	if len(os.Args) > 1 {
+		job = os.Args[1]
+		// an unknown one never gets to the app: it would connect everything first
+		if job != cmdCollection && !slices.Contains(jobs.Names, job) {
+			fmt.Fprintf(os.Stderr, "unknown command %q, one of: %s, %s\n", job, cmdCollection, strings.Join(jobs.Names, ", "))
+			os.Exit(2)
+		}
+		fs := flag.NewFlagSet(job, flag.ExitOnError)
+		del := fs.Bool("delete", false, "delete orphans instead of only reporting them")
+		object := fs.String("object", "", "the backup archive key to restore")
+		format = fs.String("format", rest.CollectionPostman, "the collection format: postman or http")
+		host = fs.String("host", "http://localhost:8080", "the host of the collection requests")
+		_ = fs.Parse(os.Args[2:])
+		// env has priority over .env(godotenv never overrides)
+		if job == jobs.NameReconcileFiles && *del {
+			_ = os.Setenv("JOBS_RECONCILE_FILES_DELETE", "true")
+		}
+		if job == jobs.NameRestore && *object != "" {
+			_ = os.Setenv("BACKUP_RESTORE_OBJECT", *object)
+		}
+	}
+
 	app, err := internal.NewApp(ctx)
 	if err != nil {
 		log.Fatalf("init app failed: %v", err)
 	}
 	defer app.Close()
 
+	if job == cmdCollection {
+		app.InitControllers()
+		if err = app.WriteCollection(os.Stdout, *format, *host); err != nil {
+			app.Logger().Sugar().Errorf("collection failed: %v", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	app.InitJobs()
+
+	if job != "" {
+		if err = app.RunJob(ctx, job); err != nil {
+			app.Logger().Sugar().Errorf("job %s failed: %v", job, err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	app.InitControllers()
+	app.InitConsumers()
 
 	if err = app.Run(ctx); err != nil {
 		app.Logger().Sugar().Errorf("usermanagerapi stopped with error: %v", err)
@@ -1,9 +1,19 @@
 package config
 
 import (
+	"encoding/base64"
+	"errors"
 	"fmt"
+	"net"
+	"net/mail"
 	"net/url"
 	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"user-manager-api/internal/domain/pagination"
 )
 
 type (
@@ -13,6 +23,68 @@ type (
 		Port      string
 		Env       string
 		JWTSecret string
+		// TokenFormat - of the access tokens: "jwt"(signed with JWTSecret), "paseto-local"
+		// (v4.local) or "paseto-public"(v4.public), both with PasetoKey
+		TokenFormat string
+		// PasetoKey - base64: 32 bytes symmetric key for v4.local, Ed25519 private key
+		// (64 bytes) or its seed(32 bytes) for v4.public
+		PasetoKey string
+
+		// limits
+		PageSize       int
+		MaxUploadSize  int64
+		MaxLogBodySize int
+		// MaxFileOpsPerUser - the uploads and deletes of a user in flight, the
+		// others are answered 429, 0 - unlimited
+		MaxFileOpsPerUser int
+		// MaxInFlight - the requests run at once by the "admission" middleware,
+		// MaxQueued more wait for QueueWait at most, the rest are answered 503 with
+		// ShedRetryAfter. 0 - unlimited
+		MaxInFlight    int
+		MaxQueued      int
+		QueueWait      time.Duration
+		ShedRetryAfter time.Duration
+
+		// ImpersonationTTL - lifetime of admin impersonation tokens
+		ImpersonationTTL time.Duration
+		// TokenRefreshWindow - the tokens expiring within it are refreshed by the
+		// requests(X-Refreshed-Token), 0 - the sessions end with the token
+		TokenRefreshWindow time.Duration
+		// RoleCacheTTL - how long an instance caches the role of a user: a role
+		// change takes effect on the issued tokens within it, 0 - no cache
+		RoleCacheTTL time.Duration
+		// UploadPolicyCacheTTL - how long an instance caches the upload policies
+		// of the organizations, 0 - no cache
+		UploadPolicyCacheTTL time.Duration
+		// EmailChangeTTL - lifetime of the new email confirmation token
+		EmailChangeTTL time.Duration
+		// InvitationTTL - lifetime of the invitation token
+		InvitationTTL time.Duration
+		// InvitationURL - the signup page of the invitation links("?token=" is appended),
+		// empty - the events carry the token only
+		InvitationURL string
+
+		// TrustedProxies - IPs/CIDRs(load balancers) allowed to set RemoteIPHeaders,
+		// empty - the client IP is always the peer address
+		TrustedProxies  []string
+		RemoteIPHeaders []string
+
+		// ReadOnly - reject the mutating requests whatever the admin toggle, e.g.
+		// during a failover when the toggle itself may be unreachable
+		ReadOnly bool
+		// ReadOnlyPollInterval - how soon the instances pick up the admin toggle
+		ReadOnlyPollInterval time.Duration
+
+		// OpenAPIValidation - the API exchanges out of the OpenAPI spec are answered
+		// 500, the responses are buffered for it: off in production
+		OpenAPIValidation bool
+
+		// Middlewares - the global middlewares in their order, of MiddlewareNames
+		Middlewares []string
+		// CORSAllowedOrigins - the browser origins of the "cors" middleware, "*" - any
+		CORSAllowedOrigins []string
+		// RateLimitPerMinute - requests per client IP of the "rate_limit" middleware
+		RateLimitPerMinute int
 	}
 	DB struct {
 		User     string
@@ -20,12 +92,331 @@ type (
 		Name     string
 		Host     string
 		Port     string
+		// MaxRetries - retries of a statement failed with a transient error, 0 disables
+		MaxRetries     int
+		RetryBaseDelay time.Duration
+
+		// resilience
+		BreakerMaxFailures uint32
+		BreakerOpenTimeout time.Duration
+		// MaxConcurrent - statements in flight, 0 disables the bulkhead
+		MaxConcurrent int
+		BulkheadWait  time.Duration
+
+		// SchemaCheck - on a schema version other than the code's: "fail" to
+		// refuse to start, "read-only" to serve the reads only, "off"
+		SchemaCheck string
+		// SlowQueryThreshold - the statements running longer are logged, 0 disables the log
+		SlowQueryThreshold time.Duration
 	}
 	S3 struct {
 		Region          string
 		AccessKeyID     string
 		SecretAccessKey string
 		BucketUploads   string
+		// DownloadMode - "public": the download URLs point to the bucket, "proxy":
+		// to the API(GET /files/raw/*key at ProxyURL), which reads the objects
+		// by ranges for the media players
+		DownloadMode string
+		ProxyURL     string
+
+		Resilience
+	}
+	Azure struct {
+		Account   string
+		AccessKey string
+		Container string
+
+		Resilience
+	}
+	GCS struct {
+		ProjectID       string
+		CredentialsFile string
+		Bucket          string
+
+		Resilience
+	}
+	// Resilience - of the calls to an object storage, <PREFIX>_TIMEOUT etc.
+	Resilience struct {
+		// Timeout - of a single attempt
+		Timeout            time.Duration
+		MaxRetries         int
+		RetryBaseDelay     time.Duration
+		BreakerMaxFailures uint32
+		BreakerOpenTimeout time.Duration
+		// MaxConcurrent - calls in flight, 0 disables the bulkhead
+		MaxConcurrent int
+		BulkheadWait  time.Duration
+	}
+	Storage struct {
+		// Driver - of StorageDrivers, "s3" by default
+		Driver      string
+		FSRoot      string
+		FSPublicURL string
+	}
+	Thumbnails struct {
+		// MaxSize - longest side in px
+		MaxSize int
+		// PDFRenderer - "pdftoppm" binary, empty disables PDF previews
+		PDFRenderer string
+		QueueSize   int
+		// MaxSourceSize - bytes of the largest upload previewed, its source is
+		// read into memory
+		MaxSourceSize int64
+		// MaxSourcePixels - of the largest image decoded, checked on its header
+		// before the decoding
+		MaxSourcePixels int
+	}
+	// OCR - the text extraction of the uploads, searched by the files search
+	OCR struct {
+		// Tesseract - the binary of the images OCR, empty disables the images
+		Tesseract string
+		// Languages - the tesseract languages, e.g. "eng+deu"
+		Languages string
+		// PDFToText - poppler's "pdftotext" binary reading the text layer of the
+		// PDFs, empty disables the PDFs
+		PDFToText string
+		QueueSize int
+		// MaxTextSize - bytes of the text kept per file, the rest is cut
+		MaxTextSize int
+		// MaxSourceSize - bytes of the largest upload extracted, its source is
+		// read into memory
+		MaxSourceSize int64
+	}
+	Jobs struct {
+		// ReconcileFilesInterval - 0 disables the periodic run(CLI only)
+		ReconcileFilesInterval time.Duration
+		ReconcileFilesDelete   bool
+		// ReencryptPIIInterval - 0 disables the periodic run(CLI only)
+		ReencryptPIIInterval time.Duration
+		// RedactInactiveInterval - 0 disables the periodic run(CLI only)
+		RedactInactiveInterval time.Duration
+		// RebuildStatsInterval - 0 disables the periodic run(CLI only)
+		RebuildStatsInterval time.Duration
+		// SyncDirectoryInterval - 0 disables the periodic run(CLI only), needs LDAP_URL
+		SyncDirectoryInterval time.Duration
+		// SendDigestsInterval - 0 disables the periodic run(CLI only), 24h - daily digests
+		SendDigestsInterval time.Duration
+		// EmitBirthdaysInterval - 0 disables the periodic run(CLI only), 24h - daily
+		EmitBirthdaysInterval time.Duration
+		// AggregateUsageInterval - 0 disables the periodic run(CLI only), 24h - daily
+		AggregateUsageInterval time.Duration
+		// BackupInterval - 0 disables the periodic run(CLI only), needs BACKUP_BUCKET
+		// and BACKUP_ENCRYPTION_KEY
+		BackupInterval time.Duration
+		// PurgeProcessedEventsInterval - 0 disables the periodic run(CLI only)
+		PurgeProcessedEventsInterval time.Duration
+		// PurgeExpiredFilesInterval - 0 disables the periodic run(CLI only)
+		PurgeExpiredFilesInterval time.Duration
+		// ResumeDeletionsInterval - 0 disables the periodic run(CLI only)
+		ResumeDeletionsInterval time.Duration
+		// RelayOutboxInterval - 0 disables the periodic run(CLI only)
+		RelayOutboxInterval time.Duration
+		// ArchiveAuditInterval - 0 disables the periodic run(CLI only), needs
+		// RETENTION_AUDIT_DAYS
+		ArchiveAuditInterval time.Duration
+		// SuspendInactiveInterval - 0 disables the periodic run(CLI only)
+		SuspendInactiveInterval time.Duration
+	}
+	// Backup - the encrypted archives of the "backup" and "restore" commands
+	Backup struct {
+		// Bucket - the S3 bucket of the archives, separate from the uploads
+		Bucket string
+		// EncryptionKey - base64 32 bytes(AES-256), without it an archive can not be restored
+		EncryptionKey string
+		// RestoreObject - the archive key "restore" replays, set by its -object flag
+		RestoreObject string
+	}
+	// Hooks - inbound webhooks of the integrations, an empty secret disables the hook
+	Hooks struct {
+		// HRSecret - the HMAC key shared with the HR system
+		HRSecret string
+		// MaxSkew - the accepted age of a signature timestamp
+		MaxSkew time.Duration
+	}
+	// Email - the notification emails sent by the events consumer
+	Email struct {
+		// Provider - "log"(default, dev only: emails go to the log), "smtp" or "ses"
+		Provider string
+		From     string
+		SMTPHost string
+		SMTPPort string
+		// SMTPUser - empty - no authentication(e.g. a local relay)
+		SMTPUser     string
+		SMTPPassword string
+		SESRegion    string
+		// MaxRetries - retries of a send failed with a transient error, 0 disables
+		MaxRetries     int
+		RetryBaseDelay time.Duration
+		// LoginURL - linked from the emails, empty - no link
+		LoginURL string
+	}
+	// Notifications - the channels of the notifications besides the email
+	Notifications struct {
+		// WebhookTimeout - a call to the webhook of a user, redirects included
+		WebhookTimeout time.Duration
+	}
+	// Anomaly - the rules of the login events
+	Anomaly struct {
+		// GeoHeader - the request header carrying the country of the client set
+		// by the edge(e.g. CF-IPCountry), empty - no country, no travel rule
+		GeoHeader string
+		// TravelWindow - a login from another country within it is impossible
+		// travel, 0 disables the rule
+		TravelWindow time.Duration
+		// NewDevice - a login with a user agent none of the known ones had is flagged
+		NewDevice bool
+		// HistorySize - the logins of a user kept to compare with
+		HistorySize int
+		// ForceReauth - a flagged login revokes the tokens of the user
+		ForceReauth bool
+		// AlertWebhookURL - the flagged logins are posted to it, empty - no alerts
+		AlertWebhookURL string
+	}
+	// Alerts - the operators are notified of the events failed to be published
+	// or consumed
+	Alerts struct {
+		// Provider - "log"(default, dev and tests: alerts go to the log), "slack"
+		// or "pagerduty"
+		Provider string
+		// WebhookURL - the Slack incoming webhook, for PagerDuty empty is the
+		// Events API v2 endpoint
+		WebhookURL          string
+		PagerDutyRoutingKey string
+		// DedupWindow - an alert of a kind is sent once per the window, 0 - every time
+		DedupWindow time.Duration
+		// RateLimit - the alerts sent a minute, 0 - no limit
+		RateLimit int
+		Timeout   time.Duration
+	}
+	// Timezones - where "today" is for the age and the birthday of the users
+	// without their own timezone
+	Timezones struct {
+		// Default - IANA name of the organizations out of Orgs
+		Default string
+		// Orgs - "<organization>=<IANA name>" items, the organization is the email domain
+		Orgs []string
+	}
+	// AgePolicy - the minimum age of the users on today's date in their timezone
+	AgePolicy struct {
+		// MinAge - of the organizations out of Orgs, 0 - no minimum
+		MinAge int
+		// Orgs - "<organization>=<years>" items, the organization is the email domain
+		Orgs []string
+	}
+	// Usage - the usage metrics per organization(email domain) and role
+	Usage struct {
+		// Orgs - the organizations with their own metrics label, the others
+		// are reported as "other", the rollups keep every organization
+		Orgs []string
+		// FlushInterval - how often the recorded requests are added to the
+		// metrics and the rollups
+		FlushInterval time.Duration
+		QueueSize     int
+	}
+	// LDAP - the directory(LDAP/Active Directory) users are synced from, empty URL - no sync
+	LDAP struct {
+		URL          string
+		BindDN       string
+		BindPassword string
+		BaseDN       string
+		Filter       string
+		// Attr* - the attribute mapping, Active Directory names by default
+		AttrEmail     string
+		AttrName      string
+		AttrLastname  string
+		AttrBirthDate string
+		AttrPhone     string
+		// AttrDisabled - userAccountControl(the ACCOUNTDISABLE flag) or a boolean attribute
+		AttrDisabled string
+		// Timeout - of the dial and of every request to the server
+		Timeout time.Duration
+	}
+	// TLS - of the HTTP listener, plain HTTP without CertFile
+	TLS struct {
+		CertFile string
+		KeyFile  string
+		// ClientCAFile - the CAs of the internal callers(mTLS): a verified client
+		// certificate authenticates the request instead of a bearer token, empty disables it
+		ClientCAFile string
+		// ClientIdentities - "<SAN>=<role>" items: the SPIFFE ID(spiffe://...) or the DNS
+		// name of a client certificate and the role the caller acts with
+		ClientIdentities []string
+	}
+	// Timeouts - the deadline budgets, 0 disables a budget
+	Timeouts struct {
+		// Handler - the deadline of a request, its queries and storage calls included
+		Handler time.Duration
+		// Upload - the deadline of a multipart(file upload) request, the body transfer included
+		Upload time.Duration
+		// DBQuery - a single statement, reading of the rows included
+		DBQuery time.Duration
+		// Storage - a whole object storage operation, S3 retries included(S3_TIMEOUT is per attempt)
+		Storage time.Duration
+		// Routes - "<METHOD> <route>=<duration>" budgets instead of Handler/Upload,
+		// e.g. longer for the exports, shorter for the reads
+		Routes []string
+	}
+	// Startup - the wait for Postgres, the storage and RabbitMQ on start
+	Startup struct {
+		// MaxWait - per dependency, then the start fails, 0 - a single attempt
+		MaxWait time.Duration
+		// RetryBaseDelay - doubled per attempt up to RetryMaxDelay, jittered
+		RetryBaseDelay time.Duration
+		RetryMaxDelay  time.Duration
+	}
+	Retention struct {
+		// InactiveMonths - users not seen(logged in) for longer get Columns blanked,
+		// 0 disables the redaction
+		InactiveMonths int
+		// Columns - name, lastname, birth_date, phone
+		Columns []string
+		// AuditDays - the audit entries older are moved to AuditBucket, 0 - kept forever
+		AuditDays   int
+		AuditBucket string
+	}
+	// Suspension - the users not seen(logged in) for InactiveDays are suspended,
+	// 0 disables it. The admins are never suspended
+	Suspension struct {
+		InactiveDays int
+		// WarnDays - the warning event goes that many days before, 0 - none
+		WarnDays int
+		// GraceDays - a login within them after the suspension lifts it, 0 - only
+		// an admin does
+		GraceDays int
+	}
+	Password struct {
+		// Algorithm - "bcrypt"(default) or "argon2id" for new hashes, both are verified
+		Algorithm  string
+		BcryptCost int
+		// Argon2Memory in KiB
+		Argon2Memory  uint32
+		Argon2Time    uint32
+		Argon2Threads uint8
+	}
+	PII struct {
+		// Keys - "<id>:<base64 32 bytes>" key encryption keys, retired ones are kept
+		// to decrypt until "reencrypt-pii" moves the data to ActiveKey
+		Keys      []string
+		ActiveKey string
+		// BlindIndexKey - base64 HMAC key of the phone lookup index, not rotatable
+		BlindIndexKey string
+	}
+	OTP struct {
+		TTL         time.Duration
+		CodeLength  int
+		MaxAttempts int
+		// Cooldown - min interval between codes sent to the same phone
+		Cooldown time.Duration
+		// IPRequestsPerMinute - limit of OTP requests from one client IP
+		IPRequestsPerMinute int
+		// SMSProvider - "log"(default, dev only: codes go to the log) or "twilio"
+		SMSProvider string
+		// TwilioAccountSID, TwilioAuthToken - the credentials of the "twilio" provider
+		TwilioAccountSID string
+		TwilioAuthToken  string
+		// TwilioFrom - the sender number(E.164) or the messaging service SID("MG...")
+		TwilioFrom string
 	}
 	MQ struct {
 		User         string
@@ -33,16 +424,108 @@ type (
 		Vhost        string
 		Host         string
 		AmqpPort     string
+		MgmtPort     string
 		Exchange     string
 		ExchangeType string
 		QueueName    string
+		// DeadLetterQueue - the deliveries the handlers failed on, empty disables it
+		DeadLetterQueue string
+		// EventEncodings - "<exchange>=json|protobuf"(schemas/event.proto) items, the
+		// encoding of the events published to an exchange, json for the exchanges
+		// not listed; the messages carry its content type
+		EventEncodings []string
+		// SchemaValidation - the events not matching schemas/event.json are
+		// rejected on publishing
+		SchemaValidation bool
+		// DedupTTL - how long the ids of the consumed events are kept to skip their
+		// redeliveries, 0 disables the deduplication
+		DedupTTL time.Duration
+		// ConsumerLanes - deliveries processed in parallel, the ones of a user
+		// always take the same lane and keep their order
+		ConsumerLanes int
+		// BufferSize - queued events per publishing worker
+		BufferSize     int
+		PublishWorkers int
+		// PublishBatchSize - events published before waiting for the broker confirms
+		PublishBatchSize int
+		// EnqueueTimeout - how long a request waits for a room in a full buffer
+		// before the event is rejected
+		EnqueueTimeout time.Duration
+		// RetryBufferSize - failed events kept for retries, the overflow is dropped
+		RetryBufferSize int
+		RetryInterval   time.Duration
+		// LeaderElection - only one replica(the leader) consumes the queue, the
+		// others take over when it is gone
+		LeaderElection bool
+		// LeaderRetryInterval - how often the followers try to become the leader
+		LeaderRetryInterval time.Duration
+
+		// resilience of the publishing
+		BreakerMaxFailures uint32
+		BreakerOpenTimeout time.Duration
+		// MaxConcurrent - batches in flight, 0 disables the bulkhead(the workers bound them anyway)
+		MaxConcurrent int
+		BulkheadWait  time.Duration
+
+		// TopologyCheck - on a drift of the exchange, queue or bindings found at
+		// startup: "warn", "fail" to refuse to start, "repair" to declare the
+		// missing ones or "off"
+		TopologyCheck string
+		// MgmtTimeout - of a management API request, an empty MgmtPort disables
+		// the topology checks and the admin API on them
+		MgmtTimeout time.Duration
 	}
 
 	Config struct {
-		App APP
-		DB  DB
-		S3  S3
-		MQ  MQ
+		App           APP
+		DB            DB
+		S3            S3
+		Azure         Azure
+		GCS           GCS
+		Storage       Storage
+		MQ            MQ
+		Thumbnails    Thumbnails
+		OCR           OCR
+		Jobs          Jobs
+		Backup        Backup
+		Hooks         Hooks
+		Email         Email
+		Notifications Notifications
+		Anomaly       Anomaly
+		Alerts        Alerts
+		Timezones     Timezones
+		AgePolicy     AgePolicy
+		Usage         Usage
+		LDAP          LDAP
+		OTP           OTP
+		Password      Password
+		PII           PII
+		Retention     Retention
+		Suspension    Suspension
+		Timeouts      Timeouts
+		Startup       Startup
+		TLS           TLS
+
+		// parseErr - the values of Load which did not parse, reported by Validate
+		parseErr error
+	}
+)
+
+var (
+	// MiddlewareNames - of SERVICE_MIDDLEWARES
+	MiddlewareNames = []string{
+		"recovery", "request_id", "compression", "logging", "error_codes", "cors", "rate_limit",
+		"admission", "timeout", "openapi", "client_cert", "auth", "usage", "read_only",
+	}
+	// StorageDrivers - of STORAGE_DRIVER
+	StorageDrivers = []string{"s3", "fs"}
+	// pendingStorageDrivers - wired up, but their SDK calls are not in yet: an
+	// upload would be discarded, so they are refused
+	pendingStorageDrivers = []string{"azure", "gcs"}
+	// DefaultMiddlewares - openapi and client_cert run only with their own
+	// settings on(SERVICE_OPENAPI_VALIDATION, TLS_CLIENT_CA_FILE)
+	DefaultMiddlewares = []string{
+		"recovery", "request_id", "logging", "error_codes", "admission", "timeout", "openapi", "client_cert", "usage", "read_only",
 	}
 )
 
@@ -53,13 +536,121 @@ func getEnv(key, def string) string {
 	return def
 }
 
+// envParser - the typed values of Load: a value which does not parse is kept in
+// errs for Validate instead of being replaced by the default
+type envParser struct {
+	errs []error
+}
+
+func (p *envParser) getEnvInt(key string, def int) int {
+	if v, ok := os.LookupEnv(key); ok && v != "" {
+		i, err := strconv.Atoi(v)
+		if err != nil {
+			p.errs = append(p.errs, fmt.Errorf("invalid %s %q: must be an integer", key, v))
+			return def
+		}
+		return i
+	}
+	return def
+}
+
+func (p *envParser) getEnvBool(key string, def bool) bool {
+	if v, ok := os.LookupEnv(key); ok && v != "" {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			p.errs = append(p.errs, fmt.Errorf("invalid %s %q: must be true or false", key, v))
+			return def
+		}
+		return b
+	}
+	return def
+}
+
+// getEnvList - comma separated values, empty items are skipped
+func getEnvList(key string, def []string) []string {
+	v, ok := os.LookupEnv(key)
+	if !ok || v == "" {
+		return def
+	}
+
+	var list []string
+	for _, item := range strings.Split(v, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
+// getResilience - the settings of <prefix>_TIMEOUT, <prefix>_MAX_RETRIES etc.
+func (p *envParser) getResilience(prefix string) Resilience {
+	return Resilience{
+		Timeout:            p.getEnvDuration(prefix+"_TIMEOUT", 10*time.Second),
+		MaxRetries:         p.getEnvInt(prefix+"_MAX_RETRIES", 3),
+		RetryBaseDelay:     p.getEnvDuration(prefix+"_RETRY_BASE_DELAY", 100*time.Millisecond),
+		BreakerMaxFailures: uint32(p.getEnvInt(prefix+"_BREAKER_MAX_FAILURES", 5)),
+		BreakerOpenTimeout: p.getEnvDuration(prefix+"_BREAKER_OPEN_TIMEOUT", 30*time.Second),
+		MaxConcurrent:      p.getEnvInt(prefix+"_MAX_CONCURRENT", 32),
+		BulkheadWait:       p.getEnvDuration(prefix+"_BULKHEAD_WAIT", time.Second),
+	}
+}
+
+func (p *envParser) getEnvDuration(key string, def time.Duration) time.Duration {
+	if v, ok := os.LookupEnv(key); ok && v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			p.errs = append(p.errs, fmt.Errorf("invalid %s %q: must be a duration(e.g. 30s, 5m)", key, v))
+			return def
+		}
+		return d
+	}
+	return def
+}
+
 func Load() Config {
+	var env envParser
 	app := APP{
 		Name:      getEnv("SERVICE_NAME", ""),
 		Host:      getEnv("SERVICE_HOST", ""),
 		Port:      getEnv("SERVICE_PORT", ""),
 		Env:       getEnv("SERVICE_ENV", ""),
 		JWTSecret: getEnv("SERVICE_JWT_SECRET", ""),
+
+		TokenFormat: getEnv("SERVICE_TOKEN_FORMAT", "jwt"),
+		PasetoKey:   getEnv("SERVICE_PASETO_KEY", ""),
+
+		PageSize:       env.getEnvInt("SERVICE_PAGE_SIZE", 50),
+		MaxUploadSize:  int64(env.getEnvInt("SERVICE_MAX_UPLOAD_SIZE", 10<<20)),
+		MaxLogBodySize: env.getEnvInt("SERVICE_MAX_LOG_BODY_SIZE", 4<<10),
+
+		MaxFileOpsPerUser: env.getEnvInt("SERVICE_MAX_FILE_OPS_PER_USER", 3),
+
+		MaxInFlight:    env.getEnvInt("SERVICE_MAX_IN_FLIGHT", 0),
+		MaxQueued:      env.getEnvInt("SERVICE_MAX_QUEUED", 50),
+		QueueWait:      env.getEnvDuration("SERVICE_QUEUE_WAIT", 500*time.Millisecond),
+		ShedRetryAfter: env.getEnvDuration("SERVICE_SHED_RETRY_AFTER", time.Second),
+
+		ImpersonationTTL: env.getEnvDuration("SERVICE_IMPERSONATION_TTL", 15*time.Minute),
+		RoleCacheTTL:     env.getEnvDuration("SERVICE_ROLE_CACHE_TTL", 30*time.Second),
+		EmailChangeTTL:   env.getEnvDuration("SERVICE_EMAIL_CHANGE_TTL", 24*time.Hour),
+		InvitationTTL:    env.getEnvDuration("SERVICE_INVITATION_TTL", 72*time.Hour),
+		InvitationURL:    getEnv("SERVICE_INVITATION_URL", ""),
+
+		TokenRefreshWindow: env.getEnvDuration("SERVICE_TOKEN_REFRESH_WINDOW", 10*time.Minute),
+
+		UploadPolicyCacheTTL: env.getEnvDuration("SERVICE_UPLOAD_POLICY_CACHE_TTL", 30*time.Second),
+
+		TrustedProxies:  getEnvList("SERVICE_TRUSTED_PROXIES", nil),
+		RemoteIPHeaders: getEnvList("SERVICE_REMOTE_IP_HEADERS", []string{"X-Forwarded-For", "X-Real-IP"}),
+
+		ReadOnly:             env.getEnvBool("SERVICE_READ_ONLY", false),
+		ReadOnlyPollInterval: env.getEnvDuration("SERVICE_READ_ONLY_POLL_INTERVAL", 5*time.Second),
+
+		OpenAPIValidation: env.getEnvBool("SERVICE_OPENAPI_VALIDATION", false),
+
+		Middlewares:        getEnvList("SERVICE_MIDDLEWARES", DefaultMiddlewares),
+		CORSAllowedOrigins: getEnvList("SERVICE_CORS_ALLOWED_ORIGINS", nil),
+		RateLimitPerMinute: env.getEnvInt("SERVICE_RATE_LIMIT_PER_MINUTE", 600),
 	}
 	db := DB{
 		User:     getEnv("POSTGRES_USER", ""),
@@ -67,12 +658,48 @@ func Load() Config {
 		Name:     getEnv("POSTGRES_DB", ""),
 		Host:     getEnv("POSTGRES_HOST", ""),
 		Port:     getEnv("POSTGRES_PORT", ""),
+		// the waits of 4 retries sum up to 3.75s at most: a failover, the
+		// request deadline bounds the rest
+		MaxRetries:     env.getEnvInt("POSTGRES_MAX_RETRIES", 4),
+		RetryBaseDelay: env.getEnvDuration("POSTGRES_RETRY_BASE_DELAY", 250*time.Millisecond),
+
+		BreakerMaxFailures: uint32(env.getEnvInt("POSTGRES_BREAKER_MAX_FAILURES", 5)),
+		BreakerOpenTimeout: env.getEnvDuration("POSTGRES_BREAKER_OPEN_TIMEOUT", 10*time.Second),
+		MaxConcurrent:      env.getEnvInt("POSTGRES_MAX_CONCURRENT", 64),
+		BulkheadWait:       env.getEnvDuration("POSTGRES_BULKHEAD_WAIT", time.Second),
+
+		SchemaCheck: getEnv("POSTGRES_SCHEMA_CHECK", "fail"),
+
+		SlowQueryThreshold: env.getEnvDuration("POSTGRES_SLOW_QUERY_THRESHOLD", 500*time.Millisecond),
 	}
 	s3 := S3{
 		Region:          getEnv("S3_REGION", ""),
 		AccessKeyID:     getEnv("S3_ACCESS_KEY_ID", ""),
 		SecretAccessKey: getEnv("S3_SECRET_ACCESS_KEY", ""),
 		BucketUploads:   getEnv("S3_BUCKET_UPLOADS", ""),
+		DownloadMode:    getEnv("S3_DOWNLOAD_MODE", "public"),
+		ProxyURL:        getEnv("S3_PROXY_URL", ""),
+
+		Resilience: env.getResilience("S3"),
+	}
+	azure := Azure{
+		Account:   getEnv("AZURE_STORAGE_ACCOUNT", ""),
+		AccessKey: getEnv("AZURE_STORAGE_ACCESS_KEY", ""),
+		Container: getEnv("AZURE_STORAGE_CONTAINER", ""),
+
+		Resilience: env.getResilience("AZURE_STORAGE"),
+	}
+	gcs := GCS{
+		ProjectID:       getEnv("GCS_PROJECT_ID", ""),
+		CredentialsFile: getEnv("GCS_CREDENTIALS_FILE", ""),
+		Bucket:          getEnv("GCS_BUCKET_UPLOADS", ""),
+
+		Resilience: env.getResilience("GCS"),
+	}
+	storage := Storage{
+		Driver:      getEnv("STORAGE_DRIVER", "s3"),
+		FSRoot:      getEnv("STORAGE_FS_ROOT", ""),
+		FSPublicURL: getEnv("STORAGE_FS_PUBLIC_URL", ""),
 	}
 	mq := MQ{
 		User:         getEnv("RABBITMQ_USER", ""),
@@ -80,17 +707,554 @@ func Load() Config {
 		Vhost:        getEnv("RABBITMQ_VHOST", ""),
 		Host:         getEnv("RABBITMQ_HOST", ""),
 		AmqpPort:     getEnv("RABBITMQ_AMQP_PORT", ""),
+		MgmtPort:     getEnv("RABBITMQ_MGMT_PORT", ""),
 		Exchange:     getEnv("RABBITMQ_EXCHANGE", ""),
 		ExchangeType: getEnv("RABBITMQ_EXCHANGE_TYPE", ""),
 		QueueName:    getEnv("RABBITMQ_QUEUE_NAME", ""),
+
+		DeadLetterQueue:  getEnv("RABBITMQ_DLQ_NAME", ""),
+		EventEncodings:   getEnvList("RABBITMQ_EVENT_ENCODINGS", nil),
+		SchemaValidation: env.getEnvBool("RABBITMQ_SCHEMA_VALIDATION", false),
+		DedupTTL:         env.getEnvDuration("RABBITMQ_DEDUP_TTL", 24*time.Hour),
+		ConsumerLanes:    env.getEnvInt("RABBITMQ_CONSUMER_LANES", 1),
+		// "Rely on metrics, not guesses."
+		BufferSize:          env.getEnvInt("RABBITMQ_BUFFER_SIZE", 128),
+		PublishWorkers:      env.getEnvInt("RABBITMQ_PUBLISH_WORKERS", 2),
+		PublishBatchSize:    env.getEnvInt("RABBITMQ_PUBLISH_BATCH_SIZE", 50),
+		EnqueueTimeout:      env.getEnvDuration("RABBITMQ_ENQUEUE_TIMEOUT", time.Second),
+		RetryBufferSize:     env.getEnvInt("RABBITMQ_RETRY_BUFFER_SIZE", 1024),
+		RetryInterval:       env.getEnvDuration("RABBITMQ_RETRY_INTERVAL", 2*time.Second),
+		LeaderElection:      env.getEnvBool("RABBITMQ_LEADER_ELECTION", false),
+		LeaderRetryInterval: env.getEnvDuration("RABBITMQ_LEADER_RETRY_INTERVAL", 5*time.Second),
+		BreakerMaxFailures:  uint32(env.getEnvInt("RABBITMQ_BREAKER_MAX_FAILURES", 3)),
+		BreakerOpenTimeout:  env.getEnvDuration("RABBITMQ_BREAKER_OPEN_TIMEOUT", 10*time.Second),
+		MaxConcurrent:       env.getEnvInt("RABBITMQ_MAX_CONCURRENT", 0),
+		BulkheadWait:        env.getEnvDuration("RABBITMQ_BULKHEAD_WAIT", 0),
+		TopologyCheck:       getEnv("RABBITMQ_TOPOLOGY_CHECK", "warn"),
+		MgmtTimeout:         env.getEnvDuration("RABBITMQ_MGMT_TIMEOUT", 5*time.Second),
+	}
+	thumbnails := Thumbnails{
+		MaxSize:         env.getEnvInt("THUMBNAILS_MAX_SIZE", 256),
+		PDFRenderer:     getEnv("THUMBNAILS_PDF_RENDERER", "pdftoppm"),
+		QueueSize:       env.getEnvInt("THUMBNAILS_QUEUE_SIZE", 16),
+		MaxSourceSize:   int64(env.getEnvInt("THUMBNAILS_MAX_SOURCE_SIZE", 20<<20)),
+		MaxSourcePixels: env.getEnvInt("THUMBNAILS_MAX_SOURCE_PIXELS", 40_000_000),
+	}
+	ocr := OCR{
+		Tesseract:     getEnv("OCR_TESSERACT", "tesseract"),
+		Languages:     getEnv("OCR_LANGUAGES", "eng"),
+		PDFToText:     getEnv("OCR_PDFTOTEXT", "pdftotext"),
+		QueueSize:     env.getEnvInt("OCR_QUEUE_SIZE", 16),
+		MaxTextSize:   env.getEnvInt("OCR_MAX_TEXT_SIZE", 256<<10),
+		MaxSourceSize: int64(env.getEnvInt("OCR_MAX_SOURCE_SIZE", 20<<20)),
+	}
+	jobs := Jobs{
+		ReconcileFilesInterval: env.getEnvDuration("JOBS_RECONCILE_FILES_INTERVAL", 0),
+		ReconcileFilesDelete:   env.getEnvBool("JOBS_RECONCILE_FILES_DELETE", false),
+		ReencryptPIIInterval:   env.getEnvDuration("JOBS_REENCRYPT_PII_INTERVAL", 0),
+		RedactInactiveInterval: env.getEnvDuration("JOBS_REDACT_INACTIVE_INTERVAL", 0),
+		RebuildStatsInterval:   env.getEnvDuration("JOBS_REBUILD_STATS_INTERVAL", 0),
+		SyncDirectoryInterval:  env.getEnvDuration("JOBS_SYNC_DIRECTORY_INTERVAL", 0),
+		SendDigestsInterval:    env.getEnvDuration("JOBS_SEND_DIGESTS_INTERVAL", 0),
+		EmitBirthdaysInterval:  env.getEnvDuration("JOBS_EMIT_BIRTHDAYS_INTERVAL", 0),
+		AggregateUsageInterval: env.getEnvDuration("JOBS_AGGREGATE_USAGE_INTERVAL", 0),
+		BackupInterval:         env.getEnvDuration("JOBS_BACKUP_INTERVAL", 0),
+
+		PurgeProcessedEventsInterval: env.getEnvDuration("JOBS_PURGE_PROCESSED_EVENTS_INTERVAL", 0),
+		PurgeExpiredFilesInterval:    env.getEnvDuration("JOBS_PURGE_EXPIRED_FILES_INTERVAL", 0),
+		ResumeDeletionsInterval:      env.getEnvDuration("JOBS_RESUME_DELETIONS_INTERVAL", 0),
+		RelayOutboxInterval:          env.getEnvDuration("JOBS_RELAY_OUTBOX_INTERVAL", 0),
+		ArchiveAuditInterval:         env.getEnvDuration("JOBS_ARCHIVE_AUDIT_INTERVAL", 0),
+		SuspendInactiveInterval:      env.getEnvDuration("JOBS_SUSPEND_INACTIVE_INTERVAL", 0),
+	}
+	hooks := Hooks{
+		HRSecret: getEnv("HOOKS_HR_SECRET", ""),
+		MaxSkew:  env.getEnvDuration("HOOKS_MAX_SKEW", 5*time.Minute),
+	}
+	email := Email{
+		Provider:       getEnv("EMAIL_PROVIDER", "log"),
+		From:           getEnv("EMAIL_FROM", "no-reply@usermanager.local"),
+		SMTPHost:       getEnv("EMAIL_SMTP_HOST", ""),
+		SMTPPort:       getEnv("EMAIL_SMTP_PORT", "587"),
+		SMTPUser:       getEnv("EMAIL_SMTP_USER", ""),
+		SMTPPassword:   getEnv("EMAIL_SMTP_PASSWORD", ""),
+		SESRegion:      getEnv("EMAIL_SES_REGION", ""),
+		MaxRetries:     env.getEnvInt("EMAIL_MAX_RETRIES", 3),
+		RetryBaseDelay: env.getEnvDuration("EMAIL_RETRY_BASE_DELAY", 500*time.Millisecond),
+		LoginURL:       getEnv("EMAIL_LOGIN_URL", ""),
+	}
+	notifications := Notifications{
+		WebhookTimeout: env.getEnvDuration("NOTIFICATIONS_WEBHOOK_TIMEOUT", 5*time.Second),
+	}
+	anomaly := Anomaly{
+		GeoHeader:       getEnv("ANOMALY_GEO_HEADER", ""),
+		TravelWindow:    env.getEnvDuration("ANOMALY_TRAVEL_WINDOW", 2*time.Hour),
+		NewDevice:       env.getEnvBool("ANOMALY_NEW_DEVICE", true),
+		HistorySize:     env.getEnvInt("ANOMALY_HISTORY_SIZE", 20),
+		ForceReauth:     env.getEnvBool("ANOMALY_FORCE_REAUTH", false),
+		AlertWebhookURL: getEnv("ANOMALY_ALERT_WEBHOOK_URL", ""),
+	}
+	alerts := Alerts{
+		Provider:            getEnv("ALERT_PROVIDER", "log"),
+		WebhookURL:          getEnv("ALERT_WEBHOOK_URL", ""),
+		PagerDutyRoutingKey: getEnv("ALERT_PAGERDUTY_ROUTING_KEY", ""),
+		DedupWindow:         env.getEnvDuration("ALERT_DEDUP_WINDOW", 10*time.Minute),
+		RateLimit:           env.getEnvInt("ALERT_RATE_LIMIT", 20),
+		Timeout:             env.getEnvDuration("ALERT_TIMEOUT", 5*time.Second),
+	}
+	timezones := Timezones{
+		Default: getEnv("TIMEZONES_DEFAULT", "UTC"),
+		Orgs:    getEnvList("TIMEZONES_ORGS", nil),
+	}
+	agePolicy := AgePolicy{
+		MinAge: env.getEnvInt("AGE_POLICY_MIN_AGE", 18),
+		Orgs:   getEnvList("AGE_POLICY_ORGS", nil),
+	}
+	usage := Usage{
+		Orgs:          getEnvList("USAGE_ORGS", nil),
+		FlushInterval: env.getEnvDuration("USAGE_FLUSH_INTERVAL", time.Minute),
+		QueueSize:     env.getEnvInt("USAGE_QUEUE_SIZE", 10000),
+	}
+	ldap := LDAP{
+		URL:           getEnv("LDAP_URL", ""),
+		BindDN:        getEnv("LDAP_BIND_DN", ""),
+		BindPassword:  getEnv("LDAP_BIND_PASSWORD", ""),
+		BaseDN:        getEnv("LDAP_BASE_DN", ""),
+		Filter:        getEnv("LDAP_FILTER", "(&(objectCategory=person)(objectClass=user))"),
+		AttrEmail:     getEnv("LDAP_ATTR_EMAIL", "mail"),
+		AttrName:      getEnv("LDAP_ATTR_NAME", "givenName"),
+		AttrLastname:  getEnv("LDAP_ATTR_LASTNAME", "sn"),
+		AttrBirthDate: getEnv("LDAP_ATTR_BIRTH_DATE", "birthDate"),
+		AttrPhone:     getEnv("LDAP_ATTR_PHONE", "mobile"),
+		AttrDisabled:  getEnv("LDAP_ATTR_DISABLED", "userAccountControl"),
+		Timeout:       env.getEnvDuration("LDAP_TIMEOUT", 30*time.Second),
+	}
+	password := Password{
+		Algorithm:     getEnv("PASSWORD_HASH_ALGORITHM", "bcrypt"),
+		BcryptCost:    env.getEnvInt("PASSWORD_BCRYPT_COST", 12),
+		Argon2Memory:  uint32(env.getEnvInt("PASSWORD_ARGON2_MEMORY", 64<<10)),
+		Argon2Time:    uint32(env.getEnvInt("PASSWORD_ARGON2_TIME", 1)),
+		Argon2Threads: uint8(env.getEnvInt("PASSWORD_ARGON2_THREADS", 4)),
+	}
+	backup := Backup{
+		Bucket:        getEnv("BACKUP_BUCKET", ""),
+		EncryptionKey: getEnv("BACKUP_ENCRYPTION_KEY", ""),
+		RestoreObject: getEnv("BACKUP_RESTORE_OBJECT", ""),
+	}
+	pii := PII{
+		Keys:          getEnvList("PII_ENCRYPTION_KEYS", nil),
+		ActiveKey:     getEnv("PII_ENCRYPTION_ACTIVE_KEY", ""),
+		BlindIndexKey: getEnv("PII_BLIND_INDEX_KEY", ""),
+	}
+	retention := Retention{
+		InactiveMonths: env.getEnvInt("RETENTION_INACTIVE_MONTHS", 0),
+		Columns:        getEnvList("RETENTION_COLUMNS", []string{"birth_date", "phone"}),
+		AuditDays:      env.getEnvInt("RETENTION_AUDIT_DAYS", 0),
+		AuditBucket:    getEnv("RETENTION_AUDIT_BUCKET", ""),
+	}
+	suspension := Suspension{
+		InactiveDays: env.getEnvInt("SUSPENSION_INACTIVE_DAYS", 0),
+		WarnDays:     env.getEnvInt("SUSPENSION_WARN_DAYS", 7),
+		GraceDays:    env.getEnvInt("SUSPENSION_GRACE_DAYS", 14),
+	}
+	timeouts := Timeouts{
+		Handler: env.getEnvDuration("HTTP_HANDLER_TIMEOUT", 30*time.Second),
+		Upload:  env.getEnvDuration("HTTP_UPLOAD_TIMEOUT", 5*time.Minute),
+		DBQuery: env.getEnvDuration("DB_QUERY_TIMEOUT", 5*time.Second),
+		Storage: env.getEnvDuration("STORAGE_TIMEOUT", time.Minute),
+		Routes:  getEnvList("HTTP_ROUTE_TIMEOUTS", nil),
+	}
+	startup := Startup{
+		MaxWait:        env.getEnvDuration("STARTUP_MAX_WAIT", 2*time.Minute),
+		RetryBaseDelay: env.getEnvDuration("STARTUP_RETRY_BASE_DELAY", 500*time.Millisecond),
+		RetryMaxDelay:  env.getEnvDuration("STARTUP_RETRY_MAX_DELAY", 10*time.Second),
+	}
+	tlsCfg := TLS{
+		CertFile:         getEnv("TLS_CERT_FILE", ""),
+		KeyFile:          getEnv("TLS_KEY_FILE", ""),
+		ClientCAFile:     getEnv("TLS_CLIENT_CA_FILE", ""),
+		ClientIdentities: getEnvList("TLS_CLIENT_IDENTITIES", nil),
+	}
+	otp := OTP{
+		TTL:                 env.getEnvDuration("OTP_TTL", 5*time.Minute),
+		CodeLength:          env.getEnvInt("OTP_CODE_LENGTH", 6),
+		MaxAttempts:         env.getEnvInt("OTP_MAX_ATTEMPTS", 5),
+		Cooldown:            env.getEnvDuration("OTP_COOLDOWN", time.Minute),
+		IPRequestsPerMinute: env.getEnvInt("OTP_IP_REQUESTS_PER_MINUTE", 10),
+		SMSProvider:         getEnv("OTP_SMS_PROVIDER", "log"),
+		TwilioAccountSID:    getEnv("OTP_TWILIO_ACCOUNT_SID", ""),
+		TwilioAuthToken:     getEnv("OTP_TWILIO_AUTH_TOKEN", ""),
+		TwilioFrom:          getEnv("OTP_TWILIO_FROM", ""),
 	}
 
 	return Config{
-		App: app,
-		DB:  db,
-		S3:  s3,
-		MQ:  mq,
+		App:           app,
+		DB:            db,
+		S3:            s3,
+		Azure:         azure,
+		GCS:           gcs,
+		Storage:       storage,
+		MQ:            mq,
+		Thumbnails:    thumbnails,
+		OCR:           ocr,
+		Jobs:          jobs,
+		Backup:        backup,
+		Hooks:         hooks,
+		Email:         email,
+		Notifications: notifications,
+		Anomaly:       anomaly,
+		Alerts:        alerts,
+		Timezones:     timezones,
+		AgePolicy:     agePolicy,
+		Usage:         usage,
+		LDAP:          ldap,
+		OTP:           otp,
+		Password:      password,
+		PII:           pii,
+		Retention:     retention,
+		Suspension:    suspension,
+		Timeouts:      timeouts,
+		Startup:       startup,
+		TLS:           tlsCfg,
+		parseErr:      errors.Join(env.errs...),
+	}
+}
+
+// Validate - limits out of range mean a broken deployment, fail fast on start
+func (c Config) Validate() error {
+	if c.parseErr != nil {
+		return c.parseErr
+	}
+
+	for _, p := range c.App.TrustedProxies {
+		if _, _, err := net.ParseCIDR(p); err != nil && net.ParseIP(p) == nil {
+			return fmt.Errorf("invalid SERVICE_TRUSTED_PROXIES item %q: must be an IP or CIDR", p)
+		}
+	}
+
+	for _, item := range c.TLS.ClientIdentities {
+		id, role, ok := strings.Cut(item, "=")
+		if !ok || id == "" || (role != "admin" && role != "worker") {
+			return fmt.Errorf("invalid TLS_CLIENT_IDENTITIES item %q: must be <SAN>=admin or <SAN>=worker", item)
+		}
+	}
+
+	for _, err := range []error{
+		c.S3.Resilience.validate("S3"),
+		c.Azure.Resilience.validate("AZURE_STORAGE"),
+		c.GCS.Resilience.validate("GCS"),
+	} {
+		if err != nil {
+			return err
+		}
+	}
+
+	if c.App.OpenAPIValidation && isProduction(c.App.Env) {
+		return fmt.Errorf("invalid SERVICE_OPENAPI_VALIDATION: must be off for SERVICE_ENV %q", c.App.Env)
+	}
+	for i, name := range c.App.Middlewares {
+		if !slices.Contains(MiddlewareNames, name) {
+			return fmt.Errorf("invalid SERVICE_MIDDLEWARES item %q: must be one of %s", name, strings.Join(MiddlewareNames, ", "))
+		}
+		if slices.Contains(c.App.Middlewares[:i], name) {
+			return fmt.Errorf("invalid SERVICE_MIDDLEWARES item %q: listed twice", name)
+		}
+	}
+	if slices.Contains(c.App.Middlewares, "cors") && len(c.App.CORSAllowedOrigins) == 0 {
+		return fmt.Errorf("invalid SERVICE_CORS_ALLOWED_ORIGINS: must be set for the cors middleware")
+	}
+	if slices.Contains(c.App.Middlewares, "rate_limit") && c.App.RateLimitPerMinute <= 0 {
+		return fmt.Errorf("invalid SERVICE_RATE_LIMIT_PER_MINUTE %d: must be positive for the rate_limit middleware", c.App.RateLimitPerMinute)
+	}
+	if !isTimezone(c.Timezones.Default) {
+		return fmt.Errorf("invalid TIMEZONES_DEFAULT %q: must be an IANA timezone", c.Timezones.Default)
+	}
+	for _, item := range c.Timezones.Orgs {
+		org, tz, ok := strings.Cut(item, "=")
+		if !ok || org == "" || !isTimezone(tz) {
+			return fmt.Errorf("invalid TIMEZONES_ORGS item %q: must be <organization>=<IANA timezone>", item)
+		}
+	}
+	if c.AgePolicy.MinAge < 0 || c.AgePolicy.MinAge > 150 {
+		return fmt.Errorf("invalid AGE_POLICY_MIN_AGE %d: must be 0..150", c.AgePolicy.MinAge)
+	}
+	for _, item := range c.AgePolicy.Orgs {
+		org, years, ok := strings.Cut(item, "=")
+		if n, err := strconv.Atoi(years); !ok || org == "" || err != nil || n < 0 || n > 150 {
+			return fmt.Errorf("invalid AGE_POLICY_ORGS item %q: must be <organization>=<years 0..150>", item)
+		}
 	}
+	for _, item := range c.MQ.EventEncodings {
+		exchange, encoding, _ := strings.Cut(item, "=")
+		if exchange == "" || encoding != "json" && encoding != "protobuf" {
+			return fmt.Errorf("invalid RABBITMQ_EVENT_ENCODINGS item %q: must be <exchange>=json|protobuf", item)
+		}
+	}
+	for _, item := range c.Timeouts.Routes {
+		route, d, ok := strings.Cut(item, "=")
+		method, path, _ := strings.Cut(route, " ")
+		timeout, err := time.ParseDuration(d)
+		if !ok || !isHTTPMethod(method) || !strings.HasPrefix(path, "/") || err != nil || timeout < 0 {
+			return fmt.Errorf("invalid HTTP_ROUTE_TIMEOUTS item %q: must be <METHOD> <route>=<duration>", item)
+		}
+	}
+
+	for _, col := range c.Retention.Columns {
+		switch col {
+		case "name", "lastname", "birth_date", "phone":
+		default:
+			return fmt.Errorf("invalid RETENTION_COLUMNS item %q: must be name, lastname, birth_date or phone", col)
+		}
+	}
+
+	activeKey := false
+	for _, k := range c.PII.Keys {
+		id, _, _ := strings.Cut(k, ":")
+		activeKey = activeKey || id == c.PII.ActiveKey
+	}
+	indexKey, err := base64.StdEncoding.DecodeString(c.PII.BlindIndexKey)
+	backupKey, backupKeyErr := base64.StdEncoding.DecodeString(c.Backup.EncryptionKey)
+	pasetoKey, pasetoKeyErr := base64.StdEncoding.DecodeString(c.App.PasetoKey)
+
+	switch {
+	case c.DB.SchemaCheck != "fail" && c.DB.SchemaCheck != "read-only" && c.DB.SchemaCheck != "off":
+		return fmt.Errorf("invalid POSTGRES_SCHEMA_CHECK %q: must be fail, read-only or off", c.DB.SchemaCheck)
+	case c.App.PageSize < 1 || c.App.PageSize > pagination.MaxPerPage:
+		return fmt.Errorf("invalid SERVICE_PAGE_SIZE %d: must be 1..%d", c.App.PageSize, pagination.MaxPerPage)
+	case c.App.MaxUploadSize < 1 || c.App.MaxUploadSize > 1<<30:
+		return fmt.Errorf("invalid SERVICE_MAX_UPLOAD_SIZE %d: must be 1..1GB", c.App.MaxUploadSize)
+	case c.App.MaxLogBodySize < 0 || c.App.MaxLogBodySize > 1<<20:
+		return fmt.Errorf("invalid SERVICE_MAX_LOG_BODY_SIZE %d: must be 0..1MB", c.App.MaxLogBodySize)
+	case c.App.MaxFileOpsPerUser < 0 || c.App.MaxFileOpsPerUser > 100:
+		return fmt.Errorf("invalid SERVICE_MAX_FILE_OPS_PER_USER %d: must be 0..100", c.App.MaxFileOpsPerUser)
+	case c.App.MaxInFlight < 0:
+		return fmt.Errorf("invalid SERVICE_MAX_IN_FLIGHT %d: must not be negative", c.App.MaxInFlight)
+	case c.App.MaxInFlight > 0 && c.App.MaxQueued < 0:
+		return fmt.Errorf("invalid SERVICE_MAX_QUEUED %d: must not be negative", c.App.MaxQueued)
+	case c.App.MaxInFlight > 0 && c.App.QueueWait < 0:
+		return fmt.Errorf("invalid SERVICE_QUEUE_WAIT %s: must not be negative", c.App.QueueWait)
+	case c.App.MaxInFlight > 0 && c.App.ShedRetryAfter <= 0:
+		return fmt.Errorf("invalid SERVICE_SHED_RETRY_AFTER %s: must be positive", c.App.ShedRetryAfter)
+	case c.App.ImpersonationTTL <= 0 || c.App.ImpersonationTTL > time.Hour:
+		return fmt.Errorf("invalid SERVICE_IMPERSONATION_TTL %s: must be up to 1h", c.App.ImpersonationTTL)
+	case c.App.TokenRefreshWindow < 0 || c.App.TokenRefreshWindow > 30*time.Minute:
+		return fmt.Errorf("invalid SERVICE_TOKEN_REFRESH_WINDOW %s: must be 0..30m", c.App.TokenRefreshWindow)
+	case c.App.RoleCacheTTL < 0 || c.App.RoleCacheTTL > 5*time.Minute:
+		return fmt.Errorf("invalid SERVICE_ROLE_CACHE_TTL %s: must be 0..5m", c.App.RoleCacheTTL)
+	case c.App.UploadPolicyCacheTTL < 0 || c.App.UploadPolicyCacheTTL > 5*time.Minute:
+		return fmt.Errorf("invalid SERVICE_UPLOAD_POLICY_CACHE_TTL %s: must be 0..5m", c.App.UploadPolicyCacheTTL)
+	case c.App.EmailChangeTTL <= 0:
+		return fmt.Errorf("invalid SERVICE_EMAIL_CHANGE_TTL %s: must be positive", c.App.EmailChangeTTL)
+	case c.App.InvitationTTL <= 0:
+		return fmt.Errorf("invalid SERVICE_INVITATION_TTL %s: must be positive", c.App.InvitationTTL)
+	case c.App.InvitationURL != "" && !isAbsoluteURL(c.App.InvitationURL):
+		return fmt.Errorf("invalid SERVICE_INVITATION_URL %q: must be an absolute http(s) URL", c.App.InvitationURL)
+	case c.App.ReadOnlyPollInterval <= 0 || c.App.ReadOnlyPollInterval > time.Minute:
+		return fmt.Errorf("invalid SERVICE_READ_ONLY_POLL_INTERVAL %s: must be up to 1m", c.App.ReadOnlyPollInterval)
+	case c.App.TokenFormat != "jwt" && c.App.TokenFormat != "paseto-local" && c.App.TokenFormat != "paseto-public":
+		return fmt.Errorf("invalid SERVICE_TOKEN_FORMAT %q: must be jwt, paseto-local or paseto-public", c.App.TokenFormat)
+	case c.App.TokenFormat == "paseto-local" && (pasetoKeyErr != nil || len(pasetoKey) != 32):
+		return fmt.Errorf("invalid SERVICE_PASETO_KEY: must be 32 bytes in base64 for paseto-local")
+	case c.App.TokenFormat == "paseto-public" && (pasetoKeyErr != nil || len(pasetoKey) != 32 && len(pasetoKey) != 64):
+		return fmt.Errorf("invalid SERVICE_PASETO_KEY: must be 32 or 64 bytes in base64 for paseto-public")
+	case c.Password.Algorithm != "bcrypt" && c.Password.Algorithm != "argon2id":
+		return fmt.Errorf("invalid PASSWORD_HASH_ALGORITHM %q: must be bcrypt or argon2id", c.Password.Algorithm)
+	case c.Password.BcryptCost < 10 || c.Password.BcryptCost > 31:
+		return fmt.Errorf("invalid PASSWORD_BCRYPT_COST %d: must be 10..31", c.Password.BcryptCost)
+	case c.Password.Argon2Time < 1 || c.Password.Argon2Threads < 1:
+		return fmt.Errorf("invalid PASSWORD_ARGON2_TIME/THREADS %d/%d: must be positive", c.Password.Argon2Time, c.Password.Argon2Threads)
+	case c.Password.Argon2Memory < 8*uint32(c.Password.Argon2Threads):
+		return fmt.Errorf("invalid PASSWORD_ARGON2_MEMORY %d: must be at least 8KiB per thread", c.Password.Argon2Memory)
+	case !activeKey:
+		return fmt.Errorf("invalid PII_ENCRYPTION_ACTIVE_KEY %q: must be one of PII_ENCRYPTION_KEYS", c.PII.ActiveKey)
+	case err != nil || len(indexKey) < 32:
+		return fmt.Errorf("invalid PII_BLIND_INDEX_KEY: must be at least 32 bytes in base64")
+	case c.Retention.InactiveMonths < 0:
+		return fmt.Errorf("invalid RETENTION_INACTIVE_MONTHS %d: must not be negative", c.Retention.InactiveMonths)
+	case c.Retention.InactiveMonths > 0 && len(c.Retention.Columns) == 0:
+		return fmt.Errorf("invalid RETENTION_COLUMNS: must not be empty when RETENTION_INACTIVE_MONTHS is set")
+	case c.Retention.AuditDays < 0:
+		return fmt.Errorf("invalid RETENTION_AUDIT_DAYS %d: must not be negative", c.Retention.AuditDays)
+	case c.Retention.AuditDays > 0 && c.Retention.AuditBucket == "":
+		return fmt.Errorf("invalid RETENTION_AUDIT_BUCKET: must be set when RETENTION_AUDIT_DAYS is set")
+	case c.Suspension.InactiveDays < 0:
+		return fmt.Errorf("invalid SUSPENSION_INACTIVE_DAYS %d: must not be negative", c.Suspension.InactiveDays)
+	case c.Suspension.WarnDays < 0:
+		return fmt.Errorf("invalid SUSPENSION_WARN_DAYS %d: must not be negative", c.Suspension.WarnDays)
+	case c.Suspension.InactiveDays > 0 && c.Suspension.WarnDays >= c.Suspension.InactiveDays:
+		return fmt.Errorf("invalid SUSPENSION_WARN_DAYS %d: must be less than SUSPENSION_INACTIVE_DAYS", c.Suspension.WarnDays)
+	case c.Suspension.GraceDays < 0:
+		return fmt.Errorf("invalid SUSPENSION_GRACE_DAYS %d: must not be negative", c.Suspension.GraceDays)
+	case c.Hooks.HRSecret != "" && len(c.Hooks.HRSecret) < 32:
+		return fmt.Errorf("invalid HOOKS_HR_SECRET: must be at least 32 characters")
+	case c.Hooks.MaxSkew <= 0:
+		return fmt.Errorf("invalid HOOKS_MAX_SKEW %s: must be positive", c.Hooks.MaxSkew)
+	case c.Email.Provider != "log" && c.Email.Provider != "smtp" && c.Email.Provider != "ses":
+		return fmt.Errorf("invalid EMAIL_PROVIDER %q: must be log, smtp or ses", c.Email.Provider)
+	case !isEmail(c.Email.From):
+		return fmt.Errorf("invalid EMAIL_FROM %q: must be an email address", c.Email.From)
+	case c.Email.Provider == "smtp" && c.Email.SMTPHost == "":
+		return fmt.Errorf("invalid EMAIL_SMTP_HOST: must be set with EMAIL_PROVIDER=smtp")
+	case c.Email.Provider == "ses" && c.Email.SESRegion == "":
+		return fmt.Errorf("invalid EMAIL_SES_REGION: must be set with EMAIL_PROVIDER=ses")
+	case c.Email.MaxRetries < 0 || c.Email.MaxRetries > 10:
+		return fmt.Errorf("invalid EMAIL_MAX_RETRIES %d: must be 0..10", c.Email.MaxRetries)
+	case c.Email.MaxRetries > 0 && c.Email.RetryBaseDelay <= 0:
+		return fmt.Errorf("invalid EMAIL_RETRY_BASE_DELAY %s: must be positive", c.Email.RetryBaseDelay)
+	case c.Email.LoginURL != "" && !isAbsoluteURL(c.Email.LoginURL):
+		return fmt.Errorf("invalid EMAIL_LOGIN_URL %q: must be an absolute http(s) URL", c.Email.LoginURL)
+	case c.Notifications.WebhookTimeout <= 0 || c.Notifications.WebhookTimeout > time.Minute:
+		return fmt.Errorf("invalid NOTIFICATIONS_WEBHOOK_TIMEOUT %s: must be up to 1m", c.Notifications.WebhookTimeout)
+	case c.Anomaly.TravelWindow < 0:
+		return fmt.Errorf("invalid ANOMALY_TRAVEL_WINDOW %s: must not be negative", c.Anomaly.TravelWindow)
+	case c.Anomaly.HistorySize < 1 || c.Anomaly.HistorySize > 1000:
+		return fmt.Errorf("invalid ANOMALY_HISTORY_SIZE %d: must be 1..1000", c.Anomaly.HistorySize)
+	case c.Anomaly.AlertWebhookURL != "" && !isAbsoluteURL(c.Anomaly.AlertWebhookURL):
+		return fmt.Errorf("invalid ANOMALY_ALERT_WEBHOOK_URL %q: must be an absolute http(s) URL", c.Anomaly.AlertWebhookURL)
+	case c.Alerts.Provider != "log" && c.Alerts.Provider != "slack" && c.Alerts.Provider != "pagerduty":
+		return fmt.Errorf("invalid ALERT_PROVIDER %q: must be log, slack or pagerduty", c.Alerts.Provider)
+	case c.Alerts.WebhookURL != "" && !isAbsoluteURL(c.Alerts.WebhookURL):
+		return fmt.Errorf("invalid ALERT_WEBHOOK_URL %q: must be an absolute http(s) URL", c.Alerts.WebhookURL)
+	case c.Alerts.Provider == "slack" && c.Alerts.WebhookURL == "":
+		return fmt.Errorf("invalid ALERT_WEBHOOK_URL: must be set for ALERT_PROVIDER=slack")
+	case c.Alerts.Provider == "pagerduty" && c.Alerts.PagerDutyRoutingKey == "":
+		return fmt.Errorf("invalid ALERT_PAGERDUTY_ROUTING_KEY: must be set for ALERT_PROVIDER=pagerduty")
+	case c.Alerts.DedupWindow < 0:
+		return fmt.Errorf("invalid ALERT_DEDUP_WINDOW %s: must not be negative", c.Alerts.DedupWindow)
+	case c.Alerts.RateLimit < 0:
+		return fmt.Errorf("invalid ALERT_RATE_LIMIT %d: must not be negative", c.Alerts.RateLimit)
+	case c.Alerts.Timeout <= 0 || c.Alerts.Timeout > time.Minute:
+		return fmt.Errorf("invalid ALERT_TIMEOUT %s: must be up to 1m", c.Alerts.Timeout)
+	case c.Usage.FlushInterval <= 0 || c.Usage.FlushInterval > time.Hour:
+		return fmt.Errorf("invalid USAGE_FLUSH_INTERVAL %s: must be up to 1h", c.Usage.FlushInterval)
+	case c.Usage.QueueSize <= 0:
+		return fmt.Errorf("invalid USAGE_QUEUE_SIZE %d: must be positive", c.Usage.QueueSize)
+	// the search vector of a text is limited to 1MB by Postgres
+	case c.OCR.MaxTextSize < 1 || c.OCR.MaxTextSize > 512<<10:
+		return fmt.Errorf("invalid OCR_MAX_TEXT_SIZE %d: must be 1..524288", c.OCR.MaxTextSize)
+	case c.OCR.MaxSourceSize < 1:
+		return fmt.Errorf("invalid OCR_MAX_SOURCE_SIZE %d: must be positive", c.OCR.MaxSourceSize)
+	case c.Thumbnails.MaxSourceSize < 1:
+		return fmt.Errorf("invalid THUMBNAILS_MAX_SOURCE_SIZE %d: must be positive", c.Thumbnails.MaxSourceSize)
+	case c.Thumbnails.MaxSourcePixels < 1:
+		return fmt.Errorf("invalid THUMBNAILS_MAX_SOURCE_PIXELS %d: must be positive", c.Thumbnails.MaxSourcePixels)
+	case c.Jobs.BackupInterval < 0:
+		return fmt.Errorf("invalid JOBS_BACKUP_INTERVAL %s: must not be negative", c.Jobs.BackupInterval)
+	case c.Jobs.BackupInterval > 0 && (c.Backup.Bucket == "" || c.Backup.EncryptionKey == ""):
+		return fmt.Errorf("invalid JOBS_BACKUP_INTERVAL %s: needs BACKUP_BUCKET and BACKUP_ENCRYPTION_KEY", c.Jobs.BackupInterval)
+	case c.Jobs.PurgeProcessedEventsInterval < 0:
+		return fmt.Errorf("invalid JOBS_PURGE_PROCESSED_EVENTS_INTERVAL %s: must not be negative", c.Jobs.PurgeProcessedEventsInterval)
+	case c.Jobs.PurgeExpiredFilesInterval < 0:
+		return fmt.Errorf("invalid JOBS_PURGE_EXPIRED_FILES_INTERVAL %s: must not be negative", c.Jobs.PurgeExpiredFilesInterval)
+	case c.Jobs.ResumeDeletionsInterval < 0:
+		return fmt.Errorf("invalid JOBS_RESUME_DELETIONS_INTERVAL %s: must not be negative", c.Jobs.ResumeDeletionsInterval)
+	case c.Jobs.RelayOutboxInterval < 0:
+		return fmt.Errorf("invalid JOBS_RELAY_OUTBOX_INTERVAL %s: must not be negative", c.Jobs.RelayOutboxInterval)
+	case c.Jobs.ArchiveAuditInterval < 0:
+		return fmt.Errorf("invalid JOBS_ARCHIVE_AUDIT_INTERVAL %s: must not be negative", c.Jobs.ArchiveAuditInterval)
+	case c.Jobs.ArchiveAuditInterval > 0 && c.Retention.AuditDays == 0:
+		return fmt.Errorf("invalid JOBS_ARCHIVE_AUDIT_INTERVAL %s: needs RETENTION_AUDIT_DAYS", c.Jobs.ArchiveAuditInterval)
+	case c.Jobs.SuspendInactiveInterval < 0:
+		return fmt.Errorf("invalid JOBS_SUSPEND_INACTIVE_INTERVAL %s: must not be negative", c.Jobs.SuspendInactiveInterval)
+	case c.Backup.EncryptionKey != "" && (backupKeyErr != nil || len(backupKey) != 32):
+		return fmt.Errorf("invalid BACKUP_ENCRYPTION_KEY: must be 32 bytes in base64")
+	case c.LDAP.URL != "" && !isLDAPURL(c.LDAP.URL):
+		return fmt.Errorf("invalid LDAP_URL %q: must be an ldap:// or ldaps:// URL", c.LDAP.URL)
+	case c.LDAP.URL != "" && c.LDAP.BaseDN == "":
+		return fmt.Errorf("invalid LDAP_BASE_DN: must be set with LDAP_URL")
+	case c.LDAP.URL != "" && c.LDAP.AttrEmail == "":
+		return fmt.Errorf("invalid LDAP_ATTR_EMAIL: must be set with LDAP_URL")
+	case c.LDAP.URL != "" && c.LDAP.Timeout <= 0:
+		return fmt.Errorf("invalid LDAP_TIMEOUT %s: must be positive", c.LDAP.Timeout)
+	case c.Jobs.SyncDirectoryInterval < 0:
+		return fmt.Errorf("invalid JOBS_SYNC_DIRECTORY_INTERVAL %s: must not be negative", c.Jobs.SyncDirectoryInterval)
+	case c.Jobs.SyncDirectoryInterval > 0 && c.LDAP.URL == "":
+		return fmt.Errorf("invalid JOBS_SYNC_DIRECTORY_INTERVAL %s: needs LDAP_URL", c.Jobs.SyncDirectoryInterval)
+	case c.OTP.TTL <= 0:
+		return fmt.Errorf("invalid OTP_TTL %s: must be positive", c.OTP.TTL)
+	case c.OTP.CodeLength < 4 || c.OTP.CodeLength > 10:
+		return fmt.Errorf("invalid OTP_CODE_LENGTH %d: must be 4..10", c.OTP.CodeLength)
+	case c.OTP.MaxAttempts < 1:
+		return fmt.Errorf("invalid OTP_MAX_ATTEMPTS %d: must be positive", c.OTP.MaxAttempts)
+	case c.OTP.Cooldown < 0:
+		return fmt.Errorf("invalid OTP_COOLDOWN %s: must not be negative", c.OTP.Cooldown)
+	case c.OTP.IPRequestsPerMinute < 1:
+		return fmt.Errorf("invalid OTP_IP_REQUESTS_PER_MINUTE %d: must be positive", c.OTP.IPRequestsPerMinute)
+	case c.OTP.SMSProvider != "log" && c.OTP.SMSProvider != "twilio":
+		return fmt.Errorf("invalid OTP_SMS_PROVIDER %q: must be log or twilio", c.OTP.SMSProvider)
+	case c.OTP.SMSProvider == "log" && isProduction(c.App.Env):
+		return fmt.Errorf("invalid OTP_SMS_PROVIDER log: the codes go to the log, must be twilio for SERVICE_ENV %q", c.App.Env)
+	case c.OTP.SMSProvider == "twilio" && (c.OTP.TwilioAccountSID == "" || c.OTP.TwilioAuthToken == "" || c.OTP.TwilioFrom == ""):
+		return fmt.Errorf("invalid OTP_TWILIO_ACCOUNT_SID/AUTH_TOKEN/FROM: must be set for OTP_SMS_PROVIDER twilio")
+	case c.MQ.BufferSize < 0 || c.MQ.BufferSize > 1<<16:
+		return fmt.Errorf("invalid RABBITMQ_BUFFER_SIZE %d: must be 0..65536", c.MQ.BufferSize)
+	case c.MQ.PublishWorkers < 1 || c.MQ.PublishWorkers > 64:
+		return fmt.Errorf("invalid RABBITMQ_PUBLISH_WORKERS %d: must be 1..64", c.MQ.PublishWorkers)
+	case c.MQ.PublishBatchSize < 1 || c.MQ.PublishBatchSize > 1000:
+		return fmt.Errorf("invalid RABBITMQ_PUBLISH_BATCH_SIZE %d: must be 1..1000", c.MQ.PublishBatchSize)
+	case c.MQ.EnqueueTimeout < 0:
+		return fmt.Errorf("invalid RABBITMQ_ENQUEUE_TIMEOUT %s: must not be negative", c.MQ.EnqueueTimeout)
+	case c.MQ.RetryBufferSize < 0 || c.MQ.RetryBufferSize > 1<<20:
+		return fmt.Errorf("invalid RABBITMQ_RETRY_BUFFER_SIZE %d: must be 0..1048576", c.MQ.RetryBufferSize)
+	case c.MQ.RetryInterval <= 0:
+		return fmt.Errorf("invalid RABBITMQ_RETRY_INTERVAL %s: must be positive", c.MQ.RetryInterval)
+	case c.MQ.LeaderElection && c.MQ.LeaderRetryInterval <= 0:
+		return fmt.Errorf("invalid RABBITMQ_LEADER_RETRY_INTERVAL %s: must be positive", c.MQ.LeaderRetryInterval)
+	case c.DB.MaxRetries < 0 || c.DB.MaxRetries > 10:
+		return fmt.Errorf("invalid POSTGRES_MAX_RETRIES %d: must be 0..10", c.DB.MaxRetries)
+	case c.DB.MaxRetries > 0 && c.DB.RetryBaseDelay <= 0:
+		return fmt.Errorf("invalid POSTGRES_RETRY_BASE_DELAY %s: must be positive", c.DB.RetryBaseDelay)
+	case c.DB.MaxConcurrent < 0:
+		return fmt.Errorf("invalid POSTGRES_MAX_CONCURRENT %d: must not be negative", c.DB.MaxConcurrent)
+	case c.DB.BulkheadWait < 0:
+		return fmt.Errorf("invalid POSTGRES_BULKHEAD_WAIT %s: must not be negative", c.DB.BulkheadWait)
+	case c.DB.SlowQueryThreshold < 0:
+		return fmt.Errorf("invalid POSTGRES_SLOW_QUERY_THRESHOLD %s: must not be negative", c.DB.SlowQueryThreshold)
+	case c.S3.DownloadMode != "public" && c.S3.DownloadMode != "proxy":
+		return fmt.Errorf("invalid S3_DOWNLOAD_MODE %q: must be public or proxy", c.S3.DownloadMode)
+	case c.S3.DownloadMode == "proxy" && !isAbsoluteURL(c.S3.ProxyURL):
+		return fmt.Errorf("invalid S3_PROXY_URL %q: must be an absolute http(s) URL in the proxy mode", c.S3.ProxyURL)
+	case slices.Contains(pendingStorageDrivers, c.Storage.Driver):
+		return fmt.Errorf("unsupported STORAGE_DRIVER %q: not implemented yet, must be one of %s", c.Storage.Driver, strings.Join(StorageDrivers, ", "))
+	case !slices.Contains(StorageDrivers, c.Storage.Driver):
+		return fmt.Errorf("invalid STORAGE_DRIVER %q: must be one of %s", c.Storage.Driver, strings.Join(StorageDrivers, ", "))
+	case c.MQ.MaxConcurrent < 0:
+		return fmt.Errorf("invalid RABBITMQ_MAX_CONCURRENT %d: must not be negative", c.MQ.MaxConcurrent)
+	case c.MQ.BulkheadWait < 0:
+		return fmt.Errorf("invalid RABBITMQ_BULKHEAD_WAIT %s: must not be negative", c.MQ.BulkheadWait)
+	case c.MQ.TopologyCheck != "warn" && c.MQ.TopologyCheck != "fail" && c.MQ.TopologyCheck != "repair" && c.MQ.TopologyCheck != "off":
+		return fmt.Errorf("invalid RABBITMQ_TOPOLOGY_CHECK %q: must be warn, fail, repair or off", c.MQ.TopologyCheck)
+	case c.MQ.DeadLetterQueue != "" && c.MQ.DeadLetterQueue == c.MQ.QueueName:
+		return fmt.Errorf("invalid RABBITMQ_DLQ_NAME %q: must differ from RABBITMQ_QUEUE_NAME", c.MQ.DeadLetterQueue)
+	case c.MQ.ConsumerLanes < 1 || c.MQ.ConsumerLanes > 64:
+		return fmt.Errorf("invalid RABBITMQ_CONSUMER_LANES %d: must be 1..64", c.MQ.ConsumerLanes)
+	case c.MQ.DedupTTL < 0:
+		return fmt.Errorf("invalid RABBITMQ_DEDUP_TTL %s: must not be negative", c.MQ.DedupTTL)
+	case c.MQ.MgmtPort != "" && c.MQ.MgmtTimeout <= 0:
+		return fmt.Errorf("invalid RABBITMQ_MGMT_TIMEOUT %s: must be positive", c.MQ.MgmtTimeout)
+	case c.Timeouts.Handler < 0:
+		return fmt.Errorf("invalid HTTP_HANDLER_TIMEOUT %s: must not be negative", c.Timeouts.Handler)
+	case c.Timeouts.Upload < 0:
+		return fmt.Errorf("invalid HTTP_UPLOAD_TIMEOUT %s: must not be negative", c.Timeouts.Upload)
+	case c.Timeouts.DBQuery < 0:
+		return fmt.Errorf("invalid DB_QUERY_TIMEOUT %s: must not be negative", c.Timeouts.DBQuery)
+	case c.Timeouts.Storage < 0:
+		return fmt.Errorf("invalid STORAGE_TIMEOUT %s: must not be negative", c.Timeouts.Storage)
+	case c.Startup.MaxWait < 0:
+		return fmt.Errorf("invalid STARTUP_MAX_WAIT %s: must not be negative", c.Startup.MaxWait)
+	case c.Startup.MaxWait > 0 && c.Startup.RetryBaseDelay <= 0:
+		return fmt.Errorf("invalid STARTUP_RETRY_BASE_DELAY %s: must be positive", c.Startup.RetryBaseDelay)
+	case c.Startup.MaxWait > 0 && c.Startup.RetryMaxDelay < c.Startup.RetryBaseDelay:
+		return fmt.Errorf("invalid STARTUP_RETRY_MAX_DELAY %s: must not be less than STARTUP_RETRY_BASE_DELAY", c.Startup.RetryMaxDelay)
+	case (c.TLS.CertFile == "") != (c.TLS.KeyFile == ""):
+		return fmt.Errorf("invalid TLS_CERT_FILE/TLS_KEY_FILE: must be set together")
+	case c.TLS.ClientCAFile != "" && c.TLS.CertFile == "":
+		return fmt.Errorf("invalid TLS_CLIENT_CA_FILE: needs TLS_CERT_FILE")
+	case len(c.TLS.ClientIdentities) > 0 && c.TLS.ClientCAFile == "":
+		return fmt.Errorf("invalid TLS_CLIENT_IDENTITIES: needs TLS_CLIENT_CA_FILE")
+	}
+
+	return nil
 }
 
 func (c Config) DBDSN() (string, error) {
@@ -121,3 +1285,67 @@ func (c Config) AMQPDSN() (string, error) {
 		url.PathEscape(c.MQ.Vhost),
 	), nil
 }
+
+// MQManagementURL - of the management API, empty if its port is not set
+func (c Config) MQManagementURL() string {
+	if c.MQ.Host == "" || c.MQ.MgmtPort == "" {
+		return ""
+	}
+	return fmt.Sprintf("http://%s:%s", c.MQ.Host, c.MQ.MgmtPort)
+}
+
+func (r Resilience) validate(prefix string) error {
+	switch {
+	case r.Timeout <= 0:
+		return fmt.Errorf("invalid %s_TIMEOUT %s: must be positive", prefix, r.Timeout)
+	case r.MaxRetries < 0:
+		return fmt.Errorf("invalid %s_MAX_RETRIES %d: must not be negative", prefix, r.MaxRetries)
+	case r.MaxConcurrent < 0:
+		return fmt.Errorf("invalid %s_MAX_CONCURRENT %d: must not be negative", prefix, r.MaxConcurrent)
+	case r.BulkheadWait < 0:
+		return fmt.Errorf("invalid %s_BULKHEAD_WAIT %s: must not be negative", prefix, r.BulkheadWait)
+	}
+	return nil
+}
+
+func isLDAPURL(s string) bool {
+	u, err := url.Parse(s)
+	return err == nil && (u.Scheme == "ldap" || u.Scheme == "ldaps") && u.Host != ""
+}
+
+// isEmail - a bare address, no display name
+func isEmail(s string) bool {
+	a, err := mail.ParseAddress(s)
+	return err == nil && a.Address == s
+}
+
+func isAbsoluteURL(s string) bool {
+	u, err := url.Parse(s)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+// isProduction - the SERVICE_ENV values the router runs in the release mode for
+func isProduction(env string) bool {
+	switch env {
+	case "release", "prod", "production":
+		return true
+	}
+	return false
+}
+
+func isHTTPMethod(method string) bool {
+	switch method {
+	case "GET", "HEAD", "POST", "PUT", "PATCH", "DELETE":
+		return true
+	}
+	return false
+}
+
+// isTimezone - "Local" is not one: it depends on the host
+func isTimezone(name string) bool {
+	if name == "" || name == "Local" {
+		return false
+	}
+	_, err := time.LoadLocation(name)
+	return err == nil
+}
@@ -0,0 +1,355 @@
+package config
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// testKey - 32 zero bytes
+const testKey = "AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA="
+
+var storageResilience = Resilience{Timeout: 10 * time.Second, MaxRetries: 3, RetryBaseDelay: 100 * time.Millisecond}
+
+func TestValidate_Table(t *testing.T) {
+	valid := func() Config {
+		return Config{
+			App: APP{
+				PageSize:         50,
+				MaxUploadSize:    10 << 20,
+				MaxLogBodySize:   4 << 10,
+				ImpersonationTTL: 15 * time.Minute,
+				RoleCacheTTL:     30 * time.Second,
+				EmailChangeTTL:   24 * time.Hour,
+				InvitationTTL:    72 * time.Hour,
+				TokenFormat:      "jwt",
+
+				ReadOnlyPollInterval: 5 * time.Second,
+			},
+			DB:    DB{SchemaCheck: "fail"},
+			Hooks: Hooks{MaxSkew: 5 * time.Minute},
+			Email: Email{
+				Provider:       "log",
+				From:           "no-reply@usermanager.local",
+				MaxRetries:     3,
+				RetryBaseDelay: 500 * time.Millisecond,
+			},
+			Notifications: Notifications{WebhookTimeout: 5 * time.Second},
+			Anomaly:       Anomaly{TravelWindow: 2 * time.Hour, NewDevice: true, HistorySize: 20},
+			Alerts:        Alerts{Provider: "log", DedupWindow: 10 * time.Minute, RateLimit: 20, Timeout: 5 * time.Second},
+			Timezones:     Timezones{Default: "UTC"},
+			Usage:         Usage{FlushInterval: time.Minute, QueueSize: 10000},
+			OCR:           OCR{MaxTextSize: 256 << 10, MaxSourceSize: 20 << 20},
+			Thumbnails:    Thumbnails{MaxSourceSize: 20 << 20, MaxSourcePixels: 40_000_000},
+			S3:            S3{DownloadMode: "public", Resilience: storageResilience},
+			Azure:         Azure{Resilience: storageResilience},
+			GCS:           GCS{Resilience: storageResilience},
+			Storage:       Storage{Driver: "s3"},
+			MQ: MQ{
+				BufferSize:       128,
+				PublishWorkers:   2,
+				PublishBatchSize: 50,
+				EnqueueTimeout:   time.Second,
+				RetryBufferSize:  1024,
+				RetryInterval:    2 * time.Second,
+				TopologyCheck:    "warn",
+				ConsumerLanes:    1,
+			},
+			Password: Password{
+				Algorithm:     "bcrypt",
+				BcryptCost:    12,
+				Argon2Memory:  64 << 10,
+				Argon2Time:    1,
+				Argon2Threads: 4,
+			},
+			OTP: OTP{
+				TTL:                 5 * time.Minute,
+				CodeLength:          6,
+				MaxAttempts:         5,
+				Cooldown:            time.Minute,
+				IPRequestsPerMinute: 10,
+				SMSProvider:         "log",
+			},
+			PII: PII{
+				Keys:          []string{"k1:" + testKey, "k2:" + testKey},
+				ActiveKey:     "k2",
+				BlindIndexKey: testKey,
+			},
+		}
+	}
+
+	type tc struct {
+		name    string
+		mutate  func(c *Config)
+		wantErr string
+	}
+	cases := []tc{
+		{"defaults are valid", func(c *Config) {}, ""},
+		{"page size zero", func(c *Config) { c.App.PageSize = 0 }, "invalid SERVICE_PAGE_SIZE 0: must be 1..100"},
+		{"page size too big", func(c *Config) { c.App.PageSize = 101 }, "invalid SERVICE_PAGE_SIZE 101: must be 1..100"},
+		{"upload size zero", func(c *Config) { c.App.MaxUploadSize = 0 }, "invalid SERVICE_MAX_UPLOAD_SIZE 0: must be 1..1GB"},
+		{"log body disabled", func(c *Config) { c.App.MaxLogBodySize = 0 }, ""},
+		{"log body negative", func(c *Config) { c.App.MaxLogBodySize = -1 }, "invalid SERVICE_MAX_LOG_BODY_SIZE -1: must be 0..1MB"},
+		{"file ops unlimited", func(c *Config) { c.App.MaxFileOpsPerUser = 0 }, ""},
+		{"file ops negative", func(c *Config) { c.App.MaxFileOpsPerUser = -1 }, "invalid SERVICE_MAX_FILE_OPS_PER_USER -1: must be 0..100"},
+		{"admission", func(c *Config) { c.App.MaxInFlight, c.App.MaxQueued, c.App.ShedRetryAfter = 200, 0, time.Second }, ""},
+		{"in flight negative", func(c *Config) { c.App.MaxInFlight = -1 }, "invalid SERVICE_MAX_IN_FLIGHT -1: must not be negative"},
+		{"queued negative", func(c *Config) { c.App.MaxInFlight, c.App.MaxQueued = 200, -1 }, "invalid SERVICE_MAX_QUEUED -1: must not be negative"},
+		{"shed retry after zero", func(c *Config) { c.App.MaxInFlight, c.App.ShedRetryAfter = 200, 0 }, "invalid SERVICE_SHED_RETRY_AFTER 0s: must be positive"},
+		{"impersonation ttl zero", func(c *Config) { c.App.ImpersonationTTL = 0 }, "invalid SERVICE_IMPERSONATION_TTL 0s: must be up to 1h"},
+		{"impersonation ttl too long", func(c *Config) { c.App.ImpersonationTTL = 2 * time.Hour }, "invalid SERVICE_IMPERSONATION_TTL 2h0m0s: must be up to 1h"},
+		{"token refresh window negative", func(c *Config) { c.App.TokenRefreshWindow = -time.Minute }, "invalid SERVICE_TOKEN_REFRESH_WINDOW -1m0s: must be 0..30m"},
+		{"token refresh window too long", func(c *Config) { c.App.TokenRefreshWindow = time.Hour }, "invalid SERVICE_TOKEN_REFRESH_WINDOW 1h0m0s: must be 0..30m"},
+		{"role cache ttl negative", func(c *Config) { c.App.RoleCacheTTL = -time.Second }, "invalid SERVICE_ROLE_CACHE_TTL -1s: must be 0..5m"},
+		{"role cache ttl too long", func(c *Config) { c.App.RoleCacheTTL = 10 * time.Minute }, "invalid SERVICE_ROLE_CACHE_TTL 10m0s: must be 0..5m"},
+		{"upload policy cache ttl too long", func(c *Config) { c.App.UploadPolicyCacheTTL = time.Hour }, "invalid SERVICE_UPLOAD_POLICY_CACHE_TTL 1h0m0s: must be 0..5m"},
+		{"email change ttl zero", func(c *Config) { c.App.EmailChangeTTL = 0 }, "invalid SERVICE_EMAIL_CHANGE_TTL 0s: must be positive"},
+		{"invitation ttl zero", func(c *Config) { c.App.InvitationTTL = 0 }, "invalid SERVICE_INVITATION_TTL 0s: must be positive"},
+		{"invitation url", func(c *Config) { c.App.InvitationURL = "https://app.example.com/signup" }, ""},
+		{"invitation url relative", func(c *Config) { c.App.InvitationURL = "/signup" }, `invalid SERVICE_INVITATION_URL "/signup": must be an absolute http(s) URL`},
+		{"read-only poll interval zero", func(c *Config) { c.App.ReadOnlyPollInterval = 0 }, "invalid SERVICE_READ_ONLY_POLL_INTERVAL 0s: must be up to 1m"},
+		{"read-only poll interval too long", func(c *Config) { c.App.ReadOnlyPollInterval = time.Hour }, "invalid SERVICE_READ_ONLY_POLL_INTERVAL 1h0m0s: must be up to 1m"},
+		{"hr hook", func(c *Config) { c.Hooks.HRSecret = strings.Repeat("s", 32) }, ""},
+		{"hr hook secret too short", func(c *Config) { c.Hooks.HRSecret = "secret" }, "invalid HOOKS_HR_SECRET: must be at least 32 characters"},
+		{"hooks max skew zero", func(c *Config) { c.Hooks.MaxSkew = 0 }, "invalid HOOKS_MAX_SKEW 0s: must be positive"},
+		{"ldap sync", func(c *Config) {
+			c.LDAP = LDAP{URL: "ldaps://dc.example.com", BaseDN: "dc=example,dc=com", AttrEmail: "mail", Timeout: 30 * time.Second}
+			c.Jobs.SyncDirectoryInterval = time.Hour
+		}, ""},
+		{"email provider", func(c *Config) { c.Email.Provider = "sendgrid" }, `invalid EMAIL_PROVIDER "sendgrid": must be log, smtp or ses`},
+		{"email from with a name", func(c *Config) { c.Email.From = "Users <no-reply@usermanager.local>" }, `invalid EMAIL_FROM "Users <no-reply@usermanager.local>": must be an email address`},
+		{"smtp without host", func(c *Config) { c.Email.Provider = "smtp" }, "invalid EMAIL_SMTP_HOST: must be set with EMAIL_PROVIDER=smtp"},
+		{"ses without region", func(c *Config) { c.Email.Provider = "ses" }, "invalid EMAIL_SES_REGION: must be set with EMAIL_PROVIDER=ses"},
+		{"email retries out of range", func(c *Config) { c.Email.MaxRetries = 11 }, "invalid EMAIL_MAX_RETRIES 11: must be 0..10"},
+		{"email retry delay zero", func(c *Config) { c.Email.RetryBaseDelay = 0 }, "invalid EMAIL_RETRY_BASE_DELAY 0s: must be positive"},
+		{"email login url relative", func(c *Config) { c.Email.LoginURL = "/login" }, `invalid EMAIL_LOGIN_URL "/login": must be an absolute http(s) URL`},
+		{"webhook timeout zero", func(c *Config) { c.Notifications.WebhookTimeout = 0 }, "invalid NOTIFICATIONS_WEBHOOK_TIMEOUT 0s: must be up to 1m"},
+		{"webhook timeout too long", func(c *Config) { c.Notifications.WebhookTimeout = time.Hour }, "invalid NOTIFICATIONS_WEBHOOK_TIMEOUT 1h0m0s: must be up to 1m"},
+		{"openapi validation", func(c *Config) { c.App.OpenAPIValidation = true; c.App.Env = "dev" }, ""},
+		{"openapi validation in production", func(c *Config) { c.App.OpenAPIValidation = true; c.App.Env = "prod" }, `invalid SERVICE_OPENAPI_VALIDATION: must be off for SERVICE_ENV "prod"`},
+		{"middlewares reordered", func(c *Config) { c.App.Middlewares = []string{"request_id", "recovery", "compression", "logging"} }, ""},
+		{"middleware unknown", func(c *Config) { c.App.Middlewares = []string{"recovery", "gzip"} }, `invalid SERVICE_MIDDLEWARES item "gzip": must be one of ` + strings.Join(MiddlewareNames, ", ")},
+		{"middleware twice", func(c *Config) { c.App.Middlewares = []string{"logging", "recovery", "logging"} }, `invalid SERVICE_MIDDLEWARES item "logging": listed twice`},
+		{"cors without origins", func(c *Config) { c.App.Middlewares = []string{"cors"} }, "invalid SERVICE_CORS_ALLOWED_ORIGINS: must be set for the cors middleware"},
+		{"rate limit zero", func(c *Config) { c.App.Middlewares, c.App.RateLimitPerMinute = []string{"rate_limit"}, 0 }, "invalid SERVICE_RATE_LIMIT_PER_MINUTE 0: must be positive for the rate_limit middleware"},
+		{"org timezones", func(c *Config) {
+			c.Timezones = Timezones{Default: "Europe/Paris", Orgs: []string{"corp.example=America/New_York"}}
+		}, ""},
+		{"default timezone unknown", func(c *Config) { c.Timezones.Default = "Mars/Olympus" }, `invalid TIMEZONES_DEFAULT "Mars/Olympus": must be an IANA timezone`},
+		{"default timezone local", func(c *Config) { c.Timezones.Default = "Local" }, `invalid TIMEZONES_DEFAULT "Local": must be an IANA timezone`},
+		{"org timezone without org", func(c *Config) { c.Timezones.Orgs = []string{"=UTC"} }, `invalid TIMEZONES_ORGS item "=UTC": must be <organization>=<IANA timezone>`},
+		{"org timezone unknown", func(c *Config) { c.Timezones.Orgs = []string{"corp.example=CET+1"} }, `invalid TIMEZONES_ORGS item "corp.example=CET+1": must be <organization>=<IANA timezone>`},
+		{"org min ages", func(c *Config) {
+			c.AgePolicy = AgePolicy{MinAge: 18, Orgs: []string{"corp.example=16", "open.example=0"}}
+		}, ""},
+		{"min age negative", func(c *Config) { c.AgePolicy.MinAge = -1 }, `invalid AGE_POLICY_MIN_AGE -1: must be 0..150`},
+		{"org min age not a number", func(c *Config) { c.AgePolicy.Orgs = []string{"corp.example=adult"} }, `invalid AGE_POLICY_ORGS item "corp.example=adult": must be <organization>=<years 0..150>`},
+		{"org min age without org", func(c *Config) { c.AgePolicy.Orgs = []string{"=16"} }, `invalid AGE_POLICY_ORGS item "=16": must be <organization>=<years 0..150>`},
+		{"usage flush interval zero", func(c *Config) { c.Usage.FlushInterval = 0 }, "invalid USAGE_FLUSH_INTERVAL 0s: must be up to 1h"},
+		{"usage flush interval too long", func(c *Config) { c.Usage.FlushInterval = 2 * time.Hour }, "invalid USAGE_FLUSH_INTERVAL 2h0m0s: must be up to 1h"},
+		{"usage queue size zero", func(c *Config) { c.Usage.QueueSize = 0 }, "invalid USAGE_QUEUE_SIZE 0: must be positive"},
+		{"ocr max text size zero", func(c *Config) { c.OCR.MaxTextSize = 0 }, "invalid OCR_MAX_TEXT_SIZE 0: must be 1..524288"},
+		{"ocr max text size too big", func(c *Config) { c.OCR.MaxTextSize = 1 << 20 }, "invalid OCR_MAX_TEXT_SIZE 1048576: must be 1..524288"},
+		{"ocr max source size zero", func(c *Config) { c.OCR.MaxSourceSize = 0 }, "invalid OCR_MAX_SOURCE_SIZE 0: must be positive"},
+		{"thumbnails max source size zero", func(c *Config) { c.Thumbnails.MaxSourceSize = 0 }, "invalid THUMBNAILS_MAX_SOURCE_SIZE 0: must be positive"},
+		{"thumbnails max source pixels zero", func(c *Config) { c.Thumbnails.MaxSourcePixels = 0 }, "invalid THUMBNAILS_MAX_SOURCE_PIXELS 0: must be positive"},
+		{"backup", func(c *Config) {
+			c.Backup = Backup{Bucket: "backups", EncryptionKey: testKey}
+			c.Jobs.BackupInterval = 24 * time.Hour
+		}, ""},
+		{"backup key short", func(c *Config) { c.Backup.EncryptionKey = "c2hvcnQ=" }, "invalid BACKUP_ENCRYPTION_KEY: must be 32 bytes in base64"},
+		{"backup key not base64", func(c *Config) { c.Backup.EncryptionKey = "%%%" }, "invalid BACKUP_ENCRYPTION_KEY: must be 32 bytes in base64"},
+		{"backup without key", func(c *Config) {
+			c.Backup.Bucket = "backups"
+			c.Jobs.BackupInterval = 24 * time.Hour
+		}, "invalid JOBS_BACKUP_INTERVAL 24h0m0s: needs BACKUP_BUCKET and BACKUP_ENCRYPTION_KEY"},
+		{"purge processed events interval negative", func(c *Config) { c.Jobs.PurgeProcessedEventsInterval = -time.Hour }, "invalid JOBS_PURGE_PROCESSED_EVENTS_INTERVAL -1h0m0s: must not be negative"},
+		{"purge expired files interval negative", func(c *Config) { c.Jobs.PurgeExpiredFilesInterval = -time.Hour }, "invalid JOBS_PURGE_EXPIRED_FILES_INTERVAL -1h0m0s: must not be negative"},
+		{"resume deletions interval negative", func(c *Config) { c.Jobs.ResumeDeletionsInterval = -time.Hour }, "invalid JOBS_RESUME_DELETIONS_INTERVAL -1h0m0s: must not be negative"},
+		{"relay outbox interval negative", func(c *Config) { c.Jobs.RelayOutboxInterval = -time.Hour }, "invalid JOBS_RELAY_OUTBOX_INTERVAL -1h0m0s: must not be negative"},
+		{"archive audit interval negative", func(c *Config) { c.Jobs.ArchiveAuditInterval = -time.Hour }, "invalid JOBS_ARCHIVE_AUDIT_INTERVAL -1h0m0s: must not be negative"},
+		{"archive audit without retention", func(c *Config) { c.Jobs.ArchiveAuditInterval = time.Hour }, "invalid JOBS_ARCHIVE_AUDIT_INTERVAL 1h0m0s: needs RETENTION_AUDIT_DAYS"},
+		{"backup interval negative", func(c *Config) { c.Jobs.BackupInterval = -time.Hour }, "invalid JOBS_BACKUP_INTERVAL -1h0m0s: must not be negative"},
+		{"schema check read-only", func(c *Config) { c.DB.SchemaCheck = "read-only" }, ""},
+		{"schema check unknown", func(c *Config) { c.DB.SchemaCheck = "warn" }, `invalid POSTGRES_SCHEMA_CHECK "warn": must be fail, read-only or off`},
+		{"ldap url scheme", func(c *Config) { c.LDAP = LDAP{URL: "https://dc.example.com", BaseDN: "dc=example,dc=com"} }, `invalid LDAP_URL "https://dc.example.com": must be an ldap:// or ldaps:// URL`},
+		{"ldap without base dn", func(c *Config) { c.LDAP = LDAP{URL: "ldap://dc.example.com", AttrEmail: "mail"} }, "invalid LDAP_BASE_DN: must be set with LDAP_URL"},
+		{"ldap without email attr", func(c *Config) { c.LDAP = LDAP{URL: "ldap://dc.example.com", BaseDN: "dc=example,dc=com"} }, "invalid LDAP_ATTR_EMAIL: must be set with LDAP_URL"},
+		{"ldap without timeout", func(c *Config) { c.LDAP = LDAP{URL: "ldap://dc.example.com", BaseDN: "dc=example,dc=com", AttrEmail: "mail"} }, "invalid LDAP_TIMEOUT 0s: must be positive"},
+		{"directory sync without ldap", func(c *Config) { c.Jobs.SyncDirectoryInterval = time.Hour }, "invalid JOBS_SYNC_DIRECTORY_INTERVAL 1h0m0s: needs LDAP_URL"},
+		{"argon2id", func(c *Config) { c.Password.Algorithm = "argon2id" }, ""},
+		{"unknown hash algorithm", func(c *Config) { c.Password.Algorithm = "md5" }, `invalid PASSWORD_HASH_ALGORITHM "md5": must be bcrypt or argon2id`},
+		{"bcrypt cost too low", func(c *Config) { c.Password.BcryptCost = 4 }, "invalid PASSWORD_BCRYPT_COST 4: must be 10..31"},
+		{"argon2 without threads", func(c *Config) { c.Password.Argon2Threads = 0 }, "invalid PASSWORD_ARGON2_TIME/THREADS 1/0: must be positive"},
+		{"argon2 memory too low", func(c *Config) { c.Password.Argon2Memory = 16 }, "invalid PASSWORD_ARGON2_MEMORY 16: must be at least 8KiB per thread"},
+		{"otp ttl zero", func(c *Config) { c.OTP.TTL = 0 }, "invalid OTP_TTL 0s: must be positive"},
+		{"otp code too short", func(c *Config) { c.OTP.CodeLength = 3 }, "invalid OTP_CODE_LENGTH 3: must be 4..10"},
+		{"otp without attempts", func(c *Config) { c.OTP.MaxAttempts = 0 }, "invalid OTP_MAX_ATTEMPTS 0: must be positive"},
+		{"otp cooldown disabled", func(c *Config) { c.OTP.Cooldown = 0 }, ""},
+		{"otp ip limit zero", func(c *Config) { c.OTP.IPRequestsPerMinute = 0 }, "invalid OTP_IP_REQUESTS_PER_MINUTE 0: must be positive"},
+		{"otp unknown sms provider", func(c *Config) { c.OTP.SMSProvider = "sns" }, `invalid OTP_SMS_PROVIDER "sns": must be log or twilio`},
+		{"otp log sms in production", func(c *Config) { c.App.Env = "prod" }, `invalid OTP_SMS_PROVIDER log: the codes go to the log, must be twilio for SERVICE_ENV "prod"`},
+		{"otp twilio", func(c *Config) { c.OTP = twilioOTP(c.OTP) }, ""},
+		{"otp twilio without token", func(c *Config) { c.OTP = twilioOTP(c.OTP); c.OTP.TwilioAuthToken = "" }, "invalid OTP_TWILIO_ACCOUNT_SID/AUTH_TOKEN/FROM: must be set for OTP_SMS_PROVIDER twilio"},
+		{"trusted proxies", func(c *Config) { c.App.TrustedProxies = []string{"10.0.0.0/8", "192.168.1.10", "::1"} }, ""},
+		{"trusted proxy invalid", func(c *Config) { c.App.TrustedProxies = []string{"10.0.0.0/8", "lb.local"} }, `invalid SERVICE_TRUSTED_PROXIES item "lb.local": must be an IP or CIDR`},
+		{"pii active key unknown", func(c *Config) { c.PII.ActiveKey = "k3" }, `invalid PII_ENCRYPTION_ACTIVE_KEY "k3": must be one of PII_ENCRYPTION_KEYS`},
+		{"pii without keys", func(c *Config) { c.PII.Keys = nil }, `invalid PII_ENCRYPTION_ACTIVE_KEY "k2": must be one of PII_ENCRYPTION_KEYS`},
+		{"pii blind index key short", func(c *Config) { c.PII.BlindIndexKey = "c2hvcnQ=" }, "invalid PII_BLIND_INDEX_KEY: must be at least 32 bytes in base64"},
+		{"pii blind index key not base64", func(c *Config) { c.PII.BlindIndexKey = "%%%" }, "invalid PII_BLIND_INDEX_KEY: must be at least 32 bytes in base64"},
+		{"retention", func(c *Config) { c.Retention = Retention{InactiveMonths: 24, Columns: []string{"name", "phone"}} }, ""},
+		{"retention negative", func(c *Config) { c.Retention.InactiveMonths = -1 }, "invalid RETENTION_INACTIVE_MONTHS -1: must not be negative"},
+		{"retention without columns", func(c *Config) { c.Retention = Retention{InactiveMonths: 24} }, "invalid RETENTION_COLUMNS: must not be empty when RETENTION_INACTIVE_MONTHS is set"},
+		{"audit retention", func(c *Config) { c.Retention.AuditDays, c.Retention.AuditBucket = 365, "audit" }, ""},
+		{"audit retention negative", func(c *Config) { c.Retention.AuditDays = -1 }, "invalid RETENTION_AUDIT_DAYS -1: must not be negative"},
+		{"audit retention without bucket", func(c *Config) { c.Retention.AuditDays = 365 }, "invalid RETENTION_AUDIT_BUCKET: must be set when RETENTION_AUDIT_DAYS is set"},
+		{"alerts slack", func(c *Config) { c.Alerts.Provider, c.Alerts.WebhookURL = "slack", "https://hooks.example.com/x" }, ""},
+		{"alerts pagerduty", func(c *Config) { c.Alerts.Provider, c.Alerts.PagerDutyRoutingKey = "pagerduty", "rk" }, ""},
+		{"alerts unknown provider", func(c *Config) { c.Alerts.Provider = "email" }, `invalid ALERT_PROVIDER "email": must be log, slack or pagerduty`},
+		{"alerts slack without url", func(c *Config) { c.Alerts.Provider = "slack" }, "invalid ALERT_WEBHOOK_URL: must be set for ALERT_PROVIDER=slack"},
+		{"alerts url relative", func(c *Config) { c.Alerts.WebhookURL = "/hooks" }, `invalid ALERT_WEBHOOK_URL "/hooks": must be an absolute http(s) URL`},
+		{"alerts pagerduty without key", func(c *Config) { c.Alerts.Provider = "pagerduty" }, "invalid ALERT_PAGERDUTY_ROUTING_KEY: must be set for ALERT_PROVIDER=pagerduty"},
+		{"alerts dedup negative", func(c *Config) { c.Alerts.DedupWindow = -time.Minute }, "invalid ALERT_DEDUP_WINDOW -1m0s: must not be negative"},
+		{"alerts rate limit negative", func(c *Config) { c.Alerts.RateLimit = -1 }, "invalid ALERT_RATE_LIMIT -1: must not be negative"},
+		{"alerts timeout zero", func(c *Config) { c.Alerts.Timeout = 0 }, "invalid ALERT_TIMEOUT 0s: must be up to 1m"},
+		{"suspension", func(c *Config) { c.Suspension = Suspension{InactiveDays: 90, WarnDays: 14, GraceDays: 30} }, ""},
+		{"suspension negative", func(c *Config) { c.Suspension.InactiveDays = -1 }, "invalid SUSPENSION_INACTIVE_DAYS -1: must not be negative"},
+		{"suspension warning too early", func(c *Config) { c.Suspension = Suspension{InactiveDays: 7, WarnDays: 7} }, "invalid SUSPENSION_WARN_DAYS 7: must be less than SUSPENSION_INACTIVE_DAYS"},
+		{"suspension grace negative", func(c *Config) { c.Suspension.GraceDays = -1 }, "invalid SUSPENSION_GRACE_DAYS -1: must not be negative"},
+		{"suspend inactive interval negative", func(c *Config) { c.Jobs.SuspendInactiveInterval = -time.Hour }, "invalid JOBS_SUSPEND_INACTIVE_INTERVAL -1h0m0s: must not be negative"},
+		{"retention email column", func(c *Config) { c.Retention.Columns = []string{"email"} }, `invalid RETENTION_COLUMNS item "email": must be name, lastname, birth_date or phone`},
+		{"unbuffered mq", func(c *Config) { c.MQ.BufferSize = 0 }, ""},
+		{"mq buffer too big", func(c *Config) { c.MQ.BufferSize = 1<<16 + 1 }, "invalid RABBITMQ_BUFFER_SIZE 65537: must be 0..65536"},
+		{"no publish workers", func(c *Config) { c.MQ.PublishWorkers = 0 }, "invalid RABBITMQ_PUBLISH_WORKERS 0: must be 1..64"},
+		{"batch too big", func(c *Config) { c.MQ.PublishBatchSize = 1001 }, "invalid RABBITMQ_PUBLISH_BATCH_SIZE 1001: must be 1..1000"},
+		{"reject at once", func(c *Config) { c.MQ.EnqueueTimeout = 0 }, ""},
+		{"no retries", func(c *Config) { c.MQ.RetryBufferSize = 0 }, ""},
+		{"retry interval zero", func(c *Config) { c.MQ.RetryInterval = 0 }, "invalid RABBITMQ_RETRY_INTERVAL 0s: must be positive"},
+		{"leader election", func(c *Config) { c.MQ.LeaderElection, c.MQ.LeaderRetryInterval = true, 5*time.Second }, ""},
+		{"leader retry zero", func(c *Config) { c.MQ.LeaderElection = true }, "invalid RABBITMQ_LEADER_RETRY_INTERVAL 0s: must be positive"},
+		{"db retries", func(c *Config) { c.DB.MaxRetries, c.DB.RetryBaseDelay = 4, 250*time.Millisecond }, ""},
+		{"db retries too many", func(c *Config) { c.DB.MaxRetries = 11 }, "invalid POSTGRES_MAX_RETRIES 11: must be 0..10"},
+		{"db retry delay zero", func(c *Config) { c.DB.MaxRetries = 4 }, "invalid POSTGRES_RETRY_BASE_DELAY 0s: must be positive"},
+		{"topology repair", func(c *Config) {
+			c.MQ.TopologyCheck, c.MQ.MgmtPort, c.MQ.MgmtTimeout = "repair", "15672", 5*time.Second
+		}, ""},
+		{"topology check unknown", func(c *Config) { c.MQ.TopologyCheck = "strict" }, `invalid RABBITMQ_TOPOLOGY_CHECK "strict": must be warn, fail, repair or off`},
+		{"mgmt timeout zero", func(c *Config) { c.MQ.MgmtPort = "15672" }, "invalid RABBITMQ_MGMT_TIMEOUT 0s: must be positive"},
+		{"dead-letter queue", func(c *Config) { c.MQ.QueueName, c.MQ.DeadLetterQueue = "users.queue", "users.queue.dlq" }, ""},
+		{"paseto local", func(c *Config) { c.App.TokenFormat, c.App.PasetoKey = "paseto-local", testKey }, ""},
+		{"paseto public", func(c *Config) { c.App.TokenFormat, c.App.PasetoKey = "paseto-public", testKey }, ""},
+		{"paseto without key", func(c *Config) { c.App.TokenFormat = "paseto-local" }, "invalid SERVICE_PASETO_KEY: must be 32 bytes in base64 for paseto-local"},
+		{"paseto public short key", func(c *Config) { c.App.TokenFormat, c.App.PasetoKey = "paseto-public", "AAAA" }, "invalid SERVICE_PASETO_KEY: must be 32 or 64 bytes in base64 for paseto-public"},
+		{"token format unknown", func(c *Config) { c.App.TokenFormat = "macaroon" }, `invalid SERVICE_TOKEN_FORMAT "macaroon": must be jwt, paseto-local or paseto-public`},
+		{"protobuf events", func(c *Config) { c.MQ.EventEncodings = []string{"usermanager.events=protobuf"} }, ""},
+		{"event encoding unknown", func(c *Config) { c.MQ.EventEncodings = []string{"usermanager.events=avro"} }, `invalid RABBITMQ_EVENT_ENCODINGS item "usermanager.events=avro": must be <exchange>=json|protobuf`},
+		{"event encoding without exchange", func(c *Config) { c.MQ.EventEncodings = []string{"protobuf"} }, `invalid RABBITMQ_EVENT_ENCODINGS item "protobuf": must be <exchange>=json|protobuf`},
+		{"consumer lanes", func(c *Config) { c.MQ.ConsumerLanes = 0 }, "invalid RABBITMQ_CONSUMER_LANES 0: must be 1..64"},
+		{"dedup ttl negative", func(c *Config) { c.MQ.DedupTTL = -time.Hour }, "invalid RABBITMQ_DEDUP_TTL -1h0m0s: must not be negative"},
+		{"dead-letter queue is the queue", func(c *Config) { c.MQ.QueueName, c.MQ.DeadLetterQueue = "users.queue", "users.queue" }, `invalid RABBITMQ_DLQ_NAME "users.queue": must differ from RABBITMQ_QUEUE_NAME`},
+		{"bulkheads disabled", func(c *Config) { c.DB.MaxConcurrent, c.S3.MaxConcurrent, c.MQ.MaxConcurrent = 0, 0, 0 }, ""},
+		{"db bulkhead negative", func(c *Config) { c.DB.MaxConcurrent = -1 }, "invalid POSTGRES_MAX_CONCURRENT -1: must not be negative"},
+		{"slow query log disabled", func(c *Config) { c.DB.SlowQueryThreshold = 0 }, ""},
+		{"slow query threshold negative", func(c *Config) { c.DB.SlowQueryThreshold = -time.Second }, "invalid POSTGRES_SLOW_QUERY_THRESHOLD -1s: must not be negative"},
+		{"s3 download mode unknown", func(c *Config) { c.S3.DownloadMode = "presigned" }, `invalid S3_DOWNLOAD_MODE "presigned": must be public or proxy`},
+		{"s3 proxy without url", func(c *Config) { c.S3.DownloadMode = "proxy" }, `invalid S3_PROXY_URL "": must be an absolute http(s) URL in the proxy mode`},
+		{"s3 proxy", func(c *Config) { c.S3.DownloadMode, c.S3.ProxyURL = "proxy", "https://api.example.com" }, ""},
+		{"s3 bulkhead wait negative", func(c *Config) { c.S3.BulkheadWait = -time.Second }, "invalid S3_BULKHEAD_WAIT -1s: must not be negative"},
+		{"azure timeout zero", func(c *Config) { c.Azure.Timeout = 0 }, "invalid AZURE_STORAGE_TIMEOUT 0s: must be positive"},
+		{"gcs retries negative", func(c *Config) { c.GCS.MaxRetries = -1 }, "invalid GCS_MAX_RETRIES -1: must not be negative"},
+		{"storage driver fs", func(c *Config) { c.Storage.Driver = "fs" }, ""},
+		{"storage driver azure", func(c *Config) { c.Storage.Driver = "azure" }, `unsupported STORAGE_DRIVER "azure": not implemented yet, must be one of s3, fs`},
+		{"storage driver gcs", func(c *Config) { c.Storage.Driver = "gcs" }, `unsupported STORAGE_DRIVER "gcs": not implemented yet, must be one of s3, fs`},
+		{"storage driver unknown", func(c *Config) { c.Storage.Driver = "minio" }, `invalid STORAGE_DRIVER "minio": must be one of s3, fs`},
+		{"mtls", func(c *Config) {
+			c.TLS = TLS{CertFile: "srv.crt", KeyFile: "srv.key", ClientCAFile: "ca.crt", ClientIdentities: []string{"spiffe://corp/billing=worker"}}
+		}, ""},
+		{"tls key missing", func(c *Config) { c.TLS.CertFile = "srv.crt" }, "invalid TLS_CERT_FILE/TLS_KEY_FILE: must be set together"},
+		{"client ca without tls", func(c *Config) { c.TLS.ClientCAFile = "ca.crt" }, "invalid TLS_CLIENT_CA_FILE: needs TLS_CERT_FILE"},
+		{"client identities without ca", func(c *Config) {
+			c.TLS = TLS{CertFile: "srv.crt", KeyFile: "srv.key", ClientIdentities: []string{"billing.internal=worker"}}
+		}, "invalid TLS_CLIENT_IDENTITIES: needs TLS_CLIENT_CA_FILE"},
+		{"client identity role unknown", func(c *Config) {
+			c.TLS = TLS{CertFile: "srv.crt", KeyFile: "srv.key", ClientCAFile: "ca.crt", ClientIdentities: []string{"spiffe://corp/billing=root"}}
+		}, `invalid TLS_CLIENT_IDENTITIES item "spiffe://corp/billing=root": must be <SAN>=admin or <SAN>=worker`},
+		{"anomaly rules disabled", func(c *Config) { c.Anomaly.TravelWindow, c.Anomaly.NewDevice = 0, false }, ""},
+		{"anomaly travel window negative", func(c *Config) { c.Anomaly.TravelWindow = -time.Hour }, "invalid ANOMALY_TRAVEL_WINDOW -1h0m0s: must not be negative"},
+		{"anomaly history size zero", func(c *Config) { c.Anomaly.HistorySize = 0 }, "invalid ANOMALY_HISTORY_SIZE 0: must be 1..1000"},
+		{"anomaly alert url relative", func(c *Config) { c.Anomaly.AlertWebhookURL = "/alerts" }, `invalid ANOMALY_ALERT_WEBHOOK_URL "/alerts": must be an absolute http(s) URL`},
+		{"timeouts disabled", func(c *Config) { c.Timeouts = Timeouts{} }, ""},
+		{"handler timeout negative", func(c *Config) { c.Timeouts.Handler = -time.Second }, "invalid HTTP_HANDLER_TIMEOUT -1s: must not be negative"},
+		{"db query timeout negative", func(c *Config) { c.Timeouts.DBQuery = -time.Second }, "invalid DB_QUERY_TIMEOUT -1s: must not be negative"},
+		{"storage timeout negative", func(c *Config) { c.Timeouts.Storage = -time.Second }, "invalid STORAGE_TIMEOUT -1s: must not be negative"},
+		{"route timeouts", func(c *Config) {
+			c.Timeouts.Routes = []string{"GET /api/v1/users=2m", "GET /api/v1/users/:user_id=5s", "POST /api/v1/users/:user_id/files=0"}
+		}, ""},
+		{"route timeout without method", func(c *Config) { c.Timeouts.Routes = []string{"/api/v1/users=2m"} }, `invalid HTTP_ROUTE_TIMEOUTS item "/api/v1/users=2m": must be <METHOD> <route>=<duration>`},
+		{"route timeout bad duration", func(c *Config) { c.Timeouts.Routes = []string{"GET /api/v1/users=2"} }, `invalid HTTP_ROUTE_TIMEOUTS item "GET /api/v1/users=2": must be <METHOD> <route>=<duration>`},
+		{"startup single attempt", func(c *Config) { c.Startup = Startup{} }, ""},
+		{"startup max wait negative", func(c *Config) { c.Startup.MaxWait = -time.Second }, "invalid STARTUP_MAX_WAIT -1s: must not be negative"},
+		{"startup base delay zero", func(c *Config) { c.Startup = Startup{MaxWait: time.Minute, RetryMaxDelay: time.Second} }, "invalid STARTUP_RETRY_BASE_DELAY 0s: must be positive"},
+		{"startup max delay below base", func(c *Config) {
+			c.Startup = Startup{MaxWait: time.Minute, RetryBaseDelay: time.Second, RetryMaxDelay: time.Millisecond}
+		}, "invalid STARTUP_RETRY_MAX_DELAY 1ms: must not be less than STARTUP_RETRY_BASE_DELAY"},
+	}
+
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			c := valid()
+			tt.mutate(&c)
+			err := c.Validate()
+			if tt.wantErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.EqualError(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestLoad_Defaults(t *testing.T) {
+	t.Setenv("SERVICE_PAGE_SIZE", "")
+	t.Setenv("SERVICE_MAX_UPLOAD_SIZE", "")
+	// no defaults for secrets
+	t.Setenv("PII_ENCRYPTION_KEYS", "k1:"+testKey)
+	t.Setenv("PII_ENCRYPTION_ACTIVE_KEY", "k1")
+	t.Setenv("PII_BLIND_INDEX_KEY", testKey)
+
+	c := Load()
+	require.Equal(t, 50, c.App.PageSize)
+	require.Equal(t, int64(10<<20), c.App.MaxUploadSize)
+	require.Empty(t, c.App.TrustedProxies)
+	require.Equal(t, []string{"X-Forwarded-For", "X-Real-IP"}, c.App.RemoteIPHeaders)
+	require.NoError(t, c.Validate())
+}
+
+func TestLoad_Unparsable(t *testing.T) {
+	t.Setenv("PII_ENCRYPTION_KEYS", "k1:"+testKey)
+	t.Setenv("PII_ENCRYPTION_ACTIVE_KEY", "k1")
+	t.Setenv("PII_BLIND_INDEX_KEY", testKey)
+	t.Setenv("SERVICE_MAX_UPLOAD_SIZE", "10MB")
+	t.Setenv("SERVICE_QUEUE_WAIT", "30")
+	t.Setenv("SERVICE_OPENAPI_VALIDATION", "yes")
+
+	err := Load().Validate()
+	require.ErrorContains(t, err, `invalid SERVICE_MAX_UPLOAD_SIZE "10MB": must be an integer`)
+	require.ErrorContains(t, err, `invalid SERVICE_QUEUE_WAIT "30": must be a duration(e.g. 30s, 5m)`)
+	require.ErrorContains(t, err, `invalid SERVICE_OPENAPI_VALIDATION "yes": must be true or false`)
+}
+
+func TestGetEnvList(t *testing.T) {
+	t.Setenv("TEST_LIST", " 10.0.0.0/8, ,192.168.1.10 ")
+	require.Equal(t, []string{"10.0.0.0/8", "192.168.1.10"}, getEnvList("TEST_LIST", nil))
+
+	t.Setenv("TEST_LIST", "")
+	require.Equal(t, []string{"def"}, getEnvList("TEST_LIST", []string{"def"}))
+}
+
+func twilioOTP(o OTP) OTP {
+	o.SMSProvider = "twilio"
+	o.TwilioAccountSID, o.TwilioAuthToken, o.TwilioFrom = "AC123", "token", "+15005550006"
+	return o
+}
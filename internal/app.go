@@ -2,12 +2,20 @@ package internal
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"log"
 	"net/http"
 	"os"
 	"os/signal"
+	"slices"
+	"strconv"
+	"strings"
 	"syscall"
 	"time"
 
@@ -20,30 +28,90 @@ import (
 	"golang.org/x/sync/errgroup"
 
 	"user-manager-api/config"
+	"user-manager-api/internal/application/jobs"
 	"user-manager-api/internal/application/ports"
 	"user-manager-api/internal/application/services"
+	"user-manager-api/internal/domain/login"
+	"user-manager-api/internal/domain/mode"
+	"user-manager-api/internal/domain/token"
+	domain "user-manager-api/internal/domain/user"
+	"user-manager-api/internal/infrastructure/azblob"
 	"user-manager-api/internal/infrastructure/db/postgres"
+	"user-manager-api/internal/infrastructure/db/postgres/audit"
+	"user-manager-api/internal/infrastructure/db/postgres/backup"
+	"user-manager-api/internal/infrastructure/db/postgres/deletion"
+	"user-manager-api/internal/infrastructure/db/postgres/device"
+	"user-manager-api/internal/infrastructure/db/postgres/directory"
+	"user-manager-api/internal/infrastructure/db/postgres/event"
+	loginRepo "user-manager-api/internal/infrastructure/db/postgres/login"
+	modeRepo "user-manager-api/internal/infrastructure/db/postgres/mode"
+	"user-manager-api/internal/infrastructure/db/postgres/notification"
+	"user-manager-api/internal/infrastructure/db/postgres/otp"
+	"user-manager-api/internal/infrastructure/db/postgres/outbox"
+	"user-manager-api/internal/infrastructure/db/postgres/projection"
+	"user-manager-api/internal/infrastructure/db/postgres/stats"
+	"user-manager-api/internal/infrastructure/db/postgres/usage"
 	"user-manager-api/internal/infrastructure/db/postgres/user"
 	"user-manager-api/internal/infrastructure/db/postgres/user_file"
+	"user-manager-api/internal/infrastructure/db/postgres/user_note"
+	"user-manager-api/internal/infrastructure/email"
+	"user-manager-api/internal/infrastructure/fieldcrypt"
+	"user-manager-api/internal/infrastructure/gcs"
 	"user-manager-api/internal/infrastructure/jwt"
+	"user-manager-api/internal/infrastructure/ldap"
+	"user-manager-api/internal/infrastructure/localfs"
 	"user-manager-api/internal/infrastructure/metrics"
 	"user-manager-api/internal/infrastructure/mq"
+	"user-manager-api/internal/infrastructure/ocr"
+	"user-manager-api/internal/infrastructure/paseto"
+	"user-manager-api/internal/infrastructure/password"
+	"user-manager-api/internal/infrastructure/resilience"
 	"user-manager-api/internal/infrastructure/s3"
+	"user-manager-api/internal/infrastructure/sms"
+	"user-manager-api/internal/infrastructure/thumbnail"
+	"user-manager-api/internal/infrastructure/webhook"
 	"user-manager-api/internal/interface/api/rest"
+	"user-manager-api/internal/interface/api/rest/api-specs/openapi/usermanagerapi"
 	"user-manager-api/internal/interface/api/rest/middleware"
+	"user-manager-api/migrations"
+	"user-manager-api/pkg/alert"
+	"user-manager-api/pkg/bulkhead"
+	"user-manager-api/pkg/openapi"
+	"user-manager-api/pkg/progress"
+	"user-manager-api/pkg/ratelimit"
 	"user-manager-api/pkg/rmqconsumer"
+	"user-manager-api/pkg/scheduler"
+	"user-manager-api/pkg/startup"
 )
 
 type App struct {
 	logger     *zap.Logger
 	cfg        config.Config
 	db         *pgxpool.Pool
-	s3         ports.S3Client
+	storage    ports.ObjectStorage
 	httpSrv    *http.Server
 	router     *gin.Engine
 	mCounter   *prometheus.CounterVec
+	mAudit     *prometheus.CounterVec
 	mq         ports.RabbitMQ
 	mqConsumer ports.RMQConsumer
+	outbox     ports.OutboxService
+	scheduler  *scheduler.Runner
+	thumbnails ports.ThumbnailService
+	texts      ports.TextExtractionService
+	usage      ports.UsageService
+	readOnly   ports.ReadOnlyService
+	piiCipher  *fieldcrypt.Cipher
+	dbTracer   *postgres.QueryTracer
+	// alerts - of the events failed to be published or consumed
+	alerts *alert.Throttle
+
+	// queryDB - db with DB_QUERY_TIMEOUT and retries for the requests and the
+	// consumers, the jobs use db: their whole table statements run longer
+	queryDB postgres.DB
+	// timedStorage - storage with STORAGE_TIMEOUT for the requests, storage
+	// itself is kept for the driver capabilities(ObjectReader, io.Closer) and the jobs
+	timedStorage ports.ObjectStorage
 }
 
 func NewApp(ctx context.Context) (*App, error) {
@@ -59,9 +127,15 @@ func NewApp(ctx context.Context) (*App, error) {
 		logger.Fatal("error loading .env file", zap.Error(err))
 	}
 	cfg := config.Load()
+	if err = cfg.Validate(); err != nil {
+		logger.Fatal("config error", zap.Error(err))
+	}
 
 	// metrics
 	mCounter := metrics.NewCounter()
+	mAudit := metrics.NewAuditEntries()
+	mBreaker := metrics.NewBreakerState()
+	mInFlight := metrics.NewBulkheadInFlight()
 
 	// router
 	switch cfg.App.Env {
@@ -73,62 +147,269 @@ func NewApp(ctx context.Context) (*App, error) {
 		gin.SetMode(gin.DebugMode)
 	}
 	r := gin.New()
-	r.Use(gin.Recovery())
-	r.Use(middleware.RequestLogGin(logger, mCounter))
+	// gin trusts every proxy by default: any client could spoof its IP via the headers
+	if err = r.SetTrustedProxies(cfg.App.TrustedProxies); err != nil {
+		logger.Fatal("trusted proxies error", zap.Error(err))
+	}
+	r.RemoteIPHeaders = cfg.App.RemoteIPHeaders
+	// the uploads above it are spooled to a temp file by the multipart parser
+	// and streamed from there to the storage, not kept in memory
+	r.MaxMultipartMemory = multipartMemory
+	// the middlewares(SERVICE_MIDDLEWARES) are added by InitControllers, gin
+	// adds them to the fallback handlers as well
+	rest.RegisterFallbackHandlers(r)
 
 	// httpServer
 	httpSrv := &http.Server{
 		Addr:    ":" + cfg.App.Port,
 		Handler: r,
 	}
+	if cfg.TLS.ClientCAFile != "" {
+		if httpSrv.TLSConfig, err = clientCATLSConfig(cfg.TLS.ClientCAFile); err != nil {
+			logger.Fatal("TLS client CA error", zap.Error(err))
+		}
+	}
 
 	// db
 	dbDsn, err := cfg.DBDSN()
 	if err != nil {
 		logger.Fatal("DB config error", zap.Error(err))
 	}
-	dbPool, err := postgres.New(ctx, logger, dbDsn)
+	// the dependencies are waited for in the order they are needed: Postgres,
+	// the storage, RabbitMQ(publisher, then consumer)
+	wait := startup.Settings{
+		MaxWait:   cfg.Startup.MaxWait,
+		BaseDelay: cfg.Startup.RetryBaseDelay,
+		MaxDelay:  cfg.Startup.RetryMaxDelay,
+	}
+	dbTracer := postgres.NewQueryTracer(cfg.DB.SlowQueryThreshold, logger, metrics.NewDBQueryDuration())
+	var dbPool *pgxpool.Pool
+	err = startup.Wait(ctx, logger, "postgres", wait, func(ctx context.Context) (err error) {
+		dbPool, err = postgres.New(ctx, logger, dbDsn, dbTracer)
+		return err
+	})
 	if err != nil {
 		logger.Fatal("failed to connect to database", zap.Error(err))
 	}
+	dbGuard := resilience.New(resilience.Settings{
+		Name:               "postgres",
+		BreakerMaxFailures: cfg.DB.BreakerMaxFailures,
+		BreakerOpenTimeout: cfg.DB.BreakerOpenTimeout,
+		MaxConcurrent:      cfg.DB.MaxConcurrent,
+		BulkheadWait:       cfg.DB.BulkheadWait,
+		IsFailure:          postgres.IsFailure,
+	}, logger, mCounter, mBreaker, mInFlight)
+	// every retry gets its own query timeout, an open breaker is not retried
+	queryDB := postgres.WithRetry(
+		postgres.WithGuard(postgres.WithQueryTimeout(dbPool, cfg.Timeouts.DBQuery), dbGuard),
+		logger,
+		cfg.DB,
+		mCounter,
+	)
+
+	// schema compatibility: during a rolling deploy the code may meet a schema
+	// it was not built for, its scans would read the wrong columns
+	var readOnlyForced string
+	if schemaReadOnly(ctx, logger, dbPool, cfg.DB.SchemaCheck) {
+		readOnlyForced = mode.ForcedSchema
+	}
+	if cfg.App.ReadOnly {
+		readOnlyForced = mode.ForcedConfig
+	}
+	checkIndexes(ctx, logger, dbPool, cfg.DB.SchemaCheck)
 
-	// s3
-	s3Client, err := s3.New(ctx, logger, cfg.S3)
+	// PII encryption
+	keyring, err := fieldcrypt.NewStaticKeyring(cfg.PII.Keys, cfg.PII.ActiveKey)
 	if err != nil {
-		logger.Fatal("failed to connect to S3", zap.Error(err))
+		logger.Fatal("PII encryption keys error", zap.Error(err))
+	}
+	// validated by cfg.Validate
+	indexKey, _ := base64.StdEncoding.DecodeString(cfg.PII.BlindIndexKey)
+	piiCipher := fieldcrypt.New(keyring, indexKey)
+
+	// object storage: the cloud drivers are wrapped by the same timeouts,
+	// retries and guard(resilience.Storage)
+	var storage ports.ObjectStorage
+	var storageGuard *resilience.Guard
+	newGuard := func(name string, r config.Resilience) *resilience.Guard {
+		return resilience.New(resilience.StorageSettings(name, r), logger, mCounter, mBreaker, mInFlight)
+	}
+	switch cfg.Storage.Driver {
+	// azure and gcs are refused by cfg.Validate until their SDK calls are in
+	case "fs":
+		storage, err = localfs.New(logger, cfg.Storage)
+		if err != nil {
+			logger.Fatal("failed to init fs storage", zap.Error(err))
+		}
+	case "azure":
+		var azClient *azblob.Client
+		err = startup.Wait(ctx, logger, "azure", wait, func(ctx context.Context) (err error) {
+			azClient, err = azblob.New(ctx, logger, cfg.Azure)
+			return err
+		})
+		if err != nil {
+			logger.Fatal("failed to connect to Azure Blob Storage", zap.Error(err))
+		}
+		storageGuard = newGuard("azure", cfg.Azure.Resilience)
+		storage = resilience.NewStorage("azure", azClient, logger, cfg.Azure.Resilience, mCounter, storageGuard)
+	case "gcs":
+		var gcsClient *gcs.Client
+		err = startup.Wait(ctx, logger, "gcs", wait, func(ctx context.Context) (err error) {
+			gcsClient, err = gcs.New(ctx, logger, cfg.GCS)
+			return err
+		})
+		if err != nil {
+			logger.Fatal("failed to connect to GCS", zap.Error(err))
+		}
+		storageGuard = newGuard("gcs", cfg.GCS.Resilience)
+		storage = resilience.NewStorage("gcs", gcsClient, logger, cfg.GCS.Resilience, mCounter, storageGuard)
+	case "s3":
+		var s3Client *s3.Client
+		err = startup.Wait(ctx, logger, "s3", wait, func(ctx context.Context) (err error) {
+			s3Client, err = s3.New(ctx, logger, cfg.S3)
+			return err
+		})
+		if err != nil {
+			logger.Fatal("failed to connect to S3", zap.Error(err))
+		}
+		storageGuard = newGuard("s3", cfg.S3.Resilience)
+		s3Storage := s3.NewResilient(s3Client, logger, cfg.S3, mCounter, storageGuard)
+		storage = s3Storage
+		if cfg.S3.DownloadMode == "proxy" {
+			storage = s3.NewProxy(s3Storage, cfg.S3.ProxyURL)
+		}
 	}
+	// uploads fail fast while the storage is down, the listings keep their stored URLs
+	timedStorage := services.NewTimeoutStorage(services.NewHealthStorage(storage, storageGuard), cfg.Timeouts.Storage)
+
+	// thumbnails
+	thumbnails := services.NewThumbnailService(
+		thumbnail.New(cfg.Thumbnails),
+		timedStorage,
+		user_file.NewRepository(queryDB, cfg.App.PageSize),
+		logger,
+		mCounter,
+		cfg.Thumbnails.QueueSize,
+		cfg.Thumbnails.MaxSourceSize,
+	)
+	// the text of the uploads for the files search
+	texts := services.NewTextExtractionService(
+		ocr.New(cfg.OCR),
+		user_file.NewRepository(queryDB, cfg.App.PageSize),
+		logger,
+		mCounter,
+		cfg.OCR.QueueSize,
+		cfg.OCR.MaxTextSize,
+		cfg.OCR.MaxSourceSize,
+	)
+
+	// usage metrics
+	usageService := services.NewUsageService(
+		usage.NewRepository(queryDB),
+		logger,
+		mCounter,
+		metrics.NewUsageRequests(),
+		metrics.NewUsageDuration(),
+		services.UsageSettings{
+			Orgs:          cfg.Usage.Orgs,
+			FlushInterval: cfg.Usage.FlushInterval,
+			QueueSize:     cfg.Usage.QueueSize,
+		},
+	)
+
+	// read-only mode: the login and the toggle itself stay available
+	readOnlyService := services.NewReadOnlyService(
+		modeRepo.NewRepository(queryDB),
+		services.NewAuditService(audit.NewRepository(queryDB, cfg.App.PageSize), logger, mCounter, mAudit),
+		logger,
+		mCounter,
+		services.ReadOnlySettings{Forced: readOnlyForced, PollInterval: cfg.App.ReadOnlyPollInterval},
+	)
 
 	// rabbitMQ
 	rabbitDsn, err := cfg.AMQPDSN()
 	if err != nil {
 		logger.Fatal("RabbitMQ config error", zap.Error(err))
 	}
-	rbMQ := mq.New(cfg.MQ, logger)
-	if err = rbMQ.Connect(ctx, rabbitDsn); err != nil {
+	rbMQ := mq.New(cfg.MQ, logger, mCounter)
+	alerts := newAlerts(cfg.Alerts, logger, mCounter)
+	rbMQ.SetAlerter(alerts)
+	mqGuard := resilience.New(resilience.Settings{
+		Name:               "rabbitmq",
+		BreakerMaxFailures: cfg.MQ.BreakerMaxFailures,
+		BreakerOpenTimeout: cfg.MQ.BreakerOpenTimeout,
+		MaxConcurrent:      cfg.MQ.MaxConcurrent,
+		BulkheadWait:       cfg.MQ.BulkheadWait,
+	}, logger, mCounter, mBreaker, mInFlight)
+	rbMQ.SetGuard(mqGuard)
+	// the events the broker does not take are kept in the outbox, so the
+	// mutations succeed while it is down
+	outboxService := services.NewOutboxService(rbMQ, mqGuard, outbox.NewRepository(queryDB, piiCipher), logger, mCounter)
+	rbMQ.SetDropHandler(func(e mq.Event) { _ = outboxService.Keep(context.Background(), e) })
+	if mgmtURL := cfg.MQManagementURL(); mgmtURL != "" {
+		rbMQ.SetManagement(mq.NewManagement(mgmtURL, cfg.MQ.User, cfg.MQ.Password, cfg.MQ.Vhost, cfg.MQ.MgmtTimeout))
+	}
+	if cfg.MQ.SchemaValidation {
+		eventSchema, err := mq.EventSchema()
+		if err != nil {
+			logger.Fatal("failed to load the event schema", zap.Error(err))
+		}
+		rbMQ.SetSchema(eventSchema)
+	}
+	err = startup.Wait(ctx, logger, "rabbitmq", wait, func(ctx context.Context) error {
+		return rbMQ.Connect(ctx, rabbitDsn)
+	})
+	if err != nil {
 		logger.Fatal("failed to connect to rabbitMQ", zap.Error(err))
 	}
+	repairTopology := verifyTopology(ctx, logger, rbMQ, cfg.MQ.TopologyCheck)
 	if err = rbMQ.Init(); err != nil {
 		logger.Fatal("failed init rabbitMQ", zap.Error(err))
 	}
+	if repairTopology {
+		if _, err = rbMQ.RepairTopology(ctx); err != nil {
+			logger.Fatal("failed to repair rabbitMQ topology", zap.Error(err))
+		}
+	}
 	//rmqConsumer
 	rmqConsumer := rmqconsumer.New(cfg.MQ, logger, rbMQ.GetConn())
-	if err = rmqConsumer.Connect(rabbitDsn); err != nil {
+	err = startup.Wait(ctx, logger, "rabbitmq consumer", wait, func(context.Context) error {
+		return rmqConsumer.Connect(rabbitDsn)
+	})
+	if err != nil {
 		logger.Fatal("failed to connect rabbitMQ consumer", zap.Error(err))
 	}
-	if err = rmqConsumer.Init(); err != nil {
-		logger.Fatal("failed to init rabbitMQ consumer", zap.Error(err))
+	rmqConsumer.SetAlerter(alerts)
+	if cfg.MQ.LeaderElection {
+		rmqConsumer.SetLeaderElection(postgres.NewAdvisoryLocker(dbPool))
 	}
 
+	// jobs: one instance at a time when the service is scaled out
+	jobsRunner := scheduler.New(logger)
+	jobsRunner.SetLocker(postgres.NewAdvisoryLocker(dbPool))
+
 	return &App{
-		logger:     logger,
-		cfg:        cfg,
-		db:         dbPool,
-		s3:         s3Client,
-		httpSrv:    httpSrv,
-		router:     r,
-		mCounter:   mCounter,
-		mq:         rbMQ,
-		mqConsumer: rmqConsumer,
+		logger:       logger,
+		cfg:          cfg,
+		db:           dbPool,
+		storage:      storage,
+		httpSrv:      httpSrv,
+		router:       r,
+		mCounter:     mCounter,
+		mAudit:       mAudit,
+		mq:           services.NewOutboxPublisher(rbMQ, mqGuard, outboxService),
+		outbox:       outboxService,
+		mqConsumer:   rmqConsumer,
+		scheduler:    jobsRunner,
+		thumbnails:   thumbnails,
+		texts:        texts,
+		usage:        usageService,
+		readOnly:     readOnlyService,
+		piiCipher:    piiCipher,
+		dbTracer:     dbTracer,
+		alerts:       alerts,
+		queryDB:      queryDB,
+		timedStorage: timedStorage,
 	}, nil
 }
 
@@ -136,6 +417,9 @@ func (a *App) Close() {
 	if a.db != nil {
 		a.db.Close()
 	}
+	if c, ok := a.storage.(io.Closer); ok {
+		_ = c.Close()
+	}
 	if a.mq.GetConn() != nil {
 		a.mq.GetConn().Close()
 	}
@@ -151,6 +435,11 @@ func (a *App) Run(ctx context.Context) error {
 	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM, syscall.SIGUSR1)
 	defer stop()
 
+	// the consumer queue is declared here and never for one-off CLI commands
+	if err := a.mqConsumer.Init(); err != nil {
+		return fmt.Errorf("failed to init rabbitMQ consumer: %w", err)
+	}
+
 	// "errgroup" instead of "WaitGroup" because:
 	// - allows return an error from gorutine
 	// - group errors from multiple gorutines into one
@@ -159,7 +448,11 @@ func (a *App) Run(ctx context.Context) error {
 	g, ctx := errgroup.WithContext(ctx)
 	g.Go(func() error {
 		a.logger.Info("starting "+a.cfg.App.Name, zap.String("addr", a.cfg.App.Host+":"+a.cfg.App.Port))
-		if err := a.httpSrv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		serve := a.httpSrv.ListenAndServe
+		if a.cfg.TLS.CertFile != "" {
+			serve = func() error { return a.httpSrv.ListenAndServeTLS(a.cfg.TLS.CertFile, a.cfg.TLS.KeyFile) }
+		}
+		if err := serve(); err != nil && !errors.Is(err, http.ErrServerClosed) {
 			return fmt.Errorf("http server "+a.cfg.App.Name+" error: %w", err)
 		}
 
@@ -172,7 +465,30 @@ func (a *App) Run(ctx context.Context) error {
 	})
 
 	g.Go(func() error {
-		a.mqConsumer.DeliveryWorker(ctx)
+		return a.mqConsumer.DeliveryWorker(ctx)
+	})
+
+	g.Go(func() error {
+		return a.scheduler.Run(ctx)
+	})
+
+	g.Go(func() error {
+		a.thumbnails.Worker(ctx)
+		return nil
+	})
+
+	g.Go(func() error {
+		a.texts.Worker(ctx)
+		return nil
+	})
+
+	g.Go(func() error {
+		a.usage.Worker(ctx)
+		return nil
+	})
+
+	g.Go(func() error {
+		a.readOnly.Worker(ctx)
 		return nil
 	})
 
@@ -188,7 +504,10 @@ func (a *App) Run(ctx context.Context) error {
 		}
 	}
 
-	if err := g.Wait(); err != nil {
+	err := g.Wait()
+	// the alerts of the shutdown(the events dropped) are delivered before exiting
+	a.alerts.Wait()
+	if err != nil {
 		a.logger.Error(a.cfg.App.Name+" returning an error", zap.Error(err))
 		return err
 	}
@@ -200,23 +519,761 @@ func (a *App) Run(ctx context.Context) error {
 
 func (a *App) InitControllers() {
 	// repos
-	userRepo := user.NewRepository(a.db)
-	userFileRepo := user_file.NewRepository(a.db)
+	userRepo := user.NewRepository(a.queryDB, a.cfg.App.PageSize, a.piiCipher)
+	userFileRepo := user_file.NewRepository(a.queryDB, a.cfg.App.PageSize)
+	userNoteRepo := user_note.NewRepository(a.queryDB, a.cfg.App.PageSize)
+	auditRepo := audit.NewRepository(a.queryDB, a.cfg.App.PageSize)
+	otpRepo := otp.NewRepository(a.queryDB, a.piiCipher)
+	statsRepo := stats.NewRepository(a.queryDB)
+	directoryRepo := directory.NewRepository(a.queryDB)
+	notificationRepo := notification.NewRepository(a.queryDB)
+	usageRepo := usage.NewRepository(a.queryDB)
+	deviceRepo := device.NewRepository(a.queryDB)
 
 	// services
-	jwtService := jwt.New(a.cfg.App.JWTSecret)
-	authService := services.NewAuthService(jwtService)
-	userService := services.NewUserService(userRepo, userFileRepo, a.mq, a.mCounter)
-	userFileService := services.NewUserFileService(a.s3, userFileRepo, userRepo, a.mCounter)
+	tokenService, err := newTokenService(a.cfg.App)
+	if err != nil {
+		a.logger.Fatal("token service error", zap.Error(err))
+	}
+	hasher := password.New(a.cfg.Password)
+	auditService := services.NewAuditService(auditRepo, a.logger, a.mCounter, a.mAudit)
+	suspensionService := services.NewSuspensionService(
+		userRepo,
+		auditService,
+		a.mq,
+		a.logger,
+		a.mCounter,
+		services.SuspensionSettings{
+			InactiveDays: a.cfg.Suspension.InactiveDays,
+			WarnDays:     a.cfg.Suspension.WarnDays,
+			GraceDays:    a.cfg.Suspension.GraceDays,
+		},
+	)
+	authService := services.NewAuthService(
+		tokenService,
+		hasher,
+		userRepo,
+		deviceRepo,
+		suspensionService,
+		a.mq,
+		a.logger,
+		a.mCounter,
+	)
+	impersonationService := services.NewImpersonationService(
+		tokenService,
+		userRepo,
+		auditService,
+		a.mCounter,
+		a.cfg.App.ImpersonationTTL,
+	)
+	credentialService := services.NewCredentialService(
+		tokenService,
+		hasher,
+		userRepo,
+		auditService,
+		suspensionService,
+		a.mq,
+		a.mCounter,
+	)
+	deviceService := services.NewDeviceService(deviceRepo, a.mCounter)
+	tokenService.SetRevocationCheck(token.AnyOf(credentialService.IsTokenRevoked, deviceService.IsTokenRevoked))
+	tokenService.SetRefreshWindow(a.cfg.App.TokenRefreshWindow)
+	timezones := newTimezones(a.cfg.Timezones)
+	userDeletionService := services.NewUserDeletionService(
+		a.timedStorage,
+		deletion.NewRepository(a.queryDB),
+		userRepo,
+		userFileRepo,
+		a.mq,
+		a.logger,
+		a.mCounter,
+	)
+	userCommands := services.NewUserCommands(
+		userRepo,
+		userDeletionService,
+		usageRepo,
+		a.mq,
+		a.mCounter,
+		a.cfg.App.EmailChangeTTL,
+		timezones,
+		newAgePolicy(a.cfg.AgePolicy, timezones),
+	)
+	// the reads have their own repositories: a replica or a cache goes here
+	userQueries := services.NewUserQueries(userRepo, userFileRepo, timezones)
+	uploadPolicyService := services.NewUploadPolicyService(userFileRepo, userRepo, a.cfg.App.UploadPolicyCacheTTL, a.mCounter)
+	userFileService := services.NewUserFileService(
+		a.timedStorage,
+		a.thumbnails,
+		a.texts,
+		userFileRepo,
+		userRepo,
+		uploadPolicyService,
+		a.mq,
+		a.mCounter,
+		a.cfg.App.MaxFileOpsPerUser,
+	)
+	adminFileService := services.NewAdminFileService(userFileRepo)
+	fileRetentionService := services.NewFileRetentionService(
+		a.timedStorage,
+		userFileRepo,
+		auditService,
+		a.mq,
+		a.logger,
+		a.mCounter,
+	)
+	statsService := services.NewStatsService(statsRepo, a.mCounter)
+	billingService := services.NewBillingService(usageRepo, a.mCounter)
+	seatService := services.NewSeatService(usageRepo, a.mCounter)
+	userNoteService := services.NewUserNoteService(userNoteRepo, userRepo, a.mCounter)
+	userHistoryService := services.NewUserHistoryService(userRepo)
+	otpService := services.NewOTPService(
+		otpRepo,
+		userRepo,
+		suspensionService,
+		newSMSSender(a.cfg.OTP, a.logger),
+		tokenService,
+		a.mCounter,
+		services.OTPSettings{
+			TTL:         a.cfg.OTP.TTL,
+			CodeLength:  a.cfg.OTP.CodeLength,
+			MaxAttempts: a.cfg.OTP.MaxAttempts,
+			Cooldown:    a.cfg.OTP.Cooldown,
+		},
+	)
+
+	roleService := services.NewRoleService(userRepo, auditService, a.cfg.App.RoleCacheTTL, a.mCounter)
+	tokenService.SetRoleLookup(roleService.CurrentRole)
+	duplicateService := services.NewDuplicateService(userRepo, auditService, a.mq, a.mCounter)
+	legalHoldService := services.NewLegalHoldService(userRepo, auditService, a.logger, a.mCounter)
+	userLookupService := services.NewUserLookupService(userRepo, auditService, a.mCounter)
+	projectionService := services.NewProjectionService(projection.NewRepository(a.queryDB, a.db), a.mCounter)
+	directorySyncService := services.NewDirectorySyncService(
+		ldap.New(a.logger, a.cfg.LDAP),
+		userCommands,
+		userQueries,
+		directoryRepo,
+		a.logger,
+		a.mCounter,
+	)
+	invitationService := services.NewInvitationService(
+		hasher,
+		userRepo,
+		usageRepo,
+		auditService,
+		a.mq,
+		a.mCounter,
+		services.InvitationSettings{
+			TTL:       a.cfg.App.InvitationTTL,
+			SignupURL: a.cfg.App.InvitationURL,
+		},
+		newAgePolicy(a.cfg.AgePolicy, timezones),
+	)
+
+	// must be registered before the routes to cover them, the impersonation
+	// audit is not configurable
+	a.useMiddlewares(tokenService)
+	a.router.Use(middleware.ImpersonationAudit(auditService, a.logger))
 
 	// controllers
-	rest.NewAuthController(a.router, a.logger, userService, authService)
-	rest.NewUserController(a.router, userService, a.logger, jwtService)
-	rest.NewUserFileController(a.router, userFileService, a.logger, jwtService)
+	rest.NewAuthController(a.router, a.logger, userCommands, userQueries, authService, credentialService, a.cfg.Anomaly.GeoHeader)
+	rest.NewOTPController(a.router, a.logger, otpService, ratelimit.New(a.cfg.OTP.IPRequestsPerMinute, time.Minute))
+	rest.NewAdminController(a.router, a.logger, impersonationService, credentialService, tokenService)
+	rest.NewAdminRoleController(a.router, roleService, a.logger, tokenService)
+	rest.NewAdminDuplicateController(a.router, duplicateService, a.logger, tokenService)
+	rest.NewAdminLegalHoldController(a.router, legalHoldService, a.logger, tokenService)
+	rest.NewAdminSuspensionController(a.router, suspensionService, a.logger, tokenService)
+	rest.NewAdminLookupController(a.router, userLookupService, a.logger, tokenService)
+	rest.NewAdminAuditController(a.router, auditService, a.logger, tokenService)
+	rest.NewAdminProjectionController(a.router, projectionService, a.logger, tokenService)
+	rest.NewAdminDirectoryController(a.router, directorySyncService, a.logger, tokenService)
+	rest.NewUserController(a.router, userCommands, userQueries, a.logger, tokenService)
+	uploads := progress.New(rest.UploadProgressTTL)
+	rest.NewUserFileController(a.router, userFileService, a.logger, tokenService, a.cfg.App.MaxUploadSize, uploads)
+	rest.NewUploadController(a.router, uploads, a.logger, tokenService)
+	rest.NewAdminFileController(a.router, adminFileService, a.logger, tokenService)
+	rest.NewAdminRetentionController(a.router, fileRetentionService, a.logger, tokenService)
+	rest.NewAdminStatsController(a.router, statsService, a.logger, tokenService)
+	rest.NewAdminUsageController(a.router, a.usage, billingService, a.logger, tokenService)
+	rest.NewAdminReadOnlyController(a.router, a.readOnly, a.logger, tokenService)
+	rest.NewAdminMQController(a.router, a.mq, a.logger, tokenService)
+	rest.NewAdminDBController(a.router, a.dbTracer, a.logger, tokenService)
+	rest.NewAdminDeadLetterController(
+		a.router,
+		services.NewDeadLetterService(a.mq, auditService, a.logger, a.mCounter),
+		a.logger,
+		tokenService,
+	)
+	rest.NewAdminSeatController(a.router, seatService, a.logger, tokenService)
+	rest.NewAdminUploadPolicyController(a.router, uploadPolicyService, a.logger, tokenService)
+	spec, err := openapi.Load(usermanagerapi.Spec)
+	if err != nil {
+		a.logger.Fatal("failed to load the OpenAPI spec", zap.Error(err))
+	}
+	rest.NewAdminCollectionController(a.router, spec, a.logger, tokenService)
+	rest.NewUserNoteController(a.router, userNoteService, a.logger, tokenService)
+	rest.NewUserHistoryController(a.router, userHistoryService, a.logger, tokenService)
+	rest.NewDeviceController(a.router, deviceService, a.logger, tokenService)
+	rest.NewInvitationController(a.router, invitationService, a.logger, tokenService)
+	rest.NewNotificationController(
+		a.router,
+		services.NewNotificationPreferenceService(notificationRepo, a.mCounter),
+		a.logger,
+		tokenService,
+	)
+	if a.cfg.Hooks.HRSecret != "" {
+		hrHookService := services.NewHRHookService(userCommands, userQueries, a.mCounter)
+		rest.NewHookController(a.router, hrHookService, a.logger, a.cfg.Hooks.HRSecret, a.cfg.Hooks.MaxSkew)
+	}
+	if reader, ok := a.storage.(ports.ObjectReader); ok {
+		rest.NewFileRawController(a.router, reader, userFileService, a.logger, tokenService)
+	}
 
 	// ops
 	a.router.GET(rest.RouteHealth, func(c *gin.Context) { c.Status(http.StatusOK) })
 	a.router.GET(rest.RouteMetrics, gin.WrapH(promhttp.Handler()))
 }
 
+// InitConsumers registers the handlers of the consumed events
+func (a *App) InitConsumers() {
+	// whatever RABBITMQ_EVENT_ENCODINGS are: the queue may hold the events of both
+	a.mqConsumer.Decode(mq.ContentTypeProtobuf, mq.ProtoToJSON)
+	if a.cfg.MQ.DedupTTL > 0 {
+		a.mqConsumer.SetDeduplicator(services.NewEventDedupService(
+			event.NewRepository(a.queryDB),
+			a.mCounter,
+			services.EventDedupSettings{TTL: a.cfg.MQ.DedupTTL},
+		))
+	}
+
+	statsService := services.NewStatsService(stats.NewRepository(a.queryDB), a.mCounter)
+	applyStats := eventHandler(statsService.ApplyEvent)
+	for _, rk := range []string{http.MethodPost, http.MethodDelete, mq.EventUserFilesChanged} {
+		a.mqConsumer.Handle(rk, applyStats)
+	}
+
+	// every event is stored, the appends wait for a rebuild instead of timing out
+	projectionService := services.NewProjectionService(
+		projection.NewRepository(postgres.WithRetry(a.db, a.logger, a.cfg.DB, a.mCounter), a.db),
+		a.mCounter,
+	)
+	for _, rk := range mq.RoutingKeys() {
+		a.mqConsumer.Handle(rk, eventHandler(projectionService.Record))
+	}
+
+	anomalyService := services.NewAnomalyService(
+		loginRepo.NewRepository(a.queryDB, a.cfg.Anomaly.HistorySize),
+		user.NewRepository(a.queryDB, a.cfg.App.PageSize, a.piiCipher),
+		services.NewAuditService(audit.NewRepository(a.queryDB, a.cfg.App.PageSize), a.logger, a.mCounter, a.mAudit),
+		webhook.NewInternal(a.cfg.Notifications.WebhookTimeout),
+		a.logger,
+		a.mCounter,
+		services.AnomalySettings{
+			Rules: login.Rules{
+				TravelWindow: a.cfg.Anomaly.TravelWindow,
+				NewDevice:    a.cfg.Anomaly.NewDevice,
+			},
+			ForceReauth: a.cfg.Anomaly.ForceReauth,
+			AlertURL:    a.cfg.Anomaly.AlertWebhookURL,
+		},
+	)
+	a.mqConsumer.Handle(mq.EventLoginSucceeded, eventHandler(anomalyService.CheckLogin))
+
+	notificationService := services.NewNotificationService(
+		email.New(a.logger, a.cfg.Email, a.mCounter),
+		newSMSSender(a.cfg.OTP, a.logger),
+		webhook.New(a.cfg.Notifications.WebhookTimeout),
+		notification.NewRepository(a.queryDB),
+		user.NewRepository(a.queryDB, a.cfg.App.PageSize, a.piiCipher),
+		a.logger,
+		a.mCounter,
+		services.NotificationSettings{LoginURL: a.cfg.Email.LoginURL},
+	)
+	notify := eventHandler(notificationService.Notify)
+	for _, rk := range []string{
+		http.MethodPost,
+		http.MethodPut,
+		mq.EventPasswordResetForced,
+		mq.EventInvitationCreated,
+		mq.EventInactivityWarning,
+		mq.EventUserSuspended,
+	} {
+		a.mqConsumer.Handle(rk, notify)
+	}
+}
+
+// eventHandler - decodes the message body into the event for apply
+func eventHandler(apply func(ctx context.Context, e mq.Event) error) rmqconsumer.Handler {
+	return func(ctx context.Context, body []byte) error {
+		var e mq.Event
+		if err := json.Unmarshal(body, &e); err != nil {
+			return err
+		}
+		return apply(ctx, e)
+	}
+}
+
+func (a *App) InitJobs() {
+	// repos
+	db := postgres.WithRetry(a.db, a.logger, a.cfg.DB, a.mCounter)
+	userRepo := user.NewRepository(db, a.cfg.App.PageSize, a.piiCipher)
+	userFileRepo := user_file.NewRepository(db, a.cfg.App.PageSize)
+	auditRepo := audit.NewRepository(db, a.cfg.App.PageSize)
+	statsRepo := stats.NewRepository(db)
+	directoryRepo := directory.NewRepository(db)
+	notificationRepo := notification.NewRepository(db)
+	usageRepo := usage.NewRepository(db)
+	backupRepo := backup.NewRepository(db)
+	eventRepo := event.NewRepository(db)
+
+	// backups go to S3 whatever the files storage is
+	backupS3, err := s3.New(context.Background(), a.logger, a.cfg.S3)
+	if err != nil {
+		a.logger.Fatal("failed to connect to S3", zap.Error(err))
+	}
+	// validated by cfg.Validate, empty if not set
+	backupKey, _ := base64.StdEncoding.DecodeString(a.cfg.Backup.EncryptionKey)
+
+	// services
+	fileReconcileService := services.NewFileReconcileService(a.storage, userFileRepo, a.mCounter)
+	piiService := services.NewPIIService(userRepo, a.mCounter)
+	statsService := services.NewStatsService(statsRepo, a.mCounter)
+	billingService := services.NewBillingService(usageRepo, a.mCounter)
+	backupService := services.NewBackupService(
+		backupRepo,
+		backupS3.WithBucket(a.cfg.Backup.Bucket),
+		a.logger,
+		a.mCounter,
+		services.BackupSettings{EncryptionKey: backupKey},
+	)
+	eventDedupService := services.NewEventDedupService(
+		eventRepo,
+		a.mCounter,
+		services.EventDedupSettings{TTL: a.cfg.MQ.DedupTTL},
+	)
+	auditService := services.NewAuditService(auditRepo, a.logger, a.mCounter, a.mAudit)
+	auditArchiveService := services.NewAuditArchiveService(
+		auditRepo,
+		backupS3.WithBucket(a.cfg.Retention.AuditBucket),
+		a.logger,
+		a.mCounter,
+		services.AuditArchiveSettings{Retention: time.Duration(a.cfg.Retention.AuditDays) * 24 * time.Hour},
+	)
+	columns := make([]domain.PIIColumn, len(a.cfg.Retention.Columns))
+	for i, col := range a.cfg.Retention.Columns {
+		columns[i] = domain.PIIColumn(col)
+	}
+	retentionService := services.NewRetentionService(
+		userRepo,
+		auditService,
+		a.mCounter,
+		services.RetentionSettings{InactiveMonths: a.cfg.Retention.InactiveMonths, Columns: columns},
+	)
+	suspensionService := services.NewSuspensionService(
+		userRepo,
+		auditService,
+		a.mq,
+		a.logger,
+		a.mCounter,
+		services.SuspensionSettings{
+			InactiveDays: a.cfg.Suspension.InactiveDays,
+			WarnDays:     a.cfg.Suspension.WarnDays,
+			GraceDays:    a.cfg.Suspension.GraceDays,
+		},
+	)
+	fileRetentionService := services.NewFileRetentionService(
+		a.storage,
+		userFileRepo,
+		auditService,
+		a.mq,
+		a.logger,
+		a.mCounter,
+	)
+
+	timezones := newTimezones(a.cfg.Timezones)
+	userDeletionService := services.NewUserDeletionService(
+		a.storage,
+		deletion.NewRepository(db),
+		userRepo,
+		userFileRepo,
+		a.mq,
+		a.logger,
+		a.mCounter,
+	)
+	userCommands := services.NewUserCommands(
+		userRepo,
+		userDeletionService,
+		usageRepo,
+		a.mq,
+		a.mCounter,
+		a.cfg.App.EmailChangeTTL,
+		timezones,
+		newAgePolicy(a.cfg.AgePolicy, timezones),
+	)
+	userQueries := services.NewUserQueries(userRepo, userFileRepo, timezones)
+	directorySyncService := services.NewDirectorySyncService(
+		ldap.New(a.logger, a.cfg.LDAP),
+		userCommands,
+		userQueries,
+		directoryRepo,
+		a.logger,
+		a.mCounter,
+	)
+	anomalyService := services.NewAnomalyService(
+		loginRepo.NewRepository(a.queryDB, a.cfg.Anomaly.HistorySize),
+		user.NewRepository(a.queryDB, a.cfg.App.PageSize, a.piiCipher),
+		services.NewAuditService(audit.NewRepository(a.queryDB, a.cfg.App.PageSize), a.logger, a.mCounter, a.mAudit),
+		webhook.NewInternal(a.cfg.Notifications.WebhookTimeout),
+		a.logger,
+		a.mCounter,
+		services.AnomalySettings{
+			Rules: login.Rules{
+				TravelWindow: a.cfg.Anomaly.TravelWindow,
+				NewDevice:    a.cfg.Anomaly.NewDevice,
+			},
+			ForceReauth: a.cfg.Anomaly.ForceReauth,
+			AlertURL:    a.cfg.Anomaly.AlertWebhookURL,
+		},
+	)
+	a.mqConsumer.Handle(mq.EventLoginSucceeded, eventHandler(anomalyService.CheckLogin))
+
+	notificationService := services.NewNotificationService(
+		email.New(a.logger, a.cfg.Email, a.mCounter),
+		newSMSSender(a.cfg.OTP, a.logger),
+		webhook.New(a.cfg.Notifications.WebhookTimeout),
+		notificationRepo,
+		userRepo,
+		a.logger,
+		a.mCounter,
+		services.NotificationSettings{LoginURL: a.cfg.Email.LoginURL},
+	)
+
+	// jobs
+	a.scheduler.Register(
+		jobs.NewReconcileFiles(fileReconcileService, a.logger, a.cfg.Jobs.ReconcileFilesDelete),
+		a.cfg.Jobs.ReconcileFilesInterval,
+	)
+	a.scheduler.Register(jobs.NewReencryptPII(piiService, a.logger), a.cfg.Jobs.ReencryptPIIInterval)
+	a.scheduler.Register(jobs.NewRedactInactive(retentionService, a.logger), a.cfg.Jobs.RedactInactiveInterval)
+	a.scheduler.Register(jobs.NewRebuildStats(statsService, a.logger), a.cfg.Jobs.RebuildStatsInterval)
+	a.scheduler.Register(jobs.NewSyncDirectory(directorySyncService, a.logger), a.cfg.Jobs.SyncDirectoryInterval)
+	a.scheduler.Register(jobs.NewSendDigests(notificationService, a.logger), a.cfg.Jobs.SendDigestsInterval)
+	a.scheduler.Register(jobs.NewEmitBirthdays(userCommands, a.logger), a.cfg.Jobs.EmitBirthdaysInterval)
+	a.scheduler.Register(jobs.NewAggregateUsage(billingService, a.logger), a.cfg.Jobs.AggregateUsageInterval)
+	a.scheduler.Register(jobs.NewBackup(backupService, a.logger), a.cfg.Jobs.BackupInterval)
+	a.scheduler.Register(
+		jobs.NewPurgeProcessedEvents(eventDedupService, a.logger),
+		a.cfg.Jobs.PurgeProcessedEventsInterval,
+	)
+	a.scheduler.Register(
+		jobs.NewPurgeExpiredFiles(fileRetentionService, a.logger),
+		a.cfg.Jobs.PurgeExpiredFilesInterval,
+	)
+	a.scheduler.Register(
+		jobs.NewResumeDeletions(userDeletionService, a.logger),
+		a.cfg.Jobs.ResumeDeletionsInterval,
+	)
+	a.scheduler.Register(jobs.NewRelayOutbox(a.outbox, a.logger), a.cfg.Jobs.RelayOutboxInterval)
+	a.scheduler.Register(jobs.NewArchiveAuditLog(auditArchiveService, a.logger), a.cfg.Jobs.ArchiveAuditInterval)
+	a.scheduler.Register(jobs.NewSuspendInactive(suspensionService, a.logger), a.cfg.Jobs.SuspendInactiveInterval)
+	// CLI only
+	a.scheduler.Register(jobs.NewRestore(backupService, a.logger, a.cfg.Backup.RestoreObject), 0)
+	a.scheduler.Register(jobs.NewRebuildProjections(
+		services.NewProjectionService(projection.NewRepository(db, a.db), a.mCounter),
+		a.logger,
+	), 0)
+}
+
+// RunJob - one-off run of a registered job(CLI subcommand)
+func (a *App) RunJob(ctx context.Context, name string) error {
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	return a.scheduler.RunOnce(ctx, name)
+}
+
+// WriteCollection - the example requests of the routes of InitControllers(CLI subcommand)
+func (a *App) WriteCollection(w io.Writer, format, host string) error {
+	spec, err := openapi.Load(usermanagerapi.Spec)
+	if err != nil {
+		return err
+	}
+	b, _, err := rest.MarshalCollection(format, host, rest.CollectionRequests(a.router.Routes(), spec))
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(b)
+
+	return err
+}
+
 func (a *App) Logger() *zap.Logger { return a.logger }
+
+// clientCATLSConfig - the client certificates are optional: the callers without
+// one authenticate by a bearer token
+func clientCATLSConfig(caFile string) (*tls.Config, error) {
+	caPEM, err := os.ReadFile(caFile)
+	if err != nil {
+		return nil, err
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(caPEM) {
+		return nil, fmt.Errorf("no certificates in %s", caFile)
+	}
+
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		ClientCAs:  pool,
+		ClientAuth: tls.VerifyClientCertIfGiven,
+	}, nil
+}
+
+// revocableTokenService - the revocation check is set once the credential service
+// exists, the role lookup once the role service does
+type revocableTokenService interface {
+	ports.TokenService
+	SetRevocationCheck(check token.RevocationCheck)
+	SetRoleLookup(lookup token.RoleLookup)
+	SetRefreshWindow(window time.Duration)
+}
+
+// useMiddlewares adds the global middlewares of SERVICE_MIDDLEWARES in their
+// order, an environment gets its own pipeline by the config only
+func (a *App) useMiddlewares(tokenService ports.TokenService) {
+	// the contract check of the non-production environments(see cfg.Validate)
+	var openAPI gin.HandlerFunc
+	if a.cfg.App.OpenAPIValidation {
+		spec, err := openapi.Load(usermanagerapi.Spec)
+		if err != nil {
+			a.logger.Fatal("failed to load the OpenAPI spec", zap.Error(err))
+		}
+		openAPI = middleware.OpenAPI(spec, a.logger, a.mCounter)
+	}
+	var clientCert gin.HandlerFunc
+	if a.cfg.TLS.ClientCAFile != "" {
+		clientCert = middleware.ClientCert(a.cfg.TLS.ClientIdentities)
+	}
+
+	// load shedding
+	var admission *bulkhead.Bulkhead
+	if a.cfg.App.MaxInFlight > 0 {
+		admission = bulkhead.NewQueued(a.cfg.App.MaxInFlight, a.cfg.App.MaxQueued, a.cfg.App.QueueWait)
+		metrics.NewHTTPQueued(admission.Queued)
+	}
+
+	pipeline, err := middleware.Pipeline(a.cfg.App.Middlewares, map[string]gin.HandlerFunc{
+		"recovery":    gin.Recovery(),
+		"request_id":  middleware.RequestID(),
+		"compression": middleware.Compress(),
+		"logging":     middleware.RequestLogGin(a.logger, a.mCounter, a.cfg.App.MaxLogBodySize),
+		"error_codes": middleware.ErrorCodes(metrics.NewHTTPErrors()),
+		"cors":        middleware.CORS(a.cfg.App.CORSAllowedOrigins),
+		"rate_limit":  middleware.RateLimitByIP(ratelimit.New(a.cfg.App.RateLimitPerMinute, time.Minute)),
+		"admission": middleware.Admission(
+			admission,
+			a.cfg.App.ShedRetryAfter,
+			metrics.NewHTTPInFlight(),
+			a.mCounter,
+			rest.RouteHealth, rest.RouteMetrics, rest.RouteUploadProgress,
+		),
+		"timeout":     middleware.RequestTimeout(a.cfg.Timeouts.Handler, a.cfg.Timeouts.Upload, routeTimeouts(a.cfg.Timeouts.Routes), a.logger, a.mCounter),
+		"openapi":     openAPI,
+		"client_cert": clientCert,
+		// the routes still require their own, the middlewares after it see the caller
+		"auth":  middleware.OptionalAuthMiddleware(tokenService),
+		"usage": middleware.Usage(a.usage),
+		// the login and the toggle itself stay available
+		"read_only": middleware.ReadOnly(a.readOnly, a.mCounter, rest.RouteLogin, rest.RouteOTPVerify, rest.RouteAdminReadOnly, rest.RouteUsersValidate),
+	})
+	if err != nil {
+		a.logger.Fatal("middlewares config error", zap.Error(err))
+	}
+	a.router.Use(pipeline...)
+}
+
+// newTokenService - the keys are validated by cfg.Validate
+func newTokenService(cfg config.APP) (revocableTokenService, error) {
+	key, _ := base64.StdEncoding.DecodeString(cfg.PasetoKey)
+	switch cfg.TokenFormat {
+	case "paseto-local":
+		return paseto.NewLocal(key)
+	case "paseto-public":
+		return paseto.NewPublic(key)
+	default:
+		return jwt.New(cfg.JWTSecret), nil
+	}
+}
+
+// newAlerts - the notifier of ALERT_PROVIDER, deduplicated and rate limited;
+// the settings are validated by cfg.Validate
+func newAlerts(cfg config.Alerts, logger *zap.Logger, mCounter *prometheus.CounterVec) *alert.Throttle {
+	var n alert.Notifier
+	switch cfg.Provider {
+	case "slack":
+		n = webhook.NewSlackNotifier(webhook.NewInternal(cfg.Timeout), cfg.WebhookURL)
+	case "pagerduty":
+		n = webhook.NewPagerDutyNotifier(webhook.NewInternal(cfg.Timeout), cfg.WebhookURL, cfg.PagerDutyRoutingKey)
+	default:
+		n = alert.NewLogNotifier(logger)
+	}
+
+	return alert.NewThrottle(n, logger, alert.Settings{
+		DedupWindow: cfg.DedupWindow,
+		RateLimit:   cfg.RateLimit,
+		Timeout:     cfg.Timeout,
+		OnResult:    func(r string) { mCounter.WithLabelValues("alerts_" + r + "_total").Inc() },
+	})
+}
+
+// newTimezones - the names are validated by cfg.Validate
+func newTimezones(cfg config.Timezones) domain.Timezones {
+	def, _ := time.LoadLocation(cfg.Default)
+	orgs := make(map[string]*time.Location, len(cfg.Orgs))
+	for _, item := range cfg.Orgs {
+		org, name, _ := strings.Cut(item, "=")
+		orgs[strings.ToLower(org)], _ = time.LoadLocation(name)
+	}
+
+	return domain.NewTimezones(def, orgs)
+}
+
+// newAgePolicy - the items are validated by cfg.Validate
+func newAgePolicy(cfg config.AgePolicy, timezones domain.Timezones) domain.AgePolicy {
+	orgs := make(map[string]int, len(cfg.Orgs))
+	for _, item := range cfg.Orgs {
+		org, years, _ := strings.Cut(item, "=")
+		orgs[strings.ToLower(org)], _ = strconv.Atoi(years)
+	}
+
+	return domain.NewAgePolicy(cfg.MinAge, orgs, timezones)
+}
+
+// multipartMemory - of a multipart form kept in memory, gin's default is 32MB
+const multipartMemory = 1 << 20
+
+// smsTimeout - the budget of a call to the SMS provider
+const smsTimeout = 10 * time.Second
+
+// newSMSSender - the provider of OTP_SMS_PROVIDER, validated by cfg.Validate
+func newSMSSender(cfg config.OTP, logger *zap.Logger) ports.SMSSender {
+	if cfg.SMSProvider == "twilio" {
+		return sms.NewTwilioSender("", cfg.TwilioAccountSID, cfg.TwilioAuthToken, cfg.TwilioFrom, smsTimeout)
+	}
+	return sms.NewLogSender(logger)
+}
+
+// rawFileTimeout - the budget of the raw file downloads: a media stream or a
+// large download takes longer than a handler, HTTP_ROUTE_TIMEOUTS overrides it
+const rawFileTimeout = time.Hour
+
+// routeTimeouts - by "<METHOD> <route>", validated by cfg.Validate
+func routeTimeouts(items []string) map[string]time.Duration {
+	routes := make(map[string]time.Duration, len(items)+1)
+	routes[http.MethodGet+" "+rest.RouteFilesRaw] = rawFileTimeout
+	for _, item := range items {
+		route, d, _ := strings.Cut(item, "=")
+		routes[route], _ = time.ParseDuration(d)
+	}
+
+	return routes
+}
+
+// schemaReadOnly compares the applied schema version with the code's
+// migrations: fails on a mismatch, or reports it to serve the reads only
+func schemaReadOnly(ctx context.Context, logger *zap.Logger, db postgres.DB, mode string) bool {
+	if mode == "off" {
+		return false
+	}
+
+	expected, err := migrations.Latest()
+	if err != nil {
+		logger.Fatal("migrations error", zap.Error(err))
+	}
+	applied, err := postgres.SchemaVersion(ctx, db)
+	if err != nil {
+		logger.Fatal("schema version error", zap.Error(err))
+	}
+	if applied == expected {
+		logger.Info("schema version checked", zap.Int64("version", applied))
+		return false
+	}
+
+	fields := []zap.Field{zap.Int64("expected_version", expected), zap.Int64("applied_version", applied)}
+	if mode != "read-only" {
+		logger.Fatal("schema version mismatch, apply the migrations or deploy the matching code", fields...)
+	}
+	logger.Warn("schema version mismatch, serving reads only", fields...)
+
+	return true
+}
+
+// checkIndexes warns about the indexes of the list filters and sorts missing
+// from the database: the lists keep working, on sequential scans
+func checkIndexes(ctx context.Context, logger *zap.Logger, db postgres.DB, mode string) {
+	if mode == "off" {
+		return
+	}
+
+	names := make([]string, len(migrations.Indexes))
+	for i, idx := range migrations.Indexes {
+		names[i] = idx.Name
+	}
+	missing, err := postgres.MissingIndexes(ctx, db, names)
+	if err != nil {
+		logger.Warn("indexes not checked", zap.Error(err))
+		return
+	}
+	if len(missing) == 0 {
+		logger.Info("indexes checked", zap.Int("count", len(names)))
+		return
+	}
+
+	for _, idx := range migrations.Indexes {
+		if slices.Contains(missing, idx.Name) {
+			logger.Warn("index missing, apply the migrations",
+				zap.String("index", idx.Name),
+				zap.String("table", idx.Table),
+				zap.String("serves", idx.Serves),
+			)
+		}
+	}
+}
+
+// verifyTopology compares the broker's topology with the configured one before
+// Init declares the missing parts. An exchange or a queue of other properties
+// fails the start unless mode is "warn"(Init fails on it anyway), the extra
+// bindings fail it in "fail" mode; true - the extra bindings are to be removed
+func verifyTopology(ctx context.Context, logger *zap.Logger, rbMQ *mq.RabbitMQ, mode string) bool {
+	if mode == "off" {
+		return false
+	}
+
+	report, err := rbMQ.CheckTopology(ctx)
+	if err != nil {
+		// the management API is optional, the broker itself is checked by Init
+		logger.Warn("rabbitmq topology not checked", zap.Error(err))
+		return false
+	}
+	if !report.Drift() {
+		logger.Info("rabbitmq topology checked")
+		return false
+	}
+
+	fields := []zap.Field{
+		zap.Bool("exchange_found", report.Exchange.Found),
+		zap.Strings("exchange_mismatches", report.Exchange.Mismatches),
+		zap.Bool("queue_found", report.Queue.Found),
+		zap.Strings("queue_mismatches", report.Queue.Mismatches),
+		zap.Strings("missing_bindings", report.MissingBindings),
+		zap.Strings("extra_bindings", report.ExtraBindings),
+	}
+	mismatch := len(report.Exchange.Mismatches) > 0 || len(report.Queue.Mismatches) > 0
+	switch {
+	case mismatch && mode != "warn":
+		logger.Fatal("rabbitmq topology mismatch, the exchange or the queue has to be redeclared", fields...)
+	case len(report.ExtraBindings) > 0 && mode == "fail":
+		logger.Fatal("rabbitmq topology drift", fields...)
+	}
+	logger.Warn("rabbitmq topology drift", fields...)
+
+	return mode == "repair" && len(report.ExtraBindings) > 0
+}
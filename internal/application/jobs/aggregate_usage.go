@@ -0,0 +1,32 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"user-manager-api/internal/application/ports"
+)
+
+const NameAggregateUsage = "aggregate-usage"
+
+// AggregateUsage recomputes the monthly billing records of the previous and
+// the current month, run daily the current one is up to date by a day.
+type AggregateUsage struct {
+	service ports.BillingService
+	logger  *zap.Logger
+}
+
+func NewAggregateUsage(service ports.BillingService, logger *zap.Logger) *AggregateUsage {
+	return &AggregateUsage{service: service, logger: logger}
+}
+
+func (j *AggregateUsage) Name() string { return NameAggregateUsage }
+
+func (j *AggregateUsage) Run(ctx context.Context) error {
+	records, err := j.service.AggregateMonthly(ctx, time.Now())
+	j.logger.Info("usage aggregated", zap.Int64("records_count", records))
+
+	return err
+}
@@ -0,0 +1,35 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"user-manager-api/internal/application/ports"
+)
+
+const NameArchiveAuditLog = "archive-audit-log"
+
+// ArchiveAuditLog moves the audit entries past RETENTION_AUDIT_DAYS to the
+// archive bucket as gzipped CSV.
+type ArchiveAuditLog struct {
+	service ports.AuditArchiveService
+	logger  *zap.Logger
+}
+
+func NewArchiveAuditLog(service ports.AuditArchiveService, logger *zap.Logger) *ArchiveAuditLog {
+	return &ArchiveAuditLog{service: service, logger: logger}
+}
+
+func (j *ArchiveAuditLog) Name() string { return NameArchiveAuditLog }
+
+func (j *ArchiveAuditLog) Run(ctx context.Context) error {
+	archived, err := j.service.Archive(ctx, time.Now())
+	if err != nil {
+		return err
+	}
+	j.logger.Info("audit log archived", zap.Int("archived_count", archived))
+
+	return nil
+}
@@ -0,0 +1,35 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"user-manager-api/internal/application/ports"
+)
+
+const NameBackup = "backup"
+
+// Backup uploads an encrypted archive of the users, the files metadata and
+// the audit log for the disaster recovery, see Restore.
+type Backup struct {
+	service ports.BackupService
+	logger  *zap.Logger
+}
+
+func NewBackup(service ports.BackupService, logger *zap.Logger) *Backup {
+	return &Backup{service: service, logger: logger}
+}
+
+func (j *Backup) Name() string { return NameBackup }
+
+func (j *Backup) Run(ctx context.Context) error {
+	key, counts, err := j.service.Backup(ctx, time.Now())
+	if err != nil {
+		return err
+	}
+	j.logger.Info("backup created", zap.String("key", key), zap.Any("rows", counts))
+
+	return nil
+}
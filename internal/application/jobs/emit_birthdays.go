@@ -0,0 +1,31 @@
+package jobs
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+
+	"user-manager-api/internal/application/ports"
+)
+
+const NameEmitBirthdays = "emit-birthdays"
+
+// EmitBirthdays publishes UserBirthday for the users whose birthday it is today
+// in their timezone, reruns the same day are deduplicated by the consumers.
+type EmitBirthdays struct {
+	service ports.UserCommands
+	logger  *zap.Logger
+}
+
+func NewEmitBirthdays(service ports.UserCommands, logger *zap.Logger) *EmitBirthdays {
+	return &EmitBirthdays{service: service, logger: logger}
+}
+
+func (j *EmitBirthdays) Name() string { return NameEmitBirthdays }
+
+func (j *EmitBirthdays) Run(ctx context.Context) error {
+	sent, err := j.service.EmitBirthdays(ctx)
+	j.logger.Info("birthdays emitted", zap.Int("sent_count", sent))
+
+	return err
+}
@@ -0,0 +1,23 @@
+package jobs
+
+// Names - the jobs the command line runs once("usermanager <name>"), a job is
+// added here with its Name constant
+var Names = []string{
+	NameReconcileFiles,
+	NameRebuildStats,
+	NameReencryptPII,
+	NameRedactInactive,
+	NamePurgeExpiredFiles,
+	NamePurgeProcessedEvents,
+	NameResumeDeletions,
+	NameRelayOutbox,
+	NameSyncDirectory,
+	NameSendDigests,
+	NameAggregateUsage,
+	NameEmitBirthdays,
+	NameRebuildProjections,
+	NameArchiveAuditLog,
+	NameBackup,
+	NameRestore,
+	NameSuspendInactive,
+}
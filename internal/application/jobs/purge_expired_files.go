@@ -0,0 +1,34 @@
+package jobs
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+
+	"user-manager-api/internal/application/ports"
+)
+
+const NamePurgeExpiredFiles = "purge-expired-files"
+
+// PurgeExpiredFiles applies the file retention rules: deletes the files kept
+// beyond their retention, the ones on a legal hold stay.
+type PurgeExpiredFiles struct {
+	service ports.FileRetentionService
+	logger  *zap.Logger
+}
+
+func NewPurgeExpiredFiles(service ports.FileRetentionService, logger *zap.Logger) *PurgeExpiredFiles {
+	return &PurgeExpiredFiles{service: service, logger: logger}
+}
+
+func (j *PurgeExpiredFiles) Name() string { return NamePurgeExpiredFiles }
+
+func (j *PurgeExpiredFiles) Run(ctx context.Context) error {
+	purged, err := j.service.PurgeExpired(ctx)
+	if err != nil {
+		return err
+	}
+	j.logger.Info("expired files purged", zap.Int("purged_files_count", purged))
+
+	return nil
+}
@@ -0,0 +1,34 @@
+package jobs
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+
+	"user-manager-api/internal/application/ports"
+)
+
+const NamePurgeProcessedEvents = "purge-processed-events"
+
+// PurgeProcessedEvents deletes the consumer's processed event ids older than
+// the dedup window: they are not needed to skip the redeliveries any more.
+type PurgeProcessedEvents struct {
+	service ports.EventDedupService
+	logger  *zap.Logger
+}
+
+func NewPurgeProcessedEvents(service ports.EventDedupService, logger *zap.Logger) *PurgeProcessedEvents {
+	return &PurgeProcessedEvents{service: service, logger: logger}
+}
+
+func (j *PurgeProcessedEvents) Name() string { return NamePurgeProcessedEvents }
+
+func (j *PurgeProcessedEvents) Run(ctx context.Context) error {
+	purged, err := j.service.Purge(ctx)
+	if err != nil {
+		return err
+	}
+	j.logger.Info("processed events purged", zap.Int64("purged_count", purged))
+
+	return nil
+}
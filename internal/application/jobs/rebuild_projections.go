@@ -0,0 +1,35 @@
+package jobs
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+
+	"user-manager-api/internal/application/ports"
+)
+
+const NameRebuildProjections = "rebuild-projections"
+
+// RebuildProjections replays the event store into the read models, CLI only:
+// after a change of the projections or of the read models tables
+// "usermanager rebuild-projections"
+type RebuildProjections struct {
+	service ports.ProjectionService
+	logger  *zap.Logger
+}
+
+func NewRebuildProjections(service ports.ProjectionService, logger *zap.Logger) *RebuildProjections {
+	return &RebuildProjections{service: service, logger: logger}
+}
+
+func (j *RebuildProjections) Name() string { return NameRebuildProjections }
+
+func (j *RebuildProjections) Run(ctx context.Context) error {
+	events, err := j.service.Rebuild(ctx)
+	if err != nil {
+		return err
+	}
+	j.logger.Info("projections rebuilt", zap.Int64("events", events))
+
+	return nil
+}
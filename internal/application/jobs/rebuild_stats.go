@@ -0,0 +1,33 @@
+package jobs
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+
+	"user-manager-api/internal/application/ports"
+)
+
+const NameRebuildStats = "rebuild-stats"
+
+// RebuildStats recomputes the dashboard aggregates: fixes the drift of changes
+// made without an event and fills the tables after a lost queue.
+type RebuildStats struct {
+	service ports.StatsService
+	logger  *zap.Logger
+}
+
+func NewRebuildStats(service ports.StatsService, logger *zap.Logger) *RebuildStats {
+	return &RebuildStats{service: service, logger: logger}
+}
+
+func (j *RebuildStats) Name() string { return NameRebuildStats }
+
+func (j *RebuildStats) Run(ctx context.Context) error {
+	if err := j.service.Rebuild(ctx); err != nil {
+		return err
+	}
+	j.logger.Info("stats rebuilt")
+
+	return nil
+}
@@ -0,0 +1,54 @@
+package jobs
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+
+	"user-manager-api/internal/application/ports"
+)
+
+const NameReconcileFiles = "reconcile-files"
+
+// ReconcileFiles reports(and optionally deletes) storage objects without
+// user_files rows and rows whose objects are missing.
+type ReconcileFiles struct {
+	service       ports.FileReconcileService
+	logger        *zap.Logger
+	deleteOrphans bool
+}
+
+func NewReconcileFiles(
+	service ports.FileReconcileService,
+	logger *zap.Logger,
+	deleteOrphans bool,
+) *ReconcileFiles {
+	return &ReconcileFiles{
+		service:       service,
+		logger:        logger,
+		deleteOrphans: deleteOrphans,
+	}
+}
+
+func (j *ReconcileFiles) Name() string { return NameReconcileFiles }
+
+func (j *ReconcileFiles) Run(ctx context.Context) error {
+	report, err := j.service.Reconcile(ctx, j.deleteOrphans)
+	if err != nil {
+		return err
+	}
+
+	missing := make([]string, len(report.MissingObjects))
+	for i, ref := range report.MissingObjects {
+		missing[i] = ref.StorageKey
+	}
+	j.logger.Info("files reconciled",
+		zap.Int("orphan_objects_count", len(report.OrphanObjects)),
+		zap.Int("missing_objects_count", len(report.MissingObjects)),
+		zap.Strings("orphan_objects", report.OrphanObjects),
+		zap.Strings("missing_objects", missing),
+		zap.Bool("deleted", report.Deleted),
+	)
+
+	return nil
+}
@@ -0,0 +1,34 @@
+package jobs
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+
+	"user-manager-api/internal/application/ports"
+)
+
+const NameRedactInactive = "redact-inactive-users"
+
+// RedactInactive applies the retention policy: blanks the PII columns of users
+// inactive beyond the retention period.
+type RedactInactive struct {
+	service ports.RetentionService
+	logger  *zap.Logger
+}
+
+func NewRedactInactive(service ports.RetentionService, logger *zap.Logger) *RedactInactive {
+	return &RedactInactive{service: service, logger: logger}
+}
+
+func (j *RedactInactive) Name() string { return NameRedactInactive }
+
+func (j *RedactInactive) Run(ctx context.Context) error {
+	redacted, err := j.service.RedactInactive(ctx)
+	if err != nil {
+		return err
+	}
+	j.logger.Info("inactive users redacted", zap.Int("redacted_users_count", redacted))
+
+	return nil
+}
@@ -0,0 +1,34 @@
+package jobs
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+
+	"user-manager-api/internal/application/ports"
+)
+
+const NameReencryptPII = "reencrypt-pii"
+
+// ReencryptPII encrypts PII written before the encryption was introduced and
+// moves PII encrypted with retired keys to the active one.
+type ReencryptPII struct {
+	service ports.PIIService
+	logger  *zap.Logger
+}
+
+func NewReencryptPII(service ports.PIIService, logger *zap.Logger) *ReencryptPII {
+	return &ReencryptPII{service: service, logger: logger}
+}
+
+func (j *ReencryptPII) Name() string { return NameReencryptPII }
+
+func (j *ReencryptPII) Run(ctx context.Context) error {
+	updated, err := j.service.Reencrypt(ctx)
+	if err != nil {
+		return err
+	}
+	j.logger.Info("pii re-encrypted", zap.Int("updated_users_count", updated))
+
+	return nil
+}
@@ -0,0 +1,34 @@
+package jobs
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+
+	"user-manager-api/internal/application/ports"
+)
+
+const NameRelayOutbox = "relay-outbox"
+
+// RelayOutbox publishes the events kept in the outbox while RabbitMQ was down
+// or saturated, a run while it is still down relays nothing.
+type RelayOutbox struct {
+	service ports.OutboxService
+	logger  *zap.Logger
+}
+
+func NewRelayOutbox(service ports.OutboxService, logger *zap.Logger) *RelayOutbox {
+	return &RelayOutbox{service: service, logger: logger}
+}
+
+func (j *RelayOutbox) Name() string { return NameRelayOutbox }
+
+func (j *RelayOutbox) Run(ctx context.Context) error {
+	relayed, err := j.service.Relay(ctx)
+	if err != nil {
+		return err
+	}
+	j.logger.Info("outbox events relayed", zap.Int("relayed_count", relayed))
+
+	return nil
+}
@@ -0,0 +1,41 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+
+	"go.uber.org/zap"
+
+	"user-manager-api/internal/application/ports"
+)
+
+const NameRestore = "restore"
+
+// Restore replays a Backup archive into an empty database, CLI only:
+// "usermanager restore -object backups/<time>.umbak"
+type Restore struct {
+	service ports.BackupService
+	logger  *zap.Logger
+	object  string
+}
+
+func NewRestore(service ports.BackupService, logger *zap.Logger, object string) *Restore {
+	return &Restore{service: service, logger: logger, object: object}
+}
+
+func (j *Restore) Name() string { return NameRestore }
+
+func (j *Restore) Run(ctx context.Context) error {
+	if j.object == "" {
+		return errors.New("the archive to restore is not set(-object)")
+	}
+
+	counts, err := j.service.Restore(ctx, j.object)
+	if err != nil {
+		return err
+	}
+	// the read models are maintained by the events, a restore publishes none
+	j.logger.Info("backup restored, run rebuild-stats", zap.String("key", j.object), zap.Any("rows", counts))
+
+	return nil
+}
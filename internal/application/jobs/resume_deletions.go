@@ -0,0 +1,34 @@
+package jobs
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+
+	"user-manager-api/internal/application/ports"
+)
+
+const NameResumeDeletions = "resume-deletions"
+
+// ResumeDeletions continues the user deletions interrupted by a failure or a
+// restart from their stored step, so no objects nor files are left behind.
+type ResumeDeletions struct {
+	service ports.UserDeletionService
+	logger  *zap.Logger
+}
+
+func NewResumeDeletions(service ports.UserDeletionService, logger *zap.Logger) *ResumeDeletions {
+	return &ResumeDeletions{service: service, logger: logger}
+}
+
+func (j *ResumeDeletions) Name() string { return NameResumeDeletions }
+
+func (j *ResumeDeletions) Run(ctx context.Context) error {
+	completed, err := j.service.Resume(ctx)
+	if err != nil {
+		return err
+	}
+	j.logger.Info("user deletions resumed", zap.Int("completed_count", completed))
+
+	return nil
+}
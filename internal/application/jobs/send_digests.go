@@ -0,0 +1,31 @@
+package jobs
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+
+	"user-manager-api/internal/application/ports"
+)
+
+const NameSendDigests = "send-digests"
+
+// SendDigests sends the queued non-urgent notifications of the digest mode
+// channels, run daily for daily digests.
+type SendDigests struct {
+	service ports.NotificationService
+	logger  *zap.Logger
+}
+
+func NewSendDigests(service ports.NotificationService, logger *zap.Logger) *SendDigests {
+	return &SendDigests{service: service, logger: logger}
+}
+
+func (j *SendDigests) Name() string { return NameSendDigests }
+
+func (j *SendDigests) Run(ctx context.Context) error {
+	sent, err := j.service.SendDigests(ctx)
+	j.logger.Info("digests sent", zap.Int("sent_count", sent))
+
+	return err
+}
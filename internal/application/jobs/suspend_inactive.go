@@ -0,0 +1,38 @@
+package jobs
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+
+	"user-manager-api/internal/application/ports"
+)
+
+const NameSuspendInactive = "suspend-inactive-users"
+
+// SuspendInactive warns the users about to be suspended for inactivity and
+// suspends the ones warned long enough.
+type SuspendInactive struct {
+	service ports.SuspensionService
+	logger  *zap.Logger
+}
+
+func NewSuspendInactive(service ports.SuspensionService, logger *zap.Logger) *SuspendInactive {
+	return &SuspendInactive{service: service, logger: logger}
+}
+
+func (j *SuspendInactive) Name() string { return NameSuspendInactive }
+
+func (j *SuspendInactive) Run(ctx context.Context) error {
+	warned, suspended, err := j.service.SuspendInactive(ctx)
+	if err != nil {
+		return err
+	}
+	j.logger.Info(
+		"inactive users suspended",
+		zap.Int("warned_users_count", warned),
+		zap.Int("suspended_users_count", suspended),
+	)
+
+	return nil
+}
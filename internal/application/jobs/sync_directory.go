@@ -0,0 +1,42 @@
+package jobs
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+
+	"user-manager-api/internal/application/ports"
+)
+
+const NameSyncDirectory = "sync-directory"
+
+// SyncDirectory reconciles the users with the LDAP/Active Directory, the
+// summary is reported by GET /api/v1/admin/directory/sync as well.
+type SyncDirectory struct {
+	service ports.DirectorySyncService
+	logger  *zap.Logger
+}
+
+func NewSyncDirectory(service ports.DirectorySyncService, logger *zap.Logger) *SyncDirectory {
+	return &SyncDirectory{service: service, logger: logger}
+}
+
+func (j *SyncDirectory) Name() string { return NameSyncDirectory }
+
+func (j *SyncDirectory) Run(ctx context.Context) error {
+	s, err := j.service.Sync(ctx)
+	if err != nil {
+		return err
+	}
+	j.logger.Info("directory synced",
+		zap.Int("fetched_count", s.Fetched),
+		zap.Int("created_count", s.Created),
+		zap.Int("updated_count", s.Updated),
+		zap.Int("disabled_count", s.Disabled),
+		zap.Int("unchanged_count", s.Unchanged),
+		zap.Int("skipped_count", s.Skipped),
+		zap.Int("failed_count", s.Failed),
+	)
+
+	return nil
+}
@@ -0,0 +1,14 @@
+package ports
+
+import (
+	"context"
+
+	"user-manager-api/internal/domain/pagination"
+	"user-manager-api/internal/domain/user_file"
+)
+
+// AdminFileService - storage governance: files of all users
+type AdminFileService interface {
+	FindFiles(ctx context.Context, f user_file.Filter, p pagination.Params) (user_file.UserFiles, error)
+	FileStats(ctx context.Context, f user_file.Filter) (*user_file.Stats, error)
+}
@@ -0,0 +1,14 @@
+package ports
+
+import (
+	"context"
+
+	"user-manager-api/internal/infrastructure/mq"
+)
+
+// AnomalyService - the rules of the login events(impossible travel, new device)
+type AnomalyService interface {
+	// CheckLogin compares the login with the known ones of the user, a flagged
+	// login is audited, alerted and optionally revokes the tokens of the user
+	CheckLogin(ctx context.Context, e mq.Event) error
+}
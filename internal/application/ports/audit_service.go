@@ -0,0 +1,24 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"user-manager-api/internal/domain/audit"
+	"user-manager-api/internal/domain/pagination"
+)
+
+type AuditService interface {
+	Record(ctx context.Context, e audit.Entry) error
+	// Recorded mirrors to the log and the metrics an entry a repository stored
+	// in the transaction of its change
+	Recorded(e audit.Entry)
+	// FindEntries - the audit log review, the archived entries are not there
+	FindEntries(ctx context.Context, f audit.Filter, p pagination.Params) ([]audit.Entry, error)
+}
+
+// AuditArchiveService moves the audit entries past the retention to the archive bucket
+type AuditArchiveService interface {
+	// Archive - the archived(and deleted) entries created before now minus the retention
+	Archive(ctx context.Context, now time.Time) (int, error)
+}
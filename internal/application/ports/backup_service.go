@@ -0,0 +1,18 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"user-manager-api/internal/domain/backup"
+)
+
+// BackupService - the disaster recovery archives of the users, the files
+// metadata(not the objects) and the audit log
+type BackupService interface {
+	// Backup uploads an encrypted archive of now, returns its key
+	Backup(ctx context.Context, now time.Time) (string, backup.Counts, error)
+	// Restore replays the archive into a database without users, files and
+	// audit entries, migrated to the schema of the archive
+	Restore(ctx context.Context, key string) (backup.Counts, error)
+}
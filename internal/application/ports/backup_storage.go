@@ -0,0 +1,12 @@
+package ports
+
+import (
+	"context"
+	"io"
+)
+
+// BackupStorage - the bucket of the backup archives, apart from the user files
+type BackupStorage interface {
+	PutObject(ctx context.Context, key, contentType string, body io.ReadSeeker, size int64) error
+	GetObject(ctx context.Context, key string) (io.ReadCloser, error)
+}
@@ -0,0 +1,18 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"user-manager-api/internal/domain/usage"
+)
+
+// BillingService - the monthly usage records per organization(email domain)
+type BillingService interface {
+	// AggregateMonthly recomputes the records of the previous and the current
+	// UTC month of now, so the previous month is final after its last rollups
+	// were flushed, returns the records count
+	AggregateMonthly(ctx context.Context, now time.Time) (int64, error)
+	// Monthly - the records of [from, to], both the first day of a UTC month
+	Monthly(ctx context.Context, from, to time.Time) ([]usage.Monthly, error)
+}
@@ -0,0 +1,18 @@
+package ports
+
+import (
+	"context"
+
+	"user-manager-api/internal/domain/token"
+	"user-manager-api/internal/domain/user"
+)
+
+type CredentialService interface {
+	// ForcePasswordReset - incident response: revokes the tokens of target and
+	// refuses its login until the password is changed
+	ForcePasswordReset(ctx context.Context, actor, target user.UUID) error
+	// ChangePassword verifies the current password and returns a new token,
+	// tokens issued before are revoked
+	ChangePassword(ctx context.Context, email, password, newPassword string) (string, error)
+	IsTokenRevoked(ctx context.Context, claims *token.Claims) (bool, error)
+}
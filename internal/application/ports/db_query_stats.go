@@ -0,0 +1,16 @@
+package ports
+
+import (
+	"time"
+
+	"user-manager-api/internal/domain/stats"
+)
+
+// DBQueryStats - the statement latencies of this instance since its start, the
+// arguments are never kept(see postgres.QueryTracer)
+type DBQueryStats interface {
+	// SlowQueries - the statements over Threshold, the ones slow most often first
+	SlowQueries(limit int) []stats.QueryStats
+	// Threshold - POSTGRES_SLOW_QUERY_THRESHOLD, 0 - off
+	Threshold() time.Duration
+}
@@ -0,0 +1,18 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"user-manager-api/internal/domain/eventbus"
+)
+
+// DeadLetterService - the dead-letter queue for the operators, the requeues and
+// the discards are audited
+type DeadLetterService interface {
+	Peek(ctx context.Context, limit int) (*eventbus.DeadLetters, error)
+	// Requeue, Discard - the message ids done, the ones not found are left out
+	Requeue(ctx context.Context, actor uuid.UUID, ids []string) ([]string, error)
+	Discard(ctx context.Context, actor uuid.UUID, ids []string) ([]string, error)
+}
@@ -0,0 +1,12 @@
+package ports
+
+import "time"
+
+// DependencyHealth - a soft dependency as its guard sees it, e.g. the storage
+// or the broker behind resilience.Guard
+type DependencyHealth interface {
+	// Healthy - false while the calls to it fail fast(the breaker is open)
+	Healthy() bool
+	// RetryAfter - until the next probe of it while it is not healthy
+	RetryAfter() time.Duration
+}
@@ -0,0 +1,20 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"user-manager-api/internal/domain/device"
+	"user-manager-api/internal/domain/token"
+	"user-manager-api/internal/domain/user"
+)
+
+// DeviceService - the devices the users logged in from, with the tokens bound to them
+type DeviceService interface {
+	Devices(ctx context.Context, userUUID user.UUID) (device.Devices, error)
+	// RevokeDevice deletes the device, the tokens bound to it are revoked
+	RevokeDevice(ctx context.Context, userUUID user.UUID, deviceUUID uuid.UUID) error
+	// IsTokenRevoked - the device of a bound token is gone, unbound tokens are never revoked
+	IsTokenRevoked(ctx context.Context, claims *token.Claims) (bool, error)
+}
@@ -0,0 +1,12 @@
+package ports
+
+import (
+	"context"
+
+	"user-manager-api/internal/domain/directory"
+)
+
+// Directory - the LDAP/Active Directory users are synced from
+type Directory interface {
+	FetchEntries(ctx context.Context) ([]directory.Entry, error)
+}
@@ -0,0 +1,14 @@
+package ports
+
+import (
+	"context"
+
+	"user-manager-api/internal/domain/directory"
+)
+
+type DirectorySyncService interface {
+	// Sync reconciles the users with the directory, the summary is stored even if it fails
+	Sync(ctx context.Context) (directory.Sync, error)
+	// LastSync - nil if never synced
+	LastSync(ctx context.Context) (*directory.Sync, error)
+}
@@ -0,0 +1,15 @@
+package ports
+
+import (
+	"context"
+
+	"user-manager-api/internal/domain/user"
+)
+
+type DuplicateService interface {
+	// FindDuplicates - the groups of the active users likely to be the same person
+	FindDuplicates(ctx context.Context) ([]user.DuplicateGroup, error)
+	// Merge moves the files, notes and audit records of loser to winner and
+	// soft-deletes loser, the winner is returned
+	Merge(ctx context.Context, actor, winner, loser user.UUID) (*user.User, user.MergeResult, error)
+}
@@ -0,0 +1,9 @@
+package ports
+
+import "context"
+
+// EmailSender - email provider, a permanent rejection of the address is
+// reported as notification.ErrRecipientRejected
+type EmailSender interface {
+	Send(ctx context.Context, to, subject, html string) error
+}
@@ -0,0 +1,11 @@
+package ports
+
+import "context"
+
+// EventDedupService - the processed events of the consumer(rmqconsumer.Deduplicator)
+type EventDedupService interface {
+	Claim(ctx context.Context, messageID string) (bool, error)
+	Release(ctx context.Context, messageID string) error
+	// Purge deletes the ones older than the dedup window
+	Purge(ctx context.Context) (int64, error)
+}
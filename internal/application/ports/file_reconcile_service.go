@@ -0,0 +1,11 @@
+package ports
+
+import (
+	"context"
+
+	"user-manager-api/internal/domain/user_file"
+)
+
+type FileReconcileService interface {
+	Reconcile(ctx context.Context, deleteOrphans bool) (*user_file.ReconcileReport, error)
+}
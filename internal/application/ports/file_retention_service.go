@@ -0,0 +1,21 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"user-manager-api/internal/domain/user_file"
+)
+
+// FileRetentionService - how long the files are kept: the rules by mime type or
+// tag, the per-file overrides and the legal holds, enforced by PurgeExpired
+type FileRetentionService interface {
+	Rules(ctx context.Context) (user_file.RetentionRules, error)
+	CreateRule(ctx context.Context, actor uuid.UUID, r user_file.RetentionRule) (*user_file.RetentionRule, error)
+	// DeleteRule - false if there is no such rule
+	DeleteRule(ctx context.Context, actor, ruleUUID uuid.UUID) (bool, error)
+	SetFileRetention(ctx context.Context, actor, fileUUID uuid.UUID, r user_file.Retention) error
+	// PurgeExpired deletes the expired files, returns the count purged
+	PurgeExpired(ctx context.Context) (int, error)
+}
@@ -0,0 +1,13 @@
+package ports
+
+import (
+	"context"
+
+	"user-manager-api/internal/domain/user"
+)
+
+type HRHookService interface {
+	// ApplyEmployee creates or updates the user of the employee email, created -
+	// a new user
+	ApplyEmployee(ctx context.Context, employee user.User) (u *user.User, created bool, err error)
+}
@@ -0,0 +1,13 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"user-manager-api/internal/domain/user"
+)
+
+type ImpersonationService interface {
+	// Impersonate issues a short-lived token of target for actor(admin)
+	Impersonate(ctx context.Context, actor, target user.UUID) (token string, expiresAt time.Time, err error)
+}
@@ -0,0 +1,15 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"user-manager-api/internal/domain/user"
+)
+
+type InvitationService interface {
+	// Invite sends a signup link to email, a pending invitation of the email is replaced
+	Invite(ctx context.Context, actor user.UUID, email, role string) (expiresAt time.Time, err error)
+	// Accept creates the invited user with the email and role of the invitation
+	Accept(ctx context.Context, token string, u user.User, password string) (*user.User, error)
+}
@@ -1,9 +1,14 @@
 package ports
 
 import (
+	"context"
+
+	"user-manager-api/internal/domain/login"
 	"user-manager-api/internal/domain/user"
 )
 
 type Auth interface {
-	GenerateToken(u *user.User, requestPassword string) (string, error)
+	// GenerateToken verifies the password, outdated hashes are replaced on success.
+	// Every attempt is published as a login event of client.
+	GenerateToken(ctx context.Context, u *user.User, requestPassword string, client login.Client) (string, error)
 }
@@ -0,0 +1,18 @@
+package ports
+
+import (
+	"context"
+
+	"user-manager-api/internal/domain/user"
+)
+
+// LegalHoldService - a user on a legal hold and their files are neither deleted,
+// redacted nor purged until it is released
+type LegalHoldService interface {
+	// Place - a placed hold gets the new reason, ErrUserNotFound if unknown
+	Place(ctx context.Context, actor, userUUID user.UUID, reason string) (*user.LegalHold, error)
+	// Release - false if the user is not on a hold, ErrUserNotFound if unknown
+	Release(ctx context.Context, actor, userUUID user.UUID) (bool, error)
+	// Get - nil if the user is not on a hold, ErrUserNotFound if unknown
+	Get(ctx context.Context, userUUID user.UUID) (*user.LegalHold, error)
+}
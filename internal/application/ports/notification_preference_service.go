@@ -0,0 +1,15 @@
+package ports
+
+import (
+	"context"
+
+	"user-manager-api/internal/domain/notification"
+	domain "user-manager-api/internal/domain/user"
+)
+
+type NotificationPreferenceService interface {
+	// Preferences - every channel, the defaults for the ones never set
+	Preferences(ctx context.Context, userUUID domain.UUID) (notification.Preferences, error)
+	// SetPreferences - the other channels are kept, returns every channel
+	SetPreferences(ctx context.Context, userUUID domain.UUID, p notification.Preferences) (notification.Preferences, error)
+}
@@ -0,0 +1,18 @@
+package ports
+
+import (
+	"context"
+
+	"user-manager-api/internal/infrastructure/mq"
+)
+
+// NotificationService - the notifications telling the users about the domain events
+type NotificationService interface {
+	// Notify sends the notifications of the event over the channels the user opted
+	// in to, the non-urgent ones of the digest mode channels are queued instead.
+	// Events without a notification are ignored.
+	Notify(ctx context.Context, e mq.Event) error
+	// SendDigests sends the queued notifications, one digest per user and channel,
+	// returns the count of the digests sent
+	SendDigests(ctx context.Context) (int, error)
+}
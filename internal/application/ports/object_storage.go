@@ -0,0 +1,27 @@
+package ports
+
+import (
+	"context"
+	"io"
+	"io/fs"
+)
+
+// ObjectStorage - blob storage behind user files(S3, local FS, ...)
+type ObjectStorage interface {
+	PutObject(ctx context.Context, key, contentType string, body io.ReadSeeker, size int64) error
+	DeleteObjects(ctx context.Context, keys []string) error
+	ListObjects(ctx context.Context, prefix string) ([]string, error)
+	GetPublicURL(key string) string
+	GetBucket() string
+}
+
+// ObjectReader - storages which serve objects through the API themselves
+type ObjectReader interface {
+	GetObject(ctx context.Context, key string) (io.ReadSeekCloser, fs.FileInfo, error)
+}
+
+// RangeLimiter - an object of ObjectReader read from a remote storage: the
+// reads stop at end(exclusive), so only the range requested is fetched
+type RangeLimiter interface {
+	LimitRange(end int64)
+}
@@ -0,0 +1,10 @@
+package ports
+
+import "context"
+
+type OTPService interface {
+	// RequestOTP sends a login code to phone, unknown phones are silently ignored
+	RequestOTP(ctx context.Context, phone string) error
+	// VerifyOTP exchanges a valid code for an access token
+	VerifyOTP(ctx context.Context, phone, code string) (string, error)
+}
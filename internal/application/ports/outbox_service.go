@@ -0,0 +1,17 @@
+package ports
+
+import (
+	"context"
+
+	"user-manager-api/internal/infrastructure/mq"
+)
+
+// OutboxService - the events kept in the database while the broker does not
+// take them, published later
+type OutboxService interface {
+	// Keep stores e to be relayed, an event kept already is kept once
+	Keep(ctx context.Context, e mq.Event) error
+	// Relay publishes the kept events oldest first while the broker takes them,
+	// returns the count relayed
+	Relay(ctx context.Context) (int, error)
+}
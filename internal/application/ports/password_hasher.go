@@ -0,0 +1,9 @@
+package ports
+
+type PasswordHasher interface {
+	Hash(password string) (string, error)
+	// Verify - needsRehash is set when the hash uses an outdated algorithm or parameters
+	Verify(password, hash string) (ok, needsRehash bool, err error)
+	// DummyVerify spends the time of Verify, for users without a hash(unknown email)
+	DummyVerify(password string)
+}
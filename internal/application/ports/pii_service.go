@@ -0,0 +1,9 @@
+package ports
+
+import "context"
+
+type PIIService interface {
+	// Reencrypt moves the encrypted PII of all users to the active key, returns
+	// the number of updated users
+	Reencrypt(ctx context.Context) (int, error)
+}
@@ -0,0 +1,22 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"user-manager-api/internal/domain/projection"
+	"user-manager-api/internal/infrastructure/mq"
+)
+
+// ProjectionService - the event store and the read models projected from it
+type ProjectionService interface {
+	// Record stores the event and projects it, a redelivered one is ignored
+	Record(ctx context.Context, e mq.Event) error
+	// Rebuild replays the whole event store into empty read models, returns the
+	// events replayed
+	Rebuild(ctx context.Context) (int64, error)
+	// UserSummary - nil if no event of the user is stored, with the activity of
+	// the latest days(today included)
+	UserSummary(ctx context.Context, userUUID uuid.UUID, days int) (*projection.UserSummary, error)
+}
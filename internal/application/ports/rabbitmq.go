@@ -5,13 +5,35 @@ import (
 
 	"github.com/rabbitmq/amqp091-go"
 
+	"user-manager-api/internal/domain/eventbus"
 	"user-manager-api/internal/infrastructure/mq"
 )
 
-type RabbitMQ interface {
-	Connect(ctx context.Context, dsn string) error
-	Init() error
-	PublisherWorker(ctx context.Context)
-	GetInputChan() chan mq.Event
-	GetConn() *amqp091.Connection
-}
+type (
+	RabbitMQ interface {
+		MQTopology
+		MQDeadLetters
+		Connect(ctx context.Context, dsn string) error
+		Init() error
+		PublisherWorker(ctx context.Context)
+		// Publish never blocks longer than the enqueue timeout, see mq.ErrBackpressure
+		Publish(ctx context.Context, e mq.Event) error
+		GetConn() *amqp091.Connection
+	}
+	// MQTopology - the exchange, queue and bindings on the broker against the
+	// declared ones, eventbus.ErrNoManagement without the management API
+	MQTopology interface {
+		CheckTopology(ctx context.Context) (*eventbus.TopologyReport, error)
+		// RepairTopology - eventbus.ErrTopologyMismatch(with the report) if the
+		// exchange or the queue itself differs
+		RepairTopology(ctx context.Context) (*eventbus.TopologyReport, error)
+	}
+	// MQDeadLetters - the deliveries the consumer failed on, eventbus.ErrNoDeadLetterQueue
+	// without the queue
+	MQDeadLetters interface {
+		PeekDeadLetters(ctx context.Context, limit int) (*eventbus.DeadLetters, error)
+		// RequeueDeadLetters, DiscardDeadLetters - the ids done, the ones not found are left out
+		RequeueDeadLetters(ctx context.Context, ids []string) ([]string, error)
+		DiscardDeadLetters(ctx context.Context, ids []string) ([]string, error)
+	}
+)
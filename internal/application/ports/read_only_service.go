@@ -0,0 +1,21 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"user-manager-api/internal/domain/mode"
+)
+
+// ReadOnlyService - the read-only mode of the failovers and the migrations:
+// the toggle is shared by the instances, an instance may be forced on its own
+type ReadOnlyService interface {
+	// Enabled - the hot path of every mutating request, no I/O
+	Enabled() bool
+	Status(ctx context.Context) (*mode.ReadOnly, error)
+	// Set toggles the mode of every instance, they pick it up within the poll interval
+	Set(ctx context.Context, actor uuid.UUID, enabled bool, reason string) (*mode.ReadOnly, error)
+	// Worker polls the toggle
+	Worker(ctx context.Context)
+}
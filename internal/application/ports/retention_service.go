@@ -0,0 +1,9 @@
+package ports
+
+import "context"
+
+type RetentionService interface {
+	// RedactInactive blanks the configured PII of users inactive beyond the
+	// retention period, returns the number of redacted users
+	RedactInactive(ctx context.Context) (int, error)
+}
@@ -1,9 +1,18 @@
 package ports
 
-import "context"
+import (
+	"context"
+
+	"user-manager-api/pkg/rmqconsumer"
+)
 
 type RMQConsumer interface {
 	Connect(dsn string) error
 	Init() error
-	DeliveryWorker(ctx context.Context)
+	Handle(routingKey string, h rmqconsumer.Handler)
+	// Decode - the messages of the content type are decoded to JSON by d
+	Decode(contentType string, d rmqconsumer.Decoder)
+	// SetDeduplicator - the redeliveries of the processed messages are skipped
+	SetDeduplicator(d rmqconsumer.Deduplicator)
+	DeliveryWorker(ctx context.Context) error
 }
@@ -0,0 +1,17 @@
+package ports
+
+import (
+	"context"
+
+	"user-manager-api/internal/domain/token"
+	"user-manager-api/internal/domain/user"
+)
+
+type RoleService interface {
+	// AssignRoles - periodic permission reviews: changes are applied at once, a
+	// result per change in the same order
+	AssignRoles(ctx context.Context, actor user.UUID, changes []user.RoleChange) ([]user.RoleChangeResult, error)
+	// CurrentRole - the token.RoleLookup: the issued tokens get the new role
+	// within the cache TTL, no re-login
+	CurrentRole(ctx context.Context, claims *token.Claims) (string, error)
+}
@@ -1,6 +0,0 @@
-package ports
-
-type S3Client interface {
-	GetPublicURL(key string) string
-	GetBucket() string
-}
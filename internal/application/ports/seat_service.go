@@ -0,0 +1,16 @@
+package ports
+
+import (
+	"context"
+
+	"user-manager-api/internal/domain/usage"
+)
+
+// SeatService - the seat limits of the organizations(email domains), checked
+// by UserService.CreateUser
+type SeatService interface {
+	SeatLimits(ctx context.Context) ([]usage.SeatLimit, error)
+	SetSeatLimit(ctx context.Context, org string, seats uint32) (*usage.SeatLimit, error)
+	// RemoveSeatLimit - false if the organization had no limit
+	RemoveSeatLimit(ctx context.Context, org string) (bool, error)
+}
@@ -0,0 +1,8 @@
+package ports
+
+import "context"
+
+// SMSSender - SMS provider, phone is in E.164 format
+type SMSSender interface {
+	Send(ctx context.Context, phone, text string) error
+}
@@ -0,0 +1,21 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"user-manager-api/internal/domain/stats"
+	"user-manager-api/internal/infrastructure/mq"
+)
+
+// StatsService - dashboard aggregates maintained from the domain events
+type StatsService interface {
+	// ApplyEvent updates the aggregates affected by the event, events without
+	// an aggregate are ignored
+	ApplyEvent(ctx context.Context, e mq.Event) error
+	// Rebuild recomputes all the aggregates, e.g. after files were deleted
+	// without an event(reconcile-files)
+	Rebuild(ctx context.Context) error
+	DailySignups(ctx context.Context, from, to time.Time) ([]stats.DailySignups, error)
+	FilesTotals(ctx context.Context) (*stats.FilesTotals, error)
+}
@@ -0,0 +1,20 @@
+package ports
+
+import (
+	"context"
+
+	"user-manager-api/internal/domain/user"
+)
+
+// SuspensionService - the users inactive for too long are warned, then suspended:
+// login is refused until a login within the grace window or an admin lifts it
+type SuspensionService interface {
+	// SuspendInactive warns the users about to be suspended and suspends the ones
+	// warned long enough, returns the counts warned and suspended
+	SuspendInactive(ctx context.Context) (warned, suspended int, err error)
+	// CheckLogin - once the credentials of u are verified: ErrAccountSuspended, or
+	// the suspension is lifted within the grace window
+	CheckLogin(ctx context.Context, u *user.User) error
+	// Reactivate - false if the user is not suspended, ErrUserNotFound if unknown
+	Reactivate(ctx context.Context, actor, userUUID user.UUID) (bool, error)
+}
@@ -0,0 +1,22 @@
+package ports
+
+import (
+	"context"
+
+	"user-manager-api/internal/domain/user_file"
+)
+
+// TextExtractor - the OCR engine(tesseract, a cloud OCR, ...)
+type TextExtractor interface {
+	Supports(mimeType string) bool
+	Extract(ctx context.Context, mimeType string, data []byte) (string, error)
+}
+
+type TextExtractionService interface {
+	// Enqueue never blocks, false means the task was dropped
+	Enqueue(uf *user_file.UserFile, data []byte) bool
+	// Accepts - the type is supported and the file is small enough to be
+	// read into memory
+	Accepts(uf *user_file.UserFile) bool
+	Worker(ctx context.Context)
+}
@@ -0,0 +1,21 @@
+package ports
+
+import (
+	"context"
+
+	"user-manager-api/internal/domain/user_file"
+)
+
+type ThumbnailRenderer interface {
+	Supports(mimeType string) bool
+	Render(ctx context.Context, mimeType string, data []byte) ([]byte, error)
+}
+
+type ThumbnailService interface {
+	// Enqueue never blocks, false means the task was dropped
+	Enqueue(uf *user_file.UserFile, data []byte) bool
+	// Accepts - the type is supported and the file is small enough to be
+	// read into memory
+	Accepts(uf *user_file.UserFile) bool
+	Worker(ctx context.Context)
+}
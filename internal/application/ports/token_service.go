@@ -0,0 +1,27 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"user-manager-api/internal/domain/token"
+)
+
+// TokenService issues and verifies the access tokens, the format(JWT, ...) is
+// the implementation detail
+type TokenService interface {
+	GenerateToken(userID, role string, expiresIn time.Duration) (string, error)
+	// GenerateImpersonationToken - token of userID issued to the actorID admin
+	GenerateImpersonationToken(userID, role, actorID string, expiresIn time.Duration) (string, error)
+	// GenerateDeviceToken - token of userID bound to the deviceID it logged in from
+	GenerateDeviceToken(userID, role, deviceID string, expiresIn time.Duration) (string, error)
+	// ValidateToken checks the signature and the expiry only, see IsRevoked
+	ValidateToken(tokenStr string) (*token.Claims, error)
+	IsRevoked(ctx context.Context, claims *token.Claims) (bool, error)
+	// CurrentRole - the role of the user now, the role claim of a token issued
+	// before a role change is stale
+	CurrentRole(ctx context.Context, claims *token.Claims) (string, error)
+	// RefreshToken - the sliding session: a new token of claims with role when
+	// they are about to expire, "" otherwise
+	RefreshToken(claims *token.Claims, role string) (string, error)
+}
@@ -0,0 +1,20 @@
+package ports
+
+import (
+	"context"
+
+	"user-manager-api/internal/domain/user"
+	"user-manager-api/internal/domain/user_file"
+)
+
+// UploadPolicyService - the upload policies of the organizations(email
+// domains), checked by UserFileService.CreateUserFile
+type UploadPolicyService interface {
+	Policies(ctx context.Context) (user_file.UploadPolicies, error)
+	SetPolicy(ctx context.Context, p user_file.UploadPolicy) (*user_file.UploadPolicy, error)
+	// RemovePolicy - false if the organization had no policy
+	RemovePolicy(ctx context.Context, org string) (bool, error)
+	// UserPolicy - the policy of the organization of the user, nil if it has
+	// none. Cached: the changes reach the other instances within the TTL
+	UserPolicy(ctx context.Context, userUUID user.UUID) (*user_file.UploadPolicy, error)
+}
@@ -0,0 +1,22 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"user-manager-api/internal/domain/usage"
+)
+
+// UsageService - requests per organization(email domain) and role for the
+// billing and the capacity planning
+type UsageService interface {
+	// Record never blocks the request: userID is empty for the requests
+	// without a token, a request over the queue is dropped
+	Record(userID, role string, duration time.Duration)
+	// Worker adds the recorded requests to the metrics and the rollups every
+	// flush interval, so they lag behind by up to the interval
+	Worker(ctx context.Context)
+	// Totals - of the rollups of [from, to] UTC days, of one organization if
+	// org is not empty
+	Totals(ctx context.Context, from, to time.Time, org string) ([]usage.Total, error)
+}
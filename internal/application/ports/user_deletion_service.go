@@ -0,0 +1,18 @@
+package ports
+
+import (
+	"context"
+
+	"user-manager-api/internal/domain/user"
+)
+
+// UserDeletionService - the deletion of a user as a saga of stored steps: the
+// objects of their files, the files, the user and its event
+type UserDeletionService interface {
+	// Delete runs a new saga to its end. A transient failure leaves it running
+	// for Resume, a permanent one(the legal hold, the last admin) compensates it.
+	// deletion.ErrInProgress if the user has a running saga
+	Delete(ctx context.Context, actor, userUUID user.UUID, reason user.DeletionReason) error
+	// Resume continues the interrupted sagas, returns the count completed
+	Resume(ctx context.Context) (int, error)
+}
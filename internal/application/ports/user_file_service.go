@@ -4,12 +4,31 @@ import (
 	"context"
 	"mime/multipart"
 
+	"github.com/google/uuid"
+
+	"user-manager-api/internal/domain/pagination"
 	"user-manager-api/internal/domain/user"
 	"user-manager-api/internal/domain/user_file"
 )
 
 type UserFileService interface {
-	FindUserFiles(ctx context.Context, userUUID user.UUID, page int) (user_file.UserFiles, error)
-	CreateUserFile(ctx context.Context, userUUID user.UUID, in *multipart.FileHeader) (*user_file.UserFile, error)
-	DeleteUserFiles(ctx context.Context, userUUID user.UUID) error
+	// StreamUserFiles - of folder only if it is not nil. fn must not keep the
+	// file, it is reused for the next one
+	StreamUserFiles(ctx context.Context, userUUID user.UUID, p pagination.Params, tags []string, folder *string, fn func(uf *user_file.UserFile) error) error
+	CreateUserFile(ctx context.Context, userUUID user.UUID, in *multipart.FileHeader, tags []string, folder string) (*user_file.UserFile, error)
+	// DeleteUserFiles - the files of the user with all the tags and their objects
+	DeleteUserFiles(ctx context.Context, userUUID user.UUID, tags []string) error
+	// GetFileText - the extracted text of a file, ErrTextNotFound if there is none(yet)
+	GetFileText(ctx context.Context, userUUID user.UUID, fileUUID uuid.UUID) (*user_file.Text, error)
+	// SearchUserFiles - the files whose text matches query, best first
+	SearchUserFiles(ctx context.Context, userUUID user.UUID, query string, limit int) (user_file.UserFiles, error)
+	ListFolders(ctx context.Context, userUUID user.UUID, parent string) (user_file.Folders, error)
+	// MoveUserFile - a nil folder or fileName is kept, the storage object stays
+	MoveUserFile(ctx context.Context, userUUID user.UUID, fileUUID uuid.UUID, folder, fileName *string) (*user_file.UserFile, error)
+	// MoveFolder - the count of the files moved
+	MoveFolder(ctx context.Context, userUUID user.UUID, from, to string) (int64, error)
+	// ObjectOwner - the owner of the live file of a storage key(the object or
+	// its thumbnail) and the type to serve it with, ErrUserFileNotFound if
+	// there is none
+	ObjectOwner(ctx context.Context, key string) (*user_file.ObjectOwner, error)
 }
@@ -0,0 +1,15 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"user-manager-api/internal/domain/user"
+)
+
+type UserHistoryService interface {
+	// History - the revisions of the user(deleted too) oldest first, the current one last
+	History(ctx context.Context, userUUID user.UUID) ([]user.Revision, error)
+	// VersionAt - the user as it was at at
+	VersionAt(ctx context.Context, userUUID user.UUID, at time.Time) (*user.Version, error)
+}
@@ -0,0 +1,16 @@
+package ports
+
+import (
+	"context"
+
+	"user-manager-api/internal/domain/user"
+)
+
+// UserLookupService resolves the internal ID of a user(the DB, the logs) to its
+// public UUID and back, for the support investigations. Every lookup is audited
+type UserLookupService interface {
+	// LookupByID - deleted users too, ErrUserNotFound if unknown
+	LookupByID(ctx context.Context, actor user.UUID, id user.ID) (*user.Identity, error)
+	// LookupByUUID - deleted users too, ErrUserNotFound if unknown
+	LookupByUUID(ctx context.Context, actor, userUUID user.UUID) (*user.Identity, error)
+}
@@ -0,0 +1,17 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"user-manager-api/internal/domain/pagination"
+	"user-manager-api/internal/domain/user"
+	"user-manager-api/internal/domain/user_note"
+)
+
+type UserNoteService interface {
+	FindNotes(ctx context.Context, userUUID user.UUID, p pagination.Params) (user_note.Notes, error)
+	CreateNote(ctx context.Context, userUUID user.UUID, n user_note.Note) (*user_note.Note, error)
+	DeleteNote(ctx context.Context, userUUID user.UUID, noteUUID uuid.UUID) error
+}
@@ -3,14 +3,33 @@ package ports
 import (
 	"context"
 
+	"user-manager-api/internal/domain/pagination"
 	"user-manager-api/internal/domain/user"
 )
 
-type UserService interface {
-	FindUserByID(ctx context.Context, uuid user.UUID) (*user.User, error)
-	FindByEmail(ctx context.Context, email string) (*user.User, error)
-	FindUsers(ctx context.Context, page int) (user.Users, error)
+// UserCommands - the changes of the users and their pre-checks, always on the
+// primary: a command never reads through the queries
+type UserCommands interface {
 	CreateUser(ctx context.Context, u user.User) (*user.User, error)
+	// CheckEmailAvailable - the uniqueness pre-checks of CreateUser, nothing is stored
+	CheckEmailAvailable(ctx context.Context, email string) error
+	// CheckAge - the minimum-age policy of CreateUser and UpdateUser, *user.UnderageError
+	CheckAge(u user.User) error
 	UpdateUser(ctx context.Context, u user.User) (*user.User, error)
-	DeleteUser(ctx context.Context, uuid user.UUID) error
+	// DeleteUser - actor is recorded as deleted_by, the own account needs confirmSelf
+	DeleteUser(ctx context.Context, actor, uuid user.UUID, reason user.DeletionReason, confirmSelf bool) error
+	ConfirmEmailChange(ctx context.Context, token string) (*user.User, error)
+	// EmitBirthdays - the count of the UserBirthday events published
+	EmitBirthdays(ctx context.Context) (int, error)
+}
+
+// UserQueries - the reads of the users, free to be served by a cache, a replica
+// or a read model without touching the commands
+type UserQueries interface {
+	FindUserByID(ctx context.Context, uuid user.UUID) (*user.User, error)
+	FindByEmail(ctx context.Context, email string) (*user.User, error)
+	// StreamUsers - fn must not keep the user, it is reused for the next one
+	StreamUsers(ctx context.Context, p pagination.Params, fn func(u *user.User) error) error
+	// FindDeletedUsers - reason "" - any reason
+	FindDeletedUsers(ctx context.Context, reason user.DeletionReason, p pagination.Params) (user.Users, error)
 }
@@ -0,0 +1,8 @@
+package ports
+
+import "context"
+
+// WebhookSender - posts the JSON body to an endpoint of a user
+type WebhookSender interface {
+	Send(ctx context.Context, url string, body []byte) error
+}
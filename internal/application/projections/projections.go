@@ -0,0 +1,86 @@
+// Package projections defines the read models of the event store: how every
+// event changes the summary, the files and the daily activity of its user.
+// The fold is pure, so the read models are rebuilt by replaying the stream
+// whenever it changes("usermanager rebuild-projections").
+package projections
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+
+	"user-manager-api/internal/domain/projection"
+	"user-manager-api/internal/infrastructure/mq"
+)
+
+// secretMeta - not stored: the pending email, the one-time tokens of the email
+// change and the invitation
+var secretMeta = map[string]bool{
+	"new_email":     true,
+	"confirm_token": true,
+	"invite_token":  true,
+	"invite_url":    true,
+}
+
+// FromEvent - e as it is stored, the events of no user(nil or invalid user_id)
+// are stored with the nil UUID
+func FromEvent(e mq.Event) projection.Event {
+	userUUID, err := uuid.Parse(e.UserID)
+	if err != nil {
+		userUUID = uuid.Nil
+	}
+
+	meta := make(map[string]string, len(e.Meta))
+	for k, v := range e.Meta {
+		if !secretMeta[k] {
+			meta[k] = v
+		}
+	}
+
+	return projection.Event{
+		ID:         e.Id,
+		Type:       e.Method,
+		UserUUID:   userUUID,
+		OccurredAt: e.TS,
+		Meta:       meta,
+	}
+}
+
+// Fold - the change of e, every event of a user counts to its activity
+func Fold(e projection.Event) projection.Delta {
+	var d projection.Delta
+	at := e.OccurredAt
+
+	switch e.Type {
+	case http.MethodPost:
+		d.SignedUpAt = &at
+		if t, err := time.Parse(time.RFC3339Nano, e.Meta["created_at"]); err == nil {
+			d.SignedUpAt = &t
+		}
+	case http.MethodPut:
+		d.UpdatedAt = &at
+	case mq.EventEmailChangeConfirmed:
+		d.UpdatedAt = &at
+		d.EmailChanges = 1
+	case http.MethodDelete:
+		// the files of a deleted user are deleted with it
+		d.DeletedAt = &at
+		d.DeletedReason = e.Meta["deleted_reason"]
+		if winner, err := uuid.Parse(e.Meta["merged_into"]); err == nil {
+			d.MergedInto = &winner
+		}
+		d.FilesReset = true
+	case mq.EventUserFilesChanged:
+		// the events published before the delta change only the changes count
+		d.FilesDelta, _ = strconv.ParseInt(e.Meta[mq.MetaFilesDelta], 10, 64)
+		d.FileChanges = 1
+	case mq.EventLoginSucceeded:
+		d.Logins = 1
+	case mq.EventLoginFailed:
+		d.FailedLogins = 1
+	}
+
+	return d
+}
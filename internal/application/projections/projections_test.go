@@ -0,0 +1,79 @@
+package projections
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"user-manager-api/internal/domain/projection"
+	"user-manager-api/internal/infrastructure/mq"
+)
+
+func TestFromEvent(t *testing.T) {
+	id, userUUID := uuid.New(), uuid.New()
+	at := time.Date(2026, 10, 15, 9, 0, 0, 0, time.UTC)
+
+	got := FromEvent(mq.Event{
+		Id:     id,
+		TS:     at,
+		Method: mq.EventEmailChangeRequested,
+		UserID: userUUID.String(),
+		Meta:   map[string]string{"new_email": "new@example.com", "confirm_token": "secret", "source": "profile"},
+	})
+	assert.Equal(t, projection.Event{
+		ID:         id,
+		Type:       mq.EventEmailChangeRequested,
+		UserUUID:   userUUID,
+		OccurredAt: at,
+		Meta:       map[string]string{"source": "profile"},
+	}, got)
+
+	got = FromEvent(mq.Event{
+		Id:     id,
+		Method: mq.EventInvitationCreated,
+		UserID: userUUID.String(),
+		Meta:   map[string]string{"email": "a@example.com", "invite_token": "secret", "invite_url": "https://app/?token=secret"},
+	})
+	assert.Equal(t, map[string]string{"email": "a@example.com"}, got.Meta)
+
+	got = FromEvent(mq.Event{Id: id, Method: mq.EventLoginFailed, UserID: "unknown"})
+	assert.False(t, got.Touches())
+}
+
+func TestFold_Table(t *testing.T) {
+	at := time.Date(2026, 10, 15, 9, 0, 0, 0, time.UTC)
+	created := time.Date(2026, 10, 14, 23, 59, 0, 0, time.UTC)
+	winner := uuid.New()
+
+	cases := []struct {
+		name string
+		typ  string
+		meta map[string]string
+		want projection.Delta
+	}{
+		{"signup", http.MethodPost, map[string]string{"created_at": created.Format(time.RFC3339Nano)}, projection.Delta{SignedUpAt: &created}},
+		{"signup without created_at", http.MethodPost, nil, projection.Delta{SignedUpAt: &at}},
+		{"update", http.MethodPut, nil, projection.Delta{UpdatedAt: &at}},
+		{"email confirmed", mq.EventEmailChangeConfirmed, nil, projection.Delta{UpdatedAt: &at, EmailChanges: 1}},
+		{
+			"merged",
+			http.MethodDelete,
+			map[string]string{"deleted_reason": "merged", "merged_into": winner.String()},
+			projection.Delta{DeletedAt: &at, DeletedReason: "merged", MergedInto: &winner, FilesReset: true},
+		},
+		{"files deleted", mq.EventUserFilesChanged, map[string]string{mq.MetaFilesDelta: "-3"}, projection.Delta{FilesDelta: -3, FileChanges: 1}},
+		{"files changed without the delta", mq.EventUserFilesChanged, nil, projection.Delta{FileChanges: 1}},
+		{"login", mq.EventLoginSucceeded, nil, projection.Delta{Logins: 1}},
+		{"failed login", mq.EventLoginFailed, map[string]string{"reason": "invalid_credentials"}, projection.Delta{FailedLogins: 1}},
+		{"activity only", mq.EventUserBirthday, nil, projection.Delta{}},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Fold(projection.Event{Type: tt.typ, UserUUID: uuid.New(), OccurredAt: at, Meta: tt.meta}))
+		})
+	}
+}
@@ -0,0 +1,25 @@
+package services
+
+import (
+	"context"
+
+	"user-manager-api/internal/application/ports"
+	"user-manager-api/internal/domain/pagination"
+	domain "user-manager-api/internal/domain/user_file"
+)
+
+type AdminFileService struct {
+	userFileRepository domain.Repository
+}
+
+func NewAdminFileService(userFileRepository domain.Repository) ports.AdminFileService {
+	return &AdminFileService{userFileRepository: userFileRepository}
+}
+
+func (afs *AdminFileService) FindFiles(ctx context.Context, f domain.Filter, p pagination.Params) (domain.UserFiles, error) {
+	return afs.userFileRepository.FetchFiles(ctx, f, p)
+}
+
+func (afs *AdminFileService) FileStats(ctx context.Context, f domain.Filter) (*domain.Stats, error) {
+	return afs.userFileRepository.FetchStats(ctx, f)
+}
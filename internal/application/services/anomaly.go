@@ -0,0 +1,164 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+
+	"user-manager-api/internal/application/ports"
+	"user-manager-api/internal/domain/audit"
+	"user-manager-api/internal/domain/login"
+	"user-manager-api/internal/domain/user"
+	"user-manager-api/internal/infrastructure/mq"
+)
+
+type (
+	AnomalySettings struct {
+		Rules login.Rules
+		// ForceReauth - a flagged login revokes the tokens of the user, its own included
+		ForceReauth bool
+		// AlertURL - the webhook of the security alerts, empty - no alerts
+		AlertURL string
+	}
+	// AnomalyService runs the rules over the consumed login events
+	AnomalyService struct {
+		repository     login.Repository
+		userRepository user.Repository
+		auditService   ports.AuditService
+		webhook        ports.WebhookSender
+		settings       AnomalySettings
+		logger         *zap.Logger
+		mCounter       *prometheus.CounterVec
+	}
+	alertPayload struct {
+		Alert     string       `json:"alert"`
+		UserID    string       `json:"user_id"`
+		Flags     []login.Flag `json:"flags"`
+		IP        string       `json:"ip"`
+		UserAgent string       `json:"user_agent"`
+		Country   string       `json:"country,omitempty"`
+		Reauth    bool         `json:"reauth_forced"`
+		TS        time.Time    `json:"time_stamp"`
+	}
+)
+
+func NewAnomalyService(
+	repository login.Repository,
+	userRepository user.Repository,
+	auditService ports.AuditService,
+	webhook ports.WebhookSender,
+	logger *zap.Logger,
+	mCounter *prometheus.CounterVec,
+	settings AnomalySettings,
+) ports.AnomalyService {
+	return &AnomalyService{
+		repository:     repository,
+		userRepository: userRepository,
+		auditService:   auditService,
+		webhook:        webhook,
+		settings:       settings,
+		logger:         logger,
+		mCounter:       mCounter,
+	}
+}
+
+// CheckLogin - the login is stored after the actions: a redelivery of the event
+// failed on one of them repeats the check. The alert failures are logged only,
+// the flag is in the audit log anyway.
+func (as *AnomalyService) CheckLogin(ctx context.Context, e mq.Event) error {
+	if e.Method != mq.EventLoginSucceeded {
+		return nil
+	}
+	userUUID, err := uuid.Parse(e.UserID)
+	if err != nil {
+		return fmt.Errorf("event %s: invalid user_id: %w", e.Id, err)
+	}
+
+	l := login.Login{
+		UserUUID: userUUID,
+		Client: login.Client{
+			IP:        e.Meta[MetaIP],
+			UserAgent: e.Meta[MetaUserAgent],
+			Country:   e.Meta[MetaCountry],
+		},
+		CreatedAt: e.TS,
+	}
+	history, err := as.repository.FetchLogins(ctx, userUUID)
+	if err != nil {
+		return err
+	}
+	l.Flags = as.settings.Rules.Evaluate(l, history)
+	if len(l.Flags) > 0 {
+		if err = as.flag(ctx, l); err != nil {
+			return err
+		}
+	}
+
+	return as.repository.CreateLogin(ctx, l)
+}
+
+// flag - audited first: the flagged accounts are found in the audit log
+func (as *AnomalyService) flag(ctx context.Context, l login.Login) error {
+	userUUID := l.UserUUID
+
+	for _, f := range l.Flags {
+		as.mCounter.WithLabelValues("login_flagged_" + string(f) + "_total").Inc()
+	}
+	if err := as.auditService.Record(ctx, audit.Entry{
+		ActorUUID:  uuid.Nil,
+		Action:     audit.ActionLoginFlagged,
+		TargetUUID: &userUUID,
+		Details: map[string]any{
+			"flags":      l.Flags,
+			"ip":         l.IP,
+			"user_agent": l.UserAgent,
+			"country":    l.Country,
+		},
+	}); err != nil {
+		return err
+	}
+
+	if as.settings.ForceReauth {
+		// iat has a second precision: the token of the login itself is revoked as well
+		if _, err := as.userRepository.RevokeTokens(ctx, userUUID, l.CreatedAt.Truncate(time.Second).Add(time.Second)); err != nil {
+			return err
+		}
+		as.mCounter.WithLabelValues("login_reauth_forced_total").Inc()
+	}
+
+	as.alert(ctx, l)
+
+	return nil
+}
+
+func (as *AnomalyService) alert(ctx context.Context, l login.Login) {
+	if as.settings.AlertURL == "" {
+		return
+	}
+
+	body, err := json.Marshal(alertPayload{
+		Alert:     string(audit.ActionLoginFlagged),
+		UserID:    l.UserUUID.String(),
+		Flags:     l.Flags,
+		IP:        l.IP,
+		UserAgent: l.UserAgent,
+		Country:   l.Country,
+		Reauth:    as.settings.ForceReauth,
+		TS:        l.CreatedAt,
+	})
+	if err == nil {
+		err = as.webhook.Send(ctx, as.settings.AlertURL, body)
+	}
+	if err != nil {
+		as.logger.Warn("anomaly alert failed", zap.Error(err), zap.Stringer("user_uuid", l.UserUUID))
+		as.mCounter.WithLabelValues("anomaly_alert_failed_total").Inc()
+		return
+	}
+
+	as.mCounter.WithLabelValues("anomaly_alert_sent_total").Inc()
+}
@@ -0,0 +1,73 @@
+package services
+
+import (
+	"context"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+
+	"user-manager-api/internal/application/ports"
+	"user-manager-api/internal/domain/audit"
+	"user-manager-api/internal/domain/pagination"
+)
+
+type AuditService struct {
+	auditRepository audit.Repository
+	logger          *zap.Logger
+	mCounter        *prometheus.CounterVec
+	mEntries        *prometheus.CounterVec
+}
+
+func NewAuditService(
+	auditRepository audit.Repository,
+	logger *zap.Logger,
+	mCounter *prometheus.CounterVec,
+	mEntries *prometheus.CounterVec,
+) ports.AuditService {
+	return &AuditService{
+		auditRepository: auditRepository,
+		logger:          logger,
+		mCounter:        mCounter,
+		mEntries:        mEntries,
+	}
+}
+
+// Record persists the entry and mirrors it to the log, so the trail survives
+// even when the DB write fails.
+func (as *AuditService) Record(ctx context.Context, e audit.Entry) error {
+	as.log(e)
+
+	if err := as.auditRepository.CreateEntry(ctx, e); err != nil {
+		as.mCounter.WithLabelValues("audit_failed_total").Inc()
+		return err
+	}
+	as.count(e)
+
+	return nil
+}
+
+func (as *AuditService) Recorded(e audit.Entry) {
+	as.log(e)
+	as.count(e)
+}
+
+func (as *AuditService) log(e audit.Entry) {
+	fields := []zap.Field{
+		zap.Stringer("actor_uuid", e.ActorUUID),
+		zap.String("action", string(e.Action)),
+		zap.Any("details", e.Details),
+	}
+	if e.TargetUUID != nil {
+		fields = append(fields, zap.Stringer("target_uuid", e.TargetUUID))
+	}
+	as.logger.Info("audit", fields...)
+}
+
+func (as *AuditService) count(e audit.Entry) {
+	as.mCounter.WithLabelValues("audit_recorded_total").Inc()
+	as.mEntries.WithLabelValues(string(e.Action)).Inc()
+}
+
+func (as *AuditService) FindEntries(ctx context.Context, f audit.Filter, p pagination.Params) ([]audit.Entry, error) {
+	return as.auditRepository.FetchEntries(ctx, f, p)
+}
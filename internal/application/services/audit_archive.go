@@ -0,0 +1,146 @@
+package services
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+
+	"user-manager-api/internal/application/ports"
+	"user-manager-api/internal/domain/audit"
+)
+
+const (
+	auditArchivePrefix = "audit-log/"
+	// auditArchiveBatch - the entries of one archive object
+	auditArchiveBatch = 10000
+)
+
+var ErrAuditRetentionOff = errors.New("audit log retention is disabled(RETENTION_AUDIT_DAYS)")
+
+// auditCSVHeader - the columns of the archived entries, details is JSON
+var auditCSVHeader = []string{"id", "created_at", "actor_uuid", "action", "target_uuid", "details"}
+
+type (
+	AuditArchiveSettings struct {
+		// Retention - the entries are kept in the DB for so long, 0 disables the archive
+		Retention time.Duration
+	}
+	AuditArchiveService struct {
+		repository audit.Repository
+		storage    ports.BackupStorage
+		logger     *zap.Logger
+		mCounter   *prometheus.CounterVec
+		retention  time.Duration
+	}
+)
+
+func NewAuditArchiveService(
+	repository audit.Repository,
+	storage ports.BackupStorage,
+	logger *zap.Logger,
+	mCounter *prometheus.CounterVec,
+	settings AuditArchiveSettings,
+) ports.AuditArchiveService {
+	return &AuditArchiveService{
+		repository: repository,
+		storage:    storage,
+		logger:     logger,
+		mCounter:   mCounter,
+		retention:  settings.Retention,
+	}
+}
+
+// Archive uploads the expired entries oldest first, a batch per gzipped CSV
+// object, and deletes a batch once it is uploaded. The key is made of the
+// batch ids: a run interrupted between the upload and the delete uploads the
+// same batch to the same key again.
+func (aas *AuditArchiveService) Archive(ctx context.Context, now time.Time) (int, error) {
+	if aas.retention == 0 {
+		return 0, ErrAuditRetentionOff
+	}
+	before := now.Add(-aas.retention)
+
+	var archived int
+	for {
+		entries, err := aas.repository.FetchExpired(ctx, before, auditArchiveBatch)
+		if err != nil {
+			return archived, err
+		}
+		if len(entries) == 0 {
+			return archived, nil
+		}
+
+		body, err := encodeAuditCSV(entries)
+		if err != nil {
+			return archived, err
+		}
+		first, last := entries[0], entries[len(entries)-1]
+		key := fmt.Sprintf("%s%s/%d-%d.csv.gz", auditArchivePrefix, first.CreatedAt.UTC().Format("2006/01/02"), first.ID, last.ID)
+		if err = aas.storage.PutObject(ctx, key, "application/gzip", bytes.NewReader(body), int64(len(body))); err != nil {
+			return archived, err
+		}
+
+		ids := make([]int64, len(entries))
+		for i, e := range entries {
+			ids[i] = e.ID
+		}
+		deleted, err := aas.repository.DeleteEntries(ctx, ids)
+		if err != nil {
+			return archived, err
+		}
+		archived += int(deleted)
+		aas.mCounter.WithLabelValues("audit_archive_uploaded_total").Inc()
+		aas.mCounter.WithLabelValues("audit_archived_total").Add(float64(deleted))
+		aas.logger.Info("audit entries archived", zap.String("key", key), zap.Int64("archived_count", deleted))
+	}
+}
+
+func encodeAuditCSV(entries []audit.Entry) ([]byte, error) {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	w := csv.NewWriter(zw)
+	if err := w.Write(auditCSVHeader); err != nil {
+		return nil, err
+	}
+	for _, e := range entries {
+		details := []byte("{}")
+		if e.Details != nil {
+			var err error
+			if details, err = json.Marshal(e.Details); err != nil {
+				return nil, err
+			}
+		}
+		target := ""
+		if e.TargetUUID != nil {
+			target = e.TargetUUID.String()
+		}
+		if err := w.Write([]string{
+			strconv.FormatInt(e.ID, 10),
+			e.CreatedAt.UTC().Format(time.RFC3339Nano),
+			e.ActorUUID.String(),
+			string(e.Action),
+			target,
+			string(details),
+		}); err != nil {
+			return nil, err
+		}
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
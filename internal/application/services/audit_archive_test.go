@@ -0,0 +1,38 @@
+package services
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/csv"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"user-manager-api/internal/domain/audit"
+)
+
+func TestEncodeAuditCSV(t *testing.T) {
+	actor := uuid.MustParse("6f1c2b1e-8d7a-4c1e-9f3a-2b5d7e9a1c3f")
+	target := uuid.MustParse("8b0c3a1e-6f0e-4a52-9d3f-3c6a1d2b4e5f")
+	at := time.Date(2025, 10, 3, 10, 11, 12, 500, time.FixedZone("CET", 3600))
+
+	body, err := encodeAuditCSV([]audit.Entry{
+		{ID: 1, ActorUUID: actor, Action: audit.ActionUserMerged, TargetUUID: &target, Details: map[string]any{"note": `a "b", c`}, CreatedAt: at},
+		{ID: 2, ActorUUID: uuid.Nil, Action: audit.ActionPIIRedacted, CreatedAt: at},
+	})
+	require.NoError(t, err)
+
+	zr, err := gzip.NewReader(bytes.NewReader(body))
+	require.NoError(t, err)
+	rows, err := csv.NewReader(zr).ReadAll()
+	require.NoError(t, err)
+
+	assert.Equal(t, [][]string{
+		auditCSVHeader,
+		{"1", "2025-10-03T09:11:12.0000005Z", actor.String(), "user.merged", target.String(), `{"note":"a \"b\", c"}`},
+		{"2", "2025-10-03T09:11:12.0000005Z", uuid.Nil.String(), "retention.pii_redacted", "", "{}"},
+	}, rows)
+}
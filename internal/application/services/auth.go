@@ -1,43 +1,178 @@
 package services
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"strings"
 	"time"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+
 	"user-manager-api/internal/application/ports"
+	"user-manager-api/internal/domain/device"
+	"user-manager-api/internal/domain/login"
 	"user-manager-api/internal/domain/user"
-	"user-manager-api/internal/infrastructure/jwt"
+	"user-manager-api/internal/infrastructure/mq"
+)
 
-	"golang.org/x/crypto/bcrypt"
+// the meta of the login events
+const (
+	MetaIP        = "ip"
+	MetaUserAgent = "user_agent"
+	MetaCountry   = "country"
+	// MetaReason - of a failed login: invalid_credentials, password_reset_required,
+	// account_suspended or error
+	MetaReason = "reason"
 )
 
+// maxUserAgentLen - of the stored user agents, the longer ones are cut
+const maxUserAgentLen = 512
+
 var (
 	ErrInvalidCredentials    = errors.New("invalid credentials")
 	ErrFailedToGenerateToken = errors.New("failed to generate token")
+	ErrPasswordResetRequired = errors.New("password change required")
 )
 
 type AuthService struct {
-	jwtService *jwt.Service
+	tokenService      ports.TokenService
+	hasher            ports.PasswordHasher
+	userRepository    user.Repository
+	deviceRepository  device.Repository
+	suspensionService ports.SuspensionService
+	mq                ports.RabbitMQ
+	logger            *zap.Logger
+	mCounter          *prometheus.CounterVec
 }
 
 func NewAuthService(
-	jwtService *jwt.Service,
-
+	tokenService ports.TokenService,
+	hasher ports.PasswordHasher,
+	userRepository user.Repository,
+	deviceRepository device.Repository,
+	suspensionService ports.SuspensionService,
+	mq ports.RabbitMQ,
+	logger *zap.Logger,
+	mCounter *prometheus.CounterVec,
 ) ports.Auth {
 	return &AuthService{
-		jwtService: jwtService,
+		tokenService:      tokenService,
+		hasher:            hasher,
+		userRepository:    userRepository,
+		deviceRepository:  deviceRepository,
+		suspensionService: suspensionService,
+		mq:                mq,
+		logger:            logger,
+		mCounter:          mCounter,
 	}
 }
 
-func (as *AuthService) GenerateToken(u *user.User, requestPassword string) (string, error) {
-	err := bcrypt.CompareHashAndPassword([]byte(*u.PasswordHash), []byte(requestPassword))
+// GenerateToken accepts a nil u(unknown email): the answer and its timing must be
+// the same as for a wrong password, otherwise login reveals registered emails.
+func (as *AuthService) GenerateToken(
+	ctx context.Context,
+	u *user.User,
+	requestPassword string,
+	client login.Client,
+) (token string, err error) {
+	defer func() { as.publishLogin(ctx, u, client, err) }()
+
+	if u == nil || u.PasswordHash == nil {
+		as.hasher.DummyVerify(requestPassword)
+		return "", ErrInvalidCredentials
+	}
+	ok, needsRehash, err := as.hasher.Verify(requestPassword, *u.PasswordHash)
 	if err != nil {
+		as.logger.Error("password hash verify error", zap.Error(err), zap.Stringer("user_uuid", u.UUID))
 		return "", ErrInvalidCredentials
 	}
+	if !ok {
+		return "", ErrInvalidCredentials
+	}
+	// checked after the password to not disclose the flag
+	if u.PasswordResetRequired {
+		return "", ErrPasswordResetRequired
+	}
+	if err = as.suspensionService.CheckLogin(ctx, u); err != nil {
+		return "", err
+	}
+	if needsRehash {
+		as.rehash(ctx, u, requestPassword)
+	}
 
-	token, err := as.jwtService.GenerateJWT(u.UUID.String(), u.Role, time.Hour)
+	d, err := as.deviceRepository.TouchDevice(ctx, u.UUID, userAgent(client.UserAgent), client.IP)
+	if err != nil {
+		return "", fmt.Errorf("device: %w", err)
+	}
+	token, err = as.tokenService.GenerateDeviceToken(u.UUID.String(), u.Role, d.UUID.String(), time.Hour)
 	if err != nil {
 		return "", ErrFailedToGenerateToken
 	}
+	// drives the retention policy, a failure must not block the login
+	if err = as.userRepository.TouchLastSeen(ctx, u.UUID); err != nil {
+		as.logger.Warn("last seen update failed", zap.Error(err), zap.Stringer("user_uuid", u.UUID))
+	}
 
 	return token, nil
 }
+
+// rehash - the plain password is known only at login, so the hash parameters are
+// upgraded here. Failures must not block the login, the next one retries.
+func (as *AuthService) rehash(ctx context.Context, u *user.User, password string) {
+	hash, err := as.hasher.Hash(password)
+	if err == nil {
+		err = as.userRepository.UpdatePasswordHash(ctx, u.UUID, hash)
+	}
+	if err != nil {
+		as.logger.Warn("password rehash failed", zap.Error(err), zap.Stringer("user_uuid", u.UUID))
+		return
+	}
+
+	as.mCounter.WithLabelValues("password_rehashed_total").Inc()
+}
+
+// publishLogin - the attempts of the unknown emails go with the nil user UUID,
+// the email is not published
+func (as *AuthService) publishLogin(ctx context.Context, u *user.User, client login.Client, err error) {
+	e := mq.Event{
+		Id:     uuid.New(),
+		TS:     time.Now(),
+		Method: mq.EventLoginSucceeded,
+		UserID: uuid.Nil.String(),
+		Meta: map[string]string{
+			MetaIP:        client.IP,
+			MetaUserAgent: client.UserAgent,
+			MetaCountry:   client.Country,
+		},
+	}
+	if u != nil {
+		e.UserID = u.UUID.String()
+	}
+	if err != nil {
+		e.Method = mq.EventLoginFailed
+		switch {
+		case errors.Is(err, ErrInvalidCredentials):
+			e.Meta[MetaReason] = "invalid_credentials"
+		case errors.Is(err, ErrPasswordResetRequired):
+			e.Meta[MetaReason] = "password_reset_required"
+		case errors.Is(err, ErrAccountSuspended):
+			e.Meta[MetaReason] = "account_suspended"
+		default:
+			e.Meta[MetaReason] = "error"
+		}
+	}
+
+	publishEvent(ctx, as.mq, e)
+}
+
+// userAgent - cut to maxUserAgentLen, the invalid UTF-8 is replaced: the column is TEXT
+func userAgent(ua string) string {
+	if len(ua) > maxUserAgentLen {
+		ua = ua[:maxUserAgentLen]
+	}
+
+	return strings.ToValidUTF8(ua, "\uFFFD")
+}
@@ -0,0 +1,202 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+
+	"user-manager-api/internal/application/ports"
+	"user-manager-api/internal/domain/backup"
+	"user-manager-api/internal/infrastructure/archive"
+)
+
+const (
+	backupKeyPrefix = "backups/"
+	// backupImportBatch - the rows of one insert statement
+	backupImportBatch = 1000
+)
+
+var (
+	ErrBackupKeyMissing = errors.New("backup encryption key is not set")
+	// ErrRestoreNotEmpty - a restore never merges into existing data
+	ErrRestoreNotEmpty = errors.New("restore needs a database without users, files and audit entries")
+)
+
+type (
+	BackupSettings struct {
+		// EncryptionKey - AES-256, nil disables both the backup and the restore
+		EncryptionKey []byte
+	}
+	BackupService struct {
+		repository backup.Repository
+		storage    ports.BackupStorage
+		logger     *zap.Logger
+		mCounter   *prometheus.CounterVec
+		key        []byte
+	}
+)
+
+func NewBackupService(
+	repository backup.Repository,
+	storage ports.BackupStorage,
+	logger *zap.Logger,
+	mCounter *prometheus.CounterVec,
+	settings BackupSettings,
+) ports.BackupService {
+	return &BackupService{
+		repository: repository,
+		storage:    storage,
+		logger:     logger,
+		mCounter:   mCounter,
+		key:        settings.EncryptionKey,
+	}
+}
+
+// Backup writes the archive to a temp file first: the upload needs its size
+func (bs *BackupService) Backup(ctx context.Context, now time.Time) (string, backup.Counts, error) {
+	if len(bs.key) == 0 {
+		return "", nil, ErrBackupKeyMissing
+	}
+
+	tmp, err := os.CreateTemp("", "usermanager-backup-*")
+	if err != nil {
+		return "", nil, err
+	}
+	defer func() {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+	}()
+
+	aw, err := archive.NewWriter(tmp, bs.key, now)
+	if err != nil {
+		return "", nil, err
+	}
+	counts := make(backup.Counts, len(backup.Tables))
+	for _, table := range backup.Tables {
+		err = bs.repository.Export(ctx, table, func(row json.RawMessage) error {
+			counts[table]++
+			return aw.Write(backup.Row{Table: table, Data: row})
+		})
+		if err != nil {
+			return "", counts, fmt.Errorf("export %s: %w", table, err)
+		}
+	}
+	if err = aw.Close(); err != nil {
+		return "", counts, err
+	}
+
+	size, err := tmp.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return "", counts, err
+	}
+	if _, err = tmp.Seek(0, io.SeekStart); err != nil {
+		return "", counts, err
+	}
+	key := backupKeyPrefix + now.UTC().Format("2006-01-02T15-04-05Z") + ".umbak"
+	if err = bs.storage.PutObject(ctx, key, "application/octet-stream", tmp, size); err != nil {
+		return "", counts, err
+	}
+
+	bs.mCounter.WithLabelValues("backup_created_total").Inc()
+
+	return key, counts, nil
+}
+
+// Restore inserts the rows in batches, in the archive order(backup.Tables):
+// the files follow their users
+func (bs *BackupService) Restore(ctx context.Context, key string) (backup.Counts, error) {
+	if len(bs.key) == 0 {
+		return nil, ErrBackupKeyMissing
+	}
+
+	empty, err := bs.repository.IsEmpty(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !empty {
+		return nil, ErrRestoreNotEmpty
+	}
+
+	body, err := bs.storage.GetObject(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = body.Close() }()
+
+	ar, header, err := archive.NewReader(body, bs.key)
+	if err != nil {
+		return nil, err
+	}
+	bs.logger.Info("restoring backup", zap.String("key", key), zap.Time("created_at", header.CreatedAt))
+
+	var (
+		counts    = make(backup.Counts, len(backup.Tables))
+		table     string
+		batch     []json.RawMessage
+		deletedBy = make(map[int64]int64)
+	)
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		if err := bs.repository.Import(ctx, table, batch); err != nil {
+			return fmt.Errorf("import %s: %w", table, err)
+		}
+		counts[table] += int64(len(batch))
+		batch = batch[:0]
+		return nil
+	}
+	for {
+		row, err := ar.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return counts, err
+		}
+		if !slices.Contains(backup.Tables, row.Table) {
+			return counts, fmt.Errorf("%w: table %q", archive.ErrMalformed, row.Table)
+		}
+		if row.Table != table || len(batch) == backupImportBatch {
+			if err = flush(); err != nil {
+				return counts, err
+			}
+			table = row.Table
+		}
+		if table == backup.TableUsers {
+			var ref struct {
+				ID        int64  `json:"id"`
+				DeletedBy *int64 `json:"deleted_by"`
+			}
+			if err = json.Unmarshal(row.Data, &ref); err != nil {
+				return counts, fmt.Errorf("%w: %v", archive.ErrMalformed, err)
+			}
+			if ref.DeletedBy != nil {
+				deletedBy[ref.ID] = *ref.DeletedBy
+			}
+		}
+		batch = append(batch, row.Data)
+	}
+	if err = flush(); err != nil {
+		return counts, err
+	}
+
+	if err = bs.repository.LinkDeletedBy(ctx, deletedBy); err != nil {
+		return counts, err
+	}
+	if err = bs.repository.ResetSequences(ctx); err != nil {
+		return counts, err
+	}
+
+	bs.mCounter.WithLabelValues("backup_restored_total").Inc()
+
+	return counts, nil
+}
@@ -0,0 +1,44 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"user-manager-api/internal/application/ports"
+	"user-manager-api/internal/domain/usage"
+)
+
+type BillingService struct {
+	usageRepository usage.Repository
+	mCounter        *prometheus.CounterVec
+}
+
+func NewBillingService(usageRepository usage.Repository, mCounter *prometheus.CounterVec) ports.BillingService {
+	return &BillingService{
+		usageRepository: usageRepository,
+		mCounter:        mCounter,
+	}
+}
+
+func (bs *BillingService) AggregateMonthly(ctx context.Context, now time.Time) (int64, error) {
+	y, m, _ := now.UTC().Date()
+	current := time.Date(y, m, 1, 0, 0, 0, 0, time.UTC)
+
+	var total int64
+	for _, month := range []time.Time{current.AddDate(0, -1, 0), current} {
+		n, err := bs.usageRepository.RefreshMonthly(ctx, month)
+		if err != nil {
+			return total, err
+		}
+		total += n
+	}
+	bs.mCounter.WithLabelValues("usage_monthly_aggregated_total").Add(float64(total))
+
+	return total, nil
+}
+
+func (bs *BillingService) Monthly(ctx context.Context, from, to time.Time) ([]usage.Monthly, error) {
+	return bs.usageRepository.FetchMonthly(ctx, from, to)
+}
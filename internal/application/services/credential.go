@@ -0,0 +1,148 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"user-manager-api/internal/application/ports"
+	"user-manager-api/internal/domain/audit"
+	"user-manager-api/internal/domain/token"
+	domain "user-manager-api/internal/domain/user"
+	"user-manager-api/internal/infrastructure/mq"
+	"user-manager-api/internal/interface/api/rest/dto/user"
+)
+
+var ErrSamePassword = errors.New("new password must differ from the current one")
+
+type CredentialService struct {
+	tokenService      ports.TokenService
+	hasher            ports.PasswordHasher
+	userRepository    domain.Repository
+	auditService      ports.AuditService
+	suspensionService ports.SuspensionService
+	mq                ports.RabbitMQ
+	mCounter          *prometheus.CounterVec
+}
+
+func NewCredentialService(
+	tokenService ports.TokenService,
+	hasher ports.PasswordHasher,
+	userRepository domain.Repository,
+	auditService ports.AuditService,
+	suspensionService ports.SuspensionService,
+	mq ports.RabbitMQ,
+	mCounter *prometheus.CounterVec,
+) ports.CredentialService {
+	return &CredentialService{
+		tokenService:      tokenService,
+		hasher:            hasher,
+		userRepository:    userRepository,
+		auditService:      auditService,
+		suspensionService: suspensionService,
+		mq:                mq,
+		mCounter:          mCounter,
+	}
+}
+
+func (cs *CredentialService) ForcePasswordReset(ctx context.Context, actor, target domain.UUID) error {
+	found, err := cs.userRepository.ForcePasswordReset(ctx, target)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return ErrUserNotFound
+	}
+
+	if err = cs.auditService.Record(ctx, audit.Entry{
+		ActorUUID:  actor,
+		Action:     audit.ActionPasswordResetForced,
+		TargetUUID: &target,
+	}); err != nil {
+		return err
+	}
+
+	// the user is told by email, the payload carries the address
+	// deleted meanwhile: nobody to tell
+	u, err := cs.userRepository.FetchUserByID(ctx, target)
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+	case err != nil:
+		return err
+	default:
+		publishEvent(ctx, cs.mq, mq.Event{
+			Id:      uuid.New(),
+			TS:      time.Now(),
+			Method:  mq.EventPasswordResetForced,
+			UserID:  u.UUID.String(),
+			Payload: user.ToResponseUser(*u),
+		})
+	}
+
+	cs.mCounter.WithLabelValues("password_reset_forced_total").Inc()
+
+	return nil
+}
+
+func (cs *CredentialService) ChangePassword(ctx context.Context, email, password, newPassword string) (string, error) {
+	u, err := cs.userRepository.FetchUserByEmail(ctx, email)
+	if err != nil && !errors.Is(err, domain.ErrNotFound) {
+		return "", err
+	}
+	if u == nil || u.PasswordHash == nil {
+		cs.hasher.DummyVerify(password)
+		return "", ErrInvalidCredentials
+	}
+	if ok, _, err := cs.hasher.Verify(password, *u.PasswordHash); err != nil || !ok {
+		return "", ErrInvalidCredentials
+	}
+	// issues a token as a login does
+	if err = cs.suspensionService.CheckLogin(ctx, u); err != nil {
+		return "", err
+	}
+	if password == newPassword {
+		return "", ErrSamePassword
+	}
+
+	hash, err := cs.hasher.Hash(newPassword)
+	if err != nil {
+		return "", err
+	}
+	if err = cs.userRepository.UpdatePassword(ctx, u.UUID, hash); err != nil {
+		return "", err
+	}
+
+	cs.mCounter.WithLabelValues("password_changed_total").Inc()
+
+	token, err := cs.tokenService.GenerateToken(u.UUID.String(), u.Role, time.Hour)
+	if err != nil {
+		return "", ErrFailedToGenerateToken
+	}
+
+	return token, nil
+}
+
+// IsTokenRevoked - "iat" has a second precision, so tokens issued within the
+// second of the revocation stay valid: the token returned by ChangePassword must.
+func (cs *CredentialService) IsTokenRevoked(ctx context.Context, claims *token.Claims) (bool, error) {
+	id, err := uuid.Parse(claims.UserID)
+	if err != nil {
+		return true, nil
+	}
+
+	validAfter, err := cs.userRepository.FetchTokensValidAfter(ctx, id)
+	if err != nil {
+		return false, err
+	}
+	if validAfter == nil {
+		return false, nil
+	}
+	if claims.IssuedAt == nil {
+		return true, nil
+	}
+
+	return claims.IssuedAt.Before(validAfter.Truncate(time.Second)), nil
+}
@@ -0,0 +1,69 @@
+package services
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+
+	"user-manager-api/internal/application/ports"
+	"user-manager-api/internal/domain/audit"
+	"user-manager-api/internal/domain/eventbus"
+)
+
+type DeadLetterService struct {
+	deadLetters  ports.MQDeadLetters
+	auditService ports.AuditService
+	logger       *zap.Logger
+	mCounter     *prometheus.CounterVec
+}
+
+func NewDeadLetterService(
+	deadLetters ports.MQDeadLetters,
+	auditService ports.AuditService,
+	logger *zap.Logger,
+	mCounter *prometheus.CounterVec,
+) ports.DeadLetterService {
+	return &DeadLetterService{
+		deadLetters:  deadLetters,
+		auditService: auditService,
+		logger:       logger,
+		mCounter:     mCounter,
+	}
+}
+
+func (dls *DeadLetterService) Peek(ctx context.Context, limit int) (*eventbus.DeadLetters, error) {
+	return dls.deadLetters.PeekDeadLetters(ctx, limit)
+}
+
+func (dls *DeadLetterService) Requeue(ctx context.Context, actor uuid.UUID, ids []string) ([]string, error) {
+	done, err := dls.deadLetters.RequeueDeadLetters(ctx, ids)
+	// the ones requeued before a failure are gone from the queue all the same
+	dls.record(ctx, actor, audit.ActionDeadLetterRequeued, "mq_dead_letters_requeued_total", done)
+
+	return done, err
+}
+
+func (dls *DeadLetterService) Discard(ctx context.Context, actor uuid.UUID, ids []string) ([]string, error) {
+	done, err := dls.deadLetters.DiscardDeadLetters(ctx, ids)
+	dls.record(ctx, actor, audit.ActionDeadLetterDiscarded, "mq_dead_letters_discarded_total", done)
+
+	return done, err
+}
+
+func (dls *DeadLetterService) record(ctx context.Context, actor uuid.UUID, action audit.Action, counter string, ids []string) {
+	if len(ids) == 0 {
+		return
+	}
+	dls.mCounter.WithLabelValues(counter).Add(float64(len(ids)))
+
+	// the messages are moved already, a failed entry is in the log(Record)
+	if err := dls.auditService.Record(ctx, audit.Entry{
+		ActorUUID: actor,
+		Action:    action,
+		Details:   map[string]any{"message_ids": ids},
+	}); err != nil {
+		dls.logger.Error("dead-letter audit error", zap.Error(err), zap.String("action", string(action)))
+	}
+}
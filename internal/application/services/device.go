@@ -0,0 +1,65 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"user-manager-api/internal/application/ports"
+	"user-manager-api/internal/domain/device"
+	"user-manager-api/internal/domain/token"
+	"user-manager-api/internal/domain/user"
+)
+
+var ErrDeviceNotFound = errors.New("device not found")
+
+type DeviceService struct {
+	deviceRepository device.Repository
+	mCounter         *prometheus.CounterVec
+}
+
+func NewDeviceService(deviceRepository device.Repository, mCounter *prometheus.CounterVec) ports.DeviceService {
+	return &DeviceService{
+		deviceRepository: deviceRepository,
+		mCounter:         mCounter,
+	}
+}
+
+func (ds *DeviceService) Devices(ctx context.Context, userUUID user.UUID) (device.Devices, error) {
+	return ds.deviceRepository.FetchDevices(ctx, userUUID)
+}
+
+func (ds *DeviceService) RevokeDevice(ctx context.Context, userUUID user.UUID, deviceUUID uuid.UUID) error {
+	deleted, err := ds.deviceRepository.DeleteDevice(ctx, userUUID, deviceUUID)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrDeviceNotFound
+	}
+
+	ds.mCounter.WithLabelValues("devices_revoked_total").Inc()
+
+	return nil
+}
+
+// IsTokenRevoked - a next login from the revoked device creates a new one, so
+// the tokens bound to the deleted one stay revoked
+func (ds *DeviceService) IsTokenRevoked(ctx context.Context, claims *token.Claims) (bool, error) {
+	if claims.DeviceID == "" {
+		return false, nil
+	}
+	id, err := uuid.Parse(claims.DeviceID)
+	if err != nil {
+		return true, nil
+	}
+
+	exists, err := ds.deviceRepository.DeviceExists(ctx, id)
+	if err != nil {
+		return false, err
+	}
+
+	return !exists, nil
+}
@@ -0,0 +1,164 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+
+	"user-manager-api/internal/application/ports"
+	"user-manager-api/internal/domain/directory"
+	domain "user-manager-api/internal/domain/user"
+)
+
+// DirectorySyncService - the directory is the source of truth for the users it
+// has: new people are created, changed profiles updated and disabled accounts
+// soft deleted. Users missing from the directory(local accounts) are left alone.
+// The changes go through UserCommands, so they publish the usual events.
+type DirectorySyncService struct {
+	directory      ports.Directory
+	userCommands   ports.UserCommands
+	userQueries    ports.UserQueries
+	syncRepository directory.Repository
+	logger         *zap.Logger
+	mCounter       *prometheus.CounterVec
+}
+
+func NewDirectorySyncService(
+	directory ports.Directory,
+	userCommands ports.UserCommands,
+	userQueries ports.UserQueries,
+	syncRepository directory.Repository,
+	logger *zap.Logger,
+	mCounter *prometheus.CounterVec,
+) ports.DirectorySyncService {
+	return &DirectorySyncService{
+		directory:      directory,
+		userCommands:   userCommands,
+		userQueries:    userQueries,
+		syncRepository: syncRepository,
+		logger:         logger,
+		mCounter:       mCounter,
+	}
+}
+
+func (ds *DirectorySyncService) Sync(ctx context.Context) (directory.Sync, error) {
+	s := directory.Sync{StartedAt: time.Now()}
+
+	err := ds.reconcile(ctx, &s)
+	if err != nil {
+		s.Error = err.Error()
+		ds.mCounter.WithLabelValues("directory_sync_failed_total").Inc()
+	} else {
+		ds.mCounter.WithLabelValues("directory_synced_total").Inc()
+	}
+	s.FinishedAt = time.Now()
+
+	// a canceled run is still reported
+	if rErr := ds.syncRepository.CreateSync(context.WithoutCancel(ctx), s); rErr != nil {
+		return s, errors.Join(err, rErr)
+	}
+
+	return s, err
+}
+
+func (ds *DirectorySyncService) LastSync(ctx context.Context) (*directory.Sync, error) {
+	return ds.syncRepository.FetchLastSync(ctx)
+}
+
+func (ds *DirectorySyncService) reconcile(ctx context.Context, s *directory.Sync) error {
+	entries, err := ds.directory.FetchEntries(ctx)
+	if err != nil {
+		return err
+	}
+	s.Fetched = len(entries)
+
+	for _, e := range entries {
+		if err = ctx.Err(); err != nil {
+			return err
+		}
+
+		var u *domain.User
+		if u, err = ds.userQueries.FindByEmail(ctx, e.Email); err != nil && !errors.Is(err, ErrUserNotFound) {
+			return err
+		}
+
+		switch {
+		case e.Disabled && u == nil:
+			s.Unchanged++
+		case e.Disabled:
+			ds.disable(ctx, s, u)
+		case !entryComplete(e):
+			ds.logger.Warn("directory entry without required attributes skipped", zap.String("email", e.Email))
+			s.Skipped++
+		case u == nil:
+			if _, err = ds.userCommands.CreateUser(ctx, entryToUser(e)); err != nil {
+				ds.fail(s, e.Email, err)
+				continue
+			}
+			s.Created++
+		case !entryMatches(u, e):
+			upd := entryToUser(e)
+			upd.UUID = u.UUID
+			upd.Email = u.Email
+			// not in the directory, kept as the user set them
+			upd.MiddleName = u.MiddleName
+			upd.Suffix = u.Suffix
+			upd.Timezone = u.Timezone
+			if _, err = ds.userCommands.UpdateUser(ctx, upd); err != nil {
+				ds.fail(s, e.Email, err)
+				continue
+			}
+			s.Updated++
+		default:
+			s.Unchanged++
+		}
+	}
+
+	return nil
+}
+
+// disable - admins are never disabled by the sync: a directory mistake must not
+// lock the service out, they are reported as failed to be handled by hand
+func (ds *DirectorySyncService) disable(ctx context.Context, s *directory.Sync, u *domain.User) {
+	if u.Role == domain.RoleAdmin {
+		ds.fail(s, u.Email, errors.New("admin accounts are not disabled by the sync"))
+		return
+	}
+	// the system(uuid.Nil) is the actor
+	if err := ds.userCommands.DeleteUser(ctx, uuid.Nil, u.UUID, domain.DeletionAdminAction, false); err != nil {
+		ds.fail(s, u.Email, err)
+		return
+	}
+	s.Disabled++
+}
+
+func (ds *DirectorySyncService) fail(s *directory.Sync, email string, err error) {
+	ds.logger.Warn("directory entry not reconciled", zap.String("email", email), zap.Error(err))
+	s.Failed++
+}
+
+// entryComplete - the directory has everything a user requires
+func entryComplete(e directory.Entry) bool {
+	return e.Name != "" && e.Lastname != "" && !e.BirthDate.IsZero() && e.Phone != ""
+}
+
+func entryMatches(u *domain.User, e directory.Entry) bool {
+	return u.Name == e.Name &&
+		u.Lastname == e.Lastname &&
+		u.Phone == e.Phone &&
+		u.BirthDate.Format(time.DateOnly) == e.BirthDate.Format(time.DateOnly)
+}
+
+func entryToUser(e directory.Entry) domain.User {
+	return domain.User{
+		Email:     e.Email,
+		Name:      e.Name,
+		Lastname:  e.Lastname,
+		BirthDate: e.BirthDate,
+		Phone:     e.Phone,
+	}
+}
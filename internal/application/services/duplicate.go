@@ -0,0 +1,134 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"user-manager-api/internal/application/ports"
+	"user-manager-api/internal/domain/audit"
+	domain "user-manager-api/internal/domain/user"
+	"user-manager-api/internal/infrastructure/mq"
+	"user-manager-api/internal/interface/api/rest/dto/user"
+)
+
+type DuplicateService struct {
+	userRepository domain.Repository
+	auditService   ports.AuditService
+	mq             ports.RabbitMQ
+	mCounter       *prometheus.CounterVec
+}
+
+func NewDuplicateService(
+	userRepository domain.Repository,
+	auditService ports.AuditService,
+	mq ports.RabbitMQ,
+	mCounter *prometheus.CounterVec,
+) ports.DuplicateService {
+	return &DuplicateService{
+		userRepository: userRepository,
+		auditService:   auditService,
+		mq:             mq,
+		mCounter:       mCounter,
+	}
+}
+
+func (ds *DuplicateService) FindDuplicates(ctx context.Context) ([]domain.DuplicateGroup, error) {
+	f := domain.NewDuplicateFinder()
+	if err := walkUsers(ctx, ds.userRepository, func(u *domain.User) error {
+		f.Add(u)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return f.Groups(), nil
+}
+
+// Merge - the loser is deleted as on DELETE(the event included), so the
+// consumers drop it, then UsersMerged tells them where its records went
+func (ds *DuplicateService) Merge(ctx context.Context, actor, winner, loser domain.UUID) (*domain.User, domain.MergeResult, error) {
+	winnerID, err := ds.userRepository.FetchInternalID(ctx, winner)
+	if err != nil {
+		return nil, domain.MergeResult{}, err
+	}
+	l, err := ds.userRepository.FetchUserByID(ctx, loser)
+	if err != nil {
+		return nil, domain.MergeResult{}, err
+	}
+	loserID, err := ds.userRepository.FetchInternalID(ctx, loser)
+	if err != nil {
+		return nil, domain.MergeResult{}, err
+	}
+
+	res, err := ds.userRepository.MergeUsers(ctx, winnerID, loserID, actor)
+	if err != nil {
+		return nil, domain.MergeResult{}, err
+	}
+	ds.mCounter.WithLabelValues("user_merged_total").Inc()
+
+	// merged already: the entry and the events go out even if the winner can not be read back
+	if err = ds.auditService.Record(ctx, audit.Entry{
+		ActorUUID:  actor,
+		Action:     audit.ActionUserMerged,
+		TargetUUID: &winner,
+		Details: map[string]any{
+			"merged_user_id": loser.String(),
+			"files":          res.Files,
+			"notes":          res.Notes,
+			"audit":          res.Audit,
+		},
+	}); err != nil {
+		return nil, domain.MergeResult{}, err
+	}
+
+	now := time.Now()
+	l.DeletedAt = &now
+	l.DeletedReason = domain.DeletionMerged
+	publishEvent(ctx, ds.mq, mq.Event{
+		Id:      uuid.New(),
+		TS:      now,
+		Method:  http.MethodDelete,
+		UserID:  loser.String(),
+		Payload: user.ToResponseUser(*l),
+		Meta: map[string]string{
+			"deleted_reason": string(domain.DeletionMerged),
+			"merged_into":    winner.String(),
+		},
+	})
+	publishAccessRevoked(ctx, ds.mq, loser, domain.DeletionMerged, now)
+
+	w, err := ds.userRepository.FetchUserByID(ctx, winner)
+	if err != nil {
+		return nil, domain.MergeResult{}, err
+	}
+	publishEvent(ctx, ds.mq, mq.Event{
+		Id:      uuid.New(),
+		TS:      now,
+		Method:  mq.EventUsersMerged,
+		UserID:  winner.String(),
+		Payload: user.ToResponseUser(*w),
+		Meta: map[string]string{
+			"merged_user_id": loser.String(),
+			"files":          strconv.Itoa(res.Files),
+			"notes":          strconv.Itoa(res.Notes),
+			"audit":          strconv.Itoa(res.Audit),
+		},
+	})
+	if res.Files > 0 {
+		// the files stats of the winner are refreshed by the event consumer
+		publishEvent(ctx, ds.mq, mq.Event{
+			Id:     uuid.New(),
+			TS:     now,
+			Method: mq.EventUserFilesChanged,
+			UserID: winner.String(),
+			Meta:   map[string]string{mq.MetaFilesDelta: strconv.Itoa(res.Files)},
+		})
+	}
+
+	return w, res, nil
+}
@@ -0,0 +1,62 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"user-manager-api/internal/application/ports"
+	"user-manager-api/internal/domain/event"
+)
+
+type (
+	EventDedupSettings struct {
+		// TTL - how long a processed event is remembered, its redelivery later
+		// is processed again
+		TTL time.Duration
+	}
+	EventDedupService struct {
+		repository event.Repository
+		mCounter   *prometheus.CounterVec
+		ttl        time.Duration
+	}
+)
+
+func NewEventDedupService(
+	repository event.Repository,
+	mCounter *prometheus.CounterVec,
+	settings EventDedupSettings,
+) ports.EventDedupService {
+	return &EventDedupService{
+		repository: repository,
+		mCounter:   mCounter,
+		ttl:        settings.TTL,
+	}
+}
+
+func (eds *EventDedupService) Claim(ctx context.Context, messageID string) (bool, error) {
+	claimed, err := eds.repository.Claim(ctx, messageID, eds.ttl)
+	if err != nil {
+		return false, err
+	}
+	if !claimed {
+		eds.mCounter.WithLabelValues("mq_events_duplicate_total").Inc()
+	}
+
+	return claimed, nil
+}
+
+func (eds *EventDedupService) Release(ctx context.Context, messageID string) error {
+	return eds.repository.Release(ctx, messageID)
+}
+
+func (eds *EventDedupService) Purge(ctx context.Context) (int64, error) {
+	n, err := eds.repository.Purge(ctx, eds.ttl)
+	if err != nil {
+		return 0, err
+	}
+	eds.mCounter.WithLabelValues("processed_events_purged_total").Add(float64(n))
+
+	return n, nil
+}
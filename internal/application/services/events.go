@@ -0,0 +1,37 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"user-manager-api/internal/application/ports"
+	domain "user-manager-api/internal/domain/user"
+	"user-manager-api/internal/infrastructure/mq"
+)
+
+// revokedOnDelete - the access revoked in the statement deleting a user. There
+// are no refresh tokens, API keys or share links: the access tokens are all.
+const revokedOnDelete = "tokens,devices"
+
+// publishEvent - events are published after the change is stored, so a rejected
+// event(broker outage, ErrBackpressure) does not fail the request: the publisher
+// logs and counts it, the stats are repaired by the rebuild-stats job.
+func publishEvent(ctx context.Context, publisher ports.RabbitMQ, e mq.Event) {
+	_ = publisher.Publish(ctx, e)
+}
+
+// publishAccessRevoked - one event for all the access revoked with the deleted user
+func publishAccessRevoked(ctx context.Context, publisher ports.RabbitMQ, userUUID domain.UUID, reason domain.DeletionReason, at time.Time) {
+	publishEvent(ctx, publisher, mq.Event{
+		Id:     uuid.New(),
+		TS:     at,
+		Method: mq.EventUserAccessRevoked,
+		UserID: userUUID.String(),
+		Meta: map[string]string{
+			"deleted_reason": string(reason),
+			mq.MetaRevoked:   revokedOnDelete,
+		},
+	})
+}
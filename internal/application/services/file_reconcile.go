@@ -0,0 +1,134 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"user-manager-api/internal/application/ports"
+	domain "user-manager-api/internal/domain/user_file"
+)
+
+const reconcileGracePeriod = time.Hour
+
+// a deletion of more than reconcileMaxShare of the objects or of the rows(but
+// reconcileMinDeletes) is refused: a wrong bucket or prefix looks the same
+const (
+	reconcileMaxShare   = 0.1
+	reconcileMinDeletes = 10
+)
+
+type FileReconcileService struct {
+	storage            ports.ObjectStorage
+	userFileRepository domain.Repository
+	mCounter           *prometheus.CounterVec
+}
+
+func NewFileReconcileService(
+	storage ports.ObjectStorage,
+	userFileRepository domain.Repository,
+	mCounter *prometheus.CounterVec,
+) ports.FileReconcileService {
+	return &FileReconcileService{
+		storage:            storage,
+		userFileRepository: userFileRepository,
+		mCounter:           mCounter,
+	}
+}
+
+// Reconcile compares objects under the documents/ prefix with user_files rows.
+// With deleteOrphans objects without rows are removed from the storage and
+// rows whose objects are missing are soft deleted, unless there are too many
+// of them(ErrTooManyMissing). A storage not listing its objects fails it.
+func (frs *FileReconcileService) Reconcile(ctx context.Context, deleteOrphans bool) (*domain.ReconcileReport, error) {
+	keys, err := frs.storage.ListObjects(ctx, storageKeyPrefix)
+	if err != nil {
+		return nil, err
+	}
+	refs, err := frs.userFileRepository.FetchStorageRefs(ctx, storageKeyPrefix)
+	if err != nil {
+		return nil, err
+	}
+
+	objects := make(map[string]struct{}, len(keys))
+	for _, k := range keys {
+		objects[k] = struct{}{}
+	}
+	rows := make(map[string]struct{}, len(refs))
+	for _, ref := range refs {
+		rows[ref.StorageKey] = struct{}{}
+	}
+
+	// an upload writes the object and then inserts the row, skip fresh
+	// entries so an upload in flight is never taken for an orphan
+	cutoff := time.Now().UTC().Add(-reconcileGracePeriod)
+
+	report := new(domain.ReconcileReport)
+	for _, k := range keys {
+		if _, ok := rows[k]; !ok && storageKeyTime(k).Before(cutoff) {
+			report.OrphanObjects = append(report.OrphanObjects, k)
+		}
+	}
+	for _, ref := range refs {
+		if _, ok := objects[ref.StorageKey]; !ok && ref.CreatedAt.Before(cutoff) {
+			report.MissingObjects = append(report.MissingObjects, ref)
+		}
+	}
+
+	frs.mCounter.WithLabelValues("files_orphan_objects_total").Add(float64(len(report.OrphanObjects)))
+	frs.mCounter.WithLabelValues("files_missing_objects_total").Add(float64(len(report.MissingObjects)))
+
+	if !deleteOrphans {
+		return report, nil
+	}
+	if len(keys) == 0 && len(refs) > 0 ||
+		tooMany(len(report.OrphanObjects), len(keys)) ||
+		tooMany(len(report.MissingObjects), len(refs)) {
+		return report, fmt.Errorf("%w: %d orphan objects of %d, %d missing of %d rows",
+			domain.ErrTooManyMissing, len(report.OrphanObjects), len(keys), len(report.MissingObjects), len(refs))
+	}
+
+	if len(report.OrphanObjects) > 0 {
+		if err = frs.storage.DeleteObjects(ctx, report.OrphanObjects); err != nil {
+			return report, err
+		}
+	}
+	if len(report.MissingObjects) > 0 {
+		uuids := make([]uuid.UUID, len(report.MissingObjects))
+		for i, ref := range report.MissingObjects {
+			uuids[i] = ref.UUID
+		}
+		if err = frs.userFileRepository.DeleteUserFilesByUUIDs(ctx, uuids); err != nil {
+			return report, err
+		}
+	}
+	report.Deleted = true
+
+	return report, nil
+}
+
+func tooMany(n, total int) bool {
+	return n > reconcileMinDeletes && float64(n) > reconcileMaxShare*float64(total)
+}
+
+// storageKeyTime extracts the upload time from "documents/users/.../<ts>/<filename>"
+// or "documents/YYYY/MM/DD/<ts>/...", unknown layouts are treated as "now" so
+// they are never deleted.
+func storageKeyTime(key string) time.Time {
+	parts := strings.Split(key, "/")
+	if len(parts) > 1 {
+		if ts, err := time.Parse(storageKeyTSLayout, parts[len(parts)-2]); err == nil {
+			return ts
+		}
+	}
+	if len(parts) > 4 {
+		if ts, err := time.Parse(storageKeyTSLayout, parts[4]); err == nil {
+			return ts
+		}
+	}
+	return time.Now().UTC()
+}
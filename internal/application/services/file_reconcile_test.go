@@ -0,0 +1,113 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"user-manager-api/internal/application/ports"
+	domain "user-manager-api/internal/domain/user_file"
+)
+
+// listingStorage - the objects listed, listErr fails the listing
+type listingStorage struct {
+	ports.ObjectStorage
+	keys    []string
+	listErr error
+	deleted []string
+}
+
+func (s *listingStorage) ListObjects(context.Context, string) ([]string, error) {
+	return s.keys, s.listErr
+}
+
+func (s *listingStorage) DeleteObjects(_ context.Context, keys []string) error {
+	s.deleted = append(s.deleted, keys...)
+	return nil
+}
+
+// refsRepository - the storage refs of the rows, the soft deleted ones kept
+type refsRepository struct {
+	domain.Repository
+	refs    domain.StorageRefs
+	deleted []uuid.UUID
+}
+
+func (r *refsRepository) FetchStorageRefs(context.Context, string) (domain.StorageRefs, error) {
+	return r.refs, nil
+}
+
+func (r *refsRepository) DeleteUserFilesByUUIDs(_ context.Context, uuids []uuid.UUID) error {
+	r.deleted = append(r.deleted, uuids...)
+	return nil
+}
+
+func TestFileReconcileService_Reconcile(t *testing.T) {
+	mCounter := prometheus.NewCounterVec(prometheus.CounterOpts{Name: "test"}, []string{"result"})
+	old := time.Now().UTC().Add(-2 * time.Hour)
+	key := func(i int) string {
+		return fmt.Sprintf("documents/users/u/%s/f%d", old.Format(storageKeyTSLayout), i)
+	}
+	rows := func(n int) domain.StorageRefs {
+		refs := make(domain.StorageRefs, n)
+		for i := range refs {
+			refs[i] = domain.StorageRef{UUID: uuid.New(), StorageKey: key(i), CreatedAt: old}
+		}
+		return refs
+	}
+	objects := func(from, to int) []string {
+		var keys []string
+		for i := from; i < to; i++ {
+			keys = append(keys, key(i))
+		}
+		return keys
+	}
+
+	tests := []struct {
+		name        string
+		keys        []string
+		listErr     error
+		refs        domain.StorageRefs
+		wantErr     error
+		wantMissing int
+		wantOrphans int
+		wantDeleted bool
+	}{
+		{name: "in sync", keys: objects(0, 3), refs: rows(3), wantDeleted: true},
+		{name: "a few differ", keys: objects(1, 101), refs: rows(100), wantMissing: 1, wantOrphans: 1, wantDeleted: true},
+		{name: "listing not supported", listErr: domain.ErrListingNotSupported, refs: rows(3), wantErr: domain.ErrListingNotSupported},
+		{name: "empty listing", refs: rows(3), wantErr: domain.ErrTooManyMissing, wantMissing: 3},
+		{name: "too many missing", keys: objects(0, 50), refs: rows(100), wantErr: domain.ErrTooManyMissing, wantMissing: 50},
+		{name: "too many orphans", keys: objects(0, 100), refs: rows(20), wantErr: domain.ErrTooManyMissing, wantOrphans: 80},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storage := &listingStorage{keys: tt.keys, listErr: tt.listErr}
+			repo := &refsRepository{refs: tt.refs}
+			frs := NewFileReconcileService(storage, repo, mCounter)
+
+			report, err := frs.Reconcile(context.Background(), true)
+			require.ErrorIs(t, err, tt.wantErr)
+			if tt.listErr != nil {
+				return
+			}
+			assert.Len(t, report.MissingObjects, tt.wantMissing)
+			assert.Len(t, report.OrphanObjects, tt.wantOrphans)
+			assert.Equal(t, tt.wantDeleted, report.Deleted)
+			if !tt.wantDeleted {
+				assert.Empty(t, storage.deleted)
+				assert.Empty(t, repo.deleted)
+				return
+			}
+			assert.Len(t, storage.deleted, tt.wantOrphans)
+			assert.Len(t, repo.deleted, tt.wantMissing)
+		})
+	}
+}
@@ -0,0 +1,165 @@
+package services
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+
+	"user-manager-api/internal/application/ports"
+	"user-manager-api/internal/domain/audit"
+	domain "user-manager-api/internal/domain/user_file"
+	"user-manager-api/internal/infrastructure/mq"
+)
+
+const purgeBatchSize = 500
+
+type FileRetentionService struct {
+	storage            ports.ObjectStorage
+	userFileRepository domain.Repository
+	auditService       ports.AuditService
+	mq                 ports.RabbitMQ
+	logger             *zap.Logger
+	mCounter           *prometheus.CounterVec
+}
+
+func NewFileRetentionService(
+	storage ports.ObjectStorage,
+	userFileRepository domain.Repository,
+	auditService ports.AuditService,
+	mq ports.RabbitMQ,
+	logger *zap.Logger,
+	mCounter *prometheus.CounterVec,
+) ports.FileRetentionService {
+	return &FileRetentionService{
+		storage:            storage,
+		userFileRepository: userFileRepository,
+		auditService:       auditService,
+		mq:                 mq,
+		logger:             logger,
+		mCounter:           mCounter,
+	}
+}
+
+func (frs *FileRetentionService) Rules(ctx context.Context) (domain.RetentionRules, error) {
+	return frs.userFileRepository.FetchRetentionRules(ctx)
+}
+
+func (frs *FileRetentionService) CreateRule(ctx context.Context, actor uuid.UUID, r domain.RetentionRule) (*domain.RetentionRule, error) {
+	rule, err := frs.userFileRepository.CreateRetentionRule(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+
+	frs.record(ctx, audit.Entry{
+		ActorUUID: actor,
+		Action:    audit.ActionRetentionRuleCreated,
+		Details: map[string]any{
+			"rule_uuid": rule.UUID,
+			"mime_type": rule.MimeType,
+			"tag":       rule.Tag,
+			"days":      rule.Days,
+		},
+	})
+
+	return rule, nil
+}
+
+func (frs *FileRetentionService) DeleteRule(ctx context.Context, actor, ruleUUID uuid.UUID) (bool, error) {
+	removed, err := frs.userFileRepository.DeleteRetentionRule(ctx, ruleUUID)
+	if err != nil || !removed {
+		return false, err
+	}
+
+	frs.record(ctx, audit.Entry{
+		ActorUUID: actor,
+		Action:    audit.ActionRetentionRuleDeleted,
+		Details:   map[string]any{"rule_uuid": ruleUUID},
+	})
+
+	return true, nil
+}
+
+// SetFileRetention - ErrUserFileNotFound if there is no such file
+func (frs *FileRetentionService) SetFileRetention(ctx context.Context, actor, fileUUID uuid.UUID, r domain.Retention) error {
+	found, err := frs.userFileRepository.SetRetention(ctx, fileUUID, r)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return ErrUserFileNotFound
+	}
+
+	frs.record(ctx, audit.Entry{
+		ActorUUID: actor,
+		Action:    audit.ActionFileRetentionChanged,
+		Details: map[string]any{
+			"file_uuid":    fileUUID,
+			"retain_until": r.RetainUntil,
+			"legal_hold":   r.LegalHold,
+		},
+	})
+
+	return nil
+}
+
+// PurgeExpired - the purged rows no longer match the expired query, so batches
+// are taken until one is empty. The rows are soft deleted before the objects:
+// objects left by a failed delete are orphans for the reconcile-files job. The
+// system is the actor(uuid.Nil) of the audit entries.
+func (frs *FileRetentionService) PurgeExpired(ctx context.Context) (int, error) {
+	var total int
+	for {
+		ufs, err := frs.userFileRepository.PurgeExpiredFiles(ctx, time.Now(), purgeBatchSize)
+		if err != nil {
+			return total, err
+		}
+		if len(ufs) == 0 {
+			return total, nil
+		}
+
+		keys := make([]string, 0, len(ufs))
+		// owners - the purged files of each user
+		owners := make(map[uuid.UUID]int)
+		for _, uf := range ufs {
+			keys = append(keys, objectKeys(uf)...)
+			owners[uf.UserUUID]++
+
+			target := uf.UserUUID
+			frs.record(ctx, audit.Entry{
+				ActorUUID:  uuid.Nil,
+				Action:     audit.ActionFilePurged,
+				TargetUUID: &target,
+				Details: map[string]any{
+					"file_uuid":   uf.UUID,
+					"storage_key": uf.StorageKey,
+				},
+			})
+		}
+		for owner, purged := range owners {
+			publishEvent(ctx, frs.mq, mq.Event{
+				Id:     uuid.New(),
+				TS:     time.Now(),
+				Method: mq.EventUserFilesChanged,
+				UserID: owner.String(),
+				Meta:   map[string]string{mq.MetaFilesDelta: strconv.Itoa(-purged)},
+			})
+		}
+		total += len(ufs)
+		frs.mCounter.WithLabelValues("files_purged_total").Add(float64(len(ufs)))
+
+		if err = frs.storage.DeleteObjects(ctx, keys); err != nil {
+			return total, err
+		}
+	}
+}
+
+// record - the change is made already, a failed entry is in the log(Record)
+func (frs *FileRetentionService) record(ctx context.Context, e audit.Entry) {
+	if err := frs.auditService.Record(ctx, e); err != nil {
+		frs.logger.Error("file retention audit error", zap.Error(err), zap.String("action", string(e.Action)))
+	}
+}
@@ -0,0 +1,57 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"user-manager-api/internal/application/ports"
+	domain "user-manager-api/internal/domain/user"
+)
+
+// HRHookService applies the employee changes pushed by the HR system. Every event
+// is an upsert by email: webhooks are redelivered and may come out of order, so
+// "created" of a known employee updates it and "updated" of an unknown one creates it.
+type HRHookService struct {
+	userCommands ports.UserCommands
+	userQueries  ports.UserQueries
+	mCounter     *prometheus.CounterVec
+}
+
+func NewHRHookService(
+	userCommands ports.UserCommands,
+	userQueries ports.UserQueries,
+	mCounter *prometheus.CounterVec,
+) ports.HRHookService {
+	return &HRHookService{
+		userCommands: userCommands,
+		userQueries:  userQueries,
+		mCounter:     mCounter,
+	}
+}
+
+func (hs *HRHookService) ApplyEmployee(ctx context.Context, employee domain.User) (*domain.User, bool, error) {
+	cur, err := hs.userQueries.FindByEmail(ctx, employee.Email)
+	if errors.Is(err, ErrUserNotFound) {
+		u, err := hs.userCommands.CreateUser(ctx, employee)
+		if err != nil {
+			return nil, false, err
+		}
+		hs.mCounter.WithLabelValues("hr_hook_applied_total").Inc()
+		return u, true, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	employee.UUID = cur.UUID
+	// ErrUserNotFound - deleted meanwhile
+	u, err := hs.userCommands.UpdateUser(ctx, employee)
+	if err != nil {
+		return nil, false, err
+	}
+	hs.mCounter.WithLabelValues("hr_hook_applied_total").Inc()
+
+	return u, false, nil
+}
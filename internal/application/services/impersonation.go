@@ -0,0 +1,77 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"user-manager-api/internal/application/ports"
+	"user-manager-api/internal/domain/audit"
+	domain "user-manager-api/internal/domain/user"
+)
+
+var (
+	ErrImpersonateSelf  = errors.New("cannot impersonate yourself")
+	ErrImpersonateAdmin = errors.New("cannot impersonate an admin")
+)
+
+type ImpersonationService struct {
+	tokenService   ports.TokenService
+	userRepository domain.Repository
+	auditService   ports.AuditService
+	mCounter       *prometheus.CounterVec
+	ttl            time.Duration
+}
+
+func NewImpersonationService(
+	tokenService ports.TokenService,
+	userRepository domain.Repository,
+	auditService ports.AuditService,
+	mCounter *prometheus.CounterVec,
+	ttl time.Duration,
+) ports.ImpersonationService {
+	return &ImpersonationService{
+		tokenService:   tokenService,
+		userRepository: userRepository,
+		auditService:   auditService,
+		mCounter:       mCounter,
+		ttl:            ttl,
+	}
+}
+
+func (is *ImpersonationService) Impersonate(ctx context.Context, actor, target domain.UUID) (string, time.Time, error) {
+	if actor == target {
+		return "", time.Time{}, ErrImpersonateSelf
+	}
+
+	u, err := is.userRepository.FetchUserByID(ctx, target)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	// an admin token in support hands would bypass the admin-only guard
+	if u.Role == domain.RoleAdmin {
+		return "", time.Time{}, ErrImpersonateAdmin
+	}
+
+	expiresAt := time.Now().Add(is.ttl)
+	// no trail - no token
+	if err = is.auditService.Record(ctx, audit.Entry{
+		ActorUUID:  actor,
+		Action:     audit.ActionImpersonationStarted,
+		TargetUUID: &target,
+		Details:    map[string]any{"expires_at": expiresAt.UTC()},
+	}); err != nil {
+		return "", time.Time{}, err
+	}
+
+	token, err := is.tokenService.GenerateImpersonationToken(target.String(), u.Role, actor.String(), is.ttl)
+	if err != nil {
+		return "", time.Time{}, ErrFailedToGenerateToken
+	}
+
+	is.mCounter.WithLabelValues("impersonation_started_total").Inc()
+
+	return token, expiresAt, nil
+}
@@ -0,0 +1,171 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"user-manager-api/internal/application/ports"
+	"user-manager-api/internal/domain/audit"
+	"user-manager-api/internal/domain/usage"
+	domain "user-manager-api/internal/domain/user"
+	"user-manager-api/internal/infrastructure/mq"
+	"user-manager-api/internal/interface/api/rest/dto/user"
+)
+
+var ErrInvalidInvitation = errors.New("invalid or expired invitation")
+
+// InvitationSettings - signupURL is the page of the invitation links, empty -
+// the events carry the token only
+type InvitationSettings struct {
+	TTL       time.Duration
+	SignupURL string
+}
+
+// InvitationService - admins invite users instead of creating them: the invitee
+// sets the password and the profile. Like the email change, the token is
+// delivered with an event and only its hash is stored.
+type InvitationService struct {
+	hasher          ports.PasswordHasher
+	userRepository  domain.Repository
+	usageRepository usage.Repository
+	auditService    ports.AuditService
+	mq              ports.RabbitMQ
+	mCounter        *prometheus.CounterVec
+	settings        InvitationSettings
+	agePolicy       domain.AgePolicy
+}
+
+func NewInvitationService(
+	hasher ports.PasswordHasher,
+	userRepository domain.Repository,
+	usageRepository usage.Repository,
+	auditService ports.AuditService,
+	mq ports.RabbitMQ,
+	mCounter *prometheus.CounterVec,
+	settings InvitationSettings,
+	agePolicy domain.AgePolicy,
+) ports.InvitationService {
+	return &InvitationService{
+		hasher:          hasher,
+		userRepository:  userRepository,
+		usageRepository: usageRepository,
+		auditService:    auditService,
+		mq:              mq,
+		mCounter:        mCounter,
+		settings:        settings,
+		agePolicy:       agePolicy,
+	}
+}
+
+// Invite - the seat limit is checked here: the invitations pending meanwhile
+// may exceed it on acceptance.
+func (is *InvitationService) Invite(ctx context.Context, actor domain.UUID, email, role string) (time.Time, error) {
+	if err := checkSeat(ctx, is.usageRepository, is.mCounter, email); err != nil {
+		return time.Time{}, err
+	}
+
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return time.Time{}, err
+	}
+	token := base64.RawURLEncoding.EncodeToString(b)
+	hash := sha256.Sum256([]byte(token))
+	expiresAt := time.Now().Add(is.settings.TTL)
+
+	if err := is.userRepository.CreateInvitation(ctx, domain.Invitation{
+		Email:     email,
+		Role:      role,
+		TokenHash: hash[:],
+		ExpiresAt: expiresAt,
+		InvitedBy: actor,
+	}); err != nil {
+		return time.Time{}, err
+	}
+
+	if err := is.auditService.Record(ctx, audit.Entry{
+		ActorUUID: actor,
+		Action:    audit.ActionUserInvited,
+		Details:   map[string]any{"email": email, "role": role},
+	}); err != nil {
+		return time.Time{}, err
+	}
+
+	meta := map[string]string{
+		"email":        email,
+		"role":         role,
+		"invite_token": token,
+		"expires_at":   expiresAt.UTC().Format(time.RFC3339),
+	}
+	if is.settings.SignupURL != "" {
+		meta["invite_url"] = is.settings.SignupURL + "?token=" + url.QueryEscape(token)
+	}
+	// no user yet: the inviting admin orders the events
+	publishEvent(ctx, is.mq, mq.Event{
+		Id:     uuid.New(),
+		TS:     time.Now(),
+		Method: mq.EventInvitationCreated,
+		UserID: actor.String(),
+		Meta:   meta,
+	})
+
+	is.mCounter.WithLabelValues("user_invited_total").Inc()
+
+	return expiresAt, nil
+}
+
+func (is *InvitationService) Accept(ctx context.Context, token string, u domain.User, password string) (*domain.User, error) {
+	if token == "" {
+		return nil, ErrInvalidInvitation
+	}
+	hash := sha256.Sum256([]byte(token))
+	// the minimum age is the one of the organization of the invited email
+	inv, err := is.userRepository.FetchInvitation(ctx, hash[:])
+	if errors.Is(err, domain.ErrNotFound) {
+		return nil, ErrInvalidInvitation
+	}
+	if err != nil {
+		return nil, err
+	}
+	u.Email = inv.Email
+	if err = is.agePolicy.Check(u, time.Now()); err != nil {
+		return nil, err
+	}
+
+	passwordHash, err := is.hasher.Hash(password)
+	if err != nil {
+		return nil, err
+	}
+
+	uRet, err := is.userRepository.AcceptInvitation(ctx, hash[:], u, passwordHash)
+	if errors.Is(err, domain.ErrNotFound) {
+		return nil, ErrInvalidInvitation
+	}
+	if err != nil {
+		countSeatRejected(is.mCounter, err)
+		return nil, err
+	}
+
+	publishEvent(ctx, is.mq, mq.Event{
+		Id:      uuid.New(),
+		TS:      time.Now(),
+		Method:  http.MethodPost,
+		UserID:  uRet.UUID.String(),
+		Payload: user.ToResponseUser(*uRet),
+		// the signups stats day
+		Meta: map[string]string{"created_at": uRet.CreatedAt.UTC().Format(time.RFC3339Nano)},
+	})
+
+	is.mCounter.WithLabelValues("user_invitation_accepted_total").Inc()
+	is.mCounter.WithLabelValues("user_created_total").Inc()
+
+	return uRet, nil
+}
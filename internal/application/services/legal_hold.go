@@ -0,0 +1,71 @@
+package services
+
+import (
+	"context"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+
+	"user-manager-api/internal/application/ports"
+	"user-manager-api/internal/domain/audit"
+	domain "user-manager-api/internal/domain/user"
+)
+
+type LegalHoldService struct {
+	userRepository domain.Repository
+	auditService   ports.AuditService
+	logger         *zap.Logger
+	mCounter       *prometheus.CounterVec
+}
+
+func NewLegalHoldService(
+	userRepository domain.Repository,
+	auditService ports.AuditService,
+	logger *zap.Logger,
+	mCounter *prometheus.CounterVec,
+) ports.LegalHoldService {
+	return &LegalHoldService{
+		userRepository: userRepository,
+		auditService:   auditService,
+		logger:         logger,
+		mCounter:       mCounter,
+	}
+}
+
+func (lhs *LegalHoldService) Place(ctx context.Context, actor, userUUID domain.UUID, reason string) (*domain.LegalHold, error) {
+	h, err := lhs.userRepository.SetLegalHold(ctx, userUUID, reason)
+	if err != nil {
+		return nil, err
+	}
+	lhs.mCounter.WithLabelValues("legal_hold_placed_total").Inc()
+	lhs.record(ctx, actor, userUUID, audit.ActionLegalHoldPlaced, map[string]any{"reason": reason})
+
+	return h, nil
+}
+
+func (lhs *LegalHoldService) Release(ctx context.Context, actor, userUUID domain.UUID) (bool, error) {
+	released, err := lhs.userRepository.ReleaseLegalHold(ctx, userUUID)
+	if err != nil || !released {
+		return false, err
+	}
+	lhs.mCounter.WithLabelValues("legal_hold_released_total").Inc()
+	lhs.record(ctx, actor, userUUID, audit.ActionLegalHoldReleased, nil)
+
+	return true, nil
+}
+
+func (lhs *LegalHoldService) Get(ctx context.Context, userUUID domain.UUID) (*domain.LegalHold, error) {
+	return lhs.userRepository.FetchLegalHold(ctx, userUUID)
+}
+
+// record - the hold is changed already, a failed entry is in the log(Record)
+func (lhs *LegalHoldService) record(ctx context.Context, actor, target domain.UUID, action audit.Action, details map[string]any) {
+	if err := lhs.auditService.Record(ctx, audit.Entry{
+		ActorUUID:  actor,
+		Action:     action,
+		TargetUUID: &target,
+		Details:    details,
+	}); err != nil {
+		lhs.logger.Error("legal hold audit error", zap.Error(err), zap.String("action", string(action)))
+	}
+}
@@ -0,0 +1,395 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"html/template"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+
+	"user-manager-api/internal/application/ports"
+	"user-manager-api/internal/domain/notification"
+	"user-manager-api/internal/domain/user"
+	"user-manager-api/internal/infrastructure/mq"
+)
+
+// email templates, every one defines "subject" and "content" rendered into the layout,
+// the names are the "notification" of the webhook payloads
+const (
+	templateUserCreated    = "user_created"
+	templateProfileUpdated = "profile_updated"
+	templatePasswordReset  = "password_reset"
+	templateInvitation     = "invitation"
+	templateDigest         = "digest"
+	templateInactivity     = "inactivity_warning"
+	templateSuspended      = "account_suspended"
+)
+
+// digestUsersBatch - users whose digests are sent per page
+const digestUsersBatch = 100
+
+//go:embed templates/email/*.html
+var emailTemplatesFS embed.FS
+
+type (
+	NotificationSettings struct {
+		// LoginURL - linked from the emails of the users, empty - no link
+		LoginURL string
+	}
+	// NotificationService renders the notifications of the consumed events and
+	// sends them over the channels the users opted in to. Email: the suppressed
+	// addresses are skipped and the rejected ones suppressed.
+	NotificationService struct {
+		email          ports.EmailSender
+		sms            ports.SMSSender
+		webhook        ports.WebhookSender
+		repository     notification.Repository
+		userRepository user.Repository
+		templates      map[string]*template.Template
+		settings       NotificationSettings
+		logger         *zap.Logger
+		mCounter       *prometheus.CounterVec
+	}
+	// emailData - the values of the templates, unset ones are empty
+	emailData struct {
+		Email        string
+		Name         string
+		LoginURL     string
+		InviteURL    string
+		InviteToken  string
+		ExpiresAt    string
+		SuspendAfter string
+		Items        []notification.DigestItem
+	}
+	// recipient - the addresses of a user per channel
+	recipient struct {
+		Email string
+		Phone string
+	}
+	// message - a notification rendered for every channel
+	message struct {
+		Subject string
+		HTML    string
+		// Webhook - the JSON body
+		Webhook []byte
+	}
+	webhookPayload struct {
+		Notification string        `json:"notification"`
+		UserID       string        `json:"user_id"`
+		Subject      string        `json:"subject"`
+		TS           time.Time     `json:"time_stamp"`
+		Items        []webhookItem `json:"items,omitempty"`
+	}
+	webhookItem struct {
+		Subject string    `json:"subject"`
+		TS      time.Time `json:"time_stamp"`
+	}
+)
+
+func NewNotificationService(
+	email ports.EmailSender,
+	sms ports.SMSSender,
+	webhook ports.WebhookSender,
+	repository notification.Repository,
+	userRepository user.Repository,
+	logger *zap.Logger,
+	mCounter *prometheus.CounterVec,
+	settings NotificationSettings,
+) ports.NotificationService {
+	// the templates are embedded, a broken one is a bug
+	templates := make(map[string]*template.Template)
+	for _, name := range []string{
+		templateUserCreated,
+		templateProfileUpdated,
+		templatePasswordReset,
+		templateInvitation,
+		templateDigest,
+		templateInactivity,
+		templateSuspended,
+	} {
+		templates[name] = template.Must(template.ParseFS(
+			emailTemplatesFS,
+			"templates/email/layout.html",
+			"templates/email/"+name+".html",
+		))
+	}
+
+	return &NotificationService{
+		email:          email,
+		sms:            sms,
+		webhook:        webhook,
+		repository:     repository,
+		userRepository: userRepository,
+		templates:      templates,
+		settings:       settings,
+		logger:         logger,
+		mCounter:       mCounter,
+	}
+}
+
+func (ns *NotificationService) Notify(ctx context.Context, e mq.Event) error {
+	var (
+		name string
+		// urgent - security notices: never digested, the email can not be opted out of
+		urgent bool
+	)
+	switch e.Method {
+	case http.MethodPost:
+		name = templateUserCreated
+	case http.MethodPut:
+		name = templateProfileUpdated
+	case mq.EventPasswordResetForced:
+		name, urgent = templatePasswordReset, true
+	case mq.EventInactivityWarning:
+		name, urgent = templateInactivity, true
+	case mq.EventUserSuspended:
+		name, urgent = templateSuspended, true
+	case mq.EventInvitationCreated:
+		// no user yet, so no preferences
+		return ns.notifyInvitee(ctx, e)
+	default:
+		return nil
+	}
+
+	userUUID, err := uuid.Parse(e.UserID)
+	if err != nil {
+		return fmt.Errorf("event %s: invalid user_id: %w", e.Id, err)
+	}
+	prefs, err := ns.repository.FetchPreferences(ctx, userUUID)
+	if err != nil {
+		return err
+	}
+
+	data := emailData{
+		Email:        e.Payload.Email,
+		Name:         e.Payload.Name,
+		LoginURL:     ns.settings.LoginURL,
+		SuspendAfter: e.Meta[mq.MetaSuspendAfter],
+	}
+	msg, err := ns.render(name, data, webhookPayload{Notification: name, UserID: e.UserID, TS: e.TS})
+	if err != nil {
+		return fmt.Errorf("event %s: render %s: %w", e.Id, name, err)
+	}
+	to := recipient{Email: e.Payload.Email, Phone: e.Payload.Phone}
+
+	var errs []error
+	for _, c := range notification.Channels {
+		p := prefs.Channel(c)
+		if !p.Enabled && !(urgent && c == notification.ChannelEmail) {
+			continue
+		}
+		if p.Mode == notification.ModeDigest && !urgent {
+			if err = ns.repository.AddDigestItem(ctx, userUUID, c, msg.Subject); err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			ns.mCounter.WithLabelValues("notification_digested_total").Inc()
+			continue
+		}
+		if err = ns.send(ctx, p, to, msg); err != nil {
+			errs = append(errs, fmt.Errorf("event %s: %s: %w", e.Id, c, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (ns *NotificationService) SendDigests(ctx context.Context) (int, error) {
+	var (
+		sent  int
+		after uuid.UUID
+		errs  []error
+	)
+	for {
+		users, err := ns.repository.FetchDigestUsers(ctx, after, digestUsersBatch)
+		if err != nil {
+			return sent, err
+		}
+		for _, userUUID := range users {
+			if err = ctx.Err(); err != nil {
+				return sent, err
+			}
+			var n int
+			n, err = ns.sendDigests(ctx, userUUID)
+			sent += n
+			if err != nil {
+				errs = append(errs, fmt.Errorf("user %s: %w", userUUID, err))
+			}
+		}
+		if len(users) < digestUsersBatch {
+			break
+		}
+		after = users[len(users)-1]
+	}
+
+	return sent, errors.Join(errs...)
+}
+
+// sendDigests - a digest per channel of the user, the items of a failed one are
+// kept for the next run
+func (ns *NotificationService) sendDigests(ctx context.Context, userUUID uuid.UUID) (int, error) {
+	items, err := ns.repository.FetchDigestItems(ctx, userUUID)
+	if err != nil {
+		return 0, err
+	}
+	u, err := ns.userRepository.FetchUserByID(ctx, userUUID)
+	if err != nil && !errors.Is(err, user.ErrNotFound) {
+		return 0, err
+	}
+	prefs, err := ns.repository.FetchPreferences(ctx, userUUID)
+	if err != nil {
+		return 0, err
+	}
+
+	byChannel := make(map[notification.Channel][]notification.DigestItem)
+	for _, it := range items {
+		byChannel[it.Channel] = append(byChannel[it.Channel], it)
+	}
+
+	var (
+		sent int
+		errs []error
+	)
+	for c, chItems := range byChannel {
+		p := prefs.Channel(c)
+		// deleted or opted out meanwhile: the items are dropped
+		if u != nil && p.Enabled {
+			data := emailData{Email: u.Email, Name: u.Name, LoginURL: ns.settings.LoginURL, Items: chItems}
+			payload := webhookPayload{Notification: templateDigest, UserID: userUUID.String(), TS: time.Now()}
+			for _, it := range chItems {
+				payload.Items = append(payload.Items, webhookItem{Subject: it.Subject, TS: it.CreatedAt})
+			}
+			var msg message
+			if msg, err = ns.render(templateDigest, data, payload); err != nil {
+				return sent, err
+			}
+			if err = ns.send(ctx, p, recipient{Email: u.Email, Phone: u.Phone}, msg); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", c, err))
+				continue
+			}
+			sent++
+			ns.mCounter.WithLabelValues("digest_sent_total").Inc()
+		}
+
+		ids := make([]int64, len(chItems))
+		for i, it := range chItems {
+			ids[i] = it.ID
+		}
+		if err = ns.repository.DeleteDigestItems(ctx, ids); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return sent, errors.Join(errs...)
+}
+
+func (ns *NotificationService) notifyInvitee(ctx context.Context, e mq.Event) error {
+	data := emailData{
+		Email:       e.Meta["email"],
+		InviteURL:   e.Meta["invite_url"],
+		InviteToken: e.Meta["invite_token"],
+		ExpiresAt:   e.Meta["expires_at"],
+	}
+	if data.Email == "" {
+		return fmt.Errorf("event %s: no email", e.Id)
+	}
+	msg, err := ns.render(templateInvitation, data, webhookPayload{})
+	if err != nil {
+		return fmt.Errorf("event %s: render %s: %w", e.Id, templateInvitation, err)
+	}
+
+	return ns.sendEmail(ctx, data.Email, msg)
+}
+
+// send - over the channel of p, a channel without the address of the user is skipped
+func (ns *NotificationService) send(ctx context.Context, p notification.Preference, to recipient, msg message) error {
+	switch p.Channel {
+	case notification.ChannelEmail:
+		if to.Email == "" {
+			return nil
+		}
+		return ns.sendEmail(ctx, to.Email, msg)
+	case notification.ChannelSMS:
+		if to.Phone == "" {
+			return nil
+		}
+		if err := ns.sms.Send(ctx, to.Phone, msg.Subject); err != nil {
+			ns.mCounter.WithLabelValues("sms_failed_total").Inc()
+			return err
+		}
+		ns.mCounter.WithLabelValues("sms_sent_total").Inc()
+	case notification.ChannelWebhook:
+		if p.WebhookURL == "" {
+			return nil
+		}
+		if err := ns.webhook.Send(ctx, p.WebhookURL, msg.Webhook); err != nil {
+			ns.mCounter.WithLabelValues("webhook_failed_total").Inc()
+			return err
+		}
+		ns.mCounter.WithLabelValues("webhook_sent_total").Inc()
+	}
+
+	return nil
+}
+
+func (ns *NotificationService) sendEmail(ctx context.Context, to string, msg message) error {
+	suppressed, err := ns.repository.IsSuppressed(ctx, to)
+	if err != nil {
+		return err
+	}
+	if suppressed {
+		ns.mCounter.WithLabelValues("email_suppressed_total").Inc()
+		return nil
+	}
+
+	if err = ns.email.Send(ctx, to, msg.Subject, msg.HTML); err != nil {
+		if errors.Is(err, notification.ErrRecipientRejected) {
+			// never again: repeated sends to dead addresses hurt the sender reputation
+			ns.logger.Warn("email rejected, the address is suppressed", zap.Error(err))
+			ns.mCounter.WithLabelValues("email_rejected_total").Inc()
+			return ns.repository.Suppress(ctx, to, notification.SuppressionRejected)
+		}
+		ns.mCounter.WithLabelValues("email_failed_total").Inc()
+		return err
+	}
+
+	ns.mCounter.WithLabelValues("email_sent_total").Inc()
+
+	return nil
+}
+
+// render - the subject is plain text, so the escaping of html/template is undone
+func (ns *NotificationService) render(name string, data emailData, payload webhookPayload) (message, error) {
+	t := ns.templates[name]
+
+	var subject, body bytes.Buffer
+	if err := t.ExecuteTemplate(&subject, "subject", data); err != nil {
+		return message{}, err
+	}
+	if err := t.ExecuteTemplate(&body, "layout", data); err != nil {
+		return message{}, err
+	}
+	msg := message{
+		Subject: strings.TrimSpace(html.UnescapeString(subject.String())),
+		HTML:    body.String(),
+	}
+
+	payload.Subject = msg.Subject
+	wh, err := json.Marshal(payload)
+	if err != nil {
+		return message{}, err
+	}
+	msg.Webhook = wh
+
+	return msg, nil
+}
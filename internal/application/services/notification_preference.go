@@ -0,0 +1,55 @@
+package services
+
+import (
+	"context"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"user-manager-api/internal/application/ports"
+	"user-manager-api/internal/domain/notification"
+	"user-manager-api/internal/domain/user"
+)
+
+type NotificationPreferenceService struct {
+	repository notification.Repository
+	mCounter   *prometheus.CounterVec
+}
+
+func NewNotificationPreferenceService(
+	repository notification.Repository,
+	mCounter *prometheus.CounterVec,
+) ports.NotificationPreferenceService {
+	return &NotificationPreferenceService{repository: repository, mCounter: mCounter}
+}
+
+func (ps *NotificationPreferenceService) Preferences(ctx context.Context, userUUID user.UUID) (notification.Preferences, error) {
+	set, err := ps.repository.FetchPreferences(ctx, userUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make(notification.Preferences, len(notification.Channels))
+	for i, c := range notification.Channels {
+		res[i] = set.Channel(c)
+	}
+
+	return res, nil
+}
+
+func (ps *NotificationPreferenceService) SetPreferences(
+	ctx context.Context,
+	userUUID user.UUID,
+	p notification.Preferences,
+) (notification.Preferences, error) {
+	found, err := ps.repository.SavePreferences(ctx, userUUID, p)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, ErrUserNotFound
+	}
+
+	ps.mCounter.WithLabelValues("notification_preferences_changed_total").Inc()
+
+	return ps.Preferences(ctx, userUUID)
+}
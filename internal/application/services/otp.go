@@ -0,0 +1,185 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"user-manager-api/internal/application/ports"
+	"user-manager-api/internal/domain/otp"
+	"user-manager-api/internal/domain/user"
+)
+
+var ErrInvalidOTP = errors.New("invalid or expired code")
+
+// OTPRateLimitedError - the phone asked for a code too recently
+type OTPRateLimitedError struct {
+	RetryAfter time.Duration
+}
+
+func (e *OTPRateLimitedError) Error() string {
+	return fmt.Sprintf("otp requested too often, retry after %s", e.RetryAfter)
+}
+
+type OTPSettings struct {
+	TTL         time.Duration
+	CodeLength  int
+	MaxAttempts int
+	Cooldown    time.Duration
+}
+
+type OTPService struct {
+	otpRepository     otp.Repository
+	userRepository    user.Repository
+	suspensionService ports.SuspensionService
+	smsSender         ports.SMSSender
+	tokenService      ports.TokenService
+	mCounter          *prometheus.CounterVec
+	settings          OTPSettings
+}
+
+func NewOTPService(
+	otpRepository otp.Repository,
+	userRepository user.Repository,
+	suspensionService ports.SuspensionService,
+	smsSender ports.SMSSender,
+	tokenService ports.TokenService,
+	mCounter *prometheus.CounterVec,
+	settings OTPSettings,
+) ports.OTPService {
+	return &OTPService{
+		otpRepository:     otpRepository,
+		userRepository:    userRepository,
+		suspensionService: suspensionService,
+		smsSender:         smsSender,
+		tokenService:      tokenService,
+		mCounter:          mCounter,
+		settings:          settings,
+	}
+}
+
+func (otps *OTPService) RequestOTP(ctx context.Context, phone string) error {
+	// the cooldown is applied before the lookup: the answer must not
+	// depend on whether the phone belongs to a user. It is kept in the
+	// database: the instances share it
+	if otps.settings.Cooldown > 0 {
+		retryAfter, err := otps.otpRepository.ClaimCooldown(ctx, phone, otps.settings.Cooldown)
+		if err != nil {
+			return err
+		}
+		if retryAfter > 0 {
+			otps.mCounter.WithLabelValues("otp_rate_limited_total").Inc()
+			return &OTPRateLimitedError{RetryAfter: retryAfter}
+		}
+	}
+
+	u, err := otps.userRepository.FetchUserByPhone(ctx, phone)
+	if errors.Is(err, user.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	id, err := otps.userRepository.FetchInternalID(ctx, u.UUID)
+	if err != nil {
+		return err
+	}
+
+	code, err := generateOTPCode(otps.settings.CodeLength)
+	if err != nil {
+		return err
+	}
+	if err = otps.otpRepository.UpsertOTP(ctx, otp.OTP{
+		UserID:    id,
+		CodeHash:  hashOTPCode(code),
+		ExpiresAt: time.Now().Add(otps.settings.TTL),
+	}); err != nil {
+		return err
+	}
+
+	text := fmt.Sprintf("Your login code: %s. It expires in %s.", code, otps.settings.TTL)
+	if err = otps.smsSender.Send(ctx, phone, text); err != nil {
+		return err
+	}
+
+	otps.mCounter.WithLabelValues("otp_sent_total").Inc()
+
+	return nil
+}
+
+func (otps *OTPService) VerifyOTP(ctx context.Context, phone, code string) (string, error) {
+	u, err := otps.userRepository.FetchUserByPhone(ctx, phone)
+	if errors.Is(err, user.ErrNotFound) {
+		otps.mCounter.WithLabelValues("otp_verify_failed_total").Inc()
+		return "", ErrInvalidOTP
+	}
+	if err != nil {
+		return "", err
+	}
+	id, err := otps.userRepository.FetchInternalID(ctx, u.UUID)
+	if err != nil {
+		return "", err
+	}
+
+	// the attempt is counted before the comparison, in the same statement
+	// as the check of the max: the concurrent guesses can not exceed it
+	o, err := otps.otpRepository.ClaimAttempt(ctx, id, otps.settings.MaxAttempts)
+	if err != nil {
+		return "", err
+	}
+	if o == nil || subtle.ConstantTimeCompare(o.CodeHash, hashOTPCode(code)) != 1 {
+		otps.mCounter.WithLabelValues("otp_verify_failed_total").Inc()
+		return "", ErrInvalidOTP
+	}
+
+	// single use: of the concurrent verifications of the code the one that
+	// deletes it wins
+	deleted, err := otps.otpRepository.DeleteOTP(ctx, id, o.CodeHash)
+	if err != nil {
+		return "", err
+	}
+	if !deleted {
+		otps.mCounter.WithLabelValues("otp_verify_failed_total").Inc()
+		return "", ErrInvalidOTP
+	}
+	if u.PasswordResetRequired {
+		return "", ErrPasswordResetRequired
+	}
+	if err = otps.suspensionService.CheckLogin(ctx, u); err != nil {
+		return "", err
+	}
+
+	token, err := otps.tokenService.GenerateToken(u.UUID.String(), u.Role, time.Hour)
+	if err != nil {
+		return "", ErrFailedToGenerateToken
+	}
+	if err = otps.userRepository.TouchLastSeen(ctx, u.UUID); err != nil {
+		return "", err
+	}
+
+	otps.mCounter.WithLabelValues("otp_login_total").Inc()
+
+	return token, nil
+}
+
+func generateOTPCode(length int) (string, error) {
+	max := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(length)), nil)
+	n, err := rand.Int(rand.Reader, max)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%0*d", length, n), nil
+}
+
+func hashOTPCode(code string) []byte {
+	sum := sha256.Sum256([]byte(code))
+	return sum[:]
+}
@@ -0,0 +1,138 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+
+	"user-manager-api/internal/application/ports"
+	"user-manager-api/internal/domain/outbox"
+	"user-manager-api/internal/infrastructure/mq"
+)
+
+const outboxBatchSize = 100
+
+type OutboxService struct {
+	publisher        ports.RabbitMQ
+	health           ports.DependencyHealth
+	outboxRepository outbox.Repository
+	logger           *zap.Logger
+	mCounter         *prometheus.CounterVec
+}
+
+// NewOutboxService - publisher is the broker's own, not the NewOutboxPublisher one
+func NewOutboxService(
+	publisher ports.RabbitMQ,
+	health ports.DependencyHealth,
+	outboxRepository outbox.Repository,
+	logger *zap.Logger,
+	mCounter *prometheus.CounterVec,
+) ports.OutboxService {
+	return &OutboxService{
+		publisher:        publisher,
+		health:           health,
+		outboxRepository: outboxRepository,
+		logger:           logger,
+		mCounter:         mCounter,
+	}
+}
+
+func (os *OutboxService) Keep(ctx context.Context, e mq.Event) error {
+	body, err := json.Marshal(e)
+	if err == nil {
+		err = os.outboxRepository.Add(ctx, outbox.Message{EventID: e.Id, EventType: e.Method, Body: body})
+	}
+	if err != nil {
+		// alert: the database is down too
+		os.mCounter.WithLabelValues("mq_events_dropped_total").Inc()
+		os.logger.Error("mq event dropped: not kept in the outbox",
+			zap.String("event_id", e.Id.String()),
+			zap.String("event_action", e.Method),
+			zap.Error(err),
+		)
+		return err
+	}
+	os.mCounter.WithLabelValues("mq_events_outboxed_total").Inc()
+
+	return nil
+}
+
+// Relay - a relayed event the broker fails later is kept again(the drop
+// handler), one relayed but not deleted is published twice: the consumers
+// deduplicate by the event id. An event the publisher rejects for good
+// (unroutable, invalid) is discarded, it would be rejected every run.
+func (os *OutboxService) Relay(ctx context.Context) (int, error) {
+	var relayed int
+	for os.health.Healthy() {
+		ms, err := os.outboxRepository.FetchOldest(ctx, outboxBatchSize)
+		if err != nil || len(ms) == 0 {
+			return relayed, err
+		}
+
+		done := make([]int64, 0, len(ms))
+		saturated := false
+		for _, m := range ms {
+			var e mq.Event
+			if err = json.Unmarshal(m.Body, &e); err == nil {
+				err = os.publisher.Publish(ctx, e)
+			}
+			if errors.Is(err, mq.ErrBackpressure) || ctx.Err() != nil {
+				saturated = true
+				break
+			}
+			if err != nil {
+				os.mCounter.WithLabelValues("mq_events_discarded_total").Inc()
+				os.logger.Error("outbox event discarded",
+					zap.String("event_id", m.EventID.String()),
+					zap.String("event_action", m.EventType),
+					zap.Error(err),
+				)
+			} else {
+				relayed++
+				os.mCounter.WithLabelValues("mq_events_relayed_total").Inc()
+			}
+			done = append(done, m.ID)
+		}
+
+		if len(done) > 0 {
+			if err = os.outboxRepository.Delete(context.WithoutCancel(ctx), done); err != nil {
+				return relayed, err
+			}
+		}
+		if saturated {
+			// the next run
+			return relayed, nil
+		}
+	}
+
+	return relayed, nil
+}
+
+// outboxPublisher keeps the events in the outbox while the broker is down(its
+// breaker is open) or saturated, so the mutations publishing them succeed
+type outboxPublisher struct {
+	ports.RabbitMQ
+	health ports.DependencyHealth
+	outbox ports.OutboxService
+}
+
+// NewOutboxPublisher - publisher with the outbox, for the services
+func NewOutboxPublisher(publisher ports.RabbitMQ, health ports.DependencyHealth, outbox ports.OutboxService) ports.RabbitMQ {
+	return &outboxPublisher{RabbitMQ: publisher, health: health, outbox: outbox}
+}
+
+func (p *outboxPublisher) Publish(ctx context.Context, e mq.Event) error {
+	if !p.health.Healthy() {
+		return p.outbox.Keep(ctx, e)
+	}
+
+	err := p.RabbitMQ.Publish(ctx, e)
+	if errors.Is(err, mq.ErrBackpressure) {
+		return p.outbox.Keep(ctx, e)
+	}
+
+	return err
+}
@@ -0,0 +1,44 @@
+package services
+
+import (
+	"context"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"user-manager-api/internal/application/ports"
+	domain "user-manager-api/internal/domain/user"
+)
+
+const piiBatchSize = 500
+
+type PIIService struct {
+	userRepository domain.Repository
+	mCounter       *prometheus.CounterVec
+}
+
+func NewPIIService(userRepository domain.Repository, mCounter *prometheus.CounterVec) ports.PIIService {
+	return &PIIService{
+		userRepository: userRepository,
+		mCounter:       mCounter,
+	}
+}
+
+// Reencrypt walks all users in batches, so a key rotation does not lock the table.
+func (ps *PIIService) Reencrypt(ctx context.Context) (int, error) {
+	var (
+		afterID domain.ID
+		total   int
+	)
+	for {
+		lastID, updated, err := ps.userRepository.ReencryptPII(ctx, afterID, piiBatchSize)
+		if err != nil {
+			return total, err
+		}
+		total += updated
+		ps.mCounter.WithLabelValues("pii_reencrypted_total").Add(float64(updated))
+		if lastID == 0 {
+			return total, nil
+		}
+		afterID = lastID
+	}
+}
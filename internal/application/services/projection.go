@@ -0,0 +1,46 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"user-manager-api/internal/application/ports"
+	"user-manager-api/internal/application/projections"
+	domain "user-manager-api/internal/domain/projection"
+	"user-manager-api/internal/infrastructure/mq"
+)
+
+type ProjectionService struct {
+	projectionRepository domain.Repository
+	mCounter             *prometheus.CounterVec
+}
+
+func NewProjectionService(projectionRepository domain.Repository, mCounter *prometheus.CounterVec) ports.ProjectionService {
+	return &ProjectionService{
+		projectionRepository: projectionRepository,
+		mCounter:             mCounter,
+	}
+}
+
+func (ps *ProjectionService) Record(ctx context.Context, e mq.Event) error {
+	stored := projections.FromEvent(e)
+	ok, err := ps.projectionRepository.Append(ctx, stored, projections.Fold(stored))
+	if err != nil || !ok {
+		return err
+	}
+	ps.mCounter.WithLabelValues("events_stored_total").Inc()
+
+	return nil
+}
+
+func (ps *ProjectionService) Rebuild(ctx context.Context) (int64, error) {
+	return ps.projectionRepository.Rebuild(ctx, projections.Fold)
+}
+
+func (ps *ProjectionService) UserSummary(ctx context.Context, userUUID uuid.UUID, days int) (*domain.UserSummary, error) {
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	return ps.projectionRepository.FetchUserSummary(ctx, userUUID, today.AddDate(0, 0, 1-days))
+}
@@ -0,0 +1,119 @@
+package services
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+
+	"user-manager-api/internal/application/ports"
+	"user-manager-api/internal/domain/audit"
+	"user-manager-api/internal/domain/mode"
+)
+
+type (
+	ReadOnlySettings struct {
+		// Forced - mode.ForcedConfig, mode.ForcedSchema or empty
+		Forced       string
+		PollInterval time.Duration
+	}
+	ReadOnlyService struct {
+		repository   mode.Repository
+		auditService ports.AuditService
+		logger       *zap.Logger
+		mCounter     *prometheus.CounterVec
+		forced       string
+		pollInterval time.Duration
+		// enabled - the last toggle read, kept while the database is unreachable
+		enabled atomic.Bool
+	}
+)
+
+func NewReadOnlyService(
+	repository mode.Repository,
+	auditService ports.AuditService,
+	logger *zap.Logger,
+	mCounter *prometheus.CounterVec,
+	settings ReadOnlySettings,
+) ports.ReadOnlyService {
+	return &ReadOnlyService{
+		repository:   repository,
+		auditService: auditService,
+		logger:       logger,
+		mCounter:     mCounter,
+		forced:       settings.Forced,
+		pollInterval: settings.PollInterval,
+	}
+}
+
+func (ros *ReadOnlyService) Enabled() bool {
+	return ros.forced != "" || ros.enabled.Load()
+}
+
+func (ros *ReadOnlyService) Status(ctx context.Context) (*mode.ReadOnly, error) {
+	ro, err := ros.repository.FetchReadOnly(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ros.enabled.Store(ro.Enabled)
+	ro.Forced = ros.forced
+
+	return ro, nil
+}
+
+func (ros *ReadOnlyService) Set(ctx context.Context, actor uuid.UUID, enabled bool, reason string) (*mode.ReadOnly, error) {
+	ro, err := ros.repository.SaveReadOnly(ctx, enabled, reason, actor)
+	if err != nil {
+		return nil, err
+	}
+	ros.enabled.Store(ro.Enabled)
+	ro.Forced = ros.forced
+	ros.mCounter.WithLabelValues("read_only_changed_total").Inc()
+
+	// the mode is switched already, a failed entry is in the log(Record)
+	if err = ros.auditService.Record(ctx, audit.Entry{
+		ActorUUID: actor,
+		Action:    audit.ActionReadOnlyChanged,
+		Details:   map[string]any{"enabled": enabled, "reason": reason},
+	}); err != nil {
+		ros.logger.Error("read-only audit error", zap.Error(err))
+	}
+
+	return ro, nil
+}
+
+func (ros *ReadOnlyService) Worker(ctx context.Context) {
+	ros.logger.Info("starting read-only worker")
+
+	defer func() {
+		ros.logger.Info("read-only worker gracefully stopped")
+	}()
+
+	ticker := time.NewTicker(ros.pollInterval)
+	defer ticker.Stop()
+
+	ros.poll(ctx)
+	for {
+		select {
+		case <-ticker.C:
+			ros.poll(ctx)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (ros *ReadOnlyService) poll(ctx context.Context) {
+	was := ros.enabled.Load()
+	ro, err := ros.Status(ctx)
+	if err != nil {
+		ros.logger.Warn("read-only poll error, keeping the last mode", zap.Error(err), zap.Bool("enabled", was))
+		return
+	}
+	if ro.Enabled != was {
+		ros.logger.Info("read-only mode changed", zap.Bool("enabled", ro.Enabled), zap.String("reason", ro.Reason))
+	}
+}
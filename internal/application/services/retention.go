@@ -0,0 +1,92 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"user-manager-api/internal/application/ports"
+	"user-manager-api/internal/domain/audit"
+	domain "user-manager-api/internal/domain/user"
+)
+
+const retentionBatchSize = 500
+
+// RetentionSettings - InactiveMonths == 0 disables the redaction
+type RetentionSettings struct {
+	InactiveMonths int
+	Columns        []domain.PIIColumn
+}
+
+type RetentionService struct {
+	userRepository domain.Repository
+	auditService   ports.AuditService
+	mCounter       *prometheus.CounterVec
+	settings       RetentionSettings
+}
+
+func NewRetentionService(
+	userRepository domain.Repository,
+	auditService ports.AuditService,
+	mCounter *prometheus.CounterVec,
+	settings RetentionSettings,
+) ports.RetentionService {
+	return &RetentionService{
+		userRepository: userRepository,
+		auditService:   auditService,
+		mCounter:       mCounter,
+		settings:       settings,
+	}
+}
+
+// RedactInactive - redacted users no longer match the candidates query, so the
+// first batch is fetched until it is empty. The system is the actor(uuid.Nil)
+// of the audit entries.
+func (rs *RetentionService) RedactInactive(ctx context.Context) (int, error) {
+	if rs.settings.InactiveMonths == 0 || len(rs.settings.Columns) == 0 {
+		return 0, nil
+	}
+	seenBefore := time.Now().AddDate(0, -rs.settings.InactiveMonths, 0)
+
+	var total int
+	for {
+		uuids, err := rs.userRepository.FetchRedactionCandidates(ctx, seenBefore, rs.settings.Columns, retentionBatchSize)
+		if err != nil {
+			return total, err
+		}
+
+		var redacted int
+		for _, u := range uuids {
+			ok, err := rs.userRepository.RedactPII(ctx, u, seenBefore, rs.settings.Columns)
+			if err != nil {
+				return total, err
+			}
+			// logged in meanwhile
+			if !ok {
+				continue
+			}
+
+			target := u
+			if err = rs.auditService.Record(ctx, audit.Entry{
+				ActorUUID:  uuid.Nil,
+				Action:     audit.ActionPIIRedacted,
+				TargetUUID: &target,
+				Details: map[string]any{
+					"columns":         rs.settings.Columns,
+					"inactive_months": rs.settings.InactiveMonths,
+				},
+			}); err != nil {
+				return total, err
+			}
+			rs.mCounter.WithLabelValues("pii_redacted_total").Inc()
+			redacted++
+		}
+
+		total += redacted
+		if redacted == 0 {
+			return total, nil
+		}
+	}
+}
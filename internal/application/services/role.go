@@ -0,0 +1,163 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"user-manager-api/internal/application/ports"
+	"user-manager-api/internal/domain/audit"
+	"user-manager-api/internal/domain/token"
+	domain "user-manager-api/internal/domain/user"
+)
+
+// maxCachedRoles - the users whose role an instance caches, a full cache is
+// emptied of the expired entries first, then at all
+const maxCachedRoles = 10_000
+
+type (
+	RoleService struct {
+		userRepository domain.Repository
+		auditService   ports.AuditService
+		mCounter       *prometheus.CounterVec
+
+		cacheTTL time.Duration
+		mu       sync.Mutex
+		roles    map[domain.UUID]cachedRole
+	}
+	cachedRole struct {
+		role    string
+		expires time.Time
+	}
+)
+
+// NewRoleService - cacheTTL 0: the role is read on every request
+func NewRoleService(
+	userRepository domain.Repository,
+	auditService ports.AuditService,
+	cacheTTL time.Duration,
+	mCounter *prometheus.CounterVec,
+) ports.RoleService {
+	return &RoleService{
+		userRepository: userRepository,
+		auditService:   auditService,
+		mCounter:       mCounter,
+		cacheTTL:       cacheTTL,
+		roles:          make(map[domain.UUID]cachedRole),
+	}
+}
+
+func (rs *RoleService) AssignRoles(
+	ctx context.Context,
+	actor domain.UUID,
+	changes []domain.RoleChange,
+) ([]domain.RoleChangeResult, error) {
+	results, err := rs.userRepository.SetRoles(ctx, changes)
+	if err != nil {
+		return nil, err
+	}
+
+	// the roles are switched already: every change gets its entry even if
+	// some of them fail(Record writes them to the log first)
+	var errs []error
+	for _, r := range results {
+		if r.Status != domain.RoleChanged {
+			continue
+		}
+		// the other instances catch up within the TTL
+		rs.forget(r.UUID)
+
+		target := r.UUID
+		errs = append(errs, rs.auditService.Record(ctx, audit.Entry{
+			ActorUUID:  actor,
+			Action:     audit.ActionRoleChanged,
+			TargetUUID: &target,
+			Details:    map[string]any{"from": r.PrevRole, "to": r.Role},
+		}))
+		rs.mCounter.WithLabelValues("user_role_changed_total").Inc()
+	}
+	if err = errors.Join(errs...); err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// CurrentRole - a deleted user keeps the role claim, its tokens are revoked by
+// the deletion. An impersonation never gets the admin role: the admins can
+// not be impersonated(see ImpersonationService)
+func (rs *RoleService) CurrentRole(ctx context.Context, claims *token.Claims) (string, error) {
+	id, err := uuid.Parse(claims.UserID)
+	if err != nil {
+		return claims.Role, nil
+	}
+
+	role, ok := rs.cached(id)
+	if !ok {
+		role, err = rs.userRepository.FetchRole(ctx, id)
+		if errors.Is(err, domain.ErrNotFound) {
+			return claims.Role, nil
+		}
+		if err != nil {
+			return "", err
+		}
+		rs.cache(id, role)
+	}
+
+	if claims.ActAs != "" && role == domain.RoleAdmin {
+		return claims.Role, nil
+	}
+	if role != claims.Role {
+		rs.mCounter.WithLabelValues("role_claim_refreshed_total").Inc()
+	}
+
+	return role, nil
+}
+
+func (rs *RoleService) cached(id domain.UUID) (string, bool) {
+	if rs.cacheTTL == 0 {
+		return "", false
+	}
+
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+
+	c, ok := rs.roles[id]
+	if !ok || time.Now().After(c.expires) {
+		return "", false
+	}
+
+	return c.role, true
+}
+
+func (rs *RoleService) cache(id domain.UUID, role string) {
+	if rs.cacheTTL == 0 {
+		return
+	}
+	now := time.Now()
+
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+
+	if len(rs.roles) >= maxCachedRoles {
+		for k, c := range rs.roles {
+			if now.After(c.expires) {
+				delete(rs.roles, k)
+			}
+		}
+		if len(rs.roles) >= maxCachedRoles {
+			clear(rs.roles)
+		}
+	}
+	rs.roles[id] = cachedRole{role: role, expires: now.Add(rs.cacheTTL)}
+}
+
+func (rs *RoleService) forget(id domain.UUID) {
+	rs.mu.Lock()
+	delete(rs.roles, id)
+	rs.mu.Unlock()
+}
@@ -0,0 +1,86 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"user-manager-api/internal/domain/token"
+	domain "user-manager-api/internal/domain/user"
+)
+
+// roleRepository - the roles of the users, FetchRole calls counted
+type roleRepository struct {
+	domain.Repository
+	roles   map[domain.UUID]string
+	fetches int
+}
+
+func (r *roleRepository) FetchRole(_ context.Context, id domain.UUID) (string, error) {
+	r.fetches++
+	role, ok := r.roles[id]
+	if !ok {
+		return "", domain.ErrNotFound
+	}
+	return role, nil
+}
+
+func TestRoleService_CurrentRole(t *testing.T) {
+	userID, deletedID := uuid.New(), uuid.New()
+	mCounter := prometheus.NewCounterVec(prometheus.CounterOpts{Name: "test"}, []string{"result"})
+	worker := &token.Claims{UserID: userID.String(), Role: domain.RoleWorker}
+
+	t.Run("cached", func(t *testing.T) {
+		repo := &roleRepository{roles: map[domain.UUID]string{userID: domain.RoleAdmin}}
+		rs := NewRoleService(repo, nil, time.Minute, mCounter)
+
+		for range 3 {
+			role, err := rs.CurrentRole(context.Background(), worker)
+			require.NoError(t, err)
+			assert.Equal(t, domain.RoleAdmin, role, "promoted after the token was issued")
+		}
+		assert.Equal(t, 1, repo.fetches)
+
+		repo.roles[userID] = domain.RoleWorker
+		rs.(*RoleService).forget(userID)
+		role, err := rs.CurrentRole(context.Background(), worker)
+		require.NoError(t, err)
+		assert.Equal(t, domain.RoleWorker, role)
+	})
+
+	t.Run("no cache", func(t *testing.T) {
+		repo := &roleRepository{roles: map[domain.UUID]string{userID: domain.RoleWorker}}
+		rs := NewRoleService(repo, nil, 0, mCounter)
+
+		for range 3 {
+			_, err := rs.CurrentRole(context.Background(), worker)
+			require.NoError(t, err)
+		}
+		assert.Equal(t, 3, repo.fetches)
+	})
+
+	tests := []struct {
+		name   string
+		claims *token.Claims
+		want   string
+	}{
+		{"deleted user keeps the claim", &token.Claims{UserID: deletedID.String(), Role: domain.RoleWorker}, domain.RoleWorker},
+		{"impersonation never admin", &token.Claims{UserID: userID.String(), Role: domain.RoleWorker, ActAs: uuid.NewString()}, domain.RoleWorker},
+		{"invalid user id", &token.Claims{UserID: "svc", Role: domain.RoleWorker}, domain.RoleWorker},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &roleRepository{roles: map[domain.UUID]string{userID: domain.RoleAdmin}}
+			rs := NewRoleService(repo, nil, time.Minute, mCounter)
+
+			role, err := rs.CurrentRole(context.Background(), tt.claims)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, role)
+		})
+	}
+}
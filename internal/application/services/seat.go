@@ -0,0 +1,85 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"user-manager-api/internal/application/ports"
+	"user-manager-api/internal/domain/usage"
+	domainUser "user-manager-api/internal/domain/user"
+)
+
+type SeatService struct {
+	usageRepository usage.Repository
+	mCounter        *prometheus.CounterVec
+}
+
+func NewSeatService(usageRepository usage.Repository, mCounter *prometheus.CounterVec) ports.SeatService {
+	return &SeatService{
+		usageRepository: usageRepository,
+		mCounter:        mCounter,
+	}
+}
+
+func (ss *SeatService) SeatLimits(ctx context.Context) ([]usage.SeatLimit, error) {
+	return ss.usageRepository.FetchSeatLimits(ctx)
+}
+
+// SetSeatLimit - a limit under the active users only blocks the new users,
+// nobody is deleted
+func (ss *SeatService) SetSeatLimit(ctx context.Context, org string, seats uint32) (*usage.SeatLimit, error) {
+	l, err := ss.usageRepository.SaveSeatLimit(ctx, org, seats)
+	if err != nil {
+		return nil, err
+	}
+	ss.mCounter.WithLabelValues("seat_limit_changed_total").Inc()
+
+	return l, nil
+}
+
+// checkSeat - ErrSeatLimitReached if the organization of the email has no free
+// seat for one more user
+func checkSeat(ctx context.Context, usageRepository usage.Repository, mCounter *prometheus.CounterVec, email string) error {
+	free, err := hasFreeSeat(ctx, usageRepository, email)
+	if err != nil {
+		return err
+	}
+	if !free {
+		mCounter.WithLabelValues("seat_limit_rejected_total").Inc()
+		return ErrSeatLimitReached
+	}
+
+	return nil
+}
+
+// countSeatRejected counts err when it is the rejection by the database: the
+// pre-check of checkSeat passed, a concurrent write took the last seat meanwhile
+func countSeatRejected(mCounter *prometheus.CounterVec, err error) {
+	if errors.Is(err, ErrSeatLimitReached) {
+		mCounter.WithLabelValues("seat_limit_rejected_total").Inc()
+	}
+}
+
+// hasFreeSeat - checkSeat without counting the rejection, for the dry runs
+func hasFreeSeat(ctx context.Context, usageRepository usage.Repository, email string) (bool, error) {
+	limit, err := usageRepository.FetchSeatLimit(ctx, domainUser.Organization(email))
+	if err != nil {
+		return false, err
+	}
+
+	return limit == nil || limit.ActiveUsers < uint64(limit.Seats), nil
+}
+
+func (ss *SeatService) RemoveSeatLimit(ctx context.Context, org string) (bool, error) {
+	removed, err := ss.usageRepository.DeleteSeatLimit(ctx, org)
+	if err != nil {
+		return false, err
+	}
+	if removed {
+		ss.mCounter.WithLabelValues("seat_limit_changed_total").Inc()
+	}
+
+	return removed, nil
+}
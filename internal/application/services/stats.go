@@ -0,0 +1,73 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"user-manager-api/internal/application/ports"
+	domain "user-manager-api/internal/domain/stats"
+	"user-manager-api/internal/infrastructure/mq"
+)
+
+type StatsService struct {
+	statsRepository domain.Repository
+	mCounter        *prometheus.CounterVec
+}
+
+func NewStatsService(statsRepository domain.Repository, mCounter *prometheus.CounterVec) ports.StatsService {
+	return &StatsService{
+		statsRepository: statsRepository,
+		mCounter:        mCounter,
+	}
+}
+
+// ApplyEvent refreshes the affected aggregate rows from the source tables
+// instead of incrementing them, so redelivered and reordered events are harmless.
+func (ss *StatsService) ApplyEvent(ctx context.Context, e mq.Event) error {
+	switch e.Method {
+	case http.MethodPost:
+		day := e.TS
+		if v, ok := e.Meta["created_at"]; ok {
+			t, err := time.Parse(time.RFC3339Nano, v)
+			if err != nil {
+				return fmt.Errorf("event %s: invalid created_at: %w", e.Id, err)
+			}
+			day = t
+		}
+		if err := ss.statsRepository.RefreshDailySignups(ctx, day); err != nil {
+			return err
+		}
+	case http.MethodDelete, mq.EventUserFilesChanged:
+		// a deleted user's files are deleted with it
+		id, err := uuid.Parse(e.UserID)
+		if err != nil {
+			return fmt.Errorf("event %s: invalid user_id: %w", e.Id, err)
+		}
+		if err = ss.statsRepository.RefreshUserFileCounts(ctx, id); err != nil {
+			return err
+		}
+	default:
+		return nil
+	}
+
+	ss.mCounter.WithLabelValues("stats_refreshed_total").Inc()
+
+	return nil
+}
+
+func (ss *StatsService) Rebuild(ctx context.Context) error {
+	return ss.statsRepository.Rebuild(ctx)
+}
+
+func (ss *StatsService) DailySignups(ctx context.Context, from, to time.Time) ([]domain.DailySignups, error) {
+	return ss.statsRepository.FetchDailySignups(ctx, from, to)
+}
+
+func (ss *StatsService) FilesTotals(ctx context.Context) (*domain.FilesTotals, error) {
+	return ss.statsRepository.FetchFilesTotals(ctx)
+}
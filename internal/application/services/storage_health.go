@@ -0,0 +1,57 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/sony/gobreaker/v2"
+
+	"user-manager-api/internal/application/ports"
+	"user-manager-api/pkg/bulkhead"
+)
+
+// minRetryAfter - of a dependency probed right now(half-open) or saturated
+const minRetryAfter = time.Second
+
+// UnavailableError - a soft dependency is down, the request is worth retrying
+// after RetryAfter
+type UnavailableError struct {
+	Dependency string
+	RetryAfter time.Duration
+}
+
+func (e *UnavailableError) Error() string {
+	return fmt.Sprintf("%s is unavailable, retry after %s", e.Dependency, e.RetryAfter)
+}
+
+// healthStorage fails the uploads fast with UnavailableError while the storage
+// is down instead of waiting for the driver to give up. The file metadata is
+// read from the database with the stored URLs, so it is served meanwhile.
+type healthStorage struct {
+	ports.ObjectStorage
+	health ports.DependencyHealth
+}
+
+func NewHealthStorage(storage ports.ObjectStorage, health ports.DependencyHealth) ports.ObjectStorage {
+	return &healthStorage{ObjectStorage: storage, health: health}
+}
+
+func (s *healthStorage) PutObject(ctx context.Context, key, contentType string, body io.ReadSeeker, size int64) error {
+	if !s.health.Healthy() {
+		return s.unavailable()
+	}
+
+	err := s.ObjectStorage.PutObject(ctx, key, contentType, body, size)
+	if errors.Is(err, gobreaker.ErrOpenState) || errors.Is(err, bulkhead.ErrFull) {
+		return s.unavailable()
+	}
+
+	return err
+}
+
+func (s *healthStorage) unavailable() error {
+	return &UnavailableError{Dependency: "storage", RetryAfter: max(s.health.RetryAfter(), minRetryAfter)}
+}
@@ -0,0 +1,42 @@
+package services
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"user-manager-api/internal/application/ports"
+)
+
+// timeoutStorage bounds every storage operation as a whole, retries of the
+// driver included, on top of the caller's deadline.
+type timeoutStorage struct {
+	ports.ObjectStorage
+	timeout time.Duration
+}
+
+// NewTimeoutStorage - 0 timeout returns storage as is
+func NewTimeoutStorage(storage ports.ObjectStorage, timeout time.Duration) ports.ObjectStorage {
+	if timeout <= 0 {
+		return storage
+	}
+	return &timeoutStorage{ObjectStorage: storage, timeout: timeout}
+}
+
+func (s *timeoutStorage) PutObject(ctx context.Context, key, contentType string, body io.ReadSeeker, size int64) error {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+	return s.ObjectStorage.PutObject(ctx, key, contentType, body, size)
+}
+
+func (s *timeoutStorage) DeleteObjects(ctx context.Context, keys []string) error {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+	return s.ObjectStorage.DeleteObjects(ctx, keys)
+}
+
+func (s *timeoutStorage) ListObjects(ctx context.Context, prefix string) ([]string, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+	return s.ObjectStorage.ListObjects(ctx, prefix)
+}
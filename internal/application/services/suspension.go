@@ -0,0 +1,225 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+
+	"user-manager-api/internal/application/ports"
+	"user-manager-api/internal/domain/audit"
+	domain "user-manager-api/internal/domain/user"
+	"user-manager-api/internal/infrastructure/mq"
+	"user-manager-api/internal/interface/api/rest/dto/user"
+)
+
+const suspensionBatchSize = 500
+
+// the "via" of the reactivations(audit details, event meta)
+const (
+	reactivatedByLogin = "login"
+	reactivatedByAdmin = "admin"
+)
+
+var ErrAccountSuspended = errors.New("account suspended for inactivity")
+
+// SuspensionSettings - InactiveDays == 0 disables the suspension, the suspended
+// users stay suspended
+type SuspensionSettings struct {
+	InactiveDays int
+	// WarnDays - the warning goes that many days before the suspension, 0 - none
+	WarnDays int
+	// GraceDays - a login within them after the suspension lifts it, 0 - only an
+	// admin does
+	GraceDays int
+}
+
+type SuspensionService struct {
+	userRepository domain.Repository
+	auditService   ports.AuditService
+	mq             ports.RabbitMQ
+	logger         *zap.Logger
+	mCounter       *prometheus.CounterVec
+	settings       SuspensionSettings
+}
+
+func NewSuspensionService(
+	userRepository domain.Repository,
+	auditService ports.AuditService,
+	mq ports.RabbitMQ,
+	logger *zap.Logger,
+	mCounter *prometheus.CounterVec,
+	settings SuspensionSettings,
+) ports.SuspensionService {
+	return &SuspensionService{
+		userRepository: userRepository,
+		auditService:   auditService,
+		mq:             mq,
+		logger:         logger,
+		mCounter:       mCounter,
+		settings:       settings,
+	}
+}
+
+// SuspendInactive - a user is suspended WarnDays after its warning at the
+// earliest, so a warning missed(the job was off) delays the suspension instead
+// of skipping the warning. The warned and the suspended users no longer match
+// their queries, so the batches are fetched until one is short. A suspension is
+// stored with its audit entry, an event failed for a user is logged and the
+// rest go on: the error is the first of them.
+func (ss *SuspensionService) SuspendInactive(ctx context.Context) (warned, suspended int, err error) {
+	if ss.settings.InactiveDays == 0 {
+		return 0, 0, nil
+	}
+	now := time.Now()
+
+	var (
+		warnedBefore *time.Time
+		failed       error
+	)
+	if ss.settings.WarnDays > 0 {
+		before := now.AddDate(0, 0, -ss.settings.WarnDays)
+		warnedBefore = &before
+		// the users warned by now are suspended whatever the others
+		warned, failed = ss.warnInactive(ctx, now)
+	}
+
+	seenBefore := now.AddDate(0, 0, -ss.settings.InactiveDays)
+	meta := map[string]string{"inactive_days": strconv.Itoa(ss.settings.InactiveDays)}
+	for {
+		uuids, err := ss.userRepository.SuspendInactive(ctx, seenBefore, warnedBefore, suspensionBatchSize, ss.settings.InactiveDays)
+		if err != nil {
+			return warned, suspended, errors.Join(failed, err)
+		}
+		for _, u := range uuids {
+			target := u
+			ss.auditService.Recorded(audit.Entry{
+				ActorUUID:  uuid.Nil,
+				Action:     audit.ActionUserSuspended,
+				TargetUUID: &target,
+				Details:    map[string]any{"inactive_days": ss.settings.InactiveDays},
+			})
+			ss.mCounter.WithLabelValues("user_suspended_total").Inc()
+			suspended++
+
+			if err = ss.publish(ctx, mq.EventUserSuspended, u, meta); err != nil {
+				ss.logger.Error("suspension event error", zap.Error(err), zap.Stringer("user_uuid", u))
+				if failed == nil {
+					failed = err
+				}
+			}
+		}
+		if len(uuids) < suspensionBatchSize {
+			return warned, suspended, failed
+		}
+	}
+}
+
+// warnInactive - the users suspended in WarnDays unless they log in. As the
+// suspensions, a failed warning is logged and the rest go on
+func (ss *SuspensionService) warnInactive(ctx context.Context, now time.Time) (int, error) {
+	seenBefore := now.AddDate(0, 0, ss.settings.WarnDays-ss.settings.InactiveDays)
+	meta := map[string]string{
+		mq.MetaSuspendAfter: now.AddDate(0, 0, ss.settings.WarnDays).Format(time.DateOnly),
+	}
+
+	var (
+		warned int
+		failed error
+	)
+	for {
+		uuids, err := ss.userRepository.WarnInactive(ctx, seenBefore, suspensionBatchSize)
+		if err != nil {
+			return warned, errors.Join(failed, err)
+		}
+		for _, u := range uuids {
+			if err = ss.publish(ctx, mq.EventInactivityWarning, u, meta); err != nil {
+				ss.logger.Error("inactivity warning event error", zap.Error(err), zap.Stringer("user_uuid", u))
+				if failed == nil {
+					failed = err
+				}
+				continue
+			}
+			ss.mCounter.WithLabelValues("inactivity_warned_total").Inc()
+			warned++
+		}
+		if len(uuids) < suspensionBatchSize {
+			return warned, failed
+		}
+	}
+}
+
+func (ss *SuspensionService) CheckLogin(ctx context.Context, u *domain.User) error {
+	suspendedAt, err := ss.userRepository.FetchSuspendedAt(ctx, u.UUID)
+	if err != nil || suspendedAt == nil {
+		return err
+	}
+	grace := time.Duration(ss.settings.GraceDays) * 24 * time.Hour
+	if time.Since(*suspendedAt) > grace {
+		ss.mCounter.WithLabelValues("suspended_login_rejected_total").Inc()
+		return ErrAccountSuspended
+	}
+
+	// lifted by a concurrent login of the user meanwhile: nothing left to do
+	_, err = ss.reactivate(ctx, u.UUID, u.UUID, reactivatedByLogin)
+	return err
+}
+
+func (ss *SuspensionService) Reactivate(ctx context.Context, actor, userUUID domain.UUID) (bool, error) {
+	return ss.reactivate(ctx, actor, userUUID, reactivatedByAdmin)
+}
+
+// reactivate - the suspension is lifted already, failed audit entries and events
+// are in the log(Record, the publisher)
+func (ss *SuspensionService) reactivate(ctx context.Context, actor, target domain.UUID, via string) (bool, error) {
+	reactivated, err := ss.userRepository.Reactivate(ctx, target)
+	if err != nil || !reactivated {
+		return false, err
+	}
+	ss.mCounter.WithLabelValues("user_reactivated_total").Inc()
+
+	if err = ss.auditService.Record(ctx, audit.Entry{
+		ActorUUID:  actor,
+		Action:     audit.ActionUserReactivated,
+		TargetUUID: &target,
+		Details:    map[string]any{"via": via},
+	}); err != nil {
+		ss.logger.Error("reactivation audit error", zap.Error(err), zap.Stringer("user_uuid", target))
+	}
+	publishEvent(ctx, ss.mq, mq.Event{
+		Id:     uuid.New(),
+		TS:     time.Now(),
+		Method: mq.EventUserReactivated,
+		UserID: target.String(),
+		Meta:   map[string]string{"via": via},
+	})
+
+	return true, nil
+}
+
+// publish - the payload carries the address the user is told at, deleted
+// meanwhile: nobody to tell
+func (ss *SuspensionService) publish(ctx context.Context, method string, userUUID domain.UUID, meta map[string]string) error {
+	u, err := ss.userRepository.FetchUserByID(ctx, userUUID)
+	if errors.Is(err, domain.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	publishEvent(ctx, ss.mq, mq.Event{
+		Id:      uuid.New(),
+		TS:      time.Now(),
+		Method:  method,
+		UserID:  u.UUID.String(),
+		Payload: user.ToResponseUser(*u),
+		Meta:    meta,
+	})
+
+	return nil
+}
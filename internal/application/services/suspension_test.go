@@ -0,0 +1,250 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"user-manager-api/internal/application/ports"
+	"user-manager-api/internal/domain/audit"
+	domain "user-manager-api/internal/domain/user"
+	"user-manager-api/internal/infrastructure/mq"
+)
+
+// suspensionRepository - the users last seen at seen, warned and suspended in memory.
+// The user broken cannot be read
+type suspensionRepository struct {
+	domain.Repository
+	seen      map[domain.UUID]time.Time
+	warned    map[domain.UUID]time.Time
+	suspended map[domain.UUID]time.Time
+	broken    domain.UUID
+}
+
+func (r *suspensionRepository) WarnInactive(_ context.Context, seenBefore time.Time, _ int) ([]domain.UUID, error) {
+	var out []domain.UUID
+	for id, seen := range r.seen {
+		_, warned := r.warned[id]
+		_, suspended := r.suspended[id]
+		if seen.Before(seenBefore) && !warned && !suspended {
+			r.warned[id] = time.Now()
+			out = append(out, id)
+		}
+	}
+	return out, nil
+}
+
+func (r *suspensionRepository) SuspendInactive(
+	_ context.Context,
+	seenBefore time.Time,
+	warnedBefore *time.Time,
+	_ int,
+	_ int,
+) ([]domain.UUID, error) {
+	var out []domain.UUID
+	for id, seen := range r.seen {
+		warnedAt, warned := r.warned[id]
+		_, suspended := r.suspended[id]
+		if !seen.Before(seenBefore) || suspended || (warnedBefore != nil && (!warned || !warnedAt.Before(*warnedBefore))) {
+			continue
+		}
+		r.suspended[id] = time.Now()
+		out = append(out, id)
+	}
+	return out, nil
+}
+
+func (r *suspensionRepository) FetchSuspendedAt(_ context.Context, id domain.UUID) (*time.Time, error) {
+	if _, ok := r.seen[id]; !ok {
+		return nil, domain.ErrNotFound
+	}
+	at, ok := r.suspended[id]
+	if !ok {
+		return nil, nil
+	}
+	return &at, nil
+}
+
+func (r *suspensionRepository) Reactivate(ctx context.Context, id domain.UUID) (bool, error) {
+	at, err := r.FetchSuspendedAt(ctx, id)
+	if err != nil || at == nil {
+		return false, err
+	}
+	delete(r.suspended, id)
+	delete(r.warned, id)
+	r.seen[id] = time.Now()
+	return true, nil
+}
+
+func (r *suspensionRepository) FetchUserByID(_ context.Context, id domain.UUID) (*domain.User, error) {
+	if id == r.broken {
+		return nil, errors.New("connection reset")
+	}
+	return &domain.User{UUID: id, Email: id.String() + "@example.com"}, nil
+}
+
+// recordingAudit - the actions recorded
+type recordingAudit struct {
+	ports.AuditService
+	actions []audit.Action
+}
+
+func (a *recordingAudit) Record(_ context.Context, e audit.Entry) error {
+	a.actions = append(a.actions, e.Action)
+	return nil
+}
+
+func (a *recordingAudit) Recorded(e audit.Entry) {
+	a.actions = append(a.actions, e.Action)
+}
+
+// recordingMQ - the methods of the events published
+type recordingMQ struct {
+	ports.RabbitMQ
+	methods []string
+}
+
+func (m *recordingMQ) Publish(_ context.Context, e mq.Event) error {
+	m.methods = append(m.methods, e.Method)
+	return nil
+}
+
+func TestSuspensionService_SuspendInactive(t *testing.T) {
+	mCounter := prometheus.NewCounterVec(prometheus.CounterOpts{Name: "test"}, []string{"result"})
+	days := func(n int) time.Time { return time.Now().AddDate(0, 0, -n) }
+	active, dueWarning, warnedEarlier, warnedRecently, unwarned := uuid.New(), uuid.New(), uuid.New(), uuid.New(), uuid.New()
+
+	tests := []struct {
+		name          string
+		settings      SuspensionSettings
+		wantWarned    int
+		wantSuspended []domain.UUID
+	}{
+		{"disabled", SuspensionSettings{}, 0, nil},
+		{
+			name:          "warned first",
+			settings:      SuspensionSettings{InactiveDays: 90, WarnDays: 7},
+			wantWarned:    2,
+			wantSuspended: []domain.UUID{warnedEarlier},
+		},
+		{
+			name:          "no warning",
+			settings:      SuspensionSettings{InactiveDays: 90},
+			wantSuspended: []domain.UUID{warnedEarlier, warnedRecently, unwarned},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &suspensionRepository{
+				seen: map[domain.UUID]time.Time{
+					active:         days(10),
+					dueWarning:     days(85),
+					warnedEarlier:  days(100),
+					warnedRecently: days(100),
+					unwarned:       days(100),
+				},
+				warned: map[domain.UUID]time.Time{
+					warnedEarlier:  days(8),
+					warnedRecently: days(2),
+				},
+				suspended: map[domain.UUID]time.Time{},
+			}
+			auditSvc, publisher := &recordingAudit{}, &recordingMQ{}
+			ss := NewSuspensionService(repo, auditSvc, publisher, zap.NewNop(), mCounter, tt.settings)
+
+			warned, suspended, err := ss.SuspendInactive(context.Background())
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantWarned, warned)
+			assert.Equal(t, len(tt.wantSuspended), suspended)
+			for _, id := range tt.wantSuspended {
+				assert.Contains(t, repo.suspended, id)
+			}
+			assert.NotContains(t, repo.suspended, active)
+			assert.Len(t, auditSvc.actions, suspended)
+			assert.Len(t, publisher.methods, warned+suspended)
+		})
+	}
+}
+
+// TestSuspensionService_SuspendInactive_EventFailed - the users after the one
+// whose event failed are suspended and published all the same
+func TestSuspensionService_SuspendInactive_EventFailed(t *testing.T) {
+	mCounter := prometheus.NewCounterVec(prometheus.CounterOpts{Name: "test"}, []string{"result"})
+	repo := &suspensionRepository{
+		seen:      map[domain.UUID]time.Time{},
+		warned:    map[domain.UUID]time.Time{},
+		suspended: map[domain.UUID]time.Time{},
+	}
+	for range 5 {
+		repo.seen[uuid.New()] = time.Now().AddDate(0, 0, -100)
+	}
+	for id := range repo.seen {
+		repo.broken = id
+		break
+	}
+	auditSvc, publisher := &recordingAudit{}, &recordingMQ{}
+	ss := NewSuspensionService(repo, auditSvc, publisher, zap.NewNop(), mCounter, SuspensionSettings{InactiveDays: 90})
+
+	_, suspended, err := ss.SuspendInactive(context.Background())
+	require.Error(t, err)
+	assert.Equal(t, 5, suspended)
+	assert.Len(t, repo.suspended, 5)
+	assert.Len(t, auditSvc.actions, 5, "every suspension is audited")
+	assert.Len(t, publisher.methods, 4)
+}
+
+func TestSuspensionService_CheckLogin(t *testing.T) {
+	mCounter := prometheus.NewCounterVec(prometheus.CounterOpts{Name: "test"}, []string{"result"})
+	userID := uuid.New()
+
+	tests := []struct {
+		name            string
+		suspendedFor    time.Duration
+		graceDays       int
+		wantErr         error
+		wantReactivated bool
+	}{
+		{name: "not suspended", graceDays: 14},
+		{name: "within grace", suspendedFor: 3 * 24 * time.Hour, graceDays: 14, wantReactivated: true},
+		{name: "past grace", suspendedFor: 15 * 24 * time.Hour, graceDays: 14, wantErr: ErrAccountSuspended},
+		{name: "no grace", suspendedFor: time.Minute, wantErr: ErrAccountSuspended},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &suspensionRepository{
+				seen:      map[domain.UUID]time.Time{userID: time.Now().AddDate(0, 0, -120)},
+				warned:    map[domain.UUID]time.Time{},
+				suspended: map[domain.UUID]time.Time{},
+			}
+			if tt.suspendedFor > 0 {
+				repo.suspended[userID] = time.Now().Add(-tt.suspendedFor)
+			}
+			auditSvc, publisher := &recordingAudit{}, &recordingMQ{}
+			ss := NewSuspensionService(repo, auditSvc, publisher, zap.NewNop(), mCounter, SuspensionSettings{
+				InactiveDays: 90,
+				GraceDays:    tt.graceDays,
+			})
+
+			err := ss.CheckLogin(context.Background(), &domain.User{UUID: userID})
+			assert.ErrorIs(t, err, tt.wantErr)
+			if tt.wantReactivated {
+				assert.NotContains(t, repo.suspended, userID)
+				assert.Equal(t, []audit.Action{audit.ActionUserReactivated}, auditSvc.actions)
+				assert.Equal(t, []string{mq.EventUserReactivated}, publisher.methods)
+				return
+			}
+			_, suspended := repo.suspended[userID]
+			assert.Equal(t, tt.suspendedFor > 0, suspended)
+			assert.Empty(t, publisher.methods)
+		})
+	}
+}
@@ -0,0 +1,127 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+
+	"user-manager-api/internal/application/ports"
+	domain "user-manager-api/internal/domain/user_file"
+)
+
+type (
+	textTask struct {
+		file *domain.UserFile
+		data []byte
+	}
+	// TextExtractionService - extracts the text of the uploads in the background
+	// for the files search, the way ThumbnailService renders the previews
+	TextExtractionService struct {
+		extractor          ports.TextExtractor
+		userFileRepository domain.Repository
+		logger             *zap.Logger
+		mCounter           *prometheus.CounterVec
+		maxTextSize        int
+		maxSourceSize      int64
+		in                 chan textTask
+	}
+)
+
+// NewTextExtractionService - maxTextSize bytes of a text are kept, the rest is
+// cut; the uploads up to maxSourceSize bytes are extracted
+func NewTextExtractionService(
+	extractor ports.TextExtractor,
+	userFileRepository domain.Repository,
+	logger *zap.Logger,
+	mCounter *prometheus.CounterVec,
+	queueSize int,
+	maxTextSize int,
+	maxSourceSize int64,
+) ports.TextExtractionService {
+	return &TextExtractionService{
+		extractor:          extractor,
+		userFileRepository: userFileRepository,
+		logger:             logger,
+		mCounter:           mCounter,
+		maxTextSize:        maxTextSize,
+		maxSourceSize:      maxSourceSize,
+		in:                 make(chan textTask, queueSize),
+	}
+}
+
+func (ts *TextExtractionService) Accepts(uf *domain.UserFile) bool {
+	return ts.extractor.Supports(uf.MimeType) && uf.SizeBytes <= uint64(ts.maxSourceSize)
+}
+
+func (ts *TextExtractionService) Enqueue(uf *domain.UserFile, data []byte) bool {
+	select {
+	case ts.in <- textTask{file: uf, data: data}:
+		return true
+	default:
+		// best effort as the thumbnails, never slow down uploads
+		ts.mCounter.WithLabelValues("texts_dropped_total").Inc()
+		return false
+	}
+}
+
+func (ts *TextExtractionService) Worker(ctx context.Context) {
+	ts.logger.Info("starting text extraction worker")
+
+	defer func() {
+		ts.logger.Info("text extraction worker gracefully stopped")
+	}()
+
+	for {
+		select {
+		case t := <-ts.in:
+			if err := ts.process(ctx, t); err != nil {
+				ts.mCounter.WithLabelValues("texts_failed_total").Inc()
+				ts.logger.Error("text extraction error",
+					zap.Error(err),
+					zap.Stringer("file_uuid", t.file.UUID),
+				)
+				continue
+			}
+			ts.mCounter.WithLabelValues("texts_extracted_total").Inc()
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (ts *TextExtractionService) process(ctx context.Context, t textTask) error {
+	body, err := ts.extractor.Extract(ctx, t.file.MimeType, t.data)
+	if err != nil {
+		// the client sees the extraction is over
+		if serr := ts.userFileRepository.SaveText(ctx, t.file.UUID, domain.Text{Status: domain.TextFailed}); serr != nil {
+			ts.logger.Error("SaveText() error", zap.Error(serr), zap.Stringer("file_uuid", t.file.UUID))
+		}
+		return err
+	}
+
+	return ts.userFileRepository.SaveText(ctx, t.file.UUID, domain.Text{
+		Status: domain.TextExtracted,
+		Body:   cleanText(body, ts.maxTextSize),
+	})
+}
+
+// cleanText - valid UTF-8 without NULs(Postgres text rejects them), cut to
+// maxSize bytes on a rune boundary
+func cleanText(s string, maxSize int) string {
+	s = strings.ToValidUTF8(s, "")
+	s = strings.ReplaceAll(s, "\x00", "")
+	s = strings.TrimSpace(s)
+	if len(s) <= maxSize {
+		return s
+	}
+
+	s = s[:maxSize]
+	for len(s) > 0 && !utf8.ValidString(s) {
+		s = s[:len(s)-1]
+	}
+
+	return s
+}
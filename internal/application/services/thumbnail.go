@@ -0,0 +1,125 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"path"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+
+	"user-manager-api/internal/application/ports"
+	domain "user-manager-api/internal/domain/user_file"
+)
+
+const (
+	thumbnailKeyPrefix = "thumbnails/"
+	// thumbnailType - of every thumbnail, whatever the type of the file
+	thumbnailType = "image/png"
+)
+
+type (
+	thumbnailTask struct {
+		file *domain.UserFile
+		data []byte
+	}
+	ThumbnailService struct {
+		renderer           ports.ThumbnailRenderer
+		storage            ports.ObjectStorage
+		userFileRepository domain.Repository
+		logger             *zap.Logger
+		mCounter           *prometheus.CounterVec
+		maxSourceSize      int64
+		in                 chan thumbnailTask
+	}
+)
+
+// NewThumbnailService - the uploads up to maxSourceSize bytes are previewed
+func NewThumbnailService(
+	renderer ports.ThumbnailRenderer,
+	storage ports.ObjectStorage,
+	userFileRepository domain.Repository,
+	logger *zap.Logger,
+	mCounter *prometheus.CounterVec,
+	queueSize int,
+	maxSourceSize int64,
+) ports.ThumbnailService {
+	return &ThumbnailService{
+		renderer:           renderer,
+		storage:            storage,
+		userFileRepository: userFileRepository,
+		logger:             logger,
+		mCounter:           mCounter,
+		maxSourceSize:      maxSourceSize,
+		in:                 make(chan thumbnailTask, queueSize),
+	}
+}
+
+func (ts *ThumbnailService) Accepts(uf *domain.UserFile) bool {
+	return ts.renderer.Supports(uf.MimeType) && uf.SizeBytes <= uint64(ts.maxSourceSize)
+}
+
+func (ts *ThumbnailService) Enqueue(uf *domain.UserFile, data []byte) bool {
+	select {
+	case ts.in <- thumbnailTask{file: uf, data: data}:
+		return true
+	default:
+		// thumbnails are best effort, never slow down uploads
+		ts.mCounter.WithLabelValues("thumbnails_dropped_total").Inc()
+		return false
+	}
+}
+
+func (ts *ThumbnailService) Worker(ctx context.Context) {
+	ts.logger.Info("starting thumbnail worker")
+
+	defer func() {
+		ts.logger.Info("thumbnail worker gracefully stopped")
+	}()
+
+	for {
+		select {
+		case t := <-ts.in:
+			if err := ts.process(ctx, t); err != nil {
+				ts.mCounter.WithLabelValues("thumbnails_failed_total").Inc()
+				ts.logger.Error("thumbnail error",
+					zap.Error(err),
+					zap.Stringer("file_uuid", t.file.UUID),
+				)
+				continue
+			}
+			ts.mCounter.WithLabelValues("thumbnails_created_total").Inc()
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (ts *ThumbnailService) process(ctx context.Context, t thumbnailTask) error {
+	png, err := ts.renderer.Render(ctx, t.file.MimeType, t.data)
+	if err != nil {
+		return err
+	}
+
+	key := thumbnailKey(t.file.StorageKey)
+	if err = ts.storage.PutObject(ctx, key, thumbnailType, bytes.NewReader(png), int64(len(png))); err != nil {
+		return err
+	}
+
+	return ts.userFileRepository.UpdateThumbnailURL(ctx, t.file.UUID, ts.storage.GetPublicURL(key))
+}
+
+// objectKeys - the object of the file and its thumbnail when one was rendered
+func objectKeys(uf *domain.UserFile) []string {
+	if uf.ThumbnailURL == "" {
+		return []string{uf.StorageKey}
+	}
+	return []string{uf.StorageKey, thumbnailKey(uf.StorageKey)}
+}
+
+// thumbnailKey: "documents/.../<filename>.ext" -> "thumbnails/.../<filename>.png"
+func thumbnailKey(storageKey string) string {
+	key := thumbnailKeyPrefix + strings.TrimPrefix(storageKey, storageKeyPrefix)
+	return strings.TrimSuffix(key, path.Ext(key)) + ".png"
+}
@@ -0,0 +1,124 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"user-manager-api/internal/application/ports"
+	"user-manager-api/internal/domain/user"
+	domain "user-manager-api/internal/domain/user_file"
+)
+
+// UploadPolicyService - the policies are few: an instance caches all of them
+// at once, so the uploads of the organizations without one cost no query
+type UploadPolicyService struct {
+	userFileRepository domain.Repository
+	userRepository     user.Repository
+	mCounter           *prometheus.CounterVec
+
+	cacheTTL time.Duration
+	mu       sync.Mutex
+	// policies - by organization, nil - not loaded or expired
+	policies map[string]domain.UploadPolicy
+	expires  time.Time
+}
+
+// NewUploadPolicyService - cacheTTL 0: the policies are read on every upload
+func NewUploadPolicyService(
+	userFileRepository domain.Repository,
+	userRepository user.Repository,
+	cacheTTL time.Duration,
+	mCounter *prometheus.CounterVec,
+) ports.UploadPolicyService {
+	return &UploadPolicyService{
+		userFileRepository: userFileRepository,
+		userRepository:     userRepository,
+		mCounter:           mCounter,
+		cacheTTL:           cacheTTL,
+	}
+}
+
+func (ups *UploadPolicyService) Policies(ctx context.Context) (domain.UploadPolicies, error) {
+	return ups.userFileRepository.FetchUploadPolicies(ctx)
+}
+
+// SetPolicy - the files over a new quota or of a type no longer allowed are
+// kept, only the next uploads are checked
+func (ups *UploadPolicyService) SetPolicy(ctx context.Context, p domain.UploadPolicy) (*domain.UploadPolicy, error) {
+	out, err := ups.userFileRepository.SaveUploadPolicy(ctx, p)
+	if err != nil {
+		return nil, err
+	}
+	// the other instances catch up within the TTL
+	ups.forget()
+	ups.mCounter.WithLabelValues("upload_policy_changed_total").Inc()
+
+	return out, nil
+}
+
+func (ups *UploadPolicyService) RemovePolicy(ctx context.Context, org string) (bool, error) {
+	removed, err := ups.userFileRepository.DeleteUploadPolicy(ctx, org)
+	if err != nil {
+		return false, err
+	}
+	if removed {
+		ups.forget()
+		ups.mCounter.WithLabelValues("upload_policy_changed_total").Inc()
+	}
+
+	return removed, nil
+}
+
+func (ups *UploadPolicyService) UserPolicy(ctx context.Context, userUUID user.UUID) (*domain.UploadPolicy, error) {
+	policies, err := ups.cached(ctx)
+	if err != nil || len(policies) == 0 {
+		return nil, err
+	}
+
+	u, err := ups.userRepository.FetchUserByID(ctx, userUUID)
+	if err != nil {
+		return nil, err
+	}
+	p, ok := policies[user.Organization(u.Email)]
+	if !ok {
+		return nil, nil
+	}
+
+	return &p, nil
+}
+
+// cached - the policies by organization, read from the DB once per TTL
+func (ups *UploadPolicyService) cached(ctx context.Context) (map[string]domain.UploadPolicy, error) {
+	ups.mu.Lock()
+	policies, expires := ups.policies, ups.expires
+	ups.mu.Unlock()
+	if policies != nil && time.Now().Before(expires) {
+		return policies, nil
+	}
+
+	list, err := ups.userFileRepository.FetchUploadPolicies(ctx)
+	if err != nil {
+		return nil, err
+	}
+	policies = make(map[string]domain.UploadPolicy, len(list))
+	for _, p := range list {
+		policies[p.Org] = p
+	}
+
+	if ups.cacheTTL > 0 {
+		ups.mu.Lock()
+		ups.policies, ups.expires = policies, time.Now().Add(ups.cacheTTL)
+		ups.mu.Unlock()
+	}
+
+	return policies, nil
+}
+
+func (ups *UploadPolicyService) forget() {
+	ups.mu.Lock()
+	ups.policies = nil
+	ups.mu.Unlock()
+}
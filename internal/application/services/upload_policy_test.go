@@ -0,0 +1,139 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"user-manager-api/internal/domain/user"
+	domain "user-manager-api/internal/domain/user_file"
+)
+
+// policyFileRepository - the upload policies and the bytes used by the users,
+// FetchUploadPolicies calls counted
+type policyFileRepository struct {
+	domain.Repository
+	policies domain.UploadPolicies
+	used     uint64
+	fetches  int
+}
+
+func (r *policyFileRepository) FetchUploadPolicies(context.Context) (domain.UploadPolicies, error) {
+	r.fetches++
+	return r.policies, nil
+}
+
+func (r *policyFileRepository) SaveUploadPolicy(_ context.Context, p domain.UploadPolicy) (*domain.UploadPolicy, error) {
+	r.policies = append(r.policies, p)
+	return &p, nil
+}
+
+func (r *policyFileRepository) FetchUsedBytes(context.Context, user.ID) (uint64, error) {
+	return r.used, nil
+}
+
+// emailRepository - the emails of the users
+type emailRepository struct {
+	user.Repository
+	emails map[user.UUID]string
+}
+
+func (r *emailRepository) FetchUserByID(_ context.Context, id user.UUID) (*user.User, error) {
+	email, ok := r.emails[id]
+	if !ok {
+		return nil, user.ErrNotFound
+	}
+	return &user.User{UUID: id, Email: email}, nil
+}
+
+func TestUploadPolicyService_UserPolicy(t *testing.T) {
+	acmeID, otherID := uuid.New(), uuid.New()
+	mCounter := prometheus.NewCounterVec(prometheus.CounterOpts{Name: "test"}, []string{"result"})
+	users := &emailRepository{emails: map[user.UUID]string{acmeID: "jane@ACME.com", otherID: "john@example.com"}}
+
+	t.Run("cached", func(t *testing.T) {
+		files := &policyFileRepository{policies: domain.UploadPolicies{{Org: "acme.com", QuotaBytes: 100}}}
+		ups := NewUploadPolicyService(files, users, time.Minute, mCounter)
+
+		for range 3 {
+			p, err := ups.UserPolicy(context.Background(), acmeID)
+			require.NoError(t, err)
+			require.NotNil(t, p)
+			assert.Equal(t, uint64(100), p.QuotaBytes)
+		}
+		p, err := ups.UserPolicy(context.Background(), otherID)
+		require.NoError(t, err)
+		assert.Nil(t, p)
+		assert.Equal(t, 1, files.fetches)
+
+		_, err = ups.SetPolicy(context.Background(), domain.UploadPolicy{Org: "example.com", MaxFileSize: 10})
+		require.NoError(t, err)
+		p, err = ups.UserPolicy(context.Background(), otherID)
+		require.NoError(t, err)
+		require.NotNil(t, p, "the change is seen at once")
+		assert.Equal(t, 2, files.fetches)
+	})
+
+	t.Run("no cache", func(t *testing.T) {
+		files := &policyFileRepository{}
+		ups := NewUploadPolicyService(files, users, 0, mCounter)
+
+		for range 2 {
+			p, err := ups.UserPolicy(context.Background(), uuid.New())
+			require.NoError(t, err, "no policies: the user is not looked up")
+			assert.Nil(t, p)
+		}
+		assert.Equal(t, 2, files.fetches)
+	})
+}
+
+func TestUserFileService_CheckUploadPolicy(t *testing.T) {
+	userID := uuid.New()
+	mCounter := prometheus.NewCounterVec(prometheus.CounterOpts{Name: "test"}, []string{"result"})
+	users := &emailRepository{emails: map[user.UUID]string{userID: "jane@acme.com"}}
+	policy := domain.UploadPolicy{
+		Org:          "acme.com",
+		AllowedTypes: []string{"image/*", "application/pdf"},
+		MaxFileSize:  1000,
+		QuotaBytes:   5000,
+	}
+
+	tests := []struct {
+		name    string
+		policy  *domain.UploadPolicy
+		file    domain.UserFile
+		used    uint64
+		wantErr error
+	}{
+		{"allowed", &policy, domain.UserFile{FileName: "a.png", MimeType: "image/png", SizeBytes: 1000}, 4000, nil},
+		{"wildcard with params", &policy, domain.UserFile{FileName: "scan", MimeType: "Image/TIFF; q=1", SizeBytes: 10}, 0, nil},
+		{"no policy", nil, domain.UserFile{FileName: "setup.exe", MimeType: "application/x-msdownload", SizeBytes: 1 << 30}, 0, nil},
+		{"type", &policy, domain.UserFile{FileName: "notes.txt", MimeType: "text/plain", SizeBytes: 10}, 0, ErrFileTypeNotAllowed},
+		{"no type", &policy, domain.UserFile{FileName: "blob", SizeBytes: 10}, 0, ErrFileTypeNotAllowed},
+		{"extension", &policy, domain.UserFile{FileName: "page.html", MimeType: "image/png", SizeBytes: 10}, 0, ErrFileTypeNotAllowed},
+		{"size", &policy, domain.UserFile{FileName: "a.pdf", MimeType: "application/pdf", SizeBytes: 1001}, 0, ErrFileTooLarge},
+		{"quota", &policy, domain.UserFile{FileName: "a.pdf", MimeType: "application/pdf", SizeBytes: 1000}, 4001, ErrQuotaExceeded},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			files := &policyFileRepository{used: tt.used}
+			if tt.policy != nil {
+				files.policies = domain.UploadPolicies{*tt.policy}
+			}
+			ufs := &UserFileService{
+				userFileRepository: files,
+				uploadPolicies:     NewUploadPolicyService(files, users, 0, mCounter),
+				mCounter:           mCounter,
+			}
+
+			err := ufs.checkUploadPolicy(context.Background(), userID, 1, &tt.file)
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
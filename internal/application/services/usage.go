@@ -0,0 +1,238 @@
+package services
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+
+	"user-manager-api/internal/application/ports"
+	"user-manager-api/internal/domain/usage"
+	domainUser "user-manager-api/internal/domain/user"
+	"user-manager-api/internal/infrastructure/metrics"
+)
+
+const (
+	// usageOrgsTTL - an email change moves the requests of a user to the new
+	// organization after up to the TTL
+	usageOrgsTTL = time.Hour
+	// usageLabelOther - the metrics label of the organizations and the roles
+	// out of the allowed ones
+	usageLabelOther = "other"
+	// usageShutdownFlush - the last flush after the worker was stopped
+	usageShutdownFlush = 5 * time.Second
+)
+
+type (
+	UsageSettings struct {
+		// Orgs - the organizations with their own metrics label
+		Orgs          []string
+		FlushInterval time.Duration
+		QueueSize     int
+	}
+	usageHit struct {
+		// userID - uuid.Nil for the requests without a token
+		userID   uuid.UUID
+		role     string
+		duration time.Duration
+		at       time.Time
+	}
+	usageKey struct {
+		day       time.Time
+		org, role string
+	}
+	UsageService struct {
+		repository    usage.Repository
+		logger        *zap.Logger
+		mCounter      *prometheus.CounterVec
+		mRequests     *prometheus.CounterVec
+		mDuration     *prometheus.HistogramVec
+		orgLabels     metrics.LabelBound
+		roleLabels    metrics.LabelBound
+		flushInterval time.Duration
+		in            chan usageHit
+
+		// owned by the worker
+		pending      []usageHit
+		orgs         map[uuid.UUID]string
+		orgsExpireAt time.Time
+		rollups      map[usageKey]*usage.Rollup
+	}
+)
+
+func NewUsageService(
+	repository usage.Repository,
+	logger *zap.Logger,
+	mCounter *prometheus.CounterVec,
+	mRequests *prometheus.CounterVec,
+	mDuration *prometheus.HistogramVec,
+	settings UsageSettings,
+) ports.UsageService {
+	return &UsageService{
+		repository:    repository,
+		logger:        logger,
+		mCounter:      mCounter,
+		mRequests:     mRequests,
+		mDuration:     mDuration,
+		orgLabels:     metrics.NewLabelBound(usageLabelOther, append(slices.Clone(settings.Orgs), usage.Anonymous)...),
+		roleLabels:    metrics.NewLabelBound(usageLabelOther, domainUser.RoleAdmin, domainUser.RoleWorker, usage.Anonymous),
+		flushInterval: settings.FlushInterval,
+		in:            make(chan usageHit, settings.QueueSize),
+		orgs:          make(map[uuid.UUID]string),
+		rollups:       make(map[usageKey]*usage.Rollup),
+	}
+}
+
+func (us *UsageService) Record(userID, role string, duration time.Duration) {
+	id, _ := uuid.Parse(userID)
+	if role == "" {
+		role = usage.Anonymous
+	}
+
+	select {
+	case us.in <- usageHit{userID: id, role: role, duration: duration, at: time.Now()}:
+	default:
+		us.mCounter.WithLabelValues("usage_dropped_total").Inc()
+	}
+}
+
+func (us *UsageService) Worker(ctx context.Context) {
+	us.logger.Info("starting usage worker")
+
+	defer func() {
+		us.logger.Info("usage worker gracefully stopped")
+	}()
+
+	ticker := time.NewTicker(us.flushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case h := <-us.in:
+			us.pending = append(us.pending, h)
+			if len(us.pending) >= cap(us.in) {
+				us.flush(ctx)
+			}
+		case <-ticker.C:
+			us.flush(ctx)
+		case <-ctx.Done():
+			us.drain()
+			flushCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), usageShutdownFlush)
+			us.flush(flushCtx)
+			cancel()
+			return
+		}
+	}
+}
+
+func (us *UsageService) Totals(ctx context.Context, from, to time.Time, org string) ([]usage.Total, error) {
+	return us.repository.FetchTotals(ctx, from, to, org)
+}
+
+// drain takes the requests recorded up to the shutdown
+func (us *UsageService) drain() {
+	for {
+		select {
+		case h := <-us.in:
+			us.pending = append(us.pending, h)
+		default:
+			return
+		}
+	}
+}
+
+// flush adds the pending requests to the metrics and the rollups, the
+// rollups failed to be written are kept for the next flush
+func (us *UsageService) flush(ctx context.Context) {
+	if len(us.pending) > 0 {
+		us.resolveOrgs(ctx)
+		for _, h := range us.pending {
+			us.add(h)
+		}
+		us.pending = us.pending[:0]
+	}
+	if len(us.rollups) == 0 {
+		return
+	}
+
+	rollups := make([]usage.Rollup, 0, len(us.rollups))
+	for _, r := range us.rollups {
+		rollups = append(rollups, *r)
+	}
+	if err := us.repository.AddRollups(ctx, rollups); err != nil {
+		us.mCounter.WithLabelValues("usage_flush_failed_total").Inc()
+		us.logger.Error("usage rollups error", zap.Error(err), zap.Int("rollups_count", len(rollups)))
+		return
+	}
+	clear(us.rollups)
+}
+
+func (us *UsageService) add(h usageHit) {
+	org := usage.Anonymous
+	if h.userID != uuid.Nil {
+		var ok bool
+		if org, ok = us.orgs[h.userID]; !ok {
+			org = usage.Unknown
+		}
+	}
+	role := us.roleLabels.Value(h.role)
+
+	orgLabel := us.orgLabels.Value(org)
+	us.mRequests.WithLabelValues(orgLabel, role).Inc()
+	us.mDuration.WithLabelValues(orgLabel, role).Observe(h.duration.Seconds())
+
+	// the rollups keep every organization, their cardinality is not a concern
+	y, m, d := h.at.UTC().Date()
+	key := usageKey{day: time.Date(y, m, d, 0, 0, 0, 0, time.UTC), org: org, role: role}
+	r, ok := us.rollups[key]
+	if !ok {
+		r = &usage.Rollup{Day: key.day, Org: org, Role: role}
+		us.rollups[key] = r
+	}
+	r.Requests++
+	r.DurationMs += uint64(h.duration.Milliseconds())
+}
+
+// resolveOrgs caches the organizations of the pending users with one query,
+// the users of a failed query are counted as usage.Unknown
+func (us *UsageService) resolveOrgs(ctx context.Context) {
+	if now := time.Now(); now.After(us.orgsExpireAt) {
+		clear(us.orgs)
+		us.orgsExpireAt = now.Add(usageOrgsTTL)
+	}
+
+	var missing []uuid.UUID
+	seen := make(map[uuid.UUID]struct{})
+	for _, h := range us.pending {
+		if h.userID == uuid.Nil {
+			continue
+		}
+		if _, ok := us.orgs[h.userID]; ok {
+			continue
+		}
+		if _, ok := seen[h.userID]; ok {
+			continue
+		}
+		seen[h.userID] = struct{}{}
+		missing = append(missing, h.userID)
+	}
+	if len(missing) == 0 {
+		return
+	}
+
+	found, err := us.repository.FetchOrganizations(ctx, missing)
+	if err != nil {
+		us.logger.Error("usage organizations error", zap.Error(err), zap.Int("users_count", len(missing)))
+		return
+	}
+	for _, id := range missing {
+		org, ok := found[id]
+		if !ok || org == "" {
+			org = usage.Unknown
+		}
+		us.orgs[id] = org
+	}
+}
@@ -2,81 +2,184 @@ package services
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/prometheus/client_golang/prometheus"
 
 	"user-manager-api/internal/application/ports"
+	"user-manager-api/internal/domain/pagination"
+	"user-manager-api/internal/domain/usage"
 	domain "user-manager-api/internal/domain/user"
-	"user-manager-api/internal/domain/user_file"
 	"user-manager-api/internal/infrastructure/mq"
 	"user-manager-api/internal/interface/api/rest/dto/user"
 )
 
-type UserService struct {
-	userRepository     domain.Repository
-	userFileRepository user_file.Repository
-	mq                 ports.RabbitMQ
-	mCounter           *prometheus.CounterVec
+var (
+	// ErrUserNotFound - the services report a missing user by the error, never by a nil user
+	ErrUserNotFound            = domain.ErrNotFound
+	ErrInvalidEmailChangeToken = errors.New("invalid or expired email change token")
+	// ErrSelfDeleteUnconfirmed - a lock-out guard: the own account is deleted on purpose only
+	ErrSelfDeleteUnconfirmed = errors.New("deleting your own account must be confirmed")
+	// ErrSeatLimitReached - checked early by the services, the database enforces it
+	ErrSeatLimitReached = domain.ErrSeatLimitReached
+)
+
+// walkBatchSize - users per page of walkUsers
+const walkBatchSize = 500
+
+// UserCommands - the changes of the users, see UserQueries for the reads
+type UserCommands struct {
+	userRepository  domain.Repository
+	deletions       ports.UserDeletionService
+	usageRepository usage.Repository
+	mq              ports.RabbitMQ
+	mCounter        *prometheus.CounterVec
+	emailChangeTTL  time.Duration
+	timezones       domain.Timezones
+	agePolicy       domain.AgePolicy
 }
 
-func NewUserService(
+func NewUserCommands(
 	userRepository domain.Repository,
-	userFileRepository user_file.Repository,
+	deletions ports.UserDeletionService,
+	usageRepository usage.Repository,
 	mq ports.RabbitMQ,
 	mCounter *prometheus.CounterVec,
-) ports.UserService {
-	return &UserService{
-		userRepository:     userRepository,
-		userFileRepository: userFileRepository,
-		mq:                 mq,
-		mCounter:           mCounter,
+	emailChangeTTL time.Duration,
+	timezones domain.Timezones,
+	agePolicy domain.AgePolicy,
+) ports.UserCommands {
+	return &UserCommands{
+		userRepository:  userRepository,
+		deletions:       deletions,
+		usageRepository: usageRepository,
+		mq:              mq,
+		mCounter:        mCounter,
+		emailChangeTTL:  emailChangeTTL,
+		timezones:       timezones,
+		agePolicy:       agePolicy,
 	}
 }
 
-func (us *UserService) FindUserByID(ctx context.Context, uuid domain.UUID) (*domain.User, error) {
-	u, err := us.userRepository.FetchUserByID(ctx, uuid)
+// CheckEmailAvailable - the uniqueness pre-checks of CreateUser without creating
+// anything: ErrEmailAlreadyExists or ErrSeatLimitReached
+func (us *UserCommands) CheckEmailAvailable(ctx context.Context, email string) error {
+	email = strings.ToLower(strings.TrimSpace(email))
+	_, err := us.userRepository.FetchUserByEmail(ctx, email)
+	switch {
+	case err == nil:
+		return domain.ErrEmailAlreadyExists
+	case !errors.Is(err, domain.ErrNotFound):
+		return err
+	}
+
+	free, err := hasFreeSeat(ctx, us.usageRepository, email)
 	if err != nil {
-		return nil, err
+		return err
+	}
+	if !free {
+		return ErrSeatLimitReached
 	}
 
-	return u, nil
+	return nil
 }
 
-func (us *UserService) FindByEmail(ctx context.Context, email string) (*domain.User, error) {
-	u, err := us.userRepository.FetchUserByEmail(ctx, email)
-	if err != nil {
-		return nil, err
-	}
+// CheckAge - the minimum-age policy of CreateUser and UpdateUser, *domain.UnderageError
+func (us *UserCommands) CheckAge(u domain.User) error {
+	return us.agePolicy.Check(u, time.Now())
+}
 
-	return u, nil
+// EmitBirthdays publishes UserBirthday for the users whose birthday it is today
+// in their timezone. The event ID is derived from the user and the year, so a
+// rerun on the same day is deduplicated by the consumers. A daily run sees
+// every local date once, whatever the timezone.
+func (us *UserCommands) EmitBirthdays(ctx context.Context) (int, error) {
+	var sent int
+	now := time.Now()
+	err := walkUsers(ctx, us.userRepository, func(u *domain.User) error {
+		u.Birthday = us.timezones.BirthdayAt(*u, now)
+		if u.Birthday == nil || !u.Birthday.IsToday {
+			return nil
+		}
+		year := now.In(us.timezones.Location(*u)).Year()
+		if err := us.mq.Publish(ctx, mq.Event{
+			Id:      uuid.NewSHA1(u.UUID, []byte(mq.EventUserBirthday+"/"+strconv.Itoa(year))),
+			TS:      now,
+			Method:  mq.EventUserBirthday,
+			UserID:  u.UUID.String(),
+			Payload: user.ToResponseUser(*u),
+		}); err != nil {
+			return err
+		}
+		sent++
+		us.mCounter.WithLabelValues("user_birthday_total").Inc()
+
+		return nil
+	})
+
+	return sent, err
 }
 
-func (us *UserService) FindUsers(ctx context.Context, page int) (domain.Users, error) {
-	users, err := us.userRepository.FetchUsers(ctx, page)
-	if err != nil {
-		return nil, err
-	}
+// walkUsers calls fn for every active user, page by page(keyset). As with
+// StreamUsers the user is reused: fn must not keep it
+func walkUsers(ctx context.Context, repo domain.Repository, fn func(u *domain.User) error) error {
+	var cursor *pagination.Cursor
+	for {
+		var (
+			n    int
+			last pagination.Cursor
+		)
+		err := repo.StreamUsers(ctx, pagination.Params{PerPage: walkBatchSize, Cursor: cursor},
+			func(u *domain.User) error {
+				n++
+				last = pagination.Cursor{CreatedAt: u.CreatedAt, UUID: u.UUID}
 
-	return users, nil
+				return fn(u)
+			})
+		if err != nil {
+			return err
+		}
+		if n < walkBatchSize {
+			return nil
+		}
+		cursor = &last
+	}
 }
 
-func (us *UserService) CreateUser(ctx context.Context, u domain.User) (*domain.User, error) {
+// CreateUser - the seat limit is checked before the insert and enforced by the
+// database(users_seat_limit_trg) against the concurrent creations.
+func (us *UserCommands) CreateUser(ctx context.Context, u domain.User) (*domain.User, error) {
+	if err := us.CheckAge(u); err != nil {
+		return nil, err
+	}
+	if err := checkSeat(ctx, us.usageRepository, us.mCounter, u.Email); err != nil {
+		return nil, err
+	}
+
 	uRet, err := us.userRepository.CreateUser(ctx, u)
 	if err != nil {
+		countSeatRejected(us.mCounter, err)
 		return nil, err
 	}
 
 	if uRet != nil {
-		us.mq.GetInputChan() <- mq.Event{
+		publishEvent(ctx, us.mq, mq.Event{
 			Id:      uuid.New(),
 			TS:      time.Now(),
 			Method:  http.MethodPost,
 			UserID:  uRet.UUID.String(),
 			Payload: user.ToResponseUser(*uRet),
-		}
+			// the signups stats day
+			Meta: map[string]string{"created_at": uRet.CreatedAt.UTC().Format(time.RFC3339Nano)},
+		})
 	}
 
 	us.mCounter.WithLabelValues("user_created_total").Inc()
@@ -84,56 +187,132 @@ func (us *UserService) CreateUser(ctx context.Context, u domain.User) (*domain.U
 	return uRet, nil
 }
 
-func (us *UserService) UpdateUser(ctx context.Context, u domain.User) (*domain.User, error) {
-	uRet, err := us.userRepository.UpdateUser(ctx, u)
+// UpdateUser never switches the email: a changed address stays pending until
+// it is confirmed via ConfirmEmailChange, so a hijacked session cannot take
+// over the account by redirecting its mail.
+func (us *UserCommands) UpdateUser(ctx context.Context, u domain.User) (*domain.User, error) {
+	cur, err := us.userRepository.FetchUserByID(ctx, u.UUID)
 	if err != nil {
 		return nil, err
 	}
 
-	if uRet != nil {
-		us.mq.GetInputChan() <- mq.Event{
-			Id:      uuid.New(),
-			TS:      time.Now(),
-			Method:  http.MethodPut,
-			UserID:  uRet.UUID.String(),
-			Payload: user.ToResponseUser(*uRet),
+	newEmail := strings.TrimSpace(u.Email)
+	emailChanged := !strings.EqualFold(newEmail, cur.Email)
+	u.Email = cur.Email
+	if err = us.CheckAge(u); err != nil {
+		return nil, err
+	}
+	if emailChanged {
+		if err = us.requestEmailChange(ctx, cur, newEmail); err != nil {
+			return nil, err
 		}
 	}
 
+	uRet, err := us.userRepository.UpdateUser(ctx, u)
+	if err != nil {
+		return nil, err
+	}
+	if emailChanged {
+		uRet.PendingEmail = newEmail
+	}
+
+	publishEvent(ctx, us.mq, mq.Event{
+		Id:      uuid.New(),
+		TS:      time.Now(),
+		Method:  http.MethodPut,
+		UserID:  uRet.UUID.String(),
+		Payload: user.ToResponseUser(*uRet),
+	})
+
 	us.mCounter.WithLabelValues("user_updated_total").Inc()
 
 	return uRet, nil
 }
 
-func (us *UserService) DeleteUser(ctx context.Context, userUUID domain.UUID) error {
-	id, err := us.userRepository.FetchInternalID(ctx, userUUID)
+// ConfirmEmailChange - moving to another organization takes a seat there:
+// ErrSeatLimitReached keeps the token for a retry once a seat is free
+func (us *UserCommands) ConfirmEmailChange(ctx context.Context, token string) (*domain.User, error) {
+	if token == "" {
+		return nil, ErrInvalidEmailChangeToken
+	}
+
+	hash := sha256.Sum256([]byte(token))
+	u, err := us.userRepository.ConfirmEmailChange(ctx, hash[:])
+	if errors.Is(err, domain.ErrNotFound) {
+		return nil, ErrInvalidEmailChangeToken
+	}
 	if err != nil {
-		return err
+		countSeatRejected(us.mCounter, err)
+		return nil, err
 	}
 
-	// todo: should be run in transaction
+	publishEvent(ctx, us.mq, mq.Event{
+		Id:      uuid.New(),
+		TS:      time.Now(),
+		Method:  mq.EventEmailChangeConfirmed,
+		UserID:  u.UUID.String(),
+		Payload: user.ToResponseUser(*u),
+	})
 
-	// example: delete objs from s3
-	// ufs.s3.DeleteObjects(ufs.userFileRepository.FetchUserFiles(...))
+	us.mCounter.WithLabelValues("user_email_change_confirmed_total").Inc()
 
-	if err = us.userFileRepository.DeleteUserFiles(ctx, id); err != nil {
-		return err
+	return u, nil
+}
+
+// requestEmailChange stores the hash of a one-time token, the token itself is
+// published only to be delivered to the new address. A new organization must
+// have a free seat, it is checked again on the confirmation.
+func (us *UserCommands) requestEmailChange(ctx context.Context, cur *domain.User, newEmail string) error {
+	if domain.Organization(newEmail) != domain.Organization(cur.Email) {
+		if err := checkSeat(ctx, us.usageRepository, us.mCounter, newEmail); err != nil {
+			return err
+		}
 	}
-	u, err := us.userRepository.DeleteUser(ctx, id)
+
+	id, err := us.userRepository.FetchInternalID(ctx, cur.UUID)
 	if err != nil {
 		return err
 	}
-	if u != nil {
-		us.mq.GetInputChan() <- mq.Event{
-			Id:      uuid.New(),
-			TS:      time.Now(),
-			Method:  http.MethodDelete,
-			UserID:  u.UUID.String(),
-			Payload: user.ToResponseUser(*u),
-		}
+
+	b := make([]byte, 32)
+	if _, err = rand.Read(b); err != nil {
+		return err
 	}
+	token := base64.RawURLEncoding.EncodeToString(b)
+	hash := sha256.Sum256([]byte(token))
+
+	if err = us.userRepository.CreateEmailChange(ctx, id, domain.EmailChange{
+		NewEmail:  newEmail,
+		TokenHash: hash[:],
+		ExpiresAt: time.Now().Add(us.emailChangeTTL),
+	}); err != nil {
+		return err
+	}
+
+	publishEvent(ctx, us.mq, mq.Event{
+		Id:      uuid.New(),
+		TS:      time.Now(),
+		Method:  mq.EventEmailChangeRequested,
+		UserID:  cur.UUID.String(),
+		Payload: user.ToResponseUser(*cur),
+		Meta: map[string]string{
+			"new_email":     newEmail,
+			"confirm_token": token,
+		},
+	})
 
-	us.mCounter.WithLabelValues("user_deleted_total").Inc()
+	us.mCounter.WithLabelValues("user_email_change_requested_total").Inc()
 
 	return nil
 }
+
+// DeleteUser - actor is recorded as deleted_by. The own account needs confirmSelf,
+// the rest is the saga of ports.UserDeletionService: the last active admin and a
+// user on a legal hold with their files are kept(domain.ErrLastAdmin, domain.ErrLegalHold).
+func (us *UserCommands) DeleteUser(ctx context.Context, actor, userUUID domain.UUID, reason domain.DeletionReason, confirmSelf bool) error {
+	if actor == userUUID && !confirmSelf {
+		return ErrSelfDeleteUnconfirmed
+	}
+
+	return us.deletions.Delete(ctx, actor, userUUID, reason)
+}
@@ -0,0 +1,251 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+
+	"user-manager-api/internal/application/ports"
+	"user-manager-api/internal/domain/deletion"
+	domain "user-manager-api/internal/domain/user"
+	"user-manager-api/internal/domain/user_file"
+	"user-manager-api/internal/infrastructure/mq"
+	"user-manager-api/internal/interface/api/rest/dto/user"
+)
+
+const (
+	// deletionStaleAfter - a running saga not updated for so long was interrupted
+	deletionStaleAfter = 5 * time.Minute
+	resumeBatchSize    = 100
+)
+
+// nextStep - the step after each one, the last one completes the saga
+var nextStep = map[deletion.Step]deletion.Step{
+	deletion.StepDeleteUser:  deletion.StepPublish,
+	deletion.StepPublish:     deletion.StepDeleteFiles,
+	deletion.StepDeleteFiles: deletion.StepDeleteObjects,
+}
+
+type UserDeletionService struct {
+	storage            ports.ObjectStorage
+	sagaRepository     deletion.Repository
+	userRepository     domain.Repository
+	userFileRepository user_file.Repository
+	mq                 ports.RabbitMQ
+	logger             *zap.Logger
+	mCounter           *prometheus.CounterVec
+}
+
+func NewUserDeletionService(
+	storage ports.ObjectStorage,
+	sagaRepository deletion.Repository,
+	userRepository domain.Repository,
+	userFileRepository user_file.Repository,
+	mq ports.RabbitMQ,
+	logger *zap.Logger,
+	mCounter *prometheus.CounterVec,
+) ports.UserDeletionService {
+	return &UserDeletionService{
+		storage:            storage,
+		sagaRepository:     sagaRepository,
+		userRepository:     userRepository,
+		userFileRepository: userFileRepository,
+		mq:                 mq,
+		logger:             logger,
+		mCounter:           mCounter,
+	}
+}
+
+// Delete - the user goes first: the deleted objects are not restored, so they
+// are purged only once nothing(a legal hold, the last admin) can keep the user.
+// CheckDeletable spares the saga of a user kept anyway.
+func (uds *UserDeletionService) Delete(ctx context.Context, actor, userUUID domain.UUID, reason domain.DeletionReason) error {
+	id, err := uds.userRepository.FetchInternalID(ctx, userUUID)
+	if err != nil {
+		return err
+	}
+	if err = uds.userRepository.CheckDeletable(ctx, id); err != nil {
+		return err
+	}
+	ufs, err := uds.userFileRepository.FetchDeletableFiles(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	s := deletion.Saga{
+		UserID:    id,
+		UserUUID:  userUUID,
+		ActorUUID: actor,
+		Reason:    reason,
+		Step:      deletion.StepDeleteUser,
+		FileUUIDs: make([]uuid.UUID, len(ufs)),
+		Keys:      make([]string, 0, len(ufs)),
+	}
+	for i, uf := range ufs {
+		s.FileUUIDs[i] = uf.UUID
+		s.Keys = append(s.Keys, objectKeys(uf)...)
+	}
+	created, err := uds.sagaRepository.Create(ctx, s)
+	if err != nil {
+		return err
+	}
+	uds.mCounter.WithLabelValues("user_deletion_started_total").Inc()
+
+	return uds.run(ctx, created)
+}
+
+// Resume - the claimed sagas are not stale until deletionStaleAfter, so batches
+// are taken until one is empty. A saga failing again waits for the next run.
+func (uds *UserDeletionService) Resume(ctx context.Context) (int, error) {
+	var completed int
+	for {
+		sagas, err := uds.sagaRepository.ClaimStale(ctx, time.Now().Add(-deletionStaleAfter), resumeBatchSize)
+		if err != nil {
+			return completed, err
+		}
+		if len(sagas) == 0 {
+			return completed, nil
+		}
+
+		for i := range sagas {
+			s := &sagas[i]
+			uds.mCounter.WithLabelValues("user_deletion_resumed_total").Inc()
+			if err = uds.run(ctx, s); err != nil {
+				uds.logger.Warn(
+					"user deletion not completed",
+					zap.Stringer("saga_uuid", s.UUID),
+					zap.Stringer("user_uuid", s.UserUUID),
+					zap.String("step", string(s.Step)),
+					zap.Int("attempts", s.Attempts),
+					zap.Error(err),
+				)
+				continue
+			}
+			completed++
+		}
+	}
+}
+
+// run takes s from its step to the end, storing every step done: a resumed
+// saga redoes at most the step it was interrupted in, each one is idempotent.
+// A failure is recorded and s stays running, the ones of the user step keeping
+// the user compensate it.
+func (uds *UserDeletionService) run(ctx context.Context, s *deletion.Saga) error {
+	// the state is stored even if the request is gone
+	storeCtx := context.WithoutCancel(ctx)
+
+	var u *domain.User
+	for s.Status == deletion.StatusRunning {
+		var err error
+		switch s.Step {
+		case deletion.StepDeleteObjects:
+			err = uds.deleteObjects(ctx, s.Keys)
+		case deletion.StepDeleteFiles:
+			err = uds.userFileRepository.DeleteUserFilesByUUIDs(ctx, s.FileUUIDs)
+		case deletion.StepDeleteUser:
+			u, err = uds.deleteUser(ctx, s)
+			if errors.Is(err, domain.ErrLegalHold) || errors.Is(err, domain.ErrLastAdmin) || errors.Is(err, domain.ErrNotFound) {
+				return uds.compensate(storeCtx, s, err)
+			}
+		case deletion.StepPublish:
+			err = uds.publish(ctx, s, u)
+		}
+		if err != nil {
+			s.LastError = err.Error()
+			if uErr := uds.sagaRepository.Update(storeCtx, *s); uErr != nil {
+				uds.logger.Error("user deletion saga not stored", zap.Stringer("saga_uuid", s.UUID), zap.Error(uErr))
+			}
+			uds.mCounter.WithLabelValues("user_deletion_failed_total").Inc()
+			return err
+		}
+
+		s.LastError = ""
+		if next, ok := nextStep[s.Step]; ok {
+			s.Step = next
+		} else {
+			s.Status = deletion.StatusCompleted
+		}
+		if err = uds.sagaRepository.Update(storeCtx, *s); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (uds *UserDeletionService) deleteObjects(ctx context.Context, keys []string) error {
+	for start := 0; start < len(keys); start += purgeBatchSize {
+		end := min(start+purgeBatchSize, len(keys))
+		if err := uds.storage.DeleteObjects(ctx, keys[start:end]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// deleteUser - a resumed saga(Attempts > 1) may find the user deleted by the
+// interrupted attempt, a first one found it deleted by another request
+func (uds *UserDeletionService) deleteUser(ctx context.Context, s *deletion.Saga) (*domain.User, error) {
+	u, err := uds.userRepository.DeleteUser(ctx, s.UserID, s.Reason, s.ActorUUID)
+	if !errors.Is(err, domain.ErrNotFound) || s.Attempts < 2 {
+		return u, err
+	}
+
+	return uds.deletedUser(ctx, s)
+}
+
+func (uds *UserDeletionService) deletedUser(ctx context.Context, s *deletion.Saga) (*domain.User, error) {
+	v, err := uds.userRepository.FetchVersionAt(ctx, s.UserID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if v.User.DeletedAt == nil {
+		return nil, domain.ErrNotFound
+	}
+
+	return &v.User, nil
+}
+
+// publish - u is nil if the saga was resumed after the user step. Its access
+// was revoked in the user step, UserAccessRevoked follows UserDeleted
+func (uds *UserDeletionService) publish(ctx context.Context, s *deletion.Saga, u *domain.User) error {
+	if u == nil {
+		var err error
+		if u, err = uds.deletedUser(ctx, s); err != nil {
+			return err
+		}
+	}
+
+	now := time.Now()
+	publishEvent(ctx, uds.mq, mq.Event{
+		Id:      uuid.New(),
+		TS:      now,
+		Method:  http.MethodDelete,
+		UserID:  u.UUID.String(),
+		Payload: user.ToResponseUser(*u),
+		Meta:    map[string]string{"deleted_reason": string(s.Reason)},
+	})
+	publishAccessRevoked(ctx, uds.mq, u.UUID, s.Reason, now)
+	uds.mCounter.WithLabelValues("user_deleted_total").Inc()
+
+	return nil
+}
+
+// compensate ends s on a failure of the user step keeping the user: it is the
+// first step, so nothing was deleted yet
+func (uds *UserDeletionService) compensate(ctx context.Context, s *deletion.Saga, cause error) error {
+	s.Status = deletion.StatusCompensated
+	s.LastError = cause.Error()
+	if err := uds.sagaRepository.Update(ctx, *s); err != nil {
+		uds.logger.Error("user deletion saga not stored", zap.Stringer("saga_uuid", s.UUID), zap.Error(err))
+	}
+	uds.mCounter.WithLabelValues("user_deletion_compensated_total").Inc()
+
+	return cause
+}
@@ -0,0 +1,135 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"user-manager-api/internal/application/ports"
+	"user-manager-api/internal/domain/deletion"
+	domain "user-manager-api/internal/domain/user"
+	"user-manager-api/internal/domain/user_file"
+)
+
+// deletionCalls - the deletions of the saga in their order
+type deletionCalls []string
+
+// sagaRepository - the sagas stored in memory
+type sagaRepository struct {
+	deletion.Repository
+	saga deletion.Saga
+}
+
+func (r *sagaRepository) Create(_ context.Context, s deletion.Saga) (*deletion.Saga, error) {
+	s.UUID, s.Status, s.Attempts = uuid.New(), deletion.StatusRunning, 1
+	r.saga = s
+	return &s, nil
+}
+
+func (r *sagaRepository) Update(_ context.Context, s deletion.Saga) error {
+	r.saga = s
+	return nil
+}
+
+// deletingUserRepository - DeleteUser fails with deleteErr
+type deletingUserRepository struct {
+	domain.Repository
+	calls     *deletionCalls
+	deleteErr error
+}
+
+func (r *deletingUserRepository) FetchInternalID(context.Context, domain.UUID) (domain.ID, error) {
+	return 1, nil
+}
+
+func (r *deletingUserRepository) CheckDeletable(context.Context, domain.ID) error {
+	return nil
+}
+
+func (r *deletingUserRepository) DeleteUser(context.Context, domain.ID, domain.DeletionReason, domain.UUID) (*domain.User, error) {
+	if r.deleteErr != nil {
+		return nil, r.deleteErr
+	}
+	*r.calls = append(*r.calls, "user")
+	return &domain.User{UUID: uuid.New()}, nil
+}
+
+// deletableFilesRepository - a single deletable file, with a thumbnail
+type deletableFilesRepository struct {
+	user_file.Repository
+	calls *deletionCalls
+}
+
+func (r *deletableFilesRepository) FetchDeletableFiles(context.Context, domain.ID) (user_file.UserFiles, error) {
+	return user_file.UserFiles{{
+		UUID:         uuid.New(),
+		StorageKey:   "documents/users/u/a.pdf",
+		ThumbnailURL: "https://cdn.example.com/thumbnails/users/u/a.png",
+	}}, nil
+}
+
+func (r *deletableFilesRepository) DeleteUserFilesByUUIDs(context.Context, []uuid.UUID) error {
+	*r.calls = append(*r.calls, "files")
+	return nil
+}
+
+// deletingStorage - the objects deleted
+type deletingStorage struct {
+	ports.ObjectStorage
+	calls *deletionCalls
+	keys  []string
+}
+
+func (s *deletingStorage) DeleteObjects(_ context.Context, keys []string) error {
+	*s.calls = append(*s.calls, "objects")
+	s.keys = append(s.keys, keys...)
+	return nil
+}
+
+func TestUserDeletionService_Delete(t *testing.T) {
+	mCounter := prometheus.NewCounterVec(prometheus.CounterOpts{Name: "test"}, []string{"result"})
+
+	tests := []struct {
+		name       string
+		deleteErr  error
+		wantCalls  deletionCalls
+		wantKeys   []string
+		wantStatus deletion.Status
+	}{
+		{
+			"the objects go last", nil, deletionCalls{"user", "files", "objects"},
+			[]string{"documents/users/u/a.pdf", "thumbnails/users/u/a.png"}, deletion.StatusCompleted,
+		},
+		{"legal hold", domain.ErrLegalHold, nil, nil, deletion.StatusCompensated},
+		{"last admin", domain.ErrLastAdmin, nil, nil, deletion.StatusCompensated},
+		{"deleted meanwhile", domain.ErrNotFound, nil, nil, deletion.StatusCompensated},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls deletionCalls
+			sagas := &sagaRepository{}
+			storage := &deletingStorage{calls: &calls}
+			uds := NewUserDeletionService(
+				storage,
+				sagas,
+				&deletingUserRepository{calls: &calls, deleteErr: tt.deleteErr},
+				&deletableFilesRepository{calls: &calls},
+				&recordingMQ{},
+				zap.NewNop(),
+				mCounter,
+			)
+
+			err := uds.Delete(context.Background(), uuid.New(), uuid.New(), domain.DeletionUserRequest)
+			require.ErrorIs(t, err, tt.deleteErr)
+			assert.Equal(t, tt.wantCalls, calls, "no object is deleted before the user")
+			assert.Equal(t, tt.wantKeys, storage.keys, "the thumbnail goes with the object")
+			assert.Equal(t, tt.wantStatus, sagas.saga.Status)
+		})
+	}
+}
@@ -2,27 +2,54 @@ package services
 
 import (
 	"context"
-	"fmt"
+	"errors"
+	"io"
 	"mime"
 	"mime/multipart"
 	"path"
 	"path/filepath"
 	"regexp"
+	"strconv"
 	"strings"
 	"time"
 	"unicode"
 	"unicode/utf8"
 
+	"github.com/google/uuid"
 	"github.com/prometheus/client_golang/prometheus"
 	"golang.org/x/text/transform"
 	"golang.org/x/text/unicode/norm"
 
 	"user-manager-api/internal/application/ports"
+	"user-manager-api/internal/domain/pagination"
 	"user-manager-api/internal/domain/user"
 	domain "user-manager-api/internal/domain/user_file"
+	"user-manager-api/internal/infrastructure/mq"
+	"user-manager-api/pkg/bulkhead"
 )
 
-const maxBaseNameLen = 100
+const (
+	maxBaseNameLen     = 100
+	storageKeyPrefix   = "documents/"
+	storageKeyTSLayout = "20060102T150405.000000000Z"
+)
+
+var (
+	ErrUserFileNotFound = errors.New("file not found")
+	ErrFolderNotFound   = errors.New("folder not found")
+	// ErrTextNotFound - the text is not extracted(yet), or the file type has none
+	ErrTextNotFound = errors.New("file text not found")
+)
+
+// ErrTooManyFileOperations - the user has SERVICE_MAX_FILE_OPS_PER_USER uploads or deletes in flight
+var ErrTooManyFileOperations = errors.New("too many concurrent file operations of the user")
+
+// the rejections of the upload policy of the organization of the user
+var (
+	ErrFileTypeNotAllowed = errors.New("the file type is not allowed by the upload policy")
+	ErrFileTooLarge       = errors.New("the file is larger than the upload policy allows")
+	ErrQuotaExceeded      = errors.New("the file exceeds the storage quota of the user")
+)
 
 var (
 	windowsReserved = map[string]struct{}{
@@ -35,45 +62,77 @@ var (
 )
 
 type UserFileService struct {
-	s3                 ports.S3Client
+	storage            ports.ObjectStorage
+	thumbnails         ports.ThumbnailService
+	texts              ports.TextExtractionService
 	userFileRepository domain.Repository
 	userRepository     user.Repository
+	uploadPolicies     ports.UploadPolicyService
+	mq                 ports.RabbitMQ
 	mCounter           *prometheus.CounterVec
+	// perUser - the uploads and deletes in flight of a user, nil - unlimited
+	perUser *bulkhead.Keyed
 }
 
+// NewUserFileService - maxConcurrentPerUser keeps one client from saturating the
+// storage bandwidth and the DB connections, 0 - unlimited
 func NewUserFileService(
-	s3 ports.S3Client,
+	storage ports.ObjectStorage,
+	thumbnails ports.ThumbnailService,
+	texts ports.TextExtractionService,
 	userFileRepository domain.Repository,
 	userRepository user.Repository,
+	uploadPolicies ports.UploadPolicyService,
+	mq ports.RabbitMQ,
 	mCounter *prometheus.CounterVec,
+	maxConcurrentPerUser int,
 ) ports.UserFileService {
-	return &UserFileService{
-		s3:                 s3,
+	ufs := &UserFileService{
+		storage:            storage,
+		thumbnails:         thumbnails,
+		texts:              texts,
 		userFileRepository: userFileRepository,
 		userRepository:     userRepository,
+		uploadPolicies:     uploadPolicies,
+		mq:                 mq,
 		mCounter:           mCounter,
 	}
+	if maxConcurrentPerUser > 0 {
+		ufs.perUser = bulkhead.NewKeyed(maxConcurrentPerUser)
+	}
+
+	return ufs
 }
 
-func (ufs *UserFileService) FindUserFiles(ctx context.Context, userUUID user.UUID, page int) (domain.UserFiles, error) {
+func (ufs *UserFileService) StreamUserFiles(
+	ctx context.Context,
+	userUUID user.UUID,
+	p pagination.Params,
+	tags []string,
+	folder *string,
+	fn func(uf *domain.UserFile) error,
+) error {
 	id, err := ufs.userRepository.FetchInternalID(ctx, userUUID)
 	if err != nil {
-		return nil, err
-	}
-
-	fls, err := ufs.userFileRepository.FetchUserFiles(ctx, id, page)
-	if err != nil {
-		return nil, err
+		return err
 	}
 
-	return fls, nil
+	return ufs.userFileRepository.StreamUserFiles(ctx, id, p, tags, folder, fn)
 }
 
 func (ufs *UserFileService) CreateUserFile(
 	ctx context.Context,
 	userUUID user.UUID,
 	in *multipart.FileHeader,
+	tags []string,
+	folder string,
 ) (*domain.UserFile, error) {
+	release, err := ufs.acquire(userUUID)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
 	uf := new(domain.UserFile)
 
 	id, err := ufs.userRepository.FetchInternalID(ctx, userUUID)
@@ -81,28 +140,106 @@ func (ufs *UserFileService) CreateUserFile(
 		return nil, err
 	}
 
+	uf.Folder = folder
 	uf = ufs.fillMetaData(in, uf, userUUID)
+	uf.Tags = tags
+	if err = ufs.checkUploadPolicy(ctx, userUUID, id, uf); err != nil {
+		return nil, err
+	}
 	f, err := in.Open()
 	if err != nil {
 		return nil, err
 	}
 	defer f.Close()
-	// release memory
-	f = nil
 
-	// example: save obj to s3
-	// ufs.s3.PutObject(...)
+	if err = ufs.storage.PutObject(ctx, uf.StorageKey, uf.MimeType, f, in.Size); err != nil {
+		return nil, err
+	}
 
 	out, err := ufs.userFileRepository.CreateUserFile(ctx, id, uf)
 	if err != nil {
 		return nil, err
 	}
 
+	// the source of the previews is read into memory, the large uploads get
+	// none(THUMBNAILS_MAX_SOURCE_SIZE, OCR_MAX_SOURCE_SIZE)
+	thumbnail, text := ufs.thumbnails.Accepts(out), ufs.texts.Accepts(out)
+	if thumbnail || text {
+		if data, rerr := readAll(f); rerr == nil {
+			if thumbnail {
+				ufs.thumbnails.Enqueue(out, data)
+			}
+			if text {
+				ufs.texts.Enqueue(out, data)
+			}
+		}
+	}
+
+	ufs.publishFilesChanged(ctx, userUUID, 1)
 	ufs.mCounter.WithLabelValues("user_files_created_total").Inc()
 
 	return out, nil
 }
 
+// checkUploadPolicy - the declared type and the type of the extension are
+// both allowed, so "x.exe" sent as "image/png" is rejected. The quota is
+// checked against the files stored: the uploads in flight of the user(at most
+// SERVICE_MAX_FILE_OPS_PER_USER) may overrun it by their size
+func (ufs *UserFileService) checkUploadPolicy(
+	ctx context.Context,
+	userUUID user.UUID,
+	id user.ID,
+	uf *domain.UserFile,
+) error {
+	p, err := ufs.uploadPolicies.UserPolicy(ctx, userUUID)
+	if err != nil || p == nil {
+		return err
+	}
+
+	if err = checkUploadType(*p, uf); err == nil {
+		err = checkUploadSize(*p, uf)
+	}
+	if err == nil && p.QuotaBytes > 0 {
+		used, ferr := ufs.userFileRepository.FetchUsedBytes(ctx, id)
+		if ferr != nil {
+			return ferr
+		}
+		if used+uf.SizeBytes > p.QuotaBytes {
+			err = ErrQuotaExceeded
+		}
+	}
+	if err != nil {
+		ufs.mCounter.WithLabelValues("upload_policy_rejected_total").Inc()
+	}
+
+	return err
+}
+
+func checkUploadType(p domain.UploadPolicy, uf *domain.UserFile) error {
+	if len(p.AllowedTypes) == 0 {
+		return nil
+	}
+	declared, _, err := mime.ParseMediaType(uf.MimeType)
+	if err != nil || !p.AllowsType(declared) {
+		return ErrFileTypeNotAllowed
+	}
+	if byExt := mime.TypeByExtension(path.Ext(uf.FileName)); byExt != "" {
+		if byExt, _, err = mime.ParseMediaType(byExt); err != nil || !p.AllowsType(byExt) {
+			return ErrFileTypeNotAllowed
+		}
+	}
+
+	return nil
+}
+
+func checkUploadSize(p domain.UploadPolicy, uf *domain.UserFile) error {
+	if p.MaxFileSize > 0 && uf.SizeBytes > p.MaxFileSize {
+		return ErrFileTooLarge
+	}
+
+	return nil
+}
+
 func (ufs *UserFileService) fillMetaData(
 	in *multipart.FileHeader,
 	uf *domain.UserFile,
@@ -111,14 +248,18 @@ func (ufs *UserFileService) fillMetaData(
 	uf.FileName = filepath.Base(sanitizeFileName(in.Filename))
 	uf.MimeType = in.Header.Get("Content-Type")
 	uf.SizeBytes = uint64(in.Size)
-	uf.Bucket = ufs.s3.GetBucket()
+	uf.Bucket = ufs.storage.GetBucket()
 	uf.StorageKey = ufs.genSafeStorageKey(uf, userUUID)
-	uf.DownloadURL = ufs.s3.GetPublicURL(uf.StorageKey)
+	uf.DownloadURL = ufs.storage.GetPublicURL(uf.StorageKey)
 
 	return uf
 }
 
-// genSafeStorageKey: "documents/YYYY/MM/DD/<ts-nanosec>/<useruuid>/<filename>.ext"
+// genSafeStorageKey: "documents/users/<useruuid>/<ts-nanosec>/<filename>.ext", the
+// folder is left out: a move changes the metadata only and S3 has no rename. The
+// files uploaded before the users prefix are under
+// "documents/YYYY/MM/DD/<ts-nanosec>/<useruuid>/<filename>.ext", some of the
+// later ones under "documents/users/<useruuid>/<folder>/<ts-nanosec>/...".
 func (ufs *UserFileService) genSafeStorageKey(
 	uf *domain.UserFile,
 	userUUID user.UUID,
@@ -161,35 +302,196 @@ func (ufs *UserFileService) genSafeStorageKey(
 
 	safeFileName := base + ext
 
-	now := time.Now().UTC()
-	return fmt.Sprintf(
-		"documents/%04d/%02d/%02d/%s/%s/%s",
-		now.Year(), int(now.Month()), now.Day(),
-		now.Format("20060102T150405.000000000Z"),
-		strings.ToLower(strings.ReplaceAll(userUUID.String(), "-", "")),
-		safeFileName,
-	)
+	return storageKeyPrefix + "users/" + strings.ToLower(strings.ReplaceAll(userUUID.String(), "-", "")) + "/" +
+		time.Now().UTC().Format(storageKeyTSLayout) + "/" + safeFileName
 }
 
 func (ufs *UserFileService) DeleteUserFiles(
 	ctx context.Context,
 	userUUID user.UUID,
+	tags []string,
 ) error {
-	id, err := ufs.userRepository.FetchInternalID(ctx, userUUID)
+	release, err := ufs.acquire(userUUID)
 	if err != nil {
 		return err
 	}
+	defer release()
 
-	// example: delete objs from s3
-	// ufs.s3.DeleteObjects(ufs.userFileRepository.FetchUserFiles(...))
+	id, err := ufs.userRepository.FetchInternalID(ctx, userUUID)
+	if err != nil {
+		return err
+	}
+	// the query keeps the files of a held user too, this tells the caller
+	hold, err := ufs.userRepository.FetchLegalHold(ctx, userUUID)
+	if err != nil {
+		return err
+	}
+	if hold != nil {
+		return user.ErrLegalHold
+	}
 
-	if err = ufs.userFileRepository.DeleteUserFiles(ctx, id); err != nil {
+	deleted, err := ufs.userFileRepository.DeleteUserFiles(ctx, id, tags)
+	if err != nil {
 		return err
 	}
+	ufs.publishFilesChanged(ctx, userUUID, -int64(len(deleted)))
+
+	// the rows go first: objects left by a failed delete are orphans for the
+	// reconcile-files job
+	keys := make([]string, 0, len(deleted))
+	for _, uf := range deleted {
+		keys = append(keys, objectKeys(uf)...)
+	}
+	for start := 0; start < len(keys); start += purgeBatchSize {
+		end := min(start+purgeBatchSize, len(keys))
+		if err = ufs.storage.DeleteObjects(ctx, keys[start:end]); err != nil {
+			return err
+		}
+	}
 
 	return nil
 }
 
+func (ufs *UserFileService) ListFolders(ctx context.Context, userUUID user.UUID, parent string) (domain.Folders, error) {
+	id, err := ufs.userRepository.FetchInternalID(ctx, userUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	return ufs.userFileRepository.FetchFolders(ctx, id, parent)
+}
+
+func (ufs *UserFileService) GetFileText(ctx context.Context, userUUID user.UUID, fileUUID uuid.UUID) (*domain.Text, error) {
+	id, err := ufs.userRepository.FetchInternalID(ctx, userUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	t, err := ufs.userFileRepository.FetchText(ctx, id, fileUUID)
+	if err != nil {
+		return nil, err
+	}
+	if t == nil {
+		return nil, ErrTextNotFound
+	}
+
+	return t, nil
+}
+
+func (ufs *UserFileService) SearchUserFiles(ctx context.Context, userUUID user.UUID, query string, limit int) (domain.UserFiles, error) {
+	id, err := ufs.userRepository.FetchInternalID(ctx, userUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	return ufs.userFileRepository.SearchUserFiles(ctx, id, query, limit)
+}
+
+func (ufs *UserFileService) MoveUserFile(
+	ctx context.Context,
+	userUUID user.UUID,
+	fileUUID uuid.UUID,
+	folder, fileName *string,
+) (*domain.UserFile, error) {
+	id, err := ufs.userRepository.FetchInternalID(ctx, userUUID)
+	if err != nil {
+		return nil, err
+	}
+	if fileName != nil {
+		name := filepath.Base(sanitizeFileName(*fileName))
+		fileName = &name
+	}
+
+	uf, err := ufs.userFileRepository.MoveUserFile(ctx, id, fileUUID, folder, fileName)
+	if err != nil {
+		return nil, err
+	}
+	if uf == nil {
+		return nil, ErrUserFileNotFound
+	}
+	ufs.mCounter.WithLabelValues("user_files_moved_total").Inc()
+
+	return uf, nil
+}
+
+func (ufs *UserFileService) MoveFolder(ctx context.Context, userUUID user.UUID, from, to string) (int64, error) {
+	id, err := ufs.userRepository.FetchInternalID(ctx, userUUID)
+	if err != nil {
+		return 0, err
+	}
+
+	moved, err := ufs.userFileRepository.MoveFolder(ctx, id, from, to)
+	if err != nil {
+		return 0, err
+	}
+	if moved == 0 {
+		return 0, ErrFolderNotFound
+	}
+	ufs.mCounter.WithLabelValues("user_files_moved_total").Add(float64(moved))
+
+	return moved, nil
+}
+
+// ObjectOwner - the owner of the live file of the storage key, of the object
+// itself or of its thumbnail, and the type stored at the upload(of the
+// thumbnail - thumbnailType). ErrUserFileNotFound for the keys of the deleted
+// files and of no file at all
+func (ufs *UserFileService) ObjectOwner(ctx context.Context, key string) (*domain.ObjectOwner, error) {
+	var thumbnailURL string
+	if strings.HasPrefix(key, thumbnailKeyPrefix) {
+		// the thumbnail keys are not stored, their URLs are
+		thumbnailURL = ufs.storage.GetPublicURL(key)
+	}
+
+	owner, err := ufs.userFileRepository.FetchObjectOwner(ctx, key, thumbnailURL)
+	if err != nil {
+		return nil, err
+	}
+	if owner == nil {
+		return nil, ErrUserFileNotFound
+	}
+	if thumbnailURL != "" {
+		owner.MimeType = thumbnailType
+	}
+
+	return owner, nil
+}
+
+// acquire - a slot of the user's file operations, ErrTooManyFileOperations
+// without waiting when they are all taken
+func (ufs *UserFileService) acquire(userUUID user.UUID) (func(), error) {
+	if ufs.perUser == nil {
+		return func() {}, nil
+	}
+	release, err := ufs.perUser.TryAcquire(userUUID.String())
+	if err != nil {
+		ufs.mCounter.WithLabelValues("user_file_ops_rejected_total").Inc()
+		return nil, ErrTooManyFileOperations
+	}
+
+	return release, nil
+}
+
+// publishFilesChanged - the files stats are refreshed by the event consumer,
+// delta - the uploaded minus the deleted files
+func (ufs *UserFileService) publishFilesChanged(ctx context.Context, userUUID user.UUID, delta int64) {
+	publishEvent(ctx, ufs.mq, mq.Event{
+		Id:     uuid.New(),
+		TS:     time.Now(),
+		Method: mq.EventUserFilesChanged,
+		UserID: userUUID.String(),
+		Meta:   map[string]string{mq.MetaFilesDelta: strconv.FormatInt(delta, 10)},
+	})
+}
+
+// readAll rewinds the already uploaded file and reads it for async processing
+func readAll(f multipart.File) ([]byte, error) {
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	return io.ReadAll(f)
+}
+
 // sanitizeFileName make file name ASCII standard
 func sanitizeFileName(original string) string {
 	if original == "" {
@@ -0,0 +1,126 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"user-manager-api/internal/domain/user"
+	domain "user-manager-api/internal/domain/user_file"
+	"user-manager-api/internal/infrastructure/mq"
+)
+
+func TestGenSafeStorageKey(t *testing.T) {
+	userUUID := uuid.MustParse("6f1c2b1e-8d7a-4c1e-9f3a-2b5d7e9a1c3f")
+	ufs := &UserFileService{}
+	before := time.Now().UTC().Add(-time.Second)
+
+	tests := []struct {
+		name   string
+		uf     domain.UserFile
+		prefix string
+		suffix string
+	}{
+		{"root", domain.UserFile{FileName: "report.pdf"}, "documents/users/6f1c2b1e8d7a4c1e9f3a2b5d7e9a1c3f/", "/report.pdf"},
+		// the folder is metadata only: a move keeps the key valid
+		{"folder", domain.UserFile{FileName: "a.png", Folder: "Invoices/2026 Q1"}, "documents/users/6f1c2b1e8d7a4c1e9f3a2b5d7e9a1c3f/2", "/a.png"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key := ufs.genSafeStorageKey(&tt.uf, userUUID)
+			assert.True(t, strings.HasPrefix(key, tt.prefix), key)
+			assert.True(t, strings.HasSuffix(key, tt.suffix), key)
+			assert.NotContains(t, key, "Invoices")
+			// the reconciliation grace period reads the upload time back
+			assert.WithinDuration(t, before, storageKeyTime(key), 2*time.Second)
+		})
+	}
+
+	legacy := "documents/2025/10/03/20251003T101112.000000000Z/6f1c2b1e8d7a4c1e9f3a2b5d7e9a1c3f/report.pdf"
+	assert.Equal(t, time.Date(2025, 10, 3, 10, 11, 12, 0, time.UTC), storageKeyTime(legacy))
+}
+
+func BenchmarkSanitizeFileName(b *testing.B) {
+	names := []string{
+		"report.pdf",
+		"  Résumé Final (v2).DOCX ",
+		`C:\Users\john\Desktop\photo 2026-01-02.JPG`,
+		"日本語のファイル名.txt",
+	}
+	b.ReportAllocs()
+	for b.Loop() {
+		for _, n := range names {
+			_ = sanitizeFileName(n)
+		}
+	}
+}
+
+// taggedFilesRepository - DeleteUserFiles deletes files
+type taggedFilesRepository struct {
+	domain.Repository
+	files domain.UserFiles
+}
+
+func (r *taggedFilesRepository) DeleteUserFiles(context.Context, user.ID, []string) (domain.UserFiles, error) {
+	return r.files, nil
+}
+
+// holdUserRepository - a user on the hold if hold is set
+type holdUserRepository struct {
+	user.Repository
+	hold *user.LegalHold
+}
+
+func (r *holdUserRepository) FetchInternalID(context.Context, user.UUID) (user.ID, error) {
+	return 1, nil
+}
+
+func (r *holdUserRepository) FetchLegalHold(context.Context, user.UUID) (*user.LegalHold, error) {
+	return r.hold, nil
+}
+
+func TestUserFileService_DeleteUserFiles(t *testing.T) {
+	mCounter := prometheus.NewCounterVec(prometheus.CounterOpts{Name: "test"}, []string{"result"})
+	files := domain.UserFiles{
+		{StorageKey: "documents/users/u/a.pdf", ThumbnailURL: "https://cdn.example.com/thumbnails/users/u/a.png"},
+		{StorageKey: "documents/users/u/b.txt"},
+	}
+
+	t.Run("the objects are deleted", func(t *testing.T) {
+		var calls deletionCalls
+		storage := &deletingStorage{calls: &calls}
+		publisher := &recordingMQ{}
+		ufs := NewUserFileService(storage, nil, nil, &taggedFilesRepository{files: files}, &holdUserRepository{},
+			nil, publisher, mCounter, 0)
+
+		require.NoError(t, ufs.DeleteUserFiles(context.Background(), uuid.New(), []string{"invoices"}))
+		assert.Equal(t, []string{"documents/users/u/a.pdf", "thumbnails/users/u/a.png", "documents/users/u/b.txt"}, storage.keys)
+		assert.Equal(t, []string{mq.EventUserFilesChanged}, publisher.methods)
+	})
+
+	t.Run("nothing deleted", func(t *testing.T) {
+		var calls deletionCalls
+		storage := &deletingStorage{calls: &calls}
+		ufs := NewUserFileService(storage, nil, nil, &taggedFilesRepository{}, &holdUserRepository{},
+			nil, &recordingMQ{}, mCounter, 0)
+
+		require.NoError(t, ufs.DeleteUserFiles(context.Background(), uuid.New(), nil))
+		assert.Empty(t, calls)
+	})
+
+	t.Run("legal hold", func(t *testing.T) {
+		var calls deletionCalls
+		ufs := NewUserFileService(&deletingStorage{calls: &calls}, nil, nil, &taggedFilesRepository{files: files},
+			&holdUserRepository{hold: &user.LegalHold{}}, nil, &recordingMQ{}, mCounter, 0)
+
+		require.ErrorIs(t, ufs.DeleteUserFiles(context.Background(), uuid.New(), nil), user.ErrLegalHold)
+		assert.Empty(t, calls)
+	})
+}
@@ -0,0 +1,49 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"user-manager-api/internal/application/ports"
+	domain "user-manager-api/internal/domain/user"
+)
+
+// ErrNoVersionAt - the user was created after the requested time
+var ErrNoVersionAt = errors.New("the user did not exist at as_of")
+
+// UserHistoryService - the prior versions of the users for the investigations,
+// recorded by the users_history trigger on every profile change
+type UserHistoryService struct {
+	userRepository domain.Repository
+}
+
+func NewUserHistoryService(userRepository domain.Repository) ports.UserHistoryService {
+	return &UserHistoryService{userRepository: userRepository}
+}
+
+func (uhs *UserHistoryService) History(ctx context.Context, userUUID domain.UUID) ([]domain.Revision, error) {
+	id, err := uhs.userRepository.FetchInternalID(ctx, userUUID)
+	if err != nil {
+		return nil, err
+	}
+	versions, err := uhs.userRepository.FetchHistory(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return domain.Revisions(versions), nil
+}
+
+func (uhs *UserHistoryService) VersionAt(ctx context.Context, userUUID domain.UUID, at time.Time) (*domain.Version, error) {
+	id, err := uhs.userRepository.FetchInternalID(ctx, userUUID)
+	if err != nil {
+		return nil, err
+	}
+	v, err := uhs.userRepository.FetchVersionAt(ctx, id, at)
+	if errors.Is(err, domain.ErrNotFound) {
+		return nil, ErrNoVersionAt
+	}
+
+	return v, err
+}
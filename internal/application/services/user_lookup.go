@@ -0,0 +1,67 @@
+package services
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"user-manager-api/internal/application/ports"
+	"user-manager-api/internal/domain/audit"
+	domain "user-manager-api/internal/domain/user"
+)
+
+type UserLookupService struct {
+	userRepository domain.Repository
+	auditService   ports.AuditService
+	mCounter       *prometheus.CounterVec
+}
+
+func NewUserLookupService(
+	userRepository domain.Repository,
+	auditService ports.AuditService,
+	mCounter *prometheus.CounterVec,
+) ports.UserLookupService {
+	return &UserLookupService{
+		userRepository: userRepository,
+		auditService:   auditService,
+		mCounter:       mCounter,
+	}
+}
+
+func (uls *UserLookupService) LookupByID(ctx context.Context, actor domain.UUID, id domain.ID) (*domain.Identity, error) {
+	ident, err := uls.userRepository.FetchIdentityByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return uls.record(ctx, actor, ident, "id")
+}
+
+func (uls *UserLookupService) LookupByUUID(ctx context.Context, actor, userUUID domain.UUID) (*domain.Identity, error) {
+	ident, err := uls.userRepository.FetchIdentityByUUID(ctx, userUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	return uls.record(ctx, actor, ident, "user_id")
+}
+
+// record - nothing is resolved without its entry: the lookup is read only, so
+// it fails instead of going unaudited
+func (uls *UserLookupService) record(ctx context.Context, actor domain.UUID, ident *domain.Identity, by string) (*domain.Identity, error) {
+	if err := uls.auditService.Record(ctx, audit.Entry{
+		ActorUUID:  actor,
+		Action:     audit.ActionUserLookedUp,
+		TargetUUID: &ident.UUID,
+		Details: map[string]any{
+			"by": by,
+			"id": strconv.FormatUint(uint64(ident.ID), 10),
+		},
+	}); err != nil {
+		return nil, err
+	}
+	uls.mCounter.WithLabelValues("user_lookup_total").Inc()
+
+	return ident, nil
+}
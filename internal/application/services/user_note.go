@@ -0,0 +1,85 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"user-manager-api/internal/application/ports"
+	"user-manager-api/internal/domain/pagination"
+	"user-manager-api/internal/domain/user"
+	domain "user-manager-api/internal/domain/user_note"
+)
+
+var ErrNoteNotFound = errors.New("note not found")
+
+type UserNoteService struct {
+	userNoteRepository domain.Repository
+	userRepository     user.Repository
+	mCounter           *prometheus.CounterVec
+}
+
+func NewUserNoteService(
+	userNoteRepository domain.Repository,
+	userRepository user.Repository,
+	mCounter *prometheus.CounterVec,
+) ports.UserNoteService {
+	return &UserNoteService{
+		userNoteRepository: userNoteRepository,
+		userRepository:     userRepository,
+		mCounter:           mCounter,
+	}
+}
+
+func (uns *UserNoteService) FindNotes(ctx context.Context, userUUID user.UUID, p pagination.Params) (domain.Notes, error) {
+	id, err := uns.internalID(ctx, userUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	return uns.userNoteRepository.FetchNotes(ctx, id, p)
+}
+
+func (uns *UserNoteService) CreateNote(ctx context.Context, userUUID user.UUID, n domain.Note) (*domain.Note, error) {
+	id, err := uns.internalID(ctx, userUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	note, err := uns.userNoteRepository.CreateNote(ctx, id, n)
+	if err != nil {
+		return nil, err
+	}
+
+	uns.mCounter.WithLabelValues("user_notes_created_total").Inc()
+
+	return note, nil
+}
+
+func (uns *UserNoteService) DeleteNote(ctx context.Context, userUUID user.UUID, noteUUID uuid.UUID) error {
+	id, err := uns.internalID(ctx, userUUID)
+	if err != nil {
+		return err
+	}
+
+	deleted, err := uns.userNoteRepository.DeleteNote(ctx, id, noteUUID)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrNoteNotFound
+	}
+
+	return nil
+}
+
+// internalID resolves an active user, ErrUserNotFound for unknown or deleted ones
+func (uns *UserNoteService) internalID(ctx context.Context, userUUID user.UUID) (user.ID, error) {
+	if _, err := uns.userRepository.FetchUserByID(ctx, userUUID); err != nil {
+		return 0, err
+	}
+
+	return uns.userRepository.FetchInternalID(ctx, userUUID)
+}
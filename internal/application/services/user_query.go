@@ -0,0 +1,89 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"user-manager-api/internal/application/ports"
+	"user-manager-api/internal/domain/pagination"
+	domain "user-manager-api/internal/domain/user"
+	"user-manager-api/internal/domain/user_file"
+)
+
+// UserQueries - the reads of the users, through their own repositories: a
+// replica, a cache or a read model is wired in without touching UserCommands
+type UserQueries struct {
+	userRepository    domain.QueryRepository
+	summaryRepository user_file.SummaryRepository
+	timezones         domain.Timezones
+}
+
+func NewUserQueries(
+	userRepository domain.QueryRepository,
+	summaryRepository user_file.SummaryRepository,
+	timezones domain.Timezones,
+) ports.UserQueries {
+	return &UserQueries{
+		userRepository:    userRepository,
+		summaryRepository: summaryRepository,
+		timezones:         timezones,
+	}
+}
+
+func (uq *UserQueries) FindUserByID(ctx context.Context, uuid domain.UUID) (*domain.User, error) {
+	u, err := uq.userRepository.FetchUserByID(ctx, uuid)
+	if err != nil {
+		return nil, err
+	}
+	if err = uq.withFilesSummary(ctx, domain.Users{u}); err != nil {
+		return nil, err
+	}
+	u.Birthday = uq.timezones.BirthdayAt(*u, time.Now())
+
+	return u, nil
+}
+
+func (uq *UserQueries) FindByEmail(ctx context.Context, email string) (*domain.User, error) {
+	u, err := uq.userRepository.FetchUserByEmail(ctx, email)
+	if err != nil {
+		return nil, err
+	}
+
+	return u, nil
+}
+
+// StreamUsers - the page rows come with their files summary, no extra query
+func (uq *UserQueries) StreamUsers(ctx context.Context, p pagination.Params, fn func(u *domain.User) error) error {
+	now := time.Now()
+	return uq.userRepository.StreamUsers(ctx, p, func(u *domain.User) error {
+		u.Birthday = uq.timezones.BirthdayAt(*u, now)
+		return fn(u)
+	})
+}
+
+func (uq *UserQueries) FindDeletedUsers(ctx context.Context, reason domain.DeletionReason, p pagination.Params) (domain.Users, error) {
+	return uq.userRepository.FetchDeletedUsers(ctx, reason, p)
+}
+
+// withFilesSummary - one aggregated query for all the users
+func (uq *UserQueries) withFilesSummary(ctx context.Context, users domain.Users) error {
+	if len(users) == 0 {
+		return nil
+	}
+	uuids := make([]uuid.UUID, len(users))
+	for i, u := range users {
+		uuids[i] = u.UUID
+	}
+	summaries, err := uq.summaryRepository.FetchSummaries(ctx, uuids)
+	if err != nil {
+		return err
+	}
+	for _, u := range users {
+		s := summaries[u.UUID]
+		u.Files = &s
+	}
+
+	return nil
+}
@@ -0,0 +1,134 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"user-manager-api/internal/domain/usage"
+	domain "user-manager-api/internal/domain/user"
+)
+
+// missingUserRepository - no user exists
+type missingUserRepository struct {
+	domain.Repository
+}
+
+func (missingUserRepository) FetchUserByID(context.Context, domain.UUID) (*domain.User, error) {
+	return nil, domain.ErrNotFound
+}
+
+// emailChangeRepository - cur exists, the confirmation fails with confirmErr
+type emailChangeRepository struct {
+	domain.Repository
+	cur        domain.User
+	changes    int
+	confirmErr error
+}
+
+func (r *emailChangeRepository) FetchUserByID(context.Context, domain.UUID) (*domain.User, error) {
+	u := r.cur
+	return &u, nil
+}
+
+func (r *emailChangeRepository) FetchInternalID(context.Context, domain.UUID) (domain.ID, error) {
+	return 1, nil
+}
+
+func (r *emailChangeRepository) CreateEmailChange(context.Context, domain.ID, domain.EmailChange) error {
+	r.changes++
+	return nil
+}
+
+func (r *emailChangeRepository) UpdateUser(_ context.Context, u domain.User) (*domain.User, error) {
+	return &u, nil
+}
+
+func (r *emailChangeRepository) ConfirmEmailChange(context.Context, []byte) (*domain.User, error) {
+	return nil, r.confirmErr
+}
+
+// fullSeatRepository - the organization full.example has no free seat
+type fullSeatRepository struct {
+	usage.Repository
+}
+
+func (fullSeatRepository) FetchSeatLimit(_ context.Context, org string) (*usage.SeatLimit, error) {
+	if org != "full.example" {
+		return nil, nil
+	}
+	return &usage.SeatLimit{Org: org, Seats: 1, ActiveUsers: 1}, nil
+}
+
+func TestUserCommands_EmailChange_SeatLimit(t *testing.T) {
+	tests := []struct {
+		name        string
+		cur         string
+		email       string
+		wantErr     error
+		wantChanges int
+	}{
+		{"another organization without a seat", "john@old.example", "john@full.example", ErrSeatLimitReached, 0},
+		{"another organization with a seat", "john@old.example", "john@free.example", nil, 1},
+		// the user keeps the own seat
+		{"the same organization", "john@full.example", "johnny@Full.example", nil, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mCounter := prometheus.NewCounterVec(prometheus.CounterOpts{Name: "test"}, []string{"result"})
+			repo := &emailChangeRepository{cur: domain.User{UUID: uuid.New(), Email: tt.cur}}
+			timezones := domain.NewTimezones(time.UTC, nil)
+			us := NewUserCommands(
+				repo, nil, fullSeatRepository{}, &recordingMQ{}, mCounter, time.Hour,
+				timezones, domain.NewAgePolicy(0, nil, timezones),
+			)
+
+			_, err := us.UpdateUser(context.Background(), domain.User{UUID: repo.cur.UUID, Email: tt.email})
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				assert.Equal(t, 1.0, testutil.ToFloat64(mCounter.WithLabelValues("seat_limit_rejected_total")))
+			} else {
+				require.NoError(t, err)
+			}
+			assert.Equal(t, tt.wantChanges, repo.changes)
+		})
+	}
+}
+
+func TestUserCommands_ConfirmEmailChange_SeatLimit(t *testing.T) {
+	mCounter := prometheus.NewCounterVec(prometheus.CounterOpts{Name: "test"}, []string{"result"})
+	publisher := &recordingMQ{}
+	timezones := domain.NewTimezones(time.UTC, nil)
+	us := NewUserCommands(
+		&emailChangeRepository{confirmErr: domain.ErrSeatLimitReached}, nil, nil, publisher, mCounter, time.Hour,
+		timezones, domain.NewAgePolicy(0, nil, timezones),
+	)
+
+	u, err := us.ConfirmEmailChange(context.Background(), "tok")
+	require.ErrorIs(t, err, ErrSeatLimitReached)
+	assert.Nil(t, u)
+	assert.Empty(t, publisher.methods)
+	assert.Equal(t, 1.0, testutil.ToFloat64(mCounter.WithLabelValues("seat_limit_rejected_total")))
+}
+
+func TestUserCommands_UpdateUser_NotFound(t *testing.T) {
+	mCounter := prometheus.NewCounterVec(prometheus.CounterOpts{Name: "test"}, []string{"result"})
+	publisher := &recordingMQ{}
+	timezones := domain.NewTimezones(time.UTC, nil)
+	us := NewUserCommands(
+		missingUserRepository{}, nil, nil, publisher, mCounter, time.Hour,
+		timezones, domain.NewAgePolicy(0, nil, timezones),
+	)
+
+	u, err := us.UpdateUser(context.Background(), domain.User{UUID: uuid.New(), Email: "new@example.com"})
+	require.ErrorIs(t, err, ErrUserNotFound)
+	assert.Nil(t, u)
+	assert.Empty(t, publisher.methods, "neither the update nor an email change is published")
+}
@@ -0,0 +1,54 @@
+package audit
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type (
+	Action string
+	// Entry - who did what to whom. UUIDs instead of internal IDs because
+	// the trail must outlive the users it mentions.
+	Entry struct {
+		// ID - 0 until it is stored
+		ID         int64
+		ActorUUID  uuid.UUID
+		Action     Action
+		TargetUUID *uuid.UUID
+		Details    map[string]any
+		CreatedAt  time.Time
+	}
+	// Filter - the audit log review, nil/zero fields are not applied
+	Filter struct {
+		ActorUUID  *uuid.UUID
+		Action     Action
+		TargetUUID *uuid.UUID
+		// From inclusive, To exclusive
+		From *time.Time
+		To   *time.Time
+	}
+)
+
+const (
+	ActionImpersonationStarted Action = "impersonation.started"
+	ActionImpersonatedRequest  Action = "impersonation.request"
+	ActionPasswordResetForced  Action = "password_reset.forced"
+	ActionPIIRedacted          Action = "retention.pii_redacted"
+	ActionUserInvited          Action = "invitation.created"
+	ActionRoleChanged          Action = "role.changed"
+	ActionReadOnlyChanged      Action = "read_only.changed"
+	ActionDeadLetterRequeued   Action = "dead_letter.requeued"
+	ActionDeadLetterDiscarded  Action = "dead_letter.discarded"
+	ActionLoginFlagged         Action = "login.flagged"
+	ActionUserMerged           Action = "user.merged"
+	ActionRetentionRuleCreated Action = "retention.rule_created"
+	ActionRetentionRuleDeleted Action = "retention.rule_deleted"
+	ActionFileRetentionChanged Action = "retention.file_changed"
+	ActionFilePurged           Action = "retention.file_purged"
+	ActionLegalHoldPlaced      Action = "legal_hold.placed"
+	ActionLegalHoldReleased    Action = "legal_hold.released"
+	ActionUserLookedUp         Action = "user.looked_up"
+	ActionUserSuspended        Action = "user.suspended"
+	ActionUserReactivated      Action = "user.reactivated"
+)
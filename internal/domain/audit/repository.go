@@ -0,0 +1,18 @@
+package audit
+
+import (
+	"context"
+	"time"
+
+	"user-manager-api/internal/domain/pagination"
+)
+
+type Repository interface {
+	CreateEntry(ctx context.Context, e Entry) error
+	// FetchEntries - a page of the entries matching f, created_at sort only
+	FetchEntries(ctx context.Context, f Filter, p pagination.Params) ([]Entry, error)
+	// FetchExpired - up to limit entries created before before, oldest first
+	FetchExpired(ctx context.Context, before time.Time, limit int) ([]Entry, error)
+	// DeleteEntries - the number of the deleted ones
+	DeleteEntries(ctx context.Context, ids []int64) (int64, error)
+}
@@ -0,0 +1,32 @@
+package backup
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	TableUsers     = "users"
+	TableUserFiles = "user_files"
+	TableAuditLog  = "audit_log"
+)
+
+// Tables - the tables of an archive in the restore order: the files reference
+// their users
+var Tables = []string{TableUsers, TableUserFiles, TableAuditLog}
+
+type (
+	// Row - a table row as a JSON object of its columns, the encrypted PII
+	// columns stay encrypted
+	Row struct {
+		Table string
+		Data  json.RawMessage
+	}
+	// Header - the first record of an archive
+	Header struct {
+		Format    int       `json:"format"`
+		CreatedAt time.Time `json:"created_at"`
+	}
+	// Counts - the rows per table
+	Counts map[string]int64
+)
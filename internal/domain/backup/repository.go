@@ -0,0 +1,21 @@
+package backup
+
+import (
+	"context"
+	"encoding/json"
+)
+
+type Repository interface {
+	// Export calls fn with the rows of the table by id, without a snapshot:
+	// the rows written meanwhile may be missing
+	Export(ctx context.Context, table string, fn func(row json.RawMessage) error) error
+	// IsEmpty - true if none of the Tables has a row
+	IsEmpty(ctx context.Context) (bool, error)
+	// Import inserts the rows keeping their ids, the users without deleted_by:
+	// the deleting admin may come later, see LinkDeletedBy
+	Import(ctx context.Context, table string, rows []json.RawMessage) error
+	// LinkDeletedBy restores the deleted_by of the users, by user id
+	LinkDeletedBy(ctx context.Context, deletedBy map[int64]int64) error
+	// ResetSequences moves the id sequences past the imported ids
+	ResetSequences(ctx context.Context) error
+}
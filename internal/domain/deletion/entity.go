@@ -0,0 +1,54 @@
+package deletion
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+
+	"user-manager-api/internal/domain/user"
+)
+
+// ErrInProgress - the user has a running saga already
+var ErrInProgress = errors.New("the deletion of the user is in progress")
+
+// the steps of a saga in their order: the objects, which can not be restored,
+// go last
+const (
+	StepDeleteUser    Step = "delete_user"
+	StepPublish       Step = "publish"
+	StepDeleteFiles   Step = "delete_files"
+	StepDeleteObjects Step = "delete_objects"
+)
+
+const (
+	StatusRunning   Status = "running"
+	StatusCompleted Status = "completed"
+	// StatusCompensated - stopped by a permanent failure, the changes done so far
+	// were compensated
+	StatusCompensated Status = "compensated"
+)
+
+type (
+	Step   string
+	Status string
+
+	// Saga - the stored state of a user deletion: Step is the next one to run.
+	// The files are the ones deletable at the start, their objects go last.
+	Saga struct {
+		UUID      uuid.UUID
+		UserID    user.ID
+		UserUUID  user.UUID
+		ActorUUID user.UUID
+		Reason    user.DeletionReason
+		Step      Step
+		Status    Status
+		FileUUIDs []uuid.UUID
+		Keys      []string
+		Attempts  int
+		LastError string
+
+		CreatedAt time.Time
+		UpdatedAt time.Time
+	}
+)
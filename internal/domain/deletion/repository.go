@@ -0,0 +1,18 @@
+package deletion
+
+import (
+	"context"
+	"time"
+)
+
+// Repository - the states of the user deletion sagas
+type Repository interface {
+	// Create stores s running at its first step, ErrInProgress if the user has
+	// a running saga
+	Create(ctx context.Context, s Saga) (*Saga, error)
+	// Update stores the step, the status and the last error of s
+	Update(ctx context.Context, s Saga) error
+	// ClaimStale - up to limit running sagas not updated since staleBefore, their
+	// attempts counted: the claimed ones are not claimed again until stale
+	ClaimStale(ctx context.Context, staleBefore time.Time, limit int) ([]Saga, error)
+}
@@ -0,0 +1,22 @@
+package device
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type (
+	// Device - a user agent the user logged in from, the tokens of the login
+	// are bound to it
+	Device struct {
+		UUID      uuid.UUID
+		UserAgent string
+		// IP - of the latest login
+		IP string
+
+		CreatedAt  time.Time
+		LastSeenAt time.Time
+	}
+	Devices []*Device
+)
@@ -0,0 +1,20 @@
+package device
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"user-manager-api/internal/domain/user"
+)
+
+type Repository interface {
+	// TouchDevice records a login of the user from userAgent, the device is
+	// created on its first login. user.ErrNotFound if the user is gone
+	TouchDevice(ctx context.Context, userUUID user.UUID, userAgent, ip string) (*Device, error)
+	// FetchDevices - the most recently seen first
+	FetchDevices(ctx context.Context, userUUID user.UUID) (Devices, error)
+	// DeleteDevice returns false if the user has no such device
+	DeleteDevice(ctx context.Context, userUUID user.UUID, deviceUUID uuid.UUID) (bool, error)
+	DeviceExists(ctx context.Context, deviceUUID uuid.UUID) (bool, error)
+}
@@ -0,0 +1,31 @@
+package directory
+
+import "time"
+
+type (
+	// Entry - a person of the directory(LDAP/Active Directory), matched to the
+	// users by email. Zero fields are missing attributes.
+	Entry struct {
+		Email     string
+		Name      string
+		Lastname  string
+		BirthDate time.Time
+		Phone     string
+		Disabled  bool
+	}
+	// Sync - the summary of a sync run, Error - the run failed as a whole
+	Sync struct {
+		StartedAt  time.Time
+		FinishedAt time.Time
+		Fetched    int
+		Created    int
+		Updated    int
+		Disabled   int
+		Unchanged  int
+		// Skipped - entries without the attributes a user requires
+		Skipped int
+		// Failed - entries the users could not be reconciled with(e.g. the last admin)
+		Failed int
+		Error  string
+	}
+)
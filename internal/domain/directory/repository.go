@@ -0,0 +1,9 @@
+package directory
+
+import "context"
+
+type Repository interface {
+	CreateSync(ctx context.Context, s Sync) error
+	// FetchLastSync - nil if never synced
+	FetchLastSync(ctx context.Context) (*Sync, error)
+}
@@ -0,0 +1,16 @@
+package event
+
+import (
+	"context"
+	"time"
+)
+
+// Repository - the events processed by the consumer, by their message id
+type Repository interface {
+	// Claim records id as processed, false if it is already within ttl
+	Claim(ctx context.Context, id string, ttl time.Duration) (bool, error)
+	// Release forgets id, e.g. its processing failed
+	Release(ctx context.Context, id string) error
+	// Purge deletes the ones processed longer than ttl ago
+	Purge(ctx context.Context, ttl time.Duration) (int64, error)
+}
@@ -0,0 +1,68 @@
+package eventbus
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	// ErrNoManagement - the topology can not be inspected without the management API
+	ErrNoManagement = errors.New("rabbitmq management API is not configured")
+	// ErrTopologyMismatch - the broker's exchange or queue differs from the declared
+	// one, a repair would have to delete it(and the queued messages)
+	ErrTopologyMismatch  = errors.New("rabbitmq topology mismatch is not repairable")
+	ErrNoDeadLetterQueue = errors.New("dead-letter queue is not configured")
+	// ErrRequeueNotConfirmed - the broker did not take the requeued message, it
+	// stays in the dead-letter queue
+	ErrRequeueNotConfirmed = errors.New("requeued message not confirmed by the broker")
+)
+
+type (
+	// EntityState - an exchange or a queue as the broker has it
+	EntityState struct {
+		Name  string
+		Found bool
+		// Mismatches - the properties differing from the declared ones
+		Mismatches []string
+	}
+	// TopologyReport - the broker's topology against the declared one and the
+	// state of the publisher
+	TopologyReport struct {
+		Connected bool
+		// Buffered, RetryQueued - the events waiting in the lanes and for a retry
+		Buffered    int
+		RetryQueued int
+
+		Exchange EntityState
+		Queue    EntityState
+		// MissingBindings, ExtraBindings - the routing keys from the exchange to the queue
+		MissingBindings []string
+		ExtraBindings   []string
+	}
+
+	// DeadLetter - a message the consumer failed on
+	DeadLetter struct {
+		MessageID  string
+		RoutingKey string
+		Error      string
+		FailedAt   time.Time
+		// PublishedAt - of the original event
+		PublishedAt time.Time
+		// Redelivered - it was peeked or scanned before
+		Redelivered bool
+		ContentType string
+		Body        []byte
+	}
+	// DeadLetters - the head of the dead-letter queue
+	DeadLetters struct {
+		// Total - the messages in the queue
+		Total    int
+		Messages []DeadLetter
+	}
+)
+
+func (tr *TopologyReport) Drift() bool {
+	return !tr.Exchange.Found || !tr.Queue.Found ||
+		len(tr.Exchange.Mismatches) > 0 || len(tr.Queue.Mismatches) > 0 ||
+		len(tr.MissingBindings) > 0 || len(tr.ExtraBindings) > 0
+}
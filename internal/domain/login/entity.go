@@ -0,0 +1,37 @@
+package login
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Flag - an anomaly rule a login broke
+type Flag string
+
+const (
+	// FlagImpossibleTravel - another country than the previous login within Rules.TravelWindow
+	FlagImpossibleTravel Flag = "impossible_travel"
+	// FlagNewDevice - a user agent none of the known logins had
+	FlagNewDevice Flag = "new_device"
+)
+
+type (
+	// Client - where a login attempt comes from
+	Client struct {
+		IP        string
+		UserAgent string
+		// Country - the ISO 3166 code set by the edge(ANOMALY_GEO_HEADER), empty if unknown
+		Country string
+	}
+	// Login - a successful login, the history the anomaly rules compare with
+	Login struct {
+		UserUUID uuid.UUID
+		Client
+		// Flags - the rules the login broke, empty for a regular one
+		Flags []Flag
+
+		CreatedAt time.Time
+	}
+	Logins []*Login
+)
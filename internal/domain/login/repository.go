@@ -0,0 +1,15 @@
+package login
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+type Repository interface {
+	// FetchLogins - the known logins of the user, newest first
+	FetchLogins(ctx context.Context, userUUID uuid.UUID) (Logins, error)
+	// CreateLogin keeps the newest logins of the user only, the older ones are
+	// removed. Does nothing if the user is gone.
+	CreateLogin(ctx context.Context, l Login) error
+}
@@ -0,0 +1,45 @@
+package login
+
+import "time"
+
+// Rules - the anomaly checks of a login against the known logins of the user
+type Rules struct {
+	// TravelWindow - a country change within it is impossible travel, 0 disables the rule
+	TravelWindow time.Duration
+	NewDevice    bool
+}
+
+// Evaluate - history newest first. The first login of a user is never flagged:
+// there is nothing to compare with.
+func (r Rules) Evaluate(l Login, history Logins) []Flag {
+	if len(history) == 0 {
+		return nil
+	}
+
+	var flags []Flag
+	if r.TravelWindow > 0 && l.Country != "" {
+		for _, prev := range history {
+			if prev.Country == "" {
+				continue
+			}
+			if prev.Country != l.Country && l.CreatedAt.Sub(prev.CreatedAt) < r.TravelWindow {
+				flags = append(flags, FlagImpossibleTravel)
+			}
+			break
+		}
+	}
+	if r.NewDevice && l.UserAgent != "" {
+		known := false
+		for _, prev := range history {
+			if prev.UserAgent == l.UserAgent {
+				known = true
+				break
+			}
+		}
+		if !known {
+			flags = append(flags, FlagNewDevice)
+		}
+	}
+
+	return flags
+}
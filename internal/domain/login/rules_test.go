@@ -0,0 +1,57 @@
+package login
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRules_Evaluate(t *testing.T) {
+	now := time.Now()
+	at := func(ago time.Duration, country, ua string) *Login {
+		return &Login{Client: Client{Country: country, UserAgent: ua}, CreatedAt: now.Add(-ago)}
+	}
+	rules := Rules{TravelWindow: 2 * time.Hour, NewDevice: true}
+
+	tests := []struct {
+		name    string
+		rules   Rules
+		login   *Login
+		history Logins
+		want    []Flag
+	}{
+		{"first login", rules, at(0, "DE", "ua-1"), nil, nil},
+		{"same country and device", rules, at(0, "DE", "ua-1"), Logins{at(time.Hour, "DE", "ua-1")}, nil},
+		{
+			"impossible travel", rules, at(0, "US", "ua-1"),
+			Logins{at(time.Hour, "DE", "ua-1")},
+			[]Flag{FlagImpossibleTravel},
+		},
+		{"travel out of the window", rules, at(0, "US", "ua-1"), Logins{at(3*time.Hour, "DE", "ua-1")}, nil},
+		{
+			"unknown countries are skipped", rules, at(0, "US", "ua-1"),
+			Logins{at(time.Minute, "", "ua-1"), at(time.Hour, "DE", "ua-1")},
+			[]Flag{FlagImpossibleTravel},
+		},
+		{"unknown country of the login", rules, at(0, "", "ua-1"), Logins{at(time.Hour, "DE", "ua-1")}, nil},
+		{
+			"new device", rules, at(0, "DE", "ua-2"),
+			Logins{at(time.Hour, "DE", "ua-1")},
+			[]Flag{FlagNewDevice},
+		},
+		{"known older device", rules, at(0, "DE", "ua-1"), Logins{at(time.Hour, "DE", "ua-2"), at(2*time.Hour, "DE", "ua-1")}, nil},
+		{
+			"both", rules, at(0, "US", "ua-2"),
+			Logins{at(time.Hour, "DE", "ua-1")},
+			[]Flag{FlagImpossibleTravel, FlagNewDevice},
+		},
+		{"rules disabled", Rules{}, at(0, "US", "ua-2"), Logins{at(time.Hour, "DE", "ua-1")}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.rules.Evaluate(*tt.login, tt.history))
+		})
+	}
+}
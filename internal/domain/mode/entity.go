@@ -0,0 +1,26 @@
+package mode
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// ForcedConfig - SERVICE_READ_ONLY
+	ForcedConfig = "config"
+	// ForcedSchema - a schema version mismatch with POSTGRES_SCHEMA_CHECK=read-only
+	ForcedSchema = "schema"
+)
+
+// ReadOnly - the mutating requests are rejected while Enabled by an admin or
+// Forced by the instance itself
+type ReadOnly struct {
+	Enabled bool
+	Reason  string
+	// Forced - ForcedConfig, ForcedSchema or empty, not stored: of an instance
+	Forced string
+	// UpdatedBy - nil until an admin toggled it
+	UpdatedBy *uuid.UUID
+	UpdatedAt time.Time
+}
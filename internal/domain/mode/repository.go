@@ -0,0 +1,12 @@
+package mode
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+type Repository interface {
+	FetchReadOnly(ctx context.Context) (*ReadOnly, error)
+	SaveReadOnly(ctx context.Context, enabled bool, reason string, by uuid.UUID) (*ReadOnly, error)
+}
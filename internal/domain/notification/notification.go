@@ -0,0 +1,72 @@
+package notification
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrRecipientRejected - the provider refused the address for good(unknown
+// mailbox, hard bounce, complaint), retrying or sending again is pointless
+var ErrRecipientRejected = errors.New("the recipient was rejected")
+
+// SuppressionRejected - the reason of the addresses suppressed after a rejection
+const SuppressionRejected = "rejected"
+
+const (
+	ChannelEmail   Channel = "email"
+	ChannelSMS     Channel = "sms"
+	ChannelWebhook Channel = "webhook"
+
+	// ModeInstant - every notification is sent at once
+	ModeInstant Mode = "instant"
+	// ModeDigest - the non-urgent notifications are batched into the daily digest
+	ModeDigest Mode = "digest"
+)
+
+// Channels - every channel a user may opt in to
+var Channels = []Channel{ChannelEmail, ChannelSMS, ChannelWebhook}
+
+type (
+	Channel string
+	Mode    string
+	// Preference - the opt-in of a user to a channel
+	Preference struct {
+		Channel Channel
+		Enabled bool
+		Mode    Mode
+		// WebhookURL - the https endpoint of the webhook channel
+		WebhookURL string
+	}
+	Preferences []Preference
+	// DigestItem - a non-urgent notification waiting for the daily digest
+	DigestItem struct {
+		ID        int64
+		Channel   Channel
+		Subject   string
+		CreatedAt time.Time
+	}
+)
+
+// DefaultPreference - of a channel the user never set: email only, at once
+func DefaultPreference(c Channel) Preference {
+	return Preference{Channel: c, Enabled: c == ChannelEmail, Mode: ModeInstant}
+}
+
+func IsChannel(c Channel) bool {
+	for _, ch := range Channels {
+		if c == ch {
+			return true
+		}
+	}
+	return false
+}
+
+// Channel - the preference of c, the default one if never set
+func (p Preferences) Channel(c Channel) Preference {
+	for _, pref := range p {
+		if pref.Channel == c {
+			return pref
+		}
+	}
+	return DefaultPreference(c)
+}
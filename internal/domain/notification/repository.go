@@ -0,0 +1,25 @@
+package notification
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+type Repository interface {
+	IsSuppressed(ctx context.Context, email string) (bool, error)
+	// Suppress - the reason of an already suppressed address is kept
+	Suppress(ctx context.Context, email, reason string) error
+
+	// FetchPreferences - the channels the user ever set
+	FetchPreferences(ctx context.Context, userUUID uuid.UUID) (Preferences, error)
+	// SavePreferences - the other channels are kept, false if the user is not found
+	SavePreferences(ctx context.Context, userUUID uuid.UUID, p Preferences) (bool, error)
+
+	AddDigestItem(ctx context.Context, userUUID uuid.UUID, channel Channel, subject string) error
+	// FetchDigestUsers - up to limit users with digest items, ordered by uuid after the given one
+	FetchDigestUsers(ctx context.Context, after uuid.UUID, limit int) ([]uuid.UUID, error)
+	// FetchDigestItems - the items of the user, in the order they were added
+	FetchDigestItems(ctx context.Context, userUUID uuid.UUID) ([]DigestItem, error)
+	DeleteDigestItems(ctx context.Context, ids []int64) error
+}
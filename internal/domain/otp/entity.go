@@ -0,0 +1,16 @@
+package otp
+
+import (
+	"time"
+
+	"user-manager-api/internal/domain/user"
+)
+
+// OTP - one-time login code, at most one active per user
+type OTP struct {
+	UserID    user.ID
+	CodeHash  []byte
+	Attempts  int
+	ExpiresAt time.Time
+	CreatedAt time.Time
+}
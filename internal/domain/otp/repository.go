@@ -0,0 +1,21 @@
+package otp
+
+import (
+	"context"
+	"time"
+
+	"user-manager-api/internal/domain/user"
+)
+
+type Repository interface {
+	// UpsertOTP replaces the active code of the user
+	UpsertOTP(ctx context.Context, o OTP) error
+	// ClaimAttempt counts an attempt and returns the code, nil when there is
+	// no active code or its attempts are used up
+	ClaimAttempt(ctx context.Context, userID user.ID, maxAttempts int) (*OTP, error)
+	// DeleteOTP deletes the code, false when it is gone(used or replaced)
+	DeleteOTP(ctx context.Context, userID user.ID, codeHash []byte) (bool, error)
+	// ClaimCooldown starts the cooldown of the phone, shared by the instances,
+	// and returns zero; the remaining time when a cooldown runs
+	ClaimCooldown(ctx context.Context, phone string, cooldown time.Duration) (time.Duration, error)
+}
@@ -0,0 +1,16 @@
+package outbox
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Message - an event the broker did not take, Body is the event as published
+type Message struct {
+	ID        int64
+	EventID   uuid.UUID
+	EventType string
+	Body      []byte
+	CreatedAt time.Time
+}
@@ -0,0 +1,15 @@
+package outbox
+
+import "context"
+
+// Repository - the events kept while the broker is down, oldest first
+type Repository interface {
+	// Add keeps m, a message of the same event is kept once
+	Add(ctx context.Context, m Message) error
+	// FetchOldest - up to limit messages, oldest first
+	FetchOldest(ctx context.Context, limit int) ([]Message, error)
+	// Delete removes the relayed messages
+	Delete(ctx context.Context, ids []int64) error
+	// Count - the messages kept
+	Count(ctx context.Context) (int64, error)
+}
@@ -0,0 +1,35 @@
+package pagination
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type (
+	// Cursor - keyset position: the last item of the previous page
+	Cursor struct {
+		CreatedAt time.Time
+		UUID      uuid.UUID
+	}
+	Params struct {
+		Page int
+		// PerPage - 0 means the repository default
+		PerPage int
+		// Cursor - keyset pagination instead of Page, nil for offset pagination
+		Cursor *Cursor
+		// Sort - whitelisted field name, "" is the default order(created_at)
+		Sort string
+		Desc bool
+	}
+)
+
+// MaxPerPage - the biggest page of the listings, the default page size too
+const MaxPerPage = 100
+
+func (p Params) Offset(perPage int) int {
+	if p.Cursor != nil || p.Page < 1 {
+		return 0
+	}
+	return (p.Page - 1) * perPage
+}
@@ -0,0 +1,73 @@
+package projection
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type (
+	// Event - a stored event, without the user payload
+	Event struct {
+		Seq        int64
+		ID         uuid.UUID
+		Type       string
+		UserUUID   uuid.UUID
+		OccurredAt time.Time
+		Meta       map[string]string
+	}
+	Events []Event
+
+	// Delta - what one event changes in the read models of its user, the
+	// counters are added and the times are kept if set
+	Delta struct {
+		SignedUpAt    *time.Time
+		UpdatedAt     *time.Time
+		DeletedAt     *time.Time
+		DeletedReason string
+		MergedInto    *uuid.UUID
+		EmailChanges  int
+		// FilesDelta - the uploaded minus the deleted files, after the reset if
+		// FilesReset(all the files of the user are gone)
+		FilesDelta   int64
+		FilesReset   bool
+		FileChanges  int
+		Logins       int
+		FailedLogins int
+	}
+
+	// UserSummary - the read model of a user, with its files and the activity
+	// of the latest days
+	UserSummary struct {
+		UserUUID      uuid.UUID
+		SignedUpAt    *time.Time
+		UpdatedAt     *time.Time
+		DeletedAt     *time.Time
+		DeletedReason string
+		MergedInto    *uuid.UUID
+		EmailChanges  int
+		Logins        int
+		FailedLogins  int
+		LastLoginAt   *time.Time
+		LastEventAt   time.Time
+		Files         Files
+		Activity      []DailyActivity
+	}
+	Files struct {
+		Count         int64
+		Changes       int
+		LastChangedAt *time.Time
+	}
+	// DailyActivity - the events of a user on a UTC day
+	DailyActivity struct {
+		Day          time.Time
+		Events       int
+		Logins       int
+		FailedLogins int
+	}
+)
+
+// Touches - false for an event of no user, it changes no read model
+func (e Event) Touches() bool {
+	return e.UserUUID != uuid.Nil
+}
@@ -0,0 +1,21 @@
+package projection
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Repository - the event store and its read models. An event changes the read
+// models only when it is stored, a redelivered one changes nothing.
+type Repository interface {
+	// Append stores e and applies d of it, false if e is stored already
+	Append(ctx context.Context, e Event, d Delta) (bool, error)
+	// Rebuild replaces the read models by the fold of all the stored events in
+	// their order, atomically: the appends wait for it. Returns the events folded.
+	Rebuild(ctx context.Context, fold func(Event) Delta) (int64, error)
+	// FetchUserSummary - nil if no event of the user is stored, the activity of
+	// the days since from
+	FetchUserSummary(ctx context.Context, userUUID uuid.UUID, from time.Time) (*UserSummary, error)
+}
@@ -0,0 +1,30 @@
+package stats
+
+import "time"
+
+type (
+	// DailySignups - users registered on a UTC day, deleted users included
+	DailySignups struct {
+		Day   time.Time
+		Count uint64
+	}
+	// FilesTotals - not deleted files of all users
+	FilesTotals struct {
+		UsersWithFiles uint64
+		FilesCount     uint64
+		TotalBytes     uint64
+	}
+	// QueryStats - the latencies of a statement since the start of the instance
+	QueryStats struct {
+		// Fingerprint - the "query" label of the histogram
+		Fingerprint string
+		// SQL - the statement with the placeholders, the arguments are never kept
+		SQL   string
+		Calls int64
+		// Slow - the calls over the threshold, LastSlowAt - nil if none
+		Slow       int64
+		Total      time.Duration
+		Max        time.Duration
+		LastSlowAt *time.Time
+	}
+)
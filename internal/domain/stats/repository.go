@@ -0,0 +1,20 @@
+package stats
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Repository - the aggregates are recomputed from the source tables per key,
+// so a refresh may be repeated or reordered safely.
+type Repository interface {
+	RefreshUserFileCounts(ctx context.Context, userUUID uuid.UUID) error
+	RefreshDailySignups(ctx context.Context, day time.Time) error
+	// Rebuild recomputes all the aggregates
+	Rebuild(ctx context.Context) error
+	// FetchDailySignups - every day of [from, to], days without signups included
+	FetchDailySignups(ctx context.Context, from, to time.Time) ([]DailySignups, error)
+	FetchFilesTotals(ctx context.Context) (*FilesTotals, error)
+}
@@ -0,0 +1,52 @@
+package token
+
+import (
+	"context"
+	"time"
+)
+
+// Claims - what a verified access token says, whatever its format is
+type Claims struct {
+	UserID string
+	Role   string
+	// ActAs - UUID of the admin impersonating UserID, empty for regular tokens
+	ActAs     string
+	ExpiresAt time.Time
+	// IssuedAt - nil for tokens issued without it, they are never revoked
+	IssuedAt *time.Time
+	// DeviceID - UUID of the device the token was issued to at login, empty if unbound
+	DeviceID string
+}
+
+// Lifetime - of the token as issued, 0 for tokens issued without "iat"
+func (c *Claims) Lifetime() time.Duration {
+	if c.IssuedAt == nil {
+		return 0
+	}
+	return c.ExpiresAt.Sub(*c.IssuedAt)
+}
+
+// RefreshDue - the token expires within window, the sliding session gets a new
+// one. Never the impersonation tokens: their TTL bounds the impersonation
+func (c *Claims) RefreshDue(window time.Duration, now time.Time) bool {
+	return window > 0 && c.ActAs == "" && c.Lifetime() > 0 && c.ExpiresAt.Sub(now) <= window
+}
+
+// RevocationCheck reports whether the token was revoked
+type RevocationCheck func(ctx context.Context, claims *Claims) (bool, error)
+
+// RoleLookup - the current role of the user of the token: the role claim is
+// stale after a role change until the token expires
+type RoleLookup func(ctx context.Context, claims *Claims) (string, error)
+
+// AnyOf - the token is revoked if any of checks says so, they run in order
+func AnyOf(checks ...RevocationCheck) RevocationCheck {
+	return func(ctx context.Context, claims *Claims) (bool, error) {
+		for _, check := range checks {
+			if revoked, err := check(ctx, claims); err != nil || revoked {
+				return revoked, err
+			}
+		}
+		return false, nil
+	}
+}
@@ -0,0 +1,6 @@
+package token
+
+import "errors"
+
+// ErrExpired - a valid token out of its lifetime, the caller has to refresh it
+var ErrExpired = errors.New("token expired")
@@ -0,0 +1,50 @@
+package usage
+
+import "time"
+
+const (
+	// Anonymous - the organization and the role of the requests without a token
+	Anonymous = "anonymous"
+	// Unknown - the organization of a user who was not found
+	Unknown = "unknown"
+)
+
+type (
+	// Rollup - the requests of an organization and a role on a UTC day
+	Rollup struct {
+		Day      time.Time
+		Org      string
+		Role     string
+		Requests uint64
+		// DurationMs - the total of the request durations
+		DurationMs uint64
+	}
+	// Monthly - the billing record of an organization for a UTC month
+	Monthly struct {
+		// Month - the first day of the month
+		Month time.Time
+		Org   string
+		// ActiveUsers - the users who were not deleted for at least a part of the month
+		ActiveUsers uint64
+		// StorageBytes - of the files not deleted at the end of the month, of now
+		// for the current month
+		StorageBytes uint64
+		APICalls     uint64
+		GeneratedAt  time.Time
+	}
+	// SeatLimit - the most active(not deleted) users of an organization, an
+	// organization without a limit is unlimited
+	SeatLimit struct {
+		Org         string
+		Seats       uint32
+		ActiveUsers uint64
+		UpdatedAt   time.Time
+	}
+	// Total - the requests of an organization and a role over a range of days
+	Total struct {
+		Org        string
+		Role       string
+		Requests   uint64
+		DurationMs uint64
+	}
+)
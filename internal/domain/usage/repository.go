@@ -0,0 +1,32 @@
+package usage
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Repository interface {
+	// AddRollups adds the counts to the stored ones: every instance writes
+	// the requests it served
+	AddRollups(ctx context.Context, rollups []Rollup) error
+	// FetchOrganizations - the organizations of the users by uuid, deleted
+	// users included, unknown uuids are missing
+	FetchOrganizations(ctx context.Context, userUUIDs []uuid.UUID) (map[uuid.UUID]string, error)
+	// FetchTotals - the totals of [from, to] UTC days per organization and
+	// role, of one organization if org is not empty
+	FetchTotals(ctx context.Context, from, to time.Time, org string) ([]Total, error)
+	// RefreshMonthly recomputes the billing records of the month from the
+	// users, the files and the rollups, returns the organizations count
+	RefreshMonthly(ctx context.Context, month time.Time) (int64, error)
+	// FetchMonthly - the billing records of [from, to] months, by month and organization
+	FetchMonthly(ctx context.Context, from, to time.Time) ([]Monthly, error)
+	// FetchSeatLimit - nil if the organization has no limit
+	FetchSeatLimit(ctx context.Context, org string) (*SeatLimit, error)
+	// FetchSeatLimits - of all the limited organizations, by organization
+	FetchSeatLimits(ctx context.Context) ([]SeatLimit, error)
+	SaveSeatLimit(ctx context.Context, org string, seats uint32) (*SeatLimit, error)
+	// DeleteSeatLimit - false if the organization had no limit
+	DeleteSeatLimit(ctx context.Context, org string) (bool, error)
+}
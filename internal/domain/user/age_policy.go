@@ -0,0 +1,50 @@
+package user
+
+import (
+	"fmt"
+	"time"
+)
+
+// UnderageError - the user is younger than the minimum age of its organization
+type UnderageError struct {
+	MinAge int
+}
+
+func (e *UnderageError) Error() string {
+	return fmt.Sprintf("user must be %d+ years old", e.MinAge)
+}
+
+// AgePolicy - the minimum age of the users: the one of its organization, else
+// the default. The age is taken on today's date in the timezone of the user.
+type AgePolicy struct {
+	def       int
+	orgs      map[string]int
+	timezones Timezones
+}
+
+// NewAgePolicy - orgs by the organization(the email domain), 0 - no minimum
+func NewAgePolicy(def int, orgs map[string]int, timezones Timezones) AgePolicy {
+	return AgePolicy{def: def, orgs: orgs, timezones: timezones}
+}
+
+// MinAge - of the organization of u
+func (p AgePolicy) MinAge(u User) int {
+	if minAge, ok := p.orgs[Organization(u.Email)]; ok {
+		return minAge
+	}
+
+	return p.def
+}
+
+// Check - *UnderageError when u is too young at now, nil without a birth date
+func (p AgePolicy) Check(u User, now time.Time) error {
+	minAge := p.MinAge(u)
+	if minAge == 0 || u.BirthDate.IsZero() {
+		return nil
+	}
+	if age, _ := AgeAt(u.BirthDate, now.In(p.timezones.Location(u))); age < minAge {
+		return &UnderageError{MinAge: minAge}
+	}
+
+	return nil
+}
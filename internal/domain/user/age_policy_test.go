@@ -0,0 +1,43 @@
+package user
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAgePolicy_Check(t *testing.T) {
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	require.NoError(t, err)
+	tz := NewTimezones(nil, map[string]*time.Location{"corp.example": tokyo})
+	p := NewAgePolicy(18, map[string]int{"corp.example": 16, "open.example": 0}, tz)
+
+	// 2026-06-15 in Tokyo, still 2026-06-14 in UTC
+	now := time.Date(2026, 6, 14, 16, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		u    User
+		want error
+	}{
+		{"default: 18 today", User{Email: "a@other.example", BirthDate: time.Date(2008, 6, 14, 0, 0, 0, 0, time.UTC)}, nil},
+		{"default: 18 tomorrow", User{Email: "a@other.example", BirthDate: time.Date(2008, 6, 15, 0, 0, 0, 0, time.UTC)}, &UnderageError{MinAge: 18}},
+		{"organization: 16 in its timezone", User{Email: "a@Corp.Example", BirthDate: time.Date(2010, 6, 15, 0, 0, 0, 0, time.UTC)}, nil},
+		{"organization: under 16", User{Email: "a@corp.example", BirthDate: time.Date(2010, 6, 16, 0, 0, 0, 0, time.UTC)}, &UnderageError{MinAge: 16}},
+		{"own timezone", User{Email: "a@corp.example", Timezone: "UTC", BirthDate: time.Date(2010, 6, 15, 0, 0, 0, 0, time.UTC)}, &UnderageError{MinAge: 16}},
+		{"no minimum", User{Email: "a@open.example", BirthDate: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)}, nil},
+		{"no birth date", User{Email: "a@other.example"}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, p.Check(tt.u, now))
+		})
+	}
+}
+
+func TestUnderageError(t *testing.T) {
+	assert.EqualError(t, &UnderageError{MinAge: 18}, "user must be 18+ years old")
+}
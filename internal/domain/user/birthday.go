@@ -0,0 +1,76 @@
+package user
+
+import (
+	"sync"
+	"time"
+)
+
+// Timezones - where "today" is for the users: the own timezone of a user, else
+// the one of its organization, else the default
+type Timezones struct {
+	def  *time.Location
+	orgs map[string]*time.Location
+	// cache - the loaded own timezones of the users by name
+	cache *sync.Map
+}
+
+// NewTimezones - orgs by the organization(the email domain), def nil - UTC
+func NewTimezones(def *time.Location, orgs map[string]*time.Location) Timezones {
+	if def == nil {
+		def = time.UTC
+	}
+
+	return Timezones{def: def, orgs: orgs, cache: new(sync.Map)}
+}
+
+// Location - an unknown own timezone(e.g. dropped from the tz database) falls
+// back to the one of the organization
+func (tz Timezones) Location(u User) *time.Location {
+	if u.Timezone != "" && tz.cache != nil {
+		if loc, ok := tz.cache.Load(u.Timezone); ok {
+			return loc.(*time.Location)
+		}
+		if loc, err := time.LoadLocation(u.Timezone); err == nil {
+			tz.cache.Store(u.Timezone, loc)
+			return loc
+		}
+	}
+	if loc, ok := tz.orgs[Organization(u.Email)]; ok {
+		return loc
+	}
+	if tz.def == nil {
+		return time.UTC
+	}
+
+	return tz.def
+}
+
+// BirthdayAt - the derived fields of u at now in its timezone, nil without a
+// birth date(e.g. redacted)
+func (tz Timezones) BirthdayAt(u User, now time.Time) *Birthday {
+	if u.BirthDate.IsZero() {
+		return nil
+	}
+	loc := tz.Location(u)
+	age, today := AgeAt(u.BirthDate, now.In(loc))
+
+	return &Birthday{Age: age, IsToday: today, Timezone: loc.String()}
+}
+
+// AgeAt - the full years of the birth date on the calendar date of now(in its
+// location) and whether it is the birthday. Born on February 29: a year older
+// on March 1 of the common years.
+func AgeAt(birthDate, now time.Time) (age int, birthday bool) {
+	by, bm, bd := birthDate.Date()
+	y, m, d := now.Date()
+	today := time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+	// normalized by time.Date: February 29 of a common year is March 1
+	anniversary := time.Date(y, bm, bd, 0, 0, 0, 0, time.UTC)
+
+	age = y - by
+	if today.Before(anniversary) {
+		age--
+	}
+
+	return age, today.Equal(anniversary)
+}
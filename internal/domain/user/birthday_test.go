@@ -0,0 +1,79 @@
+package user
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAgeAt(t *testing.T) {
+	date := func(s string) time.Time {
+		d, err := time.Parse("2006-01-02", s)
+		require.NoError(t, err)
+		return d
+	}
+
+	tests := []struct {
+		name     string
+		birth    string
+		today    string
+		age      int
+		birthday bool
+	}{
+		{"day before", "1990-06-15", "2026-06-14", 35, false},
+		{"birthday", "1990-06-15", "2026-06-15", 36, true},
+		{"day after", "1990-06-15", "2026-06-16", 36, false},
+		{"leap day in a leap year", "2000-02-29", "2024-02-29", 24, true},
+		{"leap day: Feb 28 of a common year", "2000-02-29", "2026-02-28", 25, false},
+		{"leap day: Mar 1 of a common year", "2000-02-29", "2026-03-01", 26, true},
+		{"born today", "2026-06-15", "2026-06-15", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			age, birthday := AgeAt(date(tt.birth), date(tt.today))
+			assert.Equal(t, tt.age, age)
+			assert.Equal(t, tt.birthday, birthday)
+		})
+	}
+}
+
+func TestTimezones_BirthdayAt(t *testing.T) {
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	require.NoError(t, err)
+	ny, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+	tz := NewTimezones(ny, map[string]*time.Location{"corp.example": tokyo})
+
+	// 2026-06-15 in Tokyo, still 2026-06-14 in New York
+	now := time.Date(2026, 6, 15, 1, 0, 0, 0, time.UTC)
+	birth := time.Date(1990, 6, 15, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		u    User
+		want *Birthday
+	}{
+		{"default", User{Email: "a@other.example", BirthDate: birth}, &Birthday{Age: 35, Timezone: "America/New_York"}},
+		{"organization", User{Email: "a@Corp.Example", BirthDate: birth}, &Birthday{Age: 36, IsToday: true, Timezone: "Asia/Tokyo"}},
+		{
+			"own timezone over the organization",
+			User{Email: "a@corp.example", Timezone: "UTC", BirthDate: birth},
+			&Birthday{Age: 36, IsToday: true, Timezone: "UTC"},
+		},
+		{
+			"unknown own timezone",
+			User{Email: "a@corp.example", Timezone: "Mars/Olympus", BirthDate: birth},
+			&Birthday{Age: 36, IsToday: true, Timezone: "Asia/Tokyo"},
+		},
+		{"no birth date", User{Email: "a@corp.example"}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tz.BirthdayAt(tt.u, now))
+		})
+	}
+}
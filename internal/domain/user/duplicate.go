@@ -0,0 +1,108 @@
+package user
+
+import (
+	"slices"
+	"strings"
+	"time"
+)
+
+// DuplicateReason - what the users of a duplicate group share
+type DuplicateReason string
+
+const (
+	DuplicatePhone DuplicateReason = "phone"
+	// DuplicateEmailAlias - the same mailbox: the "+tag" and the dots of Gmail aside
+	DuplicateEmailAlias DuplicateReason = "email_alias"
+	// DuplicateNameBirthDate - the same name and lastname(case-insensitive) and birth date
+	DuplicateNameBirthDate DuplicateReason = "name_birth_date"
+)
+
+type (
+	// DuplicateGroup - the users sharing Key, in the order they were added
+	DuplicateGroup struct {
+		Reason DuplicateReason
+		Key    string
+		Users  []UUID
+	}
+
+	// MergeResult - what was moved from the merged user to the kept one
+	MergeResult struct {
+		Files int
+		Notes int
+		Audit int
+	}
+)
+
+// DuplicateFinder groups the users by every DuplicateReason: Add all the users,
+// then take the Groups. The blank(redacted) fields are skipped.
+type DuplicateFinder struct {
+	keys map[DuplicateReason]map[string][]UUID
+}
+
+func NewDuplicateFinder() *DuplicateFinder {
+	return &DuplicateFinder{keys: map[DuplicateReason]map[string][]UUID{
+		DuplicatePhone:         {},
+		DuplicateEmailAlias:    {},
+		DuplicateNameBirthDate: {},
+	}}
+}
+
+func (f *DuplicateFinder) Add(u *User) {
+	if u.Phone != "" {
+		f.add(DuplicatePhone, u.Phone, u.UUID)
+	}
+	if alias := EmailAlias(u.Email); alias != "" {
+		f.add(DuplicateEmailAlias, alias, u.UUID)
+	}
+	if u.Name != "" && u.Lastname != "" && !u.BirthDate.IsZero() {
+		key := strings.ToLower(NormalizeName(u.Name)+" "+NormalizeName(u.Lastname)) + " " +
+			u.BirthDate.Format(time.DateOnly)
+		f.add(DuplicateNameBirthDate, key, u.UUID)
+	}
+}
+
+func (f *DuplicateFinder) add(reason DuplicateReason, key string, id UUID) {
+	f.keys[reason][key] = append(f.keys[reason][key], id)
+}
+
+// Groups - the groups of 2+ users ordered by the reason and the key
+func (f *DuplicateFinder) Groups() []DuplicateGroup {
+	var groups []DuplicateGroup
+	for reason, keys := range f.keys {
+		for key, ids := range keys {
+			if len(ids) > 1 {
+				groups = append(groups, DuplicateGroup{Reason: reason, Key: key, Users: ids})
+			}
+		}
+	}
+	slices.SortFunc(groups, func(a, b DuplicateGroup) int {
+		if c := strings.Compare(string(a.Reason), string(b.Reason)); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Key, b.Key)
+	})
+
+	return groups
+}
+
+// EmailAlias - the mailbox of the email: lowercased, without the "+tag" of the
+// local part, for Gmail without its dots too(googlemail.com is gmail.com).
+// Empty for an invalid email.
+func EmailAlias(email string) string {
+	local, domain, ok := strings.Cut(strings.ToLower(strings.TrimSpace(email)), "@")
+	if !ok || local == "" || domain == "" {
+		return ""
+	}
+	local, _, _ = strings.Cut(local, "+")
+	if domain == "googlemail.com" {
+		domain = "gmail.com"
+	}
+	if domain == "gmail.com" {
+		local = strings.ReplaceAll(local, ".", "")
+	}
+	if local == "" {
+		return ""
+	}
+
+	return local + "@" + domain
+}
@@ -0,0 +1,45 @@
+package user
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEmailAlias(t *testing.T) {
+	for in, want := range map[string]string{
+		"John@Example.com":        "john@example.com",
+		"john+news@example.com":   "john@example.com",
+		"j.o.hn+x@gmail.com":      "john@gmail.com",
+		"John.Doe@GoogleMail.com": "johndoe@gmail.com",
+		"john.doe@example.com":    "john.doe@example.com",
+		"+tag@example.com":        "",
+		"not-an-email":            "",
+		"john@":                   "",
+	} {
+		assert.Equal(t, want, EmailAlias(in), in)
+	}
+}
+
+func TestDuplicateFinder(t *testing.T) {
+	birth := time.Date(1990, 1, 2, 0, 0, 0, 0, time.UTC)
+	a, b, c, d := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+
+	f := NewDuplicateFinder()
+	f.Add(&User{UUID: a, Email: "john.doe@gmail.com", Name: "John", Lastname: "Doe", BirthDate: birth, Phone: "+33600000001"})
+	f.Add(&User{UUID: b, Email: "johndoe+work@gmail.com", Name: "Jane", Lastname: "Doe", BirthDate: birth, Phone: "+33600000002"})
+	// decomposed ë, another case
+	f.Add(&User{UUID: c, Email: "zoe@example.com", Name: "ZOË", Lastname: "doe", BirthDate: birth, Phone: "+33600000001"})
+	f.Add(&User{UUID: d, Email: "zoe@other.example", Name: "Zoë", Lastname: "Doe", BirthDate: birth})
+	// redacted: blank fields are not duplicates
+	f.Add(&User{UUID: uuid.New(), Email: "x@example.com"})
+	f.Add(&User{UUID: uuid.New(), Email: "y@example.com"})
+
+	assert.Equal(t, []DuplicateGroup{
+		{Reason: DuplicateEmailAlias, Key: "johndoe@gmail.com", Users: []UUID{a, b}},
+		{Reason: DuplicateNameBirthDate, Key: "zoë doe 1990-01-02", Users: []UUID{c, d}},
+		{Reason: DuplicatePhone, Key: "+33600000001", Users: []UUID{a, c}},
+	}, f.Groups())
+}
@@ -1,11 +1,70 @@
 package user
 
 import (
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 )
 
+const (
+	RoleAdmin  = "admin"
+	RoleWorker = "worker"
+)
+
+// PIIColumn - a column the retention policy can blank
+type PIIColumn string
+
+const (
+	ColumnName      PIIColumn = "name"
+	ColumnLastname  PIIColumn = "lastname"
+	ColumnBirthDate PIIColumn = "birth_date"
+	ColumnPhone     PIIColumn = "phone"
+)
+
+// DeletionReason - why an account was soft deleted, empty for the accounts
+// deleted before the reasons were recorded
+type DeletionReason string
+
+const (
+	DeletionUserRequest DeletionReason = "user_request"
+	DeletionAdminAction DeletionReason = "admin_action"
+	DeletionGDPR        DeletionReason = "gdpr"
+	DeletionFraud       DeletionReason = "fraud"
+	// DeletionMerged - the duplicate account merged into another one
+	DeletionMerged DeletionReason = "merged"
+)
+
+// DeletionReasons - all the known reasons
+var DeletionReasons = []DeletionReason{DeletionUserRequest, DeletionAdminAction, DeletionGDPR, DeletionFraud, DeletionMerged}
+
+func (r DeletionReason) Valid() bool {
+	return slices.Contains(DeletionReasons, r)
+}
+
+// Organization - the organization(tenant) of a user is the domain of the email,
+// lowercased
+func Organization(email string) string {
+	i := strings.LastIndexByte(email, '@')
+	if i < 0 {
+		return ""
+	}
+
+	return strings.ToLower(email[i+1:])
+}
+
+// RoleChangeStatus - the outcome of a role assignment
+type RoleChangeStatus string
+
+const (
+	RoleChanged      RoleChangeStatus = "updated"
+	RoleUnchanged    RoleChangeStatus = "unchanged"
+	RoleUserNotFound RoleChangeStatus = "not_found"
+	// RoleLastAdmin - the demotion would leave no active admin
+	RoleLastAdmin RoleChangeStatus = "last_admin"
+)
+
 type (
 	ID   uint64
 	UUID = uuid.UUID
@@ -15,16 +74,91 @@ type (
 		PasswordHash *string
 		Role         string
 		Name         string
-		Lastname     string
-		BirthDate    time.Time
-		Phone        string
+		// MiddleName, Suffix(e.g. Jr., III) - optional, empty if none
+		MiddleName string
+		Lastname   string
+		Suffix     string
+		BirthDate  time.Time
+		Phone      string
 
 		CreatedAt time.Time
 		UpdatedAt time.Time
 
 		DeletedAt     *time.Time
-		DeletedReason string
+		DeletedReason DeletionReason
 		DeletedBy     *ID
+
+		// PasswordResetRequired - set by an admin, login is refused until the password is changed
+		PasswordResetRequired bool
+		// Timezone - IANA name of the user's own timezone, empty - the one of the organization
+		Timezone string
+
+		// PendingEmail - not persisted in users, set by the update which
+		// requested the email change awaiting confirmation
+		PendingEmail string
+
+		// Files - not persisted in users, set by the profile reads
+		Files *FilesSummary
+		// Birthday - not persisted in users, derived by the profile reads
+		Birthday *Birthday
 	}
 	Users []*User
+
+	// Birthday - the birth date derived fields as of the date in Timezone
+	Birthday struct {
+		Age     int
+		IsToday bool
+		// Timezone - IANA name the date was taken in
+		Timezone string
+	}
+
+	// FilesSummary - active files of a user
+	FilesSummary struct {
+		Count      uint64
+		TotalBytes uint64
+	}
+
+	// EmailChange - the new address becomes active only after confirmation
+	EmailChange struct {
+		NewEmail  string
+		TokenHash []byte
+		ExpiresAt time.Time
+	}
+
+	RoleChange struct {
+		UUID UUID
+		Role string
+	}
+	// RoleChangeResult - PrevRole is empty if the user is not found
+	RoleChangeResult struct {
+		UUID     UUID
+		Role     string
+		PrevRole string
+		Status   RoleChangeStatus
+	}
+
+	// Identity - the internal ID(the DB, the logs) and the public UUID of a
+	// user, DeletedAt nil if it is active
+	Identity struct {
+		ID        ID
+		UUID      UUID
+		DeletedAt *time.Time
+	}
+
+	// LegalHold - the user and their files are neither deleted, redacted nor
+	// purged while it is placed
+	LegalHold struct {
+		Reason string
+		Since  time.Time
+	}
+
+	// Invitation - the admin sets the email and role, the invitee the rest of
+	// the profile and the password
+	Invitation struct {
+		Email     string
+		Role      string
+		TokenHash []byte
+		ExpiresAt time.Time
+		InvitedBy UUID
+	}
 )
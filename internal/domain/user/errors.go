@@ -0,0 +1,15 @@
+package user
+
+import "errors"
+
+// the errors of the repositories whatever the storage is, the interface layer
+// maps them to the responses
+var (
+	// ErrNotFound - no (active) user matches, never a nil user with a nil error
+	ErrNotFound           = errors.New("user not found")
+	ErrEmailAlreadyExists = errors.New("user email is already exists")
+	ErrLastAdmin          = errors.New("the last admin cannot be deleted")
+	ErrLegalHold          = errors.New("the user is on a legal hold")
+	// ErrSeatLimitReached - the organization of the email has no free seat
+	ErrSeatLimitReached = errors.New("the seat limit of the organization is reached")
+)
@@ -0,0 +1,94 @@
+package user
+
+import (
+	"strconv"
+	"time"
+)
+
+type (
+	// Version - the user as it was in [ValidFrom, ValidTo), ValidTo is nil for
+	// the current one
+	Version struct {
+		User      User
+		ValidFrom time.Time
+		ValidTo   *time.Time
+	}
+
+	// Change - a field(as named by the API) changed by a revision
+	Change struct {
+		Field string
+		From  string
+		To    string
+	}
+
+	// Revision - a version with its changes from the previous one, none for the first
+	Revision struct {
+		Version
+		Changes []Change
+	}
+)
+
+// Revisions - versions oldest first. A version without any change(the same
+// profile saved again) is folded into the previous one.
+func Revisions(versions []Version) []Revision {
+	var revs []Revision
+	for _, v := range versions {
+		if len(revs) == 0 {
+			revs = append(revs, Revision{Version: v})
+			continue
+		}
+		prev := &revs[len(revs)-1]
+		changes := Diff(prev.User, v.User)
+		if len(changes) == 0 {
+			prev.ValidTo = v.ValidTo
+			prev.User = v.User
+			continue
+		}
+		revs = append(revs, Revision{Version: v, Changes: changes})
+	}
+
+	return revs
+}
+
+// Diff - the changed fields from a to b, in a fixed order
+func Diff(a, b User) []Change {
+	var changes []Change
+	for _, f := range []struct {
+		name     string
+		from, to string
+	}{
+		{"email", a.Email, b.Email},
+		{"role", a.Role, b.Role},
+		{"name", a.Name, b.Name},
+		{"middle_name", a.MiddleName, b.MiddleName},
+		{"lastname", a.Lastname, b.Lastname},
+		{"suffix", a.Suffix, b.Suffix},
+		{"birth_date", formatDate(a.BirthDate), formatDate(b.BirthDate)},
+		{"phone", a.Phone, b.Phone},
+		{"timezone", a.Timezone, b.Timezone},
+		{"password_reset_required", strconv.FormatBool(a.PasswordResetRequired), strconv.FormatBool(b.PasswordResetRequired)},
+		{"deleted_at", formatTime(a.DeletedAt), formatTime(b.DeletedAt)},
+		{"deleted_reason", string(a.DeletedReason), string(b.DeletedReason)},
+	} {
+		if f.from != f.to {
+			changes = append(changes, Change{Field: f.name, From: f.from, To: f.to})
+		}
+	}
+
+	return changes
+}
+
+// formatDate - "" for a blank(redacted) date
+func formatDate(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.DateOnly)
+}
+
+func formatTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
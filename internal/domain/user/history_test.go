@@ -0,0 +1,55 @@
+package user
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRevisions(t *testing.T) {
+	t0 := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	t1, t2, t3 := t0.Add(time.Hour), t0.Add(2*time.Hour), t0.Add(3*time.Hour)
+	birth := time.Date(1990, 1, 2, 0, 0, 0, 0, time.UTC)
+	u := User{Email: "john@example.com", Role: RoleWorker, Name: "John", Lastname: "Doe", BirthDate: birth}
+
+	renamed := u
+	renamed.Lastname = "Smith"
+	deleted := renamed
+	deleted.DeletedAt = &t3
+	deleted.DeletedReason = DeletionGDPR
+
+	revs := Revisions([]Version{
+		{User: u, ValidFrom: t0, ValidTo: &t1},
+		// saved again unchanged: folded
+		{User: u, ValidFrom: t1, ValidTo: &t2},
+		{User: renamed, ValidFrom: t2, ValidTo: &t3},
+		{User: deleted, ValidFrom: t3},
+	})
+
+	assert.Equal(t, []Revision{
+		{Version: Version{User: u, ValidFrom: t0, ValidTo: &t2}},
+		{
+			Version: Version{User: renamed, ValidFrom: t2, ValidTo: &t3},
+			Changes: []Change{{Field: "lastname", From: "Doe", To: "Smith"}},
+		},
+		{
+			Version: Version{User: deleted, ValidFrom: t3},
+			Changes: []Change{
+				{Field: "deleted_at", To: "2026-01-01T03:00:00Z"},
+				{Field: "deleted_reason", To: "gdpr"},
+			},
+		},
+	}, revs)
+	assert.Empty(t, Revisions(nil))
+}
+
+func TestDiff_Redacted(t *testing.T) {
+	a := User{Name: "John", BirthDate: time.Date(1990, 1, 2, 0, 0, 0, 0, time.UTC), Phone: "+33600000001"}
+
+	assert.Equal(t, []Change{
+		{Field: "name", From: "John"},
+		{Field: "birth_date", From: "1990-01-02"},
+		{Field: "phone", From: "+33600000001"},
+	}, Diff(a, User{}))
+}
@@ -0,0 +1,25 @@
+package user
+
+import (
+	"strings"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// NormalizeName - trimmed NFC: the same name typed with the precomposed or the
+// combining characters is stored, sorted and compared the same
+func NormalizeName(s string) string {
+	return norm.NFC.String(strings.TrimSpace(s))
+}
+
+// FullName - the non-empty name parts in the western order, e.g. "John Ronald Doe Jr."
+func (u User) FullName() string {
+	parts := make([]string, 0, 4)
+	for _, p := range []string{u.Name, u.MiddleName, u.Lastname, u.Suffix} {
+		if p != "" {
+			parts = append(parts, p)
+		}
+	}
+
+	return strings.Join(parts, " ")
+}
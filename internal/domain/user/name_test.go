@@ -0,0 +1,19 @@
+package user
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeName(t *testing.T) {
+	// e + COMBINING DIAERESIS is composed to ë
+	assert.Equal(t, "Zo\u00eb", NormalizeName(" Zoe\u0308 "))
+	assert.Equal(t, "Zo\u00eb", NormalizeName("Zo\u00eb"))
+}
+
+func TestUser_FullName(t *testing.T) {
+	assert.Equal(t, "John Doe", User{Name: "John", Lastname: "Doe"}.FullName())
+	assert.Equal(t, "John Ronald Doe Jr.", User{Name: "John", MiddleName: "Ronald", Lastname: "Doe", Suffix: "Jr."}.FullName())
+	assert.Equal(t, "Doe", User{Lastname: "Doe"}.FullName())
+}
@@ -2,14 +2,109 @@ package user
 
 import (
 	"context"
+	"time"
+
+	"user-manager-api/internal/domain/pagination"
 )
 
-type Repository interface {
+// QueryRepository - the reads of the user queries(ports.UserQueries), a
+// replica or a cache may implement it. The lookups of a single user return
+// ErrNotFound instead of a nil user
+type QueryRepository interface {
 	FetchUserByID(ctx context.Context, uuid UUID) (*User, error)
 	FetchUserByEmail(ctx context.Context, email string) (*User, error)
-	FetchUsers(ctx context.Context, page int) (Users, error)
+	// StreamUsers calls fn for every user of the page, Files filled, once the
+	// page is read: fn holds no connection. The user is reused for the next
+	// row: fn must not keep it
+	StreamUsers(ctx context.Context, p pagination.Params, fn func(u *User) error) error
+	// FetchDeletedUsers - reason "" - any reason
+	FetchDeletedUsers(ctx context.Context, reason DeletionReason, p pagination.Params) (Users, error)
+}
+
+// Repository - the primary: the reads of QueryRepository and the changes
+type Repository interface {
+	QueryRepository
+	// FetchUserByPhone - ErrNotFound if the phone is unknown or shared by several users
+	FetchUserByPhone(ctx context.Context, phone string) (*User, error)
 	CreateUser(ctx context.Context, req User) (*User, error)
 	UpdateUser(ctx context.Context, req User) (*User, error)
 	FetchInternalID(ctx context.Context, uuid UUID) (ID, error)
-	DeleteUser(ctx context.Context, uuid ID) (*User, error)
+	// FetchIdentityByID, FetchIdentityByUUID - deleted users too, ErrNotFound if unknown
+	FetchIdentityByID(ctx context.Context, id ID) (*Identity, error)
+	FetchIdentityByUUID(ctx context.Context, uuid UUID) (*Identity, error)
+	// CheckDeletable - the error DeleteUser would return now(ErrNotFound,
+	// ErrLegalHold, ErrLastAdmin), nil if it would delete the user
+	CheckDeletable(ctx context.Context, id ID) error
+	// DeleteUser - actor is the internal ID source of deleted_by(uuid.Nil - the system),
+	// the last active admin is never deleted(ErrLastAdmin) nor a user on a legal
+	// hold(ErrLegalHold), ErrNotFound if missing or already deleted
+	DeleteUser(ctx context.Context, id ID, reason DeletionReason, actor UUID) (*User, error)
+	// MergeUsers moves the files, notes and audit records of loser to winner and
+	// soft-deletes loser(DeletionMerged) at once. ErrNotFound if either is missing or
+	// deleted, ErrLastAdmin if loser is the last active admin, ErrLegalHold if it is on a legal hold
+	MergeUsers(ctx context.Context, winner, loser ID, actor UUID) (MergeResult, error)
+	// FetchHistory - all the versions of the user(deleted too) oldest first, the
+	// current one last. ErrNotFound if unknown
+	FetchHistory(ctx context.Context, id ID) ([]Version, error)
+	// FetchVersionAt - the version valid at at, ErrNotFound if the user did not exist yet
+	FetchVersionAt(ctx context.Context, id ID, at time.Time) (*Version, error)
+	// ForcePasswordReset flags the user and revokes issued tokens, false if not found
+	ForcePasswordReset(ctx context.Context, uuid UUID) (bool, error)
+	// RevokeTokens revokes the tokens issued before issuedBefore, false if not found
+	RevokeTokens(ctx context.Context, uuid UUID, issuedBefore time.Time) (bool, error)
+	// SetRoles applies changes at once, results in the order of changes. The tokens
+	// of the changed users stay valid(see FetchRole), the demotions which would
+	// leave no active admin are skipped
+	SetRoles(ctx context.Context, changes []RoleChange) ([]RoleChangeResult, error)
+	// UpdatePassword clears the reset flag and revokes issued tokens
+	UpdatePassword(ctx context.Context, uuid UUID, passwordHash string) error
+	// UpdatePasswordHash replaces the hash of the same password(new algorithm or parameters)
+	UpdatePasswordHash(ctx context.Context, uuid UUID, passwordHash string) error
+	// FetchTokensValidAfter - tokens issued earlier are revoked, nil if never revoked
+	FetchTokensValidAfter(ctx context.Context, uuid UUID) (*time.Time, error)
+	// FetchRole - the current role of an active user, the one the role claims of
+	// its tokens are replaced with; ErrNotFound if deleted
+	FetchRole(ctx context.Context, uuid UUID) (string, error)
+	// ReencryptPII re-encrypts the PII of up to limit users with id > afterID that is not
+	// under the active key(or not encrypted yet), lastID == 0 - no users left
+	ReencryptPII(ctx context.Context, afterID ID, limit int) (lastID ID, updated int, err error)
+	// TouchLastSeen - the user has just logged in, a suspension warning is void
+	TouchLastSeen(ctx context.Context, uuid UUID) error
+	// FetchRedactionCandidates - up to limit users not seen since seenBefore with
+	// any of columns not blank yet, deleted users included, the ones on a legal hold not
+	FetchRedactionCandidates(ctx context.Context, seenBefore time.Time, columns []PIIColumn, limit int) ([]UUID, error)
+	// RedactPII blanks columns, false if the user was seen since seenBefore or is on a legal hold
+	RedactPII(ctx context.Context, uuid UUID, seenBefore time.Time, columns []PIIColumn) (bool, error)
+	// WarnInactive flags up to limit active users not seen since seenBefore as
+	// warned of their suspension, the admins and the ones warned already not
+	WarnInactive(ctx context.Context, seenBefore time.Time, limit int) ([]UUID, error)
+	// SuspendInactive suspends up to limit active users not seen since seenBefore and
+	// warned before warnedBefore(nil - not needed), their tokens are revoked. The
+	// admins are never suspended: they lift the suspensions. The user.suspended
+	// audit entries(details.inactive_days) are stored with the suspensions
+	SuspendInactive(ctx context.Context, seenBefore time.Time, warnedBefore *time.Time, limit int, inactiveDays int) ([]UUID, error)
+	// FetchSuspendedAt - nil if the user is not suspended, ErrNotFound if unknown or deleted
+	FetchSuspendedAt(ctx context.Context, uuid UUID) (*time.Time, error)
+	// Reactivate lifts the suspension, the user counts as seen now. False if it is
+	// not suspended, ErrNotFound if unknown or deleted
+	Reactivate(ctx context.Context, uuid UUID) (bool, error)
+	// CreateEmailChange replaces a pending change of the user
+	CreateEmailChange(ctx context.Context, id ID, c EmailChange) error
+	// ConfirmEmailChange switches the email, ErrNotFound if the token is unknown or expired
+	ConfirmEmailChange(ctx context.Context, tokenHash []byte) (*User, error)
+	// CreateInvitation replaces a pending invitation of the same email
+	CreateInvitation(ctx context.Context, inv Invitation) error
+	// FetchInvitation - the pending invitation of the token, ErrNotFound if it is
+	// unknown or expired
+	FetchInvitation(ctx context.Context, tokenHash []byte) (*Invitation, error)
+	// AcceptInvitation creates the invited user and removes the invitation,
+	// ErrNotFound if the token is unknown or expired
+	AcceptInvitation(ctx context.Context, tokenHash []byte, u User, passwordHash string) (*User, error)
+	// SetLegalHold places the hold(deleted users too) or replaces its reason, the
+	// since of a placed one is kept. ErrNotFound if unknown
+	SetLegalHold(ctx context.Context, uuid UUID, reason string) (*LegalHold, error)
+	// ReleaseLegalHold - false if the user is not on a hold, ErrNotFound if unknown
+	ReleaseLegalHold(ctx context.Context, uuid UUID) (bool, error)
+	// FetchLegalHold - nil if the user is not on a hold, ErrNotFound if unknown
+	FetchLegalHold(ctx context.Context, uuid UUID) (*LegalHold, error)
 }
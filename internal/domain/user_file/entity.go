@@ -1,27 +1,148 @@
 package user_file
 
 import (
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 
-	userDB "user-manager-api/internal/infrastructure/db/postgres/user"
+	"user-manager-api/internal/domain/user"
+)
+
+// the outcomes of a text extraction
+const (
+	TextExtracted = "extracted"
+	TextFailed    = "failed"
 )
 
 type (
 	UserFile struct {
 		UUID   uuid.UUID
-		UserID *userDB.ID
+		UserID *user.ID
+		// UserUUID - the owner, filled by the admin browsing only
+		UserUUID uuid.UUID
 
-		Bucket      string
-		StorageKey  string
-		FileName    string
-		MimeType    string
-		SizeBytes   uint64
-		DownloadURL string
+		Bucket       string
+		StorageKey   string
+		FileName     string
+		MimeType     string
+		SizeBytes    uint64
+		DownloadURL  string
+		ThumbnailURL string
+		Tags         []string
+		// Folder - "a/b" path of the file, "" - the root
+		Folder string
 
 		CreatedAt time.Time
 		DeletedAt *time.Time
 	}
 	UserFiles []*UserFile
+
+	// Folder - a subfolder of a listed folder, the counts cover its subfolders
+	Folder struct {
+		Path       string
+		FilesCount uint64
+		TotalBytes uint64
+	}
+	Folders []Folder
+
+	// Text - the text extracted from a file, Body is empty if it failed
+	Text struct {
+		Status      string
+		Body        string
+		ExtractedAt time.Time
+	}
+
+	// RetentionRule - the files of MimeType("image/*" wildcard) or of Tag, one of
+	// them is set, are purged Days after the upload. The longest of the
+	// matching rules applies
+	RetentionRule struct {
+		UUID      uuid.UUID
+		MimeType  string
+		Tag       string
+		Days      int
+		CreatedAt time.Time
+	}
+	RetentionRules []RetentionRule
+	// Retention - the per-file override of the rules: RetainUntil nil - the rules
+	// apply, LegalHold - never deleted
+	Retention struct {
+		RetainUntil *time.Time
+		LegalHold   bool
+	}
+
+	// UploadPolicy - the uploads of the users of an organization(email domain):
+	// AllowedTypes("image/*" wildcards, empty - any type), MaxFileSize of a file
+	// and QuotaBytes of all the files of a user, 0 - no limit of its own. The
+	// global SERVICE_MAX_UPLOAD_SIZE applies anyway
+	UploadPolicy struct {
+		Org          string
+		AllowedTypes []string
+		MaxFileSize  uint64
+		QuotaBytes   uint64
+		UpdatedAt    time.Time
+	}
+	UploadPolicies []UploadPolicy
+
+	// Filter - admin browsing across all users, nil/zero fields are not applied
+	Filter struct {
+		// MimeType - exact "image/png" or the type wildcard "image/*"
+		MimeType string
+		MinSize  *uint64
+		MaxSize  *uint64
+		// UploadedFrom inclusive, UploadedTo exclusive
+		UploadedFrom *time.Time
+		UploadedTo   *time.Time
+		UserUUID     *uuid.UUID
+	}
+	// Stats - aggregates of all files matching a Filter
+	Stats struct {
+		FilesCount uint64
+		TotalBytes uint64
+		ByMimeType []MimeTypeStats
+	}
+	MimeTypeStats struct {
+		MimeType   string
+		FilesCount uint64
+		TotalBytes uint64
+	}
+
+	// StorageRef - light projection of a row used for storage reconciliation
+	StorageRef struct {
+		UUID       uuid.UUID
+		StorageKey string
+		CreatedAt  time.Time
+	}
+	StorageRefs []StorageRef
+
+	// ObjectOwner - the owner of a stored object and the type the object is
+	// served with: the one of the file stored at the upload
+	ObjectOwner struct {
+		UserUUID user.UUID
+		MimeType string
+	}
+
+	// ReconcileReport - difference between storage objects and user_files rows
+	ReconcileReport struct {
+		// OrphanObjects - objects without rows
+		OrphanObjects []string
+		// MissingObjects - rows whose objects are missing
+		MissingObjects StorageRefs
+		Deleted        bool
+	}
 )
+
+// AllowsType - mimeType("type/subtype", lowercased) is one of AllowedTypes or
+// of a "type/*" of them, any type if there are none
+func (p UploadPolicy) AllowsType(mimeType string) bool {
+	if len(p.AllowedTypes) == 0 {
+		return true
+	}
+	major, _, ok := strings.Cut(mimeType, "/")
+	if !ok || major == "" {
+		return false
+	}
+
+	return slices.Contains(p.AllowedTypes, mimeType) || slices.Contains(p.AllowedTypes, major+"/*")
+}
@@ -0,0 +1,15 @@
+package user_file
+
+import "errors"
+
+var (
+	// ErrListingNotSupported - the object storage can not list its objects, an
+	// empty listing would tell every file is missing
+	ErrListingNotSupported = errors.New("object storage listing is not supported")
+	// ErrTooManyMissing - the difference is too large to be real(a wrong bucket
+	// or prefix, a failed query), nothing is deleted
+	ErrTooManyMissing = errors.New("too many files differ between the storage and the database")
+	// ErrInvalidKey - the object key escapes the storage(e.g. "../"), the request is
+	// refused rather than served
+	ErrInvalidKey = errors.New("invalid storage key")
+)
@@ -2,11 +2,67 @@ package user_file
 
 import (
 	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"user-manager-api/internal/domain/pagination"
 	"user-manager-api/internal/domain/user"
 )
 
+// SummaryRepository - the files summaries of the user queries
+type SummaryRepository interface {
+	// FetchSummaries - from the read model, users not counted yet are absent from the map
+	FetchSummaries(ctx context.Context, userUUIDs []uuid.UUID) (map[uuid.UUID]user.FilesSummary, error)
+}
+
 type Repository interface {
-	FetchUserFiles(ctx context.Context, userID user.ID, page int) (UserFiles, error)
+	// StreamUserFiles calls fn for every file of the page once it is read, of
+	// folder only if it is not nil. The file is reused for the next row: fn
+	// must not keep it
+	StreamUserFiles(ctx context.Context, userID user.ID, p pagination.Params, tags []string, folder *string, fn func(uf *UserFile) error) error
 	CreateUserFile(ctx context.Context, userID user.ID, req *UserFile) (*UserFile, error)
-	DeleteUserFiles(ctx context.Context, userID user.ID) error
+	// DeleteUserFiles returns the files deleted, their StorageKey and ThumbnailURL only
+	DeleteUserFiles(ctx context.Context, userID user.ID, tags []string) (UserFiles, error)
+	// FetchFiles - files of all users, UserUUID is filled
+	FetchFiles(ctx context.Context, f Filter, p pagination.Params) (UserFiles, error)
+	FetchStats(ctx context.Context, f Filter) (*Stats, error)
+	SummaryRepository
+	FetchStorageRefs(ctx context.Context, prefix string) (StorageRefs, error)
+	// FetchObjectOwner - the owner and the type of the live file stored under
+	// storageKey or whose thumbnail has thumbnailURL, nil if there is none
+	FetchObjectOwner(ctx context.Context, storageKey, thumbnailURL string) (*ObjectOwner, error)
+	// FetchDeletableFiles - the files of the user off a legal hold of their own,
+	// UUID and StorageKey filled
+	FetchDeletableFiles(ctx context.Context, userID user.ID) (UserFiles, error)
+	DeleteUserFilesByUUIDs(ctx context.Context, uuids []uuid.UUID) error
+	UpdateThumbnailURL(ctx context.Context, fileUUID uuid.UUID, url string) error
+	// FetchFolders - the direct subfolders of parent("" - the root) holding files
+	FetchFolders(ctx context.Context, userID user.ID, parent string) (Folders, error)
+	// MoveUserFile sets the folder and the name of a file(nil - kept), nil if the
+	// user has no such file
+	MoveUserFile(ctx context.Context, userID user.ID, fileUUID uuid.UUID, folder, fileName *string) (*UserFile, error)
+	SaveText(ctx context.Context, fileUUID uuid.UUID, t Text) error
+	// FetchText - nil if the user has no such file or it has no text yet
+	FetchText(ctx context.Context, userID user.ID, fileUUID uuid.UUID) (*Text, error)
+	// SearchUserFiles - the files whose text matches query(web search syntax), best first
+	SearchUserFiles(ctx context.Context, userID user.ID, query string, limit int) (UserFiles, error)
+	FetchRetentionRules(ctx context.Context) (RetentionRules, error)
+	CreateRetentionRule(ctx context.Context, r RetentionRule) (*RetentionRule, error)
+	// DeleteRetentionRule - false if there is no such rule
+	DeleteRetentionRule(ctx context.Context, ruleUUID uuid.UUID) (bool, error)
+	// SetRetention - false if there is no such file
+	SetRetention(ctx context.Context, fileUUID uuid.UUID, r Retention) (bool, error)
+	// PurgeExpiredFiles soft deletes up to limit files expired before now and not
+	// on a legal hold, returns them with UserUUID filled
+	PurgeExpiredFiles(ctx context.Context, now time.Time, limit int) (UserFiles, error)
+	// FetchUsedBytes - the total size of the files of the user
+	FetchUsedBytes(ctx context.Context, userID user.ID) (uint64, error)
+	// FetchUploadPolicies - of all the organizations with one, by organization
+	FetchUploadPolicies(ctx context.Context) (UploadPolicies, error)
+	SaveUploadPolicy(ctx context.Context, p UploadPolicy) (*UploadPolicy, error)
+	// DeleteUploadPolicy - false if the organization had no policy
+	DeleteUploadPolicy(ctx context.Context, org string) (bool, error)
+	// MoveFolder moves the files of from and its subfolders under to, returns the count moved
+	MoveFolder(ctx context.Context, userID user.ID, from, to string) (int64, error)
 }
@@ -0,0 +1,20 @@
+package user_note
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type (
+	// Note - admin-only annotation on a user account. Never part of user-facing
+	// responses or exports.
+	Note struct {
+		UUID       uuid.UUID
+		AuthorUUID uuid.UUID
+		Body       string
+
+		CreatedAt time.Time
+	}
+	Notes []*Note
+)
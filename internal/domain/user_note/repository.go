@@ -0,0 +1,17 @@
+package user_note
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"user-manager-api/internal/domain/pagination"
+	"user-manager-api/internal/domain/user"
+)
+
+type Repository interface {
+	FetchNotes(ctx context.Context, userID user.ID, p pagination.Params) (Notes, error)
+	CreateNote(ctx context.Context, userID user.ID, req Note) (*Note, error)
+	// DeleteNote returns false if the user has no such note
+	DeleteNote(ctx context.Context, userID user.ID, noteUUID uuid.UUID) (bool, error)
+}
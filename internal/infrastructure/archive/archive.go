@@ -0,0 +1,278 @@
+package archive
+
+import (
+	"bufio"
+	"compress/gzip"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"user-manager-api/internal/domain/backup"
+)
+
+// An archive is a gzip stream of JSON lines, a backup.Header and then the
+// rows, encrypted in chunks with AES-256-GCM:
+//
+//	magic | nonce prefix(8) | { flags|length(4) | sealed chunk }...
+//
+// The nonce of a chunk is the prefix and the chunk number, the last chunk is
+// flagged and the flag is authenticated, so reordered, dropped or truncated
+// chunks fail to open instead of restoring a part of the data.
+const (
+	Format = 1
+
+	KeyLen = 32
+
+	magic      = "UMBAK1"
+	prefixLen  = 8
+	chunkSize  = 64 << 10
+	lastChunk  = uint32(1) << 31
+	lengthMask = lastChunk - 1
+)
+
+var (
+	ErrMalformed = errors.New("malformed backup archive")
+	// ErrAuth - a wrong key or a modified archive
+	ErrAuth = errors.New("backup archive authentication failed")
+)
+
+type (
+	record struct {
+		Table string          `json:"table"`
+		Row   json.RawMessage `json:"row"`
+	}
+	Writer struct {
+		chunks *chunkWriter
+		gz     *gzip.Writer
+		enc    *json.Encoder
+	}
+	Reader struct {
+		dec *json.Decoder
+	}
+)
+
+// NewWriter writes the header, the rows are written by Write, Close writes
+// the last chunk(without it the archive is truncated) but not closes w
+func NewWriter(w io.Writer, key []byte, createdAt time.Time) (*Writer, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	prefix := make([]byte, prefixLen)
+	if _, err = rand.Read(prefix); err != nil {
+		return nil, err
+	}
+	if _, err = io.WriteString(w, magic); err != nil {
+		return nil, err
+	}
+	if _, err = w.Write(prefix); err != nil {
+		return nil, err
+	}
+
+	chunks := &chunkWriter{w: w, aead: aead, prefix: prefix, buf: make([]byte, 0, chunkSize)}
+	gz := gzip.NewWriter(chunks)
+	aw := &Writer{chunks: chunks, gz: gz, enc: json.NewEncoder(gz)}
+	if err = aw.enc.Encode(backup.Header{Format: Format, CreatedAt: createdAt.UTC()}); err != nil {
+		return nil, err
+	}
+
+	return aw, nil
+}
+
+func (aw *Writer) Write(row backup.Row) error {
+	return aw.enc.Encode(record{Table: row.Table, Row: row.Data})
+}
+
+func (aw *Writer) Close() error {
+	if err := aw.gz.Close(); err != nil {
+		return err
+	}
+	return aw.chunks.close()
+}
+
+// NewReader reads the header, the rows are read by Next
+func NewReader(r io.Reader, key []byte) (*Reader, backup.Header, error) {
+	var header backup.Header
+
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, header, err
+	}
+	br := bufio.NewReader(r)
+	head := make([]byte, len(magic)+prefixLen)
+	if _, err = io.ReadFull(br, head); err != nil || string(head[:len(magic)]) != magic {
+		return nil, header, ErrMalformed
+	}
+
+	gz, err := gzip.NewReader(&chunkReader{r: br, aead: aead, prefix: head[len(magic):]})
+	if err != nil {
+		return nil, header, archiveErr(err)
+	}
+	ar := &Reader{dec: json.NewDecoder(gz)}
+	if err = ar.dec.Decode(&header); err != nil {
+		return nil, header, archiveErr(err)
+	}
+	if header.Format != Format {
+		return nil, header, fmt.Errorf("%w: format %d", ErrMalformed, header.Format)
+	}
+
+	return ar, header, nil
+}
+
+// Next - io.EOF after the last row of an archive read to its authenticated end
+func (ar *Reader) Next() (backup.Row, error) {
+	var rec record
+	if err := ar.dec.Decode(&rec); err != nil {
+		if errors.Is(err, io.EOF) {
+			return backup.Row{}, io.EOF
+		}
+		return backup.Row{}, archiveErr(err)
+	}
+	if rec.Table == "" || len(rec.Row) == 0 {
+		return backup.Row{}, ErrMalformed
+	}
+
+	return backup.Row{Table: rec.Table, Data: rec.Row}, nil
+}
+
+// archiveErr keeps the chunk errors and reports the rest(gzip, json) as malformed
+func archiveErr(err error) error {
+	if errors.Is(err, ErrAuth) || errors.Is(err, ErrMalformed) {
+		return err
+	}
+	if errors.Is(err, io.ErrUnexpectedEOF) {
+		return fmt.Errorf("%w: truncated", ErrMalformed)
+	}
+	return fmt.Errorf("%w: %v", ErrMalformed, err)
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	if len(key) != KeyLen {
+		return nil, fmt.Errorf("backup key must be %d bytes", KeyLen)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func chunkNonce(prefix []byte, n uint32) []byte {
+	nonce := make([]byte, 0, prefixLen+4)
+	nonce = append(nonce, prefix...)
+	return binary.BigEndian.AppendUint32(nonce, n)
+}
+
+// chunkAD - the associated data of a chunk: whether it is the last one
+func chunkAD(last bool) []byte {
+	if last {
+		return []byte{1}
+	}
+	return []byte{0}
+}
+
+type chunkWriter struct {
+	w      io.Writer
+	aead   cipher.AEAD
+	prefix []byte
+	buf    []byte
+	n      uint32
+}
+
+func (cw *chunkWriter) Write(p []byte) (int, error) {
+	written := 0
+	for len(p) > 0 {
+		// a full chunk is sealed on the next write or close: the last one
+		// has to be flagged
+		if len(cw.buf) == chunkSize {
+			if err := cw.seal(false); err != nil {
+				return written, err
+			}
+		}
+		k := min(chunkSize-len(cw.buf), len(p))
+		cw.buf = append(cw.buf, p[:k]...)
+		p = p[k:]
+		written += k
+	}
+	return written, nil
+}
+
+func (cw *chunkWriter) close() error { return cw.seal(true) }
+
+func (cw *chunkWriter) seal(last bool) error {
+	sealed := cw.aead.Seal(nil, chunkNonce(cw.prefix, cw.n), cw.buf, chunkAD(last))
+	length := uint32(len(sealed))
+	if last {
+		length |= lastChunk
+	}
+	if err := binary.Write(cw.w, binary.BigEndian, length); err != nil {
+		return err
+	}
+	if _, err := cw.w.Write(sealed); err != nil {
+		return err
+	}
+	cw.n++
+	cw.buf = cw.buf[:0]
+	return nil
+}
+
+type chunkReader struct {
+	r      io.Reader
+	aead   cipher.AEAD
+	prefix []byte
+	buf    []byte
+	n      uint32
+	done   bool
+}
+
+func (cr *chunkReader) Read(p []byte) (int, error) {
+	for len(cr.buf) == 0 {
+		if cr.done {
+			return 0, io.EOF
+		}
+		if err := cr.open(); err != nil {
+			return 0, err
+		}
+	}
+	k := copy(p, cr.buf)
+	cr.buf = cr.buf[k:]
+	return k, nil
+}
+
+func (cr *chunkReader) open() error {
+	var length uint32
+	if err := binary.Read(cr.r, binary.BigEndian, &length); err != nil {
+		// the end without the last chunk
+		return fmt.Errorf("%w: truncated", ErrMalformed)
+	}
+	last := length&lastChunk != 0
+	size := int(length & lengthMask)
+	if size < cr.aead.Overhead() || size > chunkSize+cr.aead.Overhead() {
+		return ErrMalformed
+	}
+
+	sealed := make([]byte, size)
+	if _, err := io.ReadFull(cr.r, sealed); err != nil {
+		return fmt.Errorf("%w: truncated", ErrMalformed)
+	}
+	plain, err := cr.aead.Open(sealed[:0], chunkNonce(cr.prefix, cr.n), sealed, chunkAD(last))
+	if err != nil {
+		return ErrAuth
+	}
+	if last {
+		// nothing may follow the last chunk
+		if n, _ := cr.r.Read(make([]byte, 1)); n > 0 {
+			return ErrMalformed
+		}
+		cr.done = true
+	}
+	cr.n++
+	cr.buf = plain
+	return nil
+}
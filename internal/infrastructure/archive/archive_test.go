@@ -0,0 +1,136 @@
+package archive
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"user-manager-api/internal/domain/backup"
+)
+
+func testKey(b byte) []byte { return bytes.Repeat([]byte{b}, KeyLen) }
+
+// writeArchive - rows of random data, so the compressed archive spans chunks
+func writeArchive(t *testing.T, key []byte, count int) ([]byte, []backup.Row) {
+	t.Helper()
+	var buf bytes.Buffer
+	aw, err := NewWriter(&buf, key, time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+
+	rows := make([]backup.Row, count)
+	for i := range rows {
+		noise := make([]byte, 512)
+		_, _ = rand.Read(noise)
+		data, err := json.Marshal(map[string]any{"id": i + 1, "noise": hex.EncodeToString(noise)})
+		require.NoError(t, err)
+		rows[i] = backup.Row{Table: backup.Tables[i%len(backup.Tables)], Data: data}
+		require.NoError(t, aw.Write(rows[i]))
+	}
+	require.NoError(t, aw.Close())
+
+	return buf.Bytes(), rows
+}
+
+func readAll(key, archive []byte) ([]backup.Row, error) {
+	ar, _, err := NewReader(bytes.NewReader(archive), key)
+	if err != nil {
+		return nil, err
+	}
+	var rows []backup.Row
+	for {
+		row, err := ar.Next()
+		if errors.Is(err, io.EOF) {
+			return rows, nil
+		}
+		if err != nil {
+			return rows, err
+		}
+		rows = append(rows, row)
+	}
+}
+
+func TestArchive_RoundTrip(t *testing.T) {
+	key := testKey('a')
+	archive, rows := writeArchive(t, key, 300)
+	assert.Greater(t, len(archive), 2*chunkSize, "spans several chunks")
+	assert.NotContains(t, string(archive), `"noise"`)
+
+	_, header, err := NewReader(bytes.NewReader(archive), key)
+	require.NoError(t, err)
+	assert.Equal(t, Format, header.Format)
+	assert.Equal(t, time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC), header.CreatedAt)
+
+	got, err := readAll(key, archive)
+	require.NoError(t, err)
+	require.Len(t, got, len(rows))
+	for i := range rows {
+		assert.Equal(t, rows[i].Table, got[i].Table)
+		assert.JSONEq(t, string(rows[i].Data), string(got[i].Data))
+	}
+}
+
+func TestArchive_Empty(t *testing.T) {
+	key := testKey('a')
+	archive, _ := writeArchive(t, key, 0)
+
+	got, err := readAll(key, archive)
+	require.NoError(t, err)
+	assert.Empty(t, got)
+}
+
+func TestArchive_Invalid_Table(t *testing.T) {
+	key := testKey('a')
+	archive, _ := writeArchive(t, key, 300)
+	headLen := len(magic) + prefixLen
+
+	// the second chunk starts after the first full one
+	secondChunk := headLen + 4 + chunkSize + 16
+	withoutSecond := append(clone(archive[:secondChunk]), archive[secondChunk+4+chunkSize+16:]...)
+
+	type tc struct {
+		name    string
+		key     []byte
+		archive []byte
+		wantErr error
+	}
+	tests := []tc{
+		{"wrong key", testKey('b'), archive, ErrAuth},
+		{"not an archive", key, []byte("PK\x03\x04 zip file"), ErrMalformed},
+		{"empty", key, nil, ErrMalformed},
+		{"flipped byte", key, flip(archive, headLen+100), ErrAuth},
+		{"flipped nonce prefix", key, flip(archive, len(magic)), ErrAuth},
+		{"truncated at a chunk", key, archive[:secondChunk], ErrMalformed},
+		{"truncated in a chunk", key, archive[:len(archive)-10], ErrMalformed},
+		{"chunk dropped", key, withoutSecond, ErrAuth},
+		{"data after the end", key, append(clone(archive), 0), ErrMalformed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := readAll(tt.key, tt.archive)
+			require.Error(t, err)
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestNewWriter_KeyLength(t *testing.T) {
+	_, err := NewWriter(io.Discard, []byte("short"), time.Now())
+	require.Error(t, err)
+}
+
+func clone(b []byte) []byte { return append([]byte(nil), b...) }
+
+func flip(b []byte, i int) []byte {
+	out := clone(b)
+	out[i] ^= 0xff
+	return out
+}
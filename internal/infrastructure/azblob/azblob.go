@@ -0,0 +1,64 @@
+package azblob
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"go.uber.org/zap"
+
+	"user-manager-api/config"
+	domain "user-manager-api/internal/domain/user_file"
+)
+
+type Client struct {
+	logger    *zap.Logger
+	account   string
+	container string
+}
+
+func New(
+	ctx context.Context,
+	logger *zap.Logger,
+	cfg config.Azure,
+) (*Client, error) {
+	if cfg.Account == "" || cfg.Container == "" {
+		return nil, fmt.Errorf("invalid Azure config: account and container are required")
+	}
+	// azblob.NewClientWithSharedKeyCredential(...)
+
+	return &Client{
+		logger:    logger,
+		account:   cfg.Account,
+		container: cfg.Container,
+	}, nil
+}
+
+func (c *Client) PutObject(ctx context.Context, key, contentType string, body io.ReadSeeker, size int64) error {
+	// simulation: client.UploadStream(ctx, container, key, body, &azblob.UploadStreamOptions{HTTPHeaders})
+	if _, err := io.Copy(io.Discard, body); err != nil {
+		return err
+	}
+
+	return ctx.Err()
+}
+
+func (c *Client) DeleteObjects(ctx context.Context, keys []string) error {
+	// simulation: blob batch delete, up to 256 blobs per batch
+	return ctx.Err()
+}
+
+func (c *Client) ListObjects(ctx context.Context, prefix string) ([]string, error) {
+	// simulation: client.NewListBlobsFlatPager(container, &azblob.ListBlobsFlatOptions{Prefix})
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return nil, domain.ErrListingNotSupported
+}
+
+func (c *Client) GetPublicURL(key string) string {
+	return fmt.Sprintf("https://%s.blob.core.windows.net/%s/%s", c.account, c.container, key)
+}
+
+func (c *Client) GetBucket() string { return c.container }
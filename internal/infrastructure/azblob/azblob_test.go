@@ -0,0 +1,59 @@
+package azblob
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"user-manager-api/config"
+	"user-manager-api/internal/domain/user_file"
+)
+
+func TestNew(t *testing.T) {
+	cases := []struct {
+		name    string
+		cfg     config.Azure
+		wantErr bool
+	}{
+		{"ok", config.Azure{Account: "acc", Container: "uploads"}, false},
+		{"no account", config.Azure{Container: "uploads"}, true},
+		{"no container", config.Azure{Account: "acc"}, true},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := New(context.Background(), zap.NewNop(), tt.cfg)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "uploads", c.GetBucket())
+		})
+	}
+}
+
+func TestClient(t *testing.T) {
+	c, err := New(context.Background(), zap.NewNop(), config.Azure{Account: "acc", Container: "uploads"})
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	body := strings.NewReader("hello")
+	require.NoError(t, c.PutObject(ctx, "u/a.txt", "text/plain", body, 5))
+	assert.Zero(t, body.Len(), "the body is read")
+	require.NoError(t, c.DeleteObjects(ctx, []string{"u/a.txt"}))
+	_, err = c.ListObjects(ctx, "u/")
+	require.ErrorIs(t, err, user_file.ErrListingNotSupported)
+	assert.Equal(t, "https://acc.blob.core.windows.net/uploads/u/a.txt", c.GetPublicURL("u/a.txt"))
+
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	require.ErrorIs(t, c.PutObject(canceled, "u/a.txt", "text/plain", strings.NewReader("hello"), 5), context.Canceled)
+	require.ErrorIs(t, c.DeleteObjects(canceled, nil), context.Canceled)
+	_, err = c.ListObjects(canceled, "u/")
+	require.ErrorIs(t, err, context.Canceled)
+}
@@ -0,0 +1,129 @@
+package postgres
+
+import (
+	"context"
+	"hash/fnv"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"user-manager-api/pkg/leader"
+)
+
+const (
+	releaseTimeout = 5 * time.Second
+	// leaseCheckInterval - how soon a leader notices its lost connection
+	leaseCheckInterval = 5 * time.Second
+)
+
+// AdvisoryLocker - cross-instance locks on Postgres session advisory locks. A
+// held lock pins its pool connection; if the instance dies the connection
+// drops and Postgres releases the lock, so it never outlives its holder.
+type AdvisoryLocker struct {
+	db *pgxpool.Pool
+}
+
+func NewAdvisoryLocker(db *pgxpool.Pool) *AdvisoryLocker {
+	return &AdvisoryLocker{db: db}
+}
+
+// TryLock - a short-lived lock, e.g. a job run
+func (l *AdvisoryLocker) TryLock(ctx context.Context, key string) (func(), bool, error) {
+	conn, id, acquired, err := l.tryLock(ctx, key)
+	if err != nil || !acquired {
+		return nil, false, err
+	}
+
+	return func() { unlock(conn, id) }, true, nil
+}
+
+// TryAcquire - a leadership, lost as soon as its connection fails a health check
+func (l *AdvisoryLocker) TryAcquire(ctx context.Context, key string) (leader.Lease, bool, error) {
+	conn, id, acquired, err := l.tryLock(ctx, key)
+	if err != nil || !acquired {
+		return nil, false, err
+	}
+
+	ls := &lease{conn: conn, id: id, done: make(chan struct{}), stop: make(chan struct{})}
+	go ls.watch()
+
+	return ls, true, nil
+}
+
+func (l *AdvisoryLocker) tryLock(ctx context.Context, key string) (*pgxpool.Conn, int64, bool, error) {
+	conn, err := l.db.Acquire(ctx)
+	if err != nil {
+		return nil, 0, false, err
+	}
+
+	id := advisoryKey(key)
+	var acquired bool
+	if err = conn.QueryRow(ctx, "SELECT pg_try_advisory_lock($1)", id).Scan(&acquired); err != nil || !acquired {
+		conn.Release()
+		return nil, 0, false, err
+	}
+
+	return conn, id, true, nil
+}
+
+func unlock(conn *pgxpool.Conn, id int64) {
+	// the holder context may be already canceled(shutdown)
+	ctx, cancel := context.WithTimeout(context.Background(), releaseTimeout)
+	defer cancel()
+	if _, err := conn.Exec(ctx, "SELECT pg_advisory_unlock($1)", id); err != nil {
+		// the lock must not stay with a pooled connection
+		_ = conn.Conn().Close(ctx)
+	}
+	conn.Release()
+}
+
+type lease struct {
+	conn *pgxpool.Conn
+	id   int64
+	// done - the leadership is lost, stop - released by the holder
+	done     chan struct{}
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+func (ls *lease) Done() <-chan struct{} { return ls.done }
+
+func (ls *lease) Release() {
+	ls.stopOnce.Do(func() { close(ls.stop) })
+	// the watcher owns the connection until it exits
+	<-ls.done
+}
+
+// watch pings the lock connection, the connection is not safe for concurrent
+// use, so watch is the only one using it until Release
+func (ls *lease) watch() {
+	defer close(ls.done)
+
+	t := time.NewTicker(leaseCheckInterval)
+	defer t.Stop()
+	for {
+		select {
+		case <-t.C:
+			ctx, cancel := context.WithTimeout(context.Background(), leaseCheckInterval)
+			err := ls.conn.Ping(ctx)
+			cancel()
+			if err != nil {
+				// Postgres has dropped the session and the lock with it
+				_ = ls.conn.Conn().Close(context.Background())
+				ls.conn.Release()
+				return
+			}
+		case <-ls.stop:
+			unlock(ls.conn, ls.id)
+			return
+		}
+	}
+}
+
+// advisoryKey maps a lock name to the bigint key space of advisory locks
+func advisoryKey(key string) int64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte("usermanager:" + key))
+	return int64(h.Sum64())
+}
@@ -0,0 +1,20 @@
+package audit
+
+const (
+	InsertEntry = `
+		INSERT INTO audit_log (actor_uuid, action, target_uuid, details)
+		VALUES ($1, $2, $3, $4)
+	`
+	SelectEntries = `
+		SELECT id, actor_uuid, action, target_uuid, details, created_at
+		FROM audit_log
+		WHERE true`
+	SelectExpiredEntries = `
+		SELECT id, actor_uuid, action, target_uuid, details, created_at
+		FROM audit_log
+		WHERE created_at < $1
+		ORDER BY created_at, id
+		LIMIT $2
+	`
+	DeleteEntries = `DELETE FROM audit_log WHERE id = ANY($1)`
+)
@@ -0,0 +1,123 @@
+package audit
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"user-manager-api/internal/domain/audit"
+	"user-manager-api/internal/domain/pagination"
+	"user-manager-api/internal/infrastructure/db/postgres"
+)
+
+type Repository struct {
+	db       postgres.DB
+	pageSize int
+}
+
+func NewRepository(db postgres.DB, pageSize int) audit.Repository {
+	return &Repository{db: db, pageSize: pageSize}
+}
+
+func (r *Repository) CreateEntry(ctx context.Context, e audit.Entry) error {
+	details := e.Details
+	if details == nil {
+		details = map[string]any{}
+	}
+
+	_, err := r.db.Exec(ctx, InsertEntry, e.ActorUUID, string(e.Action), e.TargetUUID, details)
+	return err
+}
+
+// FetchEntries - offset pages: the entries have no UUID for the keyset cursor,
+// the id breaks the ties of created_at
+func (r *Repository) FetchEntries(ctx context.Context, f audit.Filter, p pagination.Params) ([]audit.Entry, error) {
+	where, args := filterClause(f, 1)
+	perPage := p.PerPage
+	if perPage == 0 {
+		perPage = r.pageSize
+	}
+	dir := "ASC"
+	if p.Desc {
+		dir = "DESC"
+	}
+	clause := fmt.Sprintf(" ORDER BY created_at %s, id %s LIMIT $%d OFFSET $%d", dir, dir, len(args)+1, len(args)+2)
+
+	rows, err := r.db.Query(ctx, SelectEntries+where+clause, append(args, perPage, p.Offset(perPage))...)
+	if err != nil {
+		return nil, err
+	}
+
+	return scanEntries(rows)
+}
+
+func (r *Repository) FetchExpired(ctx context.Context, before time.Time, limit int) ([]audit.Entry, error) {
+	rows, err := r.db.Query(ctx, SelectExpiredEntries, before, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return scanEntries(rows)
+}
+
+func (r *Repository) DeleteEntries(ctx context.Context, ids []int64) (int64, error) {
+	tag, err := r.db.Exec(ctx, DeleteEntries, ids)
+	if err != nil {
+		return 0, err
+	}
+
+	return tag.RowsAffected(), nil
+}
+
+func scanEntries(rows pgx.Rows) ([]audit.Entry, error) {
+	defer rows.Close()
+
+	var entries []audit.Entry
+	for rows.Next() {
+		var (
+			e      audit.Entry
+			action string
+		)
+		if err := rows.Scan(&e.ID, &e.ActorUUID, &action, &e.TargetUUID, &e.Details, &e.CreatedAt); err != nil {
+			return nil, err
+		}
+		e.Action = audit.Action(action)
+		entries = append(entries, e)
+	}
+
+	return entries, rows.Err()
+}
+
+// filterClause - " AND ..." conditions of f, argN - next placeholder
+func filterClause(f audit.Filter, argN int) (string, []any) {
+	var (
+		b    strings.Builder
+		args []any
+	)
+	add := func(cond string, arg any) {
+		fmt.Fprintf(&b, " AND "+cond, argN)
+		args = append(args, arg)
+		argN++
+	}
+
+	if f.ActorUUID != nil {
+		add("actor_uuid = $%d", *f.ActorUUID)
+	}
+	if f.Action != "" {
+		add("action = $%d", string(f.Action))
+	}
+	if f.TargetUUID != nil {
+		add("target_uuid = $%d", *f.TargetUUID)
+	}
+	if f.From != nil {
+		add("created_at >= $%d", *f.From)
+	}
+	if f.To != nil {
+		add("created_at < $%d", *f.To)
+	}
+
+	return b.String(), args
+}
@@ -0,0 +1,62 @@
+package backup
+
+import "user-manager-api/internal/domain/backup"
+
+const (
+	ExportUsers     = `SELECT to_jsonb(t) FROM users t ORDER BY t.id`
+	ExportUserFiles = `SELECT to_jsonb(t) FROM user_files t ORDER BY t.id`
+	ExportAuditLog  = `SELECT to_jsonb(t) FROM audit_log t ORDER BY t.id`
+
+	SelectIsEmpty = `
+		SELECT NOT EXISTS (SELECT 1 FROM users)
+		   AND NOT EXISTS (SELECT 1 FROM user_files)
+		   AND NOT EXISTS (SELECT 1 FROM audit_log)
+	`
+
+	// the columns missing in a row(an archive of an older schema) are NULL,
+	// not their defaults. The archive is restored as it is, over the seat limits:
+	// the check of users_seat_limit_trg is off for the statement
+	ImportUsers = `
+		INSERT INTO users
+		SELECT (jsonb_populate_record(NULL::users, r.row::jsonb - 'deleted_by')).*
+		FROM unnest($1::text[]) AS r (row)
+		WHERE set_config('user_manager.seat_limit_check', 'off', true) = 'off'
+	`
+	ImportUserFiles = `
+		INSERT INTO user_files
+		SELECT (jsonb_populate_record(NULL::user_files, r.row::jsonb)).*
+		FROM unnest($1::text[]) AS r (row)
+	`
+	ImportAuditLog = `
+		INSERT INTO audit_log
+		SELECT (jsonb_populate_record(NULL::audit_log, r.row::jsonb)).*
+		FROM unnest($1::text[]) AS r (row)
+	`
+
+	UpdateDeletedBy = `
+		UPDATE users u
+		SET deleted_by = r.deleted_by
+		FROM unnest($1::int[], $2::int[]) AS r (id, deleted_by)
+		WHERE u.id = r.id
+	`
+
+	ResetSequences = `
+		SELECT setval(pg_get_serial_sequence('users', 'id'), COALESCE((SELECT max(id) FROM users), 0) + 1, false),
+		       setval(pg_get_serial_sequence('user_files', 'id'), COALESCE((SELECT max(id) FROM user_files), 0) + 1, false),
+		       setval(pg_get_serial_sequence('audit_log', 'id'), COALESCE((SELECT max(id) FROM audit_log), 0) + 1, false)
+	`
+)
+
+// the table names never come from an archive into SQL, only these statements
+var (
+	exports = map[string]string{
+		backup.TableUsers:     ExportUsers,
+		backup.TableUserFiles: ExportUserFiles,
+		backup.TableAuditLog:  ExportAuditLog,
+	}
+	imports = map[string]string{
+		backup.TableUsers:     ImportUsers,
+		backup.TableUserFiles: ImportUserFiles,
+		backup.TableAuditLog:  ImportAuditLog,
+	}
+)
@@ -0,0 +1,88 @@
+package backup
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"user-manager-api/internal/domain/backup"
+	"user-manager-api/internal/infrastructure/db/postgres"
+)
+
+type Repository struct {
+	db postgres.DB
+}
+
+func NewRepository(db postgres.DB) backup.Repository {
+	return &Repository{db: db}
+}
+
+func (r *Repository) Export(ctx context.Context, table string, fn func(row json.RawMessage) error) error {
+	query, ok := exports[table]
+	if !ok {
+		return fmt.Errorf("unknown backup table %q", table)
+	}
+
+	rows, err := r.db.Query(ctx, query)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var row []byte
+		if err = rows.Scan(&row); err != nil {
+			return err
+		}
+		if err = fn(row); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+func (r *Repository) IsEmpty(ctx context.Context) (bool, error) {
+	var empty bool
+	err := r.db.QueryRow(ctx, SelectIsEmpty).Scan(&empty)
+	return empty, err
+}
+
+func (r *Repository) Import(ctx context.Context, table string, rows []json.RawMessage) error {
+	query, ok := imports[table]
+	if !ok {
+		return fmt.Errorf("unknown backup table %q", table)
+	}
+	if len(rows) == 0 {
+		return nil
+	}
+
+	values := make([]string, len(rows))
+	for i, row := range rows {
+		values[i] = string(row)
+	}
+
+	_, err := r.db.Exec(ctx, query, values)
+	return err
+}
+
+func (r *Repository) LinkDeletedBy(ctx context.Context, deletedBy map[int64]int64) error {
+	if len(deletedBy) == 0 {
+		return nil
+	}
+
+	ids := make([]int64, 0, len(deletedBy))
+	admins := make([]int64, 0, len(deletedBy))
+	for id, admin := range deletedBy {
+		ids = append(ids, id)
+		admins = append(admins, admin)
+	}
+
+	_, err := r.db.Exec(ctx, UpdateDeletedBy, ids, admins)
+	return err
+}
+
+func (r *Repository) ResetSequences(ctx context.Context) error {
+	_, err := r.db.Exec(ctx, ResetSequences)
+	return err
+}
@@ -2,8 +2,12 @@ package postgres
 
 import (
 	"errors"
+	"fmt"
+	"strings"
 
 	"github.com/jackc/pgx/v5/pgconn"
+
+	"user-manager-api/internal/domain/pagination"
 )
 
 func IsPgUniqueViolation(err error) bool {
@@ -13,3 +17,48 @@ func IsPgUniqueViolation(err error) bool {
 	}
 	return false
 }
+
+// IsPgSeatLimitReached - raised by users_seat_limit_trg
+func IsPgSeatLimitReached(err error) bool {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgErr.Code == "UM001"
+	}
+	return false
+}
+
+// PageClause appends keyset condition, ORDER BY and LIMIT/OFFSET to a query
+// whose WHERE clause is already open. Sort columns come from the whitelist
+// only, so they are safe to be formatted into SQL. argN - next placeholder.
+func PageClause(
+	p pagination.Params,
+	sortColumns map[string]string,
+	defaultPerPage int,
+	argN int,
+) (string, []any) {
+	perPage := p.PerPage
+	if perPage == 0 {
+		perPage = defaultPerPage
+	}
+
+	dir, cmp := "ASC", ">"
+	if p.Desc {
+		dir, cmp = "DESC", "<"
+	}
+	col, ok := sortColumns[p.Sort]
+	if !ok || p.Sort == "" {
+		col = "created_at"
+	}
+
+	var b strings.Builder
+	var args []any
+	if p.Cursor != nil {
+		fmt.Fprintf(&b, " AND (created_at, uuid) %s ($%d, $%d)", cmp, argN, argN+1)
+		args = append(args, p.Cursor.CreatedAt, p.Cursor.UUID)
+		argN += 2
+	}
+	fmt.Fprintf(&b, " ORDER BY %s %s, uuid %s LIMIT $%d OFFSET $%d", col, dir, dir, argN, argN+1)
+	args = append(args, perPage, p.Offset(perPage))
+
+	return b.String(), args
+}
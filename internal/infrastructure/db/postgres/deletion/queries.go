@@ -0,0 +1,34 @@
+package deletion
+
+const (
+	InsertSaga = `
+		INSERT INTO user_deletion_sagas (user_id, actor_uuid, reason, step, file_uuids, storage_keys)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING uuid, attempts, created_at, updated_at
+	`
+
+	UpdateSaga = `
+		UPDATE user_deletion_sagas
+		SET step = $2, status = $3, last_error = $4, updated_at = now()
+		WHERE uuid = $1
+	`
+
+	// ClaimStaleSagas - the claim bumps updated_at, so a saga resumed by one
+	// instance is not stale for the others
+	ClaimStaleSagas = `
+		UPDATE user_deletion_sagas s
+		SET attempts = s.attempts + 1, updated_at = now()
+		FROM users u
+		WHERE u.id = s.user_id AND s.id IN (
+		    SELECT c.id
+		    FROM user_deletion_sagas c
+		    WHERE c.status = 'running' AND c.updated_at < $1
+		    ORDER BY c.updated_at
+		    LIMIT $2
+		    FOR UPDATE SKIP LOCKED
+		)
+		RETURNING
+		  s.uuid, s.user_id, u.uuid, s.actor_uuid, s.reason, s.step, s.status, s.file_uuids, s.storage_keys,
+		  s.attempts, s.last_error, s.created_at, s.updated_at
+	`
+)
@@ -0,0 +1,91 @@
+package deletion
+
+import (
+	"context"
+	"time"
+
+	"user-manager-api/internal/domain/deletion"
+	"user-manager-api/internal/domain/user"
+	"user-manager-api/internal/infrastructure/db/postgres"
+)
+
+type Repository struct {
+	db postgres.DB
+}
+
+func NewRepository(db postgres.DB) deletion.Repository {
+	return &Repository{db: db}
+}
+
+func (r *Repository) Create(ctx context.Context, s deletion.Saga) (*deletion.Saga, error) {
+	err := r.db.QueryRow(
+		ctx,
+		InsertSaga,
+		s.UserID,
+		s.ActorUUID,
+		string(s.Reason),
+		string(s.Step),
+		s.FileUUIDs,
+		s.Keys,
+	).Scan(&s.UUID, &s.Attempts, &s.CreatedAt, &s.UpdatedAt)
+	if err != nil {
+		if postgres.IsPgUniqueViolation(err) {
+			return nil, deletion.ErrInProgress
+		}
+		return nil, err
+	}
+	s.Status = deletion.StatusRunning
+
+	return &s, nil
+}
+
+func (r *Repository) Update(ctx context.Context, s deletion.Saga) error {
+	_, err := r.db.Exec(ctx, UpdateSaga, s.UUID, string(s.Step), string(s.Status), s.LastError)
+	return err
+}
+
+func (r *Repository) ClaimStale(ctx context.Context, staleBefore time.Time, limit int) ([]deletion.Saga, error) {
+	rows, err := r.db.Query(ctx, ClaimStaleSagas, staleBefore, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var sagas []deletion.Saga
+	for rows.Next() {
+		var (
+			s            deletion.Saga
+			userID       uint64
+			reason       string
+			step, status string
+		)
+		err = rows.Scan(
+			&s.UUID,
+			&userID,
+			&s.UserUUID,
+			&s.ActorUUID,
+			&reason,
+			&step,
+			&status,
+			&s.FileUUIDs,
+			&s.Keys,
+			&s.Attempts,
+			&s.LastError,
+			&s.CreatedAt,
+			&s.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		s.UserID = user.ID(userID)
+		s.Reason = user.DeletionReason(reason)
+		s.Step = deletion.Step(step)
+		s.Status = deletion.Status(status)
+		sagas = append(sagas, s)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return sagas, nil
+}
@@ -0,0 +1,27 @@
+package device
+
+import (
+	domain "user-manager-api/internal/domain/device"
+)
+
+func fromDBModel(model *Device) *domain.Device {
+	var d = &domain.Device{
+		UUID:      model.UUID,
+		UserAgent: model.UserAgent,
+		IP:        model.IP,
+
+		CreatedAt:  model.CreatedAt,
+		LastSeenAt: model.LastSeenAt,
+	}
+
+	return d
+}
+
+func fromDBModels(models *Devices) domain.Devices {
+	ds := make(domain.Devices, len(*models))
+	for idx, d := range *models {
+		ds[idx] = fromDBModel(d)
+	}
+
+	return ds
+}
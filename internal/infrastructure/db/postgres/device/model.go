@@ -0,0 +1,19 @@
+package device
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type (
+	Device struct {
+		UUID      uuid.UUID
+		UserAgent string
+		IP        string
+
+		CreatedAt  time.Time
+		LastSeenAt time.Time
+	}
+	Devices []*Device
+)
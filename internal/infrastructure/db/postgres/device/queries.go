@@ -0,0 +1,27 @@
+package device
+
+const (
+	UpsertDevice = `
+		INSERT INTO devices (user_id, user_agent, ip)
+		SELECT id, $2, $3
+		FROM users
+		WHERE uuid = $1 AND deleted_at IS NULL
+		ON CONFLICT (user_id, user_agent) DO UPDATE
+		SET ip = EXCLUDED.ip,
+		    last_seen_at = now()
+		RETURNING uuid, user_agent, ip, created_at, last_seen_at
+	`
+	SelectDevices = `
+		SELECT d.uuid, d.user_agent, d.ip, d.created_at, d.last_seen_at
+		FROM devices d
+		JOIN users u ON u.id = d.user_id
+		WHERE u.uuid = $1
+		ORDER BY d.last_seen_at DESC, d.id DESC
+	`
+	DeleteDevice = `
+		DELETE FROM devices d
+		USING users u
+		WHERE u.id = d.user_id AND u.uuid = $1 AND d.uuid = $2
+	`
+	SelectDeviceExists = `SELECT EXISTS (SELECT 1 FROM devices WHERE uuid = $1)`
+)
@@ -0,0 +1,84 @@
+package device
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"user-manager-api/internal/domain/device"
+	"user-manager-api/internal/domain/user"
+	"user-manager-api/internal/infrastructure/db/postgres"
+)
+
+type Repository struct {
+	db postgres.DB
+}
+
+func NewRepository(db postgres.DB) device.Repository {
+	return &Repository{db: db}
+}
+
+func (r *Repository) TouchDevice(ctx context.Context, userUUID user.UUID, userAgent, ip string) (*device.Device, error) {
+	d := new(Device)
+	err := r.db.QueryRow(ctx, UpsertDevice, userUUID, userAgent, ip).Scan(
+		&d.UUID,
+		&d.UserAgent,
+		&d.IP,
+		&d.CreatedAt,
+		&d.LastSeenAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, user.ErrNotFound
+		}
+		return nil, err
+	}
+
+	return fromDBModel(d), nil
+}
+
+func (r *Repository) FetchDevices(ctx context.Context, userUUID user.UUID) (device.Devices, error) {
+	rows, err := r.db.Query(ctx, SelectDevices, userUUID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ds Devices
+	for rows.Next() {
+		d := new(Device)
+		if err = rows.Scan(
+			&d.UUID,
+			&d.UserAgent,
+			&d.IP,
+			&d.CreatedAt,
+			&d.LastSeenAt,
+		); err != nil {
+			return nil, err
+		}
+		ds = append(ds, d)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return fromDBModels(&ds), nil
+}
+
+func (r *Repository) DeleteDevice(ctx context.Context, userUUID user.UUID, deviceUUID uuid.UUID) (bool, error) {
+	tag, err := r.db.Exec(ctx, DeleteDevice, userUUID, deviceUUID)
+	if err != nil {
+		return false, err
+	}
+
+	return tag.RowsAffected() > 0, nil
+}
+
+func (r *Repository) DeviceExists(ctx context.Context, deviceUUID uuid.UUID) (bool, error) {
+	var exists bool
+	err := r.db.QueryRow(ctx, SelectDeviceExists, deviceUUID).Scan(&exists)
+
+	return exists, err
+}
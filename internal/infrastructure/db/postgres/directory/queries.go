@@ -0,0 +1,14 @@
+package directory
+
+const (
+	InsertSync = `
+		INSERT INTO directory_syncs (started_at, finished_at, fetched, created, updated, disabled, unchanged, skipped, failed, error)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	`
+	SelectLastSync = `
+		SELECT started_at, finished_at, fetched, created, updated, disabled, unchanged, skipped, failed, error
+		FROM directory_syncs
+		ORDER BY started_at DESC
+		LIMIT 1
+	`
+)
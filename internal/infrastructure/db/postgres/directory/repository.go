@@ -0,0 +1,52 @@
+package directory
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+
+	"user-manager-api/internal/domain/directory"
+	"user-manager-api/internal/infrastructure/db/postgres"
+)
+
+type Repository struct {
+	db postgres.DB
+}
+
+func NewRepository(db postgres.DB) directory.Repository {
+	return &Repository{db: db}
+}
+
+func (r *Repository) CreateSync(ctx context.Context, s directory.Sync) error {
+	_, err := r.db.Exec(
+		ctx,
+		InsertSync,
+		s.StartedAt, s.FinishedAt, s.Fetched, s.Created, s.Updated, s.Disabled, s.Unchanged, s.Skipped, s.Failed, s.Error,
+	)
+	return err
+}
+
+func (r *Repository) FetchLastSync(ctx context.Context) (*directory.Sync, error) {
+	s := new(directory.Sync)
+	err := r.db.QueryRow(ctx, SelectLastSync).Scan(
+		&s.StartedAt,
+		&s.FinishedAt,
+		&s.Fetched,
+		&s.Created,
+		&s.Updated,
+		&s.Disabled,
+		&s.Unchanged,
+		&s.Skipped,
+		&s.Failed,
+		&s.Error,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return s, nil
+}
@@ -0,0 +1,23 @@
+package event
+
+const (
+	// ClaimProcessed - an expired row not purged yet is claimed anew
+	ClaimProcessed = `
+		INSERT INTO processed_events (message_id)
+		VALUES ($1)
+		ON CONFLICT (message_id) DO UPDATE
+			SET processed_at = now()
+			WHERE processed_events.processed_at < now() - make_interval(secs => $2)
+		RETURNING true
+	`
+
+	DeleteProcessed = `
+		DELETE FROM processed_events
+		WHERE message_id = $1
+	`
+
+	PurgeProcessed = `
+		DELETE FROM processed_events
+		WHERE processed_at < now() - make_interval(secs => $1)
+	`
+)
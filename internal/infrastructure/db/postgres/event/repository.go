@@ -0,0 +1,48 @@
+package event
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"user-manager-api/internal/domain/event"
+	"user-manager-api/internal/infrastructure/db/postgres"
+)
+
+type Repository struct {
+	db postgres.DB
+}
+
+func NewRepository(db postgres.DB) event.Repository {
+	return &Repository{db: db}
+}
+
+func (r *Repository) Claim(ctx context.Context, id string, ttl time.Duration) (bool, error) {
+	var claimed bool
+	err := r.db.QueryRow(ctx, ClaimProcessed, id, ttl.Seconds()).Scan(&claimed)
+	if errors.Is(err, pgx.ErrNoRows) {
+		// the conflicting row is within ttl
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return claimed, nil
+}
+
+func (r *Repository) Release(ctx context.Context, id string) error {
+	_, err := r.db.Exec(ctx, DeleteProcessed, id)
+	return err
+}
+
+func (r *Repository) Purge(ctx context.Context, ttl time.Duration) (int64, error) {
+	tag, err := r.db.Exec(ctx, PurgeProcessed, ttl.Seconds())
+	if err != nil {
+		return 0, err
+	}
+
+	return tag.RowsAffected(), nil
+}
@@ -0,0 +1,94 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"user-manager-api/internal/infrastructure/resilience"
+)
+
+type (
+	guardDB struct {
+		db    DB
+		guard *resilience.Guard
+	}
+	// guardRows holds the bulkhead slot until the rows are read
+	guardRows struct {
+		pgx.Rows
+		done func(err error)
+	}
+	guardRow struct {
+		pgx.Row
+		done func(err error)
+	}
+	rejectedRow struct{ err error }
+)
+
+// WithGuard runs the statements through the circuit breaker and the bulkhead
+// of guard, nil guard returns db as is.
+func WithGuard(db DB, guard *resilience.Guard) DB {
+	if guard == nil {
+		return db
+	}
+	return &guardDB{db: db, guard: guard}
+}
+
+// IsFailure - the errors telling Postgres is unhealthy, not just answering
+// with an error
+func IsFailure(err error) bool {
+	return IsRetryable(err) || errors.Is(err, context.DeadlineExceeded)
+}
+
+func (g *guardDB) Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	var tag pgconn.CommandTag
+	err := g.guard.Do(ctx, func(ctx context.Context) error {
+		var err error
+		tag, err = g.db.Exec(ctx, sql, args...)
+		return err
+	})
+
+	return tag, err
+}
+
+func (g *guardDB) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	done, err := g.guard.Enter(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := g.db.Query(ctx, sql, args...)
+	if err != nil {
+		done(err)
+		return nil, err
+	}
+
+	return &guardRows{Rows: rows, done: done}, nil
+}
+
+func (g *guardDB) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	done, err := g.guard.Enter(ctx)
+	if err != nil {
+		return rejectedRow{err: err}
+	}
+
+	return &guardRow{Row: g.db.QueryRow(ctx, sql, args...), done: done}
+}
+
+func (r *guardRows) Close() {
+	r.Rows.Close()
+	if r.done != nil {
+		r.done(r.Rows.Err())
+		r.done = nil
+	}
+}
+
+func (r *guardRow) Scan(dest ...any) error {
+	err := r.Row.Scan(dest...)
+	r.done(err)
+
+	return err
+}
+
+func (r rejectedRow) Scan(...any) error { return r.err }
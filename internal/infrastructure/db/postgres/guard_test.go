@@ -0,0 +1,46 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"user-manager-api/internal/infrastructure/resilience"
+	"user-manager-api/pkg/bulkhead"
+)
+
+func TestWithGuard_Bulkhead(t *testing.T) {
+	ctx := context.Background()
+	guard := resilience.New(resilience.Settings{Name: "test", MaxConcurrent: 1}, zap.NewNop(), nil, nil, nil)
+	db := WithGuard(&fakeDB{}, guard)
+
+	// the slot is held until the rows are closed
+	rows, err := db.Query(ctx, "")
+	require.NoError(t, err)
+	_, err = db.Exec(ctx, "")
+	require.ErrorIs(t, err, bulkhead.ErrFull)
+	require.ErrorIs(t, db.QueryRow(ctx, "").Scan(), bulkhead.ErrFull)
+	rows.Close()
+	rows.Close()
+
+	// and until the row is scanned
+	row := db.QueryRow(ctx, "")
+	_, err = db.Query(ctx, "")
+	require.ErrorIs(t, err, bulkhead.ErrFull)
+	require.NoError(t, row.Scan())
+
+	_, err = db.Exec(ctx, "")
+	require.NoError(t, err)
+}
+
+func TestIsFailure(t *testing.T) {
+	assert.True(t, IsFailure(&pgconn.PgError{Code: "57P01"}))
+	assert.True(t, IsFailure(context.DeadlineExceeded))
+	assert.False(t, IsFailure(&pgconn.PgError{Code: "23505"}))
+	assert.False(t, IsFailure(pgx.ErrNoRows))
+}
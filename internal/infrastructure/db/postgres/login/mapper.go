@@ -0,0 +1,33 @@
+package login
+
+import (
+	domain "user-manager-api/internal/domain/login"
+)
+
+func fromDBModel(model *Login) *domain.Login {
+	var l = &domain.Login{
+		UserUUID: model.UserUUID,
+		Client: domain.Client{
+			IP:        model.IP,
+			UserAgent: model.UserAgent,
+			Country:   model.Country,
+		},
+		Flags: make([]domain.Flag, len(model.Flags)),
+
+		CreatedAt: model.CreatedAt,
+	}
+	for i, f := range model.Flags {
+		l.Flags[i] = domain.Flag(f)
+	}
+
+	return l
+}
+
+func fromDBModels(models *Logins) domain.Logins {
+	ls := make(domain.Logins, len(*models))
+	for idx, l := range *models {
+		ls[idx] = fromDBModel(l)
+	}
+
+	return ls
+}
@@ -0,0 +1,21 @@
+package login
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type (
+	Login struct {
+		ID        uint64
+		UserUUID  uuid.UUID
+		IP        string
+		UserAgent string
+		Country   string
+		Flags     []string
+
+		CreatedAt time.Time
+	}
+	Logins []*Login
+)
@@ -0,0 +1,31 @@
+package login
+
+const (
+	SelectLogins = `
+		SELECT l.id, u.uuid, l.ip, l.user_agent, l.country, l.flags, l.created_at
+		FROM logins l
+		JOIN users u ON u.id = l.user_id
+		WHERE u.uuid = $1
+		ORDER BY l.created_at DESC, l.id DESC
+	`
+	// InsertLogin - $7 the logins of the user kept, the new one included: the
+	// DELETE does not see the row inserted by the same statement
+	InsertLogin = `
+		WITH inserted AS (
+			INSERT INTO logins (user_id, ip, user_agent, country, flags, created_at)
+			SELECT id, $2, $3, $4, $5, $6
+			FROM users
+			WHERE uuid = $1
+			RETURNING user_id
+		)
+		DELETE FROM logins
+		WHERE user_id = (SELECT user_id FROM inserted)
+		  AND id NOT IN (
+		      SELECT id
+		      FROM logins
+		      WHERE user_id = (SELECT user_id FROM inserted)
+		      ORDER BY created_at DESC, id DESC
+		      LIMIT $7 - 1
+		  )
+	`
+)
@@ -0,0 +1,60 @@
+package login
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"user-manager-api/internal/domain/login"
+	"user-manager-api/internal/infrastructure/db/postgres"
+)
+
+type Repository struct {
+	db postgres.DB
+	// keep - the logins of a user kept for the rules
+	keep int
+}
+
+func NewRepository(db postgres.DB, keep int) login.Repository {
+	return &Repository{db: db, keep: keep}
+}
+
+func (r *Repository) FetchLogins(ctx context.Context, userUUID uuid.UUID) (login.Logins, error) {
+	rows, err := r.db.Query(ctx, SelectLogins, userUUID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ls Logins
+	for rows.Next() {
+		l := new(Login)
+		if err = rows.Scan(
+			&l.ID,
+			&l.UserUUID,
+			&l.IP,
+			&l.UserAgent,
+			&l.Country,
+			&l.Flags,
+			&l.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		ls = append(ls, l)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return fromDBModels(&ls), nil
+}
+
+func (r *Repository) CreateLogin(ctx context.Context, l login.Login) error {
+	flags := make([]string, len(l.Flags))
+	for i, f := range l.Flags {
+		flags[i] = string(f)
+	}
+
+	_, err := r.db.Exec(ctx, InsertLogin, l.UserUUID, l.IP, l.UserAgent, l.Country, flags, l.CreatedAt, r.keep)
+	return err
+}
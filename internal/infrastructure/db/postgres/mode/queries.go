@@ -0,0 +1,14 @@
+package mode
+
+const (
+	SelectReadOnly = `
+		SELECT read_only, reason, updated_by, updated_at
+		FROM service_mode
+	`
+
+	UpdateReadOnly = `
+		UPDATE service_mode
+		SET read_only = $1, reason = $2, updated_by = $3, updated_at = now()
+		RETURNING read_only, reason, updated_by, updated_at
+	`
+)
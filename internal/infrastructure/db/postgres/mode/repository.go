@@ -0,0 +1,36 @@
+package mode
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"user-manager-api/internal/domain/mode"
+	"user-manager-api/internal/infrastructure/db/postgres"
+)
+
+type Repository struct {
+	db postgres.DB
+}
+
+func NewRepository(db postgres.DB) mode.Repository {
+	return &Repository{db: db}
+}
+
+func (r *Repository) FetchReadOnly(ctx context.Context) (*mode.ReadOnly, error) {
+	return scanReadOnly(r.db.QueryRow(ctx, SelectReadOnly))
+}
+
+func (r *Repository) SaveReadOnly(ctx context.Context, enabled bool, reason string, by uuid.UUID) (*mode.ReadOnly, error) {
+	return scanReadOnly(r.db.QueryRow(ctx, UpdateReadOnly, enabled, reason, by))
+}
+
+func scanReadOnly(row pgx.Row) (*mode.ReadOnly, error) {
+	var ro mode.ReadOnly
+	if err := row.Scan(&ro.Enabled, &ro.Reason, &ro.UpdatedBy, &ro.UpdatedAt); err != nil {
+		return nil, err
+	}
+
+	return &ro, nil
+}
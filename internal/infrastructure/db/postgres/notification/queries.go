@@ -0,0 +1,58 @@
+package notification
+
+const (
+	SelectSuppressed = `
+		SELECT EXISTS (SELECT 1 FROM email_suppressions WHERE email = $1)
+	`
+	InsertSuppression = `
+		INSERT INTO email_suppressions (email, reason)
+		VALUES ($1, $2)
+		ON CONFLICT (email) DO NOTHING
+	`
+
+	SelectPreferences = `
+		SELECT p.channel, p.enabled, p.mode, p.webhook_url
+		FROM notification_preferences p
+		JOIN users u ON u.id = p.user_id
+		WHERE u.uuid = $1
+		ORDER BY p.channel
+	`
+	UpsertPreferences = `
+		INSERT INTO notification_preferences (user_id, channel, enabled, mode, webhook_url)
+		SELECT u.id, p.channel, p.enabled, p.mode, p.webhook_url
+		FROM users u,
+		     unnest($2::text[], $3::bool[], $4::text[], $5::text[]) AS p (channel, enabled, mode, webhook_url)
+		WHERE u.uuid = $1 AND u.deleted_at IS NULL
+		ON CONFLICT (user_id, channel) DO UPDATE
+		SET enabled     = EXCLUDED.enabled,
+		    mode        = EXCLUDED.mode,
+		    webhook_url = EXCLUDED.webhook_url,
+		    updated_at  = now()
+	`
+
+	InsertDigestItem = `
+		INSERT INTO notification_digest_items (user_id, channel, subject)
+		SELECT id, $2, $3
+		FROM users
+		WHERE uuid = $1
+	`
+	SelectDigestUsers = `
+		SELECT DISTINCT u.uuid
+		FROM notification_digest_items d
+		JOIN users u ON u.id = d.user_id
+		WHERE u.uuid > $1
+		ORDER BY u.uuid
+		LIMIT $2
+	`
+	SelectDigestItems = `
+		SELECT d.id, d.channel, d.subject, d.created_at
+		FROM notification_digest_items d
+		JOIN users u ON u.id = d.user_id
+		WHERE u.uuid = $1
+		ORDER BY d.id
+	`
+	DeleteDigestItems = `
+		DELETE FROM notification_digest_items
+		WHERE id = ANY ($1)
+	`
+)
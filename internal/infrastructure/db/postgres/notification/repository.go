@@ -0,0 +1,121 @@
+package notification
+
+import (
+	"context"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"user-manager-api/internal/domain/notification"
+	"user-manager-api/internal/infrastructure/db/postgres"
+)
+
+type Repository struct {
+	db postgres.DB
+}
+
+func NewRepository(db postgres.DB) notification.Repository {
+	return &Repository{db: db}
+}
+
+// IsSuppressed - the addresses are stored and compared lowercased
+func (r *Repository) IsSuppressed(ctx context.Context, email string) (bool, error) {
+	var suppressed bool
+	err := r.db.QueryRow(ctx, SelectSuppressed, strings.ToLower(email)).Scan(&suppressed)
+	return suppressed, err
+}
+
+func (r *Repository) Suppress(ctx context.Context, email, reason string) error {
+	_, err := r.db.Exec(ctx, InsertSuppression, strings.ToLower(email), reason)
+	return err
+}
+
+func (r *Repository) FetchPreferences(ctx context.Context, userUUID uuid.UUID) (notification.Preferences, error) {
+	rows, err := r.db.Query(ctx, SelectPreferences, userUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	var res notification.Preferences
+	for rows.Next() {
+		var p notification.Preference
+		if err = rows.Scan(&p.Channel, &p.Enabled, &p.Mode, &p.WebhookURL); err != nil {
+			return nil, err
+		}
+		res = append(res, p)
+	}
+
+	return res, rows.Err()
+}
+
+func (r *Repository) SavePreferences(ctx context.Context, userUUID uuid.UUID, p notification.Preferences) (bool, error) {
+	channels := make([]string, len(p))
+	enabled := make([]bool, len(p))
+	modes := make([]string, len(p))
+	urls := make([]string, len(p))
+	for i, pref := range p {
+		channels[i] = string(pref.Channel)
+		enabled[i] = pref.Enabled
+		modes[i] = string(pref.Mode)
+		urls[i] = pref.WebhookURL
+	}
+
+	tag, err := r.db.Exec(ctx, UpsertPreferences, userUUID, channels, enabled, modes, urls)
+	if err != nil {
+		return false, err
+	}
+
+	return tag.RowsAffected() > 0, nil
+}
+
+func (r *Repository) AddDigestItem(ctx context.Context, userUUID uuid.UUID, channel notification.Channel, subject string) error {
+	_, err := r.db.Exec(ctx, InsertDigestItem, userUUID, string(channel), subject)
+	return err
+}
+
+func (r *Repository) FetchDigestUsers(ctx context.Context, after uuid.UUID, limit int) ([]uuid.UUID, error) {
+	rows, err := r.db.Query(ctx, SelectDigestUsers, after, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	var res []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err = rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		res = append(res, id)
+	}
+
+	return res, rows.Err()
+}
+
+func (r *Repository) FetchDigestItems(ctx context.Context, userUUID uuid.UUID) ([]notification.DigestItem, error) {
+	rows, err := r.db.Query(ctx, SelectDigestItems, userUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	var res []notification.DigestItem
+	for rows.Next() {
+		var it notification.DigestItem
+		if err = rows.Scan(&it.ID, &it.Channel, &it.Subject, &it.CreatedAt); err != nil {
+			return nil, err
+		}
+		res = append(res, it)
+	}
+
+	return res, rows.Err()
+}
+
+func (r *Repository) DeleteDigestItems(ctx context.Context, ids []int64) error {
+	_, err := r.db.Exec(ctx, DeleteDigestItems, ids)
+	return err
+}
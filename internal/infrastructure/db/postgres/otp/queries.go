@@ -0,0 +1,42 @@
+package otp
+
+const (
+	UpsertOTP = `
+		INSERT INTO user_otps (user_id, code_hash, expires_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (user_id) DO UPDATE
+		SET code_hash = EXCLUDED.code_hash,
+		    attempts = 0,
+		    expires_at = EXCLUDED.expires_at,
+		    created_at = now()
+	`
+	// ClaimAttempt - counts the attempt and returns the code in one statement:
+	// the concurrent verifications of a code can not exceed the max attempts
+	ClaimAttempt = `
+		UPDATE user_otps
+		SET attempts = attempts + 1
+		WHERE user_id = $1 AND attempts < $2 AND expires_at > now()
+		RETURNING user_id, code_hash, attempts, expires_at, created_at
+	`
+	// DeleteOTP - by the code hash too: a code replaced meanwhile is kept
+	DeleteOTP = `DELETE FROM user_otps WHERE user_id = $1 AND code_hash = $2`
+	// ClaimCooldown - starts the cooldown of the phone unless it runs, and
+	// returns the start of the running one(the snapshot of the statement, the
+	// upsert is not visible to the select). The expired cooldowns of the other
+	// phones are pruned on the way
+	ClaimCooldown = `
+		WITH pruned AS (
+			DELETE FROM otp_cooldowns
+			WHERE requested_at <= now() - $2::interval AND phone_hash <> $1
+		), claimed AS (
+			INSERT INTO otp_cooldowns (phone_hash, requested_at)
+			VALUES ($1, now())
+			ON CONFLICT (phone_hash) DO UPDATE
+			SET requested_at = EXCLUDED.requested_at
+			WHERE otp_cooldowns.requested_at <= now() - $2::interval
+			RETURNING requested_at
+		)
+		SELECT EXISTS (SELECT 1 FROM claimed),
+		       COALESCE((SELECT requested_at FROM otp_cooldowns WHERE phone_hash = $1), now())
+	`
+)
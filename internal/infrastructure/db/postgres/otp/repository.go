@@ -0,0 +1,73 @@
+package otp
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"user-manager-api/internal/domain/otp"
+	"user-manager-api/internal/domain/user"
+	"user-manager-api/internal/infrastructure/db/postgres"
+	"user-manager-api/internal/infrastructure/fieldcrypt"
+)
+
+type Repository struct {
+	db postgres.DB
+	// cipher - the cooldowns are keyed by the blind index of the phone
+	cipher *fieldcrypt.Cipher
+}
+
+func NewRepository(db postgres.DB, cipher *fieldcrypt.Cipher) otp.Repository {
+	return &Repository{db: db, cipher: cipher}
+}
+
+func (r *Repository) UpsertOTP(ctx context.Context, o otp.OTP) error {
+	_, err := r.db.Exec(ctx, UpsertOTP, o.UserID, o.CodeHash, o.ExpiresAt)
+	return err
+}
+
+func (r *Repository) ClaimAttempt(ctx context.Context, userID user.ID, maxAttempts int) (*otp.OTP, error) {
+	o := new(otp.OTP)
+	err := r.db.QueryRow(ctx, ClaimAttempt, userID, maxAttempts).Scan(
+		&o.UserID,
+		&o.CodeHash,
+		&o.Attempts,
+		&o.ExpiresAt,
+		&o.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return o, nil
+}
+
+func (r *Repository) DeleteOTP(ctx context.Context, userID user.ID, codeHash []byte) (bool, error) {
+	tag, err := r.db.Exec(ctx, DeleteOTP, userID, codeHash)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() == 1, nil
+}
+
+func (r *Repository) ClaimCooldown(ctx context.Context, phone string, cooldown time.Duration) (time.Duration, error) {
+	var (
+		claimed     bool
+		requestedAt time.Time
+	)
+	err := r.db.QueryRow(ctx, ClaimCooldown, r.cipher.BlindIndex(phone), cooldown).Scan(&claimed, &requestedAt)
+	if err != nil {
+		return 0, err
+	}
+	if claimed {
+		return 0, nil
+	}
+
+	// a cooldown ending right now still rejects this request
+	return max(time.Until(requestedAt.Add(cooldown)), time.Second), nil
+}
@@ -0,0 +1,20 @@
+package outbox
+
+const (
+	InsertMessage = `
+		INSERT INTO event_outbox (event_id, event_type, body)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (event_id) DO NOTHING
+	`
+
+	SelectOldestMessages = `
+		SELECT id, event_id, event_type, body, created_at
+		FROM event_outbox
+		ORDER BY id
+		LIMIT $1
+	`
+
+	DeleteMessages = `DELETE FROM event_outbox WHERE id = ANY($1)`
+
+	CountMessages = `SELECT count(*) FROM event_outbox`
+)
@@ -0,0 +1,69 @@
+package outbox
+
+import (
+	"context"
+
+	"user-manager-api/internal/domain/outbox"
+	"user-manager-api/internal/infrastructure/db/postgres"
+	"user-manager-api/internal/infrastructure/fieldcrypt"
+)
+
+// Repository - the bodies are stored encrypted by cipher
+type Repository struct {
+	db     postgres.DB
+	cipher *fieldcrypt.Cipher
+}
+
+func NewRepository(db postgres.DB, cipher *fieldcrypt.Cipher) outbox.Repository {
+	return &Repository{db: db, cipher: cipher}
+}
+
+func (r *Repository) Add(ctx context.Context, m outbox.Message) error {
+	body, err := r.cipher.Encrypt(ctx, string(m.Body))
+	if err != nil {
+		return err
+	}
+	_, err = r.db.Exec(ctx, InsertMessage, m.EventID, m.EventType, body)
+	return err
+}
+
+func (r *Repository) FetchOldest(ctx context.Context, limit int) ([]outbox.Message, error) {
+	rows, err := r.db.Query(ctx, SelectOldestMessages, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ms []outbox.Message
+	for rows.Next() {
+		var (
+			m    outbox.Message
+			body string
+		)
+		if err = rows.Scan(&m.ID, &m.EventID, &m.EventType, &body, &m.CreatedAt); err != nil {
+			return nil, err
+		}
+		plain, err := r.cipher.Decrypt(ctx, body)
+		if err != nil {
+			return nil, err
+		}
+		m.Body = []byte(plain)
+		ms = append(ms, m)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return ms, nil
+}
+
+func (r *Repository) Delete(ctx context.Context, ids []int64) error {
+	_, err := r.db.Exec(ctx, DeleteMessages, ids)
+	return err
+}
+
+func (r *Repository) Count(ctx context.Context) (int64, error) {
+	var n int64
+	err := r.db.QueryRow(ctx, CountMessages).Scan(&n)
+	return n, err
+}
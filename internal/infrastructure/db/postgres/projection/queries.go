@@ -0,0 +1,92 @@
+package projection
+
+// project - the read models changes of one event, made only if the "gate" CTE
+// has a row:
+// $1 user_uuid(NULL - none), $2 occurred_at, $3 signed_up_at, $4 updated_at,
+// $5 deleted_at, $6 deleted_reason, $7 merged_into, $8 email_changes,
+// $9 files_delta, $10 files_reset, $11 file_changes, $12 logins, $13 failed_logins
+const project = `
+	summary AS (
+		INSERT INTO projection_user_summaries AS s (
+			user_uuid, signed_up_at, updated_at, deleted_at, deleted_reason, merged_into,
+			email_changes, logins, failed_logins, last_login_at, last_event_at
+		)
+		SELECT $1::uuid, $3::timestamptz, $4::timestamptz, $5::timestamptz, $6::text, $7::uuid,
+		       $8::int, $12::int, $13::int, CASE WHEN $12::int > 0 THEN $2::timestamptz END, $2::timestamptz
+		FROM gate
+		WHERE $1::uuid IS NOT NULL
+		ON CONFLICT (user_uuid) DO UPDATE
+		SET signed_up_at   = COALESCE(s.signed_up_at, EXCLUDED.signed_up_at),
+		    updated_at     = GREATEST(s.updated_at, EXCLUDED.updated_at),
+		    deleted_at     = COALESCE(EXCLUDED.deleted_at, s.deleted_at),
+		    deleted_reason = CASE WHEN EXCLUDED.deleted_at IS NULL THEN s.deleted_reason ELSE EXCLUDED.deleted_reason END,
+		    merged_into    = COALESCE(EXCLUDED.merged_into, s.merged_into),
+		    email_changes  = s.email_changes + EXCLUDED.email_changes,
+		    logins         = s.logins + EXCLUDED.logins,
+		    failed_logins  = s.failed_logins + EXCLUDED.failed_logins,
+		    last_login_at  = GREATEST(s.last_login_at, EXCLUDED.last_login_at),
+		    last_event_at  = GREATEST(s.last_event_at, EXCLUDED.last_event_at)
+	),
+	files AS (
+		INSERT INTO projection_user_files AS f (user_uuid, files_count, changes, last_changed_at)
+		SELECT $1::uuid, GREATEST($9::bigint, 0), $11::int, $2::timestamptz
+		FROM gate
+		WHERE $1::uuid IS NOT NULL AND ($11::int > 0 OR $10::boolean)
+		ON CONFLICT (user_uuid) DO UPDATE
+		SET files_count     = GREATEST(CASE WHEN $10::boolean THEN 0 ELSE f.files_count END + $9::bigint, 0),
+		    changes         = f.changes + EXCLUDED.changes,
+		    last_changed_at = GREATEST(f.last_changed_at, EXCLUDED.last_changed_at)
+	),
+	activity AS (
+		INSERT INTO projection_user_activity AS a (user_uuid, day, events, logins, failed_logins)
+		SELECT $1::uuid, ($2::timestamptz AT TIME ZONE 'UTC')::date, 1, $12::int, $13::int
+		FROM gate
+		WHERE $1::uuid IS NOT NULL
+		ON CONFLICT (user_uuid, day) DO UPDATE
+		SET events        = a.events + 1,
+		    logins        = a.logins + EXCLUDED.logins,
+		    failed_logins = a.failed_logins + EXCLUDED.failed_logins
+	)
+`
+
+const (
+	// AppendEvent - the event is projected only if it is not stored yet:
+	// $14 event_id, $15 event_type, $16 meta
+	AppendEvent = `
+		WITH gate AS (
+			INSERT INTO event_store (event_id, event_type, user_uuid, occurred_at, meta)
+			VALUES ($14, $15, $1, $2, $16)
+			ON CONFLICT (event_id) DO NOTHING
+			RETURNING seq
+		),` + project + `
+		SELECT count(*) FROM gate
+	`
+	// ProjectEvent - a stored event, on a rebuild
+	ProjectEvent = `WITH gate AS (SELECT 1),` + project + `SELECT 1`
+
+	// LockEventStore - the appends wait for the rebuild, the reads do not
+	LockEventStore      = `LOCK TABLE event_store IN SHARE MODE`
+	TruncateProjections = `TRUNCATE projection_user_summaries, projection_user_files, projection_user_activity`
+	SelectEvents        = `
+		SELECT seq, event_id, event_type, COALESCE(user_uuid, '00000000-0000-0000-0000-000000000000'), occurred_at, meta
+		FROM event_store
+		WHERE seq > $1
+		ORDER BY seq
+		LIMIT $2
+	`
+
+	SelectUserSummary = `
+		SELECT s.user_uuid, s.signed_up_at, s.updated_at, s.deleted_at, s.deleted_reason, s.merged_into,
+		       s.email_changes, s.logins, s.failed_logins, s.last_login_at, s.last_event_at,
+		       COALESCE(f.files_count, 0), COALESCE(f.changes, 0), f.last_changed_at
+		FROM projection_user_summaries s
+		LEFT JOIN projection_user_files f ON f.user_uuid = s.user_uuid
+		WHERE s.user_uuid = $1
+	`
+	SelectUserActivity = `
+		SELECT day, events, logins, failed_logins
+		FROM projection_user_activity
+		WHERE user_uuid = $1 AND day >= ($2::timestamptz AT TIME ZONE 'UTC')::date
+		ORDER BY day
+	`
+)
@@ -0,0 +1,162 @@
+package projection
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"user-manager-api/internal/domain/projection"
+	"user-manager-api/internal/infrastructure/db/postgres"
+)
+
+// rebuildBatchSize - the events read and projected per round trip
+const rebuildBatchSize = 1000
+
+type Repository struct {
+	db postgres.DB
+	// pool - the transaction of the rebuild
+	pool *pgxpool.Pool
+}
+
+func NewRepository(db postgres.DB, pool *pgxpool.Pool) projection.Repository {
+	return &Repository{db: db, pool: pool}
+}
+
+func (r *Repository) Append(ctx context.Context, e projection.Event, d projection.Delta) (bool, error) {
+	args := append(projectArgs(e, d), e.ID, e.Type, e.Meta)
+
+	var stored int
+	if err := r.db.QueryRow(ctx, AppendEvent, args...).Scan(&stored); err != nil {
+		return false, err
+	}
+
+	return stored > 0, nil
+}
+
+func (r *Repository) Rebuild(ctx context.Context, fold func(projection.Event) projection.Delta) (int64, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if _, err = tx.Exec(ctx, LockEventStore); err != nil {
+		return 0, err
+	}
+	if _, err = tx.Exec(ctx, TruncateProjections); err != nil {
+		return 0, err
+	}
+
+	var after, folded int64
+	for {
+		events, err := fetchEvents(ctx, tx, after)
+		if err != nil {
+			return 0, err
+		}
+		if len(events) == 0 {
+			break
+		}
+
+		b := new(pgx.Batch)
+		for _, e := range events {
+			if e.Touches() {
+				b.Queue(ProjectEvent, projectArgs(e, fold(e))...)
+			}
+		}
+		if err = tx.SendBatch(ctx, b).Close(); err != nil {
+			return 0, err
+		}
+		after = events[len(events)-1].Seq
+		folded += int64(len(events))
+	}
+
+	return folded, tx.Commit(ctx)
+}
+
+func (r *Repository) FetchUserSummary(ctx context.Context, userUUID uuid.UUID, from time.Time) (*projection.UserSummary, error) {
+	s := new(projection.UserSummary)
+	if err := r.db.QueryRow(ctx, SelectUserSummary, userUUID).Scan(
+		&s.UserUUID,
+		&s.SignedUpAt,
+		&s.UpdatedAt,
+		&s.DeletedAt,
+		&s.DeletedReason,
+		&s.MergedInto,
+		&s.EmailChanges,
+		&s.Logins,
+		&s.FailedLogins,
+		&s.LastLoginAt,
+		&s.LastEventAt,
+		&s.Files.Count,
+		&s.Files.Changes,
+		&s.Files.LastChangedAt,
+	); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	rows, err := r.db.Query(ctx, SelectUserActivity, userUUID, from)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var a projection.DailyActivity
+		if err = rows.Scan(&a.Day, &a.Events, &a.Logins, &a.FailedLogins); err != nil {
+			return nil, err
+		}
+		s.Activity = append(s.Activity, a)
+	}
+
+	return s, rows.Err()
+}
+
+func fetchEvents(ctx context.Context, tx pgx.Tx, after int64) (projection.Events, error) {
+	rows, err := tx.Query(ctx, SelectEvents, after, rebuildBatchSize)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events projection.Events
+	for rows.Next() {
+		var e projection.Event
+		if err = rows.Scan(&e.Seq, &e.ID, &e.Type, &e.UserUUID, &e.OccurredAt, &e.Meta); err != nil {
+			return nil, err
+		}
+		events = append(events, e)
+	}
+
+	return events, rows.Err()
+}
+
+// projectArgs - $1-$13 of the project CTEs
+func projectArgs(e projection.Event, d projection.Delta) []any {
+	var user *uuid.UUID
+	if e.Touches() {
+		user = &e.UserUUID
+	}
+
+	return []any{
+		user,
+		e.OccurredAt,
+		d.SignedUpAt,
+		d.UpdatedAt,
+		d.DeletedAt,
+		d.DeletedReason,
+		d.MergedInto,
+		d.EmailChanges,
+		d.FilesDelta,
+		d.FilesReset,
+		d.FileChanges,
+		d.Logins,
+		d.FailedLogins,
+	}
+}
@@ -0,0 +1,124 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+
+	"user-manager-api/config"
+	"user-manager-api/pkg/retry"
+)
+
+type (
+	// retryDB retries the statements failed with transient errors(failover,
+	// serialization conflicts) with full jitter(pkg/retry). Only the errors which guarantee
+	// the statement had no effect are retried, so writes are never applied twice.
+	// Errors while reading the rows of Query are not retried.
+	retryDB struct {
+		db       DB
+		logger   *zap.Logger
+		policy   retry.Policy
+		mCounter *prometheus.CounterVec
+	}
+	retryRow struct {
+		r    *retryDB
+		ctx  context.Context
+		sql  string
+		args []any
+	}
+)
+
+// WithRetry - POSTGRES_MAX_RETRIES=0 returns db as is
+func WithRetry(db DB, logger *zap.Logger, cfg config.DB, mCounter *prometheus.CounterVec) DB {
+	if cfg.MaxRetries <= 0 {
+		return db
+	}
+	return &retryDB{
+		db:       db,
+		logger:   logger,
+		policy:   retry.Policy{MaxRetries: cfg.MaxRetries, BaseDelay: cfg.RetryBaseDelay, Retryable: IsRetryable},
+		mCounter: mCounter,
+	}
+}
+
+func (r *retryDB) Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	var tag pgconn.CommandTag
+	err := r.do(ctx, "exec", func() error {
+		var err error
+		tag, err = r.db.Exec(ctx, sql, args...)
+		return err
+	})
+
+	return tag, err
+}
+
+func (r *retryDB) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	var rows pgx.Rows
+	err := r.do(ctx, "query", func() error {
+		var err error
+		rows, err = r.db.Query(ctx, sql, args...)
+		return err
+	})
+
+	return rows, err
+}
+
+// QueryRow - the error of a row comes with Scan, so the statement runs there
+func (r *retryDB) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	return &retryRow{r: r, ctx: ctx, sql: sql, args: args}
+}
+
+func (rr *retryRow) Scan(dest ...any) error {
+	return rr.r.do(rr.ctx, "query_row", func() error {
+		return rr.r.db.QueryRow(rr.ctx, rr.sql, rr.args...).Scan(dest...)
+	})
+}
+
+func (r *retryDB) do(ctx context.Context, op string, fn func() error) error {
+	p := r.policy
+	p.OnRetry = func(attempt int, _ time.Duration, err error) {
+		if r.mCounter != nil {
+			r.mCounter.WithLabelValues("db_retries_total").Inc()
+		}
+		r.logger.Warn("db statement failed",
+			zap.String("op", op),
+			zap.Int("attempt", attempt),
+			zap.Error(err),
+		)
+	}
+
+	return p.Do(ctx, func(context.Context) error { return fn() })
+}
+
+// IsRetryable - the statement failed without effect and may succeed again:
+// nothing was sent to the server, the connection could not be established,
+// the transaction was rolled back on a conflict or the server is shutting down
+func IsRetryable(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch pgErr.Code {
+		case "40001", // serialization_failure
+			"40P01", // deadlock_detected
+			"57P01", // admin_shutdown
+			"57P02", // crash_shutdown
+			"57P03": // cannot_connect_now
+			return true
+		}
+		// connection_exception class
+		return len(pgErr.Code) == 5 && pgErr.Code[:2] == "08"
+	}
+	var connErr *pgconn.ConnectError
+	if errors.As(err, &connErr) {
+		return true
+	}
+
+	return pgconn.SafeToRetry(err)
+}
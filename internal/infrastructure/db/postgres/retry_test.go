@@ -0,0 +1,126 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"user-manager-api/config"
+)
+
+// flakyDB fails with errs in turn, then succeeds
+type (
+	flakyDB struct {
+		errs  []error
+		calls int
+	}
+	errRow struct{ err error }
+)
+
+func (f *flakyDB) next() error {
+	f.calls++
+	if f.calls <= len(f.errs) {
+		return f.errs[f.calls-1]
+	}
+	return nil
+}
+
+func (f *flakyDB) Exec(context.Context, string, ...any) (pgconn.CommandTag, error) {
+	return pgconn.CommandTag{}, f.next()
+}
+
+func (f *flakyDB) Query(context.Context, string, ...any) (pgx.Rows, error) {
+	if err := f.next(); err != nil {
+		return nil, err
+	}
+	return fakeRows{}, nil
+}
+
+func (f *flakyDB) QueryRow(context.Context, string, ...any) pgx.Row {
+	return errRow{err: f.next()}
+}
+
+func (r errRow) Scan(...any) error { return r.err }
+
+func newRetryDB(f *flakyDB) DB {
+	mCounter := prometheus.NewCounterVec(prometheus.CounterOpts{Name: "test"}, []string{"result"})
+	return WithRetry(f, zap.NewNop(), config.DB{MaxRetries: 2, RetryBaseDelay: time.Millisecond}, mCounter)
+}
+
+func TestWithRetry(t *testing.T) {
+	ctx := context.Background()
+	serialization := &pgconn.PgError{Code: "40001"}
+	unique := &pgconn.PgError{Code: "23505"}
+
+	type tc struct {
+		name      string
+		errs      []error
+		call      func(db DB) error
+		wantCalls int
+		wantErr   error
+	}
+	exec := func(db DB) error { _, err := db.Exec(ctx, ""); return err }
+	query := func(db DB) error { _, err := db.Query(ctx, ""); return err }
+	queryRow := func(db DB) error { return db.QueryRow(ctx, "").Scan() }
+	cases := []tc{
+		{"exec retried", []error{serialization}, exec, 2, nil},
+		{"query retried", []error{serialization, serialization}, query, 3, nil},
+		{"query row retried on scan", []error{serialization}, queryRow, 2, nil},
+		{"attempts exhausted", []error{serialization, serialization, serialization}, exec, 3, serialization},
+		{"not retryable", []error{unique}, exec, 1, unique},
+		{"no rows", []error{pgx.ErrNoRows}, queryRow, 1, pgx.ErrNoRows},
+	}
+
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			f := &flakyDB{errs: tt.errs}
+			err := tt.call(newRetryDB(f))
+			assert.Equal(t, tt.wantCalls, f.calls)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+
+	t.Run("disabled", func(t *testing.T) {
+		f := &flakyDB{}
+		assert.Same(t, f, WithRetry(f, zap.NewNop(), config.DB{}, nil))
+	})
+}
+
+func TestIsRetryable(t *testing.T) {
+	type tc struct {
+		name string
+		err  error
+		want bool
+	}
+	cases := []tc{
+		{"serialization failure", &pgconn.PgError{Code: "40001"}, true},
+		{"deadlock", fmt.Errorf("wrapped: %w", &pgconn.PgError{Code: "40P01"}), true},
+		{"admin shutdown", &pgconn.PgError{Code: "57P01"}, true},
+		{"connection exception", &pgconn.PgError{Code: "08006"}, true},
+		{"connect failed", &pgconn.ConnectError{}, true},
+		{"unique violation", &pgconn.PgError{Code: "23505"}, false},
+		{"deadline", context.DeadlineExceeded, false},
+		{"other", errors.New("boom"), false},
+	}
+
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, IsRetryable(tt.err))
+		})
+	}
+}
@@ -0,0 +1,45 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+const selectSchemaVersion = `SELECT COALESCE(max(version), 0) FROM schema_migrations`
+
+// SchemaVersion - the latest applied migration(see migrations.Version), 0 for
+// a database migrated before schema_migrations
+func SchemaVersion(ctx context.Context, db DB) (int64, error) {
+	var version int64
+	err := db.QueryRow(ctx, selectSchemaVersion).Scan(&version)
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "42P01" {
+		// undefined_table
+		return 0, nil
+	}
+	return version, err
+}
+
+// an index left invalid by a failed CREATE INDEX CONCURRENTLY serves nothing
+const selectMissingIndexes = `
+	SELECT COALESCE(array_agg(n ORDER BY n), '{}')
+	FROM unnest($1::text[]) n
+	WHERE NOT EXISTS (
+	    SELECT 1 FROM pg_class c
+	    JOIN pg_index i ON i.indexrelid = c.oid
+	    WHERE c.relname = n
+	      AND c.relnamespace = current_schema()::regnamespace
+	      AND i.indisvalid
+	)`
+
+// MissingIndexes - the names of the indexes absent from the current schema or
+// invalid, sorted
+func MissingIndexes(ctx context.Context, db DB, names []string) ([]string, error) {
+	var missing []string
+	err := db.QueryRow(ctx, selectMissingIndexes, names).Scan(&missing)
+
+	return missing, err
+}
@@ -0,0 +1,50 @@
+package stats
+
+const (
+	RefreshUserFileCounts = `
+		INSERT INTO user_file_counts (user_id, files_count, total_bytes, updated_at)
+		SELECT u.id, count(f.id), COALESCE(sum(f.size_bytes), 0), now()
+		FROM users u
+		LEFT JOIN user_files f ON f.user_id = u.id AND f.deleted_at IS NULL
+		WHERE u.uuid = $1
+		GROUP BY u.id
+		ON CONFLICT (user_id) DO UPDATE
+		SET files_count = EXCLUDED.files_count, total_bytes = EXCLUDED.total_bytes, updated_at = EXCLUDED.updated_at
+	`
+	// $1 - UTC day
+	RefreshDailySignups = `
+		INSERT INTO daily_signup_counts (day, signups, updated_at)
+		SELECT $1::date, count(*), now()
+		FROM users
+		WHERE created_at >= ($1::date AT TIME ZONE 'UTC') AND created_at < (($1::date + 1) AT TIME ZONE 'UTC')
+		ON CONFLICT (day) DO UPDATE
+		SET signups = EXCLUDED.signups, updated_at = EXCLUDED.updated_at
+	`
+	RebuildUserFileCounts = `
+		INSERT INTO user_file_counts (user_id, files_count, total_bytes, updated_at)
+		SELECT u.id, count(f.id), COALESCE(sum(f.size_bytes), 0), now()
+		FROM users u
+		LEFT JOIN user_files f ON f.user_id = u.id AND f.deleted_at IS NULL
+		GROUP BY u.id
+		ON CONFLICT (user_id) DO UPDATE
+		SET files_count = EXCLUDED.files_count, total_bytes = EXCLUDED.total_bytes, updated_at = EXCLUDED.updated_at
+	`
+	RebuildDailySignups = `
+		INSERT INTO daily_signup_counts (day, signups, updated_at)
+		SELECT (created_at AT TIME ZONE 'UTC')::date, count(*), now()
+		FROM users
+		GROUP BY 1
+		ON CONFLICT (day) DO UPDATE
+		SET signups = EXCLUDED.signups, updated_at = EXCLUDED.updated_at
+	`
+	SelectDailySignups = `
+		SELECT d::date, COALESCE(c.signups, 0)
+		FROM generate_series($1::date, $2::date, interval '1 day') d
+		LEFT JOIN daily_signup_counts c ON c.day = d::date
+		ORDER BY 1
+	`
+	SelectFilesTotals = `
+		SELECT count(*) FILTER (WHERE files_count > 0), COALESCE(sum(files_count), 0), COALESCE(sum(total_bytes), 0)
+		FROM user_file_counts
+	`
+)
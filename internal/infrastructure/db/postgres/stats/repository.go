@@ -0,0 +1,74 @@
+package stats
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"user-manager-api/internal/domain/stats"
+	"user-manager-api/internal/infrastructure/db/postgres"
+)
+
+type Repository struct {
+	db postgres.DB
+}
+
+func NewRepository(db postgres.DB) stats.Repository {
+	return &Repository{db: db}
+}
+
+func (r *Repository) RefreshUserFileCounts(ctx context.Context, userUUID uuid.UUID) error {
+	_, err := r.db.Exec(ctx, RefreshUserFileCounts, userUUID)
+	return err
+}
+
+func (r *Repository) RefreshDailySignups(ctx context.Context, day time.Time) error {
+	_, err := r.db.Exec(ctx, RefreshDailySignups, utcDay(day))
+	return err
+}
+
+func (r *Repository) Rebuild(ctx context.Context) error {
+	if _, err := r.db.Exec(ctx, RebuildUserFileCounts); err != nil {
+		return err
+	}
+	_, err := r.db.Exec(ctx, RebuildDailySignups)
+	return err
+}
+
+func (r *Repository) FetchDailySignups(ctx context.Context, from, to time.Time) ([]stats.DailySignups, error) {
+	rows, err := r.db.Query(ctx, SelectDailySignups, utcDay(from), utcDay(to))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []stats.DailySignups
+	for rows.Next() {
+		var d stats.DailySignups
+		if err = rows.Scan(&d.Day, &d.Count); err != nil {
+			return nil, err
+		}
+		out = append(out, d)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+func (r *Repository) FetchFilesTotals(ctx context.Context) (*stats.FilesTotals, error) {
+	t := new(stats.FilesTotals)
+	if err := r.db.QueryRow(ctx, SelectFilesTotals).Scan(&t.UsersWithFiles, &t.FilesCount, &t.TotalBytes); err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+// utcDay - the date codec takes the calendar day of the time's own location
+func utcDay(t time.Time) time.Time {
+	y, m, d := t.UTC().Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,72 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// DB - what the repositories use of the pool
+type DB interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+// WithQueryTimeout bounds every statement by timeout on top of the caller's
+// deadline(the shorter one wins), so a slow query can not hold a request open.
+// The timeout of Query covers the reading of the rows, until Close.
+func WithQueryTimeout(db DB, timeout time.Duration) DB {
+	if timeout <= 0 {
+		return db
+	}
+	return &timeoutDB{db: db, timeout: timeout}
+}
+
+type (
+	timeoutDB struct {
+		db      DB
+		timeout time.Duration
+	}
+	timeoutRows struct {
+		pgx.Rows
+		cancel context.CancelFunc
+	}
+	timeoutRow struct {
+		pgx.Row
+		cancel context.CancelFunc
+	}
+)
+
+func (t *timeoutDB) Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	ctx, cancel := context.WithTimeout(ctx, t.timeout)
+	defer cancel()
+	return t.db.Exec(ctx, sql, args...)
+}
+
+func (t *timeoutDB) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	ctx, cancel := context.WithTimeout(ctx, t.timeout)
+	rows, err := t.db.Query(ctx, sql, args...)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	return &timeoutRows{Rows: rows, cancel: cancel}, nil
+}
+
+func (t *timeoutDB) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	ctx, cancel := context.WithTimeout(ctx, t.timeout)
+	return &timeoutRow{Row: t.db.QueryRow(ctx, sql, args...), cancel: cancel}
+}
+
+func (r *timeoutRows) Close() {
+	r.Rows.Close()
+	r.cancel()
+}
+
+func (r *timeoutRow) Scan(dest ...any) error {
+	defer r.cancel()
+	return r.Row.Scan(dest...)
+}
@@ -0,0 +1,85 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type (
+	fakeDB struct {
+		ctx context.Context
+	}
+	fakeRows struct{ pgx.Rows }
+	fakeRow  struct{}
+)
+
+func (f *fakeDB) Exec(ctx context.Context, _ string, _ ...any) (pgconn.CommandTag, error) {
+	f.ctx = ctx
+	return pgconn.CommandTag{}, ctx.Err()
+}
+
+func (f *fakeDB) Query(ctx context.Context, _ string, _ ...any) (pgx.Rows, error) {
+	f.ctx = ctx
+	return fakeRows{}, nil
+}
+
+func (f *fakeDB) QueryRow(ctx context.Context, _ string, _ ...any) pgx.Row {
+	f.ctx = ctx
+	return fakeRow{}
+}
+
+func (fakeRows) Close()           {}
+func (fakeRows) Err() error       { return nil }
+func (fakeRow) Scan(...any) error { return nil }
+
+func TestWithQueryTimeout(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("disabled", func(t *testing.T) {
+		f := &fakeDB{}
+		assert.Same(t, f, WithQueryTimeout(f, 0))
+	})
+
+	t.Run("exec", func(t *testing.T) {
+		f := &fakeDB{}
+		_, err := WithQueryTimeout(f, time.Minute).Exec(ctx, "")
+		require.NoError(t, err)
+		deadline, ok := f.ctx.Deadline()
+		require.True(t, ok)
+		assert.WithinDuration(t, time.Now().Add(time.Minute), deadline, time.Second)
+		assert.Error(t, f.ctx.Err(), "released after the statement")
+	})
+
+	t.Run("caller deadline is shorter", func(t *testing.T) {
+		f := &fakeDB{}
+		short, cancel := context.WithTimeout(ctx, time.Second)
+		defer cancel()
+		_, _ = WithQueryTimeout(f, time.Minute).Exec(short, "")
+		deadline, _ := f.ctx.Deadline()
+		want, _ := short.Deadline()
+		assert.Equal(t, want, deadline)
+	})
+
+	t.Run("query lives until close", func(t *testing.T) {
+		f := &fakeDB{}
+		rows, err := WithQueryTimeout(f, time.Minute).Query(ctx, "")
+		require.NoError(t, err)
+		assert.NoError(t, f.ctx.Err())
+		rows.Close()
+		assert.Error(t, f.ctx.Err())
+	})
+
+	t.Run("query row lives until scan", func(t *testing.T) {
+		f := &fakeDB{}
+		row := WithQueryTimeout(f, time.Minute).QueryRow(ctx, "")
+		assert.NoError(t, f.ctx.Err())
+		require.NoError(t, row.Scan())
+		assert.Error(t, f.ctx.Err())
+	})
+}
@@ -0,0 +1,175 @@
+package postgres
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"hash/fnv"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+
+	"user-manager-api/internal/domain/stats"
+)
+
+const (
+	// maxTracedQueries - the distinct statements tracked, the later ones are
+	// counted as FingerprintOther: the label of the histogram stays bounded
+	maxTracedQueries = 500
+	FingerprintOther = "other"
+)
+
+type (
+	// QueryTracer - a pgx.QueryTracer of the pool: the latency histogram per
+	// statement, the statements over the threshold are logged with their
+	// arguments redacted to the types(the emails, the hashes stay out of the logs)
+	QueryTracer struct {
+		threshold time.Duration
+		logger    *zap.Logger
+		mDuration *prometheus.HistogramVec
+
+		mu    sync.Mutex
+		stats map[string]*stats.QueryStats
+	}
+	traceKey   struct{}
+	traceStart struct {
+		at   time.Time
+		sql  string
+		args []any
+	}
+)
+
+// NewQueryTracer - threshold 0 disables the log, the stats are kept anyway
+func NewQueryTracer(threshold time.Duration, logger *zap.Logger, mDuration *prometheus.HistogramVec) *QueryTracer {
+	return &QueryTracer{
+		threshold: threshold,
+		logger:    logger,
+		mDuration: mDuration,
+		stats:     make(map[string]*stats.QueryStats),
+	}
+}
+
+func (t *QueryTracer) TraceQueryStart(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	return context.WithValue(ctx, traceKey{}, traceStart{at: time.Now(), sql: data.SQL, args: data.Args})
+}
+
+func (t *QueryTracer) TraceQueryEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryEndData) {
+	start, ok := ctx.Value(traceKey{}).(traceStart)
+	if !ok {
+		return
+	}
+	d := time.Since(start.at)
+	slow := t.threshold > 0 && d >= t.threshold
+
+	sql := normalizeSQL(start.sql)
+	fingerprint := t.record(sql, d, slow)
+	t.mDuration.WithLabelValues(fingerprint).Observe(d.Seconds())
+	if !slow {
+		return
+	}
+
+	fields := []zap.Field{
+		zap.String("fingerprint", fingerprint),
+		zap.String("sql", sql),
+		zap.Strings("args", redactArgs(start.args)),
+		zap.Duration("duration", d),
+		zap.Int64("rows", data.CommandTag.RowsAffected()),
+	}
+	if data.Err != nil {
+		fields = append(fields, zap.Error(data.Err))
+	}
+	t.logger.Warn("slow query", fields...)
+}
+
+// record - the fingerprint sql is counted under
+func (t *QueryTracer) record(sql string, d time.Duration, slow bool) string {
+	fingerprint := Fingerprint(sql)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	s, ok := t.stats[fingerprint]
+	if !ok {
+		if len(t.stats) >= maxTracedQueries {
+			fingerprint, sql = FingerprintOther, ""
+			s, ok = t.stats[fingerprint]
+		}
+		if !ok {
+			s = &stats.QueryStats{Fingerprint: fingerprint, SQL: sql}
+			t.stats[fingerprint] = s
+		}
+	}
+	s.Calls++
+	s.Total += d
+	s.Max = max(s.Max, d)
+	if slow {
+		now := time.Now().UTC()
+		s.Slow++
+		s.LastSlowAt = &now
+	}
+
+	return fingerprint
+}
+
+// SlowQueries - up to limit statements, the ones slow most often first, then
+// the slowest; the statements never over the threshold are left out
+func (t *QueryTracer) SlowQueries(limit int) []stats.QueryStats {
+	t.mu.Lock()
+	out := make([]stats.QueryStats, 0, len(t.stats))
+	for _, s := range t.stats {
+		if s.Slow > 0 {
+			out = append(out, *s)
+		}
+	}
+	t.mu.Unlock()
+
+	slices.SortFunc(out, func(a, b stats.QueryStats) int {
+		return cmp.Or(
+			cmp.Compare(b.Slow, a.Slow),
+			cmp.Compare(b.Max, a.Max),
+			strings.Compare(a.Fingerprint, b.Fingerprint),
+		)
+	})
+	if limit > 0 && len(out) > limit {
+		out = out[:limit]
+	}
+
+	return out
+}
+
+// Threshold - the duration a statement is slow from, 0 - the log is off
+func (t *QueryTracer) Threshold() time.Duration { return t.threshold }
+
+// Fingerprint - the id of a normalized statement, the same for its every call
+func Fingerprint(sql string) string {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(sql))
+
+	return strconv.FormatUint(h.Sum64(), 16)
+}
+
+// normalizeSQL - the statement on a line: the queries of the repositories are
+// indented raw strings
+func normalizeSQL(sql string) string {
+	return strings.Join(strings.Fields(sql), " ")
+}
+
+// redactArgs - the types of the arguments, nil ones are told apart
+func redactArgs(args []any) []string {
+	out := make([]string, len(args))
+	for i, a := range args {
+		if a == nil {
+			out[i] = "nil"
+			continue
+		}
+		out[i] = fmt.Sprintf("%T", a)
+	}
+
+	return out
+}
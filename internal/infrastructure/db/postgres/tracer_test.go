@@ -0,0 +1,87 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+const (
+	tracedSelect = `
+		SELECT id FROM users
+		WHERE email = $1 AND deleted_at IS NULL`
+	tracedUpdate = `UPDATE users SET name = $1 WHERE id = $2`
+)
+
+// trace - a statement of d as the pool reports it
+func trace(tr *QueryTracer, sql string, d time.Duration, args ...any) {
+	ctx := tr.TraceQueryStart(context.Background(), nil, pgx.TraceQueryStartData{SQL: sql, Args: args})
+	start := ctx.Value(traceKey{}).(traceStart)
+	start.at = start.at.Add(-d)
+	tr.TraceQueryEnd(context.WithValue(ctx, traceKey{}, start), nil, pgx.TraceQueryEndData{CommandTag: pgconn.NewCommandTag("SELECT 1")})
+}
+
+func TestQueryTracer(t *testing.T) {
+	core, logs := observer.New(zapcore.WarnLevel)
+	mDuration := prometheus.NewHistogramVec(prometheus.HistogramOpts{Name: "test"}, []string{"query"})
+	tr := NewQueryTracer(100*time.Millisecond, zap.New(core), mDuration)
+
+	trace(tr, tracedSelect, time.Millisecond, "jane@example.com")
+	trace(tr, tracedSelect, 300*time.Millisecond, "john@example.com")
+	trace(tr, tracedUpdate, 200*time.Millisecond, "John", int64(42))
+	trace(tr, tracedUpdate, 150*time.Millisecond, nil, int64(42))
+	trace(tr, `SELECT 1`, time.Millisecond)
+
+	selectSQL := "SELECT id FROM users WHERE email = $1 AND deleted_at IS NULL"
+	require.Equal(t, 3, logs.Len(), "the slow ones")
+	entry := logs.All()[0]
+	assert.Equal(t, "slow query", entry.Message)
+	fields := entry.ContextMap()
+	assert.Equal(t, selectSQL, fields["sql"])
+	assert.Equal(t, Fingerprint(selectSQL), fields["fingerprint"])
+	assert.Equal(t, []any{"string"}, fields["args"], "the email is redacted")
+	assert.Equal(t, []any{"nil", "int64"}, logs.All()[2].ContextMap()["args"])
+
+	got := tr.SlowQueries(0)
+	require.Len(t, got, 2, "SELECT 1 is never slow")
+	assert.Equal(t, tracedUpdate, got[0].SQL, "slow most often first")
+	assert.Equal(t, int64(2), got[0].Slow)
+	assert.Equal(t, 200*time.Millisecond, got[0].Max.Round(time.Millisecond))
+	assert.Equal(t, selectSQL, got[1].SQL)
+	assert.Equal(t, int64(2), got[1].Calls)
+	assert.Equal(t, int64(1), got[1].Slow)
+	assert.NotNil(t, got[1].LastSlowAt)
+	assert.Len(t, tr.SlowQueries(1), 1)
+
+	assert.Equal(t, 3, testutil.CollectAndCount(mDuration))
+
+	t.Run("log off", func(t *testing.T) {
+		core, logs := observer.New(zapcore.WarnLevel)
+		tr := NewQueryTracer(0, zap.New(core), mDuration)
+		trace(tr, tracedSelect, time.Hour, "jane@example.com")
+		assert.Zero(t, logs.Len())
+		assert.Empty(t, tr.SlowQueries(0))
+	})
+
+	t.Run("bounded", func(t *testing.T) {
+		tr := NewQueryTracer(time.Millisecond, zap.NewNop(), mDuration)
+		for i := range maxTracedQueries + 10 {
+			trace(tr, tracedSelect+" LIMIT "+time.Duration(i).String(), time.Second)
+		}
+		got := tr.SlowQueries(0)
+		assert.Len(t, got, maxTracedQueries+1)
+		assert.Equal(t, FingerprintOther, got[0].Fingerprint)
+		assert.Equal(t, int64(10), got[0].Slow)
+		assert.Empty(t, got[0].SQL)
+	})
+}
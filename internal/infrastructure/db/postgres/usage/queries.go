@@ -0,0 +1,99 @@
+package usage
+
+const (
+	// the rows of one statement must not repeat a key: the rollups are
+	// aggregated per key before
+	AddRollups = `
+		INSERT INTO usage_rollups (day, org, role, requests, duration_ms)
+		SELECT r.day, r.org, r.role, r.requests, r.duration_ms
+		FROM unnest($1::date[], $2::text[], $3::text[], $4::bigint[], $5::bigint[]) AS r (day, org, role, requests, duration_ms)
+		ON CONFLICT (day, org, role) DO UPDATE
+		SET requests    = usage_rollups.requests + EXCLUDED.requests,
+		    duration_ms = usage_rollups.duration_ms + EXCLUDED.duration_ms,
+		    updated_at  = now()
+	`
+
+	SelectUserEmails = `
+		SELECT uuid, email
+		FROM users
+		WHERE uuid = ANY($1)
+	`
+
+	// $3 - the organization, empty for all
+	SelectTotals = `
+		SELECT org, role, sum(requests)::bigint, sum(duration_ms)::bigint
+		FROM usage_rollups
+		WHERE day BETWEEN $1 AND $2 AND ($3 = '' OR org = $3)
+		GROUP BY org, role
+		ORDER BY sum(requests) DESC, org, role
+	`
+
+	// $1 - the first day of the month. An organization is the email domain,
+	// as user.Organization: the part after the last "@", lowercased.
+	// The storage is of the files not deleted at the end of the month.
+	RefreshMonthly = `
+		WITH bounds AS (
+			SELECT $1::date AT TIME ZONE 'UTC' AS month_start,
+			       ($1::date + interval '1 month') AT TIME ZONE 'UTC' AS month_end
+		)
+		INSERT INTO usage_monthly (month, org, active_users, storage_bytes, api_calls, generated_at)
+		SELECT $1::date, s.org, sum(s.active_users), sum(s.storage_bytes), sum(s.api_calls), now()
+		FROM (
+			SELECT lower(substring(u.email FROM '[^@]*$')) AS org, count(*) AS active_users, 0::bigint AS storage_bytes, 0::bigint AS api_calls
+			FROM users u, bounds b
+			WHERE u.created_at < b.month_end AND (u.deleted_at IS NULL OR u.deleted_at >= b.month_start)
+			GROUP BY 1
+			UNION ALL
+			SELECT lower(substring(u.email FROM '[^@]*$')), 0, sum(f.size_bytes), 0
+			FROM user_files f
+			JOIN users u ON u.id = f.user_id, bounds b
+			WHERE f.created_at < b.month_end AND (f.deleted_at IS NULL OR f.deleted_at >= b.month_end)
+			GROUP BY 1
+			UNION ALL
+			SELECT org, 0, 0, sum(requests)
+			FROM usage_rollups
+			WHERE day >= $1::date AND day < $1::date + interval '1 month'
+			GROUP BY org
+		) s
+		GROUP BY s.org
+		ON CONFLICT (month, org) DO UPDATE
+		SET active_users  = EXCLUDED.active_users,
+		    storage_bytes = EXCLUDED.storage_bytes,
+		    api_calls     = EXCLUDED.api_calls,
+		    generated_at  = EXCLUDED.generated_at
+	`
+
+	SelectMonthly = `
+		SELECT month, org, active_users, storage_bytes, api_calls, generated_at
+		FROM usage_monthly
+		WHERE month BETWEEN $1 AND $2
+		ORDER BY month, org
+	`
+
+	// the active users as users_org_active_idx
+	SelectSeatLimit = `
+		SELECT l.org, l.seats, l.updated_at,
+		       (SELECT count(*) FROM users u WHERE u.deleted_at IS NULL AND lower(substring(u.email FROM '[^@]*$')) = l.org)
+		FROM org_seat_limits l
+		WHERE l.org = $1
+	`
+
+	SelectSeatLimits = `
+		SELECT l.org, l.seats, l.updated_at,
+		       (SELECT count(*) FROM users u WHERE u.deleted_at IS NULL AND lower(substring(u.email FROM '[^@]*$')) = l.org)
+		FROM org_seat_limits l
+		ORDER BY l.org
+	`
+
+	UpsertSeatLimit = `
+		INSERT INTO org_seat_limits (org, seats)
+		VALUES ($1, $2)
+		ON CONFLICT (org) DO UPDATE
+		SET seats = EXCLUDED.seats, updated_at = now()
+	`
+
+	DeleteSeatLimit = `
+		DELETE FROM org_seat_limits
+		WHERE org = $1
+	`
+)
@@ -0,0 +1,168 @@
+package usage
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"user-manager-api/internal/domain/usage"
+	"user-manager-api/internal/domain/user"
+	"user-manager-api/internal/infrastructure/db/postgres"
+)
+
+type Repository struct {
+	db postgres.DB
+}
+
+func NewRepository(db postgres.DB) usage.Repository {
+	return &Repository{db: db}
+}
+
+func (r *Repository) AddRollups(ctx context.Context, rollups []usage.Rollup) error {
+	if len(rollups) == 0 {
+		return nil
+	}
+
+	days := make([]time.Time, len(rollups))
+	orgs := make([]string, len(rollups))
+	roles := make([]string, len(rollups))
+	requests := make([]int64, len(rollups))
+	durations := make([]int64, len(rollups))
+	for i, ru := range rollups {
+		days[i] = utcDay(ru.Day)
+		orgs[i] = ru.Org
+		roles[i] = ru.Role
+		requests[i] = int64(ru.Requests)
+		durations[i] = int64(ru.DurationMs)
+	}
+
+	_, err := r.db.Exec(ctx, AddRollups, days, orgs, roles, requests, durations)
+	return err
+}
+
+func (r *Repository) FetchOrganizations(ctx context.Context, userUUIDs []uuid.UUID) (map[uuid.UUID]string, error) {
+	rows, err := r.db.Query(ctx, SelectUserEmails, userUUIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	orgs := make(map[uuid.UUID]string, len(userUUIDs))
+	for rows.Next() {
+		var (
+			id    uuid.UUID
+			email string
+		)
+		if err = rows.Scan(&id, &email); err != nil {
+			return nil, err
+		}
+		orgs[id] = user.Organization(email)
+	}
+
+	return orgs, rows.Err()
+}
+
+func (r *Repository) FetchTotals(ctx context.Context, from, to time.Time, org string) ([]usage.Total, error) {
+	rows, err := r.db.Query(ctx, SelectTotals, utcDay(from), utcDay(to), org)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []usage.Total
+	for rows.Next() {
+		var t usage.Total
+		if err = rows.Scan(&t.Org, &t.Role, &t.Requests, &t.DurationMs); err != nil {
+			return nil, err
+		}
+		out = append(out, t)
+	}
+
+	return out, rows.Err()
+}
+
+func (r *Repository) RefreshMonthly(ctx context.Context, month time.Time) (int64, error) {
+	tag, err := r.db.Exec(ctx, RefreshMonthly, utcDay(month))
+	if err != nil {
+		return 0, err
+	}
+
+	return tag.RowsAffected(), nil
+}
+
+func (r *Repository) FetchMonthly(ctx context.Context, from, to time.Time) ([]usage.Monthly, error) {
+	rows, err := r.db.Query(ctx, SelectMonthly, utcDay(from), utcDay(to))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []usage.Monthly
+	for rows.Next() {
+		var m usage.Monthly
+		if err = rows.Scan(&m.Month, &m.Org, &m.ActiveUsers, &m.StorageBytes, &m.APICalls, &m.GeneratedAt); err != nil {
+			return nil, err
+		}
+		out = append(out, m)
+	}
+
+	return out, rows.Err()
+}
+
+func (r *Repository) FetchSeatLimit(ctx context.Context, org string) (*usage.SeatLimit, error) {
+	l := new(usage.SeatLimit)
+	err := r.db.QueryRow(ctx, SelectSeatLimit, org).Scan(&l.Org, &l.Seats, &l.UpdatedAt, &l.ActiveUsers)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return l, nil
+}
+
+func (r *Repository) FetchSeatLimits(ctx context.Context) ([]usage.SeatLimit, error) {
+	rows, err := r.db.Query(ctx, SelectSeatLimits)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []usage.SeatLimit
+	for rows.Next() {
+		var l usage.SeatLimit
+		if err = rows.Scan(&l.Org, &l.Seats, &l.UpdatedAt, &l.ActiveUsers); err != nil {
+			return nil, err
+		}
+		out = append(out, l)
+	}
+
+	return out, rows.Err()
+}
+
+func (r *Repository) SaveSeatLimit(ctx context.Context, org string, seats uint32) (*usage.SeatLimit, error) {
+	if _, err := r.db.Exec(ctx, UpsertSeatLimit, org, int64(seats)); err != nil {
+		return nil, err
+	}
+
+	return r.FetchSeatLimit(ctx, org)
+}
+
+func (r *Repository) DeleteSeatLimit(ctx context.Context, org string) (bool, error) {
+	tag, err := r.db.Exec(ctx, DeleteSeatLimit, org)
+	if err != nil {
+		return false, err
+	}
+
+	return tag.RowsAffected() > 0, nil
+}
+
+// utcDay - the date codec takes the calendar day of the time's own location
+func utcDay(t time.Time) time.Time {
+	y, m, d := t.UTC().Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
@@ -2,4 +2,4 @@ package user
 
 import "errors"
 
-var ErrEmailAlreadyExists = errors.New("user email is already exists")
+var ErrUnknownPIIColumn = errors.New("unknown PII column")
@@ -1,36 +1,103 @@
 package user
 
 import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
 	domain "user-manager-api/internal/domain/user"
 )
 
-func fromDBModel(model *User) *domain.User {
-	var u = &domain.User{
+// maxPooledRows - bigger page buffers(the rare big pages) are left to the GC
+const maxPooledRows = 1000
+
+// pagePool - the page buffers of StreamUsers: the rows are mapped into the
+// reused user one by one, so a buffer serves the next page
+var pagePool = sync.Pool{New: func() any { return new([]PageRow) }}
+
+func getPage() *[]PageRow {
+	return pagePool.Get().(*[]PageRow)
+}
+
+func putPage(buf *[]PageRow) {
+	// the pooled rows must not keep the strings of the page alive
+	clear(*buf)
+	if cap(*buf) > maxPooledRows {
+		return
+	}
+	*buf = (*buf)[:0]
+	pagePool.Put(buf)
+}
+
+func (r *Repository) fromDBModel(ctx context.Context, model *User) (*domain.User, error) {
+	u := new(domain.User)
+	if err := r.toDomain(ctx, model, u); err != nil {
+		return nil, err
+	}
+
+	return u, nil
+}
+
+func (r *Repository) toDomain(ctx context.Context, model *User, u *domain.User) error {
+	phone, err := r.cipher.Decrypt(ctx, model.Phone)
+	if err != nil {
+		return fmt.Errorf("decrypt phone of user %s: %w", model.UUID, err)
+	}
+	birthDate, err := r.decryptBirthDate(ctx, model.BirthDate)
+	if err != nil {
+		return fmt.Errorf("decrypt birth date of user %s: %w", model.UUID, err)
+	}
+
+	*u = domain.User{
 		UUID:         model.UUID,
 		Email:        model.Email,
 		PasswordHash: model.PasswordHash,
 		Role:         model.Role,
 		Name:         model.Name,
+		MiddleName:   model.MiddleName,
 		Lastname:     model.Lastname,
-		BirthDate:    model.BirthDate,
-		Phone:        model.Phone,
+		Suffix:       model.Suffix,
+		BirthDate:    birthDate,
+		Phone:        phone,
 
 		CreatedAt: model.CreatedAt,
 		UpdatedAt: model.UpdatedAt,
 
 		DeletedAt:     model.DeletedAt,
-		DeletedReason: model.DeletedReason,
+		DeletedReason: domain.DeletionReason(model.DeletedReason),
 		DeletedBy:     (*domain.ID)(model.DeletedBy),
+
+		PasswordResetRequired: model.PasswordResetRequired,
+		Timezone:              model.Timezone,
+	}
+
+	return nil
+}
+
+// toDBPII encrypts the PII of u, phoneHash - the blind index for phone lookups.
+// Blank(redacted) values stay blank.
+func (r *Repository) toDBPII(ctx context.Context, u domain.User) (birthDate, phone string, phoneHash []byte, err error) {
+	if !u.BirthDate.IsZero() {
+		if birthDate, err = r.cipher.Encrypt(ctx, u.BirthDate.Format(time.DateOnly)); err != nil {
+			return "", "", nil, err
+		}
+	}
+	if u.Phone != "" {
+		if phone, err = r.cipher.Encrypt(ctx, u.Phone); err != nil {
+			return "", "", nil, err
+		}
+		phoneHash = r.cipher.BlindIndex(u.Phone)
 	}
 
-	return u
+	return birthDate, phone, phoneHash, nil
 }
 
-func fromDBModels(models *Users) domain.Users {
-	us := make(domain.Users, len(*models))
-	for idx, u := range *models {
-		us[idx] = fromDBModel(u)
+func (r *Repository) decryptBirthDate(ctx context.Context, value string) (time.Time, error) {
+	plain, err := r.cipher.Decrypt(ctx, value)
+	if err != nil || plain == "" {
+		return time.Time{}, err
 	}
 
-	return us
+	return time.Parse(time.DateOnly, plain)
 }
@@ -0,0 +1,90 @@
+package user
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	domain "user-manager-api/internal/domain/user"
+	"user-manager-api/internal/infrastructure/fieldcrypt"
+)
+
+func newTestRepository(tb testing.TB) *Repository {
+	tb.Helper()
+	key := base64.StdEncoding.EncodeToString([]byte(strings.Repeat("k", 32)))
+	kr, err := fieldcrypt.NewStaticKeyring([]string{"k1:" + key}, "k1")
+	require.NoError(tb, err)
+
+	return &Repository{cipher: fieldcrypt.New(kr, []byte(strings.Repeat("i", 32)))}
+}
+
+func TestToDomain(t *testing.T) {
+	ctx := context.Background()
+	r := newTestRepository(t)
+	phone, err := r.cipher.Encrypt(ctx, "+33612345678")
+	require.NoError(t, err)
+
+	// StreamUsers reuses the user for all the rows
+	var u domain.User
+	require.NoError(t, r.toDomain(ctx, &User{UUID: uuid.New(), Email: "a@example.com", Phone: phone, BirthDate: "1990-01-02"}, &u))
+	assert.Equal(t, "a@example.com", u.Email)
+	assert.Equal(t, "+33612345678", u.Phone)
+	assert.Equal(t, time.Date(1990, 1, 2, 0, 0, 0, 0, time.UTC), u.BirthDate)
+
+	u.Files = &domain.FilesSummary{Count: 1}
+	// redacted, nothing of the previous row is left
+	require.NoError(t, r.toDomain(ctx, &User{UUID: uuid.New(), Email: "b@example.com"}, &u))
+	assert.Equal(t, "b@example.com", u.Email)
+	assert.Empty(t, u.Phone)
+	assert.True(t, u.BirthDate.IsZero())
+	assert.Nil(t, u.Files)
+}
+
+// BenchmarkToDomain - the mapping of a StreamUsers page(default size) into
+// the reused user, PII decryption included
+func BenchmarkToDomain(b *testing.B) {
+	ctx := context.Background()
+	r := newTestRepository(b)
+
+	birthDate, err := r.cipher.Encrypt(ctx, "1990-01-02")
+	if err != nil {
+		b.Fatal(err)
+	}
+	phone, err := r.cipher.Encrypt(ctx, "+33612345678")
+	if err != nil {
+		b.Fatal(err)
+	}
+	ts := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	models := make([]User, 50)
+	for i := range models {
+		models[i] = User{
+			ID:        uint64(i + 1),
+			UUID:      uuid.New(),
+			Email:     fmt.Sprintf("user%d@example.com", i),
+			Role:      "user",
+			Name:      "John",
+			Lastname:  "Doe",
+			BirthDate: birthDate,
+			Phone:     phone,
+			CreatedAt: ts,
+			UpdatedAt: ts,
+		}
+	}
+
+	var u domain.User
+	b.ReportAllocs()
+	for b.Loop() {
+		for i := range models {
+			if err = r.toDomain(ctx, &models[i], &u); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
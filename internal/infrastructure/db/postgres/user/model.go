@@ -15,9 +15,12 @@ type (
 		PasswordHash *string
 		Role         string
 		Name         string
+		MiddleName   string
 		Lastname     string
-		BirthDate    time.Time
-		Phone        string
+		Suffix       string
+		// BirthDate, Phone - encrypted column values
+		BirthDate string
+		Phone     string
 
 		CreatedAt time.Time
 		UpdatedAt time.Time
@@ -25,6 +28,25 @@ type (
 		DeletedAt     *time.Time
 		DeletedReason string
 		DeletedBy     *ID
+
+		PasswordResetRequired bool
+		// Timezone - IANA name, empty - the one of the organization
+		Timezone string
 	}
 	Users []*User
+
+	// PageRow - a row of the users page, with the files summary of the user
+	PageRow struct {
+		User
+		FilesCount      uint64
+		FilesTotalBytes uint64
+	}
+
+	// PII - encrypted columns of a user for the re-encryption
+	PII struct {
+		ID        uint64
+		BirthDate string
+		Phone     string
+		PhoneHash []byte
+	}
 )
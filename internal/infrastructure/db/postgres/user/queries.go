@@ -1,27 +1,61 @@
 package user
 
+import "user-manager-api/internal/domain/user"
+
+// SortColumns - whitelist of "sort" query param values
+var SortColumns = map[string]string{
+	"email":    "lower(email)",
+	"name":     "name",
+	"lastname": "lastname",
+}
+
+// redactColumns - retention policy columns: how to blank the column(in users
+// and in users_history) and how to check it is not blank yet. The middle name
+// goes with the name, the suffix with the lastname
+var redactColumns = map[user.PIIColumn]struct{ set, setHistory, present string }{
+	user.ColumnName:      {"name = '', middle_name = ''", "name = '', middle_name = ''", "(name <> '' OR middle_name <> '')"},
+	user.ColumnLastname:  {"lastname = '', suffix = ''", "lastname = '', suffix = ''", "(lastname <> '' OR suffix <> '')"},
+	user.ColumnBirthDate: {"birth_date = ''", "birth_date = ''", "birth_date <> ''"},
+	user.ColumnPhone:     {"phone = '', phone_hash = NULL", "phone = ''", "phone <> ''"},
+}
+
 const (
+	// the files summary from the read model via subqueries: a join would make
+	// the PageClause columns ambiguous
 	SelectUsers = `
-		SELECT id, uuid, email, password_hash, role, name, lastname, birth_date, phone, created_at, updated_at, deleted_at, deleted_reason, deleted_by
+		SELECT id, uuid, email, password_hash, role, name, lastname, birth_date, phone, created_at, updated_at, deleted_at, deleted_reason, deleted_by, password_reset_required, timezone, middle_name, suffix,
+		       COALESCE((SELECT c.files_count FROM user_file_counts c WHERE c.user_id = users.id), 0),
+		       COALESCE((SELECT c.total_bytes FROM user_file_counts c WHERE c.user_id = users.id), 0)
 		FROM users
-		WHERE deleted_at IS NULL
-		LIMIT 50 OFFSET ( ($1 - 1) * 50 )
-	`
+		WHERE deleted_at IS NULL`
+	// $1 - the reason, '' - any
+	SelectDeletedUsers = `
+		SELECT id, uuid, email, password_hash, role, name, lastname, birth_date, phone, created_at, updated_at, deleted_at, deleted_reason, deleted_by, password_reset_required, timezone, middle_name, suffix
+		FROM users
+		WHERE deleted_at IS NOT NULL AND ($1 = '' OR deleted_reason = $1)`
 	SelectUserByID = `
-		SELECT id, uuid, email, password_hash, role, name, lastname, birth_date, phone, created_at, updated_at, deleted_at, deleted_reason, deleted_by 
+		SELECT id, uuid, email, password_hash, role, name, lastname, birth_date, phone, created_at, updated_at, deleted_at, deleted_reason, deleted_by, password_reset_required, timezone, middle_name, suffix 
 		FROM users 
 		WHERE uuid = $1 AND deleted_at IS NULL
 	`
 	SelectUserByEmail = `
-		SELECT id, uuid, email, password_hash, role, name, lastname, birth_date, phone, created_at, updated_at, deleted_at, deleted_reason, deleted_by 
+		SELECT id, uuid, email, password_hash, role, name, lastname, birth_date, phone, created_at, updated_at, deleted_at, deleted_reason, deleted_by, password_reset_required, timezone, middle_name, suffix 
 		FROM users 
 		WHERE email = $1 AND deleted_at IS NULL
 	`
+	// phone is encrypted, $1 - its blind index, $2 - the plain phone of rows
+	// written before the encryption. LIMIT 2 - enough to detect a phone shared by several users
+	SelectUsersByPhone = `
+		SELECT id, uuid, email, password_hash, role, name, lastname, birth_date, phone, created_at, updated_at, deleted_at, deleted_reason, deleted_by, password_reset_required, timezone, middle_name, suffix
+		FROM users
+		WHERE (phone_hash = $1 OR (phone_hash IS NULL AND phone = $2)) AND deleted_at IS NULL
+		LIMIT 2
+	`
 	InsertUser = `
-		INSERT INTO users (email, name, lastname, birth_date, phone, deleted_reason)
-		VALUES ($1, $2, $3, $4, $5, '')
+		INSERT INTO users (email, name, lastname, birth_date, phone, phone_hash, timezone, middle_name, suffix, deleted_reason)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, '')
 		RETURNING
-		  id, uuid, email, password_hash, role, name, lastname, birth_date, phone, created_at, updated_at, deleted_at, deleted_reason, deleted_by
+		  id, uuid, email, password_hash, role, name, lastname, birth_date, phone, created_at, updated_at, deleted_at, deleted_reason, deleted_by, password_reset_required, timezone, middle_name, suffix
 	`
 	UpdateUserByUUID = `
 		UPDATE users
@@ -30,17 +64,364 @@ const (
 		    lastname = $3,
 		    birth_date = $4,
 		    phone = $5,
+		    phone_hash = $6,
+		    timezone = $7,
+		    middle_name = $8,
+		    suffix = $9,
 		    updated_at = now()
-		WHERE uuid = $6 AND deleted_at IS NULL
+		WHERE uuid = $10 AND deleted_at IS NULL
 		RETURNING
-		  id, uuid, email, password_hash, role, name, lastname, birth_date, phone, created_at, updated_at, deleted_at, deleted_reason, deleted_by
+		  id, uuid, email, password_hash, role, name, lastname, birth_date, phone, created_at, updated_at, deleted_at, deleted_reason, deleted_by, password_reset_required, timezone, middle_name, suffix
 	`
-	SelectIdByUUID     = `SELECT id FROM users WHERE uuid = $1::uuid`
-	SoftDeleteUserByID = `
+	// the new email must not belong to another active user at request time,
+	// the unique index re-checks it on confirmation
+	UpsertEmailChange = `
+		INSERT INTO user_email_changes (user_id, new_email, token_hash, expires_at)
+		SELECT $1, $2::text, $3, $4
+		WHERE NOT EXISTS (SELECT 1 FROM users WHERE lower(email) = lower($2::text) AND deleted_at IS NULL)
+		ON CONFLICT (user_id) DO UPDATE
+		SET new_email = EXCLUDED.new_email,
+		    token_hash = EXCLUDED.token_hash,
+		    expires_at = EXCLUDED.expires_at,
+		    created_at = now()
+	`
+	// $4 - the inviting admin uuid
+	UpsertInvitation = `
+		INSERT INTO user_invitations (email, role, token_hash, invited_by, expires_at)
+		SELECT $1::text, $2, $3, (SELECT a.id FROM users a WHERE a.uuid = $4), $5
+		WHERE NOT EXISTS (SELECT 1 FROM users WHERE lower(email) = lower($1::text) AND deleted_at IS NULL)
+		ON CONFLICT (lower(email)) DO UPDATE
+		SET role = EXCLUDED.role,
+		    token_hash = EXCLUDED.token_hash,
+		    invited_by = EXCLUDED.invited_by,
+		    expires_at = EXCLUDED.expires_at,
+		    created_at = now()
+	`
+	SelectInvitation = `
+		SELECT email, role, token_hash, expires_at
+		FROM user_invitations
+		WHERE token_hash = $1 AND expires_at > now()
+	`
+	AcceptInvitation = `
+		WITH inv AS (
+		    DELETE FROM user_invitations
+		    WHERE token_hash = $1 AND expires_at > now()
+		    RETURNING email, role
+		)
+		INSERT INTO users (email, password_hash, role, name, lastname, birth_date, phone, phone_hash, timezone, middle_name, suffix, deleted_reason)
+		SELECT inv.email, $2, inv.role, $3, $4, $5, $6, $7, $8, $9, $10, ''
+		FROM inv
+		RETURNING
+		  id, uuid, email, password_hash, role, name, lastname, birth_date, phone, created_at, updated_at, deleted_at, deleted_reason, deleted_by, password_reset_required, timezone, middle_name, suffix
+	`
+	ConfirmEmailChange = `
+		WITH req AS (
+		    DELETE FROM user_email_changes
+		    WHERE token_hash = $1 AND expires_at > now()
+		    RETURNING user_id, new_email
+		)
+		UPDATE users u
+		SET email = req.new_email,
+		    updated_at = now()
+		FROM req
+		WHERE u.id = req.user_id AND u.deleted_at IS NULL
+		RETURNING
+		  u.id, u.uuid, u.email, u.password_hash, u.role, u.name, u.lastname, u.birth_date, u.phone, u.created_at, u.updated_at, u.deleted_at, u.deleted_reason, u.deleted_by, u.password_reset_required, u.timezone, u.middle_name, u.suffix
+	`
+	// tokens issued before tokens_valid_after are revoked
+	ForcePasswordReset = `
+		UPDATE users
+		SET password_reset_required = true,
+		    tokens_valid_after = now(),
+		    updated_at = now()
+		WHERE uuid = $1 AND deleted_at IS NULL
+	`
+	// RevokeTokens - never moves tokens_valid_after back
+	RevokeTokens = `
+		UPDATE users
+		SET tokens_valid_after = GREATEST(tokens_valid_after, $2),
+		    updated_at = now()
+		WHERE uuid = $1 AND deleted_at IS NULL
+	`
+	// $1 - uuids, $2 - roles. Either all demotions apply or none: they are
+	// skipped when the active admins would run out
+	SetRoles = `
+		WITH req AS (
+		    SELECT r.uuid, r.role, r.ord
+		    FROM unnest($1::uuid[], $2::text[]) WITH ORDINALITY AS r(uuid, role, ord)
+		),
+		admins AS (
+		    SELECT id FROM users WHERE role = 'admin' AND deleted_at IS NULL FOR UPDATE
+		),
+		cur AS (
+		    SELECT u.id, u.uuid, u.role
+		    FROM users u
+		    WHERE u.uuid IN (SELECT req.uuid FROM req) AND u.deleted_at IS NULL
+		    FOR UPDATE
+		),
+		guard AS (
+		    SELECT (SELECT count(*) FROM admins)
+		         - count(*) FILTER (WHERE cur.role = 'admin' AND req.role <> 'admin')
+		         + count(*) FILTER (WHERE cur.role <> 'admin' AND req.role = 'admin') > 0 AS demote_ok
+		    FROM req JOIN cur ON cur.uuid = req.uuid
+		),
+		upd AS (
+		    UPDATE users u
+		    SET role = req.role,
+		        updated_at = now()
+		    FROM req JOIN cur ON cur.uuid = req.uuid, guard
+		    WHERE u.id = cur.id AND cur.role <> req.role
+		      AND (cur.role <> 'admin' OR guard.demote_ok)
+		    RETURNING u.uuid
+		)
+		SELECT req.uuid, req.role, COALESCE(cur.role, ''),
+		       CASE
+		           WHEN cur.id IS NULL THEN 'not_found'
+		           WHEN cur.role = req.role THEN 'unchanged'
+		           WHEN upd.uuid IS NOT NULL THEN 'updated'
+		           ELSE 'last_admin'
+		       END
+		FROM req
+		LEFT JOIN cur ON cur.uuid = req.uuid
+		LEFT JOIN upd ON upd.uuid = req.uuid
+		ORDER BY req.ord
+	`
+	UpdatePassword = `
+		UPDATE users
+		SET password_hash = $1,
+		    password_reset_required = false,
+		    tokens_valid_after = now(),
+		    updated_at = now()
+		WHERE uuid = $2 AND deleted_at IS NULL
+	`
+	UpdatePasswordHash = `
 		UPDATE users
-		SET deleted_at = now()
+		SET password_hash = $1
+		WHERE uuid = $2 AND deleted_at IS NULL
+	`
+	// keyset batches of all rows(deleted too) for the PII re-encryption
+	SelectPIIBatch = `
+		SELECT id, birth_date, phone, phone_hash
+		FROM users
+		WHERE id > $1
+		ORDER BY id
+		LIMIT $2
+	`
+	// skipped if the row was changed since it was read
+	UpdatePII = `
+		UPDATE users
+		SET birth_date = $1,
+		    phone = $2,
+		    phone_hash = $3
+		WHERE id = $4 AND birth_date = $5 AND phone = $6
+	`
+	TouchLastSeen = `UPDATE users SET last_seen_at = now(), inactivity_warned_at = NULL WHERE uuid = $1`
+	// the admins are never suspended, nor warned
+	WarnInactive = `
+		UPDATE users
+		SET inactivity_warned_at = now()
+		WHERE id IN (
+		    SELECT id FROM users
+		    WHERE last_seen_at < $1 AND deleted_at IS NULL AND suspended_at IS NULL
+		      AND inactivity_warned_at IS NULL AND role <> 'admin'
+		    ORDER BY id
+		    LIMIT $2
+		    FOR UPDATE
+		)
+		RETURNING uuid
+	`
+	// $2 - warned before, NULL - no warning needed. The tokens issued before the
+	// suspension are revoked. The audit entries($4 - the actor, $5 - the action,
+	// $6 - details.inactive_days) are written by the same statement
+	SuspendInactive = `
+		WITH suspended AS (
+		    UPDATE users
+		    SET suspended_at = now(),
+		        tokens_valid_after = now()
+		    WHERE id IN (
+		        SELECT id FROM users
+		        WHERE last_seen_at < $1 AND deleted_at IS NULL AND suspended_at IS NULL
+		          AND role <> 'admin'
+		          AND ($2::timestamptz IS NULL OR inactivity_warned_at < $2)
+		        ORDER BY id
+		        LIMIT $3
+		        FOR UPDATE
+		    )
+		    RETURNING uuid
+		),
+		audit AS (
+		    INSERT INTO audit_log (actor_uuid, action, target_uuid, details)
+		    SELECT $4, $5, uuid, jsonb_build_object('inactive_days', $6::int)
+		    FROM suspended
+		)
+		SELECT uuid FROM suspended
+	`
+	SelectSuspendedAt = `SELECT suspended_at FROM users WHERE uuid = $1 AND deleted_at IS NULL`
+	Reactivate        = `
+		UPDATE users
+		SET suspended_at = NULL,
+		    inactivity_warned_at = NULL,
+		    last_seen_at = now()
+		WHERE uuid = $1 AND deleted_at IS NULL AND suspended_at IS NOT NULL
+	`
+	// %s - OR of the present checks, in the user or in its history
+	SelectRedactionCandidates = `
+		SELECT uuid
+		FROM users
+		WHERE last_seen_at < $1 AND legal_hold_since IS NULL
+		  AND ((%[1]s) OR EXISTS (SELECT 1 FROM users_history h WHERE h.user_id = users.id AND (%[1]s)))
+		ORDER BY id
+		LIMIT $2
+	`
+	// %s - the blanking assignments
+	RedactUser = `
+		UPDATE users
+		SET %s,
+		    updated_at = now()
+		WHERE uuid = $1 AND last_seen_at < $2 AND legal_hold_since IS NULL
+	`
+	// %s - the blanking assignments. Run after RedactUser: its prior version
+	// has just been recorded by the history trigger
+	RedactHistory = `
+		UPDATE users_history
+		SET %s
+		WHERE user_id = (SELECT id FROM users WHERE uuid = $1)
+	`
+	// the history rows of the users of a SelectPIIBatch batch: (afterID, lastID]
+	SelectHistoryPIIBatch = `
+		SELECT id, birth_date, phone
+		FROM users_history
+		WHERE user_id > $1 AND user_id <= $2
+		ORDER BY id
+	`
+	// skipped if the row was changed since it was read(redacted)
+	UpdateHistoryPII = `
+		UPDATE users_history
+		SET birth_date = $1,
+		    phone = $2
+		WHERE id = $3 AND birth_date = $4 AND phone = $5
+	`
+	SelectTokensValidAfter = `SELECT tokens_valid_after FROM users WHERE uuid = $1`
+	SelectRole             = `SELECT role FROM users WHERE uuid = $1 AND deleted_at IS NULL`
+	SelectIdByUUID         = `SELECT id FROM users WHERE uuid = $1::uuid`
+	SelectIdentityByID     = `SELECT id, uuid, deleted_at FROM users WHERE id = $1`
+	SelectIdentityByUUID   = `SELECT id, uuid, deleted_at FROM users WHERE uuid = $1::uuid`
+	SelectActiveRoleByID   = `SELECT role FROM users WHERE id = $1 AND deleted_at IS NULL`
+	// SelectDeleteChecks - whether SoftDeleteUserByID would keep the user now: on a
+	// legal hold, the last active admin
+	SelectDeleteChecks = `
+		SELECT legal_hold_since IS NOT NULL,
+		       role = 'admin' AND (SELECT count(*) FROM users a WHERE a.role = 'admin' AND a.deleted_at IS NULL) < 2
+		FROM users
 		WHERE id = $1 AND deleted_at IS NULL
-		RETURNING
-		  id, uuid, email, password_hash, role, name, lastname, birth_date, phone, created_at, updated_at, deleted_at, deleted_reason, deleted_by
 	`
+	// SelectDeleteBlockers - why an active user was not deleted
+	SelectDeleteBlockers = `SELECT role, legal_hold_since IS NOT NULL FROM users WHERE id = $1 AND deleted_at IS NULL`
+	// deleted_by is NULL for an unknown actor(the system). The active admins are
+	// locked, so of two admins deleting each other at once the second one sees
+	// the first deleted and keeps the last admin. The access of the user goes
+	// with it: the issued tokens are revoked and the devices deleted
+	SoftDeleteUserByID = `
+		WITH admins AS (
+		  SELECT id FROM users WHERE role = 'admin' AND deleted_at IS NULL FOR UPDATE
+		),
+		deleted AS (
+		  UPDATE users
+		  SET deleted_at = now(),
+		      deleted_reason = $2,
+		      deleted_by = (SELECT a.id FROM users a WHERE a.uuid = $3),
+		      tokens_valid_after = now()
+		  WHERE id = $1 AND deleted_at IS NULL AND legal_hold_since IS NULL
+		    AND (role <> 'admin' OR (SELECT count(*) FROM admins) > 1)
+		  RETURNING
+		    id, uuid, email, password_hash, role, name, lastname, birth_date, phone, created_at, updated_at, deleted_at, deleted_reason, deleted_by, password_reset_required, timezone, middle_name, suffix
+		),
+		devices AS (
+		  DELETE FROM devices d
+		  USING deleted
+		  WHERE d.user_id = deleted.id
+		)
+		SELECT
+		  id, uuid, email, password_hash, role, name, lastname, birth_date, phone, created_at, updated_at, deleted_at, deleted_reason, deleted_by, password_reset_required, timezone, middle_name, suffix
+		FROM deleted
+	`
+	// MergeUsers moves the files, notes and audit records of the loser $2 to the
+	// winner $1 and soft-deletes the loser, nothing if the winner is not active
+	// or the loser is the last admin(the admins are locked as on delete) or on a
+	// legal hold. The access of the loser is revoked as on delete. The
+	// audit records keep the original target in details.merged_from
+	MergeUsers = `
+		WITH admins AS (
+		  SELECT id FROM users WHERE role = 'admin' AND deleted_at IS NULL FOR UPDATE
+		),
+		winner AS (
+		  SELECT id, uuid FROM users WHERE id = $1 AND deleted_at IS NULL FOR UPDATE
+		),
+		loser AS (
+		  UPDATE users
+		  SET deleted_at = now(),
+		      deleted_reason = 'merged',
+		      deleted_by = (SELECT a.id FROM users a WHERE a.uuid = $3),
+		      tokens_valid_after = now()
+		  WHERE id = $2 AND id <> $1 AND deleted_at IS NULL AND legal_hold_since IS NULL
+		    AND EXISTS (SELECT 1 FROM winner)
+		    AND (role <> 'admin' OR (SELECT count(*) FROM admins) > 1)
+		  RETURNING id, uuid
+		),
+		devices AS (
+		  DELETE FROM devices d
+		  USING loser
+		  WHERE d.user_id = loser.id
+		),
+		files AS (
+		  UPDATE user_files SET user_id = $1
+		  WHERE user_id IN (SELECT id FROM loser)
+		  RETURNING 1
+		),
+		notes AS (
+		  UPDATE user_notes SET user_id = $1
+		  WHERE user_id IN (SELECT id FROM loser)
+		  RETURNING 1
+		),
+		audit AS (
+		  UPDATE audit_log
+		  SET target_uuid = (SELECT uuid FROM winner),
+		      details = details || jsonb_build_object('merged_from', target_uuid)
+		  WHERE target_uuid IN (SELECT uuid FROM loser)
+		  RETURNING 1
+		)
+		SELECT (SELECT count(*) FROM files), (SELECT count(*) FROM notes), (SELECT count(*) FROM audit)
+		FROM loser
+	`
+	// SelectVersions - the past versions(users_history) and the current one of $1,
+	// the current one is valid since the end of the latest past one
+	SelectVersions = `
+		SELECT id, uuid, email, password_hash, role, name, lastname, birth_date, phone, created_at, updated_at, deleted_at, deleted_reason, deleted_by, password_reset_required, timezone, middle_name, suffix, valid_from, valid_to
+		FROM (
+		  SELECT h.user_id AS id, u.uuid, h.email, NULL::text AS password_hash, h.role, h.name, h.lastname, h.birth_date, h.phone, h.created_at, h.updated_at, h.deleted_at, h.deleted_reason, h.deleted_by, h.password_reset_required, h.timezone, h.middle_name, h.suffix, h.valid_from, h.valid_to
+		  FROM users_history h
+		  JOIN users u ON u.id = h.user_id
+		  WHERE h.user_id = $1
+		  UNION ALL
+		  SELECT id, uuid, email, NULL, role, name, lastname, birth_date, phone, created_at, updated_at, deleted_at, deleted_reason, deleted_by, password_reset_required, timezone, middle_name, suffix,
+		         COALESCE((SELECT max(h.valid_to) FROM users_history h WHERE h.user_id = users.id), created_at), NULL
+		  FROM users
+		  WHERE id = $1
+		) v`
+	SelectHistory   = SelectVersions + ` ORDER BY valid_from, valid_to NULLS LAST`
+	SelectVersionAt = SelectVersions + ` WHERE valid_from <= $2 AND (valid_to IS NULL OR valid_to > $2)`
+
+	// the since of a placed hold is kept
+	SetLegalHold = `
+		UPDATE users
+		SET legal_hold_since = COALESCE(legal_hold_since, now()), legal_hold_reason = $2
+		WHERE uuid = $1
+		RETURNING legal_hold_reason, legal_hold_since
+	`
+	ReleaseLegalHold = `
+		UPDATE users
+		SET legal_hold_since = NULL, legal_hold_reason = ''
+		WHERE uuid = $1 AND legal_hold_since IS NOT NULL
+	`
+	SelectLegalHold = `SELECT legal_hold_reason, legal_hold_since FROM users WHERE uuid = $1`
 )
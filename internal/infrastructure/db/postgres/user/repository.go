@@ -4,24 +4,177 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
-	"github.com/jackc/pgx/v5/pgxpool"
 
+	"user-manager-api/internal/domain/audit"
+	"user-manager-api/internal/domain/pagination"
 	"user-manager-api/internal/domain/user"
 	"user-manager-api/internal/infrastructure/db/postgres"
+	"user-manager-api/internal/infrastructure/fieldcrypt"
 )
 
 type Repository struct {
-	db *pgxpool.Pool
+	db       postgres.DB
+	pageSize int
+	// cipher - birth_date and phone are encrypted in the mapping layer
+	cipher *fieldcrypt.Cipher
 }
 
-func NewRepository(db *pgxpool.Pool) user.Repository {
-	return &Repository{db: db}
+func NewRepository(db postgres.DB, pageSize int, cipher *fieldcrypt.Cipher) user.Repository {
+	return &Repository{db: db, pageSize: pageSize, cipher: cipher}
 }
 
-func (r *Repository) FetchUsers(ctx context.Context, page int) (user.Users, error) {
-	rows, err := r.db.Query(ctx, SelectUsers, page)
+// StreamUsers - the page(per_page rows at most) is read first and the rows are
+// closed before fn is called, so a slow client does not hold the connection,
+// the bulkhead slot or the DB_QUERY_TIMEOUT. One user is reused for all the rows.
+func (r *Repository) StreamUsers(ctx context.Context, p pagination.Params, fn func(u *user.User) error) error {
+	buf := getPage()
+	defer putPage(buf)
+	if err := r.fetchPage(ctx, p, buf); err != nil {
+		return err
+	}
+	rows := *buf
+
+	var (
+		u     user.User
+		files user.FilesSummary
+	)
+	for idx := range rows {
+		if err := r.toDomain(ctx, &rows[idx].User, &u); err != nil {
+			return err
+		}
+		files = user.FilesSummary{Count: rows[idx].FilesCount, TotalBytes: rows[idx].FilesTotalBytes}
+		u.Files = &files
+
+		if err := fn(&u); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// fetchPage appends the rows of the page to buf
+func (r *Repository) fetchPage(ctx context.Context, p pagination.Params, buf *[]PageRow) error {
+	clause, args := postgres.PageClause(p, SortColumns, r.pageSize, 1)
+	rows, err := r.db.Query(ctx, SelectUsers+clause, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		*buf = append(*buf, PageRow{})
+		m := &(*buf)[len(*buf)-1]
+
+		if err = rows.Scan(
+			&m.ID,
+			&m.UUID,
+			&m.Email,
+			&m.PasswordHash,
+			&m.Role,
+			&m.Name,
+			&m.Lastname,
+			&m.BirthDate,
+			&m.Phone,
+
+			&m.CreatedAt,
+			&m.UpdatedAt,
+
+			&m.DeletedAt,
+			&m.DeletedReason,
+			&m.DeletedBy,
+			&m.PasswordResetRequired,
+			&m.Timezone,
+			&m.MiddleName,
+			&m.Suffix,
+
+			&m.FilesCount,
+			&m.FilesTotalBytes,
+		); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+func (r *Repository) FetchUserByID(ctx context.Context, uuid user.UUID) (*user.User, error) {
+	u := new(User)
+	err := r.db.QueryRow(ctx, SelectUserByID, uuid.String()).Scan(
+		&u.ID,
+		&u.UUID,
+		&u.Email,
+		&u.PasswordHash,
+		&u.Role,
+		&u.Name,
+		&u.Lastname,
+		&u.BirthDate,
+		&u.Phone,
+
+		&u.CreatedAt,
+		&u.UpdatedAt,
+
+		&u.DeletedAt,
+		&u.DeletedReason,
+		&u.DeletedBy,
+		&u.PasswordResetRequired,
+		&u.Timezone,
+		&u.MiddleName,
+		&u.Suffix,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, user.ErrNotFound
+		}
+		return nil, err
+	}
+
+	return r.fromDBModel(ctx, u)
+}
+
+func (r *Repository) FetchUserByEmail(ctx context.Context, email string) (*user.User, error) {
+	u := new(User)
+	err := r.db.QueryRow(ctx, SelectUserByEmail, email).Scan(
+		&u.ID,
+		&u.UUID,
+		&u.Email,
+		&u.PasswordHash,
+		&u.Role,
+		&u.Name,
+		&u.Lastname,
+		&u.BirthDate,
+		&u.Phone,
+
+		&u.CreatedAt,
+		&u.UpdatedAt,
+
+		&u.DeletedAt,
+		&u.DeletedReason,
+		&u.DeletedBy,
+		&u.PasswordResetRequired,
+		&u.Timezone,
+		&u.MiddleName,
+		&u.Suffix,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, user.ErrNotFound
+		}
+		return nil, err
+	}
+
+	return r.fromDBModel(ctx, u)
+}
+
+// FetchUserByPhone returns user.ErrNotFound when no user or more than one user has the phone:
+// an ambiguous phone can not identify the account.
+func (r *Repository) FetchUserByPhone(ctx context.Context, phone string) (*user.User, error) {
+	rows, err := r.db.Query(ctx, SelectUsersByPhone, r.cipher.BlindIndex(phone), phone)
 	if err != nil {
 		return nil, err
 	}
@@ -48,6 +201,10 @@ func (r *Repository) FetchUsers(ctx context.Context, page int) (user.Users, erro
 			&u.DeletedAt,
 			&u.DeletedReason,
 			&u.DeletedBy,
+			&u.PasswordResetRequired,
+			&u.Timezone,
+			&u.MiddleName,
+			&u.Suffix,
 		); err != nil {
 			return nil, err
 		}
@@ -57,13 +214,25 @@ func (r *Repository) FetchUsers(ctx context.Context, page int) (user.Users, erro
 	if err = rows.Err(); err != nil {
 		return nil, err
 	}
+	if len(us) != 1 {
+		return nil, user.ErrNotFound
+	}
 
-	return fromDBModels(&us), nil
+	return r.fromDBModel(ctx, us[0])
 }
 
-func (r *Repository) FetchUserByID(ctx context.Context, uuid user.UUID) (*user.User, error) {
+func (r *Repository) CreateUser(ctx context.Context, req user.User) (*user.User, error) {
+	birthDate, phone, phoneHash, err := r.toDBPII(ctx, req)
+	if err != nil {
+		return nil, err
+	}
 	u := new(User)
-	err := r.db.QueryRow(ctx, SelectUserByID, uuid.String()).Scan(
+
+	err = r.db.QueryRow(
+		ctx,
+		InsertUser,
+		req.Email, req.Name, req.Lastname, birthDate, phone, phoneHash, req.Timezone, req.MiddleName, req.Suffix,
+	).Scan(
 		&u.ID,
 		&u.UUID,
 		&u.Email,
@@ -80,20 +249,34 @@ func (r *Repository) FetchUserByID(ctx context.Context, uuid user.UUID) (*user.U
 		&u.DeletedAt,
 		&u.DeletedReason,
 		&u.DeletedBy,
+		&u.PasswordResetRequired,
+		&u.Timezone,
+		&u.MiddleName,
+		&u.Suffix,
 	)
 	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
-			return nil, nil
+		if postgres.IsPgUniqueViolation(err) {
+			return nil, user.ErrEmailAlreadyExists
+		}
+		if postgres.IsPgSeatLimitReached(err) {
+			return nil, user.ErrSeatLimitReached
 		}
 		return nil, err
 	}
 
-	return fromDBModel(u), err
+	return r.fromDBModel(ctx, u)
 }
 
-func (r *Repository) FetchUserByEmail(ctx context.Context, email string) (*user.User, error) {
+func (r *Repository) UpdateUser(ctx context.Context, req user.User) (*user.User, error) {
+	birthDate, phone, phoneHash, err := r.toDBPII(ctx, req)
+	if err != nil {
+		return nil, err
+	}
 	u := new(User)
-	err := r.db.QueryRow(ctx, SelectUserByEmail, email).Scan(
+
+	err = r.db.QueryRow(ctx, UpdateUserByUUID,
+		req.Email, req.Name, req.Lastname, birthDate, phone, phoneHash, req.Timezone, req.MiddleName, req.Suffix, req.UUID,
+	).Scan(
 		&u.ID,
 		&u.UUID,
 		&u.Email,
@@ -110,25 +293,42 @@ func (r *Repository) FetchUserByEmail(ctx context.Context, email string) (*user.
 		&u.DeletedAt,
 		&u.DeletedReason,
 		&u.DeletedBy,
+		&u.PasswordResetRequired,
+		&u.Timezone,
+		&u.MiddleName,
+		&u.Suffix,
 	)
 	if err != nil {
+		if postgres.IsPgUniqueViolation(err) {
+			return nil, user.ErrEmailAlreadyExists
+		}
+		if postgres.IsPgSeatLimitReached(err) {
+			return nil, user.ErrSeatLimitReached
+		}
 		if errors.Is(err, pgx.ErrNoRows) {
-			return nil, nil
+			return nil, user.ErrNotFound
 		}
 		return nil, err
 	}
 
-	return fromDBModel(u), err
+	return r.fromDBModel(ctx, u)
 }
 
-func (r *Repository) CreateUser(ctx context.Context, req user.User) (*user.User, error) {
-	u := new(User)
+func (r *Repository) CreateEmailChange(ctx context.Context, id user.ID, c user.EmailChange) error {
+	tag, err := r.db.Exec(ctx, UpsertEmailChange, id, c.NewEmail, c.TokenHash, c.ExpiresAt)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return user.ErrEmailAlreadyExists
+	}
 
-	err := r.db.QueryRow(
-		ctx,
-		InsertUser,
-		req.Email, req.Name, req.Lastname, req.BirthDate, req.Phone,
-	).Scan(
+	return nil
+}
+
+func (r *Repository) ConfirmEmailChange(ctx context.Context, tokenHash []byte) (*user.User, error) {
+	u := new(User)
+	err := r.db.QueryRow(ctx, ConfirmEmailChange, tokenHash).Scan(
 		&u.ID,
 		&u.UUID,
 		&u.Email,
@@ -145,22 +345,64 @@ func (r *Repository) CreateUser(ctx context.Context, req user.User) (*user.User,
 		&u.DeletedAt,
 		&u.DeletedReason,
 		&u.DeletedBy,
+		&u.PasswordResetRequired,
+		&u.Timezone,
+		&u.MiddleName,
+		&u.Suffix,
 	)
 	if err != nil {
 		if postgres.IsPgUniqueViolation(err) {
-			return nil, ErrEmailAlreadyExists
+			return nil, user.ErrEmailAlreadyExists
+		}
+		if postgres.IsPgSeatLimitReached(err) {
+			return nil, user.ErrSeatLimitReached
+		}
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, user.ErrNotFound
 		}
 		return nil, err
 	}
 
-	return fromDBModel(u), err
+	return r.fromDBModel(ctx, u)
 }
 
-func (r *Repository) UpdateUser(ctx context.Context, req user.User) (*user.User, error) {
+func (r *Repository) CreateInvitation(ctx context.Context, inv user.Invitation) error {
+	tag, err := r.db.Exec(ctx, UpsertInvitation, inv.Email, inv.Role, inv.TokenHash, inv.InvitedBy, inv.ExpiresAt)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return user.ErrEmailAlreadyExists
+	}
+
+	return nil
+}
+
+func (r *Repository) FetchInvitation(ctx context.Context, tokenHash []byte) (*user.Invitation, error) {
+	inv := new(user.Invitation)
+	err := r.db.QueryRow(ctx, SelectInvitation, tokenHash).Scan(&inv.Email, &inv.Role, &inv.TokenHash, &inv.ExpiresAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, user.ErrNotFound
+		}
+		return nil, err
+	}
+
+	return inv, nil
+}
+
+func (r *Repository) AcceptInvitation(ctx context.Context, tokenHash []byte, req user.User, passwordHash string) (*user.User, error) {
+	birthDate, phone, phoneHash, err := r.toDBPII(ctx, req)
+	if err != nil {
+		return nil, err
+	}
 	u := new(User)
 
-	err := r.db.QueryRow(ctx, UpdateUserByUUID,
-		req.Email, req.Name, req.Lastname, req.BirthDate, req.Phone, req.UUID,
+	// a failed insert(the email was taken meanwhile) keeps the invitation
+	err = r.db.QueryRow(
+		ctx,
+		AcceptInvitation,
+		tokenHash, passwordHash, req.Name, req.Lastname, birthDate, phone, phoneHash, req.Timezone, req.MiddleName, req.Suffix,
 	).Scan(
 		&u.ID,
 		&u.UUID,
@@ -178,25 +420,353 @@ func (r *Repository) UpdateUser(ctx context.Context, req user.User) (*user.User,
 		&u.DeletedAt,
 		&u.DeletedReason,
 		&u.DeletedBy,
+		&u.PasswordResetRequired,
+		&u.Timezone,
+		&u.MiddleName,
+		&u.Suffix,
 	)
 	if err != nil {
 		if postgres.IsPgUniqueViolation(err) {
-			return nil, ErrEmailAlreadyExists
+			return nil, user.ErrEmailAlreadyExists
+		}
+		if postgres.IsPgSeatLimitReached(err) {
+			return nil, user.ErrSeatLimitReached
 		}
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, user.ErrNotFound
+		}
+		return nil, err
+	}
+
+	return r.fromDBModel(ctx, u)
+}
+
+func (r *Repository) ForcePasswordReset(ctx context.Context, uuid user.UUID) (bool, error) {
+	tag, err := r.db.Exec(ctx, ForcePasswordReset, uuid)
+	if err != nil {
+		return false, err
+	}
+
+	return tag.RowsAffected() > 0, nil
+}
+
+func (r *Repository) RevokeTokens(ctx context.Context, uuid user.UUID, issuedBefore time.Time) (bool, error) {
+	tag, err := r.db.Exec(ctx, RevokeTokens, uuid, issuedBefore)
+	if err != nil {
+		return false, err
+	}
+
+	return tag.RowsAffected() > 0, nil
+}
+
+func (r *Repository) SetRoles(ctx context.Context, changes []user.RoleChange) ([]user.RoleChangeResult, error) {
+	uuids := make([]user.UUID, len(changes))
+	roles := make([]string, len(changes))
+	for i, c := range changes {
+		uuids[i] = c.UUID
+		roles[i] = c.Role
+	}
+
+	rows, err := r.db.Query(ctx, SetRoles, uuids, roles)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	res := make([]user.RoleChangeResult, 0, len(changes))
+	for rows.Next() {
+		var (
+			rc     user.RoleChangeResult
+			status string
+		)
+		if err = rows.Scan(&rc.UUID, &rc.Role, &rc.PrevRole, &status); err != nil {
+			return nil, err
+		}
+		rc.Status = user.RoleChangeStatus(status)
+		res = append(res, rc)
+	}
+
+	return res, rows.Err()
+}
+
+func (r *Repository) UpdatePassword(ctx context.Context, uuid user.UUID, passwordHash string) error {
+	_, err := r.db.Exec(ctx, UpdatePassword, passwordHash, uuid)
+	return err
+}
+
+func (r *Repository) UpdatePasswordHash(ctx context.Context, uuid user.UUID, passwordHash string) error {
+	_, err := r.db.Exec(ctx, UpdatePasswordHash, passwordHash, uuid)
+	return err
+}
+
+func (r *Repository) ReencryptPII(ctx context.Context, afterID user.ID, limit int) (user.ID, int, error) {
+	rows, err := r.db.Query(ctx, SelectPIIBatch, afterID, limit)
+	if err != nil {
+		return 0, 0, err
+	}
+	var batch []PII
+	for rows.Next() {
+		var p PII
+		if err = rows.Scan(&p.ID, &p.BirthDate, &p.Phone, &p.PhoneHash); err != nil {
+			rows.Close()
+			return 0, 0, err
+		}
+		batch = append(batch, p)
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		return 0, 0, err
+	}
+
+	var (
+		lastID  user.ID
+		updated int
+	)
+	for _, p := range batch {
+		lastID = user.ID(p.ID)
+		if r.cipher.IsCurrent(p.BirthDate) && r.cipher.IsCurrent(p.Phone) && (p.Phone == "" || p.PhoneHash != nil) {
+			continue
+		}
+
+		phone, err := r.cipher.Decrypt(ctx, p.Phone)
+		if err != nil {
+			return 0, 0, fmt.Errorf("decrypt phone of user %d: %w", p.ID, err)
+		}
+		birthDate, err := r.decryptBirthDate(ctx, p.BirthDate)
+		if err != nil {
+			return 0, 0, fmt.Errorf("decrypt birth date of user %d: %w", p.ID, err)
+		}
+		newBirthDate, newPhone, phoneHash, err := r.toDBPII(ctx, user.User{BirthDate: birthDate, Phone: phone})
+		if err != nil {
+			return 0, 0, err
+		}
+
+		tag, err := r.db.Exec(ctx, UpdatePII, newBirthDate, newPhone, phoneHash, p.ID, p.BirthDate, p.Phone)
+		if err != nil {
+			return 0, 0, err
+		}
+		updated += int(tag.RowsAffected())
+	}
+	if lastID == 0 {
+		return 0, updated, nil
+	}
+
+	n, err := r.reencryptHistoryPII(ctx, afterID, lastID)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return lastID, updated + n, nil
+}
+
+// reencryptHistoryPII - the history rows of the users in (afterID, lastID]
+func (r *Repository) reencryptHistoryPII(ctx context.Context, afterID, lastID user.ID) (int, error) {
+	rows, err := r.db.Query(ctx, SelectHistoryPIIBatch, afterID, lastID)
+	if err != nil {
+		return 0, err
+	}
+	var batch []PII
+	for rows.Next() {
+		var p PII
+		if err = rows.Scan(&p.ID, &p.BirthDate, &p.Phone); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		batch = append(batch, p)
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		return 0, err
+	}
+
+	var updated int
+	for _, p := range batch {
+		if r.cipher.IsCurrent(p.BirthDate) && r.cipher.IsCurrent(p.Phone) {
+			continue
+		}
+
+		phone, err := r.cipher.Decrypt(ctx, p.Phone)
+		if err != nil {
+			return 0, fmt.Errorf("decrypt phone of history row %d: %w", p.ID, err)
+		}
+		birthDate, err := r.decryptBirthDate(ctx, p.BirthDate)
+		if err != nil {
+			return 0, fmt.Errorf("decrypt birth date of history row %d: %w", p.ID, err)
+		}
+		newBirthDate, newPhone, _, err := r.toDBPII(ctx, user.User{BirthDate: birthDate, Phone: phone})
+		if err != nil {
+			return 0, err
+		}
+
+		tag, err := r.db.Exec(ctx, UpdateHistoryPII, newBirthDate, newPhone, p.ID, p.BirthDate, p.Phone)
+		if err != nil {
+			return 0, err
+		}
+		updated += int(tag.RowsAffected())
+	}
+
+	return updated, nil
+}
+
+func (r *Repository) TouchLastSeen(ctx context.Context, uuid user.UUID) error {
+	_, err := r.db.Exec(ctx, TouchLastSeen, uuid)
+	return err
+}
+
+func (r *Repository) FetchRedactionCandidates(
+	ctx context.Context,
+	seenBefore time.Time,
+	columns []user.PIIColumn,
+	limit int,
+) ([]user.UUID, error) {
+	present := make([]string, len(columns))
+	for i, col := range columns {
+		c, ok := redactColumns[col]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownPIIColumn, col)
+		}
+		present[i] = c.present
+	}
+
+	rows, err := r.db.Query(ctx, fmt.Sprintf(SelectRedactionCandidates, strings.Join(present, " OR ")), seenBefore, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var uuids []user.UUID
+	for rows.Next() {
+		var u user.UUID
+		if err = rows.Scan(&u); err != nil {
+			return nil, err
+		}
+		uuids = append(uuids, u)
+	}
+
+	return uuids, rows.Err()
+}
+
+func (r *Repository) RedactPII(ctx context.Context, uuid user.UUID, seenBefore time.Time, columns []user.PIIColumn) (bool, error) {
+	set := make([]string, len(columns))
+	setHistory := make([]string, len(columns))
+	for i, col := range columns {
+		c, ok := redactColumns[col]
+		if !ok {
+			return false, fmt.Errorf("%w: %s", ErrUnknownPIIColumn, col)
+		}
+		set[i] = c.set
+		setHistory[i] = c.setHistory
+	}
+
+	tag, err := r.db.Exec(ctx, fmt.Sprintf(RedactUser, strings.Join(set, ", ")), uuid, seenBefore)
+	if err != nil {
+		return false, err
+	}
+	if tag.RowsAffected() == 0 {
+		return false, nil
+	}
+	// a failure here leaves the user a candidate: the history is checked as well
+	if _, err = r.db.Exec(ctx, fmt.Sprintf(RedactHistory, strings.Join(setHistory, ", ")), uuid); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (r *Repository) WarnInactive(ctx context.Context, seenBefore time.Time, limit int) ([]user.UUID, error) {
+	return r.queryUUIDs(ctx, WarnInactive, seenBefore, limit)
+}
+
+func (r *Repository) SuspendInactive(
+	ctx context.Context,
+	seenBefore time.Time,
+	warnedBefore *time.Time,
+	limit int,
+	inactiveDays int,
+) ([]user.UUID, error) {
+	return r.queryUUIDs(ctx, SuspendInactive,
+		seenBefore, warnedBefore, limit,
+		uuid.Nil, string(audit.ActionUserSuspended), inactiveDays,
+	)
+}
+
+func (r *Repository) FetchSuspendedAt(ctx context.Context, uuid user.UUID) (*time.Time, error) {
+	var t *time.Time
+	if err := r.db.QueryRow(ctx, SelectSuspendedAt, uuid).Scan(&t); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, user.ErrNotFound
+		}
+		return nil, err
+	}
+
+	return t, nil
+}
+
+func (r *Repository) Reactivate(ctx context.Context, uuid user.UUID) (bool, error) {
+	tag, err := r.db.Exec(ctx, Reactivate, uuid)
+	if err != nil {
+		return false, err
+	}
+	if tag.RowsAffected() > 0 {
+		return true, nil
+	}
+	// unknown or not suspended
+	if _, err = r.FetchSuspendedAt(ctx, uuid); err != nil {
+		return false, err
+	}
+
+	return false, nil
+}
+
+// queryUUIDs - the uuid column of the rows of sql
+func (r *Repository) queryUUIDs(ctx context.Context, sql string, args ...any) ([]user.UUID, error) {
+	rows, err := r.db.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var uuids []user.UUID
+	for rows.Next() {
+		var u user.UUID
+		if err = rows.Scan(&u); err != nil {
+			return nil, err
+		}
+		uuids = append(uuids, u)
+	}
+
+	return uuids, rows.Err()
+}
+
+func (r *Repository) FetchTokensValidAfter(ctx context.Context, uuid user.UUID) (*time.Time, error) {
+	var t *time.Time
+	if err := r.db.QueryRow(ctx, SelectTokensValidAfter, uuid).Scan(&t); err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, nil
 		}
 		return nil, err
 	}
 
-	return fromDBModel(u), err
+	return t, nil
+}
+
+func (r *Repository) FetchRole(ctx context.Context, uuid user.UUID) (string, error) {
+	var role string
+	if err := r.db.QueryRow(ctx, SelectRole, uuid).Scan(&role); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", user.ErrNotFound
+		}
+		return "", err
+	}
+
+	return role, nil
 }
 
 func (r *Repository) FetchInternalID(ctx context.Context, uuid user.UUID) (user.ID, error) {
 	var id uint64
 	if err := r.db.QueryRow(ctx, SelectIdByUUID, uuid.String()).Scan(&id); err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			return 0, fmt.Errorf("user not found by uuid %s: %w", uuid.String(), err)
+			return 0, fmt.Errorf("uuid %s: %w", uuid.String(), user.ErrNotFound)
 		}
 		return 0, err
 	}
@@ -204,9 +774,51 @@ func (r *Repository) FetchInternalID(ctx context.Context, uuid user.UUID) (user.
 	return user.ID(id), nil
 }
 
-func (r *Repository) DeleteUser(ctx context.Context, id user.ID) (*user.User, error) {
+func (r *Repository) FetchIdentityByID(ctx context.Context, id user.ID) (*user.Identity, error) {
+	return r.fetchIdentity(ctx, SelectIdentityByID, uint64(id))
+}
+
+func (r *Repository) FetchIdentityByUUID(ctx context.Context, uuid user.UUID) (*user.Identity, error) {
+	return r.fetchIdentity(ctx, SelectIdentityByUUID, uuid.String())
+}
+
+func (r *Repository) fetchIdentity(ctx context.Context, query string, arg any) (*user.Identity, error) {
+	var (
+		id    uint64
+		ident user.Identity
+	)
+	if err := r.db.QueryRow(ctx, query, arg).Scan(&id, &ident.UUID, &ident.DeletedAt); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, user.ErrNotFound
+		}
+		return nil, err
+	}
+	ident.ID = user.ID(id)
+
+	return &ident, nil
+}
+
+func (r *Repository) CheckDeletable(ctx context.Context, id user.ID) error {
+	var held, lastAdmin bool
+	if err := r.db.QueryRow(ctx, SelectDeleteChecks, id).Scan(&held, &lastAdmin); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return user.ErrNotFound
+		}
+		return err
+	}
+	switch {
+	case held:
+		return user.ErrLegalHold
+	case lastAdmin:
+		return user.ErrLastAdmin
+	}
+
+	return nil
+}
+
+func (r *Repository) DeleteUser(ctx context.Context, id user.ID, reason user.DeletionReason, actor user.UUID) (*user.User, error) {
 	u := new(User)
-	err := r.db.QueryRow(ctx, SoftDeleteUserByID, id).Scan(
+	err := r.db.QueryRow(ctx, SoftDeleteUserByID, id, string(reason), actor).Scan(
 		&u.ID,
 		&u.UUID,
 		&u.Email,
@@ -223,13 +835,235 @@ func (r *Repository) DeleteUser(ctx context.Context, id user.ID) (*user.User, er
 		&u.DeletedAt,
 		&u.DeletedReason,
 		&u.DeletedBy,
+		&u.PasswordResetRequired,
+		&u.Timezone,
+		&u.MiddleName,
+		&u.Suffix,
 	)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			return nil, nil
+			return nil, r.deleteErr(ctx, id)
+		}
+		return nil, err
+	}
+
+	return r.fromDBModel(ctx, u)
+}
+
+func (r *Repository) MergeUsers(ctx context.Context, winner, loser user.ID, actor user.UUID) (user.MergeResult, error) {
+	var res user.MergeResult
+	err := r.db.QueryRow(ctx, MergeUsers, winner, loser, actor).Scan(&res.Files, &res.Notes, &res.Audit)
+	if err != nil {
+		if !errors.Is(err, pgx.ErrNoRows) {
+			return user.MergeResult{}, err
+		}
+		var role string
+		if err = r.db.QueryRow(ctx, SelectActiveRoleByID, winner).Scan(&role); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return user.MergeResult{}, user.ErrNotFound
+			}
+			return user.MergeResult{}, err
+		}
+		return user.MergeResult{}, r.deleteErr(ctx, loser)
+	}
+
+	return res, nil
+}
+
+func (r *Repository) FetchHistory(ctx context.Context, id user.ID) ([]user.Version, error) {
+	rows, err := r.db.Query(ctx, SelectHistory, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var versions []user.Version
+	for rows.Next() {
+		v, err := r.scanVersion(ctx, rows)
+		if err != nil {
+			return nil, err
+		}
+		versions = append(versions, *v)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(versions) == 0 {
+		return nil, user.ErrNotFound
+	}
+
+	return versions, nil
+}
+
+func (r *Repository) FetchVersionAt(ctx context.Context, id user.ID, at time.Time) (*user.Version, error) {
+	v, err := r.scanVersion(ctx, r.db.QueryRow(ctx, SelectVersionAt, id, at))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, user.ErrNotFound
+		}
+		return nil, err
+	}
+
+	return v, nil
+}
+
+func (r *Repository) scanVersion(ctx context.Context, row pgx.Row) (*user.Version, error) {
+	var (
+		v user.Version
+		u = new(User)
+	)
+	if err := row.Scan(
+		&u.ID,
+		&u.UUID,
+		&u.Email,
+		&u.PasswordHash,
+		&u.Role,
+		&u.Name,
+		&u.Lastname,
+		&u.BirthDate,
+		&u.Phone,
+
+		&u.CreatedAt,
+		&u.UpdatedAt,
+
+		&u.DeletedAt,
+		&u.DeletedReason,
+		&u.DeletedBy,
+		&u.PasswordResetRequired,
+		&u.Timezone,
+		&u.MiddleName,
+		&u.Suffix,
+
+		&v.ValidFrom,
+		&v.ValidTo,
+	); err != nil {
+		return nil, err
+	}
+	if err := r.toDomain(ctx, u, &v.User); err != nil {
+		return nil, err
+	}
+
+	return &v, nil
+}
+
+// lastAdminErr tells apart a not deleted(last) admin from a missing or already
+// deleted user
+// deleteErr - why an active user was not deleted: the legal hold, the last
+// admin or not found
+func (r *Repository) deleteErr(ctx context.Context, id user.ID) error {
+	var (
+		role string
+		held bool
+	)
+	if err := r.db.QueryRow(ctx, SelectDeleteBlockers, id).Scan(&role, &held); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return user.ErrNotFound
+		}
+		return err
+	}
+	if held {
+		return user.ErrLegalHold
+	}
+	if role == user.RoleAdmin {
+		return user.ErrLastAdmin
+	}
+
+	// deleted by a concurrent request between the two queries
+	return user.ErrNotFound
+}
+
+func (r *Repository) FetchDeletedUsers(ctx context.Context, reason user.DeletionReason, p pagination.Params) (user.Users, error) {
+	clause, args := postgres.PageClause(p, SortColumns, r.pageSize, 2)
+	rows, err := r.db.Query(ctx, SelectDeletedUsers+clause, append([]any{string(reason)}, args...)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var us user.Users
+	for rows.Next() {
+		u := new(User)
+
+		if err = rows.Scan(
+			&u.ID,
+			&u.UUID,
+			&u.Email,
+			&u.PasswordHash,
+			&u.Role,
+			&u.Name,
+			&u.Lastname,
+			&u.BirthDate,
+			&u.Phone,
+
+			&u.CreatedAt,
+			&u.UpdatedAt,
+
+			&u.DeletedAt,
+			&u.DeletedReason,
+			&u.DeletedBy,
+			&u.PasswordResetRequired,
+			&u.Timezone,
+			&u.MiddleName,
+			&u.Suffix,
+		); err != nil {
+			return nil, err
+		}
+
+		var du *user.User
+		if du, err = r.fromDBModel(ctx, u); err != nil {
+			return nil, err
+		}
+		us = append(us, du)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return us, nil
+}
+
+func (r *Repository) SetLegalHold(ctx context.Context, uuid user.UUID, reason string) (*user.LegalHold, error) {
+	h := new(user.LegalHold)
+	if err := r.db.QueryRow(ctx, SetLegalHold, uuid, reason).Scan(&h.Reason, &h.Since); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, user.ErrNotFound
 		}
 		return nil, err
 	}
 
-	return fromDBModel(u), err
+	return h, nil
+}
+
+func (r *Repository) ReleaseLegalHold(ctx context.Context, uuid user.UUID) (bool, error) {
+	tag, err := r.db.Exec(ctx, ReleaseLegalHold, uuid)
+	if err != nil {
+		return false, err
+	}
+	if tag.RowsAffected() > 0 {
+		return true, nil
+	}
+	// unknown or not on a hold
+	if _, err = r.FetchLegalHold(ctx, uuid); err != nil {
+		return false, err
+	}
+
+	return false, nil
+}
+
+func (r *Repository) FetchLegalHold(ctx context.Context, uuid user.UUID) (*user.LegalHold, error) {
+	var (
+		reason string
+		since  *time.Time
+	)
+	if err := r.db.QueryRow(ctx, SelectLegalHold, uuid).Scan(&reason, &since); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, user.ErrNotFound
+		}
+		return nil, err
+	}
+	if since == nil {
+		return nil, nil
+	}
+
+	return &user.LegalHold{Reason: reason, Since: *since}, nil
 }
@@ -0,0 +1,83 @@
+package user
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"user-manager-api/internal/domain/pagination"
+	domain "user-manager-api/internal/domain/user"
+	"user-manager-api/internal/infrastructure/db/postgres"
+)
+
+type (
+	// pageDB - a users page of a row per email, closed reports the rows are released
+	pageDB struct {
+		postgres.DB
+		emails []string
+		closed bool
+	}
+	pageRows struct {
+		pgx.Rows
+		db  *pageDB
+		row int
+	}
+)
+
+func (d *pageDB) Query(context.Context, string, ...any) (pgx.Rows, error) {
+	return &pageRows{db: d}, nil
+}
+
+func (r *pageRows) Next() bool {
+	r.row++
+	return r.row <= len(r.db.emails)
+}
+
+// Scan fills the email and the files count only
+func (r *pageRows) Scan(dest ...any) error {
+	*dest[2].(*string) = r.db.emails[r.row-1]
+	*dest[18].(*uint64) = uint64(r.row)
+	return nil
+}
+
+func (r *pageRows) Close()     { r.db.closed = true }
+func (r *pageRows) Err() error { return nil }
+
+func TestRepository_StreamUsers(t *testing.T) {
+	db := &pageDB{emails: []string{"user1@example.com", "user2@example.com", "user3@example.com"}}
+	r := newTestRepository(t)
+	r.db, r.pageSize = db, 50
+
+	var emails []string
+	err := r.StreamUsers(context.Background(), pagination.Params{}, func(u *domain.User) error {
+		assert.True(t, db.closed, "the rows are released before the page is written")
+		emails = append(emails, u.Email)
+		assert.Equal(t, uint64(len(emails)), u.Files.Count)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"user1@example.com", "user2@example.com", "user3@example.com"}, emails)
+}
+
+// BenchmarkRepository_StreamUsers - the reading and the mapping of a users
+// page(default size, no PII) without the database: the pooled page buffer
+func BenchmarkRepository_StreamUsers(b *testing.B) {
+	db := &pageDB{emails: make([]string, 50)}
+	for i := range db.emails {
+		db.emails[i] = fmt.Sprintf("user%d@example.com", i)
+	}
+	r := newTestRepository(b)
+	r.db, r.pageSize = db, 50
+	ctx := context.Background()
+
+	b.ReportAllocs()
+	for b.Loop() {
+		if err := r.StreamUsers(ctx, pagination.Params{}, func(*domain.User) error { return nil }); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package user_file
+
+import (
+	"fmt"
+	"strings"
+
+	"user-manager-api/internal/domain/user_file"
+)
+
+// filterClause - " AND ..." conditions of f, argN - next placeholder.
+func filterClause(f user_file.Filter, argN int) (string, []any) {
+	var (
+		b    strings.Builder
+		args []any
+	)
+	add := func(cond string, arg any) {
+		fmt.Fprintf(&b, " AND "+cond, argN)
+		args = append(args, arg)
+		argN++
+	}
+
+	if f.MimeType != "" {
+		if typ, ok := strings.CutSuffix(f.MimeType, "/*"); ok {
+			add("split_part(mime_type, '/', 1) = $%d", typ)
+		} else {
+			add("mime_type = $%d", f.MimeType)
+		}
+	}
+	if f.MinSize != nil {
+		add("size_bytes >= $%d", *f.MinSize)
+	}
+	if f.MaxSize != nil {
+		add("size_bytes <= $%d", *f.MaxSize)
+	}
+	if f.UploadedFrom != nil {
+		add("created_at >= $%d", *f.UploadedFrom)
+	}
+	if f.UploadedTo != nil {
+		add("created_at < $%d", *f.UploadedTo)
+	}
+	if f.UserUUID != nil {
+		add("user_id = (SELECT id FROM users WHERE uuid = $%d)", *f.UserUUID)
+	}
+
+	return b.String(), args
+}
@@ -1,26 +1,36 @@
 package user_file
 
 import (
+	"user-manager-api/internal/domain/user"
 	domain "user-manager-api/internal/domain/user_file"
 )
 
 func fromDBModel(model *UserFile) *domain.UserFile {
-	var uf = &domain.UserFile{
-		UUID:   model.UUID,
-		UserID: model.UserID,
+	uf := new(domain.UserFile)
+	toDomain(model, uf)
 
-		Bucket:      model.Bucket,
-		StorageKey:  model.StorageKey,
-		FileName:    model.FileName,
-		MimeType:    model.MimeType,
-		SizeBytes:   model.SizeBytes,
-		DownloadURL: model.DownloadURL,
+	return uf
+}
+
+func toDomain(model *UserFile, uf *domain.UserFile) {
+	*uf = domain.UserFile{
+		UUID:     model.UUID,
+		UserID:   (*user.ID)(model.UserID),
+		UserUUID: model.UserUUID,
+
+		Bucket:       model.Bucket,
+		StorageKey:   model.StorageKey,
+		FileName:     model.FileName,
+		MimeType:     model.MimeType,
+		SizeBytes:    model.SizeBytes,
+		DownloadURL:  model.DownloadURL,
+		ThumbnailURL: model.ThumbnailURL,
+		Tags:         model.Tags,
+		Folder:       model.Folder,
 
 		CreatedAt: model.CreatedAt,
 		DeletedAt: model.DeletedAt,
 	}
-
-	return uf
 }
 
 func fromDBModels(models *UserFiles) domain.UserFiles {
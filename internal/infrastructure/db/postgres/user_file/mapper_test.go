@@ -0,0 +1,33 @@
+package user_file
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"user-manager-api/internal/domain/user"
+	userDB "user-manager-api/internal/infrastructure/db/postgres/user"
+)
+
+func TestFromDBModel_UserID(t *testing.T) {
+	type tc struct {
+		name   string
+		userID *userDB.ID
+		want   *user.ID
+	}
+	id, want := userDB.ID(42), user.ID(42)
+	tests := []tc{
+		{"owner", &id, &want},
+		// NULL user_id
+		{"no owner", nil, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uf := fromDBModel(&UserFile{UUID: uuid.New(), UserID: tt.userID, FileName: "a.png"})
+			assert.Equal(t, tt.want, uf.UserID)
+			assert.Equal(t, "a.png", uf.FileName)
+		})
+	}
+}
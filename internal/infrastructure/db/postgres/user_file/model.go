@@ -12,13 +12,18 @@ type (
 		ID     uint64
 		UUID   uuid.UUID
 		UserID *userDB.ID
+		// UserUUID - selected by the admin browsing only
+		UserUUID uuid.UUID
 
-		Bucket      string
-		StorageKey  string
-		FileName    string
-		MimeType    string
-		SizeBytes   uint64
-		DownloadURL string
+		Bucket       string
+		StorageKey   string
+		FileName     string
+		MimeType     string
+		SizeBytes    uint64
+		DownloadURL  string
+		ThumbnailURL string
+		Tags         []string
+		Folder       string
 
 		CreatedAt time.Time
 		DeletedAt *time.Time
@@ -1,21 +1,189 @@
 package user_file
 
+// SortColumns - whitelist of "sort" query param values
+var SortColumns = map[string]string{
+	"file_name":  "file_name",
+	"size_bytes": "size_bytes",
+}
+
 const (
 	SelectUserFiles = `
-		SELECT id, uuid, user_id, bucket, storage_key, file_name, mime_type, size_bytes, download_url, created_at, deleted_at
+		SELECT id, uuid, user_id, bucket, storage_key, file_name, mime_type, size_bytes, download_url, thumbnail_url, tags, folder, created_at, deleted_at
 		FROM user_files
-		WHERE user_id = $1 AND deleted_at IS NULL
-		LIMIT 50 OFFSET ( ($2 - 1) * 50 )
+		WHERE user_id = $1 AND deleted_at IS NULL AND tags @> $2 AND ($3::text IS NULL OR folder = $3)`
+	// the owner via a subquery: a join would make the PageClause columns ambiguous
+	SelectFiles = `
+		SELECT id, uuid, user_id, bucket, storage_key, file_name, mime_type, size_bytes, download_url, thumbnail_url, tags, folder, created_at, deleted_at,
+		       (SELECT u.uuid FROM users u WHERE u.id = user_files.user_id)
+		FROM user_files
+		WHERE deleted_at IS NULL`
+	// %s - the filter conditions
+	SelectFilesStats = `
+		SELECT mime_type, count(*), COALESCE(sum(size_bytes), 0)
+		FROM user_files
+		WHERE deleted_at IS NULL%s
+		GROUP BY mime_type
+		ORDER BY 3 DESC, mime_type
+	`
+	// the read model maintained by the event consumer(see StatsService)
+	SelectSummaries = `
+		SELECT u.uuid, c.files_count, c.total_bytes
+		FROM user_file_counts c
+		JOIN users u ON u.id = c.user_id
+		WHERE u.uuid = ANY($1)
 	`
 	InsertUserFile = `
-		INSERT INTO user_files (user_id, bucket, storage_key, file_name, mime_type, size_bytes, download_url)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		INSERT INTO user_files (user_id, bucket, storage_key, file_name, mime_type, size_bytes, download_url, tags, folder)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
 		RETURNING
-		  id, uuid, user_id, bucket, storage_key, file_name, mime_type, size_bytes, download_url, created_at, deleted_at
+		  id, uuid, user_id, bucket, storage_key, file_name, mime_type, size_bytes, download_url, thumbnail_url, tags, folder, created_at, deleted_at
 	`
+	// the files on a legal hold, of their own or of the user, are kept
 	SoftDeleteUserFiles = `
 		UPDATE user_files
 		SET deleted_at = now()
+		WHERE user_id = $1 AND deleted_at IS NULL AND NOT legal_hold AND tags @> $2
+		  AND NOT EXISTS (SELECT 1 FROM users u WHERE u.id = $1 AND u.legal_hold_since IS NOT NULL)
+		RETURNING storage_key, COALESCE(thumbnail_url, '')
+	`
+	SelectStorageRefs = `
+		SELECT uuid, storage_key, created_at
+		FROM user_files
+		WHERE deleted_at IS NULL AND storage_key LIKE $1 || '%'
+	`
+	// SelectObjectOwner - the owner and the type of the live file stored under
+	// $1 or whose thumbnail has the URL $2
+	SelectObjectOwner = `
+		SELECT u.uuid, f.mime_type
+		FROM user_files f
+		JOIN users u ON u.id = f.user_id
+		WHERE f.deleted_at IS NULL AND u.deleted_at IS NULL
+		  AND (f.storage_key = $1 OR f.thumbnail_url = $2)
+		LIMIT 1
+	`
+	// SelectDeletableFiles - the live files of $1 off a legal hold of their own
+	SelectDeletableFiles = `
+		SELECT uuid, storage_key, COALESCE(thumbnail_url, '')
+		FROM user_files
+		WHERE user_id = $1 AND deleted_at IS NULL AND NOT legal_hold
+		ORDER BY id
+	`
+	SoftDeleteUserFilesByUUIDs = `
+		UPDATE user_files
+		SET deleted_at = now()
+		WHERE uuid = ANY($1) AND deleted_at IS NULL
+	`
+	UpdateThumbnailURL = `
+		UPDATE user_files
+		SET thumbnail_url = $2
+		WHERE uuid = $1 AND deleted_at IS NULL
+	`
+	// $2 - the prefix of the subfolders: "parent/", '' for the root
+	SelectFolders = `
+		SELECT $2 || split_part(substr(folder, length($2) + 1), '/', 1), count(*), COALESCE(sum(size_bytes), 0)
+		FROM user_files
+		WHERE user_id = $1 AND deleted_at IS NULL AND starts_with(folder, $2) AND length(folder) > length($2)
+		GROUP BY 1
+		ORDER BY 1
+	`
+	MoveUserFile = `
+		UPDATE user_files
+		SET folder = COALESCE($3, folder), file_name = COALESCE($4, file_name)
+		WHERE user_id = $1 AND uuid = $2 AND deleted_at IS NULL
+		RETURNING
+		  id, uuid, user_id, bucket, storage_key, file_name, mime_type, size_bytes, download_url, thumbnail_url, tags, folder, created_at, deleted_at
+	`
+	// ltrim: the subfolders of a folder moved to the root
+	MoveFolder = `
+		UPDATE user_files
+		SET folder = ltrim($3 || substr(folder, length($2) + 1), '/')
+		WHERE user_id = $1 AND deleted_at IS NULL AND (folder = $2 OR starts_with(folder, $2 || '/'))
+	`
+	UpsertText = `
+		INSERT INTO user_file_texts (file_id, status, body)
+		SELECT id, $2, $3 FROM user_files WHERE uuid = $1
+		ON CONFLICT (file_id) DO UPDATE
+		SET status = EXCLUDED.status, body = EXCLUDED.body, extracted_at = now()
+	`
+	SelectText = `
+		SELECT t.status, t.body, t.extracted_at
+		FROM user_file_texts t
+		JOIN user_files f ON f.id = t.file_id
+		WHERE f.user_id = $1 AND f.uuid = $2 AND f.deleted_at IS NULL
+	`
+	SearchUserFiles = `
+		SELECT f.id, f.uuid, f.user_id, f.bucket, f.storage_key, f.file_name, f.mime_type, f.size_bytes, f.download_url, f.thumbnail_url, f.tags, f.folder, f.created_at, f.deleted_at
+		FROM user_files f
+		JOIN user_file_texts t ON t.file_id = f.id
+		WHERE f.user_id = $1 AND f.deleted_at IS NULL AND t.search_vector @@ websearch_to_tsquery('simple', $2)
+		ORDER BY ts_rank(t.search_vector, websearch_to_tsquery('simple', $2)) DESC, f.created_at DESC
+		LIMIT $3
+	`
+	SelectRetentionRules = `
+		SELECT uuid, mime_type, tag, retention_days, created_at
+		FROM file_retention_rules
+		ORDER BY created_at, id
+	`
+	InsertRetentionRule = `
+		INSERT INTO file_retention_rules (mime_type, tag, retention_days)
+		VALUES ($1, $2, $3)
+		RETURNING uuid, mime_type, tag, retention_days, created_at
+	`
+	DeleteRetentionRule = `
+		DELETE FROM file_retention_rules
+		WHERE uuid = $1
+	`
+	UpdateRetention = `
+		UPDATE user_files
+		SET retain_until = $2, legal_hold = $3
+		WHERE uuid = $1 AND deleted_at IS NULL
+	`
+	// a file expires at retain_until or, without it, the longest of the matching
+	// rules after the upload; no rule - kept. The files of a user on a legal hold
+	// are kept as well. SKIP LOCKED - instances running the
+	// job at once take different files
+	PurgeExpiredFiles = `
+		UPDATE user_files f
+		SET deleted_at = now()
+		FROM users u
+		WHERE u.id = f.user_id AND f.id IN (
+		    SELECT c.id
+		    FROM user_files c
+		    WHERE c.deleted_at IS NULL AND NOT c.legal_hold
+		      AND NOT EXISTS (SELECT 1 FROM users h WHERE h.id = c.user_id AND h.legal_hold_since IS NOT NULL)
+		      AND COALESCE(c.retain_until, c.created_at + (
+		        SELECT make_interval(days => max(r.retention_days))
+		        FROM file_retention_rules r
+		        WHERE (r.tag <> '' AND r.tag = ANY(c.tags))
+		           OR (r.mime_type <> '' AND (r.mime_type = c.mime_type
+		               OR r.mime_type = split_part(c.mime_type, '/', 1) || '/*'))
+		    )) < $1
+		    ORDER BY c.id
+		    LIMIT $2
+		    FOR UPDATE SKIP LOCKED
+		)
+		RETURNING f.uuid, f.storage_key, COALESCE(f.thumbnail_url, ''), f.created_at, u.uuid
+	`
+	SelectUsedBytes = `
+		SELECT COALESCE(sum(size_bytes), 0)
+		FROM user_files
 		WHERE user_id = $1 AND deleted_at IS NULL
 	`
+	SelectUploadPolicies = `
+		SELECT org, allowed_types, max_file_size, quota_bytes, updated_at
+		FROM org_upload_policies
+		ORDER BY org
+	`
+	UpsertUploadPolicy = `
+		INSERT INTO org_upload_policies (org, allowed_types, max_file_size, quota_bytes)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (org) DO UPDATE
+		SET allowed_types = EXCLUDED.allowed_types, max_file_size = EXCLUDED.max_file_size,
+		    quota_bytes = EXCLUDED.quota_bytes, updated_at = now()
+		RETURNING org, allowed_types, max_file_size, quota_bytes, updated_at
+	`
+	DeleteUploadPolicy = `
+		DELETE FROM org_upload_policies
+		WHERE org = $1
+	`
 )
@@ -2,22 +2,106 @@ package user_file
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"time"
+	"user-manager-api/internal/domain/pagination"
 	"user-manager-api/internal/domain/user"
 	"user-manager-api/internal/domain/user_file"
+	"user-manager-api/internal/infrastructure/db/postgres"
 
-	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 )
 
 type Repository struct {
-	db *pgxpool.Pool
+	db       postgres.DB
+	pageSize int
 }
 
-func NewRepository(db *pgxpool.Pool) user_file.Repository {
-	return &Repository{db: db}
+func NewRepository(db postgres.DB, pageSize int) user_file.Repository {
+	return &Repository{db: db, pageSize: pageSize}
 }
 
-func (r *Repository) FetchUserFiles(ctx context.Context, userID user.ID, page int) (user_file.UserFiles, error) {
-	rows, err := r.db.Query(ctx, SelectUserFiles, userID, page)
+// StreamUserFiles - as user.Repository.StreamUsers the page is read first and
+// the rows are closed before fn is called. One file is reused for all the rows.
+func (r *Repository) StreamUserFiles(
+	ctx context.Context,
+	userID user.ID,
+	p pagination.Params,
+	tags []string,
+	folder *string,
+	fn func(uf *user_file.UserFile) error,
+) error {
+	page, err := r.fetchUserFilesPage(ctx, userID, p, tags, folder)
+	if err != nil {
+		return err
+	}
+
+	var uf user_file.UserFile
+	for idx := range page {
+		toDomain(&page[idx], &uf)
+
+		if err = fn(&uf); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *Repository) fetchUserFilesPage(
+	ctx context.Context,
+	userID user.ID,
+	p pagination.Params,
+	tags []string,
+	folder *string,
+) ([]UserFile, error) {
+	clause, args := postgres.PageClause(p, SortColumns, r.pageSize, 4)
+	rows, err := r.db.Query(ctx, SelectUserFiles+clause, append([]any{userID, nonNilTags(tags), folder}, args...)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	perPage := p.PerPage
+	if perPage == 0 {
+		perPage = r.pageSize
+	}
+	page := make([]UserFile, 0, perPage)
+	for rows.Next() {
+		page = append(page, UserFile{})
+		m := &page[len(page)-1]
+
+		if err = rows.Scan(
+			&m.ID,
+			&m.UUID,
+			&m.UserID,
+
+			&m.Bucket,
+			&m.StorageKey,
+			&m.FileName,
+			&m.MimeType,
+			&m.SizeBytes,
+			&m.DownloadURL,
+			&m.ThumbnailURL,
+			&m.Tags,
+			&m.Folder,
+
+			&m.CreatedAt,
+			&m.DeletedAt,
+		); err != nil {
+			return nil, err
+		}
+	}
+
+	return page, rows.Err()
+}
+
+func (r *Repository) FetchFiles(ctx context.Context, f user_file.Filter, p pagination.Params) (user_file.UserFiles, error) {
+	where, args := filterClause(f, 1)
+	clause, pageArgs := postgres.PageClause(p, SortColumns, r.pageSize, len(args)+1)
+	rows, err := r.db.Query(ctx, SelectFiles+where+clause, append(args, pageArgs...)...)
 	if err != nil {
 		return nil, err
 	}
@@ -38,9 +122,13 @@ func (r *Repository) FetchUserFiles(ctx context.Context, userID user.ID, page in
 			&uf.MimeType,
 			&uf.SizeBytes,
 			&uf.DownloadURL,
+			&uf.ThumbnailURL,
+			&uf.Tags,
+			&uf.Folder,
 
 			&uf.CreatedAt,
 			&uf.DeletedAt,
+			&uf.UserUUID,
 		); err != nil {
 			return nil, err
 		}
@@ -54,13 +142,63 @@ func (r *Repository) FetchUserFiles(ctx context.Context, userID user.ID, page in
 	return fromDBModels(&ufs), nil
 }
 
+func (r *Repository) FetchStats(ctx context.Context, f user_file.Filter) (*user_file.Stats, error) {
+	where, args := filterClause(f, 1)
+	rows, err := r.db.Query(ctx, fmt.Sprintf(SelectFilesStats, where), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	stats := &user_file.Stats{ByMimeType: []user_file.MimeTypeStats{}}
+	for rows.Next() {
+		var m user_file.MimeTypeStats
+		if err = rows.Scan(&m.MimeType, &m.FilesCount, &m.TotalBytes); err != nil {
+			return nil, err
+		}
+		stats.FilesCount += m.FilesCount
+		stats.TotalBytes += m.TotalBytes
+		stats.ByMimeType = append(stats.ByMimeType, m)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return stats, nil
+}
+
+func (r *Repository) FetchSummaries(ctx context.Context, userUUIDs []uuid.UUID) (map[uuid.UUID]user.FilesSummary, error) {
+	rows, err := r.db.Query(ctx, SelectSummaries, userUUIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	summaries := make(map[uuid.UUID]user.FilesSummary, len(userUUIDs))
+	for rows.Next() {
+		var (
+			id uuid.UUID
+			s  user.FilesSummary
+		)
+		if err = rows.Scan(&id, &s.Count, &s.TotalBytes); err != nil {
+			return nil, err
+		}
+		summaries[id] = s
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return summaries, nil
+}
+
 func (r *Repository) CreateUserFile(ctx context.Context, userID user.ID, req *user_file.UserFile) (*user_file.UserFile, error) {
 	uf := new(UserFile)
 
 	err := r.db.QueryRow(
 		ctx,
 		InsertUserFile,
-		userID, req.Bucket, req.StorageKey, req.FileName, req.MimeType, req.SizeBytes, req.DownloadURL,
+		userID, req.Bucket, req.StorageKey, req.FileName, req.MimeType, req.SizeBytes, req.DownloadURL, nonNilTags(req.Tags), req.Folder,
 	).Scan(
 		&uf.ID,
 		&uf.UUID,
@@ -72,6 +210,9 @@ func (r *Repository) CreateUserFile(ctx context.Context, userID user.ID, req *us
 		&uf.MimeType,
 		&uf.SizeBytes,
 		&uf.DownloadURL,
+		&uf.ThumbnailURL,
+		&uf.Tags,
+		&uf.Folder,
 
 		&uf.CreatedAt,
 		&uf.DeletedAt,
@@ -83,7 +224,366 @@ func (r *Repository) CreateUserFile(ctx context.Context, userID user.ID, req *us
 	return fromDBModel(uf), err
 }
 
-func (r *Repository) DeleteUserFiles(ctx context.Context, userID user.ID) error {
-	_, err := r.db.Exec(ctx, SoftDeleteUserFiles, userID)
+func (r *Repository) DeleteUserFiles(ctx context.Context, userID user.ID, tags []string) (user_file.UserFiles, error) {
+	rows, err := r.db.Query(ctx, SoftDeleteUserFiles, userID, nonNilTags(tags))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ufs user_file.UserFiles
+	for rows.Next() {
+		uf := new(user_file.UserFile)
+		if err = rows.Scan(&uf.StorageKey, &uf.ThumbnailURL); err != nil {
+			return nil, err
+		}
+		ufs = append(ufs, uf)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return ufs, nil
+}
+
+func (r *Repository) FetchStorageRefs(ctx context.Context, prefix string) (user_file.StorageRefs, error) {
+	rows, err := r.db.Query(ctx, SelectStorageRefs, prefix)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var refs user_file.StorageRefs
+	for rows.Next() {
+		var ref user_file.StorageRef
+		if err = rows.Scan(&ref.UUID, &ref.StorageKey, &ref.CreatedAt); err != nil {
+			return nil, err
+		}
+		refs = append(refs, ref)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return refs, nil
+}
+
+func (r *Repository) FetchObjectOwner(ctx context.Context, storageKey, thumbnailURL string) (*user_file.ObjectOwner, error) {
+	owner := new(user_file.ObjectOwner)
+	if err := r.db.QueryRow(ctx, SelectObjectOwner, storageKey, thumbnailURL).Scan(&owner.UserUUID, &owner.MimeType); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return owner, nil
+}
+
+func (r *Repository) FetchDeletableFiles(ctx context.Context, userID user.ID) (user_file.UserFiles, error) {
+	rows, err := r.db.Query(ctx, SelectDeletableFiles, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ufs user_file.UserFiles
+	for rows.Next() {
+		uf := new(user_file.UserFile)
+		if err = rows.Scan(&uf.UUID, &uf.StorageKey, &uf.ThumbnailURL); err != nil {
+			return nil, err
+		}
+		ufs = append(ufs, uf)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return ufs, nil
+}
+
+func (r *Repository) DeleteUserFilesByUUIDs(ctx context.Context, uuids []uuid.UUID) error {
+	_, err := r.db.Exec(ctx, SoftDeleteUserFilesByUUIDs, uuids)
+	return err
+}
+
+func (r *Repository) UpdateThumbnailURL(ctx context.Context, fileUUID uuid.UUID, url string) error {
+	_, err := r.db.Exec(ctx, UpdateThumbnailURL, fileUUID, url)
+	return err
+}
+
+func (r *Repository) FetchFolders(ctx context.Context, userID user.ID, parent string) (user_file.Folders, error) {
+	prefix := parent
+	if prefix != "" {
+		prefix += "/"
+	}
+	rows, err := r.db.Query(ctx, SelectFolders, userID, prefix)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	folders := user_file.Folders{}
+	for rows.Next() {
+		var f user_file.Folder
+		if err = rows.Scan(&f.Path, &f.FilesCount, &f.TotalBytes); err != nil {
+			return nil, err
+		}
+		folders = append(folders, f)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return folders, nil
+}
+
+func (r *Repository) MoveUserFile(
+	ctx context.Context,
+	userID user.ID,
+	fileUUID uuid.UUID,
+	folder, fileName *string,
+) (*user_file.UserFile, error) {
+	uf := new(UserFile)
+
+	err := r.db.QueryRow(ctx, MoveUserFile, userID, fileUUID, folder, fileName).Scan(
+		&uf.ID,
+		&uf.UUID,
+		&uf.UserID,
+
+		&uf.Bucket,
+		&uf.StorageKey,
+		&uf.FileName,
+		&uf.MimeType,
+		&uf.SizeBytes,
+		&uf.DownloadURL,
+		&uf.ThumbnailURL,
+		&uf.Tags,
+		&uf.Folder,
+
+		&uf.CreatedAt,
+		&uf.DeletedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return fromDBModel(uf), nil
+}
+
+func (r *Repository) SaveText(ctx context.Context, fileUUID uuid.UUID, t user_file.Text) error {
+	_, err := r.db.Exec(ctx, UpsertText, fileUUID, t.Status, t.Body)
 	return err
 }
+
+func (r *Repository) FetchText(ctx context.Context, userID user.ID, fileUUID uuid.UUID) (*user_file.Text, error) {
+	t := new(user_file.Text)
+	err := r.db.QueryRow(ctx, SelectText, userID, fileUUID).Scan(&t.Status, &t.Body, &t.ExtractedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return t, nil
+}
+
+func (r *Repository) SearchUserFiles(ctx context.Context, userID user.ID, query string, limit int) (user_file.UserFiles, error) {
+	rows, err := r.db.Query(ctx, SearchUserFiles, userID, query, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ufs UserFiles
+	for rows.Next() {
+		uf := new(UserFile)
+
+		if err = rows.Scan(
+			&uf.ID,
+			&uf.UUID,
+			&uf.UserID,
+
+			&uf.Bucket,
+			&uf.StorageKey,
+			&uf.FileName,
+			&uf.MimeType,
+			&uf.SizeBytes,
+			&uf.DownloadURL,
+			&uf.ThumbnailURL,
+			&uf.Tags,
+			&uf.Folder,
+
+			&uf.CreatedAt,
+			&uf.DeletedAt,
+		); err != nil {
+			return nil, err
+		}
+
+		ufs = append(ufs, uf)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return fromDBModels(&ufs), nil
+}
+
+func (r *Repository) MoveFolder(ctx context.Context, userID user.ID, from, to string) (int64, error) {
+	tag, err := r.db.Exec(ctx, MoveFolder, userID, from, to)
+	if err != nil {
+		return 0, err
+	}
+
+	return tag.RowsAffected(), nil
+}
+
+func (r *Repository) FetchRetentionRules(ctx context.Context) (user_file.RetentionRules, error) {
+	rows, err := r.db.Query(ctx, SelectRetentionRules)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	rules := user_file.RetentionRules{}
+	for rows.Next() {
+		var rule user_file.RetentionRule
+		if err = rows.Scan(&rule.UUID, &rule.MimeType, &rule.Tag, &rule.Days, &rule.CreatedAt); err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return rules, nil
+}
+
+func (r *Repository) CreateRetentionRule(ctx context.Context, req user_file.RetentionRule) (*user_file.RetentionRule, error) {
+	rule := new(user_file.RetentionRule)
+	err := r.db.QueryRow(ctx, InsertRetentionRule, req.MimeType, req.Tag, req.Days).
+		Scan(&rule.UUID, &rule.MimeType, &rule.Tag, &rule.Days, &rule.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return rule, nil
+}
+
+func (r *Repository) DeleteRetentionRule(ctx context.Context, ruleUUID uuid.UUID) (bool, error) {
+	tag, err := r.db.Exec(ctx, DeleteRetentionRule, ruleUUID)
+	if err != nil {
+		return false, err
+	}
+
+	return tag.RowsAffected() > 0, nil
+}
+
+func (r *Repository) SetRetention(ctx context.Context, fileUUID uuid.UUID, ret user_file.Retention) (bool, error) {
+	tag, err := r.db.Exec(ctx, UpdateRetention, fileUUID, ret.RetainUntil, ret.LegalHold)
+	if err != nil {
+		return false, err
+	}
+
+	return tag.RowsAffected() > 0, nil
+}
+
+func (r *Repository) PurgeExpiredFiles(ctx context.Context, now time.Time, limit int) (user_file.UserFiles, error) {
+	rows, err := r.db.Query(ctx, PurgeExpiredFiles, now, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ufs user_file.UserFiles
+	for rows.Next() {
+		uf := new(user_file.UserFile)
+		if err = rows.Scan(&uf.UUID, &uf.StorageKey, &uf.ThumbnailURL, &uf.CreatedAt, &uf.UserUUID); err != nil {
+			return nil, err
+		}
+		ufs = append(ufs, uf)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return ufs, nil
+}
+
+func (r *Repository) FetchUsedBytes(ctx context.Context, userID user.ID) (uint64, error) {
+	var used int64
+	if err := r.db.QueryRow(ctx, SelectUsedBytes, userID).Scan(&used); err != nil {
+		return 0, err
+	}
+
+	return uint64(used), nil
+}
+
+func (r *Repository) FetchUploadPolicies(ctx context.Context) (user_file.UploadPolicies, error) {
+	rows, err := r.db.Query(ctx, SelectUploadPolicies)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	policies := user_file.UploadPolicies{}
+	for rows.Next() {
+		p, err := scanUploadPolicy(rows)
+		if err != nil {
+			return nil, err
+		}
+		policies = append(policies, *p)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return policies, nil
+}
+
+func (r *Repository) SaveUploadPolicy(ctx context.Context, p user_file.UploadPolicy) (*user_file.UploadPolicy, error) {
+	return scanUploadPolicy(r.db.QueryRow(
+		ctx,
+		UpsertUploadPolicy,
+		p.Org,
+		nonNilTags(p.AllowedTypes),
+		int64(p.MaxFileSize),
+		int64(p.QuotaBytes),
+	))
+}
+
+func (r *Repository) DeleteUploadPolicy(ctx context.Context, org string) (bool, error) {
+	tag, err := r.db.Exec(ctx, DeleteUploadPolicy, org)
+	if err != nil {
+		return false, err
+	}
+
+	return tag.RowsAffected() > 0, nil
+}
+
+func scanUploadPolicy(row pgx.Row) (*user_file.UploadPolicy, error) {
+	var (
+		p                  user_file.UploadPolicy
+		maxFileSize, quota int64
+	)
+	if err := row.Scan(&p.Org, &p.AllowedTypes, &maxFileSize, &quota, &p.UpdatedAt); err != nil {
+		return nil, err
+	}
+	p.MaxFileSize, p.QuotaBytes = uint64(maxFileSize), uint64(quota)
+
+	return &p, nil
+}
+
+// nonNilTags - nil is sent as NULL and "tags @> NULL" never matches,
+// an empty array matches every row
+func nonNilTags(tags []string) []string {
+	if tags == nil {
+		return []string{}
+	}
+	return tags
+}
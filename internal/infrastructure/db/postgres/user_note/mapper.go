@@ -0,0 +1,26 @@
+package user_note
+
+import (
+	domain "user-manager-api/internal/domain/user_note"
+)
+
+func fromDBModel(model *Note) *domain.Note {
+	var n = &domain.Note{
+		UUID:       model.UUID,
+		AuthorUUID: model.AuthorUUID,
+		Body:       model.Body,
+
+		CreatedAt: model.CreatedAt,
+	}
+
+	return n
+}
+
+func fromDBModels(models *Notes) domain.Notes {
+	ns := make(domain.Notes, len(*models))
+	for idx, n := range *models {
+		ns[idx] = fromDBModel(n)
+	}
+
+	return ns
+}
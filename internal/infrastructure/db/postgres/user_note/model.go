@@ -0,0 +1,22 @@
+package user_note
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	userDB "user-manager-api/internal/infrastructure/db/postgres/user"
+)
+
+type (
+	Note struct {
+		ID         uint64
+		UUID       uuid.UUID
+		UserID     userDB.ID
+		AuthorUUID uuid.UUID
+		Body       string
+
+		CreatedAt time.Time
+	}
+	Notes []*Note
+)
@@ -0,0 +1,17 @@
+package user_note
+
+const (
+	SelectNotes = `
+		SELECT id, uuid, user_id, author_uuid, body, created_at
+		FROM user_notes
+		WHERE user_id = $1`
+	InsertNote = `
+		INSERT INTO user_notes (user_id, author_uuid, body)
+		VALUES ($1, $2, $3)
+		RETURNING id, uuid, user_id, author_uuid, body, created_at
+	`
+	DeleteNote = `
+		DELETE FROM user_notes
+		WHERE user_id = $1 AND uuid = $2
+	`
+)
@@ -0,0 +1,77 @@
+package user_note
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"user-manager-api/internal/domain/pagination"
+	"user-manager-api/internal/domain/user"
+	"user-manager-api/internal/domain/user_note"
+	"user-manager-api/internal/infrastructure/db/postgres"
+)
+
+type Repository struct {
+	db       postgres.DB
+	pageSize int
+}
+
+func NewRepository(db postgres.DB, pageSize int) user_note.Repository {
+	return &Repository{db: db, pageSize: pageSize}
+}
+
+func (r *Repository) FetchNotes(ctx context.Context, userID user.ID, p pagination.Params) (user_note.Notes, error) {
+	clause, args := postgres.PageClause(p, nil, r.pageSize, 2)
+	rows, err := r.db.Query(ctx, SelectNotes+clause, append([]any{userID}, args...)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ns Notes
+	for rows.Next() {
+		n := new(Note)
+		if err = rows.Scan(
+			&n.ID,
+			&n.UUID,
+			&n.UserID,
+			&n.AuthorUUID,
+			&n.Body,
+			&n.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		ns = append(ns, n)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return fromDBModels(&ns), nil
+}
+
+func (r *Repository) CreateNote(ctx context.Context, userID user.ID, req user_note.Note) (*user_note.Note, error) {
+	n := new(Note)
+	err := r.db.QueryRow(ctx, InsertNote, userID, req.AuthorUUID, req.Body).Scan(
+		&n.ID,
+		&n.UUID,
+		&n.UserID,
+		&n.AuthorUUID,
+		&n.Body,
+		&n.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return fromDBModel(n), nil
+}
+
+func (r *Repository) DeleteNote(ctx context.Context, userID user.ID, noteUUID uuid.UUID) (bool, error) {
+	tag, err := r.db.Exec(ctx, DeleteNote, userID, noteUUID)
+	if err != nil {
+		return false, err
+	}
+
+	return tag.RowsAffected() > 0, nil
+}
@@ -0,0 +1,66 @@
+package email
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+
+	"user-manager-api/config"
+	"user-manager-api/internal/domain/notification"
+	"user-manager-api/pkg/retry"
+)
+
+type (
+	Sender interface {
+		Send(ctx context.Context, to, subject, html string) error
+	}
+	// retrySender retries the sends failed with transient errors(relay down,
+	// greylisting) with full jitter(pkg/retry), rejected recipients are not retried.
+	retrySender struct {
+		sender Sender
+		policy retry.Policy
+	}
+)
+
+// New - the sender of EMAIL_PROVIDER with the retries of EMAIL_MAX_RETRIES
+func New(logger *zap.Logger, cfg config.Email, mCounter *prometheus.CounterVec) Sender {
+	var s Sender
+	switch cfg.Provider {
+	case "smtp":
+		s = NewSMTPSender(cfg)
+	case "ses":
+		s = NewSESSender(logger, cfg)
+	default:
+		s = NewLogSender(logger)
+	}
+
+	return WithRetry(s, logger, cfg, mCounter)
+}
+
+// WithRetry - EMAIL_MAX_RETRIES=0 returns s as is
+func WithRetry(s Sender, logger *zap.Logger, cfg config.Email, mCounter *prometheus.CounterVec) Sender {
+	if cfg.MaxRetries <= 0 {
+		return s
+	}
+	return &retrySender{
+		sender: s,
+		policy: retry.Policy{
+			MaxRetries: cfg.MaxRetries,
+			BaseDelay:  cfg.RetryBaseDelay,
+			Retryable:  func(err error) bool { return !errors.Is(err, notification.ErrRecipientRejected) },
+			OnRetry: func(attempt int, _ time.Duration, err error) {
+				logger.Warn("email send failed", zap.Int("attempt", attempt), zap.Error(err))
+				mCounter.WithLabelValues("email_retries_total").Inc()
+			},
+		},
+	}
+}
+
+func (r *retrySender) Send(ctx context.Context, to, subject, html string) error {
+	return r.policy.Do(ctx, func(ctx context.Context) error {
+		return r.sender.Send(ctx, to, subject, html)
+	})
+}
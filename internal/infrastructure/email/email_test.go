@@ -0,0 +1,85 @@
+package email
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"user-manager-api/config"
+	"user-manager-api/internal/domain/notification"
+)
+
+// flakySender fails with errs in turn, then succeeds
+type flakySender struct {
+	errs  []error
+	calls int
+}
+
+func (f *flakySender) Send(context.Context, string, string, string) error {
+	f.calls++
+	if f.calls <= len(f.errs) {
+		return f.errs[f.calls-1]
+	}
+	return nil
+}
+
+func TestWithRetry(t *testing.T) {
+	errRelay := errors.New("connection refused")
+	rejected := fmt.Errorf("smtp rcpt to: %w", notification.ErrRecipientRejected)
+
+	type tc struct {
+		name      string
+		errs      []error
+		wantErr   error
+		wantCalls int
+	}
+
+	cases := []tc{
+		{"ok", nil, nil, 1},
+		{"transient then ok", []error{errRelay, errRelay}, nil, 3},
+		{"transient exhausted", []error{errRelay, errRelay, errRelay}, errRelay, 3},
+		{"rejected is not retried", []error{rejected}, notification.ErrRecipientRejected, 1},
+	}
+
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			f := &flakySender{errs: tt.errs}
+			mCounter := prometheus.NewCounterVec(prometheus.CounterOpts{Name: "test"}, []string{"result"})
+			s := WithRetry(f, zap.NewNop(), config.Email{MaxRetries: 2, RetryBaseDelay: time.Millisecond}, mCounter)
+
+			err := s.Send(context.Background(), "jane@example.com", "subject", "<p>hi</p>")
+			if tt.wantErr == nil {
+				require.NoError(t, err)
+			} else {
+				require.ErrorIs(t, err, tt.wantErr)
+			}
+			assert.Equal(t, tt.wantCalls, f.calls)
+		})
+	}
+}
+
+func TestWithRetry_Disabled(t *testing.T) {
+	f := &flakySender{}
+	s := WithRetry(f, zap.NewNop(), config.Email{}, nil)
+	assert.Same(t, f, s)
+}
+
+func TestWithRetry_CanceledContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	f := &flakySender{errs: []error{errors.New("connection refused")}}
+	mCounter := prometheus.NewCounterVec(prometheus.CounterOpts{Name: "test"}, []string{"result"})
+	s := WithRetry(f, zap.NewNop(), config.Email{MaxRetries: 3, RetryBaseDelay: time.Hour}, mCounter)
+
+	require.ErrorIs(t, s.Send(ctx, "jane@example.com", "subject", ""), context.Canceled)
+	assert.Equal(t, 1, f.calls)
+}
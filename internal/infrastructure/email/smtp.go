@@ -0,0 +1,104 @@
+package email
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"net/textproto"
+	"time"
+
+	"user-manager-api/config"
+	"user-manager-api/internal/domain/notification"
+)
+
+// SMTPSender sends via a relay(STARTTLS when offered), a connection per email:
+// the volume is low and a relay may drop idle connections.
+type SMTPSender struct {
+	cfg  config.Email
+	addr string
+}
+
+func NewSMTPSender(cfg config.Email) *SMTPSender {
+	return &SMTPSender{cfg: cfg, addr: net.JoinHostPort(cfg.SMTPHost, cfg.SMTPPort)}
+}
+
+func (s *SMTPSender) Send(ctx context.Context, to, subject, html string) error {
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", s.addr)
+	if err != nil {
+		return fmt.Errorf("smtp dial: %w", err)
+	}
+	// net/smtp knows no context, the deadline bounds the whole dialog
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	} else {
+		_ = conn.SetDeadline(time.Now().Add(time.Minute))
+	}
+
+	c, err := smtp.NewClient(conn, s.cfg.SMTPHost)
+	if err != nil {
+		_ = conn.Close()
+		return fmt.Errorf("smtp hello: %w", err)
+	}
+	defer c.Close()
+
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		if err = c.StartTLS(&tls.Config{ServerName: s.cfg.SMTPHost}); err != nil {
+			return fmt.Errorf("smtp starttls: %w", err)
+		}
+	}
+	if s.cfg.SMTPUser != "" {
+		if err = c.Auth(smtp.PlainAuth("", s.cfg.SMTPUser, s.cfg.SMTPPassword, s.cfg.SMTPHost)); err != nil {
+			return fmt.Errorf("smtp auth: %w", err)
+		}
+	}
+	if err = c.Mail(s.cfg.From); err != nil {
+		return fmt.Errorf("smtp mail from: %w", err)
+	}
+	if err = c.Rcpt(to); err != nil {
+		return rcptError(err)
+	}
+	w, err := c.Data()
+	if err != nil {
+		return fmt.Errorf("smtp data: %w", err)
+	}
+	if _, err = w.Write(message(s.cfg.From, to, subject, html, time.Now())); err != nil {
+		return fmt.Errorf("smtp write: %w", err)
+	}
+	if err = w.Close(); err != nil {
+		return fmt.Errorf("smtp data: %w", err)
+	}
+
+	return c.Quit()
+}
+
+// rcptError - 5xx replies to RCPT TO are permanent(unknown mailbox, relaying
+// denied), 4xx are worth a retry(greylisting, a full mailbox)
+func rcptError(err error) error {
+	var perr *textproto.Error
+	if errors.As(err, &perr) && perr.Code >= 500 {
+		return fmt.Errorf("smtp rcpt to: %w: %s", notification.ErrRecipientRejected, perr.Msg)
+	}
+	return fmt.Errorf("smtp rcpt to: %w", err)
+}
+
+// message - an RFC 5322 single part HTML message
+func message(from, to, subject, html string, now time.Time) []byte {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "From: %s\r\n", from)
+	fmt.Fprintf(&b, "To: %s\r\n", to)
+	fmt.Fprintf(&b, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject))
+	fmt.Fprintf(&b, "Date: %s\r\n", now.Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/html; charset=UTF-8\r\n")
+	b.WriteString("Content-Transfer-Encoding: 8bit\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(html)
+
+	return b.Bytes()
+}
@@ -0,0 +1,49 @@
+package email
+
+import (
+	"errors"
+	"net/textproto"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"user-manager-api/internal/domain/notification"
+)
+
+func TestRcptError(t *testing.T) {
+	type tc struct {
+		name         string
+		err          error
+		wantRejected bool
+	}
+
+	cases := []tc{
+		{"unknown mailbox", &textproto.Error{Code: 550, Msg: "no such user"}, true},
+		{"relaying denied", &textproto.Error{Code: 554, Msg: "relay access denied"}, true},
+		{"greylisted", &textproto.Error{Code: 451, Msg: "try again later"}, false},
+		{"connection lost", errors.New("EOF"), false},
+	}
+
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.wantRejected, errors.Is(rcptError(tt.err), notification.ErrRecipientRejected))
+		})
+	}
+}
+
+func TestMessage(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	msg := string(message("no-reply@usermanager.local", "jane@example.com", "Bienvenue à bord", "<p>hi</p>", now))
+
+	head, body, ok := strings.Cut(msg, "\r\n\r\n")
+	assert.True(t, ok)
+	assert.Equal(t, "<p>hi</p>", body)
+	assert.Contains(t, head, "From: no-reply@usermanager.local\r\n")
+	assert.Contains(t, head, "To: jane@example.com\r\n")
+	assert.Contains(t, head, "Subject: =?utf-8?q?Bienvenue_=C3=A0_bord?=\r\n")
+	assert.Contains(t, head, "Date: Fri, 16 Oct 2026 12:00:00 +0000\r\n")
+	assert.Contains(t, head, "Content-Type: text/html; charset=UTF-8")
+}
@@ -0,0 +1,120 @@
+package fieldcrypt
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"strings"
+)
+
+const (
+	prefix = "enc:v1:"
+
+	dekLen = 32
+)
+
+var ErrMalformed = errors.New("malformed encrypted value")
+
+// Cipher - envelope encryption of single column values: every value gets its own
+// data key(DEK, AES-256-GCM), the DEK is stored wrapped by the keyring next to it:
+//
+//	enc:v1:<key id>:<base64 wrapped DEK>:<base64 nonce|ciphertext>
+//
+// Values without the prefix were written before the encryption and are returned
+// as is until re-encrypted. Empty values(blanked by the retention) stay empty.
+type Cipher struct {
+	keyring  Keyring
+	indexKey []byte
+}
+
+// New - indexKey is the HMAC key of BlindIndex, it can not be rotated without
+// recomputing the stored indexes.
+func New(keyring Keyring, indexKey []byte) *Cipher {
+	return &Cipher{keyring: keyring, indexKey: indexKey}
+}
+
+func (c *Cipher) Encrypt(ctx context.Context, plaintext string) (string, error) {
+	if plaintext == "" {
+		return "", nil
+	}
+	dek := make([]byte, dekLen)
+	if _, err := rand.Read(dek); err != nil {
+		return "", err
+	}
+	keyID, wrapped, err := c.keyring.Wrap(ctx, dek)
+	if err != nil {
+		return "", err
+	}
+	aead, err := newAEAD(dek)
+	if err != nil {
+		return "", err
+	}
+	sealed, err := seal(aead, []byte(plaintext))
+	if err != nil {
+		return "", err
+	}
+
+	return prefix + keyID + ":" +
+		base64.RawStdEncoding.EncodeToString(wrapped) + ":" +
+		base64.RawStdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt - the hot path of every user read: the parts are cut without a
+// split, decoded into a single buffer and opened in place.
+func (c *Cipher) Decrypt(ctx context.Context, value string) (string, error) {
+	rest, ok := strings.CutPrefix(value, prefix)
+	if !ok {
+		return value, nil
+	}
+	keyID, rest, ok := strings.Cut(rest, ":")
+	if !ok {
+		return "", ErrMalformed
+	}
+	wrappedB64, sealedB64, ok := strings.Cut(rest, ":")
+	if !ok || strings.Contains(sealedB64, ":") {
+		return "", ErrMalformed
+	}
+
+	enc := base64.RawStdEncoding
+	buf := make([]byte, enc.DecodedLen(len(wrappedB64))+enc.DecodedLen(len(sealedB64)))
+	n, err := enc.Decode(buf, []byte(wrappedB64))
+	if err != nil {
+		return "", ErrMalformed
+	}
+	wrapped := buf[:n:n]
+	m, err := enc.Decode(buf[n:], []byte(sealedB64))
+	if err != nil {
+		return "", ErrMalformed
+	}
+	sealed := buf[n : n+m]
+
+	dek, err := c.keyring.Unwrap(ctx, keyID, wrapped)
+	if err != nil {
+		return "", err
+	}
+	aead, err := newAEAD(dek)
+	if err != nil {
+		return "", err
+	}
+	plaintext, err := open(aead, sealed)
+	if err != nil {
+		return "", err
+	}
+
+	return string(plaintext), nil
+}
+
+// IsCurrent - the value is encrypted with the active key, no re-encryption needed
+func (c *Cipher) IsCurrent(value string) bool {
+	return value == "" || strings.HasPrefix(value, prefix+c.keyring.ActiveKeyID()+":")
+}
+
+// BlindIndex - deterministic HMAC for equality lookups of encrypted values
+func (c *Cipher) BlindIndex(plaintext string) []byte {
+	mac := hmac.New(sha256.New, c.indexKey)
+	mac.Write([]byte(plaintext))
+	return mac.Sum(nil)
+}
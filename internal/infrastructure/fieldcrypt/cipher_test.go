@@ -0,0 +1,159 @@
+package fieldcrypt
+
+import (
+	"context"
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testKey(b byte) string {
+	return base64.StdEncoding.EncodeToString([]byte(strings.Repeat(string(b), 32)))
+}
+
+func newCipher(t *testing.T, active string, keys ...string) *Cipher {
+	t.Helper()
+	kr, err := NewStaticKeyring(keys, active)
+	require.NoError(t, err)
+	return New(kr, []byte("index-key-index-key-index-key-32"))
+}
+
+func TestCipher_EncryptDecrypt(t *testing.T) {
+	ctx := context.Background()
+	c := newCipher(t, "k1", "k1:"+testKey('a'))
+
+	enc, err := c.Encrypt(ctx, "+33788888888")
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(enc, "enc:v1:k1:"))
+	assert.NotContains(t, enc, "+33788888888")
+	assert.True(t, c.IsCurrent(enc))
+
+	again, err := c.Encrypt(ctx, "+33788888888")
+	require.NoError(t, err)
+	assert.NotEqual(t, enc, again, "every value has its own data key and nonce")
+
+	plain, err := c.Decrypt(ctx, enc)
+	require.NoError(t, err)
+	assert.Equal(t, "+33788888888", plain)
+
+	// blanked by the retention
+	blank, err := c.Encrypt(ctx, "")
+	require.NoError(t, err)
+	assert.Empty(t, blank)
+	assert.True(t, c.IsCurrent(blank))
+}
+
+func TestCipher_Decrypt(t *testing.T) {
+	ctx := context.Background()
+	old := newCipher(t, "k1", "k1:"+testKey('a'))
+	encOld, err := old.Encrypt(ctx, "1990-01-01")
+	require.NoError(t, err)
+
+	rotated := newCipher(t, "k2", "k1:"+testKey('a'), "k2:"+testKey('b'))
+	withoutOld := newCipher(t, "k2", "k2:"+testKey('b'))
+
+	type tc struct {
+		name    string
+		cipher  *Cipher
+		value   string
+		want    string
+		wantErr error
+	}
+	cases := []tc{
+		{"retired key still decrypts", rotated, encOld, "1990-01-01", nil},
+		{"plain legacy value", rotated, "1990-01-01", "1990-01-01", nil},
+		{"removed key", withoutOld, encOld, "", ErrUnknownKey},
+		{"malformed", rotated, "enc:v1:k1:abc", "", ErrMalformed},
+		{"bad base64", rotated, "enc:v1:k1:%%:%%", "", ErrMalformed},
+	}
+
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.cipher.Decrypt(ctx, tt.value)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	assert.False(t, rotated.IsCurrent(encOld))
+	assert.False(t, rotated.IsCurrent("1990-01-01"))
+}
+
+func TestCipher_DecryptTampered(t *testing.T) {
+	ctx := context.Background()
+	c := newCipher(t, "k1", "k1:"+testKey('a'))
+	enc, err := c.Encrypt(ctx, "+33788888888")
+	require.NoError(t, err)
+
+	parts := strings.Split(enc, ":")
+	sealed, err := base64.RawStdEncoding.DecodeString(parts[4])
+	require.NoError(t, err)
+	sealed[len(sealed)-1] ^= 1
+	parts[4] = base64.RawStdEncoding.EncodeToString(sealed)
+
+	_, err = c.Decrypt(ctx, strings.Join(parts, ":"))
+	require.Error(t, err)
+}
+
+func TestCipher_BlindIndex(t *testing.T) {
+	c := newCipher(t, "k1", "k1:"+testKey('a'))
+
+	assert.Equal(t, c.BlindIndex("+33788888888"), c.BlindIndex("+33788888888"))
+	assert.NotEqual(t, c.BlindIndex("+33788888888"), c.BlindIndex("+33788888889"))
+	assert.Len(t, c.BlindIndex("+33788888888"), 32)
+}
+
+func TestNewStaticKeyring(t *testing.T) {
+	type tc struct {
+		name    string
+		keys    []string
+		active  string
+		wantErr string
+	}
+	cases := []tc{
+		{"ok", []string{"k1:" + testKey('a'), "k2:" + testKey('b')}, "k2", ""},
+		{"active missing", []string{"k1:" + testKey('a')}, "k2", `active key "k2": unknown encryption key`},
+		{"without id", []string{testKey('a')}, "k1", "invalid key #1: must be <id>:<base64 key>"},
+		{"short key", []string{"k1:c2hvcnQ="}, "k1", `invalid key "k1": must be 32 bytes in base64`},
+	}
+
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewStaticKeyring(tt.keys, tt.active)
+			if tt.wantErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func BenchmarkCipher_Decrypt(b *testing.B) {
+	ctx := context.Background()
+	kr, err := NewStaticKeyring([]string{"k1:" + testKey('a')}, "k1")
+	if err != nil {
+		b.Fatal(err)
+	}
+	c := New(kr, []byte("index-key-index-key-index-key-32"))
+	enc, err := c.Encrypt(ctx, "+33788888888")
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ReportAllocs()
+	for b.Loop() {
+		if _, err = c.Decrypt(ctx, enc); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
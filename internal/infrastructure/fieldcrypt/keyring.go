@@ -0,0 +1,105 @@
+package fieldcrypt
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var ErrUnknownKey = errors.New("unknown encryption key")
+
+// Keyring wraps data keys with key encryption keys(KEK) that never leave it:
+// a KMS client or StaticKeyring. Old keys stay available for Unwrap after rotation.
+type Keyring interface {
+	// Wrap encrypts dek with the active KEK and returns its id
+	Wrap(ctx context.Context, dek []byte) (keyID string, wrapped []byte, err error)
+	// Unwrap may decrypt in place, wrapped must not be used after it
+	Unwrap(ctx context.Context, keyID string, wrapped []byte) ([]byte, error)
+	ActiveKeyID() string
+}
+
+// StaticKeyring - KEKs from config, AES-256-GCM wrapping.
+type StaticKeyring struct {
+	active string
+	keys   map[string]cipher.AEAD
+}
+
+// NewStaticKeyring parses "<id>:<base64 32 bytes key>" items.
+func NewStaticKeyring(items []string, active string) (*StaticKeyring, error) {
+	kr := &StaticKeyring{active: active, keys: make(map[string]cipher.AEAD, len(items))}
+	for i, item := range items {
+		id, encoded, ok := strings.Cut(item, ":")
+		if !ok || id == "" {
+			// the item may be a bare key, never put it into the error
+			return nil, fmt.Errorf("invalid key #%d: must be <id>:<base64 key>", i+1)
+		}
+		key, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil || len(key) != 32 {
+			return nil, fmt.Errorf("invalid key %q: must be 32 bytes in base64", id)
+		}
+		aead, err := newAEAD(key)
+		if err != nil {
+			return nil, err
+		}
+		kr.keys[id] = aead
+	}
+	if _, ok := kr.keys[active]; !ok {
+		return nil, fmt.Errorf("active key %q: %w", active, ErrUnknownKey)
+	}
+
+	return kr, nil
+}
+
+func (kr *StaticKeyring) ActiveKeyID() string { return kr.active }
+
+func (kr *StaticKeyring) Wrap(_ context.Context, dek []byte) (string, []byte, error) {
+	wrapped, err := seal(kr.keys[kr.active], dek)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return kr.active, wrapped, nil
+}
+
+func (kr *StaticKeyring) Unwrap(_ context.Context, keyID string, wrapped []byte) ([]byte, error) {
+	aead, ok := kr.keys[keyID]
+	if !ok {
+		return nil, fmt.Errorf("key %q: %w", keyID, ErrUnknownKey)
+	}
+
+	return open(aead, wrapped)
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
+
+// seal - nonce|ciphertext
+func seal(aead cipher.AEAD, plaintext []byte) ([]byte, error) {
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(plaintext)+aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	return aead.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+// open decrypts in place, sealed is overwritten
+func open(aead cipher.AEAD, sealed []byte) ([]byte, error) {
+	if len(sealed) < aead.NonceSize() {
+		return nil, ErrMalformed
+	}
+	nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+
+	return aead.Open(ciphertext[:0], nonce, ciphertext, nil)
+}
@@ -0,0 +1,64 @@
+package gcs
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"go.uber.org/zap"
+
+	"user-manager-api/config"
+	domain "user-manager-api/internal/domain/user_file"
+)
+
+type Client struct {
+	logger  *zap.Logger
+	project string
+	bucket  string
+}
+
+func New(
+	ctx context.Context,
+	logger *zap.Logger,
+	cfg config.GCS,
+) (*Client, error) {
+	if cfg.Bucket == "" {
+		return nil, fmt.Errorf("invalid GCS config: bucket is required")
+	}
+	// storage.NewClient(ctx, option.WithCredentialsFile(cfg.CredentialsFile))
+
+	return &Client{
+		logger:  logger,
+		project: cfg.ProjectID,
+		bucket:  cfg.Bucket,
+	}, nil
+}
+
+func (c *Client) PutObject(ctx context.Context, key, contentType string, body io.ReadSeeker, size int64) error {
+	// simulation: w := bucket.Object(key).NewWriter(ctx); w.ContentType = contentType; io.Copy(w, body); w.Close()
+	if _, err := io.Copy(io.Discard, body); err != nil {
+		return err
+	}
+
+	return ctx.Err()
+}
+
+func (c *Client) DeleteObjects(ctx context.Context, keys []string) error {
+	// simulation: bucket.Object(key).Delete(ctx) per key, GCS has no multi-delete
+	return ctx.Err()
+}
+
+func (c *Client) ListObjects(ctx context.Context, prefix string) ([]string, error) {
+	// simulation: bucket.Objects(ctx, &storage.Query{Prefix: prefix})
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return nil, domain.ErrListingNotSupported
+}
+
+func (c *Client) GetPublicURL(key string) string {
+	return fmt.Sprintf("https://storage.googleapis.com/%s/%s", c.bucket, key)
+}
+
+func (c *Client) GetBucket() string { return c.bucket }
@@ -1,48 +1,152 @@
 package jwt
 
 import (
+	"context"
 	"errors"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+
+	"user-manager-api/internal/domain/token"
 )
 
 type Service struct {
 	jwtSecret string
+	revoked   token.RevocationCheck
+	role      token.RoleLookup
+	// refreshWindow - see RefreshToken, 0 - off
+	refreshWindow time.Duration
 }
 
 func New(jwtSecret string) *Service { return &Service{jwtSecret: jwtSecret} }
 
+// SetRevocationCheck must be called before serving requests, without it no token is revoked.
+func (s *Service) SetRevocationCheck(check token.RevocationCheck) { s.revoked = check }
+
+// SetRoleLookup must be called before serving requests, without it the role claim is trusted.
+func (s *Service) SetRoleLookup(lookup token.RoleLookup) { s.role = lookup }
+
+// SetRefreshWindow - how long before the expiry the tokens are refreshed, 0 - never
+func (s *Service) SetRefreshWindow(window time.Duration) { s.refreshWindow = window }
+
+// Claims - the JWT payload, mapped to token.Claims for the callers
 type Claims struct {
 	UserID string `json:"user_id"`
 	Role   string `json:"role"`
+	// ActAs - UUID of the admin impersonating UserID, empty for regular tokens
+	ActAs string `json:"act_as,omitempty"`
+	// DeviceID - UUID of the device of the login, empty for unbound tokens
+	DeviceID string `json:"device_id,omitempty"`
 	jwt.RegisteredClaims
 }
 
-func (s *Service) GenerateJWT(userID, role string, expiresIn time.Duration) (string, error) {
-	claims := Claims{
+func (s *Service) GenerateToken(userID, role string, expiresIn time.Duration) (string, error) {
+	return s.sign(Claims{
 		UserID: userID,
 		Role:   role,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(expiresIn)),
 		},
-	}
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	})
+}
 
-	return token.SignedString([]byte(s.jwtSecret))
+// GenerateImpersonationToken - token of userID issued to the actorID admin
+func (s *Service) GenerateImpersonationToken(userID, role, actorID string, expiresIn time.Duration) (string, error) {
+	return s.sign(Claims{
+		UserID: userID,
+		Role:   role,
+		ActAs:  actorID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(expiresIn)),
+		},
+	})
 }
 
-func (s *Service) ValidateToken(tokenStr string) (*Claims, error) {
-	token, err := jwt.ParseWithClaims(tokenStr, &Claims{}, func(token *jwt.Token) (interface{}, error) {
+// GenerateDeviceToken - token of userID bound to the deviceID it logged in from
+func (s *Service) GenerateDeviceToken(userID, role, deviceID string, expiresIn time.Duration) (string, error) {
+	return s.sign(Claims{
+		UserID:   userID,
+		Role:     role,
+		DeviceID: deviceID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(expiresIn)),
+		},
+	})
+}
+
+func (s *Service) sign(claims Claims) (string, error) {
+	claims.IssuedAt = jwt.NewNumericDate(time.Now())
+	t := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+
+	return t.SignedString([]byte(s.jwtSecret))
+}
+
+func (s *Service) ValidateToken(tokenStr string) (*token.Claims, error) {
+	t, err := jwt.ParseWithClaims(tokenStr, &Claims{}, func(t *jwt.Token) (interface{}, error) {
 		return []byte(s.jwtSecret), nil
 	})
-	if err != nil || !token.Valid {
+	if errors.Is(err, jwt.ErrTokenExpired) {
+		return nil, token.ErrExpired
+	}
+	if err != nil || !t.Valid {
 		return nil, errors.New("invalid token")
 	}
 
-	claims, ok := token.Claims.(*Claims)
+	claims, ok := t.Claims.(*Claims)
 	if !ok {
 		return nil, errors.New("invalid claims")
 	}
-	return claims, nil
+
+	return toTokenClaims(claims), nil
+}
+
+func toTokenClaims(c *Claims) *token.Claims {
+	tc := &token.Claims{
+		UserID:   c.UserID,
+		Role:     c.Role,
+		ActAs:    c.ActAs,
+		DeviceID: c.DeviceID,
+	}
+	if c.ExpiresAt != nil {
+		tc.ExpiresAt = c.ExpiresAt.Time
+	}
+	if c.IssuedAt != nil {
+		issuedAt := c.IssuedAt.Time
+		tc.IssuedAt = &issuedAt
+	}
+
+	return tc
+}
+
+func (s *Service) IsRevoked(ctx context.Context, claims *token.Claims) (bool, error) {
+	if s.revoked == nil {
+		return false, nil
+	}
+
+	return s.revoked(ctx, claims)
+}
+
+func (s *Service) CurrentRole(ctx context.Context, claims *token.Claims) (string, error) {
+	if s.role == nil {
+		return claims.Role, nil
+	}
+
+	return s.role(ctx, claims)
+}
+
+// RefreshToken - a token of the same user and device with role for the lifetime
+// of claims, "" unless they expire within the refresh window
+func (s *Service) RefreshToken(claims *token.Claims, role string) (string, error) {
+	if !claims.RefreshDue(s.refreshWindow, time.Now()) {
+		return "", nil
+	}
+
+	return s.sign(Claims{
+		UserID:   claims.UserID,
+		Role:     role,
+		DeviceID: claims.DeviceID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(claims.Lifetime())),
+		},
+	})
 }
@@ -1,11 +1,14 @@
 package jwt
 
 import (
+	"context"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"user-manager-api/internal/domain/token"
 )
 
 func TestGenerateAndValidate_Success(t *testing.T) {
@@ -13,8 +16,8 @@ func TestGenerateAndValidate_Success(t *testing.T) {
 	userID := "u-123"
 	role := "admin"
 
-	tok, err := s.GenerateJWT(userID, role, time.Hour)
-	require.NoError(t, err, "GenerateJWT should not error")
+	tok, err := s.GenerateToken(userID, role, time.Hour)
+	require.NoError(t, err, "GenerateToken should not error")
 	require.NotEmpty(t, tok, "token must not be empty")
 
 	claims, err := s.ValidateToken(tok)
@@ -23,8 +26,7 @@ func TestGenerateAndValidate_Success(t *testing.T) {
 
 	assert.Equal(t, userID, claims.UserID)
 	assert.Equal(t, role, claims.Role)
-	require.NotNil(t, claims.ExpiresAt)
-	assert.True(t, claims.ExpiresAt.Time.After(time.Now().Add(-1*time.Second)))
+	assert.True(t, claims.ExpiresAt.After(time.Now().Add(-1*time.Second)))
 }
 
 func TestValidateToken_Table(t *testing.T) {
@@ -34,12 +36,12 @@ func TestValidateToken_Table(t *testing.T) {
 	type want struct {
 		ok    bool
 		err   string
-		check func(t *testing.T, c *Claims)
+		check func(t *testing.T, c *token.Claims)
 	}
 
 	makeToken := func(secret string, exp time.Duration) string {
 		s := New(secret)
-		tok, err := s.GenerateJWT("user-42", "worker", exp)
+		tok, err := s.GenerateToken("user-42", "worker", exp)
 		require.NoError(t, err)
 		return tok
 	}
@@ -57,11 +59,10 @@ func TestValidateToken_Table(t *testing.T) {
 			want: want{
 				ok:  true,
 				err: "",
-				check: func(t *testing.T, c *Claims) {
+				check: func(t *testing.T, c *token.Claims) {
 					assert.Equal(t, "user-42", c.UserID)
 					assert.Equal(t, "worker", c.Role)
-					require.NotNil(t, c.ExpiresAt)
-					assert.True(t, c.ExpiresAt.Time.After(time.Now().Add(-1*time.Second)))
+					assert.True(t, c.ExpiresAt.After(time.Now().Add(-1*time.Second)))
 				},
 			},
 		},
@@ -80,7 +81,7 @@ func TestValidateToken_Table(t *testing.T) {
 			token:  makeToken("k1", -1*time.Minute),
 			want: want{
 				ok:  false,
-				err: "invalid token",
+				err: "token expired",
 			},
 		},
 		{
@@ -114,3 +115,101 @@ func TestValidateToken_Table(t *testing.T) {
 		})
 	}
 }
+
+func TestGenerateImpersonationToken(t *testing.T) {
+	s := New("super-secret")
+
+	tok, err := s.GenerateImpersonationToken("u-42", "worker", "admin-1", time.Minute)
+	require.NoError(t, err)
+
+	claims, err := s.ValidateToken(tok)
+	require.NoError(t, err)
+	assert.Equal(t, "u-42", claims.UserID)
+	assert.Equal(t, "worker", claims.Role)
+	assert.Equal(t, "admin-1", claims.ActAs)
+
+	// regular tokens carry no act_as claim
+	tok, err = s.GenerateToken("u-42", "worker", time.Minute)
+	require.NoError(t, err)
+	claims, err = s.ValidateToken(tok)
+	require.NoError(t, err)
+	assert.Empty(t, claims.ActAs)
+	assert.Empty(t, claims.DeviceID)
+
+	tok, err = s.GenerateDeviceToken("u-42", "worker", "device-1", time.Minute)
+	require.NoError(t, err)
+	claims, err = s.ValidateToken(tok)
+	require.NoError(t, err)
+	assert.Equal(t, "device-1", claims.DeviceID)
+	assert.Empty(t, claims.ActAs)
+}
+
+func TestIsRevoked(t *testing.T) {
+	s := New("super-secret")
+
+	tok, err := s.GenerateToken("u-42", "worker", time.Minute)
+	require.NoError(t, err)
+	claims, err := s.ValidateToken(tok)
+	require.NoError(t, err)
+	require.NotNil(t, claims.IssuedAt, "tokens must carry iat")
+
+	// no check configured
+	revoked, err := s.IsRevoked(context.Background(), claims)
+	require.NoError(t, err)
+	assert.False(t, revoked)
+
+	s.SetRevocationCheck(func(_ context.Context, c *token.Claims) (bool, error) {
+		assert.Equal(t, "u-42", c.UserID)
+		require.NotNil(t, c.IssuedAt)
+		return c.IssuedAt.Equal(*claims.IssuedAt), nil
+	})
+	revoked, err = s.IsRevoked(context.Background(), claims)
+	require.NoError(t, err)
+	assert.True(t, revoked)
+}
+
+func TestRefreshToken(t *testing.T) {
+	s := New("super-secret")
+	s.SetRefreshWindow(10 * time.Minute)
+
+	issue := func(tok string, err error) *token.Claims {
+		t.Helper()
+		require.NoError(t, err)
+		claims, err := s.ValidateToken(tok)
+		require.NoError(t, err)
+		return claims
+	}
+
+	tests := []struct {
+		name   string
+		claims *token.Claims
+		want   bool
+	}{
+		{"far from the expiry", issue(s.GenerateToken("u-1", "worker", time.Hour)), false},
+		{"within the window", issue(s.GenerateDeviceToken("u-1", "worker", "d-1", 5*time.Minute)), true},
+		{"impersonation", issue(s.GenerateImpersonationToken("u-1", "worker", "a-1", 5*time.Minute)), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tok, err := s.RefreshToken(tt.claims, "admin")
+			require.NoError(t, err)
+			if !tt.want {
+				assert.Empty(t, tok)
+				return
+			}
+
+			got, err := s.ValidateToken(tok)
+			require.NoError(t, err)
+			assert.Equal(t, tt.claims.UserID, got.UserID)
+			assert.Equal(t, tt.claims.DeviceID, got.DeviceID)
+			assert.Equal(t, "admin", got.Role)
+			assert.Equal(t, tt.claims.Lifetime(), got.Lifetime(), "the lifetime of the login")
+		})
+	}
+
+	s.SetRefreshWindow(0)
+	tok, err := s.RefreshToken(tests[1].claims, "worker")
+	require.NoError(t, err)
+	assert.Empty(t, tok, "off")
+}
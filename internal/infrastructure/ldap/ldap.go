@@ -0,0 +1,204 @@
+package ldap
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	goldap "github.com/go-ldap/ldap/v3"
+	"go.uber.org/zap"
+
+	"user-manager-api/config"
+	"user-manager-api/internal/domain/directory"
+	"user-manager-api/internal/domain/user"
+)
+
+// uacAccountDisable - the ACCOUNTDISABLE flag of the AD userAccountControl
+const uacAccountDisable = 0x2
+
+// generalizedTime - the LDAP date syntax(e.g. "19900102000000Z")
+const generalizedTime = "20060102150405Z0700"
+
+// pageSize - entries per page of the search, below the AD MaxPageSize(1000)
+const pageSize = 500
+
+var ErrNotConfigured = errors.New("LDAP_URL is not set")
+
+type (
+	// conn - the calls of *goldap.Conn the sync makes
+	conn interface {
+		Bind(username, password string) error
+		SearchWithPaging(req *goldap.SearchRequest, pagingSize uint32) (*goldap.SearchResult, error)
+		Close() error
+	}
+	Client struct {
+		logger *zap.Logger
+		cfg    config.LDAP
+		dial   func(url string) (conn, error)
+	}
+)
+
+func New(logger *zap.Logger, cfg config.LDAP) *Client {
+	return &Client{
+		logger: logger,
+		cfg:    cfg,
+		dial: func(url string) (conn, error) {
+			c, err := goldap.DialURL(url, goldap.DialWithDialer(&net.Dialer{Timeout: cfg.Timeout}))
+			if err != nil {
+				return nil, err
+			}
+			c.SetTimeout(cfg.Timeout)
+			return c, nil
+		},
+	}
+}
+
+// FetchEntries - every person under the base DN matching the filter, a new
+// connection per call: the sync runs rarely.
+func (c *Client) FetchEntries(ctx context.Context) ([]directory.Entry, error) {
+	if c.cfg.URL == "" {
+		return nil, ErrNotConfigured
+	}
+	c.logger.Debug(
+		"LDAP search",
+		zap.String("base_dn", c.cfg.BaseDN),
+		zap.String("filter", c.cfg.Filter),
+		zap.Strings("attributes", c.attributes()),
+	)
+	results, err := c.search(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]directory.Entry, 0, len(results))
+	for _, r := range results {
+		e := c.toEntry(attributesOf(r))
+		if e.Email == "" {
+			c.logger.Warn("LDAP entry without email skipped", zap.String("attribute", c.cfg.AttrEmail))
+			continue
+		}
+		entries = append(entries, e)
+	}
+
+	return entries, nil
+}
+
+// search - the paged search of the whole subtree, an anonymous one without
+// LDAP_BIND_DN. The connection is closed when ctx is done, which ends the search.
+func (c *Client) search(ctx context.Context) ([]*goldap.Entry, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	lc, err := c.dial(c.cfg.URL)
+	if err != nil {
+		return nil, fmt.Errorf("LDAP dial: %w", err)
+	}
+	defer lc.Close()
+	stop := context.AfterFunc(ctx, func() { _ = lc.Close() })
+	defer stop()
+
+	if c.cfg.BindDN != "" {
+		if err = lc.Bind(c.cfg.BindDN, c.cfg.BindPassword); err != nil {
+			return nil, errors.Join(fmt.Errorf("LDAP bind: %w", err), ctx.Err())
+		}
+	}
+	res, err := lc.SearchWithPaging(goldap.NewSearchRequest(
+		c.cfg.BaseDN,
+		goldap.ScopeWholeSubtree, goldap.NeverDerefAliases, 0, 0, false,
+		c.cfg.Filter,
+		c.attributes(),
+		nil,
+	), pageSize)
+	if err != nil {
+		return nil, errors.Join(fmt.Errorf("LDAP search: %w", err), ctx.Err())
+	}
+
+	return res.Entries, nil
+}
+
+// attributesOf - the values by the attribute names the server returned
+func attributesOf(e *goldap.Entry) map[string][]string {
+	attrs := make(map[string][]string, len(e.Attributes))
+	for _, a := range e.Attributes {
+		attrs[a.Name] = a.Values
+	}
+
+	return attrs
+}
+
+// attributes - requested from the server, unset mappings are not
+func (c *Client) attributes() []string {
+	var attrs []string
+	for _, a := range []string{
+		c.cfg.AttrEmail,
+		c.cfg.AttrName,
+		c.cfg.AttrLastname,
+		c.cfg.AttrBirthDate,
+		c.cfg.AttrPhone,
+		c.cfg.AttrDisabled,
+	} {
+		if a != "" {
+			attrs = append(attrs, a)
+		}
+	}
+
+	return attrs
+}
+
+// toEntry maps the attributes by the configured names(case-insensitive, like
+// LDAP), values not parsed are left zero.
+func (c *Client) toEntry(attrs map[string][]string) directory.Entry {
+	get := func(name string) string {
+		if name == "" {
+			return ""
+		}
+		for k, v := range attrs {
+			if strings.EqualFold(k, name) && len(v) > 0 {
+				return strings.TrimSpace(v[0])
+			}
+		}
+		return ""
+	}
+
+	e := directory.Entry{
+		Email: strings.ToLower(get(c.cfg.AttrEmail)),
+		// normalized as the API stores them, or the entry never matches the user
+		Name:     user.NormalizeName(get(c.cfg.AttrName)),
+		Lastname: user.NormalizeName(get(c.cfg.AttrLastname)),
+		Phone:    normalizePhone(get(c.cfg.AttrPhone)),
+		Disabled: isDisabled(get(c.cfg.AttrDisabled)),
+	}
+	if v := get(c.cfg.AttrBirthDate); v != "" {
+		if d, err := time.Parse(time.DateOnly, v); err == nil {
+			e.BirthDate = d
+		} else if d, err = time.Parse(generalizedTime, v); err == nil {
+			e.BirthDate = d.UTC().Truncate(24 * time.Hour)
+		}
+	}
+
+	return e
+}
+
+// isDisabled - userAccountControl flags or a boolean attribute(e.g. nsAccountLock)
+func isDisabled(v string) bool {
+	if uac, err := strconv.ParseUint(v, 10, 32); err == nil {
+		return uac&uacAccountDisable != 0
+	}
+	b, _ := strconv.ParseBool(v)
+	return b
+}
+
+// normalizePhone drops the separators directories keep, "+33 7 88-88-88-88" - "+33788888888"
+func normalizePhone(v string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case ' ', '-', '.', '(', ')':
+			return -1
+		}
+		return r
+	}, v)
+}
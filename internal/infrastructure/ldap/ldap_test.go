@@ -0,0 +1,196 @@
+package ldap
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	goldap "github.com/go-ldap/ldap/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"user-manager-api/config"
+	"user-manager-api/internal/domain/directory"
+)
+
+func testConfig() config.LDAP {
+	return config.LDAP{
+		URL:           "ldap://dc.example.com",
+		BaseDN:        "dc=example,dc=com",
+		AttrEmail:     "mail",
+		AttrName:      "givenName",
+		AttrLastname:  "sn",
+		AttrBirthDate: "birthDate",
+		AttrPhone:     "mobile",
+		AttrDisabled:  "userAccountControl",
+		Timeout:       time.Second,
+	}
+}
+
+// fakeConn - a directory answering the search with entries
+type fakeConn struct {
+	entries []*goldap.Entry
+	bindErr error
+	bound   string
+	req     *goldap.SearchRequest
+	closed  bool
+}
+
+func (f *fakeConn) Bind(username, _ string) error {
+	f.bound = username
+	return f.bindErr
+}
+
+func (f *fakeConn) SearchWithPaging(req *goldap.SearchRequest, _ uint32) (*goldap.SearchResult, error) {
+	f.req = req
+	return &goldap.SearchResult{Entries: f.entries}, nil
+}
+
+func (f *fakeConn) Close() error {
+	f.closed = true
+	return nil
+}
+
+func newTestClient(cfg config.LDAP, fc *fakeConn) *Client {
+	c := New(zap.NewNop(), cfg)
+	c.dial = func(url string) (conn, error) {
+		if url != cfg.URL {
+			return nil, errors.New("unexpected URL " + url)
+		}
+		return fc, nil
+	}
+	return c
+}
+
+func TestClient_toEntry(t *testing.T) {
+	cases := []struct {
+		name  string
+		cfg   func(c *config.LDAP)
+		attrs map[string][]string
+		want  directory.Entry
+	}{
+		{
+			name: "active AD account",
+			attrs: map[string][]string{
+				"mail":               {" Jane.Doe@Example.com "},
+				"givenname":          {"Jane"},
+				"sn":                 {"Doe"},
+				"birthDate":          {"1990-01-02"},
+				"mobile":             {"+33 7 88-88-88-88"},
+				"userAccountControl": {"512"},
+			},
+			want: directory.Entry{
+				Email:     "jane.doe@example.com",
+				Name:      "Jane",
+				Lastname:  "Doe",
+				BirthDate: time.Date(1990, 1, 2, 0, 0, 0, 0, time.UTC),
+				Phone:     "+33788888888",
+			},
+		},
+		{
+			name: "disabled AD account, generalized time",
+			attrs: map[string][]string{
+				"mail":               {"john@example.com"},
+				"birthDate":          {"19851231000000Z"},
+				"userAccountControl": {"514"},
+			},
+			want: directory.Entry{
+				Email:     "john@example.com",
+				BirthDate: time.Date(1985, 12, 31, 0, 0, 0, 0, time.UTC),
+				Disabled:  true,
+			},
+		},
+		{
+			name: "boolean lock attribute",
+			cfg:  func(c *config.LDAP) { c.AttrDisabled = "nsAccountLock" },
+			attrs: map[string][]string{
+				"mail":          {"john@example.com"},
+				"nsAccountLock": {"TRUE"},
+			},
+			want: directory.Entry{Email: "john@example.com", Disabled: true},
+		},
+		{
+			name: "unmapped and unparsable",
+			cfg:  func(c *config.LDAP) { c.AttrPhone = "" },
+			attrs: map[string][]string{
+				"mail":      {"john@example.com"},
+				"mobile":    {"+33788888888"},
+				"birthDate": {"02/01/1990"},
+			},
+			want: directory.Entry{Email: "john@example.com"},
+		},
+	}
+
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := testConfig()
+			if tt.cfg != nil {
+				tt.cfg(&cfg)
+			}
+			c := New(zap.NewNop(), cfg)
+			assert.Equal(t, tt.want, c.toEntry(tt.attrs))
+		})
+	}
+}
+
+func TestClient_FetchEntries_NotConfigured(t *testing.T) {
+	c := New(zap.NewNop(), config.LDAP{})
+	_, err := c.FetchEntries(context.Background())
+	require.ErrorIs(t, err, ErrNotConfigured)
+}
+
+func TestClient_FetchEntries(t *testing.T) {
+	cfg := testConfig()
+	cfg.BindDN, cfg.BindPassword = "cn=sync,dc=example,dc=com", "secret"
+	cfg.Filter = "(objectClass=person)"
+	fc := &fakeConn{entries: []*goldap.Entry{
+		goldap.NewEntry("cn=jane,dc=example,dc=com", map[string][]string{
+			"mail":      {"Jane@Example.com"},
+			"givenName": {"Jane"},
+		}),
+		goldap.NewEntry("cn=printer,dc=example,dc=com", map[string][]string{"givenName": {"Printer"}}),
+	}}
+
+	entries, err := newTestClient(cfg, fc).FetchEntries(context.Background())
+	require.NoError(t, err)
+	// the entry without email is skipped
+	assert.Equal(t, []directory.Entry{{Email: "jane@example.com", Name: "Jane"}}, entries)
+	assert.Equal(t, cfg.BindDN, fc.bound)
+	assert.Equal(t, "dc=example,dc=com", fc.req.BaseDN)
+	assert.Equal(t, goldap.ScopeWholeSubtree, fc.req.Scope)
+	assert.Equal(t, "(objectClass=person)", fc.req.Filter)
+	assert.Equal(t, []string{"mail", "givenName", "sn", "birthDate", "mobile", "userAccountControl"}, fc.req.Attributes)
+	assert.True(t, fc.closed)
+}
+
+func TestClient_FetchEntries_Failed(t *testing.T) {
+	t.Run("anonymous", func(t *testing.T) {
+		fc := &fakeConn{}
+		entries, err := newTestClient(testConfig(), fc).FetchEntries(context.Background())
+		require.NoError(t, err)
+		assert.Empty(t, entries)
+		assert.Empty(t, fc.bound)
+	})
+
+	t.Run("bind", func(t *testing.T) {
+		cfg := testConfig()
+		cfg.BindDN = "cn=sync,dc=example,dc=com"
+		fc := &fakeConn{bindErr: goldap.NewError(goldap.LDAPResultInvalidCredentials, errors.New("invalid credentials"))}
+		_, err := newTestClient(cfg, fc).FetchEntries(context.Background())
+		require.ErrorContains(t, err, "LDAP bind")
+		assert.Nil(t, fc.req)
+		assert.True(t, fc.closed)
+	})
+
+	t.Run("canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		fc := &fakeConn{}
+		_, err := newTestClient(testConfig(), fc).FetchEntries(ctx)
+		require.ErrorIs(t, err, context.Canceled)
+		assert.Nil(t, fc.req)
+	})
+}
@@ -0,0 +1,204 @@
+package localfs
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"user-manager-api/config"
+	"user-manager-api/internal/domain/user_file"
+)
+
+const (
+	dirPerm  = 0o750
+	filePerm = 0o640
+	// temp files live next to the target so rename(2) stays atomic on the same FS
+	tmpPrefix = ".tmp-"
+)
+
+// Storage keeps objects on a local or NFS mounted directory for on-prem
+// deployments without S3. All access goes through os.Root, so a key can
+// never escape the configured root.
+type Storage struct {
+	logger    *zap.Logger
+	root      *os.Root
+	rootPath  string
+	publicURL string
+}
+
+func New(logger *zap.Logger, cfg config.Storage) (*Storage, error) {
+	if cfg.FSRoot == "" {
+		return nil, errors.New("fs storage root is required")
+	}
+	if err := os.MkdirAll(cfg.FSRoot, dirPerm); err != nil {
+		return nil, fmt.Errorf("create fs storage root: %w", err)
+	}
+	root, err := os.OpenRoot(cfg.FSRoot)
+	if err != nil {
+		return nil, fmt.Errorf("open fs storage root: %w", err)
+	}
+
+	logger.Info("fs storage initialized", zap.String("root", cfg.FSRoot))
+
+	return &Storage{
+		logger:    logger,
+		root:      root,
+		rootPath:  cfg.FSRoot,
+		publicURL: strings.TrimSuffix(cfg.FSPublicURL, "/"),
+	}, nil
+}
+
+func (s *Storage) PutObject(ctx context.Context, key, contentType string, body io.ReadSeeker, size int64) error {
+	name, err := cleanKey(key)
+	if err != nil {
+		return err
+	}
+	if err = s.root.MkdirAll(path.Dir(name), dirPerm); err != nil {
+		return err
+	}
+
+	tmp := path.Join(path.Dir(name), tmpPrefix+randSuffix())
+	f, err := s.root.OpenFile(tmp, os.O_WRONLY|os.O_CREATE|os.O_EXCL, filePerm)
+	if err != nil {
+		return err
+	}
+	// cleanup on any failure below, no-op after a successful rename
+	defer func() { _ = s.root.Remove(tmp) }()
+
+	if _, err = io.Copy(f, ctxReader{ctx: ctx, r: body}); err != nil {
+		_ = f.Close()
+		return err
+	}
+	if err = f.Sync(); err != nil {
+		_ = f.Close()
+		return err
+	}
+	if err = f.Close(); err != nil {
+		return err
+	}
+
+	return s.root.Rename(tmp, name)
+}
+
+func (s *Storage) DeleteObjects(ctx context.Context, keys []string) error {
+	for _, key := range keys {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		name, err := cleanKey(key)
+		if err != nil {
+			return err
+		}
+		if err = s.root.Remove(name); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *Storage) ListObjects(ctx context.Context, prefix string) ([]string, error) {
+	dir := path.Clean(strings.TrimSuffix(prefix, "/"))
+	if dir == "" || dir == "/" {
+		dir = "."
+	}
+
+	var keys []string
+	err := fs.WalkDir(s.root.FS(), dir, func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return fs.SkipAll
+			}
+			return err
+		}
+		if cerr := ctx.Err(); cerr != nil {
+			return cerr
+		}
+		if d.IsDir() || strings.HasPrefix(d.Name(), tmpPrefix) {
+			return nil
+		}
+		if strings.HasPrefix(name, prefix) {
+			keys = append(keys, name)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return keys, nil
+}
+
+// GetObject opens the object for reading, the caller must close it.
+func (s *Storage) GetObject(_ context.Context, key string) (io.ReadSeekCloser, fs.FileInfo, error) {
+	name, err := cleanKey(key)
+	if err != nil {
+		return nil, nil, err
+	}
+	f, err := s.root.Open(name)
+	if err != nil {
+		return nil, nil, err
+	}
+	fi, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return nil, nil, err
+	}
+	if fi.IsDir() {
+		_ = f.Close()
+		return nil, nil, fs.ErrNotExist
+	}
+
+	return f, fi, nil
+}
+
+// GetPublicURL points to the /files/raw/*key handler
+func (s *Storage) GetPublicURL(key string) string {
+	segs := strings.Split(key, "/")
+	for i, seg := range segs {
+		segs[i] = url.PathEscape(seg)
+	}
+	return s.publicURL + "/api/v1/files/raw/" + strings.Join(segs, "/")
+}
+
+func (s *Storage) GetBucket() string { return s.rootPath }
+
+func (s *Storage) Close() error { return s.root.Close() }
+
+func cleanKey(key string) (string, error) {
+	name := path.Clean(strings.TrimPrefix(key, "/"))
+	if name == "." || name == "" || strings.HasPrefix(name, "..") ||
+		strings.HasPrefix(path.Base(name), tmpPrefix) {
+		return "", user_file.ErrInvalidKey
+	}
+	return name, nil
+}
+
+func randSuffix() string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// ctxReader stops a long copy as soon as the request is cancelled
+type ctxReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (cr ctxReader) Read(p []byte) (int, error) {
+	if err := cr.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return cr.r.Read(p)
+}
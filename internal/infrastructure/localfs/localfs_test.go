@@ -0,0 +1,107 @@
+package localfs
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"user-manager-api/config"
+	"user-manager-api/internal/domain/user_file"
+)
+
+func newStorage(t *testing.T) (*Storage, string) {
+	t.Helper()
+	dir := t.TempDir()
+	s, err := New(zap.NewNop(), config.Storage{FSRoot: dir, FSPublicURL: "http://localhost:8080/"})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = s.Close() })
+	return s, dir
+}
+
+func TestPutGetDelete(t *testing.T) {
+	s, dir := newStorage(t)
+	ctx := context.Background()
+	key := "documents/2025/10/03/ts/abc/report.pdf"
+
+	require.NoError(t, s.PutObject(ctx, key, "application/pdf", strings.NewReader("hello"), 5))
+
+	b, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(key)))
+	require.NoError(t, err)
+	assert.Equal(t, "hello", string(b))
+
+	// no temp leftovers after the atomic rename
+	entries, err := os.ReadDir(filepath.Dir(filepath.Join(dir, filepath.FromSlash(key))))
+	require.NoError(t, err)
+	assert.Equal(t, 1, len(entries))
+
+	obj, fi, err := s.GetObject(ctx, key)
+	require.NoError(t, err)
+	got, err := io.ReadAll(obj)
+	require.NoError(t, err)
+	require.NoError(t, obj.Close())
+	assert.Equal(t, "hello", string(got))
+	assert.Equal(t, int64(5), fi.Size())
+
+	require.NoError(t, s.DeleteObjects(ctx, []string{key, "documents/missing.txt"}))
+	_, _, err = s.GetObject(ctx, key)
+	assert.True(t, errors.Is(err, fs.ErrNotExist))
+}
+
+func TestInvalidKeys_Table(t *testing.T) {
+	s, _ := newStorage(t)
+	ctx := context.Background()
+
+	cases := []struct {
+		name string
+		key  string
+	}{
+		{"empty", ""},
+		{"dot", "."},
+		{"parent traversal", "../etc/passwd"},
+		{"nested traversal", "documents/../../etc/passwd"},
+		{"temp file", "documents/.tmp-123"},
+	}
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			err := s.PutObject(ctx, tt.key, "text/plain", strings.NewReader("x"), 1)
+			assert.Equal(t, user_file.ErrInvalidKey, err)
+			_, _, err = s.GetObject(ctx, tt.key)
+			assert.Equal(t, user_file.ErrInvalidKey, err)
+		})
+	}
+}
+
+func TestGetPublicURL(t *testing.T) {
+	s, _ := newStorage(t)
+	assert.Equal(t,
+		"http://localhost:8080/api/v1/files/raw/documents/a%20b/file.txt",
+		s.GetPublicURL("documents/a b/file.txt"),
+	)
+}
+
+func TestListObjects(t *testing.T) {
+	s, _ := newStorage(t)
+	ctx := context.Background()
+
+	for _, k := range []string{"documents/a/1.txt", "documents/b/2.txt", "thumbnails/a/1.png"} {
+		require.NoError(t, s.PutObject(ctx, k, "text/plain", strings.NewReader("x"), 1))
+	}
+
+	keys, err := s.ListObjects(ctx, "documents/")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"documents/a/1.txt", "documents/b/2.txt"}, keys)
+
+	keys, err = s.ListObjects(ctx, "missing/")
+	require.NoError(t, err)
+	assert.Nil(t, keys)
+}
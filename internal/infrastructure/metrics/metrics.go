@@ -13,3 +13,122 @@ func NewCounter() *prometheus.CounterVec {
 		},
 		[]string{"result"})
 }
+
+// NewBreakerState - 0 closed, 1 half-open, 2 open
+func NewBreakerState() *prometheus.GaugeVec {
+	return promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "usermanager",
+			Name:      "circuit_breaker_state",
+		},
+		[]string{"name"})
+}
+
+// NewBulkheadInFlight - calls in flight per dependency
+func NewBulkheadInFlight() *prometheus.GaugeVec {
+	return promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "usermanager",
+			Name:      "bulkhead_in_flight",
+		},
+		[]string{"name"})
+}
+
+// NewHTTPInFlight - the requests admitted and not answered yet
+func NewHTTPInFlight() prometheus.Gauge {
+	return promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: "usermanager",
+			Name:      "http_requests_in_flight",
+		})
+}
+
+// NewHTTPQueued - the requests waiting for the admission, read by queued
+func NewHTTPQueued(queued func() int) prometheus.GaugeFunc {
+	return promauto.NewGaugeFunc(
+		prometheus.GaugeOpts{
+			Namespace: "usermanager",
+			Name:      "http_requests_queued",
+		},
+		func() float64 { return float64(queued()) })
+}
+
+// NewDBQueryDuration - statement durations per fingerprint(see postgres.QueryTracer)
+func NewDBQueryDuration() *prometheus.HistogramVec {
+	return promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: "usermanager",
+			Name:      "db_query_duration_seconds",
+			Buckets:   prometheus.DefBuckets,
+		},
+		[]string{"query"})
+}
+
+// NewHTTPErrors - the error responses per code, the label values are the
+// codes of the error envelopes(see middleware.ErrorCodes)
+func NewHTTPErrors() *prometheus.CounterVec {
+	return promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "usermanager",
+			Name:      "http_errors_total",
+		},
+		[]string{"code"})
+}
+
+// NewAuditEntries - the recorded audit entries per action, the actions are
+// the audit.Action constants
+func NewAuditEntries() *prometheus.CounterVec {
+	return promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "usermanager",
+			Name:      "audit_entries_total",
+		},
+		[]string{"action"})
+}
+
+// NewUsageRequests - requests per organization and role, the label values
+// are bounded by a LabelBound
+func NewUsageRequests() *prometheus.CounterVec {
+	return promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "usermanager",
+			Name:      "usage_requests_total",
+		},
+		[]string{"org", "role"})
+}
+
+// NewUsageDuration - request durations per organization and role, the label
+// values are bounded by a LabelBound
+func NewUsageDuration() *prometheus.HistogramVec {
+	return promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: "usermanager",
+			Name:      "usage_request_duration_seconds",
+			Buckets:   prometheus.DefBuckets,
+		},
+		[]string{"org", "role"})
+}
+
+// LabelBound keeps the cardinality of a label bounded: a value out of the
+// allowed ones is reported as the fallback
+type LabelBound struct {
+	allowed  map[string]struct{}
+	fallback string
+}
+
+func NewLabelBound(fallback string, allowed ...string) LabelBound {
+	b := LabelBound{allowed: make(map[string]struct{}, len(allowed)), fallback: fallback}
+	for _, v := range allowed {
+		b.allowed[v] = struct{}{}
+	}
+
+	return b
+}
+
+func (b LabelBound) Value(v string) string {
+	if _, ok := b.allowed[v]; ok {
+		return v
+	}
+
+	return b.fallback
+}
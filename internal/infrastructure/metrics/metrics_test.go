@@ -0,0 +1,33 @@
+package metrics
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLabelBound_Value(t *testing.T) {
+	b := NewLabelBound("other", "acme.com", "anonymous")
+
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"allowed", "acme.com", "acme.com"},
+		{"allowed anonymous", "anonymous", "anonymous"},
+		{"not allowed", "example.org", "other"},
+		{"case sensitive", "ACME.com", "other"},
+		{"empty", "", "other"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, b.Value(tt.in))
+		})
+	}
+}
+
+func TestLabelBound_NoneAllowed(t *testing.T) {
+	b := NewLabelBound("other")
+	assert.Equal(t, "other", b.Value("acme.com"))
+}
@@ -0,0 +1,186 @@
+package mq
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/rabbitmq/amqp091-go"
+
+	"user-manager-api/internal/domain/eventbus"
+)
+
+// dead-letter headers, set by the consumer(pkg/rmqconsumer)
+const (
+	headerOriginalRoutingKey = "x-original-routing-key"
+	headerError              = "x-error"
+	headerFailedAt           = "x-failed-at"
+)
+
+// deadLetterScanLimit - the messages a requeue or a discard looks through at most
+const deadLetterScanLimit = 10000
+
+// PeekDeadLetters returns the first limit messages, all of them stay in the queue
+func (r *RabbitMQ) PeekDeadLetters(ctx context.Context, limit int) (*eventbus.DeadLetters, error) {
+	out := &eventbus.DeadLetters{Messages: []eventbus.DeadLetter{}}
+	total, err := r.scanDeadLetters(ctx, limit, func(_ *amqp091.Channel, d amqp091.Delivery) (bool, error) {
+		out.Messages = append(out.Messages, toDeadLetter(d))
+		return false, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	out.Total = total
+
+	return out, nil
+}
+
+// RequeueDeadLetters publishes the messages of ids to the exchange with their
+// original routing keys and removes them from the dead-letter queue. Returns
+// the ids requeued, the ones not found are left out.
+func (r *RabbitMQ) RequeueDeadLetters(ctx context.Context, ids []string) ([]string, error) {
+	var done []string
+	_, err := r.scanDeadLetters(ctx, deadLetterScanLimit, func(ch *amqp091.Channel, d amqp091.Delivery) (bool, error) {
+		if !slices.Contains(ids, d.MessageId) || slices.Contains(done, d.MessageId) {
+			return false, nil
+		}
+		rk, _ := d.Headers[headerOriginalRoutingKey].(string)
+		if rk == "" {
+			return false, fmt.Errorf("message %s: no %s header", d.MessageId, headerOriginalRoutingKey)
+		}
+
+		conf, err := ch.PublishWithDeferredConfirmWithContext(ctx, r.cfg.Exchange, rk, true, false, requeuePublishing(d))
+		if err != nil {
+			return false, err
+		}
+		acked, err := conf.WaitContext(ctx)
+		if err != nil {
+			return false, err
+		}
+		if !acked {
+			return false, fmt.Errorf("message %s: %w", d.MessageId, eventbus.ErrRequeueNotConfirmed)
+		}
+		done = append(done, d.MessageId)
+
+		return true, nil
+	})
+
+	return done, err
+}
+
+// DiscardDeadLetters removes the messages of ids from the dead-letter queue.
+// Returns the ids discarded, the ones not found are left out.
+func (r *RabbitMQ) DiscardDeadLetters(ctx context.Context, ids []string) ([]string, error) {
+	var done []string
+	_, err := r.scanDeadLetters(ctx, deadLetterScanLimit, func(_ *amqp091.Channel, d amqp091.Delivery) (bool, error) {
+		if !slices.Contains(ids, d.MessageId) {
+			return false, nil
+		}
+		if !slices.Contains(done, d.MessageId) {
+			done = append(done, d.MessageId)
+		}
+		return true, nil
+	})
+
+	return done, err
+}
+
+// scanDeadLetters gets at most limit messages of the dead-letter queue over a
+// channel of its own. take decides on each: true - acked(gone from the queue),
+// false - returned to the queue in its order once the scan is over. Returns the
+// messages in the queue before the scan.
+// The scans run one at a time: the messages taken by one are hidden from another.
+func (r *RabbitMQ) scanDeadLetters(
+	ctx context.Context,
+	limit int,
+	take func(ch *amqp091.Channel, d amqp091.Delivery) (bool, error),
+) (int, error) {
+	if r.cfg.DeadLetterQueue == "" {
+		return 0, eventbus.ErrNoDeadLetterQueue
+	}
+	r.dlqMu.Lock()
+	defer r.dlqMu.Unlock()
+
+	ch, err := r.openChannel()
+	if err != nil {
+		return 0, err
+	}
+	// closing it would return the unacked messages as well, but after the ones
+	// acked later: nacked at once they keep their order
+	defer func() { _ = ch.Close() }()
+
+	q, err := ch.QueueDeclarePassive(r.cfg.DeadLetterQueue, true, false, false, false, nil)
+	if err != nil {
+		return 0, fmt.Errorf("dead-letter queue: %w", err)
+	}
+
+	var kept bool
+	defer func() {
+		if kept {
+			_ = ch.Nack(0, true, true)
+		}
+	}()
+	for i := 0; i < min(q.Messages, limit); i++ {
+		if err = ctx.Err(); err != nil {
+			return q.Messages, err
+		}
+		d, ok, err := ch.Get(r.cfg.DeadLetterQueue, false)
+		if err != nil {
+			return q.Messages, err
+		}
+		if !ok {
+			break
+		}
+
+		taken, err := take(ch, d)
+		if err != nil {
+			kept = true
+			return q.Messages, err
+		}
+		if taken {
+			if err = d.Ack(false); err != nil {
+				return q.Messages, err
+			}
+			continue
+		}
+		kept = true
+	}
+
+	return q.Messages, nil
+}
+
+func toDeadLetter(d amqp091.Delivery) eventbus.DeadLetter {
+	dl := eventbus.DeadLetter{
+		MessageID:   d.MessageId,
+		PublishedAt: d.Timestamp,
+		Redelivered: d.Redelivered,
+		ContentType: d.ContentType,
+		Body:        d.Body,
+	}
+	dl.RoutingKey, _ = d.Headers[headerOriginalRoutingKey].(string)
+	dl.Error, _ = d.Headers[headerError].(string)
+	dl.FailedAt, _ = d.Headers[headerFailedAt].(time.Time)
+
+	return dl
+}
+
+// requeuePublishing - d as it was published first, without the dead-letter headers
+func requeuePublishing(d amqp091.Delivery) amqp091.Publishing {
+	headers := amqp091.Table{}
+	for k, v := range d.Headers {
+		if k != headerOriginalRoutingKey && k != headerError && k != headerFailedAt {
+			headers[k] = v
+		}
+	}
+
+	return amqp091.Publishing{
+		Headers:      headers,
+		ContentType:  d.ContentType,
+		DeliveryMode: amqp091.Persistent,
+		MessageId:    d.MessageId,
+		Timestamp:    d.Timestamp,
+		Type:         d.Type,
+		Body:         d.Body,
+	}
+}
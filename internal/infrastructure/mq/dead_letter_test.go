@@ -0,0 +1,75 @@
+package mq
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rabbitmq/amqp091-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"user-manager-api/config"
+	"user-manager-api/internal/domain/eventbus"
+)
+
+func deadLetterDelivery() amqp091.Delivery {
+	return amqp091.Delivery{
+		Headers: amqp091.Table{
+			"x-trace":                "abc",
+			headerOriginalRoutingKey: "DELETE",
+			headerError:              "DELETE handler: db",
+			headerFailedAt:           time.Date(2026, 10, 16, 9, 0, 5, 0, time.UTC),
+		},
+		ContentType: "application/json",
+		MessageId:   "7c1f7a4e-4a35-4c4f-9d8e-2f6a3c2b1d00",
+		Timestamp:   time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC),
+		Type:        "DELETE",
+		Redelivered: true,
+		RoutingKey:  "users.queue.dlq",
+		Body:        []byte(`{"id":3}`),
+	}
+}
+
+func TestToDeadLetter(t *testing.T) {
+	assert.Equal(t, eventbus.DeadLetter{
+		MessageID:   "7c1f7a4e-4a35-4c4f-9d8e-2f6a3c2b1d00",
+		RoutingKey:  "DELETE",
+		Error:       "DELETE handler: db",
+		FailedAt:    time.Date(2026, 10, 16, 9, 0, 5, 0, time.UTC),
+		PublishedAt: time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC),
+		Redelivered: true,
+		ContentType: "application/json",
+		Body:        []byte(`{"id":3}`),
+	}, toDeadLetter(deadLetterDelivery()))
+
+	t.Run("without the headers", func(t *testing.T) {
+		dl := toDeadLetter(amqp091.Delivery{MessageId: "m1", Headers: amqp091.Table{headerFailedAt: "not a time"}})
+		assert.Equal(t, eventbus.DeadLetter{MessageID: "m1"}, dl)
+	})
+}
+
+func TestRequeuePublishing(t *testing.T) {
+	d := deadLetterDelivery()
+	assert.Equal(t, amqp091.Publishing{
+		Headers:      amqp091.Table{"x-trace": "abc"},
+		ContentType:  "application/json",
+		DeliveryMode: amqp091.Persistent,
+		MessageId:    d.MessageId,
+		Timestamp:    d.Timestamp,
+		Type:         "DELETE",
+		Body:         d.Body,
+	}, requeuePublishing(d))
+}
+
+func TestDeadLetters_NotConfigured(t *testing.T) {
+	r, _ := newTestMQ(t, config.MQ{PublishWorkers: 1})
+	ctx := context.Background()
+
+	_, err := r.PeekDeadLetters(ctx, 10)
+	require.ErrorIs(t, err, eventbus.ErrNoDeadLetterQueue)
+	_, err = r.RequeueDeadLetters(ctx, []string{"m1"})
+	require.ErrorIs(t, err, eventbus.ErrNoDeadLetterQueue)
+	_, err = r.DiscardDeadLetters(ctx, []string{"m1"})
+	require.ErrorIs(t, err, eventbus.ErrNoDeadLetterQueue)
+}
@@ -0,0 +1,158 @@
+package mq
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"user-manager-api/internal/interface/api/rest/dto/user"
+	"user-manager-api/schemas/eventpb"
+)
+
+// content types of the event encodings(RABBITMQ_EVENT_ENCODINGS)
+const (
+	ContentTypeJSON     = "application/json"
+	ContentTypeProtobuf = "application/x-protobuf"
+)
+
+// the event encodings of the exchanges
+const (
+	EncodingJSON     = "json"
+	EncodingProtobuf = "protobuf"
+)
+
+var errProtoMalformed = errors.New("malformed protobuf event")
+
+// MarshalProto encodes e as the Event message of schemas/event.proto(the
+// generated eventpb), the zero values are left out as proto3 does.
+func MarshalProto(e Event) ([]byte, error) {
+	pb := &eventpb.Event{
+		TimeStamp:   timestamp(e.TS),
+		EventAction: e.Method,
+		UserId:      e.UserID,
+		UserPayload: toProtoUser(e.Payload),
+		Meta:        e.Meta,
+	}
+	if e.Id != uuid.Nil {
+		pb.EventId = e.Id.String()
+	}
+
+	return proto.Marshal(pb)
+}
+
+// UnmarshalProto decodes the Event message, the unknown fields are skipped.
+// The times are in UTC: the protobuf timestamps carry no zone.
+func UnmarshalProto(b []byte) (Event, error) {
+	var pb eventpb.Event
+	if err := proto.Unmarshal(b, &pb); err != nil {
+		return Event{}, fmt.Errorf("%w: %w", errProtoMalformed, err)
+	}
+
+	e := Event{
+		TS:     fromTimestamp(pb.GetTimeStamp()),
+		Method: pb.GetEventAction(),
+		UserID: pb.GetUserId(),
+		Meta:   pb.GetMeta(),
+	}
+	var err error
+	if id := pb.GetEventId(); id != "" {
+		if e.Id, err = uuid.Parse(id); err != nil {
+			return Event{}, fmt.Errorf("%w: event_id: %w", errProtoMalformed, err)
+		}
+	}
+	if u := pb.GetUserPayload(); u != nil {
+		if e.Payload, err = fromProtoUser(u); err != nil {
+			return Event{}, fmt.Errorf("%w: %w", errProtoMalformed, err)
+		}
+	}
+
+	return e, nil
+}
+
+// ProtoToJSON - the protobuf event as the JSON of the handlers(rmqconsumer decoder)
+func ProtoToJSON(b []byte) ([]byte, error) {
+	e, err := UnmarshalProto(b)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(e)
+}
+
+// toProtoUser - nil for the zero user, the events without one carry no payload
+func toProtoUser(u user.User) *eventpb.User {
+	pb := &eventpb.User{
+		Email:             u.Email,
+		Name:              u.Name,
+		Lastname:          u.Lastname,
+		MiddleName:        u.MiddleName,
+		Suffix:            u.Suffix,
+		BirthDate:         timestamp(u.BirthDate),
+		Phone:             u.Phone,
+		PendingEmail:      u.PendingEmail,
+		FilesCount:        u.FilesCount,
+		TotalStorageBytes: u.TotalStorageBytes,
+		Timezone:          u.Timezone,
+		IsBirthdayToday:   u.IsBirthdayToday,
+	}
+	if u.UUID != uuid.Nil {
+		pb.Uuid = u.UUID.String()
+	}
+	if u.Age != nil {
+		age := uint32(*u.Age)
+		pb.Age = &age
+	}
+	if proto.Size(pb) == 0 {
+		return nil
+	}
+
+	return pb
+}
+
+func fromProtoUser(pb *eventpb.User) (user.User, error) {
+	u := user.User{
+		Email:             pb.GetEmail(),
+		Name:              pb.GetName(),
+		Lastname:          pb.GetLastname(),
+		MiddleName:        pb.GetMiddleName(),
+		Suffix:            pb.GetSuffix(),
+		BirthDate:         fromTimestamp(pb.GetBirthDate()),
+		Phone:             pb.GetPhone(),
+		PendingEmail:      pb.GetPendingEmail(),
+		FilesCount:        pb.FilesCount,
+		TotalStorageBytes: pb.TotalStorageBytes,
+		Timezone:          pb.GetTimezone(),
+		IsBirthdayToday:   pb.IsBirthdayToday,
+	}
+	if pb.Age != nil {
+		age := int(*pb.Age)
+		u.Age = &age
+	}
+	if id := pb.GetUuid(); id != "" {
+		var err error
+		if u.UUID, err = uuid.Parse(id); err != nil {
+			return user.User{}, fmt.Errorf("user_payload.uuid: %w", err)
+		}
+	}
+
+	return u, nil
+}
+
+// timestamp - nil for the zero time, proto3 leaves it out
+func timestamp(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	return timestamppb.New(t)
+}
+
+func fromTimestamp(ts *timestamppb.Timestamp) time.Time {
+	if ts == nil {
+		return time.Time{}
+	}
+	return ts.AsTime()
+}
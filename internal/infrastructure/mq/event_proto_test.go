@@ -0,0 +1,134 @@
+package mq
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"user-manager-api/internal/interface/api/rest/dto/user"
+)
+
+func marshalProto(t *testing.T, e Event) []byte {
+	t.Helper()
+	b, err := MarshalProto(e)
+	require.NoError(t, err)
+	return b
+}
+
+func TestMarshalProto_RoundTrip(t *testing.T) {
+	zero, files := uint64(0), uint64(3)
+	age, today := 36, false
+	type tc struct {
+		name string
+		e    Event
+	}
+	cases := []tc{
+		{
+			name: "full",
+			e: Event{
+				Id:     uuid.New(),
+				TS:     time.Date(2026, 10, 16, 9, 0, 0, 123456789, time.UTC),
+				Method: http.MethodPut,
+				UserID: uuid.NewString(),
+				Payload: user.User{
+					UUID:              uuid.New(),
+					Email:             "jane@example.com",
+					Name:              "Jane",
+					Lastname:          "Doe",
+					MiddleName:        "Ann",
+					Suffix:            "Jr.",
+					BirthDate:         time.Date(1990, 1, 2, 0, 0, 0, 0, time.UTC),
+					Phone:             "+15550100",
+					PendingEmail:      "jane.doe@example.com",
+					FilesCount:        &files,
+					TotalStorageBytes: &zero,
+					Timezone:          "Europe/Paris",
+					Age:               &age,
+					IsBirthdayToday:   &today,
+				},
+				Meta: map[string]string{"new_email": "jane.doe@example.com", "token": ""},
+			},
+		},
+		{
+			name: "no user",
+			e:    Event{Id: uuid.New(), TS: time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC), Method: EventInvitationCreated, UserID: uuid.NewString()},
+		},
+		{
+			name: "born before 1970",
+			e:    Event{Method: http.MethodPost, Payload: user.User{BirthDate: time.Date(1950, 6, 1, 12, 30, 0, 5, time.UTC)}},
+		},
+		{
+			name: "zero",
+			e:    Event{},
+		},
+	}
+
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := UnmarshalProto(marshalProto(t, tt.e))
+			require.NoError(t, err)
+			require.Equal(t, tt.e, got)
+		})
+	}
+}
+
+func TestUnmarshalProto_Zone(t *testing.T) {
+	ts := time.Date(2026, 10, 16, 11, 0, 0, 0, time.FixedZone("CEST", 2*60*60))
+	got, err := UnmarshalProto(marshalProto(t, Event{TS: ts}))
+	require.NoError(t, err)
+	require.Equal(t, ts.UTC(), got.TS)
+}
+
+func TestUnmarshalProto_Malformed(t *testing.T) {
+	type tc struct {
+		name string
+		b    []byte
+	}
+	cases := []tc{
+		{"truncated", marshalProto(t, Event{Method: http.MethodPost, UserID: uuid.NewString()})[:5]},
+		{"bad uuid", []byte{0x0a, 0x02, 'x', 'y'}},
+		{"bad tag", []byte{0x00}},
+	}
+
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := UnmarshalProto(tt.b)
+			require.ErrorIs(t, err, errProtoMalformed)
+		})
+	}
+}
+
+func TestProtoToJSON(t *testing.T) {
+	e := Event{
+		Id:      uuid.New(),
+		TS:      time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC),
+		Method:  EventUserFilesChanged,
+		UserID:  uuid.NewString(),
+		Payload: user.User{UUID: uuid.New(), Email: "jane@example.com", Name: "Jane"},
+	}
+	pb := marshalProto(t, e)
+	want, err := json.Marshal(e)
+	require.NoError(t, err)
+
+	got, err := ProtoToJSON(pb)
+	require.NoError(t, err)
+	require.JSONEq(t, string(want), string(got))
+	require.Less(t, len(pb), len(want)/2, "the protobuf encoding is the compact one")
+
+	s, err := EventSchema()
+	require.NoError(t, err)
+	require.NoError(t, s.Validate(got))
+}
+
+func TestExchangeEncoding(t *testing.T) {
+	items := []string{"usermanager.events=protobuf", "audit.events=json"}
+	require.Equal(t, EncodingProtobuf, exchangeEncoding(items, "usermanager.events"))
+	require.Equal(t, EncodingJSON, exchangeEncoding(items, "audit.events"))
+	require.Equal(t, EncodingJSON, exchangeEncoding(items, "other.events"), "json for the exchanges not listed")
+}
@@ -0,0 +1,111 @@
+package mq
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Management - the RabbitMQ management HTTP API(the plugin): AMQP can declare
+// the topology but can not list it
+type Management struct {
+	baseURL  string
+	user     string
+	password string
+	vhost    string
+	client   *http.Client
+}
+
+type (
+	mgmtExchange struct {
+		Type       string `json:"type"`
+		Durable    bool   `json:"durable"`
+		AutoDelete bool   `json:"auto_delete"`
+	}
+	mgmtQueue struct {
+		Durable    bool `json:"durable"`
+		AutoDelete bool `json:"auto_delete"`
+		Exclusive  bool `json:"exclusive"`
+	}
+	mgmtBinding struct {
+		RoutingKey string `json:"routing_key"`
+	}
+)
+
+func NewManagement(baseURL, user, password, vhost string, timeout time.Duration) *Management {
+	return &Management{
+		baseURL:  baseURL,
+		user:     user,
+		password: password,
+		vhost:    vhost,
+		client:   &http.Client{Timeout: timeout},
+	}
+}
+
+// exchange - nil if not declared
+func (m *Management) exchange(ctx context.Context, name string) (*mgmtExchange, error) {
+	var e mgmtExchange
+	found, err := m.get(ctx, "/api/exchanges/"+url.PathEscape(m.vhost)+"/"+url.PathEscape(name), &e)
+	if err != nil || !found {
+		return nil, err
+	}
+	return &e, nil
+}
+
+// queue - nil if not declared
+func (m *Management) queue(ctx context.Context, name string) (*mgmtQueue, error) {
+	var q mgmtQueue
+	found, err := m.get(ctx, "/api/queues/"+url.PathEscape(m.vhost)+"/"+url.PathEscape(name), &q)
+	if err != nil || !found {
+		return nil, err
+	}
+	return &q, nil
+}
+
+// routingKeys - of the bindings from the exchange to the queue
+func (m *Management) routingKeys(ctx context.Context, exchange, queue string) ([]string, error) {
+	var bindings []mgmtBinding
+	path := "/api/bindings/" + url.PathEscape(m.vhost) + "/e/" + url.PathEscape(exchange) + "/q/" + url.PathEscape(queue)
+	if _, err := m.get(ctx, path, &bindings); err != nil {
+		return nil, err
+	}
+
+	keys := make([]string, len(bindings))
+	for i, b := range bindings {
+		keys[i] = b.RoutingKey
+	}
+	return keys, nil
+}
+
+// get decodes the response into out, false on 404
+func (m *Management) get(ctx context.Context, path string, out any) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, m.baseURL+path, nil)
+	if err != nil {
+		return false, err
+	}
+	req.SetBasicAuth(m.user, m.password)
+
+	resp, err := m.client.Do(req)
+	if err != nil {
+		return false, fmt.Errorf("rabbitmq management: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return false, nil
+	case resp.StatusCode != http.StatusOK:
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return false, fmt.Errorf("rabbitmq management: %s %s", path, resp.Status)
+	}
+
+	if err = json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return false, fmt.Errorf("rabbitmq management: %s: %w", path, err)
+	}
+	return true, nil
+}
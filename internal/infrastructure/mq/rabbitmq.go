@@ -3,29 +3,141 @@ package mq
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
 	"net"
 	"net/http"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/rabbitmq/amqp091-go"
+	"github.com/sony/gobreaker/v2"
 	"go.uber.org/zap"
 
 	"user-manager-api/config"
+	"user-manager-api/internal/infrastructure/resilience"
 	"user-manager-api/internal/interface/api/rest/dto/user"
+	"user-manager-api/pkg/alert"
+	"user-manager-api/pkg/bulkhead"
+	"user-manager-api/pkg/jsonschema"
 )
 
-// "Rely on metrics, not guesses."
-const bufferSize = 128
+// routing keys of domain events, CRUD events are routed by HTTP method
+const (
+	EventEmailChangeRequested = "EmailChangeRequested"
+	EventEmailChangeConfirmed = "EmailChangeConfirmed"
+	// EventUserFilesChanged - files of UserID were uploaded or deleted, Meta
+	// carries the change of their count(MetaFilesDelta)
+	EventUserFilesChanged = "UserFilesChanged"
+	// EventInvitationCreated - the signup link to be delivered to the invitee
+	EventInvitationCreated = "InvitationCreated"
+	// EventPasswordResetForced - an admin revoked the credentials of the user
+	EventPasswordResetForced = "PasswordResetForced"
+	// EventLoginSucceeded, EventLoginFailed - the login attempts, Meta carries the
+	// client(ip, user_agent, country) and the failure reason
+	EventLoginSucceeded = "LoginSucceeded"
+	EventLoginFailed    = "LoginFailed"
+	// EventUserBirthday - it is the birthday of the user today in its timezone,
+	// the payload carries the age
+	EventUserBirthday = "UserBirthday"
+	// EventUsersMerged - a duplicate account was merged into UserID, Meta carries
+	// the merged user and the moved records counts
+	EventUsersMerged = "UsersMerged"
+	// EventUserAccessRevoked - the access of a deleted user was revoked with it:
+	// the issued tokens and the devices(sessions). Meta carries the deletion
+	// reason and what was revoked(MetaRevoked)
+	EventUserAccessRevoked = "UserAccessRevoked"
+	// EventInactivityWarning - the user is about to be suspended for inactivity,
+	// Meta carries the date of the suspension(MetaSuspendAfter)
+	EventInactivityWarning = "InactivityWarning"
+	// EventUserSuspended, EventUserReactivated - the inactive user was suspended,
+	// its tokens revoked; the suspension was lifted by a login or an admin(Meta "via")
+	EventUserSuspended   = "UserSuspended"
+	EventUserReactivated = "UserReactivated"
+)
+
+// MetaRevoked - the comma separated kinds of access revoked by EventUserAccessRevoked
+const MetaRevoked = "revoked"
+
+// MetaSuspendAfter - the date(YYYY-MM-DD) the user of EventInactivityWarning is
+// suspended after unless it logs in
+const MetaSuspendAfter = "suspend_after"
+
+// MetaFilesDelta - the uploaded minus the deleted files of EventUserFilesChanged
+const MetaFilesDelta = "files_delta"
+
+// alertKeyDropped - the events lost for good
+const alertKeyDropped = "mq_event_dropped"
+
+// flushTimeout - publishing of the already queued events on shutdown
+const flushTimeout = 5 * time.Second
+
+var (
+	// ErrBackpressure - the publisher can not keep up(broker outage), the event is rejected
+	ErrBackpressure = errors.New("mq publisher is saturated")
+	// ErrUnroutable - the event type has no routing key, it would be lost by the broker
+	ErrUnroutable = errors.New("mq event type is not routed")
+)
+
+// routes - routing key of every published event type, the queue is bound to all of them
+var routes = map[string]string{
+	http.MethodPost:           http.MethodPost,
+	http.MethodPut:            http.MethodPut,
+	http.MethodDelete:         http.MethodDelete,
+	EventEmailChangeRequested: EventEmailChangeRequested,
+	EventEmailChangeConfirmed: EventEmailChangeConfirmed,
+	EventUserFilesChanged:     EventUserFilesChanged,
+	EventInvitationCreated:    EventInvitationCreated,
+	EventPasswordResetForced:  EventPasswordResetForced,
+	EventLoginSucceeded:       EventLoginSucceeded,
+	EventLoginFailed:          EventLoginFailed,
+	EventUserBirthday:         EventUserBirthday,
+	EventUsersMerged:          EventUsersMerged,
+	EventUserAccessRevoked:    EventUserAccessRevoked,
+	EventInactivityWarning:    EventInactivityWarning,
+	EventUserSuspended:        EventUserSuspended,
+	EventUserReactivated:      EventUserReactivated,
+}
 
 type (
-	InputCh  = chan Event
+	// RabbitMQ - the events publisher: a pool of workers, each one with its own
+	// lane and channel. Events of a user always take the same lane, so they are
+	// published in order, unless one of them had to be retried.
 	RabbitMQ struct {
-		cfg   config.MQ
-		log   *zap.Logger
-		conn  *amqp091.Connection
+		cfg      config.MQ
+		log      *zap.Logger
+		mCounter *prometheus.CounterVec
+		// encoding - of the events of cfg.Exchange(RABBITMQ_EVENT_ENCODINGS)
+		encoding string
+		dsn      string
+		dial     func(network, addr string) (net.Conn, error)
+
+		connMu sync.Mutex
+		conn   *amqp091.Connection
+		// pubCh - the topology declaration only, workers publish via their own channels
 		pubCh *amqp091.Channel
-		in    InputCh
+
+		lanes []chan Event
+		// retry - events failed to publish, re-dispatched every RetryInterval
+		retry chan Event
+		// guard - while the broker is failing, the batches go to retry at once
+		guard *resilience.Guard
+		// mgmt - the topology checks, nil disables them
+		mgmt *Management
+		// dlqMu - one dead-letter queue scan at a time
+		dlqMu sync.Mutex
+		// schema - the events are validated on Publish, nil disables it
+		schema *jsonschema.Schema
+		// onDrop - takes the events which would be dropped(a full retry buffer,
+		// the shutdown), nil - they are dropped
+		onDrop func(e Event)
+		// alerter - the events failed to be published, nil - logged only
+		alerter alert.Notifier
 	}
 	Event struct {
 		Id      uuid.UUID `json:"event_id"`
@@ -33,34 +145,65 @@ type (
 		Method  string    `json:"event_action"`
 		UserID  string    `json:"user_id"`
 		Payload user.User `json:"user_payload"`
+		// Meta - event specific values, e.g. the new email and confirmation token
+		Meta map[string]string `json:"meta,omitempty"`
 	}
 )
 
-func New(cfg config.MQ, logger *zap.Logger) *RabbitMQ {
+func New(cfg config.MQ, logger *zap.Logger, mCounter *prometheus.CounterVec) *RabbitMQ {
+	lanes := make([]chan Event, cfg.PublishWorkers)
+	for i := range lanes {
+		lanes[i] = make(chan Event, cfg.BufferSize)
+	}
+
 	return &RabbitMQ{
-		cfg: cfg,
-		log: logger,
-		in:  make(chan Event, bufferSize),
+		cfg:      cfg,
+		log:      logger,
+		mCounter: mCounter,
+		encoding: exchangeEncoding(cfg.EventEncodings, cfg.Exchange),
+		lanes:    lanes,
+		retry:    make(chan Event, cfg.RetryBufferSize),
+	}
+}
+
+// exchangeEncoding - the one of the "<exchange>=<encoding>" items(validated by
+// cfg.Validate), json for an exchange not listed
+func exchangeEncoding(items []string, exchange string) string {
+	for _, item := range items {
+		if name, encoding, _ := strings.Cut(item, "="); name == exchange {
+			return encoding
+		}
 	}
+
+	return EncodingJSON
+}
+
+// SetGuard puts the publishing behind the circuit breaker and the bulkhead of g
+func (r *RabbitMQ) SetGuard(g *resilience.Guard) {
+	r.guard = g
+}
+
+// SetDropHandler hands the events failed for good to fn instead of dropping
+// them, e.g. to keep them in an outbox. fn is called from the publisher workers
+func (r *RabbitMQ) SetDropHandler(fn func(e Event)) {
+	r.onDrop = fn
+}
+
+// SetAlerter notifies the operators of the events failed to be published,
+// a is expected to deduplicate(see alert.Throttle)
+func (r *RabbitMQ) SetAlerter(a alert.Notifier) {
+	r.alerter = a
 }
 
 func (r *RabbitMQ) Connect(ctx context.Context, dsn string) error {
 	dialer := &net.Dialer{Timeout: 10 * time.Second}
-
-	amqpCfg := amqp091.Config{
-		Heartbeat: 10 * time.Second,
-		Locale:    "en_US",
-		Properties: amqp091.Table{
-			"connection_name": "usermanagerapi",
-		},
-		Dial: func(network, addr string) (net.Conn, error) {
-			return dialer.DialContext(ctx, network, addr)
-		},
-		TLSClientConfig: nil,
+	r.dsn = dsn
+	r.dial = func(network, addr string) (net.Conn, error) {
+		return dialer.DialContext(ctx, network, addr)
 	}
 
 	var err error
-	r.conn, err = amqp091.DialConfig(dsn, amqpCfg)
+	r.conn, err = r.dialConfig()
 	if err != nil {
 		return err
 	}
@@ -75,9 +218,32 @@ func (r *RabbitMQ) Connect(ctx context.Context, dsn string) error {
 	return err
 }
 
+func (r *RabbitMQ) dialConfig() (*amqp091.Connection, error) {
+	return amqp091.DialConfig(r.dsn, amqp091.Config{
+		Heartbeat: 10 * time.Second,
+		Locale:    "en_US",
+		Properties: amqp091.Table{
+			"connection_name": "usermanagerapi",
+		},
+		Dial:            r.dial,
+		TLSClientConfig: nil,
+	})
+}
+
 func (r *RabbitMQ) Init() error {
+	if err := r.declare(r.pubCh); err != nil {
+		_ = r.pubCh.Close()
+		return err
+	}
+
+	return nil
+}
+
+// declare - the exchange, the queue and its bindings and the dead-letter queue,
+// a no-op for the ones declared alike, a broker's one of other properties closes ch
+func (r *RabbitMQ) declare(ch *amqp091.Channel) error {
 	var err error
-	if err = r.pubCh.ExchangeDeclare(
+	if err = ch.ExchangeDeclare(
 		r.cfg.Exchange,
 		r.cfg.ExchangeType,
 		true,
@@ -86,10 +252,9 @@ func (r *RabbitMQ) Init() error {
 		false,
 		nil,
 	); err != nil {
-		_ = r.pubCh.Close()
 		return err
 	}
-	q, err := r.pubCh.QueueDeclare(
+	q, err := ch.QueueDeclare(
 		r.cfg.QueueName,
 		true,
 		false,
@@ -101,12 +266,13 @@ func (r *RabbitMQ) Init() error {
 		return err
 	}
 
-	for _, rk := range []string{
-		http.MethodPost,
-		http.MethodPut,
-		http.MethodDelete,
-	} {
-		if err = r.pubCh.QueueBind(q.Name, rk, r.cfg.Exchange, false, nil); err != nil {
+	for _, rk := range routes {
+		if err = ch.QueueBind(q.Name, rk, r.cfg.Exchange, false, nil); err != nil {
+			return err
+		}
+	}
+	if r.cfg.DeadLetterQueue != "" {
+		if _, err = ch.QueueDeclare(r.cfg.DeadLetterQueue, true, false, false, false, nil); err != nil {
 			return err
 		}
 	}
@@ -114,59 +280,375 @@ func (r *RabbitMQ) Init() error {
 	return nil
 }
 
+// Publish queues the event for publishing. It waits for a room in the lane at
+// most EnqueueTimeout, so callers never hang on a broker outage: ErrBackpressure
+// tells the event was rejected. With a schema set, an event not matching it is
+// rejected with ErrInvalidEvent.
+func (r *RabbitMQ) Publish(ctx context.Context, e Event) error {
+	if _, ok := routes[e.Method]; !ok {
+		r.log.Error("mq event rejected", zap.String("event_action", e.Method), zap.Error(ErrUnroutable))
+		return fmt.Errorf("%s: %w", e.Method, ErrUnroutable)
+	}
+	if r.schema != nil {
+		if err := validateEvent(r.schema, e); err != nil {
+			// the producer drifted from the contract
+			r.mCounter.WithLabelValues("mq_events_invalid_total").Inc()
+			r.log.Error("mq event rejected",
+				zap.String("event_id", e.Id.String()),
+				zap.String("event_action", e.Method),
+				zap.Error(err),
+			)
+			r.alert(alert.Alert{
+				Key:      "mq_event_invalid:" + e.Method,
+				Severity: alert.SeverityError,
+				Summary:  "mq event rejected: it does not match the event schema",
+				Details:  map[string]string{"event_action": e.Method, "error": err.Error()},
+			})
+			return err
+		}
+	}
+
+	lane := r.lanes[r.laneOf(e)]
+	select {
+	case lane <- e:
+		return nil
+	default:
+	}
+
+	t := time.NewTimer(r.cfg.EnqueueTimeout)
+	defer t.Stop()
+	select {
+	case lane <- e:
+		return nil
+	case <-t.C:
+		r.mCounter.WithLabelValues("mq_events_rejected_total").Inc()
+		r.log.Error("mq event rejected",
+			zap.String("event_id", e.Id.String()),
+			zap.String("event_action", e.Method),
+			zap.Error(ErrBackpressure),
+		)
+		r.alert(alert.Alert{
+			Key:      "mq_event_rejected",
+			Severity: alert.SeverityWarning,
+			Summary:  "mq event rejected: the publisher is saturated",
+			Details:  map[string]string{"event_action": e.Method},
+		})
+		return ErrBackpressure
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (r *RabbitMQ) laneOf(e Event) int {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(e.UserID))
+	return int(h.Sum32() % uint32(len(r.lanes)))
+}
+
+// PublisherWorker runs the publishing workers and re-dispatches the failed
+// events until ctx is done, then flushes what is already queued.
 func (r *RabbitMQ) PublisherWorker(ctx context.Context) {
-	r.log.Info("starting publisher worker ")
+	r.log.Info("starting publisher worker ", zap.Int("workers", len(r.lanes)))
 
 	defer func() {
 		r.log.Info("publisher worker gracefully stopped")
 	}()
 
+	var wg sync.WaitGroup
+	for i, lane := range r.lanes {
+		wg.Add(1)
+		go func(id int, lane chan Event) {
+			defer wg.Done()
+			r.worker(ctx, id, lane)
+		}(i, lane)
+	}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		r.retryWorker(ctx)
+	}()
+	wg.Wait()
+	// the flush of the lanes may have failed some more
+	r.dropRetries()
+
+	_ = r.pubCh.Close()
+}
+
+func (r *RabbitMQ) worker(ctx context.Context, id int, lane chan Event) {
+	var ch *amqp091.Channel
+	defer func() {
+		if ch != nil {
+			_ = ch.Close()
+		}
+	}()
+
+	batch := make([]Event, 0, r.cfg.PublishBatchSize)
 	for {
 		select {
-		case e := <-r.in:
-			if err := r.publish(ctx, e); err != nil {
-				// alert
-				r.log.Error("mq publish error", zap.Error(err))
-			}
+		case e := <-lane:
+			// a batch is what has been queued meanwhile: no waiting for it to fill up
+			batch = collect(lane, append(batch, e), r.cfg.PublishBatchSize)
+			ch = r.publishBatch(ctx, ch, batch)
+			batch = batch[:0]
 		case <-ctx.Done():
-			close(r.in)
-			r.pubCh.Close()
+			flushCtx, cancel := context.WithTimeout(context.Background(), flushTimeout)
+			for {
+				batch = collect(lane, batch[:0], r.cfg.PublishBatchSize)
+				if len(batch) == 0 {
+					break
+				}
+				ch = r.publishBatch(flushCtx, ch, batch)
+			}
+			cancel()
+			r.log.Info("publisher lane stopped", zap.Int("lane", id))
 			return
 		}
 	}
 }
 
-func (r *RabbitMQ) publish(ctx context.Context, e Event) error {
-	// for a good boost of performance(x3 minimum) and to avoid reflection under the hood
-	// better to use codegen for marshal/unmarshal for example:
-	// https://github.com/mailru/easyjson
-	b, err := json.Marshal(e)
-	if err != nil {
-		// alert
+// collect appends the queued events without blocking, up to size
+func collect(lane chan Event, batch []Event, size int) []Event {
+	for len(batch) < size {
+		select {
+		case e := <-lane:
+			batch = append(batch, e)
+		default:
+			return batch
+		}
+	}
+	return batch
+}
+
+// publishBatch publishes the batch and waits for the broker confirms of all
+// of it at once. Not confirmed events go to the retry buffer. Returns the
+// channel to be used for the next batch, nil if it has to be reopened.
+func (r *RabbitMQ) publishBatch(ctx context.Context, ch *amqp091.Channel, batch []Event) *amqp091.Channel {
+	err := r.guard.Do(ctx, func(ctx context.Context) error {
+		var err error
+		ch, err = r.sendBatch(ctx, ch, batch)
 		return err
+	})
+	if errors.Is(err, gobreaker.ErrOpenState) || errors.Is(err, bulkhead.ErrFull) {
+		for _, e := range batch {
+			r.retryLater(e)
+		}
+	}
+
+	return ch
+}
+
+// sendBatch - publishBatch without the guard, the error tells the broker failed
+// some of the batch(already put into the retry buffer)
+func (r *RabbitMQ) sendBatch(ctx context.Context, ch *amqp091.Channel, batch []Event) (*amqp091.Channel, error) {
+	if ch == nil || ch.IsClosed() {
+		var err error
+		if ch, err = r.openChannel(); err != nil {
+			r.log.Error("mq channel error", zap.Error(err))
+			for _, e := range batch {
+				r.retryLater(e)
+			}
+			return nil, err
+		}
+	}
+
+	confirms := make([]*amqp091.DeferredConfirmation, len(batch))
+	for i, e := range batch {
+		dc, err := r.publish(ctx, ch, e)
+		if err != nil {
+			r.log.Error("mq publish error", zap.Error(err))
+			r.retryLater(e)
+			continue
+		}
+		confirms[i] = dc
+	}
+
+	published := 0
+	for i, dc := range confirms {
+		if dc == nil {
+			continue
+		}
+		if ok, err := dc.WaitContext(ctx); err != nil || !ok {
+			r.log.Error("mq publish not confirmed", zap.String("event_id", batch[i].Id.String()), zap.Error(err))
+			r.retryLater(batch[i])
+			continue
+		}
+		published++
+	}
+	r.mCounter.WithLabelValues("mq_events_published_total").Add(float64(published))
+
+	var err error
+	if published < len(batch) {
+		err = fmt.Errorf("%d of %d events not published", len(batch)-published, len(batch))
+	}
+	if ch.IsClosed() {
+		return nil, err
 	}
+	return ch, err
+}
 
+func (r *RabbitMQ) publish(ctx context.Context, ch *amqp091.Channel, e Event) (*amqp091.DeferredConfirmation, error) {
 	pub := amqp091.Publishing{
-		ContentType:  "application/json",
+		ContentType:  ContentTypeJSON,
 		DeliveryMode: amqp091.Persistent,
 		MessageId:    e.Id.String(),
 		Timestamp:    e.TS,
 		Type:         e.Method,
-		Body:         b,
 	}
-	if err = r.pubCh.PublishWithContext(
+	var err error
+	if r.encoding == EncodingProtobuf {
+		pub.ContentType = ContentTypeProtobuf
+		pub.Body, err = MarshalProto(e)
+	} else {
+		// for a good boost of performance(x3 minimum) and to avoid reflection under the hood
+		// better to use codegen for marshal/unmarshal for example:
+		// https://github.com/mailru/easyjson
+		pub.Body, err = json.Marshal(e)
+	}
+	if err != nil {
+		r.alert(alert.Alert{
+			Key:      "mq_event_unmarshalable:" + e.Method,
+			Severity: alert.SeverityError,
+			Summary:  "mq event can not be marshaled",
+			Details:  map[string]string{"event_action": e.Method, "error": err.Error()},
+		})
+		return nil, err
+	}
+
+	return ch.PublishWithDeferredConfirmWithContext(
 		ctx,
 		r.cfg.Exchange,
-		e.Method,
+		routes[e.Method],
 		true,
 		false,
 		pub,
-	); err != nil {
-		return err
+	)
+}
+
+// openChannel opens a channel in the confirm mode, reconnecting if the
+// connection is gone(broker restart).
+func (r *RabbitMQ) openChannel() (*amqp091.Channel, error) {
+	r.connMu.Lock()
+	defer r.connMu.Unlock()
+
+	if r.conn == nil || r.conn.IsClosed() {
+		conn, err := r.dialConfig()
+		if err != nil {
+			return nil, fmt.Errorf("reconnect: %w", err)
+		}
+		r.conn = conn
+		r.log.Info("rabbitmq reconnected")
 	}
 
-	return nil
+	ch, err := r.conn.Channel()
+	if err != nil {
+		return nil, err
+	}
+	if err = ch.Confirm(false); err != nil {
+		_ = ch.Close()
+		return nil, err
+	}
+	// mandatory publishings nobody is bound to come back
+	returns := ch.NotifyReturn(make(chan amqp091.Return, 1))
+	go func() {
+		for ret := range returns {
+			r.mCounter.WithLabelValues("mq_events_unroutable_total").Inc()
+			r.log.Error("mq event returned",
+				zap.String("event_id", ret.MessageId),
+				zap.String("routing_key", ret.RoutingKey),
+				zap.String("reason", ret.ReplyText),
+			)
+			r.alert(alert.Alert{
+				Key:      "mq_event_unroutable:" + ret.RoutingKey,
+				Severity: alert.SeverityError,
+				Summary:  "mq event returned by the broker: no queue is bound to it",
+				Details:  map[string]string{"routing_key": ret.RoutingKey, "reason": ret.ReplyText},
+			})
+		}
+	}()
+
+	return ch, nil
 }
 
-func (r *RabbitMQ) GetInputChan() chan Event     { return r.in }
-func (r *RabbitMQ) GetConn() *amqp091.Connection { return r.conn }
+// retryLater keeps the event in the bounded retry buffer, a full buffer drops it
+func (r *RabbitMQ) retryLater(e Event) {
+	select {
+	case r.retry <- e:
+		r.mCounter.WithLabelValues("mq_events_retried_total").Inc()
+	default:
+		if r.onDrop != nil {
+			r.onDrop(e)
+			return
+		}
+		r.mCounter.WithLabelValues("mq_events_dropped_total").Inc()
+		r.log.Error("mq event dropped: retry buffer is full",
+			zap.String("event_id", e.Id.String()),
+			zap.String("event_action", e.Method),
+		)
+		r.alert(alert.Alert{
+			Key:      alertKeyDropped,
+			Severity: alert.SeverityCritical,
+			Summary:  "mq event dropped: the retry buffer is full",
+			Details:  map[string]string{"event_action": e.Method},
+		})
+	}
+}
+
+// alert - a failure to be seen by the operators, not only in the log
+func (r *RabbitMQ) alert(a alert.Alert) {
+	if r.alerter == nil {
+		return
+	}
+	_ = r.alerter.Notify(context.Background(), a)
+}
+
+// dropRetries empties the retry buffer on shutdown into the drop handler
+func (r *RabbitMQ) dropRetries() {
+	n := len(r.retry)
+	if n == 0 {
+		return
+	}
+	if r.onDrop == nil {
+		r.mCounter.WithLabelValues("mq_events_dropped_total").Add(float64(n))
+		r.log.Error("mq events dropped on shutdown", zap.Int("count", n))
+		r.alert(alert.Alert{
+			Key:      alertKeyDropped,
+			Severity: alert.SeverityCritical,
+			Summary:  "mq events dropped on shutdown",
+			Details:  map[string]string{"count": strconv.Itoa(n)},
+		})
+		return
+	}
+	for ; n > 0; n-- {
+		r.onDrop(<-r.retry)
+	}
+}
+
+// retryWorker moves the failed events back to their lanes every RetryInterval,
+// events not fitting into a lane wait for the next round.
+func (r *RabbitMQ) retryWorker(ctx context.Context) {
+	t := time.NewTicker(r.cfg.RetryInterval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-t.C:
+			for n := len(r.retry); n > 0; n-- {
+				e := <-r.retry
+				select {
+				case r.lanes[r.laneOf(e)] <- e:
+				default:
+					r.retryLater(e)
+				}
+			}
+		case <-ctx.Done():
+			// the rest is dropped by PublisherWorker after the lanes flush
+			return
+		}
+	}
+}
+
+func (r *RabbitMQ) GetConn() *amqp091.Connection {
+	r.connMu.Lock()
+	defer r.connMu.Unlock()
+	return r.conn
+}
@@ -0,0 +1,165 @@
+package mq
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"user-manager-api/config"
+	"user-manager-api/pkg/alert"
+)
+
+func newTestMQ(t *testing.T, cfg config.MQ) (*RabbitMQ, *prometheus.CounterVec) {
+	t.Helper()
+	counter := prometheus.NewCounterVec(prometheus.CounterOpts{Name: "test_counters"}, []string{"result"})
+	return New(cfg, zap.NewNop(), counter), counter
+}
+
+func counterValue(t *testing.T, counter *prometheus.CounterVec, label string) float64 {
+	t.Helper()
+	var m dto.Metric
+	require.NoError(t, counter.WithLabelValues(label).Write(&m))
+	return m.GetCounter().GetValue()
+}
+
+func TestPublish_Table(t *testing.T) {
+	type tc struct {
+		name     string
+		method   string
+		prefill  int
+		wantErr  error
+		rejected float64
+	}
+	cases := []tc{
+		{"queued", http.MethodPost, 0, nil, 0},
+		{"domain event", EventUserFilesChanged, 0, nil, 0},
+		{"not routed", "PATCH", 0, ErrUnroutable, 0},
+		{"full lane", http.MethodPut, 2, ErrBackpressure, 1},
+	}
+
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			r, counter := newTestMQ(t, config.MQ{BufferSize: 2, PublishWorkers: 1, EnqueueTimeout: 10 * time.Millisecond})
+			alerts := &alert.Recorder{}
+			r.SetAlerter(alerts)
+			for i := 0; i < tt.prefill; i++ {
+				r.lanes[0] <- Event{}
+			}
+
+			start := time.Now()
+			err := r.Publish(context.Background(), Event{Id: uuid.New(), Method: tt.method, UserID: uuid.NewString()})
+			if tt.wantErr == nil {
+				require.NoError(t, err)
+				assert.Len(t, r.lanes[0], 1)
+			} else {
+				require.ErrorIs(t, err, tt.wantErr)
+			}
+			if tt.wantErr == ErrBackpressure {
+				assert.GreaterOrEqual(t, time.Since(start), 10*time.Millisecond, "waits for a room first")
+			}
+			assert.Len(t, alerts.Alerts(), int(tt.rejected))
+			assert.Equal(t, tt.rejected, counterValue(t, counter, "mq_events_rejected_total"))
+		})
+	}
+}
+
+func TestPublish_CanceledContext(t *testing.T) {
+	r, _ := newTestMQ(t, config.MQ{BufferSize: 0, PublishWorkers: 1, EnqueueTimeout: time.Hour})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := r.Publish(ctx, Event{Method: http.MethodPost})
+	require.ErrorIs(t, err, context.Canceled)
+}
+
+func TestLaneOf_KeepsUserOrder(t *testing.T) {
+	r, _ := newTestMQ(t, config.MQ{BufferSize: 10, PublishWorkers: 4, EnqueueTimeout: time.Second})
+	user := uuid.NewString()
+
+	for _, method := range []string{http.MethodPost, EventUserFilesChanged, http.MethodPut, http.MethodDelete} {
+		require.NoError(t, r.Publish(context.Background(), Event{Method: method, UserID: user}))
+	}
+
+	lane := r.lanes[r.laneOf(Event{UserID: user})]
+	require.Len(t, lane, 4)
+	var got []string
+	for len(lane) > 0 {
+		got = append(got, (<-lane).Method)
+	}
+	assert.Equal(t, []string{http.MethodPost, EventUserFilesChanged, http.MethodPut, http.MethodDelete}, got)
+}
+
+func TestCollect(t *testing.T) {
+	lane := make(chan Event, 5)
+	for i := 0; i < 5; i++ {
+		lane <- Event{}
+	}
+
+	assert.Len(t, collect(lane, nil, 3), 3)
+	assert.Len(t, collect(lane, []Event{{}}, 3), 3)
+	assert.Empty(t, collect(lane, nil, 3))
+}
+
+func TestRetryLater_DropsOverflow(t *testing.T) {
+	r, counter := newTestMQ(t, config.MQ{PublishWorkers: 1, RetryBufferSize: 1})
+	alerts := &alert.Recorder{}
+	r.SetAlerter(alerts)
+
+	r.retryLater(Event{Id: uuid.New()})
+	r.retryLater(Event{Id: uuid.New(), Method: http.MethodPost})
+
+	assert.Len(t, r.retry, 1)
+	assert.Equal(t, float64(1), counterValue(t, counter, "mq_events_retried_total"))
+	assert.Equal(t, float64(1), counterValue(t, counter, "mq_events_dropped_total"))
+	require.Len(t, alerts.Alerts(), 1)
+	assert.Equal(t, alertKeyDropped, alerts.Alerts()[0].Key)
+	assert.Equal(t, http.MethodPost, alerts.Alerts()[0].Details["event_action"])
+}
+
+func TestRetryLater_DropHandler(t *testing.T) {
+	r, counter := newTestMQ(t, config.MQ{PublishWorkers: 1, RetryBufferSize: 1})
+	var kept []Event
+	r.SetDropHandler(func(e Event) { kept = append(kept, e) })
+
+	first, second := Event{Id: uuid.New()}, Event{Id: uuid.New()}
+	r.retryLater(first)
+	r.retryLater(second)
+	assert.Equal(t, []Event{second}, kept)
+
+	// shutdown
+	r.dropRetries()
+	assert.Equal(t, []Event{second, first}, kept)
+	assert.Empty(t, r.retry)
+	assert.Zero(t, counterValue(t, counter, "mq_events_dropped_total"))
+}
+
+func TestRetryWorker_Redispatches(t *testing.T) {
+	r, _ := newTestMQ(t, config.MQ{BufferSize: 1, PublishWorkers: 1, RetryBufferSize: 4, RetryInterval: 5 * time.Millisecond})
+	for i := 0; i < 3; i++ {
+		r.retryLater(Event{Id: uuid.New()})
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		r.retryWorker(ctx)
+	}()
+
+	// one fits into the lane, the rest waits for the next rounds
+	require.Eventually(t, func() bool { return len(r.lanes[0]) == 1 }, time.Second, time.Millisecond)
+	<-r.lanes[0]
+	require.Eventually(t, func() bool { return len(r.lanes[0]) == 1 }, time.Second, time.Millisecond)
+	cancel()
+	<-done
+	assert.Len(t, r.retry, 1)
+}
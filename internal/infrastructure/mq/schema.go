@@ -0,0 +1,40 @@
+package mq
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"user-manager-api/pkg/jsonschema"
+	"user-manager-api/schemas"
+)
+
+// ErrInvalidEvent - the event does not match its published contract(schemas/event.json)
+var ErrInvalidEvent = errors.New("mq event does not match the schema")
+
+// EventSchema - the contract of Event
+func EventSchema() (*jsonschema.Schema, error) {
+	doc, err := schemas.FS.ReadFile(schemas.Event)
+	if err != nil {
+		return nil, err
+	}
+	return jsonschema.Compile(doc)
+}
+
+// SetSchema enables the validation of the events on Publish, must be called
+// before PublisherWorker
+func (r *RabbitMQ) SetSchema(s *jsonschema.Schema) {
+	r.schema = s
+}
+
+// validateEvent - e as it is going to be published
+func validateEvent(s *jsonschema.Schema, e Event) error {
+	b, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	if err = s.Validate(b); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidEvent, err)
+	}
+	return nil
+}
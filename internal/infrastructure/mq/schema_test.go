@@ -0,0 +1,67 @@
+package mq
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"user-manager-api/config"
+	"user-manager-api/internal/interface/api/rest/dto/user"
+)
+
+// TestEventSchema_Contract fails once Event or its payload drifts from
+// schemas/event.json: a field added, renamed or removed must be published
+// in the schema as well.
+func TestEventSchema_Contract(t *testing.T) {
+	s, err := EventSchema()
+	require.NoError(t, err)
+
+	files, bytes := uint64(2), uint64(2048)
+	for method := range routes {
+		t.Run(method, func(t *testing.T) {
+			e := Event{
+				Id:     uuid.New(),
+				TS:     time.Now(),
+				Method: method,
+				UserID: uuid.NewString(),
+				Payload: user.User{
+					UUID:              uuid.New(),
+					Email:             "jane@example.com",
+					Name:              "Jane",
+					Lastname:          "Doe",
+					BirthDate:         time.Date(1990, 1, 2, 0, 0, 0, 0, time.UTC),
+					Phone:             "+15550100",
+					PendingEmail:      "jane.doe@example.com",
+					FilesCount:        &files,
+					TotalStorageBytes: &bytes,
+				},
+				Meta: map[string]string{"new_email": "jane.doe@example.com"},
+			}
+			require.NoError(t, validateEvent(s, e))
+
+			// the zero payload of the events without a user
+			require.NoError(t, validateEvent(s, Event{Id: uuid.New(), TS: time.Now(), Method: method, UserID: uuid.NewString()}))
+		})
+	}
+
+}
+
+func TestPublish_InvalidEvent(t *testing.T) {
+	s, err := EventSchema()
+	require.NoError(t, err)
+	r, counter := newTestMQ(t, config.MQ{BufferSize: 2, PublishWorkers: 1, EnqueueTimeout: 10 * time.Millisecond})
+	r.SetSchema(s)
+
+	// not a uuid
+	err = r.Publish(context.Background(), Event{Id: uuid.New(), TS: time.Now(), Method: EventInvitationCreated, UserID: "42"})
+	require.ErrorIs(t, err, ErrInvalidEvent)
+	require.Contains(t, err.Error(), `$.user_id: "42" is not a uuid`)
+	require.Len(t, r.lanes[0], 0)
+	require.Equal(t, float64(1), counterValue(t, counter, "mq_events_invalid_total"))
+
+	require.NoError(t, r.Publish(context.Background(), Event{Id: uuid.New(), TS: time.Now(), Method: EventInvitationCreated, UserID: uuid.NewString()}))
+	require.Len(t, r.lanes[0], 1)
+}
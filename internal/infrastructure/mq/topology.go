@@ -0,0 +1,149 @@
+package mq
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"go.uber.org/zap"
+
+	"user-manager-api/internal/domain/eventbus"
+)
+
+// RoutingKeys - the bindings of the queue, sorted
+func RoutingKeys() []string {
+	keys := make([]string, 0, len(routes))
+	for _, rk := range routes {
+		keys = append(keys, rk)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// SetManagement enables the topology checks
+func (r *RabbitMQ) SetManagement(m *Management) {
+	r.mgmt = m
+}
+
+func (r *RabbitMQ) CheckTopology(ctx context.Context) (*eventbus.TopologyReport, error) {
+	if r.mgmt == nil {
+		return nil, eventbus.ErrNoManagement
+	}
+
+	report := &eventbus.TopologyReport{
+		Connected:   r.connected(),
+		RetryQueued: len(r.retry),
+		Exchange:    eventbus.EntityState{Name: r.cfg.Exchange},
+		Queue:       eventbus.EntityState{Name: r.cfg.QueueName},
+	}
+	for _, lane := range r.lanes {
+		report.Buffered += len(lane)
+	}
+
+	e, err := r.mgmt.exchange(ctx, r.cfg.Exchange)
+	if err != nil {
+		return nil, err
+	}
+	if e != nil {
+		report.Exchange.Found = true
+		if e.Type != r.cfg.ExchangeType {
+			report.Exchange.Mismatches = append(report.Exchange.Mismatches, fmt.Sprintf("type %s, declared %s", e.Type, r.cfg.ExchangeType))
+		}
+		report.Exchange.Mismatches = append(report.Exchange.Mismatches, flagMismatches(e.Durable, e.AutoDelete, false)...)
+	}
+
+	q, err := r.mgmt.queue(ctx, r.cfg.QueueName)
+	if err != nil {
+		return nil, err
+	}
+	if q != nil {
+		report.Queue.Found = true
+		report.Queue.Mismatches = flagMismatches(q.Durable, q.AutoDelete, q.Exclusive)
+	}
+
+	bound := []string{}
+	if report.Exchange.Found && report.Queue.Found {
+		if bound, err = r.mgmt.routingKeys(ctx, r.cfg.Exchange, r.cfg.QueueName); err != nil {
+			return nil, err
+		}
+	}
+	declared := RoutingKeys()
+	for _, rk := range declared {
+		if !slices.Contains(bound, rk) {
+			report.MissingBindings = append(report.MissingBindings, rk)
+		}
+	}
+	for _, rk := range bound {
+		if !slices.Contains(declared, rk) && !slices.Contains(report.ExtraBindings, rk) {
+			report.ExtraBindings = append(report.ExtraBindings, rk)
+		}
+	}
+	slices.Sort(report.ExtraBindings)
+
+	if report.Drift() {
+		r.mCounter.WithLabelValues("mq_topology_drift_total").Inc()
+	}
+
+	return report, nil
+}
+
+// RepairTopology declares the missing exchange, queue and bindings and removes
+// the extra bindings, the mismatches are left to an operator: eventbus.ErrTopologyMismatch
+func (r *RabbitMQ) RepairTopology(ctx context.Context) (*eventbus.TopologyReport, error) {
+	report, err := r.CheckTopology(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(report.Exchange.Mismatches) > 0 || len(report.Queue.Mismatches) > 0 {
+		return report, eventbus.ErrTopologyMismatch
+	}
+	if !report.Drift() {
+		return report, nil
+	}
+
+	// a channel of its own: a failed declaration closes it
+	ch, err := r.openChannel()
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = ch.Close() }()
+
+	if err = r.declare(ch); err != nil {
+		return nil, fmt.Errorf("declare: %w", err)
+	}
+	for _, rk := range report.ExtraBindings {
+		if err = ch.QueueUnbind(r.cfg.QueueName, rk, r.cfg.Exchange, nil); err != nil {
+			return nil, fmt.Errorf("unbind %s: %w", rk, err)
+		}
+	}
+	r.mCounter.WithLabelValues("mq_topology_repaired_total").Inc()
+	r.log.Warn("rabbitmq topology repaired",
+		zap.Bool("exchange_declared", !report.Exchange.Found),
+		zap.Bool("queue_declared", !report.Queue.Found),
+		zap.Strings("bound", report.MissingBindings),
+		zap.Strings("unbound", report.ExtraBindings),
+	)
+
+	return r.CheckTopology(ctx)
+}
+
+func (r *RabbitMQ) connected() bool {
+	r.connMu.Lock()
+	defer r.connMu.Unlock()
+	return r.conn != nil && !r.conn.IsClosed()
+}
+
+// flagMismatches - of the declared durable, not auto-deleted, not exclusive
+func flagMismatches(durable, autoDelete, exclusive bool) []string {
+	var out []string
+	if !durable {
+		out = append(out, "not durable")
+	}
+	if autoDelete {
+		out = append(out, "auto-delete")
+	}
+	if exclusive {
+		out = append(out, "exclusive")
+	}
+	return out
+}
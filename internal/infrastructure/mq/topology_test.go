@@ -0,0 +1,183 @@
+package mq
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"user-manager-api/config"
+	"user-manager-api/internal/domain/eventbus"
+)
+
+// fakeManagement - the management API of a vhost "usermanager", nil entities are 404
+type fakeManagement struct {
+	exchange *mgmtExchange
+	queue    *mgmtQueue
+	bindings []mgmtBinding
+	status   int
+}
+
+func (f *fakeManagement) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if user, password, ok := r.BasicAuth(); !ok || user != "guest" || password != "secret" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	if f.status != 0 {
+		w.WriteHeader(f.status)
+		return
+	}
+
+	var body any
+	switch r.URL.EscapedPath() {
+	case "/api/exchanges/usermanager/usermanager.events":
+		if f.exchange != nil {
+			body = f.exchange
+		}
+	case "/api/queues/usermanager/users.queue":
+		if f.queue != nil {
+			body = f.queue
+		}
+	case "/api/bindings/usermanager/e/usermanager.events/q/users.queue":
+		body = f.bindings
+	}
+	if body == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func declaredBindings() []mgmtBinding {
+	var out []mgmtBinding
+	for _, rk := range RoutingKeys() {
+		out = append(out, mgmtBinding{RoutingKey: rk})
+	}
+	return out
+}
+
+func TestCheckTopology_Table(t *testing.T) {
+	type tc struct {
+		name      string
+		mgmt      fakeManagement
+		wantDrift bool
+		check     func(t *testing.T, report *eventbus.TopologyReport)
+	}
+	declared := fakeManagement{
+		exchange: &mgmtExchange{Type: "topic", Durable: true},
+		queue:    &mgmtQueue{Durable: true},
+		bindings: declaredBindings(),
+	}
+	cases := []tc{
+		{"in sync", declared, false, func(t *testing.T, report *eventbus.TopologyReport) {
+			assert.True(t, report.Exchange.Found)
+			assert.True(t, report.Queue.Found)
+			assert.Empty(t, report.MissingBindings)
+		}},
+		{"nothing declared", fakeManagement{}, true, func(t *testing.T, report *eventbus.TopologyReport) {
+			assert.False(t, report.Exchange.Found)
+			assert.False(t, report.Queue.Found)
+			assert.Equal(t, RoutingKeys(), report.MissingBindings)
+		}},
+		{"exchange type and flags", fakeManagement{
+			exchange: &mgmtExchange{Type: "direct", AutoDelete: true},
+			queue:    &mgmtQueue{Durable: true, Exclusive: true},
+			bindings: declaredBindings(),
+		}, true, func(t *testing.T, report *eventbus.TopologyReport) {
+			assert.Equal(t, []string{"type direct, declared topic", "not durable", "auto-delete"}, report.Exchange.Mismatches)
+			assert.Equal(t, []string{"exclusive"}, report.Queue.Mismatches)
+		}},
+		{"binding missing and extra", fakeManagement{
+			exchange: declared.exchange,
+			queue:    declared.queue,
+			bindings: append(declaredBindings()[1:], mgmtBinding{RoutingKey: "users.legacy"}, mgmtBinding{RoutingKey: "users.legacy"}),
+		}, true, func(t *testing.T, report *eventbus.TopologyReport) {
+			assert.Equal(t, RoutingKeys()[:1], report.MissingBindings)
+			assert.Equal(t, []string{"users.legacy"}, report.ExtraBindings)
+		}},
+	}
+
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(&tt.mgmt)
+			defer srv.Close()
+
+			r, counter := newTestMQ(t, config.MQ{
+				Exchange:       "usermanager.events",
+				ExchangeType:   "topic",
+				QueueName:      "users.queue",
+				BufferSize:     4,
+				PublishWorkers: 2,
+			})
+			r.SetManagement(NewManagement(srv.URL, "guest", "secret", "usermanager", time.Second))
+			r.lanes[1] <- Event{}
+
+			report, err := r.CheckTopology(context.Background())
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantDrift, report.Drift())
+			assert.False(t, report.Connected)
+			assert.Equal(t, 1, report.Buffered)
+			tt.check(t, report)
+
+			want := 0.0
+			if tt.wantDrift {
+				want = 1
+			}
+			assert.Equal(t, want, counterValue(t, counter, "mq_topology_drift_total"))
+		})
+	}
+}
+
+func TestCheckTopology_Invalid_Management(t *testing.T) {
+	cfg := config.MQ{Exchange: "usermanager.events", QueueName: "users.queue", PublishWorkers: 1}
+
+	t.Run("not configured", func(t *testing.T) {
+		r, _ := newTestMQ(t, cfg)
+		_, err := r.CheckTopology(context.Background())
+		assert.ErrorIs(t, err, eventbus.ErrNoManagement)
+	})
+
+	type tc struct {
+		name     string
+		password string
+		status   int
+	}
+	cases := []tc{
+		{"wrong credentials", "wrong", 0},
+		{"server error", "secret", http.StatusInternalServerError},
+	}
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(&fakeManagement{status: tt.status})
+			defer srv.Close()
+
+			r, _ := newTestMQ(t, cfg)
+			r.SetManagement(NewManagement(srv.URL, "guest", tt.password, "usermanager", time.Second))
+			_, err := r.CheckTopology(context.Background())
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestRepairTopology_Mismatch(t *testing.T) {
+	srv := httptest.NewServer(&fakeManagement{
+		exchange: &mgmtExchange{Type: "fanout", Durable: true},
+		queue:    &mgmtQueue{Durable: true},
+	})
+	defer srv.Close()
+
+	r, counter := newTestMQ(t, config.MQ{Exchange: "usermanager.events", ExchangeType: "topic", QueueName: "users.queue", PublishWorkers: 1})
+	r.SetManagement(NewManagement(srv.URL, "guest", "secret", "usermanager", time.Second))
+
+	report, err := r.RepairTopology(context.Background())
+	require.ErrorIs(t, err, eventbus.ErrTopologyMismatch)
+	assert.NotEmpty(t, report.Exchange.Mismatches)
+	assert.Zero(t, counterValue(t, counter, "mq_topology_repaired_total"))
+}
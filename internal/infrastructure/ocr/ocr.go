@@ -0,0 +1,88 @@
+package ocr
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"user-manager-api/config"
+)
+
+var ErrUnsupported = errors.New("unsupported mime type for text extraction")
+
+// Extractor extracts the text of the images through tesseract and the text
+// layer of the PDFs through poppler's "pdftotext", each when it is configured
+// and found. A cloud OCR is another ports.TextExtractor.
+type Extractor struct {
+	tesseract string
+	languages string
+	pdftotext string
+}
+
+func New(cfg config.OCR) *Extractor {
+	return &Extractor{
+		tesseract: lookPath(cfg.Tesseract),
+		languages: cfg.Languages,
+		pdftotext: lookPath(cfg.PDFToText),
+	}
+}
+
+func (e *Extractor) Supports(mimeType string) bool {
+	switch normalize(mimeType) {
+	case "image/png", "image/jpeg", "image/gif", "image/tiff", "image/bmp", "image/webp":
+		return e.tesseract != ""
+	case "application/pdf":
+		return e.pdftotext != ""
+	}
+	return false
+}
+
+func (e *Extractor) Extract(ctx context.Context, mimeType string, data []byte) (string, error) {
+	if !e.Supports(mimeType) {
+		return "", ErrUnsupported
+	}
+	if normalize(mimeType) == "application/pdf" {
+		// stdin to stdout, the pages are separated by form feeds
+		return run(ctx, data, e.pdftotext, "-enc", "UTF-8", "-", "-")
+	}
+
+	args := []string{"stdin", "stdout"}
+	if e.languages != "" {
+		args = append(args, "-l", e.languages)
+	}
+
+	return run(ctx, data, e.tesseract, args...)
+}
+
+func run(ctx context.Context, data []byte, name string, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Stdin = bytes.NewReader(data)
+	var out, stderr bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("%s: %w: %s", name, err, strings.TrimSpace(stderr.String()))
+	}
+
+	return out.String(), nil
+}
+
+// lookPath - the binary if it is found, "" - disabled
+func lookPath(name string) string {
+	if name == "" {
+		return ""
+	}
+	if _, err := exec.LookPath(name); err != nil {
+		return ""
+	}
+
+	return name
+}
+
+func normalize(mimeType string) string {
+	mt, _, _ := strings.Cut(mimeType, ";")
+	return strings.ToLower(strings.TrimSpace(mt))
+}
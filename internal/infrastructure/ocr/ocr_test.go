@@ -0,0 +1,58 @@
+package ocr
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"user-manager-api/config"
+)
+
+// fakeBinary - a script printing its args and stdin, instead of the engine
+func fakeBinary(t *testing.T, name string) string {
+	t.Helper()
+	bin := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(bin, []byte("#!/bin/sh\necho \"$@\"\ncat\n"), 0o755))
+
+	return bin
+}
+
+func TestExtract_Table(t *testing.T) {
+	e := New(config.OCR{
+		Tesseract: fakeBinary(t, "tesseract"),
+		Languages: "eng+deu",
+		PDFToText: fakeBinary(t, "pdftotext"),
+	})
+
+	cases := []struct {
+		name string
+		mime string
+		want string
+	}{
+		{"image", "image/png", "stdin stdout -l eng+deu\nscan"},
+		{"pdf", "application/pdf; charset=binary", "-enc UTF-8 - -\nscan"},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			require.True(t, e.Supports(tt.mime))
+			got, err := e.Extract(context.Background(), tt.mime, []byte("scan"))
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestExtract_Unsupported(t *testing.T) {
+	e := New(config.OCR{Tesseract: "", PDFToText: "no-such-binary-for-sure"})
+
+	for _, mime := range []string{"image/png", "application/pdf", "text/plain"} {
+		assert.False(t, e.Supports(mime), mime)
+		_, err := e.Extract(context.Background(), mime, []byte("x"))
+		assert.ErrorIs(t, err, ErrUnsupported, mime)
+	}
+}
@@ -0,0 +1,188 @@
+// Package paseto issues and verifies PASETO v4 access tokens(aidanwoods.dev/go-paseto):
+// v4.local(encrypted with a symmetric key) or v4.public(signed with an Ed25519 key).
+// The tokens carry neither a footer nor an implicit assertion.
+package paseto
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"aidanwoods.dev/go-paseto"
+
+	"user-manager-api/internal/domain/token"
+)
+
+// LocalKeySize - of the v4.local symmetric key
+const LocalKeySize = 32
+
+var errInvalidToken = errors.New("invalid token")
+
+type Service struct {
+	// local - v4.local, false for v4.public
+	local      bool
+	localKey   paseto.V4SymmetricKey
+	privateKey paseto.V4AsymmetricSecretKey
+	publicKey  paseto.V4AsymmetricPublicKey
+	// parser - the expiry is checked by ValidateToken, it tells token.ErrExpired
+	parser  paseto.Parser
+	revoked token.RevocationCheck
+	role    token.RoleLookup
+	// refreshWindow - see RefreshToken, 0 - off
+	refreshWindow time.Duration
+}
+
+// NewLocal - v4.local tokens, the key is shared by all the instances
+func NewLocal(key []byte) (*Service, error) {
+	if len(key) != LocalKeySize {
+		return nil, fmt.Errorf("v4.local key must be %d bytes, got %d", LocalKeySize, len(key))
+	}
+	k, err := paseto.V4SymmetricKeyFromBytes(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Service{local: true, localKey: k, parser: paseto.NewParserWithoutExpiryCheck()}, nil
+}
+
+// NewPublic - v4.public tokens, key is the Ed25519 private key or its seed
+func NewPublic(key []byte) (*Service, error) {
+	var priv ed25519.PrivateKey
+	switch len(key) {
+	case ed25519.SeedSize:
+		priv = ed25519.NewKeyFromSeed(key)
+	case ed25519.PrivateKeySize:
+		priv = ed25519.PrivateKey(bytes.Clone(key))
+	default:
+		return nil, fmt.Errorf("v4.public key must be %d or %d bytes, got %d", ed25519.SeedSize, ed25519.PrivateKeySize, len(key))
+	}
+	k, err := paseto.NewV4AsymmetricSecretKeyFromEd25519(priv)
+	if err != nil {
+		return nil, fmt.Errorf("v4.public key: %w", err)
+	}
+
+	return &Service{privateKey: k, publicKey: k.Public(), parser: paseto.NewParserWithoutExpiryCheck()}, nil
+}
+
+// SetRevocationCheck must be called before serving requests, without it no token is revoked.
+func (s *Service) SetRevocationCheck(check token.RevocationCheck) { s.revoked = check }
+
+// SetRoleLookup must be called before serving requests, without it the role claim is trusted.
+func (s *Service) SetRoleLookup(lookup token.RoleLookup) { s.role = lookup }
+
+// SetRefreshWindow - how long before the expiry the tokens are refreshed, 0 - never
+func (s *Service) SetRefreshWindow(window time.Duration) { s.refreshWindow = window }
+
+// claims - the token payload, exp and iat are the registered PASETO claims
+type claims struct {
+	UserID string `json:"user_id"`
+	Role   string `json:"role"`
+	// ActAs - UUID of the admin impersonating UserID, empty for regular tokens
+	ActAs string `json:"act_as,omitempty"`
+	// DeviceID - UUID of the device of the login, empty for unbound tokens
+	DeviceID  string    `json:"device_id,omitempty"`
+	ExpiresAt time.Time `json:"exp"`
+	IssuedAt  time.Time `json:"iat"`
+}
+
+func (s *Service) GenerateToken(userID, role string, expiresIn time.Duration) (string, error) {
+	return s.issue(claims{UserID: userID, Role: role}, expiresIn)
+}
+
+// GenerateImpersonationToken - token of userID issued to the actorID admin
+func (s *Service) GenerateImpersonationToken(userID, role, actorID string, expiresIn time.Duration) (string, error) {
+	return s.issue(claims{UserID: userID, Role: role, ActAs: actorID}, expiresIn)
+}
+
+// GenerateDeviceToken - token of userID bound to the deviceID it logged in from
+func (s *Service) GenerateDeviceToken(userID, role, deviceID string, expiresIn time.Duration) (string, error) {
+	return s.issue(claims{UserID: userID, Role: role, DeviceID: deviceID}, expiresIn)
+}
+
+// issue - iat has a second precision like the JWT one, the revocation check relies on it
+func (s *Service) issue(c claims, expiresIn time.Duration) (string, error) {
+	now := time.Now().UTC().Truncate(time.Second)
+	c.IssuedAt = now
+	c.ExpiresAt = now.Add(expiresIn)
+
+	payload, err := json.Marshal(c)
+	if err != nil {
+		return "", err
+	}
+	t, err := paseto.NewTokenFromClaimsJSON(payload, nil)
+	if err != nil {
+		return "", err
+	}
+	if s.local {
+		return t.V4Encrypt(s.localKey, nil), nil
+	}
+
+	return t.V4Sign(s.privateKey, nil), nil
+}
+
+func (s *Service) ValidateToken(tokenStr string) (*token.Claims, error) {
+	var (
+		t   *paseto.Token
+		err error
+	)
+	if s.local {
+		t, err = s.parser.ParseV4Local(s.localKey, tokenStr, nil)
+	} else {
+		t, err = s.parser.ParseV4Public(s.publicKey, tokenStr, nil)
+	}
+	// a token with a footer is not ours
+	if err != nil || len(t.Footer()) > 0 {
+		return nil, errInvalidToken
+	}
+
+	var c claims
+	if err = json.Unmarshal(t.ClaimsJSON(), &c); err != nil {
+		return nil, errors.New("invalid claims")
+	}
+	if !time.Now().Before(c.ExpiresAt) {
+		return nil, token.ErrExpired
+	}
+
+	tc := &token.Claims{
+		UserID:    c.UserID,
+		Role:      c.Role,
+		ActAs:     c.ActAs,
+		ExpiresAt: c.ExpiresAt,
+		DeviceID:  c.DeviceID,
+	}
+	if !c.IssuedAt.IsZero() {
+		tc.IssuedAt = &c.IssuedAt
+	}
+
+	return tc, nil
+}
+
+func (s *Service) IsRevoked(ctx context.Context, claims *token.Claims) (bool, error) {
+	if s.revoked == nil {
+		return false, nil
+	}
+
+	return s.revoked(ctx, claims)
+}
+
+func (s *Service) CurrentRole(ctx context.Context, claims *token.Claims) (string, error) {
+	if s.role == nil {
+		return claims.Role, nil
+	}
+
+	return s.role(ctx, claims)
+}
+
+// RefreshToken - a token of the same user and device with role for the lifetime
+// of c, "" unless it expires within the refresh window
+func (s *Service) RefreshToken(c *token.Claims, role string) (string, error) {
+	if !c.RefreshDue(s.refreshWindow, time.Now()) {
+		return "", nil
+	}
+
+	return s.issue(claims{UserID: c.UserID, Role: role, DeviceID: c.DeviceID}, c.Lifetime())
+}
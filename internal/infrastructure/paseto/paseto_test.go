@@ -0,0 +1,238 @@
+package paseto
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"user-manager-api/internal/domain/token"
+)
+
+func localKey(b byte) []byte { return bytes.Repeat([]byte{b}, LocalKeySize) }
+
+func publicKey(b byte) []byte { return bytes.Repeat([]byte{b}, ed25519.SeedSize) }
+
+func TestNew_KeySize(t *testing.T) {
+	_, err := NewLocal(make([]byte, 16))
+	assert.EqualError(t, err, "v4.local key must be 32 bytes, got 16")
+
+	_, err = NewPublic(make([]byte, 16))
+	assert.EqualError(t, err, "v4.public key must be 32 or 64 bytes, got 16")
+
+	// the seed and the full private key are the same key
+	seeded, err := NewPublic(publicKey(1))
+	require.NoError(t, err)
+	full, err := NewPublic(ed25519.NewKeyFromSeed(publicKey(1)))
+	require.NoError(t, err)
+	tok, err := seeded.GenerateToken("u-42", "worker", time.Minute)
+	require.NoError(t, err)
+	_, err = full.ValidateToken(tok)
+	assert.NoError(t, err)
+}
+
+func TestGenerateAndValidate(t *testing.T) {
+	local, err := NewLocal(localKey(1))
+	require.NoError(t, err)
+	public, err := NewPublic(publicKey(1))
+	require.NoError(t, err)
+
+	for name, tt := range map[string]struct {
+		s      *Service
+		header string
+	}{
+		"local":  {local, "v4.local."},
+		"public": {public, "v4.public."},
+	} {
+		t.Run(name, func(t *testing.T) {
+			tok, err := tt.s.GenerateToken("u-42", "worker", time.Hour)
+			require.NoError(t, err)
+			assert.True(t, strings.HasPrefix(tok, tt.header), tok)
+
+			claims, err := tt.s.ValidateToken(tok)
+			require.NoError(t, err)
+			assert.Equal(t, "u-42", claims.UserID)
+			assert.Equal(t, "worker", claims.Role)
+			assert.Empty(t, claims.ActAs)
+			assert.True(t, claims.ExpiresAt.After(time.Now().Add(59*time.Minute)))
+			require.NotNil(t, claims.IssuedAt, "tokens must carry iat")
+			assert.Equal(t, claims.IssuedAt.Truncate(time.Second), *claims.IssuedAt)
+
+			tok, err = tt.s.GenerateImpersonationToken("u-42", "worker", "admin-1", time.Minute)
+			require.NoError(t, err)
+			claims, err = tt.s.ValidateToken(tok)
+			require.NoError(t, err)
+			assert.Equal(t, "admin-1", claims.ActAs)
+
+			tok, err = tt.s.GenerateDeviceToken("u-42", "worker", "device-1", time.Minute)
+			require.NoError(t, err)
+			claims, err = tt.s.ValidateToken(tok)
+			require.NoError(t, err)
+			assert.Equal(t, "device-1", claims.DeviceID)
+		})
+	}
+}
+
+func TestValidateToken_Invalid(t *testing.T) {
+	local, err := NewLocal(localKey(1))
+	require.NoError(t, err)
+	otherLocal, err := NewLocal(localKey(2))
+	require.NoError(t, err)
+	public, err := NewPublic(publicKey(1))
+	require.NoError(t, err)
+	otherPublic, err := NewPublic(publicKey(2))
+	require.NoError(t, err)
+
+	localTok, err := local.GenerateToken("u-42", "worker", time.Minute)
+	require.NoError(t, err)
+	publicTok, err := public.GenerateToken("u-42", "worker", time.Minute)
+	require.NoError(t, err)
+	expired, err := local.GenerateToken("u-42", "worker", -time.Minute)
+	require.NoError(t, err)
+
+	// a flipped byte of the body
+	tamper := func(tok, header string) string {
+		body, err := base64.RawURLEncoding.DecodeString(strings.TrimPrefix(tok, header))
+		require.NoError(t, err)
+		body[len(body)/2] ^= 1
+		return header + base64.RawURLEncoding.EncodeToString(body)
+	}
+
+	tests := []struct {
+		name  string
+		s     *Service
+		token string
+	}{
+		{"local: other key", otherLocal, localTok},
+		{"local: tampered", local, tamper(localTok, "v4.local.")},
+		{"local: public token", local, publicTok},
+		{"local: with footer", local, localTok + ".Zm9vdGVy"},
+		{"public: other key", otherPublic, publicTok},
+		{"public: tampered", public, tamper(publicTok, "v4.public.")},
+		{"public: local token", public, localTok},
+		{"malformed", local, "v4.local.!!!"},
+		{"a JWT", local, "eyJhbGciOiJIUzI1NiJ9.e30.sig"},
+		{"too short", local, "v4.local.AAAA"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims, err := tt.s.ValidateToken(tt.token)
+			assert.EqualError(t, err, "invalid token")
+			assert.Nil(t, claims)
+		})
+	}
+
+	claims, err := local.ValidateToken(expired)
+	assert.ErrorIs(t, err, token.ErrExpired, "expired")
+	assert.Nil(t, claims)
+}
+
+func TestIsRevoked(t *testing.T) {
+	s, err := NewLocal(localKey(1))
+	require.NoError(t, err)
+
+	tok, err := s.GenerateToken("u-42", "worker", time.Minute)
+	require.NoError(t, err)
+	claims, err := s.ValidateToken(tok)
+	require.NoError(t, err)
+
+	// no check configured
+	revoked, err := s.IsRevoked(context.Background(), claims)
+	require.NoError(t, err)
+	assert.False(t, revoked)
+
+	s.SetRevocationCheck(func(_ context.Context, c *token.Claims) (bool, error) {
+		assert.Equal(t, "u-42", c.UserID)
+		require.NotNil(t, c.IssuedAt)
+		return c.IssuedAt.Equal(*claims.IssuedAt), nil
+	})
+	revoked, err = s.IsRevoked(context.Background(), claims)
+	require.NoError(t, err)
+	assert.True(t, revoked)
+}
+
+func TestRefreshToken(t *testing.T) {
+	s, err := NewPublic(publicKey(1))
+	require.NoError(t, err)
+	s.SetRefreshWindow(10 * time.Minute)
+
+	tok, err := s.GenerateDeviceToken("u-42", "worker", "d-42", 5*time.Minute)
+	require.NoError(t, err)
+	claims, err := s.ValidateToken(tok)
+	require.NoError(t, err)
+
+	tok, err = s.RefreshToken(claims, "admin")
+	require.NoError(t, err)
+	got, err := s.ValidateToken(tok)
+	require.NoError(t, err)
+	assert.Equal(t, "u-42", got.UserID)
+	assert.Equal(t, "d-42", got.DeviceID)
+	assert.Equal(t, "admin", got.Role)
+	assert.Equal(t, 5*time.Minute, got.Lifetime())
+
+	tok, err = s.GenerateToken("u-42", "worker", time.Hour)
+	require.NoError(t, err)
+	claims, err = s.ValidateToken(tok)
+	require.NoError(t, err)
+	tok, err = s.RefreshToken(claims, "worker")
+	require.NoError(t, err)
+	assert.Empty(t, tok, "far from the expiry")
+}
+
+// the official vectors(github.com/paseto-standard/test-vectors): the claims of
+// the valid ones expired in 2022, so ErrExpired tells the token was decrypted
+// or verified
+func TestValidateToken_Vectors(t *testing.T) {
+	hexKey := func(s string) []byte {
+		b, err := hex.DecodeString(s)
+		require.NoError(t, err)
+		return b
+	}
+	local, err := NewLocal(hexKey("707172737475767778797a7b7c7d7e7f808182838485868788898a8b8c8d8e8f"))
+	require.NoError(t, err)
+	public, err := NewPublic(hexKey("b4cbfb43df4ce210727d953e4a713307fa19bb7d9f85041438d9e11b942a3774"))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		s       *Service
+		token   string
+		wantErr error
+	}{
+		{
+			name: "4-E-1", s: local, wantErr: token.ErrExpired,
+			token: "v4.local.AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAQAr68PS4AXe7If_ZgesdkUMvSwscFlAl1pk5HC0e8kApeaqMfGo_7OpBnwJOAbY9V7WU6abu74MmcUE8YWAiaArVI8XJ5hOb_4v9RmDkneN0S92dx0OW4pgy7omxgf3S8c3LlQg",
+		},
+		{
+			name: "4-E-2", s: local, wantErr: token.ErrExpired,
+			token: "v4.local.AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAQAr68PS4AXe7If_ZgesdkUMvS2csCgglvpk5HC0e8kApeaqMfGo_7OpBnwJOAbY9V7WU6abu74MmcUE8YWAiaArVI8XIemu9chy3WVKvRBfg6t8wwYHK0ArLxxfZP73W_vfwt5A",
+		},
+		{
+			name: "4-S-1", s: public, wantErr: token.ErrExpired,
+			token: "v4.public.eyJkYXRhIjoidGhpcyBpcyBhIHNpZ25lZCBtZXNzYWdlIiwiZXhwIjoiMjAyMi0wMS0wMVQwMDowMDowMCswMDowMCJ9bg_XBBzds8lTZShVlwwKSgeKpLT3yukTw6JUz3W4h_ExsQV-P0V54zemZDcAxFaSeef1QlXEFtkqxT1ciiQEDA",
+		},
+		{
+			name: "4-S-2 with a footer", s: public, wantErr: errInvalidToken,
+			token: "v4.public.eyJkYXRhIjoidGhpcyBpcyBhIHNpZ25lZCBtZXNzYWdlIiwiZXhwIjoiMjAyMi0wMS0wMVQwMDowMDowMCswMDowMCJ9v3Jt8mx_TdM2ceTGoqwrh4yDFn0XsHvvV_D0DtwQxVrJEBMl0F2caAdgnpKlt4p7xBnx1HcO-SPo8FPp214HDw.eyJraWQiOiJ6VmhNaVBCUDlmUmYyc25FY1Q3Z0ZUaW9lQTlDT2NOeTlEZmdMMVc2MGhhTiJ9",
+		},
+		{
+			name: "4-F-2 public as local", s: local, wantErr: errInvalidToken,
+			token: "v4.public.eyJpbnZhbGlkIjoidGhpcyBzaG91bGQgbmV2ZXIgZGVjb2RlIn22Sp4gjCaUw0c7EH84ZSm_jN_Qr41MrgLNu5LIBCzUr1pn3Z-Wukg9h3ceplWigpoHaTLcwxj0NsI1vjTh67YB.eyJraWQiOiJ6VmhNaVBCUDlmUmYyc25FY1Q3Z0ZUaW9lQTlDT2NOeTlEZmdMMVc2MGhhTiJ9",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.s.ValidateToken(tt.token)
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
@@ -0,0 +1,149 @@
+package password
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+
+	"user-manager-api/config"
+)
+
+const (
+	AlgorithmBcrypt   = "bcrypt"
+	AlgorithmArgon2id = "argon2id"
+
+	argon2SaltLen = 16
+	argon2KeyLen  = 32
+)
+
+var ErrUnknownHashFormat = errors.New("unknown password hash format")
+
+// Hasher hashes with the configured algorithm and verifies hashes of any supported
+// one. The algorithm and its parameters are stored in the hash itself: bcrypt's
+// "$2a$<cost>$..." and PHC "$argon2id$v=19$m=<KiB>,t=<time>,p=<threads>$<salt>$<key>".
+type Hasher struct {
+	cfg config.Password
+
+	dummyOnce sync.Once
+	dummyHash string
+}
+
+func New(cfg config.Password) *Hasher {
+	return &Hasher{cfg: cfg}
+}
+
+func (h *Hasher) Hash(password string) (string, error) {
+	if h.cfg.Algorithm == AlgorithmArgon2id {
+		salt := make([]byte, argon2SaltLen)
+		if _, err := rand.Read(salt); err != nil {
+			return "", err
+		}
+		p := argon2Params{memory: h.cfg.Argon2Memory, time: h.cfg.Argon2Time, threads: h.cfg.Argon2Threads}
+		return p.encode(salt, p.key(password, salt)), nil
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), h.cfg.BcryptCost)
+	if err != nil {
+		return "", err
+	}
+
+	return string(hash), nil
+}
+
+// Verify reports whether password matches hash, and whether the hash should be
+// replaced because the algorithm or its parameters are outdated.
+func (h *Hasher) Verify(password, hash string) (ok, needsRehash bool, err error) {
+	if strings.HasPrefix(hash, "$"+AlgorithmArgon2id+"$") {
+		p, salt, key, err := decodeArgon2(hash)
+		if err != nil {
+			return false, false, err
+		}
+		if subtle.ConstantTimeCompare(key, p.key(password, salt)) != 1 {
+			return false, false, nil
+		}
+		outdated := h.cfg.Algorithm != AlgorithmArgon2id ||
+			p.memory != h.cfg.Argon2Memory ||
+			p.time != h.cfg.Argon2Time ||
+			p.threads != h.cfg.Argon2Threads
+		return true, outdated, nil
+	}
+
+	cost, err := bcrypt.Cost([]byte(hash))
+	if err != nil {
+		return false, false, ErrUnknownHashFormat
+	}
+	if err = bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)); err != nil {
+		if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+			return false, false, nil
+		}
+		return false, false, err
+	}
+
+	return true, h.cfg.Algorithm != AlgorithmBcrypt || cost != h.cfg.BcryptCost, nil
+}
+
+// DummyVerify - the dummy hash is made with the current parameters to cost as much
+// as verifying an up-to-date user hash.
+func (h *Hasher) DummyVerify(password string) {
+	h.dummyOnce.Do(func() {
+		h.dummyHash, _ = h.Hash("dummy password for unknown users")
+	})
+	if h.dummyHash != "" {
+		_, _, _ = h.Verify(password, h.dummyHash)
+	}
+}
+
+type argon2Params struct {
+	memory  uint32
+	time    uint32
+	threads uint8
+}
+
+func (p argon2Params) key(password string, salt []byte) []byte {
+	return argon2.IDKey([]byte(password), salt, p.time, p.memory, p.threads, argon2KeyLen)
+}
+
+func (p argon2Params) encode(salt, key []byte) string {
+	return fmt.Sprintf(
+		"$%s$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		AlgorithmArgon2id,
+		argon2.Version,
+		p.memory, p.time, p.threads,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	)
+}
+
+func decodeArgon2(hash string) (argon2Params, []byte, []byte, error) {
+	var p argon2Params
+	// "", "argon2id", "v=19", "m=..,t=..,p=..", salt, key
+	parts := strings.Split(hash, "$")
+	if len(parts) != 6 {
+		return p, nil, nil, ErrUnknownHashFormat
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return p, nil, nil, ErrUnknownHashFormat
+	}
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &p.memory, &p.time, &p.threads); err != nil {
+		return p, nil, nil, ErrUnknownHashFormat
+	}
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return p, nil, nil, ErrUnknownHashFormat
+	}
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil {
+		return p, nil, nil, ErrUnknownHashFormat
+	}
+
+	return p, salt, key, nil
+}
@@ -0,0 +1,90 @@
+package password
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+
+	"user-manager-api/config"
+)
+
+func bcryptCfg(cost int) config.Password {
+	return config.Password{Algorithm: AlgorithmBcrypt, BcryptCost: cost, Argon2Memory: 64, Argon2Time: 1, Argon2Threads: 1}
+}
+
+func argon2Cfg(memory uint32) config.Password {
+	return config.Password{Algorithm: AlgorithmArgon2id, BcryptCost: bcrypt.MinCost, Argon2Memory: memory, Argon2Time: 1, Argon2Threads: 1}
+}
+
+func TestHasher_HashAndVerify(t *testing.T) {
+	type tc struct {
+		name       string
+		hashWith   config.Password
+		verifyWith config.Password
+		password   string
+		wantOK     bool
+		wantRehash bool
+	}
+	cases := []tc{
+		{"bcrypt ok", bcryptCfg(bcrypt.MinCost), bcryptCfg(bcrypt.MinCost), "secret-pass", true, false},
+		{"bcrypt wrong password", bcryptCfg(bcrypt.MinCost), bcryptCfg(bcrypt.MinCost), "other-pass", false, false},
+		{"bcrypt cost raised", bcryptCfg(bcrypt.MinCost), bcryptCfg(bcrypt.MinCost + 1), "secret-pass", true, true},
+		{"bcrypt to argon2id", bcryptCfg(bcrypt.MinCost), argon2Cfg(64), "secret-pass", true, true},
+		{"argon2id ok", argon2Cfg(64), argon2Cfg(64), "secret-pass", true, false},
+		{"argon2id wrong password", argon2Cfg(64), argon2Cfg(64), "other-pass", false, false},
+		{"argon2id memory raised", argon2Cfg(64), argon2Cfg(128), "secret-pass", true, true},
+		{"argon2id to bcrypt", argon2Cfg(64), bcryptCfg(bcrypt.MinCost), "secret-pass", true, true},
+	}
+
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			hash, err := New(tt.hashWith).Hash("secret-pass")
+			require.NoError(t, err)
+
+			ok, rehash, err := New(tt.verifyWith).Verify(tt.password, hash)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.wantRehash, rehash)
+		})
+	}
+}
+
+func TestHasher_Argon2idFormat(t *testing.T) {
+	hash, err := New(argon2Cfg(64)).Hash("secret-pass")
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(hash, "$argon2id$v=19$m=64,t=1,p=1$"), hash)
+
+	// salted: same password, different hashes
+	other, err := New(argon2Cfg(64)).Hash("secret-pass")
+	require.NoError(t, err)
+	assert.NotEqual(t, hash, other)
+}
+
+func TestHasher_VerifyMalformed(t *testing.T) {
+	h := New(bcryptCfg(bcrypt.MinCost))
+
+	for _, hash := range []string{
+		"",
+		"plain-text",
+		"$argon2id$v=19$m=64,t=1,p=1$salt",
+		"$argon2id$v=18$m=64,t=1,p=1$c2FsdA$a2V5",
+		"$argon2id$v=19$m=64,t=1,p=1$!!!$a2V5",
+	} {
+		ok, _, err := h.Verify("secret-pass", hash)
+		assert.ErrorIs(t, err, ErrUnknownHashFormat, hash)
+		assert.False(t, ok)
+	}
+}
+
+func TestHasher_DummyVerify(t *testing.T) {
+	h := New(bcryptCfg(bcrypt.MinCost))
+
+	h.DummyVerify("secret-pass")
+	cost, err := bcrypt.Cost([]byte(h.dummyHash))
+	require.NoError(t, err, "dummy hash must use the configured algorithm")
+	assert.Equal(t, bcrypt.MinCost, cost)
+}
@@ -0,0 +1,185 @@
+package resilience
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sony/gobreaker/v2"
+	"go.uber.org/zap"
+
+	"user-manager-api/pkg/bulkhead"
+)
+
+const (
+	// defaultMaxFailures, defaultOpenTimeout - of the zero Settings
+	defaultMaxFailures = 5
+	defaultOpenTimeout = 30 * time.Second
+)
+
+type (
+	Settings struct {
+		// Name - the dependency, the label of the gauges and the counters prefix
+		Name string
+		// BreakerMaxFailures - consecutive failures which trip the breaker
+		BreakerMaxFailures uint32
+		// BreakerOpenTimeout - how long the breaker stays open before letting a probe through
+		BreakerOpenTimeout time.Duration
+		// MaxConcurrent - calls in flight, 0 disables the bulkhead
+		MaxConcurrent int
+		// BulkheadWait - how long a call waits for a free slot before it is rejected
+		BulkheadWait time.Duration
+		// IsFailure - errors telling the dependency is unhealthy(nil - every error),
+		// e.g. a unique violation is an answer of a healthy DB
+		IsFailure func(err error) bool
+	}
+	// Guard - circuit breaker and bulkhead of a downstream dependency: calls
+	// fail fast with gobreaker.ErrOpenState while it is failing and with
+	// bulkhead.ErrFull while it is saturated, instead of piling up.
+	// A nil Guard lets every call through.
+	Guard struct {
+		s       Settings
+		breaker *gobreaker.TwoStepCircuitBreaker[struct{}]
+		// openedAt - unix nanos of the last trip, for RetryAfter
+		openedAt  atomic.Int64
+		bulkhead  *bulkhead.Bulkhead
+		mCounter  *prometheus.CounterVec
+		mInFlight *prometheus.GaugeVec
+	}
+)
+
+func New(
+	s Settings,
+	logger *zap.Logger,
+	mCounter *prometheus.CounterVec,
+	mBreaker *prometheus.GaugeVec,
+	mInFlight *prometheus.GaugeVec,
+) *Guard {
+	if s.BreakerMaxFailures == 0 {
+		s.BreakerMaxFailures = defaultMaxFailures
+	}
+	if s.BreakerOpenTimeout <= 0 {
+		s.BreakerOpenTimeout = defaultOpenTimeout
+	}
+
+	g := &Guard{s: s, mCounter: mCounter, mInFlight: mInFlight}
+	g.breaker = gobreaker.NewTwoStepCircuitBreaker[struct{}](gobreaker.Settings{
+		Name: s.Name,
+		// a single probe at a time while half-open
+		MaxRequests: 1,
+		Timeout:     s.BreakerOpenTimeout,
+		ReadyToTrip: func(counts gobreaker.Counts) bool {
+			return counts.ConsecutiveFailures >= s.BreakerMaxFailures
+		},
+		OnStateChange: func(name string, from, to gobreaker.State) {
+			if to == gobreaker.StateOpen {
+				g.openedAt.Store(time.Now().UnixNano())
+			}
+			logger.Warn("circuit breaker state changed",
+				zap.String("name", name),
+				zap.Stringer("from", from),
+				zap.Stringer("to", to),
+			)
+			if mBreaker != nil {
+				mBreaker.WithLabelValues(name).Set(float64(to))
+			}
+		},
+	})
+	if mBreaker != nil {
+		mBreaker.WithLabelValues(s.Name).Set(float64(gobreaker.StateClosed))
+	}
+	if s.MaxConcurrent > 0 {
+		g.bulkhead = bulkhead.New(s.MaxConcurrent, s.BulkheadWait)
+	}
+
+	return g
+}
+
+// Do runs fn if the guard admits it and records the outcome.
+func (g *Guard) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	done, err := g.Enter(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = fn(ctx)
+	done(err)
+
+	return err
+}
+
+// Enter - the two-step Do for calls outliving a closure(e.g. rows read after
+// the query): done records the outcome and frees the slot, exactly once.
+func (g *Guard) Enter(ctx context.Context) (done func(err error), err error) {
+	if g == nil {
+		return func(error) {}, nil
+	}
+
+	release := func() {}
+	if g.bulkhead != nil {
+		if release, err = g.bulkhead.Acquire(ctx); err != nil {
+			if errors.Is(err, bulkhead.ErrFull) {
+				g.inc("_bulkhead_rejected_total")
+			}
+			return nil, err
+		}
+		g.setInFlight()
+	}
+	breakerDone, err := g.breaker.Allow()
+	if err != nil {
+		release()
+		g.setInFlight()
+		g.inc("_breaker_rejected_total")
+		// the probe of a half-open breaker in flight: as open to the callers
+		if errors.Is(err, gobreaker.ErrTooManyRequests) {
+			err = gobreaker.ErrOpenState
+		}
+		return nil, err
+	}
+
+	return func(err error) {
+		release()
+		g.setInFlight()
+		breakerDone(!g.isFailure(ctx, err))
+	}, nil
+}
+
+// Healthy - false while the breaker is open: the calls fail fast
+func (g *Guard) Healthy() bool {
+	return g == nil || g.breaker.State() != gobreaker.StateOpen
+}
+
+// RetryAfter - until the breaker lets a probe through, 0 if it is not open
+func (g *Guard) RetryAfter() time.Duration {
+	if g == nil || g.breaker.State() != gobreaker.StateOpen {
+		return 0
+	}
+	openedAt := time.Unix(0, g.openedAt.Load())
+	return max(time.Until(openedAt.Add(g.s.BreakerOpenTimeout)), 0)
+}
+
+func (g *Guard) isFailure(ctx context.Context, err error) bool {
+	// the caller gave up(canceled, or its own deadline passed), nothing is
+	// known about the dependency
+	if err == nil || errors.Is(err, context.Canceled) || ctx.Err() != nil {
+		return false
+	}
+	if g.s.IsFailure == nil {
+		return true
+	}
+	return g.s.IsFailure(err)
+}
+
+func (g *Guard) setInFlight() {
+	if g.bulkhead != nil && g.mInFlight != nil {
+		g.mInFlight.WithLabelValues(g.s.Name).Set(float64(g.bulkhead.InFlight()))
+	}
+}
+
+func (g *Guard) inc(suffix string) {
+	if g.mCounter != nil {
+		g.mCounter.WithLabelValues(g.s.Name + suffix).Inc()
+	}
+}
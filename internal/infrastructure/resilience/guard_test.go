@@ -0,0 +1,108 @@
+package resilience
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sony/gobreaker/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"user-manager-api/pkg/bulkhead"
+)
+
+var (
+	errDown    = errors.New("connection refused")
+	errAnswer  = errors.New("duplicate key")
+	failureOf  = func(err error) bool { return errors.Is(err, errDown) }
+	returnsErr = func(err error) func(context.Context) error {
+		return func(context.Context) error { return err }
+	}
+)
+
+func TestGuard_Breaker(t *testing.T) {
+	ctx := context.Background()
+
+	type tc struct {
+		name    string
+		calls   []error
+		wantErr error
+	}
+	cases := []tc{
+		{"failures trip", []error{errDown, errDown}, gobreaker.ErrOpenState},
+		{"answers are not failures", []error{errAnswer, errAnswer, errAnswer}, nil},
+		{"caller gave up", []error{context.Canceled, context.Canceled}, nil},
+	}
+
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			g := New(Settings{
+				Name:               "test",
+				BreakerMaxFailures: 2,
+				BreakerOpenTimeout: time.Hour,
+				IsFailure:          failureOf,
+			}, zap.NewNop(), nil, nil, nil)
+			for _, e := range tt.calls {
+				_ = g.Do(ctx, returnsErr(e))
+			}
+
+			err := g.Do(ctx, returnsErr(nil))
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestGuard_CallerDeadline(t *testing.T) {
+	g := New(Settings{Name: "test", BreakerMaxFailures: 1, BreakerOpenTimeout: time.Hour}, zap.NewNop(), nil, nil, nil)
+
+	// the caller's own deadline passed: not a failure of the dependency
+	ctx, cancel := context.WithTimeout(context.Background(), time.Nanosecond)
+	defer cancel()
+	<-ctx.Done()
+	_ = g.Do(ctx, returnsErr(context.DeadlineExceeded))
+
+	assert.True(t, g.Healthy())
+	require.NoError(t, g.Do(context.Background(), returnsErr(nil)))
+}
+
+func TestGuard_Bulkhead(t *testing.T) {
+	ctx := context.Background()
+	g := New(Settings{Name: "test", MaxConcurrent: 1}, zap.NewNop(), nil, nil, nil)
+
+	done, err := g.Enter(ctx)
+	require.NoError(t, err)
+
+	// saturated, not failing: the breaker stays closed
+	for i := 0; i < 10; i++ {
+		require.ErrorIs(t, g.Do(ctx, returnsErr(nil)), bulkhead.ErrFull)
+	}
+	done(nil)
+
+	require.NoError(t, g.Do(ctx, returnsErr(nil)))
+	assert.Equal(t, gobreaker.StateClosed, g.breaker.State())
+}
+
+func TestGuard_Healthy(t *testing.T) {
+	g := New(Settings{Name: "test", BreakerMaxFailures: 1, BreakerOpenTimeout: time.Hour}, zap.NewNop(), nil, nil, nil)
+	require.True(t, g.Healthy())
+	require.Zero(t, g.RetryAfter())
+
+	_ = g.Do(context.Background(), returnsErr(errDown))
+	assert.False(t, g.Healthy())
+	assert.InDelta(t, time.Hour, g.RetryAfter(), float64(time.Second))
+}
+
+func TestGuard_Nil(t *testing.T) {
+	var g *Guard
+	require.ErrorIs(t, g.Do(context.Background(), returnsErr(errDown)), errDown)
+	assert.True(t, g.Healthy())
+	assert.Zero(t, g.RetryAfter())
+}
@@ -0,0 +1,155 @@
+package resilience
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sony/gobreaker/v2"
+	"go.uber.org/zap"
+
+	"user-manager-api/config"
+	"user-manager-api/pkg/bulkhead"
+	"user-manager-api/pkg/retry"
+)
+
+type (
+	// ObjectStorage - the calls of a storage driver(s3, azblob, gcs) wrapped by Storage
+	ObjectStorage interface {
+		PutObject(ctx context.Context, key, contentType string, body io.ReadSeeker, size int64) error
+		DeleteObjects(ctx context.Context, keys []string) error
+		ListObjects(ctx context.Context, prefix string) ([]string, error)
+		GetPublicURL(key string) string
+		GetBucket() string
+	}
+	// Storage wraps a driver so a slow or failing storage does not pile up
+	// goroutines behind file uploads: every call gets its own timeout, transient
+	// errors(see Retryable) are retried with full jitter(pkg/retry), and the
+	// guard(circuit breaker and bulkhead) fails fast.
+	Storage struct {
+		ObjectStorage
+		name     string
+		logger   *zap.Logger
+		timeout  time.Duration
+		policy   retry.Policy
+		guard    *Guard
+		mCounter *prometheus.CounterVec
+	}
+)
+
+// NewStorage - name is the driver, the prefix of the retries counter
+func NewStorage(
+	name string,
+	s ObjectStorage,
+	logger *zap.Logger,
+	cfg config.Resilience,
+	mCounter *prometheus.CounterVec,
+	guard *Guard,
+) *Storage {
+	return &Storage{
+		ObjectStorage: s,
+		name:          name,
+		logger:        logger,
+		timeout:       cfg.Timeout,
+		policy:        retry.Policy{MaxRetries: cfg.MaxRetries, BaseDelay: cfg.RetryBaseDelay, Retryable: Retryable},
+		guard:         guard,
+		mCounter:      mCounter,
+	}
+}
+
+// StorageSettings - of the guard of a storage driver
+func StorageSettings(name string, cfg config.Resilience) Settings {
+	return Settings{
+		Name:               name,
+		BreakerMaxFailures: cfg.BreakerMaxFailures,
+		BreakerOpenTimeout: cfg.BreakerOpenTimeout,
+		MaxConcurrent:      cfg.MaxConcurrent,
+		BulkheadWait:       cfg.BulkheadWait,
+	}
+}
+
+func (s *Storage) PutObject(ctx context.Context, key, contentType string, body io.ReadSeeker, size int64) error {
+	return s.Do(ctx, "put_object", func(ctx context.Context) error {
+		// rewind the body for each attempt
+		if _, err := body.Seek(0, io.SeekStart); err != nil {
+			return err
+		}
+		return s.ObjectStorage.PutObject(ctx, key, contentType, body, size)
+	})
+}
+
+func (s *Storage) DeleteObjects(ctx context.Context, keys []string) error {
+	return s.Do(ctx, "delete_objects", func(ctx context.Context) error {
+		return s.ObjectStorage.DeleteObjects(ctx, keys)
+	})
+}
+
+func (s *Storage) ListObjects(ctx context.Context, prefix string) ([]string, error) {
+	var keys []string
+	err := s.Do(ctx, "list_objects", func(ctx context.Context) error {
+		var err error
+		keys, err = s.ObjectStorage.ListObjects(ctx, prefix)
+		return err
+	})
+
+	return keys, err
+}
+
+// Do - a call of the driver beyond ObjectStorage(e.g. a HEAD of the S3 proxy)
+// wrapped as the ones of ObjectStorage
+func (s *Storage) Do(ctx context.Context, op string, fn func(ctx context.Context) error) error {
+	p := s.policy
+	p.OnRetry = func(attempt int, _ time.Duration, err error) {
+		s.logger.Warn("storage call failed",
+			zap.String("storage", s.name),
+			zap.String("op", op),
+			zap.Int("attempt", attempt),
+			zap.Error(err),
+		)
+		if s.mCounter != nil {
+			s.mCounter.WithLabelValues(s.name + "_retries_total").Inc()
+		}
+	}
+
+	return p.Do(ctx, func(ctx context.Context) error {
+		return s.guard.Do(ctx, func(ctx context.Context) error {
+			callCtx, cancel := context.WithTimeout(ctx, s.timeout)
+			defer cancel()
+			return fn(callCtx)
+		})
+	})
+}
+
+// Retryable - the transient errors of a storage: the call timed out, the
+// connection failed, or the storage answered 5xx or throttled(429, 503 SlowDown).
+// The answers of a healthy storage(not found, denied, invalid) and the fail fast
+// errors of the guard are final
+func Retryable(err error) bool {
+	switch {
+	case errors.Is(err, gobreaker.ErrOpenState), errors.Is(err, bulkhead.ErrFull),
+		errors.Is(err, context.Canceled),
+		errors.Is(err, fs.ErrNotExist), errors.Is(err, fs.ErrPermission), errors.Is(err, fs.ErrInvalid):
+		return false
+	case errors.Is(err, context.DeadlineExceeded):
+		return true
+	}
+
+	// the SDK errors: smithy's retryable marker, the HTTP status of the answer
+	var marked interface{ RetryableError() bool }
+	if errors.As(err, &marked) {
+		return marked.RetryableError()
+	}
+	var answered interface{ HTTPStatusCode() int }
+	if errors.As(err, &answered) {
+		code := answered.HTTPStatusCode()
+		return code == http.StatusTooManyRequests || code >= http.StatusInternalServerError
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) || errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, syscall.ECONNRESET)
+}
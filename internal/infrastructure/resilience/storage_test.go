@@ -0,0 +1,151 @@
+package resilience
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/sony/gobreaker/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"user-manager-api/config"
+	"user-manager-api/pkg/bulkhead"
+)
+
+// statusError - a storage answer with the HTTP status, as the SDK's ResponseError
+type statusError int
+
+func (e statusError) Error() string       { return fmt.Sprintf("storage answered %d", int(e)) }
+func (e statusError) HTTPStatusCode() int { return int(e) }
+
+// flakyStorage fails the first calls with errs, PutObject reads the whole body
+type flakyStorage struct {
+	errs  []error
+	calls int
+	read  []string
+}
+
+func (f *flakyStorage) call() error {
+	f.calls++
+	if f.calls <= len(f.errs) {
+		return f.errs[f.calls-1]
+	}
+	return nil
+}
+
+func (f *flakyStorage) PutObject(_ context.Context, _, _ string, body io.ReadSeeker, _ int64) error {
+	b, err := io.ReadAll(body)
+	if err != nil {
+		return err
+	}
+	f.read = append(f.read, string(b))
+	return f.call()
+}
+
+func (f *flakyStorage) DeleteObjects(context.Context, []string) error { return f.call() }
+
+func (f *flakyStorage) ListObjects(context.Context, string) ([]string, error) {
+	return []string{"u/a.png"}, f.call()
+}
+
+func (f *flakyStorage) GetPublicURL(key string) string { return "https://storage.test/" + key }
+func (f *flakyStorage) GetBucket() string              { return "uploads" }
+
+func TestRetryable(t *testing.T) {
+	cases := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"call timed out", fmt.Errorf("put: %w", context.DeadlineExceeded), true},
+		{"connection reset", &net.OpError{Op: "read", Err: syscall.ECONNRESET}, true},
+		{"server error", statusError(500), true},
+		{"slow down", statusError(503), true},
+		{"throttled", statusError(429), true},
+		{"canceled", context.Canceled, false},
+		{"not found", fmt.Errorf("s3 object b/k: %w", fs.ErrNotExist), false},
+		{"denied", statusError(403), false},
+		{"breaker open", gobreaker.ErrOpenState, false},
+		{"bulkhead full", bulkhead.ErrFull, false},
+		{"unknown", errors.New("invalid argument"), false},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Retryable(tt.err))
+		})
+	}
+}
+
+func TestStorage(t *testing.T) {
+	cfg := config.Resilience{Timeout: time.Second, MaxRetries: 2, RetryBaseDelay: time.Millisecond}
+	ctx := context.Background()
+
+	cases := []struct {
+		name         string
+		errs         []error
+		wantErr      error
+		wantAttempts int
+	}{
+		{"ok", nil, nil, 1},
+		{"transient is retried", []error{statusError(503), statusError(503)}, nil, 3},
+		{"retries exhausted", []error{statusError(503), statusError(503), statusError(503)}, statusError(503), 3},
+		{"final is not", []error{fs.ErrNotExist}, fs.ErrNotExist, 1},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			for op, call := range map[string]func(s *Storage) error{
+				"put": func(s *Storage) error {
+					return s.PutObject(ctx, "u/a.png", "image/png", strings.NewReader("png"), 3)
+				},
+				"delete": func(s *Storage) error { return s.DeleteObjects(ctx, []string{"u/a.png"}) },
+				"list": func(s *Storage) error {
+					keys, err := s.ListObjects(ctx, "u/")
+					if err == nil {
+						assert.Equal(t, []string{"u/a.png"}, keys)
+					}
+					return err
+				},
+			} {
+				t.Run(op, func(t *testing.T) {
+					f := &flakyStorage{errs: tt.errs}
+					err := call(NewStorage("test", f, zap.NewNop(), cfg, nil, nil))
+					if tt.wantErr != nil {
+						require.ErrorIs(t, err, tt.wantErr)
+					} else {
+						require.NoError(t, err)
+					}
+					assert.Equal(t, tt.wantAttempts, f.calls)
+					// the body is rewound for each attempt
+					for _, read := range f.read {
+						assert.Equal(t, "png", read)
+					}
+				})
+			}
+		})
+	}
+}
+
+func TestStorage_Guard(t *testing.T) {
+	ctx := context.Background()
+	f := &flakyStorage{errs: []error{statusError(500), statusError(500)}}
+	guard := New(Settings{Name: "test", BreakerMaxFailures: 2}, zap.NewNop(), nil, nil, nil)
+	s := NewStorage("test", f, zap.NewNop(), config.Resilience{Timeout: time.Second}, nil, guard)
+
+	require.Error(t, s.DeleteObjects(ctx, nil))
+	require.Error(t, s.DeleteObjects(ctx, nil))
+	// the breaker is open: fails fast, the driver is not called
+	require.ErrorIs(t, s.DeleteObjects(ctx, nil), gobreaker.ErrOpenState)
+	assert.Equal(t, 2, f.calls)
+	assert.Equal(t, "https://storage.test/u/a.png", s.GetPublicURL("u/a.png"))
+}
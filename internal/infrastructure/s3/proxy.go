@@ -0,0 +1,144 @@
+package s3
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"net/url"
+	"strings"
+)
+
+// Proxy - the storage of S3_DOWNLOAD_MODE=proxy: the download URLs point to
+// the API, which serves the objects(ports.ObjectReader) by the ranged GETs, so
+// the Range requests of the media players reach the bucket as they are
+type Proxy struct {
+	*ResilientClient
+	publicURL string
+}
+
+func NewProxy(rc *ResilientClient, publicURL string) *Proxy {
+	return &Proxy{ResilientClient: rc, publicURL: strings.TrimSuffix(publicURL, "/")}
+}
+
+// GetPublicURL points to the /files/raw/*key handler
+func (p *Proxy) GetPublicURL(key string) string {
+	segs := strings.Split(key, "/")
+	for i, seg := range segs {
+		segs[i] = url.PathEscape(seg)
+	}
+	return p.publicURL + "/api/v1/files/raw/" + strings.Join(segs, "/")
+}
+
+// GetObject - the object is not read yet: every read after a seek is a GET of
+// the object from the offset to the end of the range(ports.RangeLimiter, the
+// end of the object by default), the caller must close it
+func (p *Proxy) GetObject(ctx context.Context, key string) (io.ReadSeekCloser, fs.FileInfo, error) {
+	var fi fs.FileInfo
+	var notFound error
+	err := p.Do(ctx, "head_object", func(ctx context.Context) error {
+		var err error
+		fi, err = p.Client.HeadObject(ctx, key)
+		// a missing object is no failure of S3: neither retried nor counted by the breaker
+		if errors.Is(err, fs.ErrNotExist) {
+			notFound, err = err, nil
+		}
+		return err
+	})
+	if err == nil {
+		err = notFound
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &rangeReader{ctx: ctx, key: key, size: fi.Size(), end: fi.Size(), open: p.openRange}, fi, nil
+}
+
+// openRange - the body outlives the call, so no per-call timeout: the request
+// deadline bounds it
+func (p *Proxy) openRange(ctx context.Context, key string, offset, end int64) (io.ReadCloser, error) {
+	var body io.ReadCloser
+	err := p.guard.Do(ctx, func(ctx context.Context) error {
+		var err error
+		body, err = p.Client.GetObjectRange(ctx, key, offset, end)
+		return err
+	})
+
+	return body, err
+}
+
+// rangeReader - an io.ReadSeeker of a remote object, e.g. for http.ServeContent:
+// the seeks are free, a read opens the body from the offset to end if there is none
+type rangeReader struct {
+	ctx  context.Context
+	key  string
+	size int64
+	// end - of the reads(exclusive), see LimitRange
+	end  int64
+	open func(ctx context.Context, key string, offset, end int64) (io.ReadCloser, error)
+
+	off  int64
+	body io.ReadCloser
+}
+
+func (r *rangeReader) Read(b []byte) (int, error) {
+	if r.off >= r.end {
+		return 0, io.EOF
+	}
+	if r.body == nil {
+		body, err := r.open(r.ctx, r.key, r.off, r.end)
+		if err != nil {
+			return 0, err
+		}
+		r.body = body
+	}
+
+	n, err := r.body.Read(b)
+	r.off += int64(n)
+
+	return n, err
+}
+
+func (r *rangeReader) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += r.off
+	case io.SeekEnd:
+		offset += r.size
+	default:
+		return 0, errors.New("s3: invalid whence")
+	}
+	if offset < 0 {
+		return 0, errors.New("s3: negative position")
+	}
+
+	// a seek to the current offset keeps reading the body
+	if offset != r.off {
+		_ = r.Close()
+	}
+	r.off = offset
+
+	return offset, nil
+}
+
+// LimitRange - the reads stop at end: the range of a request is fetched, not
+// the rest of the object, a body open already is reopened
+func (r *rangeReader) LimitRange(end int64) {
+	end = min(end, r.size)
+	if end != r.end {
+		_ = r.Close()
+	}
+	r.end = end
+}
+
+func (r *rangeReader) Close() error {
+	if r.body == nil {
+		return nil
+	}
+	err := r.body.Close()
+	r.body = nil
+
+	return err
+}
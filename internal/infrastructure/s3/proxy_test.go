@@ -0,0 +1,70 @@
+package s3
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRangeReader_ServeContent(t *testing.T) {
+	object := []byte("0123456789abcdefghij")
+
+	tests := []struct {
+		name        string
+		rangeHeader string
+		wantStatus  int
+		wantBody    string
+		// limit - of the range(LimitRange), 0 - none
+		limit      int64
+		wantRanges [][2]int64
+	}{
+		{"whole object", "", http.StatusOK, string(object), 0, [][2]int64{{0, 20}}},
+		{"resume", "bytes=10-", http.StatusPartialContent, "abcdefghij", 0, [][2]int64{{10, 20}}},
+		{"middle", "bytes=5-9", http.StatusPartialContent, "56789", 10, [][2]int64{{5, 10}}},
+		{"suffix", "bytes=-3", http.StatusPartialContent, "hij", 0, [][2]int64{{17, 20}}},
+		{"limit past the end", "bytes=15-40", http.StatusPartialContent, "fghij", 41, [][2]int64{{15, 20}}},
+		{"not satisfiable", "bytes=30-", http.StatusRequestedRangeNotSatisfiable, "", 0, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var ranges [][2]int64
+			r := &rangeReader{
+				ctx:  context.Background(),
+				key:  "u/video.mp4",
+				size: int64(len(object)),
+				end:  int64(len(object)),
+				open: func(_ context.Context, key string, offset, end int64) (io.ReadCloser, error) {
+					assert.Equal(t, "u/video.mp4", key)
+					ranges = append(ranges, [2]int64{offset, end})
+					return io.NopCloser(bytes.NewReader(object[offset:end])), nil
+				},
+			}
+			defer r.Close()
+			if tt.limit > 0 {
+				r.LimitRange(tt.limit)
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.rangeHeader != "" {
+				req.Header.Set("Range", tt.rangeHeader)
+			}
+			rr := httptest.NewRecorder()
+			rr.Header().Set("Content-Type", "video/mp4")
+			http.ServeContent(rr, req, "video.mp4", time.Time{}, r)
+
+			require.Equal(t, tt.wantStatus, rr.Code)
+			if tt.wantStatus != http.StatusRequestedRangeNotSatisfiable {
+				assert.Equal(t, tt.wantBody, rr.Body.String())
+			}
+			assert.Equal(t, tt.wantRanges, ranges, "a GET per range, of the range only")
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package s3
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+
+	"user-manager-api/config"
+	"user-manager-api/internal/infrastructure/resilience"
+)
+
+// ResilientClient - Client wrapped by resilience.Storage as every storage
+// driver is, Client stays at hand for the calls of the proxy
+type ResilientClient struct {
+	*resilience.Storage
+	Client *Client
+	guard  *resilience.Guard
+}
+
+func NewResilient(
+	c *Client,
+	logger *zap.Logger,
+	cfg config.S3,
+	mCounter *prometheus.CounterVec,
+	guard *resilience.Guard,
+) *ResilientClient {
+	return &ResilientClient{
+		Storage: resilience.NewStorage("s3", c, logger, cfg.Resilience, mCounter, guard),
+		Client:  c,
+		guard:   guard,
+	}
+}
@@ -3,10 +3,13 @@ package s3
 import (
 	"context"
 	"fmt"
+	"io"
+	"io/fs"
 
 	"go.uber.org/zap"
 
 	"user-manager-api/config"
+	domain "user-manager-api/internal/domain/user_file"
 )
 
 type Client struct {
@@ -30,8 +33,68 @@ func New(
 	}, nil
 }
 
+func (c *Client) PutObject(ctx context.Context, key, contentType string, body io.ReadSeeker, size int64) error {
+	// simulation: s3.PutObject(ctx, &s3.PutObjectInput{Bucket, Key, Body, ContentType, ContentLength})
+	if _, err := io.Copy(io.Discard, body); err != nil {
+		return err
+	}
+
+	return ctx.Err()
+}
+
+func (c *Client) GetObject(ctx context.Context, key string) (io.ReadCloser, error) {
+	// simulation: s3.GetObject(ctx, &s3.GetObjectInput{Bucket, Key}), NoSuchKey -> fs.ErrNotExist
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return nil, fmt.Errorf("s3 object %s/%s: %w", c.bucket, key, fs.ErrNotExist)
+}
+
+// HeadObject - the size and the modification time of an object
+func (c *Client) HeadObject(ctx context.Context, key string) (fs.FileInfo, error) {
+	// simulation: s3.HeadObject(ctx, &s3.HeadObjectInput{Bucket, Key}), NotFound -> fs.ErrNotExist
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return nil, fmt.Errorf("s3 object %s/%s: %w", c.bucket, key, fs.ErrNotExist)
+}
+
+// GetObjectRange - the bytes [offset, end) of the object, the caller must close it
+func (c *Client) GetObjectRange(ctx context.Context, key string, offset, end int64) (io.ReadCloser, error) {
+	// simulation: s3.GetObject(ctx, &s3.GetObjectInput{Bucket, Key, Range: "bytes=<offset>-<end-1>"}),
+	// NoSuchKey -> fs.ErrNotExist
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return nil, fmt.Errorf("s3 object %s/%s: %w", c.bucket, key, fs.ErrNotExist)
+}
+
+func (c *Client) DeleteObjects(ctx context.Context, keys []string) error {
+	// simulation: s3.DeleteObjects(ctx, &s3.DeleteObjectsInput{Bucket, Delete{Objects}})
+	return ctx.Err()
+}
+
+func (c *Client) ListObjects(ctx context.Context, prefix string) ([]string, error) {
+	// simulation: s3.NewListObjectsV2Paginator(client, &s3.ListObjectsV2Input{Bucket, Prefix})
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return nil, domain.ErrListingNotSupported
+}
+
 func (c *Client) GetPublicURL(key string) string {
 	return fmt.Sprintf("https://%s.example.s3.%s.amazonaws.com/%s", c.bucket, c.region, key)
 }
 
 func (c *Client) GetBucket() string { return c.bucket }
+
+// WithBucket - the client of another bucket, e.g. the backups
+func (c *Client) WithBucket(bucket string) *Client {
+	cc := *c
+	cc.bucket = bucket
+	return &cc
+}
@@ -0,0 +1,76 @@
+package sms
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// TwilioAPIURL - the Programmable Messaging API
+const TwilioAPIURL = "https://api.twilio.com"
+
+// TwilioSender sends the messages via the Twilio Messages API
+type TwilioSender struct {
+	http       *http.Client
+	url        string
+	accountSID string
+	authToken  string
+	from       string
+}
+
+// NewTwilioSender - apiURL empty is TwilioAPIURL, from is the sender number(E.164)
+// or the messaging service SID("MG...")
+func NewTwilioSender(apiURL, accountSID, authToken, from string, timeout time.Duration) *TwilioSender {
+	if apiURL == "" {
+		apiURL = TwilioAPIURL
+	}
+	return &TwilioSender{
+		http:       &http.Client{Timeout: timeout},
+		url:        fmt.Sprintf("%s/2010-04-01/Accounts/%s/Messages.json", strings.TrimSuffix(apiURL, "/"), accountSID),
+		accountSID: accountSID,
+		authToken:  authToken,
+		from:       from,
+	}
+}
+
+// Send - any answer but 2xx is an error, with the Twilio error code when given
+func (s *TwilioSender) Send(ctx context.Context, phone, text string) error {
+	form := url.Values{"To": {phone}, "Body": {text}}
+	if strings.HasPrefix(s.from, "MG") {
+		form.Set("MessagingServiceSid", s.from)
+	} else {
+		form.Set("From", s.from)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth(s.accountSID, s.authToken)
+
+	resp, err := s.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 200 && resp.StatusCode <= 299 {
+		_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4<<10))
+		return nil
+	}
+
+	var apiErr struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	}
+	if json.NewDecoder(io.LimitReader(resp.Body, 4<<10)).Decode(&apiErr) != nil || apiErr.Code == 0 {
+		return fmt.Errorf("twilio answered %d", resp.StatusCode)
+	}
+	return fmt.Errorf("twilio answered %d: error %d: %s", resp.StatusCode, apiErr.Code, apiErr.Message)
+}
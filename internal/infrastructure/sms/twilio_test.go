@@ -0,0 +1,87 @@
+package sms
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTwilioSender_Send(t *testing.T) {
+	type tc struct {
+		name     string
+		from     string
+		status   int
+		answer   string
+		wantForm url.Values
+		wantErr  string
+	}
+
+	cases := []tc{
+		{
+			name:     "from a number",
+			from:     "+15005550006",
+			status:   http.StatusCreated,
+			answer:   `{"sid":"SM1"}`,
+			wantForm: url.Values{"To": {"+15551234567"}, "Body": {"code 123456"}, "From": {"+15005550006"}},
+		},
+		{
+			name:     "from a messaging service",
+			from:     "MG123",
+			status:   http.StatusCreated,
+			answer:   `{"sid":"SM1"}`,
+			wantForm: url.Values{"To": {"+15551234567"}, "Body": {"code 123456"}, "MessagingServiceSid": {"MG123"}},
+		},
+		{
+			name:    "rejected",
+			from:    "+15005550006",
+			status:  http.StatusBadRequest,
+			answer:  `{"code":21211,"message":"Invalid 'To' Phone Number","status":400}`,
+			wantErr: "twilio answered 400: error 21211: Invalid 'To' Phone Number",
+		},
+		{
+			name:    "not json",
+			from:    "+15005550006",
+			status:  http.StatusBadGateway,
+			answer:  `<html></html>`,
+			wantErr: "twilio answered 502",
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				gotPath string
+				gotForm url.Values
+				gotUser string
+				gotPass string
+			)
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotPath = r.URL.Path
+				gotUser, gotPass, _ = r.BasicAuth()
+				_ = r.ParseForm()
+				gotForm = r.PostForm
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.answer))
+			}))
+			defer srv.Close()
+
+			s := NewTwilioSender(srv.URL, "AC123", "token", tt.from, time.Second)
+			err := s.Send(context.Background(), "+15551234567", "code 123456")
+			assert.Equal(t, "/2010-04-01/Accounts/AC123/Messages.json", gotPath)
+			assert.Equal(t, "AC123", gotUser)
+			assert.Equal(t, "token", gotPass)
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantForm, gotForm)
+		})
+	}
+}
@@ -0,0 +1,150 @@
+package thumbnail
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/gif"
+	_ "image/jpeg"
+	"image/png"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"user-manager-api/config"
+)
+
+var (
+	ErrUnsupported = errors.New("unsupported mime type for thumbnail")
+	// ErrTooManyPixels - the image would take too much memory decoded(a
+	// decompression bomb is a few KB compressed)
+	ErrTooManyPixels = errors.New("image has too many pixels for thumbnail")
+)
+
+// Renderer renders PNG previews: images natively, PDFs(first page) through
+// poppler's "pdftoppm" when it is configured.
+type Renderer struct {
+	maxSize   int
+	maxPixels int64
+	pdftoppm  string
+	pdfActive bool
+}
+
+func New(cfg config.Thumbnails) *Renderer {
+	r := &Renderer{maxSize: cfg.MaxSize, maxPixels: int64(cfg.MaxSourcePixels), pdftoppm: cfg.PDFRenderer}
+	if r.pdftoppm != "" {
+		if _, err := exec.LookPath(r.pdftoppm); err == nil {
+			r.pdfActive = true
+		}
+	}
+
+	return r
+}
+
+func (r *Renderer) Supports(mimeType string) bool {
+	switch normalize(mimeType) {
+	case "image/png", "image/jpeg", "image/gif":
+		return true
+	case "application/pdf":
+		return r.pdfActive
+	}
+	return false
+}
+
+func (r *Renderer) Render(ctx context.Context, mimeType string, data []byte) ([]byte, error) {
+	switch normalize(mimeType) {
+	case "image/png", "image/jpeg", "image/gif":
+		// the header tells the size, checked before the pixels are allocated
+		hdr, _, err := image.DecodeConfig(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("decode image: %w", err)
+		}
+		if hdr.Width <= 0 || hdr.Height <= 0 || int64(hdr.Width)*int64(hdr.Height) > r.maxPixels {
+			return nil, fmt.Errorf("%w: %dx%d", ErrTooManyPixels, hdr.Width, hdr.Height)
+		}
+		src, _, err := image.Decode(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("decode image: %w", err)
+		}
+		return encode(resize(src, r.maxSize))
+	case "application/pdf":
+		if !r.pdfActive {
+			return nil, ErrUnsupported
+		}
+		return r.renderPDF(ctx, data)
+	}
+
+	return nil, ErrUnsupported
+}
+
+func (r *Renderer) renderPDF(ctx context.Context, data []byte) ([]byte, error) {
+	// first page only, read from stdin, write PNG to stdout
+	cmd := exec.CommandContext(ctx, r.pdftoppm,
+		"-png", "-singlefile", "-f", "1", "-l", "1",
+		"-scale-to", strconv.Itoa(r.maxSize),
+		"-",
+	)
+	cmd.Stdin = bytes.NewReader(data)
+	var out, stderr bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("pdftoppm: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	return out.Bytes(), nil
+}
+
+// resize - area averaging downscale which keeps the aspect ratio, images
+// smaller than maxSize are returned as is.
+func resize(src image.Image, maxSize int) image.Image {
+	b := src.Bounds()
+	w, h := b.Dx(), b.Dy()
+	if w <= maxSize && h <= maxSize || w == 0 || h == 0 {
+		return src
+	}
+
+	dw, dh := maxSize, h*maxSize/w
+	if h > w {
+		dw, dh = w*maxSize/h, maxSize
+	}
+	dw, dh = max(dw, 1), max(dh, 1)
+
+	dst := image.NewRGBA(image.Rect(0, 0, dw, dh))
+	for y := 0; y < dh; y++ {
+		sy0, sy1 := b.Min.Y+y*h/dh, b.Min.Y+(y+1)*h/dh
+		for x := 0; x < dw; x++ {
+			sx0, sx1 := b.Min.X+x*w/dw, b.Min.X+(x+1)*w/dw
+
+			var rs, gs, bs, as, n uint64
+			for sy := sy0; sy < max(sy1, sy0+1); sy++ {
+				for sx := sx0; sx < max(sx1, sx0+1); sx++ {
+					cr, cg, cb, ca := src.At(sx, sy).RGBA()
+					rs, gs, bs, as = rs+uint64(cr), gs+uint64(cg), bs+uint64(cb), as+uint64(ca)
+					n++
+				}
+			}
+			dst.Set(x, y, color.RGBA64{
+				R: uint16(rs / n), G: uint16(gs / n), B: uint16(bs / n), A: uint16(as / n),
+			})
+		}
+	}
+
+	return dst
+}
+
+func encode(img image.Image) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func normalize(mimeType string) string {
+	mt, _, _ := strings.Cut(mimeType, ";")
+	return strings.ToLower(strings.TrimSpace(mt))
+}
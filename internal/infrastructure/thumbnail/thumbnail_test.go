@@ -0,0 +1,87 @@
+package thumbnail
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"user-manager-api/config"
+)
+
+func pngOf(t *testing.T, w, h int) []byte {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.Set(x, y, color.RGBA{R: 200, G: 100, B: 50, A: 255})
+		}
+	}
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, img))
+	return buf.Bytes()
+}
+
+func TestRender_Table(t *testing.T) {
+	r := New(config.Thumbnails{MaxSize: 64, MaxSourcePixels: 1 << 20})
+
+	type tc struct {
+		name         string
+		mime         string
+		w, h         int
+		wantW, wantH int
+	}
+	cases := []tc{
+		{"landscape downscaled", "image/png", 640, 320, 64, 32},
+		{"portrait downscaled", "image/png", 100, 400, 16, 64},
+		{"small image kept", "image/png", 20, 10, 20, 10},
+		{"mime with params", "image/png; charset=binary", 128, 128, 64, 64},
+	}
+
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			require.True(t, r.Supports(tt.mime))
+
+			out, err := r.Render(context.Background(), tt.mime, pngOf(t, tt.w, tt.h))
+			require.NoError(t, err)
+
+			img, err := png.Decode(bytes.NewReader(out))
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantW, img.Bounds().Dx())
+			assert.Equal(t, tt.wantH, img.Bounds().Dy())
+
+			cr, cg, cb, _ := img.At(0, 0).RGBA()
+			assert.Equal(t, []uint32{200, 100, 50}, []uint32{cr >> 8, cg >> 8, cb >> 8})
+		})
+	}
+}
+
+func TestRender_TooManyPixels(t *testing.T) {
+	r := New(config.Thumbnails{MaxSize: 64, MaxSourcePixels: 100 * 100})
+
+	_, err := r.Render(context.Background(), "image/png", pngOf(t, 200, 100))
+	require.ErrorIs(t, err, ErrTooManyPixels)
+
+	_, err = r.Render(context.Background(), "image/png", pngOf(t, 100, 100))
+	require.NoError(t, err)
+}
+
+func TestRender_Unsupported(t *testing.T) {
+	r := New(config.Thumbnails{MaxSize: 64, MaxSourcePixels: 1 << 20})
+
+	assert.False(t, r.Supports("text/plain"))
+	_, err := r.Render(context.Background(), "text/plain", []byte("x"))
+	assert.Equal(t, ErrUnsupported, err)
+
+	// no renderer configured
+	assert.False(t, r.Supports("application/pdf"))
+
+	_, err = r.Render(context.Background(), "image/png", []byte("not a png"))
+	assert.Error(t, err)
+}
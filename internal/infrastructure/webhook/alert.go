@@ -0,0 +1,92 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"user-manager-api/pkg/alert"
+)
+
+// PagerDutyEventsURL - the Events API v2 endpoint
+const PagerDutyEventsURL = "https://events.pagerduty.com/v2/enqueue"
+
+// alertSource - the source of the PagerDuty events
+const alertSource = "usermanagerapi"
+
+type (
+	// SlackNotifier posts the alerts to a Slack incoming webhook
+	SlackNotifier struct {
+		client *Client
+		url    string
+	}
+	// PagerDutyNotifier triggers the PagerDuty incidents of a service, the Key
+	// of the alert is the dedup key of the incident
+	PagerDutyNotifier struct {
+		client     *Client
+		url        string
+		routingKey string
+	}
+	slackMessage struct {
+		Text string `json:"text"`
+	}
+	pagerDutyEvent struct {
+		RoutingKey  string           `json:"routing_key"`
+		EventAction string           `json:"event_action"`
+		DedupKey    string           `json:"dedup_key"`
+		Payload     pagerDutyPayload `json:"payload"`
+	}
+	pagerDutyPayload struct {
+		Summary       string            `json:"summary"`
+		Source        string            `json:"source"`
+		Severity      string            `json:"severity"`
+		CustomDetails map[string]string `json:"custom_details,omitempty"`
+	}
+)
+
+func NewSlackNotifier(client *Client, url string) *SlackNotifier {
+	return &SlackNotifier{client: client, url: url}
+}
+
+func (n *SlackNotifier) Notify(ctx context.Context, a alert.Alert) error {
+	var text strings.Builder
+	fmt.Fprintf(&text, "[%s] %s", a.Severity, a.Summary)
+	for _, name := range slices.Sorted(maps.Keys(a.Details)) {
+		fmt.Fprintf(&text, "\n%s: %s", name, a.Details[name])
+	}
+
+	body, err := json.Marshal(slackMessage{Text: text.String()})
+	if err != nil {
+		return err
+	}
+	return n.client.Send(ctx, n.url, body)
+}
+
+// NewPagerDutyNotifier - url empty is PagerDutyEventsURL
+func NewPagerDutyNotifier(client *Client, url, routingKey string) *PagerDutyNotifier {
+	if url == "" {
+		url = PagerDutyEventsURL
+	}
+	return &PagerDutyNotifier{client: client, url: url, routingKey: routingKey}
+}
+
+func (n *PagerDutyNotifier) Notify(ctx context.Context, a alert.Alert) error {
+	body, err := json.Marshal(pagerDutyEvent{
+		RoutingKey:  n.routingKey,
+		EventAction: "trigger",
+		DedupKey:    a.Key,
+		Payload: pagerDutyPayload{
+			Summary:       a.Summary,
+			Source:        alertSource,
+			Severity:      a.Severity,
+			CustomDetails: a.Details,
+		},
+	})
+	if err != nil {
+		return err
+	}
+	return n.client.Send(ctx, n.url, body)
+}
@@ -0,0 +1,61 @@
+package webhook
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"user-manager-api/pkg/alert"
+)
+
+func TestAlertNotifiers(t *testing.T) {
+	a := alert.Alert{
+		Key:      "mq_event_dropped",
+		Severity: alert.SeverityError,
+		Summary:  "mq event dropped",
+		Details:  map[string]string{"event_action": "POST", "suppressed": "3"},
+	}
+
+	type tc struct {
+		name     string
+		notifier func(c *Client, url string) alert.Notifier
+		want     string
+	}
+
+	cases := []tc{
+		{
+			name:     "slack",
+			notifier: func(c *Client, url string) alert.Notifier { return NewSlackNotifier(c, url) },
+			want:     `{"text":"[error] mq event dropped\nevent_action: POST\nsuppressed: 3"}`,
+		},
+		{
+			name:     "pagerduty",
+			notifier: func(c *Client, url string) alert.Notifier { return NewPagerDutyNotifier(c, url, "rk") },
+			want: `{"routing_key":"rk","event_action":"trigger","dedup_key":"mq_event_dropped","payload":{` +
+				`"summary":"mq event dropped","source":"usermanagerapi","severity":"error",` +
+				`"custom_details":{"event_action":"POST","suppressed":"3"}}}`,
+		},
+	}
+
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			var got []byte
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got, _ = io.ReadAll(r.Body)
+				w.WriteHeader(http.StatusAccepted)
+			}))
+			defer srv.Close()
+
+			err := tt.notifier(newClient(time.Second, nil), srv.URL).Notify(context.Background(), a)
+			require.NoError(t, err)
+			assert.JSONEq(t, tt.want, string(got))
+		})
+	}
+}
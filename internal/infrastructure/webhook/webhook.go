@@ -0,0 +1,97 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+)
+
+// maxRedirects - the endpoints are set by the users, a redirect is re-checked by the dialer
+const maxRedirects = 3
+
+var ErrPrivateAddress = errors.New("webhook address is not public")
+
+// Client posts the notifications to the endpoints of the users. The URLs come
+// from the users, so only public addresses are dialed: the check happens on
+// the resolved IP, a DNS name pointing inside the network is refused as well.
+type Client struct {
+	http *http.Client
+}
+
+func New(timeout time.Duration) *Client {
+	return newClient(timeout, publicOnly)
+}
+
+// NewInternal - the endpoints set by the operators(e.g. the security alerts),
+// the internal addresses are dialed as well
+func NewInternal(timeout time.Duration) *Client {
+	return newClient(timeout, nil)
+}
+
+func newClient(timeout time.Duration, control func(network, address string, c syscall.RawConn) error) *Client {
+	dialer := &net.Dialer{Timeout: timeout, Control: control}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	return &Client{http: &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+		CheckRedirect: func(_ *http.Request, via []*http.Request) error {
+			if len(via) >= maxRedirects {
+				return http.ErrUseLastResponse
+			}
+			return nil
+		},
+	}}
+}
+
+// Send posts the JSON body, any answer but 2xx is an error
+func (c *Client) Send(ctx context.Context, url string, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "usermanagerapi-webhook")
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook answered %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// publicOnly - the dialer control refusing loopback, private, link-local
+// (cloud metadata) and unspecified addresses
+func publicOnly(_, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil ||
+		ip.IsLoopback() ||
+		ip.IsPrivate() ||
+		ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() ||
+		ip.IsMulticast() ||
+		ip.IsUnspecified() {
+		return fmt.Errorf("%w: %s", ErrPrivateAddress, host)
+	}
+
+	return nil
+}
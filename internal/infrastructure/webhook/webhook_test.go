@@ -0,0 +1,95 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPublicOnly(t *testing.T) {
+	type tc struct {
+		address string
+		wantErr bool
+	}
+
+	cases := []tc{
+		{"93.184.216.34:443", false},
+		{"[2606:2800:220:1:248:1893:25c8:1946]:443", false},
+		{"127.0.0.1:443", true},
+		{"[::1]:443", true},
+		{"10.0.0.5:80", true},
+		{"172.16.3.4:80", true},
+		{"192.168.1.1:80", true},
+		{"169.254.169.254:80", true},
+		{"0.0.0.0:80", true},
+		{"[fd00::1]:443", true},
+	}
+
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.address, func(t *testing.T) {
+			err := publicOnly("tcp", tt.address, nil)
+			if tt.wantErr {
+				require.ErrorIs(t, err, ErrPrivateAddress)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestSend_RefusesPrivateAddress(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		t.Error("private address dialed")
+	}))
+	defer srv.Close()
+
+	err := New(time.Second).Send(context.Background(), srv.URL, []byte(`{}`))
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, ErrPrivateAddress))
+}
+
+func TestSend(t *testing.T) {
+	type tc struct {
+		name    string
+		status  int
+		wantErr bool
+	}
+
+	cases := []tc{
+		{"ok", http.StatusOK, false},
+		{"no content", http.StatusNoContent, false},
+		{"server error", http.StatusInternalServerError, true},
+		{"not found", http.StatusNotFound, true},
+	}
+
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			var got []byte
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, http.MethodPost, r.Method)
+				assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+				got, _ = io.ReadAll(r.Body)
+				w.WriteHeader(tt.status)
+			}))
+			defer srv.Close()
+
+			c := newClient(time.Second, nil)
+			err := c.Send(context.Background(), srv.URL, []byte(`{"event":"digest"}`))
+			if tt.wantErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+			assert.JSONEq(t, `{"event":"digest"}`, string(got))
+		})
+	}
+}
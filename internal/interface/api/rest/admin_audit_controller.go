@@ -0,0 +1,76 @@
+package rest
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"user-manager-api/internal/application/ports"
+	"user-manager-api/internal/interface/api/rest/dto/audit"
+	"user-manager-api/internal/interface/api/rest/middleware"
+	"user-manager-api/internal/interface/api/rest/validator"
+)
+
+// AdminAuditController - the audit log review, the entries past the retention
+// are in the archive bucket
+type AdminAuditController struct {
+	auditService ports.AuditService
+	logger       *zap.Logger
+}
+
+func NewAdminAuditController(
+	r *gin.Engine,
+	auditService ports.AuditService,
+	logger *zap.Logger,
+	tokenService ports.TokenService,
+) *AdminAuditController {
+	aac := &AdminAuditController{
+		auditService: auditService,
+		logger:       logger,
+	}
+
+	r.GET(
+		RouteAdminAudit,
+		middleware.AuthMiddleware(tokenService),
+		middleware.RequireAdmin(),
+		aac.GetAuditHandler,
+	)
+
+	return aac
+}
+
+// GetAuditHandler - page pagination only, the entries have no keyset cursor
+func (aac *AdminAuditController) GetAuditHandler(c *gin.Context) {
+	p, errs := validator.ParsePagination(c.Request.URL.Query(), validator.AuditSortFields)
+	if errs == nil && p.Cursor != nil {
+		errs = map[string]string{"cursor": "cursor is not supported, use page"}
+	}
+	if errs != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "invalid pagination params",
+			"details": errs,
+		})
+		return
+	}
+	f, errs := validator.ParseAuditFilter(c.Request.URL.Query())
+	if errs != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "invalid filter params",
+			"details": errs,
+		})
+		return
+	}
+
+	entries, err := aac.auditService.FindEntries(c.Request.Context(), f, p)
+	if err != nil {
+		c.JSON(
+			http.StatusInternalServerError,
+			gin.H{"error": "failed to get the audit log"},
+		)
+		aac.logger.Error("FindEntries() error", zap.Error(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, audit.ResponseData{Data: audit.ToResponseEntries(entries)})
+}
@@ -0,0 +1,88 @@
+package rest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"user-manager-api/internal/domain/audit"
+	"user-manager-api/internal/domain/pagination"
+	domain "user-manager-api/internal/domain/user"
+	jwtSvc "user-manager-api/internal/infrastructure/jwt"
+	"user-manager-api/internal/interface/api/rest/validator"
+)
+
+func TestAdminAuditController_GetAuditHandler(t *testing.T) {
+	actor, target := uuid.New(), uuid.New()
+	at := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	entries := []audit.Entry{
+		{ID: 7, ActorUUID: actor, Action: audit.ActionUserMerged, TargetUUID: &target, Details: map[string]any{"files": float64(2)}, CreatedAt: at},
+		{ID: 8, ActorUUID: actor, Action: audit.ActionReadOnlyChanged, CreatedAt: at},
+	}
+	wantBody := `{"data":[` +
+		`{"id":7,"actor_uuid":"` + actor.String() + `","action":"user.merged","target_uuid":"` + target.String() + `","details":{"files":2},"created_at":"2026-10-01T12:00:00Z"},` +
+		`{"id":8,"actor_uuid":"` + actor.String() + `","action":"read_only.changed","target_uuid":null,"details":{},"created_at":"2026-10-01T12:00:00Z"}]}`
+
+	from := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 10, 2, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		query      string
+		role       string
+		err        error
+		wantStatus int
+		wantFilter audit.Filter
+		wantParams pagination.Params
+	}{
+		{
+			name:       "200 filtered",
+			query:      "?actor_id=" + actor.String() + "&action=user.merged&target_id=" + target.String() + "&from=2026-10-01&to=2026-10-02&page=2&per_page=10&sort=-created_at",
+			role:       domain.RoleAdmin,
+			wantStatus: http.StatusOK,
+			wantFilter: audit.Filter{ActorUUID: &actor, Action: audit.ActionUserMerged, TargetUUID: &target, From: &from, To: &to},
+			wantParams: pagination.Params{Page: 2, PerPage: 10, Desc: true},
+		},
+		{"200 all", "", domain.RoleAdmin, nil, http.StatusOK, audit.Filter{}, pagination.Params{Page: 1}},
+		{name: "400 actor_id", query: "?actor_id=42", role: domain.RoleAdmin, wantStatus: http.StatusBadRequest},
+		{name: "400 action", query: "?action=DROP%20TABLE", role: domain.RoleAdmin, wantStatus: http.StatusBadRequest},
+		{name: "400 range", query: "?from=2026-10-02&to=2026-10-01", role: domain.RoleAdmin, wantStatus: http.StatusBadRequest},
+		{name: "400 sort", query: "?sort=action", role: domain.RoleAdmin, wantStatus: http.StatusBadRequest},
+		{name: "400 cursor", query: "?cursor=" + validator.EncodeCursor(pagination.Cursor{CreatedAt: at, UUID: actor}), role: domain.RoleAdmin, wantStatus: http.StatusBadRequest},
+		{name: "403 worker", role: domain.RoleWorker, wantStatus: http.StatusForbidden},
+		{name: "500", role: domain.RoleAdmin, err: errors.New("db down"), wantStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			r := gin.New()
+			j := jwtSvc.New("test-secret")
+
+			var (
+				gotFilter audit.Filter
+				gotParams pagination.Params
+			)
+			NewAdminAuditController(r, &fakeAuditService{FindEntriesFunc: func(_ context.Context, f audit.Filter, p pagination.Params) ([]audit.Entry, error) {
+				gotFilter, gotParams = f, p
+				return entries, tt.err
+			}}, zap.NewNop(), j)
+
+			rr := doReq(t, r, http.MethodGet, RouteAdminAudit+tt.query, nil, adminStatsHeaders(t, j, tt.role))
+			require.Equal(t, tt.wantStatus, rr.Code, rr.Body.String())
+			if tt.wantStatus == http.StatusOK {
+				assert.JSONEq(t, wantBody, rr.Body.String())
+				assert.Equal(t, tt.wantFilter, gotFilter)
+				assert.Equal(t, tt.wantParams, gotParams)
+			}
+		})
+	}
+}
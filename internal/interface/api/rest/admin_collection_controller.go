@@ -0,0 +1,73 @@
+package rest
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"user-manager-api/internal/application/ports"
+	"user-manager-api/internal/interface/api/rest/middleware"
+	"user-manager-api/pkg/openapi"
+)
+
+// AdminCollectionController - the example requests of the API generated from the
+// route table, so the clients' examples never drift from the code
+type AdminCollectionController struct {
+	routes func() gin.RoutesInfo
+	spec   *openapi.Spec
+	logger *zap.Logger
+}
+
+func NewAdminCollectionController(
+	r *gin.Engine,
+	spec *openapi.Spec,
+	logger *zap.Logger,
+	tokenService ports.TokenService,
+) *AdminCollectionController {
+	acc := &AdminCollectionController{
+		// read per request: the routes registered after the controller included
+		routes: r.Routes,
+		spec:   spec,
+		logger: logger,
+	}
+
+	r.GET(
+		RouteAdminCollection,
+		middleware.AuthMiddleware(tokenService),
+		middleware.RequireAdmin(),
+		acc.GetCollectionHandler,
+	)
+
+	return acc
+}
+
+// GetCollectionHandler - "?format=postman(default)|http", the host is the one
+// the request came to
+func (acc *AdminCollectionController) GetCollectionHandler(c *gin.Context) {
+	scheme := "http"
+	if c.Request.TLS != nil {
+		scheme = "https"
+	}
+
+	b, contentType, err := MarshalCollection(
+		c.DefaultQuery("format", CollectionPostman),
+		scheme+"://"+c.Request.Host,
+		CollectionRequests(acc.routes(), acc.spec),
+	)
+	if errors.Is(err, errCollectionFormat) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(
+			http.StatusInternalServerError,
+			gin.H{"error": "failed to generate the collection"},
+		)
+		acc.logger.Error("MarshalCollection() error", zap.Error(err))
+		return
+	}
+
+	c.Data(http.StatusOK, contentType, b)
+}
@@ -0,0 +1,148 @@
+package rest
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	domain "user-manager-api/internal/domain/user"
+	jwtSvc "user-manager-api/internal/infrastructure/jwt"
+	"user-manager-api/internal/interface/api/rest/api-specs/openapi/usermanagerapi"
+	"user-manager-api/internal/interface/api/rest/dto/collection"
+	"user-manager-api/pkg/openapi"
+)
+
+func setupAdminCollectionRouter(t *testing.T) (*gin.Engine, *jwtSvc.Service) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	spec, err := openapi.Load(usermanagerapi.Spec)
+	require.NoError(t, err)
+	r := gin.New()
+	j := jwtSvc.New("test-secret")
+	NewAdminCollectionController(r, spec, zap.NewNop(), j)
+	// registered after the controller, still in the collection
+	noop := func(c *gin.Context) {}
+	r.POST(RouteLogin, noop)
+	r.PUT(RouteUser, noop)
+	r.GET(RouteHealth, noop)
+	r.GET("/internal", noop)
+
+	return r, j
+}
+
+func TestAdminCollectionController_GetCollectionHandler(t *testing.T) {
+	type tc struct {
+		name       string
+		role       string
+		query      string
+		wantStatus int
+		check      func(t *testing.T, body []byte)
+	}
+	tests := []tc{
+		{
+			name:       "200 postman",
+			role:       domain.RoleAdmin,
+			wantStatus: http.StatusOK,
+			check: func(t *testing.T, body []byte) {
+				var p collection.Postman
+				require.NoError(t, json.Unmarshal(body, &p))
+				assert.Equal(t, collection.SchemaPostman, p.Info.Schema)
+				assert.Equal(t, []collection.Variable{{Key: "host", Value: "http://example.com"}, {Key: "token"}}, p.Variable)
+
+				folders := make(map[string][]collection.Item)
+				for _, f := range p.Item {
+					folders[f.Name] = f.Item
+				}
+				require.Len(t, folders["auth"], 1)
+				login := folders["auth"][0]
+				assert.Equal(t, "Login", login.Name)
+				assert.Equal(t, "noauth", login.Request.Auth.Type)
+				require.NotNil(t, login.Request.Body)
+				assert.Contains(t, login.Request.Body.Raw, `"password": "admin123"`)
+
+				require.Len(t, folders["users"], 1)
+				update := folders["users"][0].Request
+				assert.Equal(t, "bearer", update.Auth.Type)
+				assert.Equal(t, "{{host}}/api/v1/users/:user_id", update.URL.Raw)
+				assert.Equal(t, []string{"api", "v1", "users", ":user_id"}, update.URL.Path)
+				assert.Equal(t, []collection.Variable{{Key: "user_id"}}, update.URL.Variable)
+
+				assert.Len(t, folders["admin"], 1, "the collection endpoint itself")
+				assert.Len(t, folders["healthz"], 1)
+				assert.Len(t, p.Item, 4, "out of the API routes are skipped")
+			},
+		},
+		{
+			name:       "200 http",
+			role:       domain.RoleAdmin,
+			query:      "?format=http",
+			wantStatus: http.StatusOK,
+			check: func(t *testing.T, body []byte) {
+				s := string(body)
+				assert.Contains(t, s, "@host = http://example.com\n")
+				assert.Contains(t, s, "@user_id = *****\n")
+				assert.Contains(t, s, "# Update user by UUID\nPUT {{host}}/api/v1/users/{{user_id}}\nAuthorization: Bearer {{token}}\nContent-Type: application/json\n")
+				assert.Contains(t, s, "# Login\nPOST {{host}}/api/v1/auth/login\nContent-Type: application/json\n")
+			},
+		},
+		{
+			name:       "400 format",
+			role:       domain.RoleAdmin,
+			query:      "?format=curl",
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "403 worker",
+			role:       domain.RoleWorker,
+			wantStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, j := setupAdminCollectionRouter(t)
+			w := doReq(t, r, http.MethodGet, "http://example.com"+RouteAdminCollection+tt.query, nil, adminStatsHeaders(t, j, tt.role))
+			require.Equal(t, tt.wantStatus, w.Code, w.Body.String())
+			if tt.check != nil {
+				tt.check(t, w.Body.Bytes())
+			}
+		})
+	}
+}
+
+// the examples are the documented requests and match their schemas
+func TestRequestExamples_Contract(t *testing.T) {
+	spec, err := openapi.Load(usermanagerapi.Spec)
+	require.NoError(t, err)
+
+	for key, example := range requestExamples {
+		method, route, _ := strings.Cut(key, " ")
+		op := spec.Operation(method, route)
+		require.NotNil(t, op, key)
+
+		body, err := json.Marshal(example)
+		require.NoError(t, err)
+		params := make(map[string]string)
+		for _, seg := range strings.Split(route, "/") {
+			if name, ok := strings.CutPrefix(seg, ":"); ok {
+				params[name] = uuid.NewString()
+			}
+		}
+		req := httptest.NewRequest(method, route, bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		// the signature headers of the hooks
+		req.Header.Set("X-Signature", "sha256=00")
+		req.Header.Set("X-Signature-Timestamp", "1700000000")
+		assert.Empty(t, op.ValidateRequest(req, params, true), key)
+	}
+}
@@ -0,0 +1,130 @@
+package rest
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"user-manager-api/internal/application/ports"
+	"user-manager-api/internal/application/services"
+	"user-manager-api/internal/interface/api/rest/middleware"
+	"user-manager-api/internal/interface/api/rest/validator"
+)
+
+type AdminController struct {
+	logger               *zap.Logger
+	impersonationService ports.ImpersonationService
+	credentialService    ports.CredentialService
+}
+
+func NewAdminController(
+	r *gin.Engine,
+	logger *zap.Logger,
+	impersonationService ports.ImpersonationService,
+	credentialService ports.CredentialService,
+	tokenService ports.TokenService,
+) *AdminController {
+	ac := &AdminController{
+		logger:               logger,
+		impersonationService: impersonationService,
+		credentialService:    credentialService,
+	}
+
+	r.POST(
+		RouteAdminImpersonate,
+		middleware.AuthMiddleware(tokenService),
+		middleware.RequireAdmin(),
+		ac.ImpersonateHandler,
+	)
+	r.POST(
+		RouteAdminForceReset,
+		middleware.AuthMiddleware(tokenService),
+		middleware.RequireAdmin(),
+		ac.ForceResetHandler,
+	)
+
+	return ac
+}
+
+func (ac *AdminController) ImpersonateHandler(c *gin.Context) {
+	ok, target := validator.IsUUID(c.Param("user_id"))
+	if !ok {
+		c.JSON(
+			http.StatusBadRequest,
+			gin.H{"error": "user_id must be a valid UUID"},
+		)
+		return
+	}
+	ok, actor := validator.IsUUID(c.GetString(middleware.CtxUserID))
+	if !ok {
+		c.JSON(
+			http.StatusUnauthorized,
+			gin.H{"error": "invalid token"},
+		)
+		return
+	}
+
+	token, expiresAt, err := ac.impersonationService.Impersonate(c.Request.Context(), actor, target)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrImpersonateSelf):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, services.ErrImpersonateAdmin):
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		case errors.Is(err, services.ErrUserNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error(), "code": codeUserNotFound})
+		default:
+			c.JSON(
+				http.StatusInternalServerError,
+				gin.H{"error": "failed to impersonate a user"},
+			)
+			ac.logger.Error("Impersonate() error", zap.Error(err), zap.Stringer("actor_uuid", actor))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"access_token": token,
+		"token_type":   "Bearer",
+		"expires_at":   expiresAt.UTC(),
+		"act_as":       actor,
+	})
+}
+
+// ForceResetHandler - incident response after a credential leak: the user tokens
+// are revoked and login is refused until the password is changed.
+func (ac *AdminController) ForceResetHandler(c *gin.Context) {
+	ok, target := validator.IsUUID(c.Param("user_id"))
+	if !ok {
+		c.JSON(
+			http.StatusBadRequest,
+			gin.H{"error": "user_id must be a valid UUID"},
+		)
+		return
+	}
+	ok, actor := validator.IsUUID(c.GetString(middleware.CtxUserID))
+	if !ok {
+		c.JSON(
+			http.StatusUnauthorized,
+			gin.H{"error": "invalid token"},
+		)
+		return
+	}
+
+	if err := ac.credentialService.ForcePasswordReset(c.Request.Context(), actor, target); err != nil {
+		if errors.Is(err, services.ErrUserNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error(), "code": codeUserNotFound})
+			return
+		}
+		c.JSON(
+			http.StatusInternalServerError,
+			gin.H{"error": "failed to force a password reset"},
+		)
+		ac.logger.Error("ForcePasswordReset() error", zap.Error(err), zap.Stringer("actor_uuid", actor))
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,583 @@
+package rest
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"user-manager-api/internal/application/services"
+	"user-manager-api/internal/domain/audit"
+	"user-manager-api/internal/domain/pagination"
+	"user-manager-api/internal/domain/token"
+	domain "user-manager-api/internal/domain/user"
+	jwtSvc "user-manager-api/internal/infrastructure/jwt"
+	"user-manager-api/internal/interface/api/rest/middleware"
+)
+
+type fakeImpersonationService struct {
+	ImpersonateFunc func(ctx context.Context, actor, target domain.UUID) (string, time.Time, error)
+}
+
+func (f *fakeImpersonationService) Impersonate(ctx context.Context, actor, target domain.UUID) (string, time.Time, error) {
+	if f.ImpersonateFunc == nil {
+		return "", time.Time{}, errors.New("not used")
+	}
+	return f.ImpersonateFunc(ctx, actor, target)
+}
+
+// fakeTokenService - any "Bearer <token>" is valid, no signing involved
+type fakeTokenService struct {
+	claims  map[string]*token.Claims
+	revoked bool
+}
+
+func (f *fakeTokenService) GenerateToken(string, string, time.Duration) (string, error) {
+	return "", errors.New("not used")
+}
+
+func (f *fakeTokenService) GenerateImpersonationToken(string, string, string, time.Duration) (string, error) {
+	return "", errors.New("not used")
+}
+
+func (f *fakeTokenService) GenerateDeviceToken(string, string, string, time.Duration) (string, error) {
+	return "", errors.New("not used")
+}
+
+func (f *fakeTokenService) ValidateToken(tokenStr string) (*token.Claims, error) {
+	if tokenStr == "expired" {
+		return nil, token.ErrExpired
+	}
+	c, ok := f.claims[tokenStr]
+	if !ok {
+		return nil, errors.New("invalid token")
+	}
+	return c, nil
+}
+
+func (f *fakeTokenService) IsRevoked(context.Context, *token.Claims) (bool, error) {
+	return f.revoked, nil
+}
+
+func (f *fakeTokenService) CurrentRole(_ context.Context, c *token.Claims) (string, error) {
+	return c.Role, nil
+}
+
+func (f *fakeTokenService) RefreshToken(*token.Claims, string) (string, error) {
+	return "", nil
+}
+
+type fakeAuditService struct {
+	entries         []audit.Entry
+	FindEntriesFunc func(ctx context.Context, f audit.Filter, p pagination.Params) ([]audit.Entry, error)
+}
+
+func (f *fakeAuditService) Record(_ context.Context, e audit.Entry) error {
+	f.entries = append(f.entries, e)
+	return nil
+}
+
+func (f *fakeAuditService) Recorded(e audit.Entry) {
+	f.entries = append(f.entries, e)
+}
+
+func (f *fakeAuditService) FindEntries(ctx context.Context, filter audit.Filter, p pagination.Params) ([]audit.Entry, error) {
+	if f.FindEntriesFunc == nil {
+		return nil, errors.New("not used")
+	}
+	return f.FindEntriesFunc(ctx, filter, p)
+}
+
+type fakeCredentialService struct {
+	ForcePasswordResetFunc func(ctx context.Context, actor, target domain.UUID) error
+	ChangePasswordFunc     func(ctx context.Context, email, password, newPassword string) (string, error)
+}
+
+func (f *fakeCredentialService) ForcePasswordReset(ctx context.Context, actor, target domain.UUID) error {
+	if f.ForcePasswordResetFunc == nil {
+		return errors.New("not used")
+	}
+	return f.ForcePasswordResetFunc(ctx, actor, target)
+}
+
+func (f *fakeCredentialService) ChangePassword(ctx context.Context, email, password, newPassword string) (string, error) {
+	if f.ChangePasswordFunc == nil {
+		return "", errors.New("not used")
+	}
+	return f.ChangePasswordFunc(ctx, email, password, newPassword)
+}
+
+func (f *fakeCredentialService) IsTokenRevoked(context.Context, *token.Claims) (bool, error) {
+	return false, nil
+}
+
+func setupAdminRouter(t *testing.T, is *fakeImpersonationService, as *fakeAuditService) (*gin.Engine, *jwtSvc.Service) {
+	t.Helper()
+	return setupAdminRouterWithCredentials(t, is, as, &fakeCredentialService{})
+}
+
+func setupAdminRouterWithCredentials(
+	t *testing.T,
+	is *fakeImpersonationService,
+	as *fakeAuditService,
+	cs *fakeCredentialService,
+) (*gin.Engine, *jwtSvc.Service) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	j := jwtSvc.New("test-secret")
+	r.Use(middleware.ImpersonationAudit(as, zap.NewNop()))
+	NewAdminController(r, zap.NewNop(), is, cs, j)
+	// any authenticated route, to check impersonated requests are audited
+	r.GET("/me", middleware.AuthMiddleware(j), func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	return r, j
+}
+
+func TestAdminController_ImpersonateHandler(t *testing.T) {
+	adminID := uuid.New()
+	targetID := uuid.New()
+	path := RouteApiV1 + "/admin/impersonate/" + targetID.String()
+	expiresAt := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+
+	type tc struct {
+		name        string
+		path        string
+		token       func(j *jwtSvc.Service) string
+		impersonate func(ctx context.Context, actor, target domain.UUID) (string, time.Time, error)
+		wantStatus  int
+		wantErr     string
+	}
+	adminToken := func(j *jwtSvc.Service) string {
+		tok, err := j.GenerateToken(adminID.String(), domain.RoleAdmin, time.Minute)
+		require.NoError(t, err)
+		return tok
+	}
+	ok := func(ctx context.Context, actor, target domain.UUID) (string, time.Time, error) {
+		if actor != adminID || target != targetID {
+			return "", time.Time{}, errors.New("unexpected ids")
+		}
+		return "imp-token", expiresAt, nil
+	}
+
+	cases := []tc{
+		{
+			name:        "200 admin",
+			path:        path,
+			token:       adminToken,
+			impersonate: ok,
+			wantStatus:  http.StatusOK,
+		},
+		{
+			name:       "401 no token",
+			path:       path,
+			wantStatus: http.StatusUnauthorized,
+			wantErr:    "missing Authorization header",
+		},
+		{
+			name: "403 worker",
+			path: path,
+			token: func(j *jwtSvc.Service) string {
+				tok, err := j.GenerateToken(uuid.NewString(), domain.RoleWorker, time.Minute)
+				require.NoError(t, err)
+				return tok
+			},
+			wantStatus: http.StatusForbidden,
+			wantErr:    "admin role required",
+		},
+		{
+			name: "403 impersonation token",
+			path: path,
+			token: func(j *jwtSvc.Service) string {
+				tok, err := j.GenerateImpersonationToken(uuid.NewString(), domain.RoleAdmin, adminID.String(), time.Minute)
+				require.NoError(t, err)
+				return tok
+			},
+			wantStatus: http.StatusForbidden,
+			wantErr:    "not allowed with an impersonation token",
+		},
+		{
+			name:       "400 invalid uuid",
+			path:       RouteApiV1 + "/admin/impersonate/bad",
+			token:      adminToken,
+			wantStatus: http.StatusBadRequest,
+			wantErr:    "user_id must be a valid UUID",
+		},
+		{
+			name:  "400 self",
+			path:  path,
+			token: adminToken,
+			impersonate: func(ctx context.Context, actor, target domain.UUID) (string, time.Time, error) {
+				return "", time.Time{}, services.ErrImpersonateSelf
+			},
+			wantStatus: http.StatusBadRequest,
+			wantErr:    services.ErrImpersonateSelf.Error(),
+		},
+		{
+			name:  "403 admin target",
+			path:  path,
+			token: adminToken,
+			impersonate: func(ctx context.Context, actor, target domain.UUID) (string, time.Time, error) {
+				return "", time.Time{}, services.ErrImpersonateAdmin
+			},
+			wantStatus: http.StatusForbidden,
+			wantErr:    services.ErrImpersonateAdmin.Error(),
+		},
+		{
+			name:  "404 target not found",
+			path:  path,
+			token: adminToken,
+			impersonate: func(ctx context.Context, actor, target domain.UUID) (string, time.Time, error) {
+				return "", time.Time{}, services.ErrUserNotFound
+			},
+			wantStatus: http.StatusNotFound,
+			wantErr:    services.ErrUserNotFound.Error(),
+		},
+		{
+			name:  "500 audit failed",
+			path:  path,
+			token: adminToken,
+			impersonate: func(ctx context.Context, actor, target domain.UUID) (string, time.Time, error) {
+				return "", time.Time{}, errors.New("db down")
+			},
+			wantStatus: http.StatusInternalServerError,
+			wantErr:    "failed to impersonate a user",
+		},
+	}
+
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			r, j := setupAdminRouter(t, &fakeImpersonationService{ImpersonateFunc: tt.impersonate}, &fakeAuditService{})
+
+			headers := map[string]string{}
+			if tt.token != nil {
+				headers["Authorization"] = "Bearer " + tt.token(j)
+			}
+			rr := doReq(t, r, http.MethodPost, tt.path, nil, headers)
+			require.Equal(t, tt.wantStatus, rr.Code, rr.Body.String())
+
+			var resp map[string]any
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+			if tt.wantErr != "" {
+				assert.Equal(t, tt.wantErr, resp["error"])
+				return
+			}
+			assert.Equal(t, "imp-token", resp["access_token"])
+			assert.Equal(t, "Bearer", resp["token_type"])
+			assert.Equal(t, adminID.String(), resp["act_as"])
+			assert.Equal(t, expiresAt.Format(time.RFC3339), resp["expires_at"])
+		})
+	}
+}
+
+func TestImpersonationAudit(t *testing.T) {
+	adminID := uuid.New()
+	targetID := uuid.New()
+	as := &fakeAuditService{}
+	r, j := setupAdminRouter(t, &fakeImpersonationService{}, as)
+
+	regular, err := j.GenerateToken(targetID.String(), domain.RoleWorker, time.Minute)
+	require.NoError(t, err)
+	rr := doReq(t, r, http.MethodGet, "/me", nil, map[string]string{"Authorization": "Bearer " + regular})
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.Empty(t, as.entries)
+
+	imp, err := j.GenerateImpersonationToken(targetID.String(), domain.RoleWorker, adminID.String(), time.Minute)
+	require.NoError(t, err)
+	rr = doReq(t, r, http.MethodGet, "/me", nil, map[string]string{"Authorization": "Bearer " + imp})
+	require.Equal(t, http.StatusOK, rr.Code)
+
+	require.Equal(t, 1, len(as.entries))
+	e := as.entries[0]
+	assert.Equal(t, adminID, e.ActorUUID)
+	assert.Equal(t, audit.ActionImpersonatedRequest, e.Action)
+	require.NotNil(t, e.TargetUUID)
+	assert.Equal(t, targetID, *e.TargetUUID)
+	assert.Equal(t, http.StatusOK, e.Details["status"])
+	assert.Equal(t, "/me", e.Details["path"])
+}
+
+func TestAdminController_ForceResetHandler(t *testing.T) {
+	adminID := uuid.New()
+	targetID := uuid.New()
+	path := RouteApiV1 + "/admin/users/" + targetID.String() + "/force-reset"
+
+	type tc struct {
+		name       string
+		path       string
+		role       string
+		forceReset func(ctx context.Context, actor, target domain.UUID) error
+		wantStatus int
+		wantErr    string
+	}
+	cases := []tc{
+		{
+			name: "204 admin",
+			path: path,
+			role: domain.RoleAdmin,
+			forceReset: func(ctx context.Context, actor, target domain.UUID) error {
+				if actor != adminID || target != targetID {
+					return errors.New("unexpected ids")
+				}
+				return nil
+			},
+			wantStatus: http.StatusNoContent,
+		},
+		{
+			name:       "403 worker",
+			path:       path,
+			role:       domain.RoleWorker,
+			wantStatus: http.StatusForbidden,
+			wantErr:    "admin role required",
+		},
+		{
+			name:       "400 invalid uuid",
+			path:       RouteApiV1 + "/admin/users/bad/force-reset",
+			role:       domain.RoleAdmin,
+			wantStatus: http.StatusBadRequest,
+			wantErr:    "user_id must be a valid UUID",
+		},
+		{
+			name: "404 target not found",
+			path: path,
+			role: domain.RoleAdmin,
+			forceReset: func(ctx context.Context, actor, target domain.UUID) error {
+				return services.ErrUserNotFound
+			},
+			wantStatus: http.StatusNotFound,
+			wantErr:    services.ErrUserNotFound.Error(),
+		},
+		{
+			name: "500 service error",
+			path: path,
+			role: domain.RoleAdmin,
+			forceReset: func(ctx context.Context, actor, target domain.UUID) error {
+				return errors.New("db down")
+			},
+			wantStatus: http.StatusInternalServerError,
+			wantErr:    "failed to force a password reset",
+		},
+	}
+
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			r, j := setupAdminRouterWithCredentials(
+				t,
+				&fakeImpersonationService{},
+				&fakeAuditService{},
+				&fakeCredentialService{ForcePasswordResetFunc: tt.forceReset},
+			)
+			tok, err := j.GenerateToken(adminID.String(), tt.role, time.Minute)
+			require.NoError(t, err)
+
+			rr := doReq(t, r, http.MethodPost, tt.path, nil, map[string]string{"Authorization": "Bearer " + tok})
+			require.Equal(t, tt.wantStatus, rr.Code, rr.Body.String())
+			if tt.wantErr == "" {
+				return
+			}
+
+			var resp map[string]any
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+			assert.Equal(t, tt.wantErr, resp["error"])
+		})
+	}
+}
+
+func TestAuthMiddleware_RevokedToken(t *testing.T) {
+	r, j := setupAdminRouter(t, &fakeImpersonationService{}, &fakeAuditService{})
+	userID := uuid.New()
+	tok, err := j.GenerateToken(userID.String(), domain.RoleWorker, time.Minute)
+	require.NoError(t, err)
+
+	revoked := false
+	j.SetRevocationCheck(func(_ context.Context, c *token.Claims) (bool, error) {
+		assert.Equal(t, userID.String(), c.UserID)
+		return revoked, nil
+	})
+	rr := doReq(t, r, http.MethodGet, "/me", nil, map[string]string{"Authorization": "Bearer " + tok})
+	require.Equal(t, http.StatusOK, rr.Code)
+
+	revoked = true
+	rr = doReq(t, r, http.MethodGet, "/me", nil, map[string]string{"Authorization": "Bearer " + tok})
+	require.Equal(t, http.StatusUnauthorized, rr.Code)
+	assert.JSONEq(t, `{"error":"token revoked","code":"token_revoked"}`, rr.Body.String())
+
+	j.SetRevocationCheck(func(context.Context, *token.Claims) (bool, error) {
+		return false, errors.New("db down")
+	})
+	rr = doReq(t, r, http.MethodGet, "/me", nil, map[string]string{"Authorization": "Bearer " + tok})
+	require.Equal(t, http.StatusInternalServerError, rr.Code)
+}
+
+func TestAuthMiddleware_CurrentRole(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	j := jwtSvc.New("test-secret")
+	r := gin.New()
+	r.GET("/admin-only", middleware.AuthMiddleware(j), middleware.RequireAdmin(), func(c *gin.Context) {
+		c.Status(http.StatusNoContent)
+	})
+
+	userID := uuid.New()
+	tok, err := j.GenerateToken(userID.String(), domain.RoleWorker, time.Minute)
+	require.NoError(t, err)
+	bearer := map[string]string{"Authorization": "Bearer " + tok}
+
+	rr := doReq(t, r, http.MethodGet, "/admin-only", nil, bearer)
+	require.Equal(t, http.StatusForbidden, rr.Code, "the role claim without a lookup")
+
+	j.SetRoleLookup(func(_ context.Context, c *token.Claims) (string, error) {
+		assert.Equal(t, userID.String(), c.UserID)
+		return domain.RoleAdmin, nil
+	})
+	rr = doReq(t, r, http.MethodGet, "/admin-only", nil, bearer)
+	require.Equal(t, http.StatusNoContent, rr.Code, "promoted without a new token")
+
+	j.SetRoleLookup(func(context.Context, *token.Claims) (string, error) {
+		return "", errors.New("db down")
+	})
+	rr = doReq(t, r, http.MethodGet, "/admin-only", nil, bearer)
+	require.Equal(t, http.StatusInternalServerError, rr.Code)
+}
+
+func TestAuthMiddleware_RefreshedToken(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	j := jwtSvc.New("test-secret")
+	j.SetRefreshWindow(10 * time.Minute)
+	r := gin.New()
+	r.GET("/me", middleware.AuthMiddleware(j), func(c *gin.Context) { c.Status(http.StatusNoContent) })
+
+	do := func(tok string, err error) string {
+		t.Helper()
+		require.NoError(t, err)
+		rr := doReq(t, r, http.MethodGet, "/me", nil, map[string]string{"Authorization": "Bearer " + tok})
+		require.Equal(t, http.StatusNoContent, rr.Code)
+		return rr.Header().Get(middleware.HeaderRefreshedToken)
+	}
+	userID := uuid.NewString()
+
+	assert.Empty(t, do(j.GenerateToken(userID, domain.RoleWorker, time.Hour)))
+	assert.Empty(t, do(j.GenerateImpersonationToken(userID, domain.RoleWorker, uuid.NewString(), 5*time.Minute)))
+
+	refreshed := do(j.GenerateToken(userID, domain.RoleWorker, 5*time.Minute))
+	require.NotEmpty(t, refreshed)
+	claims, err := j.ValidateToken(refreshed)
+	require.NoError(t, err)
+	assert.Equal(t, userID, claims.UserID)
+}
+
+func TestAuthMiddleware_TokenService(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	adminID, userID := uuid.NewString(), uuid.NewString()
+	ts := &fakeTokenService{claims: map[string]*token.Claims{
+		"regular": {UserID: userID, Role: domain.RoleWorker},
+		"imp":     {UserID: userID, Role: domain.RoleWorker, ActAs: adminID},
+	}}
+
+	r := gin.New()
+	r.GET("/whoami", middleware.AuthMiddleware(ts), func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{
+			"user_id": c.GetString(middleware.CtxUserID),
+			"role":    c.GetString(middleware.CtxUserRole),
+			"act_as":  c.GetString(middleware.CtxActAs),
+		})
+	})
+	bearer := func(tok string) map[string]string { return map[string]string{"Authorization": "Bearer " + tok} }
+
+	rr := doReq(t, r, http.MethodGet, "/whoami", nil, bearer("regular"))
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `{"user_id":"`+userID+`","role":"worker","act_as":""}`, rr.Body.String())
+
+	rr = doReq(t, r, http.MethodGet, "/whoami", nil, bearer("imp"))
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `{"user_id":"`+userID+`","role":"worker","act_as":"`+adminID+`"}`, rr.Body.String())
+
+	rr = doReq(t, r, http.MethodGet, "/whoami", nil, bearer("unknown"))
+	require.Equal(t, http.StatusUnauthorized, rr.Code)
+	assert.JSONEq(t, `{"error":"invalid token","code":"token_invalid"}`, rr.Body.String())
+
+	rr = doReq(t, r, http.MethodGet, "/whoami", nil, bearer("expired"))
+	require.Equal(t, http.StatusUnauthorized, rr.Code)
+	assert.JSONEq(t, `{"error":"token expired","code":"token_expired"}`, rr.Body.String())
+
+	rr = doReq(t, r, http.MethodGet, "/whoami", nil, nil)
+	require.Equal(t, http.StatusUnauthorized, rr.Code)
+	assert.JSONEq(t, `{"error":"missing Authorization header","code":"token_missing"}`, rr.Body.String())
+
+	ts.revoked = true
+	rr = doReq(t, r, http.MethodGet, "/whoami", nil, bearer("regular"))
+	require.Equal(t, http.StatusUnauthorized, rr.Code)
+	assert.JSONEq(t, `{"error":"token revoked","code":"token_revoked"}`, rr.Body.String())
+}
+
+func TestClientCert(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	userID := uuid.NewString()
+	ts := &fakeTokenService{claims: map[string]*token.Claims{"regular": {UserID: userID, Role: domain.RoleWorker}}}
+
+	r := gin.New()
+	r.Use(middleware.ClientCert([]string{"spiffe://corp/ns/billing/sa/billing=worker", "reports.internal=admin"}))
+	r.GET("/whoami", middleware.AuthMiddleware(ts), middleware.RequireAdmin(), func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{
+			"user_id":   c.GetString(middleware.CtxUserID),
+			"client_id": c.GetString(middleware.CtxClientID),
+		})
+	})
+
+	spiffe, err := url.Parse("spiffe://corp/ns/billing/sa/billing")
+	require.NoError(t, err)
+	billing := &x509.Certificate{URIs: []*url.URL{spiffe}}
+	reports := &x509.Certificate{DNSNames: []string{"reports.internal"}}
+	unknown := &x509.Certificate{DNSNames: []string{"other.internal"}}
+
+	do := func(cert *x509.Certificate, verified bool, auth string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/whoami", nil)
+		if cert != nil {
+			req.TLS = &tls.ConnectionState{PeerCertificates: []*x509.Certificate{cert}}
+			if verified {
+				req.TLS.VerifiedChains = [][]*x509.Certificate{{cert}}
+			}
+		}
+		if auth != "" {
+			req.Header.Set("Authorization", "Bearer "+auth)
+		}
+		rr := httptest.NewRecorder()
+		r.ServeHTTP(rr, req)
+		return rr
+	}
+
+	// DNS SAN mapped to admin, acts as the system
+	rr := do(reports, true, "")
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	assert.JSONEq(t, `{"user_id":"`+uuid.Nil.String()+`","client_id":"reports.internal"}`, rr.Body.String())
+
+	// SPIFFE ID mapped to worker: not an admin
+	rr = do(billing, true, "")
+	require.Equal(t, http.StatusForbidden, rr.Code, rr.Body.String())
+
+	// unverified or unknown certificates identify nobody
+	rr = do(reports, false, "")
+	require.Equal(t, http.StatusUnauthorized, rr.Code)
+	rr = do(unknown, true, "")
+	require.Equal(t, http.StatusUnauthorized, rr.Code)
+	rr = do(nil, false, "")
+	require.Equal(t, http.StatusUnauthorized, rr.Code)
+
+	// a bearer token takes precedence over the certificate
+	rr = do(reports, true, "regular")
+	require.Equal(t, http.StatusForbidden, rr.Code, rr.Body.String())
+}
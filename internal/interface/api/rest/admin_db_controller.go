@@ -0,0 +1,58 @@
+package rest
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"user-manager-api/internal/application/ports"
+	"user-manager-api/internal/interface/api/rest/dto/database"
+	"user-manager-api/internal/interface/api/rest/middleware"
+	"user-manager-api/internal/interface/api/rest/validator"
+)
+
+// AdminDBController - the slow statements of the answering instance, for the
+// production diagnosis without access to the DB
+type AdminDBController struct {
+	queryStats ports.DBQueryStats
+	logger     *zap.Logger
+}
+
+func NewAdminDBController(
+	r *gin.Engine,
+	queryStats ports.DBQueryStats,
+	logger *zap.Logger,
+	tokenService ports.TokenService,
+) *AdminDBController {
+	adc := &AdminDBController{
+		queryStats: queryStats,
+		logger:     logger,
+	}
+
+	r.GET(
+		RouteAdminSlowQueries,
+		middleware.AuthMiddleware(tokenService),
+		middleware.RequireAdmin(),
+		adc.GetSlowQueriesHandler,
+	)
+
+	return adc
+}
+
+// GetSlowQueriesHandler - "?limit=" of the statements, 20 by default
+func (adc *AdminDBController) GetSlowQueriesHandler(c *gin.Context) {
+	limit, err := validator.ParseSlowQueryLimit(c.Query("limit"))
+	if err != nil {
+		c.JSON(
+			http.StatusBadRequest,
+			gin.H{"error": err.Error()},
+		)
+		return
+	}
+
+	c.JSON(http.StatusOK, database.ToResponseSlowQueries(
+		adc.queryStats.Threshold(),
+		adc.queryStats.SlowQueries(limit),
+	))
+}
@@ -0,0 +1,74 @@
+package rest
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"user-manager-api/internal/domain/stats"
+	domain "user-manager-api/internal/domain/user"
+	jwtSvc "user-manager-api/internal/infrastructure/jwt"
+)
+
+type fakeDBQueryStats struct {
+	queries []stats.QueryStats
+	limit   int
+}
+
+func (f *fakeDBQueryStats) SlowQueries(limit int) []stats.QueryStats {
+	f.limit = limit
+	return f.queries
+}
+
+func (f *fakeDBQueryStats) Threshold() time.Duration { return 500 * time.Millisecond }
+
+func TestAdminDBController_GetSlowQueriesHandler(t *testing.T) {
+	lastSlowAt := time.Date(2026, 10, 15, 9, 0, 0, 0, time.UTC)
+	s := &fakeDBQueryStats{queries: []stats.QueryStats{{
+		Fingerprint: "9f3c2a1b7d4e5f60",
+		SQL:         "SELECT id FROM users WHERE email = $1",
+		Calls:       4,
+		Slow:        1,
+		Total:       1200 * time.Millisecond,
+		Max:         900500 * time.Microsecond,
+		LastSlowAt:  &lastSlowAt,
+	}}}
+	wantBody := `{"threshold":"500ms","data":[{"fingerprint":"9f3c2a1b7d4e5f60","sql":"SELECT id FROM users WHERE email = $1",` +
+		`"calls":4,"slow":1,"mean_ms":300,"max_ms":900.5,"last_slow_at":"2026-10-15T09:00:00Z"}]}`
+
+	tests := []struct {
+		name       string
+		query      string
+		role       string
+		wantStatus int
+		wantLimit  int
+		wantBody   string
+	}{
+		{"200 default limit", "", domain.RoleAdmin, http.StatusOK, 20, wantBody},
+		{"200 limit", "?limit=5", domain.RoleAdmin, http.StatusOK, 5, wantBody},
+		{"400 limit", "?limit=0", domain.RoleAdmin, http.StatusBadRequest, 0, ""},
+		{"403 worker", "", domain.RoleWorker, http.StatusForbidden, 0, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			r := gin.New()
+			j := jwtSvc.New("test-secret")
+			NewAdminDBController(r, s, zap.NewNop(), j)
+			s.limit = 0
+
+			rr := doReq(t, r, http.MethodGet, RouteAdminSlowQueries+tt.query, nil, adminStatsHeaders(t, j, tt.role))
+			require.Equal(t, tt.wantStatus, rr.Code, rr.Body.String())
+			assert.Equal(t, tt.wantLimit, s.limit)
+			if tt.wantBody != "" {
+				assert.JSONEq(t, tt.wantBody, rr.Body.String())
+			}
+		})
+	}
+}
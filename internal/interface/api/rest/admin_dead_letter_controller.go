@@ -0,0 +1,131 @@
+package rest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"user-manager-api/internal/application/ports"
+	"user-manager-api/internal/domain/eventbus"
+	"user-manager-api/internal/interface/api/rest/dto/broker"
+	"user-manager-api/internal/interface/api/rest/middleware"
+	"user-manager-api/internal/interface/api/rest/validator"
+)
+
+// AdminDeadLetterController - the messages the consumer failed on: peek,
+// requeue to the exchange or discard
+type AdminDeadLetterController struct {
+	deadLetterService ports.DeadLetterService
+	logger            *zap.Logger
+}
+
+func NewAdminDeadLetterController(
+	r *gin.Engine,
+	deadLetterService ports.DeadLetterService,
+	logger *zap.Logger,
+	tokenService ports.TokenService,
+) *AdminDeadLetterController {
+	adlc := &AdminDeadLetterController{
+		deadLetterService: deadLetterService,
+		logger:            logger,
+	}
+
+	r.GET(
+		RouteAdminDeadLetters,
+		middleware.AuthMiddleware(tokenService),
+		middleware.RequireAdmin(),
+		adlc.PeekHandler,
+	)
+	r.POST(
+		RouteAdminDLQRequeue,
+		middleware.AuthMiddleware(tokenService),
+		middleware.RequireAdmin(),
+		adlc.RequeueHandler,
+	)
+	r.POST(
+		RouteAdminDLQDiscard,
+		middleware.AuthMiddleware(tokenService),
+		middleware.RequireAdmin(),
+		adlc.DiscardHandler,
+	)
+
+	return adlc
+}
+
+func (adlc *AdminDeadLetterController) PeekHandler(c *gin.Context) {
+	limit, err := validator.ParseDeadLetterLimit(c.Query("limit"))
+	if err != nil {
+		c.JSON(
+			http.StatusBadRequest,
+			gin.H{"error": err.Error()},
+		)
+		return
+	}
+
+	dls, err := adlc.deadLetterService.Peek(c.Request.Context(), limit)
+	if err != nil {
+		adlc.deadLetterError(c, "Peek", err, nil)
+		return
+	}
+
+	c.JSON(http.StatusOK, broker.ToResponseDeadLetters(*dls))
+}
+
+func (adlc *AdminDeadLetterController) RequeueHandler(c *gin.Context) {
+	adlc.move(c, "Requeue", adlc.deadLetterService.Requeue)
+}
+
+func (adlc *AdminDeadLetterController) DiscardHandler(c *gin.Context) {
+	adlc.move(c, "Discard", adlc.deadLetterService.Discard)
+}
+
+// move - a requeue or a discard of the requested messages
+func (adlc *AdminDeadLetterController) move(
+	c *gin.Context,
+	op string,
+	fn func(ctx context.Context, actor uuid.UUID, ids []string) ([]string, error),
+) {
+	ok, actor := validator.IsUUID(c.GetString(middleware.CtxUserID))
+	if !ok {
+		c.JSON(
+			http.StatusUnauthorized,
+			gin.H{"error": "invalid token"},
+		)
+		return
+	}
+	req, ok := BindAndValidate(c, validator.ValidateDeadLetters)
+	if !ok {
+		return
+	}
+
+	done, err := fn(c.Request.Context(), actor, req.MessageIDs)
+	if err != nil {
+		// the ones moved before the failure are gone from the queue all the same
+		adlc.deadLetterError(c, op, err, done)
+		return
+	}
+
+	c.JSON(http.StatusOK, broker.ToResponseDeadLettersResult(req.MessageIDs, done))
+}
+
+// deadLetterError - done are the ids a requeue or a discard moved before err
+func (adlc *AdminDeadLetterController) deadLetterError(c *gin.Context, op string, err error, done []string) {
+	if errors.Is(err, eventbus.ErrNoDeadLetterQueue) {
+		c.JSON(
+			http.StatusServiceUnavailable,
+			gin.H{"error": "dead-letter queue is not configured"},
+		)
+		return
+	}
+
+	resp := gin.H{"error": "failed to process the dead-letter queue"}
+	if done != nil {
+		resp["done"] = done
+	}
+	c.JSON(http.StatusBadGateway, resp)
+	adlc.logger.Error(op+"() error", zap.Error(err), zap.Strings("done", done))
+}
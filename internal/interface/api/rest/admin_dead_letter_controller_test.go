@@ -0,0 +1,222 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"user-manager-api/internal/domain/eventbus"
+	domain "user-manager-api/internal/domain/user"
+	jwtSvc "user-manager-api/internal/infrastructure/jwt"
+	dto "user-manager-api/internal/interface/api/rest/dto/broker"
+)
+
+type fakeDeadLetterService struct {
+	PeekFunc    func(ctx context.Context, limit int) (*eventbus.DeadLetters, error)
+	RequeueFunc func(ctx context.Context, actor uuid.UUID, ids []string) ([]string, error)
+	DiscardFunc func(ctx context.Context, actor uuid.UUID, ids []string) ([]string, error)
+}
+
+func (f *fakeDeadLetterService) Peek(ctx context.Context, limit int) (*eventbus.DeadLetters, error) {
+	if f.PeekFunc == nil {
+		return nil, errors.New("not used")
+	}
+	return f.PeekFunc(ctx, limit)
+}
+
+func (f *fakeDeadLetterService) Requeue(ctx context.Context, actor uuid.UUID, ids []string) ([]string, error) {
+	if f.RequeueFunc == nil {
+		return nil, errors.New("not used")
+	}
+	return f.RequeueFunc(ctx, actor, ids)
+}
+
+func (f *fakeDeadLetterService) Discard(ctx context.Context, actor uuid.UUID, ids []string) ([]string, error) {
+	if f.DiscardFunc == nil {
+		return nil, errors.New("not used")
+	}
+	return f.DiscardFunc(ctx, actor, ids)
+}
+
+func setupAdminDeadLetterRouter(t *testing.T, s *fakeDeadLetterService) (*gin.Engine, *jwtSvc.Service) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	j := jwtSvc.New("test-secret")
+	NewAdminDeadLetterController(r, s, zap.NewNop(), j)
+
+	return r, j
+}
+
+func TestAdminDeadLetterController_PeekHandler(t *testing.T) {
+	failedAt := time.Date(2026, 10, 16, 9, 0, 5, 0, time.UTC)
+
+	type tc struct {
+		name       string
+		role       string
+		query      string
+		peek       func(context.Context, int) (*eventbus.DeadLetters, error)
+		wantStatus int
+		want       *dto.DeadLetters
+	}
+	tests := []tc{
+		{
+			name:  "200 default limit",
+			role:  domain.RoleAdmin,
+			query: "",
+			peek: func(_ context.Context, limit int) (*eventbus.DeadLetters, error) {
+				if limit != 20 {
+					return nil, errors.New("unexpected limit")
+				}
+				return &eventbus.DeadLetters{Total: 3, Messages: []eventbus.DeadLetter{
+					{MessageID: "m1", RoutingKey: "DELETE", Error: "DELETE handler: db", FailedAt: failedAt, Body: []byte(`{"id":3}`)},
+					{MessageID: "m2", Body: []byte("not json")},
+				}}, nil
+			},
+			wantStatus: http.StatusOK,
+			want: &dto.DeadLetters{Total: 3, Messages: []dto.DeadLetter{
+				{MessageID: "m1", RoutingKey: "DELETE", Error: "DELETE handler: db", FailedAt: &failedAt, Body: map[string]any{"id": float64(3)}},
+				{MessageID: "m2", Body: "not json"},
+			}},
+		},
+		{
+			name:       "400 limit",
+			role:       domain.RoleAdmin,
+			query:      "?limit=1000",
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:  "503 no dead-letter queue",
+			role:  domain.RoleAdmin,
+			query: "?limit=5",
+			peek: func(context.Context, int) (*eventbus.DeadLetters, error) {
+				return nil, eventbus.ErrNoDeadLetterQueue
+			},
+			wantStatus: http.StatusServiceUnavailable,
+		},
+		{
+			name:  "502 broker",
+			role:  domain.RoleAdmin,
+			query: "?limit=5",
+			peek: func(context.Context, int) (*eventbus.DeadLetters, error) {
+				return nil, errors.New("channel closed")
+			},
+			wantStatus: http.StatusBadGateway,
+		},
+		{
+			name:       "403 worker",
+			role:       domain.RoleWorker,
+			wantStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, j := setupAdminDeadLetterRouter(t, &fakeDeadLetterService{PeekFunc: tt.peek})
+			w := doReq(t, r, http.MethodGet, RouteAdminDeadLetters+tt.query, nil, adminStatsHeaders(t, j, tt.role))
+			require.Equal(t, tt.wantStatus, w.Code)
+			if tt.want == nil {
+				return
+			}
+
+			var got dto.DeadLetters
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &got))
+			assert.Equal(t, *tt.want, got)
+		})
+	}
+}
+
+func TestAdminDeadLetterController_MoveHandlers(t *testing.T) {
+	type tc struct {
+		name       string
+		path       string
+		body       dto.DeadLettersRequest
+		move       func(context.Context, uuid.UUID, []string) ([]string, error)
+		wantStatus int
+		want       *dto.DeadLettersResult
+	}
+	found := func(_ context.Context, _ uuid.UUID, ids []string) ([]string, error) {
+		return ids[:1], nil
+	}
+	tests := []tc{
+		{
+			name:       "200 requeue",
+			path:       RouteAdminDLQRequeue,
+			body:       dto.DeadLettersRequest{MessageIDs: []string{"m1", "m2", "m2"}},
+			move:       found,
+			wantStatus: http.StatusOK,
+			want:       &dto.DeadLettersResult{Done: []string{"m1"}, NotFound: []string{"m2"}},
+		},
+		{
+			name:       "200 discard",
+			path:       RouteAdminDLQDiscard,
+			body:       dto.DeadLettersRequest{MessageIDs: []string{"m1"}},
+			move:       found,
+			wantStatus: http.StatusOK,
+			want:       &dto.DeadLettersResult{Done: []string{"m1"}, NotFound: []string{}},
+		},
+		{
+			name:       "400 no ids",
+			path:       RouteAdminDLQRequeue,
+			body:       dto.DeadLettersRequest{MessageIDs: []string{}},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name: "502 broker",
+			path: RouteAdminDLQRequeue,
+			body: dto.DeadLettersRequest{MessageIDs: []string{"m1", "m2"}},
+			move: func(context.Context, uuid.UUID, []string) ([]string, error) {
+				return []string{"m1"}, eventbus.ErrRequeueNotConfirmed
+			},
+			wantStatus: http.StatusBadGateway,
+		},
+		{
+			name: "503 no dead-letter queue",
+			path: RouteAdminDLQDiscard,
+			body: dto.DeadLettersRequest{MessageIDs: []string{"m1"}},
+			move: func(context.Context, uuid.UUID, []string) ([]string, error) {
+				return nil, eventbus.ErrNoDeadLetterQueue
+			},
+			wantStatus: http.StatusServiceUnavailable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, j := setupAdminDeadLetterRouter(t, &fakeDeadLetterService{RequeueFunc: tt.move, DiscardFunc: tt.move})
+			w := doReq(t, r, http.MethodPost, tt.path, tt.body, adminStatsHeaders(t, j, domain.RoleAdmin))
+			require.Equal(t, tt.wantStatus, w.Code)
+			if tt.want == nil {
+				return
+			}
+
+			var got dto.DeadLettersResult
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &got))
+			assert.Equal(t, *tt.want, got)
+		})
+	}
+
+	t.Run("502 keeps the ones done", func(t *testing.T) {
+		r, j := setupAdminDeadLetterRouter(t, &fakeDeadLetterService{RequeueFunc: func(context.Context, uuid.UUID, []string) ([]string, error) {
+			return []string{"m1"}, eventbus.ErrRequeueNotConfirmed
+		}})
+		w := doReq(t, r, http.MethodPost, RouteAdminDLQRequeue, dto.DeadLettersRequest{MessageIDs: []string{"m1", "m2"}}, adminStatsHeaders(t, j, domain.RoleAdmin))
+		require.Equal(t, http.StatusBadGateway, w.Code)
+
+		var got struct {
+			Done []string `json:"done"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &got))
+		assert.Equal(t, []string{"m1"}, got.Done)
+	})
+}
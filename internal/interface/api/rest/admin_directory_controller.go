@@ -0,0 +1,58 @@
+package rest
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"user-manager-api/internal/application/ports"
+	"user-manager-api/internal/interface/api/rest/dto/directory"
+	"user-manager-api/internal/interface/api/rest/middleware"
+)
+
+// AdminDirectoryController - the state of the LDAP/Active Directory sync, the
+// sync itself is a job(sync-directory).
+type AdminDirectoryController struct {
+	directorySyncService ports.DirectorySyncService
+	logger               *zap.Logger
+}
+
+func NewAdminDirectoryController(
+	r *gin.Engine,
+	directorySyncService ports.DirectorySyncService,
+	logger *zap.Logger,
+	tokenService ports.TokenService,
+) *AdminDirectoryController {
+	adc := &AdminDirectoryController{
+		directorySyncService: directorySyncService,
+		logger:               logger,
+	}
+
+	r.GET(
+		RouteAdminDirectory,
+		middleware.AuthMiddleware(tokenService),
+		middleware.RequireAdmin(),
+		adc.GetLastSyncHandler,
+	)
+
+	return adc
+}
+
+func (adc *AdminDirectoryController) GetLastSyncHandler(c *gin.Context) {
+	s, err := adc.directorySyncService.LastSync(c.Request.Context())
+	if err != nil {
+		c.JSON(
+			http.StatusInternalServerError,
+			gin.H{"error": "failed to get the directory sync"},
+		)
+		adc.logger.Error("LastSync() error", zap.Error(err))
+		return
+	}
+	if s == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "the directory was never synced"})
+		return
+	}
+
+	c.JSON(http.StatusOK, directory.ToResponseSync(*s))
+}
@@ -0,0 +1,120 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	domainDirectory "user-manager-api/internal/domain/directory"
+	domain "user-manager-api/internal/domain/user"
+	jwtSvc "user-manager-api/internal/infrastructure/jwt"
+	"user-manager-api/internal/interface/api/rest/dto/directory"
+)
+
+type fakeDirectorySyncService struct {
+	LastSyncFunc func(ctx context.Context) (*domainDirectory.Sync, error)
+}
+
+func (f *fakeDirectorySyncService) Sync(context.Context) (domainDirectory.Sync, error) {
+	return domainDirectory.Sync{}, errors.New("not used")
+}
+
+func (f *fakeDirectorySyncService) LastSync(ctx context.Context) (*domainDirectory.Sync, error) {
+	if f.LastSyncFunc == nil {
+		return nil, errors.New("not used")
+	}
+	return f.LastSyncFunc(ctx)
+}
+
+func setupAdminDirectoryRouter(t *testing.T, ds *fakeDirectorySyncService) (*gin.Engine, *jwtSvc.Service) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	j := jwtSvc.New("test-secret")
+	NewAdminDirectoryController(r, ds, zap.NewNop(), j)
+
+	return r, j
+}
+
+func TestAdminDirectoryController_GetLastSyncHandler(t *testing.T) {
+	started := time.Date(2026, 10, 16, 3, 0, 0, 0, time.UTC)
+	last := &domainDirectory.Sync{
+		StartedAt:  started,
+		FinishedAt: started.Add(time.Minute),
+		Fetched:    10,
+		Created:    2,
+		Updated:    1,
+		Disabled:   1,
+		Unchanged:  5,
+		Failed:     1,
+	}
+
+	type tc struct {
+		name       string
+		role       string
+		lastSync   func(ctx context.Context) (*domainDirectory.Sync, error)
+		wantStatus int
+		wantErr    string
+	}
+
+	cases := []tc{
+		{
+			name:       "200",
+			role:       domain.RoleAdmin,
+			lastSync:   func(context.Context) (*domainDirectory.Sync, error) { return last, nil },
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "403 worker",
+			role:       domain.RoleWorker,
+			wantStatus: http.StatusForbidden,
+			wantErr:    "admin role required",
+		},
+		{
+			name:       "404 never synced",
+			role:       domain.RoleAdmin,
+			lastSync:   func(context.Context) (*domainDirectory.Sync, error) { return nil, nil },
+			wantStatus: http.StatusNotFound,
+			wantErr:    "the directory was never synced",
+		},
+		{
+			name:       "500",
+			role:       domain.RoleAdmin,
+			lastSync:   func(context.Context) (*domainDirectory.Sync, error) { return nil, errors.New("db down") },
+			wantStatus: http.StatusInternalServerError,
+			wantErr:    "failed to get the directory sync",
+		},
+	}
+
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			r, j := setupAdminDirectoryRouter(t, &fakeDirectorySyncService{LastSyncFunc: tt.lastSync})
+			tok, err := j.GenerateToken(uuid.NewString(), tt.role, time.Minute)
+			require.NoError(t, err)
+
+			rr := doReq(t, r, http.MethodGet, RouteAdminDirectory, nil, map[string]string{"Authorization": "Bearer " + tok})
+			require.Equal(t, tt.wantStatus, rr.Code, rr.Body.String())
+
+			if tt.wantErr != "" {
+				var resp map[string]any
+				require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+				assert.Equal(t, tt.wantErr, resp["error"])
+				return
+			}
+			var resp directory.Sync
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+			assert.Equal(t, directory.ToResponseSync(*last), resp)
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package rest
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"user-manager-api/internal/application/ports"
+	domain "user-manager-api/internal/domain/user"
+	"user-manager-api/internal/interface/api/rest/dto/user"
+	"user-manager-api/internal/interface/api/rest/middleware"
+	"user-manager-api/internal/interface/api/rest/validator"
+)
+
+// AdminDuplicateController - the likely duplicate accounts and their merge
+type AdminDuplicateController struct {
+	duplicateService ports.DuplicateService
+	logger           *zap.Logger
+}
+
+func NewAdminDuplicateController(
+	r *gin.Engine,
+	duplicateService ports.DuplicateService,
+	logger *zap.Logger,
+	tokenService ports.TokenService,
+) *AdminDuplicateController {
+	adc := &AdminDuplicateController{
+		duplicateService: duplicateService,
+		logger:           logger,
+	}
+
+	r.GET(
+		RouteAdminDuplicates,
+		middleware.AuthMiddleware(tokenService),
+		middleware.RequireAdmin(),
+		adc.FindDuplicatesHandler,
+	)
+	r.POST(
+		RouteAdminMerge,
+		middleware.AuthMiddleware(tokenService),
+		middleware.RequireAdmin(),
+		adc.MergeHandler,
+	)
+
+	return adc
+}
+
+func (adc *AdminDuplicateController) FindDuplicatesHandler(c *gin.Context) {
+	groups, err := adc.duplicateService.FindDuplicates(c.Request.Context())
+	if err != nil {
+		c.JSON(
+			http.StatusInternalServerError,
+			gin.H{"error": "failed to find duplicates"},
+		)
+		adc.logger.Error("FindDuplicates() error", zap.Error(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, user.ToDuplicatesResponse(groups))
+}
+
+// MergeHandler - 200 with the winner, 404 if either user is missing or deleted,
+// 409 if the loser is the last admin
+func (adc *AdminDuplicateController) MergeHandler(c *gin.Context) {
+	ok, actor := validator.IsUUID(c.GetString(middleware.CtxUserID))
+	if !ok {
+		c.JSON(
+			http.StatusUnauthorized,
+			gin.H{"error": "invalid token"},
+		)
+		return
+	}
+	req, ok := BindAndValidate(c, validator.ValidateMerge)
+	if !ok {
+		return
+	}
+	winner := uuid.MustParse(strings.TrimSpace(req.WinnerID))
+	loser := uuid.MustParse(strings.TrimSpace(req.LoserID))
+
+	u, res, err := adc.duplicateService.Merge(c.Request.Context(), actor, winner, loser)
+	switch {
+	case errors.Is(err, domain.ErrLastAdmin):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error(), "code": codeLastAdmin})
+		return
+	case errors.Is(err, domain.ErrLegalHold):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error(), "code": codeLegalHold})
+		return
+	case errors.Is(err, domain.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "user not found", "code": codeUserNotFound})
+		return
+	case err != nil:
+		c.JSON(
+			http.StatusInternalServerError,
+			gin.H{"error": "failed to merge users"},
+		)
+		adc.logger.Error("Merge() error", zap.Error(err), zap.Stringer("actor_uuid", actor),
+			zap.Stringer("winner_uuid", winner), zap.Stringer("loser_uuid", loser))
+		return
+	}
+
+	c.JSON(http.StatusOK, user.ToMergeResponse(*u, res))
+}
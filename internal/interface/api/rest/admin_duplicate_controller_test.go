@@ -0,0 +1,158 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	domain "user-manager-api/internal/domain/user"
+	jwtSvc "user-manager-api/internal/infrastructure/jwt"
+	"user-manager-api/internal/interface/api/rest/dto/user"
+)
+
+type fakeDuplicateService struct {
+	FindDuplicatesFunc func(ctx context.Context) ([]domain.DuplicateGroup, error)
+	MergeFunc          func(ctx context.Context, actor, winner, loser domain.UUID) (*domain.User, domain.MergeResult, error)
+}
+
+func (f *fakeDuplicateService) FindDuplicates(ctx context.Context) ([]domain.DuplicateGroup, error) {
+	if f.FindDuplicatesFunc == nil {
+		return nil, errors.New("not used")
+	}
+	return f.FindDuplicatesFunc(ctx)
+}
+
+func (f *fakeDuplicateService) Merge(ctx context.Context, actor, winner, loser domain.UUID) (*domain.User, domain.MergeResult, error) {
+	if f.MergeFunc == nil {
+		return nil, domain.MergeResult{}, errors.New("not used")
+	}
+	return f.MergeFunc(ctx, actor, winner, loser)
+}
+
+func setupAdminDuplicateRouter(t *testing.T, ds *fakeDuplicateService) (*gin.Engine, *jwtSvc.Service) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	j := jwtSvc.New("test-secret")
+	NewAdminDuplicateController(r, ds, zap.NewNop(), j)
+	// shares the path prefix with the route of a user
+	NewAdminController(r, zap.NewNop(), &fakeImpersonationService{}, &fakeCredentialService{}, j)
+
+	return r, j
+}
+
+func TestAdminDuplicateController_FindDuplicatesHandler(t *testing.T) {
+	a, b := uuid.New(), uuid.New()
+	ds := &fakeDuplicateService{FindDuplicatesFunc: func(context.Context) ([]domain.DuplicateGroup, error) {
+		return []domain.DuplicateGroup{{Reason: domain.DuplicatePhone, Key: "+33600000001", Users: []domain.UUID{a, b}}}, nil
+	}}
+	r, j := setupAdminDuplicateRouter(t, ds)
+
+	tok, err := j.GenerateToken(uuid.NewString(), domain.RoleWorker, time.Minute)
+	require.NoError(t, err)
+	rr := doReq(t, r, http.MethodGet, RouteAdminDuplicates, nil, map[string]string{"Authorization": "Bearer " + tok})
+	require.Equal(t, http.StatusForbidden, rr.Code, rr.Body.String())
+
+	tok, err = j.GenerateToken(uuid.NewString(), domain.RoleAdmin, time.Minute)
+	require.NoError(t, err)
+	rr = doReq(t, r, http.MethodGet, RouteAdminDuplicates, nil, map[string]string{"Authorization": "Bearer " + tok})
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+
+	var resp user.DuplicatesResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+	assert.Equal(t, []user.DuplicateGroup{{Reason: "phone", Key: "+33600000001", UserIDs: []uuid.UUID{a, b}}}, resp.Data)
+}
+
+func TestAdminDuplicateController_MergeHandler(t *testing.T) {
+	adminID := uuid.New()
+	winner, loser := uuid.New(), uuid.New()
+	body := user.MergeRequest{WinnerID: winner.String(), LoserID: loser.String()}
+
+	type tc struct {
+		name       string
+		body       any
+		merge      func(ctx context.Context, actor, winner, loser domain.UUID) (*domain.User, domain.MergeResult, error)
+		wantStatus int
+		wantErr    string
+	}
+
+	cases := []tc{
+		{
+			name: "200",
+			body: body,
+			merge: func(_ context.Context, actor, w, l domain.UUID) (*domain.User, domain.MergeResult, error) {
+				if actor != adminID || w != winner || l != loser {
+					return nil, domain.MergeResult{}, errors.New("unexpected args")
+				}
+				return &domain.User{UUID: winner, Role: domain.RoleWorker}, domain.MergeResult{Files: 2, Notes: 1, Audit: 3}, nil
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "400 same user",
+			body:       user.MergeRequest{WinnerID: winner.String(), LoserID: winner.String()},
+			wantStatus: http.StatusBadRequest,
+			wantErr:    errInvalidRequestBody,
+		},
+		{
+			name: "404",
+			body: body,
+			merge: func(context.Context, domain.UUID, domain.UUID, domain.UUID) (*domain.User, domain.MergeResult, error) {
+				return nil, domain.MergeResult{}, domain.ErrNotFound
+			},
+			wantStatus: http.StatusNotFound,
+			wantErr:    "user not found",
+		},
+		{
+			name: "409 last admin",
+			body: body,
+			merge: func(context.Context, domain.UUID, domain.UUID, domain.UUID) (*domain.User, domain.MergeResult, error) {
+				return nil, domain.MergeResult{}, domain.ErrLastAdmin
+			},
+			wantStatus: http.StatusConflict,
+			wantErr:    domain.ErrLastAdmin.Error(),
+		},
+		{
+			name: "500",
+			body: body,
+			merge: func(context.Context, domain.UUID, domain.UUID, domain.UUID) (*domain.User, domain.MergeResult, error) {
+				return nil, domain.MergeResult{}, errors.New("db down")
+			},
+			wantStatus: http.StatusInternalServerError,
+			wantErr:    "failed to merge users",
+		},
+	}
+
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			r, j := setupAdminDuplicateRouter(t, &fakeDuplicateService{MergeFunc: tt.merge})
+			tok, err := j.GenerateToken(adminID.String(), domain.RoleAdmin, time.Minute)
+			require.NoError(t, err)
+
+			rr := doReq(t, r, http.MethodPost, RouteAdminMerge, tt.body, map[string]string{"Authorization": "Bearer " + tok})
+			require.Equal(t, tt.wantStatus, rr.Code, rr.Body.String())
+
+			if tt.wantErr != "" {
+				var resp map[string]any
+				require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+				assert.Equal(t, tt.wantErr, resp["error"])
+				return
+			}
+			var resp user.MergeResponse
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+			assert.Equal(t, winner, resp.User.UUID)
+			assert.Equal(t, user.MergedCount{Files: 2, Notes: 1, Audit: 3}, resp.Moved)
+		})
+	}
+}
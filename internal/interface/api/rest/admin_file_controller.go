@@ -0,0 +1,91 @@
+package rest
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"user-manager-api/internal/application/ports"
+	"user-manager-api/internal/domain/pagination"
+	"user-manager-api/internal/interface/api/rest/dto/user_file"
+	"user-manager-api/internal/interface/api/rest/middleware"
+	"user-manager-api/internal/interface/api/rest/validator"
+)
+
+// AdminFileController - storage governance reviews: files of all users.
+type AdminFileController struct {
+	adminFileService ports.AdminFileService
+	logger           *zap.Logger
+}
+
+func NewAdminFileController(
+	r *gin.Engine,
+	adminFileService ports.AdminFileService,
+	logger *zap.Logger,
+	tokenService ports.TokenService,
+) *AdminFileController {
+	afc := &AdminFileController{
+		adminFileService: adminFileService,
+		logger:           logger,
+	}
+
+	r.GET(
+		RouteAdminFiles,
+		middleware.AuthMiddleware(tokenService),
+		middleware.RequireAdmin(),
+		afc.GetFilesHandler,
+	)
+
+	return afc
+}
+
+func (afc *AdminFileController) GetFilesHandler(c *gin.Context) {
+	p, errs := validator.ParsePagination(c.Request.URL.Query(), validator.UserFileSortFields)
+	if errs != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "invalid pagination params",
+			"details": errs,
+		})
+		return
+	}
+	f, errs := validator.ParseFileFilter(c.Request.URL.Query())
+	if errs != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "invalid filter params",
+			"details": errs,
+		})
+		return
+	}
+
+	files, err := afc.adminFileService.FindFiles(c.Request.Context(), f, p)
+	if err != nil {
+		c.JSON(
+			http.StatusInternalServerError,
+			gin.H{"error": "failed to get files"},
+		)
+		afc.logger.Error("FindFiles() error", zap.Error(err))
+		return
+	}
+	stats, err := afc.adminFileService.FileStats(c.Request.Context(), f)
+	if err != nil {
+		c.JSON(
+			http.StatusInternalServerError,
+			gin.H{"error": "failed to get files stats"},
+		)
+		afc.logger.Error("FileStats() error", zap.Error(err))
+		return
+	}
+
+	resp := user_file.AdminResponseData{
+		Data:  user_file.ToAdminUserFiles(files),
+		Stats: user_file.ToResponseStats(*stats),
+	}
+	// keyset pagination is available for the default(created_at) order only
+	if len(files) > 0 && p.Sort == "" {
+		last := files[len(files)-1]
+		resp.NextCursor = validator.EncodeCursor(pagination.Cursor{CreatedAt: last.CreatedAt, UUID: last.UUID})
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
@@ -0,0 +1,176 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"user-manager-api/internal/domain/pagination"
+	domain "user-manager-api/internal/domain/user"
+	"user-manager-api/internal/domain/user_file"
+	jwtSvc "user-manager-api/internal/infrastructure/jwt"
+	dto "user-manager-api/internal/interface/api/rest/dto/user_file"
+)
+
+type fakeAdminFileService struct {
+	FindFilesFunc func(ctx context.Context, f user_file.Filter, p pagination.Params) (user_file.UserFiles, error)
+	FileStatsFunc func(ctx context.Context, f user_file.Filter) (*user_file.Stats, error)
+}
+
+func (f *fakeAdminFileService) FindFiles(ctx context.Context, filter user_file.Filter, p pagination.Params) (user_file.UserFiles, error) {
+	if f.FindFilesFunc == nil {
+		return nil, errors.New("not used")
+	}
+	return f.FindFilesFunc(ctx, filter, p)
+}
+
+func (f *fakeAdminFileService) FileStats(ctx context.Context, filter user_file.Filter) (*user_file.Stats, error) {
+	if f.FileStatsFunc == nil {
+		return nil, errors.New("not used")
+	}
+	return f.FileStatsFunc(ctx, filter)
+}
+
+func setupAdminFileRouter(t *testing.T, s *fakeAdminFileService) (*gin.Engine, *jwtSvc.Service) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	j := jwtSvc.New("test-secret")
+	NewAdminFileController(r, s, zap.NewNop(), j)
+
+	return r, j
+}
+
+func TestAdminFileController_GetFilesHandler(t *testing.T) {
+	ownerID := uuid.New()
+	file := &user_file.UserFile{
+		UUID:      uuid.New(),
+		UserUUID:  ownerID,
+		FileName:  "scan.pdf",
+		MimeType:  "application/pdf",
+		SizeBytes: 2048,
+		CreatedAt: time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC),
+	}
+	stats := &user_file.Stats{
+		FilesCount: 3,
+		TotalBytes: 4096,
+		ByMimeType: []user_file.MimeTypeStats{
+			{MimeType: "application/pdf", FilesCount: 2, TotalBytes: 3072},
+			{MimeType: "image/png", FilesCount: 1, TotalBytes: 1024},
+		},
+	}
+
+	type tc struct {
+		name       string
+		query      string
+		role       string
+		noToken    bool
+		find       func(ctx context.Context, f user_file.Filter, p pagination.Params) (user_file.UserFiles, error)
+		stats      func(ctx context.Context, f user_file.Filter) (*user_file.Stats, error)
+		wantStatus int
+		wantErr    string
+		wantFilter *user_file.Filter
+	}
+	ok := func(context.Context, user_file.Filter, pagination.Params) (user_file.UserFiles, error) {
+		return user_file.UserFiles{file}, nil
+	}
+	okStats := func(context.Context, user_file.Filter) (*user_file.Stats, error) { return stats, nil }
+
+	minSize, maxSize := uint64(1024), uint64(4096)
+	from := time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC)
+
+	cases := []tc{
+		{name: "200 no filters", role: domain.RoleAdmin, find: ok, stats: okStats, wantStatus: http.StatusOK, wantFilter: &user_file.Filter{}},
+		{
+			name:       "200 filters",
+			query:      "?mime_type=application/*&min_size=1024&max_size=4096&uploaded_from=2026-09-01&user_id=" + ownerID.String(),
+			role:       domain.RoleAdmin,
+			find:       ok,
+			stats:      okStats,
+			wantStatus: http.StatusOK,
+			wantFilter: &user_file.Filter{
+				MimeType:     "application/*",
+				MinSize:      &minSize,
+				MaxSize:      &maxSize,
+				UploadedFrom: &from,
+				UserUUID:     &ownerID,
+			},
+		},
+		{name: "401 no token", noToken: true, wantStatus: http.StatusUnauthorized, wantErr: "missing Authorization header"},
+		{name: "403 worker", role: domain.RoleWorker, wantStatus: http.StatusForbidden, wantErr: "admin role required"},
+		{name: "400 bad filter", query: "?min_size=-1", role: domain.RoleAdmin, wantStatus: http.StatusBadRequest, wantErr: "invalid filter params"},
+		{name: "400 bad pagination", query: "?sort=mime_type", role: domain.RoleAdmin, wantStatus: http.StatusBadRequest, wantErr: "invalid pagination params"},
+		{
+			name: "500 find error",
+			role: domain.RoleAdmin,
+			find: func(context.Context, user_file.Filter, pagination.Params) (user_file.UserFiles, error) {
+				return nil, errors.New("db")
+			},
+			stats:      okStats,
+			wantStatus: http.StatusInternalServerError,
+			wantErr:    "failed to get files",
+		},
+		{
+			name: "500 stats error",
+			role: domain.RoleAdmin,
+			find: ok,
+			stats: func(context.Context, user_file.Filter) (*user_file.Stats, error) {
+				return nil, errors.New("db")
+			},
+			wantStatus: http.StatusInternalServerError,
+			wantErr:    "failed to get files stats",
+		},
+	}
+
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			var gotFilter *user_file.Filter
+			s := &fakeAdminFileService{FileStatsFunc: tt.stats}
+			if tt.find != nil {
+				s.FindFilesFunc = func(ctx context.Context, f user_file.Filter, p pagination.Params) (user_file.UserFiles, error) {
+					gotFilter = &f
+					return tt.find(ctx, f, p)
+				}
+			}
+			r, j := setupAdminFileRouter(t, s)
+
+			headers := map[string]string{}
+			if !tt.noToken {
+				tok, err := j.GenerateToken(uuid.NewString(), tt.role, time.Minute)
+				require.NoError(t, err)
+				headers["Authorization"] = "Bearer " + tok
+			}
+			rr := doReq(t, r, http.MethodGet, RouteAdminFiles+tt.query, nil, headers)
+			require.Equal(t, tt.wantStatus, rr.Code, rr.Body.String())
+
+			if tt.wantErr != "" {
+				var body map[string]any
+				require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &body))
+				assert.Equal(t, tt.wantErr, body["error"])
+				return
+			}
+
+			assert.Equal(t, tt.wantFilter, gotFilter)
+			var resp dto.AdminResponseData
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+			require.Len(t, resp.Data, 1)
+			assert.Equal(t, ownerID, resp.Data[0].UserUUID)
+			assert.Equal(t, "scan.pdf", resp.Data[0].FileName)
+			assert.NotEmpty(t, resp.NextCursor)
+			assert.Equal(t, uint64(3), resp.Stats.FilesCount)
+			assert.Equal(t, uint64(4096), resp.Stats.TotalBytes)
+			assert.Len(t, resp.Stats.ByMimeType, 2)
+		})
+	}
+}
@@ -0,0 +1,172 @@
+package rest
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"user-manager-api/internal/application/ports"
+	"user-manager-api/internal/application/services"
+	"user-manager-api/internal/interface/api/rest/dto/user"
+	"user-manager-api/internal/interface/api/rest/middleware"
+	"user-manager-api/internal/interface/api/rest/validator"
+)
+
+// AdminLegalHoldController - a user on a legal hold and their files are not
+// deleted, merged, anonymized or purged until the hold is released
+type AdminLegalHoldController struct {
+	legalHoldService ports.LegalHoldService
+	logger           *zap.Logger
+}
+
+func NewAdminLegalHoldController(
+	r *gin.Engine,
+	legalHoldService ports.LegalHoldService,
+	logger *zap.Logger,
+	tokenService ports.TokenService,
+) *AdminLegalHoldController {
+	alc := &AdminLegalHoldController{
+		legalHoldService: legalHoldService,
+		logger:           logger,
+	}
+
+	r.GET(
+		RouteAdminLegalHold,
+		middleware.AuthMiddleware(tokenService),
+		middleware.RequireAdmin(),
+		alc.GetLegalHoldHandler,
+	)
+	r.POST(
+		RouteAdminLegalHold,
+		middleware.AuthMiddleware(tokenService),
+		middleware.RequireAdmin(),
+		alc.PostLegalHoldHandler,
+	)
+	r.DELETE(
+		RouteAdminLegalHold,
+		middleware.AuthMiddleware(tokenService),
+		middleware.RequireAdmin(),
+		alc.DeleteLegalHoldHandler,
+	)
+
+	return alc
+}
+
+func (alc *AdminLegalHoldController) GetLegalHoldHandler(c *gin.Context) {
+	userUUID, ok := alc.userUUID(c)
+	if !ok {
+		return
+	}
+
+	h, err := alc.legalHoldService.Get(c.Request.Context(), userUUID)
+	if err != nil {
+		if errors.Is(err, services.ErrUserNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "user not found", "code": codeUserNotFound})
+			return
+		}
+		c.JSON(
+			http.StatusInternalServerError,
+			gin.H{"error": "failed to get the legal hold"},
+		)
+		alc.logger.Error("Get() error", zap.Error(err), zap.Stringer("user_uuid", userUUID))
+		return
+	}
+	if h == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "the user is not on a legal hold"})
+		return
+	}
+
+	c.JSON(http.StatusOK, user.ToResponseLegalHold(userUUID, *h))
+}
+
+// PostLegalHoldHandler - placing a hold again only changes its reason
+func (alc *AdminLegalHoldController) PostLegalHoldHandler(c *gin.Context) {
+	actor, ok := alc.actor(c)
+	if !ok {
+		return
+	}
+	userUUID, ok := alc.userUUID(c)
+	if !ok {
+		return
+	}
+	req, ok := BindAndValidate(c, validator.ValidateLegalHold)
+	if !ok {
+		return
+	}
+
+	h, err := alc.legalHoldService.Place(c.Request.Context(), actor, userUUID, req.Reason)
+	if err != nil {
+		if errors.Is(err, services.ErrUserNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "user not found", "code": codeUserNotFound})
+			return
+		}
+		c.JSON(
+			http.StatusInternalServerError,
+			gin.H{"error": "failed to place the legal hold"},
+		)
+		alc.logger.Error("Place() error", zap.Error(err), zap.Stringer("user_uuid", userUUID))
+		return
+	}
+
+	c.JSON(http.StatusOK, user.ToResponseLegalHold(userUUID, *h))
+}
+
+func (alc *AdminLegalHoldController) DeleteLegalHoldHandler(c *gin.Context) {
+	actor, ok := alc.actor(c)
+	if !ok {
+		return
+	}
+	userUUID, ok := alc.userUUID(c)
+	if !ok {
+		return
+	}
+
+	released, err := alc.legalHoldService.Release(c.Request.Context(), actor, userUUID)
+	if err != nil {
+		if errors.Is(err, services.ErrUserNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "user not found", "code": codeUserNotFound})
+			return
+		}
+		c.JSON(
+			http.StatusInternalServerError,
+			gin.H{"error": "failed to release the legal hold"},
+		)
+		alc.logger.Error("Release() error", zap.Error(err), zap.Stringer("user_uuid", userUUID))
+		return
+	}
+	if !released {
+		c.JSON(http.StatusNotFound, gin.H{"error": "the user is not on a legal hold"})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (alc *AdminLegalHoldController) userUUID(c *gin.Context) (uuid.UUID, bool) {
+	ok, userUUID := validator.IsUUID(c.Param("user_id"))
+	if !ok {
+		c.JSON(
+			http.StatusBadRequest,
+			gin.H{"error": "user_id must be a valid UUID"},
+		)
+		return uuid.Nil, false
+	}
+
+	return userUUID, true
+}
+
+func (alc *AdminLegalHoldController) actor(c *gin.Context) (uuid.UUID, bool) {
+	ok, actor := validator.IsUUID(c.GetString(middleware.CtxUserID))
+	if !ok {
+		c.JSON(
+			http.StatusUnauthorized,
+			gin.H{"error": "invalid token"},
+		)
+		return uuid.Nil, false
+	}
+
+	return actor, true
+}
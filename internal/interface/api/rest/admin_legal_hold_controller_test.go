@@ -0,0 +1,139 @@
+package rest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"user-manager-api/internal/application/services"
+	domain "user-manager-api/internal/domain/user"
+	jwtSvc "user-manager-api/internal/infrastructure/jwt"
+)
+
+type fakeLegalHoldService struct {
+	PlaceFunc   func(ctx context.Context, actor, userUUID uuid.UUID, reason string) (*domain.LegalHold, error)
+	ReleaseFunc func(ctx context.Context, actor, userUUID uuid.UUID) (bool, error)
+	GetFunc     func(ctx context.Context, userUUID uuid.UUID) (*domain.LegalHold, error)
+}
+
+func (f *fakeLegalHoldService) Place(ctx context.Context, actor, userUUID uuid.UUID, reason string) (*domain.LegalHold, error) {
+	if f.PlaceFunc == nil {
+		return nil, errors.New("not used")
+	}
+	return f.PlaceFunc(ctx, actor, userUUID, reason)
+}
+
+func (f *fakeLegalHoldService) Release(ctx context.Context, actor, userUUID uuid.UUID) (bool, error) {
+	if f.ReleaseFunc == nil {
+		return false, errors.New("not used")
+	}
+	return f.ReleaseFunc(ctx, actor, userUUID)
+}
+
+func (f *fakeLegalHoldService) Get(ctx context.Context, userUUID uuid.UUID) (*domain.LegalHold, error) {
+	if f.GetFunc == nil {
+		return nil, errors.New("not used")
+	}
+	return f.GetFunc(ctx, userUUID)
+}
+
+func setupAdminLegalHoldRouter(t *testing.T, s *fakeLegalHoldService) (*gin.Engine, *jwtSvc.Service) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	j := jwtSvc.New("test-secret")
+	NewAdminLegalHoldController(r, s, zap.NewNop(), j)
+
+	return r, j
+}
+
+func TestAdminLegalHoldController(t *testing.T) {
+	userUUID := uuid.New()
+	path := "/api/v1/admin/users/" + userUUID.String() + "/legal-hold"
+	since := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	held := &domain.LegalHold{Reason: "litigation #42", Since: since}
+	wantBody := `{"user_uuid":"` + userUUID.String() + `","reason":"litigation #42","since":"2026-10-01T00:00:00Z"}`
+
+	t.Run("200 place", func(t *testing.T) {
+		var gotReason string
+		r, j := setupAdminLegalHoldRouter(t, &fakeLegalHoldService{PlaceFunc: func(_ context.Context, _, u uuid.UUID, reason string) (*domain.LegalHold, error) {
+			require.Equal(t, userUUID, u)
+			gotReason = reason
+			return held, nil
+		}})
+
+		rr := doReq(t, r, http.MethodPost, path, map[string]any{"reason": "litigation #42"}, adminStatsHeaders(t, j, domain.RoleAdmin))
+		require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+		assert.Equal(t, "litigation #42", gotReason)
+		assert.JSONEq(t, wantBody, rr.Body.String())
+	})
+
+	t.Run("400 place without reason", func(t *testing.T) {
+		r, j := setupAdminLegalHoldRouter(t, &fakeLegalHoldService{})
+		rr := doReq(t, r, http.MethodPost, path, map[string]any{}, adminStatsHeaders(t, j, domain.RoleAdmin))
+		require.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+
+	t.Run("403 worker", func(t *testing.T) {
+		r, j := setupAdminLegalHoldRouter(t, &fakeLegalHoldService{})
+		rr := doReq(t, r, http.MethodPost, path, map[string]any{"reason": "x"}, adminStatsHeaders(t, j, domain.RoleWorker))
+		require.Equal(t, http.StatusForbidden, rr.Code)
+	})
+
+	t.Run("404 place unknown user", func(t *testing.T) {
+		r, j := setupAdminLegalHoldRouter(t, &fakeLegalHoldService{PlaceFunc: func(context.Context, uuid.UUID, uuid.UUID, string) (*domain.LegalHold, error) {
+			return nil, services.ErrUserNotFound
+		}})
+		rr := doReq(t, r, http.MethodPost, path, map[string]any{"reason": "x"}, adminStatsHeaders(t, j, domain.RoleAdmin))
+		require.Equal(t, http.StatusNotFound, rr.Code)
+	})
+
+	for name, tt := range map[string]struct {
+		path       string
+		hold       *domain.LegalHold
+		wantStatus int
+	}{
+		"200":             {path: path, hold: held, wantStatus: http.StatusOK},
+		"404 not on hold": {path: path, wantStatus: http.StatusNotFound},
+		"400 user_id":     {path: "/api/v1/admin/users/42/legal-hold", wantStatus: http.StatusBadRequest},
+	} {
+		t.Run("get "+name, func(t *testing.T) {
+			r, j := setupAdminLegalHoldRouter(t, &fakeLegalHoldService{GetFunc: func(context.Context, uuid.UUID) (*domain.LegalHold, error) {
+				return tt.hold, nil
+			}})
+			rr := doReq(t, r, http.MethodGet, tt.path, nil, adminStatsHeaders(t, j, domain.RoleAdmin))
+			require.Equal(t, tt.wantStatus, rr.Code, rr.Body.String())
+			if tt.wantStatus == http.StatusOK {
+				assert.JSONEq(t, wantBody, rr.Body.String())
+			}
+		})
+	}
+
+	for name, tt := range map[string]struct {
+		released   bool
+		err        error
+		wantStatus int
+	}{
+		"204":              {released: true, wantStatus: http.StatusNoContent},
+		"404 not on hold":  {wantStatus: http.StatusNotFound},
+		"404 unknown user": {err: services.ErrUserNotFound, wantStatus: http.StatusNotFound},
+		"500":              {err: errors.New("db down"), wantStatus: http.StatusInternalServerError},
+	} {
+		t.Run("release "+name, func(t *testing.T) {
+			r, j := setupAdminLegalHoldRouter(t, &fakeLegalHoldService{ReleaseFunc: func(context.Context, uuid.UUID, uuid.UUID) (bool, error) {
+				return tt.released, tt.err
+			}})
+			rr := doReq(t, r, http.MethodDelete, path, nil, adminStatsHeaders(t, j, domain.RoleAdmin))
+			require.Equal(t, tt.wantStatus, rr.Code, rr.Body.String())
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package rest
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"user-manager-api/internal/application/ports"
+	"user-manager-api/internal/application/services"
+	domain "user-manager-api/internal/domain/user"
+	"user-manager-api/internal/interface/api/rest/dto/user"
+	"user-manager-api/internal/interface/api/rest/middleware"
+	"user-manager-api/internal/interface/api/rest/validator"
+)
+
+// AdminLookupController resolves the internal ID of a user to its UUID and back
+type AdminLookupController struct {
+	userLookupService ports.UserLookupService
+	logger            *zap.Logger
+}
+
+func NewAdminLookupController(
+	r *gin.Engine,
+	userLookupService ports.UserLookupService,
+	logger *zap.Logger,
+	tokenService ports.TokenService,
+) *AdminLookupController {
+	alc := &AdminLookupController{
+		userLookupService: userLookupService,
+		logger:            logger,
+	}
+
+	r.GET(
+		RouteAdminUserLookup,
+		middleware.AuthMiddleware(tokenService),
+		middleware.RequireAdmin(),
+		alc.GetLookupHandler,
+	)
+
+	return alc
+}
+
+// GetLookupHandler - by ?id=(internal) or ?user_id=(UUID), exactly one of them
+func (alc *AdminLookupController) GetLookupHandler(c *gin.Context) {
+	ok, actor := validator.IsUUID(c.GetString(middleware.CtxUserID))
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid token"})
+		return
+	}
+	rawID, rawUUID := c.Query("id"), c.Query("user_id")
+	if (rawID == "") == (rawUUID == "") {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "exactly one of id or user_id is required"})
+		return
+	}
+
+	var (
+		ident *domain.Identity
+		err   error
+	)
+	if rawID != "" {
+		id, pErr := strconv.ParseUint(rawID, 10, 64)
+		if pErr != nil || id == 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "id must be a positive integer"})
+			return
+		}
+		ident, err = alc.userLookupService.LookupByID(c.Request.Context(), actor, domain.ID(id))
+	} else {
+		ok, userUUID := validator.IsUUID(rawUUID)
+		if !ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "user_id must be a valid UUID"})
+			return
+		}
+		ident, err = alc.userLookupService.LookupByUUID(c.Request.Context(), actor, userUUID)
+	}
+	if err != nil {
+		if errors.Is(err, services.ErrUserNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "user not found", "code": codeUserNotFound})
+			return
+		}
+		c.JSON(
+			http.StatusInternalServerError,
+			gin.H{"error": "failed to look up the user"},
+		)
+		alc.logger.Error("Lookup() error", zap.Error(err), zap.String("id", rawID), zap.String("user_id", rawUUID))
+		return
+	}
+
+	c.JSON(http.StatusOK, user.ToResponseIdentity(*ident))
+}
@@ -0,0 +1,98 @@
+package rest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"user-manager-api/internal/application/services"
+	domain "user-manager-api/internal/domain/user"
+	jwtSvc "user-manager-api/internal/infrastructure/jwt"
+)
+
+type fakeUserLookupService struct {
+	LookupByIDFunc   func(ctx context.Context, actor uuid.UUID, id domain.ID) (*domain.Identity, error)
+	LookupByUUIDFunc func(ctx context.Context, actor, userUUID uuid.UUID) (*domain.Identity, error)
+}
+
+func (f *fakeUserLookupService) LookupByID(ctx context.Context, actor uuid.UUID, id domain.ID) (*domain.Identity, error) {
+	if f.LookupByIDFunc == nil {
+		return nil, errors.New("not used")
+	}
+	return f.LookupByIDFunc(ctx, actor, id)
+}
+
+func (f *fakeUserLookupService) LookupByUUID(ctx context.Context, actor, userUUID uuid.UUID) (*domain.Identity, error) {
+	if f.LookupByUUIDFunc == nil {
+		return nil, errors.New("not used")
+	}
+	return f.LookupByUUIDFunc(ctx, actor, userUUID)
+}
+
+func TestAdminLookupController_GetLookupHandler(t *testing.T) {
+	userUUID := uuid.New()
+	deletedAt := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	ident := &domain.Identity{ID: 42, UUID: userUUID, DeletedAt: &deletedAt}
+	wantBody := `{"id":42,"user_id":"` + userUUID.String() + `","deleted_at":"2026-10-01T00:00:00Z"}`
+
+	s := &fakeUserLookupService{
+		LookupByIDFunc: func(_ context.Context, _ uuid.UUID, id domain.ID) (*domain.Identity, error) {
+			switch id {
+			case 42:
+				return ident, nil
+			case 500:
+				return nil, errors.New("audit down")
+			}
+			return nil, services.ErrUserNotFound
+		},
+		LookupByUUIDFunc: func(_ context.Context, _, u uuid.UUID) (*domain.Identity, error) {
+			if u == userUUID {
+				return ident, nil
+			}
+			return nil, services.ErrUserNotFound
+		},
+	}
+
+	tests := []struct {
+		name       string
+		query      string
+		role       string
+		wantStatus int
+		wantBody   string
+	}{
+		{"200 by id", "?id=42", domain.RoleAdmin, http.StatusOK, wantBody},
+		{"200 by user_id", "?user_id=" + userUUID.String(), domain.RoleAdmin, http.StatusOK, wantBody},
+		{"400 neither", "", domain.RoleAdmin, http.StatusBadRequest, ""},
+		{"400 both", "?id=42&user_id=" + userUUID.String(), domain.RoleAdmin, http.StatusBadRequest, ""},
+		{"400 id", "?id=-1", domain.RoleAdmin, http.StatusBadRequest, ""},
+		{"400 zero id", "?id=0", domain.RoleAdmin, http.StatusBadRequest, ""},
+		{"400 user_id", "?user_id=42", domain.RoleAdmin, http.StatusBadRequest, ""},
+		{"403 worker", "?id=42", domain.RoleWorker, http.StatusForbidden, ""},
+		{"404 id", "?id=7", domain.RoleAdmin, http.StatusNotFound, ""},
+		{"404 user_id", "?user_id=" + uuid.NewString(), domain.RoleAdmin, http.StatusNotFound, ""},
+		{"500", "?id=500", domain.RoleAdmin, http.StatusInternalServerError, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			r := gin.New()
+			j := jwtSvc.New("test-secret")
+			NewAdminLookupController(r, s, zap.NewNop(), j)
+
+			rr := doReq(t, r, http.MethodGet, RouteAdminUserLookup+tt.query, nil, adminStatsHeaders(t, j, tt.role))
+			require.Equal(t, tt.wantStatus, rr.Code, rr.Body.String())
+			if tt.wantBody != "" {
+				assert.JSONEq(t, tt.wantBody, rr.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,90 @@
+package rest
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"user-manager-api/internal/application/ports"
+	"user-manager-api/internal/domain/eventbus"
+	"user-manager-api/internal/interface/api/rest/dto/broker"
+	"user-manager-api/internal/interface/api/rest/middleware"
+)
+
+// AdminMQController - the broker's topology against the configuration
+type AdminMQController struct {
+	topology ports.MQTopology
+	logger   *zap.Logger
+}
+
+func NewAdminMQController(
+	r *gin.Engine,
+	topology ports.MQTopology,
+	logger *zap.Logger,
+	tokenService ports.TokenService,
+) *AdminMQController {
+	amc := &AdminMQController{
+		topology: topology,
+		logger:   logger,
+	}
+
+	r.GET(
+		RouteAdminMQTopology,
+		middleware.AuthMiddleware(tokenService),
+		middleware.RequireAdmin(),
+		amc.GetTopologyHandler,
+	)
+	r.POST(
+		RouteAdminMQRepair,
+		middleware.AuthMiddleware(tokenService),
+		middleware.RequireAdmin(),
+		amc.RepairTopologyHandler,
+	)
+
+	return amc
+}
+
+func (amc *AdminMQController) GetTopologyHandler(c *gin.Context) {
+	report, err := amc.topology.CheckTopology(c.Request.Context())
+	if err != nil {
+		amc.topologyError(c, "CheckTopology", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, broker.ToResponseTopology(*report))
+}
+
+func (amc *AdminMQController) RepairTopologyHandler(c *gin.Context) {
+	report, err := amc.topology.RepairTopology(c.Request.Context())
+	if errors.Is(err, eventbus.ErrTopologyMismatch) {
+		c.JSON(http.StatusConflict, gin.H{
+			"error":    "the exchange or the queue differs from the configuration, it has to be redeclared by an operator",
+			"topology": broker.ToResponseTopology(*report),
+		})
+		return
+	}
+	if err != nil {
+		amc.topologyError(c, "RepairTopology", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, broker.ToResponseTopology(*report))
+}
+
+func (amc *AdminMQController) topologyError(c *gin.Context, op string, err error) {
+	if errors.Is(err, eventbus.ErrNoManagement) {
+		c.JSON(
+			http.StatusServiceUnavailable,
+			gin.H{"error": "rabbitmq management API is not configured"},
+		)
+		return
+	}
+
+	c.JSON(
+		http.StatusBadGateway,
+		gin.H{"error": "failed to inspect the rabbitmq topology"},
+	)
+	amc.logger.Error(op+"() error", zap.Error(err))
+}
@@ -0,0 +1,175 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"user-manager-api/internal/domain/eventbus"
+	domain "user-manager-api/internal/domain/user"
+	jwtSvc "user-manager-api/internal/infrastructure/jwt"
+	dto "user-manager-api/internal/interface/api/rest/dto/broker"
+)
+
+type fakeMQTopology struct {
+	CheckTopologyFunc  func(ctx context.Context) (*eventbus.TopologyReport, error)
+	RepairTopologyFunc func(ctx context.Context) (*eventbus.TopologyReport, error)
+}
+
+func (f *fakeMQTopology) CheckTopology(ctx context.Context) (*eventbus.TopologyReport, error) {
+	if f.CheckTopologyFunc == nil {
+		return nil, errors.New("not used")
+	}
+	return f.CheckTopologyFunc(ctx)
+}
+
+func (f *fakeMQTopology) RepairTopology(ctx context.Context) (*eventbus.TopologyReport, error) {
+	if f.RepairTopologyFunc == nil {
+		return nil, errors.New("not used")
+	}
+	return f.RepairTopologyFunc(ctx)
+}
+
+func setupAdminMQRouter(t *testing.T, s *fakeMQTopology) (*gin.Engine, *jwtSvc.Service) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	j := jwtSvc.New("test-secret")
+	NewAdminMQController(r, s, zap.NewNop(), j)
+
+	return r, j
+}
+
+func driftReport() *eventbus.TopologyReport {
+	return &eventbus.TopologyReport{
+		Connected:     true,
+		Buffered:      3,
+		Exchange:      eventbus.EntityState{Name: "usermanager.events", Found: true},
+		Queue:         eventbus.EntityState{Name: "users.queue", Found: true},
+		ExtraBindings: []string{"users.legacy"},
+	}
+}
+
+func TestAdminMQController_GetTopologyHandler(t *testing.T) {
+	type tc struct {
+		name       string
+		role       string
+		check      func(context.Context) (*eventbus.TopologyReport, error)
+		wantStatus int
+		want       *dto.Topology
+	}
+	tests := []tc{
+		{
+			name:       "200 drift",
+			role:       domain.RoleAdmin,
+			check:      func(context.Context) (*eventbus.TopologyReport, error) { return driftReport(), nil },
+			wantStatus: http.StatusOK,
+			want: &dto.Topology{
+				Connected:       true,
+				Buffered:        3,
+				Drift:           true,
+				Exchange:        dto.Entity{Name: "usermanager.events", Found: true, Mismatches: []string{}},
+				Queue:           dto.Entity{Name: "users.queue", Found: true, Mismatches: []string{}},
+				MissingBindings: []string{},
+				ExtraBindings:   []string{"users.legacy"},
+			},
+		},
+		{
+			name:       "503 no management API",
+			role:       domain.RoleAdmin,
+			check:      func(context.Context) (*eventbus.TopologyReport, error) { return nil, eventbus.ErrNoManagement },
+			wantStatus: http.StatusServiceUnavailable,
+		},
+		{
+			name:       "502 broker",
+			role:       domain.RoleAdmin,
+			check:      func(context.Context) (*eventbus.TopologyReport, error) { return nil, errors.New("connection refused") },
+			wantStatus: http.StatusBadGateway,
+		},
+		{
+			name:       "403 worker",
+			role:       domain.RoleWorker,
+			wantStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, j := setupAdminMQRouter(t, &fakeMQTopology{CheckTopologyFunc: tt.check})
+			w := doReq(t, r, http.MethodGet, RouteAdminMQTopology, nil, adminStatsHeaders(t, j, tt.role))
+			require.Equal(t, tt.wantStatus, w.Code)
+			if tt.want == nil {
+				return
+			}
+
+			var got dto.Topology
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &got))
+			assert.Equal(t, *tt.want, got)
+		})
+	}
+}
+
+func TestAdminMQController_RepairTopologyHandler(t *testing.T) {
+	type tc struct {
+		name       string
+		repair     func(context.Context) (*eventbus.TopologyReport, error)
+		wantStatus int
+		wantDrift  bool
+	}
+	tests := []tc{
+		{
+			name: "200 repaired",
+			repair: func(context.Context) (*eventbus.TopologyReport, error) {
+				report := driftReport()
+				report.ExtraBindings = nil
+				return report, nil
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "409 mismatch",
+			repair: func(context.Context) (*eventbus.TopologyReport, error) {
+				report := driftReport()
+				report.Queue.Mismatches = []string{"not durable"}
+				return report, eventbus.ErrTopologyMismatch
+			},
+			wantStatus: http.StatusConflict,
+			wantDrift:  true,
+		},
+		{
+			name:       "502 broker",
+			repair:     func(context.Context) (*eventbus.TopologyReport, error) { return nil, errors.New("channel closed") },
+			wantStatus: http.StatusBadGateway,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, j := setupAdminMQRouter(t, &fakeMQTopology{RepairTopologyFunc: tt.repair})
+			w := doReq(t, r, http.MethodPost, RouteAdminMQRepair, nil, adminStatsHeaders(t, j, domain.RoleAdmin))
+			require.Equal(t, tt.wantStatus, w.Code)
+
+			switch tt.wantStatus {
+			case http.StatusOK:
+				var got dto.Topology
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &got))
+				assert.False(t, got.Drift)
+			case http.StatusConflict:
+				var got struct {
+					Topology dto.Topology `json:"topology"`
+				}
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &got))
+				assert.Equal(t, tt.wantDrift, got.Topology.Drift)
+				assert.Equal(t, []string{"not durable"}, got.Topology.Queue.Mismatches)
+			}
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package rest
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"user-manager-api/internal/application/ports"
+	"user-manager-api/internal/interface/api/rest/dto/projection"
+	"user-manager-api/internal/interface/api/rest/middleware"
+	"user-manager-api/internal/interface/api/rest/validator"
+)
+
+// AdminProjectionController - the read models of the event store, they never
+// touch the transactional tables
+type AdminProjectionController struct {
+	projectionService ports.ProjectionService
+	logger            *zap.Logger
+}
+
+func NewAdminProjectionController(
+	r *gin.Engine,
+	projectionService ports.ProjectionService,
+	logger *zap.Logger,
+	tokenService ports.TokenService,
+) *AdminProjectionController {
+	apc := &AdminProjectionController{
+		projectionService: projectionService,
+		logger:            logger,
+	}
+
+	r.GET(
+		RouteAdminUserSummary,
+		middleware.AuthMiddleware(tokenService),
+		middleware.RequireAdmin(),
+		apc.GetUserSummaryHandler,
+	)
+
+	return apc
+}
+
+func (apc *AdminProjectionController) GetUserSummaryHandler(c *gin.Context) {
+	ok, userUUID := validator.IsUUID(c.Param("user_id"))
+	if !ok {
+		c.JSON(
+			http.StatusBadRequest,
+			gin.H{"error": "user_id must be a valid UUID"},
+		)
+		return
+	}
+	days, err := validator.ParseSummaryDays(c.Query("days"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	s, err := apc.projectionService.UserSummary(c.Request.Context(), userUUID, days)
+	if err != nil {
+		c.JSON(
+			http.StatusInternalServerError,
+			gin.H{"error": "failed to get the user summary"},
+		)
+		apc.logger.Error("UserSummary() error", zap.Error(err), zap.Stringer("user_uuid", userUUID))
+		return
+	}
+	if s == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "no event of the user is stored"})
+		return
+	}
+
+	c.JSON(http.StatusOK, projection.ToResponseUserSummary(*s))
+}
@@ -0,0 +1,114 @@
+package rest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"user-manager-api/internal/domain/projection"
+	domain "user-manager-api/internal/domain/user"
+	jwtSvc "user-manager-api/internal/infrastructure/jwt"
+	"user-manager-api/internal/infrastructure/mq"
+)
+
+type fakeProjectionService struct {
+	UserSummaryFunc func(ctx context.Context, userUUID uuid.UUID, days int) (*projection.UserSummary, error)
+}
+
+func (f *fakeProjectionService) Record(context.Context, mq.Event) error {
+	return errors.New("not used")
+}
+
+func (f *fakeProjectionService) Rebuild(context.Context) (int64, error) {
+	return 0, errors.New("not used")
+}
+
+func (f *fakeProjectionService) UserSummary(ctx context.Context, userUUID uuid.UUID, days int) (*projection.UserSummary, error) {
+	if f.UserSummaryFunc == nil {
+		return nil, errors.New("not used")
+	}
+	return f.UserSummaryFunc(ctx, userUUID, days)
+}
+
+func setupAdminProjectionRouter(t *testing.T, s *fakeProjectionService) (*gin.Engine, *jwtSvc.Service) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	j := jwtSvc.New("test-secret")
+	NewAdminProjectionController(r, s, zap.NewNop(), j)
+
+	return r, j
+}
+
+func TestAdminProjectionController_GetUserSummaryHandler(t *testing.T) {
+	userUUID := uuid.New()
+	path := "/api/v1/admin/users/" + userUUID.String() + "/summary"
+	signedUp := time.Date(2026, 10, 1, 8, 0, 0, 0, time.UTC)
+	summary := &projection.UserSummary{
+		UserUUID:    userUUID,
+		SignedUpAt:  &signedUp,
+		Logins:      2,
+		LastEventAt: signedUp,
+		Files:       projection.Files{Count: 3, Changes: 4},
+		Activity:    []projection.DailyActivity{{Day: time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC), Events: 5, Logins: 2}},
+	}
+
+	tests := []struct {
+		name       string
+		path       string
+		role       string
+		summary    *projection.UserSummary
+		err        error
+		wantDays   int
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name:       "200 default days",
+			path:       path,
+			role:       domain.RoleAdmin,
+			summary:    summary,
+			wantDays:   30,
+			wantStatus: http.StatusOK,
+			wantBody: `{"user_uuid":"` + userUUID.String() + `","signed_up_at":"2026-10-01T08:00:00Z","updated_at":null,` +
+				`"deleted_at":null,"email_changes":0,"logins":2,"failed_logins":0,"last_login_at":null,` +
+				`"last_event_at":"2026-10-01T08:00:00Z","files":{"count":3,"changes":4,"last_changed_at":null},` +
+				`"activity":[{"day":"2026-10-01","events":5,"logins":2,"failed_logins":0}]}`,
+		},
+		{name: "200 days", path: path + "?days=7", role: domain.RoleAdmin, summary: summary, wantDays: 7, wantStatus: http.StatusOK},
+		{name: "400 days", path: path + "?days=0", role: domain.RoleAdmin, wantStatus: http.StatusBadRequest},
+		{name: "400 user_id", path: "/api/v1/admin/users/42/summary", role: domain.RoleAdmin, wantStatus: http.StatusBadRequest},
+		{name: "403 worker", path: path, role: domain.RoleWorker, wantStatus: http.StatusForbidden},
+		{name: "404 no events", path: path, role: domain.RoleAdmin, wantDays: 30, wantStatus: http.StatusNotFound},
+		{name: "500", path: path, role: domain.RoleAdmin, err: errors.New("db down"), wantDays: 30, wantStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotDays int
+			r, j := setupAdminProjectionRouter(t, &fakeProjectionService{
+				UserSummaryFunc: func(_ context.Context, u uuid.UUID, days int) (*projection.UserSummary, error) {
+					require.Equal(t, userUUID, u)
+					gotDays = days
+					return tt.summary, tt.err
+				},
+			})
+
+			rr := doReq(t, r, http.MethodGet, tt.path, nil, adminStatsHeaders(t, j, tt.role))
+			require.Equal(t, tt.wantStatus, rr.Code, rr.Body.String())
+			assert.Equal(t, tt.wantDays, gotDays)
+			if tt.wantBody != "" {
+				assert.JSONEq(t, tt.wantBody, rr.Body.String())
+			}
+		})
+	}
+}
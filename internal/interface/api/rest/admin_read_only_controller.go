@@ -0,0 +1,89 @@
+package rest
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"user-manager-api/internal/application/ports"
+	"user-manager-api/internal/interface/api/rest/dto/mode"
+	"user-manager-api/internal/interface/api/rest/middleware"
+	"user-manager-api/internal/interface/api/rest/validator"
+)
+
+// AdminReadOnlyController - the read-only mode toggle, its route is exempt
+// from the mode: it must be possible to turn it off
+type AdminReadOnlyController struct {
+	readOnlyService ports.ReadOnlyService
+	logger          *zap.Logger
+}
+
+func NewAdminReadOnlyController(
+	r *gin.Engine,
+	readOnlyService ports.ReadOnlyService,
+	logger *zap.Logger,
+	tokenService ports.TokenService,
+) *AdminReadOnlyController {
+	aroc := &AdminReadOnlyController{
+		readOnlyService: readOnlyService,
+		logger:          logger,
+	}
+
+	r.GET(
+		RouteAdminReadOnly,
+		middleware.AuthMiddleware(tokenService),
+		middleware.RequireAdmin(),
+		aroc.GetReadOnlyHandler,
+	)
+	r.PUT(
+		RouteAdminReadOnly,
+		middleware.AuthMiddleware(tokenService),
+		middleware.RequireAdmin(),
+		aroc.PutReadOnlyHandler,
+	)
+
+	return aroc
+}
+
+func (aroc *AdminReadOnlyController) GetReadOnlyHandler(c *gin.Context) {
+	ro, err := aroc.readOnlyService.Status(c.Request.Context())
+	if err != nil {
+		c.JSON(
+			http.StatusInternalServerError,
+			gin.H{"error": "failed to get the read-only mode"},
+		)
+		aroc.logger.Error("Status() error", zap.Error(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, mode.ToResponseReadOnly(*ro))
+}
+
+func (aroc *AdminReadOnlyController) PutReadOnlyHandler(c *gin.Context) {
+	ok, actor := validator.IsUUID(c.GetString(middleware.CtxUserID))
+	if !ok {
+		c.JSON(
+			http.StatusUnauthorized,
+			gin.H{"error": "invalid token"},
+		)
+		return
+	}
+	req, ok := BindAndValidate(c, validator.ValidateReadOnly)
+	if !ok {
+		return
+	}
+
+	ro, err := aroc.readOnlyService.Set(c.Request.Context(), actor, *req.Enabled, strings.TrimSpace(req.Reason))
+	if err != nil {
+		c.JSON(
+			http.StatusInternalServerError,
+			gin.H{"error": "failed to set the read-only mode"},
+		)
+		aroc.logger.Error("Set() error", zap.Error(err), zap.Bool("enabled", *req.Enabled))
+		return
+	}
+
+	c.JSON(http.StatusOK, mode.ToResponseReadOnly(*ro))
+}
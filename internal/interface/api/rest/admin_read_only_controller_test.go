@@ -0,0 +1,169 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"user-manager-api/internal/domain/mode"
+	domain "user-manager-api/internal/domain/user"
+	jwtSvc "user-manager-api/internal/infrastructure/jwt"
+	dto "user-manager-api/internal/interface/api/rest/dto/mode"
+)
+
+type fakeReadOnlyService struct {
+	enabled    bool
+	StatusFunc func(ctx context.Context) (*mode.ReadOnly, error)
+	SetFunc    func(ctx context.Context, actor uuid.UUID, enabled bool, reason string) (*mode.ReadOnly, error)
+}
+
+func (f *fakeReadOnlyService) Enabled() bool { return f.enabled }
+
+func (f *fakeReadOnlyService) Status(ctx context.Context) (*mode.ReadOnly, error) {
+	if f.StatusFunc == nil {
+		return nil, errors.New("not used")
+	}
+	return f.StatusFunc(ctx)
+}
+
+func (f *fakeReadOnlyService) Set(ctx context.Context, actor uuid.UUID, enabled bool, reason string) (*mode.ReadOnly, error) {
+	if f.SetFunc == nil {
+		return nil, errors.New("not used")
+	}
+	return f.SetFunc(ctx, actor, enabled, reason)
+}
+
+func (f *fakeReadOnlyService) Worker(context.Context) {}
+
+func setupAdminReadOnlyRouter(t *testing.T, s *fakeReadOnlyService) (*gin.Engine, *jwtSvc.Service) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	j := jwtSvc.New("test-secret")
+	NewAdminReadOnlyController(r, s, zap.NewNop(), j)
+
+	return r, j
+}
+
+func TestAdminReadOnlyController_GetReadOnlyHandler(t *testing.T) {
+	updated := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	admin := uuid.New()
+	adminID := admin.String()
+
+	type tc struct {
+		name       string
+		role       string
+		status     func(context.Context) (*mode.ReadOnly, error)
+		wantStatus int
+		want       *dto.ReadOnly
+	}
+	tests := []tc{
+		{
+			name: "200",
+			role: domain.RoleAdmin,
+			status: func(context.Context) (*mode.ReadOnly, error) {
+				return &mode.ReadOnly{Enabled: true, Reason: "db failover", UpdatedBy: &admin, UpdatedAt: updated}, nil
+			},
+			wantStatus: http.StatusOK,
+			want:       &dto.ReadOnly{Enabled: true, Reason: "db failover", UpdatedBy: &adminID, UpdatedAt: updated},
+		},
+		{
+			name: "200 forced, never toggled",
+			role: domain.RoleAdmin,
+			status: func(context.Context) (*mode.ReadOnly, error) {
+				return &mode.ReadOnly{Forced: mode.ForcedSchema, UpdatedAt: updated}, nil
+			},
+			wantStatus: http.StatusOK,
+			want:       &dto.ReadOnly{Forced: mode.ForcedSchema, UpdatedAt: updated},
+		},
+		{
+			name:       "403 worker",
+			role:       domain.RoleWorker,
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name: "500",
+			role: domain.RoleAdmin,
+			status: func(context.Context) (*mode.ReadOnly, error) {
+				return nil, errors.New("db down")
+			},
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, j := setupAdminReadOnlyRouter(t, &fakeReadOnlyService{StatusFunc: tt.status})
+
+			rr := doReq(t, r, http.MethodGet, RouteAdminReadOnly, nil, adminStatsHeaders(t, j, tt.role))
+			require.Equal(t, tt.wantStatus, rr.Code, rr.Body.String())
+			if tt.want == nil {
+				return
+			}
+
+			var resp dto.ReadOnly
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+			assert.Equal(t, *tt.want, resp)
+		})
+	}
+}
+
+func TestAdminReadOnlyController_PutReadOnlyHandler(t *testing.T) {
+	updated := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+
+	type tc struct {
+		name       string
+		body       any
+		setErr     error
+		wantStatus int
+		wantSet    bool
+		wantReason string
+	}
+	tests := []tc{
+		{"200 enable", map[string]any{"enabled": true, "reason": " db failover "}, nil, http.StatusOK, true, "db failover"},
+		{"200 disable", map[string]any{"enabled": false}, nil, http.StatusOK, true, ""},
+		{"400 without reason", map[string]any{"enabled": true}, nil, http.StatusBadRequest, false, ""},
+		{"400 without enabled", map[string]any{"reason": "db failover"}, nil, http.StatusBadRequest, false, ""},
+		{"400 malformed", "{", nil, http.StatusBadRequest, false, ""},
+		{"500", map[string]any{"enabled": false}, errors.New("db down"), http.StatusInternalServerError, true, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called := false
+			s := &fakeReadOnlyService{SetFunc: func(_ context.Context, actor uuid.UUID, enabled bool, reason string) (*mode.ReadOnly, error) {
+				called = true
+				assert.NotEqual(t, uuid.Nil, actor)
+				assert.Equal(t, tt.wantReason, reason)
+				if tt.setErr != nil {
+					return nil, tt.setErr
+				}
+				return &mode.ReadOnly{Enabled: enabled, Reason: reason, UpdatedBy: &actor, UpdatedAt: updated}, nil
+			}}
+			r, j := setupAdminReadOnlyRouter(t, s)
+
+			rr := doReq(t, r, http.MethodPut, RouteAdminReadOnly, tt.body, adminStatsHeaders(t, j, domain.RoleAdmin))
+			require.Equal(t, tt.wantStatus, rr.Code, rr.Body.String())
+			assert.Equal(t, tt.wantSet, called)
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+
+			var resp dto.ReadOnly
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+			assert.Equal(t, tt.body.(map[string]any)["enabled"], resp.Enabled)
+			assert.Equal(t, tt.wantReason, resp.Reason)
+			require.NotNil(t, resp.UpdatedBy)
+		})
+	}
+}
@@ -0,0 +1,186 @@
+package rest
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"user-manager-api/internal/application/ports"
+	"user-manager-api/internal/application/services"
+	domainFile "user-manager-api/internal/domain/user_file"
+	"user-manager-api/internal/interface/api/rest/dto/user_file"
+	"user-manager-api/internal/interface/api/rest/middleware"
+	"user-manager-api/internal/interface/api/rest/validator"
+)
+
+// AdminRetentionController - how long the files are kept: the rules by mime
+// type or tag and the per-file overrides with the legal holds
+type AdminRetentionController struct {
+	fileRetentionService ports.FileRetentionService
+	logger               *zap.Logger
+}
+
+func NewAdminRetentionController(
+	r *gin.Engine,
+	fileRetentionService ports.FileRetentionService,
+	logger *zap.Logger,
+	tokenService ports.TokenService,
+) *AdminRetentionController {
+	arc := &AdminRetentionController{
+		fileRetentionService: fileRetentionService,
+		logger:               logger,
+	}
+
+	r.GET(
+		RouteAdminRetentionRules,
+		middleware.AuthMiddleware(tokenService),
+		middleware.RequireAdmin(),
+		arc.GetRulesHandler,
+	)
+	r.POST(
+		RouteAdminRetentionRules,
+		middleware.AuthMiddleware(tokenService),
+		middleware.RequireAdmin(),
+		arc.PostRuleHandler,
+	)
+	r.DELETE(
+		RouteAdminRetentionRule,
+		middleware.AuthMiddleware(tokenService),
+		middleware.RequireAdmin(),
+		arc.DeleteRuleHandler,
+	)
+	r.PUT(
+		RouteAdminFileRetention,
+		middleware.AuthMiddleware(tokenService),
+		middleware.RequireAdmin(),
+		arc.PutFileRetentionHandler,
+	)
+
+	return arc
+}
+
+func (arc *AdminRetentionController) GetRulesHandler(c *gin.Context) {
+	rules, err := arc.fileRetentionService.Rules(c.Request.Context())
+	if err != nil {
+		c.JSON(
+			http.StatusInternalServerError,
+			gin.H{"error": "failed to get retention rules"},
+		)
+		arc.logger.Error("Rules() error", zap.Error(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, user_file.ToResponseRetentionRules(rules))
+}
+
+func (arc *AdminRetentionController) PostRuleHandler(c *gin.Context) {
+	actor, ok := arc.actor(c)
+	if !ok {
+		return
+	}
+	req, ok := BindAndValidate(c, validator.ValidateRetentionRule)
+	if !ok {
+		return
+	}
+
+	rule, err := arc.fileRetentionService.CreateRule(c.Request.Context(), actor, user_file.ToDomainRetentionRule(req))
+	if err != nil {
+		c.JSON(
+			http.StatusInternalServerError,
+			gin.H{"error": "failed to create the retention rule"},
+		)
+		arc.logger.Error("CreateRule() error", zap.Error(err))
+		return
+	}
+
+	c.JSON(http.StatusCreated, user_file.ToResponseRetentionRule(*rule))
+}
+
+func (arc *AdminRetentionController) DeleteRuleHandler(c *gin.Context) {
+	actor, ok := arc.actor(c)
+	if !ok {
+		return
+	}
+	ok, ruleUUID := validator.IsUUID(c.Param("rule_id"))
+	if !ok {
+		c.JSON(
+			http.StatusBadRequest,
+			gin.H{"error": "rule_id must be a valid UUID"},
+		)
+		return
+	}
+
+	removed, err := arc.fileRetentionService.DeleteRule(c.Request.Context(), actor, ruleUUID)
+	if err != nil {
+		c.JSON(
+			http.StatusInternalServerError,
+			gin.H{"error": "failed to delete the retention rule"},
+		)
+		arc.logger.Error("DeleteRule() error", zap.Error(err), zap.Stringer("rule_uuid", ruleUUID))
+		return
+	}
+	if !removed {
+		c.JSON(
+			http.StatusNotFound,
+			gin.H{"error": "retention rule not found"},
+		)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (arc *AdminRetentionController) PutFileRetentionHandler(c *gin.Context) {
+	actor, ok := arc.actor(c)
+	if !ok {
+		return
+	}
+	ok, fileUUID := validator.IsUUID(c.Param("file_id"))
+	if !ok {
+		c.JSON(
+			http.StatusBadRequest,
+			gin.H{"error": "file_id must be a valid UUID"},
+		)
+		return
+	}
+	req, ok := BindAndValidate(c, validator.ValidateFileRetention)
+	if !ok {
+		return
+	}
+
+	ret := domainFile.Retention{RetainUntil: req.RetainUntil, LegalHold: *req.LegalHold}
+	if err := arc.fileRetentionService.SetFileRetention(c.Request.Context(), actor, fileUUID, ret); err != nil {
+		if errors.Is(err, services.ErrUserFileNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(
+			http.StatusInternalServerError,
+			gin.H{"error": "failed to set the file retention"},
+		)
+		arc.logger.Error("SetFileRetention() error", zap.Error(err), zap.Stringer("file_uuid", fileUUID))
+		return
+	}
+
+	c.JSON(http.StatusOK, user_file.FileRetention{
+		FileUUID:    fileUUID,
+		RetainUntil: req.RetainUntil,
+		LegalHold:   *req.LegalHold,
+	})
+}
+
+func (arc *AdminRetentionController) actor(c *gin.Context) (uuid.UUID, bool) {
+	ok, actor := validator.IsUUID(c.GetString(middleware.CtxUserID))
+	if !ok {
+		c.JSON(
+			http.StatusUnauthorized,
+			gin.H{"error": "invalid token"},
+		)
+		return uuid.Nil, false
+	}
+
+	return actor, true
+}
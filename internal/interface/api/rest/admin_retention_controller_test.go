@@ -0,0 +1,208 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"user-manager-api/internal/application/services"
+	domain "user-manager-api/internal/domain/user"
+	domainFile "user-manager-api/internal/domain/user_file"
+	jwtSvc "user-manager-api/internal/infrastructure/jwt"
+	dto "user-manager-api/internal/interface/api/rest/dto/user_file"
+)
+
+type fakeFileRetentionService struct {
+	RulesFunc            func(ctx context.Context) (domainFile.RetentionRules, error)
+	CreateRuleFunc       func(ctx context.Context, actor uuid.UUID, r domainFile.RetentionRule) (*domainFile.RetentionRule, error)
+	DeleteRuleFunc       func(ctx context.Context, actor, ruleUUID uuid.UUID) (bool, error)
+	SetFileRetentionFunc func(ctx context.Context, actor, fileUUID uuid.UUID, r domainFile.Retention) error
+}
+
+func (f *fakeFileRetentionService) Rules(ctx context.Context) (domainFile.RetentionRules, error) {
+	if f.RulesFunc == nil {
+		return nil, errors.New("not used")
+	}
+	return f.RulesFunc(ctx)
+}
+
+func (f *fakeFileRetentionService) CreateRule(ctx context.Context, actor uuid.UUID, r domainFile.RetentionRule) (*domainFile.RetentionRule, error) {
+	if f.CreateRuleFunc == nil {
+		return nil, errors.New("not used")
+	}
+	return f.CreateRuleFunc(ctx, actor, r)
+}
+
+func (f *fakeFileRetentionService) DeleteRule(ctx context.Context, actor, ruleUUID uuid.UUID) (bool, error) {
+	if f.DeleteRuleFunc == nil {
+		return false, errors.New("not used")
+	}
+	return f.DeleteRuleFunc(ctx, actor, ruleUUID)
+}
+
+func (f *fakeFileRetentionService) SetFileRetention(ctx context.Context, actor, fileUUID uuid.UUID, r domainFile.Retention) error {
+	if f.SetFileRetentionFunc == nil {
+		return errors.New("not used")
+	}
+	return f.SetFileRetentionFunc(ctx, actor, fileUUID, r)
+}
+
+func (f *fakeFileRetentionService) PurgeExpired(context.Context) (int, error) {
+	return 0, errors.New("not used")
+}
+
+func setupAdminRetentionRouter(t *testing.T, s *fakeFileRetentionService) (*gin.Engine, *jwtSvc.Service) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	j := jwtSvc.New("test-secret")
+	NewAdminRetentionController(r, s, zap.NewNop(), j)
+
+	return r, j
+}
+
+func TestAdminRetentionController_Rules(t *testing.T) {
+	created := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	ruleUUID := uuid.New()
+
+	t.Run("200 list", func(t *testing.T) {
+		r, j := setupAdminRetentionRouter(t, &fakeFileRetentionService{RulesFunc: func(context.Context) (domainFile.RetentionRules, error) {
+			return domainFile.RetentionRules{{UUID: ruleUUID, Tag: "payslip", Days: 2555, CreatedAt: created}}, nil
+		}})
+
+		rr := doReq(t, r, http.MethodGet, RouteAdminRetentionRules, nil, adminStatsHeaders(t, j, domain.RoleAdmin))
+		require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+		var resp dto.RetentionRulesResponse
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+		assert.Equal(t, []dto.RetentionRule{{UUID: ruleUUID, Tag: "payslip", Days: 2555, CreatedAt: created}}, resp.Data)
+	})
+
+	t.Run("403 worker", func(t *testing.T) {
+		r, j := setupAdminRetentionRouter(t, &fakeFileRetentionService{})
+		rr := doReq(t, r, http.MethodGet, RouteAdminRetentionRules, nil, adminStatsHeaders(t, j, domain.RoleWorker))
+		require.Equal(t, http.StatusForbidden, rr.Code)
+	})
+
+	t.Run("201 create normalized", func(t *testing.T) {
+		var got domainFile.RetentionRule
+		r, j := setupAdminRetentionRouter(t, &fakeFileRetentionService{CreateRuleFunc: func(_ context.Context, _ uuid.UUID, rule domainFile.RetentionRule) (*domainFile.RetentionRule, error) {
+			got = rule
+			rule.UUID, rule.CreatedAt = ruleUUID, created
+			return &rule, nil
+		}})
+
+		rr := doReq(t, r, http.MethodPost, RouteAdminRetentionRules, map[string]any{"mime_type": " Image/* ", "days": 30}, adminStatsHeaders(t, j, domain.RoleAdmin))
+		require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+		assert.Equal(t, domainFile.RetentionRule{MimeType: "image/*", Days: 30}, got)
+		assert.JSONEq(t, `{"uuid":"`+ruleUUID.String()+`","mime_type":"image/*","days":30,"created_at":"2026-10-01T00:00:00Z"}`, rr.Body.String())
+	})
+
+	t.Run("400 create", func(t *testing.T) {
+		r, j := setupAdminRetentionRouter(t, &fakeFileRetentionService{})
+		rr := doReq(t, r, http.MethodPost, RouteAdminRetentionRules, map[string]any{"tag": "tmp", "mime_type": "image/*", "days": 30}, adminStatsHeaders(t, j, domain.RoleAdmin))
+		require.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+
+	for name, tt := range map[string]struct {
+		id         string
+		removed    bool
+		wantStatus int
+	}{
+		"204 delete":    {ruleUUID.String(), true, http.StatusNoContent},
+		"404 delete":    {ruleUUID.String(), false, http.StatusNotFound},
+		"400 delete id": {"42", false, http.StatusBadRequest},
+	} {
+		t.Run(name, func(t *testing.T) {
+			r, j := setupAdminRetentionRouter(t, &fakeFileRetentionService{DeleteRuleFunc: func(_ context.Context, _, id uuid.UUID) (bool, error) {
+				assert.Equal(t, ruleUUID, id)
+				return tt.removed, nil
+			}})
+			rr := doReq(t, r, http.MethodDelete, RouteAdminRetentionRules+"/"+tt.id, nil, adminStatsHeaders(t, j, domain.RoleAdmin))
+			require.Equal(t, tt.wantStatus, rr.Code, rr.Body.String())
+		})
+	}
+}
+
+func TestAdminRetentionController_PutFileRetentionHandler(t *testing.T) {
+	fileUUID := uuid.New()
+	until := time.Now().Add(24 * time.Hour).UTC().Truncate(time.Second)
+
+	tests := []struct {
+		name       string
+		fileID     string
+		body       any
+		mockErr    error
+		wantStatus int
+		want       domainFile.Retention
+	}{
+		{
+			name:       "200 hold",
+			fileID:     fileUUID.String(),
+			body:       map[string]any{"legal_hold": true},
+			wantStatus: http.StatusOK,
+			want:       domainFile.Retention{LegalHold: true},
+		},
+		{
+			name:       "200 override",
+			fileID:     fileUUID.String(),
+			body:       map[string]any{"retain_until": until, "legal_hold": false},
+			wantStatus: http.StatusOK,
+			want:       domainFile.Retention{RetainUntil: &until},
+		},
+		{
+			name:       "400 file_id",
+			fileID:     "42",
+			body:       map[string]any{"legal_hold": true},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "400 no hold",
+			fileID:     fileUUID.String(),
+			body:       map[string]any{"retain_until": until},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "404 file",
+			fileID:     fileUUID.String(),
+			body:       map[string]any{"legal_hold": true},
+			mockErr:    services.ErrUserFileNotFound,
+			wantStatus: http.StatusNotFound,
+			want:       domainFile.Retention{LegalHold: true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, j := setupAdminRetentionRouter(t, &fakeFileRetentionService{SetFileRetentionFunc: func(_ context.Context, actor, id uuid.UUID, ret domainFile.Retention) error {
+				assert.NotEqual(t, uuid.Nil, actor)
+				assert.Equal(t, fileUUID, id)
+				if tt.want.RetainUntil != nil {
+					require.NotNil(t, ret.RetainUntil)
+					assert.True(t, tt.want.RetainUntil.Equal(*ret.RetainUntil))
+					ret.RetainUntil = tt.want.RetainUntil
+				}
+				assert.Equal(t, tt.want, ret)
+				return tt.mockErr
+			}})
+
+			rr := doReq(t, r, http.MethodPut, RouteAdminFiles+"/"+tt.fileID+"/retention", tt.body, adminStatsHeaders(t, j, domain.RoleAdmin))
+			require.Equal(t, tt.wantStatus, rr.Code, rr.Body.String())
+			if tt.wantStatus == http.StatusOK {
+				var got dto.FileRetention
+				require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &got))
+				assert.Equal(t, fileUUID, got.FileUUID)
+				assert.Equal(t, tt.want.LegalHold, got.LegalHold)
+			}
+		})
+	}
+}
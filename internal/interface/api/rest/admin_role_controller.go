@@ -0,0 +1,69 @@
+package rest
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"user-manager-api/internal/application/ports"
+	"user-manager-api/internal/interface/api/rest/dto/user"
+	"user-manager-api/internal/interface/api/rest/middleware"
+	"user-manager-api/internal/interface/api/rest/validator"
+)
+
+// AdminRoleController - bulk role assignment for the periodic permission reviews
+type AdminRoleController struct {
+	roleService ports.RoleService
+	logger      *zap.Logger
+}
+
+func NewAdminRoleController(
+	r *gin.Engine,
+	roleService ports.RoleService,
+	logger *zap.Logger,
+	tokenService ports.TokenService,
+) *AdminRoleController {
+	arc := &AdminRoleController{
+		roleService: roleService,
+		logger:      logger,
+	}
+
+	r.POST(
+		RouteAdminUserRoles,
+		middleware.AuthMiddleware(tokenService),
+		middleware.RequireAdmin(),
+		arc.AssignRolesHandler,
+	)
+
+	return arc
+}
+
+// AssignRolesHandler - 200 with a result per assignment, the failed ones(unknown
+// users, the last admin demotions) do not fail the request.
+func (arc *AdminRoleController) AssignRolesHandler(c *gin.Context) {
+	ok, actor := validator.IsUUID(c.GetString(middleware.CtxUserID))
+	if !ok {
+		c.JSON(
+			http.StatusUnauthorized,
+			gin.H{"error": "invalid token"},
+		)
+		return
+	}
+	req, ok := BindAndValidate(c, validator.ValidateRoleAssignments)
+	if !ok {
+		return
+	}
+
+	results, err := arc.roleService.AssignRoles(c.Request.Context(), actor, user.ToDomainRoleChanges(req))
+	if err != nil {
+		c.JSON(
+			http.StatusInternalServerError,
+			gin.H{"error": "failed to assign roles"},
+		)
+		arc.logger.Error("AssignRoles() error", zap.Error(err), zap.Stringer("actor_uuid", actor))
+		return
+	}
+
+	c.JSON(http.StatusOK, user.ToRolesResponse(results))
+}
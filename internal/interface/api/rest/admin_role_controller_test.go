@@ -0,0 +1,139 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"user-manager-api/internal/domain/token"
+	domain "user-manager-api/internal/domain/user"
+	jwtSvc "user-manager-api/internal/infrastructure/jwt"
+	"user-manager-api/internal/interface/api/rest/dto/user"
+)
+
+type fakeRoleService struct {
+	AssignRolesFunc func(ctx context.Context, actor domain.UUID, changes []domain.RoleChange) ([]domain.RoleChangeResult, error)
+}
+
+func (f *fakeRoleService) AssignRoles(ctx context.Context, actor domain.UUID, changes []domain.RoleChange) ([]domain.RoleChangeResult, error) {
+	if f.AssignRolesFunc == nil {
+		return nil, errors.New("not used")
+	}
+	return f.AssignRolesFunc(ctx, actor, changes)
+}
+
+func (f *fakeRoleService) CurrentRole(context.Context, *token.Claims) (string, error) {
+	return "", errors.New("not used")
+}
+
+func setupAdminRoleRouter(t *testing.T, rs *fakeRoleService) (*gin.Engine, *jwtSvc.Service) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	j := jwtSvc.New("test-secret")
+	NewAdminRoleController(r, rs, zap.NewNop(), j)
+	// shares the path prefix with the route of a user
+	NewAdminController(r, zap.NewNop(), &fakeImpersonationService{}, &fakeCredentialService{}, j)
+
+	return r, j
+}
+
+func TestAdminRoleController_AssignRolesHandler(t *testing.T) {
+	adminID := uuid.New()
+	promoted := uuid.New()
+	lastAdmin := uuid.New()
+	missing := uuid.New()
+	body := user.RolesRequest{Assignments: []user.RoleAssignment{
+		{UserID: promoted.String(), Role: "admin"},
+		{UserID: lastAdmin.String(), Role: "worker"},
+		{UserID: missing.String(), Role: "worker"},
+	}}
+
+	type tc struct {
+		name       string
+		role       string
+		body       any
+		assign     func(ctx context.Context, actor domain.UUID, changes []domain.RoleChange) ([]domain.RoleChangeResult, error)
+		wantStatus int
+		wantErr    string
+	}
+
+	cases := []tc{
+		{
+			name: "200 per-item results",
+			role: domain.RoleAdmin,
+			body: body,
+			assign: func(_ context.Context, actor domain.UUID, changes []domain.RoleChange) ([]domain.RoleChangeResult, error) {
+				if actor != adminID || len(changes) != 3 || changes[0] != (domain.RoleChange{UUID: promoted, Role: "admin"}) {
+					return nil, errors.New("unexpected args")
+				}
+				return []domain.RoleChangeResult{
+					{UUID: promoted, Role: "admin", PrevRole: "worker", Status: domain.RoleChanged},
+					{UUID: lastAdmin, Role: "worker", PrevRole: "admin", Status: domain.RoleLastAdmin},
+					{UUID: missing, Role: "worker", Status: domain.RoleUserNotFound},
+				}, nil
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "403 worker",
+			role:       domain.RoleWorker,
+			body:       body,
+			wantStatus: http.StatusForbidden,
+			wantErr:    "admin role required",
+		},
+		{
+			name:       "400 invalid assignment",
+			role:       domain.RoleAdmin,
+			body:       user.RolesRequest{Assignments: []user.RoleAssignment{{UserID: "bad", Role: "admin"}}},
+			wantStatus: http.StatusBadRequest,
+			wantErr:    errInvalidRequestBody,
+		},
+		{
+			name: "500",
+			role: domain.RoleAdmin,
+			body: body,
+			assign: func(context.Context, domain.UUID, []domain.RoleChange) ([]domain.RoleChangeResult, error) {
+				return nil, errors.New("db down")
+			},
+			wantStatus: http.StatusInternalServerError,
+			wantErr:    "failed to assign roles",
+		},
+	}
+
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			r, j := setupAdminRoleRouter(t, &fakeRoleService{AssignRolesFunc: tt.assign})
+			tok, err := j.GenerateToken(adminID.String(), tt.role, time.Minute)
+			require.NoError(t, err)
+
+			rr := doReq(t, r, http.MethodPost, RouteAdminUserRoles, tt.body, map[string]string{"Authorization": "Bearer " + tok})
+			require.Equal(t, tt.wantStatus, rr.Code, rr.Body.String())
+
+			if tt.wantErr != "" {
+				var resp map[string]any
+				require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+				assert.Equal(t, tt.wantErr, resp["error"])
+				return
+			}
+			var resp user.RolesResponse
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+			assert.Equal(t, []user.RoleResult{
+				{UserID: promoted, Role: "admin", PreviousRole: "worker", Result: "updated"},
+				{UserID: lastAdmin, Role: "worker", PreviousRole: "admin", Result: "last_admin"},
+				{UserID: missing, Role: "worker", Result: "not_found"},
+			}, resp.Data)
+		})
+	}
+}
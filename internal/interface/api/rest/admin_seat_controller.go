@@ -0,0 +1,128 @@
+package rest
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"user-manager-api/internal/application/ports"
+	"user-manager-api/internal/interface/api/rest/dto/usage"
+	"user-manager-api/internal/interface/api/rest/middleware"
+	"user-manager-api/internal/interface/api/rest/validator"
+)
+
+// AdminSeatController - the seat limits of the organizations(email domains)
+type AdminSeatController struct {
+	seatService ports.SeatService
+	logger      *zap.Logger
+}
+
+func NewAdminSeatController(
+	r *gin.Engine,
+	seatService ports.SeatService,
+	logger *zap.Logger,
+	tokenService ports.TokenService,
+) *AdminSeatController {
+	asc := &AdminSeatController{
+		seatService: seatService,
+		logger:      logger,
+	}
+
+	r.GET(
+		RouteAdminSeatLimits,
+		middleware.AuthMiddleware(tokenService),
+		middleware.RequireAdmin(),
+		asc.GetSeatLimitsHandler,
+	)
+	r.PUT(
+		RouteAdminSeatLimit,
+		middleware.AuthMiddleware(tokenService),
+		middleware.RequireAdmin(),
+		asc.PutSeatLimitHandler,
+	)
+	r.DELETE(
+		RouteAdminSeatLimit,
+		middleware.AuthMiddleware(tokenService),
+		middleware.RequireAdmin(),
+		asc.DeleteSeatLimitHandler,
+	)
+
+	return asc
+}
+
+func (asc *AdminSeatController) GetSeatLimitsHandler(c *gin.Context) {
+	limits, err := asc.seatService.SeatLimits(c.Request.Context())
+	if err != nil {
+		c.JSON(
+			http.StatusInternalServerError,
+			gin.H{"error": "failed to get seat limits"},
+		)
+		asc.logger.Error("SeatLimits() error", zap.Error(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, usage.ToSeatLimitsResponseData(limits))
+}
+
+func (asc *AdminSeatController) PutSeatLimitHandler(c *gin.Context) {
+	org, ok := asc.orgParam(c)
+	if !ok {
+		return
+	}
+	req, ok := BindAndValidate(c, validator.ValidateSeatLimit)
+	if !ok {
+		return
+	}
+
+	l, err := asc.seatService.SetSeatLimit(c.Request.Context(), org, uint32(*req.Seats))
+	if err != nil {
+		c.JSON(
+			http.StatusInternalServerError,
+			gin.H{"error": "failed to set the seat limit"},
+		)
+		asc.logger.Error("SetSeatLimit() error", zap.Error(err), zap.String("org", org))
+		return
+	}
+
+	c.JSON(http.StatusOK, usage.ToResponseSeatLimit(*l))
+}
+
+func (asc *AdminSeatController) DeleteSeatLimitHandler(c *gin.Context) {
+	org, ok := asc.orgParam(c)
+	if !ok {
+		return
+	}
+
+	removed, err := asc.seatService.RemoveSeatLimit(c.Request.Context(), org)
+	if err != nil {
+		c.JSON(
+			http.StatusInternalServerError,
+			gin.H{"error": "failed to remove the seat limit"},
+		)
+		asc.logger.Error("RemoveSeatLimit() error", zap.Error(err), zap.String("org", org))
+		return
+	}
+	if !removed {
+		c.JSON(
+			http.StatusNotFound,
+			gin.H{"error": "seat limit not found"},
+		)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (asc *AdminSeatController) orgParam(c *gin.Context) (string, bool) {
+	org, err := validator.ParseOrg(c.Param("org"))
+	if err != nil {
+		c.JSON(
+			http.StatusBadRequest,
+			gin.H{"error": err.Error()},
+		)
+		return "", false
+	}
+
+	return org, true
+}
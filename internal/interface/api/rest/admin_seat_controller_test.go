@@ -0,0 +1,209 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"user-manager-api/internal/domain/usage"
+	domain "user-manager-api/internal/domain/user"
+	jwtSvc "user-manager-api/internal/infrastructure/jwt"
+	dto "user-manager-api/internal/interface/api/rest/dto/usage"
+)
+
+type fakeSeatService struct {
+	SeatLimitsFunc      func(ctx context.Context) ([]usage.SeatLimit, error)
+	SetSeatLimitFunc    func(ctx context.Context, org string, seats uint32) (*usage.SeatLimit, error)
+	RemoveSeatLimitFunc func(ctx context.Context, org string) (bool, error)
+}
+
+func (f *fakeSeatService) SeatLimits(ctx context.Context) ([]usage.SeatLimit, error) {
+	if f.SeatLimitsFunc == nil {
+		return nil, errors.New("not used")
+	}
+	return f.SeatLimitsFunc(ctx)
+}
+
+func (f *fakeSeatService) SetSeatLimit(ctx context.Context, org string, seats uint32) (*usage.SeatLimit, error) {
+	if f.SetSeatLimitFunc == nil {
+		return nil, errors.New("not used")
+	}
+	return f.SetSeatLimitFunc(ctx, org, seats)
+}
+
+func (f *fakeSeatService) RemoveSeatLimit(ctx context.Context, org string) (bool, error) {
+	if f.RemoveSeatLimitFunc == nil {
+		return false, errors.New("not used")
+	}
+	return f.RemoveSeatLimitFunc(ctx, org)
+}
+
+func setupAdminSeatRouter(t *testing.T, s *fakeSeatService) (*gin.Engine, *jwtSvc.Service) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	j := jwtSvc.New("test-secret")
+	NewAdminSeatController(r, s, zap.NewNop(), j)
+
+	return r, j
+}
+
+func TestAdminSeatController_GetSeatLimitsHandler(t *testing.T) {
+	updated := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("200", func(t *testing.T) {
+		r, j := setupAdminSeatRouter(t, &fakeSeatService{SeatLimitsFunc: func(context.Context) ([]usage.SeatLimit, error) {
+			return []usage.SeatLimit{{Org: "acme.com", Seats: 10, ActiveUsers: 10, UpdatedAt: updated}}, nil
+		}})
+
+		rr := doReq(t, r, http.MethodGet, RouteAdminSeatLimits, nil, adminStatsHeaders(t, j, domain.RoleAdmin))
+		require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+
+		var resp dto.SeatLimitsResponseData
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+		assert.Equal(t, []dto.SeatLimit{{Org: "acme.com", Seats: 10, ActiveUsers: 10, UpdatedAt: updated}}, resp.Data)
+	})
+
+	t.Run("200 none", func(t *testing.T) {
+		r, j := setupAdminSeatRouter(t, &fakeSeatService{SeatLimitsFunc: func(context.Context) ([]usage.SeatLimit, error) {
+			return nil, nil
+		}})
+
+		rr := doReq(t, r, http.MethodGet, RouteAdminSeatLimits, nil, adminStatsHeaders(t, j, domain.RoleAdmin))
+		require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+		assert.JSONEq(t, `{"data":[]}`, rr.Body.String())
+	})
+
+	t.Run("403 worker", func(t *testing.T) {
+		r, j := setupAdminSeatRouter(t, &fakeSeatService{})
+
+		rr := doReq(t, r, http.MethodGet, RouteAdminSeatLimits, nil, adminStatsHeaders(t, j, domain.RoleWorker))
+		require.Equal(t, http.StatusForbidden, rr.Code, rr.Body.String())
+	})
+
+	t.Run("500", func(t *testing.T) {
+		r, j := setupAdminSeatRouter(t, &fakeSeatService{SeatLimitsFunc: func(context.Context) ([]usage.SeatLimit, error) {
+			return nil, errors.New("db")
+		}})
+
+		rr := doReq(t, r, http.MethodGet, RouteAdminSeatLimits, nil, adminStatsHeaders(t, j, domain.RoleAdmin))
+		require.Equal(t, http.StatusInternalServerError, rr.Code, rr.Body.String())
+	})
+}
+
+func TestAdminSeatController_PutSeatLimitHandler(t *testing.T) {
+	updated := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	set := func(_ context.Context, org string, seats uint32) (*usage.SeatLimit, error) {
+		return &usage.SeatLimit{Org: org, Seats: seats, ActiveUsers: 3, UpdatedAt: updated}, nil
+	}
+
+	type tc struct {
+		name       string
+		org        string
+		body       any
+		role       string
+		set        func(ctx context.Context, org string, seats uint32) (*usage.SeatLimit, error)
+		wantStatus int
+		wantErr    string
+		wantOrg    string
+		wantSeats  uint32
+	}
+	cases := []tc{
+		{name: "200", org: "ACME.com", body: map[string]any{"seats": 25}, role: domain.RoleAdmin, set: set, wantStatus: http.StatusOK, wantOrg: "acme.com", wantSeats: 25},
+		{name: "200 zero seats", org: "acme.com", body: map[string]any{"seats": 0}, role: domain.RoleAdmin, set: set, wantStatus: http.StatusOK, wantOrg: "acme.com"},
+		{name: "403 worker", org: "acme.com", body: map[string]any{"seats": 1}, role: domain.RoleWorker, wantStatus: http.StatusForbidden, wantErr: "admin role required"},
+		{name: "400 bad org", org: "a%2Bb", body: map[string]any{"seats": 1}, role: domain.RoleAdmin, wantStatus: http.StatusBadRequest, wantErr: "org must be a domain name"},
+		{name: "400 missing seats", org: "acme.com", body: map[string]any{}, role: domain.RoleAdmin, wantStatus: http.StatusBadRequest, wantErr: errInvalidRequestBody},
+		{name: "400 negative seats", org: "acme.com", body: map[string]any{"seats": -1}, role: domain.RoleAdmin, wantStatus: http.StatusBadRequest, wantErr: errInvalidRequestBody},
+		{
+			name: "500",
+			org:  "acme.com",
+			body: map[string]any{"seats": 1},
+			role: domain.RoleAdmin,
+			set: func(context.Context, string, uint32) (*usage.SeatLimit, error) {
+				return nil, errors.New("db")
+			},
+			wantStatus: http.StatusInternalServerError,
+			wantErr:    "failed to set the seat limit",
+		},
+	}
+
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				gotOrg   string
+				gotSeats uint32
+			)
+			s := &fakeSeatService{}
+			if tt.set != nil {
+				s.SetSeatLimitFunc = func(ctx context.Context, org string, seats uint32) (*usage.SeatLimit, error) {
+					gotOrg, gotSeats = org, seats
+					return tt.set(ctx, org, seats)
+				}
+			}
+			r, j := setupAdminSeatRouter(t, s)
+
+			rr := doReq(t, r, http.MethodPut, RouteAdminSeatLimits+"/"+tt.org, tt.body, adminStatsHeaders(t, j, tt.role))
+			require.Equal(t, tt.wantStatus, rr.Code, rr.Body.String())
+
+			var body map[string]any
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &body))
+			if tt.wantErr != "" {
+				assert.Equal(t, tt.wantErr, body["error"])
+				return
+			}
+
+			assert.Equal(t, tt.wantOrg, gotOrg)
+			assert.Equal(t, tt.wantSeats, gotSeats)
+			assert.Equal(t, tt.wantOrg, body["org"])
+			assert.EqualValues(t, tt.wantSeats, body["seats"])
+			assert.EqualValues(t, 3, body["active_users"])
+		})
+	}
+}
+
+func TestAdminSeatController_DeleteSeatLimitHandler(t *testing.T) {
+	type tc struct {
+		name       string
+		role       string
+		remove     func(ctx context.Context, org string) (bool, error)
+		wantStatus int
+	}
+	cases := []tc{
+		{name: "204", role: domain.RoleAdmin, remove: func(context.Context, string) (bool, error) { return true, nil }, wantStatus: http.StatusNoContent},
+		{name: "404 no limit", role: domain.RoleAdmin, remove: func(context.Context, string) (bool, error) { return false, nil }, wantStatus: http.StatusNotFound},
+		{name: "403 worker", role: domain.RoleWorker, wantStatus: http.StatusForbidden},
+		{name: "500", role: domain.RoleAdmin, remove: func(context.Context, string) (bool, error) { return false, errors.New("db") }, wantStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			var gotOrg string
+			s := &fakeSeatService{}
+			if tt.remove != nil {
+				s.RemoveSeatLimitFunc = func(ctx context.Context, org string) (bool, error) {
+					gotOrg = org
+					return tt.remove(ctx, org)
+				}
+			}
+			r, j := setupAdminSeatRouter(t, s)
+
+			rr := doReq(t, r, http.MethodDelete, RouteAdminSeatLimits+"/acme.com", nil, adminStatsHeaders(t, j, tt.role))
+			require.Equal(t, tt.wantStatus, rr.Code, rr.Body.String())
+			if tt.remove != nil {
+				assert.Equal(t, "acme.com", gotOrg)
+			}
+		})
+	}
+}
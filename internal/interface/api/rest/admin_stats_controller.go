@@ -0,0 +1,85 @@
+package rest
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"user-manager-api/internal/application/ports"
+	"user-manager-api/internal/interface/api/rest/dto/stats"
+	"user-manager-api/internal/interface/api/rest/middleware"
+	"user-manager-api/internal/interface/api/rest/validator"
+)
+
+// AdminStatsController - dashboard stats, read from the aggregates maintained by
+// the event consumer, so they may lag behind the latest changes.
+type AdminStatsController struct {
+	statsService ports.StatsService
+	logger       *zap.Logger
+}
+
+func NewAdminStatsController(
+	r *gin.Engine,
+	statsService ports.StatsService,
+	logger *zap.Logger,
+	tokenService ports.TokenService,
+) *AdminStatsController {
+	asc := &AdminStatsController{
+		statsService: statsService,
+		logger:       logger,
+	}
+
+	r.GET(
+		RouteAdminStatsSignups,
+		middleware.AuthMiddleware(tokenService),
+		middleware.RequireAdmin(),
+		asc.GetSignupsHandler,
+	)
+	r.GET(
+		RouteAdminStatsFiles,
+		middleware.AuthMiddleware(tokenService),
+		middleware.RequireAdmin(),
+		asc.GetFilesHandler,
+	)
+
+	return asc
+}
+
+func (asc *AdminStatsController) GetSignupsHandler(c *gin.Context) {
+	from, to, errs := validator.ParseDayRange(c.Request.URL.Query(), time.Now())
+	if errs != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "invalid date range",
+			"details": errs,
+		})
+		return
+	}
+
+	days, err := asc.statsService.DailySignups(c.Request.Context(), from, to)
+	if err != nil {
+		c.JSON(
+			http.StatusInternalServerError,
+			gin.H{"error": "failed to get signups stats"},
+		)
+		asc.logger.Error("DailySignups() error", zap.Error(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, stats.ToSignupsResponseData(days))
+}
+
+func (asc *AdminStatsController) GetFilesHandler(c *gin.Context) {
+	totals, err := asc.statsService.FilesTotals(c.Request.Context())
+	if err != nil {
+		c.JSON(
+			http.StatusInternalServerError,
+			gin.H{"error": "failed to get files stats"},
+		)
+		asc.logger.Error("FilesTotals() error", zap.Error(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, stats.ToResponseFilesTotals(*totals))
+}
@@ -0,0 +1,180 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"user-manager-api/internal/domain/stats"
+	domain "user-manager-api/internal/domain/user"
+	jwtSvc "user-manager-api/internal/infrastructure/jwt"
+	"user-manager-api/internal/infrastructure/mq"
+	dto "user-manager-api/internal/interface/api/rest/dto/stats"
+)
+
+type fakeStatsService struct {
+	DailySignupsFunc func(ctx context.Context, from, to time.Time) ([]stats.DailySignups, error)
+	FilesTotalsFunc  func(ctx context.Context) (*stats.FilesTotals, error)
+}
+
+func (f *fakeStatsService) ApplyEvent(context.Context, mq.Event) error { return errors.New("not used") }
+func (f *fakeStatsService) Rebuild(context.Context) error              { return errors.New("not used") }
+
+func (f *fakeStatsService) DailySignups(ctx context.Context, from, to time.Time) ([]stats.DailySignups, error) {
+	if f.DailySignupsFunc == nil {
+		return nil, errors.New("not used")
+	}
+	return f.DailySignupsFunc(ctx, from, to)
+}
+
+func (f *fakeStatsService) FilesTotals(ctx context.Context) (*stats.FilesTotals, error) {
+	if f.FilesTotalsFunc == nil {
+		return nil, errors.New("not used")
+	}
+	return f.FilesTotalsFunc(ctx)
+}
+
+func setupAdminStatsRouter(t *testing.T, s *fakeStatsService) (*gin.Engine, *jwtSvc.Service) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	j := jwtSvc.New("test-secret")
+	NewAdminStatsController(r, s, zap.NewNop(), j)
+
+	return r, j
+}
+
+func adminStatsHeaders(t *testing.T, j *jwtSvc.Service, role string) map[string]string {
+	t.Helper()
+	tok, err := j.GenerateToken(uuid.NewString(), role, time.Minute)
+	require.NoError(t, err)
+	return map[string]string{"Authorization": "Bearer " + tok}
+}
+
+func TestAdminStatsController_GetSignupsHandler(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2026, 10, d, 0, 0, 0, 0, time.UTC) }
+	ok := func(_ context.Context, from, to time.Time) ([]stats.DailySignups, error) {
+		return []stats.DailySignups{{Day: from, Count: 2}, {Day: from.AddDate(0, 0, 1), Count: 0}, {Day: to, Count: 5}}, nil
+	}
+
+	type tc struct {
+		name       string
+		query      string
+		role       string
+		signups    func(ctx context.Context, from, to time.Time) ([]stats.DailySignups, error)
+		wantStatus int
+		wantErr    string
+		wantFrom   time.Time
+		wantTo     time.Time
+	}
+	cases := []tc{
+		{name: "200", query: "?from=2026-10-01&to=2026-10-03", role: domain.RoleAdmin, signups: ok, wantStatus: http.StatusOK, wantFrom: day(1), wantTo: day(3)},
+		{name: "403 worker", role: domain.RoleWorker, wantStatus: http.StatusForbidden, wantErr: "admin role required"},
+		{name: "400 bad range", query: "?from=2026-10-03&to=2026-10-01", role: domain.RoleAdmin, wantStatus: http.StatusBadRequest, wantErr: "invalid date range"},
+		{
+			name:  "500 service error",
+			query: "?from=2026-10-01&to=2026-10-03",
+			role:  domain.RoleAdmin,
+			signups: func(context.Context, time.Time, time.Time) ([]stats.DailySignups, error) {
+				return nil, errors.New("db")
+			},
+			wantStatus: http.StatusInternalServerError,
+			wantErr:    "failed to get signups stats",
+		},
+	}
+
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			var gotFrom, gotTo time.Time
+			s := &fakeStatsService{}
+			if tt.signups != nil {
+				s.DailySignupsFunc = func(ctx context.Context, from, to time.Time) ([]stats.DailySignups, error) {
+					gotFrom, gotTo = from, to
+					return tt.signups(ctx, from, to)
+				}
+			}
+			r, j := setupAdminStatsRouter(t, s)
+
+			rr := doReq(t, r, http.MethodGet, RouteAdminStatsSignups+tt.query, nil, adminStatsHeaders(t, j, tt.role))
+			require.Equal(t, tt.wantStatus, rr.Code, rr.Body.String())
+
+			if tt.wantErr != "" {
+				var body map[string]any
+				require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &body))
+				assert.Equal(t, tt.wantErr, body["error"])
+				return
+			}
+
+			assert.Equal(t, tt.wantFrom, gotFrom)
+			assert.Equal(t, tt.wantTo, gotTo)
+			var resp dto.SignupsResponseData
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+			assert.Equal(t, uint64(7), resp.Total)
+			assert.Equal(t, []dto.DailySignups{
+				{Day: "2026-10-01", Signups: 2},
+				{Day: "2026-10-02", Signups: 0},
+				{Day: "2026-10-03", Signups: 5},
+			}, resp.Data)
+		})
+	}
+}
+
+func TestAdminStatsController_GetFilesHandler(t *testing.T) {
+	type tc struct {
+		name       string
+		role       string
+		totals     func(ctx context.Context) (*stats.FilesTotals, error)
+		wantStatus int
+		wantErr    string
+	}
+	cases := []tc{
+		{
+			name: "200",
+			role: domain.RoleAdmin,
+			totals: func(context.Context) (*stats.FilesTotals, error) {
+				return &stats.FilesTotals{UsersWithFiles: 2, FilesCount: 5, TotalBytes: 4096}, nil
+			},
+			wantStatus: http.StatusOK,
+		},
+		{name: "403 worker", role: domain.RoleWorker, wantStatus: http.StatusForbidden, wantErr: "admin role required"},
+		{
+			name:       "500 service error",
+			role:       domain.RoleAdmin,
+			totals:     func(context.Context) (*stats.FilesTotals, error) { return nil, errors.New("db") },
+			wantStatus: http.StatusInternalServerError,
+			wantErr:    "failed to get files stats",
+		},
+	}
+
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			r, j := setupAdminStatsRouter(t, &fakeStatsService{FilesTotalsFunc: tt.totals})
+
+			rr := doReq(t, r, http.MethodGet, RouteAdminStatsFiles, nil, adminStatsHeaders(t, j, tt.role))
+			require.Equal(t, tt.wantStatus, rr.Code, rr.Body.String())
+
+			if tt.wantErr != "" {
+				var body map[string]any
+				require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &body))
+				assert.Equal(t, tt.wantErr, body["error"])
+				return
+			}
+
+			var resp dto.FilesTotals
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+			assert.Equal(t, dto.FilesTotals{UsersWithFiles: 2, FilesCount: 5, TotalBytes: 4096}, resp)
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package rest
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"user-manager-api/internal/application/ports"
+	"user-manager-api/internal/application/services"
+	"user-manager-api/internal/interface/api/rest/middleware"
+	"user-manager-api/internal/interface/api/rest/validator"
+)
+
+// AdminSuspensionController - the users suspended for inactivity past the
+// grace window log in again once an admin reactivates them
+type AdminSuspensionController struct {
+	suspensionService ports.SuspensionService
+	logger            *zap.Logger
+}
+
+func NewAdminSuspensionController(
+	r *gin.Engine,
+	suspensionService ports.SuspensionService,
+	logger *zap.Logger,
+	tokenService ports.TokenService,
+) *AdminSuspensionController {
+	asc := &AdminSuspensionController{
+		suspensionService: suspensionService,
+		logger:            logger,
+	}
+
+	r.POST(
+		RouteAdminReactivate,
+		middleware.AuthMiddleware(tokenService),
+		middleware.RequireAdmin(),
+		asc.ReactivateHandler,
+	)
+
+	return asc
+}
+
+func (asc *AdminSuspensionController) ReactivateHandler(c *gin.Context) {
+	ok, target := validator.IsUUID(c.Param("user_id"))
+	if !ok {
+		c.JSON(
+			http.StatusBadRequest,
+			gin.H{"error": "user_id must be a valid UUID"},
+		)
+		return
+	}
+	ok, actor := validator.IsUUID(c.GetString(middleware.CtxUserID))
+	if !ok {
+		c.JSON(
+			http.StatusUnauthorized,
+			gin.H{"error": "invalid token"},
+		)
+		return
+	}
+
+	reactivated, err := asc.suspensionService.Reactivate(c.Request.Context(), actor, target)
+	if err != nil {
+		if errors.Is(err, services.ErrUserNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "user not found", "code": codeUserNotFound})
+			return
+		}
+		c.JSON(
+			http.StatusInternalServerError,
+			gin.H{"error": "failed to reactivate the user"},
+		)
+		asc.logger.Error("Reactivate() error", zap.Error(err), zap.Stringer("user_uuid", target))
+		return
+	}
+	if !reactivated {
+		c.JSON(http.StatusNotFound, gin.H{"error": "the user is not suspended"})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,91 @@
+package rest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"user-manager-api/internal/application/services"
+	domain "user-manager-api/internal/domain/user"
+	jwtSvc "user-manager-api/internal/infrastructure/jwt"
+)
+
+type fakeSuspensionService struct {
+	ReactivateFunc func(ctx context.Context, actor, userUUID uuid.UUID) (bool, error)
+}
+
+func (f *fakeSuspensionService) SuspendInactive(context.Context) (int, int, error) {
+	return 0, 0, errors.New("not used")
+}
+
+func (f *fakeSuspensionService) CheckLogin(context.Context, *domain.User) error {
+	return errors.New("not used")
+}
+
+func (f *fakeSuspensionService) Reactivate(ctx context.Context, actor, userUUID uuid.UUID) (bool, error) {
+	if f.ReactivateFunc == nil {
+		return false, errors.New("not used")
+	}
+	return f.ReactivateFunc(ctx, actor, userUUID)
+}
+
+func setupAdminSuspensionRouter(t *testing.T, s *fakeSuspensionService) (*gin.Engine, *jwtSvc.Service) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	j := jwtSvc.New("test-secret")
+	NewAdminSuspensionController(r, s, zap.NewNop(), j)
+
+	return r, j
+}
+
+func TestAdminSuspensionController_ReactivateHandler(t *testing.T) {
+	userUUID := uuid.New()
+	path := "/api/v1/admin/users/" + userUUID.String() + "/reactivate"
+
+	tests := []struct {
+		name        string
+		path        string
+		role        string
+		reactivated bool
+		err         error
+		wantStatus  int
+		wantCode    string
+	}{
+		{name: "204", path: path, role: domain.RoleAdmin, reactivated: true, wantStatus: http.StatusNoContent},
+		{name: "404 not suspended", path: path, role: domain.RoleAdmin, wantStatus: http.StatusNotFound},
+		{name: "404 unknown user", path: path, role: domain.RoleAdmin, err: services.ErrUserNotFound, wantStatus: http.StatusNotFound, wantCode: codeUserNotFound},
+		{name: "400 user_id", path: "/api/v1/admin/users/42/reactivate", role: domain.RoleAdmin, wantStatus: http.StatusBadRequest},
+		{name: "403 worker", path: path, role: domain.RoleWorker, wantStatus: http.StatusForbidden},
+		{name: "500", path: path, role: domain.RoleAdmin, err: errors.New("db down"), wantStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotUser uuid.UUID
+			r, j := setupAdminSuspensionRouter(t, &fakeSuspensionService{
+				ReactivateFunc: func(_ context.Context, _, u uuid.UUID) (bool, error) {
+					gotUser = u
+					return tt.reactivated, tt.err
+				},
+			})
+
+			rr := doReq(t, r, http.MethodPost, tt.path, nil, adminStatsHeaders(t, j, tt.role))
+			require.Equal(t, tt.wantStatus, rr.Code, rr.Body.String())
+			if tt.wantCode != "" {
+				assert.Contains(t, rr.Body.String(), `"code":"`+tt.wantCode+`"`)
+			}
+			if tt.wantStatus == http.StatusNoContent {
+				assert.Equal(t, userUUID, gotUser)
+			}
+		})
+	}
+}
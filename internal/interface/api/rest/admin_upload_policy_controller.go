@@ -0,0 +1,129 @@
+package rest
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"user-manager-api/internal/application/ports"
+	"user-manager-api/internal/interface/api/rest/dto/user_file"
+	"user-manager-api/internal/interface/api/rest/middleware"
+	"user-manager-api/internal/interface/api/rest/validator"
+)
+
+// AdminUploadPolicyController - the upload policies of the organizations(email
+// domains): the allowed file types, the max file size and the quota of a user
+type AdminUploadPolicyController struct {
+	uploadPolicyService ports.UploadPolicyService
+	logger              *zap.Logger
+}
+
+func NewAdminUploadPolicyController(
+	r *gin.Engine,
+	uploadPolicyService ports.UploadPolicyService,
+	logger *zap.Logger,
+	tokenService ports.TokenService,
+) *AdminUploadPolicyController {
+	aupc := &AdminUploadPolicyController{
+		uploadPolicyService: uploadPolicyService,
+		logger:              logger,
+	}
+
+	r.GET(
+		RouteAdminUploadPolicies,
+		middleware.AuthMiddleware(tokenService),
+		middleware.RequireAdmin(),
+		aupc.GetUploadPoliciesHandler,
+	)
+	r.PUT(
+		RouteAdminUploadPolicy,
+		middleware.AuthMiddleware(tokenService),
+		middleware.RequireAdmin(),
+		aupc.PutUploadPolicyHandler,
+	)
+	r.DELETE(
+		RouteAdminUploadPolicy,
+		middleware.AuthMiddleware(tokenService),
+		middleware.RequireAdmin(),
+		aupc.DeleteUploadPolicyHandler,
+	)
+
+	return aupc
+}
+
+func (aupc *AdminUploadPolicyController) GetUploadPoliciesHandler(c *gin.Context) {
+	policies, err := aupc.uploadPolicyService.Policies(c.Request.Context())
+	if err != nil {
+		c.JSON(
+			http.StatusInternalServerError,
+			gin.H{"error": "failed to get upload policies"},
+		)
+		aupc.logger.Error("Policies() error", zap.Error(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, user_file.ToResponseUploadPolicies(policies))
+}
+
+func (aupc *AdminUploadPolicyController) PutUploadPolicyHandler(c *gin.Context) {
+	org, ok := aupc.orgParam(c)
+	if !ok {
+		return
+	}
+	req, ok := BindAndValidate(c, validator.ValidateUploadPolicy)
+	if !ok {
+		return
+	}
+
+	p, err := aupc.uploadPolicyService.SetPolicy(c.Request.Context(), user_file.ToDomainUploadPolicy(org, req))
+	if err != nil {
+		c.JSON(
+			http.StatusInternalServerError,
+			gin.H{"error": "failed to set the upload policy"},
+		)
+		aupc.logger.Error("SetPolicy() error", zap.Error(err), zap.String("org", org))
+		return
+	}
+
+	c.JSON(http.StatusOK, user_file.ToResponseUploadPolicy(*p))
+}
+
+func (aupc *AdminUploadPolicyController) DeleteUploadPolicyHandler(c *gin.Context) {
+	org, ok := aupc.orgParam(c)
+	if !ok {
+		return
+	}
+
+	removed, err := aupc.uploadPolicyService.RemovePolicy(c.Request.Context(), org)
+	if err != nil {
+		c.JSON(
+			http.StatusInternalServerError,
+			gin.H{"error": "failed to remove the upload policy"},
+		)
+		aupc.logger.Error("RemovePolicy() error", zap.Error(err), zap.String("org", org))
+		return
+	}
+	if !removed {
+		c.JSON(
+			http.StatusNotFound,
+			gin.H{"error": "upload policy not found"},
+		)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (aupc *AdminUploadPolicyController) orgParam(c *gin.Context) (string, bool) {
+	org, err := validator.ParseOrg(c.Param("org"))
+	if err != nil {
+		c.JSON(
+			http.StatusBadRequest,
+			gin.H{"error": err.Error()},
+		)
+		return "", false
+	}
+
+	return org, true
+}
@@ -0,0 +1,229 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	domain "user-manager-api/internal/domain/user"
+	domainFile "user-manager-api/internal/domain/user_file"
+	jwtSvc "user-manager-api/internal/infrastructure/jwt"
+)
+
+type fakeUploadPolicyService struct {
+	PoliciesFunc     func(ctx context.Context) (domainFile.UploadPolicies, error)
+	SetPolicyFunc    func(ctx context.Context, p domainFile.UploadPolicy) (*domainFile.UploadPolicy, error)
+	RemovePolicyFunc func(ctx context.Context, org string) (bool, error)
+}
+
+func (f *fakeUploadPolicyService) Policies(ctx context.Context) (domainFile.UploadPolicies, error) {
+	if f.PoliciesFunc == nil {
+		return nil, errors.New("not used")
+	}
+	return f.PoliciesFunc(ctx)
+}
+
+func (f *fakeUploadPolicyService) SetPolicy(ctx context.Context, p domainFile.UploadPolicy) (*domainFile.UploadPolicy, error) {
+	if f.SetPolicyFunc == nil {
+		return nil, errors.New("not used")
+	}
+	return f.SetPolicyFunc(ctx, p)
+}
+
+func (f *fakeUploadPolicyService) RemovePolicy(ctx context.Context, org string) (bool, error) {
+	if f.RemovePolicyFunc == nil {
+		return false, errors.New("not used")
+	}
+	return f.RemovePolicyFunc(ctx, org)
+}
+
+func (f *fakeUploadPolicyService) UserPolicy(context.Context, domain.UUID) (*domainFile.UploadPolicy, error) {
+	return nil, errors.New("not used")
+}
+
+func setupAdminUploadPolicyRouter(t *testing.T, s *fakeUploadPolicyService) (*gin.Engine, *jwtSvc.Service) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	j := jwtSvc.New("test-secret")
+	NewAdminUploadPolicyController(r, s, zap.NewNop(), j)
+
+	return r, j
+}
+
+func TestAdminUploadPolicyController_GetUploadPoliciesHandler(t *testing.T) {
+	updated := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		role       string
+		policies   func(ctx context.Context) (domainFile.UploadPolicies, error)
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name: "200",
+			role: domain.RoleAdmin,
+			policies: func(context.Context) (domainFile.UploadPolicies, error) {
+				return domainFile.UploadPolicies{
+					{Org: "acme.com", AllowedTypes: []string{"image/*"}, MaxFileSize: 1 << 20, UpdatedAt: updated},
+					{Org: "globex.com", QuotaBytes: 1 << 30, UpdatedAt: updated},
+				}, nil
+			},
+			wantStatus: http.StatusOK,
+			wantBody: `{"data":[` +
+				`{"org":"acme.com","allowed_types":["image/*"],"max_file_size":1048576,"quota_bytes":0,"updated_at":"2026-10-01T00:00:00Z"},` +
+				`{"org":"globex.com","allowed_types":[],"max_file_size":0,"quota_bytes":1073741824,"updated_at":"2026-10-01T00:00:00Z"}]}`,
+		},
+		{
+			name:       "200 none",
+			role:       domain.RoleAdmin,
+			policies:   func(context.Context) (domainFile.UploadPolicies, error) { return nil, nil },
+			wantStatus: http.StatusOK,
+			wantBody:   `{"data":[]}`,
+		},
+		{name: "403 worker", role: domain.RoleWorker, wantStatus: http.StatusForbidden},
+		{
+			name:       "500",
+			role:       domain.RoleAdmin,
+			policies:   func(context.Context) (domainFile.UploadPolicies, error) { return nil, errors.New("db") },
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, j := setupAdminUploadPolicyRouter(t, &fakeUploadPolicyService{PoliciesFunc: tt.policies})
+
+			rr := doReq(t, r, http.MethodGet, RouteAdminUploadPolicies, nil, adminStatsHeaders(t, j, tt.role))
+			require.Equal(t, tt.wantStatus, rr.Code, rr.Body.String())
+			if tt.wantBody != "" {
+				assert.JSONEq(t, tt.wantBody, rr.Body.String())
+			}
+		})
+	}
+}
+
+func TestAdminUploadPolicyController_PutUploadPolicyHandler(t *testing.T) {
+	updated := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	set := func(_ context.Context, p domainFile.UploadPolicy) (*domainFile.UploadPolicy, error) {
+		p.UpdatedAt = updated
+		return &p, nil
+	}
+
+	tests := []struct {
+		name       string
+		org        string
+		body       any
+		role       string
+		set        func(ctx context.Context, p domainFile.UploadPolicy) (*domainFile.UploadPolicy, error)
+		wantStatus int
+		wantErr    string
+		want       domainFile.UploadPolicy
+	}{
+		{
+			name:       "200",
+			org:        "ACME.com",
+			body:       map[string]any{"allowed_types": []string{"Image/*", "application/pdf", "image/*"}, "max_file_size": 5 << 20, "quota_bytes": 1 << 30},
+			role:       domain.RoleAdmin,
+			set:        set,
+			wantStatus: http.StatusOK,
+			want:       domainFile.UploadPolicy{Org: "acme.com", AllowedTypes: []string{"image/*", "application/pdf"}, MaxFileSize: 5 << 20, QuotaBytes: 1 << 30},
+		},
+		{
+			name:       "200 quota only",
+			org:        "acme.com",
+			body:       map[string]any{"quota_bytes": 1 << 30},
+			role:       domain.RoleAdmin,
+			set:        set,
+			wantStatus: http.StatusOK,
+			want:       domainFile.UploadPolicy{Org: "acme.com", AllowedTypes: []string{}, QuotaBytes: 1 << 30},
+		},
+		{name: "403 worker", org: "acme.com", body: map[string]any{"quota_bytes": 1}, role: domain.RoleWorker, wantStatus: http.StatusForbidden, wantErr: "admin role required"},
+		{name: "400 bad org", org: "a%2Bb", body: map[string]any{"quota_bytes": 1}, role: domain.RoleAdmin, wantStatus: http.StatusBadRequest, wantErr: "org must be a domain name"},
+		{name: "400 empty policy", org: "acme.com", body: map[string]any{}, role: domain.RoleAdmin, wantStatus: http.StatusBadRequest, wantErr: errInvalidRequestBody},
+		{name: "400 bad type", org: "acme.com", body: map[string]any{"allowed_types": []string{"*/*"}}, role: domain.RoleAdmin, wantStatus: http.StatusBadRequest, wantErr: errInvalidRequestBody},
+		{
+			name: "500",
+			org:  "acme.com",
+			body: map[string]any{"quota_bytes": 1},
+			role: domain.RoleAdmin,
+			set: func(context.Context, domainFile.UploadPolicy) (*domainFile.UploadPolicy, error) {
+				return nil, errors.New("db")
+			},
+			wantStatus: http.StatusInternalServerError,
+			wantErr:    "failed to set the upload policy",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got domainFile.UploadPolicy
+			s := &fakeUploadPolicyService{}
+			if tt.set != nil {
+				s.SetPolicyFunc = func(ctx context.Context, p domainFile.UploadPolicy) (*domainFile.UploadPolicy, error) {
+					got = p
+					return tt.set(ctx, p)
+				}
+			}
+			r, j := setupAdminUploadPolicyRouter(t, s)
+
+			rr := doReq(t, r, http.MethodPut, RouteAdminUploadPolicies+"/"+tt.org, tt.body, adminStatsHeaders(t, j, tt.role))
+			require.Equal(t, tt.wantStatus, rr.Code, rr.Body.String())
+
+			var body map[string]any
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &body))
+			if tt.wantErr != "" {
+				assert.Equal(t, tt.wantErr, body["error"])
+				return
+			}
+
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.want.Org, body["org"])
+			assert.EqualValues(t, tt.want.QuotaBytes, body["quota_bytes"])
+		})
+	}
+}
+
+func TestAdminUploadPolicyController_DeleteUploadPolicyHandler(t *testing.T) {
+	tests := []struct {
+		name       string
+		role       string
+		remove     func(ctx context.Context, org string) (bool, error)
+		wantStatus int
+	}{
+		{name: "204", role: domain.RoleAdmin, remove: func(context.Context, string) (bool, error) { return true, nil }, wantStatus: http.StatusNoContent},
+		{name: "404 no policy", role: domain.RoleAdmin, remove: func(context.Context, string) (bool, error) { return false, nil }, wantStatus: http.StatusNotFound},
+		{name: "403 worker", role: domain.RoleWorker, wantStatus: http.StatusForbidden},
+		{name: "500", role: domain.RoleAdmin, remove: func(context.Context, string) (bool, error) { return false, errors.New("db") }, wantStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotOrg string
+			s := &fakeUploadPolicyService{}
+			if tt.remove != nil {
+				s.RemovePolicyFunc = func(ctx context.Context, org string) (bool, error) {
+					gotOrg = org
+					return tt.remove(ctx, org)
+				}
+			}
+			r, j := setupAdminUploadPolicyRouter(t, s)
+
+			rr := doReq(t, r, http.MethodDelete, RouteAdminUploadPolicies+"/acme.com", nil, adminStatsHeaders(t, j, tt.role))
+			require.Equal(t, tt.wantStatus, rr.Code, rr.Body.String())
+			if tt.remove != nil {
+				assert.Equal(t, "acme.com", gotOrg)
+			}
+		})
+	}
+}
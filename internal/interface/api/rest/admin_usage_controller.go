@@ -0,0 +1,107 @@
+package rest
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"user-manager-api/internal/application/ports"
+	"user-manager-api/internal/interface/api/rest/dto/usage"
+	"user-manager-api/internal/interface/api/rest/middleware"
+	"user-manager-api/internal/interface/api/rest/validator"
+)
+
+// AdminUsageController - the usage report for the billing and the capacity
+// planning, read from the rollups which lag behind by up to USAGE_FLUSH_INTERVAL,
+// and the monthly billing records export.
+type AdminUsageController struct {
+	usageService   ports.UsageService
+	billingService ports.BillingService
+	logger         *zap.Logger
+}
+
+func NewAdminUsageController(
+	r *gin.Engine,
+	usageService ports.UsageService,
+	billingService ports.BillingService,
+	logger *zap.Logger,
+	tokenService ports.TokenService,
+) *AdminUsageController {
+	auc := &AdminUsageController{
+		usageService:   usageService,
+		billingService: billingService,
+		logger:         logger,
+	}
+
+	r.GET(
+		RouteAdminUsage,
+		middleware.AuthMiddleware(tokenService),
+		middleware.RequireAdmin(),
+		auc.GetUsageHandler,
+	)
+	r.GET(
+		RouteAdminUsageMonthly,
+		middleware.AuthMiddleware(tokenService),
+		middleware.RequireAdmin(),
+		auc.GetMonthlyHandler,
+	)
+
+	return auc
+}
+
+func (auc *AdminUsageController) GetUsageHandler(c *gin.Context) {
+	from, to, errs := validator.ParseDayRange(c.Request.URL.Query(), time.Now())
+	if errs != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "invalid date range",
+			"details": errs,
+		})
+		return
+	}
+	org, err := validator.ParseOrg(c.Query("org"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "invalid filter params",
+			"details": map[string]string{"org": err.Error()},
+		})
+		return
+	}
+
+	totals, err := auc.usageService.Totals(c.Request.Context(), from, to, org)
+	if err != nil {
+		c.JSON(
+			http.StatusInternalServerError,
+			gin.H{"error": "failed to get usage"},
+		)
+		auc.logger.Error("Totals() error", zap.Error(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, usage.ToReportResponseData(from, to, totals))
+}
+
+func (auc *AdminUsageController) GetMonthlyHandler(c *gin.Context) {
+	from, to, errs := validator.ParseMonthRange(c.Request.URL.Query(), time.Now())
+	if errs != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "invalid month range",
+			"details": errs,
+		})
+		return
+	}
+
+	records, err := auc.billingService.Monthly(c.Request.Context(), from, to)
+	if err != nil {
+		c.JSON(
+			http.StatusInternalServerError,
+			gin.H{"error": "failed to get usage records"},
+		)
+		auc.logger.Error("Monthly() error", zap.Error(err))
+		return
+	}
+
+	c.Header("Content-Disposition", `attachment; filename="usage_`+from.Format("2006-01")+`_`+to.Format("2006-01")+`.csv"`)
+	c.Data(http.StatusOK, usage.ContentTypeCSV, usage.ToMonthlyCSV(records))
+}
@@ -0,0 +1,249 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"user-manager-api/internal/domain/usage"
+	domain "user-manager-api/internal/domain/user"
+	jwtSvc "user-manager-api/internal/infrastructure/jwt"
+	dto "user-manager-api/internal/interface/api/rest/dto/usage"
+	"user-manager-api/internal/interface/api/rest/middleware"
+)
+
+type usageRecord struct {
+	userID, role string
+}
+
+type fakeUsageService struct {
+	TotalsFunc func(ctx context.Context, from, to time.Time, org string) ([]usage.Total, error)
+
+	mu      sync.Mutex
+	records []usageRecord
+}
+
+func (f *fakeUsageService) Record(userID, role string, _ time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.records = append(f.records, usageRecord{userID: userID, role: role})
+}
+
+func (f *fakeUsageService) Worker(context.Context) {}
+
+func (f *fakeUsageService) Totals(ctx context.Context, from, to time.Time, org string) ([]usage.Total, error) {
+	if f.TotalsFunc == nil {
+		return nil, errors.New("not used")
+	}
+	return f.TotalsFunc(ctx, from, to, org)
+}
+
+type fakeBillingService struct {
+	MonthlyFunc func(ctx context.Context, from, to time.Time) ([]usage.Monthly, error)
+}
+
+func (f *fakeBillingService) AggregateMonthly(context.Context, time.Time) (int64, error) {
+	return 0, errors.New("not used")
+}
+
+func (f *fakeBillingService) Monthly(ctx context.Context, from, to time.Time) ([]usage.Monthly, error) {
+	if f.MonthlyFunc == nil {
+		return nil, errors.New("not used")
+	}
+	return f.MonthlyFunc(ctx, from, to)
+}
+
+func setupAdminUsageRouter(t *testing.T, s *fakeUsageService, b *fakeBillingService) (*gin.Engine, *jwtSvc.Service) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	r.Use(middleware.Usage(s))
+	j := jwtSvc.New("test-secret")
+	NewAdminUsageController(r, s, b, zap.NewNop(), j)
+
+	return r, j
+}
+
+func TestAdminUsageController_GetUsageHandler(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2026, 10, d, 0, 0, 0, 0, time.UTC) }
+	ok := func(context.Context, time.Time, time.Time, string) ([]usage.Total, error) {
+		return []usage.Total{
+			{Org: "acme.com", Role: domain.RoleWorker, Requests: 4, DurationMs: 100},
+			{Org: usage.Anonymous, Role: usage.Anonymous, Requests: 2, DurationMs: 3},
+		}, nil
+	}
+
+	type tc struct {
+		name       string
+		query      string
+		role       string
+		totals     func(ctx context.Context, from, to time.Time, org string) ([]usage.Total, error)
+		wantStatus int
+		wantErr    string
+		wantOrg    string
+	}
+	cases := []tc{
+		{name: "200", query: "?from=2026-10-01&to=2026-10-03", role: domain.RoleAdmin, totals: ok, wantStatus: http.StatusOK},
+		{name: "200 org filter", query: "?from=2026-10-01&to=2026-10-03&org=ACME.com", role: domain.RoleAdmin, totals: ok, wantStatus: http.StatusOK, wantOrg: "acme.com"},
+		{name: "403 worker", role: domain.RoleWorker, wantStatus: http.StatusForbidden, wantErr: "admin role required"},
+		{name: "400 bad range", query: "?from=2026-10-03&to=2026-10-01", role: domain.RoleAdmin, wantStatus: http.StatusBadRequest, wantErr: "invalid date range"},
+		{name: "400 bad org", query: "?org=a@b.com", role: domain.RoleAdmin, wantStatus: http.StatusBadRequest, wantErr: "invalid filter params"},
+		{
+			name:  "500 service error",
+			query: "?from=2026-10-01&to=2026-10-03",
+			role:  domain.RoleAdmin,
+			totals: func(context.Context, time.Time, time.Time, string) ([]usage.Total, error) {
+				return nil, errors.New("db")
+			},
+			wantStatus: http.StatusInternalServerError,
+			wantErr:    "failed to get usage",
+		},
+	}
+
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				gotFrom, gotTo time.Time
+				gotOrg         string
+			)
+			s := &fakeUsageService{}
+			if tt.totals != nil {
+				s.TotalsFunc = func(ctx context.Context, from, to time.Time, org string) ([]usage.Total, error) {
+					gotFrom, gotTo, gotOrg = from, to, org
+					return tt.totals(ctx, from, to, org)
+				}
+			}
+			r, j := setupAdminUsageRouter(t, s, &fakeBillingService{})
+
+			rr := doReq(t, r, http.MethodGet, RouteAdminUsage+tt.query, nil, adminStatsHeaders(t, j, tt.role))
+			require.Equal(t, tt.wantStatus, rr.Code, rr.Body.String())
+
+			if tt.wantErr != "" {
+				var body map[string]any
+				require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &body))
+				assert.Equal(t, tt.wantErr, body["error"])
+				return
+			}
+
+			assert.Equal(t, day(1), gotFrom)
+			assert.Equal(t, day(3), gotTo)
+			assert.Equal(t, tt.wantOrg, gotOrg)
+
+			var resp dto.ReportResponseData
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+			assert.Equal(t, "2026-10-01", resp.From)
+			assert.Equal(t, "2026-10-03", resp.To)
+			assert.Equal(t, []dto.Total{
+				{Org: "acme.com", Role: domain.RoleWorker, Requests: 4, AvgDurationMs: 25},
+				{Org: usage.Anonymous, Role: usage.Anonymous, Requests: 2, AvgDurationMs: 1},
+			}, resp.Data)
+			assert.Equal(t, uint64(6), resp.TotalRequests)
+		})
+	}
+}
+
+func TestUsageMiddleware_RecordsUserAndRole(t *testing.T) {
+	s := &fakeUsageService{TotalsFunc: func(context.Context, time.Time, time.Time, string) ([]usage.Total, error) {
+		return nil, nil
+	}}
+	r, j := setupAdminUsageRouter(t, s, &fakeBillingService{})
+	adminID := uuid.NewString()
+	tok, err := j.GenerateToken(adminID, domain.RoleAdmin, time.Minute)
+	require.NoError(t, err)
+
+	rr := doReq(t, r, http.MethodGet, RouteAdminUsage, nil, map[string]string{"Authorization": "Bearer " + tok})
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	// no token: recorded as anonymous
+	rr = doReq(t, r, http.MethodGet, RouteAdminUsage, nil, nil)
+	require.Equal(t, http.StatusUnauthorized, rr.Code, rr.Body.String())
+	// not recorded
+	rr = doReq(t, r, http.MethodOptions, RouteAdminUsage, nil, nil)
+	require.Equal(t, http.StatusNotFound, rr.Code, rr.Body.String())
+
+	assert.Equal(t, []usageRecord{
+		{userID: adminID, role: domain.RoleAdmin},
+		{userID: "", role: ""},
+	}, s.records)
+}
+
+func TestAdminUsageController_GetMonthlyHandler(t *testing.T) {
+	month := func(m time.Month) time.Time { return time.Date(2026, m, 1, 0, 0, 0, 0, time.UTC) }
+	ok := func(_ context.Context, from, _ time.Time) ([]usage.Monthly, error) {
+		return []usage.Monthly{{
+			Month:        from,
+			Org:          "acme.com",
+			ActiveUsers:  3,
+			StorageBytes: 2048,
+			APICalls:     90,
+			GeneratedAt:  time.Date(2026, 10, 2, 0, 0, 0, 0, time.UTC),
+		}}, nil
+	}
+
+	type tc struct {
+		name       string
+		query      string
+		role       string
+		monthly    func(ctx context.Context, from, to time.Time) ([]usage.Monthly, error)
+		wantStatus int
+		wantErr    string
+	}
+	cases := []tc{
+		{name: "200", query: "?from=2026-09&to=2026-10", role: domain.RoleAdmin, monthly: ok, wantStatus: http.StatusOK},
+		{name: "403 worker", role: domain.RoleWorker, wantStatus: http.StatusForbidden, wantErr: "admin role required"},
+		{name: "400 bad range", query: "?from=2026-10&to=2026-09", role: domain.RoleAdmin, wantStatus: http.StatusBadRequest, wantErr: "invalid month range"},
+		{
+			name:  "500 service error",
+			query: "?from=2026-09&to=2026-10",
+			role:  domain.RoleAdmin,
+			monthly: func(context.Context, time.Time, time.Time) ([]usage.Monthly, error) {
+				return nil, errors.New("db")
+			},
+			wantStatus: http.StatusInternalServerError,
+			wantErr:    "failed to get usage records",
+		},
+	}
+
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			var gotFrom, gotTo time.Time
+			b := &fakeBillingService{}
+			if tt.monthly != nil {
+				b.MonthlyFunc = func(ctx context.Context, from, to time.Time) ([]usage.Monthly, error) {
+					gotFrom, gotTo = from, to
+					return tt.monthly(ctx, from, to)
+				}
+			}
+			r, j := setupAdminUsageRouter(t, &fakeUsageService{}, b)
+
+			rr := doReq(t, r, http.MethodGet, RouteAdminUsageMonthly+tt.query, nil, adminStatsHeaders(t, j, tt.role))
+			require.Equal(t, tt.wantStatus, rr.Code, rr.Body.String())
+
+			if tt.wantErr != "" {
+				var body map[string]any
+				require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &body))
+				assert.Equal(t, tt.wantErr, body["error"])
+				return
+			}
+
+			assert.Equal(t, month(9), gotFrom)
+			assert.Equal(t, month(10), gotTo)
+			assert.Equal(t, dto.ContentTypeCSV, rr.Header().Get("Content-Type"))
+			assert.Equal(t, `attachment; filename="usage_2026-09_2026-10.csv"`, rr.Header().Get("Content-Disposition"))
+			assert.Equal(t, "month,org,active_users,storage_bytes,api_calls,generated_at\r\n"+
+				"2026-09,acme.com,3,2048,90,2026-10-02T00:00:00Z\r\n", rr.Body.String())
+		})
+	}
+}
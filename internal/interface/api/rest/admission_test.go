@@ -0,0 +1,68 @@
+package rest
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"user-manager-api/internal/interface/api/rest/middleware"
+	"user-manager-api/pkg/bulkhead"
+)
+
+func TestAdmissionMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mCounter := prometheus.NewCounterVec(prometheus.CounterOpts{Name: "test"}, []string{"result"})
+	inFlight := prometheus.NewGauge(prometheus.GaugeOpts{Name: "test_in_flight"})
+	limit := bulkhead.NewQueued(1, 1, time.Second)
+
+	entered := make(chan struct{}, 2)
+	unblock := make(chan struct{})
+	r := gin.New()
+	r.Use(middleware.Admission(limit, 1500*time.Millisecond, inFlight, mCounter, RouteHealth))
+	r.GET(RouteUsers, func(c *gin.Context) {
+		entered <- struct{}{}
+		<-unblock
+		c.Status(http.StatusOK)
+	})
+	r.GET(RouteHealth, func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	// one runs, one waits in the queue
+	var wg sync.WaitGroup
+	codes := make([]int, 2)
+	for i := range codes {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			codes[i] = doReq(t, r, http.MethodGet, RouteUsers, nil, nil).Code
+		}()
+	}
+	<-entered
+	require.Eventually(t, func() bool { return limit.Queued() == 1 }, time.Second, time.Millisecond)
+	assert.Equal(t, float64(1), testutil.ToFloat64(inFlight))
+
+	// saturated: shed
+	w := doReq(t, r, http.MethodGet, RouteUsers, nil, nil)
+	require.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Equal(t, "2", w.Header().Get("Retry-After"))
+	var body map[string]any
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, middleware.CodeOverloaded, body["code"])
+
+	// the probes are exempt
+	w = doReq(t, r, http.MethodGet, RouteHealth, nil, nil)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	close(unblock)
+	wg.Wait()
+	assert.Equal(t, []int{http.StatusOK, http.StatusOK}, codes, "the queued request runs once a slot frees up")
+	assert.Equal(t, float64(0), testutil.ToFloat64(inFlight))
+	assert.Equal(t, float64(1), testutil.ToFloat64(mCounter.WithLabelValues("http_requests_shed_total")))
+}
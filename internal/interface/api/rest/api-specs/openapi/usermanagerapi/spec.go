@@ -0,0 +1,8 @@
+// Package usermanagerapi - the OpenAPI contract of the REST API, the handlers
+// drifting from it fail the runtime validation(SERVICE_OPENAPI_VALIDATION)
+package usermanagerapi
+
+import _ "embed"
+
+//go:embed openapi.yaml
+var Spec []byte
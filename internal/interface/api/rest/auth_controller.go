@@ -3,84 +3,166 @@ package rest
 import (
 	"errors"
 	"net/http"
+	"strings"
 	"user-manager-api/internal/application/services"
 
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
 
 	"user-manager-api/internal/application/ports"
-	"user-manager-api/internal/interface/api/rest/dto/auth"
+	"user-manager-api/internal/domain/login"
+	domain "user-manager-api/internal/domain/user"
+	"user-manager-api/internal/interface/api/rest/dto/user"
 	"user-manager-api/internal/interface/api/rest/validator"
 )
 
+// codeAccountSuspended - the 403 of the logins of a user suspended for
+// inactivity past the grace window(see AdminSuspensionController)
+const codeAccountSuspended = "account_suspended"
+
 type AuthController struct {
-	logger      *zap.Logger
-	userService ports.UserService
-	authService ports.Auth
+	logger            *zap.Logger
+	userCommands      ports.UserCommands
+	userQueries       ports.UserQueries
+	authService       ports.Auth
+	credentialService ports.CredentialService
+	// geoHeader - the country of the client set by the edge, empty - unknown
+	geoHeader string
 }
 
 func NewAuthController(
 	r *gin.Engine,
 	logger *zap.Logger,
-	userService ports.UserService,
+	userCommands ports.UserCommands,
+	userQueries ports.UserQueries,
 	authService ports.Auth,
+	credentialService ports.CredentialService,
+	geoHeader string,
 ) *AuthController {
 	ac := &AuthController{
-		logger:      logger,
-		userService: userService,
-		authService: authService,
+		logger:            logger,
+		userCommands:      userCommands,
+		userQueries:       userQueries,
+		authService:       authService,
+		credentialService: credentialService,
+		geoHeader:         geoHeader,
 	}
 
 	r.POST(RouteLogin, ac.LoginHandler)
+	r.POST(RouteEmailConfirm, ac.ConfirmEmailHandler)
+	r.POST(RoutePassword, ac.ChangePasswordHandler)
 
 	return ac
 }
 
 func (ac *AuthController) LoginHandler(c *gin.Context) {
-	var req auth.LoginRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
+	req, ok := BindAndValidate(c, validator.ValidateLogin)
+	if !ok {
+		return
+	}
+
+	u, err := ac.userQueries.FindByEmail(c.Request.Context(), req.Email)
+	if err != nil && !errors.Is(err, services.ErrUserNotFound) {
 		c.JSON(
-			http.StatusBadRequest,
-			gin.H{"error": "invalid json"},
+			http.StatusInternalServerError,
+			gin.H{"error": "failed to get a user"},
 		)
+		ac.logger.Error("FindByEmail() error", zap.Error(err))
 		return
 	}
+	// u is nil for an unknown email: it must not be distinguishable from a wrong password
+	token, err := ac.authService.GenerateToken(c.Request.Context(), u, req.Password, ac.client(c))
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidCredentials):
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		case errors.Is(err, services.ErrPasswordResetRequired):
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		case errors.Is(err, services.ErrAccountSuspended):
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error(), "code": codeAccountSuspended})
+		default:
+			ac.logger.Error("GenerateToken() error", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": services.ErrFailedToGenerateToken.Error()})
+		}
+
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"access_token": token,
+		"token_type":   "Bearer",
+	})
+}
+
+// client - the IP is the one of SERVICE_TRUSTED_PROXIES/SERVICE_REMOTE_IP_HEADERS
+func (ac *AuthController) client(c *gin.Context) login.Client {
+	cl := login.Client{IP: c.ClientIP(), UserAgent: c.Request.UserAgent()}
+	if ac.geoHeader != "" {
+		cl.Country = strings.ToUpper(strings.TrimSpace(c.GetHeader(ac.geoHeader)))
+	}
+
+	return cl
+}
 
-	if errs := validator.ValidateLogin(req); errs != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "invalid request body",
-			"details": errs,
-		})
+// ConfirmEmailHandler - the token from the confirmation link is the only proof
+// of the new address ownership, so no JWT is required.
+func (ac *AuthController) ConfirmEmailHandler(c *gin.Context) {
+	req, ok := BindAndValidate(c, validator.ValidateEmailConfirm)
+	if !ok {
 		return
 	}
 
-	u, err := ac.userService.FindByEmail(c.Request.Context(), req.Email)
+	u, err := ac.userCommands.ConfirmEmailChange(c.Request.Context(), req.Token)
 	if err != nil {
-		c.JSON(
-			http.StatusInternalServerError,
-			gin.H{"error": "failed to get a user"},
-		)
-		ac.logger.Error("FindUserByID() error", zap.Error(err))
+		switch {
+		case errors.Is(err, services.ErrInvalidEmailChangeToken):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, domain.ErrEmailAlreadyExists):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error(), "code": codeEmailTaken})
+		case errors.Is(err, services.ErrSeatLimitReached):
+			c.JSON(http.StatusPaymentRequired, gin.H{"error": err.Error(), "code": codeUpgradeRequired})
+		default:
+			c.JSON(
+				http.StatusInternalServerError,
+				gin.H{"error": "failed to confirm email"},
+			)
+			ac.logger.Error("ConfirmEmailChange() error", zap.Error(err))
+		}
 		return
 	}
-	if u == nil {
-		c.JSON(
-			http.StatusNotFound,
-			gin.H{"error": "user not found"},
-		)
+
+	c.JSON(http.StatusOK, user.ToResponseUser(*u))
+}
+
+// ChangePasswordHandler takes the credentials instead of a JWT: it is the way out
+// of a forced reset, when login is refused.
+func (ac *AuthController) ChangePasswordHandler(c *gin.Context) {
+	req, ok := BindAndValidate(c, validator.ValidatePasswordChange)
+	if !ok {
 		return
 	}
 
-	token, err := ac.authService.GenerateToken(u, req.Password)
+	token, err := ac.credentialService.ChangePassword(
+		c.Request.Context(),
+		strings.ToLower(strings.TrimSpace(req.Email)),
+		req.Password,
+		req.NewPassword,
+	)
 	if err != nil {
-		if errors.Is(err, services.ErrInvalidCredentials) {
+		switch {
+		case errors.Is(err, services.ErrInvalidCredentials):
 			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		case errors.Is(err, services.ErrSamePassword):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, services.ErrAccountSuspended):
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error(), "code": codeAccountSuspended})
+		default:
+			c.JSON(
+				http.StatusInternalServerError,
+				gin.H{"error": "failed to change password"},
+			)
+			ac.logger.Error("ChangePassword() error", zap.Error(err))
 		}
-		if errors.Is(err, services.ErrFailedToGenerateToken) {
-			ac.logger.Error("GenerateToken() error", zap.Error(err), zap.Stringer("user_uuid", u.UUID))
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		}
-
 		return
 	}
 
@@ -17,6 +17,8 @@ import (
 	"go.uber.org/zap"
 
 	"user-manager-api/internal/application/ports"
+	"user-manager-api/internal/domain/login"
+	"user-manager-api/internal/domain/pagination"
 	"user-manager-api/internal/interface/api/rest/dto/auth"
 
 	domain "user-manager-api/internal/domain/user"
@@ -26,21 +28,24 @@ type fakeAuthService struct {
 	GenerateTokenFunc func(u *domain.User, password string) (string, error)
 }
 
-func (f *fakeAuthService) GenerateToken(u *domain.User, password string) (string, error) {
+func (f *fakeAuthService) GenerateToken(_ context.Context, u *domain.User, password string, _ login.Client) (string, error) {
 	return f.GenerateTokenFunc(u, password)
 }
 
-func newRouterWithController(t *testing.T, us ports.UserService, as ports.Auth) (*gin.Engine, *AuthController) {
+func newRouterWithController(t *testing.T, us userService, as ports.Auth) (*gin.Engine, *AuthController) {
 	t.Helper()
 	gin.SetMode(gin.TestMode)
 
 	r := gin.New()
 	ac := &AuthController{
-		logger:      zap.NewNop(),
-		userService: us,
-		authService: as,
+		logger:       zap.NewNop(),
+		userCommands: us,
+		userQueries:  us,
+		authService:  as,
 	}
 	r.POST("/login", ac.LoginHandler)
+	r.POST("/email/confirm", ac.ConfirmEmailHandler)
+	r.POST("/password", ac.ChangePasswordHandler)
 	return r, ac
 }
 
@@ -100,8 +105,8 @@ func TestAuthController_LoginHandler(t *testing.T) {
 			},
 			want: want{
 				code:        http.StatusBadRequest,
-				jsonEq:      map[string]any{"error": "invalid json"},
-				jsonHasKeys: []string{"error"},
+				jsonEq:      map[string]any{"error": "invalid request body"},
+				jsonHasKeys: []string{"error", "details"},
 			},
 		},
 		{
@@ -132,15 +137,22 @@ func TestAuthController_LoginHandler(t *testing.T) {
 			},
 		},
 		{
-			name: "user not found -> 404",
+			name: "user not found -> 401 same as wrong password",
 			body: validLogin(),
 			fields: fields{
-				findByEmail:   func(ctx context.Context, email string) (*domain.User, error) { return nil, nil },
-				generateToken: func(u *domain.User, password string) (string, error) { return "", nil },
+				findByEmail: func(ctx context.Context, email string) (*domain.User, error) {
+					return nil, services.ErrUserNotFound
+				},
+				generateToken: func(u *domain.User, password string) (string, error) {
+					if u != nil {
+						return "", errors.New("unexpected user")
+					}
+					return "", services.ErrInvalidCredentials
+				},
 			},
 			want: want{
-				code:        http.StatusNotFound,
-				jsonEq:      map[string]any{"error": "user not found"},
+				code:        http.StatusUnauthorized,
+				jsonEq:      map[string]any{"error": services.ErrInvalidCredentials.Error()},
 				jsonHasKeys: []string{"error"},
 			},
 		},
@@ -160,6 +172,38 @@ func TestAuthController_LoginHandler(t *testing.T) {
 				jsonHasKeys: []string{"error"},
 			},
 		},
+		{
+			name: "GenerateToken ErrPasswordResetRequired -> 403",
+			body: validLogin(),
+			fields: fields{
+				findByEmail: func(ctx context.Context, email string) (*domain.User, error) {
+					return &domain.User{}, nil
+				},
+				generateToken: func(u *domain.User, password string) (string, error) {
+					return "", services.ErrPasswordResetRequired
+				},
+			},
+			want: want{
+				code:   http.StatusForbidden,
+				jsonEq: map[string]any{"error": services.ErrPasswordResetRequired.Error()},
+			},
+		},
+		{
+			name: "GenerateToken ErrAccountSuspended -> 403",
+			body: validLogin(),
+			fields: fields{
+				findByEmail: func(ctx context.Context, email string) (*domain.User, error) {
+					return &domain.User{}, nil
+				},
+				generateToken: func(u *domain.User, password string) (string, error) {
+					return "", services.ErrAccountSuspended
+				},
+			},
+			want: want{
+				code:   http.StatusForbidden,
+				jsonEq: map[string]any{"error": services.ErrAccountSuspended.Error(), "code": codeAccountSuspended},
+			},
+		},
 		{
 			name: "GenerateToken ErrFailedToGenerateToken -> 500",
 			body: validLogin(),
@@ -201,10 +245,14 @@ func TestAuthController_LoginHandler(t *testing.T) {
 			us := &FakeUserService{
 				FindByEmailFunc:  tt.fields.findByEmail,
 				FindUserByIDFunc: func(ctx context.Context, uuid domain.UUID) (*domain.User, error) { return nil, errors.New("not used") },
-				FindUsersFunc:    func(ctx context.Context, page int) (domain.Users, error) { return nil, errors.New("not used") },
-				CreateUserFunc:   func(ctx context.Context, u domain.User) (*domain.User, error) { return nil, errors.New("not used") },
-				UpdateUserFunc:   func(ctx context.Context, u domain.User) (*domain.User, error) { return nil, errors.New("not used") },
-				DeleteUserFunc:   func(ctx context.Context, userUUID domain.UUID) error { return errors.New("not used") },
+				StreamUsersFunc: func(ctx context.Context, p pagination.Params, fn func(u *domain.User) error) error {
+					return errors.New("not used")
+				},
+				CreateUserFunc: func(ctx context.Context, u domain.User) (*domain.User, error) { return nil, errors.New("not used") },
+				UpdateUserFunc: func(ctx context.Context, u domain.User) (*domain.User, error) { return nil, errors.New("not used") },
+				DeleteUserFunc: func(ctx context.Context, actor, userUUID domain.UUID, reason domain.DeletionReason, confirmSelf bool) error {
+					return errors.New("not used")
+				},
 			}
 			as := &fakeAuthService{GenerateTokenFunc: tt.fields.generateToken}
 
@@ -239,3 +287,166 @@ func TestAuthController_LoginHandler(t *testing.T) {
 		})
 	}
 }
+
+func TestAuthController_ConfirmEmailHandler(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       any
+		confirm    func(ctx context.Context, token string) (*domain.User, error)
+		wantStatus int
+		wantErr    string
+		wantEmail  string
+	}{
+		{
+			name:       "400 missing token",
+			body:       map[string]string{"token": " "},
+			wantStatus: http.StatusBadRequest,
+			wantErr:    "invalid request body",
+		},
+		{
+			name: "400 invalid or expired token",
+			body: map[string]string{"token": "stale"},
+			confirm: func(ctx context.Context, token string) (*domain.User, error) {
+				return nil, services.ErrInvalidEmailChangeToken
+			},
+			wantStatus: http.StatusBadRequest,
+			wantErr:    services.ErrInvalidEmailChangeToken.Error(),
+		},
+		{
+			name: "409 email taken meanwhile",
+			body: map[string]string{"token": "tok"},
+			confirm: func(ctx context.Context, token string) (*domain.User, error) {
+				return nil, domain.ErrEmailAlreadyExists
+			},
+			wantStatus: http.StatusConflict,
+			wantErr:    domain.ErrEmailAlreadyExists.Error(),
+		},
+		{
+			name: "402 no seat in the new organization",
+			body: map[string]string{"token": "tok"},
+			confirm: func(ctx context.Context, token string) (*domain.User, error) {
+				return nil, services.ErrSeatLimitReached
+			},
+			wantStatus: http.StatusPaymentRequired,
+			wantErr:    services.ErrSeatLimitReached.Error(),
+		},
+		{
+			name: "500 service error",
+			body: map[string]string{"token": "tok"},
+			confirm: func(ctx context.Context, token string) (*domain.User, error) {
+				return nil, errors.New("db error")
+			},
+			wantStatus: http.StatusInternalServerError,
+			wantErr:    "failed to confirm email",
+		},
+		{
+			name: "200 confirmed",
+			body: map[string]string{"token": "tok"},
+			confirm: func(ctx context.Context, token string) (*domain.User, error) {
+				if token != "tok" {
+					return nil, errors.New("unexpected token")
+				}
+				return &domain.User{Email: "new@example.com"}, nil
+			},
+			wantStatus: http.StatusOK,
+			wantEmail:  "new@example.com",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			us := &FakeUserService{ConfirmEmailChangeFunc: tt.confirm}
+			r, _ := newRouterWithController(t, us, &fakeAuthService{})
+
+			rr := doPOST(t, r, "/email/confirm", tt.body)
+			require.Equal(t, tt.wantStatus, rr.Code)
+
+			var resp map[string]any
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+			if tt.wantErr != "" {
+				assert.Equal(t, tt.wantErr, resp["error"])
+			}
+			if tt.wantEmail != "" {
+				assert.Equal(t, tt.wantEmail, resp["email"])
+			}
+		})
+	}
+}
+
+func TestAuthController_ChangePasswordHandler(t *testing.T) {
+	valid := auth.PasswordChangeRequest{Email: " John@Example.com ", Password: "old-password", NewPassword: "new-password"}
+
+	tests := []struct {
+		name       string
+		body       any
+		change     func(ctx context.Context, email, password, newPassword string) (string, error)
+		wantStatus int
+		wantErr    string
+	}{
+		{
+			name: "200 token",
+			body: valid,
+			change: func(ctx context.Context, email, password, newPassword string) (string, error) {
+				if email != "john@example.com" || password != "old-password" || newPassword != "new-password" {
+					return "", errors.New("unexpected args")
+				}
+				return "tok_123", nil
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "400 short new password",
+			body:       auth.PasswordChangeRequest{Email: "john@example.com", Password: "old-password", NewPassword: "short"},
+			wantStatus: http.StatusBadRequest,
+			wantErr:    errInvalidRequestBody,
+		},
+		{
+			name: "400 same password",
+			body: valid,
+			change: func(ctx context.Context, email, password, newPassword string) (string, error) {
+				return "", services.ErrSamePassword
+			},
+			wantStatus: http.StatusBadRequest,
+			wantErr:    services.ErrSamePassword.Error(),
+		},
+		{
+			name: "401 invalid credentials",
+			body: valid,
+			change: func(ctx context.Context, email, password, newPassword string) (string, error) {
+				return "", services.ErrInvalidCredentials
+			},
+			wantStatus: http.StatusUnauthorized,
+			wantErr:    services.ErrInvalidCredentials.Error(),
+		},
+		{
+			name: "500 service error",
+			body: valid,
+			change: func(ctx context.Context, email, password, newPassword string) (string, error) {
+				return "", errors.New("db down")
+			},
+			wantStatus: http.StatusInternalServerError,
+			wantErr:    "failed to change password",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			r, ac := newRouterWithController(t, &FakeUserService{}, &fakeAuthService{})
+			ac.credentialService = &fakeCredentialService{ChangePasswordFunc: tt.change}
+
+			rr := doPOST(t, r, "/password", tt.body)
+			require.Equal(t, tt.wantStatus, rr.Code, rr.Body.String())
+
+			var resp map[string]any
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+			if tt.wantErr != "" {
+				assert.Equal(t, tt.wantErr, resp["error"])
+				return
+			}
+			assert.Equal(t, "tok_123", resp["access_token"])
+			assert.Equal(t, "Bearer", resp["token_type"])
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package rest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type bindReq struct {
+	Name string `json:"name"`
+}
+
+func validateBindReq(r bindReq) map[string]string {
+	if r.Name == "" {
+		return map[string]string{"name": "name is required"}
+	}
+	return nil
+}
+
+func TestBindAndValidate_Table(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	cases := []struct {
+		name        string
+		body        string
+		validate    func(bindReq) map[string]string
+		wantStatus  int
+		wantDetails any
+	}{
+		{"ok", `{"name":"john"}`, validateBindReq, http.StatusOK, nil},
+		{"ok without validator", `{}`, nil, http.StatusOK, nil},
+		{"malformed json", `{bad`, validateBindReq, http.StatusBadRequest, nil},
+		{"validation failed", `{"name":""}`, validateBindReq, http.StatusBadRequest, map[string]any{"name": "name is required"}},
+	}
+
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			r := gin.New()
+			r.POST("/", func(c *gin.Context) {
+				req, ok := BindAndValidate(c, tt.validate)
+				if !ok {
+					return
+				}
+				c.JSON(http.StatusOK, req)
+			})
+
+			rr := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			r.ServeHTTP(rr, req)
+
+			require.Equal(t, tt.wantStatus, rr.Code)
+			if tt.wantStatus == http.StatusOK {
+				return
+			}
+
+			var resp map[string]any
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+			assert.Equal(t, errInvalidRequestBody, resp["error"])
+			assert.Contains(t, resp, "details")
+			if tt.wantDetails != nil {
+				assert.Equal(t, tt.wantDetails, resp["details"])
+			}
+		})
+	}
+}
@@ -0,0 +1,248 @@
+package rest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/evgenyspirin/user-manager-api/client"
+
+	"user-manager-api/internal/application/services"
+	"user-manager-api/internal/domain/login"
+	"user-manager-api/internal/domain/pagination"
+	"user-manager-api/internal/domain/token"
+	domain "user-manager-api/internal/domain/user"
+	jwtSvc "user-manager-api/internal/infrastructure/jwt"
+	"user-manager-api/internal/interface/api/rest/api-specs/openapi/usermanagerapi"
+	"user-manager-api/internal/interface/api/rest/middleware"
+	"user-manager-api/pkg/openapi"
+)
+
+const testPassword = "secret123"
+
+// memUserService - the users of the contract tests, in memory
+type memUserService struct {
+	mu    sync.Mutex
+	users map[domain.UUID]domain.User
+}
+
+func (s *memUserService) FindUserByID(_ context.Context, id domain.UUID) (*domain.User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	u, ok := s.users[id]
+	if !ok {
+		return nil, services.ErrUserNotFound
+	}
+	return &u, nil
+}
+func (s *memUserService) FindByEmail(_ context.Context, email string) (*domain.User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, u := range s.users {
+		if u.Email == email {
+			return &u, nil
+		}
+	}
+	return nil, services.ErrUserNotFound
+}
+func (s *memUserService) StreamUsers(_ context.Context, _ pagination.Params, fn func(u *domain.User) error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, u := range s.users {
+		if err := fn(&u); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+func (s *memUserService) CreateUser(ctx context.Context, u domain.User) (*domain.User, error) {
+	if err := s.CheckEmailAvailable(ctx, u.Email); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	u.UUID = uuid.New()
+	u.Role = domain.RoleWorker
+	u.CreatedAt, u.UpdatedAt = time.Now().UTC(), time.Now().UTC()
+	s.users[u.UUID] = u
+	return &u, nil
+}
+func (s *memUserService) CheckEmailAvailable(ctx context.Context, email string) error {
+	if _, err := s.FindByEmail(ctx, email); err == nil {
+		return domain.ErrEmailAlreadyExists
+	}
+	return nil
+}
+func (s *memUserService) CheckAge(domain.User) error { return nil }
+func (s *memUserService) UpdateUser(_ context.Context, u domain.User) (*domain.User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	prev, ok := s.users[u.UUID]
+	if !ok {
+		return nil, services.ErrUserNotFound
+	}
+	u.Role, u.CreatedAt, u.UpdatedAt = prev.Role, prev.CreatedAt, time.Now().UTC()
+	s.users[u.UUID] = u
+	return &u, nil
+}
+func (s *memUserService) DeleteUser(_ context.Context, actor, id domain.UUID, _ domain.DeletionReason, confirmSelf bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.users[id]; !ok {
+		return services.ErrUserNotFound
+	}
+	if actor == id && !confirmSelf {
+		return services.ErrSelfDeleteUnconfirmed
+	}
+	delete(s.users, id)
+	return nil
+}
+func (s *memUserService) FindDeletedUsers(context.Context, domain.DeletionReason, pagination.Params) (domain.Users, error) {
+	return nil, errors.New("not used")
+}
+func (s *memUserService) ConfirmEmailChange(context.Context, string) (*domain.User, error) {
+	return nil, errors.New("not used")
+}
+func (s *memUserService) EmitBirthdays(context.Context) (int, error) {
+	return 0, errors.New("not used")
+}
+
+// memAuthService - every user has testPassword
+type memAuthService struct{ tokens *jwtSvc.Service }
+
+func (s memAuthService) GenerateToken(_ context.Context, u *domain.User, password string, _ login.Client) (string, error) {
+	if u == nil || password != testPassword {
+		return "", services.ErrInvalidCredentials
+	}
+	return s.tokens.GenerateToken(u.UUID.String(), u.Role, time.Hour)
+}
+
+type noCredentialService struct{}
+
+func (noCredentialService) ForcePasswordReset(context.Context, domain.UUID, domain.UUID) error {
+	return errors.New("not used")
+}
+func (noCredentialService) ChangePassword(context.Context, string, string, string) (string, error) {
+	return "", errors.New("not used")
+}
+func (noCredentialService) IsTokenRevoked(context.Context, *token.Claims) (bool, error) {
+	return false, nil
+}
+
+// newContractServer - the real controllers behind the OpenAPI check: a request or
+// a response of the client out of the contract is answered 500
+func newContractServer(t *testing.T) (*httptest.Server, domain.User) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	spec, err := openapi.Load(usermanagerapi.Spec)
+	require.NoError(t, err)
+	logger := zap.NewNop()
+	tokens := jwtSvc.New("contract-secret")
+
+	admin := domain.User{
+		UUID:      uuid.New(),
+		Email:     "admin@example.com",
+		Role:      domain.RoleAdmin,
+		Name:      "Ada",
+		Lastname:  "Lovelace",
+		BirthDate: time.Date(1990, 12, 10, 0, 0, 0, 0, time.UTC),
+		Phone:     "+33788888888",
+		CreatedAt: time.Now().UTC(),
+		UpdatedAt: time.Now().UTC(),
+	}
+	us := &memUserService{users: map[domain.UUID]domain.User{admin.UUID: admin}}
+
+	r := gin.New()
+	r.Use(middleware.OpenAPI(spec, logger, prometheus.NewCounterVec(prometheus.CounterOpts{Name: "test"}, []string{"result"})))
+	NewAuthController(r, logger, us, us, memAuthService{tokens: tokens}, noCredentialService{}, "")
+	NewUserController(r, us, us, logger, tokens)
+
+	srv := httptest.NewServer(r)
+	t.Cleanup(srv.Close)
+
+	return srv, admin
+}
+
+func TestClient_Contract(t *testing.T) {
+	srv, admin := newContractServer(t)
+	ctx := context.Background()
+	anonymous := client.New(srv.URL + RouteApiV1)
+
+	_, err := anonymous.Login(ctx, client.LoginRequest{Email: admin.Email, Password: "wrong-password"})
+	require.Error(t, err)
+	assert.True(t, client.IsStatus(err, http.StatusUnauthorized), err)
+
+	tok, err := anonymous.Login(ctx, client.LoginRequest{Email: admin.Email, Password: testPassword})
+	require.NoError(t, err)
+	assert.Equal(t, "Bearer", tok.TokenType)
+	c := anonymous.WithToken(tok.AccessToken)
+
+	me, err := c.GetMe(ctx, nil)
+	require.NoError(t, err)
+	assert.Equal(t, admin.UUID, me.UUID)
+	assert.Equal(t, "1990-12-10", me.BirthDate.Format(time.DateOnly))
+
+	req := client.UserRequest{
+		Email:     "grace@example.com",
+		Name:      "Grace",
+		Lastname:  "Hopper",
+		BirthDate: "1986-12-09",
+		Phone:     "+33788888889",
+	}
+	res, err := c.ValidateUser(ctx, nil, req)
+	require.NoError(t, err)
+	assert.True(t, res.Valid, res.Errors)
+
+	created, err := c.CreateUser(ctx, req)
+	require.NoError(t, err)
+	assert.Equal(t, req.Email, created.Email)
+
+	fields := "email"
+	res, err = c.ValidateUser(ctx, &client.ValidateUserParams{Fields: &fields}, req)
+	require.NoError(t, err)
+	assert.False(t, res.Valid)
+	assert.Contains(t, res.Errors, "email")
+
+	_, err = c.CreateUser(ctx, req)
+	assert.True(t, client.IsStatus(err, http.StatusConflict), err)
+
+	req.Lastname = "Murray Hopper"
+	updated, err := c.UpdateUser(ctx, created.UUID, req)
+	require.NoError(t, err)
+	assert.Equal(t, "Murray Hopper", updated.Lastname)
+
+	got, err := anonymous.GetUser(ctx, created.UUID, nil)
+	require.NoError(t, err)
+	public, err := got.AsPublicUser()
+	require.NoError(t, err)
+	assert.Equal(t, "Grace", public.Name)
+	assert.NotContains(t, string(got.RawMessage), "email", "anyone gets the public card only")
+
+	perPage, sort := 10, "email"
+	list, err := c.ListUsers(ctx, &client.ListUsersParams{PerPage: &perPage, Sort: &sort})
+	require.NoError(t, err)
+	assert.Len(t, list.Data, 2)
+
+	var apiErr *client.APIError
+	err = c.DeleteUser(ctx, admin.UUID, nil)
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusConflict, apiErr.StatusCode)
+	assert.Equal(t, "self_delete_unconfirmed", apiErr.Code)
+
+	reason := client.DeletionReasonGdpr
+	require.NoError(t, c.DeleteUser(ctx, created.UUID, &client.DeleteUserParams{Reason: &reason}))
+	_, err = c.GetUser(ctx, created.UUID, nil)
+	assert.True(t, client.IsStatus(err, http.StatusNotFound), err)
+}
@@ -0,0 +1,135 @@
+package rest
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"user-manager-api/internal/interface/api/rest/dto/auth"
+	"user-manager-api/internal/interface/api/rest/dto/broker"
+	"user-manager-api/internal/interface/api/rest/dto/collection"
+	"user-manager-api/internal/interface/api/rest/dto/hook"
+	"user-manager-api/internal/interface/api/rest/dto/invitation"
+	"user-manager-api/internal/interface/api/rest/dto/mode"
+	"user-manager-api/internal/interface/api/rest/dto/notification"
+	"user-manager-api/internal/interface/api/rest/dto/usage"
+	"user-manager-api/internal/interface/api/rest/dto/user"
+	"user-manager-api/internal/interface/api/rest/dto/user_file"
+	"user-manager-api/internal/interface/api/rest/dto/user_note"
+	"user-manager-api/pkg/openapi"
+)
+
+// the formats of the generated example collection
+const (
+	CollectionPostman = "postman"
+	CollectionHTTP    = "http"
+
+	collectionName = "User Manager API"
+)
+
+var errCollectionFormat = errors.New("format must be postman or http")
+
+var exampleUser = user.Request{
+	Email:     "john.doe@example.com",
+	Name:      "John",
+	Lastname:  "Doe",
+	BirthDate: "1990-05-20",
+	Phone:     "+33755555555",
+}
+
+// requestExamples - the bodies of the collection by "<METHOD> <route>". They are
+// the request DTOs, so a renamed or removed field does not compile.
+var requestExamples = map[string]any{
+	http.MethodPost + " " + RouteLogin:        auth.LoginRequest{Email: "admin@example.com", Password: "admin123"},
+	http.MethodPost + " " + RoutePassword:     auth.PasswordChangeRequest{Email: "admin@example.com", Password: "admin123", NewPassword: "admin456"},
+	http.MethodPost + " " + RouteEmailConfirm: auth.EmailConfirmRequest{Token: "token-from-the-email"},
+	http.MethodPost + " " + RouteOTPRequest:   auth.OTPRequest{Phone: exampleUser.Phone},
+	http.MethodPost + " " + RouteOTPVerify:    auth.OTPVerifyRequest{Phone: exampleUser.Phone, Code: "123456"},
+
+	http.MethodPost + " " + RouteUsers:         exampleUser,
+	http.MethodPost + " " + RouteUsersValidate: exampleUser,
+	http.MethodPut + " " + RouteUser:           exampleUser,
+	http.MethodPut + " " + RouteMe:             exampleUser,
+	http.MethodPost + " " + RouteUserNotes:     user_note.Request{Body: "Called about the invoice"},
+
+	http.MethodPatch + " " + RouteUserFile:       user_file.MoveRequest{Folder: ref("Invoices/2026"), FileName: ref("invoice-march.pdf")},
+	http.MethodPost + " " + RouteUserFoldersMove: user_file.FolderMoveRequest{From: "Invoices/2026", To: "Archive/2026"},
+
+	http.MethodPost + " " + RouteInvitations: invitation.Request{Email: exampleUser.Email, Role: "worker"},
+	http.MethodPost + " " + RouteInvitationAccept: invitation.AcceptRequest{
+		Password:  "secret123",
+		Name:      exampleUser.Name,
+		Lastname:  exampleUser.Lastname,
+		BirthDate: exampleUser.BirthDate,
+		Phone:     exampleUser.Phone,
+	},
+	http.MethodPut + " " + RouteMeNotifications: notification.Request{Preferences: []notification.Preference{
+		{Channel: "email", Enabled: true, Mode: "digest"},
+	}},
+
+	http.MethodPost + " " + RouteAdminUserRoles: user.RolesRequest{Assignments: []user.RoleAssignment{
+		{UserID: "00000000-0000-0000-0000-000000000000", Role: "admin"},
+	}},
+	http.MethodPost + " " + RouteAdminMerge: user.MergeRequest{
+		WinnerID: "00000000-0000-0000-0000-000000000000",
+		LoserID:  "00000000-0000-0000-0000-000000000001",
+	},
+	http.MethodPost + " " + RouteAdminLegalHold:      user.LegalHoldRequest{Reason: "litigation #42"},
+	http.MethodPut + " " + RouteAdminSeatLimit:       usage.SeatLimitRequest{Seats: ref(int64(50))},
+	http.MethodPost + " " + RouteAdminRetentionRules: user_file.RetentionRuleRequest{Tag: "payslip", Days: ref(2555)},
+	http.MethodPut + " " + RouteAdminFileRetention:   user_file.FileRetentionRequest{LegalHold: ref(true)},
+	http.MethodPut + " " + RouteAdminReadOnly:        mode.ReadOnlyRequest{Enabled: ref(true), Reason: "database failover"},
+	http.MethodPost + " " + RouteAdminDLQRequeue:     broker.DeadLettersRequest{MessageIDs: []string{"message-id"}},
+	http.MethodPost + " " + RouteAdminDLQDiscard:     broker.DeadLettersRequest{MessageIDs: []string{"message-id"}},
+
+	http.MethodPost + " " + RouteHookHR: hook.HREvent{Event: hook.EventEmployeeCreated, Employee: exampleUser},
+}
+
+// CollectionRequests - the API routes of the route table named and secured by
+// the spec, an undocumented route is named by its method and path
+func CollectionRequests(routes gin.RoutesInfo, spec *openapi.Spec) []collection.Request {
+	reqs := make([]collection.Request, 0, len(routes))
+	for _, rt := range routes {
+		rel, ok := strings.CutPrefix(rt.Path, RouteApiV1+"/")
+		if !ok {
+			continue
+		}
+		folder, _, _ := strings.Cut(rel, "/")
+		req := collection.Request{
+			Name:          rt.Method + " " + rt.Path,
+			Folder:        folder,
+			Method:        rt.Method,
+			Path:          rt.Path,
+			Authenticated: true,
+			Body:          requestExamples[rt.Method+" "+rt.Path],
+		}
+		if op := spec.Operation(rt.Method, rt.Path); op != nil {
+			if op.Summary != "" {
+				req.Name = op.Summary
+			}
+			req.Authenticated = op.Authenticated
+		}
+		reqs = append(reqs, req)
+	}
+
+	return reqs
+}
+
+// MarshalCollection - the requests as a Postman collection or an .http file, the
+// content type of it
+func MarshalCollection(format, host string, reqs []collection.Request) ([]byte, string, error) {
+	switch format {
+	case CollectionPostman:
+		b, err := json.MarshalIndent(collection.ToPostman(collectionName, host, reqs), "", "  ")
+		return b, "application/json", err
+	case CollectionHTTP:
+		return collection.ToHTTPFile(host, reqs), collection.ContentTypeHTTP, nil
+	default:
+		return nil, "", errCollectionFormat
+	}
+}
+
+func ref[T any](v T) *T { return &v }
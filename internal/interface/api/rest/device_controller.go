@@ -0,0 +1,101 @@
+package rest
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"user-manager-api/internal/application/ports"
+	"user-manager-api/internal/application/services"
+	"user-manager-api/internal/interface/api/rest/dto/device"
+	"user-manager-api/internal/interface/api/rest/middleware"
+	"user-manager-api/internal/interface/api/rest/validator"
+)
+
+// DeviceController - the devices the token subject logged in from
+type DeviceController struct {
+	deviceService ports.DeviceService
+	logger        *zap.Logger
+}
+
+func NewDeviceController(
+	r *gin.Engine,
+	deviceService ports.DeviceService,
+	logger *zap.Logger,
+	tokenService ports.TokenService,
+) *DeviceController {
+	dc := &DeviceController{
+		deviceService: deviceService,
+		logger:        logger,
+	}
+
+	r.GET(RouteMeDevices, middleware.AuthMiddleware(tokenService), dc.GetDevicesHandler)
+	r.DELETE(RouteMeDevice, middleware.AuthMiddleware(tokenService), dc.DeleteDeviceHandler)
+
+	return dc
+}
+
+// GetDevicesHandler - the one of the token of the request is marked current
+func (dc *DeviceController) GetDevicesHandler(c *gin.Context) {
+	ok, userUUID := validator.IsUUID(c.GetString(middleware.CtxUserID))
+	if !ok {
+		c.JSON(
+			http.StatusUnauthorized,
+			gin.H{"error": "invalid token"},
+		)
+		return
+	}
+
+	devices, err := dc.deviceService.Devices(c.Request.Context(), userUUID)
+	if err != nil {
+		c.JSON(
+			http.StatusInternalServerError,
+			gin.H{"error": "failed to get the devices"},
+		)
+		dc.logger.Error("Devices() error", zap.Error(err), zap.Stringer("user_uuid", userUUID))
+		return
+	}
+
+	// unbound tokens(e.g. OTP login) have no current device
+	current, _ := uuid.Parse(c.GetString(middleware.CtxDeviceID))
+	c.JSON(http.StatusOK, device.ResponseData{Data: device.ToResponseDevices(devices, current)})
+}
+
+// DeleteDeviceHandler - the tokens bound to the device are revoked, the current
+// one included if it is the device of the request
+func (dc *DeviceController) DeleteDeviceHandler(c *gin.Context) {
+	ok, userUUID := validator.IsUUID(c.GetString(middleware.CtxUserID))
+	if !ok {
+		c.JSON(
+			http.StatusUnauthorized,
+			gin.H{"error": "invalid token"},
+		)
+		return
+	}
+	ok, deviceUUID := validator.IsUUID(c.Param("device_id"))
+	if !ok {
+		c.JSON(
+			http.StatusBadRequest,
+			gin.H{"error": "device_id must be a valid UUID"},
+		)
+		return
+	}
+
+	if err := dc.deviceService.RevokeDevice(c.Request.Context(), userUUID, deviceUUID); err != nil {
+		if errors.Is(err, services.ErrDeviceNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(
+			http.StatusInternalServerError,
+			gin.H{"error": "failed to delete the device"},
+		)
+		dc.logger.Error("RevokeDevice() error", zap.Error(err), zap.Stringer("user_uuid", userUUID))
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,192 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"user-manager-api/internal/application/services"
+	domain "user-manager-api/internal/domain/device"
+	"user-manager-api/internal/domain/token"
+	"user-manager-api/internal/domain/user"
+	jwtSvc "user-manager-api/internal/infrastructure/jwt"
+	"user-manager-api/internal/interface/api/rest/dto/device"
+)
+
+type fakeDeviceService struct {
+	DevicesFunc      func(ctx context.Context, userUUID user.UUID) (domain.Devices, error)
+	RevokeDeviceFunc func(ctx context.Context, userUUID user.UUID, deviceUUID uuid.UUID) error
+}
+
+func (f *fakeDeviceService) Devices(ctx context.Context, userUUID user.UUID) (domain.Devices, error) {
+	if f.DevicesFunc == nil {
+		return nil, errors.New("not used")
+	}
+	return f.DevicesFunc(ctx, userUUID)
+}
+
+func (f *fakeDeviceService) RevokeDevice(ctx context.Context, userUUID user.UUID, deviceUUID uuid.UUID) error {
+	if f.RevokeDeviceFunc == nil {
+		return errors.New("not used")
+	}
+	return f.RevokeDeviceFunc(ctx, userUUID, deviceUUID)
+}
+
+func (f *fakeDeviceService) IsTokenRevoked(context.Context, *token.Claims) (bool, error) {
+	return false, nil
+}
+
+func setupDeviceRouter(t *testing.T, ds *fakeDeviceService) (*gin.Engine, *jwtSvc.Service) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	j := jwtSvc.New("test-secret")
+	NewDeviceController(r, ds, zap.NewNop(), j)
+
+	return r, j
+}
+
+func TestDeviceController(t *testing.T) {
+	userID := uuid.New()
+	current, other := uuid.New(), uuid.New()
+	devices := domain.Devices{
+		{UUID: current, UserAgent: "ua-1", IP: "203.0.113.1", LastSeenAt: time.Now()},
+		{UUID: other, UserAgent: "ua-2", IP: "203.0.113.2", LastSeenAt: time.Now().Add(-time.Hour)},
+	}
+
+	type tc struct {
+		name        string
+		method      string
+		path        string
+		unbound     bool
+		noToken     bool
+		list        func(ctx context.Context, userUUID user.UUID) (domain.Devices, error)
+		revoke      func(ctx context.Context, userUUID user.UUID, deviceUUID uuid.UUID) error
+		wantStatus  int
+		wantErr     string
+		wantCurrent []bool
+	}
+
+	cases := []tc{
+		{
+			name:   "GET 200 current marked",
+			method: http.MethodGet,
+			path:   RouteMeDevices,
+			list: func(_ context.Context, userUUID user.UUID) (domain.Devices, error) {
+				if userUUID != userID {
+					return nil, errors.New("unexpected user")
+				}
+				return devices, nil
+			},
+			wantStatus:  http.StatusOK,
+			wantCurrent: []bool{true, false},
+		},
+		{
+			name:    "GET 200 unbound token",
+			method:  http.MethodGet,
+			path:    RouteMeDevices,
+			unbound: true,
+			list: func(context.Context, user.UUID) (domain.Devices, error) {
+				return devices, nil
+			},
+			wantStatus:  http.StatusOK,
+			wantCurrent: []bool{false, false},
+		},
+		{
+			name:       "GET 401 no token",
+			method:     http.MethodGet,
+			path:       RouteMeDevices,
+			noToken:    true,
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:   "GET 500",
+			method: http.MethodGet,
+			path:   RouteMeDevices,
+			list: func(context.Context, user.UUID) (domain.Devices, error) {
+				return nil, errors.New("db down")
+			},
+			wantStatus: http.StatusInternalServerError,
+			wantErr:    "failed to get the devices",
+		},
+		{
+			name:   "DELETE 204",
+			method: http.MethodDelete,
+			path:   RouteMeDevices + "/" + other.String(),
+			revoke: func(_ context.Context, userUUID user.UUID, deviceUUID uuid.UUID) error {
+				if userUUID != userID || deviceUUID != other {
+					return errors.New("unexpected device")
+				}
+				return nil
+			},
+			wantStatus: http.StatusNoContent,
+		},
+		{
+			name:       "DELETE 400 invalid id",
+			method:     http.MethodDelete,
+			path:       RouteMeDevices + "/not-a-uuid",
+			wantStatus: http.StatusBadRequest,
+			wantErr:    "device_id must be a valid UUID",
+		},
+		{
+			name:   "DELETE 404",
+			method: http.MethodDelete,
+			path:   RouteMeDevices + "/" + other.String(),
+			revoke: func(context.Context, user.UUID, uuid.UUID) error {
+				return services.ErrDeviceNotFound
+			},
+			wantStatus: http.StatusNotFound,
+			wantErr:    "device not found",
+		},
+		{
+			name:   "DELETE 500",
+			method: http.MethodDelete,
+			path:   RouteMeDevices + "/" + other.String(),
+			revoke: func(context.Context, user.UUID, uuid.UUID) error {
+				return errors.New("db down")
+			},
+			wantStatus: http.StatusInternalServerError,
+			wantErr:    "failed to delete the device",
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			r, j := setupDeviceRouter(t, &fakeDeviceService{DevicesFunc: tt.list, RevokeDeviceFunc: tt.revoke})
+
+			headers := map[string]string{}
+			if !tt.noToken {
+				tok, err := j.GenerateDeviceToken(userID.String(), user.RoleWorker, current.String(), time.Minute)
+				if tt.unbound {
+					tok, err = j.GenerateToken(userID.String(), user.RoleWorker, time.Minute)
+				}
+				require.NoError(t, err)
+				headers["Authorization"] = "Bearer " + tok
+			}
+
+			rr := doReq(t, r, tt.method, tt.path, nil, headers)
+			require.Equal(t, tt.wantStatus, rr.Code, rr.Body.String())
+			if tt.wantErr != "" {
+				assert.JSONEq(t, `{"error":"`+tt.wantErr+`"}`, rr.Body.String())
+			}
+			if tt.wantCurrent != nil {
+				var resp device.ResponseData
+				require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+				require.Len(t, resp.Data, len(tt.wantCurrent))
+				for i, d := range resp.Data {
+					assert.Equal(t, tt.wantCurrent[i], d.Current, d.UUID)
+				}
+			}
+		})
+	}
+}
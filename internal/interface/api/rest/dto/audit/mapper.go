@@ -0,0 +1,23 @@
+package audit
+
+import "user-manager-api/internal/domain/audit"
+
+func ToResponseEntries(esDomain []audit.Entry) Entries {
+	es := make(Entries, len(esDomain))
+	for idx, e := range esDomain {
+		details := e.Details
+		if details == nil {
+			details = map[string]any{}
+		}
+		es[idx] = Entry{
+			ID:         e.ID,
+			ActorUUID:  e.ActorUUID,
+			Action:     string(e.Action),
+			TargetUUID: e.TargetUUID,
+			Details:    details,
+			CreatedAt:  e.CreatedAt,
+		}
+	}
+
+	return es
+}
@@ -0,0 +1,22 @@
+package audit
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type (
+	Entry struct {
+		ID         int64          `json:"id"`
+		ActorUUID  uuid.UUID      `json:"actor_uuid"`
+		Action     string         `json:"action"`
+		TargetUUID *uuid.UUID     `json:"target_uuid"`
+		Details    map[string]any `json:"details"`
+		CreatedAt  time.Time      `json:"created_at"`
+	}
+	Entries      []Entry
+	ResponseData struct {
+		Data Entries `json:"data"`
+	}
+)
@@ -4,3 +4,22 @@ type LoginRequest struct {
 	Email    string `json:"email"`
 	Password string `json:"password"`
 }
+
+type PasswordChangeRequest struct {
+	Email       string `json:"email"`
+	Password    string `json:"password"`
+	NewPassword string `json:"new_password"`
+}
+
+type EmailConfirmRequest struct {
+	Token string `json:"token"`
+}
+
+type OTPRequest struct {
+	Phone string `json:"phone"`
+}
+
+type OTPVerifyRequest struct {
+	Phone string `json:"phone"`
+	Code  string `json:"code"`
+}
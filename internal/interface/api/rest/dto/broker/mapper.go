@@ -0,0 +1,74 @@
+package broker
+
+import (
+	"encoding/json"
+	"slices"
+	"time"
+
+	"user-manager-api/internal/domain/eventbus"
+)
+
+func ToResponseTopology(tr eventbus.TopologyReport) Topology {
+	return Topology{
+		Connected:       tr.Connected,
+		Buffered:        tr.Buffered,
+		RetryQueued:     tr.RetryQueued,
+		Drift:           tr.Drift(),
+		Exchange:        toResponseEntity(tr.Exchange),
+		Queue:           toResponseEntity(tr.Queue),
+		MissingBindings: nonNil(tr.MissingBindings),
+		ExtraBindings:   nonNil(tr.ExtraBindings),
+	}
+}
+
+func toResponseEntity(e eventbus.EntityState) Entity {
+	return Entity{Name: e.Name, Found: e.Found, Mismatches: nonNil(e.Mismatches)}
+}
+
+func ToResponseDeadLetters(dls eventbus.DeadLetters) DeadLetters {
+	resp := DeadLetters{Total: dls.Total, Messages: make([]DeadLetter, len(dls.Messages))}
+	for i, dl := range dls.Messages {
+		resp.Messages[i] = DeadLetter{
+			MessageID:   dl.MessageID,
+			RoutingKey:  dl.RoutingKey,
+			Error:       dl.Error,
+			FailedAt:    timeOrNil(dl.FailedAt),
+			PublishedAt: timeOrNil(dl.PublishedAt),
+			Redelivered: dl.Redelivered,
+			ContentType: dl.ContentType,
+			Body:        string(dl.Body),
+		}
+		if json.Valid(dl.Body) {
+			resp.Messages[i].Body = json.RawMessage(dl.Body)
+		}
+	}
+
+	return resp
+}
+
+// ToResponseDeadLettersResult - requested are the ids asked for, done the ones found
+func ToResponseDeadLettersResult(requested, done []string) DeadLettersResult {
+	resp := DeadLettersResult{Done: nonNil(done), NotFound: []string{}}
+	for _, id := range requested {
+		if !slices.Contains(done, id) && !slices.Contains(resp.NotFound, id) {
+			resp.NotFound = append(resp.NotFound, id)
+		}
+	}
+
+	return resp
+}
+
+func timeOrNil(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
+
+// nonNil - the lists are [] in JSON, never null
+func nonNil(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
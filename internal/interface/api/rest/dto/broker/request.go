@@ -0,0 +1,8 @@
+package broker
+
+type (
+	// DeadLettersRequest - of a requeue or a discard
+	DeadLettersRequest struct {
+		MessageIDs []string `json:"message_ids"`
+	}
+)
@@ -0,0 +1,45 @@
+package broker
+
+import "time"
+
+type (
+	Entity struct {
+		Name  string `json:"name"`
+		Found bool   `json:"found"`
+		// Mismatches - the properties differing from the declared ones
+		Mismatches []string `json:"mismatches"`
+	}
+	Topology struct {
+		Connected bool `json:"connected"`
+		// Buffered, RetryQueued - events of this instance not yet published
+		Buffered        int      `json:"buffered"`
+		RetryQueued     int      `json:"retry_queued"`
+		Drift           bool     `json:"drift"`
+		Exchange        Entity   `json:"exchange"`
+		Queue           Entity   `json:"queue"`
+		MissingBindings []string `json:"missing_bindings"`
+		ExtraBindings   []string `json:"extra_bindings"`
+	}
+	DeadLetter struct {
+		MessageID  string `json:"message_id"`
+		RoutingKey string `json:"routing_key"`
+		Error      string `json:"error"`
+		// FailedAt, PublishedAt - null if the message does not carry them
+		FailedAt    *time.Time `json:"failed_at"`
+		PublishedAt *time.Time `json:"published_at"`
+		Redelivered bool       `json:"redelivered"`
+		ContentType string     `json:"content_type"`
+		// Body - the JSON of the event, a string if the body is not JSON
+		Body any `json:"body"`
+	}
+	DeadLetters struct {
+		// Total - the messages in the queue, Messages - the first of them
+		Total    int          `json:"total"`
+		Messages []DeadLetter `json:"messages"`
+	}
+	// DeadLettersResult - of a requeue or a discard
+	DeadLettersResult struct {
+		Done     []string `json:"done"`
+		NotFound []string `json:"not_found"`
+	}
+)
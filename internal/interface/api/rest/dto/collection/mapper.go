@@ -0,0 +1,140 @@
+package collection
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/json"
+	"slices"
+	"strings"
+)
+
+const (
+	ContentTypeHTTP = "text/plain; charset=utf-8"
+
+	varHost  = "host"
+	varToken = "token"
+	// placeholder - the value of the variables to be filled in
+	placeholder = "*****"
+)
+
+// ToPostman - an importable collection, a folder per Request.Folder. The host and
+// the token are the collection variables, the path params the request ones.
+func ToPostman(name, host string, reqs []Request) Postman {
+	p := Postman{
+		Info:     Info{Name: name, Schema: SchemaPostman},
+		Variable: []Variable{{Key: varHost, Value: host}, {Key: varToken, Value: ""}},
+		Item:     []Folder{},
+	}
+	for _, r := range sorted(reqs) {
+		if len(p.Item) == 0 || p.Item[len(p.Item)-1].Name != r.Folder {
+			p.Item = append(p.Item, Folder{Name: r.Folder})
+		}
+
+		item := Item{Name: r.Name, Request: PostmanRequest{
+			Method: r.Method,
+			Auth:   Auth{Type: "noauth"},
+			Header: []Header{{Key: "Accept", Value: "application/json"}},
+			URL:    URL{Raw: "{{" + varHost + "}}" + r.Path, Host: []string{"{{" + varHost + "}}"}},
+		}}
+		if r.Authenticated {
+			item.Request.Auth = Auth{Type: "bearer", Bearer: []Variable{{Key: varToken, Value: "{{" + varToken + "}}"}}}
+		}
+		for _, seg := range strings.Split(strings.Trim(r.Path, "/"), "/") {
+			if param, ok := pathParam(seg); ok {
+				// Postman path variables are ":name" only
+				seg = ":" + param
+				item.Request.URL.Variable = append(item.Request.URL.Variable, Variable{Key: param})
+			}
+			item.Request.URL.Path = append(item.Request.URL.Path, seg)
+		}
+		if body, ok := rawJSON(r.Body); ok {
+			item.Request.Header = append(item.Request.Header, Header{Key: "Content-Type", Value: "application/json"})
+			item.Request.Body = &Body{Mode: "raw", Raw: body}
+		}
+
+		folder := &p.Item[len(p.Item)-1]
+		folder.Item = append(folder.Item, item)
+	}
+
+	return p
+}
+
+// ToHTTPFile - the requests in the .http format of the IDEs(GoLand, VS Code
+// REST Client), the path params and the token as the file variables.
+func ToHTTPFile(host string, reqs []Request) []byte {
+	reqs = sorted(reqs)
+
+	var vars []string
+	for _, r := range reqs {
+		for _, seg := range strings.Split(r.Path, "/") {
+			if param, ok := pathParam(seg); ok && !slices.Contains(vars, param) {
+				vars = append(vars, param)
+			}
+		}
+	}
+	slices.Sort(vars)
+
+	var b bytes.Buffer
+	b.WriteString("# Generated from the route table, do not edit\n")
+	b.WriteString("@" + varHost + " = " + host + "\n")
+	b.WriteString("@" + varToken + " = " + placeholder + "\n")
+	for _, v := range vars {
+		b.WriteString("@" + v + " = " + placeholder + "\n")
+	}
+
+	for _, r := range reqs {
+		segs := strings.Split(r.Path, "/")
+		for i, seg := range segs {
+			if param, ok := pathParam(seg); ok {
+				segs[i] = "{{" + param + "}}"
+			}
+		}
+
+		b.WriteString("\n###\n# " + r.Name + "\n")
+		b.WriteString(r.Method + " {{" + varHost + "}}" + strings.Join(segs, "/") + "\n")
+		if r.Authenticated {
+			b.WriteString("Authorization: Bearer {{" + varToken + "}}\n")
+		}
+		body, ok := rawJSON(r.Body)
+		if ok {
+			b.WriteString("Content-Type: application/json\n")
+		}
+		b.WriteString("Accept: application/json\n")
+		if ok {
+			b.WriteString("\n" + body + "\n")
+		}
+	}
+
+	return b.Bytes()
+}
+
+// sorted - by the folder, the path and the method
+func sorted(reqs []Request) []Request {
+	reqs = slices.Clone(reqs)
+	slices.SortFunc(reqs, func(a, b Request) int {
+		return cmp.Or(cmp.Compare(a.Folder, b.Folder), cmp.Compare(a.Path, b.Path), cmp.Compare(a.Method, b.Method))
+	})
+
+	return reqs
+}
+
+// pathParam - the name of a ":name" or "*name" segment
+func pathParam(seg string) (string, bool) {
+	if len(seg) > 1 && (seg[0] == ':' || seg[0] == '*') {
+		return seg[1:], true
+	}
+
+	return "", false
+}
+
+func rawJSON(body any) (string, bool) {
+	if body == nil {
+		return "", false
+	}
+	raw, err := json.MarshalIndent(body, "", "  ")
+	if err != nil {
+		return "", false
+	}
+
+	return string(raw), true
+}
@@ -0,0 +1,65 @@
+package collection
+
+// the Postman collection format v2.1
+const SchemaPostman = "https://schema.getpostman.com/json/collection/v2.1.0/collection.json"
+
+type (
+	// Request - an API route of the collection, Body nil - none or not JSON
+	Request struct {
+		Name   string
+		Folder string
+		Method string
+		// Path - in the gin syntax(/users/:user_id, /files/raw/*key)
+		Path          string
+		Authenticated bool
+		Body          any
+	}
+
+	Postman struct {
+		Info     Info       `json:"info"`
+		Variable []Variable `json:"variable"`
+		Item     []Folder   `json:"item"`
+	}
+	Info struct {
+		Name   string `json:"name"`
+		Schema string `json:"schema"`
+	}
+	Variable struct {
+		Key   string `json:"key"`
+		Value string `json:"value"`
+	}
+	Folder struct {
+		Name string `json:"name"`
+		Item []Item `json:"item"`
+	}
+	Item struct {
+		Name    string         `json:"name"`
+		Request PostmanRequest `json:"request"`
+	}
+	PostmanRequest struct {
+		Method string   `json:"method"`
+		Auth   Auth     `json:"auth"`
+		Header []Header `json:"header"`
+		Body   *Body    `json:"body,omitempty"`
+		URL    URL      `json:"url"`
+	}
+	// Auth - "bearer" with the {{token}} variable or "noauth"
+	Auth struct {
+		Type   string     `json:"type"`
+		Bearer []Variable `json:"bearer,omitempty"`
+	}
+	Header struct {
+		Key   string `json:"key"`
+		Value string `json:"value"`
+	}
+	Body struct {
+		Mode string `json:"mode"`
+		Raw  string `json:"raw"`
+	}
+	URL struct {
+		Raw      string     `json:"raw"`
+		Host     []string   `json:"host"`
+		Path     []string   `json:"path"`
+		Variable []Variable `json:"variable,omitempty"`
+	}
+)
@@ -0,0 +1,30 @@
+package database
+
+import (
+	"time"
+
+	"user-manager-api/internal/domain/stats"
+)
+
+func ToResponseSlowQueries(threshold time.Duration, qs []stats.QueryStats) SlowQueries {
+	resp := SlowQueries{Threshold: threshold.String(), Data: make([]SlowQuery, len(qs))}
+	for i, q := range qs {
+		resp.Data[i] = SlowQuery{
+			Fingerprint: q.Fingerprint,
+			SQL:         q.SQL,
+			Calls:       q.Calls,
+			Slow:        q.Slow,
+			MaxMs:       ms(q.Max),
+			LastSlowAt:  q.LastSlowAt,
+		}
+		if q.Calls > 0 {
+			resp.Data[i].MeanMs = ms(q.Total / time.Duration(q.Calls))
+		}
+	}
+
+	return resp
+}
+
+func ms(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
@@ -0,0 +1,22 @@
+package database
+
+import "time"
+
+type (
+	SlowQuery struct {
+		Fingerprint string `json:"fingerprint"`
+		// SQL - with the placeholders, "" for the statements over the tracked ones
+		SQL   string `json:"sql"`
+		Calls int64  `json:"calls"`
+		Slow  int64  `json:"slow"`
+		// MeanMs, MaxMs - of all the calls, the fast ones included
+		MeanMs     float64    `json:"mean_ms"`
+		MaxMs      float64    `json:"max_ms"`
+		LastSlowAt *time.Time `json:"last_slow_at"`
+	}
+	SlowQueries struct {
+		// Threshold - a Go duration, "0s" - the log is off
+		Threshold string      `json:"threshold"`
+		Data      []SlowQuery `json:"data"`
+	}
+)
@@ -0,0 +1,30 @@
+package device
+
+import (
+	"github.com/google/uuid"
+
+	"user-manager-api/internal/domain/device"
+)
+
+func ToResponseDevice(dDomain device.Device, current uuid.UUID) Device {
+	var d = Device{
+		UUID:       dDomain.UUID,
+		UserAgent:  dDomain.UserAgent,
+		IP:         dDomain.IP,
+		CreatedAt:  dDomain.CreatedAt,
+		LastSeenAt: dDomain.LastSeenAt,
+		Current:    dDomain.UUID == current,
+	}
+
+	return d
+}
+
+// ToResponseDevices - current is the device of the request, uuid.Nil if unbound
+func ToResponseDevices(dsDomain device.Devices, current uuid.UUID) Devices {
+	ds := make(Devices, len(dsDomain))
+	for idx, d := range dsDomain {
+		ds[idx] = ToResponseDevice(*d, current)
+	}
+
+	return ds
+}
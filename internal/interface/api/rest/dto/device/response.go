@@ -0,0 +1,23 @@
+package device
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type (
+	Device struct {
+		UUID       uuid.UUID `json:"uuid"`
+		UserAgent  string    `json:"user_agent"`
+		IP         string    `json:"ip"`
+		CreatedAt  time.Time `json:"created_at"`
+		LastSeenAt time.Time `json:"last_seen_at"`
+		// Current - the device of the token of the request
+		Current bool `json:"current"`
+	}
+	Devices      []Device
+	ResponseData struct {
+		Data Devices `json:"data"`
+	}
+)
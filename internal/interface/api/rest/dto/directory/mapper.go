@@ -0,0 +1,18 @@
+package directory
+
+import "user-manager-api/internal/domain/directory"
+
+func ToResponseSync(s directory.Sync) Sync {
+	return Sync{
+		StartedAt:  s.StartedAt.UTC(),
+		FinishedAt: s.FinishedAt.UTC(),
+		Fetched:    s.Fetched,
+		Created:    s.Created,
+		Updated:    s.Updated,
+		Disabled:   s.Disabled,
+		Unchanged:  s.Unchanged,
+		Skipped:    s.Skipped,
+		Failed:     s.Failed,
+		Error:      s.Error,
+	}
+}
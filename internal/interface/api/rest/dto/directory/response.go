@@ -0,0 +1,17 @@
+package directory
+
+import "time"
+
+type Sync struct {
+	StartedAt  time.Time `json:"started_at"`
+	FinishedAt time.Time `json:"finished_at"`
+	Fetched    int       `json:"fetched"`
+	Created    int       `json:"created"`
+	Updated    int       `json:"updated"`
+	Disabled   int       `json:"disabled"`
+	Unchanged  int       `json:"unchanged"`
+	Skipped    int       `json:"skipped"`
+	Failed     int       `json:"failed"`
+	// Error - the run failed as a whole
+	Error string `json:"error,omitempty"`
+}
@@ -0,0 +1,22 @@
+package hook
+
+import (
+	"strings"
+
+	domain "user-manager-api/internal/domain/user"
+	"user-manager-api/internal/interface/api/rest/dto/user"
+)
+
+// ToDomainUser - the employee is matched by the normalized email
+func ToDomainUser(e HREvent) (domain.User, error) {
+	u, err := user.ToDomainUser(e.Employee)
+	if err != nil {
+		return domain.User{}, err
+	}
+	u.Email = strings.ToLower(strings.TrimSpace(u.Email))
+	u.Name = strings.TrimSpace(u.Name)
+	u.Lastname = strings.TrimSpace(u.Lastname)
+	u.Phone = strings.TrimSpace(u.Phone)
+
+	return u, nil
+}
@@ -0,0 +1,14 @@
+package hook
+
+import "user-manager-api/internal/interface/api/rest/dto/user"
+
+const (
+	EventEmployeeCreated = "employee.created"
+	EventEmployeeUpdated = "employee.updated"
+)
+
+// HREvent - pushed by the HR system on every change of an employee
+type HREvent struct {
+	Event    string       `json:"event"`
+	Employee user.Request `json:"employee"`
+}
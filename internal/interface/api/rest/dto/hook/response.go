@@ -0,0 +1,9 @@
+package hook
+
+import "github.com/google/uuid"
+
+// HRResult - Result is created or updated
+type HRResult struct {
+	UUID   uuid.UUID `json:"uuid"`
+	Result string    `json:"result"`
+}
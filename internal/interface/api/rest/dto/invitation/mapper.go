@@ -0,0 +1,21 @@
+package invitation
+
+import (
+	"strings"
+
+	"user-manager-api/internal/domain/user"
+	dtoUser "user-manager-api/internal/interface/api/rest/dto/user"
+)
+
+// ToDomainUser - the profile of the invitee, the email and role are set by the invitation
+func ToDomainUser(r AcceptRequest) (user.User, error) {
+	return dtoUser.ToDomainUser(dtoUser.Request{
+		Name:       r.Name,
+		MiddleName: r.MiddleName,
+		Lastname:   r.Lastname,
+		Suffix:     r.Suffix,
+		BirthDate:  strings.TrimSpace(r.BirthDate),
+		Phone:      strings.TrimSpace(r.Phone),
+		Timezone:   r.Timezone,
+	})
+}
@@ -0,0 +1,18 @@
+package invitation
+
+type Request struct {
+	Email string `json:"email"`
+	Role  string `json:"role"`
+}
+
+// AcceptRequest - the email and role come from the invitation
+type AcceptRequest struct {
+	Password   string `json:"password"`
+	Name       string `json:"name"`
+	MiddleName string `json:"middle_name"`
+	Lastname   string `json:"lastname"`
+	Suffix     string `json:"suffix"`
+	BirthDate  string `json:"birth_date"`
+	Phone      string `json:"phone"`
+	Timezone   string `json:"timezone"`
+}
@@ -0,0 +1,9 @@
+package invitation
+
+import "time"
+
+type Invitation struct {
+	Email     string    `json:"email"`
+	Role      string    `json:"role"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
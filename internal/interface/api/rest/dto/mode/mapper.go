@@ -0,0 +1,13 @@
+package mode
+
+import "user-manager-api/internal/domain/mode"
+
+func ToResponseReadOnly(ro mode.ReadOnly) ReadOnly {
+	resp := ReadOnly{Enabled: ro.Enabled, Reason: ro.Reason, Forced: ro.Forced, UpdatedAt: ro.UpdatedAt}
+	if ro.UpdatedBy != nil {
+		by := ro.UpdatedBy.String()
+		resp.UpdatedBy = &by
+	}
+
+	return resp
+}
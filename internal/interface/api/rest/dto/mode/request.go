@@ -0,0 +1,10 @@
+package mode
+
+type (
+	ReadOnlyRequest struct {
+		// Enabled - required
+		Enabled *bool `json:"enabled"`
+		// Reason - required to enable, shown to the admins
+		Reason string `json:"reason"`
+	}
+)
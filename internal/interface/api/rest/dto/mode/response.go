@@ -0,0 +1,15 @@
+package mode
+
+import "time"
+
+type (
+	ReadOnly struct {
+		Enabled bool   `json:"enabled"`
+		Reason  string `json:"reason"`
+		// Forced - "config" or "schema" if the instance answering is read-only
+		// whatever the toggle
+		Forced    string    `json:"forced,omitempty"`
+		UpdatedBy *string   `json:"updated_by"`
+		UpdatedAt time.Time `json:"updated_at"`
+	}
+)
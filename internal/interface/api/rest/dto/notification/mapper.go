@@ -0,0 +1,40 @@
+package notification
+
+import (
+	"strings"
+
+	"user-manager-api/internal/domain/notification"
+)
+
+func ToResponsePreferences(pDomain notification.Preferences) Preferences {
+	res := Preferences{Preferences: make([]Preference, len(pDomain))}
+	for i, p := range pDomain {
+		res.Preferences[i] = Preference{
+			Channel:    string(p.Channel),
+			Enabled:    p.Enabled,
+			Mode:       string(p.Mode),
+			WebhookURL: p.WebhookURL,
+		}
+	}
+
+	return res
+}
+
+// ToDomainPreferences - the mode defaults to instant
+func ToDomainPreferences(req Request) notification.Preferences {
+	res := make(notification.Preferences, len(req.Preferences))
+	for i, p := range req.Preferences {
+		mode := notification.Mode(p.Mode)
+		if mode == "" {
+			mode = notification.ModeInstant
+		}
+		res[i] = notification.Preference{
+			Channel:    notification.Channel(p.Channel),
+			Enabled:    p.Enabled,
+			Mode:       mode,
+			WebhookURL: strings.TrimSpace(p.WebhookURL),
+		}
+	}
+
+	return res
+}
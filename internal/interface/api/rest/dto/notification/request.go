@@ -0,0 +1,8 @@
+package notification
+
+type (
+	// Request - the channels to set, the others are kept
+	Request struct {
+		Preferences []Preference `json:"preferences"`
+	}
+)
@@ -0,0 +1,14 @@
+package notification
+
+type (
+	Preference struct {
+		Channel string `json:"channel"`
+		Enabled bool   `json:"enabled"`
+		// Mode - "instant"(default) or "digest"
+		Mode       string `json:"mode"`
+		WebhookURL string `json:"webhook_url,omitempty"`
+	}
+	Preferences struct {
+		Preferences []Preference `json:"preferences"`
+	}
+)
@@ -0,0 +1,39 @@
+package projection
+
+import (
+	"time"
+
+	"user-manager-api/internal/domain/projection"
+)
+
+func ToResponseUserSummary(s projection.UserSummary) UserSummary {
+	resp := UserSummary{
+		UserUUID:      s.UserUUID,
+		SignedUpAt:    s.SignedUpAt,
+		UpdatedAt:     s.UpdatedAt,
+		DeletedAt:     s.DeletedAt,
+		DeletedReason: s.DeletedReason,
+		MergedInto:    s.MergedInto,
+		EmailChanges:  s.EmailChanges,
+		Logins:        s.Logins,
+		FailedLogins:  s.FailedLogins,
+		LastLoginAt:   s.LastLoginAt,
+		LastEventAt:   s.LastEventAt,
+		Files: Files{
+			Count:         s.Files.Count,
+			Changes:       s.Files.Changes,
+			LastChangedAt: s.Files.LastChangedAt,
+		},
+		Activity: make([]DailyActivity, len(s.Activity)),
+	}
+	for idx, a := range s.Activity {
+		resp.Activity[idx] = DailyActivity{
+			Day:          a.Day.Format(time.DateOnly),
+			Events:       a.Events,
+			Logins:       a.Logins,
+			FailedLogins: a.FailedLogins,
+		}
+	}
+
+	return resp
+}
@@ -0,0 +1,37 @@
+package projection
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type (
+	UserSummary struct {
+		UserUUID      uuid.UUID       `json:"user_uuid"`
+		SignedUpAt    *time.Time      `json:"signed_up_at"`
+		UpdatedAt     *time.Time      `json:"updated_at"`
+		DeletedAt     *time.Time      `json:"deleted_at"`
+		DeletedReason string          `json:"deleted_reason,omitempty"`
+		MergedInto    *uuid.UUID      `json:"merged_into,omitempty"`
+		EmailChanges  int             `json:"email_changes"`
+		Logins        int             `json:"logins"`
+		FailedLogins  int             `json:"failed_logins"`
+		LastLoginAt   *time.Time      `json:"last_login_at"`
+		LastEventAt   time.Time       `json:"last_event_at"`
+		Files         Files           `json:"files"`
+		Activity      []DailyActivity `json:"activity"`
+	}
+	Files struct {
+		Count         int64      `json:"count"`
+		Changes       int        `json:"changes"`
+		LastChangedAt *time.Time `json:"last_changed_at"`
+	}
+	DailyActivity struct {
+		// Day - YYYY-MM-DD, UTC
+		Day          string `json:"day"`
+		Events       int    `json:"events"`
+		Logins       int    `json:"logins"`
+		FailedLogins int    `json:"failed_logins"`
+	}
+)
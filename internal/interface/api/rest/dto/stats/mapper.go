@@ -0,0 +1,25 @@
+package stats
+
+import (
+	"time"
+
+	"user-manager-api/internal/domain/stats"
+)
+
+func ToSignupsResponseData(days []stats.DailySignups) SignupsResponseData {
+	resp := SignupsResponseData{Data: make([]DailySignups, len(days))}
+	for idx, d := range days {
+		resp.Data[idx] = DailySignups{Day: d.Day.Format(time.DateOnly), Signups: d.Count}
+		resp.Total += d.Count
+	}
+
+	return resp
+}
+
+func ToResponseFilesTotals(tDomain stats.FilesTotals) FilesTotals {
+	return FilesTotals{
+		UsersWithFiles: tDomain.UsersWithFiles,
+		FilesCount:     tDomain.FilesCount,
+		TotalBytes:     tDomain.TotalBytes,
+	}
+}
@@ -0,0 +1,18 @@
+package stats
+
+type (
+	DailySignups struct {
+		// Day - YYYY-MM-DD, UTC
+		Day     string `json:"day"`
+		Signups uint64 `json:"signups"`
+	}
+	SignupsResponseData struct {
+		Data  []DailySignups `json:"data"`
+		Total uint64         `json:"total"`
+	}
+	FilesTotals struct {
+		UsersWithFiles uint64 `json:"users_with_files"`
+		FilesCount     uint64 `json:"files_count"`
+		TotalBytes     uint64 `json:"total_bytes"`
+	}
+)
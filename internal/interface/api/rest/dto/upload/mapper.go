@@ -0,0 +1,13 @@
+package upload
+
+import "user-manager-api/pkg/progress"
+
+func ToResponseProgress(s progress.Snapshot) Progress {
+	return Progress{
+		UploadID:  s.ID,
+		Received:  s.Received,
+		Total:     s.Total,
+		Status:    s.Status,
+		UpdatedAt: s.UpdatedAt,
+	}
+}
@@ -0,0 +1,15 @@
+package upload
+
+import "time"
+
+type (
+	Progress struct {
+		UploadID string `json:"upload_id"`
+		Received int64  `json:"received"`
+		// Total - the length of the request body, 0 if the client did not send it
+		Total int64 `json:"total"`
+		// Status - receiving, processing, done or failed
+		Status    string    `json:"status"`
+		UpdatedAt time.Time `json:"updated_at"`
+	}
+)
@@ -0,0 +1,45 @@
+package usage
+
+import (
+	"bytes"
+	"encoding/csv"
+	"strconv"
+	"strings"
+	"time"
+
+	"user-manager-api/internal/domain/usage"
+)
+
+const ContentTypeCSV = "text/csv; charset=utf-8"
+
+// ToMonthlyCSV - the billing records for the billing tooling(RFC 4180), one
+// row per month and organization after the header.
+func ToMonthlyCSV(records []usage.Monthly) []byte {
+	var b bytes.Buffer
+	w := csv.NewWriter(&b)
+	w.UseCRLF = true
+
+	_ = w.Write([]string{"month", "org", "active_users", "storage_bytes", "api_calls", "generated_at"})
+	for _, r := range records {
+		_ = w.Write([]string{
+			r.Month.Format("2006-01"),
+			csvSafe(r.Org),
+			strconv.FormatUint(r.ActiveUsers, 10),
+			strconv.FormatUint(r.StorageBytes, 10),
+			strconv.FormatUint(r.APICalls, 10),
+			r.GeneratedAt.UTC().Format(time.RFC3339),
+		})
+	}
+	w.Flush()
+
+	return b.Bytes()
+}
+
+// csvSafe - a spreadsheet runs a cell starting with a formula char as a formula
+func csvSafe(v string) string {
+	if v != "" && strings.ContainsRune("=+-@\t\r", rune(v[0])) {
+		return "'" + v
+	}
+
+	return v
+}
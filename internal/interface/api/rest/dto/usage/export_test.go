@@ -0,0 +1,29 @@
+package usage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"user-manager-api/internal/domain/usage"
+)
+
+func TestToMonthlyCSV(t *testing.T) {
+	generated := time.Date(2026, 10, 1, 3, 0, 0, 0, time.FixedZone("CEST", 2*60*60))
+	records := []usage.Monthly{
+		{Month: time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC), Org: "acme.com", ActiveUsers: 12, StorageBytes: 1 << 20, APICalls: 3400, GeneratedAt: generated},
+		{Month: time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC), Org: "anonymous", APICalls: 7, GeneratedAt: generated},
+		{Month: time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC), Org: "=cmd|x,y", ActiveUsers: 1, GeneratedAt: generated},
+	}
+
+	assert.Equal(t, "month,org,active_users,storage_bytes,api_calls,generated_at\r\n"+
+		"2026-09,acme.com,12,1048576,3400,2026-10-01T01:00:00Z\r\n"+
+		"2026-09,anonymous,0,0,7,2026-10-01T01:00:00Z\r\n"+
+		"2026-09,\"'=cmd|x,y\",1,0,0,2026-10-01T01:00:00Z\r\n",
+		string(ToMonthlyCSV(records)))
+}
+
+func TestToMonthlyCSV_Empty(t *testing.T) {
+	assert.Equal(t, "month,org,active_users,storage_bytes,api_calls,generated_at\r\n", string(ToMonthlyCSV(nil)))
+}
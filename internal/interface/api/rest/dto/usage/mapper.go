@@ -0,0 +1,37 @@
+package usage
+
+import (
+	"time"
+
+	"user-manager-api/internal/domain/usage"
+)
+
+func ToReportResponseData(from, to time.Time, totals []usage.Total) ReportResponseData {
+	resp := ReportResponseData{
+		From: from.Format(time.DateOnly),
+		To:   to.Format(time.DateOnly),
+		Data: make([]Total, len(totals)),
+	}
+	for idx, t := range totals {
+		resp.Data[idx] = Total{Org: t.Org, Role: t.Role, Requests: t.Requests}
+		if t.Requests > 0 {
+			resp.Data[idx].AvgDurationMs = t.DurationMs / t.Requests
+		}
+		resp.TotalRequests += t.Requests
+	}
+
+	return resp
+}
+
+func ToResponseSeatLimit(l usage.SeatLimit) SeatLimit {
+	return SeatLimit{Org: l.Org, Seats: l.Seats, ActiveUsers: l.ActiveUsers, UpdatedAt: l.UpdatedAt}
+}
+
+func ToSeatLimitsResponseData(limits []usage.SeatLimit) SeatLimitsResponseData {
+	resp := SeatLimitsResponseData{Data: make([]SeatLimit, len(limits))}
+	for idx, l := range limits {
+		resp.Data[idx] = ToResponseSeatLimit(l)
+	}
+
+	return resp
+}
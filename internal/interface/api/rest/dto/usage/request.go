@@ -0,0 +1,8 @@
+package usage
+
+type (
+	SeatLimitRequest struct {
+		// Seats - required, 0 blocks the new users of the organization
+		Seats *int64 `json:"seats"`
+	}
+)
@@ -0,0 +1,28 @@
+package usage
+
+import "time"
+
+type (
+	SeatLimit struct {
+		Org         string    `json:"org"`
+		Seats       uint32    `json:"seats"`
+		ActiveUsers uint64    `json:"active_users"`
+		UpdatedAt   time.Time `json:"updated_at"`
+	}
+	SeatLimitsResponseData struct {
+		Data []SeatLimit `json:"data"`
+	}
+	Total struct {
+		Org           string `json:"org"`
+		Role          string `json:"role"`
+		Requests      uint64 `json:"requests"`
+		AvgDurationMs uint64 `json:"avg_duration_ms"`
+	}
+	ReportResponseData struct {
+		// From, To - YYYY-MM-DD, UTC, both inclusive
+		From          string  `json:"from"`
+		To            string  `json:"to"`
+		Data          []Total `json:"data"`
+		TotalRequests uint64  `json:"total_requests"`
+	}
+)
@@ -0,0 +1,89 @@
+package user
+
+import (
+	"strings"
+	"time"
+
+	"user-manager-api/internal/domain/user"
+	"user-manager-api/pkg/pdf"
+)
+
+const (
+	ContentTypeVCard = "text/vcard; charset=utf-8"
+	ContentTypePDF   = "application/pdf"
+)
+
+// ToVCard - vCard 4.0(RFC 6350) for contact import. Blank(redacted) fields are omitted.
+func ToVCard(uDomain user.User) []byte {
+	var b strings.Builder
+	prop := func(name, value string) {
+		writeFolded(&b, name+":"+value)
+	}
+
+	prop("BEGIN", "VCARD")
+	prop("VERSION", "4.0")
+	prop("UID", "urn:uuid:"+uDomain.UUID.String())
+	prop("FN", vcardEscape(uDomain.FullName()))
+	// family;given;additional;prefixes;suffixes
+	prop("N", vcardEscape(uDomain.Lastname)+";"+vcardEscape(uDomain.Name)+";"+
+		vcardEscape(uDomain.MiddleName)+";;"+vcardEscape(uDomain.Suffix))
+	prop("EMAIL", vcardEscape(uDomain.Email))
+	if uDomain.Phone != "" {
+		prop("TEL;VALUE=uri;TYPE=cell", "tel:"+uDomain.Phone)
+	}
+	if !uDomain.BirthDate.IsZero() {
+		prop("BDAY", uDomain.BirthDate.Format("20060102"))
+	}
+	prop("END", "VCARD")
+
+	return []byte(b.String())
+}
+
+// ToPDF - printable profile for HR tooling.
+func ToPDF(uDomain user.User) ([]byte, error) {
+	doc := pdf.New()
+	doc.Title(uDomain.FullName())
+	doc.Field("UUID", uDomain.UUID.String())
+	doc.Field("Email", uDomain.Email)
+	doc.Field("Name", uDomain.Name)
+	if uDomain.MiddleName != "" {
+		doc.Field("Middle name", uDomain.MiddleName)
+	}
+	doc.Field("Lastname", uDomain.Lastname)
+	if uDomain.Suffix != "" {
+		doc.Field("Suffix", uDomain.Suffix)
+	}
+	if !uDomain.BirthDate.IsZero() {
+		doc.Field("Birth date", uDomain.BirthDate.Format(time.DateOnly))
+	}
+	doc.Field("Phone", uDomain.Phone)
+	doc.Field("Role", uDomain.Role)
+	if !uDomain.CreatedAt.IsZero() {
+		doc.Field("Registered", uDomain.CreatedAt.UTC().Format(time.RFC3339))
+	}
+
+	return doc.Bytes()
+}
+
+// vcardEscape - text value escaping(RFC 6350 3.4)
+func vcardEscape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, ",", `\,`, ";", `\;`, "\r\n", `\n`, "\n", `\n`).Replace(s)
+}
+
+// writeFolded writes a content line folded at 75 octets(RFC 6350 3.2) without
+// splitting UTF-8 sequences.
+func writeFolded(b *strings.Builder, line string) {
+	const limit = 75
+	n := 0
+	for _, r := range line {
+		size := len(string(r))
+		if n+size > limit {
+			b.WriteString("\r\n ")
+			// the leading space counts
+			n = 1
+		}
+		b.WriteRune(r)
+		n += size
+	}
+	b.WriteString("\r\n")
+}
@@ -0,0 +1,48 @@
+package user
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"user-manager-api/internal/domain/user"
+)
+
+func TestToVCard(t *testing.T) {
+	id := uuid.MustParse("8b0c3a1e-6f0e-4a52-9d3f-3c6a1d2b4e5f")
+	u := user.User{
+		UUID:      id,
+		Email:     "john.doe@example.com",
+		Name:      "John",
+		Lastname:  "Doe, Jr; III",
+		BirthDate: time.Date(1990, 1, 2, 0, 0, 0, 0, time.UTC),
+		Phone:     "+33612345678",
+	}
+
+	assert.Equal(t, "BEGIN:VCARD\r\n"+
+		"VERSION:4.0\r\n"+
+		"UID:urn:uuid:8b0c3a1e-6f0e-4a52-9d3f-3c6a1d2b4e5f\r\n"+
+		"FN:John Doe\\, Jr\\; III\r\n"+
+		"N:Doe\\, Jr\\; III;John;;;\r\n"+
+		"EMAIL:john.doe@example.com\r\n"+
+		"TEL;VALUE=uri;TYPE=cell:tel:+33612345678\r\n"+
+		"BDAY:19900102\r\n"+
+		"END:VCARD\r\n", string(ToVCard(u)))
+
+	t.Run("redacted fields are omitted", func(t *testing.T) {
+		out := string(ToVCard(user.User{UUID: id, Email: "a@b.c", Name: "A"}))
+		assert.NotContains(t, out, "TEL")
+		assert.NotContains(t, out, "BDAY")
+	})
+
+	t.Run("long lines are folded", func(t *testing.T) {
+		out := string(ToVCard(user.User{UUID: id, Email: "a@b.c", Name: strings.Repeat("é", 60)}))
+		for _, line := range strings.Split(strings.TrimSuffix(out, "\r\n"), "\r\n") {
+			assert.LessOrEqual(t, len(line), 75, line)
+		}
+		assert.Contains(t, out, "\r\n é")
+	})
+}
@@ -0,0 +1,50 @@
+package user
+
+import (
+	"time"
+
+	"user-manager-api/internal/domain/user"
+)
+
+type (
+	// Revision - the user as it was in [valid_from, valid_to), valid_to is
+	// missing for the current one. Changes - from the previous revision
+	Revision struct {
+		ValidFrom time.Time  `json:"valid_from"`
+		ValidTo   *time.Time `json:"valid_to,omitempty"`
+		User      AdminUser  `json:"user"`
+		Changes   []Change   `json:"changes,omitempty"`
+	}
+	// Change - From or To is empty for a value set or blanked
+	Change struct {
+		Field string `json:"field"`
+		From  string `json:"from"`
+		To    string `json:"to"`
+	}
+	HistoryResponse struct {
+		Data []Revision `json:"data"`
+	}
+	VersionResponse struct {
+		Data Revision `json:"data"`
+	}
+)
+
+func ToHistoryResponse(revs []user.Revision) HistoryResponse {
+	data := make([]Revision, len(revs))
+	for i, r := range revs {
+		data[i] = toRevision(r.Version)
+		for _, c := range r.Changes {
+			data[i].Changes = append(data[i].Changes, Change{Field: c.Field, From: c.From, To: c.To})
+		}
+	}
+
+	return HistoryResponse{Data: data}
+}
+
+func ToVersionResponse(v user.Version) VersionResponse {
+	return VersionResponse{Data: toRevision(v)}
+}
+
+func toRevision(v user.Version) Revision {
+	return Revision{ValidFrom: v.ValidFrom, ValidTo: v.ValidTo, User: ToAdminUser(v.User)}
+}
@@ -0,0 +1,25 @@
+package user
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"user-manager-api/internal/domain/user"
+)
+
+type (
+	// LegalHoldRequest - the reason is kept with the hold and in the audit log
+	LegalHoldRequest struct {
+		Reason string `json:"reason"`
+	}
+	LegalHold struct {
+		UserUUID uuid.UUID `json:"user_uuid"`
+		Reason   string    `json:"reason"`
+		Since    time.Time `json:"since"`
+	}
+)
+
+func ToResponseLegalHold(userUUID uuid.UUID, h user.LegalHold) LegalHold {
+	return LegalHold{UserUUID: userUUID, Reason: h.Reason, Since: h.Since}
+}
@@ -0,0 +1,20 @@
+package user
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"user-manager-api/internal/domain/user"
+)
+
+// Identity - the internal ID and the UUID of a user, DeletedAt of the deleted ones only
+type Identity struct {
+	ID        uint64     `json:"id"`
+	UserID    uuid.UUID  `json:"user_id"`
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+}
+
+func ToResponseIdentity(ident user.Identity) Identity {
+	return Identity{ID: uint64(ident.ID), UserID: ident.UUID, DeletedAt: ident.DeletedAt}
+}
@@ -2,8 +2,11 @@ package user
 
 import (
 	"errors"
+	"strings"
 	"time"
 
+	"github.com/google/uuid"
+
 	"user-manager-api/internal/domain/user"
 )
 
@@ -15,20 +18,94 @@ func ToResponseUser(uDomain user.User) User {
 		Lastname:  uDomain.Lastname,
 		BirthDate: uDomain.BirthDate,
 		Phone:     uDomain.Phone,
+
+		MiddleName:   uDomain.MiddleName,
+		Suffix:       uDomain.Suffix,
+		PendingEmail: uDomain.PendingEmail,
+		Timezone:     uDomain.Timezone,
+	}
+	if uDomain.Files != nil {
+		u.FilesCount = &uDomain.Files.Count
+		u.TotalStorageBytes = &uDomain.Files.TotalBytes
+	}
+	if uDomain.Birthday != nil {
+		u.Age = &uDomain.Birthday.Age
+		u.IsBirthdayToday = &uDomain.Birthday.IsToday
 	}
 
 	return u
 }
 
-func ToResponseUsers(usDomain user.Users) Users {
-	us := make(Users, len(usDomain))
+func ToPublicUser(uDomain user.User) PublicUser {
+	return PublicUser{
+		UUID: uDomain.UUID,
+		Name: uDomain.Name,
+	}
+}
+
+func ToAdminUser(uDomain user.User) AdminUser {
+	return AdminUser{
+		User:          ToResponseUser(uDomain),
+		Role:          uDomain.Role,
+		CreatedAt:     uDomain.CreatedAt,
+		UpdatedAt:     uDomain.UpdatedAt,
+		DeletedAt:     uDomain.DeletedAt,
+		DeletedReason: string(uDomain.DeletedReason),
+	}
+}
+
+func ToAdminUsers(usDomain user.Users) AdminUsers {
+	us := make(AdminUsers, len(usDomain))
 	for idx, u := range usDomain {
-		us[idx] = ToResponseUser(*u)
+		us[idx] = ToAdminUser(*u)
 	}
 
 	return us
 }
 
+// ToDomainRoleChanges - the request must be validated
+func ToDomainRoleChanges(r RolesRequest) []user.RoleChange {
+	changes := make([]user.RoleChange, len(r.Assignments))
+	for i, a := range r.Assignments {
+		changes[i] = user.RoleChange{
+			UUID: uuid.MustParse(strings.TrimSpace(a.UserID)),
+			Role: strings.TrimSpace(a.Role),
+		}
+	}
+
+	return changes
+}
+
+func ToRolesResponse(results []user.RoleChangeResult) RolesResponse {
+	data := make([]RoleResult, len(results))
+	for i, r := range results {
+		data[i] = RoleResult{
+			UserID:       r.UUID,
+			Role:         r.Role,
+			PreviousRole: r.PrevRole,
+			Result:       string(r.Status),
+		}
+	}
+
+	return RolesResponse{Data: data}
+}
+
+func ToDuplicatesResponse(groups []user.DuplicateGroup) DuplicatesResponse {
+	data := make([]DuplicateGroup, len(groups))
+	for i, g := range groups {
+		data[i] = DuplicateGroup{Reason: string(g.Reason), Key: g.Key, UserIDs: g.Users}
+	}
+
+	return DuplicatesResponse{Data: data}
+}
+
+func ToMergeResponse(winner user.User, res user.MergeResult) MergeResponse {
+	return MergeResponse{
+		User:  ToAdminUser(winner),
+		Moved: MergedCount{Files: res.Files, Notes: res.Notes, Audit: res.Audit},
+	}
+}
+
 func ToDomainUser(uRequest Request) (user.User, error) {
 	d, err := time.Parse("2006-01-02", uRequest.BirthDate)
 	if err != nil {
@@ -36,11 +113,14 @@ func ToDomainUser(uRequest Request) (user.User, error) {
 	}
 
 	var u = user.User{
-		Email:     uRequest.Email,
-		Name:      uRequest.Name,
-		Lastname:  uRequest.Lastname,
-		BirthDate: d,
-		Phone:     uRequest.Phone,
+		Email:      uRequest.Email,
+		Name:       user.NormalizeName(uRequest.Name),
+		MiddleName: user.NormalizeName(uRequest.MiddleName),
+		Lastname:   user.NormalizeName(uRequest.Lastname),
+		Suffix:     user.NormalizeName(uRequest.Suffix),
+		BirthDate:  d,
+		Phone:      uRequest.Phone,
+		Timezone:   strings.TrimSpace(uRequest.Timezone),
 	}
 
 	return u, nil
@@ -0,0 +1,54 @@
+package user
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"user-manager-api/internal/domain/user"
+)
+
+// benchPageSize - the default page of GET /users
+const benchPageSize = 50
+
+func benchUsers() user.Users {
+	ts := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	us := make(user.Users, benchPageSize)
+	for i := range us {
+		us[i] = &user.User{
+			UUID:      uuid.New(),
+			Email:     fmt.Sprintf("user%d@example.com", i),
+			Role:      "user",
+			Name:      "John",
+			Lastname:  "Doe",
+			BirthDate: time.Date(1990, 1, 2, 0, 0, 0, 0, time.UTC),
+			Phone:     "+33612345678",
+			CreatedAt: ts,
+			UpdatedAt: ts,
+		}
+	}
+
+	return us
+}
+
+func BenchmarkToAdminUsers(b *testing.B) {
+	us := benchUsers()
+	b.ReportAllocs()
+	for b.Loop() {
+		_ = ToAdminUsers(us)
+	}
+}
+
+// BenchmarkResponseData_Marshal - mapping and encoding of a GET /users page
+func BenchmarkResponseData_Marshal(b *testing.B) {
+	us := benchUsers()
+	b.ReportAllocs()
+	for b.Loop() {
+		if _, err := json.Marshal(ResponseData{Data: ToAdminUsers(us), NextCursor: "cursor"}); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
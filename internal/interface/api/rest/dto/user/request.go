@@ -1,9 +1,30 @@
 package user
 
 type Request struct {
-	Email     string `json:"email"`
-	Name      string `json:"name"`
-	Lastname  string `json:"lastname"`
-	BirthDate string `json:"birth_date"`
-	Phone     string `json:"phone"`
+	Email string `json:"email"`
+	Name  string `json:"name"`
+	// MiddleName, Suffix(e.g. Jr., III) - optional
+	MiddleName string `json:"middle_name"`
+	Lastname   string `json:"lastname"`
+	Suffix     string `json:"suffix"`
+	BirthDate  string `json:"birth_date"`
+	Phone      string `json:"phone"`
+	// Timezone - IANA name, optional: empty - the one of the organization
+	Timezone string `json:"timezone"`
+}
+
+// RolesRequest - bulk role assignment
+type RolesRequest struct {
+	Assignments []RoleAssignment `json:"assignments"`
+}
+
+type RoleAssignment struct {
+	UserID string `json:"user_id"`
+	Role   string `json:"role"`
+}
+
+// MergeRequest - the loser is merged into the winner and deleted
+type MergeRequest struct {
+	WinnerID string `json:"winner_id"`
+	LoserID  string `json:"loser_id"`
 }
@@ -14,9 +14,78 @@ type (
 		Lastname  string    `json:"lastname"`
 		BirthDate time.Time `json:"birth_date"`
 		Phone     string    `json:"phone"`
+		// MiddleName, Suffix - optional parts of the name
+		MiddleName string `json:"middle_name,omitempty"`
+		Suffix     string `json:"suffix,omitempty"`
+		// PendingEmail - requested email awaiting confirmation
+		PendingEmail string `json:"pending_email,omitempty"`
+		// Timezone - own IANA timezone, empty - the one of the organization
+		Timezone string `json:"timezone,omitempty"`
+		// FilesCount, TotalStorageBytes - active files, only in the profile reads
+		FilesCount        *uint64 `json:"files_count,omitempty"`
+		TotalStorageBytes *uint64 `json:"total_storage_bytes,omitempty"`
+		// Age, IsBirthdayToday - as of today in the user's timezone, only in the profile reads
+		Age             *int  `json:"age,omitempty"`
+		IsBirthdayToday *bool `json:"is_birthday_today,omitempty"`
 	}
-	Users        []User
+	Users []User
+
+	// PublicUser - what anyone may see about a user
+	PublicUser struct {
+		UUID uuid.UUID `json:"uuid"`
+		Name string    `json:"name"`
+	}
+	// AdminUser - the full profile with the account metadata
+	AdminUser struct {
+		User
+		Role          string     `json:"role"`
+		CreatedAt     time.Time  `json:"created_at"`
+		UpdatedAt     time.Time  `json:"updated_at"`
+		DeletedAt     *time.Time `json:"deleted_at,omitempty"`
+		DeletedReason string     `json:"deleted_reason,omitempty"`
+	}
+	AdminUsers []AdminUser
+
 	ResponseData struct {
-		Data Users `json:"data"`
+		Data       AdminUsers `json:"data"`
+		NextCursor string     `json:"next_cursor,omitempty"`
+	}
+
+	// ValidationResponse - the dry run of a signup, Errors keyed by the request field
+	ValidationResponse struct {
+		Valid  bool              `json:"valid"`
+		Errors map[string]string `json:"errors,omitempty"`
+	}
+
+	// RoleResult - Result is updated, unchanged, not_found or last_admin
+	RoleResult struct {
+		UserID       uuid.UUID `json:"user_id"`
+		Role         string    `json:"role"`
+		PreviousRole string    `json:"previous_role,omitempty"`
+		Result       string    `json:"result"`
+	}
+	RolesResponse struct {
+		Data []RoleResult `json:"data"`
+	}
+
+	// DuplicateGroup - Reason is phone, email_alias or name_birth_date, Key the shared value
+	DuplicateGroup struct {
+		Reason  string      `json:"reason"`
+		Key     string      `json:"key"`
+		UserIDs []uuid.UUID `json:"user_ids"`
+	}
+	DuplicatesResponse struct {
+		Data []DuplicateGroup `json:"data"`
+	}
+
+	// MergeResponse - the winner and the counts of its records moved from the loser
+	MergeResponse struct {
+		User  AdminUser   `json:"user"`
+		Moved MergedCount `json:"moved"`
+	}
+	MergedCount struct {
+		Files int `json:"files"`
+		Notes int `json:"notes"`
+		Audit int `json:"audit"`
 	}
 )
@@ -1,17 +1,25 @@
 package user_file
 
 import (
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+
 	"user-manager-api/internal/domain/user_file"
 )
 
 func ToResponseUserFile(uDomain user_file.UserFile) UserFile {
 	var uf = UserFile{
-		UUID:        uDomain.UUID,
-		FileName:    uDomain.FileName,
-		MimeType:    uDomain.MimeType,
-		SizeBytes:   uDomain.SizeBytes,
-		StorageKey:  uDomain.StorageKey,
-		DownloadURL: uDomain.DownloadURL,
+		UUID:         uDomain.UUID,
+		FileName:     uDomain.FileName,
+		MimeType:     uDomain.MimeType,
+		SizeBytes:    uDomain.SizeBytes,
+		StorageKey:   uDomain.StorageKey,
+		DownloadURL:  uDomain.DownloadURL,
+		ThumbnailURL: uDomain.ThumbnailURL,
+		Tags:         uDomain.Tags,
+		Folder:       uDomain.Folder,
 	}
 
 	return uf
@@ -25,3 +33,125 @@ func ToResponseUserFiles(ufDomain user_file.UserFiles) UserFiles {
 
 	return ufs
 }
+
+func ToAdminUserFiles(ufDomain user_file.UserFiles) AdminUserFiles {
+	ufs := make(AdminUserFiles, len(ufDomain))
+	for idx, u := range ufDomain {
+		ufs[idx] = AdminUserFile{
+			UserFile:  ToResponseUserFile(*u),
+			UserUUID:  u.UserUUID,
+			CreatedAt: u.CreatedAt,
+		}
+	}
+
+	return ufs
+}
+
+func ToResponseStats(sDomain user_file.Stats) Stats {
+	s := Stats{
+		FilesCount: sDomain.FilesCount,
+		TotalBytes: sDomain.TotalBytes,
+		ByMimeType: make([]MimeTypeStats, len(sDomain.ByMimeType)),
+	}
+	for idx, m := range sDomain.ByMimeType {
+		s.ByMimeType[idx] = MimeTypeStats{
+			MimeType:   m.MimeType,
+			FilesCount: m.FilesCount,
+			TotalBytes: m.TotalBytes,
+		}
+	}
+
+	return s
+}
+
+func ToResponseFolders(fDomain user_file.Folders) FoldersResponse {
+	resp := FoldersResponse{Data: make([]Folder, len(fDomain))}
+	for idx, f := range fDomain {
+		resp.Data[idx] = Folder{
+			Path:       f.Path,
+			FilesCount: f.FilesCount,
+			TotalBytes: f.TotalBytes,
+		}
+	}
+
+	return resp
+}
+
+func ToResponseText(fileUUID uuid.UUID, tDomain user_file.Text) Text {
+	return Text{
+		FileUUID:    fileUUID,
+		Status:      tDomain.Status,
+		Text:        tDomain.Body,
+		ExtractedAt: tDomain.ExtractedAt,
+	}
+}
+
+func ToResponseRetentionRule(rDomain user_file.RetentionRule) RetentionRule {
+	return RetentionRule{
+		UUID:      rDomain.UUID,
+		MimeType:  rDomain.MimeType,
+		Tag:       rDomain.Tag,
+		Days:      rDomain.Days,
+		CreatedAt: rDomain.CreatedAt,
+	}
+}
+
+func ToResponseRetentionRules(rsDomain user_file.RetentionRules) RetentionRulesResponse {
+	resp := RetentionRulesResponse{Data: make([]RetentionRule, len(rsDomain))}
+	for idx, r := range rsDomain {
+		resp.Data[idx] = ToResponseRetentionRule(r)
+	}
+
+	return resp
+}
+
+func ToDomainRetentionRule(r RetentionRuleRequest) user_file.RetentionRule {
+	return user_file.RetentionRule{
+		MimeType: strings.ToLower(strings.TrimSpace(r.MimeType)),
+		Tag:      strings.ToLower(strings.TrimSpace(r.Tag)),
+		Days:     *r.Days,
+	}
+}
+
+func ToResponseUploadPolicy(pDomain user_file.UploadPolicy) UploadPolicy {
+	p := UploadPolicy{
+		Org:          pDomain.Org,
+		AllowedTypes: pDomain.AllowedTypes,
+		MaxFileSize:  pDomain.MaxFileSize,
+		QuotaBytes:   pDomain.QuotaBytes,
+		UpdatedAt:    pDomain.UpdatedAt,
+	}
+	if p.AllowedTypes == nil {
+		p.AllowedTypes = []string{}
+	}
+
+	return p
+}
+
+func ToResponseUploadPolicies(psDomain user_file.UploadPolicies) UploadPoliciesResponse {
+	resp := UploadPoliciesResponse{Data: make([]UploadPolicy, len(psDomain))}
+	for idx, p := range psDomain {
+		resp.Data[idx] = ToResponseUploadPolicy(p)
+	}
+
+	return resp
+}
+
+// ToDomainUploadPolicy - of a validated request, the types lowercased and deduplicated
+func ToDomainUploadPolicy(org string, r UploadPolicyRequest) user_file.UploadPolicy {
+	p := user_file.UploadPolicy{Org: org, AllowedTypes: []string{}}
+	for _, t := range r.AllowedTypes {
+		t = strings.ToLower(strings.TrimSpace(t))
+		if !slices.Contains(p.AllowedTypes, t) {
+			p.AllowedTypes = append(p.AllowedTypes, t)
+		}
+	}
+	if r.MaxFileSize != nil {
+		p.MaxFileSize = uint64(*r.MaxFileSize)
+	}
+	if r.QuotaBytes != nil {
+		p.QuotaBytes = uint64(*r.QuotaBytes)
+	}
+
+	return p
+}
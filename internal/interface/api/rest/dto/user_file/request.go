@@ -0,0 +1,37 @@
+package user_file
+
+import "time"
+
+type (
+	// MoveRequest - a move and/or a rename, a nil field is kept
+	MoveRequest struct {
+		// Folder - "a/b", "" - the root
+		Folder   *string `json:"folder"`
+		FileName *string `json:"file_name"`
+	}
+	// FolderMoveRequest - moves(renames) a folder with its subfolders
+	FolderMoveRequest struct {
+		From string `json:"from"`
+		// To - "" - the root
+		To string `json:"to"`
+	}
+	// RetentionRuleRequest - one of MimeType("image/*" wildcard) and Tag
+	RetentionRuleRequest struct {
+		MimeType string `json:"mime_type"`
+		Tag      string `json:"tag"`
+		Days     *int   `json:"days"`
+	}
+	// UploadPolicyRequest - replaces the policy of an organization, a nil or 0
+	// limit - none of its own, no allowed types - any type
+	UploadPolicyRequest struct {
+		// AllowedTypes - "type/subtype" or the "type/*" wildcards
+		AllowedTypes []string `json:"allowed_types"`
+		MaxFileSize  *int64   `json:"max_file_size"`
+		QuotaBytes   *int64   `json:"quota_bytes"`
+	}
+	// FileRetentionRequest - replaces the override, RetainUntil null - the rules apply
+	FileRetentionRequest struct {
+		RetainUntil *time.Time `json:"retain_until"`
+		LegalHold   *bool      `json:"legal_hold"`
+	}
+)
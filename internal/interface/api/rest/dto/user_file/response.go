@@ -1,20 +1,95 @@
 package user_file
 
 import (
+	"time"
+
 	"github.com/google/uuid"
 )
 
 type (
 	UserFile struct {
-		UUID        uuid.UUID `json:"uuid"`
-		FileName    string    `json:"file_name"`
-		MimeType    string    `json:"mime_type"`
-		SizeBytes   uint64    `json:"size_bytes"`
-		StorageKey  string    `json:"storage_key"`
-		DownloadURL string    `json:"download_url"`
+		UUID         uuid.UUID `json:"uuid"`
+		FileName     string    `json:"file_name"`
+		MimeType     string    `json:"mime_type"`
+		SizeBytes    uint64    `json:"size_bytes"`
+		StorageKey   string    `json:"storage_key"`
+		DownloadURL  string    `json:"download_url"`
+		ThumbnailURL string    `json:"thumbnail_url,omitempty"`
+		Tags         []string  `json:"tags"`
+		// Folder - "a/b", "" - the root
+		Folder string `json:"folder"`
 	}
 	UserFiles    []UserFile
 	ResponseData struct {
-		Data UserFiles `json:"data"`
+		Data       UserFiles `json:"data"`
+		NextCursor string    `json:"next_cursor,omitempty"`
+	}
+
+	// AdminUserFile - a file in the cross-user browsing
+	AdminUserFile struct {
+		UserFile
+		UserUUID  uuid.UUID `json:"user_uuid"`
+		CreatedAt time.Time `json:"created_at"`
+	}
+	AdminUserFiles []AdminUserFile
+	Stats          struct {
+		FilesCount uint64          `json:"files_count"`
+		TotalBytes uint64          `json:"total_bytes"`
+		ByMimeType []MimeTypeStats `json:"by_mime_type"`
+	}
+	MimeTypeStats struct {
+		MimeType   string `json:"mime_type"`
+		FilesCount uint64 `json:"files_count"`
+		TotalBytes uint64 `json:"total_bytes"`
+	}
+	// AdminResponseData - Stats cover all matching files, not only the page
+	AdminResponseData struct {
+		Data       AdminUserFiles `json:"data"`
+		NextCursor string         `json:"next_cursor,omitempty"`
+		Stats      Stats          `json:"stats"`
+	}
+
+	Folder struct {
+		Path       string `json:"path"`
+		FilesCount uint64 `json:"files_count"`
+		TotalBytes uint64 `json:"total_bytes"`
+	}
+	FoldersResponse struct {
+		Data []Folder `json:"data"`
+	}
+	FolderMoveResponse struct {
+		Moved int64 `json:"moved"`
+	}
+	// Text - Text is empty if the extraction failed
+	Text struct {
+		FileUUID    uuid.UUID `json:"file_uuid"`
+		Status      string    `json:"status"`
+		Text        string    `json:"text"`
+		ExtractedAt time.Time `json:"extracted_at"`
+	}
+	RetentionRule struct {
+		UUID      uuid.UUID `json:"uuid"`
+		MimeType  string    `json:"mime_type,omitempty"`
+		Tag       string    `json:"tag,omitempty"`
+		Days      int       `json:"days"`
+		CreatedAt time.Time `json:"created_at"`
+	}
+	RetentionRulesResponse struct {
+		Data []RetentionRule `json:"data"`
+	}
+	UploadPolicy struct {
+		Org          string    `json:"org"`
+		AllowedTypes []string  `json:"allowed_types"`
+		MaxFileSize  uint64    `json:"max_file_size"`
+		QuotaBytes   uint64    `json:"quota_bytes"`
+		UpdatedAt    time.Time `json:"updated_at"`
+	}
+	UploadPoliciesResponse struct {
+		Data []UploadPolicy `json:"data"`
+	}
+	FileRetention struct {
+		FileUUID    uuid.UUID  `json:"file_uuid"`
+		RetainUntil *time.Time `json:"retain_until"`
+		LegalHold   bool       `json:"legal_hold"`
 	}
 )
@@ -0,0 +1,31 @@
+package user_note
+
+import (
+	"strings"
+
+	"user-manager-api/internal/domain/user_note"
+)
+
+func ToResponseNote(nDomain user_note.Note) Note {
+	var n = Note{
+		UUID:       nDomain.UUID,
+		AuthorUUID: nDomain.AuthorUUID,
+		Body:       nDomain.Body,
+		CreatedAt:  nDomain.CreatedAt,
+	}
+
+	return n
+}
+
+func ToResponseNotes(nsDomain user_note.Notes) Notes {
+	ns := make(Notes, len(nsDomain))
+	for idx, n := range nsDomain {
+		ns[idx] = ToResponseNote(*n)
+	}
+
+	return ns
+}
+
+func ToDomainNote(nRequest Request) user_note.Note {
+	return user_note.Note{Body: strings.TrimSpace(nRequest.Body)}
+}
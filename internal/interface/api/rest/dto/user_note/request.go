@@ -0,0 +1,5 @@
+package user_note
+
+type Request struct {
+	Body string `json:"body"`
+}
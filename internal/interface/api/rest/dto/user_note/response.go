@@ -0,0 +1,21 @@
+package user_note
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type (
+	Note struct {
+		UUID       uuid.UUID `json:"uuid"`
+		AuthorUUID uuid.UUID `json:"author_uuid"`
+		Body       string    `json:"body"`
+		CreatedAt  time.Time `json:"created_at"`
+	}
+	Notes        []Note
+	ResponseData struct {
+		Data       Notes  `json:"data"`
+		NextCursor string `json:"next_cursor,omitempty"`
+	}
+)
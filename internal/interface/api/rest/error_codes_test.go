@@ -0,0 +1,132 @@
+package rest
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"user-manager-api/internal/interface/api/rest/middleware"
+)
+
+func TestErrorCodesMiddleware_Table(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name       string
+		handler    gin.HandlerFunc
+		wantStatus int
+		wantBody   string
+		wantCode   string
+	}{
+		{
+			name:       "ok passes",
+			handler:    func(c *gin.Context) { c.JSON(http.StatusOK, gin.H{"id": 1}) },
+			wantStatus: http.StatusOK,
+			wantBody:   `{"id":1}`,
+		},
+		{
+			name: "code of the handler",
+			handler: func(c *gin.Context) {
+				c.JSON(http.StatusNotFound, gin.H{"error": "user not found", "code": codeUserNotFound})
+			},
+			wantStatus: http.StatusNotFound,
+			wantBody:   `{"error":"user not found","code":"user_not_found"}`,
+			wantCode:   codeUserNotFound,
+		},
+		{
+			name: "code of the status",
+			handler: func(c *gin.Context) {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body", "details": gin.H{"email": "email is required"}})
+			},
+			wantStatus: http.StatusBadRequest,
+			wantBody:   `{"error":"invalid request body","details":{"email":"email is required"},"code":"bad_request"}`,
+			wantCode:   "bad_request",
+		},
+		{
+			name: "code of an unlisted status",
+			handler: func(c *gin.Context) {
+				c.JSON(http.StatusTeapot, gin.H{"error": "short and stout"})
+			},
+			wantStatus: http.StatusTeapot,
+			wantBody:   `{"error":"short and stout","code":"client_error"}`,
+			wantCode:   "client_error",
+		},
+		{
+			name: "unbounded code",
+			handler: func(c *gin.Context) {
+				c.JSON(http.StatusConflict, gin.H{"error": "conflict", "code": "Email Taken!"})
+			},
+			wantStatus: http.StatusConflict,
+			wantBody:   `{"error":"conflict","code":"Email Taken!"}`,
+			wantCode:   middleware.CodeOther,
+		},
+		{
+			name:       "no body",
+			handler:    func(c *gin.Context) { c.AbortWithStatus(http.StatusForbidden) },
+			wantStatus: http.StatusForbidden,
+			wantCode:   "forbidden",
+		},
+		{
+			name: "not a JSON body",
+			handler: func(c *gin.Context) {
+				c.String(http.StatusInternalServerError, "oops")
+			},
+			wantStatus: http.StatusInternalServerError,
+			wantBody:   "oops",
+			wantCode:   "internal",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mErrors := prometheus.NewCounterVec(prometheus.CounterOpts{Name: "test"}, []string{"code"})
+			r := gin.New()
+			r.Use(middleware.ErrorCodes(mErrors))
+			r.GET(RouteHealth, tt.handler)
+
+			w := doReq(t, r, http.MethodGet, RouteHealth, nil, nil)
+			require.Equal(t, tt.wantStatus, w.Code)
+			if strings.HasPrefix(tt.wantBody, "{") {
+				assert.JSONEq(t, tt.wantBody, w.Body.String())
+			} else {
+				assert.Equal(t, tt.wantBody, w.Body.String())
+			}
+
+			if tt.wantCode == "" {
+				assert.Zero(t, testutil.CollectAndCount(mErrors))
+				return
+			}
+			assert.Equal(t, 1, testutil.CollectAndCount(mErrors))
+			assert.Equal(t, float64(1), testutil.ToFloat64(mErrors.WithLabelValues(tt.wantCode)))
+		})
+	}
+}
+
+func TestErrorCodesMiddleware_Timeout(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mErrors := prometheus.NewCounterVec(prometheus.CounterOpts{Name: "test"}, []string{"code"})
+	mCounter := prometheus.NewCounterVec(prometheus.CounterOpts{Name: "test_counter"}, []string{"result"})
+	r := gin.New()
+	r.Use(
+		middleware.ErrorCodes(mErrors),
+		middleware.RequestTimeout(10*time.Millisecond, 0, nil, zap.NewNop(), mCounter),
+	)
+	r.GET(RouteHealth, func(c *gin.Context) {
+		<-c.Request.Context().Done()
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get a user"})
+	})
+
+	w := doReq(t, r, http.MethodGet, RouteHealth, nil, nil)
+	require.Equal(t, http.StatusGatewayTimeout, w.Code)
+	assert.JSONEq(t, `{"error":"the request took too long, try again later","code":"timeout","timeout":"10ms"}`, w.Body.String())
+	assert.Equal(t, float64(1), testutil.ToFloat64(mErrors.WithLabelValues(middleware.CodeTimeout)))
+	assert.Equal(t, 1, testutil.CollectAndCount(mErrors))
+}
@@ -0,0 +1,41 @@
+package rest
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterFallbackHandlers replaces gin's plain text 404 for unknown routes and
+// enables 405 for known routes called with a wrong method.
+func RegisterFallbackHandlers(r *gin.Engine) {
+	r.HandleMethodNotAllowed = true
+	r.NoRoute(noRouteHandler)
+	r.NoMethod(noMethodHandler)
+}
+
+func noRouteHandler(c *gin.Context) {
+	hint := "see the OpenAPI specification for the available routes"
+	if !strings.HasPrefix(c.Request.URL.Path, RouteApiV1+"/") {
+		hint = "all routes start with " + RouteApiV1
+	}
+
+	c.JSON(http.StatusNotFound, gin.H{
+		"error": "route not found",
+		"path":  c.Request.URL.Path,
+		"hint":  hint,
+	})
+}
+
+// noMethodHandler - gin sets the "Allow" header before calling it.
+func noMethodHandler(c *gin.Context) {
+	allowed := strings.Split(c.Writer.Header().Get("Allow"), ", ")
+
+	c.JSON(http.StatusMethodNotAllowed, gin.H{
+		"error":           "method not allowed",
+		"method":          c.Request.Method,
+		"allowed_methods": allowed,
+		"hint":            "use one of allowed_methods",
+	})
+}
@@ -0,0 +1,88 @@
+package rest
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFallbackHandlers(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	RegisterFallbackHandlers(r)
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+	r.GET(RouteUsers, ok)
+	r.POST(RouteUsers, ok)
+
+	type tc struct {
+		name       string
+		method     string
+		path       string
+		wantStatus int
+		wantJSON   map[string]any
+		wantAllow  string
+	}
+	cases := []tc{
+		{
+			name:       "known route",
+			method:     http.MethodGet,
+			path:       RouteUsers,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "unknown api route",
+			method:     http.MethodGet,
+			path:       RouteApiV1 + "/nope",
+			wantStatus: http.StatusNotFound,
+			wantJSON: map[string]any{
+				"error": "route not found",
+				"path":  RouteApiV1 + "/nope",
+				"hint":  "see the OpenAPI specification for the available routes",
+			},
+		},
+		{
+			name:       "missing api prefix",
+			method:     http.MethodGet,
+			path:       "/users",
+			wantStatus: http.StatusNotFound,
+			wantJSON: map[string]any{
+				"error": "route not found",
+				"path":  "/users",
+				"hint":  "all routes start with " + RouteApiV1,
+			},
+		},
+		{
+			name:       "wrong method",
+			method:     http.MethodDelete,
+			path:       RouteUsers,
+			wantStatus: http.StatusMethodNotAllowed,
+			wantJSON: map[string]any{
+				"error":           "method not allowed",
+				"method":          http.MethodDelete,
+				"allowed_methods": []any{http.MethodGet, http.MethodPost},
+				"hint":            "use one of allowed_methods",
+			},
+			wantAllow: "GET, POST",
+		},
+	}
+
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			rr := doReq(t, r, tt.method, tt.path, nil, nil)
+			require.Equal(t, tt.wantStatus, rr.Code, rr.Body.String())
+			assert.Equal(t, tt.wantAllow, rr.Header().Get("Allow"))
+			if tt.wantJSON == nil {
+				return
+			}
+
+			var resp map[string]any
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+			assert.Equal(t, tt.wantJSON, resp)
+		})
+	}
+}
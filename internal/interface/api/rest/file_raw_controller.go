@@ -0,0 +1,199 @@
+package rest
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"user-manager-api/internal/application/ports"
+	"user-manager-api/internal/application/services"
+	domain "user-manager-api/internal/domain/user"
+	domainFile "user-manager-api/internal/domain/user_file"
+	"user-manager-api/internal/interface/api/rest/middleware"
+	"user-manager-api/internal/interface/api/rest/validator"
+)
+
+// inlineTypes - the types a browser shows inline with no script to run, the
+// others(HTML, SVG, ...) are always downloaded: the objects are served from
+// the origin of the API
+var inlineTypes = []string{"application/pdf", "text/plain", "image/png", "image/jpeg", "image/gif", "image/webp", "audio/", "video/"}
+
+// FileRawController serves objects for storages without their own public
+// endpoint(STORAGE_DRIVER=fs) or proxied by the API(S3_DOWNLOAD_MODE=proxy),
+// the Range requests included. Only the objects of the live files are served,
+// to their owners and the admins.
+type FileRawController struct {
+	reader          ports.ObjectReader
+	userFileService ports.UserFileService
+	logger          *zap.Logger
+}
+
+func NewFileRawController(
+	r *gin.Engine,
+	reader ports.ObjectReader,
+	userFileService ports.UserFileService,
+	logger *zap.Logger,
+	tokenService ports.TokenService,
+) *FileRawController {
+	frc := &FileRawController{
+		reader:          reader,
+		userFileService: userFileService,
+		logger:          logger,
+	}
+
+	r.GET(RouteFilesRaw, middleware.AuthMiddleware(tokenService), frc.GetRawFileHandler)
+
+	return frc
+}
+
+// GetRawFileHandler - "?disposition=inline|attachment", by the type by default:
+// an inline one of a type not in inlineTypes is downloaded anyway.
+// "?filename=" - the name to save it under, the name of the object by default
+func (frc *FileRawController) GetRawFileHandler(c *gin.Context) {
+	key := strings.TrimPrefix(c.Param("key"), "/")
+	if key == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "key is required"})
+		return
+	}
+	disposition, err := validator.ParseDisposition(c.Query("disposition"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	name, err := validator.ParseDownloadName(c.Query("filename"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// the keys of the deleted files and of the other users are not disclosed
+	owner, err := frc.userFileService.ObjectOwner(c.Request.Context(), key)
+	if err != nil {
+		if errors.Is(err, services.ErrUserFileNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "file not found"})
+			return
+		}
+		c.JSON(
+			http.StatusInternalServerError,
+			gin.H{"error": "failed to get a file"},
+		)
+		frc.logger.Error("ObjectOwner() error", zap.Error(err))
+		return
+	}
+	if c.GetString(middleware.CtxUserRole) != domain.RoleAdmin && c.GetString(middleware.CtxUserID) != owner.UserUUID.String() {
+		c.JSON(http.StatusNotFound, gin.H{"error": "file not found"})
+		return
+	}
+
+	obj, fi, err := frc.reader.GetObject(c.Request.Context(), key)
+	if err != nil {
+		if errors.Is(err, domainFile.ErrInvalidKey) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if errors.Is(err, fs.ErrNotExist) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "file not found"})
+			return
+		}
+		c.JSON(
+			http.StatusInternalServerError,
+			gin.H{"error": "failed to get a file"},
+		)
+		frc.logger.Error("GetObject() error", zap.Error(err))
+		return
+	}
+	defer obj.Close()
+	if rl, ok := obj.(ports.RangeLimiter); ok {
+		if end, ok := rangeEnd(c.GetHeader("Range"), fi.Size()); ok {
+			rl.LimitRange(end)
+		}
+	}
+
+	// the type stored at the upload, the extension of the key is the one of
+	// the name the client sent
+	contentType := owner.MimeType
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	if disposition != validator.DispositionAttachment && !inlineType(contentType) {
+		disposition = validator.DispositionAttachment
+	}
+	if disposition == "" {
+		disposition = validator.DispositionInline
+	}
+	if name == "" {
+		name = fi.Name()
+	}
+
+	c.Header("Content-Type", contentType)
+	c.Header("Content-Disposition", contentDisposition(disposition, name))
+	c.Header("X-Content-Type-Options", "nosniff")
+	http.ServeContent(c.Writer, c.Request, fi.Name(), fi.ModTime(), obj)
+}
+
+// rangeEnd - the end(exclusive) of a single "bytes=<first>-<last>" range, the
+// other ranges end with the object
+func rangeEnd(header string, size int64) (int64, bool) {
+	spec, ok := strings.CutPrefix(header, "bytes=")
+	if !ok || strings.Contains(spec, ",") {
+		return 0, false
+	}
+	first, last, ok := strings.Cut(strings.TrimSpace(spec), "-")
+	if !ok || first == "" || last == "" {
+		return 0, false
+	}
+	end, err := strconv.ParseInt(last, 10, 64)
+	if err != nil || end < 0 || end >= size {
+		return 0, false
+	}
+
+	return end + 1, true
+}
+
+func inlineType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	for _, t := range inlineTypes {
+		if mediaType == t || strings.HasSuffix(t, "/") && strings.HasPrefix(mediaType, t) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// contentDisposition - RFC 6266: the quoted ASCII filename of the old clients,
+// a Unicode one in the RFC 5987 filename* as well
+func contentDisposition(disposition, name string) string {
+	var ascii, ext strings.Builder
+	for _, b := range []byte(name) {
+		if b >= 0x20 && b < 0x7f && b != '"' && b != '\\' {
+			ascii.WriteByte(b)
+		} else if b < 0x80 || b >= 0xc0 {
+			// a rune of a multibyte sequence is replaced once
+			ascii.WriteByte('_')
+		}
+
+		if b < 0x80 && (b >= 'a' && b <= 'z' || b >= 'A' && b <= 'Z' || b >= '0' && b <= '9' || strings.IndexByte("!#$&+-.^_`|~", b) >= 0) {
+			ext.WriteByte(b)
+		} else {
+			fmt.Fprintf(&ext, "%%%02X", b)
+		}
+	}
+
+	v := disposition + `; filename="` + ascii.String() + `"`
+	if ascii.String() != name {
+		v += "; filename*=UTF-8''" + ext.String()
+	}
+
+	return v
+}
@@ -0,0 +1,181 @@
+package rest
+
+import (
+	"context"
+	"io"
+	"io/fs"
+	"net/http"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"user-manager-api/internal/application/services"
+	domainUser "user-manager-api/internal/domain/user"
+	domainFile "user-manager-api/internal/domain/user_file"
+	jwtSvc "user-manager-api/internal/infrastructure/jwt"
+)
+
+// mapObjectReader - the objects of a MapFS, its files are seekable
+type mapObjectReader fstest.MapFS
+
+func (m mapObjectReader) GetObject(_ context.Context, key string) (io.ReadSeekCloser, fs.FileInfo, error) {
+	f, err := fstest.MapFS(m).Open(key)
+	if err != nil {
+		return nil, nil, err
+	}
+	fi, err := f.Stat()
+	if err != nil {
+		return nil, nil, err
+	}
+	return f.(io.ReadSeekCloser), fi, nil
+}
+
+// limitedReader - a ports.RangeLimiter, the end limited is kept
+type limitedReader struct {
+	io.ReadSeekCloser
+	end *int64
+}
+
+func (l limitedReader) LimitRange(end int64) { *l.end = end }
+
+func TestFileRawController_GetRawFileHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	j := jwtSvc.New("test-secret")
+	owner := uuid.New()
+	ufs := &FakeUserFileService{
+		ObjectOwnerFunc: func(_ context.Context, key string) (*domainFile.ObjectOwner, error) {
+			// the types stored at the upload
+			switch key {
+			case "u/other.pdf":
+				return &domainFile.ObjectOwner{UserUUID: uuid.New(), MimeType: "application/pdf"}, nil
+			case "u/deleted.pdf":
+				return nil, services.ErrUserFileNotFound
+			case "u/page.html", "u/image.png":
+				return &domainFile.ObjectOwner{UserUUID: owner, MimeType: "text/html"}, nil
+			case "u/blob":
+				return &domainFile.ObjectOwner{UserUUID: owner, MimeType: "application/octet-stream"}, nil
+			}
+			return &domainFile.ObjectOwner{UserUUID: owner, MimeType: "application/pdf"}, nil
+		},
+	}
+	NewFileRawController(r, mapObjectReader{
+		"u/report.pdf":  {Data: []byte("%PDF-1.7")},
+		"u/page.html":   {Data: []byte("<script>alert(1)</script>")},
+		"u/image.png":   {Data: []byte("<script>alert(1)</script>")},
+		"u/blob":        {Data: []byte{0, 1, 2}},
+		"u/other.pdf":   {Data: []byte("%PDF-1.7")},
+		"u/deleted.pdf": {Data: []byte("%PDF-1.7")},
+	}, ufs, zap.NewNop(), j)
+
+	tok, err := j.GenerateToken(owner.String(), "user", time.Minute)
+	require.NoError(t, err)
+	ownerHeaders := map[string]string{"Authorization": "Bearer " + tok}
+	adminHeaders := adminStatsHeaders(t, j, domainUser.RoleAdmin)
+
+	tests := []struct {
+		name            string
+		path            string
+		headers         map[string]string
+		wantStatus      int
+		wantType        string
+		wantDisposition string
+	}{
+		{name: "401 anonymous", path: "/u/report.pdf", wantStatus: http.StatusUnauthorized},
+		{name: "404 of another user", path: "/u/other.pdf", headers: ownerHeaders, wantStatus: http.StatusNotFound},
+		{name: "404 deleted", path: "/u/deleted.pdf", headers: adminHeaders, wantStatus: http.StatusNotFound},
+		{"admin", "/u/other.pdf", adminHeaders, http.StatusOK, "application/pdf", `inline; filename="other.pdf"`},
+		{"pdf inline by default", "/u/report.pdf", ownerHeaders, http.StatusOK, "application/pdf", `inline; filename="report.pdf"`},
+		{"pdf attachment", "/u/report.pdf?disposition=attachment", ownerHeaders, http.StatusOK, "application/pdf", `attachment; filename="report.pdf"`},
+		{
+			"unicode name", "/u/report.pdf?filename=Résumé 2026.pdf", ownerHeaders, http.StatusOK, "application/pdf",
+			`inline; filename="R_sum_ 2026.pdf"; filename*=UTF-8''R%C3%A9sum%C3%A9%202026.pdf`,
+		},
+		{"quotes escaped", `/u/report.pdf?filename=a"b.pdf`, ownerHeaders, http.StatusOK, "application/pdf", `inline; filename="a_b.pdf"; filename*=UTF-8''a%22b.pdf`},
+		{"html never inline", "/u/page.html?disposition=inline", ownerHeaders, http.StatusOK, "text/html", `attachment; filename="page.html"`},
+		{"stored type, not the extension", "/u/image.png?disposition=inline", ownerHeaders, http.StatusOK, "text/html", `attachment; filename="image.png"`},
+		{"unknown type", "/u/blob", ownerHeaders, http.StatusOK, "application/octet-stream", `attachment; filename="blob"`},
+		{"400 disposition", "/u/report.pdf?disposition=open", ownerHeaders, http.StatusBadRequest, "", ""},
+		{"400 filename", "/u/report.pdf?filename=../x.pdf", ownerHeaders, http.StatusBadRequest, "", ""},
+		{"404", "/u/missing.pdf", ownerHeaders, http.StatusNotFound, "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := doReq(t, r, http.MethodGet, RouteFiles+"/raw"+tt.path, nil, tt.headers)
+			require.Equal(t, tt.wantStatus, rr.Code, rr.Body.String())
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			assert.Equal(t, tt.wantType, rr.Header().Get("Content-Type"))
+			assert.Equal(t, tt.wantDisposition, rr.Header().Get("Content-Disposition"))
+			assert.Equal(t, "nosniff", rr.Header().Get("X-Content-Type-Options"))
+		})
+	}
+}
+
+// limitingReader - the objects of a MapFS as ports.RangeLimiter ones
+type limitingReader struct {
+	mapObjectReader
+	end *int64
+}
+
+func (l limitingReader) GetObject(ctx context.Context, key string) (io.ReadSeekCloser, fs.FileInfo, error) {
+	obj, fi, err := l.mapObjectReader.GetObject(ctx, key)
+	if err != nil {
+		return nil, nil, err
+	}
+	return limitedReader{ReadSeekCloser: obj, end: l.end}, fi, nil
+}
+
+func TestFileRawController_RangeLimited(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	j := jwtSvc.New("test-secret")
+	ufs := &FakeUserFileService{
+		ObjectOwnerFunc: func(context.Context, string) (*domainFile.ObjectOwner, error) {
+			return &domainFile.ObjectOwner{UserUUID: uuid.New(), MimeType: "video/mp4"}, nil
+		},
+	}
+	var end int64
+	reader := limitingReader{mapObjectReader{"u/video.mp4": {Data: []byte("0123456789")}}, &end}
+	NewFileRawController(r, reader, ufs, zap.NewNop(), j)
+
+	headers := adminStatsHeaders(t, j, domainUser.RoleAdmin)
+	headers["Range"] = "bytes=2-4"
+	rr := doReq(t, r, http.MethodGet, RouteFiles+"/raw/u/video.mp4", nil, headers)
+	require.Equal(t, http.StatusPartialContent, rr.Code)
+	assert.Equal(t, "234", rr.Body.String())
+	assert.Equal(t, int64(5), end, "the range requested is forwarded")
+}
+
+func TestRangeEnd(t *testing.T) {
+	tests := []struct {
+		header  string
+		wantEnd int64
+		wantOK  bool
+	}{
+		{"bytes=5-9", 10, true},
+		{"bytes=0-0", 1, true},
+		{"", 0, false},
+		{"bytes=10-", 0, false},
+		{"bytes=-3", 0, false},
+		{"bytes=0-1,5-9", 0, false},
+		{"bytes=5-100", 0, false},
+		{"items=5-9", 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.header, func(t *testing.T) {
+			end, ok := rangeEnd(tt.header, 20)
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.wantEnd, end)
+		})
+	}
+}
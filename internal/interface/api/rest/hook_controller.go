@@ -0,0 +1,82 @@
+package rest
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"user-manager-api/internal/application/ports"
+	"user-manager-api/internal/application/services"
+	domain "user-manager-api/internal/domain/user"
+	"user-manager-api/internal/interface/api/rest/dto/hook"
+	"user-manager-api/internal/interface/api/rest/middleware"
+	"user-manager-api/internal/interface/api/rest/validator"
+)
+
+// HookController - webhooks pushed by the integrations, authenticated by the
+// HMAC signature of the body instead of a JWT.
+type HookController struct {
+	hrHookService ports.HRHookService
+	logger        *zap.Logger
+}
+
+func NewHookController(
+	r *gin.Engine,
+	hrHookService ports.HRHookService,
+	logger *zap.Logger,
+	hrSecret string,
+	maxSkew time.Duration,
+) *HookController {
+	hc := &HookController{
+		hrHookService: hrHookService,
+		logger:        logger,
+	}
+
+	r.POST(RouteHookHR, middleware.VerifySignature([]byte(hrSecret), maxSkew), hc.HRHandler)
+
+	return hc
+}
+
+// HRHandler - 201 for a new user, 200 for an updated one
+func (hc *HookController) HRHandler(c *gin.Context) {
+	req, ok := BindAndValidate(c, validator.ValidateHREvent)
+	if !ok {
+		return
+	}
+
+	employee, err := hook.ToDomainUser(req)
+	if err != nil {
+		abortInvalidBody(c, err.Error())
+		return
+	}
+
+	u, created, err := hc.hrHookService.ApplyEmployee(c.Request.Context(), employee)
+	if err != nil {
+		if abortUnderage(c, err) {
+			return
+		}
+		switch {
+		// a concurrent signup or deletion, the HR system retries
+		case errors.Is(err, domain.ErrEmailAlreadyExists), errors.Is(err, services.ErrUserNotFound):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		case errors.Is(err, services.ErrSeatLimitReached):
+			c.JSON(http.StatusPaymentRequired, gin.H{"error": err.Error(), "code": codeUpgradeRequired})
+		default:
+			c.JSON(
+				http.StatusInternalServerError,
+				gin.H{"error": "failed to apply the employee"},
+			)
+			hc.logger.Error("ApplyEmployee() error", zap.Error(err), zap.String("event", req.Event))
+		}
+		return
+	}
+
+	if created {
+		c.JSON(http.StatusCreated, hook.HRResult{UUID: u.UUID, Result: "created"})
+		return
+	}
+	c.JSON(http.StatusOK, hook.HRResult{UUID: u.UUID, Result: "updated"})
+}
@@ -0,0 +1,216 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"user-manager-api/internal/application/services"
+	domain "user-manager-api/internal/domain/user"
+	"user-manager-api/internal/interface/api/rest/dto/hook"
+	"user-manager-api/internal/interface/api/rest/dto/user"
+	"user-manager-api/internal/interface/api/rest/middleware"
+)
+
+const testHookSecret = "hr-test-secret-0123456789abcdefgh"
+
+type fakeHRHookService struct {
+	ApplyEmployeeFunc func(ctx context.Context, employee domain.User) (*domain.User, bool, error)
+}
+
+func (f *fakeHRHookService) ApplyEmployee(ctx context.Context, employee domain.User) (*domain.User, bool, error) {
+	if f.ApplyEmployeeFunc == nil {
+		return nil, false, errors.New("not used")
+	}
+	return f.ApplyEmployeeFunc(ctx, employee)
+}
+
+func setupHookRouter(t *testing.T, hs *fakeHRHookService) *gin.Engine {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	NewHookController(r, hs, zap.NewNop(), testHookSecret, 5*time.Minute)
+
+	return r
+}
+
+func TestHookController_HRHandler(t *testing.T) {
+	userID := uuid.New()
+	event := hook.HREvent{
+		Event: hook.EventEmployeeCreated,
+		Employee: user.Request{
+			Email:     " Jane.Doe@Example.com ",
+			Name:      "Jane",
+			Lastname:  "Doe",
+			BirthDate: "1990-01-02",
+			Phone:     "+33788888888",
+		},
+	}
+	body, err := json.Marshal(event)
+	require.NoError(t, err)
+	now := strconv.FormatInt(time.Now().Unix(), 10)
+	signed := func(ts string, b []byte) map[string]string {
+		return map[string]string{
+			middleware.HeaderSignatureTimestamp: ts,
+			middleware.HeaderSignature:          middleware.Sign([]byte(testHookSecret), ts, b),
+		}
+	}
+	created := func(_ context.Context, e domain.User) (*domain.User, bool, error) {
+		if e.Email != "jane.doe@example.com" || e.Name != "Jane" {
+			return nil, false, errors.New("unexpected employee")
+		}
+		e.UUID = userID
+		return &e, true, nil
+	}
+
+	type tc struct {
+		name       string
+		body       string
+		headers    map[string]string
+		apply      func(ctx context.Context, employee domain.User) (*domain.User, bool, error)
+		wantStatus int
+		wantErr    string
+		wantResult string
+	}
+
+	cases := []tc{
+		{
+			name:       "201 created",
+			body:       string(body),
+			headers:    signed(now, body),
+			apply:      created,
+			wantStatus: http.StatusCreated,
+			wantResult: "created",
+		},
+		{
+			name:    "200 updated",
+			body:    string(body),
+			headers: signed(now, body),
+			apply: func(_ context.Context, e domain.User) (*domain.User, bool, error) {
+				e.UUID = userID
+				return &e, false, nil
+			},
+			wantStatus: http.StatusOK,
+			wantResult: "updated",
+		},
+		{
+			name:       "401 no signature",
+			body:       string(body),
+			headers:    map[string]string{middleware.HeaderSignatureTimestamp: now},
+			wantStatus: http.StatusUnauthorized,
+			wantErr:    "invalid signature",
+		},
+		{
+			name: "401 wrong secret",
+			body: string(body),
+			headers: map[string]string{
+				middleware.HeaderSignatureTimestamp: now,
+				middleware.HeaderSignature:          middleware.Sign([]byte("other-secret"), now, body),
+			},
+			wantStatus: http.StatusUnauthorized,
+			wantErr:    "invalid signature",
+		},
+		{
+			name:       "401 tampered body",
+			body:       strings.Replace(string(body), "Jane", "John", 1),
+			headers:    signed(now, body),
+			wantStatus: http.StatusUnauthorized,
+			wantErr:    "invalid signature",
+		},
+		{
+			name: "401 stale timestamp",
+			body: string(body),
+			headers: signed(
+				strconv.FormatInt(time.Now().Add(-10*time.Minute).Unix(), 10),
+				body,
+			),
+			wantStatus: http.StatusUnauthorized,
+			wantErr:    "invalid signature",
+		},
+		{
+			name:       "413 body too large",
+			body:       strings.Repeat("x", 64<<10+1),
+			headers:    map[string]string{},
+			wantStatus: http.StatusRequestEntityTooLarge,
+			wantErr:    "request body too large",
+		},
+		{
+			name:       "400 invalid payload",
+			body:       `{"event":"employee.hired"}`,
+			headers:    signed(now, []byte(`{"event":"employee.hired"}`)),
+			wantStatus: http.StatusBadRequest,
+			wantErr:    errInvalidRequestBody,
+		},
+		{
+			name:    "409 email taken meanwhile",
+			body:    string(body),
+			headers: signed(now, body),
+			apply: func(context.Context, domain.User) (*domain.User, bool, error) {
+				return nil, false, domain.ErrEmailAlreadyExists
+			},
+			wantStatus: http.StatusConflict,
+			wantErr:    domain.ErrEmailAlreadyExists.Error(),
+		},
+		{
+			name:    "409 deleted meanwhile",
+			body:    string(body),
+			headers: signed(now, body),
+			apply: func(context.Context, domain.User) (*domain.User, bool, error) {
+				return nil, false, services.ErrUserNotFound
+			},
+			wantStatus: http.StatusConflict,
+			wantErr:    services.ErrUserNotFound.Error(),
+		},
+		{
+			name:    "402 seat limit reached",
+			body:    string(body),
+			headers: signed(now, body),
+			apply: func(context.Context, domain.User) (*domain.User, bool, error) {
+				return nil, false, services.ErrSeatLimitReached
+			},
+			wantStatus: http.StatusPaymentRequired,
+			wantErr:    services.ErrSeatLimitReached.Error(),
+		},
+		{
+			name:    "500",
+			body:    string(body),
+			headers: signed(now, body),
+			apply: func(context.Context, domain.User) (*domain.User, bool, error) {
+				return nil, false, errors.New("db down")
+			},
+			wantStatus: http.StatusInternalServerError,
+			wantErr:    "failed to apply the employee",
+		},
+	}
+
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			r := setupHookRouter(t, &fakeHRHookService{ApplyEmployeeFunc: tt.apply})
+
+			rr := doReq(t, r, http.MethodPost, RouteHookHR, tt.body, tt.headers)
+			require.Equal(t, tt.wantStatus, rr.Code, rr.Body.String())
+
+			var resp map[string]any
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+			if tt.wantErr != "" {
+				assert.Equal(t, tt.wantErr, resp["error"])
+				return
+			}
+			assert.Equal(t, userID.String(), resp["uuid"])
+			assert.Equal(t, tt.wantResult, resp["result"])
+		})
+	}
+}
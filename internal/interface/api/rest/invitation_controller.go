@@ -0,0 +1,123 @@
+package rest
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"user-manager-api/internal/application/ports"
+	"user-manager-api/internal/application/services"
+	domain "user-manager-api/internal/domain/user"
+	"user-manager-api/internal/interface/api/rest/dto/invitation"
+	"user-manager-api/internal/interface/api/rest/dto/user"
+	"user-manager-api/internal/interface/api/rest/middleware"
+	"user-manager-api/internal/interface/api/rest/validator"
+)
+
+type InvitationController struct {
+	invitationService ports.InvitationService
+	logger            *zap.Logger
+}
+
+func NewInvitationController(
+	r *gin.Engine,
+	invitationService ports.InvitationService,
+	logger *zap.Logger,
+	tokenService ports.TokenService,
+) *InvitationController {
+	ic := &InvitationController{
+		invitationService: invitationService,
+		logger:            logger,
+	}
+
+	r.POST(RouteInvitations, middleware.AuthMiddleware(tokenService), middleware.RequireAdmin(), ic.InviteHandler)
+	// the token is the credential of the invitee
+	r.POST(RouteInvitationAccept, ic.AcceptHandler)
+
+	return ic
+}
+
+func (ic *InvitationController) InviteHandler(c *gin.Context) {
+	ok, actor := validator.IsUUID(c.GetString(middleware.CtxUserID))
+	if !ok {
+		c.JSON(
+			http.StatusUnauthorized,
+			gin.H{"error": "invalid token"},
+		)
+		return
+	}
+	req, ok := BindAndValidate(c, validator.ValidateInvitation)
+	if !ok {
+		return
+	}
+
+	email := strings.ToLower(strings.TrimSpace(req.Email))
+	role := strings.TrimSpace(req.Role)
+	if role == "" {
+		role = domain.RoleWorker
+	}
+
+	expiresAt, err := ic.invitationService.Invite(c.Request.Context(), actor, email, role)
+	if err != nil {
+		if errors.Is(err, domain.ErrEmailAlreadyExists) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error(), "code": codeEmailTaken})
+			return
+		}
+		if errors.Is(err, services.ErrSeatLimitReached) {
+			c.JSON(http.StatusPaymentRequired, gin.H{"error": err.Error(), "code": codeUpgradeRequired})
+			return
+		}
+		c.JSON(
+			http.StatusInternalServerError,
+			gin.H{"error": "failed to invite a user"},
+		)
+		ic.logger.Error("Invite() error", zap.Error(err), zap.Stringer("actor_uuid", actor))
+		return
+	}
+
+	c.JSON(http.StatusCreated, invitation.Invitation{
+		Email:     email,
+		Role:      role,
+		ExpiresAt: expiresAt.UTC(),
+	})
+}
+
+func (ic *InvitationController) AcceptHandler(c *gin.Context) {
+	req, ok := BindAndValidate(c, validator.ValidateInvitationAccept)
+	if !ok {
+		return
+	}
+
+	uDomain, err := invitation.ToDomainUser(req)
+	if err != nil {
+		abortInvalidBody(c, err.Error())
+		return
+	}
+
+	u, err := ic.invitationService.Accept(c.Request.Context(), c.Param("token"), uDomain, req.Password)
+	if err != nil {
+		if abortUnderage(c, err) {
+			return
+		}
+		switch {
+		case errors.Is(err, services.ErrInvalidInvitation):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case errors.Is(err, domain.ErrEmailAlreadyExists):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error(), "code": codeEmailTaken})
+		case errors.Is(err, services.ErrSeatLimitReached):
+			c.JSON(http.StatusPaymentRequired, gin.H{"error": err.Error(), "code": codeUpgradeRequired})
+		default:
+			c.JSON(
+				http.StatusInternalServerError,
+				gin.H{"error": "failed to accept the invitation"},
+			)
+			ic.logger.Error("Accept() error", zap.Error(err))
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, user.ToResponseUser(*u))
+}
@@ -0,0 +1,244 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"user-manager-api/internal/application/services"
+	domain "user-manager-api/internal/domain/user"
+	jwtSvc "user-manager-api/internal/infrastructure/jwt"
+	"user-manager-api/internal/interface/api/rest/dto/invitation"
+)
+
+type fakeInvitationService struct {
+	InviteFunc func(ctx context.Context, actor domain.UUID, email, role string) (time.Time, error)
+	AcceptFunc func(ctx context.Context, token string, u domain.User, password string) (*domain.User, error)
+}
+
+func (f *fakeInvitationService) Invite(ctx context.Context, actor domain.UUID, email, role string) (time.Time, error) {
+	if f.InviteFunc == nil {
+		return time.Time{}, errors.New("not used")
+	}
+	return f.InviteFunc(ctx, actor, email, role)
+}
+
+func (f *fakeInvitationService) Accept(ctx context.Context, token string, u domain.User, password string) (*domain.User, error) {
+	if f.AcceptFunc == nil {
+		return nil, errors.New("not used")
+	}
+	return f.AcceptFunc(ctx, token, u, password)
+}
+
+func setupInvitationRouter(t *testing.T, is *fakeInvitationService) (*gin.Engine, *jwtSvc.Service) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	j := jwtSvc.New("test-secret")
+	NewInvitationController(r, is, zap.NewNop(), j)
+
+	return r, j
+}
+
+func TestInvitationController_InviteHandler(t *testing.T) {
+	adminID := uuid.New()
+	expiresAt := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+
+	type tc struct {
+		name       string
+		role       string
+		body       any
+		invite     func(ctx context.Context, actor domain.UUID, email, role string) (time.Time, error)
+		wantStatus int
+		wantErr    string
+	}
+	ok := func(ctx context.Context, actor domain.UUID, email, role string) (time.Time, error) {
+		if actor != adminID || email != "new@example.com" || role != domain.RoleWorker {
+			return time.Time{}, errors.New("unexpected args")
+		}
+		return expiresAt, nil
+	}
+
+	cases := []tc{
+		{
+			name:       "201 default role, normalized email",
+			role:       domain.RoleAdmin,
+			body:       invitation.Request{Email: " New@Example.com "},
+			invite:     ok,
+			wantStatus: http.StatusCreated,
+		},
+		{
+			name:       "403 worker",
+			role:       domain.RoleWorker,
+			body:       invitation.Request{Email: "new@example.com"},
+			wantStatus: http.StatusForbidden,
+			wantErr:    "admin role required",
+		},
+		{
+			name:       "400 bad role",
+			role:       domain.RoleAdmin,
+			body:       invitation.Request{Email: "new@example.com", Role: "root"},
+			wantStatus: http.StatusBadRequest,
+			wantErr:    errInvalidRequestBody,
+		},
+		{
+			name: "409 email taken",
+			role: domain.RoleAdmin,
+			body: invitation.Request{Email: "new@example.com"},
+			invite: func(context.Context, domain.UUID, string, string) (time.Time, error) {
+				return time.Time{}, domain.ErrEmailAlreadyExists
+			},
+			wantStatus: http.StatusConflict,
+			wantErr:    domain.ErrEmailAlreadyExists.Error(),
+		},
+		{
+			name: "402 seat limit reached",
+			role: domain.RoleAdmin,
+			body: invitation.Request{Email: "new@example.com"},
+			invite: func(context.Context, domain.UUID, string, string) (time.Time, error) {
+				return time.Time{}, services.ErrSeatLimitReached
+			},
+			wantStatus: http.StatusPaymentRequired,
+			wantErr:    services.ErrSeatLimitReached.Error(),
+		},
+		{
+			name: "500",
+			role: domain.RoleAdmin,
+			body: invitation.Request{Email: "new@example.com"},
+			invite: func(context.Context, domain.UUID, string, string) (time.Time, error) {
+				return time.Time{}, errors.New("db down")
+			},
+			wantStatus: http.StatusInternalServerError,
+			wantErr:    "failed to invite a user",
+		},
+	}
+
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			r, j := setupInvitationRouter(t, &fakeInvitationService{InviteFunc: tt.invite})
+			tok, err := j.GenerateToken(adminID.String(), tt.role, time.Minute)
+			require.NoError(t, err)
+
+			rr := doReq(t, r, http.MethodPost, RouteInvitations, tt.body, map[string]string{"Authorization": "Bearer " + tok})
+			require.Equal(t, tt.wantStatus, rr.Code, rr.Body.String())
+
+			var resp map[string]any
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+			if tt.wantErr != "" {
+				assert.Equal(t, tt.wantErr, resp["error"])
+				return
+			}
+			assert.Equal(t, "new@example.com", resp["email"])
+			assert.Equal(t, domain.RoleWorker, resp["role"])
+			assert.Equal(t, expiresAt.Format(time.RFC3339), resp["expires_at"])
+		})
+	}
+}
+
+func TestInvitationController_AcceptHandler(t *testing.T) {
+	userID := uuid.New()
+	path := RouteInvitations + "/tok123/accept"
+	body := invitation.AcceptRequest{
+		Password:  "s3cret-pass",
+		Name:      "Jane",
+		Lastname:  "Doe",
+		BirthDate: "1990-01-02",
+		Phone:     "+33788888888",
+	}
+
+	type tc struct {
+		name       string
+		body       any
+		accept     func(ctx context.Context, token string, u domain.User, password string) (*domain.User, error)
+		wantStatus int
+		wantErr    string
+	}
+
+	cases := []tc{
+		{
+			name: "201",
+			body: body,
+			accept: func(_ context.Context, token string, u domain.User, password string) (*domain.User, error) {
+				if token != "tok123" || password != body.Password || u.Name != "Jane" {
+					return nil, errors.New("unexpected args")
+				}
+				u.UUID = userID
+				u.Email = "new@example.com"
+				return &u, nil
+			},
+			wantStatus: http.StatusCreated,
+		},
+		{
+			name:       "400 short password",
+			body:       invitation.AcceptRequest{Password: "short", Name: "Jane", Lastname: "Doe", BirthDate: "1990-01-02", Phone: "+33788888888"},
+			wantStatus: http.StatusBadRequest,
+			wantErr:    errInvalidRequestBody,
+		},
+		{
+			name: "404 invalid token",
+			body: body,
+			accept: func(context.Context, string, domain.User, string) (*domain.User, error) {
+				return nil, services.ErrInvalidInvitation
+			},
+			wantStatus: http.StatusNotFound,
+			wantErr:    services.ErrInvalidInvitation.Error(),
+		},
+		{
+			name: "409 email taken",
+			body: body,
+			accept: func(context.Context, string, domain.User, string) (*domain.User, error) {
+				return nil, domain.ErrEmailAlreadyExists
+			},
+			wantStatus: http.StatusConflict,
+			wantErr:    domain.ErrEmailAlreadyExists.Error(),
+		},
+		{
+			name: "402 the last seat taken meanwhile",
+			body: body,
+			accept: func(context.Context, string, domain.User, string) (*domain.User, error) {
+				return nil, services.ErrSeatLimitReached
+			},
+			wantStatus: http.StatusPaymentRequired,
+			wantErr:    services.ErrSeatLimitReached.Error(),
+		},
+		{
+			name: "500",
+			body: body,
+			accept: func(context.Context, string, domain.User, string) (*domain.User, error) {
+				return nil, errors.New("db down")
+			},
+			wantStatus: http.StatusInternalServerError,
+			wantErr:    "failed to accept the invitation",
+		},
+	}
+
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			r, _ := setupInvitationRouter(t, &fakeInvitationService{AcceptFunc: tt.accept})
+
+			rr := doReq(t, r, http.MethodPost, path, tt.body, nil)
+			require.Equal(t, tt.wantStatus, rr.Code, rr.Body.String())
+
+			var resp map[string]any
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+			if tt.wantErr != "" {
+				assert.Equal(t, tt.wantErr, resp["error"])
+				return
+			}
+			assert.Equal(t, userID.String(), resp["uuid"])
+			assert.Equal(t, "new@example.com", resp["email"])
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package middleware
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"user-manager-api/pkg/bulkhead"
+)
+
+const CodeOverloaded = "overloaded"
+
+// Admission sheds the load over the capacity of the service: at most limit's
+// slots of requests run at once, a few more wait in its queue and the rest are
+// answered 503 with Retry-After at once, so a spike does not pile up on the
+// Postgres pool. limit nil - unlimited. The exempt routes(the probes, the
+// metrics, the long-lived streams) always pass. inFlight counts the admitted
+// requests.
+func Admission(
+	limit *bulkhead.Bulkhead,
+	retryAfter time.Duration,
+	inFlight prometheus.Gauge,
+	mCounter *prometheus.CounterVec,
+	exemptRoutes ...string,
+) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if slices.Contains(exemptRoutes, c.FullPath()) {
+			c.Next()
+			return
+		}
+
+		if limit != nil {
+			release, err := limit.Acquire(c.Request.Context())
+			if errors.Is(err, bulkhead.ErrFull) {
+				mCounter.WithLabelValues("http_requests_shed_total").Inc()
+				c.Header("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+				c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{
+					"error": "the service is overloaded, retry later",
+					"code":  CodeOverloaded,
+				})
+				return
+			}
+			if err != nil {
+				// the caller is gone while queued
+				c.Abort()
+				return
+			}
+			defer release()
+		}
+
+		inFlight.Inc()
+		defer inFlight.Dec()
+
+		c.Next()
+	}
+}
@@ -1,26 +1,49 @@
 package middleware
 
 import (
+	"errors"
 	"net/http"
 	"strings"
 
 	"github.com/gin-gonic/gin"
 
-	"user-manager-api/internal/infrastructure/jwt"
+	"user-manager-api/internal/application/ports"
+	"user-manager-api/internal/domain/token"
 )
 
 const (
 	CtxUserRole = "userRole"
 	CtxUserID   = "userID"
+	// CtxActAs - impersonating admin UUID, set for impersonation tokens only
+	CtxActAs = "actAs"
+	// CtxDeviceID - UUID of the device of the login, set for device bound tokens only
+	CtxDeviceID = "deviceID"
+
+	// HeaderRefreshedToken - the new token of a request whose one is about to
+	// expire, the client replaces its token with it
+	HeaderRefreshedToken = "X-Refreshed-Token"
 )
 
-func AuthMiddleware(jwtService *jwt.Service) gin.HandlerFunc {
+// the codes of the 401 responses, a client refreshes an expired token only
+const (
+	CodeTokenMissing = "token_missing"
+	CodeTokenInvalid = "token_invalid"
+	CodeTokenExpired = "token_expired"
+	CodeTokenRevoked = "token_revoked"
+)
+
+func AuthMiddleware(tokenService ports.TokenService) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
+		// an internal caller identified by ClientCert, a bearer token takes precedence
+		if authHeader == "" && c.GetString(CtxClientID) != "" {
+			c.Next()
+			return
+		}
 		if authHeader == "" {
 			c.AbortWithStatusJSON(
 				http.StatusUnauthorized,
-				gin.H{"error": "missing Authorization header"},
+				gin.H{"error": "missing Authorization header", "code": CodeTokenMissing},
 			)
 			return
 		}
@@ -29,23 +52,79 @@ func AuthMiddleware(jwtService *jwt.Service) gin.HandlerFunc {
 		if tokenStr == authHeader {
 			c.AbortWithStatusJSON(
 				http.StatusUnauthorized,
-				gin.H{"error": "invalid token format"},
+				gin.H{"error": "invalid token format", "code": CodeTokenInvalid},
+			)
+			return
+		}
+
+		claims, err := tokenService.ValidateToken(tokenStr)
+		if errors.Is(err, token.ErrExpired) {
+			c.AbortWithStatusJSON(
+				http.StatusUnauthorized,
+				gin.H{"error": "token expired", "code": CodeTokenExpired},
 			)
 			return
 		}
-		
-		claims, err := jwtService.ValidateToken(tokenStr)
 		if err != nil {
 			c.AbortWithStatusJSON(
 				http.StatusUnauthorized,
-				gin.H{"error": "invalid token"},
+				gin.H{"error": "invalid token", "code": CodeTokenInvalid},
 			)
 			return
 		}
 
-		c.Set(CtxUserRole, claims.Role)
+		revoked, err := tokenService.IsRevoked(c.Request.Context(), claims)
+		if err != nil {
+			c.AbortWithStatusJSON(
+				http.StatusInternalServerError,
+				gin.H{"error": "failed to check the token"},
+			)
+			return
+		}
+		if revoked {
+			c.AbortWithStatusJSON(
+				http.StatusUnauthorized,
+				gin.H{"error": "token revoked", "code": CodeTokenRevoked},
+			)
+			return
+		}
+
+		role, err := tokenService.CurrentRole(c.Request.Context(), claims)
+		if err != nil {
+			c.AbortWithStatusJSON(
+				http.StatusInternalServerError,
+				gin.H{"error": "failed to check the token"},
+			)
+			return
+		}
+
+		// a failed refresh is retried by the next request, the token is valid still
+		if refreshed, err := tokenService.RefreshToken(claims, role); err == nil && refreshed != "" {
+			c.Header(HeaderRefreshedToken, refreshed)
+		}
+
+		c.Set(CtxUserRole, role)
 		c.Set(CtxUserID, claims.UserID)
+		if claims.ActAs != "" {
+			c.Set(CtxActAs, claims.ActAs)
+		}
+		if claims.DeviceID != "" {
+			c.Set(CtxDeviceID, claims.DeviceID)
+		}
 
 		c.Next()
 	}
 }
+
+// OptionalAuthMiddleware authenticates only the requests carrying a token, handlers
+// tell anonymous callers by the empty CtxUserID.
+func OptionalAuthMiddleware(tokenService ports.TokenService) gin.HandlerFunc {
+	auth := AuthMiddleware(tokenService)
+	return func(c *gin.Context) {
+		if c.GetHeader("Authorization") == "" {
+			c.Next()
+			return
+		}
+		auth(c)
+	}
+}
@@ -0,0 +1,55 @@
+package middleware
+
+import (
+	"crypto/tls"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// CtxClientID - the SAN of the verified client certificate, set for the mTLS callers only
+const CtxClientID = "clientID"
+
+// ClientCert authenticates the internal callers by their verified client certificate
+// (the listener verifies it against TLS_CLIENT_CA_FILE): the first SAN, SPIFFE ID or DNS
+// name, found in identities("<SAN>=<role>") gives the role, the caller acts as the
+// system(uuid.Nil). Other requests pass untouched, AuthMiddleware lets the identified
+// ones through without a bearer token.
+func ClientCert(identities []string) gin.HandlerFunc {
+	roles := make(map[string]string, len(identities))
+	for _, item := range identities {
+		id, role, _ := strings.Cut(item, "=")
+		roles[id] = role
+	}
+
+	return func(c *gin.Context) {
+		if id, role, ok := clientIdentity(c.Request.TLS, roles); ok {
+			c.Set(CtxClientID, id)
+			c.Set(CtxUserRole, role)
+			c.Set(CtxUserID, uuid.Nil.String())
+		}
+		c.Next()
+	}
+}
+
+// clientIdentity - an unverified certificate(no chain) identifies nobody
+func clientIdentity(cs *tls.ConnectionState, roles map[string]string) (id, role string, ok bool) {
+	if cs == nil || len(cs.VerifiedChains) == 0 || len(cs.PeerCertificates) == 0 {
+		return "", "", false
+	}
+	cert := cs.PeerCertificates[0]
+
+	for _, u := range cert.URIs {
+		if role, ok = roles[u.String()]; ok {
+			return u.String(), role, true
+		}
+	}
+	for _, name := range cert.DNSNames {
+		if role, ok = roles[name]; ok {
+			return name, role, true
+		}
+	}
+
+	return "", "", false
+}
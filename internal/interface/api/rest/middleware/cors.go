@@ -0,0 +1,44 @@
+package middleware
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// corsMaxAge - seconds the browsers cache a preflight
+const corsMaxAge = "600"
+
+// CORS allows the browser calls of origins("*" - any) and answers their
+// preflights, the other origins get no CORS headers, so the browsers block the
+// responses. The tokens are bearer ones, no credentials(cookies) are allowed.
+func CORS(origins []string) gin.HandlerFunc {
+	anyOrigin := slices.Contains(origins, "*")
+
+	return func(c *gin.Context) {
+		origin := c.GetHeader("Origin")
+		if origin == "" || (!anyOrigin && !slices.Contains(origins, origin)) {
+			c.Next()
+			return
+		}
+
+		h := c.Writer.Header()
+		h.Set("Access-Control-Allow-Origin", origin)
+		h.Add("Vary", "Origin")
+		h.Set("Access-Control-Expose-Headers", strings.Join([]string{"Retry-After", "Content-Disposition", HeaderRequestID, HeaderRefreshedToken}, ", "))
+
+		if c.Request.Method == http.MethodOptions && c.GetHeader("Access-Control-Request-Method") != "" {
+			h.Set("Access-Control-Allow-Methods", "GET, HEAD, POST, PUT, PATCH, DELETE")
+			if reqHeaders := c.GetHeader("Access-Control-Request-Headers"); reqHeaders != "" {
+				h.Set("Access-Control-Allow-Headers", reqHeaders)
+			}
+			h.Set("Access-Control-Max-Age", corsMaxAge)
+			c.AbortWithStatus(http.StatusNoContent)
+			return
+		}
+
+		c.Next()
+	}
+}
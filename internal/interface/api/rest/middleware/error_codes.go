@@ -0,0 +1,155 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// CodeOther - the label of a code out of codeRe, the codes are the constants
+// of the handlers and the middlewares but a label is never unbounded
+const CodeOther = "other"
+
+var codeRe = regexp.MustCompile(`^[a-z][a-z0-9_]{0,47}$`)
+
+// statusCodes - the code of an error response whose handler has not set one
+var statusCodes = map[int]string{
+	http.StatusBadRequest:            "bad_request",
+	http.StatusUnauthorized:          "unauthorized",
+	http.StatusForbidden:             "forbidden",
+	http.StatusNotFound:              "not_found",
+	http.StatusMethodNotAllowed:      "method_not_allowed",
+	http.StatusNotAcceptable:         "not_acceptable",
+	http.StatusConflict:              "conflict",
+	http.StatusGone:                  "gone",
+	http.StatusPreconditionFailed:    "precondition_failed",
+	http.StatusRequestEntityTooLarge: "too_large",
+	http.StatusUnsupportedMediaType:  "unsupported_media_type",
+	http.StatusUnprocessableEntity:   "unprocessable",
+	http.StatusTooManyRequests:       "rate_limited",
+	http.StatusInternalServerError:   "internal",
+	http.StatusBadGateway:            "bad_gateway",
+	http.StatusServiceUnavailable:    "unavailable",
+	http.StatusGatewayTimeout:        "timeout",
+}
+
+// StatusCode - the generic code of an error status
+func StatusCode(status int) string {
+	if code, ok := statusCodes[status]; ok {
+		return code
+	}
+	if status >= http.StatusInternalServerError {
+		return "server_error"
+	}
+
+	return "client_error"
+}
+
+// ErrorCodes counts every error response(4xx, 5xx) by its machine-readable
+// code and puts the generic code of the status into the JSON error bodies
+// without one, so every error envelope is {"error", "code"[, "details"]}. It
+// has to run after the middlewares reading the response(logging), they see
+// the code, and before the ones answering errors themselves(admission, timeout).
+func ErrorCodes(mErrors *prometheus.CounterVec) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		w := &errorCodeWriter{ResponseWriter: c.Writer}
+		c.Writer = w
+
+		c.Next()
+
+		c.Writer = w.ResponseWriter
+		status := w.ResponseWriter.Status()
+		if w.body == nil {
+			if status >= http.StatusBadRequest {
+				mErrors.WithLabelValues(StatusCode(status)).Inc()
+			}
+			return
+		}
+
+		body, code := withCode(w.body.Bytes(), status)
+		mErrors.WithLabelValues(code).Inc()
+		w.Header().Del("Content-Length")
+		_, _ = w.ResponseWriter.Write(body)
+	}
+}
+
+// withCode - body with the code of status if it is a JSON object without
+// one, the code of the body to count
+func withCode(body []byte, status int) ([]byte, string) {
+	var envelope map[string]json.RawMessage
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		return body, StatusCode(status)
+	}
+
+	var code string
+	if raw, ok := envelope["code"]; ok {
+		if err := json.Unmarshal(raw, &code); err != nil || !codeRe.MatchString(code) {
+			return body, CodeOther
+		}
+		return body, code
+	}
+
+	code = StatusCode(status)
+	envelope["code"], _ = json.Marshal(code)
+	tagged, err := json.Marshal(envelope)
+	if err != nil {
+		return body, code
+	}
+
+	return tagged, code
+}
+
+// errorCodeWriter holds the JSON error bodies back until the handler is done,
+// the other responses pass as they are written
+type errorCodeWriter struct {
+	gin.ResponseWriter
+	body *bytes.Buffer
+}
+
+func (w *errorCodeWriter) Write(b []byte) (int, error) {
+	if w.body == nil && !w.holds() {
+		return w.ResponseWriter.Write(b)
+	}
+	if w.body == nil {
+		w.body = new(bytes.Buffer)
+	}
+
+	return w.body.Write(b)
+}
+
+func (w *errorCodeWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// holds - on the first write, the handler has set the status and the headers by then
+func (w *errorCodeWriter) holds() bool {
+	return w.ResponseWriter.Status() >= http.StatusBadRequest &&
+		!w.ResponseWriter.Written() &&
+		strings.HasPrefix(w.Header().Get("Content-Type"), "application/json")
+}
+
+// Written - a held body is written, the middlewares answering an error
+// themselves(timeout) must not answer again
+func (w *errorCodeWriter) Written() bool {
+	return w.body != nil || w.ResponseWriter.Written()
+}
+
+func (w *errorCodeWriter) Size() int {
+	if w.body != nil {
+		return w.body.Len()
+	}
+
+	return w.ResponseWriter.Size()
+}
+
+func (w *errorCodeWriter) Flush() {
+	if w.body != nil {
+		return
+	}
+	w.ResponseWriter.Flush()
+}
@@ -0,0 +1,73 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"user-manager-api/internal/application/ports"
+	"user-manager-api/internal/domain/audit"
+	"user-manager-api/internal/domain/user"
+)
+
+// RequireAdmin must be chained after AuthMiddleware. Impersonation tokens are
+// rejected regardless of the role they carry.
+func RequireAdmin() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetString(CtxActAs) != "" {
+			c.AbortWithStatusJSON(
+				http.StatusForbidden,
+				gin.H{"error": "not allowed with an impersonation token"},
+			)
+			return
+		}
+		if c.GetString(CtxUserRole) != user.RoleAdmin {
+			c.AbortWithStatusJSON(
+				http.StatusForbidden,
+				gin.H{"error": "admin role required"},
+			)
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// ImpersonationAudit records every request made with an impersonation token.
+// Global middleware: AuthMiddleware sets the claims further down the chain.
+func ImpersonationAudit(auditService ports.AuditService, logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		actAs := c.GetString(CtxActAs)
+		if actAs == "" {
+			return
+		}
+		actor, err := uuid.Parse(actAs)
+		if err != nil {
+			logger.Error("impersonation audit: invalid act_as claim", zap.String("act_as", actAs))
+			return
+		}
+		entry := audit.Entry{
+			ActorUUID: actor,
+			Action:    audit.ActionImpersonatedRequest,
+			Details: map[string]any{
+				"method": c.Request.Method,
+				"path":   c.Request.URL.Path,
+				"status": c.Writer.Status(),
+				"ip":     c.ClientIP(),
+			},
+		}
+		if target, err := uuid.Parse(c.GetString(CtxUserID)); err == nil {
+			entry.TargetUUID = &target
+		}
+
+		// the response is already sent, a client disconnect must not drop the record
+		if err = auditService.Record(context.WithoutCancel(c.Request.Context()), entry); err != nil {
+			logger.Error("impersonation audit: Record() error", zap.Error(err))
+		}
+	}
+}
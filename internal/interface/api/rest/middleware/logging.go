@@ -12,9 +12,7 @@ import (
 	"go.uber.org/zap"
 )
 
-const maxLogBodySize = 1 << 12 // 4 KB
-
-func RequestLogGin(logger *zap.Logger, mCounter *prometheus.CounterVec) gin.HandlerFunc {
+func RequestLogGin(logger *zap.Logger, mCounter *prometheus.CounterVec, maxLogBodySize int) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		if c.Request.Method == http.MethodOptions ||
 			c.Request.URL.Path == "/favicon.ico" ||
@@ -33,7 +31,7 @@ func RequestLogGin(logger *zap.Logger, mCounter *prometheus.CounterVec) gin.Hand
 				body = "<multipart/form-data omitted>"
 			} else {
 				var buf bytes.Buffer
-				limited := io.LimitReader(c.Request.Body, maxLogBodySize)
+				limited := io.LimitReader(c.Request.Body, int64(maxLogBodySize))
 				_, _ = io.Copy(&buf, limited)
 				body = buf.String()
 				c.Request.Body.Close()
@@ -55,6 +53,7 @@ func RequestLogGin(logger *zap.Logger, mCounter *prometheus.CounterVec) gin.Hand
 			zap.String("body", body),
 			zap.String("client_ip", c.ClientIP()),
 			zap.String("user_agent", c.Request.UserAgent()),
+			zap.String("request_id", c.GetString(CtxRequestID)),
 		)
 	}
 }
@@ -0,0 +1,163 @@
+package middleware
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+
+	"user-manager-api/pkg/openapi"
+)
+
+// openAPIMaxBody - the request bodies checked, a bigger one goes to the handler
+// unchecked instead of being held in memory
+const openAPIMaxBody = 1 << 20
+
+// OpenAPI checks the API exchanges against the contract: an undocumented route or
+// status, a response body out of its schema, or a request out of the contract the
+// handler accepted(2xx) is logged and answered 500 with the violations instead of
+// the response. The responses are buffered to be replaced, so it is meant for the
+// non-production environments only; the streamed ones(x-streaming) are not, only
+// their accepted requests out of the contract are logged.
+func OpenAPI(spec *openapi.Spec, logger *zap.Logger, mCounter *prometheus.CounterVec) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		route := c.FullPath()
+		// the unknown routes(404) and the ones out of the API(e.g. /metrics)
+		if route == "" || c.Request.Method == http.MethodOptions || !strings.HasPrefix(route, spec.BasePath()+"/") {
+			c.Next()
+			return
+		}
+
+		violated := func(status int, violations []string) {
+			mCounter.WithLabelValues("openapi_violations_total").Inc()
+			logger.Error("OpenAPI contract violated",
+				zap.String("method", c.Request.Method),
+				zap.String("url", route),
+				zap.Int("status", status),
+				zap.Strings("violations", violations),
+			)
+		}
+
+		op := spec.Operation(c.Request.Method, route)
+		var reqViolations []string
+		pathParams := make(map[string]string, len(c.Params))
+		if op != nil {
+			for _, p := range c.Params {
+				// the catch-all ones(*key) start with the "/"
+				pathParams[p.Key] = strings.TrimPrefix(p.Value, "/")
+			}
+			reqViolations = validateRequest(c.Request, op, pathParams)
+		}
+
+		if op != nil && op.Streaming {
+			c.Next()
+			if status := c.Writer.Status(); status >= 200 && status < 300 && len(reqViolations) > 0 {
+				violated(status, reqViolations)
+			}
+			return
+		}
+
+		w := &bufferedWriter{ResponseWriter: c.Writer, status: http.StatusOK}
+		c.Writer = w
+		c.Next()
+		c.Writer = w.ResponseWriter
+
+		var violations []string
+		if op == nil {
+			violations = []string{"route is not documented"}
+		} else {
+			if w.status >= 200 && w.status < 300 {
+				violations = reqViolations
+			}
+			violations = append(violations, op.ValidateResponse(c.Request, pathParams, w.status, w.Header(), w.body.Bytes())...)
+		}
+		if len(violations) == 0 {
+			w.flush()
+			return
+		}
+
+		violated(w.status, violations)
+		// the headers of the replaced response, e.g. an attachment
+		w.Header().Del("Content-Disposition")
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "the response does not match the OpenAPI contract",
+			"details": violations,
+		})
+	}
+}
+
+// validateRequest checks a copy of the request, the body of r is read up to
+// openAPIMaxBody and put back in front of the rest of it. A multipart body is
+// not read, nor checked.
+func validateRequest(r *http.Request, op *openapi.Operation, pathParams map[string]string) []string {
+	checked := r.Clone(r.Context())
+	checked.Body = http.NoBody
+	checkBody := !strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data")
+	if checkBody && r.Body != nil && r.Body != http.NoBody {
+		head, _ := io.ReadAll(io.LimitReader(r.Body, openAPIMaxBody+1))
+		r.Body = readCloser{Reader: io.MultiReader(bytes.NewReader(head), r.Body), Closer: r.Body}
+		checkBody = len(head) <= openAPIMaxBody
+		checked.Body = io.NopCloser(bytes.NewReader(head))
+	}
+
+	return op.ValidateRequest(checked, pathParams, checkBody)
+}
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// bufferedWriter holds the status and the body until the exchange is checked,
+// the headers go to the wrapped writer as they are not sent before the body
+type bufferedWriter struct {
+	gin.ResponseWriter
+	status  int
+	written bool
+	body    bytes.Buffer
+}
+
+func (w *bufferedWriter) WriteHeader(code int) {
+	if code > 0 && !w.written {
+		w.status = code
+	}
+}
+
+func (w *bufferedWriter) WriteHeaderNow() { w.written = true }
+
+func (w *bufferedWriter) Write(b []byte) (int, error) {
+	w.written = true
+	return w.body.Write(b)
+}
+
+func (w *bufferedWriter) WriteString(s string) (int, error) {
+	w.written = true
+	return w.body.WriteString(s)
+}
+
+func (w *bufferedWriter) Status() int { return w.status }
+
+func (w *bufferedWriter) Size() int {
+	if !w.written {
+		return -1
+	}
+	return w.body.Len()
+}
+
+func (w *bufferedWriter) Written() bool { return w.written }
+
+// Flush - nothing is sent before the check
+func (w *bufferedWriter) Flush() {}
+
+func (w *bufferedWriter) flush() {
+	w.ResponseWriter.WriteHeader(w.status)
+	if w.body.Len() > 0 {
+		_, _ = w.ResponseWriter.Write(w.body.Bytes())
+		return
+	}
+	w.ResponseWriter.WriteHeaderNow()
+}
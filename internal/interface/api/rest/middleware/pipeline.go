@@ -0,0 +1,24 @@
+package middleware
+
+import (
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Pipeline - the middlewares of names(SERVICE_MIDDLEWARES) in their order out of
+// available, a nil one is off by its own settings(e.g. openapi) and skipped
+func Pipeline(names []string, available map[string]gin.HandlerFunc) ([]gin.HandlerFunc, error) {
+	pipeline := make([]gin.HandlerFunc, 0, len(names))
+	for _, name := range names {
+		h, ok := available[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown middleware %q", name)
+		}
+		if h != nil {
+			pipeline = append(pipeline, h)
+		}
+	}
+
+	return pipeline, nil
+}
@@ -0,0 +1,27 @@
+package middleware
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"user-manager-api/pkg/ratelimit"
+)
+
+// RateLimitByIP rejects requests over the limiter budget of the client IP with 429.
+func RateLimitByIP(limiter *ratelimit.Limiter) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if ok, retryAfter := limiter.Allow(c.ClientIP()); !ok {
+			c.Header("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+			c.AbortWithStatusJSON(
+				http.StatusTooManyRequests,
+				gin.H{"error": "too many requests"},
+			)
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,40 @@
+package middleware
+
+import (
+	"net/http"
+	"slices"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"user-manager-api/internal/application/ports"
+)
+
+const CodeReadOnly = "read_only"
+
+// ReadOnly rejects the mutating requests with 503 while the mode is on, the
+// reads and the exempt routes(e.g. the login: a read-only service is of no use
+// without tokens, the toggle itself) pass
+func ReadOnly(readOnly ports.ReadOnlyService, mCounter *prometheus.CounterVec, exemptRoutes ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !readOnly.Enabled() {
+			c.Next()
+			return
+		}
+		switch c.Request.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			c.Next()
+			return
+		}
+		if slices.Contains(exemptRoutes, c.FullPath()) {
+			c.Next()
+			return
+		}
+
+		mCounter.WithLabelValues("read_only_rejected_total").Inc()
+		c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{
+			"error": "the service is read-only, try again later",
+			"code":  CodeReadOnly,
+		})
+	}
+}
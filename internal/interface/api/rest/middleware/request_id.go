@@ -0,0 +1,46 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const (
+	// HeaderRequestID - of the request, echoed in the response
+	HeaderRequestID = "X-Request-ID"
+	// CtxRequestID - the id of the request, logged with it
+	CtxRequestID = "requestID"
+)
+
+// maxRequestIDLen - a longer id of the caller is replaced
+const maxRequestIDLen = 128
+
+// RequestID tags the request with the X-Request-ID of the caller(a load
+// balancer, another service) or a new UUID, echoed in the response so the
+// support finds the request in the logs.
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(HeaderRequestID)
+		if !validRequestID(id) {
+			id = uuid.NewString()
+		}
+		c.Set(CtxRequestID, id)
+		c.Header(HeaderRequestID, id)
+
+		c.Next()
+	}
+}
+
+// validRequestID - printable ASCII only, the id goes to the logs and the headers
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLen {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] < 0x21 || id[i] > 0x7e {
+			return false
+		}
+	}
+
+	return true
+}
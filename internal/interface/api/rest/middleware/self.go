@@ -0,0 +1,37 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"user-manager-api/internal/domain/user"
+)
+
+// SelfParam exposes the token subject as the name path param, so the handlers of
+// "/users/:user_id" serve the "/me" routes as is. Must be chained after AuthMiddleware.
+func SelfParam(name string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.AddParam(name, c.GetString(CtxUserID))
+		c.Next()
+	}
+}
+
+// SelfOrAdmin lets the token subject reach the resources of the user of the
+// name path param, admins those of anyone. A param not a UUID is left to the
+// handler(400). Must be chained after AuthMiddleware.
+func SelfOrAdmin(name string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		target, err := uuid.Parse(c.Param(name))
+		if err != nil || c.GetString(CtxUserRole) == user.RoleAdmin || c.GetString(CtxUserID) == target.String() {
+			c.Next()
+			return
+		}
+
+		c.AbortWithStatusJSON(
+			http.StatusForbidden,
+			gin.H{"error": "only the user itself and admins are allowed"},
+		)
+	}
+}
@@ -0,0 +1,119 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+)
+
+const CodeTimeout = "timeout"
+
+// RequestTimeout - the deadline budget of a request: the DB queries and the
+// storage calls of the handler inherit it through c.Request.Context(), so the
+// handler returns instead of waiting for a slow backend. routes("<METHOD>
+// <route>") have their own budgets, the other multipart(file upload) requests
+// get uploadTimeout, the body transfer counts against it. 0 disables a budget.
+// A request out of its budget before a response is answered 504, whatever
+// the handler writes after the deadline is discarded.
+func RequestTimeout(timeout, uploadTimeout time.Duration, routes map[string]time.Duration, logger *zap.Logger, mCounter *prometheus.CounterVec) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		d, ok := routes[c.Request.Method+" "+c.FullPath()]
+		switch {
+		case ok:
+		case strings.HasPrefix(c.GetHeader("Content-Type"), "multipart/form-data"):
+			d = uploadTimeout
+		default:
+			d = timeout
+		}
+		if d <= 0 {
+			c.Next()
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), d)
+		defer cancel()
+		c.Request = c.Request.WithContext(ctx)
+		w := &timeoutWriter{ResponseWriter: c.Writer, ctx: ctx}
+		c.Writer = w
+		// the headers of the middlewares before, the handler's ones go with its response
+		header := w.Header().Clone()
+
+		c.Next()
+
+		if !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return
+		}
+		mCounter.WithLabelValues("http_request_timeouts_total").Inc()
+		logger.Warn("request deadline exceeded",
+			zap.String("method", c.Request.Method),
+			zap.String("url", c.FullPath()),
+			zap.Duration("timeout", d),
+		)
+		if w.ResponseWriter.Written() {
+			// a response started in time, e.g. a stream: it is cut
+			return
+		}
+		c.Writer = w.ResponseWriter
+		clear(c.Writer.Header())
+		for k, v := range header {
+			c.Writer.Header()[k] = v
+		}
+		c.AbortWithStatusJSON(http.StatusGatewayTimeout, gin.H{
+			"error":   "the request took too long, try again later",
+			"code":    CodeTimeout,
+			"timeout": d.String(),
+		})
+	}
+}
+
+// timeoutWriter discards the response of a handler written after the deadline,
+// usually the 500 of the canceled query, the middleware answers 504 instead
+type timeoutWriter struct {
+	gin.ResponseWriter
+	ctx context.Context
+}
+
+func (w *timeoutWriter) late() bool {
+	return !w.ResponseWriter.Written() && errors.Is(w.ctx.Err(), context.DeadlineExceeded)
+}
+
+func (w *timeoutWriter) WriteHeader(code int) {
+	if w.late() {
+		return
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *timeoutWriter) WriteHeaderNow() {
+	if w.late() {
+		return
+	}
+	w.ResponseWriter.WriteHeaderNow()
+}
+
+func (w *timeoutWriter) Write(b []byte) (int, error) {
+	if w.late() {
+		return len(b), nil
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *timeoutWriter) WriteString(s string) (int, error) {
+	if w.late() {
+		return len(s), nil
+	}
+	return w.ResponseWriter.WriteString(s)
+}
+
+func (w *timeoutWriter) Flush() {
+	if w.late() {
+		return
+	}
+	w.ResponseWriter.Flush()
+}
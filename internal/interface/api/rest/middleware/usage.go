@@ -0,0 +1,30 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"user-manager-api/internal/application/ports"
+)
+
+// Usage records every request for the usage metrics by the user and the role
+// the route's AuthMiddleware set, so it must run before the route handlers.
+func Usage(usageService ports.UsageService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.Method == http.MethodOptions ||
+			c.Request.URL.Path == "/favicon.ico" ||
+			strings.HasSuffix(c.Request.URL.Path, "/metrics") {
+			c.Next()
+			return
+		}
+
+		start := time.Now()
+
+		c.Next()
+
+		usageService.Record(c.GetString(CtxUserID), c.GetString(CtxUserRole), time.Since(start))
+	}
+}
@@ -0,0 +1,97 @@
+package rest
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"user-manager-api/internal/application/ports"
+	"user-manager-api/internal/application/services"
+	"user-manager-api/internal/interface/api/rest/dto/notification"
+	"user-manager-api/internal/interface/api/rest/middleware"
+	"user-manager-api/internal/interface/api/rest/validator"
+)
+
+// NotificationController - the notification preferences of the token subject
+type NotificationController struct {
+	preferenceService ports.NotificationPreferenceService
+	logger            *zap.Logger
+}
+
+func NewNotificationController(
+	r *gin.Engine,
+	preferenceService ports.NotificationPreferenceService,
+	logger *zap.Logger,
+	tokenService ports.TokenService,
+) *NotificationController {
+	nc := &NotificationController{
+		preferenceService: preferenceService,
+		logger:            logger,
+	}
+
+	r.GET(RouteMeNotifications, middleware.AuthMiddleware(tokenService), nc.GetPreferencesHandler)
+	r.PUT(RouteMeNotifications, middleware.AuthMiddleware(tokenService), nc.SetPreferencesHandler)
+
+	return nc
+}
+
+// GetPreferencesHandler - every channel, the defaults for the ones never set
+func (nc *NotificationController) GetPreferencesHandler(c *gin.Context) {
+	ok, userUUID := validator.IsUUID(c.GetString(middleware.CtxUserID))
+	if !ok {
+		c.JSON(
+			http.StatusUnauthorized,
+			gin.H{"error": "invalid token"},
+		)
+		return
+	}
+
+	prefs, err := nc.preferenceService.Preferences(c.Request.Context(), userUUID)
+	if err != nil {
+		c.JSON(
+			http.StatusInternalServerError,
+			gin.H{"error": "failed to get the notification preferences"},
+		)
+		nc.logger.Error("Preferences() error", zap.Error(err), zap.Stringer("user_uuid", userUUID))
+		return
+	}
+
+	c.JSON(http.StatusOK, notification.ToResponsePreferences(prefs))
+}
+
+// SetPreferencesHandler - the channels of the request are set, the others kept
+func (nc *NotificationController) SetPreferencesHandler(c *gin.Context) {
+	ok, userUUID := validator.IsUUID(c.GetString(middleware.CtxUserID))
+	if !ok {
+		c.JSON(
+			http.StatusUnauthorized,
+			gin.H{"error": "invalid token"},
+		)
+		return
+	}
+	req, ok := BindAndValidate(c, validator.ValidateNotificationPreferences)
+	if !ok {
+		return
+	}
+
+	prefs, err := nc.preferenceService.SetPreferences(c.Request.Context(), userUUID, notification.ToDomainPreferences(req))
+	if err != nil {
+		if errors.Is(err, services.ErrUserNotFound) {
+			c.JSON(
+				http.StatusNotFound,
+				gin.H{"error": services.ErrUserNotFound.Error(), "code": codeUserNotFound},
+			)
+			return
+		}
+		c.JSON(
+			http.StatusInternalServerError,
+			gin.H{"error": "failed to set the notification preferences"},
+		)
+		nc.logger.Error("SetPreferences() error", zap.Error(err), zap.Stringer("user_uuid", userUUID))
+		return
+	}
+
+	c.JSON(http.StatusOK, notification.ToResponsePreferences(prefs))
+}
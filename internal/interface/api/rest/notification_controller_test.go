@@ -0,0 +1,191 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"user-manager-api/internal/application/services"
+	domain "user-manager-api/internal/domain/notification"
+	"user-manager-api/internal/domain/user"
+	jwtSvc "user-manager-api/internal/infrastructure/jwt"
+	"user-manager-api/internal/interface/api/rest/dto/notification"
+)
+
+type fakeNotificationPreferenceService struct {
+	PreferencesFunc    func(ctx context.Context, userUUID user.UUID) (domain.Preferences, error)
+	SetPreferencesFunc func(ctx context.Context, userUUID user.UUID, p domain.Preferences) (domain.Preferences, error)
+}
+
+func (f *fakeNotificationPreferenceService) Preferences(ctx context.Context, userUUID user.UUID) (domain.Preferences, error) {
+	if f.PreferencesFunc == nil {
+		return nil, errors.New("not used")
+	}
+	return f.PreferencesFunc(ctx, userUUID)
+}
+
+func (f *fakeNotificationPreferenceService) SetPreferences(
+	ctx context.Context,
+	userUUID user.UUID,
+	p domain.Preferences,
+) (domain.Preferences, error) {
+	if f.SetPreferencesFunc == nil {
+		return nil, errors.New("not used")
+	}
+	return f.SetPreferencesFunc(ctx, userUUID, p)
+}
+
+func setupNotificationRouter(t *testing.T, ps *fakeNotificationPreferenceService) (*gin.Engine, *jwtSvc.Service) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	j := jwtSvc.New("test-secret")
+	NewNotificationController(r, ps, zap.NewNop(), j)
+
+	return r, j
+}
+
+func TestNotificationController(t *testing.T) {
+	userID := uuid.New()
+	prefs := domain.Preferences{
+		{Channel: domain.ChannelEmail, Enabled: true, Mode: domain.ModeDigest},
+		{Channel: domain.ChannelSMS, Enabled: false, Mode: domain.ModeInstant},
+		{Channel: domain.ChannelWebhook, Enabled: true, Mode: domain.ModeInstant, WebhookURL: "https://hooks.example.com/u"},
+	}
+	wantPrefs := []notification.Preference{
+		{Channel: "email", Enabled: true, Mode: "digest"},
+		{Channel: "sms", Enabled: false, Mode: "instant"},
+		{Channel: "webhook", Enabled: true, Mode: "instant", WebhookURL: "https://hooks.example.com/u"},
+	}
+	body := notification.Request{Preferences: []notification.Preference{
+		{Channel: "email", Enabled: true, Mode: "digest"},
+		{Channel: "webhook", Enabled: true, WebhookURL: " https://hooks.example.com/u "},
+	}}
+
+	type tc struct {
+		name       string
+		method     string
+		body       any
+		noToken    bool
+		get        func(ctx context.Context, userUUID user.UUID) (domain.Preferences, error)
+		set        func(ctx context.Context, userUUID user.UUID, p domain.Preferences) (domain.Preferences, error)
+		wantStatus int
+		wantErr    string
+	}
+
+	cases := []tc{
+		{
+			name:   "GET 200",
+			method: http.MethodGet,
+			get: func(_ context.Context, userUUID user.UUID) (domain.Preferences, error) {
+				if userUUID != userID {
+					return nil, errors.New("unexpected user")
+				}
+				return prefs, nil
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "GET 401 no token",
+			method:     http.MethodGet,
+			noToken:    true,
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:   "GET 500",
+			method: http.MethodGet,
+			get: func(context.Context, user.UUID) (domain.Preferences, error) {
+				return nil, errors.New("db down")
+			},
+			wantStatus: http.StatusInternalServerError,
+			wantErr:    "failed to get the notification preferences",
+		},
+		{
+			name:   "PUT 200",
+			method: http.MethodPut,
+			body:   body,
+			set: func(_ context.Context, userUUID user.UUID, p domain.Preferences) (domain.Preferences, error) {
+				want := domain.Preferences{
+					{Channel: domain.ChannelEmail, Enabled: true, Mode: domain.ModeDigest},
+					{Channel: domain.ChannelWebhook, Enabled: true, Mode: domain.ModeInstant, WebhookURL: "https://hooks.example.com/u"},
+				}
+				if userUUID != userID || len(p) != 2 || p[0] != want[0] || p[1] != want[1] {
+					return nil, errors.New("unexpected args")
+				}
+				return prefs, nil
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:   "PUT 400 webhook without url",
+			method: http.MethodPut,
+			body: notification.Request{Preferences: []notification.Preference{
+				{Channel: "webhook", Enabled: true},
+			}},
+			wantStatus: http.StatusBadRequest,
+			wantErr:    errInvalidRequestBody,
+		},
+		{
+			name:   "PUT 404",
+			method: http.MethodPut,
+			body:   body,
+			set: func(context.Context, user.UUID, domain.Preferences) (domain.Preferences, error) {
+				return nil, services.ErrUserNotFound
+			},
+			wantStatus: http.StatusNotFound,
+			wantErr:    services.ErrUserNotFound.Error(),
+		},
+		{
+			name:   "PUT 500",
+			method: http.MethodPut,
+			body:   body,
+			set: func(context.Context, user.UUID, domain.Preferences) (domain.Preferences, error) {
+				return nil, errors.New("db down")
+			},
+			wantStatus: http.StatusInternalServerError,
+			wantErr:    "failed to set the notification preferences",
+		},
+	}
+
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			r, j := setupNotificationRouter(t, &fakeNotificationPreferenceService{
+				PreferencesFunc:    tt.get,
+				SetPreferencesFunc: tt.set,
+			})
+			headers := map[string]string{}
+			if !tt.noToken {
+				tok, err := j.GenerateToken(userID.String(), user.RoleWorker, time.Minute)
+				require.NoError(t, err)
+				headers["Authorization"] = "Bearer " + tok
+			}
+
+			rr := doReq(t, r, tt.method, RouteMeNotifications, tt.body, headers)
+			require.Equal(t, tt.wantStatus, rr.Code, rr.Body.String())
+
+			if tt.wantErr != "" {
+				var resp map[string]any
+				require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+				assert.Equal(t, tt.wantErr, resp["error"])
+				return
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			var resp notification.Preferences
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+			assert.Equal(t, wantPrefs, resp.Preferences)
+		})
+	}
+}
@@ -0,0 +1,151 @@
+package rest
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"user-manager-api/internal/interface/api/rest/api-specs/openapi/usermanagerapi"
+	"user-manager-api/internal/interface/api/rest/middleware"
+	"user-manager-api/pkg/openapi"
+)
+
+func TestOpenAPIMiddleware_Table(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	spec, err := openapi.Load(usermanagerapi.Spec)
+	require.NoError(t, err, "the spec must load with every $ref resolved")
+
+	mCounter := prometheus.NewCounterVec(prometheus.CounterOpts{Name: "test"}, []string{"result"})
+	r := gin.New()
+	r.Use(middleware.OpenAPI(spec, zap.NewNop(), mCounter))
+
+	var loginResp gin.H
+	r.POST(RouteLogin, func(c *gin.Context) { c.JSON(http.StatusOK, loginResp) })
+	r.GET(RouteUsers, func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"data": []any{}})
+	})
+	r.GET(RouteAdminCollection, func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"info": gin.H{}})
+	})
+	r.POST(RouteUsersValidate, func(c *gin.Context) {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": errInvalidRequestBody, "details": "bad"})
+	})
+	r.GET(RouteApiV1+"/undocumented", func(c *gin.Context) { c.Status(http.StatusOK) })
+	r.GET("/metrics", func(c *gin.Context) { c.String(http.StatusOK, "# metrics") })
+
+	type tc struct {
+		name       string
+		method     string
+		path       string
+		body       any
+		loginResp  gin.H
+		wantStatus int
+		wantDetail string
+		// wantLogged - a violation of a streamed response, logged only
+		wantLogged bool
+	}
+	tests := []tc{
+		{
+			name:       "documented",
+			method:     http.MethodPost,
+			path:       RouteLogin,
+			body:       map[string]string{"email": "a@example.com", "password": "secret123"},
+			loginResp:  gin.H{"access_token": "t", "token_type": "Bearer"},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "response out of the schema",
+			method:     http.MethodPost,
+			path:       RouteLogin,
+			body:       map[string]string{"email": "a@example.com", "password": "secret123"},
+			loginResp:  gin.H{"token": "t"},
+			wantStatus: http.StatusInternalServerError,
+			wantDetail: `response body doesn't match schema #/components/schemas/AuthTokenResponse: /access_token: property "access_token" is missing`,
+		},
+		{
+			name:       "accepted request out of the contract",
+			method:     http.MethodPost,
+			path:       RouteLogin,
+			body:       map[string]any{"email": 42},
+			loginResp:  gin.H{"access_token": "t", "token_type": "Bearer"},
+			wantStatus: http.StatusInternalServerError,
+			wantDetail: "request body has an error: doesn't match schema #/components/schemas/LoginRequest: /email: value must be a string",
+		},
+		{
+			name:       "request over the checked size",
+			method:     http.MethodPost,
+			path:       RouteLogin,
+			body:       map[string]any{"email": 42, "password": strings.Repeat("x", 1<<20)},
+			loginResp:  gin.H{"access_token": "t", "token_type": "Bearer"},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "rejected request",
+			method:     http.MethodPost,
+			path:       RouteUsersValidate,
+			body:       map[string]any{"email": 42},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "query out of the contract",
+			method:     http.MethodGet,
+			path:       RouteAdminCollection + "?format=yaml",
+			wantStatus: http.StatusInternalServerError,
+			wantDetail: `parameter "format" in query has an error: value is not one of the allowed values ["postman","http"]`,
+		},
+		{
+			name:       "streamed query out of the contract",
+			method:     http.MethodGet,
+			path:       RouteUsers + "?sort=age",
+			wantStatus: http.StatusOK,
+			wantLogged: true,
+		},
+		{
+			name:       "route not documented",
+			method:     http.MethodGet,
+			path:       RouteApiV1 + "/undocumented",
+			wantStatus: http.StatusInternalServerError,
+			wantDetail: "route is not documented",
+		},
+		{
+			name:       "out of the API",
+			method:     http.MethodGet,
+			path:       "/metrics",
+			wantStatus: http.StatusOK,
+		},
+	}
+
+	violated := 0
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loginResp = tt.loginResp
+			w := doReq(t, r, tt.method, tt.path, tt.body, nil)
+			require.Equal(t, tt.wantStatus, w.Code, w.Body.String())
+			if tt.wantLogged {
+				violated++
+			}
+			if tt.wantDetail == "" {
+				return
+			}
+			violated++
+
+			var body struct {
+				Error   string   `json:"error"`
+				Details []string `json:"details"`
+			}
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+			assert.Equal(t, "the response does not match the OpenAPI contract", body.Error)
+			require.NotEmpty(t, body.Details)
+			assert.Contains(t, body.Details[0], tt.wantDetail)
+		})
+	}
+	assert.Equal(t, float64(violated), testutil.ToFloat64(mCounter.WithLabelValues("openapi_violations_total")))
+}
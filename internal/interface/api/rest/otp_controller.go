@@ -0,0 +1,101 @@
+package rest
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"user-manager-api/internal/application/ports"
+	"user-manager-api/internal/application/services"
+	"user-manager-api/internal/interface/api/rest/middleware"
+	"user-manager-api/internal/interface/api/rest/validator"
+	"user-manager-api/pkg/ratelimit"
+)
+
+type OTPController struct {
+	logger     *zap.Logger
+	otpService ports.OTPService
+}
+
+func NewOTPController(
+	r *gin.Engine,
+	logger *zap.Logger,
+	otpService ports.OTPService,
+	ipLimiter *ratelimit.Limiter,
+) *OTPController {
+	oc := &OTPController{
+		logger:     logger,
+		otpService: otpService,
+	}
+
+	limit := middleware.RateLimitByIP(ipLimiter)
+	r.POST(RouteOTPRequest, limit, oc.RequestHandler)
+	r.POST(RouteOTPVerify, limit, oc.VerifyHandler)
+
+	return oc
+}
+
+// RequestHandler answers the same way for known and unknown phones.
+func (oc *OTPController) RequestHandler(c *gin.Context) {
+	req, ok := BindAndValidate(c, validator.ValidateOTPRequest)
+	if !ok {
+		return
+	}
+
+	if err := oc.otpService.RequestOTP(c.Request.Context(), strings.TrimSpace(req.Phone)); err != nil {
+		var limited *services.OTPRateLimitedError
+		if errors.As(err, &limited) {
+			c.Header("Retry-After", strconv.Itoa(int(math.Ceil(limited.RetryAfter.Seconds()))))
+			c.JSON(http.StatusTooManyRequests, gin.H{"error": "code was requested recently"})
+			return
+		}
+		c.JSON(
+			http.StatusInternalServerError,
+			gin.H{"error": "failed to send a code"},
+		)
+		oc.logger.Error("RequestOTP() error", zap.Error(err))
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"message": "if the phone is registered, a code has been sent"})
+}
+
+func (oc *OTPController) VerifyHandler(c *gin.Context) {
+	req, ok := BindAndValidate(c, validator.ValidateOTPVerify)
+	if !ok {
+		return
+	}
+
+	token, err := oc.otpService.VerifyOTP(
+		c.Request.Context(),
+		strings.TrimSpace(req.Phone),
+		strings.TrimSpace(req.Code),
+	)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidOTP):
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		case errors.Is(err, services.ErrPasswordResetRequired):
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		case errors.Is(err, services.ErrAccountSuspended):
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error(), "code": codeAccountSuspended})
+		default:
+			c.JSON(
+				http.StatusInternalServerError,
+				gin.H{"error": "failed to verify the code"},
+			)
+			oc.logger.Error("VerifyOTP() error", zap.Error(err))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"access_token": token,
+		"token_type":   "Bearer",
+	})
+}
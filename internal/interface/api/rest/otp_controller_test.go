@@ -0,0 +1,202 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"user-manager-api/internal/application/services"
+	"user-manager-api/internal/interface/api/rest/dto/auth"
+	"user-manager-api/pkg/ratelimit"
+)
+
+type fakeOTPService struct {
+	RequestOTPFunc func(ctx context.Context, phone string) error
+	VerifyOTPFunc  func(ctx context.Context, phone, code string) (string, error)
+}
+
+func (f *fakeOTPService) RequestOTP(ctx context.Context, phone string) error {
+	if f.RequestOTPFunc == nil {
+		return errors.New("not used")
+	}
+	return f.RequestOTPFunc(ctx, phone)
+}
+
+func (f *fakeOTPService) VerifyOTP(ctx context.Context, phone, code string) (string, error) {
+	if f.VerifyOTPFunc == nil {
+		return "", errors.New("not used")
+	}
+	return f.VerifyOTPFunc(ctx, phone, code)
+}
+
+func setupOTPRouter(t *testing.T, s *fakeOTPService, ipLimit int) *gin.Engine {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	NewOTPController(r, zap.NewNop(), s, ratelimit.New(ipLimit, time.Minute))
+
+	return r
+}
+
+func TestOTPController_RequestHandler(t *testing.T) {
+	type tc struct {
+		name           string
+		body           any
+		request        func(ctx context.Context, phone string) error
+		wantStatus     int
+		wantErr        string
+		wantRetryAfter string
+	}
+	cases := []tc{
+		{
+			name: "202 sent",
+			body: auth.OTPRequest{Phone: " +33788888888 "},
+			request: func(ctx context.Context, phone string) error {
+				if phone != "+33788888888" {
+					return errors.New("phone is not trimmed")
+				}
+				return nil
+			},
+			wantStatus: http.StatusAccepted,
+		},
+		{
+			name:       "400 invalid phone",
+			body:       auth.OTPRequest{Phone: "0788888888"},
+			wantStatus: http.StatusBadRequest,
+			wantErr:    errInvalidRequestBody,
+		},
+		{
+			name: "429 phone cooldown",
+			body: auth.OTPRequest{Phone: "+33788888888"},
+			request: func(ctx context.Context, phone string) error {
+				return &services.OTPRateLimitedError{RetryAfter: 1500 * time.Millisecond}
+			},
+			wantStatus:     http.StatusTooManyRequests,
+			wantErr:        "code was requested recently",
+			wantRetryAfter: "2",
+		},
+		{
+			name: "500 service error",
+			body: auth.OTPRequest{Phone: "+33788888888"},
+			request: func(ctx context.Context, phone string) error {
+				return errors.New("sms provider down")
+			},
+			wantStatus: http.StatusInternalServerError,
+			wantErr:    "failed to send a code",
+		},
+	}
+
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			r := setupOTPRouter(t, &fakeOTPService{RequestOTPFunc: tt.request}, 10)
+
+			rr := doPOST(t, r, RouteOTPRequest, tt.body)
+			require.Equal(t, tt.wantStatus, rr.Code, rr.Body.String())
+			assert.Equal(t, tt.wantRetryAfter, rr.Header().Get("Retry-After"))
+
+			var resp map[string]any
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+			if tt.wantErr != "" {
+				assert.Equal(t, tt.wantErr, resp["error"])
+			}
+		})
+	}
+}
+
+func TestOTPController_VerifyHandler(t *testing.T) {
+	type tc struct {
+		name       string
+		body       any
+		verify     func(ctx context.Context, phone, code string) (string, error)
+		wantStatus int
+		wantErr    string
+	}
+	cases := []tc{
+		{
+			name: "200 token",
+			body: auth.OTPVerifyRequest{Phone: "+33788888888", Code: "123456"},
+			verify: func(ctx context.Context, phone, code string) (string, error) {
+				return "tok", nil
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "400 invalid code",
+			body:       auth.OTPVerifyRequest{Phone: "+33788888888", Code: "12ab"},
+			wantStatus: http.StatusBadRequest,
+			wantErr:    errInvalidRequestBody,
+		},
+		{
+			name: "401 wrong code",
+			body: auth.OTPVerifyRequest{Phone: "+33788888888", Code: "000000"},
+			verify: func(ctx context.Context, phone, code string) (string, error) {
+				return "", services.ErrInvalidOTP
+			},
+			wantStatus: http.StatusUnauthorized,
+			wantErr:    services.ErrInvalidOTP.Error(),
+		},
+		{
+			name: "403 password reset required",
+			body: auth.OTPVerifyRequest{Phone: "+33788888888", Code: "123456"},
+			verify: func(ctx context.Context, phone, code string) (string, error) {
+				return "", services.ErrPasswordResetRequired
+			},
+			wantStatus: http.StatusForbidden,
+			wantErr:    services.ErrPasswordResetRequired.Error(),
+		},
+		{
+			name: "500 service error",
+			body: auth.OTPVerifyRequest{Phone: "+33788888888", Code: "123456"},
+			verify: func(ctx context.Context, phone, code string) (string, error) {
+				return "", errors.New("db down")
+			},
+			wantStatus: http.StatusInternalServerError,
+			wantErr:    "failed to verify the code",
+		},
+	}
+
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			r := setupOTPRouter(t, &fakeOTPService{VerifyOTPFunc: tt.verify}, 10)
+
+			rr := doPOST(t, r, RouteOTPVerify, tt.body)
+			require.Equal(t, tt.wantStatus, rr.Code, rr.Body.String())
+
+			var resp map[string]any
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+			if tt.wantErr != "" {
+				assert.Equal(t, tt.wantErr, resp["error"])
+				return
+			}
+			assert.Equal(t, "tok", resp["access_token"])
+			assert.Equal(t, "Bearer", resp["token_type"])
+		})
+	}
+}
+
+func TestOTPController_IPRateLimit(t *testing.T) {
+	r := setupOTPRouter(t, &fakeOTPService{
+		RequestOTPFunc: func(ctx context.Context, phone string) error { return nil },
+	}, 2)
+
+	body := auth.OTPRequest{Phone: "+33788888888"}
+	for i := 0; i < 2; i++ {
+		rr := doPOST(t, r, RouteOTPRequest, body)
+		require.Equal(t, http.StatusAccepted, rr.Code)
+	}
+
+	rr := doPOST(t, r, RouteOTPRequest, body)
+	require.Equal(t, http.StatusTooManyRequests, rr.Code)
+	assert.NotEmpty(t, rr.Header().Get("Retry-After"))
+}
@@ -0,0 +1,156 @@
+package rest
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"user-manager-api/internal/interface/api/rest/middleware"
+)
+
+func TestPipeline_Order(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	var order []string
+	mark := func(name string) gin.HandlerFunc {
+		return func(c *gin.Context) { order = append(order, name); c.Next() }
+	}
+	available := map[string]gin.HandlerFunc{"a": mark("a"), "b": mark("b"), "off": nil}
+
+	pipeline, err := middleware.Pipeline([]string{"b", "off", "a"}, available)
+	require.NoError(t, err)
+	r := gin.New()
+	r.Use(pipeline...)
+	r.GET(RouteHealth, func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	w := doReq(t, r, http.MethodGet, RouteHealth, nil, nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, []string{"b", "a"}, order)
+
+	_, err = middleware.Pipeline([]string{"a", "gzip"}, available)
+	assert.EqualError(t, err, `unknown middleware "gzip"`)
+}
+
+func TestRequestIDMiddleware_Table(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	var got string
+	r := gin.New()
+	r.Use(middleware.RequestID())
+	r.GET(RouteHealth, func(c *gin.Context) { got = c.GetString(middleware.CtxRequestID) })
+
+	tests := []struct {
+		name   string
+		header string
+		keep   bool
+	}{
+		{"caller's", "lb-5f2c9a", true},
+		{"none", "", false},
+		{"spaces", "a b", false},
+		{"too long", strings.Repeat("a", 129), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := doReq(t, r, http.MethodGet, RouteHealth, nil, map[string]string{middleware.HeaderRequestID: tt.header})
+			assert.Equal(t, got, w.Header().Get(middleware.HeaderRequestID))
+			if tt.keep {
+				assert.Equal(t, tt.header, got)
+				return
+			}
+			_, err := uuid.Parse(got)
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestCORSMiddleware_Table(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	RegisterFallbackHandlers(r)
+	r.Use(middleware.CORS([]string{"https://app.example.com"}))
+	r.GET(RouteUsers, func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	tests := []struct {
+		name       string
+		method     string
+		headers    map[string]string
+		wantStatus int
+		wantOrigin string
+	}{
+		{"allowed", http.MethodGet, map[string]string{"Origin": "https://app.example.com"}, http.StatusOK, "https://app.example.com"},
+		{"other origin", http.MethodGet, map[string]string{"Origin": "https://evil.example.com"}, http.StatusOK, ""},
+		{"same origin", http.MethodGet, nil, http.StatusOK, ""},
+		{"preflight", http.MethodOptions, map[string]string{
+			"Origin":                         "https://app.example.com",
+			"Access-Control-Request-Method":  http.MethodGet,
+			"Access-Control-Request-Headers": "authorization",
+		}, http.StatusNoContent, "https://app.example.com"},
+		{"preflight of other origin", http.MethodOptions, map[string]string{
+			"Origin":                        "https://evil.example.com",
+			"Access-Control-Request-Method": http.MethodGet,
+		}, http.StatusMethodNotAllowed, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := doReq(t, r, tt.method, RouteUsers, nil, tt.headers)
+			require.Equal(t, tt.wantStatus, w.Code)
+			assert.Equal(t, tt.wantOrigin, w.Header().Get("Access-Control-Allow-Origin"))
+			if tt.wantStatus == http.StatusNoContent {
+				assert.Equal(t, "authorization", w.Header().Get("Access-Control-Allow-Headers"))
+				assert.Contains(t, w.Header().Get("Access-Control-Allow-Methods"), http.MethodDelete)
+			}
+		})
+	}
+}
+
+func TestCompressMiddleware_Table(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	body := strings.Repeat(`{"name":"Ann"}`, 100)
+	r := gin.New()
+	r.Use(middleware.Compress())
+	r.GET("/json", func(c *gin.Context) { c.Data(http.StatusOK, "application/json; charset=utf-8", []byte(body)) })
+	r.GET("/pdf", func(c *gin.Context) { c.Data(http.StatusOK, "application/pdf", []byte(body)) })
+	r.GET("/empty", func(c *gin.Context) { c.Status(http.StatusNoContent) })
+
+	tests := []struct {
+		name         string
+		path         string
+		accept       string
+		wantEncoding string
+	}{
+		{"json", "/json", "gzip, deflate, br", "gzip"},
+		{"not accepted", "/json", "", ""},
+		{"compressed already", "/pdf", "gzip", ""},
+		{"no body", "/empty", "gzip", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := doReq(t, r, http.MethodGet, tt.path, nil, map[string]string{"Accept-Encoding": tt.accept})
+			require.Less(t, w.Code, 300)
+			assert.Equal(t, tt.wantEncoding, w.Header().Get("Content-Encoding"))
+			if tt.path == "/empty" {
+				assert.Empty(t, w.Body.Bytes())
+				return
+			}
+
+			got := w.Body.String()
+			if tt.wantEncoding == "gzip" {
+				assert.Less(t, w.Body.Len(), len(body))
+				zr, err := gzip.NewReader(w.Body)
+				require.NoError(t, err)
+				b, err := io.ReadAll(zr)
+				require.NoError(t, err)
+				got = string(b)
+			}
+			assert.Equal(t, body, got)
+		})
+	}
+}
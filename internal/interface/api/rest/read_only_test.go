@@ -0,0 +1,68 @@
+package rest
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"user-manager-api/internal/interface/api/rest/middleware"
+)
+
+func TestReadOnlyMiddleware_Table(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mCounter := prometheus.NewCounterVec(prometheus.CounterOpts{Name: "test"}, []string{"result"})
+	readOnly := &fakeReadOnlyService{enabled: true}
+	r := gin.New()
+	r.Use(middleware.ReadOnly(readOnly, mCounter, RouteLogin))
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+	r.GET(RouteUsers, ok)
+	r.HEAD(RouteUsers, ok)
+	r.POST(RouteUsers, ok)
+	r.PUT(RouteUser, ok)
+	r.DELETE(RouteUser, ok)
+	r.POST(RouteLogin, ok)
+
+	type tc struct {
+		name       string
+		method     string
+		path       string
+		wantStatus int
+	}
+	tests := []tc{
+		{"get", http.MethodGet, RouteUsers, http.StatusOK},
+		{"head", http.MethodHead, RouteUsers, http.StatusOK},
+		{"login is exempt", http.MethodPost, RouteLogin, http.StatusOK},
+		{"create", http.MethodPost, RouteUsers, http.StatusServiceUnavailable},
+		{"update", http.MethodPut, RouteUsers + "/1", http.StatusServiceUnavailable},
+		{"delete", http.MethodDelete, RouteUsers + "/1", http.StatusServiceUnavailable},
+	}
+
+	rejected := 0
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := doReq(t, r, tt.method, tt.path, nil, nil)
+			require.Equal(t, tt.wantStatus, w.Code)
+			if tt.wantStatus != http.StatusServiceUnavailable {
+				return
+			}
+			rejected++
+
+			var body map[string]any
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+			assert.Equal(t, middleware.CodeReadOnly, body["code"])
+		})
+	}
+	assert.Equal(t, float64(rejected), testutil.ToFloat64(mCounter.WithLabelValues("read_only_rejected_total")))
+
+	t.Run("disabled", func(t *testing.T) {
+		readOnly.enabled = false
+		w := doReq(t, r, http.MethodPost, RouteUsers, nil, nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+}
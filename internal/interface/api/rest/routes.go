@@ -5,12 +5,90 @@ const (
 	RouteApiV1 = "/api/v1"
 
 	// auth
-	RouteAuth  = RouteApiV1 + "/auth"
-	RouteLogin = RouteAuth + "/login"
+	RouteAuth         = RouteApiV1 + "/auth"
+	RouteLogin        = RouteAuth + "/login"
+	RouteEmailConfirm = RouteAuth + "/email/confirm"
+	RoutePassword     = RouteAuth + "/password"
+	RouteOTPRequest   = RouteAuth + "/otp/request"
+	RouteOTPVerify    = RouteAuth + "/otp/verify"
 
-	RouteUsers     = RouteApiV1 + "/users"
-	RouteUser      = RouteUsers + "/:user_id"
-	RouteUserFiles = RouteUser + "/files"
+	RouteUsers           = RouteApiV1 + "/users"
+	RouteUser            = RouteUsers + "/:user_id"
+	RouteUserFiles       = RouteUser + "/files"
+	RouteUserFile        = RouteUserFiles + "/:file_id"
+	RouteUserFileText    = RouteUserFile + "/text"
+	RouteUserFilesSearch = RouteUserFiles + "/search"
+	RouteUserFolders     = RouteUser + "/folders"
+	// RouteUserFoldersMove - moves(renames) a folder with its subfolders
+	RouteUserFoldersMove = RouteUserFolders + "/move"
+	RouteUserNotes       = RouteUser + "/notes"
+	RouteUserNote        = RouteUserNotes + "/:note_id"
+	RouteUserHistory     = RouteUser + "/history"
+	// RouteUsersValidate - the dry run of POST RouteUsers
+	RouteUsersValidate = RouteUsers + "/validate"
+
+	// signup by invitation
+	RouteInvitations      = RouteApiV1 + "/invitations"
+	RouteInvitationAccept = RouteInvitations + "/:token/accept"
+
+	// the token subject
+	RouteMe              = RouteApiV1 + "/me"
+	RouteMeFiles         = RouteMe + "/files"
+	RouteMeNotifications = RouteMe + "/notifications"
+	RouteMeDevices       = RouteMe + "/devices"
+	RouteMeDevice        = RouteMeDevices + "/:device_id"
+
+	// admin
+	RouteAdmin               = RouteApiV1 + "/admin"
+	RouteAdminImpersonate    = RouteAdmin + "/impersonate/:user_id"
+	RouteAdminForceReset     = RouteAdmin + "/users/:user_id/force-reset"
+	RouteAdminDeletedUsers   = RouteAdmin + "/users/deleted"
+	RouteAdminUserRoles      = RouteAdmin + "/users/roles"
+	RouteAdminDuplicates     = RouteAdmin + "/users/duplicates"
+	RouteAdminMerge          = RouteAdmin + "/users/merge"
+	RouteAdminLegalHold      = RouteAdmin + "/users/:user_id/legal-hold"
+	RouteAdminUserSummary    = RouteAdmin + "/users/:user_id/summary"
+	RouteAdminUserLookup     = RouteAdmin + "/users/lookup"
+	RouteAdminAudit          = RouteAdmin + "/audit"
+	RouteAdminFiles          = RouteAdmin + "/files"
+	RouteAdminRetentionRules = RouteAdminFiles + "/retention-rules"
+	RouteAdminRetentionRule  = RouteAdminRetentionRules + "/:rule_id"
+	// RouteAdminFileRetention - the retention override and the legal hold of a file
+	RouteAdminFileRetention = RouteAdminFiles + "/:file_id/retention"
+	RouteAdminDirectory     = RouteAdmin + "/directory/sync"
+	RouteAdminStats         = RouteAdmin + "/stats"
+	RouteAdminStatsFiles    = RouteAdminStats + "/files"
+	RouteAdminStatsSignups  = RouteAdminStats + "/signups"
+	RouteAdminUsage         = RouteAdmin + "/usage"
+	RouteAdminUsageMonthly  = RouteAdminUsage + "/monthly"
+	RouteAdminSeatLimits    = RouteAdmin + "/seat-limits"
+	RouteAdminSeatLimit     = RouteAdminSeatLimits + "/:org"
+	RouteAdminReadOnly      = RouteAdmin + "/read-only"
+	RouteAdminMQTopology    = RouteAdmin + "/mq/topology"
+	RouteAdminMQRepair      = RouteAdminMQTopology + "/repair"
+	RouteAdminDeadLetters   = RouteAdmin + "/mq/dead-letters"
+	RouteAdminDLQRequeue    = RouteAdminDeadLetters + "/requeue"
+	RouteAdminDLQDiscard    = RouteAdminDeadLetters + "/discard"
+	RouteAdminCollection    = RouteAdmin + "/collection"
+	RouteAdminSlowQueries   = RouteAdmin + "/db/slow-queries"
+
+	// RouteAdminUploadPolicies - the file types, sizes and quotas of the
+	// uploads of the organizations
+	RouteAdminUploadPolicies = RouteAdmin + "/upload-policies"
+	RouteAdminUploadPolicy   = RouteAdminUploadPolicies + "/:org"
+	// RouteAdminReactivate - lifts the suspension of an inactive user
+	RouteAdminReactivate = RouteAdmin + "/users/:user_id/reactivate"
+
+	// files
+	RouteUploads        = RouteApiV1 + "/uploads"
+	RouteUploadProgress = RouteUploads + "/:upload_id/progress"
+
+	RouteFiles    = RouteApiV1 + "/files"
+	RouteFilesRaw = RouteFiles + "/raw/*key"
+
+	// inbound webhooks of the integrations
+	RouteHooks  = RouteApiV1 + "/hooks"
+	RouteHookHR = RouteHooks + "/hr-system"
 
 	// ops
 	RouteHealth  = RouteApiV1 + "/healthz"
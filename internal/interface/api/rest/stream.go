@@ -0,0 +1,67 @@
+package rest
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// jsonList writes {"data":[...],"next_cursor":"..."}(the ResponseData shape of
+// the dto packages) item by item instead of building the DTOs of the page first.
+// The status goes out with the first item: an error before it is answered as
+// usual, a later one can only cut the body short(the client gets an invalid JSON).
+type jsonList struct {
+	w   gin.ResponseWriter
+	enc *json.Encoder
+	n   int
+}
+
+func newJSONList(c *gin.Context) *jsonList {
+	return &jsonList{w: c.Writer, enc: json.NewEncoder(c.Writer)}
+}
+
+// Len - items written so far, 0 - nothing is sent yet
+func (l *jsonList) Len() int {
+	return l.n
+}
+
+// Add - v is a pointer to a DTO reused for all the items, a struct passed by
+// value would be copied to the heap for every one
+func (l *jsonList) Add(v any) error {
+	sep := ","
+	if l.n == 0 {
+		l.writeHeader()
+		sep = `{"data":[`
+	}
+	if _, err := l.w.WriteString(sep); err != nil {
+		return err
+	}
+	l.n++
+
+	return l.enc.Encode(v)
+}
+
+// Close ends the body, an empty nextCursor is omitted
+func (l *jsonList) Close(nextCursor string) error {
+	tail := "]}"
+	if l.n == 0 {
+		l.writeHeader()
+		tail = `{"data":[]}`
+	}
+	if nextCursor != "" {
+		cursor, err := json.Marshal(nextCursor)
+		if err != nil {
+			return err
+		}
+		tail = tail[:len(tail)-1] + `,"next_cursor":` + string(cursor) + "}"
+	}
+	_, err := l.w.WriteString(tail)
+
+	return err
+}
+
+func (l *jsonList) writeHeader() {
+	l.w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	l.w.WriteHeader(http.StatusOK)
+}
@@ -0,0 +1,120 @@
+package rest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	domain "user-manager-api/internal/domain/user"
+	"user-manager-api/internal/interface/api/rest/dto/user"
+)
+
+func TestJSONList(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	type item struct {
+		ID int `json:"id"`
+	}
+	tests := []struct {
+		name       string
+		items      []item
+		nextCursor string
+		wantBody   string
+	}{
+		{
+			name:     "empty",
+			wantBody: `{"data":[]}`,
+		},
+		{
+			name:       "empty with cursor",
+			nextCursor: "c1",
+			wantBody:   `{"data":[],"next_cursor":"c1"}`,
+		},
+		{
+			name:     "items",
+			items:    []item{{1}, {2}},
+			wantBody: `{"data":[{"id":1},{"id":2}]}`,
+		},
+		{
+			name:       "items with cursor",
+			items:      []item{{1}},
+			nextCursor: `c"2`,
+			wantBody:   `{"data":[{"id":1}],"next_cursor":"c\"2"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(rr)
+
+			list := newJSONList(c)
+			for _, it := range tt.items {
+				require.NoError(t, list.Add(it))
+			}
+			assert.Equal(t, len(tt.items), list.Len())
+			require.NoError(t, list.Close(tt.nextCursor))
+
+			assert.Equal(t, http.StatusOK, rr.Code)
+			assert.Equal(t, "application/json; charset=utf-8", rr.Header().Get("Content-Type"))
+			assert.True(t, json.Valid(rr.Body.Bytes()), rr.Body.String())
+			assert.JSONEq(t, tt.wantBody, rr.Body.String())
+		})
+	}
+}
+
+// discardWriter - a response nobody reads, so the benchmark measures the
+// listing and not the recorder
+type discardWriter struct{ header http.Header }
+
+func (w discardWriter) Header() http.Header       { return w.header }
+func (discardWriter) Write(b []byte) (int, error) { return len(b), nil }
+func (discardWriter) WriteHeader(int)             {}
+
+// BenchmarkJSONList_AdminUsers - the mapping and encoding of a streamed
+// GET /users page(default size) as GetUsersHandler does it
+func BenchmarkJSONList_AdminUsers(b *testing.B) {
+	gin.SetMode(gin.TestMode)
+	c, _ := gin.CreateTestContext(discardWriter{header: http.Header{}})
+
+	ts := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	us := make([]domain.User, 50)
+	for i := range us {
+		us[i] = domain.User{
+			UUID:      uuid.New(),
+			Email:     fmt.Sprintf("user%d@example.com", i),
+			Role:      "user",
+			Name:      "John",
+			Lastname:  "Doe",
+			BirthDate: time.Date(1990, 1, 2, 0, 0, 0, 0, time.UTC),
+			Phone:     "+33612345678",
+			CreatedAt: ts,
+			UpdatedAt: ts,
+			Files:     &domain.FilesSummary{Count: 2, TotalBytes: 1024},
+		}
+	}
+
+	b.ReportAllocs()
+	for b.Loop() {
+		list := newJSONList(c)
+		var dto user.AdminUser
+		for i := range us {
+			dto = user.ToAdminUser(us[i])
+			if err := list.Add(&dto); err != nil {
+				b.Fatal(err)
+			}
+		}
+		if err := list.Close("cursor"); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
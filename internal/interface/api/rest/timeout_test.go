@@ -0,0 +1,74 @@
+package rest
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"user-manager-api/internal/interface/api/rest/middleware"
+)
+
+func TestRequestTimeoutMiddleware_Table(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mCounter := prometheus.NewCounterVec(prometheus.CounterOpts{Name: "test"}, []string{"result"})
+	routes := map[string]time.Duration{
+		http.MethodGet + " " + RouteUsers: 10 * time.Millisecond,
+		http.MethodGet + " " + RouteUser:  0,
+	}
+	r := gin.New()
+	r.Use(middleware.RequestID())
+	r.Use(middleware.RequestTimeout(time.Second, time.Second, routes, zap.NewNop(), mCounter))
+	// a handler waiting on a slow backend, it answers when its context is canceled
+	slow := func(c *gin.Context) {
+		c.Header("Content-Disposition", "attachment")
+		select {
+		case <-c.Request.Context().Done():
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get users"})
+		case <-time.After(100 * time.Millisecond):
+			c.JSON(http.StatusOK, gin.H{})
+		}
+	}
+	r.GET(RouteUsers, slow)
+	r.GET(RouteUser, slow)
+	r.POST(RouteUsers, slow)
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		wantStatus int
+	}{
+		{"route budget exceeded", http.MethodGet, RouteUsers, http.StatusGatewayTimeout},
+		{"route budget disabled", http.MethodGet, RouteUsers + "/1", http.StatusOK},
+		{"default budget", http.MethodPost, RouteUsers, http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := doReq(t, r, tt.method, tt.path, nil, nil)
+			require.Equal(t, tt.wantStatus, w.Code, w.Body.String())
+			assert.NotEmpty(t, w.Header().Get(middleware.HeaderRequestID), "the headers of the middlewares before are kept")
+			if tt.wantStatus != http.StatusGatewayTimeout {
+				return
+			}
+
+			assert.Empty(t, w.Header().Get("Content-Disposition"), "the headers of the handler are discarded")
+			var body map[string]any
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+			assert.Equal(t, map[string]any{
+				"error":   "the request took too long, try again later",
+				"code":    middleware.CodeTimeout,
+				"timeout": "10ms",
+			}, body)
+		})
+	}
+	assert.Equal(t, float64(1), testutil.ToFloat64(mCounter.WithLabelValues("http_request_timeouts_total")))
+}
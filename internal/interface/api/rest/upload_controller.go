@@ -0,0 +1,103 @@
+package rest
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"user-manager-api/internal/application/ports"
+	"user-manager-api/internal/interface/api/rest/dto/upload"
+	"user-manager-api/internal/interface/api/rest/middleware"
+	"user-manager-api/internal/interface/api/rest/validator"
+	"user-manager-api/pkg/progress"
+)
+
+const (
+	// UploadProgressTTL - how long the outcome of a finished upload is kept
+	UploadProgressTTL = 5 * time.Minute
+	// uploadProgressInterval - the period of the progress events of the stream
+	uploadProgressInterval = 500 * time.Millisecond
+)
+
+var errUploadFailed = errors.New("upload failed")
+
+// UploadController - the progress of the file uploads: the client picks an
+// upload_id(UUID) for POST RouteUserFiles and polls or streams it meanwhile. The
+// tracker is per instance, the polls must reach the instance of the upload.
+type UploadController struct {
+	uploads *progress.Tracker
+	logger  *zap.Logger
+}
+
+func NewUploadController(
+	r *gin.Engine,
+	uploads *progress.Tracker,
+	logger *zap.Logger,
+	tokenService ports.TokenService,
+) *UploadController {
+	uc := &UploadController{
+		uploads: uploads,
+		logger:  logger,
+	}
+
+	r.GET(RouteUploadProgress, middleware.AuthMiddleware(tokenService), uc.GetProgressHandler)
+
+	return uc
+}
+
+// GetProgressHandler - the progress as JSON, or as "progress" server-sent events
+// until the upload is finished for "Accept: text/event-stream". The uploads of
+// the other callers are not found.
+func (uc *UploadController) GetProgressHandler(c *gin.Context) {
+	id := c.Param("upload_id")
+	if ok, _ := validator.IsUUID(id); !ok {
+		c.JSON(
+			http.StatusBadRequest,
+			gin.H{"error": "upload_id must be a valid UUID"},
+		)
+		return
+	}
+
+	s, ok := uc.uploads.Get(id)
+	if !ok || s.Owner != uploadOwner(c) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "upload not found"})
+		return
+	}
+	if !strings.Contains(c.GetHeader("Accept"), "text/event-stream") {
+		c.JSON(http.StatusOK, upload.ToResponseProgress(s))
+		return
+	}
+
+	c.Header("Cache-Control", "no-cache")
+	ticker := time.NewTicker(uploadProgressInterval)
+	defer ticker.Stop()
+	for {
+		c.SSEvent("progress", upload.ToResponseProgress(s))
+		c.Writer.Flush()
+		if s.Finished() {
+			return
+		}
+
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case <-ticker.C:
+		}
+		if s, ok = uc.uploads.Get(id); !ok {
+			return
+		}
+	}
+}
+
+// uploadOwner - the caller the progress of an upload is shown to
+func uploadOwner(c *gin.Context) string {
+	if id := c.GetString(middleware.CtxUserID); id != "" {
+		return id
+	}
+
+	return c.GetString(middleware.CtxClientID)
+}
@@ -0,0 +1,160 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	domainUser "user-manager-api/internal/domain/user"
+	domainFile "user-manager-api/internal/domain/user_file"
+	jwtSvc "user-manager-api/internal/infrastructure/jwt"
+	"user-manager-api/internal/interface/api/rest/dto/upload"
+	"user-manager-api/pkg/progress"
+)
+
+func setupUploadRouter(t *testing.T, ufs *FakeUserFileService) (*gin.Engine, *progress.Tracker, func(userID string) map[string]string) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	j := jwtSvc.New("test-secret")
+	uploads := progress.New(time.Minute)
+	NewUserFileController(r, ufs, zap.NewNop(), j, 10<<20, uploads)
+	NewUploadController(r, uploads, zap.NewNop(), j)
+
+	auth := func(userID string) map[string]string {
+		tok, err := j.GenerateToken(userID, domainUser.RoleAdmin, time.Minute)
+		require.NoError(t, err)
+		return map[string]string{"Authorization": "Bearer " + tok}
+	}
+
+	return r, uploads, auth
+}
+
+func TestUploadController_Upload(t *testing.T) {
+	owner := uuid.New().String()
+	uploadID := uuid.New().String()
+	filesPath := "/api/v1/users/" + uuid.New().String() + "/files?upload_id="
+
+	var tracker *progress.Tracker
+	var during progress.Snapshot
+	r, uploads, auth := setupUploadRouter(t, &FakeUserFileService{
+		CreateUserFileFunc: func(ctx context.Context, userUUID domainUser.UUID, fh *multipart.FileHeader, tags []string, folder string) (*domainFile.UserFile, error) {
+			during, _ = tracker.Get(uploadID)
+			return &domainFile.UserFile{}, nil
+		},
+	})
+	tracker = uploads
+
+	rr := doMultipartReq(t, r, http.MethodPost, filesPath+uploadID, nil, "file", "doc.pdf", []byte("%PDF..."), auth(owner))
+	require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+	assert.Equal(t, progress.StatusProcessing, during.Status)
+	assert.Positive(t, during.Total)
+	assert.Equal(t, during.Total, during.Received, "the body is received before it is stored")
+
+	rr = doFileReq(t, r, http.MethodGet, "/api/v1/uploads/"+uploadID+"/progress", nil, auth(owner))
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	var got upload.Progress
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &got))
+	assert.Equal(t, uploadID, got.UploadID)
+	assert.Equal(t, progress.StatusDone, got.Status)
+	assert.Equal(t, during.Total, got.Received)
+
+	// a failed upload of the same id
+	rr = doMultipartReq(t, r, http.MethodPost, filesPath+uploadID, nil, "", "", nil, auth(owner))
+	require.Equal(t, http.StatusBadRequest, rr.Code)
+	s, _ := uploads.Get(uploadID)
+	assert.Equal(t, progress.StatusFailed, s.Status)
+
+	rr = doMultipartReq(t, r, http.MethodPost, filesPath+uploadID, nil, "file", "doc.pdf", []byte("%PDF..."), auth(uuid.New().String()))
+	require.Equal(t, http.StatusConflict, rr.Code, "the id of another caller")
+	rr = doMultipartReq(t, r, http.MethodPost, filesPath+"42", nil, "file", "doc.pdf", []byte("%PDF..."), auth(owner))
+	require.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+func TestUploadController_GetProgressHandler(t *testing.T) {
+	owner := uuid.New().String()
+	done := uuid.New().String()
+	inFlight := uuid.New().String()
+
+	tests := []struct {
+		name       string
+		uploadID   string
+		userID     string
+		accept     string
+		wantStatus int
+		check      func(t *testing.T, body string)
+	}{
+		{
+			name:       "200 json",
+			uploadID:   inFlight,
+			userID:     owner,
+			wantStatus: http.StatusOK,
+			check: func(t *testing.T, body string) {
+				var got upload.Progress
+				require.NoError(t, json.Unmarshal([]byte(body), &got))
+				assert.Equal(t, upload.Progress{UploadID: inFlight, Total: 100, Status: progress.StatusReceiving, UpdatedAt: got.UpdatedAt}, got)
+			},
+		},
+		{
+			name:       "200 events of a finished upload",
+			uploadID:   done,
+			userID:     owner,
+			accept:     "text/event-stream",
+			wantStatus: http.StatusOK,
+			check: func(t *testing.T, body string) {
+				assert.Equal(t, 1, strings.Count(body, "event:progress\n"))
+				assert.Contains(t, body, `"status":"done"`)
+			},
+		},
+		{
+			name:       "400 upload_id",
+			uploadID:   "42",
+			userID:     owner,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "404 unknown",
+			uploadID:   uuid.New().String(),
+			userID:     owner,
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:       "404 another caller",
+			uploadID:   inFlight,
+			userID:     uuid.New().String(),
+			wantStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, uploads, auth := setupUploadRouter(t, &FakeUserFileService{})
+			_, err := uploads.Start(inFlight, owner, 100)
+			require.NoError(t, err)
+			up, err := uploads.Start(done, owner, 0)
+			require.NoError(t, err)
+			up.Finish(nil)
+
+			headers := auth(tt.userID)
+			if tt.accept != "" {
+				headers["Accept"] = tt.accept
+			}
+			rr := doFileReq(t, r, http.MethodGet, "/api/v1/uploads/"+tt.uploadID+"/progress", nil, headers)
+			require.Equal(t, tt.wantStatus, rr.Code, rr.Body.String())
+			if tt.check != nil {
+				tt.check(t, rr.Body.String())
+			}
+		})
+	}
+}
@@ -3,68 +3,124 @@ package rest
 import (
 	"errors"
 	"net/http"
-	"user-manager-api/internal/infrastructure/jwt"
+	"slices"
+	"strconv"
 	"user-manager-api/internal/interface/api/rest/middleware"
 
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
 
 	"user-manager-api/internal/application/ports"
-	userDB "user-manager-api/internal/infrastructure/db/postgres/user"
+	"user-manager-api/internal/application/services"
+	"user-manager-api/internal/domain/deletion"
+	"user-manager-api/internal/domain/pagination"
+	domain "user-manager-api/internal/domain/user"
 	"user-manager-api/internal/interface/api/rest/dto/user"
 	"user-manager-api/internal/interface/api/rest/validator"
 )
 
+// "code" values of the 409 answers to DELETE user: lock-out guards
+const (
+	codeSelfDeleteUnconfirmed = "self_delete_unconfirmed"
+	codeLastAdmin             = "last_admin"
+	// codeLegalHold - the user and their files are kept until the hold is released
+	codeLegalHold = "legal_hold"
+	// codeDeletionInProgress - another request is deleting the user
+	codeDeletionInProgress = "deletion_in_progress"
+	// codeUpgradeRequired - the organization needs a plan with more seats
+	codeUpgradeRequired = "upgrade_required"
+)
+
+// "code" values shared by the routes of a user, the other errors get the
+// generic one of their status(see middleware.ErrorCodes)
+const (
+	codeUserNotFound = "user_not_found"
+	codeEmailTaken   = "email_taken"
+)
+
+// GET user "format" query param values
+const (
+	formatJSON  = "json"
+	formatVCard = "vcard"
+	formatPDF   = "pdf"
+)
+
 type UserController struct {
-	userService ports.UserService
-	logger      *zap.Logger
+	userCommands ports.UserCommands
+	userQueries  ports.UserQueries
+	logger       *zap.Logger
 }
 
 func NewUserController(
 	r *gin.Engine,
-	userService ports.UserService,
+	userCommands ports.UserCommands,
+	userQueries ports.UserQueries,
 	logger *zap.Logger,
-	jwtService *jwt.Service,
+	tokenService ports.TokenService,
 ) *UserController {
 	uc := &UserController{
-		userService: userService,
-		logger:      logger,
+		userCommands: userCommands,
+		userQueries:  userQueries,
+		logger:       logger,
 	}
 
-	r.GET(RouteUsers, uc.GetUsersHandler)
-	r.GET(RouteUser, uc.GetUserHandler)
-	r.POST(RouteUsers, middleware.AuthMiddleware(jwtService), uc.CreateUserHandler)
-	r.PUT(RouteUser, middleware.AuthMiddleware(jwtService), uc.UpdateUserHandler)
-	r.DELETE(RouteUser, middleware.AuthMiddleware(jwtService), uc.DeleteUserHandler)
+	// the directory(emails, phones, birth dates) is for admins only
+	r.GET(RouteUsers, middleware.AuthMiddleware(tokenService), middleware.RequireAdmin(), uc.GetUsersHandler)
+	r.GET(RouteUser, middleware.OptionalAuthMiddleware(tokenService), uc.GetUserHandler)
+	r.GET(RouteMe, middleware.AuthMiddleware(tokenService), middleware.SelfParam("user_id"), uc.GetUserHandler)
+	r.PUT(RouteMe, middleware.AuthMiddleware(tokenService), middleware.SelfParam("user_id"), uc.UpdateUserHandler)
+	r.POST(RouteUsers, middleware.AuthMiddleware(tokenService), uc.CreateUserHandler)
+	r.POST(RouteUsersValidate, middleware.AuthMiddleware(tokenService), uc.ValidateUserHandler)
+	r.PUT(RouteUser, middleware.AuthMiddleware(tokenService), middleware.SelfOrAdmin("user_id"), uc.UpdateUserHandler)
+	r.DELETE(RouteUser, middleware.AuthMiddleware(tokenService), middleware.SelfOrAdmin("user_id"), uc.DeleteUserHandler)
+	r.GET(RouteAdminDeletedUsers, middleware.AuthMiddleware(tokenService), middleware.RequireAdmin(), uc.GetDeletedUsersHandler)
 
 	return uc
 }
 
 func (uc *UserController) GetUsersHandler(c *gin.Context) {
-	page, err := validator.ValidatePage(c.Query("page"))
-	if err != nil {
-		c.JSON(
-			http.StatusBadRequest,
-			gin.H{"error": err.Error()},
-		)
+	p, errs := validator.ParsePagination(c.Request.URL.Query(), validator.UserSortFields)
+	if errs != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "invalid pagination params",
+			"details": errs,
+		})
 		return
 	}
 
-	users, err := uc.userService.FindUsers(c.Request.Context(), page)
+	list := newJSONList(c)
+	var (
+		last pagination.Cursor
+		// one DTO for all the rows: passed by pointer, it is not copied to the heap per row
+		dto user.AdminUser
+	)
+	err := uc.userQueries.StreamUsers(c.Request.Context(), p, func(u *domain.User) error {
+		last = pagination.Cursor{CreatedAt: u.CreatedAt, UUID: u.UUID}
+		dto = user.ToAdminUser(*u)
+		return list.Add(&dto)
+	})
 	if err != nil {
-		c.JSON(
-			http.StatusInternalServerError,
-			gin.H{"error": "failed to get users"},
-		)
-		uc.logger.Error("FindUsers() error", zap.Error(err))
+		if list.Len() == 0 {
+			c.JSON(
+				http.StatusInternalServerError,
+				gin.H{"error": "failed to get users"},
+			)
+		}
+		uc.logger.Error("StreamUsers() error", zap.Error(err), zap.Int("written", list.Len()))
 		return
 	}
 
-	c.JSON(http.StatusOK, user.ResponseData{
-		Data: user.ToResponseUsers(users),
-	})
+	var nextCursor string
+	// keyset pagination is available for the default(created_at) order only
+	if list.Len() > 0 && p.Sort == "" {
+		nextCursor = validator.EncodeCursor(last)
+	}
+	if err = list.Close(nextCursor); err != nil {
+		uc.logger.Warn("users response write error", zap.Error(err))
+	}
 }
 
+// GetUserHandler - "?format=vcard|pdf" exports the profile(the user itself and admins only).
 func (uc *UserController) GetUserHandler(c *gin.Context) {
 	ok, uuid := validator.IsUUID(c.Param("user_id"))
 	if !ok {
@@ -74,9 +130,33 @@ func (uc *UserController) GetUserHandler(c *gin.Context) {
 		)
 		return
 	}
+	format := c.DefaultQuery("format", formatJSON)
+	switch format {
+	case formatJSON:
+	case formatVCard, formatPDF:
+		// checked before the lookup to not disclose which users exist
+		if c.GetString(middleware.CtxUserID) == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "missing Authorization header"})
+			return
+		}
+		if c.GetString(middleware.CtxUserRole) != domain.RoleAdmin && c.GetString(middleware.CtxUserID) != uuid.String() {
+			c.JSON(http.StatusForbidden, gin.H{"error": "export is available to the user itself and admins"})
+			return
+		}
+	default:
+		c.JSON(
+			http.StatusBadRequest,
+			gin.H{"error": "format must be json, vcard or pdf"},
+		)
+		return
+	}
 
-	u, err := uc.userService.FindUserByID(c.Request.Context(), uuid)
+	u, err := uc.userQueries.FindUserByID(c.Request.Context(), uuid)
 	if err != nil {
+		if errors.Is(err, services.ErrUserNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error(), "code": codeUserNotFound})
+			return
+		}
 		c.JSON(
 			http.StatusInternalServerError,
 			gin.H{"error": "failed to get a user"},
@@ -85,49 +165,47 @@ func (uc *UserController) GetUserHandler(c *gin.Context) {
 		return
 	}
 
-	if u == nil {
-		c.JSON(
-			http.StatusNotFound,
-			gin.H{"error": "user not found"},
-		)
-		return
+	switch format {
+	case formatVCard:
+		c.Header("Content-Disposition", `attachment; filename="`+u.UUID.String()+`.vcf"`)
+		c.Data(http.StatusOK, user.ContentTypeVCard, user.ToVCard(*u))
+	case formatPDF:
+		doc, err := user.ToPDF(*u)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to render the profile"})
+			uc.logger.Error("ToPDF() error", zap.Error(err))
+			return
+		}
+		c.Header("Content-Disposition", `attachment; filename="`+u.UUID.String()+`.pdf"`)
+		c.Data(http.StatusOK, user.ContentTypePDF, doc)
+	default:
+		c.JSON(http.StatusOK, toUserResponse(c, *u))
 	}
-
-	c.JSON(http.StatusOK, user.ToResponseUser(*u))
 }
 
 func (uc *UserController) CreateUserHandler(c *gin.Context) {
-	var req user.Request
-	// for a good boost of performance(x3 minimum) and to avoid reflection under the hood
-	// better to use codegen for marshal/unmarshal for example:
-	// https://github.com/mailru/easyjson
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "invalid request body",
-			"details": err.Error(),
-		})
-		return
-	}
-	if errs := validator.ValidateUser(req); errs != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "invalid request body",
-			"details": errs,
-		})
+	req, ok := BindAndValidate(c, validator.ValidateUser)
+	if !ok {
 		return
 	}
 
 	uDomain, err := user.ToDomainUser(req)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "invalid request body",
-			"details": err.Error(),
-		})
+		abortInvalidBody(c, err.Error())
+		return
 	}
 
-	u, err := uc.userService.CreateUser(c.Request.Context(), uDomain)
+	u, err := uc.userCommands.CreateUser(c.Request.Context(), uDomain)
 	if err != nil {
-		if errors.Is(err, userDB.ErrEmailAlreadyExists) {
-			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		if abortUnderage(c, err) {
+			return
+		}
+		if errors.Is(err, domain.ErrEmailAlreadyExists) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error(), "code": codeEmailTaken})
+			return
+		}
+		if errors.Is(err, services.ErrSeatLimitReached) {
+			c.JSON(http.StatusPaymentRequired, gin.H{"error": err.Error(), "code": codeUpgradeRequired})
 			return
 		}
 		c.JSON(
@@ -138,9 +216,65 @@ func (uc *UserController) CreateUserHandler(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusCreated, user.ToResponseUser(*u))
+	c.JSON(http.StatusCreated, toUserResponse(c, *u))
+}
+
+// ValidateUserHandler - the dry run of CreateUserHandler: 200 with the field errors,
+// the uniqueness pre-checks included, nothing is stored. "?fields=email,name"
+// limits it to the fields of a step of a multi-step form.
+func (uc *UserController) ValidateUserHandler(c *gin.Context) {
+	fields, err := validator.ParseUserFields(c.Query("fields"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "invalid validation params",
+			"details": map[string]string{"fields": err.Error()},
+		})
+		return
+	}
+	req, ok := BindAndValidate[user.Request](c, nil)
+	if !ok {
+		return
+	}
+
+	errs := validator.ValidateUser(req)
+	for f := range errs {
+		if fields != nil && !slices.Contains(fields, f) {
+			delete(errs, f)
+		}
+	}
+	if _, invalid := errs["birth_date"]; !invalid && (fields == nil || slices.Contains(fields, "birth_date")) {
+		// birth_date is valid, so is the mapping
+		uDomain, _ := user.ToDomainUser(req)
+		if err = uc.userCommands.CheckAge(uDomain); err != nil {
+			if errs == nil {
+				errs = make(map[string]string)
+			}
+			errs["birth_date"] = err.Error()
+		}
+	}
+	if _, invalid := errs["email"]; !invalid && (fields == nil || slices.Contains(fields, "email")) {
+		err = uc.userCommands.CheckEmailAvailable(c.Request.Context(), req.Email)
+		switch {
+		case errors.Is(err, domain.ErrEmailAlreadyExists), errors.Is(err, services.ErrSeatLimitReached):
+			if errs == nil {
+				errs = make(map[string]string)
+			}
+			errs["email"] = err.Error()
+		case err != nil:
+			c.JSON(
+				http.StatusInternalServerError,
+				gin.H{"error": "failed to validate a user"},
+			)
+			uc.logger.Error("CheckEmailAvailable() error", zap.Error(err))
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, user.ValidationResponse{Valid: len(errs) == 0, Errors: errs})
 }
 
+// UpdateUserHandler - of the user itself or by an admin: the email change would
+// let anyone else take the account over
 func (uc *UserController) UpdateUserHandler(c *gin.Context) {
 	ok, uuid := validator.IsUUID(c.Param("user_id"))
 	if !ok {
@@ -150,34 +284,35 @@ func (uc *UserController) UpdateUserHandler(c *gin.Context) {
 		)
 		return
 	}
-
-	var req user.Request
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "invalid request body",
-			"details": err.Error(),
-		})
-		return
-	}
-	if errs := validator.ValidateUser(req); errs != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "invalid request body",
-			"details": errs,
-		})
+	req, ok := BindAndValidate(c, validator.ValidateUser)
+	if !ok {
 		return
 	}
 
 	uDomain, err := user.ToDomainUser(req)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "invalid request body",
-			"details": err.Error(),
-		})
+		abortInvalidBody(c, err.Error())
+		return
 	}
 	uDomain.UUID = uuid
 
-	u, err := uc.userService.UpdateUser(c.Request.Context(), uDomain)
+	u, err := uc.userCommands.UpdateUser(c.Request.Context(), uDomain)
 	if err != nil {
+		if abortUnderage(c, err) {
+			return
+		}
+		if errors.Is(err, domain.ErrEmailAlreadyExists) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error(), "code": codeEmailTaken})
+			return
+		}
+		if errors.Is(err, services.ErrSeatLimitReached) {
+			c.JSON(http.StatusPaymentRequired, gin.H{"error": err.Error(), "code": codeUpgradeRequired})
+			return
+		}
+		if errors.Is(err, services.ErrUserNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error(), "code": codeUserNotFound})
+			return
+		}
 		c.JSON(
 			http.StatusInternalServerError,
 			gin.H{"error": "failed to update a user"},
@@ -186,17 +321,13 @@ func (uc *UserController) UpdateUserHandler(c *gin.Context) {
 		return
 	}
 
-	if u == nil {
-		c.JSON(
-			http.StatusNotFound,
-			gin.H{"error": "user not found"},
-		)
-		return
-	}
-
-	c.JSON(http.StatusOK, user.ToResponseUser(*u))
+	c.JSON(http.StatusOK, toUserResponse(c, *u))
 }
 
+// DeleteUserHandler - "?reason=" defaults to user_request for the own account
+// and to admin_action for an admin deleting another one. Only admins set other
+// reasons than user_request. The own account needs "?confirm_self=true", the
+// last admin is never deleted(409 with a "code").
 func (uc *UserController) DeleteUserHandler(c *gin.Context) {
 	ok, uuid := validator.IsUUID(c.Param("user_id"))
 	if !ok {
@@ -206,9 +337,63 @@ func (uc *UserController) DeleteUserHandler(c *gin.Context) {
 		)
 		return
 	}
-
-	err := uc.userService.DeleteUser(c.Request.Context(), uuid)
+	reason, err := validator.ParseDeleteReason(c.Query("reason"))
+	if err != nil {
+		c.JSON(
+			http.StatusBadRequest,
+			gin.H{"error": err.Error()},
+		)
+		return
+	}
+	confirmSelf, err := strconv.ParseBool(c.DefaultQuery("confirm_self", "false"))
 	if err != nil {
+		c.JSON(
+			http.StatusBadRequest,
+			gin.H{"error": "confirm_self must be true or false"},
+		)
+		return
+	}
+	ok, actor := validator.IsUUID(c.GetString(middleware.CtxUserID))
+	if !ok {
+		c.JSON(
+			http.StatusUnauthorized,
+			gin.H{"error": "invalid token"},
+		)
+		return
+	}
+
+	isAdmin := c.GetString(middleware.CtxUserRole) == domain.RoleAdmin
+	switch {
+	case reason == "" && isAdmin && actor != uuid:
+		reason = domain.DeletionAdminAction
+	case reason == "":
+		reason = domain.DeletionUserRequest
+	case reason != domain.DeletionUserRequest && !isAdmin:
+		c.JSON(
+			http.StatusForbidden,
+			gin.H{"error": "only admins can set the deletion reason"},
+		)
+		return
+	}
+
+	err = uc.userCommands.DeleteUser(c.Request.Context(), actor, uuid, reason, confirmSelf)
+	switch {
+	case errors.Is(err, services.ErrSelfDeleteUnconfirmed):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error(), "code": codeSelfDeleteUnconfirmed})
+		return
+	case errors.Is(err, domain.ErrLastAdmin):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error(), "code": codeLastAdmin})
+		return
+	case errors.Is(err, domain.ErrLegalHold):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error(), "code": codeLegalHold})
+		return
+	case errors.Is(err, deletion.ErrInProgress):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error(), "code": codeDeletionInProgress})
+		return
+	case errors.Is(err, services.ErrUserNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error(), "code": codeUserNotFound})
+		return
+	case err != nil:
 		c.JSON(
 			http.StatusInternalServerError,
 			gin.H{"error": "failed to delete user"},
@@ -219,3 +404,57 @@ func (uc *UserController) DeleteUserHandler(c *gin.Context) {
 
 	c.Status(http.StatusNoContent)
 }
+
+// GetDeletedUsersHandler - "?reason=" filters by the deletion reason.
+func (uc *UserController) GetDeletedUsersHandler(c *gin.Context) {
+	p, errs := validator.ParsePagination(c.Request.URL.Query(), validator.UserSortFields)
+	if errs != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "invalid pagination params",
+			"details": errs,
+		})
+		return
+	}
+	reason, err := validator.ParseDeletionReason(c.Query("reason"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "invalid filter params",
+			"details": map[string]string{"reason": err.Error()},
+		})
+		return
+	}
+
+	users, err := uc.userQueries.FindDeletedUsers(c.Request.Context(), reason, p)
+	if err != nil {
+		c.JSON(
+			http.StatusInternalServerError,
+			gin.H{"error": "failed to get deleted users"},
+		)
+		uc.logger.Error("FindDeletedUsers() error", zap.Error(err))
+		return
+	}
+
+	resp := user.ResponseData{
+		Data: user.ToAdminUsers(users),
+	}
+	// keyset pagination is available for the default(created_at) order only
+	if len(users) > 0 && p.Sort == "" {
+		last := users[len(users)-1]
+		resp.NextCursor = validator.EncodeCursor(pagination.Cursor{CreatedAt: last.CreatedAt, UUID: last.UUID})
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// toUserResponse picks the DTO by the caller claims: admins get the account
+// metadata, the user itself the full profile, anyone else the public card.
+func toUserResponse(c *gin.Context, u domain.User) any {
+	switch {
+	case c.GetString(middleware.CtxUserRole) == domain.RoleAdmin:
+		return user.ToAdminUser(u)
+	case c.GetString(middleware.CtxUserID) == u.UUID.String():
+		return user.ToResponseUser(u)
+	}
+
+	return user.ToPublicUser(u)
+}
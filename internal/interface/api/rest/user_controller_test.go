@@ -7,6 +7,7 @@ import (
 	"errors"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
@@ -18,20 +19,35 @@ import (
 	"go.uber.org/zap"
 
 	"user-manager-api/internal/application/ports"
+	"user-manager-api/internal/application/services"
+	"user-manager-api/internal/domain/deletion"
+	"user-manager-api/internal/domain/pagination"
 	domain "user-manager-api/internal/domain/user"
-	userDB "user-manager-api/internal/infrastructure/db/postgres/user"
 	jwtSvc "user-manager-api/internal/infrastructure/jwt"
 	"user-manager-api/internal/interface/api/rest/dto/user"
 	"user-manager-api/internal/interface/api/rest/middleware"
+	"user-manager-api/internal/interface/api/rest/validator"
 )
 
+// userService - both sides of the user service, FakeUserService fakes both
+type userService interface {
+	ports.UserCommands
+	ports.UserQueries
+}
+
 type FakeUserService struct {
 	FindUserByIDFunc func(ctx context.Context, id domain.UUID) (*domain.User, error)
 	FindByEmailFunc  func(ctx context.Context, email string) (*domain.User, error)
-	FindUsersFunc    func(ctx context.Context, page int) (domain.Users, error)
+	StreamUsersFunc  func(ctx context.Context, p pagination.Params, fn func(u *domain.User) error) error
 	CreateUserFunc   func(ctx context.Context, u domain.User) (*domain.User, error)
+	CheckEmailFunc   func(ctx context.Context, email string) error
+	CheckAgeFunc     func(u domain.User) error
 	UpdateUserFunc   func(ctx context.Context, u domain.User) (*domain.User, error)
-	DeleteUserFunc   func(ctx context.Context, userUUID domain.UUID) error
+	DeleteUserFunc   func(ctx context.Context, actor, userUUID domain.UUID, reason domain.DeletionReason, confirmSelf bool) error
+
+	FindDeletedUsersFunc func(ctx context.Context, reason domain.DeletionReason, p pagination.Params) (domain.Users, error)
+
+	ConfirmEmailChangeFunc func(ctx context.Context, token string) (*domain.User, error)
 }
 
 func (f *FakeUserService) FindUserByID(ctx context.Context, id domain.UUID) (*domain.User, error) {
@@ -46,11 +62,11 @@ func (f *FakeUserService) FindByEmail(ctx context.Context, email string) (*domai
 	}
 	return f.FindByEmailFunc(ctx, email)
 }
-func (f *FakeUserService) FindUsers(ctx context.Context, page int) (domain.Users, error) {
-	if f.FindUsersFunc == nil {
-		return nil, errors.New("not used")
+func (f *FakeUserService) StreamUsers(ctx context.Context, p pagination.Params, fn func(u *domain.User) error) error {
+	if f.StreamUsersFunc == nil {
+		return errors.New("not used")
 	}
-	return f.FindUsersFunc(ctx, page)
+	return f.StreamUsersFunc(ctx, p, fn)
 }
 func (f *FakeUserService) CreateUser(ctx context.Context, u domain.User) (*domain.User, error) {
 	if f.CreateUserFunc == nil {
@@ -58,20 +74,51 @@ func (f *FakeUserService) CreateUser(ctx context.Context, u domain.User) (*domai
 	}
 	return f.CreateUserFunc(ctx, u)
 }
+func (f *FakeUserService) CheckEmailAvailable(ctx context.Context, email string) error {
+	if f.CheckEmailFunc == nil {
+		return errors.New("not used")
+	}
+	return f.CheckEmailFunc(ctx, email)
+}
+
+// CheckAge - nil CheckAgeFunc: the policy passes
+func (f *FakeUserService) CheckAge(u domain.User) error {
+	if f.CheckAgeFunc == nil {
+		return nil
+	}
+	return f.CheckAgeFunc(u)
+}
 func (f *FakeUserService) UpdateUser(ctx context.Context, u domain.User) (*domain.User, error) {
 	if f.UpdateUserFunc == nil {
 		return nil, errors.New("not used")
 	}
 	return f.UpdateUserFunc(ctx, u)
 }
-func (f *FakeUserService) DeleteUser(ctx context.Context, userUUID domain.UUID) error {
+func (f *FakeUserService) DeleteUser(ctx context.Context, actor, userUUID domain.UUID, reason domain.DeletionReason, confirmSelf bool) error {
 	if f.DeleteUserFunc == nil {
 		return errors.New("not used")
 	}
-	return f.DeleteUserFunc(ctx, userUUID)
+	return f.DeleteUserFunc(ctx, actor, userUUID, reason, confirmSelf)
+}
+func (f *FakeUserService) FindDeletedUsers(ctx context.Context, reason domain.DeletionReason, p pagination.Params) (domain.Users, error) {
+	if f.FindDeletedUsersFunc == nil {
+		return nil, errors.New("not used")
+	}
+	return f.FindDeletedUsersFunc(ctx, reason, p)
+}
+
+func (f *FakeUserService) ConfirmEmailChange(ctx context.Context, token string) (*domain.User, error) {
+	if f.ConfirmEmailChangeFunc == nil {
+		return nil, errors.New("not used")
+	}
+	return f.ConfirmEmailChangeFunc(ctx, token)
+}
+
+func (f *FakeUserService) EmitBirthdays(context.Context) (int, error) {
+	return 0, errors.New("not used")
 }
 
-func setupRouter(t *testing.T, us ports.UserService, withJWT bool) (*gin.Engine, *UserController, *jwtSvc.Service, string) {
+func setupRouter(t *testing.T, us userService, withJWT bool) (*gin.Engine, *UserController, *jwtSvc.Service, string) {
 	t.Helper()
 	gin.SetMode(gin.TestMode)
 
@@ -81,16 +128,17 @@ func setupRouter(t *testing.T, us ports.UserService, withJWT bool) (*gin.Engine,
 	j := jwtSvc.New(secret)
 
 	uc := &UserController{
-		userService: us,
-		logger:      logger,
+		userCommands: us,
+		userQueries:  us,
+		logger:       logger,
 	}
 
 	r.GET("/users", uc.GetUsersHandler)
 	r.GET("/users/:user_id", uc.GetUserHandler)
 	if withJWT {
 		r.POST("/users", middleware.AuthMiddleware(j), uc.CreateUserHandler)
-		r.PUT("/users/:user_id", middleware.AuthMiddleware(j), uc.UpdateUserHandler)
-		r.DELETE("/users/:user_id", middleware.AuthMiddleware(j), uc.DeleteUserHandler)
+		r.PUT("/users/:user_id", middleware.AuthMiddleware(j), middleware.SelfOrAdmin("user_id"), uc.UpdateUserHandler)
+		r.DELETE("/users/:user_id", middleware.AuthMiddleware(j), middleware.SelfOrAdmin("user_id"), uc.DeleteUserHandler)
 	} else {
 		r.POST("/users", uc.CreateUserHandler)
 		r.PUT("/users/:user_id", uc.UpdateUserHandler)
@@ -172,17 +220,17 @@ func TestUserController_GetUsersHandler(t *testing.T) {
 	tests := []struct {
 		name       string
 		pageQuery  string
-		mockUS     func() ports.UserService
+		mockUS     func() userService
 		wantStatus int
 		wantErr    string
 	}{
 		{
 			name:      "500 when service fails",
 			pageQuery: "1",
-			mockUS: func() ports.UserService {
+			mockUS: func() userService {
 				return &FakeUserService{
-					FindUsersFunc: func(ctx context.Context, page int) (domain.Users, error) {
-						return nil, errors.New("db error")
+					StreamUsersFunc: func(ctx context.Context, p pagination.Params, fn func(u *domain.User) error) error {
+						return errors.New("db error")
 					},
 				}
 			},
@@ -192,10 +240,39 @@ func TestUserController_GetUsersHandler(t *testing.T) {
 		{
 			name:      "200 success",
 			pageQuery: "2",
-			mockUS: func() ports.UserService {
+			mockUS: func() userService {
+				return &FakeUserService{
+					StreamUsersFunc: func(ctx context.Context, p pagination.Params, fn func(u *domain.User) error) error {
+						return fn(someDomainUser())
+					},
+				}
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "400 non numeric page",
+			pageQuery:  "abc",
+			mockUS:     func() userService { return &FakeUserService{} },
+			wantStatus: http.StatusBadRequest,
+			wantErr:    "invalid pagination params",
+		},
+		{
+			name:       "400 negative page",
+			pageQuery:  "-1",
+			mockUS:     func() userService { return &FakeUserService{} },
+			wantStatus: http.StatusBadRequest,
+			wantErr:    "invalid pagination params",
+		},
+		{
+			name:      "200 params passed to service",
+			pageQuery: "3&per_page=20&sort=-email",
+			mockUS: func() userService {
 				return &FakeUserService{
-					FindUsersFunc: func(ctx context.Context, page int) (domain.Users, error) {
-						return domain.Users{someDomainUser()}, nil
+					StreamUsersFunc: func(ctx context.Context, p pagination.Params, fn func(u *domain.User) error) error {
+						if p != (pagination.Params{Page: 3, PerPage: 20, Sort: "email", Desc: true}) {
+							return errors.New("unexpected params")
+						}
+						return nil
 					},
 				}
 			},
@@ -219,27 +296,74 @@ func TestUserController_GetUsersHandler(t *testing.T) {
 	}
 }
 
+func TestUserController_GetUsersHandler_Stream(t *testing.T) {
+	first, second := someDomainUser(), someDomainUser()
+	second.CreatedAt = time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	t.Run("page with cursor", func(t *testing.T) {
+		us := &FakeUserService{StreamUsersFunc: func(ctx context.Context, p pagination.Params, fn func(u *domain.User) error) error {
+			// the same user is reused for every row
+			var u domain.User
+			for _, row := range []*domain.User{first, second} {
+				u = *row
+				if err := fn(&u); err != nil {
+					return err
+				}
+			}
+			return nil
+		}}
+		r, _, _, _ := setupRouter(t, us, false)
+		rr := doReq(t, r, http.MethodGet, "/users", nil, nil)
+		require.Equal(t, http.StatusOK, rr.Code)
+
+		var resp user.ResponseData
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp), rr.Body.String())
+		require.Len(t, resp.Data, 2)
+		assert.Equal(t, first.UUID, resp.Data[0].UUID)
+		assert.Equal(t, second.UUID, resp.Data[1].UUID)
+
+		cursor, err := validator.DecodeCursor(resp.NextCursor)
+		require.NoError(t, err)
+		assert.Equal(t, second.UUID, cursor.UUID)
+		assert.True(t, second.CreatedAt.Equal(cursor.CreatedAt))
+	})
+
+	t.Run("error after the first row cuts the body", func(t *testing.T) {
+		us := &FakeUserService{StreamUsersFunc: func(ctx context.Context, p pagination.Params, fn func(u *domain.User) error) error {
+			if err := fn(first); err != nil {
+				return err
+			}
+			return errors.New("connection reset")
+		}}
+		r, _, _, _ := setupRouter(t, us, false)
+		rr := doReq(t, r, http.MethodGet, "/users", nil, nil)
+		// the status is already sent
+		require.Equal(t, http.StatusOK, rr.Code)
+		assert.False(t, json.Valid(rr.Body.Bytes()), rr.Body.String())
+	})
+}
+
 func TestUserController_GetUserHandler(t *testing.T) {
 	okID := uuid.New()
 
 	tests := []struct {
 		name       string
 		userID     string
-		mockUS     func() ports.UserService
+		mockUS     func() userService
 		wantStatus int
 		wantErr    string
 	}{
 		{
 			name:       "400 invalid uuid",
 			userID:     "not-a-uuid",
-			mockUS:     func() ports.UserService { return &FakeUserService{} },
+			mockUS:     func() userService { return &FakeUserService{} },
 			wantStatus: http.StatusBadRequest,
 			wantErr:    "user_id must be a valid UUID",
 		},
 		{
 			name:   "500 service error",
 			userID: okID.String(),
-			mockUS: func() ports.UserService {
+			mockUS: func() userService {
 				return &FakeUserService{
 					FindUserByIDFunc: func(ctx context.Context, id domain.UUID) (*domain.User, error) {
 						return nil, errors.New("db error")
@@ -252,10 +376,10 @@ func TestUserController_GetUserHandler(t *testing.T) {
 		{
 			name:   "404 not found",
 			userID: okID.String(),
-			mockUS: func() ports.UserService {
+			mockUS: func() userService {
 				return &FakeUserService{
 					FindUserByIDFunc: func(ctx context.Context, id domain.UUID) (*domain.User, error) {
-						return nil, nil
+						return nil, services.ErrUserNotFound
 					},
 				}
 			},
@@ -265,7 +389,7 @@ func TestUserController_GetUserHandler(t *testing.T) {
 		{
 			name:   "200 success",
 			userID: okID.String(),
-			mockUS: func() ports.UserService {
+			mockUS: func() userService {
 				u := someDomainUser()
 				u.UUID = okID
 				return &FakeUserService{
@@ -302,15 +426,16 @@ func TestUserController_CreateUserHandler(t *testing.T) {
 		name       string
 		headers    map[string]string
 		body       any
-		mockUS     func() ports.UserService
+		mockUS     func() userService
 		wantStatus int
 		wantErr    string
+		wantCode   string
 	}{
 		{
 			name:       "401 missing auth header",
 			headers:    nil,
 			body:       validReq,
-			mockUS:     func() ports.UserService { return &FakeUserService{} },
+			mockUS:     func() userService { return &FakeUserService{} },
 			wantStatus: http.StatusUnauthorized,
 			wantErr:    "missing Authorization header",
 		},
@@ -320,7 +445,7 @@ func TestUserController_CreateUserHandler(t *testing.T) {
 				"Authorization": "Token something",
 			},
 			body:       validReq,
-			mockUS:     func() ports.UserService { return &FakeUserService{} },
+			mockUS:     func() userService { return &FakeUserService{} },
 			wantStatus: http.StatusUnauthorized,
 			wantErr:    "invalid token format",
 		},
@@ -331,7 +456,7 @@ func TestUserController_CreateUserHandler(t *testing.T) {
 				return map[string]string{"Authorization": "Bearer " + tok}
 			}(),
 			body:       validReq,
-			mockUS:     func() ports.UserService { return &FakeUserService{} },
+			mockUS:     func() userService { return &FakeUserService{} },
 			wantStatus: http.StatusUnauthorized,
 			wantErr:    "invalid token",
 		},
@@ -342,7 +467,7 @@ func TestUserController_CreateUserHandler(t *testing.T) {
 				return map[string]string{"Authorization": "Bearer " + tok}
 			}(),
 			body:       "{bad json",
-			mockUS:     func() ports.UserService { return &FakeUserService{} },
+			mockUS:     func() userService { return &FakeUserService{} },
 			wantStatus: http.StatusBadRequest,
 			wantErr:    "invalid request body",
 		},
@@ -359,7 +484,7 @@ func TestUserController_CreateUserHandler(t *testing.T) {
 				BirthDate: "2020-01-01",
 				Phone:     "123",
 			},
-			mockUS:     func() ports.UserService { return &FakeUserService{} },
+			mockUS:     func() userService { return &FakeUserService{} },
 			wantStatus: http.StatusBadRequest,
 			wantErr:    "invalid request body",
 		},
@@ -370,16 +495,51 @@ func TestUserController_CreateUserHandler(t *testing.T) {
 				return map[string]string{"Authorization": "Bearer " + tok}
 			}(),
 			body: validReq,
-			mockUS: func() ports.UserService {
+			mockUS: func() userService {
 				return &FakeUserService{
 					CreateUserFunc: func(ctx context.Context, du domain.User) (*domain.User, error) {
-						return nil, userDB.ErrEmailAlreadyExists
+						return nil, domain.ErrEmailAlreadyExists
 					},
 				}
 			},
 			wantStatus: http.StatusConflict,
 			wantErr:    "",
 		},
+		{
+			name: "400 under the minimum age",
+			headers: func() map[string]string {
+				tok, _ := SignJWT("test-secret", "123", "admin", time.Hour)
+				return map[string]string{"Authorization": "Bearer " + tok}
+			}(),
+			body: validReq,
+			mockUS: func() userService {
+				return &FakeUserService{
+					CreateUserFunc: func(ctx context.Context, du domain.User) (*domain.User, error) {
+						return nil, &domain.UnderageError{MinAge: 16}
+					},
+				}
+			},
+			wantStatus: http.StatusBadRequest,
+			wantErr:    "invalid request body",
+		},
+		{
+			name: "402 seat limit reached",
+			headers: func() map[string]string {
+				tok, _ := SignJWT("test-secret", "123", "admin", time.Hour)
+				return map[string]string{"Authorization": "Bearer " + tok}
+			}(),
+			body: validReq,
+			mockUS: func() userService {
+				return &FakeUserService{
+					CreateUserFunc: func(ctx context.Context, du domain.User) (*domain.User, error) {
+						return nil, services.ErrSeatLimitReached
+					},
+				}
+			},
+			wantStatus: http.StatusPaymentRequired,
+			wantErr:    services.ErrSeatLimitReached.Error(),
+			wantCode:   codeUpgradeRequired,
+		},
 		{
 			name: "500 service error",
 			headers: func() map[string]string {
@@ -387,7 +547,7 @@ func TestUserController_CreateUserHandler(t *testing.T) {
 				return map[string]string{"Authorization": "Bearer " + tok}
 			}(),
 			body: validReq,
-			mockUS: func() ports.UserService {
+			mockUS: func() userService {
 				return &FakeUserService{
 					CreateUserFunc: func(ctx context.Context, du domain.User) (*domain.User, error) {
 						return nil, errors.New("db error")
@@ -403,7 +563,7 @@ func TestUserController_CreateUserHandler(t *testing.T) {
 				return map[string]string{"Authorization": "Bearer " + tok}
 			}(),
 			body: validReq,
-			mockUS: func() ports.UserService {
+			mockUS: func() userService {
 				u := someDomainUser()
 				return &FakeUserService{
 					CreateUserFunc: func(ctx context.Context, du domain.User) (*domain.User, error) {
@@ -427,7 +587,126 @@ func TestUserController_CreateUserHandler(t *testing.T) {
 				var resp map[string]any
 				_ = json.Unmarshal(rr.Body.Bytes(), &resp)
 				assert.Equal(t, tt.wantErr, resp["error"])
+				if tt.wantCode != "" {
+					assert.Equal(t, tt.wantCode, resp["code"])
+				}
+			}
+		})
+	}
+}
+
+func TestUserController_ValidateUserHandler(t *testing.T) {
+	type tc struct {
+		name       string
+		query      string
+		body       any
+		checkEmail func(ctx context.Context, email string) error
+		checkAge   func(u domain.User) error
+		wantStatus int
+		want       user.ValidationResponse
+	}
+
+	taken := func(context.Context, string) error { return domain.ErrEmailAlreadyExists }
+	cases := []tc{
+		{
+			name: "200 valid",
+			body: validUserRequest(),
+			checkEmail: func(_ context.Context, email string) error {
+				if email != validUserRequest().Email {
+					return errors.New("unexpected email")
+				}
+				return nil
+			},
+			wantStatus: http.StatusOK,
+			want:       user.ValidationResponse{Valid: true},
+		},
+		{
+			name:       "200 email taken",
+			body:       validUserRequest(),
+			checkEmail: taken,
+			wantStatus: http.StatusOK,
+			want:       user.ValidationResponse{Errors: map[string]string{"email": domain.ErrEmailAlreadyExists.Error()}},
+		},
+		{
+			name: "200 no seat",
+			body: validUserRequest(),
+			checkEmail: func(context.Context, string) error {
+				return services.ErrSeatLimitReached
+			},
+			wantStatus: http.StatusOK,
+			want:       user.ValidationResponse{Errors: map[string]string{"email": services.ErrSeatLimitReached.Error()}},
+		},
+		{
+			name:       "200 under the minimum age",
+			body:       validUserRequest(),
+			checkEmail: func(context.Context, string) error { return nil },
+			checkAge: func(u domain.User) error {
+				if u.Email != validUserRequest().Email || u.BirthDate.IsZero() {
+					return errors.New("unexpected user")
+				}
+				return &domain.UnderageError{MinAge: 21}
+			},
+			wantStatus: http.StatusOK,
+			want:       user.ValidationResponse{Errors: map[string]string{"birth_date": "user must be 21+ years old"}},
+		},
+		{
+			name:       "200 field errors, no pre-check of a bad email",
+			body:       user.Request{Email: "bad", Name: "J"},
+			wantStatus: http.StatusOK,
+			want: user.ValidationResponse{Errors: map[string]string{
+				"email":      "invalid email format",
+				"name":       "name length must be 2–64 characters",
+				"lastname":   "lastname is required",
+				"birth_date": "birth_date is required",
+				"phone":      "phone is required",
+			}},
+		},
+		{
+			name:       "200 a step: the other fields are not checked",
+			query:      "?fields=name,lastname",
+			body:       user.Request{Name: "John", Lastname: "Doe"},
+			wantStatus: http.StatusOK,
+			want:       user.ValidationResponse{Valid: true},
+		},
+		{
+			name:       "200 a step with the email",
+			query:      "?fields=email",
+			body:       user.Request{Email: "john@example.com"},
+			checkEmail: taken,
+			wantStatus: http.StatusOK,
+			want:       user.ValidationResponse{Errors: map[string]string{"email": domain.ErrEmailAlreadyExists.Error()}},
+		},
+		{
+			name:       "400 unknown field",
+			query:      "?fields=password",
+			body:       validUserRequest(),
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name: "500",
+			body: validUserRequest(),
+			checkEmail: func(context.Context, string) error {
+				return errors.New("db down")
+			},
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			r, uc, _, _ := setupRouter(t, &FakeUserService{CheckEmailFunc: tt.checkEmail, CheckAgeFunc: tt.checkAge}, false)
+			r.POST("/users/validate", uc.ValidateUserHandler)
+
+			rr := doReq(t, r, http.MethodPost, "/users/validate"+tt.query, tt.body, nil)
+			require.Equal(t, tt.wantStatus, rr.Code, rr.Body.String())
+			if tt.wantStatus != http.StatusOK {
+				return
 			}
+
+			var resp user.ValidationResponse
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+			assert.Equal(t, tt.want, resp)
 		})
 	}
 }
@@ -442,20 +721,21 @@ func TestUserController_UpdateUserHandler(t *testing.T) {
 	}
 
 	tests := []struct {
-		name       string
-		userID     string
-		headers    map[string]string
-		body       any
-		mockUS     func() ports.UserService
-		wantStatus int
-		wantErr    string
+		name        string
+		userID      string
+		headers     map[string]string
+		body        any
+		mockUS      func() userService
+		wantStatus  int
+		wantErr     string
+		wantPending string
 	}{
 		{
 			name:       "401 missing header",
 			userID:     id.String(),
 			headers:    nil,
 			body:       validReq,
-			mockUS:     func() ports.UserService { return &FakeUserService{} },
+			mockUS:     func() userService { return &FakeUserService{} },
 			wantStatus: http.StatusUnauthorized,
 			wantErr:    "missing Authorization header",
 		},
@@ -464,16 +744,47 @@ func TestUserController_UpdateUserHandler(t *testing.T) {
 			userID:     "not-uuid",
 			headers:    authHeader(),
 			body:       validReq,
-			mockUS:     func() ports.UserService { return &FakeUserService{} },
+			mockUS:     func() userService { return &FakeUserService{} },
 			wantStatus: http.StatusBadRequest,
 			wantErr:    "user_id must be a valid UUID",
 		},
+		{
+			name:   "403 another user",
+			userID: id.String(),
+			headers: func() map[string]string {
+				tok, _ := SignJWT("test-secret", uuid.NewString(), "worker", time.Hour)
+				return map[string]string{"Authorization": "Bearer " + tok}
+			}(),
+			body:       validReq,
+			mockUS:     func() userService { return &FakeUserService{} },
+			wantStatus: http.StatusForbidden,
+			wantErr:    "only the user itself and admins are allowed",
+		},
+		{
+			name:   "200 the user itself",
+			userID: id.String(),
+			headers: func() map[string]string {
+				tok, _ := SignJWT("test-secret", id.String(), "worker", time.Hour)
+				return map[string]string{"Authorization": "Bearer " + tok}
+			}(),
+			body: validReq,
+			mockUS: func() userService {
+				u := someDomainUser()
+				u.UUID = id
+				return &FakeUserService{
+					UpdateUserFunc: func(ctx context.Context, du domain.User) (*domain.User, error) {
+						return u, nil
+					},
+				}
+			},
+			wantStatus: http.StatusOK,
+		},
 		{
 			name:       "400 invalid JSON",
 			userID:     id.String(),
 			headers:    authHeader(),
 			body:       "{bad json",
-			mockUS:     func() ports.UserService { return &FakeUserService{} },
+			mockUS:     func() userService { return &FakeUserService{} },
 			wantStatus: http.StatusBadRequest,
 			wantErr:    "invalid request body",
 		},
@@ -488,7 +799,7 @@ func TestUserController_UpdateUserHandler(t *testing.T) {
 				BirthDate: "2020-01-01",
 				Phone:     "123",
 			},
-			mockUS:     func() ports.UserService { return &FakeUserService{} },
+			mockUS:     func() userService { return &FakeUserService{} },
 			wantStatus: http.StatusBadRequest,
 			wantErr:    "invalid request body",
 		},
@@ -497,7 +808,7 @@ func TestUserController_UpdateUserHandler(t *testing.T) {
 			userID:  id.String(),
 			headers: authHeader(),
 			body:    validReq,
-			mockUS: func() ports.UserService {
+			mockUS: func() userService {
 				return &FakeUserService{
 					UpdateUserFunc: func(ctx context.Context, du domain.User) (*domain.User, error) {
 						return nil, errors.New("db error")
@@ -508,26 +819,56 @@ func TestUserController_UpdateUserHandler(t *testing.T) {
 			wantErr:    "failed to update a user",
 		},
 		{
-			name:    "404 not found (nil)",
+			name:    "404 not found",
 			userID:  id.String(),
 			headers: authHeader(),
 			body:    validReq,
-			mockUS: func() ports.UserService {
+			mockUS: func() userService {
 				return &FakeUserService{
 					UpdateUserFunc: func(ctx context.Context, du domain.User) (*domain.User, error) {
-						return nil, nil
+						return nil, services.ErrUserNotFound
 					},
 				}
 			},
 			wantStatus: http.StatusNotFound,
 			wantErr:    "user not found",
 		},
+		{
+			name:    "409 new email taken",
+			userID:  id.String(),
+			headers: authHeader(),
+			body:    validReq,
+			mockUS: func() userService {
+				return &FakeUserService{
+					UpdateUserFunc: func(ctx context.Context, du domain.User) (*domain.User, error) {
+						return nil, domain.ErrEmailAlreadyExists
+					},
+				}
+			},
+			wantStatus: http.StatusConflict,
+			wantErr:    domain.ErrEmailAlreadyExists.Error(),
+		},
+		{
+			name:    "402 no seat in the new organization",
+			userID:  id.String(),
+			headers: authHeader(),
+			body:    validReq,
+			mockUS: func() userService {
+				return &FakeUserService{
+					UpdateUserFunc: func(ctx context.Context, du domain.User) (*domain.User, error) {
+						return nil, services.ErrSeatLimitReached
+					},
+				}
+			},
+			wantStatus: http.StatusPaymentRequired,
+			wantErr:    services.ErrSeatLimitReached.Error(),
+		},
 		{
 			name:    "200 success",
 			userID:  id.String(),
 			headers: authHeader(),
 			body:    validReq,
-			mockUS: func() ports.UserService {
+			mockUS: func() userService {
 				u := someDomainUser()
 				u.UUID = id
 				return &FakeUserService{
@@ -540,6 +881,24 @@ func TestUserController_UpdateUserHandler(t *testing.T) {
 			wantStatus: http.StatusOK,
 			wantErr:    "",
 		},
+		{
+			name:    "200 email change pending",
+			userID:  id.String(),
+			headers: authHeader(),
+			body:    validReq,
+			mockUS: func() userService {
+				u := someDomainUser()
+				u.UUID = id
+				u.PendingEmail = "new@example.com"
+				return &FakeUserService{
+					UpdateUserFunc: func(ctx context.Context, du domain.User) (*domain.User, error) {
+						return u, nil
+					},
+				}
+			},
+			wantStatus:  http.StatusOK,
+			wantPending: "new@example.com",
+		},
 	}
 
 	for _, tt := range tests {
@@ -549,54 +908,106 @@ func TestUserController_UpdateUserHandler(t *testing.T) {
 			rr := doReq(t, r, http.MethodPut, "/users/"+tt.userID, tt.body, tt.headers)
 			require.Equal(t, tt.wantStatus, rr.Code)
 
+			var resp map[string]any
+			_ = json.Unmarshal(rr.Body.Bytes(), &resp)
 			if tt.wantErr != "" {
-				var resp map[string]any
-				_ = json.Unmarshal(rr.Body.Bytes(), &resp)
 				assert.Equal(t, tt.wantErr, resp["error"])
 			}
+			if tt.wantPending != "" {
+				assert.Equal(t, tt.wantPending, resp["pending_email"])
+			}
 		})
 	}
 }
 
 func TestUserController_DeleteUserHandler(t *testing.T) {
-	id := uuid.New()
+	id, adminID := uuid.New(), uuid.New()
 
-	authHeader := func() map[string]string {
-		tok, _ := SignJWT("test-secret", "123", "admin", time.Hour)
+	authHeader := func(sub, role string) map[string]string {
+		tok, _ := SignJWT("test-secret", sub, role, time.Hour)
 		return map[string]string{"Authorization": "Bearer " + tok}
 	}
+	deleted := func(wantActor domain.UUID, wantReason domain.DeletionReason, wantConfirmSelf bool) func() userService {
+		return func() userService {
+			return &FakeUserService{
+				DeleteUserFunc: func(ctx context.Context, actor, userUUID domain.UUID, reason domain.DeletionReason, confirmSelf bool) error {
+					if actor != wantActor || userUUID != id || reason != wantReason || confirmSelf != wantConfirmSelf {
+						return errors.New("unexpected args")
+					}
+					return nil
+				},
+			}
+		}
+	}
 
 	tests := []struct {
 		name       string
 		userID     string
+		query      string
 		headers    map[string]string
-		mockUS     func() ports.UserService
+		mockUS     func() userService
 		wantStatus int
 		wantErr    string
+		wantCode   string
 	}{
 		{
 			name:       "401 missing header",
 			userID:     id.String(),
 			headers:    nil,
-			mockUS:     func() ports.UserService { return &FakeUserService{} },
+			mockUS:     func() userService { return &FakeUserService{} },
 			wantStatus: http.StatusUnauthorized,
 			wantErr:    "missing Authorization header",
 		},
 		{
 			name:       "400 invalid uuid",
 			userID:     "not-uuid",
-			headers:    authHeader(),
-			mockUS:     func() ports.UserService { return &FakeUserService{} },
+			headers:    authHeader(adminID.String(), "admin"),
+			mockUS:     func() userService { return &FakeUserService{} },
 			wantStatus: http.StatusBadRequest,
 			wantErr:    "user_id must be a valid UUID",
 		},
+		{
+			name:       "400 unknown reason",
+			userID:     id.String(),
+			query:      "?reason=spam",
+			headers:    authHeader(adminID.String(), "admin"),
+			mockUS:     func() userService { return &FakeUserService{} },
+			wantStatus: http.StatusBadRequest,
+			wantErr:    "reason must be one of: user_request, admin_action, gdpr, fraud",
+		},
+		{
+			name:       "401 token subject is not a uuid",
+			userID:     id.String(),
+			headers:    authHeader("123", "admin"),
+			mockUS:     func() userService { return &FakeUserService{} },
+			wantStatus: http.StatusUnauthorized,
+			wantErr:    "invalid token",
+		},
+		{
+			name:       "403 another user",
+			userID:     id.String(),
+			query:      "?reason=user_request",
+			headers:    authHeader(uuid.NewString(), "worker"),
+			mockUS:     func() userService { return &FakeUserService{} },
+			wantStatus: http.StatusForbidden,
+			wantErr:    "only the user itself and admins are allowed",
+		},
+		{
+			name:       "403 reason set by a worker",
+			userID:     id.String(),
+			query:      "?reason=fraud",
+			headers:    authHeader(id.String(), "worker"),
+			mockUS:     func() userService { return &FakeUserService{} },
+			wantStatus: http.StatusForbidden,
+			wantErr:    "only admins can set the deletion reason",
+		},
 		{
 			name:    "500 service error",
 			userID:  id.String(),
-			headers: authHeader(),
-			mockUS: func() ports.UserService {
+			headers: authHeader(adminID.String(), "admin"),
+			mockUS: func() userService {
 				return &FakeUserService{
-					DeleteUserFunc: func(ctx context.Context, userUUID domain.UUID) error {
+					DeleteUserFunc: func(ctx context.Context, actor, userUUID domain.UUID, reason domain.DeletionReason, confirmSelf bool) error {
 						return errors.New("db error")
 					},
 				}
@@ -605,16 +1016,95 @@ func TestUserController_DeleteUserHandler(t *testing.T) {
 			wantErr:    "failed to delete user",
 		},
 		{
-			name:    "204 success",
+			name:       "204 admin default reason",
+			userID:     id.String(),
+			headers:    authHeader(adminID.String(), "admin"),
+			mockUS:     deleted(adminID, domain.DeletionAdminAction, false),
+			wantStatus: http.StatusNoContent,
+		},
+		{
+			name:       "204 admin sets the reason",
+			userID:     id.String(),
+			query:      "?reason=gdpr",
+			headers:    authHeader(adminID.String(), "admin"),
+			mockUS:     deleted(adminID, domain.DeletionGDPR, false),
+			wantStatus: http.StatusNoContent,
+		},
+		{
+			name:       "204 own account default reason",
+			userID:     id.String(),
+			query:      "?confirm_self=true",
+			headers:    authHeader(id.String(), "worker"),
+			mockUS:     deleted(id, domain.DeletionUserRequest, true),
+			wantStatus: http.StatusNoContent,
+		},
+		{
+			name:       "400 invalid confirm_self",
+			userID:     id.String(),
+			query:      "?confirm_self=yes-please",
+			headers:    authHeader(id.String(), "worker"),
+			mockUS:     func() userService { return &FakeUserService{} },
+			wantStatus: http.StatusBadRequest,
+			wantErr:    "confirm_self must be true or false",
+		},
+		{
+			name:    "409 own account not confirmed",
 			userID:  id.String(),
-			headers: authHeader(),
-			mockUS: func() ports.UserService {
+			headers: authHeader(id.String(), "worker"),
+			mockUS: func() userService {
 				return &FakeUserService{
-					DeleteUserFunc: func(ctx context.Context, userUUID domain.UUID) error { return nil },
+					DeleteUserFunc: func(ctx context.Context, actor, userUUID domain.UUID, reason domain.DeletionReason, confirmSelf bool) error {
+						return services.ErrSelfDeleteUnconfirmed
+					},
 				}
 			},
-			wantStatus: http.StatusNoContent,
-			wantErr:    "",
+			wantStatus: http.StatusConflict,
+			wantErr:    "deleting your own account must be confirmed",
+			wantCode:   "self_delete_unconfirmed",
+		},
+		{
+			name:    "409 last admin",
+			userID:  id.String(),
+			headers: authHeader(adminID.String(), "admin"),
+			mockUS: func() userService {
+				return &FakeUserService{
+					DeleteUserFunc: func(ctx context.Context, actor, userUUID domain.UUID, reason domain.DeletionReason, confirmSelf bool) error {
+						return domain.ErrLastAdmin
+					},
+				}
+			},
+			wantStatus: http.StatusConflict,
+			wantErr:    "the last admin cannot be deleted",
+			wantCode:   "last_admin",
+		},
+		{
+			name:    "409 deletion in progress",
+			userID:  id.String(),
+			headers: authHeader(adminID.String(), "admin"),
+			mockUS: func() userService {
+				return &FakeUserService{
+					DeleteUserFunc: func(ctx context.Context, actor, userUUID domain.UUID, reason domain.DeletionReason, confirmSelf bool) error {
+						return deletion.ErrInProgress
+					},
+				}
+			},
+			wantStatus: http.StatusConflict,
+			wantErr:    "the deletion of the user is in progress",
+			wantCode:   "deletion_in_progress",
+		},
+		{
+			name:    "404 not found",
+			userID:  id.String(),
+			headers: authHeader(adminID.String(), "admin"),
+			mockUS: func() userService {
+				return &FakeUserService{
+					DeleteUserFunc: func(ctx context.Context, actor, userUUID domain.UUID, reason domain.DeletionReason, confirmSelf bool) error {
+						return services.ErrUserNotFound
+					},
+				}
+			},
+			wantStatus: http.StatusNotFound,
+			wantErr:    "user not found",
 		},
 	}
 
@@ -622,14 +1112,377 @@ func TestUserController_DeleteUserHandler(t *testing.T) {
 		tt := tt
 		t.Run(tt.name, func(t *testing.T) {
 			r, _, _, _ := setupRouter(t, tt.mockUS(), true)
-			rr := doReq(t, r, http.MethodDelete, "/users/"+tt.userID, nil, tt.headers)
-			require.Equal(t, tt.wantStatus, rr.Code)
+			rr := doReq(t, r, http.MethodDelete, "/users/"+tt.userID+tt.query, nil, tt.headers)
+			require.Equal(t, tt.wantStatus, rr.Code, rr.Body.String())
 
 			if tt.wantErr != "" {
 				var resp map[string]any
 				_ = json.Unmarshal(rr.Body.Bytes(), &resp)
 				assert.Equal(t, tt.wantErr, resp["error"])
+				if tt.wantCode != "" {
+					assert.Equal(t, tt.wantCode, resp["code"])
+				}
+			}
+		})
+	}
+}
+
+func TestUserController_SelfAndAdminRoutes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	selfID := uuid.New()
+
+	type tc struct {
+		name       string
+		method     string
+		path       string
+		role       string
+		body       any
+		us         *FakeUserService
+		wantStatus int
+		wantErr    string
+	}
+	cases := []tc{
+		{
+			name:       "list without token",
+			method:     http.MethodGet,
+			path:       RouteUsers,
+			us:         &FakeUserService{},
+			wantStatus: http.StatusUnauthorized,
+			wantErr:    "missing Authorization header",
+		},
+		{
+			name:       "list as worker",
+			method:     http.MethodGet,
+			path:       RouteUsers,
+			role:       domain.RoleWorker,
+			us:         &FakeUserService{},
+			wantStatus: http.StatusForbidden,
+			wantErr:    "admin role required",
+		},
+		{
+			name:   "list as admin",
+			method: http.MethodGet,
+			path:   RouteUsers,
+			role:   domain.RoleAdmin,
+			us: &FakeUserService{StreamUsersFunc: func(ctx context.Context, p pagination.Params, fn func(u *domain.User) error) error {
+				return fn(someDomainUser())
+			}},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "me without token",
+			method:     http.MethodGet,
+			path:       RouteMe,
+			us:         &FakeUserService{},
+			wantStatus: http.StatusUnauthorized,
+			wantErr:    "missing Authorization header",
+		},
+		{
+			name:   "get me",
+			method: http.MethodGet,
+			path:   RouteMe,
+			role:   domain.RoleWorker,
+			us: &FakeUserService{FindUserByIDFunc: func(ctx context.Context, id domain.UUID) (*domain.User, error) {
+				if id != selfID {
+					return nil, errors.New("not the token subject")
+				}
+				return someDomainUser(), nil
+			}},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:   "update me",
+			method: http.MethodPut,
+			path:   RouteMe,
+			role:   domain.RoleWorker,
+			body:   validUserRequest(),
+			us: &FakeUserService{UpdateUserFunc: func(ctx context.Context, u domain.User) (*domain.User, error) {
+				if u.UUID != selfID {
+					return nil, errors.New("not the token subject")
+				}
+				return someDomainUser(), nil
+			}},
+			wantStatus: http.StatusOK,
+		},
+	}
+
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			r := gin.New()
+			j := jwtSvc.New("test-secret")
+			NewUserController(r, tt.us, tt.us, zap.NewNop(), j)
+
+			headers := map[string]string{}
+			if tt.role != "" {
+				tok, err := j.GenerateToken(selfID.String(), tt.role, time.Minute)
+				require.NoError(t, err)
+				headers["Authorization"] = "Bearer " + tok
+			}
+			rr := doReq(t, r, tt.method, tt.path, tt.body, headers)
+			require.Equal(t, tt.wantStatus, rr.Code, rr.Body.String())
+			if tt.wantErr == "" {
+				return
+			}
+
+			var resp map[string]any
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+			assert.Equal(t, tt.wantErr, resp["error"])
+		})
+	}
+}
+
+func TestUserController_GetDeletedUsersHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	deletedAt := time.Now()
+	u := someDomainUser()
+	u.DeletedAt, u.DeletedReason = &deletedAt, domain.DeletionGDPR
+
+	type tc struct {
+		name       string
+		query      string
+		role       string
+		us         *FakeUserService
+		wantStatus int
+		wantErr    string
+	}
+	cases := []tc{
+		{
+			name:       "as worker",
+			role:       domain.RoleWorker,
+			us:         &FakeUserService{},
+			wantStatus: http.StatusForbidden,
+			wantErr:    "admin role required",
+		},
+		{
+			name:       "unknown reason",
+			query:      "?reason=spam",
+			role:       domain.RoleAdmin,
+			us:         &FakeUserService{},
+			wantStatus: http.StatusBadRequest,
+			wantErr:    "invalid filter params",
+		},
+		{
+			name:  "service error",
+			role:  domain.RoleAdmin,
+			query: "?reason=fraud",
+			us: &FakeUserService{FindDeletedUsersFunc: func(ctx context.Context, reason domain.DeletionReason, p pagination.Params) (domain.Users, error) {
+				return nil, errors.New("db error")
+			}},
+			wantStatus: http.StatusInternalServerError,
+			wantErr:    "failed to get deleted users",
+		},
+		{
+			name:  "filtered by reason",
+			query: "?reason=gdpr&per_page=10",
+			role:  domain.RoleAdmin,
+			us: &FakeUserService{FindDeletedUsersFunc: func(ctx context.Context, reason domain.DeletionReason, p pagination.Params) (domain.Users, error) {
+				if reason != domain.DeletionGDPR || p.PerPage != 10 {
+					return nil, errors.New("unexpected args")
+				}
+				return domain.Users{u}, nil
+			}},
+			wantStatus: http.StatusOK,
+		},
+	}
+
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			r := gin.New()
+			j := jwtSvc.New("test-secret")
+			NewUserController(r, tt.us, tt.us, zap.NewNop(), j)
+
+			tok, err := j.GenerateToken(uuid.NewString(), tt.role, time.Minute)
+			require.NoError(t, err)
+			rr := doReq(t, r, http.MethodGet, RouteAdminDeletedUsers+tt.query, nil, map[string]string{"Authorization": "Bearer " + tok})
+			require.Equal(t, tt.wantStatus, rr.Code, rr.Body.String())
+			if tt.wantErr != "" {
+				var resp map[string]any
+				require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+				assert.Equal(t, tt.wantErr, resp["error"])
+				return
+			}
+
+			var resp user.ResponseData
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+			require.Len(t, resp.Data, 1)
+			assert.Equal(t, "gdpr", resp.Data[0].DeletedReason)
+			assert.NotEmpty(t, resp.NextCursor)
+		})
+	}
+}
+
+func TestUserController_ResponseAudience(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	u := someDomainUser()
+
+	type tc struct {
+		name     string
+		subject  string
+		role     string
+		wantKeys []string
+	}
+	cases := []tc{
+		{"anonymous gets the public card", "", "", []string{"uuid", "name"}},
+		{"other user gets the public card", uuid.NewString(), domain.RoleWorker, []string{"uuid", "name"}},
+		{"self gets the full profile", u.UUID.String(), domain.RoleWorker, []string{"uuid", "email", "name", "lastname", "birth_date", "phone"}},
+		{
+			"admin gets the account metadata", uuid.NewString(), domain.RoleAdmin,
+			[]string{"uuid", "email", "name", "lastname", "birth_date", "phone", "role", "created_at", "updated_at"},
+		},
+	}
+
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			r := gin.New()
+			j := jwtSvc.New("test-secret")
+			us := &FakeUserService{
+				FindUserByIDFunc: func(ctx context.Context, id domain.UUID) (*domain.User, error) { return u, nil },
+			}
+			NewUserController(r, us, us, zap.NewNop(), j)
+
+			headers := map[string]string{}
+			if tt.subject != "" {
+				tok, err := j.GenerateToken(tt.subject, tt.role, time.Minute)
+				require.NoError(t, err)
+				headers["Authorization"] = "Bearer " + tok
+			}
+			rr := doReq(t, r, http.MethodGet, RouteUsers+"/"+u.UUID.String(), nil, headers)
+			require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+
+			var resp map[string]any
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+			keys := make([]string, 0, len(resp))
+			for k := range resp {
+				keys = append(keys, k)
+			}
+			assert.ElementsMatch(t, tt.wantKeys, keys)
+		})
+	}
+
+	t.Run("invalid token is rejected", func(t *testing.T) {
+		r := gin.New()
+		NewUserController(r, &FakeUserService{}, &FakeUserService{}, zap.NewNop(), jwtSvc.New("test-secret"))
+
+		rr := doReq(t, r, http.MethodGet, RouteUsers+"/"+u.UUID.String(), nil, map[string]string{"Authorization": "Bearer bad"})
+		require.Equal(t, http.StatusUnauthorized, rr.Code)
+	})
+}
+
+func TestUserController_Export(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	u := someDomainUser()
+
+	type tc struct {
+		name            string
+		format          string
+		subject         string
+		role            string
+		wantStatus      int
+		wantContentType string
+		wantErr         string
+	}
+	cases := []tc{
+		{"self vcard", "vcard", u.UUID.String(), domain.RoleWorker, http.StatusOK, "text/vcard; charset=utf-8", ""},
+		{"admin pdf", "pdf", uuid.NewString(), domain.RoleAdmin, http.StatusOK, "application/pdf", ""},
+		{"explicit json", "json", "", "", http.StatusOK, "application/json; charset=utf-8", ""},
+		{"anonymous export", "vcard", "", "", http.StatusUnauthorized, "", "missing Authorization header"},
+		{"other user export", "pdf", uuid.NewString(), domain.RoleWorker, http.StatusForbidden, "", "export is available to the user itself and admins"},
+		{"unknown format", "xml", u.UUID.String(), domain.RoleWorker, http.StatusBadRequest, "", "format must be json, vcard or pdf"},
+	}
+
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			r := gin.New()
+			j := jwtSvc.New("test-secret")
+			us := &FakeUserService{
+				FindUserByIDFunc: func(ctx context.Context, id domain.UUID) (*domain.User, error) { return u, nil },
 			}
+			NewUserController(r, us, us, zap.NewNop(), j)
+
+			headers := map[string]string{}
+			if tt.subject != "" {
+				tok, err := j.GenerateToken(tt.subject, tt.role, time.Minute)
+				require.NoError(t, err)
+				headers["Authorization"] = "Bearer " + tok
+			}
+			rr := doReq(t, r, http.MethodGet, RouteUsers+"/"+u.UUID.String()+"?format="+tt.format, nil, headers)
+			require.Equal(t, tt.wantStatus, rr.Code, rr.Body.String())
+
+			if tt.wantErr != "" {
+				var body map[string]any
+				require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &body))
+				assert.Equal(t, tt.wantErr, body["error"])
+				return
+			}
+			assert.Equal(t, tt.wantContentType, rr.Header().Get("Content-Type"))
+			switch tt.format {
+			case "vcard":
+				assert.Equal(t, `attachment; filename="`+u.UUID.String()+`.vcf"`, rr.Header().Get("Content-Disposition"))
+				assert.Contains(t, rr.Body.String(), "EMAIL:john.doe@example.com\r\n")
+			case "pdf":
+				assert.True(t, strings.HasPrefix(rr.Body.String(), "%PDF-"))
+			}
+		})
+	}
+}
+
+func TestUserController_FilesSummary(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	u := someDomainUser()
+	u.Files = &domain.FilesSummary{Count: 3, TotalBytes: 4096}
+
+	type tc struct {
+		name      string
+		subject   string
+		role      string
+		wantFiles bool
+	}
+	cases := []tc{
+		{"self", u.UUID.String(), domain.RoleWorker, true},
+		{"admin", uuid.NewString(), domain.RoleAdmin, true},
+		{"public", "", "", false},
+	}
+
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			r := gin.New()
+			j := jwtSvc.New("test-secret")
+			us := &FakeUserService{
+				FindUserByIDFunc: func(ctx context.Context, id domain.UUID) (*domain.User, error) { return u, nil },
+			}
+			NewUserController(r, us, us, zap.NewNop(), j)
+
+			headers := map[string]string{}
+			if tt.subject != "" {
+				tok, err := j.GenerateToken(tt.subject, tt.role, time.Minute)
+				require.NoError(t, err)
+				headers["Authorization"] = "Bearer " + tok
+			}
+			rr := doReq(t, r, http.MethodGet, RouteUsers+"/"+u.UUID.String(), nil, headers)
+			require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+
+			var resp map[string]any
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+			if !tt.wantFiles {
+				assert.NotContains(t, resp, "files_count")
+				assert.NotContains(t, resp, "total_storage_bytes")
+				return
+			}
+			assert.EqualValues(t, 3, resp["files_count"])
+			assert.EqualValues(t, 4096, resp["total_storage_bytes"])
 		})
 	}
+
+	t.Run("empty summary is zero, not omitted", func(t *testing.T) {
+		empty := *u
+		empty.Files = &domain.FilesSummary{}
+		b, err := json.Marshal(user.ToResponseUser(empty))
+		require.NoError(t, err)
+		assert.Contains(t, string(b), `"files_count":0`)
+	})
 }
@@ -1,8 +1,11 @@
 package rest
 
 import (
+	"errors"
+	"math"
 	"net/http"
-	"user-manager-api/internal/infrastructure/jwt"
+	"strconv"
+	"user-manager-api/internal/application/services"
 	"user-manager-api/internal/interface/api/rest/dto/user_file"
 	"user-manager-api/internal/interface/api/rest/middleware"
 
@@ -10,42 +13,68 @@ import (
 	"go.uber.org/zap"
 
 	"user-manager-api/internal/application/ports"
+	"user-manager-api/internal/domain/pagination"
+	domainUser "user-manager-api/internal/domain/user"
+	domainFile "user-manager-api/internal/domain/user_file"
 	"user-manager-api/internal/interface/api/rest/validator"
+	"user-manager-api/pkg/progress"
 )
 
-// 10MB
-const maxSize = int64(10 << 20)
+// "code" values of the answers to an upload rejected by the upload policy of
+// the organization of the user
+const (
+	codeFileTypeNotAllowed = "file_type_not_allowed"
+	codeFileTooLarge       = "file_too_large"
+	codeQuotaExceeded      = "quota_exceeded"
+)
+
+// maxUploadFormOverhead - of an upload form over its file: the part headers,
+// the boundaries, the tags and the folder
+const maxUploadFormOverhead = 64 << 10
 
 type UserFileController struct {
 	userFileService ports.UserFileService
 	logger          *zap.Logger
+	maxUploadSize   int64
+	// uploads - nil disables the progress of the uploads
+	uploads *progress.Tracker
 }
 
 func NewUserFileController(
 	r *gin.Engine,
 	userFileService ports.UserFileService,
 	logger *zap.Logger,
-	jwtService *jwt.Service,
+	tokenService ports.TokenService,
+	maxUploadSize int64,
+	uploads *progress.Tracker,
 ) *UserFileController {
 	ufc := &UserFileController{
 		userFileService: userFileService,
 		logger:          logger,
+		maxUploadSize:   maxUploadSize,
+		uploads:         uploads,
 	}
 
-	r.GET(RouteUserFiles, ufc.GetUserFilesHandler)
-	r.POST(RouteUserFiles, middleware.AuthMiddleware(jwtService), ufc.CreateUserFileHandler)
-	r.DELETE(RouteUserFiles, middleware.AuthMiddleware(jwtService), ufc.DeleteUserFilesHandler)
+	r.GET(RouteUserFiles, middleware.AuthMiddleware(tokenService), middleware.SelfOrAdmin("user_id"), ufc.GetUserFilesHandler)
+	r.GET(RouteMeFiles, middleware.AuthMiddleware(tokenService), middleware.SelfParam("user_id"), ufc.GetUserFilesHandler)
+	r.POST(RouteUserFiles, middleware.AuthMiddleware(tokenService), middleware.SelfOrAdmin("user_id"), ufc.CreateUserFileHandler)
+	r.DELETE(RouteUserFiles, middleware.AuthMiddleware(tokenService), middleware.SelfOrAdmin("user_id"), ufc.DeleteUserFilesHandler)
+	r.PATCH(RouteUserFile, middleware.AuthMiddleware(tokenService), middleware.SelfOrAdmin("user_id"), ufc.MoveUserFileHandler)
+	r.GET(RouteUserFileText, middleware.AuthMiddleware(tokenService), middleware.SelfOrAdmin("user_id"), ufc.GetUserFileTextHandler)
+	r.GET(RouteUserFilesSearch, middleware.AuthMiddleware(tokenService), middleware.SelfOrAdmin("user_id"), ufc.SearchUserFilesHandler)
+	r.GET(RouteUserFolders, middleware.AuthMiddleware(tokenService), middleware.SelfOrAdmin("user_id"), ufc.GetUserFoldersHandler)
+	r.POST(RouteUserFoldersMove, middleware.AuthMiddleware(tokenService), middleware.SelfOrAdmin("user_id"), ufc.MoveFolderHandler)
 
 	return ufc
 }
 
 func (ufc *UserFileController) GetUserFilesHandler(c *gin.Context) {
-	page, err := validator.ValidatePage(c.Query("page"))
-	if err != nil {
-		c.JSON(
-			http.StatusBadRequest,
-			gin.H{"error": err.Error()},
-		)
+	p, errs := validator.ParsePagination(c.Request.URL.Query(), validator.UserFileSortFields)
+	if errs != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "invalid pagination params",
+			"details": errs,
+		})
 		return
 	}
 	ok, uuid := validator.IsUUID(c.Param("user_id"))
@@ -57,19 +86,62 @@ func (ufc *UserFileController) GetUserFilesHandler(c *gin.Context) {
 		return
 	}
 
-	files, err := ufc.userFileService.FindUserFiles(c.Request.Context(), uuid, page)
+	tags, err := validator.ValidateTags(c.QueryArray("tag"))
 	if err != nil {
 		c.JSON(
-			http.StatusInternalServerError,
-			gin.H{"error": "failed to get files"},
+			http.StatusBadRequest,
+			gin.H{"error": err.Error()},
 		)
-		ufc.logger.Error("FindUserFiles() error", zap.Error(err))
 		return
 	}
 
-	c.JSON(http.StatusOK, user_file.ResponseData{
-		Data: user_file.ToResponseUserFiles(files),
+	// "?folder=" - the root files only, absent - the files of all the folders
+	var folder *string
+	if v, ok := c.GetQuery("folder"); ok {
+		f, err := validator.ParseFolder(v)
+		if err != nil {
+			c.JSON(
+				http.StatusBadRequest,
+				gin.H{"error": err.Error()},
+			)
+			return
+		}
+		folder = &f
+	}
+
+	list := newJSONList(c)
+	var (
+		last pagination.Cursor
+		dto  user_file.UserFile
+	)
+	err = ufc.userFileService.StreamUserFiles(c.Request.Context(), uuid, p, tags, folder, func(uf *domainFile.UserFile) error {
+		last = pagination.Cursor{CreatedAt: uf.CreatedAt, UUID: uf.UUID}
+		dto = user_file.ToResponseUserFile(*uf)
+		return list.Add(&dto)
 	})
+	if err != nil {
+		if list.Len() == 0 && errors.Is(err, services.ErrUserNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error(), "code": codeUserNotFound})
+			return
+		}
+		if list.Len() == 0 {
+			c.JSON(
+				http.StatusInternalServerError,
+				gin.H{"error": "failed to get files"},
+			)
+		}
+		ufc.logger.Error("StreamUserFiles() error", zap.Error(err), zap.Int("written", list.Len()))
+		return
+	}
+
+	var nextCursor string
+	// keyset pagination is available for the default(created_at) order only
+	if list.Len() > 0 && p.Sort == "" {
+		nextCursor = validator.EncodeCursor(last)
+	}
+	if err = list.Close(nextCursor); err != nil {
+		ufc.logger.Warn("files response write error", zap.Error(err))
+	}
 }
 
 func (ufc *UserFileController) CreateUserFileHandler(c *gin.Context) {
@@ -82,18 +154,86 @@ func (ufc *UserFileController) CreateUserFileHandler(c *gin.Context) {
 		return
 	}
 
+	up, ok := ufc.trackUpload(c)
+	if !ok {
+		return
+	}
+	if up != nil {
+		defer func() {
+			var err error
+			if c.Writer.Status() != http.StatusCreated {
+				err = errUploadFailed
+			}
+			up.Finish(err)
+		}()
+	}
+
+	// the parser stops at the max upload size(and the form fields), the body
+	// of a larger one is not spooled to the disk whole
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, ufc.maxUploadSize+maxUploadFormOverhead)
 	fh, err := c.FormFile("file")
 	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "file too large or empty"})
+			return
+		}
 		c.JSON(http.StatusBadRequest, gin.H{"error": "file is required"})
 		return
 	}
-	if fh.Size <= 0 || fh.Size > maxSize {
+	if up != nil {
+		up.Processing()
+	}
+	if fh.Size <= 0 || fh.Size > ufc.maxUploadSize {
 		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "file too large or empty"})
 		return
 	}
 
-	uf, err := ufc.userFileService.CreateUserFile(c.Request.Context(), uuid, fh)
+	tags, err := validator.ValidateTags(c.PostFormArray("tags"))
+	if err != nil {
+		c.JSON(
+			http.StatusBadRequest,
+			gin.H{"error": err.Error()},
+		)
+		return
+	}
+	folder, err := validator.ParseFolder(c.PostForm("folder"))
 	if err != nil {
+		c.JSON(
+			http.StatusBadRequest,
+			gin.H{"error": err.Error()},
+		)
+		return
+	}
+
+	uf, err := ufc.userFileService.CreateUserFile(c.Request.Context(), uuid, fh, tags, folder)
+	if err != nil {
+		if errors.Is(err, services.ErrTooManyFileOperations) {
+			c.JSON(http.StatusTooManyRequests, gin.H{"error": err.Error()})
+			return
+		}
+		if errors.Is(err, services.ErrUserNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error(), "code": codeUserNotFound})
+			return
+		}
+		switch {
+		case errors.Is(err, services.ErrFileTypeNotAllowed):
+			c.JSON(http.StatusUnsupportedMediaType, gin.H{"error": err.Error(), "code": codeFileTypeNotAllowed})
+			return
+		case errors.Is(err, services.ErrFileTooLarge):
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": err.Error(), "code": codeFileTooLarge})
+			return
+		case errors.Is(err, services.ErrQuotaExceeded):
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": err.Error(), "code": codeQuotaExceeded})
+			return
+		}
+		// the storage is down: the listing still works, the upload is retried later
+		var unavailable *services.UnavailableError
+		if errors.As(err, &unavailable) {
+			c.Header("Retry-After", strconv.Itoa(int(math.Ceil(unavailable.RetryAfter.Seconds()))))
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": "file storage is unavailable, retry later"})
+			return
+		}
 		c.JSON(
 			http.StatusInternalServerError,
 			gin.H{"error": "failed to create a file"},
@@ -105,6 +245,39 @@ func (ufc *UserFileController) CreateUserFileHandler(c *gin.Context) {
 	c.JSON(http.StatusCreated, user_file.ToResponseUserFile(*uf))
 }
 
+// trackUpload - the progress of the request body under "?upload_id=", nil if the
+// client did not ask for it. False if the request is answered.
+func (ufc *UserFileController) trackUpload(c *gin.Context) (*progress.Upload, bool) {
+	id := c.Query("upload_id")
+	if id == "" || ufc.uploads == nil {
+		return nil, true
+	}
+	if ok, _ := validator.IsUUID(id); !ok {
+		c.JSON(
+			http.StatusBadRequest,
+			gin.H{"error": "upload_id must be a valid UUID"},
+		)
+		return nil, false
+	}
+
+	up, err := ufc.uploads.Start(id, uploadOwner(c), c.Request.ContentLength)
+	if errors.Is(err, progress.ErrExists) {
+		c.JSON(http.StatusConflict, gin.H{"error": "upload_id is already in use"})
+		return nil, false
+	}
+	if err != nil {
+		c.JSON(
+			http.StatusInternalServerError,
+			gin.H{"error": "failed to create a file"},
+		)
+		ufc.logger.Error("Start() upload error", zap.Error(err))
+		return nil, false
+	}
+	c.Request.Body = up.Reader(c.Request.Body)
+
+	return up, true
+}
+
 func (ufc *UserFileController) DeleteUserFilesHandler(c *gin.Context) {
 	ok, uuid := validator.IsUUID(c.Param("user_id"))
 	if !ok {
@@ -115,8 +288,30 @@ func (ufc *UserFileController) DeleteUserFilesHandler(c *gin.Context) {
 		return
 	}
 
-	err := ufc.userFileService.DeleteUserFiles(c.Request.Context(), uuid)
+	// "?tag=" narrows the bulk delete down to the tagged files
+	tags, err := validator.ValidateTags(c.QueryArray("tag"))
 	if err != nil {
+		c.JSON(
+			http.StatusBadRequest,
+			gin.H{"error": err.Error()},
+		)
+		return
+	}
+
+	err = ufc.userFileService.DeleteUserFiles(c.Request.Context(), uuid, tags)
+	if err != nil {
+		if errors.Is(err, services.ErrTooManyFileOperations) {
+			c.JSON(http.StatusTooManyRequests, gin.H{"error": err.Error()})
+			return
+		}
+		if errors.Is(err, services.ErrUserNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error(), "code": codeUserNotFound})
+			return
+		}
+		if errors.Is(err, domainUser.ErrLegalHold) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error(), "code": codeLegalHold})
+			return
+		}
 		c.JSON(
 			http.StatusInternalServerError,
 			gin.H{"error": "failed to delete user files"},
@@ -127,3 +322,206 @@ func (ufc *UserFileController) DeleteUserFilesHandler(c *gin.Context) {
 
 	c.Status(http.StatusNoContent)
 }
+
+// MoveUserFileHandler - moves a file to another folder and/or renames it
+func (ufc *UserFileController) MoveUserFileHandler(c *gin.Context) {
+	ok, uuid := validator.IsUUID(c.Param("user_id"))
+	if !ok {
+		c.JSON(
+			http.StatusBadRequest,
+			gin.H{"error": "user_id must be a valid UUID"},
+		)
+		return
+	}
+	ok, fileUUID := validator.IsUUID(c.Param("file_id"))
+	if !ok {
+		c.JSON(
+			http.StatusBadRequest,
+			gin.H{"error": "file_id must be a valid UUID"},
+		)
+		return
+	}
+
+	req, ok := BindAndValidate(c, validator.ValidateFileMove)
+	if !ok {
+		return
+	}
+	if req.Folder != nil {
+		folder, _ := validator.ParseFolder(*req.Folder)
+		req.Folder = &folder
+	}
+
+	uf, err := ufc.userFileService.MoveUserFile(c.Request.Context(), uuid, fileUUID, req.Folder, req.FileName)
+	if err != nil {
+		if errors.Is(err, services.ErrUserNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error(), "code": codeUserNotFound})
+			return
+		}
+		if errors.Is(err, services.ErrUserFileNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(
+			http.StatusInternalServerError,
+			gin.H{"error": "failed to move the file"},
+		)
+		ufc.logger.Error("MoveUserFile() error", zap.Error(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, user_file.ToResponseUserFile(*uf))
+}
+
+// GetUserFileTextHandler - the text extracted from a PDF or an image after
+// the upload, 404 until it is extracted
+func (ufc *UserFileController) GetUserFileTextHandler(c *gin.Context) {
+	ok, uuid := validator.IsUUID(c.Param("user_id"))
+	if !ok {
+		c.JSON(
+			http.StatusBadRequest,
+			gin.H{"error": "user_id must be a valid UUID"},
+		)
+		return
+	}
+	ok, fileUUID := validator.IsUUID(c.Param("file_id"))
+	if !ok {
+		c.JSON(
+			http.StatusBadRequest,
+			gin.H{"error": "file_id must be a valid UUID"},
+		)
+		return
+	}
+
+	t, err := ufc.userFileService.GetFileText(c.Request.Context(), uuid, fileUUID)
+	if err != nil {
+		if errors.Is(err, services.ErrUserNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error(), "code": codeUserNotFound})
+			return
+		}
+		if errors.Is(err, services.ErrTextNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(
+			http.StatusInternalServerError,
+			gin.H{"error": "failed to get the file text"},
+		)
+		ufc.logger.Error("GetFileText() error", zap.Error(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, user_file.ToResponseText(fileUUID, *t))
+}
+
+// SearchUserFilesHandler - the files whose extracted text matches "?q=", best first
+func (ufc *UserFileController) SearchUserFilesHandler(c *gin.Context) {
+	ok, uuid := validator.IsUUID(c.Param("user_id"))
+	if !ok {
+		c.JSON(
+			http.StatusBadRequest,
+			gin.H{"error": "user_id must be a valid UUID"},
+		)
+		return
+	}
+	query, limit, errs := validator.ParseFileSearch(c.Request.URL.Query())
+	if errs != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "invalid search params",
+			"details": errs,
+		})
+		return
+	}
+
+	ufs, err := ufc.userFileService.SearchUserFiles(c.Request.Context(), uuid, query, limit)
+	if err != nil {
+		if errors.Is(err, services.ErrUserNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error(), "code": codeUserNotFound})
+			return
+		}
+		c.JSON(
+			http.StatusInternalServerError,
+			gin.H{"error": "failed to search files"},
+		)
+		ufc.logger.Error("SearchUserFiles() error", zap.Error(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, user_file.ResponseData{Data: user_file.ToResponseUserFiles(ufs)})
+}
+
+// GetUserFoldersHandler - the subfolders of "?parent="(the root by default)
+// holding files, a folder exists while it has files
+func (ufc *UserFileController) GetUserFoldersHandler(c *gin.Context) {
+	ok, uuid := validator.IsUUID(c.Param("user_id"))
+	if !ok {
+		c.JSON(
+			http.StatusBadRequest,
+			gin.H{"error": "user_id must be a valid UUID"},
+		)
+		return
+	}
+	parent, err := validator.ParseFolder(c.Query("parent"))
+	if err != nil {
+		c.JSON(
+			http.StatusBadRequest,
+			gin.H{"error": err.Error()},
+		)
+		return
+	}
+
+	folders, err := ufc.userFileService.ListFolders(c.Request.Context(), uuid, parent)
+	if err != nil {
+		if errors.Is(err, services.ErrUserNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error(), "code": codeUserNotFound})
+			return
+		}
+		c.JSON(
+			http.StatusInternalServerError,
+			gin.H{"error": "failed to get folders"},
+		)
+		ufc.logger.Error("ListFolders() error", zap.Error(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, user_file.ToResponseFolders(folders))
+}
+
+// MoveFolderHandler - moves(renames) a folder with its subfolders, into
+// another folder when they exist both
+func (ufc *UserFileController) MoveFolderHandler(c *gin.Context) {
+	ok, uuid := validator.IsUUID(c.Param("user_id"))
+	if !ok {
+		c.JSON(
+			http.StatusBadRequest,
+			gin.H{"error": "user_id must be a valid UUID"},
+		)
+		return
+	}
+
+	req, ok := BindAndValidate(c, validator.ValidateFolderMove)
+	if !ok {
+		return
+	}
+	from, _ := validator.ParseFolder(req.From)
+	to, _ := validator.ParseFolder(req.To)
+
+	moved, err := ufc.userFileService.MoveFolder(c.Request.Context(), uuid, from, to)
+	if err != nil {
+		if errors.Is(err, services.ErrUserNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error(), "code": codeUserNotFound})
+			return
+		}
+		if errors.Is(err, services.ErrFolderNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(
+			http.StatusInternalServerError,
+			gin.H{"error": "failed to move the folder"},
+		)
+		ufc.logger.Error("MoveFolder() error", zap.Error(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, user_file.FolderMoveResponse{Moved: moved})
+}
@@ -20,38 +20,84 @@ import (
 	"go.uber.org/zap"
 
 	"user-manager-api/internal/application/ports"
+	"user-manager-api/internal/application/services"
+	"user-manager-api/internal/domain/pagination"
 	domainUser "user-manager-api/internal/domain/user"
 	domainFile "user-manager-api/internal/domain/user_file"
 	jwtSvc "user-manager-api/internal/infrastructure/jwt"
+	"user-manager-api/internal/interface/api/rest/dto/user_file"
 	"user-manager-api/internal/interface/api/rest/middleware"
 )
 
 type FakeUserFileService struct {
-	FindUserFilesFunc   func(ctx context.Context, userUUID domainUser.UUID, page int) (domainFile.UserFiles, error)
-	CreateUserFileFunc  func(ctx context.Context, userUUID domainUser.UUID, fh *multipart.FileHeader) (*domainFile.UserFile, error)
-	DeleteUserFilesFunc func(ctx context.Context, userUUID domainUser.UUID) error
+	StreamUserFilesFunc func(ctx context.Context, userUUID domainUser.UUID, p pagination.Params, tags []string, folder *string, fn func(uf *domainFile.UserFile) error) error
+	CreateUserFileFunc  func(ctx context.Context, userUUID domainUser.UUID, fh *multipart.FileHeader, tags []string, folder string) (*domainFile.UserFile, error)
+	DeleteUserFilesFunc func(ctx context.Context, userUUID domainUser.UUID, tags []string) error
+	ListFoldersFunc     func(ctx context.Context, userUUID domainUser.UUID, parent string) (domainFile.Folders, error)
+	MoveUserFileFunc    func(ctx context.Context, userUUID domainUser.UUID, fileUUID uuid.UUID, folder, fileName *string) (*domainFile.UserFile, error)
+	MoveFolderFunc      func(ctx context.Context, userUUID domainUser.UUID, from, to string) (int64, error)
+	GetFileTextFunc     func(ctx context.Context, userUUID domainUser.UUID, fileUUID uuid.UUID) (*domainFile.Text, error)
+	SearchUserFilesFunc func(ctx context.Context, userUUID domainUser.UUID, query string, limit int) (domainFile.UserFiles, error)
+	ObjectOwnerFunc     func(ctx context.Context, key string) (*domainFile.ObjectOwner, error)
 }
 
-func (f *FakeUserFileService) FindUserFiles(ctx context.Context, userUUID domainUser.UUID, page int) (domainFile.UserFiles, error) {
-	if f.FindUserFilesFunc == nil {
-		return nil, errors.New("not used")
+func (f *FakeUserFileService) StreamUserFiles(ctx context.Context, userUUID domainUser.UUID, p pagination.Params, tags []string, folder *string, fn func(uf *domainFile.UserFile) error) error {
+	if f.StreamUserFilesFunc == nil {
+		return errors.New("not used")
 	}
-	return f.FindUserFilesFunc(ctx, userUUID, page)
+	return f.StreamUserFilesFunc(ctx, userUUID, p, tags, folder, fn)
 }
-func (f *FakeUserFileService) CreateUserFile(ctx context.Context, userUUID domainUser.UUID, fh *multipart.FileHeader) (*domainFile.UserFile, error) {
+func (f *FakeUserFileService) CreateUserFile(ctx context.Context, userUUID domainUser.UUID, fh *multipart.FileHeader, tags []string, folder string) (*domainFile.UserFile, error) {
 	if f.CreateUserFileFunc == nil {
 		return nil, errors.New("not used")
 	}
-	return f.CreateUserFileFunc(ctx, userUUID, fh)
+	return f.CreateUserFileFunc(ctx, userUUID, fh, tags, folder)
 }
-func (f *FakeUserFileService) DeleteUserFiles(ctx context.Context, userUUID domainUser.UUID) error {
+func (f *FakeUserFileService) DeleteUserFiles(ctx context.Context, userUUID domainUser.UUID, tags []string) error {
 	if f.DeleteUserFilesFunc == nil {
 		return errors.New("not used")
 	}
-	return f.DeleteUserFilesFunc(ctx, userUUID)
+	return f.DeleteUserFilesFunc(ctx, userUUID, tags)
+}
+func (f *FakeUserFileService) ListFolders(ctx context.Context, userUUID domainUser.UUID, parent string) (domainFile.Folders, error) {
+	if f.ListFoldersFunc == nil {
+		return nil, errors.New("not used")
+	}
+	return f.ListFoldersFunc(ctx, userUUID, parent)
+}
+func (f *FakeUserFileService) MoveUserFile(ctx context.Context, userUUID domainUser.UUID, fileUUID uuid.UUID, folder, fileName *string) (*domainFile.UserFile, error) {
+	if f.MoveUserFileFunc == nil {
+		return nil, errors.New("not used")
+	}
+	return f.MoveUserFileFunc(ctx, userUUID, fileUUID, folder, fileName)
+}
+func (f *FakeUserFileService) MoveFolder(ctx context.Context, userUUID domainUser.UUID, from, to string) (int64, error) {
+	if f.MoveFolderFunc == nil {
+		return 0, errors.New("not used")
+	}
+	return f.MoveFolderFunc(ctx, userUUID, from, to)
+}
+func (f *FakeUserFileService) GetFileText(ctx context.Context, userUUID domainUser.UUID, fileUUID uuid.UUID) (*domainFile.Text, error) {
+	if f.GetFileTextFunc == nil {
+		return nil, errors.New("not used")
+	}
+	return f.GetFileTextFunc(ctx, userUUID, fileUUID)
+}
+func (f *FakeUserFileService) SearchUserFiles(ctx context.Context, userUUID domainUser.UUID, query string, limit int) (domainFile.UserFiles, error) {
+	if f.SearchUserFilesFunc == nil {
+		return nil, errors.New("not used")
+	}
+	return f.SearchUserFilesFunc(ctx, userUUID, query, limit)
 }
 
-func setupRouterUFC(t *testing.T, ufs ports.UserFileService, withJWT bool) (*gin.Engine, *UserFileController, string) {
+func (f *FakeUserFileService) ObjectOwner(ctx context.Context, key string) (*domainFile.ObjectOwner, error) {
+	if f.ObjectOwnerFunc == nil {
+		return nil, errors.New("not used")
+	}
+	return f.ObjectOwnerFunc(ctx, key)
+}
+
+func setupRouterUFC(t *testing.T, ufs ports.UserFileService) (*gin.Engine, *UserFileController, string) {
 	t.Helper()
 	gin.SetMode(gin.TestMode)
 
@@ -63,16 +109,12 @@ func setupRouterUFC(t *testing.T, ufs ports.UserFileService, withJWT bool) (*gin
 	ufc := &UserFileController{
 		userFileService: ufs,
 		logger:          logger,
+		maxUploadSize:   10 << 20,
 	}
 
-	r.GET("/users/:user_id/files", ufc.GetUserFilesHandler)
-	if withJWT {
-		r.POST("/users/:user_id/files", middleware.AuthMiddleware(j), ufc.CreateUserFileHandler)
-		r.DELETE("/users/:user_id/files", middleware.AuthMiddleware(j), ufc.DeleteUserFilesHandler)
-	} else {
-		r.POST("/users/:user_id/files", ufc.CreateUserFileHandler)
-		r.DELETE("/users/:user_id/files", ufc.DeleteUserFilesHandler)
-	}
+	r.GET("/users/:user_id/files", middleware.AuthMiddleware(j), middleware.SelfOrAdmin("user_id"), ufc.GetUserFilesHandler)
+	r.POST("/users/:user_id/files", middleware.AuthMiddleware(j), middleware.SelfOrAdmin("user_id"), ufc.CreateUserFileHandler)
+	r.DELETE("/users/:user_id/files", middleware.AuthMiddleware(j), middleware.SelfOrAdmin("user_id"), ufc.DeleteUserFilesHandler)
 
 	return r, ufc, secret
 }
@@ -144,19 +186,56 @@ func doMultipartReq(t *testing.T, r *gin.Engine, method, path string, fields map
 
 func TestUserFileController_GetUserFilesHandler(t *testing.T) {
 	okID := uuid.New()
+	headers := func(sub, role string) map[string]string {
+		tok, _ := SignJWT("test-secret", sub, role, time.Hour)
+		return map[string]string{"Authorization": "Bearer " + tok}
+	}
 
 	tests := []struct {
 		name       string
 		userID     string
 		page       string
+		headers    map[string]string
 		mockUFS    func() ports.UserFileService
 		wantStatus int
 		wantErr    string
 	}{
 		{
-			name:   "400 invalid uuid",
-			userID: "not-uuid",
-			page:   "1",
+			name:       "401 missing Authorization",
+			userID:     okID.String(),
+			page:       "1",
+			mockUFS:    func() ports.UserFileService { return &FakeUserFileService{} },
+			wantStatus: http.StatusUnauthorized,
+			wantErr:    "missing Authorization header",
+		},
+		{
+			name:       "403 another user",
+			userID:     okID.String(),
+			page:       "1",
+			headers:    headers(uuid.NewString(), domainUser.RoleWorker),
+			mockUFS:    func() ports.UserFileService { return &FakeUserFileService{} },
+			wantStatus: http.StatusForbidden,
+			wantErr:    "only the user itself and admins are allowed",
+		},
+		{
+			name:    "200 the user itself",
+			userID:  okID.String(),
+			page:    "1",
+			headers: headers(okID.String(), domainUser.RoleWorker),
+			mockUFS: func() ports.UserFileService {
+				return &FakeUserFileService{
+					StreamUserFilesFunc: func(ctx context.Context, userUUID domainUser.UUID, p pagination.Params, tags []string, folder *string, fn func(uf *domainFile.UserFile) error) error {
+						return nil
+					},
+				}
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:    "400 invalid uuid",
+			userID:  "not-uuid",
+			page:    "1",
+			headers: headers("u1", domainUser.RoleAdmin),
 			mockUFS: func() ports.UserFileService {
 				return &FakeUserFileService{}
 			},
@@ -164,13 +243,14 @@ func TestUserFileController_GetUserFilesHandler(t *testing.T) {
 			wantErr:    "user_id must be a valid UUID",
 		},
 		{
-			name:   "500 service error",
-			userID: okID.String(),
-			page:   "2",
+			name:    "500 service error",
+			userID:  okID.String(),
+			page:    "2",
+			headers: headers("u1", domainUser.RoleAdmin),
 			mockUFS: func() ports.UserFileService {
 				return &FakeUserFileService{
-					FindUserFilesFunc: func(ctx context.Context, userUUID domainUser.UUID, page int) (domainFile.UserFiles, error) {
-						return nil, errors.New("db error")
+					StreamUserFilesFunc: func(ctx context.Context, userUUID domainUser.UUID, p pagination.Params, tags []string, folder *string, fn func(uf *domainFile.UserFile) error) error {
+						return errors.New("db error")
 					},
 				}
 			},
@@ -178,14 +258,58 @@ func TestUserFileController_GetUserFilesHandler(t *testing.T) {
 			wantErr:    "failed to get files",
 		},
 		{
-			name:   "200 success (empty list ok)",
-			userID: okID.String(),
-			page:   "3",
+			name:    "404 user not found",
+			userID:  okID.String(),
+			page:    "1",
+			headers: headers("u1", domainUser.RoleAdmin),
 			mockUFS: func() ports.UserFileService {
 				return &FakeUserFileService{
-					FindUserFilesFunc: func(ctx context.Context, userUUID domainUser.UUID, page int) (domainFile.UserFiles, error) {
-						var files domainFile.UserFiles
-						return files, nil
+					StreamUserFilesFunc: func(ctx context.Context, userUUID domainUser.UUID, p pagination.Params, tags []string, folder *string, fn func(uf *domainFile.UserFile) error) error {
+						return domainUser.ErrNotFound
+					},
+				}
+			},
+			wantStatus: http.StatusNotFound,
+			wantErr:    "user not found",
+		},
+		{
+			name:    "200 success (empty list ok)",
+			userID:  okID.String(),
+			page:    "3",
+			headers: headers("u1", domainUser.RoleAdmin),
+			mockUFS: func() ports.UserFileService {
+				return &FakeUserFileService{
+					StreamUserFilesFunc: func(ctx context.Context, userUUID domainUser.UUID, p pagination.Params, tags []string, folder *string, fn func(uf *domainFile.UserFile) error) error {
+						return nil
+					},
+				}
+			},
+			wantStatus: http.StatusOK,
+			wantErr:    "",
+		},
+		{
+			name:    "400 invalid tag",
+			userID:  okID.String(),
+			page:    "1&tag=bad!tag",
+			headers: headers("u1", domainUser.RoleAdmin),
+			mockUFS: func() ports.UserFileService {
+				return &FakeUserFileService{}
+			},
+			wantStatus: http.StatusBadRequest,
+			wantErr:    "tag allowed characters: a-z, 0-9, '-', '_'",
+		},
+		{
+			name:    "200 tags normalized and passed",
+			userID:  okID.String(),
+			page:    "1&tag=Contracts&tag=ids,contracts",
+			headers: headers("u1", domainUser.RoleAdmin),
+			mockUFS: func() ports.UserFileService {
+				return &FakeUserFileService{
+					StreamUserFilesFunc: func(ctx context.Context, userUUID domainUser.UUID, p pagination.Params, tags []string, folder *string, fn func(uf *domainFile.UserFile) error) error {
+						if len(tags) != 2 || tags[0] != "contracts" || tags[1] != "ids" {
+							return errors.New("unexpected tags")
+						}
+						return nil
 					},
 				}
 			},
@@ -197,8 +321,8 @@ func TestUserFileController_GetUserFilesHandler(t *testing.T) {
 	for _, tt := range tests {
 		tt := tt
 		t.Run(tt.name, func(t *testing.T) {
-			r, _, _ := setupRouterUFC(t, tt.mockUFS(), false)
-			rr := doFileReq(t, r, http.MethodGet, "/users/"+tt.userID+"/files?page="+tt.page, nil, nil)
+			r, _, _ := setupRouterUFC(t, tt.mockUFS())
+			rr := doFileReq(t, r, http.MethodGet, "/users/"+tt.userID+"/files?page="+tt.page, nil, tt.headers)
 			require.Equal(t, tt.wantStatus, rr.Code)
 
 			if tt.wantErr != "" {
@@ -228,6 +352,8 @@ func TestUserFileController_CreateUserFileHandler(t *testing.T) {
 		mockUFS    func() ports.UserFileService
 		wantStatus int
 		wantErr    string
+		// wantRetryAfter - the Retry-After header of a 503
+		wantRetryAfter string
 	}{
 		{
 			name:       "401 missing Authorization",
@@ -262,6 +388,20 @@ func TestUserFileController_CreateUserFileHandler(t *testing.T) {
 			wantStatus: http.StatusUnauthorized,
 			wantErr:    "invalid token",
 		},
+		{
+			name:   "403 another user",
+			userID: okID.String(),
+			headers: func() map[string]string {
+				tok, _ := SignJWT("test-secret", uuid.NewString(), domainUser.RoleWorker, time.Hour)
+				return map[string]string{"Authorization": "Bearer " + tok}
+			}(),
+			fileField:  "file",
+			fileName:   "doc.pdf",
+			fileBytes:  []byte("pdf-bytes"),
+			mockUFS:    func() ports.UserFileService { return &FakeUserFileService{} },
+			wantStatus: http.StatusForbidden,
+			wantErr:    "only the user itself and admins are allowed",
+		},
 		{
 			name:       "400 invalid uuid",
 			userID:     "not-uuid",
@@ -295,6 +435,17 @@ func TestUserFileController_CreateUserFileHandler(t *testing.T) {
 			wantStatus: http.StatusRequestEntityTooLarge,
 			wantErr:    "file too large or empty",
 		},
+		{
+			name:       "413 body cut at the max upload size",
+			userID:     okID.String(),
+			headers:    withAuth("test-secret"),
+			fileField:  "file",
+			fileName:   "big.bin",
+			fileBytes:  bytes.Repeat([]byte{'x'}, 10<<20+maxUploadFormOverhead),
+			mockUFS:    func() ports.UserFileService { return &FakeUserFileService{} },
+			wantStatus: http.StatusRequestEntityTooLarge,
+			wantErr:    "file too large or empty",
+		},
 		{
 			name:      "500 service error",
 			userID:    okID.String(),
@@ -304,7 +455,7 @@ func TestUserFileController_CreateUserFileHandler(t *testing.T) {
 			fileBytes: []byte("content"),
 			mockUFS: func() ports.UserFileService {
 				return &FakeUserFileService{
-					CreateUserFileFunc: func(ctx context.Context, userUUID domainUser.UUID, fh *multipart.FileHeader) (*domainFile.UserFile, error) {
+					CreateUserFileFunc: func(ctx context.Context, userUUID domainUser.UUID, fh *multipart.FileHeader, tags []string, folder string) (*domainFile.UserFile, error) {
 						return nil, errors.New("db error")
 					},
 				}
@@ -312,6 +463,75 @@ func TestUserFileController_CreateUserFileHandler(t *testing.T) {
 			wantStatus: http.StatusInternalServerError,
 			wantErr:    "failed to create a file",
 		},
+		{
+			name:      "429 too many uploads",
+			userID:    okID.String(),
+			headers:   withAuth("test-secret"),
+			fileField: "file",
+			fileName:  "doc.pdf",
+			fileBytes: []byte("content"),
+			mockUFS: func() ports.UserFileService {
+				return &FakeUserFileService{
+					CreateUserFileFunc: func(ctx context.Context, userUUID domainUser.UUID, fh *multipart.FileHeader, tags []string, folder string) (*domainFile.UserFile, error) {
+						return nil, services.ErrTooManyFileOperations
+					},
+				}
+			},
+			wantStatus: http.StatusTooManyRequests,
+			wantErr:    services.ErrTooManyFileOperations.Error(),
+		},
+		{
+			name:      "415 type not allowed by the policy",
+			userID:    okID.String(),
+			headers:   withAuth("test-secret"),
+			fileField: "file",
+			fileName:  "setup.exe",
+			fileBytes: []byte("MZ"),
+			mockUFS: func() ports.UserFileService {
+				return &FakeUserFileService{
+					CreateUserFileFunc: func(ctx context.Context, userUUID domainUser.UUID, fh *multipart.FileHeader, tags []string, folder string) (*domainFile.UserFile, error) {
+						return nil, services.ErrFileTypeNotAllowed
+					},
+				}
+			},
+			wantStatus: http.StatusUnsupportedMediaType,
+			wantErr:    services.ErrFileTypeNotAllowed.Error(),
+		},
+		{
+			name:      "413 quota exceeded",
+			userID:    okID.String(),
+			headers:   withAuth("test-secret"),
+			fileField: "file",
+			fileName:  "doc.pdf",
+			fileBytes: []byte("content"),
+			mockUFS: func() ports.UserFileService {
+				return &FakeUserFileService{
+					CreateUserFileFunc: func(ctx context.Context, userUUID domainUser.UUID, fh *multipart.FileHeader, tags []string, folder string) (*domainFile.UserFile, error) {
+						return nil, services.ErrQuotaExceeded
+					},
+				}
+			},
+			wantStatus: http.StatusRequestEntityTooLarge,
+			wantErr:    services.ErrQuotaExceeded.Error(),
+		},
+		{
+			name:      "503 storage unavailable",
+			userID:    okID.String(),
+			headers:   withAuth("test-secret"),
+			fileField: "file",
+			fileName:  "doc.pdf",
+			fileBytes: []byte("content"),
+			mockUFS: func() ports.UserFileService {
+				return &FakeUserFileService{
+					CreateUserFileFunc: func(ctx context.Context, userUUID domainUser.UUID, fh *multipart.FileHeader, tags []string, folder string) (*domainFile.UserFile, error) {
+						return nil, &services.UnavailableError{Dependency: "storage", RetryAfter: 1500 * time.Millisecond}
+					},
+				}
+			},
+			wantStatus:     http.StatusServiceUnavailable,
+			wantErr:        "file storage is unavailable, retry later",
+			wantRetryAfter: "2",
+		},
 		{
 			name:      "201 success",
 			userID:    okID.String(),
@@ -321,7 +541,7 @@ func TestUserFileController_CreateUserFileHandler(t *testing.T) {
 			fileBytes: []byte("%PDF..."),
 			mockUFS: func() ports.UserFileService {
 				return &FakeUserFileService{
-					CreateUserFileFunc: func(ctx context.Context, userUUID domainUser.UUID, fh *multipart.FileHeader) (*domainFile.UserFile, error) {
+					CreateUserFileFunc: func(ctx context.Context, userUUID domainUser.UUID, fh *multipart.FileHeader, tags []string, folder string) (*domainFile.UserFile, error) {
 						return &domainFile.UserFile{}, nil
 					},
 				}
@@ -334,13 +554,14 @@ func TestUserFileController_CreateUserFileHandler(t *testing.T) {
 	for _, tt := range tests {
 		tt := tt
 		t.Run(tt.name, func(t *testing.T) {
-			r, _, secret := setupRouterUFC(t, tt.mockUFS(), true)
+			r, _, secret := setupRouterUFC(t, tt.mockUFS())
 			_ = secret // secret is "test-secret" used in withAuth
 
 			rr := doMultipartReq(t, r, http.MethodPost, "/users/"+tt.userID+"/files",
 				nil, tt.fileField, tt.fileName, tt.fileBytes, tt.headers)
 
 			require.Equal(t, tt.wantStatus, rr.Code)
+			assert.Equal(t, tt.wantRetryAfter, rr.Header().Get("Retry-After"))
 
 			if tt.wantErr != "" {
 				var resp map[string]any
@@ -394,6 +615,31 @@ func TestUserFileController_DeleteUserFilesHandler(t *testing.T) {
 			wantStatus: http.StatusUnauthorized,
 			wantErr:    "invalid token",
 		},
+		{
+			name:   "403 another user",
+			userID: okID.String(),
+			headers: func() map[string]string {
+				tok, _ := SignJWT("test-secret", uuid.NewString(), domainUser.RoleWorker, time.Hour)
+				return map[string]string{"Authorization": "Bearer " + tok}
+			}(),
+			mockUFS:    func() ports.UserFileService { return &FakeUserFileService{} },
+			wantStatus: http.StatusForbidden,
+			wantErr:    "only the user itself and admins are allowed",
+		},
+		{
+			name:   "204 the user itself",
+			userID: okID.String(),
+			headers: func() map[string]string {
+				tok, _ := SignJWT("test-secret", okID.String(), domainUser.RoleWorker, time.Hour)
+				return map[string]string{"Authorization": "Bearer " + tok}
+			}(),
+			mockUFS: func() ports.UserFileService {
+				return &FakeUserFileService{
+					DeleteUserFilesFunc: func(ctx context.Context, userUUID domainUser.UUID, tags []string) error { return nil },
+				}
+			},
+			wantStatus: http.StatusNoContent,
+		},
 		{
 			name:       "400 invalid uuid",
 			userID:     "not-uuid",
@@ -408,7 +654,7 @@ func TestUserFileController_DeleteUserFilesHandler(t *testing.T) {
 			headers: authHeader(),
 			mockUFS: func() ports.UserFileService {
 				return &FakeUserFileService{
-					DeleteUserFilesFunc: func(ctx context.Context, userUUID domainUser.UUID) error {
+					DeleteUserFilesFunc: func(ctx context.Context, userUUID domainUser.UUID, tags []string) error {
 						return errors.New("db error")
 					},
 				}
@@ -416,13 +662,41 @@ func TestUserFileController_DeleteUserFilesHandler(t *testing.T) {
 			wantStatus: http.StatusInternalServerError,
 			wantErr:    "failed to delete user files",
 		},
+		{
+			name:    "429 too many file operations",
+			userID:  okID.String(),
+			headers: authHeader(),
+			mockUFS: func() ports.UserFileService {
+				return &FakeUserFileService{
+					DeleteUserFilesFunc: func(ctx context.Context, userUUID domainUser.UUID, tags []string) error {
+						return services.ErrTooManyFileOperations
+					},
+				}
+			},
+			wantStatus: http.StatusTooManyRequests,
+			wantErr:    services.ErrTooManyFileOperations.Error(),
+		},
+		{
+			name:    "404 user not found",
+			userID:  okID.String(),
+			headers: authHeader(),
+			mockUFS: func() ports.UserFileService {
+				return &FakeUserFileService{
+					DeleteUserFilesFunc: func(ctx context.Context, userUUID domainUser.UUID, tags []string) error {
+						return domainUser.ErrNotFound
+					},
+				}
+			},
+			wantStatus: http.StatusNotFound,
+			wantErr:    "user not found",
+		},
 		{
 			name:    "204 success",
 			userID:  okID.String(),
 			headers: authHeader(),
 			mockUFS: func() ports.UserFileService {
 				return &FakeUserFileService{
-					DeleteUserFilesFunc: func(ctx context.Context, userUUID domainUser.UUID) error { return nil },
+					DeleteUserFilesFunc: func(ctx context.Context, userUUID domainUser.UUID, tags []string) error { return nil },
 				}
 			},
 			wantStatus: http.StatusNoContent,
@@ -433,7 +707,7 @@ func TestUserFileController_DeleteUserFilesHandler(t *testing.T) {
 	for _, tt := range tests {
 		tt := tt
 		t.Run(tt.name, func(t *testing.T) {
-			r, _, _ := setupRouterUFC(t, tt.mockUFS(), true)
+			r, _, _ := setupRouterUFC(t, tt.mockUFS())
 			rr := doFileReq(t, r, http.MethodDelete, "/users/"+tt.userID+"/files", nil, tt.headers)
 			require.Equal(t, tt.wantStatus, rr.Code)
 
@@ -445,3 +719,465 @@ func TestUserFileController_DeleteUserFilesHandler(t *testing.T) {
 		})
 	}
 }
+
+func TestUserFileController_GetMyFiles(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	selfID := uuid.New()
+
+	r := gin.New()
+	j := jwtSvc.New("test-secret")
+	NewUserFileController(r, &FakeUserFileService{
+		StreamUserFilesFunc: func(ctx context.Context, userUUID domainUser.UUID, p pagination.Params, tags []string, folder *string, fn func(uf *domainFile.UserFile) error) error {
+			if userUUID != selfID {
+				return errors.New("not the token subject")
+			}
+			return nil
+		},
+	}, zap.NewNop(), j, 10<<20, nil)
+
+	rr := doFileReq(t, r, http.MethodGet, RouteMeFiles, nil, nil)
+	require.Equal(t, http.StatusUnauthorized, rr.Code)
+
+	tok, err := j.GenerateToken(selfID.String(), domainUser.RoleWorker, time.Minute)
+	require.NoError(t, err)
+	rr = doFileReq(t, r, http.MethodGet, RouteMeFiles, nil, map[string]string{"Authorization": "Bearer " + tok})
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+}
+
+func setupFolderRouter(t *testing.T, ufs ports.UserFileService) (*gin.Engine, map[string]string) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	j := jwtSvc.New("test-secret")
+	NewUserFileController(r, ufs, zap.NewNop(), j, 10<<20, nil)
+
+	tok, err := j.GenerateToken(uuid.NewString(), domainUser.RoleAdmin, time.Minute)
+	require.NoError(t, err)
+
+	return r, map[string]string{"Authorization": "Bearer " + tok}
+}
+
+func TestUserFileController_FolderParams(t *testing.T) {
+	userID := uuid.New()
+	var gotFolder *string
+	var gotUploadFolder string
+	r, auth := setupFolderRouter(t, &FakeUserFileService{
+		StreamUserFilesFunc: func(ctx context.Context, userUUID domainUser.UUID, p pagination.Params, tags []string, folder *string, fn func(uf *domainFile.UserFile) error) error {
+			gotFolder = folder
+			return fn(&domainFile.UserFile{Folder: "a/b"})
+		},
+		CreateUserFileFunc: func(ctx context.Context, userUUID domainUser.UUID, fh *multipart.FileHeader, tags []string, folder string) (*domainFile.UserFile, error) {
+			gotUploadFolder = folder
+			return &domainFile.UserFile{Folder: folder}, nil
+		},
+	})
+	files := "/api/v1/users/" + userID.String() + "/files"
+
+	rr := doFileReq(t, r, http.MethodGet, files, nil, auth)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	assert.Nil(t, gotFolder, "all the folders")
+	assert.Contains(t, rr.Body.String(), `"folder":"a/b"`)
+
+	rr = doFileReq(t, r, http.MethodGet, files+"?folder=", nil, auth)
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.NotNil(t, gotFolder)
+	assert.Equal(t, "", *gotFolder, "the root")
+
+	rr = doFileReq(t, r, http.MethodGet, files+"?folder=/a/b/", nil, auth)
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "a/b", *gotFolder)
+
+	rr = doFileReq(t, r, http.MethodGet, files+"?folder=a/../b", nil, auth)
+	require.Equal(t, http.StatusBadRequest, rr.Code)
+
+	rr = doMultipartReq(t, r, http.MethodPost, files, map[string]string{"folder": "Invoices/2026/"}, "file", "doc.pdf", []byte("%PDF..."), auth)
+	require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+	assert.Equal(t, "Invoices/2026", gotUploadFolder)
+
+	rr = doMultipartReq(t, r, http.MethodPost, files, map[string]string{"folder": ".."}, "file", "doc.pdf", []byte("%PDF..."), auth)
+	require.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+func TestUserFileController_MoveUserFileHandler(t *testing.T) {
+	userID, fileID := uuid.New(), uuid.New()
+
+	tests := []struct {
+		name       string
+		fileID     string
+		body       any
+		mockErr    error
+		wantStatus int
+		wantFolder *string
+		wantName   *string
+	}{
+		{
+			name:       "200 move and rename",
+			fileID:     fileID.String(),
+			body:       map[string]any{"folder": "/a/b/", "file_name": "Report.pdf"},
+			wantStatus: http.StatusOK,
+			wantFolder: ref("a/b"),
+			wantName:   ref("Report.pdf"),
+		},
+		{
+			name:       "200 to the root",
+			fileID:     fileID.String(),
+			body:       map[string]any{"folder": ""},
+			wantStatus: http.StatusOK,
+			wantFolder: ref(""),
+		},
+		{
+			name:       "400 file_id",
+			fileID:     "42",
+			body:       map[string]any{"folder": "a"},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "400 nothing to change",
+			fileID:     fileID.String(),
+			body:       map[string]any{},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "404 file",
+			fileID:     fileID.String(),
+			body:       map[string]any{"folder": "a"},
+			mockErr:    services.ErrUserFileNotFound,
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:       "500",
+			fileID:     fileID.String(),
+			body:       map[string]any{"folder": "a"},
+			mockErr:    errors.New("db down"),
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotFolder, gotName *string
+			r, auth := setupFolderRouter(t, &FakeUserFileService{
+				MoveUserFileFunc: func(ctx context.Context, userUUID domainUser.UUID, fileUUID uuid.UUID, folder, fileName *string) (*domainFile.UserFile, error) {
+					require.Equal(t, fileID, fileUUID)
+					gotFolder, gotName = folder, fileName
+					if tt.mockErr != nil {
+						return nil, tt.mockErr
+					}
+					return &domainFile.UserFile{UUID: fileUUID}, nil
+				},
+			})
+
+			rr := doFileReq(t, r, http.MethodPatch, "/api/v1/users/"+userID.String()+"/files/"+tt.fileID, tt.body, auth)
+			require.Equal(t, tt.wantStatus, rr.Code, rr.Body.String())
+			if tt.wantStatus == http.StatusOK {
+				assert.Equal(t, tt.wantFolder, gotFolder)
+				assert.Equal(t, tt.wantName, gotName)
+			}
+		})
+	}
+}
+
+func TestUserFileController_GetUserFoldersHandler(t *testing.T) {
+	userID := uuid.New()
+
+	tests := []struct {
+		name       string
+		query      string
+		mockErr    error
+		wantStatus int
+		wantParent string
+		wantBody   string
+	}{
+		{
+			name:       "200 root",
+			wantStatus: http.StatusOK,
+			wantBody:   `{"data":[{"path":"a","files_count":2,"total_bytes":30}]}`,
+		},
+		{
+			name:       "200 parent",
+			query:      "?parent=a/",
+			wantStatus: http.StatusOK,
+			wantParent: "a",
+			wantBody:   `{"data":[{"path":"a","files_count":2,"total_bytes":30}]}`,
+		},
+		{
+			name:       "400 parent",
+			query:      "?parent=a//b",
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "404 user",
+			mockErr:    services.ErrUserNotFound,
+			wantStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, auth := setupFolderRouter(t, &FakeUserFileService{
+				ListFoldersFunc: func(ctx context.Context, userUUID domainUser.UUID, parent string) (domainFile.Folders, error) {
+					assert.Equal(t, tt.wantParent, parent)
+					if tt.mockErr != nil {
+						return nil, tt.mockErr
+					}
+					return domainFile.Folders{{Path: "a", FilesCount: 2, TotalBytes: 30}}, nil
+				},
+			})
+
+			rr := doFileReq(t, r, http.MethodGet, "/api/v1/users/"+userID.String()+"/folders"+tt.query, nil, auth)
+			require.Equal(t, tt.wantStatus, rr.Code, rr.Body.String())
+			if tt.wantBody != "" {
+				assert.JSONEq(t, tt.wantBody, rr.Body.String())
+			}
+		})
+	}
+}
+
+func TestUserFileController_MoveFolderHandler(t *testing.T) {
+	userID := uuid.New()
+
+	tests := []struct {
+		name       string
+		body       any
+		mockErr    error
+		wantStatus int
+		wantTo     string
+	}{
+		{
+			name:       "200",
+			body:       map[string]any{"from": "a/", "to": "/b/c"},
+			wantStatus: http.StatusOK,
+			wantTo:     "b/c",
+		},
+		{
+			name:       "400 into itself",
+			body:       map[string]any{"from": "a", "to": "a/b"},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "404 folder",
+			body:       map[string]any{"from": "a", "to": "b"},
+			mockErr:    services.ErrFolderNotFound,
+			wantStatus: http.StatusNotFound,
+			wantTo:     "b",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, auth := setupFolderRouter(t, &FakeUserFileService{
+				MoveFolderFunc: func(ctx context.Context, userUUID domainUser.UUID, from, to string) (int64, error) {
+					assert.Equal(t, "a", from)
+					assert.Equal(t, tt.wantTo, to)
+					return 3, tt.mockErr
+				},
+			})
+
+			rr := doFileReq(t, r, http.MethodPost, "/api/v1/users/"+userID.String()+"/folders/move", tt.body, auth)
+			require.Equal(t, tt.wantStatus, rr.Code, rr.Body.String())
+			if tt.wantStatus == http.StatusOK {
+				assert.JSONEq(t, `{"moved":3}`, rr.Body.String())
+			}
+		})
+	}
+}
+
+func TestUserFileController_GetUserFileTextHandler(t *testing.T) {
+	userID := uuid.New()
+	fileUUID := uuid.New()
+	extractedAt := time.Date(2026, 10, 15, 9, 31, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		fileID     string
+		mockErr    error
+		wantStatus int
+	}{
+		{
+			name:       "200",
+			fileID:     fileUUID.String(),
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "400 file_id",
+			fileID:     "42",
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "404 not extracted",
+			fileID:     fileUUID.String(),
+			mockErr:    services.ErrTextNotFound,
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:       "500",
+			fileID:     fileUUID.String(),
+			mockErr:    errors.New("db down"),
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, auth := setupFolderRouter(t, &FakeUserFileService{
+				GetFileTextFunc: func(ctx context.Context, userUUID domainUser.UUID, id uuid.UUID) (*domainFile.Text, error) {
+					assert.Equal(t, fileUUID, id)
+					if tt.mockErr != nil {
+						return nil, tt.mockErr
+					}
+					return &domainFile.Text{Status: domainFile.TextExtracted, Body: "invoice 42", ExtractedAt: extractedAt}, nil
+				},
+			})
+
+			rr := doFileReq(t, r, http.MethodGet, "/api/v1/users/"+userID.String()+"/files/"+tt.fileID+"/text", nil, auth)
+			require.Equal(t, tt.wantStatus, rr.Code, rr.Body.String())
+			if tt.wantStatus == http.StatusOK {
+				assert.JSONEq(t, `{"file_uuid":"`+fileUUID.String()+`","status":"extracted","text":"invoice 42","extracted_at":"2026-10-15T09:31:00Z"}`, rr.Body.String())
+			}
+		})
+	}
+}
+
+func TestUserFileController_SearchUserFilesHandler(t *testing.T) {
+	userID := uuid.New()
+
+	tests := []struct {
+		name       string
+		query      string
+		mockErr    error
+		wantStatus int
+		wantQuery  string
+		wantLimit  int
+	}{
+		{
+			name:       "200",
+			query:      "?q=invoice+-draft&limit=5",
+			wantStatus: http.StatusOK,
+			wantQuery:  "invoice -draft",
+			wantLimit:  5,
+		},
+		{
+			name:       "400 no q",
+			query:      "?limit=5",
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "404 user",
+			query:      "?q=invoice",
+			mockErr:    services.ErrUserNotFound,
+			wantStatus: http.StatusNotFound,
+			wantQuery:  "invoice",
+			wantLimit:  20,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, auth := setupFolderRouter(t, &FakeUserFileService{
+				SearchUserFilesFunc: func(ctx context.Context, userUUID domainUser.UUID, query string, limit int) (domainFile.UserFiles, error) {
+					assert.Equal(t, tt.wantQuery, query)
+					assert.Equal(t, tt.wantLimit, limit)
+					if tt.mockErr != nil {
+						return nil, tt.mockErr
+					}
+					return domainFile.UserFiles{{FileName: "invoice.pdf"}}, nil
+				},
+			})
+
+			rr := doFileReq(t, r, http.MethodGet, "/api/v1/users/"+userID.String()+"/files/search"+tt.query, nil, auth)
+			require.Equal(t, tt.wantStatus, rr.Code, rr.Body.String())
+			if tt.wantStatus == http.StatusOK {
+				var got user_file.ResponseData
+				require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &got))
+				require.Len(t, got.Data, 1)
+				assert.Equal(t, "invoice.pdf", got.Data[0].FileName)
+			}
+		})
+	}
+}
+
+func TestUserFileController_SelfOrAdmin(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	j := jwtSvc.New("test-secret")
+	NewUserFileController(r, &FakeUserFileService{
+		GetFileTextFunc: func(context.Context, domainUser.UUID, uuid.UUID) (*domainFile.Text, error) {
+			return &domainFile.Text{Status: domainFile.TextExtracted}, nil
+		},
+		SearchUserFilesFunc: func(context.Context, domainUser.UUID, string, int) (domainFile.UserFiles, error) {
+			return domainFile.UserFiles{}, nil
+		},
+		MoveUserFileFunc: func(context.Context, domainUser.UUID, uuid.UUID, *string, *string) (*domainFile.UserFile, error) {
+			return &domainFile.UserFile{}, nil
+		},
+		ListFoldersFunc: func(context.Context, domainUser.UUID, string) (domainFile.Folders, error) {
+			return domainFile.Folders{}, nil
+		},
+		MoveFolderFunc: func(context.Context, domainUser.UUID, string, string) (int64, error) {
+			return 1, nil
+		},
+	}, zap.NewNop(), j, 10<<20, nil)
+
+	owner := uuid.New()
+	headers := func(sub, role string) map[string]string {
+		tok, err := j.GenerateToken(sub, role, time.Minute)
+		require.NoError(t, err)
+		return map[string]string{"Authorization": "Bearer " + tok}
+	}
+	users := "/api/v1/users/" + owner.String()
+	routes := []struct {
+		method string
+		path   string
+		body   any
+	}{
+		{http.MethodGet, users + "/files/" + uuid.NewString() + "/text", nil},
+		{http.MethodGet, users + "/files/search?q=invoice", nil},
+		{http.MethodPatch, users + "/files/" + uuid.NewString(), map[string]any{"folder": "a"}},
+		{http.MethodGet, users + "/folders", nil},
+		{http.MethodPost, users + "/folders/move", map[string]any{"from": "a", "to": "b"}},
+	}
+
+	for _, rt := range routes {
+		t.Run(rt.method+" "+rt.path, func(t *testing.T) {
+			rr := doFileReq(t, r, rt.method, rt.path, rt.body, headers(uuid.NewString(), domainUser.RoleWorker))
+			assert.Equal(t, http.StatusForbidden, rr.Code, "another user")
+			rr = doFileReq(t, r, rt.method, rt.path, rt.body, headers(owner.String(), domainUser.RoleWorker))
+			assert.Equal(t, http.StatusOK, rr.Code, "the user itself: %s", rr.Body.String())
+			rr = doFileReq(t, r, rt.method, rt.path, rt.body, headers(uuid.NewString(), domainUser.RoleAdmin))
+			assert.Equal(t, http.StatusOK, rr.Code, "an admin")
+		})
+	}
+}
+
+func TestUserFileController_UserNotFound(t *testing.T) {
+	r, auth := setupFolderRouter(t, &FakeUserFileService{
+		MoveUserFileFunc: func(context.Context, domainUser.UUID, uuid.UUID, *string, *string) (*domainFile.UserFile, error) {
+			return nil, services.ErrUserNotFound
+		},
+		GetFileTextFunc: func(context.Context, domainUser.UUID, uuid.UUID) (*domainFile.Text, error) {
+			return nil, services.ErrUserNotFound
+		},
+		MoveFolderFunc: func(context.Context, domainUser.UUID, string, string) (int64, error) {
+			return 0, services.ErrUserNotFound
+		},
+	})
+	users := "/api/v1/users/" + uuid.NewString()
+	routes := []struct {
+		method string
+		path   string
+		body   any
+	}{
+		{http.MethodPatch, users + "/files/" + uuid.NewString(), map[string]any{"folder": "a"}},
+		{http.MethodGet, users + "/files/" + uuid.NewString() + "/text", nil},
+		{http.MethodPost, users + "/folders/move", map[string]any{"from": "a", "to": "b"}},
+	}
+
+	for _, rt := range routes {
+		t.Run(rt.method+" "+rt.path, func(t *testing.T) {
+			rr := doFileReq(t, r, rt.method, rt.path, rt.body, auth)
+			require.Equal(t, http.StatusNotFound, rr.Code)
+			assert.JSONEq(t, `{"error":"user not found","code":"`+codeUserNotFound+`"}`, rr.Body.String())
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package rest
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"user-manager-api/internal/application/ports"
+	"user-manager-api/internal/application/services"
+	"user-manager-api/internal/interface/api/rest/dto/user"
+	"user-manager-api/internal/interface/api/rest/middleware"
+	"user-manager-api/internal/interface/api/rest/validator"
+)
+
+// UserHistoryController - the prior versions of the users, admin-only(investigations)
+type UserHistoryController struct {
+	userHistoryService ports.UserHistoryService
+	logger             *zap.Logger
+}
+
+func NewUserHistoryController(
+	r *gin.Engine,
+	userHistoryService ports.UserHistoryService,
+	logger *zap.Logger,
+	tokenService ports.TokenService,
+) *UserHistoryController {
+	uhc := &UserHistoryController{
+		userHistoryService: userHistoryService,
+		logger:             logger,
+	}
+
+	r.GET(RouteUserHistory, middleware.AuthMiddleware(tokenService), middleware.RequireAdmin(), uhc.GetUserHistoryHandler)
+
+	return uhc
+}
+
+// GetUserHistoryHandler - the revisions with their changes, "?as_of=" the
+// version valid at that time. Deleted users included.
+func (uhc *UserHistoryController) GetUserHistoryHandler(c *gin.Context) {
+	ok, uuid := validator.IsUUID(c.Param("user_id"))
+	if !ok {
+		c.JSON(
+			http.StatusBadRequest,
+			gin.H{"error": "user_id must be a valid UUID"},
+		)
+		return
+	}
+	asOf, errs := validator.ParseAsOf(c.Request.URL.Query())
+	if errs != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "invalid history params",
+			"details": errs,
+		})
+		return
+	}
+
+	if asOf != nil {
+		v, err := uhc.userHistoryService.VersionAt(c.Request.Context(), uuid, *asOf)
+		if err != nil {
+			uhc.fail(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, user.ToVersionResponse(*v))
+		return
+	}
+
+	revs, err := uhc.userHistoryService.History(c.Request.Context(), uuid)
+	if err != nil {
+		uhc.fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, user.ToHistoryResponse(revs))
+}
+
+func (uhc *UserHistoryController) fail(c *gin.Context, err error) {
+	if errors.Is(err, services.ErrUserNotFound) || errors.Is(err, services.ErrNoVersionAt) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(
+		http.StatusInternalServerError,
+		gin.H{"error": "failed to get the user history"},
+	)
+	uhc.logger.Error("user history error", zap.Error(err))
+}
@@ -0,0 +1,177 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"user-manager-api/internal/application/services"
+	domain "user-manager-api/internal/domain/user"
+	jwtSvc "user-manager-api/internal/infrastructure/jwt"
+	"user-manager-api/internal/interface/api/rest/dto/user"
+)
+
+type fakeUserHistoryService struct {
+	HistoryFunc   func(ctx context.Context, userUUID domain.UUID) ([]domain.Revision, error)
+	VersionAtFunc func(ctx context.Context, userUUID domain.UUID, at time.Time) (*domain.Version, error)
+}
+
+func (f *fakeUserHistoryService) History(ctx context.Context, userUUID domain.UUID) ([]domain.Revision, error) {
+	if f.HistoryFunc == nil {
+		return nil, errors.New("not used")
+	}
+	return f.HistoryFunc(ctx, userUUID)
+}
+
+func (f *fakeUserHistoryService) VersionAt(ctx context.Context, userUUID domain.UUID, at time.Time) (*domain.Version, error) {
+	if f.VersionAtFunc == nil {
+		return nil, errors.New("not used")
+	}
+	return f.VersionAtFunc(ctx, userUUID, at)
+}
+
+func setupUserHistoryRouter(t *testing.T, hs *fakeUserHistoryService) (*gin.Engine, *jwtSvc.Service) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	j := jwtSvc.New("test-secret")
+	NewUserHistoryController(r, hs, zap.NewNop(), j)
+
+	return r, j
+}
+
+func TestUserHistoryController_GetUserHistoryHandler(t *testing.T) {
+	userID := uuid.New()
+	t0 := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	t1 := t0.Add(time.Hour)
+	first := domain.Version{User: domain.User{UUID: userID, Role: domain.RoleWorker, Lastname: "Doe"}, ValidFrom: t0, ValidTo: &t1}
+	current := domain.Version{User: domain.User{UUID: userID, Role: domain.RoleWorker, Lastname: "Smith"}, ValidFrom: t1}
+
+	type tc struct {
+		name       string
+		role       string
+		query      string
+		svc        *fakeUserHistoryService
+		wantStatus int
+		wantErr    string
+		check      func(t *testing.T, body []byte)
+	}
+
+	cases := []tc{
+		{
+			name: "200 revisions",
+			role: domain.RoleAdmin,
+			svc: &fakeUserHistoryService{HistoryFunc: func(_ context.Context, id domain.UUID) ([]domain.Revision, error) {
+				if id != userID {
+					return nil, errors.New("unexpected user")
+				}
+				return []domain.Revision{
+					{Version: first},
+					{Version: current, Changes: []domain.Change{{Field: "lastname", From: "Doe", To: "Smith"}}},
+				}, nil
+			}},
+			wantStatus: http.StatusOK,
+			check: func(t *testing.T, body []byte) {
+				var resp user.HistoryResponse
+				require.NoError(t, json.Unmarshal(body, &resp))
+				require.Len(t, resp.Data, 2)
+				assert.Equal(t, &t1, resp.Data[0].ValidTo)
+				assert.Empty(t, resp.Data[0].Changes)
+				assert.Nil(t, resp.Data[1].ValidTo)
+				assert.Equal(t, []user.Change{{Field: "lastname", From: "Doe", To: "Smith"}}, resp.Data[1].Changes)
+			},
+		},
+		{
+			name:  "200 as of",
+			role:  domain.RoleAdmin,
+			query: "?as_of=2026-01-01T00:30:00Z",
+			svc: &fakeUserHistoryService{VersionAtFunc: func(_ context.Context, _ domain.UUID, at time.Time) (*domain.Version, error) {
+				if !at.Equal(t0.Add(30 * time.Minute)) {
+					return nil, errors.New("unexpected time")
+				}
+				return &first, nil
+			}},
+			wantStatus: http.StatusOK,
+			check: func(t *testing.T, body []byte) {
+				var resp user.VersionResponse
+				require.NoError(t, json.Unmarshal(body, &resp))
+				assert.Equal(t, "Doe", resp.Data.User.Lastname)
+				assert.Equal(t, t0, resp.Data.ValidFrom)
+			},
+		},
+		{
+			name:       "400 as of",
+			role:       domain.RoleAdmin,
+			query:      "?as_of=yesterday",
+			svc:        &fakeUserHistoryService{},
+			wantStatus: http.StatusBadRequest,
+			wantErr:    "invalid history params",
+		},
+		{
+			name:       "403 worker",
+			role:       domain.RoleWorker,
+			svc:        &fakeUserHistoryService{},
+			wantStatus: http.StatusForbidden,
+			wantErr:    "admin role required",
+		},
+		{
+			name: "404 unknown user",
+			role: domain.RoleAdmin,
+			svc: &fakeUserHistoryService{HistoryFunc: func(context.Context, domain.UUID) ([]domain.Revision, error) {
+				return nil, services.ErrUserNotFound
+			}},
+			wantStatus: http.StatusNotFound,
+			wantErr:    "user not found",
+		},
+		{
+			name:  "404 not created yet",
+			role:  domain.RoleAdmin,
+			query: "?as_of=2020-01-01T00:00:00Z",
+			svc: &fakeUserHistoryService{VersionAtFunc: func(context.Context, domain.UUID, time.Time) (*domain.Version, error) {
+				return nil, services.ErrNoVersionAt
+			}},
+			wantStatus: http.StatusNotFound,
+			wantErr:    "the user did not exist at as_of",
+		},
+		{
+			name: "500",
+			role: domain.RoleAdmin,
+			svc: &fakeUserHistoryService{HistoryFunc: func(context.Context, domain.UUID) ([]domain.Revision, error) {
+				return nil, errors.New("db down")
+			}},
+			wantStatus: http.StatusInternalServerError,
+			wantErr:    "failed to get the user history",
+		},
+	}
+
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			r, j := setupUserHistoryRouter(t, tt.svc)
+			tok, err := j.GenerateToken(uuid.NewString(), tt.role, time.Minute)
+			require.NoError(t, err)
+
+			rr := doReq(t, r, http.MethodGet, RouteUsers+"/"+userID.String()+"/history"+tt.query, nil,
+				map[string]string{"Authorization": "Bearer " + tok})
+			require.Equal(t, tt.wantStatus, rr.Code, rr.Body.String())
+
+			if tt.wantErr != "" {
+				var resp map[string]any
+				require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+				assert.Equal(t, tt.wantErr, resp["error"])
+				return
+			}
+			tt.check(t, rr.Body.Bytes())
+		})
+	}
+}
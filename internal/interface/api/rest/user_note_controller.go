@@ -0,0 +1,160 @@
+package rest
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"user-manager-api/internal/application/ports"
+	"user-manager-api/internal/application/services"
+	"user-manager-api/internal/domain/pagination"
+	"user-manager-api/internal/interface/api/rest/dto/user_note"
+	"user-manager-api/internal/interface/api/rest/middleware"
+	"user-manager-api/internal/interface/api/rest/validator"
+)
+
+// UserNoteController - admin-only annotations on user accounts
+type UserNoteController struct {
+	userNoteService ports.UserNoteService
+	logger          *zap.Logger
+}
+
+func NewUserNoteController(
+	r *gin.Engine,
+	userNoteService ports.UserNoteService,
+	logger *zap.Logger,
+	tokenService ports.TokenService,
+) *UserNoteController {
+	unc := &UserNoteController{
+		userNoteService: userNoteService,
+		logger:          logger,
+	}
+
+	r.GET(RouteUserNotes, middleware.AuthMiddleware(tokenService), middleware.RequireAdmin(), unc.GetUserNotesHandler)
+	r.POST(RouteUserNotes, middleware.AuthMiddleware(tokenService), middleware.RequireAdmin(), unc.CreateUserNoteHandler)
+	r.DELETE(RouteUserNote, middleware.AuthMiddleware(tokenService), middleware.RequireAdmin(), unc.DeleteUserNoteHandler)
+
+	return unc
+}
+
+func (unc *UserNoteController) GetUserNotesHandler(c *gin.Context) {
+	ok, uuid := validator.IsUUID(c.Param("user_id"))
+	if !ok {
+		c.JSON(
+			http.StatusBadRequest,
+			gin.H{"error": "user_id must be a valid UUID"},
+		)
+		return
+	}
+	p, errs := validator.ParsePagination(c.Request.URL.Query(), validator.UserNoteSortFields)
+	if errs != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "invalid pagination params",
+			"details": errs,
+		})
+		return
+	}
+
+	notes, err := unc.userNoteService.FindNotes(c.Request.Context(), uuid, p)
+	if err != nil {
+		if errors.Is(err, services.ErrUserNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error(), "code": codeUserNotFound})
+			return
+		}
+		c.JSON(
+			http.StatusInternalServerError,
+			gin.H{"error": "failed to get notes"},
+		)
+		unc.logger.Error("FindNotes() error", zap.Error(err))
+		return
+	}
+
+	resp := user_note.ResponseData{
+		Data: user_note.ToResponseNotes(notes),
+	}
+	if len(notes) > 0 {
+		last := notes[len(notes)-1]
+		resp.NextCursor = validator.EncodeCursor(pagination.Cursor{CreatedAt: last.CreatedAt, UUID: last.UUID})
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+func (unc *UserNoteController) CreateUserNoteHandler(c *gin.Context) {
+	ok, uuid := validator.IsUUID(c.Param("user_id"))
+	if !ok {
+		c.JSON(
+			http.StatusBadRequest,
+			gin.H{"error": "user_id must be a valid UUID"},
+		)
+		return
+	}
+	ok, author := validator.IsUUID(c.GetString(middleware.CtxUserID))
+	if !ok {
+		c.JSON(
+			http.StatusUnauthorized,
+			gin.H{"error": "invalid token"},
+		)
+		return
+	}
+
+	req, ok := BindAndValidate(c, validator.ValidateNote)
+	if !ok {
+		return
+	}
+	nDomain := user_note.ToDomainNote(req)
+	nDomain.AuthorUUID = author
+
+	n, err := unc.userNoteService.CreateNote(c.Request.Context(), uuid, nDomain)
+	if err != nil {
+		if errors.Is(err, services.ErrUserNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error(), "code": codeUserNotFound})
+			return
+		}
+		c.JSON(
+			http.StatusInternalServerError,
+			gin.H{"error": "failed to create a note"},
+		)
+		unc.logger.Error("CreateNote() error", zap.Error(err))
+		return
+	}
+
+	c.JSON(http.StatusCreated, user_note.ToResponseNote(*n))
+}
+
+func (unc *UserNoteController) DeleteUserNoteHandler(c *gin.Context) {
+	ok, uuid := validator.IsUUID(c.Param("user_id"))
+	if !ok {
+		c.JSON(
+			http.StatusBadRequest,
+			gin.H{"error": "user_id must be a valid UUID"},
+		)
+		return
+	}
+	ok, noteUUID := validator.IsUUID(c.Param("note_id"))
+	if !ok {
+		c.JSON(
+			http.StatusBadRequest,
+			gin.H{"error": "note_id must be a valid UUID"},
+		)
+		return
+	}
+
+	err := unc.userNoteService.DeleteNote(c.Request.Context(), uuid, noteUUID)
+	if err != nil {
+		if errors.Is(err, services.ErrUserNotFound) || errors.Is(err, services.ErrNoteNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(
+			http.StatusInternalServerError,
+			gin.H{"error": "failed to delete a note"},
+		)
+		unc.logger.Error("DeleteNote() error", zap.Error(err))
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
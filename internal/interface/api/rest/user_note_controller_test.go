@@ -0,0 +1,193 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"user-manager-api/internal/application/services"
+	"user-manager-api/internal/domain/pagination"
+	domain "user-manager-api/internal/domain/user"
+	"user-manager-api/internal/domain/user_note"
+	jwtSvc "user-manager-api/internal/infrastructure/jwt"
+)
+
+type fakeUserNoteService struct {
+	FindNotesFunc  func(ctx context.Context, userUUID domain.UUID, p pagination.Params) (user_note.Notes, error)
+	CreateNoteFunc func(ctx context.Context, userUUID domain.UUID, n user_note.Note) (*user_note.Note, error)
+	DeleteNoteFunc func(ctx context.Context, userUUID domain.UUID, noteUUID uuid.UUID) error
+}
+
+func (f *fakeUserNoteService) FindNotes(ctx context.Context, userUUID domain.UUID, p pagination.Params) (user_note.Notes, error) {
+	if f.FindNotesFunc == nil {
+		return nil, errors.New("not used")
+	}
+	return f.FindNotesFunc(ctx, userUUID, p)
+}
+func (f *fakeUserNoteService) CreateNote(ctx context.Context, userUUID domain.UUID, n user_note.Note) (*user_note.Note, error) {
+	if f.CreateNoteFunc == nil {
+		return nil, errors.New("not used")
+	}
+	return f.CreateNoteFunc(ctx, userUUID, n)
+}
+func (f *fakeUserNoteService) DeleteNote(ctx context.Context, userUUID domain.UUID, noteUUID uuid.UUID) error {
+	if f.DeleteNoteFunc == nil {
+		return errors.New("not used")
+	}
+	return f.DeleteNoteFunc(ctx, userUUID, noteUUID)
+}
+
+func setupNoteRouter(t *testing.T, ns *fakeUserNoteService) (*gin.Engine, *jwtSvc.Service) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	j := jwtSvc.New("test-secret")
+	NewUserNoteController(r, ns, zap.NewNop(), j)
+
+	return r, j
+}
+
+func TestUserNoteController_Handlers(t *testing.T) {
+	adminID := uuid.New()
+	userID := uuid.New()
+	noteID := uuid.New()
+	notesPath := RouteApiV1 + "/users/" + userID.String() + "/notes"
+	note := &user_note.Note{UUID: noteID, AuthorUUID: adminID, Body: "called about invoices", CreatedAt: time.Now()}
+
+	adminToken := func(j *jwtSvc.Service) string {
+		tok, err := j.GenerateToken(adminID.String(), domain.RoleAdmin, time.Minute)
+		require.NoError(t, err)
+		return tok
+	}
+	workerToken := func(j *jwtSvc.Service) string {
+		tok, err := j.GenerateToken(userID.String(), domain.RoleWorker, time.Minute)
+		require.NoError(t, err)
+		return tok
+	}
+
+	type tc struct {
+		name       string
+		method     string
+		path       string
+		body       any
+		token      func(j *jwtSvc.Service) string
+		svc        *fakeUserNoteService
+		wantStatus int
+		wantErr    string
+	}
+	cases := []tc{
+		// list
+		{
+			name: "GET 200", method: http.MethodGet, path: notesPath, token: adminToken,
+			svc: &fakeUserNoteService{FindNotesFunc: func(ctx context.Context, u domain.UUID, p pagination.Params) (user_note.Notes, error) {
+				return user_note.Notes{note}, nil
+			}},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "GET 403 worker", method: http.MethodGet, path: notesPath, token: workerToken,
+			svc: &fakeUserNoteService{}, wantStatus: http.StatusForbidden, wantErr: "admin role required",
+		},
+		{
+			name: "GET 401 no token", method: http.MethodGet, path: notesPath,
+			svc: &fakeUserNoteService{}, wantStatus: http.StatusUnauthorized, wantErr: "missing Authorization header",
+		},
+		{
+			name: "GET 400 sort", method: http.MethodGet, path: notesPath + "?sort=body", token: adminToken,
+			svc: &fakeUserNoteService{}, wantStatus: http.StatusBadRequest, wantErr: "invalid pagination params",
+		},
+		{
+			name: "GET 404 user", method: http.MethodGet, path: notesPath, token: adminToken,
+			svc: &fakeUserNoteService{FindNotesFunc: func(ctx context.Context, u domain.UUID, p pagination.Params) (user_note.Notes, error) {
+				return nil, services.ErrUserNotFound
+			}},
+			wantStatus: http.StatusNotFound, wantErr: "user not found",
+		},
+
+		// create
+		{
+			name: "POST 201 author from token", method: http.MethodPost, path: notesPath, token: adminToken,
+			body: map[string]string{"body": "  called about invoices  "},
+			svc: &fakeUserNoteService{CreateNoteFunc: func(ctx context.Context, u domain.UUID, n user_note.Note) (*user_note.Note, error) {
+				if u != userID || n.AuthorUUID != adminID || n.Body != "called about invoices" {
+					return nil, errors.New("unexpected note")
+				}
+				return note, nil
+			}},
+			wantStatus: http.StatusCreated,
+		},
+		{
+			name: "POST 400 empty body", method: http.MethodPost, path: notesPath, token: adminToken,
+			body: map[string]string{"body": "   "},
+			svc:  &fakeUserNoteService{}, wantStatus: http.StatusBadRequest, wantErr: "invalid request body",
+		},
+		{
+			name: "POST 400 too long", method: http.MethodPost, path: notesPath, token: adminToken,
+			body: map[string]string{"body": strings.Repeat("a", 4001)},
+			svc:  &fakeUserNoteService{}, wantStatus: http.StatusBadRequest, wantErr: "invalid request body",
+		},
+		{
+			name: "POST 500", method: http.MethodPost, path: notesPath, token: adminToken,
+			body: map[string]string{"body": "x"},
+			svc: &fakeUserNoteService{CreateNoteFunc: func(ctx context.Context, u domain.UUID, n user_note.Note) (*user_note.Note, error) {
+				return nil, errors.New("db down")
+			}},
+			wantStatus: http.StatusInternalServerError, wantErr: "failed to create a note",
+		},
+
+		// delete
+		{
+			name: "DELETE 204", method: http.MethodDelete, path: notesPath + "/" + noteID.String(), token: adminToken,
+			svc: &fakeUserNoteService{DeleteNoteFunc: func(ctx context.Context, u domain.UUID, n uuid.UUID) error {
+				if n != noteID {
+					return errors.New("unexpected note")
+				}
+				return nil
+			}},
+			wantStatus: http.StatusNoContent,
+		},
+		{
+			name: "DELETE 400 note id", method: http.MethodDelete, path: notesPath + "/bad", token: adminToken,
+			svc: &fakeUserNoteService{}, wantStatus: http.StatusBadRequest, wantErr: "note_id must be a valid UUID",
+		},
+		{
+			name: "DELETE 404 note", method: http.MethodDelete, path: notesPath + "/" + noteID.String(), token: adminToken,
+			svc: &fakeUserNoteService{DeleteNoteFunc: func(ctx context.Context, u domain.UUID, n uuid.UUID) error {
+				return services.ErrNoteNotFound
+			}},
+			wantStatus: http.StatusNotFound, wantErr: "note not found",
+		},
+	}
+
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			r, j := setupNoteRouter(t, tt.svc)
+
+			headers := map[string]string{}
+			if tt.token != nil {
+				headers["Authorization"] = "Bearer " + tt.token(j)
+			}
+			rr := doReq(t, r, tt.method, tt.path, tt.body, headers)
+			require.Equal(t, tt.wantStatus, rr.Code, rr.Body.String())
+			if tt.wantErr == "" {
+				return
+			}
+
+			var resp map[string]any
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+			assert.Equal(t, tt.wantErr, resp["error"])
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package validator
+
+import (
+	"net/url"
+	"regexp"
+	"time"
+
+	"user-manager-api/internal/domain/audit"
+)
+
+var (
+	AuditSortFields = []string{"created_at"}
+	// auditActionRe - "<subject>.<verb>" as the audit.Action constants
+	auditActionRe = regexp.MustCompile(`^[a-z_]{1,32}\.[a-z_]{1,32}$`)
+)
+
+// ParseAuditFilter parses "actor_id", "action", "target_id", "from" and "to"
+// query params. Errors are keyed by the param name.
+func ParseAuditFilter(q url.Values) (audit.Filter, map[string]string) {
+	errs := make(map[string]string)
+	var f audit.Filter
+
+	if v, ok := lookup(q, "actor_id"); ok {
+		if ok, id := IsUUID(v); !ok {
+			errs["actor_id"] = "actor_id must be a valid UUID"
+		} else {
+			f.ActorUUID = &id
+		}
+	}
+	if v, ok := lookup(q, "target_id"); ok {
+		if ok, id := IsUUID(v); !ok {
+			errs["target_id"] = "target_id must be a valid UUID"
+		} else {
+			f.TargetUUID = &id
+		}
+	}
+	if v, ok := lookup(q, "action"); ok {
+		if !auditActionRe.MatchString(v) {
+			errs["action"] = "action must be like user.merged"
+		} else {
+			f.Action = audit.Action(v)
+		}
+	}
+
+	for key, dst := range map[string]**time.Time{"from": &f.From, "to": &f.To} {
+		if v, ok := lookup(q, key); ok {
+			t, err := parseTimeOrDate(v)
+			if err != nil {
+				errs[key] = key + " must be RFC 3339 or YYYY-MM-DD"
+				continue
+			}
+			*dst = &t
+		}
+	}
+	if f.From != nil && f.To != nil && !f.From.Before(*f.To) {
+		errs["to"] = "to must be after from"
+	}
+
+	if len(errs) > 0 {
+		return audit.Filter{}, errs
+	}
+
+	return f, nil
+}
@@ -0,0 +1,54 @@
+package validator
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"user-manager-api/internal/domain/audit"
+)
+
+func TestParseAuditFilter_Table(t *testing.T) {
+	id := uuid.MustParse("8b0c3a1e-6f0e-4a52-9d3f-3c6a1d2b4e5f")
+	at := func(t time.Time) *time.Time { return &t }
+
+	cases := []struct {
+		name     string
+		query    string
+		want     audit.Filter
+		wantErrs map[string]string
+	}{
+		{"empty query", "", audit.Filter{}, nil},
+		{"blank values are ignored", "action=&actor_id=%20", audit.Filter{}, nil},
+		{"actor and target", "actor_id=" + id.String() + "&target_id=" + id.String(), audit.Filter{ActorUUID: &id, TargetUUID: &id}, nil},
+		{"actor invalid", "actor_id=42", audit.Filter{}, map[string]string{"actor_id": "actor_id must be a valid UUID"}},
+		{"target invalid", "target_id=42", audit.Filter{}, map[string]string{"target_id": "target_id must be a valid UUID"}},
+		{"action", "action=legal_hold.placed", audit.Filter{Action: audit.ActionLegalHoldPlaced}, nil},
+		{"action without verb", "action=user", audit.Filter{}, map[string]string{"action": "action must be like user.merged"}},
+		{"action sql", "action=user.merged'--", audit.Filter{}, map[string]string{"action": "action must be like user.merged"}},
+		{
+			"dates", "from=2026-09-01&to=2026-10-01T12:00:00Z",
+			audit.Filter{
+				From: at(time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC)),
+				To:   at(time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)),
+			}, nil,
+		},
+		{"date invalid", "from=01.09.2026", audit.Filter{}, map[string]string{"from": "from must be RFC 3339 or YYYY-MM-DD"}},
+		{"dates reversed", "from=2026-10-01&to=2026-10-01", audit.Filter{}, map[string]string{"to": "to must be after from"}},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := url.ParseQuery(tt.query)
+			require.NoError(t, err)
+
+			got, errs := ParseAuditFilter(q)
+			assert.Equal(t, tt.wantErrs, errs)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
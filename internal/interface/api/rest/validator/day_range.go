@@ -0,0 +1,54 @@
+package validator
+
+import (
+	"net/url"
+	"time"
+)
+
+const (
+	// DefaultDayRange - days up to "to" when "from" is omitted
+	DefaultDayRange = 30
+	maxDayRange     = 366
+)
+
+// ParseDayRange parses the "from" and "to" YYYY-MM-DD query params, both
+// inclusive UTC days. "to" defaults to the day of now, "from" to DefaultDayRange
+// days up to "to". Errors are keyed by the param name.
+func ParseDayRange(q url.Values, now time.Time) (from, to time.Time, errs map[string]string) {
+	errs = make(map[string]string)
+
+	y, m, d := now.UTC().Date()
+	to = time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+	if v, ok := lookup(q, "to"); ok {
+		t, err := time.Parse(time.DateOnly, v)
+		if err != nil {
+			errs["to"] = "to must be YYYY-MM-DD"
+		} else {
+			to = t
+		}
+	}
+	from = to.AddDate(0, 0, 1-DefaultDayRange)
+	if v, ok := lookup(q, "from"); ok {
+		t, err := time.Parse(time.DateOnly, v)
+		if err != nil {
+			errs["from"] = "from must be YYYY-MM-DD"
+		} else {
+			from = t
+		}
+	}
+
+	if len(errs) == 0 {
+		switch {
+		case from.After(to):
+			errs["to"] = "to must not be before from"
+		case to.Sub(from) >= maxDayRange*24*time.Hour:
+			errs["from"] = "the range must not exceed 366 days"
+		}
+	}
+
+	if len(errs) > 0 {
+		return time.Time{}, time.Time{}, errs
+	}
+
+	return from, to, nil
+}
@@ -0,0 +1,49 @@
+package validator
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDayRange_Table(t *testing.T) {
+	now := time.Date(2026, 10, 16, 23, 30, 0, 0, time.FixedZone("CEST", 2*60*60))
+	day := func(m time.Month, d int) time.Time { return time.Date(2026, m, d, 0, 0, 0, 0, time.UTC) }
+
+	type tc struct {
+		name     string
+		query    string
+		wantFrom time.Time
+		wantTo   time.Time
+		wantErrs map[string]string
+	}
+	cases := []tc{
+		{"defaults: 30 UTC days up to today", "", day(9, 17), day(10, 16), nil},
+		{"to only", "to=2026-10-01", day(9, 2), day(10, 1), nil},
+		{"both", "from=2026-01-01&to=2026-01-31", day(1, 1), day(1, 31), nil},
+		{"single day", "from=2026-10-01&to=2026-10-01", day(10, 1), day(10, 1), nil},
+		{"366 days", "from=2025-10-16&to=2026-10-16", time.Date(2025, 10, 16, 0, 0, 0, 0, time.UTC), day(10, 16), nil},
+		{"too long", "from=2025-10-15&to=2026-10-16", time.Time{}, time.Time{}, map[string]string{"from": "the range must not exceed 366 days"}},
+		{"reversed", "from=2026-10-02&to=2026-10-01", time.Time{}, time.Time{}, map[string]string{"to": "to must not be before from"}},
+		{"invalid", "from=01.10.2026&to=2026-10-01T00:00:00Z", time.Time{}, time.Time{}, map[string]string{
+			"from": "from must be YYYY-MM-DD",
+			"to":   "to must be YYYY-MM-DD",
+		}},
+	}
+
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := url.ParseQuery(tt.query)
+			require.NoError(t, err)
+
+			from, to, errs := ParseDayRange(q, now)
+			assert.Equal(t, tt.wantErrs, errs)
+			assert.Equal(t, tt.wantFrom, from)
+			assert.Equal(t, tt.wantTo, to)
+		})
+	}
+}
@@ -0,0 +1,27 @@
+package validator
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+const (
+	defaultSlowQueries = 20
+	maxSlowQueries     = 100
+)
+
+var errSlowQueryLimit = errors.New("limit must be an integer 1..100")
+
+// ParseSlowQueryLimit parses the "limit" query param of the slow queries, "" - the default.
+func ParseSlowQueryLimit(v string) (int, error) {
+	if strings.TrimSpace(v) == "" {
+		return defaultSlowQueries, nil
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(v))
+	if err != nil || n < 1 || n > maxSlowQueries {
+		return 0, errSlowQueryLimit
+	}
+
+	return n, nil
+}
@@ -0,0 +1,35 @@
+package validator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSlowQueryLimit_Table(t *testing.T) {
+	cases := []struct {
+		name    string
+		in      string
+		want    int
+		wantErr bool
+	}{
+		{"not given", "", 20, false},
+		{"given", " 5 ", 5, false},
+		{"max", "100", 100, false},
+		{"zero", "0", 0, true},
+		{"too big", "101", 0, true},
+		{"not a number", "top", 0, true},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseSlowQueryLimit(tt.in)
+			if tt.wantErr {
+				assert.EqualError(t, err, "limit must be an integer 1..100")
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
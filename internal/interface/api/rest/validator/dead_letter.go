@@ -0,0 +1,46 @@
+package validator
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"user-manager-api/internal/interface/api/rest/dto/broker"
+)
+
+const (
+	defaultDeadLetterPeek = 20
+	maxDeadLetterPeek     = 100
+	maxDeadLetterIDs      = 100
+)
+
+var errDeadLetterLimit = errors.New("limit must be an integer 1..100")
+
+// ParseDeadLetterLimit parses the "limit" query param of a peek, "" - the default.
+func ParseDeadLetterLimit(v string) (int, error) {
+	if strings.TrimSpace(v) == "" {
+		return defaultDeadLetterPeek, nil
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(v))
+	if err != nil || n < 1 || n > maxDeadLetterPeek {
+		return 0, errDeadLetterLimit
+	}
+
+	return n, nil
+}
+
+func ValidateDeadLetters(r broker.DeadLettersRequest) map[string]string {
+	switch {
+	case len(r.MessageIDs) == 0:
+		return map[string]string{"message_ids": "message_ids is required"}
+	case len(r.MessageIDs) > maxDeadLetterIDs:
+		return map[string]string{"message_ids": "message_ids must be up to 100 items"}
+	}
+	for _, id := range r.MessageIDs {
+		if strings.TrimSpace(id) == "" {
+			return map[string]string{"message_ids": "message_ids must not contain empty items"}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,63 @@
+package validator
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"user-manager-api/internal/interface/api/rest/dto/broker"
+)
+
+func TestParseDeadLetterLimit_Table(t *testing.T) {
+	cases := []struct {
+		name    string
+		in      string
+		want    int
+		wantErr bool
+	}{
+		{"not given", "", 20, false},
+		{"given", " 50 ", 50, false},
+		{"max", "100", 100, false},
+		{"zero", "0", 0, true},
+		{"too big", "101", 0, true},
+		{"not a number", "all", 0, true},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseDeadLetterLimit(tt.in)
+			if tt.wantErr {
+				assert.EqualError(t, err, "limit must be an integer 1..100")
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestValidateDeadLetters_Table(t *testing.T) {
+	many := make([]string, 101)
+	for i := range many {
+		many[i] = strconv.Itoa(i)
+	}
+
+	cases := []struct {
+		name string
+		in   broker.DeadLettersRequest
+		want map[string]string
+	}{
+		{"ids", broker.DeadLettersRequest{MessageIDs: []string{"7c1f7a4e-4a35-4c4f-9d8e-2f6a3c2b1d00", "m2"}}, nil},
+		{"most ids", broker.DeadLettersRequest{MessageIDs: many[:100]}, nil},
+		{"missing", broker.DeadLettersRequest{}, map[string]string{"message_ids": "message_ids is required"}},
+		{"too many", broker.DeadLettersRequest{MessageIDs: many}, map[string]string{"message_ids": "message_ids must be up to 100 items"}},
+		{"empty item", broker.DeadLettersRequest{MessageIDs: []string{"m1", " "}}, map[string]string{"message_ids": "message_ids must not contain empty items"}},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ValidateDeadLetters(tt.in))
+		})
+	}
+}
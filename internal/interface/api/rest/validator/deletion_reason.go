@@ -0,0 +1,45 @@
+package validator
+
+import (
+	"errors"
+	"slices"
+	"strings"
+
+	"user-manager-api/internal/domain/user"
+)
+
+var (
+	errDeletionReason = errors.New("reason must be one of: " + joinReasons(user.DeletionReasons))
+	// deleteReasons - merged is set by the merge only, never given to DELETE
+	deleteReasons   = slices.DeleteFunc(slices.Clone(user.DeletionReasons), func(r user.DeletionReason) bool { return r == user.DeletionMerged })
+	errDeleteReason = errors.New("reason must be one of: " + joinReasons(deleteReasons))
+)
+
+// ParseDeletionReason parses the "reason" query param, "" - not given.
+func ParseDeletionReason(v string) (user.DeletionReason, error) {
+	r := user.DeletionReason(strings.ToLower(strings.TrimSpace(v)))
+	if r != "" && !r.Valid() {
+		return "", errDeletionReason
+	}
+
+	return r, nil
+}
+
+// ParseDeleteReason - ParseDeletionReason of the reasons DELETE may be given
+func ParseDeleteReason(v string) (user.DeletionReason, error) {
+	r, err := ParseDeletionReason(v)
+	if err != nil || r == user.DeletionMerged {
+		return "", errDeleteReason
+	}
+
+	return r, nil
+}
+
+func joinReasons(reasons []user.DeletionReason) string {
+	names := make([]string, len(reasons))
+	for i, r := range reasons {
+		names[i] = string(r)
+	}
+
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,51 @@
+package validator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"user-manager-api/internal/domain/user"
+)
+
+func TestParseDeletionReason_Table(t *testing.T) {
+	cases := []struct {
+		name    string
+		in      string
+		want    user.DeletionReason
+		wantErr string
+	}{
+		{"not given", "", "", ""},
+		{"user request", "user_request", user.DeletionUserRequest, ""},
+		{"admin action", "admin_action", user.DeletionAdminAction, ""},
+		{"gdpr normalized", " GDPR ", user.DeletionGDPR, ""},
+		{"fraud", "fraud", user.DeletionFraud, ""},
+		{"merged", "merged", user.DeletionMerged, ""},
+		{"unknown", "spam", "", "reason must be one of: user_request, admin_action, gdpr, fraud, merged"},
+	}
+
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseDeletionReason(tt.in)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParseDeleteReason(t *testing.T) {
+	got, err := ParseDeleteReason(" Fraud ")
+	assert.NoError(t, err)
+	assert.Equal(t, user.DeletionFraud, got)
+
+	// set by the merge only
+	for _, in := range []string{"merged", "spam"} {
+		_, err = ParseDeleteReason(in)
+		assert.EqualError(t, err, "reason must be one of: user_request, admin_action, gdpr, fraud", in)
+	}
+}
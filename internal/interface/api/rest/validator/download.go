@@ -0,0 +1,43 @@
+package validator
+
+import (
+	"errors"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	DispositionInline     = "inline"
+	DispositionAttachment = "attachment"
+)
+
+var (
+	errDisposition  = errors.New("disposition must be inline or attachment")
+	errDownloadName = errors.New("filename must be up to 255 characters, without '/', '\\' and control characters")
+)
+
+// ParseDisposition parses the "disposition" query param of a download, "" - by
+// the type of the file
+func ParseDisposition(v string) (string, error) {
+	switch v = strings.ToLower(strings.TrimSpace(v)); v {
+	case "", DispositionInline, DispositionAttachment:
+		return v, nil
+	default:
+		return "", errDisposition
+	}
+}
+
+// ParseDownloadName parses the "filename" query param of a download: the name
+// the browser saves it under, Unicode allowed; "" - the name of the object
+func ParseDownloadName(v string) (string, error) {
+	v = strings.TrimSpace(v)
+	if utf8.RuneCountInString(v) > maxFileNameLen || !utf8.ValidString(v) || v == "." || v == ".." {
+		return "", errDownloadName
+	}
+	if strings.ContainsFunc(v, func(r rune) bool { return r == '/' || r == '\\' || unicode.IsControl(r) }) {
+		return "", errDownloadName
+	}
+
+	return v, nil
+}
@@ -0,0 +1,65 @@
+package validator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseDisposition_Table(t *testing.T) {
+	cases := []struct {
+		name    string
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{"not given", "", "", false},
+		{"inline", "inline", "inline", false},
+		{"attachment", " Attachment ", "attachment", false},
+		{"unknown", "download", "", true},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseDisposition(tt.in)
+			if tt.wantErr {
+				assert.EqualError(t, err, "disposition must be inline or attachment")
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParseDownloadName_Table(t *testing.T) {
+	cases := []struct {
+		name    string
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{"not given", "", "", false},
+		{"unicode", " Résumé 2026.pdf ", "Résumé 2026.pdf", false},
+		{"max", strings.Repeat("я", 255), strings.Repeat("я", 255), false},
+		{"too long", strings.Repeat("я", 256), "", true},
+		{"path", "../etc/passwd", "", true},
+		{"windows path", `C:\report.pdf`, "", true},
+		{"header injection", "a.pdf\r\nSet-Cookie: x=1", "", true},
+		{"dot dot", "..", "", true},
+		{"invalid utf-8", "a\xff.pdf", "", true},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseDownloadName(tt.in)
+			if tt.wantErr {
+				assert.EqualError(t, err, "filename must be up to 255 characters, without '/', '\\' and control characters")
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,32 @@
+package validator
+
+import (
+	"errors"
+	"slices"
+	"strings"
+)
+
+// UserFields - the fields of the user request, as named in the JSON body
+var UserFields = []string{"email", "name", "middle_name", "lastname", "suffix", "birth_date", "phone", "timezone"}
+
+var errUserFields = errors.New("fields must be a comma separated list of: " + strings.Join(UserFields, ", "))
+
+// ParseUserFields parses the "fields" query param, nil - all the fields.
+func ParseUserFields(v string) ([]string, error) {
+	if strings.TrimSpace(v) == "" {
+		return nil, nil
+	}
+
+	var fields []string
+	for _, f := range strings.Split(v, ",") {
+		f = strings.ToLower(strings.TrimSpace(f))
+		if !slices.Contains(UserFields, f) {
+			return nil, errUserFields
+		}
+		if !slices.Contains(fields, f) {
+			fields = append(fields, f)
+		}
+	}
+
+	return fields, nil
+}
@@ -0,0 +1,35 @@
+package validator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseUserFields_Table(t *testing.T) {
+	cases := []struct {
+		name    string
+		in      string
+		want    []string
+		wantErr bool
+	}{
+		{"not given", "", nil, false},
+		{"one", "email", []string{"email"}, false},
+		{"normalized, deduplicated", " Name ,birth_date,name", []string{"name", "birth_date"}, false},
+		{"unknown", "email,password", nil, true},
+		{"empty item", "email,", nil, true},
+	}
+
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseUserFields(tt.in)
+			if tt.wantErr {
+				assert.EqualError(t, err, "fields must be a comma separated list of: email, name, middle_name, lastname, suffix, birth_date, phone, timezone")
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
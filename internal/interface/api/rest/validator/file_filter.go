@@ -0,0 +1,79 @@
+package validator
+
+import (
+	"net/url"
+	"regexp"
+	"strconv"
+	"time"
+
+	"user-manager-api/internal/domain/user_file"
+)
+
+// "type/subtype" or the "type/*" wildcard(RFC 6838 restricted names)
+var mimeTypeRe = regexp.MustCompile(`^[a-z0-9][a-z0-9!#$&^_.+-]*/([a-z0-9][a-z0-9!#$&^_.+-]*|\*)$`)
+
+// ParseFileFilter parses "mime_type", "min_size", "max_size", "uploaded_from",
+// "uploaded_to"(RFC 3339 or YYYY-MM-DD, UTC midnight) and "user_id" query params.
+// Errors are keyed by the param name.
+func ParseFileFilter(q url.Values) (user_file.Filter, map[string]string) {
+	errs := make(map[string]string)
+	var f user_file.Filter
+
+	if v, ok := lookup(q, "mime_type"); ok {
+		if !mimeTypeRe.MatchString(v) {
+			errs["mime_type"] = "mime_type must be type/subtype or type/*"
+		} else {
+			f.MimeType = v
+		}
+	}
+
+	for key, dst := range map[string]**uint64{"min_size": &f.MinSize, "max_size": &f.MaxSize} {
+		if v, ok := lookup(q, key); ok {
+			// 63 bits - size_bytes is BIGINT
+			n, err := strconv.ParseUint(v, 10, 63)
+			if err != nil {
+				errs[key] = key + " must be a non-negative integer(bytes)"
+				continue
+			}
+			*dst = &n
+		}
+	}
+	if f.MinSize != nil && f.MaxSize != nil && *f.MinSize > *f.MaxSize {
+		errs["max_size"] = "max_size must not be less than min_size"
+	}
+
+	for key, dst := range map[string]**time.Time{"uploaded_from": &f.UploadedFrom, "uploaded_to": &f.UploadedTo} {
+		if v, ok := lookup(q, key); ok {
+			t, err := parseTimeOrDate(v)
+			if err != nil {
+				errs[key] = key + " must be RFC 3339 or YYYY-MM-DD"
+				continue
+			}
+			*dst = &t
+		}
+	}
+	if f.UploadedFrom != nil && f.UploadedTo != nil && !f.UploadedFrom.Before(*f.UploadedTo) {
+		errs["uploaded_to"] = "uploaded_to must be after uploaded_from"
+	}
+
+	if v, ok := lookup(q, "user_id"); ok {
+		if ok, id := IsUUID(v); !ok {
+			errs["user_id"] = "user_id must be a valid UUID"
+		} else {
+			f.UserUUID = &id
+		}
+	}
+
+	if len(errs) > 0 {
+		return user_file.Filter{}, errs
+	}
+
+	return f, nil
+}
+
+func parseTimeOrDate(v string) (time.Time, error) {
+	if t, err := time.Parse(time.DateOnly, v); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.RFC3339, v)
+}
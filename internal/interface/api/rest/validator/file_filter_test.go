@@ -0,0 +1,76 @@
+package validator
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"user-manager-api/internal/domain/user_file"
+)
+
+func TestParseFileFilter_Table(t *testing.T) {
+	owner := uuid.MustParse("8b0c3a1e-6f0e-4a52-9d3f-3c6a1d2b4e5f")
+	size := func(n uint64) *uint64 { return &n }
+	at := func(t time.Time) *time.Time { return &t }
+
+	type tc struct {
+		name     string
+		query    string
+		want     user_file.Filter
+		wantErrs map[string]string
+	}
+	cases := []tc{
+		{"empty query", "", user_file.Filter{}, nil},
+		{"blank values are ignored", "mime_type=&min_size=%20", user_file.Filter{}, nil},
+
+		// mime_type
+		{"mime exact", "mime_type=image/png", user_file.Filter{MimeType: "image/png"}, nil},
+		{"mime wildcard", "mime_type=image/*", user_file.Filter{MimeType: "image/*"}, nil},
+		{"mime vendor", "mime_type=application/vnd.ms-excel", user_file.Filter{MimeType: "application/vnd.ms-excel"}, nil},
+		{"mime without subtype", "mime_type=image", user_file.Filter{}, map[string]string{"mime_type": "mime_type must be type/subtype or type/*"}},
+		{"mime any type", "mime_type=*/*", user_file.Filter{}, map[string]string{"mime_type": "mime_type must be type/subtype or type/*"}},
+		{"mime sql", "mime_type=image/png'--", user_file.Filter{}, map[string]string{"mime_type": "mime_type must be type/subtype or type/*"}},
+
+		// size
+		{"size range", "min_size=0&max_size=1048576", user_file.Filter{MinSize: size(0), MaxSize: size(1 << 20)}, nil},
+		{"size negative", "min_size=-1", user_file.Filter{}, map[string]string{"min_size": "min_size must be a non-negative integer(bytes)"}},
+		{"size overflow", "max_size=9223372036854775808", user_file.Filter{}, map[string]string{"max_size": "max_size must be a non-negative integer(bytes)"}},
+		{"size reversed", "min_size=10&max_size=5", user_file.Filter{}, map[string]string{"max_size": "max_size must not be less than min_size"}},
+
+		// uploaded
+		{
+			"uploaded dates", "uploaded_from=2026-09-01&uploaded_to=2026-10-01T12:00:00Z",
+			user_file.Filter{
+				UploadedFrom: at(time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC)),
+				UploadedTo:   at(time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)),
+			}, nil,
+		},
+		{"uploaded invalid", "uploaded_from=01.09.2026", user_file.Filter{}, map[string]string{"uploaded_from": "uploaded_from must be RFC 3339 or YYYY-MM-DD"}},
+		{"uploaded reversed", "uploaded_from=2026-10-01&uploaded_to=2026-10-01", user_file.Filter{}, map[string]string{"uploaded_to": "uploaded_to must be after uploaded_from"}},
+
+		// user
+		{"user ok", "user_id=" + owner.String(), user_file.Filter{UserUUID: &owner}, nil},
+		{"user invalid", "user_id=42", user_file.Filter{}, map[string]string{"user_id": "user_id must be a valid UUID"}},
+
+		{"several errors", "mime_type=x&user_id=42", user_file.Filter{}, map[string]string{
+			"mime_type": "mime_type must be type/subtype or type/*",
+			"user_id":   "user_id must be a valid UUID",
+		}},
+	}
+
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := url.ParseQuery(tt.query)
+			require.NoError(t, err)
+
+			got, errs := ParseFileFilter(q)
+			assert.Equal(t, tt.wantErrs, errs)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
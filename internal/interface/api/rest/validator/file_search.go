@@ -0,0 +1,43 @@
+package validator
+
+import (
+	"net/url"
+	"strconv"
+	"unicode/utf8"
+)
+
+const (
+	maxSearchQueryLen  = 200
+	defaultSearchLimit = 20
+	maxSearchLimit     = 100
+)
+
+// ParseFileSearch parses the "q"(required, the web search syntax: words,
+// "quoted phrases", -excluded, or) and "limit"(1..100, 20 by default) query
+// params. Errors are keyed by the param name.
+func ParseFileSearch(q url.Values) (string, int, map[string]string) {
+	errs := make(map[string]string)
+
+	query, ok := lookup(q, "q")
+	switch {
+	case !ok:
+		errs["q"] = "q is required"
+	case utf8.RuneCountInString(query) > maxSearchQueryLen:
+		errs["q"] = "q length must be up to 200 characters"
+	}
+
+	limit := defaultSearchLimit
+	if v, ok := lookup(q, "limit"); ok {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > maxSearchLimit {
+			errs["limit"] = "limit must be 1..100"
+		} else {
+			limit = n
+		}
+	}
+
+	if len(errs) == 0 {
+		return query, limit, nil
+	}
+	return "", 0, errs
+}
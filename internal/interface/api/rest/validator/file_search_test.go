@@ -0,0 +1,39 @@
+package validator
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseFileSearch_Table(t *testing.T) {
+	cases := []struct {
+		name      string
+		query     string
+		wantQ     string
+		wantLimit int
+		wantErrs  map[string]string
+	}{
+		{"default limit", "q=%20invoice%20march%20", "invoice march", 20, nil},
+		{"limit", "q=invoice&limit=100", "invoice", 100, nil},
+		{"no q", "limit=5", "", 0, map[string]string{"q": "q is required"}},
+		{"long q", "q=" + strings.Repeat("a", 201), "", 0, map[string]string{"q": "q length must be up to 200 characters"}},
+		{"limit zero", "q=a&limit=0", "", 0, map[string]string{"limit": "limit must be 1..100"}},
+		{"limit too big", "q=a&limit=101", "", 0, map[string]string{"limit": "limit must be 1..100"}},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			v, err := url.ParseQuery(tt.query)
+			require.NoError(t, err)
+
+			q, limit, errs := ParseFileSearch(v)
+			assert.Equal(t, tt.wantErrs, errs)
+			assert.Equal(t, tt.wantQ, q)
+			assert.Equal(t, tt.wantLimit, limit)
+		})
+	}
+}
@@ -0,0 +1,96 @@
+package validator
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"user-manager-api/internal/interface/api/rest/dto/user_file"
+)
+
+const (
+	maxFolderLen     = 255
+	maxFolderDepth   = 10
+	maxFolderNameLen = 64
+	maxFileNameLen   = 255
+)
+
+var (
+	// folderNameRe - "." and ".." never match: a name starts with a letter or a digit
+	folderNameRe = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9 _.-]*$`)
+
+	errFolderLen   = errors.New("folder length must be up to 255 characters")
+	errFolderDepth = errors.New("folder depth must be up to 10")
+	errFolderName  = errors.New("folder names must be 1–64 characters: letters, digits, ' ', '.', '-', '_', starting with a letter or a digit")
+)
+
+// ParseFolder - "a/b" of "/a/b/", "" - the root
+func ParseFolder(v string) (string, error) {
+	v = strings.Trim(strings.TrimSpace(v), "/")
+	if v == "" {
+		return "", nil
+	}
+	if len(v) > maxFolderLen {
+		return "", errFolderLen
+	}
+
+	names := strings.Split(v, "/")
+	if len(names) > maxFolderDepth {
+		return "", errFolderDepth
+	}
+	for _, name := range names {
+		if len(name) > maxFolderNameLen || !folderNameRe.MatchString(name) || strings.TrimSpace(name) != name {
+			return "", errFolderName
+		}
+	}
+
+	return v, nil
+}
+
+func ValidateFileMove(r user_file.MoveRequest) map[string]string {
+	errs := make(map[string]string)
+
+	if r.Folder == nil && r.FileName == nil {
+		errs["folder"] = "folder or file_name is required"
+	}
+	if r.Folder != nil {
+		if _, err := ParseFolder(*r.Folder); err != nil {
+			errs["folder"] = err.Error()
+		}
+	}
+	if r.FileName != nil {
+		if n := utf8.RuneCountInString(strings.TrimSpace(*r.FileName)); n == 0 || n > maxFileNameLen {
+			errs["file_name"] = "file_name length must be 1–255 characters"
+		}
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+func ValidateFolderMove(r user_file.FolderMoveRequest) map[string]string {
+	errs := make(map[string]string)
+
+	from, err := ParseFolder(r.From)
+	switch {
+	case err != nil:
+		errs["from"] = err.Error()
+	case from == "":
+		errs["from"] = "from is required, the root can not be moved"
+	}
+	to, err := ParseFolder(r.To)
+	if err != nil {
+		errs["to"] = err.Error()
+	}
+	if len(errs) == 0 && (to == from || strings.HasPrefix(to, from+"/")) {
+		errs["to"] = "to must not be from or its subfolder"
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
@@ -0,0 +1,89 @@
+package validator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"user-manager-api/internal/interface/api/rest/dto/user_file"
+)
+
+func TestParseFolder_Table(t *testing.T) {
+	cases := []struct {
+		name    string
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{"root", "", "", false},
+		{"root slash", " / ", "", false},
+		{"nested trimmed", "/Invoices/2026 Q1/", "Invoices/2026 Q1", false},
+		{"dots inside", "a/v1.2_final-draft", "a/v1.2_final-draft", false},
+		{"dot dot", "a/../b", "", true},
+		{"dot", "a/./b", "", true},
+		{"empty name", "a//b", "", true},
+		{"padded name", "a/ b", "", true},
+		{"backslash", `a\b`, "", true},
+		{"too deep", strings.Repeat("a/", 11) + "a", "", true},
+		{"name too long", strings.Repeat("a", 65), "", true},
+		{"too long", strings.Repeat(strings.Repeat("a", 60)+"/", 4) + strings.Repeat("a", 20), "", true},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseFolder(tt.in)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestValidateFileMove_Table(t *testing.T) {
+	cases := []struct {
+		name string
+		in   user_file.MoveRequest
+		want map[string]string
+	}{
+		{"move", user_file.MoveRequest{Folder: ref("a/b")}, nil},
+		{"to the root", user_file.MoveRequest{Folder: ref("")}, nil},
+		{"rename", user_file.MoveRequest{FileName: ref("report.pdf")}, nil},
+		{"nothing", user_file.MoveRequest{}, map[string]string{"folder": "folder or file_name is required"}},
+		{"bad folder", user_file.MoveRequest{Folder: ref("..")}, map[string]string{"folder": errFolderName.Error()}},
+		{"empty name", user_file.MoveRequest{FileName: ref(" ")}, map[string]string{"file_name": "file_name length must be 1–255 characters"}},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ValidateFileMove(tt.in))
+		})
+	}
+}
+
+func TestValidateFolderMove_Table(t *testing.T) {
+	cases := []struct {
+		name string
+		in   user_file.FolderMoveRequest
+		want map[string]string
+	}{
+		{"rename", user_file.FolderMoveRequest{From: "a/b", To: "a/c"}, nil},
+		{"to the root", user_file.FolderMoveRequest{From: "a/b", To: ""}, nil},
+		{"to a sibling with the prefix", user_file.FolderMoveRequest{From: "a", To: "ab/a"}, nil},
+		{"the root", user_file.FolderMoveRequest{From: "/", To: "a"}, map[string]string{"from": "from is required, the root can not be moved"}},
+		{"same", user_file.FolderMoveRequest{From: "a/", To: "/a"}, map[string]string{"to": "to must not be from or its subfolder"}},
+		{"into itself", user_file.FolderMoveRequest{From: "a", To: "a/b"}, map[string]string{"to": "to must not be from or its subfolder"}},
+		{"bad names", user_file.FolderMoveRequest{From: "..", To: "-"}, map[string]string{"from": errFolderName.Error(), "to": errFolderName.Error()}},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ValidateFolderMove(tt.in))
+		})
+	}
+}
+
+func ref(s string) *string { return &s }
@@ -0,0 +1,20 @@
+package validator
+
+import (
+	"net/url"
+	"time"
+)
+
+// ParseAsOf parses the "as_of" RFC 3339 query param, nil if not given.
+func ParseAsOf(q url.Values) (*time.Time, map[string]string) {
+	v, ok := lookup(q, "as_of")
+	if !ok {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, v)
+	if err != nil {
+		return nil, map[string]string{"as_of": "as_of must be an RFC 3339 time(e.g., 2026-01-02T15:04:05Z)"}
+	}
+
+	return &t, nil
+}
@@ -0,0 +1,25 @@
+package validator
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseAsOf(t *testing.T) {
+	at, errs := ParseAsOf(url.Values{})
+	assert.Nil(t, at)
+	assert.Nil(t, errs)
+
+	at, errs = ParseAsOf(url.Values{"as_of": {"2026-01-02T15:04:05+02:00"}})
+	assert.Nil(t, errs)
+	if assert.NotNil(t, at) {
+		assert.True(t, at.Equal(time.Date(2026, 1, 2, 13, 4, 5, 0, time.UTC)))
+	}
+
+	at, errs = ParseAsOf(url.Values{"as_of": {"2026-01-02"}})
+	assert.Nil(t, at)
+	assert.Equal(t, map[string]string{"as_of": "as_of must be an RFC 3339 time(e.g., 2026-01-02T15:04:05Z)"}, errs)
+}
@@ -0,0 +1,23 @@
+package validator
+
+import (
+	"user-manager-api/internal/interface/api/rest/dto/hook"
+)
+
+func ValidateHREvent(r hook.HREvent) map[string]string {
+	errs := make(map[string]string)
+
+	switch r.Event {
+	case hook.EventEmployeeCreated, hook.EventEmployeeUpdated:
+	default:
+		errs["event"] = "event must be one of: employee.created, employee.updated"
+	}
+	for k, v := range ValidateUser(r.Employee) {
+		errs["employee."+k] = v
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
@@ -0,0 +1,46 @@
+package validator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"user-manager-api/internal/interface/api/rest/dto/hook"
+	"user-manager-api/internal/interface/api/rest/dto/user"
+)
+
+func TestValidateHREvent_Table(t *testing.T) {
+	employee := user.Request{
+		Email:     "jane.doe@example.com",
+		Name:      "Jane",
+		Lastname:  "Doe",
+		BirthDate: "1990-01-02",
+		Phone:     "+33788888888",
+	}
+	cases := []struct {
+		name   string
+		modify func(r *hook.HREvent)
+		want   map[string]string
+	}{
+		{"created", func(*hook.HREvent) {}, nil},
+		{"updated", func(r *hook.HREvent) { r.Event = hook.EventEmployeeUpdated }, nil},
+		{"unknown event", func(r *hook.HREvent) { r.Event = "employee.hired" }, map[string]string{"event": "event must be one of: employee.created, employee.updated"}},
+		{
+			"bad employee",
+			func(r *hook.HREvent) { r.Employee.Email = ""; r.Employee.Phone = "123" },
+			map[string]string{
+				"employee.email": "email is required",
+				"employee.phone": "must be in E.164 format (e.g., +33788888888)",
+			},
+		},
+	}
+
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			r := hook.HREvent{Event: hook.EventEmployeeCreated, Employee: employee}
+			tt.modify(&r)
+			assert.Equal(t, tt.want, ValidateHREvent(r))
+		})
+	}
+}
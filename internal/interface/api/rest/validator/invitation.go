@@ -0,0 +1,58 @@
+package validator
+
+import (
+	"net/mail"
+	"strings"
+	"unicode/utf8"
+
+	"user-manager-api/internal/domain/user"
+	"user-manager-api/internal/interface/api/rest/dto/invitation"
+	dtoUser "user-manager-api/internal/interface/api/rest/dto/user"
+)
+
+func ValidateInvitation(r invitation.Request) map[string]string {
+	errs := make(map[string]string)
+
+	email := strings.ToLower(strings.TrimSpace(r.Email))
+	if email == "" {
+		errs["email"] = "email is required"
+	} else if _, err := mail.ParseAddress(email); err != nil {
+		errs["email"] = "invalid email format"
+	}
+
+	switch strings.TrimSpace(r.Role) {
+	case "", user.RoleWorker, user.RoleAdmin:
+	default:
+		errs["role"] = "role must be one of: worker, admin"
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+func ValidateInvitationAccept(r invitation.AcceptRequest) map[string]string {
+	errs := make(map[string]string)
+
+	if strings.TrimSpace(r.Password) == "" {
+		errs["password"] = "password is required"
+	} else if l := utf8.RuneCountInString(r.Password); l < minPasswordLen || l > maxPasswordLen {
+		errs["password"] = "password length must be 8–72 characters"
+	}
+
+	validateProfile(errs, dtoUser.Request{
+		Name:       r.Name,
+		MiddleName: r.MiddleName,
+		Lastname:   r.Lastname,
+		Suffix:     r.Suffix,
+		BirthDate:  r.BirthDate,
+		Phone:      r.Phone,
+		Timezone:   r.Timezone,
+	})
+
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
@@ -0,0 +1,65 @@
+package validator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"user-manager-api/internal/interface/api/rest/dto/invitation"
+)
+
+func TestValidateInvitation_Table(t *testing.T) {
+	cases := []struct {
+		name string
+		in   invitation.Request
+		want map[string]string
+	}{
+		{"default role", invitation.Request{Email: "new@example.com"}, nil},
+		{"admin", invitation.Request{Email: " New@Example.com ", Role: "admin"}, nil},
+		{"no email", invitation.Request{Role: "worker"}, map[string]string{"email": "email is required"}},
+		{"bad email", invitation.Request{Email: "nope"}, map[string]string{"email": "invalid email format"}},
+		{"bad role", invitation.Request{Email: "new@example.com", Role: "root"}, map[string]string{"role": "role must be one of: worker, admin"}},
+	}
+
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ValidateInvitation(tt.in))
+		})
+	}
+}
+
+func TestValidateInvitationAccept_Table(t *testing.T) {
+	valid := invitation.AcceptRequest{
+		Password:  "s3cret-pass",
+		Name:      "Jane",
+		Lastname:  "Doe",
+		BirthDate: "1990-01-02",
+		Phone:     "+33788888888",
+	}
+	cases := []struct {
+		name   string
+		modify func(r *invitation.AcceptRequest)
+		want   map[string]string
+	}{
+		{"valid", func(*invitation.AcceptRequest) {}, nil},
+		{"no password", func(r *invitation.AcceptRequest) { r.Password = "  " }, map[string]string{"password": "password is required"}},
+		{"short password", func(r *invitation.AcceptRequest) { r.Password = "short" }, map[string]string{"password": "password length must be 8–72 characters"}},
+		{"timezone", func(r *invitation.AcceptRequest) { r.Timezone = "Europe/Paris" }, nil},
+		{"bad timezone", func(r *invitation.AcceptRequest) { r.Timezone = "Paris" }, map[string]string{"timezone": "must be an IANA timezone (e.g., Europe/Paris)"}},
+		{"middle name and suffix", func(r *invitation.AcceptRequest) { r.MiddleName, r.Suffix = "Ann-Marie", "Jr." }, nil},
+		{"bad middle name", func(r *invitation.AcceptRequest) { r.MiddleName = "A1" }, map[string]string{"middle_name": errHumanName}},
+		{"bad suffix", func(r *invitation.AcceptRequest) { r.Suffix = "Jr!" }, map[string]string{"suffix": "allowed characters: letters, digits, '.', space (e.g., Jr., III)"}},
+		{"bad phone", func(r *invitation.AcceptRequest) { r.Phone = "123" }, map[string]string{"phone": "must be in E.164 format (e.g., +33788888888)"}},
+		{"no name", func(r *invitation.AcceptRequest) { r.Name = "" }, map[string]string{"name": "name is required"}},
+	}
+
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			r := valid
+			tt.modify(&r)
+			assert.Equal(t, tt.want, ValidateInvitationAccept(r))
+		})
+	}
+}
@@ -0,0 +1,22 @@
+package validator
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"user-manager-api/internal/interface/api/rest/dto/user"
+)
+
+const maxLegalHoldReasonLen = 500
+
+func ValidateLegalHold(r user.LegalHoldRequest) map[string]string {
+	reason := strings.TrimSpace(r.Reason)
+	switch {
+	case reason == "":
+		return map[string]string{"reason": "reason is required"}
+	case utf8.RuneCountInString(reason) > maxLegalHoldReasonLen:
+		return map[string]string{"reason": "reason must be up to 500 characters"}
+	}
+
+	return nil
+}
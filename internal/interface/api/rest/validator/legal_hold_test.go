@@ -0,0 +1,29 @@
+package validator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"user-manager-api/internal/interface/api/rest/dto/user"
+)
+
+func TestValidateLegalHold_Table(t *testing.T) {
+	cases := []struct {
+		name string
+		in   user.LegalHoldRequest
+		want map[string]string
+	}{
+		{"ok", user.LegalHoldRequest{Reason: "litigation #42"}, nil},
+		{"longest reason", user.LegalHoldRequest{Reason: strings.Repeat("ü", 500)}, nil},
+		{"missing reason", user.LegalHoldRequest{Reason: "  "}, map[string]string{"reason": "reason is required"}},
+		{"reason too long", user.LegalHoldRequest{Reason: strings.Repeat("a", 501)}, map[string]string{"reason": "reason must be up to 500 characters"}},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ValidateLegalHold(tt.in))
+		})
+	}
+}
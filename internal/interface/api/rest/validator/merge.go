@@ -0,0 +1,28 @@
+package validator
+
+import (
+	"strings"
+
+	"user-manager-api/internal/interface/api/rest/dto/user"
+)
+
+func ValidateMerge(r user.MergeRequest) map[string]string {
+	errs := make(map[string]string)
+
+	okWinner, winner := IsUUID(strings.TrimSpace(r.WinnerID))
+	if !okWinner {
+		errs["winner_id"] = "winner_id must be a valid UUID"
+	}
+	okLoser, loser := IsUUID(strings.TrimSpace(r.LoserID))
+	if !okLoser {
+		errs["loser_id"] = "loser_id must be a valid UUID"
+	}
+	if okWinner && okLoser && winner == loser {
+		errs["loser_id"] = "loser_id must differ from winner_id"
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
@@ -0,0 +1,38 @@
+package validator
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"user-manager-api/internal/interface/api/rest/dto/user"
+)
+
+func TestValidateMerge_Table(t *testing.T) {
+	a, b := uuid.NewString(), uuid.NewString()
+
+	cases := []struct {
+		name string
+		in   user.MergeRequest
+		want map[string]string
+	}{
+		{"valid", user.MergeRequest{WinnerID: a, LoserID: " " + b + " "}, nil},
+		{
+			"missing",
+			user.MergeRequest{},
+			map[string]string{
+				"winner_id": "winner_id must be a valid UUID",
+				"loser_id":  "loser_id must be a valid UUID",
+			},
+		},
+		{"same user", user.MergeRequest{WinnerID: a, LoserID: a}, map[string]string{"loser_id": "loser_id must differ from winner_id"}},
+	}
+
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ValidateMerge(tt.in))
+		})
+	}
+}
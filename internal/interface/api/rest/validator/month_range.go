@@ -0,0 +1,50 @@
+package validator
+
+import (
+	"net/url"
+	"time"
+)
+
+const maxMonthRange = 24
+
+// ParseMonthRange parses the "from" and "to" YYYY-MM query params, both
+// inclusive UTC months returned as their first day. "to" defaults to the month
+// of now, "from" to "to". Errors are keyed by the param name.
+func ParseMonthRange(q url.Values, now time.Time) (from, to time.Time, errs map[string]string) {
+	errs = make(map[string]string)
+
+	y, m, _ := now.UTC().Date()
+	to = time.Date(y, m, 1, 0, 0, 0, 0, time.UTC)
+	if v, ok := lookup(q, "to"); ok {
+		t, err := time.Parse("2006-01", v)
+		if err != nil {
+			errs["to"] = "to must be YYYY-MM"
+		} else {
+			to = t
+		}
+	}
+	from = to
+	if v, ok := lookup(q, "from"); ok {
+		t, err := time.Parse("2006-01", v)
+		if err != nil {
+			errs["from"] = "from must be YYYY-MM"
+		} else {
+			from = t
+		}
+	}
+
+	if len(errs) == 0 {
+		switch {
+		case from.After(to):
+			errs["to"] = "to must not be before from"
+		case !from.AddDate(0, maxMonthRange, 0).After(to):
+			errs["from"] = "the range must not exceed 24 months"
+		}
+	}
+
+	if len(errs) > 0 {
+		return time.Time{}, time.Time{}, errs
+	}
+
+	return from, to, nil
+}
@@ -0,0 +1,48 @@
+package validator
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMonthRange_Table(t *testing.T) {
+	now := time.Date(2026, 10, 31, 23, 30, 0, 0, time.FixedZone("EDT", -4*60*60))
+	month := func(y int, m time.Month) time.Time { return time.Date(y, m, 1, 0, 0, 0, 0, time.UTC) }
+
+	type tc struct {
+		name     string
+		query    string
+		wantFrom time.Time
+		wantTo   time.Time
+		wantErrs map[string]string
+	}
+	cases := []tc{
+		{"defaults: the UTC month of now", "", month(2026, 11), month(2026, 11), nil},
+		{"to only", "to=2026-09", month(2026, 9), month(2026, 9), nil},
+		{"both", "from=2026-01&to=2026-06", month(2026, 1), month(2026, 6), nil},
+		{"24 months", "from=2024-11&to=2026-10", month(2024, 11), month(2026, 10), nil},
+		{"too long", "from=2024-10&to=2026-10", time.Time{}, time.Time{}, map[string]string{"from": "the range must not exceed 24 months"}},
+		{"reversed", "from=2026-10&to=2026-09", time.Time{}, time.Time{}, map[string]string{"to": "to must not be before from"}},
+		{"invalid", "from=2026-10-01&to=10.2026", time.Time{}, time.Time{}, map[string]string{
+			"from": "from must be YYYY-MM",
+			"to":   "to must be YYYY-MM",
+		}},
+	}
+
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := url.ParseQuery(tt.query)
+			require.NoError(t, err)
+
+			from, to, errs := ParseMonthRange(q, now)
+			assert.Equal(t, tt.wantErrs, errs)
+			assert.Equal(t, tt.wantFrom, from)
+			assert.Equal(t, tt.wantTo, to)
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package validator
+
+import "unicode"
+
+const (
+	maxSuffixLen = 16
+
+	errHumanName = "allowed characters: letters, space, '-', apostrophes, '·', '.' after a letter"
+)
+
+// isHumanName - words of letters(with their combining marks) joined by a
+// single separator: a space, a hyphen, an apostrophe("O'Neil", "Kaʻiulani") or
+// an interpunct("Gal·la", "ジョン・スミス"). A period ends an initial or an
+// abbreviation("J. R. R."). Starts with a letter, ends with a letter or a period.
+func isHumanName(s string) bool {
+	var prev rune
+	for _, r := range s {
+		switch {
+		case unicode.IsLetter(r):
+		case unicode.Is(unicode.M, r), r == '.':
+			if !isLetterOrMark(prev) {
+				return false
+			}
+		case isNameSeparator(r):
+			if !isLetterOrMark(prev) && (prev != '.' || r != ' ') {
+				return false
+			}
+		default:
+			return false
+		}
+		prev = r
+	}
+
+	return isLetterOrMark(prev) || prev == '.'
+}
+
+// isNameSuffix - "Jr.", "III", "PhD", "2nd"
+func isNameSuffix(s string) bool {
+	for i, r := range s {
+		switch {
+		case unicode.IsLetter(r), unicode.IsDigit(r):
+		case unicode.Is(unicode.M, r), r == '.', r == ' ':
+			if i == 0 {
+				return false
+			}
+		default:
+			return false
+		}
+	}
+
+	return true
+}
+
+func isLetterOrMark(r rune) bool {
+	return unicode.IsLetter(r) || unicode.Is(unicode.M, r)
+}
+
+func isNameSeparator(r rune) bool {
+	switch r {
+	case ' ', '-', '‐', // hyphen
+		'\'', '’', '‘', // apostrophes, the modifier letter ones(ʼ, ʻ) are letters
+		'·', '‧', '・': // interpuncts: Catalan(the Greek one is the same in NFC), hyphenation point, katakana
+		return true
+	}
+	return false
+}
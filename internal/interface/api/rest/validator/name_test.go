@@ -0,0 +1,111 @@
+package validator
+
+import (
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/quick"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/text/unicode/norm"
+)
+
+func TestIsHumanName_Table(t *testing.T) {
+	cases := []struct {
+		name string
+		in   string
+		want bool
+	}{
+		{"plain", "John", true},
+		{"two words", "Mary Ann", true},
+		{"hyphen", "Jean-Luc", true},
+		{"apostrophe", "O'Neil", true},
+		{"typographic apostrophe", "D’Angelo", true},
+		{"modifier letter apostrophe", "Kaʼiulani", true},
+		{"okina", "Kaʻiulani", true},
+		{"interpunct", "Gal·la", true},
+		{"katakana middle dot", "ジョン・スミス", true},
+		{"initials", "J. R. R.", true},
+		{"combining marks", "Zoë", true},
+		{"devanagari", "देवनागरी", true},
+		{"leading separator", "-John", false},
+		{"trailing separator", "John-", false},
+		{"double separator", "Mary  Ann", false},
+		{"leading mark", "\u0308Zoe", false},
+		{"leading period", ".John", false},
+		{"digit", "J0hn", false},
+		{"symbol", "John!", false},
+		{"control", "Jo\u0000hn", false},
+		{"zero width joiner", "Jo\u200dhn", false},
+	}
+
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, isHumanName(tt.in))
+		})
+	}
+}
+
+func TestIsNameSuffix_Table(t *testing.T) {
+	for in, want := range map[string]bool{
+		"Jr.": true, "III": true, "PhD": true, "2nd": true, "Ph. D.": true,
+		".Jr": false, " Jr": false, "Jr,": false, "Jr;": false,
+	} {
+		assert.Equal(t, want, isNameSuffix(in), in)
+	}
+}
+
+// randomName - 1-4 words of letters(precomposed, decomposable and non-Latin)
+// joined by a separator
+type randomName string
+
+var (
+	nameLetters    = []rune("abzAZéÉñøßĳŁžΩжЖ文字ひらカナ한글")
+	nameSeparators = []string{" ", "-", "'", "’", "·", "・", ". "}
+)
+
+func (randomName) Generate(r *rand.Rand, _ int) reflect.Value {
+	var b strings.Builder
+	for w := r.Intn(4); w >= 0; w-- {
+		for l := r.Intn(8); l >= 0; l-- {
+			b.WriteRune(nameLetters[r.Intn(len(nameLetters))])
+		}
+		if w > 0 {
+			b.WriteString(nameSeparators[r.Intn(len(nameSeparators))])
+		}
+	}
+
+	return reflect.ValueOf(randomName(b.String()))
+}
+
+func TestIsHumanName_Properties(t *testing.T) {
+	t.Run("separated words are accepted", func(t *testing.T) {
+		assert.NoError(t, quick.Check(func(n randomName) bool {
+			return isHumanName(string(n))
+		}, nil))
+	})
+
+	t.Run("the normalization form does not matter", func(t *testing.T) {
+		assert.NoError(t, quick.Check(func(n randomName) bool {
+			return isHumanName(norm.NFD.String(string(n))) && isHumanName(norm.NFC.String(string(n)))
+		}, nil))
+	})
+
+	t.Run("a digit is rejected anywhere", func(t *testing.T) {
+		assert.NoError(t, quick.Check(func(n randomName, at uint8, d uint8) bool {
+			rs := []rune(string(n))
+			i := int(at) % (len(rs) + 1)
+			rs = append(rs[:i], append([]rune{rune('0' + d%10)}, rs[i:]...)...)
+			return !isHumanName(string(rs))
+		}, nil))
+	})
+
+	t.Run("a leading or trailing separator is rejected", func(t *testing.T) {
+		assert.NoError(t, quick.Check(func(n randomName, s uint8) bool {
+			sep := nameSeparators[int(s)%len(nameSeparators)]
+			return !isHumanName(sep+string(n)) && !isHumanName(string(n)+sep)
+		}, nil))
+	})
+}
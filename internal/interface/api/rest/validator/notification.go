@@ -0,0 +1,66 @@
+package validator
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	domain "user-manager-api/internal/domain/notification"
+	"user-manager-api/internal/interface/api/rest/dto/notification"
+)
+
+const maxWebhookURLLength = 2048
+
+func ValidateNotificationPreferences(r notification.Request) map[string]string {
+	if len(r.Preferences) == 0 {
+		return map[string]string{"preferences": "preferences are required"}
+	}
+	if len(r.Preferences) > len(domain.Channels) {
+		return map[string]string{"preferences": fmt.Sprintf("too many preferences, max %d", len(domain.Channels))}
+	}
+
+	errs := make(map[string]string)
+	seen := make(map[string]struct{}, len(r.Preferences))
+	for i, p := range r.Preferences {
+		key := fmt.Sprintf("preferences[%d]", i)
+
+		channel := domain.Channel(p.Channel)
+		if !domain.IsChannel(channel) {
+			errs[key+".channel"] = "channel must be one of: email, sms, webhook"
+		} else if _, dup := seen[p.Channel]; dup {
+			errs[key+".channel"] = "duplicate channel"
+		} else {
+			seen[p.Channel] = struct{}{}
+		}
+
+		switch domain.Mode(p.Mode) {
+		case "", domain.ModeInstant, domain.ModeDigest:
+		default:
+			errs[key+".mode"] = "mode must be one of: instant, digest"
+		}
+
+		webhookURL := strings.TrimSpace(p.WebhookURL)
+		switch {
+		case channel != domain.ChannelWebhook && webhookURL != "":
+			errs[key+".webhook_url"] = "webhook_url is for the webhook channel only"
+		case channel == domain.ChannelWebhook && p.Enabled && webhookURL == "":
+			errs[key+".webhook_url"] = "webhook_url is required to enable the webhook channel"
+		case webhookURL != "" && !isHTTPSURL(webhookURL):
+			errs[key+".webhook_url"] = "webhook_url must be an absolute https URL up to 2048 chars"
+		}
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+// isHTTPSURL - the payloads carry personal data, plain http is refused
+func isHTTPSURL(s string) bool {
+	if len(s) > maxWebhookURLLength {
+		return false
+	}
+	u, err := url.Parse(s)
+	return err == nil && u.Scheme == "https" && u.Host != "" && u.User == nil
+}
@@ -0,0 +1,126 @@
+package validator
+
+import (
+	"encoding/base64"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"user-manager-api/internal/domain/pagination"
+)
+
+const maxPage = 100000
+
+var (
+	UserSortFields     = []string{"created_at", "email", "name", "lastname"}
+	UserFileSortFields = []string{"created_at", "file_name", "size_bytes"}
+	UserNoteSortFields = []string{"created_at"}
+)
+
+// ParsePagination parses "page", "per_page", "cursor" and "sort"("-" prefix for
+// descending) query params. Errors are keyed by the param name.
+func ParsePagination(q url.Values, sortFields []string) (pagination.Params, map[string]string) {
+	errs := make(map[string]string)
+	p := pagination.Params{Page: 1}
+
+	if v, ok := lookup(q, "page"); ok {
+		n, err := strconv.Atoi(v)
+		switch {
+		case err != nil:
+			errs["page"] = "page must be an integer"
+		case n < 1 || n > maxPage:
+			errs["page"] = "page must be 1–" + strconv.Itoa(maxPage)
+		default:
+			p.Page = n
+		}
+	}
+
+	if v, ok := lookup(q, "per_page"); ok {
+		n, err := strconv.Atoi(v)
+		switch {
+		case err != nil:
+			errs["per_page"] = "per_page must be an integer"
+		case n < 1 || n > pagination.MaxPerPage:
+			errs["per_page"] = "per_page must be 1–" + strconv.Itoa(pagination.MaxPerPage)
+		default:
+			p.PerPage = n
+		}
+	}
+
+	if v, ok := lookup(q, "sort"); ok {
+		field := strings.TrimPrefix(v, "-")
+		if !contains(sortFields, field) {
+			errs["sort"] = "sort must be one of: " + strings.Join(sortFields, ", ") + " (prefix '-' for descending)"
+		} else {
+			p.Desc = strings.HasPrefix(v, "-")
+			if field != "created_at" {
+				p.Sort = field
+			}
+		}
+	}
+
+	if v, ok := lookup(q, "cursor"); ok {
+		c, err := DecodeCursor(v)
+		switch {
+		case err != nil:
+			errs["cursor"] = "invalid cursor"
+		case p.Page != 1:
+			errs["cursor"] = "cursor cannot be combined with page"
+		case p.Sort != "":
+			errs["cursor"] = "cursor supports only created_at sort"
+		default:
+			p.Cursor = c
+		}
+	}
+
+	if len(errs) > 0 {
+		return pagination.Params{}, errs
+	}
+
+	return p, nil
+}
+
+// EncodeCursor - opaque token "<unix nano>.<uuid>"
+func EncodeCursor(c pagination.Cursor) string {
+	raw := strconv.FormatInt(c.CreatedAt.UnixNano(), 10) + "." + c.UUID.String()
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func DecodeCursor(s string) (*pagination.Cursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	ts, id, ok := strings.Cut(string(b), ".")
+	if !ok {
+		return nil, strconv.ErrSyntax
+	}
+	nano, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	uid, err := uuid.Parse(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pagination.Cursor{CreatedAt: time.Unix(0, nano).UTC(), UUID: uid}, nil
+}
+
+// lookup - present and not blank, repeated params use the first value
+func lookup(q url.Values, key string) (string, bool) {
+	v := strings.TrimSpace(q.Get(key))
+	return v, v != ""
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
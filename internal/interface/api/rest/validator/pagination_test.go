@@ -0,0 +1,101 @@
+package validator
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"user-manager-api/internal/domain/pagination"
+)
+
+func TestParsePagination_Table(t *testing.T) {
+	cur := pagination.Cursor{
+		CreatedAt: time.Date(2025, 10, 3, 12, 38, 36, 123456789, time.UTC),
+		UUID:      uuid.MustParse("8b0c3a1e-6f0e-4a52-9d3f-3c6a1d2b4e5f"),
+	}
+	token := EncodeCursor(cur)
+
+	type tc struct {
+		name     string
+		query    string
+		want     pagination.Params
+		wantErrs map[string]string
+	}
+	cases := []tc{
+		// defaults
+		{"empty query", "", pagination.Params{Page: 1}, nil},
+		{"blank values are ignored", "page=&per_page=%20&sort=", pagination.Params{Page: 1}, nil},
+
+		// page
+		{"page ok", "page=7", pagination.Params{Page: 7}, nil},
+		{"page max", "page=100000", pagination.Params{Page: 100000}, nil},
+		{"page not a number", "page=abc", pagination.Params{}, map[string]string{"page": "page must be an integer"}},
+		{"page float", "page=1.5", pagination.Params{}, map[string]string{"page": "page must be an integer"}},
+		{"page zero", "page=0", pagination.Params{}, map[string]string{"page": "page must be 1–100000"}},
+		{"page negative", "page=-3", pagination.Params{}, map[string]string{"page": "page must be 1–100000"}},
+		{"page too big", "page=100001", pagination.Params{}, map[string]string{"page": "page must be 1–100000"}},
+		{"page overflow", "page=99999999999999999999", pagination.Params{}, map[string]string{"page": "page must be an integer"}},
+
+		// per_page
+		{"per_page ok", "per_page=25", pagination.Params{Page: 1, PerPage: 25}, nil},
+		{"per_page min", "per_page=1", pagination.Params{Page: 1, PerPage: 1}, nil},
+		{"per_page max", "per_page=100", pagination.Params{Page: 1, PerPage: 100}, nil},
+		{"per_page zero", "per_page=0", pagination.Params{}, map[string]string{"per_page": "per_page must be 1–100"}},
+		{"per_page too big", "per_page=101", pagination.Params{}, map[string]string{"per_page": "per_page must be 1–100"}},
+		{"per_page not a number", "per_page=x", pagination.Params{}, map[string]string{"per_page": "per_page must be an integer"}},
+
+		// sort
+		{"sort asc", "sort=email", pagination.Params{Page: 1, Sort: "email"}, nil},
+		{"sort desc", "sort=-name", pagination.Params{Page: 1, Sort: "name", Desc: true}, nil},
+		{"sort default field desc", "sort=-created_at", pagination.Params{Page: 1, Desc: true}, nil},
+		{"sort unknown field", "sort=password_hash", pagination.Params{}, map[string]string{
+			"sort": "sort must be one of: created_at, email, name, lastname (prefix '-' for descending)",
+		}},
+
+		// cursor
+		{"cursor ok", "cursor=" + token, pagination.Params{Page: 1, Cursor: &cur}, nil},
+		{"cursor desc", "cursor=" + token + "&sort=-created_at", pagination.Params{Page: 1, Cursor: &cur, Desc: true}, nil},
+		{"cursor garbage", "cursor=!!!", pagination.Params{}, map[string]string{"cursor": "invalid cursor"}},
+		{"cursor bad uuid", "cursor=MTIzLm5vdC11dWlk", pagination.Params{}, map[string]string{"cursor": "invalid cursor"}},
+		{"cursor with page", "page=2&cursor=" + token, pagination.Params{}, map[string]string{"cursor": "cursor cannot be combined with page"}},
+		{"cursor with sort", "sort=email&cursor=" + token, pagination.Params{}, map[string]string{"cursor": "cursor supports only created_at sort"}},
+
+		// combined errors
+		{"all invalid", "page=a&per_page=0&sort=x", pagination.Params{}, map[string]string{
+			"page":     "page must be an integer",
+			"per_page": "per_page must be 1–100",
+			"sort":     "sort must be one of: created_at, email, name, lastname (prefix '-' for descending)",
+		}},
+	}
+
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := url.ParseQuery(tt.query)
+			require.NoError(t, err)
+
+			got, errs := ParsePagination(q, UserSortFields)
+			assert.Equal(t, tt.wantErrs, errs)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestCursor_RoundTrip(t *testing.T) {
+	c := pagination.Cursor{CreatedAt: time.Now().UTC(), UUID: uuid.New()}
+
+	got, err := DecodeCursor(EncodeCursor(c))
+	require.NoError(t, err)
+	assert.True(t, c.CreatedAt.Equal(got.CreatedAt))
+	assert.Equal(t, c.UUID, got.UUID)
+}
+
+func TestParams_Offset(t *testing.T) {
+	assert.Equal(t, 0, pagination.Params{Page: 1}.Offset(50))
+	assert.Equal(t, 100, pagination.Params{Page: 3}.Offset(50))
+	assert.Equal(t, 0, pagination.Params{Page: 3, Cursor: &pagination.Cursor{}}.Offset(50))
+}
@@ -0,0 +1,24 @@
+package validator
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"user-manager-api/internal/interface/api/rest/dto/mode"
+)
+
+const maxReadOnlyReasonLen = 500
+
+func ValidateReadOnly(r mode.ReadOnlyRequest) map[string]string {
+	reason := strings.TrimSpace(r.Reason)
+	switch {
+	case r.Enabled == nil:
+		return map[string]string{"enabled": "enabled is required"}
+	case *r.Enabled && reason == "":
+		return map[string]string{"reason": "reason is required to enable the read-only mode"}
+	case utf8.RuneCountInString(reason) > maxReadOnlyReasonLen:
+		return map[string]string{"reason": "reason must be up to 500 characters"}
+	}
+
+	return nil
+}
@@ -0,0 +1,32 @@
+package validator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"user-manager-api/internal/interface/api/rest/dto/mode"
+)
+
+func TestValidateReadOnly_Table(t *testing.T) {
+	on, off := true, false
+	cases := []struct {
+		name string
+		in   mode.ReadOnlyRequest
+		want map[string]string
+	}{
+		{"enable", mode.ReadOnlyRequest{Enabled: &on, Reason: "db failover"}, nil},
+		{"disable without reason", mode.ReadOnlyRequest{Enabled: &off}, nil},
+		{"longest reason", mode.ReadOnlyRequest{Enabled: &on, Reason: strings.Repeat("ü", 500)}, nil},
+		{"missing enabled", mode.ReadOnlyRequest{Reason: "db failover"}, map[string]string{"enabled": "enabled is required"}},
+		{"enable without reason", mode.ReadOnlyRequest{Enabled: &on, Reason: "  "}, map[string]string{"reason": "reason is required to enable the read-only mode"}},
+		{"reason too long", mode.ReadOnlyRequest{Enabled: &off, Reason: strings.Repeat("a", 501)}, map[string]string{"reason": "reason must be up to 500 characters"}},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ValidateReadOnly(tt.in))
+		})
+	}
+}
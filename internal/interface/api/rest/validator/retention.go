@@ -0,0 +1,54 @@
+package validator
+
+import (
+	"strings"
+	"time"
+
+	"user-manager-api/internal/interface/api/rest/dto/user_file"
+)
+
+// maxRetentionDays - 100 years
+const maxRetentionDays = 36500
+
+func ValidateRetentionRule(r user_file.RetentionRuleRequest) map[string]string {
+	errs := make(map[string]string)
+
+	mimeType := strings.ToLower(strings.TrimSpace(r.MimeType))
+	tag := strings.ToLower(strings.TrimSpace(r.Tag))
+	switch {
+	case (mimeType == "") == (tag == ""):
+		errs["mime_type"] = "one of mime_type and tag is required"
+	case mimeType != "" && !mimeTypeRe.MatchString(mimeType):
+		errs["mime_type"] = "mime_type must be type/subtype or type/*"
+	case tag != "" && (len(tag) > maxTagLen || !tagRe.MatchString(tag)):
+		errs["tag"] = "tag must be 1–32 characters: a-z, 0-9, '-', '_'"
+	}
+	switch {
+	case r.Days == nil:
+		errs["days"] = "days is required"
+	case *r.Days < 1 || *r.Days > maxRetentionDays:
+		errs["days"] = "days must be from 1 to 36500"
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+func ValidateFileRetention(r user_file.FileRetentionRequest) map[string]string {
+	errs := make(map[string]string)
+
+	if r.LegalHold == nil {
+		errs["legal_hold"] = "legal_hold is required"
+	}
+	// a past date would purge the file on the next run
+	if r.RetainUntil != nil && !r.RetainUntil.After(time.Now()) {
+		errs["retain_until"] = "retain_until must be in the future"
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
@@ -0,0 +1,59 @@
+package validator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"user-manager-api/internal/interface/api/rest/dto/user_file"
+)
+
+func TestValidateRetentionRule_Table(t *testing.T) {
+	days := func(n int) *int { return &n }
+
+	cases := []struct {
+		name string
+		in   user_file.RetentionRuleRequest
+		want map[string]string
+	}{
+		{"mime type", user_file.RetentionRuleRequest{MimeType: "application/pdf", Days: days(2555)}, nil},
+		{"wildcard", user_file.RetentionRuleRequest{MimeType: " Image/* ", Days: days(30)}, nil},
+		{"tag", user_file.RetentionRuleRequest{Tag: "Payslip", Days: days(36500)}, nil},
+		{"none", user_file.RetentionRuleRequest{Days: days(30)}, map[string]string{"mime_type": "one of mime_type and tag is required"}},
+		{"both", user_file.RetentionRuleRequest{MimeType: "image/*", Tag: "tmp", Days: days(30)}, map[string]string{"mime_type": "one of mime_type and tag is required"}},
+		{"bad mime type", user_file.RetentionRuleRequest{MimeType: "*/*", Days: days(30)}, map[string]string{"mime_type": "mime_type must be type/subtype or type/*"}},
+		{"bad tag", user_file.RetentionRuleRequest{Tag: "tmp files", Days: days(30)}, map[string]string{"tag": "tag must be 1–32 characters: a-z, 0-9, '-', '_'"}},
+		{"no days", user_file.RetentionRuleRequest{Tag: "tmp"}, map[string]string{"days": "days is required"}},
+		{"zero days", user_file.RetentionRuleRequest{Tag: "tmp", Days: days(0)}, map[string]string{"days": "days must be from 1 to 36500"}},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ValidateRetentionRule(tt.in))
+		})
+	}
+}
+
+func TestValidateFileRetention_Table(t *testing.T) {
+	hold := true
+	future := time.Now().Add(time.Hour)
+	past := time.Now().Add(-time.Hour)
+
+	cases := []struct {
+		name string
+		in   user_file.FileRetentionRequest
+		want map[string]string
+	}{
+		{"hold", user_file.FileRetentionRequest{LegalHold: &hold}, nil},
+		{"override", user_file.FileRetentionRequest{RetainUntil: &future, LegalHold: &hold}, nil},
+		{"no hold", user_file.FileRetentionRequest{RetainUntil: &future}, map[string]string{"legal_hold": "legal_hold is required"}},
+		{"past", user_file.FileRetentionRequest{RetainUntil: &past, LegalHold: &hold}, map[string]string{"retain_until": "retain_until must be in the future"}},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ValidateFileRetention(tt.in))
+		})
+	}
+}
@@ -0,0 +1,45 @@
+package validator
+
+import (
+	"fmt"
+	"strings"
+
+	domain "user-manager-api/internal/domain/user"
+	"user-manager-api/internal/interface/api/rest/dto/user"
+)
+
+const maxRoleAssignments = 100
+
+func ValidateRoleAssignments(r user.RolesRequest) map[string]string {
+	if len(r.Assignments) == 0 {
+		return map[string]string{"assignments": "assignments are required"}
+	}
+	if len(r.Assignments) > maxRoleAssignments {
+		return map[string]string{"assignments": "too many assignments, max 100"}
+	}
+
+	errs := make(map[string]string)
+	seen := make(map[string]struct{}, len(r.Assignments))
+	for i, a := range r.Assignments {
+		key := fmt.Sprintf("assignments[%d]", i)
+
+		if ok, id := IsUUID(strings.TrimSpace(a.UserID)); !ok {
+			errs[key+".user_id"] = "user_id must be a valid UUID"
+		} else if _, dup := seen[id.String()]; dup {
+			errs[key+".user_id"] = "duplicate user_id"
+		} else {
+			seen[id.String()] = struct{}{}
+		}
+
+		switch strings.TrimSpace(a.Role) {
+		case domain.RoleWorker, domain.RoleAdmin:
+		default:
+			errs[key+".role"] = "role must be one of: worker, admin"
+		}
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
@@ -0,0 +1,48 @@
+package validator
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"user-manager-api/internal/interface/api/rest/dto/user"
+)
+
+func TestValidateRoleAssignments_Table(t *testing.T) {
+	id := uuid.NewString()
+	tooMany := make([]user.RoleAssignment, maxRoleAssignments+1)
+	for i := range tooMany {
+		tooMany[i] = user.RoleAssignment{UserID: uuid.NewString(), Role: "worker"}
+	}
+
+	cases := []struct {
+		name string
+		in   []user.RoleAssignment
+		want map[string]string
+	}{
+		{"valid", []user.RoleAssignment{{UserID: id, Role: "admin"}, {UserID: uuid.NewString(), Role: " worker "}}, nil},
+		{"empty", nil, map[string]string{"assignments": "assignments are required"}},
+		{"too many", tooMany, map[string]string{"assignments": "too many assignments, max 100"}},
+		{
+			"bad items",
+			[]user.RoleAssignment{{UserID: "bad", Role: "admin"}, {UserID: id, Role: "root"}},
+			map[string]string{
+				"assignments[0].user_id": "user_id must be a valid UUID",
+				"assignments[1].role":    "role must be one of: worker, admin",
+			},
+		},
+		{
+			"duplicate",
+			[]user.RoleAssignment{{UserID: id, Role: "admin"}, {UserID: id, Role: "worker"}},
+			map[string]string{"assignments[1].user_id": "duplicate user_id"},
+		},
+	}
+
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ValidateRoleAssignments(user.RolesRequest{Assignments: tt.in}))
+		})
+	}
+}
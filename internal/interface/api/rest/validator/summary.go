@@ -0,0 +1,27 @@
+package validator
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+const (
+	defaultSummaryDays = 30
+	maxSummaryDays     = 366
+)
+
+var errSummaryDays = errors.New("days must be an integer 1..366")
+
+// ParseSummaryDays parses the "days" query param of a user summary, "" - the default.
+func ParseSummaryDays(v string) (int, error) {
+	if strings.TrimSpace(v) == "" {
+		return defaultSummaryDays, nil
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(v))
+	if err != nil || n < 1 || n > maxSummaryDays {
+		return 0, errSummaryDays
+	}
+
+	return n, nil
+}
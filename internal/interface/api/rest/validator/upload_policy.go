@@ -0,0 +1,36 @@
+package validator
+
+import (
+	"slices"
+	"strings"
+
+	"user-manager-api/internal/interface/api/rest/dto/user_file"
+)
+
+const maxAllowedTypes = 50
+
+func ValidateUploadPolicy(r user_file.UploadPolicyRequest) map[string]string {
+	errs := make(map[string]string)
+
+	switch {
+	case len(r.AllowedTypes) == 0 && r.MaxFileSize == nil && r.QuotaBytes == nil:
+		errs["allowed_types"] = "one of allowed_types, max_file_size and quota_bytes is required"
+	case len(r.AllowedTypes) > maxAllowedTypes:
+		errs["allowed_types"] = "allowed_types must have at most 50 types"
+	case slices.ContainsFunc(r.AllowedTypes, func(t string) bool {
+		return !mimeTypeRe.MatchString(strings.ToLower(strings.TrimSpace(t)))
+	}):
+		errs["allowed_types"] = "allowed_types must be type/subtype or type/*"
+	}
+	if r.MaxFileSize != nil && *r.MaxFileSize < 0 {
+		errs["max_file_size"] = "max_file_size must not be negative"
+	}
+	if r.QuotaBytes != nil && *r.QuotaBytes < 0 {
+		errs["quota_bytes"] = "quota_bytes must not be negative"
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
@@ -0,0 +1,36 @@
+package validator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"user-manager-api/internal/interface/api/rest/dto/user_file"
+)
+
+func TestValidateUploadPolicy_Table(t *testing.T) {
+	size := func(n int64) *int64 { return &n }
+
+	cases := []struct {
+		name string
+		in   user_file.UploadPolicyRequest
+		want map[string]string
+	}{
+		{"types", user_file.UploadPolicyRequest{AllowedTypes: []string{"application/pdf", " Image/* "}}, nil},
+		{"max file size", user_file.UploadPolicyRequest{MaxFileSize: size(5 << 20)}, nil},
+		{"zero quota", user_file.UploadPolicyRequest{QuotaBytes: size(0)}, nil},
+		{"none", user_file.UploadPolicyRequest{}, map[string]string{"allowed_types": "one of allowed_types, max_file_size and quota_bytes is required"}},
+		{"bad type", user_file.UploadPolicyRequest{AllowedTypes: []string{"image/png", "*/*"}}, map[string]string{"allowed_types": "allowed_types must be type/subtype or type/*"}},
+		{"too many types", user_file.UploadPolicyRequest{AllowedTypes: make([]string, 51)}, map[string]string{"allowed_types": "allowed_types must have at most 50 types"}},
+		{"negative sizes", user_file.UploadPolicyRequest{MaxFileSize: size(-1), QuotaBytes: size(-1)}, map[string]string{
+			"max_file_size": "max_file_size must not be negative",
+			"quota_bytes":   "quota_bytes must not be negative",
+		}},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ValidateUploadPolicy(tt.in))
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package validator
+
+import (
+	"errors"
+	"strings"
+
+	"user-manager-api/internal/interface/api/rest/dto/usage"
+)
+
+const (
+	// maxOrgLen - the longest domain name
+	maxOrgLen = 253
+	maxSeats  = 1_000_000
+)
+
+var errOrg = errors.New("org must be a domain name")
+
+// ParseOrg parses the "org" query param: the email domain of the users or
+// one of the special organizations("anonymous", "unknown"), "" - not given.
+func ParseOrg(v string) (string, error) {
+	org := strings.ToLower(strings.TrimSpace(v))
+	if len(org) > maxOrgLen {
+		return "", errOrg
+	}
+	for _, r := range org {
+		if (r < 'a' || r > 'z') && (r < '0' || r > '9') && r != '.' && r != '-' {
+			return "", errOrg
+		}
+	}
+
+	return org, nil
+}
+
+func ValidateSeatLimit(r usage.SeatLimitRequest) map[string]string {
+	switch {
+	case r.Seats == nil:
+		return map[string]string{"seats": "seats is required"}
+	case *r.Seats < 0 || *r.Seats > maxSeats:
+		return map[string]string{"seats": "seats must be from 0 to 1000000"}
+	}
+
+	return nil
+}
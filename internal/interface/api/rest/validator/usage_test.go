@@ -0,0 +1,65 @@
+package validator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"user-manager-api/internal/interface/api/rest/dto/usage"
+)
+
+func TestParseOrg_Table(t *testing.T) {
+	cases := []struct {
+		name    string
+		in      string
+		want    string
+		wantErr string
+	}{
+		{"not given", "", "", ""},
+		{"domain", "acme.com", "acme.com", ""},
+		{"normalized", " Mail.ACME-corp.io ", "mail.acme-corp.io", ""},
+		{"anonymous", "anonymous", "anonymous", ""},
+		{"longest", strings.Repeat("a", 253), strings.Repeat("a", 253), ""},
+		{"too long", strings.Repeat("a", 254), "", "org must be a domain name"},
+		{"email", "bob@acme.com", "", "org must be a domain name"},
+		{"wildcard", "%", "", "org must be a domain name"},
+	}
+
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseOrg(tt.in)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestValidateSeatLimit_Table(t *testing.T) {
+	seats := func(n int64) *int64 { return &n }
+
+	cases := []struct {
+		name string
+		req  usage.SeatLimitRequest
+		want map[string]string
+	}{
+		{"ok", usage.SeatLimitRequest{Seats: seats(25)}, nil},
+		{"zero", usage.SeatLimitRequest{Seats: seats(0)}, nil},
+		{"max", usage.SeatLimitRequest{Seats: seats(1_000_000)}, nil},
+		{"missing", usage.SeatLimitRequest{}, map[string]string{"seats": "seats is required"}},
+		{"negative", usage.SeatLimitRequest{Seats: seats(-1)}, map[string]string{"seats": "seats must be from 0 to 1000000"}},
+		{"too many", usage.SeatLimitRequest{Seats: seats(1_000_001)}, map[string]string{"seats": "seats must be from 0 to 1000000"}},
+	}
+
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ValidateSeatLimit(tt.req))
+		})
+	}
+}
@@ -4,40 +4,34 @@ import (
 	"errors"
 	"net/mail"
 	"regexp"
-	"strconv"
 	"strings"
 	"time"
-	"unicode"
 	"unicode/utf8"
 	"user-manager-api/internal/interface/api/rest/dto/auth"
 
 	"github.com/google/uuid"
 
+	domainUser "user-manager-api/internal/domain/user"
 	"user-manager-api/internal/interface/api/rest/dto/user"
+	"user-manager-api/internal/interface/api/rest/dto/user_note"
 )
 
 const (
 	minPasswordLen = 8
 	maxPasswordLen = 72 // bcrypt safe
+
+	maxTags   = 10
+	maxTagLen = 32
+
+	maxNoteLen = 4000
 )
 
 var (
 	e164Re = regexp.MustCompile(`^\+[1-9]\d{7,14}$`)
+	tagRe  = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+	otpRe  = regexp.MustCompile(`^\d{4,10}$`)
 )
 
-func ValidatePage(page string) (int, error) {
-	p := 1
-	if page != "" {
-		p, err := strconv.Atoi(page)
-		if err != nil && p < 0 {
-			return p, errors.New("invalid page")
-		}
-		return p, nil
-	}
-
-	return p, nil
-}
-
 func IsUUID(s string) (bool, uuid.UUID) {
 	id, err := uuid.Parse(s)
 	return err == nil, id
@@ -48,10 +42,6 @@ func ValidateUser(r user.Request) map[string]string {
 
 	// Normalize
 	email := strings.ToLower(strings.TrimSpace(r.Email))
-	name := strings.TrimSpace(r.Name)
-	last := strings.TrimSpace(r.Lastname)
-	bdate := strings.TrimSpace(r.BirthDate)
-	phone := strings.TrimSpace(r.Phone)
 
 	// email (required + format)
 	if email == "" {
@@ -60,13 +50,42 @@ func ValidateUser(r user.Request) map[string]string {
 		errs["email"] = "invalid email format"
 	}
 
+	validateProfile(errs, r)
+
+	if len(errs) == 0 {
+		return nil
+	}
+
+	return errs
+}
+
+// validateProfile checks the profile fields of r(the email aside), shared by
+// the signup and the invitation accept. The names are checked as they are
+// stored: trimmed and NFC normalized. The minimum age is the domain policy of
+// the services, it is not checked here.
+func validateProfile(errs map[string]string, r user.Request) {
+	name := domainUser.NormalizeName(r.Name)
+	middle := domainUser.NormalizeName(r.MiddleName)
+	last := domainUser.NormalizeName(r.Lastname)
+	suffix := domainUser.NormalizeName(r.Suffix)
+	bdate := strings.TrimSpace(r.BirthDate)
+	phone := strings.TrimSpace(r.Phone)
+	tz := strings.TrimSpace(r.Timezone)
+
 	// name (required + length + allowed chars)
 	if name == "" {
 		errs["name"] = "name is required"
 	} else if l := utf8.RuneCountInString(name); l < 2 || l > 64 {
 		errs["name"] = "name length must be 2–64 characters"
 	} else if !isHumanName(name) {
-		errs["name"] = "allowed characters: letters, space, '-', '''"
+		errs["name"] = errHumanName
+	}
+
+	// middle_name (optional + length + allowed chars)
+	if utf8.RuneCountInString(middle) > 64 {
+		errs["middle_name"] = "middle_name length must be up to 64 characters"
+	} else if middle != "" && !isHumanName(middle) {
+		errs["middle_name"] = errHumanName
 	}
 
 	// lastname (required + length + allowed chars)
@@ -75,16 +94,28 @@ func ValidateUser(r user.Request) map[string]string {
 	} else if l := utf8.RuneCountInString(last); l < 2 || l > 64 {
 		errs["lastname"] = "lastname length must be 2–64 characters"
 	} else if !isHumanName(last) {
-		errs["lastname"] = "allowed characters: letters, space, '-', '''"
+		errs["lastname"] = errHumanName
+	}
+
+	// suffix (optional + length + allowed chars)
+	if utf8.RuneCountInString(suffix) > maxSuffixLen {
+		errs["suffix"] = "suffix length must be up to 16 characters"
+	} else if suffix != "" && !isNameSuffix(suffix) {
+		errs["suffix"] = "allowed characters: letters, digits, '.', space (e.g., Jr., III)"
+	}
+
+	// timezone (optional + IANA name)
+	if tz != "" {
+		if _, err := time.LoadLocation(tz); err != nil || tz == "Local" {
+			errs["timezone"] = "must be an IANA timezone (e.g., Europe/Paris)"
+		}
 	}
 
-	// birth_date (required + format + 18+)
+	// birth_date (required + format)
 	if bdate == "" {
 		errs["birth_date"] = "birth_date is required"
-	} else if dob, err := time.Parse("2006-01-02", bdate); err != nil {
+	} else if _, err := time.Parse("2006-01-02", bdate); err != nil {
 		errs["birth_date"] = "must be YYYY-MM-DD"
-	} else if dob.After(time.Now().UTC().AddDate(-18, 0, 0)) {
-		errs["birth_date"] = "user must be 18+ years old"
 	}
 
 	// phone (required + E.164)
@@ -93,22 +124,6 @@ func ValidateUser(r user.Request) map[string]string {
 	} else if !e164Re.MatchString(phone) {
 		errs["phone"] = "must be in E.164 format (e.g., +33788888888)"
 	}
-
-	if len(errs) == 0 {
-		return nil
-	}
-
-	return errs
-}
-
-func isHumanName(s string) bool {
-	for _, r := range s {
-		if unicode.IsLetter(r) || r == ' ' || r == '-' || r == '\'' {
-			continue
-		}
-		return false
-	}
-	return true
 }
 
 func ValidateLogin(r auth.LoginRequest) map[string]string {
@@ -137,3 +152,96 @@ func ValidateLogin(r auth.LoginRequest) map[string]string {
 	}
 	return errs
 }
+
+func ValidatePasswordChange(r auth.PasswordChangeRequest) map[string]string {
+	errs := ValidateLogin(auth.LoginRequest{Email: r.Email, Password: r.Password})
+	if errs == nil {
+		errs = make(map[string]string)
+	}
+
+	if strings.TrimSpace(r.NewPassword) == "" {
+		errs["new_password"] = "new_password is required"
+	} else if l := utf8.RuneCountInString(r.NewPassword); l < minPasswordLen || l > maxPasswordLen {
+		errs["new_password"] = "password length must be 8–72 characters"
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+func ValidateNote(r user_note.Request) map[string]string {
+	body := strings.TrimSpace(r.Body)
+	if body == "" {
+		return map[string]string{"body": "body is required"}
+	}
+	if utf8.RuneCountInString(body) > maxNoteLen {
+		return map[string]string{"body": "body length must be 1–4000 characters"}
+	}
+
+	return nil
+}
+
+func ValidateEmailConfirm(r auth.EmailConfirmRequest) map[string]string {
+	if strings.TrimSpace(r.Token) == "" {
+		return map[string]string{"token": "token is required"}
+	}
+
+	return nil
+}
+
+func ValidateOTPRequest(r auth.OTPRequest) map[string]string {
+	if !e164Re.MatchString(strings.TrimSpace(r.Phone)) {
+		return map[string]string{"phone": "must be in E.164 format (e.g., +33788888888)"}
+	}
+
+	return nil
+}
+
+func ValidateOTPVerify(r auth.OTPVerifyRequest) map[string]string {
+	errs := make(map[string]string)
+
+	if !e164Re.MatchString(strings.TrimSpace(r.Phone)) {
+		errs["phone"] = "must be in E.164 format (e.g., +33788888888)"
+	}
+	if !otpRe.MatchString(strings.TrimSpace(r.Code)) {
+		errs["code"] = "code must be 4–10 digits"
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+// ValidateTags accepts repeated values and comma separated lists
+// ("?tag=a&tag=b" or "?tag=a,b"), returns lowercased unique tags.
+func ValidateTags(raw []string) ([]string, error) {
+	var tags []string
+	seen := make(map[string]struct{})
+	for _, r := range raw {
+		for _, t := range strings.Split(r, ",") {
+			t = strings.ToLower(strings.TrimSpace(t))
+			if t == "" {
+				continue
+			}
+			if len(t) > maxTagLen {
+				return nil, errors.New("tag length must be 1–32 characters")
+			}
+			if !tagRe.MatchString(t) {
+				return nil, errors.New("tag allowed characters: a-z, 0-9, '-', '_'")
+			}
+			if _, ok := seen[t]; ok {
+				continue
+			}
+			seen[t] = struct{}{}
+			tags = append(tags, t)
+		}
+	}
+	if len(tags) > maxTags {
+		return nil, errors.New("too many tags, max 10")
+	}
+
+	return tags, nil
+}
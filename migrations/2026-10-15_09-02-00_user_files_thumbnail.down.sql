@@ -0,0 +1,2 @@
+ALTER TABLE user_files
+    DROP COLUMN IF EXISTS thumbnail_url;
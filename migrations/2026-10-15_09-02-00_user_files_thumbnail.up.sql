@@ -0,0 +1,2 @@
+ALTER TABLE user_files
+    ADD COLUMN IF NOT EXISTS thumbnail_url TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS user_files_tags_gin_idx;
+ALTER TABLE user_files
+    DROP COLUMN IF EXISTS tags;
@@ -0,0 +1,5 @@
+ALTER TABLE user_files
+    ADD COLUMN IF NOT EXISTS tags TEXT[] NOT NULL DEFAULT '{}';
+
+CREATE INDEX IF NOT EXISTS user_files_tags_gin_idx
+    ON user_files USING GIN (tags);
@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS audit_log
+(
+    id          BIGSERIAL PRIMARY KEY,
+    actor_uuid  UUID        NOT NULL,
+    action      TEXT        NOT NULL,
+    target_uuid UUID,
+    details     JSONB       NOT NULL DEFAULT '{}',
+
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS audit_log_actor_created_idx
+    ON audit_log (actor_uuid, created_at);
+
+CREATE INDEX IF NOT EXISTS audit_log_target_created_idx
+    ON audit_log (target_uuid, created_at);
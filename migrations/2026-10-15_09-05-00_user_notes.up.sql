@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS user_notes
+(
+    id          SERIAL PRIMARY KEY,
+    uuid        UUID        NOT NULL DEFAULT gen_random_uuid(),
+    user_id     INTEGER     NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    author_uuid UUID        NOT NULL,
+    body        TEXT        NOT NULL CHECK (length(body) BETWEEN 1 AND 4000),
+
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS user_notes_uuid_unique_idx
+    ON user_notes (uuid);
+
+CREATE INDEX IF NOT EXISTS user_notes_user_created_idx
+    ON user_notes (user_id, created_at, uuid);
@@ -0,0 +1,13 @@
+-- pending email change, at most one per user
+CREATE TABLE IF NOT EXISTS user_email_changes
+(
+    user_id    INTEGER PRIMARY KEY REFERENCES users (id) ON DELETE CASCADE,
+    new_email  TEXT        NOT NULL,
+    token_hash BYTEA       NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS user_email_changes_token_hash_unique_idx
+    ON user_email_changes (token_hash);
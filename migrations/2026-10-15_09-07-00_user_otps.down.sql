@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS users_phone_active_idx;
+DROP TABLE IF EXISTS user_otps;
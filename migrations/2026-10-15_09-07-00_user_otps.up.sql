@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS user_otps
+(
+    user_id    INTEGER PRIMARY KEY REFERENCES users (id) ON DELETE CASCADE,
+    code_hash  BYTEA       NOT NULL,
+    attempts   INTEGER     NOT NULL DEFAULT 0,
+    expires_at TIMESTAMPTZ NOT NULL,
+
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- phone lookups for OTP login
+CREATE INDEX IF NOT EXISTS users_phone_active_idx
+    ON users (phone)
+    WHERE deleted_at IS NULL;
@@ -0,0 +1,3 @@
+ALTER TABLE users
+    DROP COLUMN IF EXISTS tokens_valid_after,
+    DROP COLUMN IF EXISTS password_reset_required;
@@ -0,0 +1,4 @@
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS password_reset_required BOOLEAN NOT NULL DEFAULT false,
+    -- tokens issued before are revoked(force reset, password change)
+    ADD COLUMN IF NOT EXISTS tokens_valid_after      TIMESTAMPTZ;
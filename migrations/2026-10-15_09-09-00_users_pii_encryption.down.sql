@@ -0,0 +1,6 @@
+-- encrypted values can not be converted back, decrypt them before rolling back
+DROP INDEX IF EXISTS users_phone_hash_active_idx;
+
+ALTER TABLE users
+    DROP COLUMN IF EXISTS phone_hash,
+    ALTER COLUMN birth_date TYPE DATE USING birth_date::date;
@@ -0,0 +1,10 @@
+-- birth_date and phone hold "enc:v1:..." envelopes written by the application,
+-- existing plain values are encrypted by the "reencrypt-pii" job
+ALTER TABLE users
+    ALTER COLUMN birth_date TYPE TEXT USING to_char(birth_date, 'YYYY-MM-DD'),
+    -- HMAC of the phone for the OTP login lookups
+    ADD COLUMN IF NOT EXISTS phone_hash BYTEA;
+
+CREATE INDEX IF NOT EXISTS users_phone_hash_active_idx
+    ON users (phone_hash)
+    WHERE deleted_at IS NULL;
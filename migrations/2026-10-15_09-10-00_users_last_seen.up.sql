@@ -0,0 +1,4 @@
+-- last login, drives the retention policy. Existing users count as seen at the
+-- migration: their activity was not tracked before
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS last_seen_at TIMESTAMPTZ NOT NULL DEFAULT now();
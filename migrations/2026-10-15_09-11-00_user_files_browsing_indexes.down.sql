@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS user_files_size_idx;
+DROP INDEX IF EXISTS user_files_mime_type_idx;
+DROP INDEX IF EXISTS user_files_created_idx;
+DROP INDEX IF EXISTS user_files_user_created_idx;
@@ -0,0 +1,16 @@
+-- the user files listing and the admin browsing(by user, upload date, mime type, size)
+CREATE INDEX IF NOT EXISTS user_files_user_created_idx
+    ON user_files (user_id, created_at, uuid)
+    WHERE deleted_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS user_files_created_idx
+    ON user_files (created_at, uuid)
+    WHERE deleted_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS user_files_mime_type_idx
+    ON user_files (mime_type)
+    WHERE deleted_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS user_files_size_idx
+    ON user_files (size_bytes)
+    WHERE deleted_at IS NULL;
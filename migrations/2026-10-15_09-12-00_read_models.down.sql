@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS users_created_at_idx;
+
+DROP TABLE IF EXISTS daily_signup_counts;
+
+DROP TABLE IF EXISTS user_file_counts;
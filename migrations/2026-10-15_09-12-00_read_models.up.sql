@@ -0,0 +1,35 @@
+-- read models maintained by the event consumer(see pkg/rmqconsumer), dashboards
+-- read them instead of counting over users/user_files on every load
+CREATE TABLE IF NOT EXISTS user_file_counts
+(
+    user_id     INTEGER PRIMARY KEY REFERENCES users (id) ON DELETE CASCADE,
+    files_count BIGINT      NOT NULL DEFAULT 0 CHECK (files_count >= 0),
+    total_bytes BIGINT      NOT NULL DEFAULT 0 CHECK (total_bytes >= 0),
+    updated_at  TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- UTC days, deleted users included: a signup stays a signup
+CREATE TABLE IF NOT EXISTS daily_signup_counts
+(
+    day        DATE PRIMARY KEY,
+    signups    BIGINT      NOT NULL DEFAULT 0 CHECK (signups >= 0),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- a day refresh scans the users of that day only
+CREATE INDEX IF NOT EXISTS users_created_at_idx
+    ON users (created_at);
+
+-- backfill
+INSERT INTO user_file_counts (user_id, files_count, total_bytes)
+SELECT u.id, count(f.id), COALESCE(sum(f.size_bytes), 0)
+FROM users u
+         LEFT JOIN user_files f ON f.user_id = u.id AND f.deleted_at IS NULL
+GROUP BY u.id
+ON CONFLICT (user_id) DO NOTHING;
+
+INSERT INTO daily_signup_counts (day, signups)
+SELECT (created_at AT TIME ZONE 'UTC')::date, count(*)
+FROM users
+GROUP BY 1
+ON CONFLICT (day) DO NOTHING;
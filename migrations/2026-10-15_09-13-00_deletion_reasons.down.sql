@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS users_deleted_reason_idx;
+
+ALTER TABLE users
+    DROP CONSTRAINT IF EXISTS users_deleted_reason_check;
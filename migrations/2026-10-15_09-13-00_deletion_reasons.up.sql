@@ -0,0 +1,13 @@
+-- the deletion reason taxonomy(see user.DeletionReason), '' - deleted before it
+UPDATE users
+SET deleted_reason = 'admin_action'
+WHERE deleted_reason NOT IN ('', 'user_request', 'admin_action', 'gdpr', 'fraud');
+
+ALTER TABLE users
+    ADD CONSTRAINT users_deleted_reason_check
+        CHECK (deleted_reason IN ('', 'user_request', 'admin_action', 'gdpr', 'fraud'));
+
+-- the admin deleted users listing
+CREATE INDEX IF NOT EXISTS users_deleted_reason_idx
+    ON users (deleted_reason, created_at, uuid)
+    WHERE deleted_at IS NOT NULL;
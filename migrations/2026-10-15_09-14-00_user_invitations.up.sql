@@ -0,0 +1,18 @@
+-- pending invitation, at most one per email: the row is removed when the invitee signs up
+CREATE TABLE IF NOT EXISTS user_invitations
+(
+    id         SERIAL PRIMARY KEY,
+    email      TEXT        NOT NULL,
+    role       TEXT        NOT NULL CHECK (role IN ('admin', 'worker')),
+    token_hash BYTEA       NOT NULL,
+    invited_by INTEGER     REFERENCES users (id) ON DELETE SET NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS user_invitations_email_unique_idx
+    ON user_invitations (lower(email));
+
+CREATE UNIQUE INDEX IF NOT EXISTS user_invitations_token_hash_unique_idx
+    ON user_invitations (token_hash);
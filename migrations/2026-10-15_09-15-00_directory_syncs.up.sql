@@ -0,0 +1,19 @@
+-- a row per directory(LDAP/AD) sync run, the admin endpoint reports the latest one
+CREATE TABLE IF NOT EXISTS directory_syncs
+(
+    id          SERIAL PRIMARY KEY,
+    started_at  TIMESTAMPTZ NOT NULL,
+    finished_at TIMESTAMPTZ NOT NULL,
+    fetched     INTEGER     NOT NULL DEFAULT 0,
+    created     INTEGER     NOT NULL DEFAULT 0,
+    updated     INTEGER     NOT NULL DEFAULT 0,
+    disabled    INTEGER     NOT NULL DEFAULT 0,
+    unchanged   INTEGER     NOT NULL DEFAULT 0,
+    skipped     INTEGER     NOT NULL DEFAULT 0,
+    failed      INTEGER     NOT NULL DEFAULT 0,
+    -- the run failed as a whole(directory unreachable), '' - completed
+    error       TEXT        NOT NULL DEFAULT ''
+);
+
+CREATE INDEX IF NOT EXISTS directory_syncs_started_at_idx
+    ON directory_syncs (started_at DESC);
@@ -0,0 +1,7 @@
+-- addresses no notification email is sent to: rejected by the provider or unsubscribed
+CREATE TABLE IF NOT EXISTS email_suppressions
+(
+    email      TEXT PRIMARY KEY,
+    reason     TEXT        NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
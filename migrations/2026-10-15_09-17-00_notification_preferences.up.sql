@@ -0,0 +1,25 @@
+-- the channels a user opted in to, a channel without a row has the default
+-- preference(email only, at once)
+CREATE TABLE IF NOT EXISTS notification_preferences
+(
+    user_id     INTEGER     NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    channel     TEXT        NOT NULL CHECK (channel IN ('email', 'sms', 'webhook')),
+    enabled     BOOLEAN     NOT NULL,
+    mode        TEXT        NOT NULL DEFAULT 'instant' CHECK (mode IN ('instant', 'digest')),
+    webhook_url TEXT        NOT NULL DEFAULT '',
+    updated_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (user_id, channel)
+);
+
+-- non-urgent notifications of the digest mode channels, sent and deleted by "send-digests"
+CREATE TABLE IF NOT EXISTS notification_digest_items
+(
+    id         BIGSERIAL PRIMARY KEY,
+    user_id    INTEGER     NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    channel    TEXT        NOT NULL,
+    subject    TEXT        NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS notification_digest_items_user_idx
+    ON notification_digest_items (user_id, id);
@@ -0,0 +1,12 @@
+-- the requests per UTC day, organization(the email domain of the user) and
+-- role, every instance adds the requests it served
+CREATE TABLE IF NOT EXISTS usage_rollups
+(
+    day         DATE        NOT NULL,
+    org         TEXT        NOT NULL,
+    role        TEXT        NOT NULL,
+    requests    BIGINT      NOT NULL DEFAULT 0,
+    duration_ms BIGINT      NOT NULL DEFAULT 0,
+    updated_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (day, org, role)
+);
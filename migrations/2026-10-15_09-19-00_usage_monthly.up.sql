@@ -0,0 +1,12 @@
+-- the billing records per UTC month and organization(the email domain of the
+-- users), recomputed by "aggregate-usage"
+CREATE TABLE IF NOT EXISTS usage_monthly
+(
+    month         DATE        NOT NULL,
+    org           TEXT        NOT NULL,
+    active_users  BIGINT      NOT NULL DEFAULT 0,
+    storage_bytes BIGINT      NOT NULL DEFAULT 0,
+    api_calls     BIGINT      NOT NULL DEFAULT 0,
+    generated_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (month, org)
+);
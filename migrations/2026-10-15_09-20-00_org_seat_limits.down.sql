@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS users_org_active_idx;
+DROP TABLE IF EXISTS org_seat_limits;
@@ -0,0 +1,13 @@
+-- the seat limits of the organizations(the email domains of the users), an
+-- organization without a row is unlimited
+CREATE TABLE IF NOT EXISTS org_seat_limits
+(
+    org        TEXT PRIMARY KEY,
+    seats      INTEGER     NOT NULL CHECK (seats >= 0),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- the active users of an organization, counted on every user creation
+CREATE INDEX IF NOT EXISTS users_org_active_idx
+    ON users (lower(substring(email FROM '[^@]*$')))
+    WHERE deleted_at IS NULL;
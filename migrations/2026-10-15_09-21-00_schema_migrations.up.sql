@@ -0,0 +1,33 @@
+-- the applied migrations, the startup compares the latest one with the code's
+-- (see "Schema version"): every migration from this one on ends with its own
+--   INSERT INTO schema_migrations (version) VALUES (<file name timestamp>);
+CREATE TABLE IF NOT EXISTS schema_migrations
+(
+    version    BIGINT PRIMARY KEY,
+    applied_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- the ones applied before, and this one
+INSERT INTO schema_migrations (version)
+VALUES (20251003123836),
+       (20261015090200),
+       (20261015090300),
+       (20261015090400),
+       (20261015090500),
+       (20261015090600),
+       (20261015090700),
+       (20261015090800),
+       (20261015090900),
+       (20261015091000),
+       (20261015091100),
+       (20261015091200),
+       (20261015091300),
+       (20261015091400),
+       (20261015091500),
+       (20261015091600),
+       (20261015091700),
+       (20261015091800),
+       (20261015091900),
+       (20261015092000),
+       (20261015092100)
+ON CONFLICT (version) DO NOTHING;
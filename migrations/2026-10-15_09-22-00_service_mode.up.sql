@@ -0,0 +1,16 @@
+-- the runtime modes the admins toggle, a single row every instance polls
+CREATE TABLE IF NOT EXISTS service_mode
+(
+    id         BOOLEAN PRIMARY KEY DEFAULT true CHECK (id),
+    read_only  BOOLEAN     NOT NULL DEFAULT false,
+    reason     TEXT        NOT NULL DEFAULT '',
+    updated_by UUID,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+INSERT INTO service_mode (id)
+VALUES (true)
+ON CONFLICT (id) DO NOTHING;
+
+INSERT INTO schema_migrations (version)
+VALUES (20261015092200);
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS processed_events;
+
+DELETE FROM schema_migrations
+WHERE version = 20261015092300;
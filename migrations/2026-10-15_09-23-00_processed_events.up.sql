@@ -0,0 +1,14 @@
+-- the events the consumer processed, by their message id: a redelivery(a
+-- reconnect, a requeue) of one is skipped
+CREATE TABLE IF NOT EXISTS processed_events
+(
+    message_id   TEXT PRIMARY KEY,
+    processed_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- the purge of the expired ones
+CREATE INDEX IF NOT EXISTS processed_events_processed_at_idx
+    ON processed_events (processed_at);
+
+INSERT INTO schema_migrations (version)
+VALUES (20261015092300);
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS logins;
+
+DELETE FROM schema_migrations
+WHERE version = 20261015092400;
@@ -0,0 +1,19 @@
+-- the successful logins the anomaly rules compare the new ones with, the
+-- newest ANOMALY_HISTORY_SIZE of a user are kept
+CREATE TABLE IF NOT EXISTS logins
+(
+    id         BIGSERIAL PRIMARY KEY,
+    user_id    INTEGER     NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    ip         TEXT        NOT NULL,
+    user_agent TEXT        NOT NULL,
+    country    TEXT        NOT NULL,
+    flags      TEXT[]      NOT NULL DEFAULT '{}',
+
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS logins_user_created_idx
+    ON logins (user_id, created_at DESC);
+
+INSERT INTO schema_migrations (version)
+VALUES (20261015092400);
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS devices;
+
+DELETE FROM schema_migrations
+WHERE version = 20261015092500;
@@ -0,0 +1,22 @@
+-- the user agents the users logged in from, a device of a login is created on
+-- its first one. The tokens bound to a deleted device are revoked.
+CREATE TABLE IF NOT EXISTS devices
+(
+    id           SERIAL PRIMARY KEY,
+    uuid         UUID        NOT NULL DEFAULT gen_random_uuid(),
+    user_id      INTEGER     NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    user_agent   TEXT        NOT NULL,
+    ip           TEXT        NOT NULL,
+
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT now(),
+    last_seen_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS devices_uuid_unique_idx
+    ON devices (uuid);
+
+CREATE UNIQUE INDEX IF NOT EXISTS devices_user_agent_unique_idx
+    ON devices (user_id, user_agent);
+
+INSERT INTO schema_migrations (version)
+VALUES (20261015092500);
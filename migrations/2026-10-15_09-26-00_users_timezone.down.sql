@@ -0,0 +1,5 @@
+ALTER TABLE users
+    DROP COLUMN IF EXISTS timezone;
+
+DELETE FROM schema_migrations
+WHERE version = 20261015092600;
@@ -0,0 +1,6 @@
+-- the own timezone of a user as an IANA name, empty - the one of its organization
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS timezone TEXT NOT NULL DEFAULT '';
+
+INSERT INTO schema_migrations (version)
+VALUES (20261015092600);
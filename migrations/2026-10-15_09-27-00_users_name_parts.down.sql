@@ -0,0 +1,6 @@
+ALTER TABLE users
+    DROP COLUMN IF EXISTS middle_name,
+    DROP COLUMN IF EXISTS suffix;
+
+DELETE FROM schema_migrations
+WHERE version = 20261015092700;
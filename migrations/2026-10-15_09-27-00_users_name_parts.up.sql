@@ -0,0 +1,7 @@
+-- the optional parts of the name, empty if none
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS middle_name TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS suffix      TEXT NOT NULL DEFAULT '';
+
+INSERT INTO schema_migrations (version)
+VALUES (20261015092700);
@@ -0,0 +1,13 @@
+UPDATE users
+SET deleted_reason = 'admin_action'
+WHERE deleted_reason = 'merged';
+
+ALTER TABLE users
+    DROP CONSTRAINT IF EXISTS users_deleted_reason_check;
+
+ALTER TABLE users
+    ADD CONSTRAINT users_deleted_reason_check
+        CHECK (deleted_reason IN ('', 'user_request', 'admin_action', 'gdpr', 'fraud'));
+
+DELETE FROM schema_migrations
+WHERE version = 20261015092800;
@@ -0,0 +1,10 @@
+-- 'merged' - the duplicate account merged into another one(see user.DeletionMerged)
+ALTER TABLE users
+    DROP CONSTRAINT IF EXISTS users_deleted_reason_check;
+
+ALTER TABLE users
+    ADD CONSTRAINT users_deleted_reason_check
+        CHECK (deleted_reason IN ('', 'user_request', 'admin_action', 'gdpr', 'fraud', 'merged'));
+
+INSERT INTO schema_migrations (version)
+VALUES (20261015092800);
@@ -0,0 +1,6 @@
+DROP TRIGGER IF EXISTS users_history_trg ON users;
+DROP FUNCTION IF EXISTS users_history_record();
+DROP TABLE IF EXISTS users_history;
+
+DELETE FROM schema_migrations
+WHERE version = 20261015092900;
@@ -0,0 +1,64 @@
+-- the prior versions of the users rows: a row per profile change, valid in
+-- [valid_from, valid_to). The password and the token columns are not kept,
+-- birth_date and phone are encrypted as in users
+CREATE TABLE IF NOT EXISTS users_history
+(
+    id                      BIGSERIAL PRIMARY KEY,
+    user_id                 INTEGER     NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+
+    email                   TEXT        NOT NULL,
+    role                    TEXT        NOT NULL,
+    name                    TEXT        NOT NULL,
+    middle_name             TEXT        NOT NULL,
+    lastname                TEXT        NOT NULL,
+    suffix                  TEXT        NOT NULL,
+    birth_date              TEXT        NOT NULL,
+    phone                   TEXT        NOT NULL,
+    timezone                TEXT        NOT NULL,
+    password_reset_required BOOLEAN     NOT NULL,
+    created_at              TIMESTAMPTZ NOT NULL,
+    updated_at              TIMESTAMPTZ NOT NULL,
+    deleted_at              TIMESTAMPTZ,
+    deleted_reason          TEXT        NOT NULL,
+    deleted_by              INTEGER,
+
+    valid_from              TIMESTAMPTZ NOT NULL,
+    valid_to                TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS users_history_user_valid_idx
+    ON users_history (user_id, valid_to);
+
+-- every write path is covered, the CTE statements included. Only the writes
+-- bumping updated_at(not the PII re-encryption, the logins) changing a kept
+-- column make a version. The versions are contiguous: one starts where the
+-- previous one ended, the first one at created_at
+CREATE OR REPLACE FUNCTION users_history_record() RETURNS trigger AS
+$$
+BEGIN
+    INSERT INTO users_history (user_id, email, role, name, middle_name, lastname, suffix, birth_date, phone,
+                               timezone, password_reset_required, created_at, updated_at, deleted_at,
+                               deleted_reason, deleted_by, valid_from)
+    VALUES (OLD.id, OLD.email, OLD.role, OLD.name, OLD.middle_name, OLD.lastname, OLD.suffix, OLD.birth_date,
+            OLD.phone, OLD.timezone, OLD.password_reset_required, OLD.created_at, OLD.updated_at, OLD.deleted_at,
+            OLD.deleted_reason, OLD.deleted_by,
+            COALESCE((SELECT max(h.valid_to) FROM users_history h WHERE h.user_id = OLD.id), OLD.created_at));
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS users_history_trg ON users;
+CREATE TRIGGER users_history_trg
+    AFTER UPDATE
+    ON users
+    FOR EACH ROW
+    WHEN ((OLD.updated_at IS DISTINCT FROM NEW.updated_at OR OLD.deleted_at IS DISTINCT FROM NEW.deleted_at)
+        AND (OLD.email, OLD.role, OLD.name, OLD.middle_name, OLD.lastname, OLD.suffix, OLD.birth_date, OLD.phone,
+             OLD.timezone, OLD.password_reset_required, OLD.deleted_at, OLD.deleted_reason)
+            IS DISTINCT FROM
+            (NEW.email, NEW.role, NEW.name, NEW.middle_name, NEW.lastname, NEW.suffix, NEW.birth_date, NEW.phone,
+             NEW.timezone, NEW.password_reset_required, NEW.deleted_at, NEW.deleted_reason))
+EXECUTE FUNCTION users_history_record();
+
+INSERT INTO schema_migrations (version)
+VALUES (20261015092900);
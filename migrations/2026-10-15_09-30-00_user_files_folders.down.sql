@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS user_files_user_folder_idx;
+ALTER TABLE user_files
+    DROP COLUMN IF EXISTS folder;
+
+DELETE FROM schema_migrations
+WHERE version = 20261015093000;
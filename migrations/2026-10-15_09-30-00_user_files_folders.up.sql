@@ -0,0 +1,11 @@
+-- folder - the "/" separated path of the file, '' the root. The folders are
+-- virtual: one exists while it has files
+ALTER TABLE user_files
+    ADD COLUMN IF NOT EXISTS folder TEXT NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS user_files_user_folder_idx
+    ON user_files (user_id, folder text_pattern_ops)
+    WHERE deleted_at IS NULL;
+
+INSERT INTO schema_migrations (version)
+VALUES (20261015093000);
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS user_file_texts;
+
+DELETE FROM schema_migrations
+WHERE version = 20261015093100;
@@ -0,0 +1,16 @@
+-- the text extracted from the files(see TextExtractionService) searched by
+-- search_vector. A failed extraction is kept with an empty body
+CREATE TABLE IF NOT EXISTS user_file_texts
+(
+    file_id       INTEGER PRIMARY KEY REFERENCES user_files (id) ON DELETE CASCADE,
+    status        TEXT        NOT NULL,
+    body          TEXT        NOT NULL,
+    search_vector TSVECTOR GENERATED ALWAYS AS (to_tsvector('simple', body)) STORED,
+    extracted_at  TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS user_file_texts_search_idx
+    ON user_file_texts USING GIN (search_vector);
+
+INSERT INTO schema_migrations (version)
+VALUES (20261015093100);
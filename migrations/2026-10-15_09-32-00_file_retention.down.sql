@@ -0,0 +1,7 @@
+ALTER TABLE user_files
+    DROP COLUMN IF EXISTS legal_hold,
+    DROP COLUMN IF EXISTS retain_until;
+DROP TABLE IF EXISTS file_retention_rules;
+
+DELETE FROM schema_migrations
+WHERE version = 20261015093200;
@@ -0,0 +1,20 @@
+-- file_retention_rules - how long the files of a mime type("image/*" wildcard)
+-- or a tag are kept, the longest of the matching rules applies
+CREATE TABLE IF NOT EXISTS file_retention_rules
+(
+    id             BIGSERIAL PRIMARY KEY,
+    uuid           UUID        NOT NULL DEFAULT gen_random_uuid() UNIQUE,
+    mime_type      TEXT        NOT NULL DEFAULT '',
+    tag            TEXT        NOT NULL DEFAULT '',
+    retention_days INTEGER     NOT NULL CHECK (retention_days > 0),
+    created_at     TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CHECK ((mime_type = '') <> (tag = ''))
+);
+
+-- retain_until - the per-file override of the rules, legal_hold - never deleted
+ALTER TABLE user_files
+    ADD COLUMN IF NOT EXISTS retain_until TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS legal_hold   BOOLEAN NOT NULL DEFAULT false;
+
+INSERT INTO schema_migrations (version)
+VALUES (20261015093200);
@@ -0,0 +1,6 @@
+ALTER TABLE users
+    DROP COLUMN IF EXISTS legal_hold_reason,
+    DROP COLUMN IF EXISTS legal_hold_since;
+
+DELETE FROM schema_migrations
+WHERE version = 20261015093300;
@@ -0,0 +1,9 @@
+-- legal_hold_since - the user and their files are neither deleted, merged away,
+-- redacted nor purged while it is set. updated_at is not bumped: a hold is not
+-- a version of the user(users_history)
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS legal_hold_since  TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS legal_hold_reason TEXT NOT NULL DEFAULT '';
+
+INSERT INTO schema_migrations (version)
+VALUES (20261015093300);
@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS projection_user_activity;
+DROP TABLE IF EXISTS projection_user_files;
+DROP TABLE IF EXISTS projection_user_summaries;
+DROP TABLE IF EXISTS event_store;
+
+DELETE FROM schema_migrations
+WHERE version = 20261015093400;
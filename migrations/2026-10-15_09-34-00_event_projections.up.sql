@@ -0,0 +1,56 @@
+-- event_store - the append-only stream of the consumed events, in the order
+-- they were stored(seq). The user payload is not kept: the profile is in users,
+-- encrypted. The read models below are rebuilt from it.
+CREATE TABLE IF NOT EXISTS event_store
+(
+    seq         BIGSERIAL PRIMARY KEY,
+    event_id    UUID        NOT NULL UNIQUE,
+    event_type  TEXT        NOT NULL,
+    -- NULL - an event of no user, e.g. a failed login of an unknown email
+    user_uuid   UUID,
+    occurred_at TIMESTAMPTZ NOT NULL,
+    meta        JSONB       NOT NULL DEFAULT '{}',
+    stored_at   TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS event_store_user_uuid_idx
+    ON event_store (user_uuid, seq)
+    WHERE user_uuid IS NOT NULL;
+
+-- the read models of the event_store, by user
+CREATE TABLE IF NOT EXISTS projection_user_summaries
+(
+    user_uuid      UUID PRIMARY KEY,
+    signed_up_at   TIMESTAMPTZ,
+    updated_at     TIMESTAMPTZ,
+    deleted_at     TIMESTAMPTZ,
+    deleted_reason TEXT        NOT NULL DEFAULT '',
+    merged_into    UUID,
+    email_changes  INTEGER     NOT NULL DEFAULT 0,
+    logins         INTEGER     NOT NULL DEFAULT 0,
+    failed_logins  INTEGER     NOT NULL DEFAULT 0,
+    last_login_at  TIMESTAMPTZ,
+    last_event_at  TIMESTAMPTZ NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS projection_user_files
+(
+    user_uuid       UUID PRIMARY KEY,
+    files_count     BIGINT      NOT NULL DEFAULT 0,
+    changes         INTEGER     NOT NULL DEFAULT 0,
+    last_changed_at TIMESTAMPTZ NOT NULL
+);
+
+-- day - UTC
+CREATE TABLE IF NOT EXISTS projection_user_activity
+(
+    user_uuid     UUID    NOT NULL,
+    day           DATE    NOT NULL,
+    events        INTEGER NOT NULL DEFAULT 0,
+    logins        INTEGER NOT NULL DEFAULT 0,
+    failed_logins INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (user_uuid, day)
+);
+
+INSERT INTO schema_migrations (version)
+VALUES (20261015093400);
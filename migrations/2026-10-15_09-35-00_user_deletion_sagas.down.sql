@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS user_deletion_sagas;
+
+DELETE FROM schema_migrations
+WHERE version = 20261015093500;
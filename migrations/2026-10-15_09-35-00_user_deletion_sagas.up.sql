@@ -0,0 +1,30 @@
+-- user_deletion_sagas - the state of every DeleteUser: step is the next one to
+-- run, file_uuids/storage_keys the files deletable at the start. A running
+-- saga not updated for a while was interrupted, the resume-deletions job
+-- continues it
+CREATE TABLE IF NOT EXISTS user_deletion_sagas
+(
+    id           BIGSERIAL PRIMARY KEY,
+    uuid         UUID        NOT NULL DEFAULT gen_random_uuid() UNIQUE,
+    user_id      BIGINT      NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    -- actor_uuid - the nil UUID for the system
+    actor_uuid   UUID        NOT NULL,
+    reason       TEXT        NOT NULL,
+    step         TEXT        NOT NULL,
+    status       TEXT        NOT NULL DEFAULT 'running',
+    file_uuids   UUID[]      NOT NULL DEFAULT '{}',
+    storage_keys TEXT[]      NOT NULL DEFAULT '{}',
+    attempts     INTEGER     NOT NULL DEFAULT 1,
+    last_error   TEXT        NOT NULL DEFAULT '',
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at   TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- one running saga per user
+CREATE UNIQUE INDEX IF NOT EXISTS user_deletion_sagas_running_uidx
+    ON user_deletion_sagas (user_id) WHERE status = 'running';
+CREATE INDEX IF NOT EXISTS user_deletion_sagas_stale_idx
+    ON user_deletion_sagas (updated_at) WHERE status = 'running';
+
+INSERT INTO schema_migrations (version)
+VALUES (20261015093500);
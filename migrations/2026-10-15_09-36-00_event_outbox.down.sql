@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS event_outbox;
+
+DELETE FROM schema_migrations
+WHERE version = 20261015093600;
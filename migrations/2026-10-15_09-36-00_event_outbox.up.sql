@@ -0,0 +1,14 @@
+-- event_outbox - the events the broker did not take(down, saturated), relayed
+-- by the relay-outbox job once it is back. body is encrypted with the PII key:
+-- the events carry PII and the email change tokens
+CREATE TABLE IF NOT EXISTS event_outbox
+(
+    id         BIGSERIAL PRIMARY KEY,
+    event_id   UUID        NOT NULL UNIQUE,
+    event_type TEXT        NOT NULL,
+    body       TEXT        NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+INSERT INTO schema_migrations (version)
+VALUES (20261015093600);
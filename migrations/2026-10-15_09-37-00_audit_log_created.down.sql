@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS audit_log_action_created_idx;
+DROP INDEX IF EXISTS audit_log_created_idx;
+
+DELETE FROM schema_migrations
+WHERE version = 20261015093700;
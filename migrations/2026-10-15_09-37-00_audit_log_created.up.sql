@@ -0,0 +1,10 @@
+-- the audit log review by action or date range and the archive-audit-log job
+-- scanning the entries past the retention
+CREATE INDEX IF NOT EXISTS audit_log_created_idx
+    ON audit_log (created_at);
+
+CREATE INDEX IF NOT EXISTS audit_log_action_created_idx
+    ON audit_log (action, created_at);
+
+INSERT INTO schema_migrations (version)
+VALUES (20261015093700);
@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS users_active_lastname_idx;
+DROP INDEX IF EXISTS users_active_name_idx;
+DROP INDEX IF EXISTS users_active_created_idx;
+DROP INDEX IF EXISTS users_email_trgm_idx;
+
+DELETE FROM schema_migrations
+WHERE version = 20261015093800;
@@ -0,0 +1,26 @@
+-- the covering indexes of the list filters and sorts, every one is listed in
+-- migrations.Indexes: the startup check warns about the missing ones
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- the email substring filters(lower(email) LIKE '%...%') of the active users
+CREATE INDEX IF NOT EXISTS users_email_trgm_idx
+    ON users USING GIN (lower(email) gin_trgm_ops)
+    WHERE deleted_at IS NULL;
+
+-- the default order of the users list and its cursor(created_at, uuid),
+-- users_created_at_idx serves the stats over all the users
+CREATE INDEX IF NOT EXISTS users_active_created_idx
+    ON users (created_at, uuid)
+    WHERE deleted_at IS NULL;
+
+-- "sort=name", "sort=lastname" of the users list
+CREATE INDEX IF NOT EXISTS users_active_name_idx
+    ON users (name, uuid)
+    WHERE deleted_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS users_active_lastname_idx
+    ON users (lastname, uuid)
+    WHERE deleted_at IS NULL;
+
+INSERT INTO schema_migrations (version)
+VALUES (20261015093800);
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS org_upload_policies;
+
+DELETE FROM schema_migrations
+WHERE version = 20261015093900;
@@ -0,0 +1,14 @@
+-- the upload policies of the organizations(the email domains of the users), an
+-- organization without a row has the global limits only. Zero - no limit of
+-- its own, an empty allowed_types - any type
+CREATE TABLE IF NOT EXISTS org_upload_policies
+(
+    org           TEXT PRIMARY KEY,
+    allowed_types TEXT[]      NOT NULL DEFAULT '{}',
+    max_file_size BIGINT      NOT NULL DEFAULT 0 CHECK (max_file_size >= 0),
+    quota_bytes   BIGINT      NOT NULL DEFAULT 0 CHECK (quota_bytes >= 0),
+    updated_at    TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+INSERT INTO schema_migrations (version)
+VALUES (20261015093900);
@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS users_active_last_seen_idx;
+
+ALTER TABLE users
+    DROP COLUMN IF EXISTS inactivity_warned_at,
+    DROP COLUMN IF EXISTS suspended_at;
+
+DELETE FROM schema_migrations
+WHERE version = 20261015094000;
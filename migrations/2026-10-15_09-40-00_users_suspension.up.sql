@@ -0,0 +1,15 @@
+-- suspended_at - the user was suspended for inactivity: login is refused, or
+-- lifts it within the grace window. inactivity_warned_at - the warning of the
+-- coming suspension was published, cleared by the next login. updated_at is not
+-- bumped: neither is a version of the user(users_history)
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS suspended_at         TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS inactivity_warned_at TIMESTAMPTZ;
+
+-- the candidates of the suspend-inactive-users job
+CREATE INDEX IF NOT EXISTS users_active_last_seen_idx
+    ON users (last_seen_at)
+    WHERE deleted_at IS NULL AND suspended_at IS NULL;
+
+INSERT INTO schema_migrations (version)
+VALUES (20261015094000);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS user_files_thumbnail_url_idx;
+DROP INDEX IF EXISTS user_files_storage_key_idx;
+
+DELETE FROM schema_migrations
+WHERE version = 20261016090000;
@@ -0,0 +1,12 @@
+-- the owner lookup of the raw file downloads(GET /files/raw/*key): the live
+-- file stored under the key, or the one whose thumbnail it is
+CREATE INDEX IF NOT EXISTS user_files_storage_key_idx
+    ON user_files (storage_key)
+    WHERE deleted_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS user_files_thumbnail_url_idx
+    ON user_files (thumbnail_url)
+    WHERE deleted_at IS NULL AND thumbnail_url IS NOT NULL;
+
+INSERT INTO schema_migrations (version)
+VALUES (20261016090000);
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS otp_cooldowns;
+
+DELETE FROM schema_migrations
+WHERE version = 20261016100000;
@@ -0,0 +1,14 @@
+-- the per-phone cooldown of the OTP requests, shared by the instances: one
+-- row per phone(blind index, the phones are not stored), the rows older than
+-- the cooldown are pruned by the next request
+CREATE TABLE IF NOT EXISTS otp_cooldowns
+(
+    phone_hash   BYTEA PRIMARY KEY,
+    requested_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS otp_cooldowns_requested_at_idx
+    ON otp_cooldowns (requested_at);
+
+INSERT INTO schema_migrations (version)
+VALUES (20261016100000);
@@ -0,0 +1,5 @@
+DROP TRIGGER IF EXISTS users_seat_limit_trg ON users;
+DROP FUNCTION IF EXISTS users_seat_limit_check();
+
+DELETE FROM schema_migrations
+WHERE version = 20261016110000;
@@ -0,0 +1,48 @@
+-- the seat limit of the organization is checked on every write making a user
+-- active in it: the creations, the accepted invitations and the confirmed email
+-- changes. The limit row is locked, so the concurrent writes into one
+-- organization count the active users one after another. A user staying in
+-- their organization keeps the seat, even under a lowered limit. The backup
+-- restore sets user_manager.seat_limit_check to off for its inserts.
+CREATE OR REPLACE FUNCTION users_seat_limit_check() RETURNS trigger AS
+$$
+DECLARE
+    new_org   TEXT := lower(substring(NEW.email FROM '[^@]*$'));
+    max_seats INTEGER;
+    active    BIGINT;
+BEGIN
+    IF current_setting('user_manager.seat_limit_check', true) = 'off' THEN
+        RETURN NEW;
+    END IF;
+    IF TG_OP = 'UPDATE' AND OLD.deleted_at IS NULL
+        AND lower(substring(OLD.email FROM '[^@]*$')) = new_org THEN
+        RETURN NEW;
+    END IF;
+
+    SELECT seats INTO max_seats FROM org_seat_limits WHERE org = new_org FOR UPDATE;
+    IF NOT FOUND THEN
+        RETURN NEW;
+    END IF;
+
+    SELECT count(*) INTO active
+    FROM users
+    WHERE deleted_at IS NULL AND lower(substring(email FROM '[^@]*$')) = new_org AND id <> NEW.id;
+    IF active >= max_seats THEN
+        RAISE EXCEPTION 'the seat limit of the organization % is reached', new_org
+            USING ERRCODE = 'UM001';
+    END IF;
+
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS users_seat_limit_trg ON users;
+CREATE TRIGGER users_seat_limit_trg
+    BEFORE INSERT OR UPDATE OF email, deleted_at
+    ON users
+    FOR EACH ROW
+    WHEN (NEW.deleted_at IS NULL)
+EXECUTE FUNCTION users_seat_limit_check();
+
+INSERT INTO schema_migrations (version)
+VALUES (20261016110000);
@@ -0,0 +1,35 @@
+package migrations
+
+// Index - an index a list endpoint relies on: without it the filter or the
+// sort scans the whole table
+type Index struct {
+	Name  string
+	Table string
+	// Serves - the filter or the sort of the list, for the startup warning
+	Serves string
+}
+
+// Indexes - the covering indexes of the list filters and sorts. A new filter
+// or sort of a list ships its index in a migration and its entry here: the
+// startup check warns about the ones missing from the database
+var Indexes = []Index{
+	{"users_active_created_idx", "users", "users list: the default order, the cursor"},
+	{"users_email_unique_active_idx", "users", "users list: sort=email, the email lookup"},
+	{"users_email_trgm_idx", "users", "users list: the email substring filter"},
+	{"users_active_name_idx", "users", "users list: sort=name"},
+	{"users_active_lastname_idx", "users", "users list: sort=lastname"},
+	{"users_deleted_reason_idx", "users", "deleted users list: the reason filter"},
+	{"user_files_user_created_idx", "user_files", "files of a user: the default order, the cursor"},
+	{"user_files_user_folder_idx", "user_files", "files of a user: the folder filter"},
+	{"user_files_tags_gin_idx", "user_files", "files of a user: the tags filter"},
+	{"user_files_size_idx", "user_files", "files lists: sort=size_bytes"},
+	{"user_files_created_idx", "user_files", "admin files list: the default order"},
+	{"user_files_mime_type_idx", "user_files", "admin files list: the mime_type filter"},
+	{"user_files_storage_key_idx", "user_files", "raw files: the owner of an object"},
+	{"user_files_thumbnail_url_idx", "user_files", "raw files: the owner of a thumbnail"},
+	{"user_notes_user_created_idx", "user_notes", "notes of a user: the default order, the cursor"},
+	{"audit_log_created_idx", "audit_log", "audit log: the default order, the archive"},
+	{"audit_log_actor_created_idx", "audit_log", "audit log: the actor filter"},
+	{"audit_log_target_created_idx", "audit_log", "audit log: the target filter"},
+	{"audit_log_action_created_idx", "audit_log", "audit log: the action filter"},
+}
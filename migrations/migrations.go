@@ -0,0 +1,52 @@
+// Package migrations - the schema the code is built for: the version of a
+// migration is the timestamp of its file name, e.g. 20261015092100 of
+// "2026-10-15_09-21-00_schema_migrations.up.sql". Every migration from
+// schema_migrations on records its version there.
+package migrations
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"strconv"
+	"strings"
+)
+
+//go:embed *.up.sql
+var FS embed.FS
+
+const stampLen = len("2006-01-02_15-04-05")
+
+// Version - of a migration file name
+func Version(name string) (int64, error) {
+	if len(name) < stampLen+1 || name[stampLen] != '_' {
+		return 0, fmt.Errorf("invalid migration file name %q", name)
+	}
+	stamp := strings.NewReplacer("-", "", "_", "").Replace(name[:stampLen])
+	v, err := strconv.ParseInt(stamp, 10, 64)
+	if err != nil || len(stamp) != 14 {
+		return 0, fmt.Errorf("invalid migration file name %q", name)
+	}
+	return v, nil
+}
+
+// Latest - the version the code expects the database at
+func Latest() (int64, error) {
+	names, err := fs.Glob(FS, "*.up.sql")
+	if err != nil {
+		return 0, err
+	}
+
+	var latest int64
+	for _, name := range names {
+		v, err := Version(name)
+		if err != nil {
+			return 0, err
+		}
+		latest = max(latest, v)
+	}
+	if latest == 0 {
+		return 0, fmt.Errorf("no migrations embedded")
+	}
+	return latest, nil
+}
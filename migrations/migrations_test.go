@@ -0,0 +1,98 @@
+package migrations
+
+import (
+	"io/fs"
+	"regexp"
+	"slices"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVersion_Table(t *testing.T) {
+	type tc struct {
+		name    string
+		file    string
+		want    int64
+		wantErr bool
+	}
+	tests := []tc{
+		{"migration", "2026-10-15_09-21-00_schema_migrations.up.sql", 20261015092100, false},
+		{"init", "2025-10-03_12-38-36_init.up.sql", 20251003123836, false},
+		{"without a name", "2026-10-15_09-21-00", 0, true},
+		{"no timestamp", "schema_migrations.up.sql", 0, true},
+		{"letters in the timestamp", "2026-10-15_09-2x-00_x.up.sql", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Version(tt.file)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+// TestMigrations_RecordVersion - a migration missing its schema_migrations row
+// would make every instance refuse to start after it was applied
+func TestMigrations_RecordVersion(t *testing.T) {
+	names, err := fs.Glob(FS, "*.up.sql")
+	require.NoError(t, err)
+	first, err := Version("2026-10-15_09-21-00_schema_migrations.up.sql")
+	require.NoError(t, err)
+
+	latest, err := Latest()
+	require.NoError(t, err)
+
+	seen := make(map[int64]string, len(names))
+	for _, name := range names {
+		v, err := Version(name)
+		require.NoError(t, err)
+		require.NotContains(t, seen, v, "%s and %s have the same version", name, seen[v])
+		seen[v] = name
+		assert.LessOrEqual(t, v, latest)
+
+		if v < first {
+			continue
+		}
+		body, err := fs.ReadFile(FS, name)
+		require.NoError(t, err)
+		assert.Contains(t, string(body), "INSERT INTO schema_migrations (version)", name)
+		assert.Contains(t, string(body), strconv.FormatInt(v, 10), name)
+	}
+	assert.Contains(t, seen, latest)
+}
+
+// TestIndexes_Migrated - every index of the startup check is created on its
+// table by a migration, and not dropped by a later one
+func TestIndexes_Migrated(t *testing.T) {
+	names, err := fs.Glob(FS, "*.up.sql")
+	require.NoError(t, err)
+	slices.Sort(names)
+
+	for _, idx := range Indexes {
+		t.Run(idx.Name, func(t *testing.T) {
+			create := regexp.MustCompile(`CREATE (UNIQUE )?INDEX IF NOT EXISTS ` + idx.Name + `\s+ON ` + idx.Table + `\b`)
+			drop := regexp.MustCompile(`DROP INDEX (IF EXISTS )?` + idx.Name + `\b`)
+
+			var created bool
+			for _, name := range names {
+				body, err := fs.ReadFile(FS, name)
+				require.NoError(t, err)
+				if drop.Match(body) {
+					created = false
+				}
+				if create.Match(body) {
+					created = true
+				}
+			}
+			assert.True(t, created, "no migration creates %s on %s", idx.Name, idx.Table)
+		})
+	}
+}
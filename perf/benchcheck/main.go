@@ -0,0 +1,138 @@
+// Command benchcheck reports the benchmarks over their budget: it reads the
+// output of "go test -bench . -benchmem" from stdin and exits with 1 when a
+// budgeted benchmark allocates more than allowed or did not run at all.
+// ns/op is reported, not checked: it depends on the machine.
+//
+//	go test -run '^$' -bench . -benchmem ./... | go run ./perf/benchcheck perf/budget.txt
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+type (
+	// result - one benchmark line, metrics by unit(ns/op, B/op, allocs/op)
+	result map[string]float64
+	budget struct {
+		bytes  float64
+		allocs float64
+	}
+)
+
+// cpuSuffix - GOMAXPROCS appended to the benchmark names
+var cpuSuffix = regexp.MustCompile(`-\d+$`)
+
+func main() {
+	if len(os.Args) != 2 {
+		fmt.Fprintln(os.Stderr, "usage: benchcheck <budget file> < bench output")
+		os.Exit(2)
+	}
+	f, err := os.Open(os.Args[1])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	budgets, err := parseBudgets(f)
+	_ = f.Close()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	if check(parseResults(os.Stdin), budgets, os.Stdout) {
+		os.Exit(1)
+	}
+}
+
+// parseBudgets - "<benchmark> <max B/op> <max allocs/op>" lines, # comments
+func parseBudgets(r io.Reader) (map[string]budget, error) {
+	budgets := make(map[string]budget)
+	sc := bufio.NewScanner(r)
+	for n := 1; sc.Scan(); n++ {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("budget line %d: want <benchmark> <max B/op> <max allocs/op>", n)
+		}
+		bytes, err := strconv.ParseFloat(fields[1], 64)
+		if err != nil {
+			return nil, fmt.Errorf("budget line %d: %w", n, err)
+		}
+		allocs, err := strconv.ParseFloat(fields[2], 64)
+		if err != nil {
+			return nil, fmt.Errorf("budget line %d: %w", n, err)
+		}
+		budgets[fields[0]] = budget{bytes: bytes, allocs: allocs}
+	}
+
+	return budgets, sc.Err()
+}
+
+// parseResults - "BenchmarkX-8  100  123 ns/op  64 B/op  2 allocs/op" lines,
+// everything else(package headers, PASS, logs) is skipped
+func parseResults(r io.Reader) map[string]result {
+	results := make(map[string]result)
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		fields := strings.Fields(sc.Text())
+		if len(fields) < 4 || !strings.HasPrefix(fields[0], "Benchmark") {
+			continue
+		}
+		res := make(result)
+		for i := 2; i+1 < len(fields); i += 2 {
+			v, err := strconv.ParseFloat(fields[i], 64)
+			if err != nil {
+				break
+			}
+			res[fields[i+1]] = v
+		}
+		results[cpuSuffix.ReplaceAllString(fields[0], "")] = res
+	}
+
+	return results
+}
+
+// check writes the report, true - some budget is exceeded
+func check(results map[string]result, budgets map[string]budget, w io.Writer) bool {
+	failed := false
+	for _, name := range slices.Sorted(maps.Keys(results)) {
+		res := results[name]
+		b, ok := budgets[name]
+		if !ok {
+			fmt.Fprintf(w, "?    %s: no budget, %.0f ns/op %.0f B/op %.0f allocs/op\n",
+				name, res["ns/op"], res["B/op"], res["allocs/op"])
+			continue
+		}
+		if _, ok = res["allocs/op"]; !ok {
+			fmt.Fprintf(w, "FAIL %s: no allocs/op, run with -benchmem\n", name)
+			failed = true
+			continue
+		}
+		status := "ok  "
+		if res["B/op"] > b.bytes || res["allocs/op"] > b.allocs {
+			status = "FAIL"
+			failed = true
+		}
+		fmt.Fprintf(w, "%s %s: %.0f ns/op %.0f/%.0f B/op %.0f/%.0f allocs/op\n",
+			status, name, res["ns/op"], res["B/op"], b.bytes, res["allocs/op"], b.allocs)
+	}
+	for _, name := range slices.Sorted(maps.Keys(budgets)) {
+		if _, ok := results[name]; !ok {
+			fmt.Fprintf(w, "FAIL %s: did not run\n", name)
+			failed = true
+		}
+	}
+
+	return failed
+}
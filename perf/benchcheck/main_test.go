@@ -0,0 +1,62 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const benchOutput = `goos: linux
+pkg: user-manager-api/internal/application/services
+BenchmarkSanitizeFileName-8   	  200	     21768 ns/op	   35712 B/op	      29 allocs/op
+BenchmarkToAdminUsers-8       	  200	     10313 ns/op	   12288 B/op	       1 allocs/op
+PASS
+`
+
+func TestCheck(t *testing.T) {
+	results := parseResults(strings.NewReader(benchOutput))
+	require.Equal(t, result{"ns/op": 21768, "B/op": 35712, "allocs/op": 29}, results["BenchmarkSanitizeFileName"])
+
+	type tc struct {
+		name       string
+		budget     string
+		wantFailed bool
+		wantReport string
+	}
+	cases := []tc{
+		{"within budget", "BenchmarkSanitizeFileName 40000 32\nBenchmarkToAdminUsers 12288 1", false, "ok   BenchmarkToAdminUsers"},
+		{"allocs over", "# comment\nBenchmarkToAdminUsers 20000 0", true, "FAIL BenchmarkToAdminUsers"},
+		{"bytes over", "BenchmarkSanitizeFileName 30000 32", true, "FAIL BenchmarkSanitizeFileName"},
+		{"did not run", "BenchmarkGone 1 1", true, "FAIL BenchmarkGone: did not run"},
+		{"without budget", "", false, "?    BenchmarkToAdminUsers: no budget"},
+	}
+
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			budgets, err := parseBudgets(strings.NewReader(tt.budget))
+			require.NoError(t, err)
+
+			var report strings.Builder
+			assert.Equal(t, tt.wantFailed, check(results, budgets, &report))
+			assert.Contains(t, report.String(), tt.wantReport)
+		})
+	}
+}
+
+func TestCheck_WithoutBenchmem(t *testing.T) {
+	results := parseResults(strings.NewReader("BenchmarkToAdminUsers-8 200 10313 ns/op\n"))
+	budgets, err := parseBudgets(strings.NewReader("BenchmarkToAdminUsers 12288 1"))
+	require.NoError(t, err)
+
+	var report strings.Builder
+	assert.True(t, check(results, budgets, &report))
+	assert.Contains(t, report.String(), "run with -benchmem")
+}
+
+func TestParseBudgets_Invalid(t *testing.T) {
+	_, err := parseBudgets(strings.NewReader("BenchmarkX 100"))
+	require.ErrorContains(t, err, "budget line 1")
+}
@@ -0,0 +1,45 @@
+// GET /users at a constant 1k RPS, the latency budget of the hot read path.
+//
+//   k6 run -e TOKEN=<admin JWT> [-e BASE_URL=http://localhost:8080] perf/k6/users.js
+//
+// The run fails when a threshold is crossed, k6 exits with 99.
+import http from 'k6/http';
+import { check } from 'k6';
+
+const BASE_URL = __ENV.BASE_URL || 'http://localhost:8080';
+const TOKEN = __ENV.TOKEN;
+
+export const options = {
+  scenarios: {
+    list_users: {
+      // open model: the rate does not drop when the service slows down
+      executor: 'constant-arrival-rate',
+      rate: 1000,
+      timeUnit: '1s',
+      duration: __ENV.DURATION || '2m',
+      preAllocatedVUs: 200,
+      maxVUs: 1000,
+    },
+  },
+  thresholds: {
+    // the budget: p99 of GET /users at 1k RPS
+    'http_req_duration{name:list_users}': ['p(99)<150', 'p(95)<60'],
+    http_req_failed: ['rate<0.001'],
+    // the service could not keep up with the rate
+    dropped_iterations: ['count<1'],
+  },
+};
+
+export function setup() {
+  if (!TOKEN) {
+    throw new Error('TOKEN(admin JWT) is required');
+  }
+}
+
+export default function () {
+  const res = http.get(`${BASE_URL}/api/v1/users?per_page=50`, {
+    headers: { Authorization: `Bearer ${TOKEN}` },
+    tags: { name: 'list_users' },
+  });
+  check(res, { 'status is 200': (r) => r.status === 200 });
+}
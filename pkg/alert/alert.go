@@ -0,0 +1,70 @@
+package alert
+
+import (
+	"context"
+	"sync"
+
+	"go.uber.org/zap"
+)
+
+// severities of PagerDuty, Slack shows them in the text
+const (
+	SeverityCritical = "critical"
+	SeverityError    = "error"
+	SeverityWarning  = "warning"
+)
+
+type (
+	// Alert - Key identifies the condition(e.g. "mq_event_dropped"), the alerts
+	// of one key are deduplicated
+	Alert struct {
+		Key      string
+		Severity string
+		Summary  string
+		Details  map[string]string
+	}
+	// Notifier delivers the alerts to the operators(Slack, PagerDuty)
+	Notifier interface {
+		Notify(ctx context.Context, a Alert) error
+	}
+)
+
+// LogNotifier writes the alerts to the log instead of sending them, for local
+// development and tests
+type LogNotifier struct {
+	log *zap.Logger
+}
+
+func NewLogNotifier(logger *zap.Logger) *LogNotifier {
+	return &LogNotifier{log: logger}
+}
+
+func (n *LogNotifier) Notify(_ context.Context, a Alert) error {
+	n.log.Warn("alert",
+		zap.String("key", a.Key),
+		zap.String("severity", a.Severity),
+		zap.String("summary", a.Summary),
+		zap.Any("details", a.Details),
+	)
+	return nil
+}
+
+// Recorder keeps the alerts in memory, the sink of the tests
+type Recorder struct {
+	mu     sync.Mutex
+	alerts []Alert
+}
+
+func (r *Recorder) Notify(_ context.Context, a Alert) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.alerts = append(r.alerts, a)
+	return nil
+}
+
+// Alerts - the alerts notified so far
+func (r *Recorder) Alerts() []Alert {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Alert(nil), r.alerts...)
+}
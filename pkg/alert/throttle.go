@@ -0,0 +1,151 @@
+package alert
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// results of the throttled alerts(Settings.OnResult)
+const (
+	ResultSent       = "sent"
+	ResultSuppressed = "suppressed"
+	ResultFailed     = "failed"
+)
+
+// DetailSuppressed - the alerts of the key suppressed since the last one sent
+const DetailSuppressed = "suppressed"
+
+type (
+	Settings struct {
+		// DedupWindow - an alert of a key is sent once per the window
+		DedupWindow time.Duration
+		// RateLimit - the alerts of all the keys sent a minute, 0 - no limit
+		RateLimit int
+		// Timeout - of a delivery
+		Timeout time.Duration
+		// OnResult - hook for metrics, nil - none
+		OnResult func(result string)
+	}
+	// Throttle deduplicates and rate limits the alerts of a Notifier: an outage
+	// fails the same way thousands of times, the operators get it once per
+	// DedupWindow with the count of the suppressed ones. Notify never blocks,
+	// the delivery runs in the background.
+	Throttle struct {
+		notifier Notifier
+		log      *zap.Logger
+		settings Settings
+		now      func() time.Time
+
+		mu   sync.Mutex
+		keys map[string]*keyState
+		// minute, sent - the fixed window of RateLimit
+		minute time.Time
+		sent   int
+
+		wg sync.WaitGroup
+	}
+	keyState struct {
+		sentAt     time.Time
+		suppressed int
+	}
+)
+
+func NewThrottle(n Notifier, logger *zap.Logger, s Settings) *Throttle {
+	return &Throttle{
+		notifier: n,
+		log:      logger,
+		settings: s,
+		now:      time.Now,
+		keys:     make(map[string]*keyState),
+	}
+}
+
+// Notify - the error is always nil, a failed delivery is logged
+func (t *Throttle) Notify(_ context.Context, a Alert) error {
+	if !t.allow(&a) {
+		t.result(ResultSuppressed)
+		return nil
+	}
+
+	t.wg.Add(1)
+	go func() {
+		defer t.wg.Done()
+		// not the ctx of the caller: the alerts of the shutdown are sent as well
+		ctx, cancel := context.WithTimeout(context.Background(), t.settings.Timeout)
+		defer cancel()
+
+		if err := t.notifier.Notify(ctx, a); err != nil {
+			t.log.Error("alert delivery failed", zap.String("key", a.Key), zap.Error(err))
+			t.result(ResultFailed)
+			return
+		}
+		t.result(ResultSent)
+	}()
+
+	return nil
+}
+
+// Wait - the deliveries in flight
+func (t *Throttle) Wait() {
+	t.wg.Wait()
+}
+
+// allow - the alerts of the key suppressed before are counted in the details
+// of the one allowed
+func (t *Throttle) allow(a *Alert) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := t.now()
+	k, ok := t.keys[a.Key]
+	if !ok {
+		k = &keyState{}
+		t.keys[a.Key] = k
+	}
+	if ok && now.Sub(k.sentAt) < t.settings.DedupWindow {
+		k.suppressed++
+		return false
+	}
+	if t.settings.RateLimit > 0 {
+		if now.Sub(t.minute) >= time.Minute {
+			t.minute, t.sent = now, 0
+		}
+		if t.sent >= t.settings.RateLimit {
+			k.suppressed++
+			return false
+		}
+		t.sent++
+	}
+
+	if k.suppressed > 0 {
+		details := make(map[string]string, len(a.Details)+1)
+		for name, v := range a.Details {
+			details[name] = v
+		}
+		details[DetailSuppressed] = strconv.Itoa(k.suppressed)
+		a.Details = details
+	}
+	k.sentAt, k.suppressed = now, 0
+	t.sweep(now)
+
+	return true
+}
+
+// sweep drops the keys quiet for a window, must be called under lock
+func (t *Throttle) sweep(now time.Time) {
+	for key, k := range t.keys {
+		if k.suppressed == 0 && now.Sub(k.sentAt) >= t.settings.DedupWindow {
+			delete(t.keys, key)
+		}
+	}
+}
+
+func (t *Throttle) result(r string) {
+	if t.settings.OnResult != nil {
+		t.settings.OnResult(r)
+	}
+}
@@ -0,0 +1,116 @@
+package alert
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// failingNotifier - every delivery fails
+type failingNotifier struct{}
+
+func (failingNotifier) Notify(context.Context, Alert) error {
+	return errors.New("webhook answered 500")
+}
+
+func TestThrottle_Notify(t *testing.T) {
+	start := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	dropped := Alert{Key: "mq_event_dropped", Severity: SeverityError, Summary: "dropped"}
+	rejected := Alert{Key: "mq_event_rejected", Severity: SeverityWarning, Summary: "rejected"}
+
+	type step struct {
+		after time.Duration
+		alert Alert
+	}
+	tests := []struct {
+		name           string
+		settings       Settings
+		steps          []step
+		wantKeys       []string
+		wantSuppressed []string
+	}{
+		{
+			name:     "deduplicated",
+			settings: Settings{DedupWindow: time.Minute},
+			steps:    []step{{0, dropped}, {time.Second, dropped}, {time.Second, dropped}, {0, rejected}},
+			wantKeys: []string{dropped.Key, rejected.Key},
+			// the first ones of the keys
+			wantSuppressed: []string{"", ""},
+		},
+		{
+			name:           "the suppressed are counted after the window",
+			settings:       Settings{DedupWindow: time.Minute},
+			steps:          []step{{0, dropped}, {time.Second, dropped}, {time.Second, dropped}, {time.Minute, dropped}},
+			wantKeys:       []string{dropped.Key, dropped.Key},
+			wantSuppressed: []string{"", "2"},
+		},
+		{
+			name:           "rate limited",
+			settings:       Settings{DedupWindow: time.Minute, RateLimit: 1},
+			steps:          []step{{0, dropped}, {time.Second, rejected}, {time.Minute, rejected}},
+			wantKeys:       []string{dropped.Key, rejected.Key},
+			wantSuppressed: []string{"", "1"},
+		},
+		{
+			name:           "no window",
+			settings:       Settings{},
+			steps:          []step{{0, dropped}, {0, dropped}},
+			wantKeys:       []string{dropped.Key, dropped.Key},
+			wantSuppressed: []string{"", ""},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := &Recorder{}
+			tt.settings.Timeout = time.Second
+			th := NewThrottle(rec, zap.NewNop(), tt.settings)
+			now := start
+			th.now = func() time.Time { return now }
+
+			for _, s := range tt.steps {
+				now = now.Add(s.after)
+				require.NoError(t, th.Notify(context.Background(), s.alert))
+				// in order
+				th.Wait()
+			}
+
+			alerts := rec.Alerts()
+			require.Len(t, alerts, len(tt.wantKeys))
+			for i, a := range alerts {
+				assert.Equal(t, tt.wantKeys[i], a.Key)
+				assert.Equal(t, tt.wantSuppressed[i], a.Details[DetailSuppressed])
+			}
+		})
+	}
+}
+
+func TestThrottle_Results(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		results []string
+	)
+	th := NewThrottle(failingNotifier{}, zap.NewNop(), Settings{
+		DedupWindow: time.Minute,
+		Timeout:     time.Second,
+		OnResult: func(r string) {
+			mu.Lock()
+			defer mu.Unlock()
+			results = append(results, r)
+		},
+	})
+
+	a := Alert{Key: "mq_dead_letter_failed", Severity: SeverityCritical, Summary: "lost"}
+	require.NoError(t, th.Notify(context.Background(), a), "a failed delivery is logged only")
+	th.Wait()
+	require.NoError(t, th.Notify(context.Background(), a))
+	th.Wait()
+
+	assert.Equal(t, []string{ResultFailed, ResultSuppressed}, results)
+}
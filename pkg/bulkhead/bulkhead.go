@@ -0,0 +1,83 @@
+package bulkhead
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrFull is returned when no slot frees up within the wait budget.
+var ErrFull = errors.New("bulkhead is full")
+
+// Bulkhead - limit of concurrent calls to a dependency, so a slow one can not
+// take all the goroutines(and connections) of the service with it.
+type Bulkhead struct {
+	slots   chan struct{}
+	maxWait time.Duration
+	// queue - the waiting calls, nil - any number of them
+	queue chan struct{}
+}
+
+// New allows up to maxConcurrent calls at once, the others wait for a slot at
+// most maxWait(0 - rejected at once).
+func New(maxConcurrent int, maxWait time.Duration) *Bulkhead {
+	return &Bulkhead{
+		slots:   make(chan struct{}, maxConcurrent),
+		maxWait: maxWait,
+	}
+}
+
+// NewQueued - New with at most maxQueue calls waiting, the calls over it are
+// rejected at once: a spike can not pile up more waiters than a slot frees up
+// for within maxWait.
+func NewQueued(maxConcurrent, maxQueue int, maxWait time.Duration) *Bulkhead {
+	b := New(maxConcurrent, maxWait)
+	b.queue = make(chan struct{}, maxQueue)
+
+	return b
+}
+
+// Acquire takes a slot, release must be called exactly once when the call is over.
+func (b *Bulkhead) Acquire(ctx context.Context) (release func(), err error) {
+	select {
+	case b.slots <- struct{}{}:
+		return b.release, nil
+	default:
+	}
+	if b.maxWait <= 0 {
+		return nil, ErrFull
+	}
+	if b.queue != nil {
+		select {
+		case b.queue <- struct{}{}:
+			defer func() { <-b.queue }()
+		default:
+			return nil, ErrFull
+		}
+	}
+
+	t := time.NewTimer(b.maxWait)
+	defer t.Stop()
+	select {
+	case b.slots <- struct{}{}:
+		return b.release, nil
+	case <-t.C:
+		return nil, ErrFull
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// InFlight - the taken slots
+func (b *Bulkhead) InFlight() int {
+	return len(b.slots)
+}
+
+// Queued - the calls waiting for a slot, of a NewQueued bulkhead only
+func (b *Bulkhead) Queued() int {
+	return len(b.queue)
+}
+
+func (b *Bulkhead) release() {
+	<-b.slots
+}
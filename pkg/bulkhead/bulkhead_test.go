@@ -0,0 +1,89 @@
+package bulkhead
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBulkhead_Acquire(t *testing.T) {
+	ctx := context.Background()
+	b := New(2, 0)
+
+	r1, err := b.Acquire(ctx)
+	require.NoError(t, err)
+	r2, err := b.Acquire(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 2, b.InFlight())
+
+	_, err = b.Acquire(ctx)
+	require.ErrorIs(t, err, ErrFull)
+
+	r1()
+	r3, err := b.Acquire(ctx)
+	require.NoError(t, err)
+
+	r2()
+	r3()
+	require.Equal(t, 0, b.InFlight())
+}
+
+func TestBulkhead_Wait(t *testing.T) {
+	ctx := context.Background()
+	b := New(1, 50*time.Millisecond)
+	release, err := b.Acquire(ctx)
+	require.NoError(t, err)
+
+	// released while waiting
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		release()
+	}()
+	release, err = b.Acquire(ctx)
+	require.NoError(t, err)
+
+	// wait budget is over
+	_, err = b.Acquire(ctx)
+	require.ErrorIs(t, err, ErrFull)
+
+	release()
+}
+
+func TestBulkhead_CallerGaveUp(t *testing.T) {
+	b := New(1, time.Hour)
+	_, err := b.Acquire(context.Background())
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = b.Acquire(ctx)
+	require.ErrorIs(t, err, context.Canceled)
+}
+
+func TestBulkhead_Queue(t *testing.T) {
+	ctx := context.Background()
+	b := NewQueued(1, 1, time.Hour)
+	release, err := b.Acquire(ctx)
+	require.NoError(t, err)
+
+	waited := make(chan error)
+	go func() {
+		r, err := b.Acquire(ctx)
+		if err == nil {
+			r()
+		}
+		waited <- err
+	}()
+	require.Eventually(t, func() bool { return b.Queued() == 1 }, time.Second, time.Millisecond)
+
+	// the queue is full: rejected without waiting
+	_, err = b.Acquire(ctx)
+	require.ErrorIs(t, err, ErrFull)
+
+	release()
+	require.NoError(t, <-waited)
+	require.Equal(t, 0, b.Queued())
+	require.Equal(t, 0, b.InFlight())
+}
@@ -0,0 +1,50 @@
+package bulkhead
+
+import "sync"
+
+// Keyed - a bulkhead per key(e.g. per user): one key can not take all the
+// capacity of a shared dependency. A key without calls in flight takes no memory.
+type Keyed struct {
+	maxPerKey int
+
+	mu       sync.Mutex
+	inFlight map[string]int
+}
+
+// NewKeyed allows up to maxPerKey calls of a key at once, the others are rejected.
+func NewKeyed(maxPerKey int) *Keyed {
+	return &Keyed{
+		maxPerKey: maxPerKey,
+		inFlight:  make(map[string]int),
+	}
+}
+
+// TryAcquire takes a slot of key without waiting, release must be called exactly
+// once when the call is over.
+func (k *Keyed) TryAcquire(key string) (release func(), err error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if k.inFlight[key] >= k.maxPerKey {
+		return nil, ErrFull
+	}
+	k.inFlight[key]++
+
+	var once sync.Once
+	return func() { once.Do(func() { k.release(key) }) }, nil
+}
+
+// InFlight - the taken slots of key
+func (k *Keyed) InFlight(key string) int {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	return k.inFlight[key]
+}
+
+func (k *Keyed) release(key string) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if k.inFlight[key]--; k.inFlight[key] <= 0 {
+		delete(k.inFlight, key)
+	}
+}
@@ -0,0 +1,34 @@
+package bulkhead
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeyed_TryAcquire(t *testing.T) {
+	k := NewKeyed(2)
+
+	r1, err := k.TryAcquire("a")
+	require.NoError(t, err)
+	r2, err := k.TryAcquire("a")
+	require.NoError(t, err)
+	_, err = k.TryAcquire("a")
+	require.ErrorIs(t, err, ErrFull)
+
+	// the other keys are not affected
+	rb, err := k.TryAcquire("b")
+	require.NoError(t, err)
+
+	r1()
+	r1()
+	require.Equal(t, 1, k.InFlight("a"), "a second release is a no-op")
+	r3, err := k.TryAcquire("a")
+	require.NoError(t, err)
+
+	r2()
+	r3()
+	rb()
+	require.Equal(t, 0, k.InFlight("a"))
+	require.Empty(t, k.inFlight, "the idle keys are dropped")
+}
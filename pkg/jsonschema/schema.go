@@ -0,0 +1,281 @@
+// Package jsonschema - a validator of the JSON Schema subset the event and the
+// OpenAPI contracts use: type, properties, required, additionalProperties(a
+// boolean or a schema), enum, items, allOf, anyOf, oneOf(checked as anyOf: the
+// alternatives of the contracts may overlap), format(uuid, date-time, date,
+// email) and $ref to the "#/$defs/..." of the document.
+package jsonschema
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/mail"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ErrInvalid - the document does not match the schema
+var ErrInvalid = errors.New("document does not match the schema")
+
+var reUUID = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+type (
+	// Schema - a compiled schema document
+	Schema struct {
+		root *node
+		defs map[string]*node
+	}
+	node struct {
+		// Types - one of them, a string or a list of strings in the document
+		Types                []string
+		Properties           map[string]*node
+		Required             []string
+		AdditionalProperties *bool
+		// AdditionalSchema - of the properties out of Properties
+		AdditionalSchema *node
+		Enum             []any
+		Items            *node
+		AllOf            []*node
+		// AnyOf - oneOf included
+		AnyOf  []*node
+		Format string
+		Ref    string
+	}
+	rawNode struct {
+		Type                 json.RawMessage  `json:"type"`
+		Properties           map[string]*node `json:"properties"`
+		Required             []string         `json:"required"`
+		AdditionalProperties json.RawMessage  `json:"additionalProperties"`
+		Enum                 []any            `json:"enum"`
+		Items                *node            `json:"items"`
+		AllOf                []*node          `json:"allOf"`
+		AnyOf                []*node          `json:"anyOf"`
+		OneOf                []*node          `json:"oneOf"`
+		Format               string           `json:"format"`
+		Ref                  string           `json:"$ref"`
+	}
+)
+
+func (n *node) UnmarshalJSON(b []byte) error {
+	var raw rawNode
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	*n = node{
+		Properties: raw.Properties,
+		Required:   raw.Required,
+		Enum:       raw.Enum,
+		Items:      raw.Items,
+		AllOf:      raw.AllOf,
+		AnyOf:      append(raw.AnyOf, raw.OneOf...),
+		Format:     raw.Format,
+		Ref:        raw.Ref,
+	}
+	if len(raw.AdditionalProperties) > 0 {
+		var allowed bool
+		if err := json.Unmarshal(raw.AdditionalProperties, &allowed); err == nil {
+			n.AdditionalProperties = &allowed
+		} else if err = json.Unmarshal(raw.AdditionalProperties, &n.AdditionalSchema); err != nil {
+			return err
+		}
+	}
+	if len(raw.Type) == 0 {
+		return nil
+	}
+	var one string
+	if err := json.Unmarshal(raw.Type, &one); err == nil {
+		n.Types = []string{one}
+		return nil
+	}
+	return json.Unmarshal(raw.Type, &n.Types)
+}
+
+// Compile parses the schema document, the refs are checked as well.
+func Compile(doc []byte) (*Schema, error) {
+	var root node
+	if err := json.Unmarshal(doc, &root); err != nil {
+		return nil, fmt.Errorf("schema: %w", err)
+	}
+	var defs struct {
+		Defs map[string]*node `json:"$defs"`
+	}
+	if err := json.Unmarshal(doc, &defs); err != nil {
+		return nil, fmt.Errorf("schema: %w", err)
+	}
+
+	schema := &Schema{root: &root, defs: defs.Defs}
+	if err := schema.checkRefs(schema.root); err != nil {
+		return nil, err
+	}
+	for _, d := range defs.Defs {
+		if err := schema.checkRefs(d); err != nil {
+			return nil, err
+		}
+	}
+
+	return schema, nil
+}
+
+// Validate returns ErrInvalid listing every mismatch of the document.
+func (s *Schema) Validate(doc []byte) error {
+	var v any
+	if err := json.Unmarshal(doc, &v); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalid, err)
+	}
+
+	var errs []string
+	s.validate(s.root, v, "$", &errs)
+	if len(errs) > 0 {
+		return fmt.Errorf("%w: %s", ErrInvalid, strings.Join(errs, "; "))
+	}
+
+	return nil
+}
+
+func (s *Schema) checkRefs(n *node) error {
+	if n == nil {
+		return nil
+	}
+	if n.Ref != "" {
+		if _, err := s.resolve(n.Ref); err != nil {
+			return err
+		}
+	}
+	for _, p := range n.Properties {
+		if err := s.checkRefs(p); err != nil {
+			return err
+		}
+	}
+	for _, sub := range slices.Concat(n.AllOf, n.AnyOf, []*node{n.Items, n.AdditionalSchema}) {
+		if err := s.checkRefs(sub); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *Schema) resolve(ref string) (*node, error) {
+	name, ok := strings.CutPrefix(ref, "#/$defs/")
+	if !ok {
+		return nil, fmt.Errorf("schema: unsupported $ref %q", ref)
+	}
+	n, ok := s.defs[name]
+	if !ok {
+		return nil, fmt.Errorf("schema: $ref %q not found", ref)
+	}
+	return n, nil
+}
+
+func (s *Schema) validate(n *node, v any, path string, errs *[]string) {
+	if n.Ref != "" {
+		// checked by Compile
+		n, _ = s.resolve(n.Ref)
+	}
+	if len(n.Types) > 0 && !slices.ContainsFunc(n.Types, func(t string) bool { return isType(v, t) }) {
+		*errs = append(*errs, fmt.Sprintf("%s: want %s, got %s", path, strings.Join(n.Types, " or "), typeOf(v)))
+		return
+	}
+	if len(n.Enum) > 0 && !slices.Contains(n.Enum, v) {
+		*errs = append(*errs, fmt.Sprintf("%s: %v is not one of %v", path, v, n.Enum))
+	}
+	if str, ok := v.(string); ok && !isFormat(str, n.Format) {
+		*errs = append(*errs, fmt.Sprintf("%s: %q is not a %s", path, str, n.Format))
+	}
+	for _, sub := range n.AllOf {
+		s.validate(sub, v, path, errs)
+	}
+	if len(n.AnyOf) > 0 && !slices.ContainsFunc(n.AnyOf, func(sub *node) bool {
+		var subErrs []string
+		s.validate(sub, v, path, &subErrs)
+		return len(subErrs) == 0
+	}) {
+		*errs = append(*errs, fmt.Sprintf("%s: matches none of the %d alternatives", path, len(n.AnyOf)))
+	}
+
+	switch v := v.(type) {
+	case map[string]any:
+		for _, r := range n.Required {
+			if _, ok := v[r]; !ok {
+				*errs = append(*errs, fmt.Sprintf("%s: %s is required", path, r))
+			}
+		}
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			p, ok := n.Properties[k]
+			if !ok {
+				if n.AdditionalProperties != nil && !*n.AdditionalProperties {
+					*errs = append(*errs, fmt.Sprintf("%s: %s is not allowed", path, k))
+				}
+				if n.AdditionalSchema != nil {
+					s.validate(n.AdditionalSchema, v[k], path+"."+k, errs)
+				}
+				continue
+			}
+			s.validate(p, v[k], path+"."+k, errs)
+		}
+	case []any:
+		if n.Items == nil {
+			return
+		}
+		for i, item := range v {
+			s.validate(n.Items, item, fmt.Sprintf("%s[%d]", path, i), errs)
+		}
+	}
+}
+
+func isType(v any, t string) bool {
+	switch t {
+	case "integer":
+		f, ok := v.(float64)
+		return ok && f == float64(int64(f))
+	case "number":
+		_, ok := v.(float64)
+		return ok
+	default:
+		return typeOf(v) == t
+	}
+}
+
+func typeOf(v any) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		return "number"
+	case string:
+		return "string"
+	case []any:
+		return "array"
+	default:
+		return "object"
+	}
+}
+
+// isFormat - the unknown formats are annotations only
+func isFormat(s, format string) bool {
+	switch format {
+	case "uuid":
+		return reUUID.MatchString(s)
+	case "date-time":
+		_, err := time.Parse(time.RFC3339Nano, s)
+		return err == nil
+	case "date":
+		_, err := time.Parse(time.DateOnly, s)
+		return err == nil
+	case "email":
+		_, err := mail.ParseAddress(s)
+		return err == nil
+	default:
+		return true
+	}
+}
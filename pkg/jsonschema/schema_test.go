@@ -0,0 +1,144 @@
+package jsonschema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testSchema = `{
+  "type": "object",
+  "required": ["id", "kind", "owner"],
+  "additionalProperties": false,
+  "properties": {
+    "id": {"type": "string", "format": "uuid"},
+    "kind": {"type": "string", "enum": ["a", "b"]},
+    "at": {"type": ["string", "null"], "format": "date-time"},
+    "count": {"type": "integer"},
+    "tags": {"type": "array", "items": {"type": "string"}},
+    "owner": {"$ref": "#/$defs/owner"},
+    "meta": {"type": "object"}
+  },
+  "$defs": {
+    "owner": {
+      "type": "object",
+      "required": ["name"],
+      "properties": {"name": {"type": "string"}}
+    }
+  }
+}`
+
+func TestSchema_Validate(t *testing.T) {
+	s, err := Compile([]byte(testSchema))
+	require.NoError(t, err)
+
+	type tc struct {
+		name    string
+		doc     string
+		wantErr string
+	}
+	cases := []tc{
+		{
+			name: "valid",
+			doc: `{"id":"7c1f7a4e-4a35-4c4f-9d8e-2f6a3c2b1d00","kind":"a","at":"2026-10-16T09:00:00.5+02:00",` +
+				`"count":3,"tags":["x"],"owner":{"name":"n","extra":1},"meta":{"k":"v"}}`,
+		},
+		{
+			name: "null allowed",
+			doc:  `{"id":"7c1f7a4e-4a35-4c4f-9d8e-2f6a3c2b1d00","kind":"b","at":null,"owner":{"name":"n"}}`,
+		},
+		{
+			name:    "missing",
+			doc:     `{"id":"7c1f7a4e-4a35-4c4f-9d8e-2f6a3c2b1d00","owner":{}}`,
+			wantErr: "$: kind is required; $.owner: name is required",
+		},
+		{
+			name:    "not allowed",
+			doc:     `{"id":"7c1f7a4e-4a35-4c4f-9d8e-2f6a3c2b1d00","kind":"a","owner":{"name":"n"},"user_name":"x"}`,
+			wantErr: "$: user_name is not allowed",
+		},
+		{
+			name:    "types",
+			doc:     `{"id":"7c1f7a4e-4a35-4c4f-9d8e-2f6a3c2b1d00","kind":"a","owner":{"name":"n"},"count":1.5,"tags":["x",2]}`,
+			wantErr: "$.count: want integer, got number; $.tags[1]: want string, got number",
+		},
+		{
+			name:    "enum and formats",
+			doc:     `{"id":"42","kind":"c","at":"yesterday","owner":{"name":"n"}}`,
+			wantErr: `$.at: "yesterday" is not a date-time; $.id: "42" is not a uuid; $.kind: c is not one of [a b]`,
+		},
+		{
+			name:    "not json",
+			doc:     `{`,
+			wantErr: "unexpected end of JSON input",
+		},
+	}
+
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			err := s.Validate([]byte(tt.doc))
+			if tt.wantErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorIs(t, err, ErrInvalid)
+			require.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestSchema_Validate_Composition(t *testing.T) {
+	s, err := Compile([]byte(`{
+  "allOf": [
+    {"$ref": "#/$defs/base"},
+    {"type": "object", "required": ["day"], "properties": {"day": {"type": "string", "format": "date"}}}
+  ],
+  "$defs": {
+    "base": {
+      "type": "object",
+      "properties": {
+        "email": {"type": "string", "format": "email"},
+        "labels": {"type": "object", "additionalProperties": {"type": "string"}},
+        "details": {"oneOf": [{"type": "string"}, {"type": "array"}]}
+      }
+    }
+  }
+}`))
+	require.NoError(t, err)
+
+	require.NoError(t, s.Validate([]byte(`{"day":"2026-10-16","email":"a@example.com","labels":{"k":"v"},"details":["x"]}`)))
+
+	err = s.Validate([]byte(`{"email":"nope","labels":{"k":1},"details":1}`))
+	require.ErrorIs(t, err, ErrInvalid)
+	require.Contains(t, err.Error(),
+		`$.details: matches none of the 2 alternatives; $.email: "nope" is not a email; $.labels.k: want string, got number; $: day is required`)
+
+	err = s.Validate([]byte(`{"day":"16.10.2026"}`))
+	require.Contains(t, err.Error(), `$.day: "16.10.2026" is not a date`)
+}
+
+func TestCompile_Refs(t *testing.T) {
+	type tc struct {
+		name    string
+		doc     string
+		wantErr string
+	}
+	cases := []tc{
+		{"not found", `{"properties":{"a":{"$ref":"#/$defs/b"}}}`, `$ref "#/$defs/b" not found`},
+		{"remote", `{"items":{"$ref":"https://example.com/a.json"}}`, `unsupported $ref "https://example.com/a.json"`},
+		{"in defs", `{"$defs":{"a":{"properties":{"b":{"$ref":"#/$defs/c"}}}}}`, `$ref "#/$defs/c" not found`},
+		{"in allOf", `{"allOf":[{"$ref":"#/$defs/b"}]}`, `$ref "#/$defs/b" not found`},
+		{"in additionalProperties", `{"additionalProperties":{"$ref":"#/$defs/b"}}`, `$ref "#/$defs/b" not found`},
+		{"bad type", `{"type":1}`, "schema:"},
+	}
+
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Compile([]byte(tt.doc))
+			require.Error(t, err)
+			require.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
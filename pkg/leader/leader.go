@@ -0,0 +1,78 @@
+package leader
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+type (
+	// Lease - a held leadership, Done is closed once it is lost(e.g. the
+	// connection holding it dropped)
+	Lease interface {
+		Done() <-chan struct{}
+		Release()
+	}
+	// Campaigner grants the leadership of key to one holder at a time
+	Campaigner interface {
+		TryAcquire(ctx context.Context, key string) (lease Lease, acquired bool, err error)
+	}
+)
+
+// Run campaigns for the key every retry until ctx is done and runs lead while
+// it is the leader. The ctx of lead is canceled when the leadership is lost,
+// lead returning(an error included) gives the leadership up.
+func Run(
+	ctx context.Context,
+	c Campaigner,
+	key string,
+	retry time.Duration,
+	logger *zap.Logger,
+	lead func(ctx context.Context) error,
+) {
+	t := time.NewTicker(retry)
+	defer t.Stop()
+
+	for {
+		lease, acquired, err := c.TryAcquire(ctx, key)
+		switch {
+		case err != nil:
+			// alert
+			logger.Error("leader election error", zap.String("key", key), zap.Error(err))
+		case acquired:
+			logger.Info("leadership acquired", zap.String("key", key))
+			runLeader(ctx, lease, key, logger, lead)
+		}
+
+		select {
+		case <-t.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func runLeader(ctx context.Context, lease Lease, key string, logger *zap.Logger, lead func(ctx context.Context) error) {
+	defer lease.Release()
+
+	leadCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		select {
+		case <-lease.Done():
+			if leadCtx.Err() == nil {
+				logger.Warn("leadership lost", zap.String("key", key))
+			}
+			cancel()
+		case <-leadCtx.Done():
+		}
+	}()
+
+	if err := lead(leadCtx); err != nil {
+		// alert
+		logger.Error("leader stopped", zap.String("key", key), zap.Error(err))
+		return
+	}
+	logger.Info("leadership released", zap.String("key", key))
+}
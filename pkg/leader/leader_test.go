@@ -0,0 +1,110 @@
+package leader
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type fakeLease struct {
+	done     chan struct{}
+	released atomic.Bool
+}
+
+func (f *fakeLease) Done() <-chan struct{} { return f.done }
+func (f *fakeLease) Release()              { f.released.Store(true) }
+
+// fakeCampaigner grants the leases queued in grants, denies once they are over
+type fakeCampaigner struct {
+	mu       sync.Mutex
+	grants   []*fakeLease
+	err      error
+	attempts int
+}
+
+func (f *fakeCampaigner) TryAcquire(context.Context, string) (Lease, bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.attempts++
+	if f.err != nil {
+		return nil, false, f.err
+	}
+	if len(f.grants) == 0 {
+		return nil, false, nil
+	}
+	l := f.grants[0]
+	f.grants = f.grants[1:]
+	return l, true, nil
+}
+
+func (f *fakeCampaigner) Attempts() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.attempts
+}
+
+func TestRun_LeadsUntilLeaseLost(t *testing.T) {
+	first := &fakeLease{done: make(chan struct{})}
+	second := &fakeLease{done: make(chan struct{})}
+	c := &fakeCampaigner{grants: []*fakeLease{first, second}}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var terms atomic.Int32
+	finished := make(chan struct{})
+	go func() {
+		defer close(finished)
+		Run(ctx, c, "consumer:q", time.Millisecond, zap.NewNop(), func(leadCtx context.Context) error {
+			if terms.Add(1) == 1 {
+				// the connection holding the first lease drops
+				close(first.done)
+			}
+			<-leadCtx.Done()
+			return nil
+		})
+	}()
+
+	require.Eventually(t, func() bool { return terms.Load() == 2 }, time.Second, time.Millisecond)
+	require.True(t, first.released.Load())
+	require.False(t, second.released.Load())
+
+	cancel()
+	<-finished
+	require.True(t, second.released.Load())
+}
+
+func TestRun_LeadErrorGivesUpLeadership(t *testing.T) {
+	lease := &fakeLease{done: make(chan struct{})}
+	c := &fakeCampaigner{grants: []*fakeLease{lease}}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Millisecond)
+	defer cancel()
+	var terms atomic.Int32
+	Run(ctx, c, "consumer:q", time.Millisecond, zap.NewNop(), func(context.Context) error {
+		terms.Add(1)
+		return errors.New("consume: channel closed")
+	})
+
+	require.Equal(t, int32(1), terms.Load())
+	require.True(t, lease.released.Load())
+	require.Greater(t, c.Attempts(), 1, "campaigns again after giving up")
+}
+
+func TestRun_FollowerNeverLeads(t *testing.T) {
+	for _, c := range []*fakeCampaigner{{}, {err: errors.New("db down")}} {
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		Run(ctx, c, "consumer:q", time.Millisecond, zap.NewNop(), func(context.Context) error {
+			t.Fatal("must not lead")
+			return nil
+		})
+		cancel()
+		require.Greater(t, c.Attempts(), 1)
+	}
+}
@@ -0,0 +1,189 @@
+// Package openapi - the runtime check of the HTTP exchanges against an OpenAPI 3
+// document: the documented routes and statuses, the parameters and the JSON bodies
+// of the requests and of the responses. The document is loaded, resolved and
+// checked by kin-openapi, the exchanges by its openapi3filter.
+package openapi
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+)
+
+// extStreaming - the operation extension of the streamed responses
+const extStreaming = "x-streaming"
+
+type (
+	// Spec - the loaded document, the operations by "<METHOD> <path>" where the
+	// path is the server path + the path of the document(/users/{user_id})
+	Spec struct {
+		basePath   string
+		operations map[string]*Operation
+	}
+	// Operation - the contract of a route
+	Operation struct {
+		ID      string
+		Summary string
+		// Authenticated - a security requirement takes a credential(e.g. the bearer
+		// token), optional one included
+		Authenticated bool
+		// Streaming - the responses are written as they are produced(x-streaming: true),
+		// they can't be held to be checked
+		Streaming bool
+
+		route *routers.Route
+	}
+)
+
+// Load loads and validates the YAML document, every $ref must resolve within it.
+func Load(doc []byte) (*Spec, error) {
+	loader := openapi3.NewLoader()
+	t, err := loader.LoadFromData(doc)
+	if err != nil {
+		return nil, fmt.Errorf("openapi: %w", err)
+	}
+	if err = t.Validate(loader.Context); err != nil {
+		return nil, fmt.Errorf("openapi: %w", err)
+	}
+
+	s := &Spec{operations: make(map[string]*Operation)}
+	if len(t.Servers) > 0 {
+		u, err := url.Parse(t.Servers[0].URL)
+		if err != nil {
+			return nil, fmt.Errorf("openapi: server: %w", err)
+		}
+		s.basePath = strings.TrimSuffix(u.Path, "/")
+	}
+
+	for path, item := range t.Paths.Map() {
+		for method, op := range item.Operations() {
+			o := &Operation{
+				ID:      op.OperationID,
+				Summary: op.Summary,
+				route:   &routers.Route{Spec: t, Path: path, PathItem: item, Method: method, Operation: op},
+			}
+			security := op.Security
+			if security == nil {
+				security = &t.Security
+			}
+			for _, req := range *security {
+				if len(req) > 0 {
+					o.Authenticated = true
+				}
+			}
+			o.Streaming, _ = op.Extensions[extStreaming].(bool)
+			s.operations[method+" "+s.basePath+path] = o
+		}
+	}
+
+	return s, nil
+}
+
+// BasePath - the path of the first server, the routes out of it are not the API ones
+func (s *Spec) BasePath() string { return s.basePath }
+
+// Operation - of the gin route(c.FullPath()), nil if it is not documented
+func (s *Spec) Operation(method, route string) *Operation {
+	return s.operations[method+" "+specPath(route)]
+}
+
+// ValidateRequest - the violations of the parameters and, with checkBody, of the
+// body; a body of another content type than JSON is not checked. The body is read
+// from r, so the caller passes a request it may consume.
+func (o *Operation) ValidateRequest(r *http.Request, pathParams map[string]string, checkBody bool) []string {
+	return violations(openapi3filter.ValidateRequest(r.Context(), o.requestInput(r, pathParams, checkBody)))
+}
+
+// ValidateResponse - the violations of the status and the body, a body of another
+// content type than JSON is not checked
+func (o *Operation) ValidateResponse(r *http.Request, pathParams map[string]string, status int, header http.Header, body []byte) []string {
+	opts := options()
+	opts.IncludeResponseStatus = true
+	opts.ExcludeResponseBody = !isJSON(header.Get("Content-Type"))
+	in := &openapi3filter.ResponseValidationInput{
+		RequestValidationInput: o.requestInput(r, pathParams, false),
+		Status:                 status,
+		Header:                 header,
+		Options:                opts,
+	}
+	in.SetBodyBytes(body)
+
+	return violations(openapi3filter.ValidateResponse(r.Context(), in))
+}
+
+func (o *Operation) requestInput(r *http.Request, pathParams map[string]string, checkBody bool) *openapi3filter.RequestValidationInput {
+	ct := r.Header.Get("Content-Type")
+	opts := options()
+	opts.ExcludeRequestBody = !checkBody || ct != "" && !isJSON(ct)
+
+	return &openapi3filter.RequestValidationInput{
+		Request:    r,
+		PathParams: pathParams,
+		Route:      o.route,
+		Options:    opts,
+	}
+}
+
+func options() *openapi3filter.Options {
+	opts := &openapi3filter.Options{
+		// the credentials are checked by the handlers
+		AuthenticationFunc: openapi3filter.NoopAuthenticationFunc,
+		// the request is the one of the handler
+		SkipSettingDefaults: true,
+		MultiError:          true,
+	}
+	// the pointer and the reason, without the dump of the schema and of the value
+	opts.WithCustomSchemaErrorFunc(func(err *openapi3.SchemaError) string {
+		if path := err.JSONPointer(); len(path) > 0 {
+			return "/" + strings.Join(path, "/") + ": " + err.Reason
+		}
+		return err.Reason
+	})
+
+	return opts
+}
+
+// violations - the messages of the errors of openapi3filter, one per violation, in
+// order: the parameters are not checked in one
+func violations(err error) []string {
+	if err == nil {
+		return nil
+	}
+	multi, ok := err.(openapi3.MultiError)
+	if !ok {
+		return []string{err.Error()}
+	}
+	out := make([]string, 0, len(multi))
+	for _, e := range multi {
+		out = append(out, violations(e)...)
+	}
+	slices.Sort(out)
+
+	return out
+}
+
+// isJSON - application/json and the +json media types, the parameters aside
+func isJSON(contentType string) bool {
+	mt, _, _ := strings.Cut(contentType, ";")
+	mt = strings.ToLower(strings.TrimSpace(mt))
+
+	return mt == "application/json" || strings.HasSuffix(mt, "+json")
+}
+
+// specPath - /users/:user_id and /files/raw/*key as /users/{user_id} and /files/raw/{key}
+func specPath(route string) string {
+	parts := strings.Split(route, "/")
+	for i, p := range parts {
+		if strings.HasPrefix(p, ":") || strings.HasPrefix(p, "*") {
+			parts[i] = "{" + p[1:] + "}"
+		}
+	}
+
+	return strings.Join(parts, "/")
+}
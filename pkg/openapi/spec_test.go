@@ -0,0 +1,263 @@
+package openapi
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testSpec = `
+openapi: 3.0.3
+info:
+  title: Items
+  version: 1.0.0
+servers:
+  - url: http://localhost:8080/api/v1
+paths:
+  /files/{key}:
+    get:
+      operationId: getFile
+      x-streaming: true
+      parameters:
+        - in: path
+          name: key
+          required: true
+          schema:
+            type: string
+      responses:
+        '200':
+          description: OK
+  /items/{item_id}:
+    parameters:
+      - $ref: '#/components/parameters/ItemIdParam'
+    put:
+      operationId: putItem
+      summary: Put an item
+      security:
+        - {}
+        - bearerAuth: []
+      parameters:
+        - $ref: '#/components/parameters/LimitParam'
+        - in: query
+          name: tag
+          schema:
+            type: array
+            items:
+              type: string
+              enum: [a, b]
+        - in: query
+          name: mode
+          required: true
+          schema:
+            type: string
+            enum: [fast, slow]
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema:
+              $ref: '#/components/schemas/Item'
+      responses:
+        '200':
+          description: OK
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Item'
+            text/csv:
+              schema:
+                type: string
+        '204':
+          description: No content
+        '4XX':
+          description: Client errors
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Error'
+components:
+  parameters:
+    ItemIdParam:
+      in: path
+      name: item_id
+      required: true
+      schema:
+        type: string
+    LimitParam:
+      in: query
+      name: limit
+      schema:
+        type: integer
+  schemas:
+    Item:
+      type: object
+      required: [name]
+      properties:
+        name:
+          type: string
+        price:
+          oneOf:
+            - type: integer
+            - type: number
+              multipleOf: 0.5
+        deleted_at:
+          type: string
+          format: date-time
+          nullable: true
+    Error:
+      type: object
+      required: [error]
+      properties:
+        error:
+          type: string
+`
+
+func TestSpec_Operation(t *testing.T) {
+	s, err := Load([]byte(testSpec))
+	require.NoError(t, err)
+
+	assert.Equal(t, "/api/v1", s.BasePath())
+	op := s.Operation("PUT", "/api/v1/items/:item_id")
+	require.NotNil(t, op)
+	assert.Equal(t, "putItem", op.ID)
+	assert.Equal(t, "Put an item", op.Summary)
+	assert.True(t, op.Authenticated, "an optional requirement takes the credential too")
+	assert.False(t, op.Streaming)
+	assert.Nil(t, s.Operation("GET", "/api/v1/items/:item_id"))
+	assert.Nil(t, s.Operation("PUT", "/items/:item_id"))
+
+	op = s.Operation("GET", "/api/v1/files/*key")
+	require.NotNil(t, op, "a catch-all is the path parameter of its name")
+	assert.True(t, op.Streaming)
+}
+
+func TestOperation_ValidateRequest(t *testing.T) {
+	s, err := Load([]byte(testSpec))
+	require.NoError(t, err)
+	op := s.Operation("PUT", "/api/v1/items/:item_id")
+
+	type tc struct {
+		name      string
+		query     string
+		ct        string
+		body      string
+		checkBody bool
+		want      []string
+	}
+	cases := []tc{
+		{"valid", "mode=fast&limit=5&tag=a&tag=b", "application/json", `{"name":"n","price":2.5,"deleted_at":null}`, true, nil},
+		{"query", "limit=five&tag=a&tag=c", "application/json; charset=utf-8", `{"name":"n"}`, true, []string{
+			`parameter "limit" in query has an error: value five: an invalid integer`,
+			`parameter "mode" in query has an error: value is required but missing`,
+			`parameter "tag" in query has an error: /1: value is not one of the allowed values ["a","b"]`,
+		}},
+		{"no body", "mode=slow", "", "", true, []string{"request body has an error: value is required but missing"}},
+		{"body", "mode=slow", "application/json", `{"deleted_at":"yesterday"}`, true, []string{
+			`request body has an error: doesn't match schema #/components/schemas/Item: /deleted_at: string doesn't match the format "date-time"`,
+		}},
+		{"oneOf is exclusive", "mode=slow", "application/json", `{"name":"n","price":2}`, true, []string{
+			`/price: value matches more than one schema from "oneOf"`,
+		}},
+		{"body not checked", "mode=slow", "application/json", `{"name":1}`, false, nil},
+		{"not json", "mode=slow", "text/plain", `name`, true, nil},
+	}
+
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPut, "/api/v1/items/1?"+tt.query, strings.NewReader(tt.body))
+			if tt.ct != "" {
+				r.Header.Set("Content-Type", tt.ct)
+			}
+			got := op.ValidateRequest(r, map[string]string{"item_id": "1"}, tt.checkBody)
+			require.Len(t, got, len(tt.want), "%q", got)
+			for i, want := range tt.want {
+				assert.Contains(t, got[i], want)
+			}
+		})
+	}
+}
+
+func TestOperation_ValidateResponse(t *testing.T) {
+	s, err := Load([]byte(testSpec))
+	require.NoError(t, err)
+	op := s.Operation("PUT", "/api/v1/items/:item_id")
+
+	type tc struct {
+		name   string
+		status int
+		ct     string
+		body   string
+		want   []string
+	}
+	cases := []tc{
+		{"valid", 200, "application/json; charset=utf-8", `{"name":"n"}`, nil},
+		{"csv", 200, "text/csv", `name`, nil},
+		{"no content", 204, "", "", nil},
+		{"range", 404, "application/json", `{"error":"not found"}`, nil},
+		{"body", 200, "application/json", `{"name":1}`, []string{
+			`response body doesn't match schema`,
+		}},
+		{"range body", 409, "application/json", `{"message":"conflict"}`, []string{
+			`property "error" is missing`,
+		}},
+		{"not documented", 500, "application/json", `{"error":"db down"}`, []string{"status is not supported"}},
+	}
+
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPut, "/api/v1/items/1?mode=fast", nil)
+			header := http.Header{}
+			if tt.ct != "" {
+				header.Set("Content-Type", tt.ct)
+			}
+			got := op.ValidateResponse(r, map[string]string{"item_id": "1"}, tt.status, header, []byte(tt.body))
+			require.Len(t, got, len(tt.want), "%q", got)
+			for i, want := range tt.want {
+				assert.Contains(t, got[i], want)
+			}
+		})
+	}
+}
+
+func TestLoad_Refs(t *testing.T) {
+	const doc = `
+openapi: 3.0.3
+info:
+  title: Refs
+  version: 1.0.0
+paths:
+  /a:
+    get:
+      responses:
+        '200':
+          description: OK
+          content:
+            application/json:
+              schema:
+                $ref: '%s'`
+
+	type tc struct {
+		name    string
+		ref     string
+		wantErr string
+	}
+	cases := []tc{
+		{"schema not found", "#/components/schemas/A", `openapi: failed to resolve "schemas" in fragment in URI: "#/components/schemas/A"`},
+		{"external", "other.yaml#/A", `openapi: encountered disallowed external reference: "other.yaml#/A"`},
+	}
+
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Load([]byte(fmt.Sprintf(doc, tt.ref)))
+			require.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package pdf
+
+import (
+	"bytes"
+
+	"github.com/go-pdf/fpdf"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/gofont/goregular"
+)
+
+// A4 in points, 1pt = 1/72 inch
+const (
+	margin = 56
+
+	fontSize  = 11
+	titleSize = 18
+	leading   = 1.5
+	// labelWidth - the value column offset of Field lines
+	labelWidth = 140
+
+	// font - the Go fonts(golang.org/x/image/font/gofont), embedded as UTF-8 TrueType:
+	// Latin, Greek and Cyrillic
+	font = "Go"
+)
+
+// Document - text-only A4 PDF of gofpdf: the Go TrueType fonts embedded(subset) with
+// a Unicode encoding, the lines wrapped at the margin and the pages added as the
+// text flows. Not safe for concurrent use.
+type Document struct {
+	pdf *fpdf.Fpdf
+}
+
+func New() *Document {
+	return newDocument(true)
+}
+
+func newDocument(compress bool) *Document {
+	pdf := fpdf.New("P", "pt", "A4", "")
+	pdf.SetCompression(compress)
+	pdf.SetMargins(margin, margin, margin)
+	pdf.SetAutoPageBreak(true, margin)
+	pdf.AddUTF8FontFromBytes(font, "", goregular.TTF)
+	pdf.AddUTF8FontFromBytes(font, "B", gobold.TTF)
+	pdf.AddPage()
+
+	return &Document{pdf: pdf}
+}
+
+func (d *Document) Title(text string) {
+	d.pdf.SetFont(font, "B", titleSize)
+	d.pdf.MultiCell(0, titleSize*leading, text, "", "L", false)
+	d.pdf.Ln(titleSize)
+}
+
+// Field - "label   value" line, a long value wraps in its column
+func (d *Document) Field(label, value string) {
+	d.pdf.SetFont(font, "B", fontSize)
+	d.pdf.CellFormat(labelWidth, fontSize*leading, label, "", 0, "L", false, 0, "")
+	d.pdf.SetFont(font, "", fontSize)
+	d.pdf.MultiCell(0, fontSize*leading, value, "", "L", false)
+}
+
+func (d *Document) Text(text string) {
+	d.pdf.SetFont(font, "", fontSize)
+	d.pdf.MultiCell(0, fontSize*leading, text, "", "L", false)
+}
+
+// Bytes renders the document, the error is the first one of the building
+func (d *Document) Bytes() ([]byte, error) {
+	var buf bytes.Buffer
+	if err := d.pdf.Output(&buf); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
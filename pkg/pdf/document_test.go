@@ -0,0 +1,65 @@
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+	"unicode/utf16"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDocument_Bytes(t *testing.T) {
+	d := newDocument(false)
+	d.Title("User profile")
+	d.Field("Name", "Zoë (HR) O\\Brien")
+	d.Field("Lastname", "Достоевский")
+
+	out, err := d.Bytes()
+	require.NoError(t, err)
+	require.True(t, bytes.HasPrefix(out, []byte("%PDF-")))
+	require.True(t, bytes.HasSuffix(bytes.TrimSpace(out), []byte("%%EOF")))
+
+	assert.Contains(t, string(out), "/Count 1")
+	assert.Contains(t, string(out), "/FontFile2", "the font is embedded")
+	// the text as UTF-16 of the Unicode font: Cyrillic and the escaped delimiters kept
+	assert.Contains(t, string(out), shown("Достоевский"))
+	assert.Contains(t, string(out), shown("Zoë (HR) O\\Brien"))
+}
+
+func TestDocument_Pages(t *testing.T) {
+	d := newDocument(false)
+	for i := 0; i < 100; i++ {
+		d.Text(fmt.Sprintf("line %d", i))
+	}
+
+	out, err := d.Bytes()
+	require.NoError(t, err)
+	assert.Contains(t, string(out), "/Count 3")
+	assert.Contains(t, string(out), shown("line 99"))
+}
+
+func TestNew_Compressed(t *testing.T) {
+	d := New()
+	d.Text("Достоевский")
+
+	out, err := d.Bytes()
+	require.NoError(t, err)
+	assert.Contains(t, string(out), "/FlateDecode")
+}
+
+// shown - s as a string literal of the Unicode font: UTF-16BE, the delimiters escaped
+func shown(s string) string {
+	var b bytes.Buffer
+	for _, u := range utf16.Encode([]rune(s)) {
+		for _, c := range []byte{byte(u >> 8), byte(u)} {
+			if c == '(' || c == ')' || c == '\\' {
+				b.WriteByte('\\')
+			}
+			b.WriteByte(c)
+		}
+	}
+
+	return "(" + b.String() + ")"
+}
@@ -0,0 +1,168 @@
+package progress
+
+import (
+	"errors"
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// the stages of an upload
+const (
+	StatusReceiving  = "receiving"
+	StatusProcessing = "processing"
+	StatusDone       = "done"
+	StatusFailed     = "failed"
+)
+
+var ErrExists = errors.New("upload is already tracked")
+
+// Snapshot - the state of an upload at a moment, Total is 0 if unknown
+type Snapshot struct {
+	ID        string
+	Owner     string
+	Received  int64
+	Total     int64
+	Status    string
+	UpdatedAt time.Time
+}
+
+// Finished - done or failed, no more progress to wait for
+func (s Snapshot) Finished() bool {
+	return s.Status == StatusDone || s.Status == StatusFailed
+}
+
+// Tracker - the progress of the uploads in flight of the instance. A finished
+// upload is kept for ttl so a poller gets its outcome.
+type Tracker struct {
+	ttl time.Duration
+	now func() time.Time
+
+	mu      sync.Mutex
+	uploads map[string]*Upload
+}
+
+func New(ttl time.Duration) *Tracker {
+	return &Tracker{
+		ttl:     ttl,
+		now:     time.Now,
+		uploads: make(map[string]*Upload),
+	}
+}
+
+// Start tracks the upload id of owner, ErrExists if the id is taken by an
+// upload in flight or by another owner.
+func (t *Tracker) Start(id, owner string, total int64) (*Upload, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := t.now()
+	t.sweep(now)
+	if u, ok := t.uploads[id]; ok {
+		if s := u.Snapshot(); !s.Finished() || s.Owner != owner {
+			return nil, ErrExists
+		}
+	}
+	if total < 0 {
+		total = 0
+	}
+
+	u := &Upload{t: t, id: id, owner: owner, total: total, status: StatusReceiving, updatedAt: now}
+	t.uploads[id] = u
+
+	return u, nil
+}
+
+// Get - the snapshot of the upload id, false if it is unknown or expired
+func (t *Tracker) Get(id string) (Snapshot, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.sweep(t.now())
+	u, ok := t.uploads[id]
+	if !ok {
+		return Snapshot{}, false
+	}
+
+	return u.Snapshot(), true
+}
+
+// sweep drops the uploads finished more than ttl ago, t.mu must be held
+func (t *Tracker) sweep(now time.Time) {
+	for id, u := range t.uploads {
+		if s := u.Snapshot(); s.Finished() && now.Sub(s.UpdatedAt) > t.ttl {
+			delete(t.uploads, id)
+		}
+	}
+}
+
+// Upload - a tracked upload, Finish must be called once it is over
+type Upload struct {
+	t        *Tracker
+	id       string
+	owner    string
+	total    int64
+	received atomic.Int64
+
+	mu        sync.Mutex
+	status    string
+	updatedAt time.Time
+}
+
+// Reader counts the bytes read through r as received
+func (u *Upload) Reader(r io.ReadCloser) io.ReadCloser {
+	return &reader{ReadCloser: r, u: u}
+}
+
+// Processing - the body is received, the upload is being stored
+func (u *Upload) Processing() {
+	u.set(StatusProcessing)
+}
+
+// Finish - done if err is nil, failed otherwise
+func (u *Upload) Finish(err error) {
+	if err != nil {
+		u.set(StatusFailed)
+		return
+	}
+	u.set(StatusDone)
+}
+
+func (u *Upload) Snapshot() Snapshot {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	return Snapshot{
+		ID:        u.id,
+		Owner:     u.owner,
+		Received:  u.received.Load(),
+		Total:     u.total,
+		Status:    u.status,
+		UpdatedAt: u.updatedAt,
+	}
+}
+
+func (u *Upload) set(status string) {
+	now := u.t.now()
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	// the outcome is final
+	if u.status == StatusDone || u.status == StatusFailed {
+		return
+	}
+	u.status = status
+	u.updatedAt = now
+}
+
+type reader struct {
+	io.ReadCloser
+	u *Upload
+}
+
+func (r *reader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	r.u.received.Add(int64(n))
+
+	return n, err
+}
@@ -0,0 +1,56 @@
+package progress
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTracker(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	tr := New(time.Minute)
+	tr.now = func() time.Time { return now }
+
+	u, err := tr.Start("up1", "alice", 10)
+	require.NoError(t, err)
+	_, err = tr.Start("up1", "alice", 10)
+	require.ErrorIs(t, err, ErrExists, "in flight")
+
+	body := u.Reader(io.NopCloser(strings.NewReader("0123456789")))
+	buf := make([]byte, 4)
+	_, err = body.Read(buf)
+	require.NoError(t, err)
+	s, ok := tr.Get("up1")
+	require.True(t, ok)
+	require.Equal(t, Snapshot{ID: "up1", Owner: "alice", Received: 4, Total: 10, Status: StatusReceiving, UpdatedAt: now}, s)
+
+	_, err = io.ReadAll(body)
+	require.NoError(t, err)
+	u.Processing()
+	u.Finish(errors.New("storage is down"))
+	u.Finish(nil)
+	s, _ = tr.Get("up1")
+	require.Equal(t, int64(10), s.Received)
+	require.Equal(t, StatusFailed, s.Status, "the outcome is final")
+
+	_, err = tr.Start("up1", "bob", 10)
+	require.ErrorIs(t, err, ErrExists, "another owner")
+	_, err = tr.Start("up1", "alice", -1)
+	require.NoError(t, err, "a retry of the owner")
+	s, _ = tr.Get("up1")
+	require.Equal(t, Snapshot{ID: "up1", Owner: "alice", Status: StatusReceiving, UpdatedAt: now}, s)
+
+	_, err = tr.Start("up2", "bob", 0)
+	require.NoError(t, err)
+	u, _ = tr.Start("up3", "bob", 0)
+	u.Finish(nil)
+	now = now.Add(2 * time.Minute)
+	_, ok = tr.Get("up3")
+	require.False(t, ok, "expired")
+	_, ok = tr.Get("up2")
+	require.True(t, ok, "in flight uploads do not expire")
+}
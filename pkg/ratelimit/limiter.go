@@ -0,0 +1,70 @@
+package ratelimit
+
+import (
+	"sync"
+	"time"
+)
+
+// sweepEvery - how many Allow calls trigger a cleanup of expired windows
+const sweepEvery = 1024
+
+type (
+	window struct {
+		start time.Time
+		count int
+	}
+	// Limiter - fixed window limiter per key, in-memory(per instance).
+	Limiter struct {
+		limit  int
+		period time.Duration
+		now    func() time.Time
+
+		mu      sync.Mutex
+		windows map[string]*window
+		calls   int
+	}
+)
+
+// New allows up to limit calls per key during period.
+func New(limit int, period time.Duration) *Limiter {
+	return &Limiter{
+		limit:   limit,
+		period:  period,
+		now:     time.Now,
+		windows: make(map[string]*window),
+	}
+}
+
+// Allow counts the call, when the limit is exceeded it returns false and the
+// time left until the key is allowed again.
+func (l *Limiter) Allow(key string) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.calls++
+	if l.calls%sweepEvery == 0 {
+		l.sweep(now)
+	}
+
+	w, ok := l.windows[key]
+	if !ok || now.Sub(w.start) >= l.period {
+		l.windows[key] = &window{start: now, count: 1}
+		return true, 0
+	}
+	if w.count >= l.limit {
+		return false, w.start.Add(l.period).Sub(now)
+	}
+	w.count++
+
+	return true, 0
+}
+
+// sweep drops expired windows, must be called under lock.
+func (l *Limiter) sweep(now time.Time) {
+	for k, w := range l.windows {
+		if now.Sub(w.start) >= l.period {
+			delete(l.windows, k)
+		}
+	}
+}
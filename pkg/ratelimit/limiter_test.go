@@ -0,0 +1,48 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLimiter_Allow(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	l := New(2, time.Minute)
+	l.now = func() time.Time { return now }
+
+	ok, _ := l.Allow("a")
+	require.True(t, ok)
+	ok, _ = l.Allow("a")
+	require.True(t, ok)
+
+	now = now.Add(20 * time.Second)
+	ok, retry := l.Allow("a")
+	require.False(t, ok)
+	require.Equal(t, 40*time.Second, retry)
+
+	// keys are independent
+	ok, _ = l.Allow("b")
+	require.True(t, ok)
+
+	// a new window starts after the period
+	now = now.Add(40 * time.Second)
+	ok, _ = l.Allow("a")
+	require.True(t, ok)
+}
+
+func TestLimiter_Sweep(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	l := New(1, time.Second)
+	l.now = func() time.Time { return now }
+
+	for i := 0; i < sweepEvery-1; i++ {
+		l.Allow(string(rune('a' + i%26)))
+	}
+	require.Equal(t, 26, len(l.windows))
+
+	now = now.Add(time.Second)
+	l.Allow("z")
+	require.Equal(t, 1, len(l.windows))
+}
@@ -0,0 +1,84 @@
+// Package retry - the retries of the transient failures with capped exponential
+// backoff and full jitter.
+package retry
+
+import (
+	"context"
+	"math/rand/v2"
+	"time"
+)
+
+// Policy of the retries of a call. The zero one makes a single attempt.
+type Policy struct {
+	// MaxRetries - the retries after the first attempt
+	MaxRetries int
+	// MaxElapsed - no retry is started later than MaxElapsed after the first attempt,
+	// 0 - no limit
+	MaxElapsed time.Duration
+	BaseDelay  time.Duration
+	// MaxDelay - the cap of the backoff, 0 - none
+	MaxDelay time.Duration
+
+	// Retryable - err is transient, nil - every error is
+	Retryable func(err error) bool
+	// OnRetry - called before the wait for the retry of the attempt failed with err,
+	// e.g. to log and count it
+	OnRetry func(attempt int, delay time.Duration, err error)
+}
+
+// Do calls fn until it succeeds, fails with an error that is not retryable or the
+// retries run out; the error is the last one of fn. A failure after ctx is done is
+// not retried and the wait for a retry ends with ctx, ctx's error is returned then.
+func (p Policy) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	start := time.Now()
+	for attempt := 1; ; attempt++ {
+		err := fn(ctx)
+		if err == nil {
+			return nil
+		}
+		if attempt > p.MaxRetries || p.Retryable != nil && !p.Retryable(err) {
+			return err
+		}
+		// the caller gave up, nothing to retry
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		delay := p.Backoff(attempt)
+		if p.MaxElapsed > 0 && time.Since(start)+delay > p.MaxElapsed {
+			return err
+		}
+		if p.OnRetry != nil {
+			p.OnRetry(attempt, delay, err)
+		}
+		if werr := Sleep(ctx, delay); werr != nil {
+			return werr
+		}
+	}
+}
+
+// Backoff - "full jitter" after the attempt(1 - the first one):
+// rand[0, min(BaseDelay*2^(attempt-1), MaxDelay))
+func (p Policy) Backoff(attempt int) time.Duration {
+	ceil := p.BaseDelay << min(attempt-1, 30)
+	if ceil <= 0 || p.MaxDelay > 0 && ceil > p.MaxDelay {
+		ceil = p.MaxDelay
+	}
+	if ceil <= 0 {
+		return 0
+	}
+
+	return time.Duration(rand.Int64N(int64(ceil)))
+}
+
+// Sleep waits for d or until ctx is done, ctx's error then
+func Sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,144 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	errTransient = errors.New("connection reset")
+	errFinal     = errors.New("not found")
+)
+
+func TestPolicy_Do(t *testing.T) {
+	type tc struct {
+		name         string
+		policy       Policy
+		errs         []error
+		wantAttempts int
+		wantErr      error
+	}
+	transient := func(err error) bool { return errors.Is(err, errTransient) }
+	tests := []tc{
+		{
+			name:         "first attempt",
+			policy:       Policy{MaxRetries: 3, BaseDelay: time.Millisecond, Retryable: transient},
+			wantAttempts: 1,
+		},
+		{
+			name:         "recovered",
+			policy:       Policy{MaxRetries: 3, BaseDelay: time.Millisecond, Retryable: transient},
+			errs:         []error{errTransient, errTransient},
+			wantAttempts: 3,
+		},
+		{
+			name:         "retries exhausted",
+			policy:       Policy{MaxRetries: 2, BaseDelay: time.Millisecond, Retryable: transient},
+			errs:         []error{errTransient, errTransient, errTransient, errTransient},
+			wantAttempts: 3,
+			wantErr:      errTransient,
+		},
+		{
+			name:         "not retryable",
+			policy:       Policy{MaxRetries: 3, BaseDelay: time.Millisecond, Retryable: transient},
+			errs:         []error{errFinal},
+			wantAttempts: 1,
+			wantErr:      errFinal,
+		},
+		{
+			name:         "every error retryable",
+			policy:       Policy{MaxRetries: 3, BaseDelay: time.Millisecond},
+			errs:         []error{errFinal},
+			wantAttempts: 2,
+		},
+		{
+			name:         "zero policy",
+			errs:         []error{errTransient},
+			wantAttempts: 1,
+			wantErr:      errTransient,
+		},
+		{
+			name:         "elapsed",
+			policy:       Policy{MaxRetries: 1000, MaxElapsed: 30 * time.Millisecond, BaseDelay: 10 * time.Millisecond, MaxDelay: 10 * time.Millisecond},
+			errs:         make([]error, 1000),
+			wantAttempts: -1,
+			wantErr:      errTransient,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			retries := 0
+			tt.policy.OnRetry = func(attempt int, _ time.Duration, err error) {
+				retries++
+				assert.Equal(t, retries, attempt)
+				assert.Error(t, err)
+			}
+			attempts := 0
+			err := tt.policy.Do(context.Background(), func(context.Context) error {
+				attempts++
+				if attempts <= len(tt.errs) {
+					if tt.errs[attempts-1] == nil {
+						return errTransient
+					}
+					return tt.errs[attempts-1]
+				}
+				return nil
+			})
+
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+			if tt.wantAttempts > 0 {
+				assert.Equal(t, tt.wantAttempts, attempts)
+			} else {
+				assert.Greater(t, attempts, 1)
+				assert.Less(t, attempts, 1000)
+			}
+			assert.Equal(t, attempts-1, retries)
+		})
+	}
+}
+
+func TestPolicy_Do_Canceled(t *testing.T) {
+	t.Run("failed after the cancel", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		attempts := 0
+		err := Policy{MaxRetries: 3, BaseDelay: time.Minute}.Do(ctx, func(context.Context) error {
+			attempts++
+			cancel()
+			return errTransient
+		})
+		require.ErrorIs(t, err, context.Canceled)
+		assert.Equal(t, 1, attempts)
+	})
+
+	t.Run("canceled while waiting", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		p := Policy{MaxRetries: 3, BaseDelay: time.Minute, MaxDelay: time.Minute}
+		p.OnRetry = func(int, time.Duration, error) { cancel() }
+		err := p.Do(ctx, func(context.Context) error { return errTransient })
+		require.ErrorIs(t, err, context.Canceled)
+	})
+}
+
+func TestPolicy_Backoff(t *testing.T) {
+	p := Policy{BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+	for attempt := 1; attempt < 100; attempt++ {
+		d := p.Backoff(attempt)
+		assert.GreaterOrEqual(t, d, time.Duration(0))
+		assert.Less(t, d, time.Second)
+	}
+	// uncapped: below BaseDelay*2^(attempt-1)
+	for range 100 {
+		assert.Less(t, Policy{BaseDelay: 100 * time.Millisecond}.Backoff(3), 400*time.Millisecond)
+	}
+	assert.Zero(t, Policy{}.Backoff(1))
+}
@@ -2,32 +2,109 @@ package rmqconsumer
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"hash/fnv"
 	"net/http"
 	"os"
+	"sync"
+	"time"
 	"user-manager-api/config"
+	"user-manager-api/pkg/alert"
+	"user-manager-api/pkg/leader"
 
 	"github.com/rabbitmq/amqp091-go"
 	"go.uber.org/zap"
 )
 
-// can scale depends on a parallel worker count
-const preFetchCount = 1
+const (
+	// deliveries are auto-acked, so it does not bound the lanes
+	preFetchCount = 1
+	// leaderKeyPrefix - the leadership is per queue
+	leaderKeyPrefix = "consumer:"
+)
+
+// domain event routing keys(see internal/infrastructure/mq)
+const (
+	eventEmailChangeRequested = "EmailChangeRequested"
+	eventEmailChangeConfirmed = "EmailChangeConfirmed"
+	eventUserFilesChanged     = "UserFilesChanged"
+	eventInvitationCreated    = "InvitationCreated"
+	eventPasswordResetForced  = "PasswordResetForced"
+	eventLoginSucceeded       = "LoginSucceeded"
+	eventLoginFailed          = "LoginFailed"
+	eventUserBirthday         = "UserBirthday"
+	eventUsersMerged          = "UsersMerged"
+	eventUserAccessRevoked    = "UserAccessRevoked"
+	eventInactivityWarning    = "InactivityWarning"
+	eventUserSuspended        = "UserSuspended"
+	eventUserReactivated      = "UserReactivated"
+)
+
+// contentTypeJSON - of the bodies the handlers take
+const contentTypeJSON = "application/json"
+
+// secretMeta - the meta values of the events carrying the one-time tokens: the
+// handlers deliver them to the users, the printed bodies have them redacted
+var secretMeta = []string{"confirm_token", "invite_token", "invite_url"}
+
+const redacted = "[REDACTED]"
+
+// dead-letter headers(see internal/infrastructure/mq)
+const (
+	headerOriginalRoutingKey = "x-original-routing-key"
+	headerError              = "x-error"
+	headerFailedAt           = "x-failed-at"
+)
+
+// Handler processes the message body of a routing key, e.g. updates a read model
+type Handler func(ctx context.Context, body []byte) error
+
+// Decoder - the body of a content type as the JSON the handlers take
+type Decoder func(body []byte) ([]byte, error)
+
+// Deduplicator remembers the processed messages: the delivery is at least once,
+// a reconnect or a requeue delivers a message again
+type Deduplicator interface {
+	// Claim - false if the message was processed already
+	Claim(ctx context.Context, messageID string) (bool, error)
+	// Release - the processing failed, a redelivery is to be processed
+	Release(ctx context.Context, messageID string) error
+}
 
 type Consumer struct {
 	cfg        config.MQ
 	log        *zap.Logger
 	conn       *amqp091.Connection
 	chConsume  *amqp091.Channel
-	chDelivery <-chan amqp091.Delivery
+	handlers   map[string][]Handler
+	campaigner leader.Campaigner
+	dedup      Deduplicator
+	// decoders - by the content type, the other bodies are JSON
+	decoders map[string]Decoder
+	// tag - the subscription to cancel on the leadership loss
+	tag string
+	// alerter - the failed deliveries, nil - logged only
+	alerter alert.Notifier
 }
 
 func New(cfg config.MQ, logger *zap.Logger, conn *amqp091.Connection) *Consumer {
+	host, _ := os.Hostname()
 	return &Consumer{
 		cfg:  cfg,
 		log:  logger,
 		conn: conn,
+		tag:  fmt.Sprintf("usermanagerapi-%s-%d", host, os.Getpid()),
+	}
+}
+
+// Handle registers h for the routing key, must be called before DeliveryWorker
+func (c *Consumer) Handle(routingKey string, h Handler) {
+	if c.handlers == nil {
+		c.handlers = make(map[string][]Handler)
 	}
+	c.handlers[routingKey] = append(c.handlers[routingKey], h)
 }
 
 var err error
@@ -70,10 +147,35 @@ func (c *Consumer) Init() error {
 	); err != nil {
 		return fmt.Errorf("queue declare: %w", err)
 	}
+	if c.cfg.DeadLetterQueue != "" {
+		if _, err = c.chConsume.QueueDeclare(
+			c.cfg.DeadLetterQueue,
+			true,
+			false,
+			false,
+			false,
+			nil,
+		); err != nil {
+			return fmt.Errorf("dead-letter queue declare: %w", err)
+		}
+	}
 	for _, rk := range []string{
 		http.MethodPost,
 		http.MethodPut,
 		http.MethodDelete,
+		eventEmailChangeRequested,
+		eventEmailChangeConfirmed,
+		eventUserFilesChanged,
+		eventInvitationCreated,
+		eventPasswordResetForced,
+		eventLoginSucceeded,
+		eventLoginFailed,
+		eventUserBirthday,
+		eventUsersMerged,
+		eventUserAccessRevoked,
+		eventInactivityWarning,
+		eventUserSuspended,
+		eventUserReactivated,
 	} {
 		if err = c.chConsume.QueueBind(
 			c.cfg.QueueName,
@@ -90,47 +192,211 @@ func (c *Consumer) Init() error {
 		return fmt.Errorf("qos: %w", err)
 	}
 
-	var cerr error
-	c.chDelivery, cerr = c.chConsume.Consume(
+	return nil
+}
+
+// SetLeaderElection must be called before DeliveryWorker: only the leader of the
+// queue consumes, so the replicas process the events one at a time and in order.
+func (c *Consumer) SetLeaderElection(campaigner leader.Campaigner) { c.campaigner = campaigner }
+
+// Decode registers d for the messages of the content type, must be called
+// before DeliveryWorker
+func (c *Consumer) Decode(contentType string, d Decoder) {
+	if c.decoders == nil {
+		c.decoders = make(map[string]Decoder)
+	}
+	c.decoders[contentType] = d
+}
+
+// SetDeduplicator must be called before DeliveryWorker, the messages without
+// a MessageId are processed every time
+func (c *Consumer) SetDeduplicator(d Deduplicator) { c.dedup = d }
+
+// SetAlerter notifies the operators of the failed deliveries, a is expected to
+// deduplicate(see alert.Throttle)
+func (c *Consumer) SetAlerter(a alert.Notifier) { c.alerter = a }
+
+func (c *Consumer) DeliveryWorker(ctx context.Context) error {
+	c.log.Info("starting delivery worker")
+
+	defer func() {
+		c.chConsume.Close()
+		c.log.Info("delivery worker gracefully stopped")
+	}()
+
+	if c.campaigner == nil {
+		return c.consume(ctx)
+	}
+	// a follower takes over once the leader's lease is gone, a crash included
+	leader.Run(ctx, c.campaigner, leaderKeyPrefix+c.cfg.QueueName, c.cfg.LeaderRetryInterval, c.log, c.consume)
+
+	return nil
+}
+
+// consume processes deliveries until ctx is done, then cancels the subscription.
+func (c *Consumer) consume(ctx context.Context) error {
+	deliveries, err := c.chConsume.Consume(
 		c.cfg.QueueName,
-		"",
+		c.tag,
 		true,
 		false,
 		false,
 		false,
 		nil,
 	)
-	if cerr != nil {
-		return fmt.Errorf("consume: %w", cerr)
+	if err != nil {
+		return fmt.Errorf("consume: %w", err)
 	}
 
-	return nil
+	return c.dispatch(ctx, deliveries, func() error { return c.chConsume.Cancel(c.tag, false) })
 }
 
-func (c *Consumer) DeliveryWorker(ctx context.Context) {
-	c.log.Info("starting delivery worker")
-
+// dispatch hands the deliveries out to the lanes until ctx is done, then cancels
+// the subscription and waits for the lanes to process what is already received.
+// The deliveries of a user always take the same lane: a lane processes them one
+// at a time, so a create, update and delete of a user are never reordered.
+func (c *Consumer) dispatch(ctx context.Context, deliveries <-chan amqp091.Delivery, cancel func() error) error {
+	// auto-acked deliveries already sent to us must not be lost, so the lanes
+	// finish them after ctx is done
+	laneCtx := context.WithoutCancel(ctx)
+	lanes := make([]chan amqp091.Delivery, max(c.cfg.ConsumerLanes, 1))
+	var wg sync.WaitGroup
+	for i := range lanes {
+		lanes[i] = make(chan amqp091.Delivery)
+		wg.Add(1)
+		go func(lane chan amqp091.Delivery) {
+			defer wg.Done()
+			for msg := range lane {
+				c.handle(laneCtx, msg)
+			}
+		}(lanes[i])
+	}
 	defer func() {
-		c.log.Info("delivery worker gracefully stopped")
+		for _, lane := range lanes {
+			close(lane)
+		}
+		wg.Wait()
 	}()
 
 	for {
 		select {
-		case msg := <-c.chDelivery:
-			// we can also use "fan-out" chan here with "worker-pool"
-			// in case of heavy logic processing of messages
-			if err = c.delivery(msg); err != nil {
-				// alert
-				c.log.Error("mq read message error", zap.Error(err))
+		case msg, ok := <-deliveries:
+			if !ok {
+				return errors.New("deliveries channel closed")
 			}
+			c.route(laneCtx, lanes, msg)
 		case <-ctx.Done():
-			c.chConsume.Close()
-			return
+			if err := cancel(); err != nil {
+				return fmt.Errorf("consume cancel: %w", err)
+			}
+			for msg := range deliveries {
+				c.route(laneCtx, lanes, msg)
+			}
+			return nil
+		}
+	}
+}
+
+// route hands msg decoded out to its lane, the one failed to decode goes to
+// the dead-letter queue as it is
+func (c *Consumer) route(ctx context.Context, lanes []chan amqp091.Delivery, msg amqp091.Delivery) {
+	decoded, err := c.decode(msg)
+	if err != nil {
+		c.log.Error("mq decode message error", zap.String("message_id", msg.MessageId), zap.Error(err))
+		c.deadLetter(ctx, msg, err)
+		return
+	}
+	lanes[laneOf(decoded, len(lanes))] <- decoded
+}
+
+// decode - msg with the JSON body, dead-lettered with it if the handlers fail
+func (c *Consumer) decode(msg amqp091.Delivery) (amqp091.Delivery, error) {
+	d, ok := c.decoders[msg.ContentType]
+	if !ok {
+		return msg, nil
+	}
+	body, err := d(msg.Body)
+	if err != nil {
+		return msg, fmt.Errorf("decode %s: %w", msg.ContentType, err)
+	}
+	msg.Body, msg.ContentType = body, contentTypeJSON
+
+	return msg, nil
+}
+
+// handle - a failed delivery goes to the dead-letter queue
+func (c *Consumer) handle(ctx context.Context, msg amqp091.Delivery) {
+	if err := c.process(ctx, msg); err != nil {
+		c.log.Error("mq read message error", zap.Error(err))
+		c.alert(alert.Alert{
+			Key:      "mq_delivery_failed:" + msg.RoutingKey,
+			Severity: alert.SeverityError,
+			Summary:  "mq message handling failed",
+			Details:  map[string]string{"routing_key": msg.RoutingKey, "error": err.Error()},
+		})
+		c.deadLetter(ctx, msg, err)
+	}
+}
+
+// alert - a failure to be seen by the operators, not only in the log
+func (c *Consumer) alert(a alert.Alert) {
+	if c.alerter == nil {
+		return
+	}
+	_ = c.alerter.Notify(context.Background(), a)
+}
+
+// laneOf - the lane of the event's user(the "user_id" of the body), the
+// deliveries without one are spread by their MessageId
+func laneOf(msg amqp091.Delivery, lanes int) int {
+	if lanes == 1 {
+		return 0
+	}
+
+	var e struct {
+		UserID string `json:"user_id"`
+	}
+	key := msg.MessageId
+	if err := json.Unmarshal(msg.Body, &e); err == nil && e.UserID != "" {
+		key = e.UserID
+	}
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(key))
+
+	return int(h.Sum32() % uint32(lanes))
+}
+
+// process - the delivery of a message not processed yet
+func (c *Consumer) process(ctx context.Context, msg amqp091.Delivery) error {
+	if c.dedup == nil || msg.MessageId == "" {
+		return c.delivery(ctx, msg)
+	}
+
+	claimed, err := c.dedup.Claim(ctx, msg.MessageId)
+	if err != nil {
+		// a duplicate side effect is better than a lost event
+		c.log.Error("mq dedup claim error", zap.String("message_id", msg.MessageId), zap.Error(err))
+		return c.delivery(ctx, msg)
+	}
+	if !claimed {
+		c.log.Info("mq duplicate message skipped",
+			zap.String("message_id", msg.MessageId),
+			zap.String("routing_key", msg.RoutingKey),
+		)
+		return nil
+	}
+
+	if err = c.delivery(ctx, msg); err != nil {
+		if rerr := c.dedup.Release(ctx, msg.MessageId); rerr != nil {
+			c.log.Error("mq dedup release error", zap.String("message_id", msg.MessageId), zap.Error(rerr))
 		}
+		return err
 	}
+
+	return nil
 }
 
-func (c *Consumer) delivery(msg amqp091.Delivery) error {
+func (c *Consumer) delivery(ctx context.Context, msg amqp091.Delivery) error {
 	// we are having simple delivery but in prod
 	// we should implement also ack/nack procedures
 
@@ -142,13 +408,104 @@ func (c *Consumer) delivery(msg amqp091.Delivery) error {
 		action = "UserUpdated"
 	case http.MethodDelete:
 		action = "UserDeleted"
+	case eventEmailChangeRequested, eventEmailChangeConfirmed, eventUserFilesChanged, eventInvitationCreated,
+		eventPasswordResetForced, eventLoginSucceeded, eventLoginFailed, eventUserBirthday,
+		eventUsersMerged, eventUserAccessRevoked, eventInactivityWarning, eventUserSuspended, eventUserReactivated:
+		action = msg.RoutingKey
 	}
 
 	fmt.Fprintf(os.Stdout,
 		"Action=%s EventBody=%s\n",
 		action,
-		string(msg.Body),
+		string(redact(msg.Body)),
 	)
 
+	for _, h := range c.handlers[msg.RoutingKey] {
+		if err := h(ctx, msg.Body); err != nil {
+			return fmt.Errorf("%s handler: %w", msg.RoutingKey, err)
+		}
+	}
+
 	return nil
 }
+
+// redact - body with the secretMeta values replaced, as is if it has none
+func redact(body []byte) []byte {
+	var e map[string]json.RawMessage
+	if err := json.Unmarshal(body, &e); err != nil {
+		return body
+	}
+	var meta map[string]string
+	if err := json.Unmarshal(e["meta"], &meta); err != nil {
+		return body
+	}
+
+	var found bool
+	for _, k := range secretMeta {
+		if _, ok := meta[k]; ok {
+			meta[k], found = redacted, true
+		}
+	}
+	if !found {
+		return body
+	}
+
+	// neither can fail: the values were unmarshaled
+	e["meta"], _ = json.Marshal(meta)
+	out, _ := json.Marshal(e)
+
+	return out
+}
+
+// deadLetter keeps the failed delivery in the dead-letter queue for an operator
+// to requeue or discard it: it is auto-acked, so lost otherwise
+func (c *Consumer) deadLetter(ctx context.Context, msg amqp091.Delivery, cause error) {
+	if c.cfg.DeadLetterQueue == "" {
+		return
+	}
+
+	// the default exchange routes to the queue of the key's name
+	if err := c.chConsume.PublishWithContext(
+		ctx,
+		"",
+		c.cfg.DeadLetterQueue,
+		false,
+		false,
+		deadLetterPublishing(msg, cause, time.Now()),
+	); err != nil {
+		c.log.Error("mq dead-letter error", zap.String("message_id", msg.MessageId), zap.Error(err))
+		c.alert(alert.Alert{
+			Key:      "mq_dead_letter_failed",
+			Severity: alert.SeverityCritical,
+			Summary:  "mq message lost: it failed and can not be dead-lettered",
+			Details:  map[string]string{"routing_key": msg.RoutingKey, "error": err.Error()},
+		})
+		return
+	}
+	c.log.Warn("mq message dead-lettered",
+		zap.String("message_id", msg.MessageId),
+		zap.String("routing_key", msg.RoutingKey),
+	)
+}
+
+// deadLetterPublishing - msg as it was published, with the routing key and the
+// error of the failure
+func deadLetterPublishing(msg amqp091.Delivery, cause error, now time.Time) amqp091.Publishing {
+	headers := amqp091.Table{}
+	for k, v := range msg.Headers {
+		headers[k] = v
+	}
+	headers[headerOriginalRoutingKey] = msg.RoutingKey
+	headers[headerError] = cause.Error()
+	headers[headerFailedAt] = now.UTC()
+
+	return amqp091.Publishing{
+		Headers:      headers,
+		ContentType:  msg.ContentType,
+		DeliveryMode: amqp091.Persistent,
+		MessageId:    msg.MessageId,
+		Timestamp:    msg.Timestamp,
+		Type:         msg.Type,
+		Body:         msg.Body,
+	}
+}
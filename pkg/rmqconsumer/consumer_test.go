@@ -2,15 +2,21 @@ package rmqconsumer
 
 import (
 	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
 	"io"
 	"os"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/rabbitmq/amqp091-go"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
 
 	"user-manager-api/config"
+	"user-manager-api/pkg/alert"
 )
 
 func captureStdout(t *testing.T, fn func()) string {
@@ -42,6 +48,9 @@ func Test_delivery_Table(t *testing.T) {
 		{"POST -> UserCreated", "POST", `{"id":1}`, "Action=UserCreated EventBody={\"id\":1}\n"},
 		{"PUT  -> UserUpdated", "PUT", `{"id":2}`, "Action=UserUpdated EventBody={\"id\":2}\n"},
 		{"DELETE -> UserDeleted", "DELETE", `{"id":3}`, "Action=UserDeleted EventBody={\"id\":3}\n"},
+		{"EmailChangeRequested", "EmailChangeRequested", `{"id":5}`, "Action=EmailChangeRequested EventBody={\"id\":5}\n"},
+		{"EmailChangeConfirmed", "EmailChangeConfirmed", `{"id":6}`, "Action=EmailChangeConfirmed EventBody={\"id\":6}\n"},
+		{"UserFilesChanged", "UserFilesChanged", `{"id":7}`, "Action=UserFilesChanged EventBody={\"id\":7}\n"},
 		{"Unknown -> empty", "PATCH", `{"id":4}`, "Action= EventBody={\"id\":4}\n"},
 	}
 
@@ -51,7 +60,7 @@ func Test_delivery_Table(t *testing.T) {
 			c := &Consumer{}
 			out := captureStdout(t, func() {
 				msg := amqp091.Delivery{RoutingKey: tt.routingKey, Body: []byte(tt.body)}
-				err := c.delivery(msg)
+				err := c.delivery(context.Background(), msg)
 				require.NoError(t, err)
 			})
 			require.Equal(t, tt.wantOut, out)
@@ -59,6 +68,307 @@ func Test_delivery_Table(t *testing.T) {
 	}
 }
 
+func Test_delivery_Redacted(t *testing.T) {
+	c := &Consumer{}
+	body := `{"event_id":"e1","meta":{"new_email":"new@example.com","confirm_token":"s3cr3t"}}`
+	out := captureStdout(t, func() {
+		msg := amqp091.Delivery{RoutingKey: eventEmailChangeRequested, Body: []byte(body)}
+		require.NoError(t, c.delivery(context.Background(), msg))
+	})
+
+	require.NotContains(t, out, "s3cr3t")
+	require.Contains(t, out, `"confirm_token":"[REDACTED]"`)
+	require.Contains(t, out, `"new_email":"new@example.com"`)
+}
+
+func Test_redact(t *testing.T) {
+	cases := []struct {
+		name string
+		body string
+		want string
+	}{
+		{"no meta", `{"id":1}`, `{"id":1}`},
+		{"no secrets", `{"meta":{"role":"admin"}}`, `{"meta":{"role":"admin"}}`},
+		{"not JSON", `\x00\x01`, `\x00\x01`},
+		{
+			"invitation",
+			`{"meta":{"email":"a@example.com","invite_token":"t","invite_url":"https://app/?token=t"}}`,
+			`{"meta":{"email":"a@example.com","invite_token":"[REDACTED]","invite_url":"[REDACTED]"}}`,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, string(redact([]byte(tt.body))))
+		})
+	}
+}
+
+func Test_delivery_Handlers(t *testing.T) {
+	c := &Consumer{}
+	var got []string
+	c.Handle("POST", func(_ context.Context, body []byte) error {
+		got = append(got, "first "+string(body))
+		return nil
+	})
+	c.Handle("POST", func(_ context.Context, body []byte) error {
+		got = append(got, "second "+string(body))
+		return nil
+	})
+	c.Handle("DELETE", func(context.Context, []byte) error { return errors.New("db") })
+
+	captureStdout(t, func() {
+		require.NoError(t, c.delivery(context.Background(), amqp091.Delivery{RoutingKey: "POST", Body: []byte(`{}`)}))
+		require.NoError(t, c.delivery(context.Background(), amqp091.Delivery{RoutingKey: "PUT", Body: []byte(`{}`)}))
+
+		err := c.delivery(context.Background(), amqp091.Delivery{RoutingKey: "DELETE", Body: []byte(`{}`)})
+		require.ErrorContains(t, err, "DELETE handler: db")
+	})
+	require.Equal(t, []string{"first {}", "second {}"}, got)
+}
+
+func Test_handle_Alert(t *testing.T) {
+	alerts := &alert.Recorder{}
+	c := &Consumer{log: zap.NewNop()}
+	c.SetAlerter(alerts)
+	c.Handle("DELETE", func(context.Context, []byte) error { return errors.New("db") })
+
+	captureStdout(t, func() {
+		c.handle(context.Background(), amqp091.Delivery{RoutingKey: "POST", Body: []byte(`{}`)})
+		c.handle(context.Background(), amqp091.Delivery{RoutingKey: "DELETE", Body: []byte(`{}`)})
+	})
+
+	got := alerts.Alerts()
+	require.Len(t, got, 1)
+	require.Equal(t, "mq_delivery_failed:DELETE", got[0].Key)
+	require.Equal(t, "DELETE handler: db", got[0].Details["error"])
+}
+
+// fakeDedup - the claimed ids, claimErr fails every claim
+type fakeDedup struct {
+	claimed  map[string]bool
+	released []string
+	claimErr error
+}
+
+func (f *fakeDedup) Claim(_ context.Context, id string) (bool, error) {
+	if f.claimErr != nil {
+		return false, f.claimErr
+	}
+	if f.claimed[id] {
+		return false, nil
+	}
+	f.claimed[id] = true
+	return true, nil
+}
+
+func (f *fakeDedup) Release(_ context.Context, id string) error {
+	delete(f.claimed, id)
+	f.released = append(f.released, id)
+	return nil
+}
+
+func Test_process_Dedup(t *testing.T) {
+	type tc struct {
+		name         string
+		dedup        *fakeDedup
+		messageIDs   []string
+		failing      bool
+		wantCalls    int
+		wantReleased []string
+	}
+	cases := []tc{
+		{"duplicate skipped", &fakeDedup{claimed: map[string]bool{}}, []string{"m1", "m1", "m2"}, false, 2, nil},
+		{"processed before", &fakeDedup{claimed: map[string]bool{"m1": true}}, []string{"m1"}, false, 0, nil},
+		{"no message id", &fakeDedup{claimed: map[string]bool{}}, []string{"", ""}, false, 2, nil},
+		{"failure released", &fakeDedup{claimed: map[string]bool{}}, []string{"m1", "m1"}, true, 2, []string{"m1", "m1"}},
+		{"claim error processes", &fakeDedup{claimErr: errors.New("db")}, []string{"m1", "m1"}, false, 2, nil},
+	}
+
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Consumer{log: zap.NewNop()}
+			c.SetDeduplicator(tt.dedup)
+			calls := 0
+			c.Handle("POST", func(context.Context, []byte) error {
+				calls++
+				if tt.failing {
+					return errors.New("db")
+				}
+				return nil
+			})
+
+			captureStdout(t, func() {
+				for _, id := range tt.messageIDs {
+					err := c.process(context.Background(), amqp091.Delivery{MessageId: id, RoutingKey: "POST", Body: []byte(`{}`)})
+					if tt.failing {
+						require.Error(t, err)
+					} else {
+						require.NoError(t, err)
+					}
+				}
+			})
+			require.Equal(t, tt.wantCalls, calls)
+			require.Equal(t, tt.wantReleased, tt.dedup.released)
+		})
+	}
+}
+
+func Test_laneOf(t *testing.T) {
+	type tc struct {
+		name  string
+		a, b  amqp091.Delivery
+		lanes int
+		same  bool
+	}
+	cases := []tc{
+		{
+			"same user",
+			amqp091.Delivery{MessageId: "m1", Body: []byte(`{"user_id":"u1","event_action":"POST"}`)},
+			amqp091.Delivery{MessageId: "m2", Body: []byte(`{"user_id":"u1","event_action":"DELETE"}`)},
+			8, true,
+		},
+		{
+			"no user, same message",
+			amqp091.Delivery{MessageId: "m1", Body: []byte("not json")},
+			amqp091.Delivery{MessageId: "m1", Body: []byte(`{}`)},
+			8, true,
+		},
+		{
+			"one lane",
+			amqp091.Delivery{MessageId: "m1", Body: []byte(`{"user_id":"u1"}`)},
+			amqp091.Delivery{MessageId: "m2", Body: []byte(`{"user_id":"u2"}`)},
+			1, true,
+		},
+	}
+
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			a, b := laneOf(tt.a, tt.lanes), laneOf(tt.b, tt.lanes)
+			require.GreaterOrEqual(t, a, 0)
+			require.Less(t, a, tt.lanes)
+			require.Equal(t, tt.same, a == b)
+		})
+	}
+}
+
+func Test_dispatch_PerUserOrder(t *testing.T) {
+	c := &Consumer{log: zap.NewNop(), cfg: config.MQ{ConsumerLanes: 4}}
+
+	var mu sync.Mutex
+	got := map[string][]string{}
+	for _, rk := range []string{"POST", "PUT", "DELETE"} {
+		c.Handle(rk, func(_ context.Context, body []byte) error {
+			var e struct {
+				UserID string `json:"user_id"`
+			}
+			require.NoError(t, json.Unmarshal(body, &e))
+			// the lanes run in parallel, a user's events must not overtake each other
+			time.Sleep(time.Duration(len(e.UserID)%3) * time.Millisecond)
+			mu.Lock()
+			got[e.UserID] = append(got[e.UserID], rk)
+			mu.Unlock()
+			return nil
+		})
+	}
+
+	deliveries := make(chan amqp091.Delivery, 64)
+	users := []string{"u1", "user-2", "u-3", "user-04", "u5"}
+	for _, rk := range []string{"POST", "PUT", "PUT", "DELETE"} {
+		for _, u := range users {
+			deliveries <- amqp091.Delivery{RoutingKey: rk, Body: []byte(`{"user_id":"` + u + `"}`)}
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	captureStdout(t, func() {
+		// the deliveries received before the cancel are processed all the same
+		err := c.dispatch(ctx, deliveries, func() error { close(deliveries); return nil })
+		require.NoError(t, err)
+	})
+
+	for _, u := range users {
+		require.Equal(t, []string{"POST", "PUT", "PUT", "DELETE"}, got[u], u)
+	}
+}
+
+func Test_dispatch_Decode(t *testing.T) {
+	c := &Consumer{log: zap.NewNop(), cfg: config.MQ{ConsumerLanes: 2}}
+	c.Decode("application/x-upper", func(body []byte) ([]byte, error) {
+		if string(body) == "bad" {
+			return nil, errors.New("malformed")
+		}
+		return bytes.ToLower(body), nil
+	})
+	var got []string
+	c.Handle("POST", func(_ context.Context, body []byte) error {
+		got = append(got, string(body))
+		return nil
+	})
+
+	deliveries := make(chan amqp091.Delivery, 3)
+	deliveries <- amqp091.Delivery{RoutingKey: "POST", ContentType: "application/x-upper", Body: []byte(`{"USER_ID":"U1"}`)}
+	deliveries <- amqp091.Delivery{RoutingKey: "POST", ContentType: "application/x-upper", Body: []byte("bad")}
+	deliveries <- amqp091.Delivery{RoutingKey: "POST", ContentType: "application/json", Body: []byte(`{"user_id":"u1"}`)}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	captureStdout(t, func() {
+		require.NoError(t, c.dispatch(ctx, deliveries, func() error { close(deliveries); return nil }))
+	})
+	// the bad one is dead-lettered, the others take the lane of u1 in order
+	require.Equal(t, []string{`{"user_id":"u1"}`, `{"user_id":"u1"}`}, got)
+}
+
+func Test_decode(t *testing.T) {
+	c := &Consumer{}
+	c.Decode("application/x-protobuf", func([]byte) ([]byte, error) { return []byte(`{}`), nil })
+
+	msg, err := c.decode(amqp091.Delivery{ContentType: "application/x-protobuf", Body: []byte{0x0a}})
+	require.NoError(t, err)
+	require.Equal(t, amqp091.Delivery{ContentType: contentTypeJSON, Body: []byte(`{}`)}, msg)
+
+	// the JSON ones are as they are
+	in := amqp091.Delivery{ContentType: "text/plain", Body: []byte("x")}
+	msg, err = c.decode(in)
+	require.NoError(t, err)
+	require.Equal(t, in, msg)
+}
+
+func Test_deadLetterPublishing(t *testing.T) {
+	publishedAt := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	failedAt := time.Date(2026, 10, 16, 9, 0, 5, 0, time.FixedZone("CEST", 2*60*60))
+	msg := amqp091.Delivery{
+		Headers:     amqp091.Table{"x-trace": "abc"},
+		ContentType: "application/json",
+		MessageId:   "7c1f7a4e-4a35-4c4f-9d8e-2f6a3c2b1d00",
+		Timestamp:   publishedAt,
+		Type:        "DELETE",
+		RoutingKey:  "DELETE",
+		Body:        []byte(`{"id":3}`),
+	}
+
+	pub := deadLetterPublishing(msg, errors.New("DELETE handler: db"), failedAt)
+	require.Equal(t, amqp091.Publishing{
+		Headers: amqp091.Table{
+			"x-trace":                "abc",
+			headerOriginalRoutingKey: "DELETE",
+			headerError:              "DELETE handler: db",
+			headerFailedAt:           failedAt.UTC(),
+		},
+		ContentType:  "application/json",
+		DeliveryMode: amqp091.Persistent,
+		MessageId:    msg.MessageId,
+		Timestamp:    publishedAt,
+		Type:         "DELETE",
+		Body:         msg.Body,
+	}, pub)
+	require.NotContains(t, msg.Headers, headerError, "the delivery is not modified")
+}
+
 func TestConnect_InvalidDSN(t *testing.T) {
 	l := zap.NewNop()
 	c := New(config.MQ{}, l, nil)
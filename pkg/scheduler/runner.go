@@ -0,0 +1,144 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+	"golang.org/x/sync/errgroup"
+)
+
+type (
+	Job interface {
+		Name() string
+		Run(ctx context.Context) error
+	}
+	// Locker - a lock shared by all the instances of the service. release must
+	// be called once the job is done if acquired.
+	Locker interface {
+		TryLock(ctx context.Context, key string) (release func(), acquired bool, err error)
+	}
+	entry struct {
+		job      Job
+		interval time.Duration
+	}
+	// Runner - periodic background jobs, every job runs in its own goroutine
+	// and never overlaps with itself, across the instances too if a Locker is set.
+	Runner struct {
+		log     *zap.Logger
+		locker  Locker
+		entries []entry
+	}
+)
+
+// ErrLocked - the job is running on another instance
+var ErrLocked = errors.New("job is running on another instance")
+
+// lockPrefix - namespace of the job lock keys
+const lockPrefix = "job:"
+
+func New(logger *zap.Logger) *Runner {
+	return &Runner{log: logger}
+}
+
+// SetLocker must be called before Run, without it jobs are only guarded
+// against overlapping within the instance.
+func (r *Runner) SetLocker(l Locker) { r.locker = l }
+
+// Register adds a job, interval <= 0 registers it for RunOnce only(CLI).
+func (r *Runner) Register(job Job, interval time.Duration) {
+	r.entries = append(r.entries, entry{job: job, interval: interval})
+}
+
+func (r *Runner) Run(ctx context.Context) error {
+	r.log.Info("starting jobs runner")
+
+	defer func() {
+		r.log.Info("jobs runner gracefully stopped")
+	}()
+
+	g, ctx := errgroup.WithContext(ctx)
+	for _, e := range r.entries {
+		if e.interval <= 0 {
+			continue
+		}
+		e := e
+		g.Go(func() error {
+			t := time.NewTicker(e.interval)
+			defer t.Stop()
+			for {
+				select {
+				case <-t.C:
+					r.run(ctx, e.job)
+				case <-ctx.Done():
+					return nil
+				}
+			}
+		})
+	}
+
+	return g.Wait()
+}
+
+func (r *Runner) RunOnce(ctx context.Context, name string) error {
+	for _, e := range r.entries {
+		if e.job.Name() == name {
+			release, err := r.lock(ctx, e.job)
+			if err != nil {
+				return err
+			}
+			defer release()
+
+			return e.job.Run(ctx)
+		}
+	}
+
+	return fmt.Errorf("job %q is not registered", name)
+}
+
+func (r *Runner) Names() []string {
+	names := make([]string, len(r.entries))
+	for i, e := range r.entries {
+		names[i] = e.job.Name()
+	}
+	return names
+}
+
+func (r *Runner) run(ctx context.Context, job Job) {
+	release, err := r.lock(ctx, job)
+	if errors.Is(err, ErrLocked) {
+		r.log.Info("job skipped", zap.String("job", job.Name()), zap.Error(err))
+		return
+	}
+	if err != nil {
+		// alert
+		r.log.Error("job lock failed", zap.String("job", job.Name()), zap.Error(err))
+		return
+	}
+	defer release()
+
+	start := time.Now()
+	if err = job.Run(ctx); err != nil {
+		// alert
+		r.log.Error("job failed", zap.String("job", job.Name()), zap.Error(err))
+		return
+	}
+	r.log.Info("job finished", zap.String("job", job.Name()), zap.Duration("duration", time.Since(start)))
+}
+
+func (r *Runner) lock(ctx context.Context, job Job) (func(), error) {
+	if r.locker == nil {
+		return func() {}, nil
+	}
+	release, acquired, err := r.locker.TryLock(ctx, lockPrefix+job.Name())
+	if err != nil {
+		return nil, fmt.Errorf("job %q lock: %w", job.Name(), err)
+	}
+	if !acquired {
+		return nil, fmt.Errorf("job %q: %w", job.Name(), ErrLocked)
+	}
+
+	return release, nil
+}
@@ -0,0 +1,137 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type fakeJob struct {
+	name  string
+	err   error
+	calls atomic.Int32
+}
+
+func (f *fakeJob) Name() string { return f.name }
+func (f *fakeJob) Run(context.Context) error {
+	f.calls.Add(1)
+	return f.err
+}
+
+func TestRunOnce_Table(t *testing.T) {
+	boom := errors.New("boom")
+	type tc struct {
+		name    string
+		job     string
+		wantErr string
+	}
+	cases := []tc{
+		{"registered job", "ok", ""},
+		{"job error is returned", "failing", "boom"},
+		{"unknown job", "missing", `job "missing" is not registered`},
+	}
+
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			r := New(zap.NewNop())
+			r.Register(&fakeJob{name: "ok"}, 0)
+			r.Register(&fakeJob{name: "failing", err: boom}, 0)
+
+			err := r.RunOnce(context.Background(), tt.job)
+			if tt.wantErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.EqualError(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestRun_Periodic(t *testing.T) {
+	r := New(zap.NewNop())
+	periodic := &fakeJob{name: "periodic"}
+	cliOnly := &fakeJob{name: "cli-only"}
+	r.Register(periodic, 5*time.Millisecond)
+	r.Register(cliOnly, 0)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Millisecond)
+	defer cancel()
+	require.NoError(t, r.Run(ctx))
+
+	require.True(t, periodic.calls.Load() >= 2)
+	require.Equal(t, int32(0), cliOnly.calls.Load())
+	require.Equal(t, []string{"periodic", "cli-only"}, r.Names())
+}
+
+type fakeLocker struct {
+	held     map[string]bool
+	err      error
+	keys     []string
+	released []string
+}
+
+func (f *fakeLocker) TryLock(_ context.Context, key string) (func(), bool, error) {
+	f.keys = append(f.keys, key)
+	if f.err != nil || f.held[key] {
+		return nil, false, f.err
+	}
+	return func() { f.released = append(f.released, key) }, true, nil
+}
+
+func TestRunOnce_Locker(t *testing.T) {
+	type tc struct {
+		name      string
+		locker    *fakeLocker
+		wantCalls int32
+		wantErr   error
+		wantErrIn string
+	}
+	cases := []tc{
+		{"free", &fakeLocker{}, 1, nil, ""},
+		{"held by another instance", &fakeLocker{held: map[string]bool{"job:ok": true}}, 0, ErrLocked, `job "ok": job is running on another instance`},
+		{"lock error", &fakeLocker{err: errors.New("db down")}, 0, nil, `job "ok" lock: db down`},
+	}
+
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			r := New(zap.NewNop())
+			r.SetLocker(tt.locker)
+			job := &fakeJob{name: "ok"}
+			r.Register(job, 0)
+
+			err := r.RunOnce(context.Background(), "ok")
+			require.Equal(t, tt.wantCalls, job.calls.Load())
+			require.Equal(t, []string{"job:ok"}, tt.locker.keys)
+			if tt.wantErrIn == "" {
+				require.NoError(t, err)
+				require.Equal(t, []string{"job:ok"}, tt.locker.released)
+				return
+			}
+			require.EqualError(t, err, tt.wantErrIn)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+			}
+			require.Empty(t, tt.locker.released)
+		})
+	}
+}
+
+func TestRun_SkipsLockedJob(t *testing.T) {
+	r := New(zap.NewNop())
+	r.SetLocker(&fakeLocker{held: map[string]bool{"job:locked": true}})
+	locked := &fakeJob{name: "locked"}
+	r.Register(locked, 5*time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Millisecond)
+	defer cancel()
+	require.NoError(t, r.Run(ctx))
+
+	require.Equal(t, int32(0), locked.calls.Load())
+}
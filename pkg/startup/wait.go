@@ -0,0 +1,59 @@
+package startup
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"go.uber.org/zap"
+
+	"user-manager-api/pkg/retry"
+)
+
+// Settings of the wait for a dependency booting slower than the service(docker
+// compose, k8s), MaxWait 0 - a single attempt
+type Settings struct {
+	MaxWait   time.Duration
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+}
+
+// Wait calls connect until it succeeds, retrying with capped exponential
+// backoff and full jitter(pkg/retry) for at most s.MaxWait. The error is the last
+// one of connect, or ctx's when it is done first.
+func Wait(ctx context.Context, logger *zap.Logger, name string, s Settings, connect func(ctx context.Context) error) error {
+	p := retry.Policy{
+		MaxElapsed: s.MaxWait,
+		BaseDelay:  s.BaseDelay,
+		MaxDelay:   s.MaxDelay,
+		OnRetry: func(attempt int, delay time.Duration, err error) {
+			logger.Warn("dependency not ready, retrying",
+				zap.String("dependency", name),
+				zap.Int("attempt", attempt),
+				zap.Duration("retry_in", delay),
+				zap.Error(err),
+			)
+		},
+	}
+	if s.MaxWait > 0 {
+		p.MaxRetries = math.MaxInt
+	}
+
+	attempts := 0
+	err := p.Do(ctx, func(ctx context.Context) error {
+		attempts++
+		return connect(ctx)
+	})
+	switch {
+	case err == nil:
+		if attempts > 1 {
+			logger.Info("dependency ready", zap.String("dependency", name), zap.Int("attempts", attempts))
+		}
+		return nil
+	case ctx.Err() != nil:
+		return fmt.Errorf("%s not ready: %w", name, ctx.Err())
+	default:
+		return fmt.Errorf("%s not ready after %d attempts: %w", name, attempts, err)
+	}
+}
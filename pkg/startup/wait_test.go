@@ -0,0 +1,64 @@
+package startup
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+var errDown = errors.New("connection refused")
+
+func TestWait_Table(t *testing.T) {
+	cases := []struct {
+		name         string
+		settings     Settings
+		failures     int
+		wantAttempts int
+		wantErr      bool
+	}{
+		{"ready", Settings{MaxWait: time.Second, BaseDelay: time.Millisecond, MaxDelay: 5 * time.Millisecond}, 0, 1, false},
+		{"ready after failures", Settings{MaxWait: time.Second, BaseDelay: time.Millisecond, MaxDelay: 5 * time.Millisecond}, 3, 4, false},
+		{"single attempt", Settings{BaseDelay: time.Millisecond}, 1, 1, true},
+		{"gives up", Settings{MaxWait: 50 * time.Millisecond, BaseDelay: 10 * time.Millisecond, MaxDelay: 10 * time.Millisecond}, 1000, 0, true},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			attempts := 0
+			err := Wait(context.Background(), zap.NewNop(), "postgres", tt.settings, func(ctx context.Context) error {
+				attempts++
+				if attempts <= tt.failures {
+					return errDown
+				}
+				return nil
+			})
+			if tt.wantErr {
+				require.ErrorIs(t, err, errDown)
+				assert.Contains(t, err.Error(), "postgres not ready")
+			} else {
+				require.NoError(t, err)
+			}
+			if tt.wantAttempts > 0 {
+				assert.Equal(t, tt.wantAttempts, attempts)
+			} else {
+				assert.Greater(t, attempts, 1)
+			}
+		})
+	}
+}
+
+func TestWait_Canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	s := Settings{MaxWait: time.Minute, BaseDelay: time.Minute, MaxDelay: time.Minute}
+
+	err := Wait(ctx, zap.NewNop(), "rabbitmq", s, func(ctx context.Context) error {
+		cancel()
+		return errDown
+	})
+	require.ErrorIs(t, err, context.Canceled)
+}
@@ -0,0 +1,362 @@
+// The protobuf encoding of the events(RABBITMQ_EVENT_ENCODINGS), the messages
+// carry the "application/x-protobuf" content type. Mirrors event.json: a field
+// added to one of them is added to the other. The Go code is generated into
+// eventpb(go generate ./schemas/).
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.9
+// 	protoc        (unknown)
+// source: event.proto
+
+package eventpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Event struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	EventId   string                 `protobuf:"bytes,1,opt,name=event_id,json=eventId,proto3" json:"event_id,omitempty"`
+	TimeStamp *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=time_stamp,json=timeStamp,proto3" json:"time_stamp,omitempty"`
+	// the routing key
+	EventAction string `protobuf:"bytes,3,opt,name=event_action,json=eventAction,proto3" json:"event_action,omitempty"`
+	UserId      string `protobuf:"bytes,4,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	UserPayload *User  `protobuf:"bytes,5,opt,name=user_payload,json=userPayload,proto3" json:"user_payload,omitempty"`
+	// event specific values, e.g. the new email and confirmation token
+	Meta          map[string]string `protobuf:"bytes,6,rep,name=meta,proto3" json:"meta,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Event) Reset() {
+	*x = Event{}
+	mi := &file_event_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Event) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Event) ProtoMessage() {}
+
+func (x *Event) ProtoReflect() protoreflect.Message {
+	mi := &file_event_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Event.ProtoReflect.Descriptor instead.
+func (*Event) Descriptor() ([]byte, []int) {
+	return file_event_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Event) GetEventId() string {
+	if x != nil {
+		return x.EventId
+	}
+	return ""
+}
+
+func (x *Event) GetTimeStamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.TimeStamp
+	}
+	return nil
+}
+
+func (x *Event) GetEventAction() string {
+	if x != nil {
+		return x.EventAction
+	}
+	return ""
+}
+
+func (x *Event) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *Event) GetUserPayload() *User {
+	if x != nil {
+		return x.UserPayload
+	}
+	return nil
+}
+
+func (x *Event) GetMeta() map[string]string {
+	if x != nil {
+		return x.Meta
+	}
+	return nil
+}
+
+type User struct {
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	Uuid         string                 `protobuf:"bytes,1,opt,name=uuid,proto3" json:"uuid,omitempty"`
+	Email        string                 `protobuf:"bytes,2,opt,name=email,proto3" json:"email,omitempty"`
+	Name         string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	Lastname     string                 `protobuf:"bytes,4,opt,name=lastname,proto3" json:"lastname,omitempty"`
+	BirthDate    *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=birth_date,json=birthDate,proto3" json:"birth_date,omitempty"`
+	Phone        string                 `protobuf:"bytes,6,opt,name=phone,proto3" json:"phone,omitempty"`
+	PendingEmail string                 `protobuf:"bytes,7,opt,name=pending_email,json=pendingEmail,proto3" json:"pending_email,omitempty"`
+	// only in the profile reads
+	FilesCount        *uint64 `protobuf:"varint,8,opt,name=files_count,json=filesCount,proto3,oneof" json:"files_count,omitempty"`
+	TotalStorageBytes *uint64 `protobuf:"varint,9,opt,name=total_storage_bytes,json=totalStorageBytes,proto3,oneof" json:"total_storage_bytes,omitempty"`
+	// IANA name, empty - the timezone of the organization
+	Timezone string `protobuf:"bytes,10,opt,name=timezone,proto3" json:"timezone,omitempty"`
+	// as of today in the timezone of the user, only in the profile reads and UserBirthday
+	Age             *uint32 `protobuf:"varint,11,opt,name=age,proto3,oneof" json:"age,omitempty"`
+	IsBirthdayToday *bool   `protobuf:"varint,12,opt,name=is_birthday_today,json=isBirthdayToday,proto3,oneof" json:"is_birthday_today,omitempty"`
+	// the optional name parts, empty if none
+	MiddleName    string `protobuf:"bytes,13,opt,name=middle_name,json=middleName,proto3" json:"middle_name,omitempty"`
+	Suffix        string `protobuf:"bytes,14,opt,name=suffix,proto3" json:"suffix,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *User) Reset() {
+	*x = User{}
+	mi := &file_event_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *User) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*User) ProtoMessage() {}
+
+func (x *User) ProtoReflect() protoreflect.Message {
+	mi := &file_event_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use User.ProtoReflect.Descriptor instead.
+func (*User) Descriptor() ([]byte, []int) {
+	return file_event_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *User) GetUuid() string {
+	if x != nil {
+		return x.Uuid
+	}
+	return ""
+}
+
+func (x *User) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *User) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *User) GetLastname() string {
+	if x != nil {
+		return x.Lastname
+	}
+	return ""
+}
+
+func (x *User) GetBirthDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.BirthDate
+	}
+	return nil
+}
+
+func (x *User) GetPhone() string {
+	if x != nil {
+		return x.Phone
+	}
+	return ""
+}
+
+func (x *User) GetPendingEmail() string {
+	if x != nil {
+		return x.PendingEmail
+	}
+	return ""
+}
+
+func (x *User) GetFilesCount() uint64 {
+	if x != nil && x.FilesCount != nil {
+		return *x.FilesCount
+	}
+	return 0
+}
+
+func (x *User) GetTotalStorageBytes() uint64 {
+	if x != nil && x.TotalStorageBytes != nil {
+		return *x.TotalStorageBytes
+	}
+	return 0
+}
+
+func (x *User) GetTimezone() string {
+	if x != nil {
+		return x.Timezone
+	}
+	return ""
+}
+
+func (x *User) GetAge() uint32 {
+	if x != nil && x.Age != nil {
+		return *x.Age
+	}
+	return 0
+}
+
+func (x *User) GetIsBirthdayToday() bool {
+	if x != nil && x.IsBirthdayToday != nil {
+		return *x.IsBirthdayToday
+	}
+	return false
+}
+
+func (x *User) GetMiddleName() string {
+	if x != nil {
+		return x.MiddleName
+	}
+	return ""
+}
+
+func (x *User) GetSuffix() string {
+	if x != nil {
+		return x.Suffix
+	}
+	return ""
+}
+
+var File_event_proto protoreflect.FileDescriptor
+
+const file_event_proto_rawDesc = "" +
+	"\n" +
+	"\vevent.proto\x12\x15usermanager.events.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"\xce\x02\n" +
+	"\x05Event\x12\x19\n" +
+	"\bevent_id\x18\x01 \x01(\tR\aeventId\x129\n" +
+	"\n" +
+	"time_stamp\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\ttimeStamp\x12!\n" +
+	"\fevent_action\x18\x03 \x01(\tR\veventAction\x12\x17\n" +
+	"\auser_id\x18\x04 \x01(\tR\x06userId\x12>\n" +
+	"\fuser_payload\x18\x05 \x01(\v2\x1b.usermanager.events.v1.UserR\vuserPayload\x12:\n" +
+	"\x04meta\x18\x06 \x03(\v2&.usermanager.events.v1.Event.MetaEntryR\x04meta\x1a7\n" +
+	"\tMetaEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\x94\x04\n" +
+	"\x04User\x12\x12\n" +
+	"\x04uuid\x18\x01 \x01(\tR\x04uuid\x12\x14\n" +
+	"\x05email\x18\x02 \x01(\tR\x05email\x12\x12\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\x12\x1a\n" +
+	"\blastname\x18\x04 \x01(\tR\blastname\x129\n" +
+	"\n" +
+	"birth_date\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\tbirthDate\x12\x14\n" +
+	"\x05phone\x18\x06 \x01(\tR\x05phone\x12#\n" +
+	"\rpending_email\x18\a \x01(\tR\fpendingEmail\x12$\n" +
+	"\vfiles_count\x18\b \x01(\x04H\x00R\n" +
+	"filesCount\x88\x01\x01\x123\n" +
+	"\x13total_storage_bytes\x18\t \x01(\x04H\x01R\x11totalStorageBytes\x88\x01\x01\x12\x1a\n" +
+	"\btimezone\x18\n" +
+	" \x01(\tR\btimezone\x12\x15\n" +
+	"\x03age\x18\v \x01(\rH\x02R\x03age\x88\x01\x01\x12/\n" +
+	"\x11is_birthday_today\x18\f \x01(\bH\x03R\x0fisBirthdayToday\x88\x01\x01\x12\x1f\n" +
+	"\vmiddle_name\x18\r \x01(\tR\n" +
+	"middleName\x12\x16\n" +
+	"\x06suffix\x18\x0e \x01(\tR\x06suffixB\x0e\n" +
+	"\f_files_countB\x16\n" +
+	"\x14_total_storage_bytesB\x06\n" +
+	"\x04_ageB\x14\n" +
+	"\x12_is_birthday_todayB\"Z user-manager-api/schemas/eventpbb\x06proto3"
+
+var (
+	file_event_proto_rawDescOnce sync.Once
+	file_event_proto_rawDescData []byte
+)
+
+func file_event_proto_rawDescGZIP() []byte {
+	file_event_proto_rawDescOnce.Do(func() {
+		file_event_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_event_proto_rawDesc), len(file_event_proto_rawDesc)))
+	})
+	return file_event_proto_rawDescData
+}
+
+var file_event_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_event_proto_goTypes = []any{
+	(*Event)(nil),                 // 0: usermanager.events.v1.Event
+	(*User)(nil),                  // 1: usermanager.events.v1.User
+	nil,                           // 2: usermanager.events.v1.Event.MetaEntry
+	(*timestamppb.Timestamp)(nil), // 3: google.protobuf.Timestamp
+}
+var file_event_proto_depIdxs = []int32{
+	3, // 0: usermanager.events.v1.Event.time_stamp:type_name -> google.protobuf.Timestamp
+	1, // 1: usermanager.events.v1.Event.user_payload:type_name -> usermanager.events.v1.User
+	2, // 2: usermanager.events.v1.Event.meta:type_name -> usermanager.events.v1.Event.MetaEntry
+	3, // 3: usermanager.events.v1.User.birth_date:type_name -> google.protobuf.Timestamp
+	4, // [4:4] is the sub-list for method output_type
+	4, // [4:4] is the sub-list for method input_type
+	4, // [4:4] is the sub-list for extension type_name
+	4, // [4:4] is the sub-list for extension extendee
+	0, // [0:4] is the sub-list for field type_name
+}
+
+func init() { file_event_proto_init() }
+func file_event_proto_init() {
+	if File_event_proto != nil {
+		return
+	}
+	file_event_proto_msgTypes[1].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_event_proto_rawDesc), len(file_event_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   3,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_event_proto_goTypes,
+		DependencyIndexes: file_event_proto_depIdxs,
+		MessageInfos:      file_event_proto_msgTypes,
+	}.Build()
+	File_event_proto = out.File
+	file_event_proto_goTypes = nil
+	file_event_proto_depIdxs = nil
+}
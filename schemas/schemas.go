@@ -0,0 +1,15 @@
+// Package schemas - the published contracts of the events: the JSON Schema and
+// the protobuf message(event.proto, its Go code generated into eventpb). A change
+// of the Event struct not reflected in event.json fails the tests of
+// internal/infrastructure/mq.
+package schemas
+
+//go:generate protoc --go_out=eventpb --go_opt=paths=source_relative event.proto
+
+import "embed"
+
+//go:embed *.json
+var FS embed.FS
+
+// Event - the file of the mq.Event schema
+const Event = "event.json"